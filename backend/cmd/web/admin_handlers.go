@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
 )
 
 // Initialize only teams and employees - customers and jobs will be created from real interactions
@@ -83,7 +85,7 @@ func initializeTeamsAndEmployees() {
 	}
 	
 	// Note: customers and jobs will be created from real user interactions
-	// serviceRequests will be populated when customers submit booking forms
+	// service requests are leads submitted through the booking forms
 }
 
 // Admin Dashboard Handlers
@@ -205,6 +207,7 @@ func getOverviewPartial(w http.ResponseWriter, r *http.Request) {
 	activeJobs := 0
 	totalRevenue := 0.0
 	pendingRequests := 0
+	serviceRequests := listServiceRequests(r)
 	
 	// Count pending service requests
 	for _, request := range serviceRequests {
@@ -375,6 +378,8 @@ func getOverviewPartial(w http.ResponseWriter, r *http.Request) {
 }
 
 func getRequestsPartial(w http.ResponseWriter, r *http.Request) {
+	serviceRequests := listServiceRequests(r)
+
 	html := `
 	<div class="flex justify-between items-center mb-6">
 		<h2 class="text-2xl font-bold text-gray-900">Service Requests</h2>
@@ -413,7 +418,7 @@ func getRequestsPartial(w http.ResponseWriter, r *http.Request) {
 			actionButtons := ""
 			
 			switch request.Status {
-			case "pending":
+			case "pending", "duplicate":
 				statusColor = "orange"
 				actionButtons = fmt.Sprintf(`
 					<div class="flex space-x-2 mt-2">
@@ -1037,10 +1042,12 @@ func createJob(w http.ResponseWriter, r *http.Request) {
 
 	// If this job was created from a service request, update the request status
 	if serviceRequestID != "" {
-		if request, exists := serviceRequests[serviceRequestID]; exists {
-			request.Status = "scheduled"
-			serviceRequests[serviceRequestID] = request
-			log.Printf("Service request %s status updated to 'scheduled' - Job %s created", serviceRequestID, id)
+		if lead, exists := getServiceRequest(r, serviceRequestID); exists {
+			if _, err := leadService.UpdateLeadStatus(leadContext(r), lead.ID, domain.LeadStatusScheduled); err != nil {
+				log.Printf("Failed to update service request %s: %v", serviceRequestID, err)
+			} else {
+				log.Printf("Service request %s status updated to 'scheduled' - Job %s created", serviceRequestID, id)
+			}
 		}
 	}
 
@@ -1275,7 +1282,8 @@ func getJobForm(w http.ResponseWriter, r *http.Request) {
 	
 	// If this job is being created from a service request, pre-populate data
 	if requestID != "" {
-		if req, exists := serviceRequests[requestID]; exists {
+		if lead, exists := getServiceRequest(r, requestID); exists {
+			req := serviceRequestFromLead(lead)
 			serviceRequest = req
 			preSelectedCustomerID = req.CustomerID
 			preSelectedServiceType = req.ServiceType
@@ -1550,15 +1558,22 @@ func acceptServiceRequest(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	requestID := vars["id"]
 	
-	request, exists := serviceRequests[requestID]
+	lead, exists := getServiceRequest(r, requestID)
 	if !exists {
 		http.Error(w, "Service request not found", http.StatusNotFound)
 		return
 	}
 	
+	// Add the contact to the customer list so the job can be scheduled for them
+	findOrCreateCustomer(lead)
+	
 	// Update status to accepted
-	request.Status = "accepted"
-	serviceRequests[requestID] = request
+	lead, err := leadService.UpdateLeadStatus(leadContext(r), lead.ID, domain.LeadStatusAccepted)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to accept service request: %v", err), http.StatusBadRequest)
+		return
+	}
+	request := serviceRequestFromLead(lead)
 	
 	log.Printf("Service request %s accepted for customer %s", requestID, request.CustomerName)
 	
@@ -1613,15 +1628,19 @@ func denyServiceRequest(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	requestID := vars["id"]
 	
-	request, exists := serviceRequests[requestID]
+	lead, exists := getServiceRequest(r, requestID)
 	if !exists {
 		http.Error(w, "Service request not found", http.StatusNotFound)
 		return
 	}
 	
 	// Update status to denied
-	request.Status = "denied"
-	serviceRequests[requestID] = request
+	lead, err := leadService.DenyLead(leadContext(r), lead.ID, "")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to deny service request: %v", err), http.StatusBadRequest)
+		return
+	}
+	request := serviceRequestFromLead(lead)
 	
 	log.Printf("Service request %s denied for customer %s", requestID, request.CustomerName)
 	
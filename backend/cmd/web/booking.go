@@ -0,0 +1,261 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// The booking form offers arrival windows computed by the API's slot generator from
+// this site's teams and scheduled jobs. Picking a window holds it for a few minutes;
+// submitting the form confirms the hold as a tentative job for staff to review.
+// Tenants using the API can embed /api/v1/public/booking instead.
+var (
+	bookingSettings *domain.BookingSettings
+	bookingHolds    = make(map[uuid.UUID]*domain.BookingHold)
+	bookingMu       sync.Mutex
+)
+
+// initBooking sets up online booking with the default settings in local time
+func initBooking() {
+	bookingSettings = services.DefaultBookingSettings(leadTenantID)
+	bookingSettings.Enabled = true
+	bookingSettings.Timezone = time.Local.String()
+}
+
+// teamCrewID gives a team a stable crew ID for slot generation
+func teamCrewID(teamID string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte("team:"+teamID))
+}
+
+// bookingDuration is the service's estimated time on site
+func bookingDuration(serviceKey string) time.Duration {
+	if config, ok := serviceConfigs[serviceKey]; ok && config.BaseHours > 0 {
+		return time.Duration(config.BaseHours * float64(time.Hour))
+	}
+	return time.Duration(bookingSettings.DefaultDurationMinutes) * time.Minute
+}
+
+// webBookingSlots generates the next week's arrival windows. Callers hold bookingMu.
+func webBookingSlots(serviceKey string, now time.Time) []services.BookingSlot {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	var crewDays []services.BookingCrewDay
+	for teamID, team := range teams {
+		if !team.Active {
+			continue
+		}
+		for day := today; day.Before(today.AddDate(0, 0, 8)); day = day.AddDate(0, 0, 1) {
+			crewDay := services.BookingCrewDay{CrewID: teamCrewID(teamID), Date: day}
+			for _, job := range jobs {
+				if job.TeamID != teamID || job.Status == "cancelled" || job.Status == "completed" {
+					continue
+				}
+				if job.ScheduledAt.Year() != day.Year() || job.ScheduledAt.YearDay() != day.YearDay() {
+					continue
+				}
+				duration := job.Duration
+				if duration <= 0 {
+					duration = bookingSettings.DefaultDurationMinutes
+				}
+				crewDay.Stops = append(crewDay.Stops, services.BookingStop{
+					Start: job.ScheduledAt,
+					End:   job.ScheduledAt.Add(time.Duration(duration) * time.Minute),
+				})
+			}
+			crewDays = append(crewDays, crewDay)
+		}
+	}
+
+	holds := make([]*domain.BookingHold, 0, len(bookingHolds))
+	for _, hold := range bookingHolds {
+		holds = append(holds, hold)
+	}
+
+	return services.GenerateBookingSlots(&services.BookingSlotInput{
+		Settings: bookingSettings,
+		CrewDays: crewDays,
+		Holds:    holds,
+		Duration: bookingDuration(serviceKey),
+		Now:      now,
+	})
+}
+
+// getBookingSlots renders the arrival window picker for the selected service
+func getBookingSlots(w http.ResponseWriter, r *http.Request) {
+	serviceKey := r.URL.Query().Get("service")
+	w.Header().Set("Content-Type", "text/html")
+	if serviceKey == "" {
+		w.Write([]byte(`<p class="text-sm text-gray-500">Choose a service to see available arrival times.</p>`))
+		return
+	}
+
+	bookingMu.Lock()
+	slots := webBookingSlots(serviceKey, time.Now())
+	bookingMu.Unlock()
+
+	if len(slots) == 0 {
+		w.Write([]byte(`<p class="text-sm text-gray-500">No arrival times are open this week. Submit your request and we'll call you to schedule.</p>`))
+		return
+	}
+
+	html := `<label class="block text-sm font-medium text-gray-700 mb-1">Arrival Window (optional)</label>
+	<div class="max-h-40 overflow-y-auto space-y-1">`
+	for _, slot := range slots {
+		html += fmt.Sprintf(`
+		<label class="flex items-center space-x-2 text-sm">
+			<input type="radio" name="slot_start" value="%s"
+			       hx-post="/api/booking/hold" hx-trigger="change" hx-target="#booking-hold" hx-include="[name='service'],[name='hold_id'],[name='hold_token']">
+			<span>%s</span>
+		</label>`, slot.Start.Format(time.RFC3339), template.HTMLEscapeString(services.FormatArrivalWindow(slot.Start, slot.End, time.Local)))
+	}
+	html += `
+	</div>
+	<div id="booking-hold"></div>`
+
+	w.Write([]byte(html))
+}
+
+// holdBookingSlot holds the picked window, replacing the visitor's previous hold
+func holdBookingSlot(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+	start, err := time.Parse(time.RFC3339, r.FormValue("slot_start"))
+	if err != nil {
+		http.Error(w, "Invalid arrival window", http.StatusBadRequest)
+		return
+	}
+	serviceKey := r.FormValue("service")
+
+	bookingMu.Lock()
+	defer bookingMu.Unlock()
+
+	now := time.Now()
+	if previous := activeWebHold(r.FormValue("hold_id"), r.FormValue("hold_token"), now); previous != nil {
+		previous.Status = domain.BookingHoldStatusReleased
+	}
+	expireWebHolds(now)
+
+	var slot *services.BookingSlot
+	for _, candidate := range webBookingSlots(serviceKey, now) {
+		if candidate.Start.Equal(start) {
+			slot = &candidate
+			break
+		}
+	}
+	w.Header().Set("Content-Type", "text/html")
+	if slot == nil {
+		w.Write([]byte(`<p class="text-sm text-red-600 mt-1">Sorry, that window was just taken. Please pick another.</p>`))
+		return
+	}
+
+	token := uuid.New().String()
+	hold := &domain.BookingHold{
+		ID:              uuid.New(),
+		TenantID:        leadTenantID,
+		CrewID:          slot.CrewIDs[0],
+		WindowStart:     slot.Start,
+		WindowEnd:       slot.End,
+		JobStart:        slot.Start,
+		JobEnd:          slot.Start.Add(bookingDuration(serviceKey)),
+		ServiceKey:      &serviceKey,
+		DurationMinutes: int(bookingDuration(serviceKey).Minutes()),
+		Token:           services.HashPortalToken(token),
+		Status:          domain.BookingHoldStatusHeld,
+		ExpiresAt:       now.Add(time.Duration(bookingSettings.HoldMinutes) * time.Minute),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	bookingHolds[hold.ID] = hold
+
+	w.Write([]byte(fmt.Sprintf(`
+	<input type="hidden" name="hold_id" value="%s">
+	<input type="hidden" name="hold_token" value="%s">
+	<p class="text-sm text-green-700 mt-1">We're holding this time for you until %s.</p>`,
+		hold.ID, token, hold.ExpiresAt.Format("3:04 PM"))))
+}
+
+// confirmWebHold turns a held window into a tentative job for the lead, returning
+// nil when the hold has lapsed
+func confirmWebHold(r *http.Request, lead *domain.Lead) *domain.BookingHold {
+	bookingMu.Lock()
+	defer bookingMu.Unlock()
+
+	hold := activeWebHold(r.FormValue("hold_id"), r.FormValue("hold_token"), time.Now())
+	if hold == nil {
+		return nil
+	}
+
+	if lead.Status == domain.LeadStatusSpam {
+		hold.Status = domain.BookingHoldStatusReleased
+		return nil
+	}
+
+	teamID := ""
+	for id := range teams {
+		if teamCrewID(id) == hold.CrewID {
+			teamID = id
+		}
+	}
+
+	jobID := fmt.Sprintf("job-%d", time.Now().UnixNano())
+	jobs[jobID] = Job{
+		ID:               jobID,
+		ServiceRequestID: lead.ID.String(),
+		ServiceID:        stringValue(hold.ServiceKey),
+		TeamID:           teamID,
+		Status:           "tentative",
+		ScheduledAt:      hold.JobStart,
+		Notes:            "Booked online. Arrival window " + services.FormatArrivalWindow(hold.WindowStart, hold.WindowEnd, time.Local),
+		Duration:         hold.DurationMinutes,
+	}
+
+	hold.Status = domain.BookingHoldStatusConfirmed
+	hold.LeadID = &lead.ID
+	hold.UpdatedAt = time.Now()
+
+	if _, err := leadService.UpdateLeadStatus(leadContext(r), lead.ID, domain.LeadStatusScheduled); err != nil {
+		log.Printf("Failed to mark lead %s scheduled: %v", lead.ID, err)
+	}
+
+	return hold
+}
+
+// activeWebHold finds a hold by ID and token. Callers hold bookingMu.
+func activeWebHold(holdID, token string, now time.Time) *domain.BookingHold {
+	id, err := uuid.Parse(holdID)
+	if err != nil || token == "" {
+		return nil
+	}
+	hold, ok := bookingHolds[id]
+	if !ok || hold.Token != services.HashPortalToken(token) || !hold.IsActive(now) {
+		return nil
+	}
+	return hold
+}
+
+// expireWebHolds drops holds that no longer block a window. Callers hold bookingMu.
+func expireWebHolds(now time.Time) {
+	for id, hold := range bookingHolds {
+		if !hold.IsActive(now) {
+			delete(bookingHolds, id)
+		}
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
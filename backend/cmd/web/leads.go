@@ -0,0 +1,457 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// Booking and consultation requests are stored as leads. With DATABASE_URL and
+// SITE_TENANT_ID (or PRICING_TENANT_ID) set they are persisted to the tenant's leads
+// table and audited; otherwise they are kept in memory for local development.
+// Converting a lead into a customer, property and quote is done through the API's
+// /leads/{id}/accept, since this site has no CRM services of its own.
+var (
+	leadService  services.LeadService
+	leadTenantID uuid.UUID
+)
+
+// initLeads sets up lead storage
+func initLeads() {
+	logger := log.New(os.Stdout, "[leads] ", log.LstdFlags)
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	tenantIDStr := os.Getenv("SITE_TENANT_ID")
+	if tenantIDStr == "" {
+		tenantIDStr = os.Getenv("PRICING_TENANT_ID")
+	}
+
+	if databaseURL != "" && tenantIDStr != "" {
+		tenantID, err := uuid.Parse(tenantIDStr)
+		if err != nil {
+			log.Printf("Leads: invalid SITE_TENANT_ID, keeping leads in memory: %v", err)
+		} else if db, err := sql.Open("postgres", databaseURL); err != nil {
+			log.Printf("Leads: failed to open database, keeping leads in memory: %v", err)
+		} else {
+			leadTenantID = tenantID
+			leadService = services.NewLeadService(&sqlLeadRepository{db: db}, nil, nil, nil, nil, nil,
+				services.NewAuditService(db, logger), logger)
+			log.Printf("Leads: storing leads for tenant %s", tenantID)
+			return
+		}
+	}
+
+	leadTenantID = uuid.New()
+	leadService = services.NewLeadService(newMemoryLeadRepository(), nil, nil, nil, nil, nil,
+		logAuditService{logger: logger}, logger)
+	log.Println("Leads: keeping leads in memory")
+}
+
+// leadContext scopes a request to the site's tenant
+func leadContext(r *http.Request) context.Context {
+	return context.WithValue(r.Context(), "tenant_id", leadTenantID)
+}
+
+// listServiceRequests returns the lead queue for the admin dashboard, pending first
+func listServiceRequests(r *http.Request) []ServiceRequest {
+	result, err := leadService.ListLeads(leadContext(r), &services.LeadFilter{BaseFilter: services.BaseFilter{PerPage: 100}})
+	if err != nil {
+		log.Printf("Failed to list leads: %v", err)
+		return nil
+	}
+
+	leads, _ := result.Data.([]*domain.Lead)
+	requests := make([]ServiceRequest, 0, len(leads))
+	for _, lead := range leads {
+		requests = append(requests, serviceRequestFromLead(lead))
+	}
+	return requests
+}
+
+// getServiceRequest looks up a lead by the ID shown on the dashboard
+func getServiceRequest(r *http.Request, requestID string) (*domain.Lead, bool) {
+	leadID, err := uuid.Parse(requestID)
+	if err != nil {
+		return nil, false
+	}
+	lead, err := leadService.GetLead(leadContext(r), leadID)
+	if err != nil {
+		return nil, false
+	}
+	return lead, true
+}
+
+func serviceRequestFromLead(lead *domain.Lead) ServiceRequest {
+	request := ServiceRequest{
+		ID:            lead.ID.String(),
+		CustomerName:  lead.FullName(),
+		CustomerEmail: derefString(lead.Email),
+		CustomerPhone: derefString(lead.Phone),
+		ServiceType:   derefString(lead.ServiceKey),
+		Message:       derefString(lead.Message),
+		Status:        lead.Status,
+		CreatedAt:     lead.CreatedAt,
+		PropertyInfo: map[string]interface{}{
+			"source":  lead.Source,
+			"address": derefString(lead.AddressLine1),
+			"zip":     derefString(lead.ZipCode),
+		},
+	}
+	if customerID, ok := findCustomer(request.CustomerEmail); ok {
+		request.CustomerID = customerID
+	}
+	if lead.EstimatedPrice != nil {
+		request.EstimatedPrice = *lead.EstimatedPrice
+	}
+	return request
+}
+
+// findCustomer looks up a site customer by email
+func findCustomer(email string) (string, bool) {
+	if email == "" {
+		return "", false
+	}
+	for id, customer := range customers {
+		if strings.EqualFold(customer.Email, email) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// findOrCreateCustomer adds an accepted lead's contact to the site's customer list
+func findOrCreateCustomer(lead *domain.Lead) string {
+	email := derefString(lead.Email)
+	if customerID, ok := findCustomer(email); ok {
+		return customerID
+	}
+
+	customerID := fmt.Sprintf("cust_%d", len(customers)+1)
+	customers[customerID] = Customer{
+		ID:        customerID,
+		FirstName: lead.FirstName,
+		LastName:  lead.LastName,
+		Email:     email,
+		Phone:     derefString(lead.Phone),
+		Address:   derefString(lead.AddressLine1),
+		City:      derefString(lead.City),
+		State:     derefString(lead.State),
+		ZipCode:   derefString(lead.ZipCode),
+		CreatedAt: time.Now(),
+	}
+	return customerID
+}
+
+// leadClientInfo returns the submitter's address and user agent
+func leadClientInfo(r *http.Request) (string, string) {
+	ip := r.RemoteAddr
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		ip = strings.TrimSpace(strings.Split(xff, ",")[0])
+	} else if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+	return ip, r.UserAgent()
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// logAuditService records lead audit events in the log when there is no database
+type logAuditService struct {
+	services.AuditService
+	logger *log.Logger
+}
+
+func (a logAuditService) LogAction(ctx context.Context, req *services.AuditLogRequest) error {
+	a.logger.Printf("audit: %s %s %v", req.Action, req.ResourceType, req.NewValues)
+	return nil
+}
+
+// sqlLeadRepository stores leads in Postgres
+type sqlLeadRepository struct {
+	db *sql.DB
+}
+
+const webLeadColumns = `
+	id, tenant_id, source, status, first_name, last_name, email, phone, service_key,
+	service_id, message, address_line1, city, state, zip_code, property_size,
+	estimated_price, spam_score, spam_reasons, duplicate_of_id, matched_customer_id,
+	customer_id, property_id, quote_id, ip_address, user_agent, metadata, reviewed_by,
+	reviewed_at, denial_reason, created_at, updated_at`
+
+func (s *sqlLeadRepository) Create(ctx context.Context, lead *domain.Lead) error {
+	reasonsJSON, err := json.Marshal(lead.SpamReasons)
+	if err != nil {
+		return fmt.Errorf("failed to marshal spam reasons: %w", err)
+	}
+	metadataJSON, err := json.Marshal(lead.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO leads (`+webLeadColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+			$17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32)`,
+		lead.ID, lead.TenantID, lead.Source, lead.Status, lead.FirstName, lead.LastName,
+		lead.Email, lead.Phone, lead.ServiceKey, lead.ServiceID, lead.Message,
+		lead.AddressLine1, lead.City, lead.State, lead.ZipCode, lead.PropertySize,
+		lead.EstimatedPrice, lead.SpamScore, reasonsJSON, lead.DuplicateOfID,
+		lead.MatchedCustomerID, lead.CustomerID, lead.PropertyID, lead.QuoteID,
+		lead.IPAddress, lead.UserAgent, metadataJSON, lead.ReviewedBy, lead.ReviewedAt,
+		lead.DenialReason, lead.CreatedAt, lead.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create lead: %w", err)
+	}
+	return nil
+}
+
+func (s *sqlLeadRepository) GetByID(ctx context.Context, tenantID, leadID uuid.UUID) (*domain.Lead, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+webLeadColumns+` FROM leads WHERE tenant_id = $1 AND id = $2`, tenantID, leadID)
+	lead, err := scanWebLead(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lead: %w", err)
+	}
+	return lead, nil
+}
+
+func (s *sqlLeadRepository) Update(ctx context.Context, lead *domain.Lead) error {
+	metadataJSON, err := json.Marshal(lead.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE leads SET
+			status = $3, service_id = $4, duplicate_of_id = $5, matched_customer_id = $6,
+			customer_id = $7, property_id = $8, quote_id = $9, metadata = $10,
+			reviewed_by = $11, reviewed_at = $12, denial_reason = $13, updated_at = $14
+		WHERE tenant_id = $1 AND id = $2`,
+		lead.TenantID, lead.ID, lead.Status, lead.ServiceID, lead.DuplicateOfID,
+		lead.MatchedCustomerID, lead.CustomerID, lead.PropertyID, lead.QuoteID,
+		metadataJSON, lead.ReviewedBy, lead.ReviewedAt, lead.DenialReason, lead.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update lead: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("lead not found")
+	}
+	return nil
+}
+
+func (s *sqlLeadRepository) List(ctx context.Context, tenantID uuid.UUID, filter *services.LeadFilter) ([]*domain.Lead, int64, error) {
+	where := "tenant_id = $1 AND status <> 'spam'"
+	args := []interface{}{tenantID}
+	if filter.Status != "" {
+		where = "tenant_id = $1 AND status = $2"
+		args = append(args, filter.Status)
+	}
+
+	var total int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM leads WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count leads: %w", err)
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT `+webLeadColumns+` FROM leads WHERE `+where+`
+		ORDER BY (status = 'pending') DESC, created_at DESC
+		LIMIT $%d OFFSET $%d`, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list leads: %w", err)
+	}
+	defer rows.Close()
+
+	leads, err := scanWebLeads(rows)
+	return leads, total, err
+}
+
+func (s *sqlLeadRepository) FindRecentByContact(ctx context.Context, tenantID uuid.UUID, email, phoneDigits string, since time.Time) ([]*domain.Lead, error) {
+	if email == "" && phoneDigits == "" {
+		return nil, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+webLeadColumns+` FROM leads
+		WHERE tenant_id = $1 AND created_at >= $2
+			AND (($3 <> '' AND LOWER(email) = LOWER($3)) OR
+				($4 <> '' AND regexp_replace(phone, '\D', '', 'g') IN ($4, '1' || $4)))
+		ORDER BY created_at DESC`,
+		tenantID, since, email, phoneDigits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find leads by contact: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebLeads(rows)
+}
+
+func (s *sqlLeadRepository) CountByIPSince(ctx context.Context, tenantID uuid.UUID, ipAddress string, since time.Time) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM leads WHERE tenant_id = $1 AND ip_address = $2::inet AND created_at >= $3`,
+		tenantID, ipAddress, since).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count leads by address: %w", err)
+	}
+	return count, nil
+}
+
+func scanWebLeads(rows *sql.Rows) ([]*domain.Lead, error) {
+	var leads []*domain.Lead
+	for rows.Next() {
+		lead, err := scanWebLead(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan lead: %w", err)
+		}
+		leads = append(leads, lead)
+	}
+	return leads, rows.Err()
+}
+
+func scanWebLead(row interface{ Scan(...interface{}) error }) (*domain.Lead, error) {
+	var lead domain.Lead
+	var reasonsJSON, metadataJSON []byte
+	if err := row.Scan(
+		&lead.ID, &lead.TenantID, &lead.Source, &lead.Status, &lead.FirstName, &lead.LastName,
+		&lead.Email, &lead.Phone, &lead.ServiceKey, &lead.ServiceID, &lead.Message,
+		&lead.AddressLine1, &lead.City, &lead.State, &lead.ZipCode, &lead.PropertySize,
+		&lead.EstimatedPrice, &lead.SpamScore, &reasonsJSON, &lead.DuplicateOfID,
+		&lead.MatchedCustomerID, &lead.CustomerID, &lead.PropertyID, &lead.QuoteID,
+		&lead.IPAddress, &lead.UserAgent, &metadataJSON, &lead.ReviewedBy, &lead.ReviewedAt,
+		&lead.DenialReason, &lead.CreatedAt, &lead.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if len(reasonsJSON) > 0 {
+		if err := json.Unmarshal(reasonsJSON, &lead.SpamReasons); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal spam reasons: %w", err)
+		}
+	}
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &lead.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+	}
+	return &lead, nil
+}
+
+// memoryLeadRepository keeps leads in memory when no database is configured
+type memoryLeadRepository struct {
+	mu    sync.RWMutex
+	leads map[uuid.UUID]domain.Lead
+}
+
+func newMemoryLeadRepository() *memoryLeadRepository {
+	return &memoryLeadRepository{leads: make(map[uuid.UUID]domain.Lead)}
+}
+
+func (m *memoryLeadRepository) Create(ctx context.Context, lead *domain.Lead) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.leads[lead.ID] = *lead
+	return nil
+}
+
+func (m *memoryLeadRepository) GetByID(ctx context.Context, tenantID, leadID uuid.UUID) (*domain.Lead, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	lead, ok := m.leads[leadID]
+	if !ok || lead.TenantID != tenantID {
+		return nil, nil
+	}
+	return &lead, nil
+}
+
+func (m *memoryLeadRepository) Update(ctx context.Context, lead *domain.Lead) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.leads[lead.ID]; !ok {
+		return fmt.Errorf("lead not found")
+	}
+	m.leads[lead.ID] = *lead
+	return nil
+}
+
+func (m *memoryLeadRepository) List(ctx context.Context, tenantID uuid.UUID, filter *services.LeadFilter) ([]*domain.Lead, int64, error) {
+	m.mu.RLock()
+	var leads []*domain.Lead
+	for _, lead := range m.leads {
+		lead := lead
+		if lead.TenantID != tenantID {
+			continue
+		}
+		if (filter.Status == "" && lead.Status == domain.LeadStatusSpam) || (filter.Status != "" && lead.Status != filter.Status) {
+			continue
+		}
+		leads = append(leads, &lead)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(leads, func(i, j int) bool {
+		iPending, jPending := leads[i].Status == domain.LeadStatusPending, leads[j].Status == domain.LeadStatusPending
+		if iPending != jPending {
+			return iPending
+		}
+		return leads[i].CreatedAt.After(leads[j].CreatedAt)
+	})
+
+	total := int64(len(leads))
+	start := (filter.Page - 1) * filter.PerPage
+	if start > len(leads) {
+		start = len(leads)
+	}
+	end := start + filter.PerPage
+	if end > len(leads) {
+		end = len(leads)
+	}
+	return leads[start:end], total, nil
+}
+
+func (m *memoryLeadRepository) FindRecentByContact(ctx context.Context, tenantID uuid.UUID, email, phoneDigits string, since time.Time) ([]*domain.Lead, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var leads []*domain.Lead
+	for _, lead := range m.leads {
+		lead := lead
+		if lead.TenantID == tenantID && !lead.CreatedAt.Before(since) {
+			leads = append(leads, &lead)
+		}
+	}
+	// The service matches the contact details itself
+	return leads, nil
+}
+
+func (m *memoryLeadRepository) CountByIPSince(ctx context.Context, tenantID uuid.UUID, ipAddress string, since time.Time) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	count := 0
+	for _, lead := range m.leads {
+		if lead.TenantID == tenantID && lead.IPAddress != nil && *lead.IPAddress == ipAddress && !lead.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -11,11 +11,13 @@ import (
 	"time"
 	"strings"
 	"strconv"
-	"math"
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/sessions"
 	"golang.org/x/crypto/bcrypt"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
 )
 
 type PageData struct {
@@ -74,7 +76,7 @@ type Job struct {
 	CustomerID  string    `json:"customer_id"`
 	ServiceID   string    `json:"service_id"`
 	TeamID      string    `json:"team_id"`
-	Status      string    `json:"status"` // scheduled, in-progress, completed, cancelled
+	Status      string    `json:"status"` // tentative, scheduled, in-progress, completed, cancelled
 	ScheduledAt time.Time `json:"scheduled_at"`
 	CompletedAt *time.Time `json:"completed_at"`
 	Notes       string    `json:"notes"`
@@ -141,7 +143,7 @@ type PricingResponse struct {
 	EstimatedHours   float64            `json:"estimated_hours"`
 }
 
-// Service pricing configuration
+// Service catalog configuration; tenant price books override the default rates
 type ServiceConfig struct {
 	ID         string
 	Name       string
@@ -202,7 +204,7 @@ var jobs = make(map[string]Job)
 var teams = make(map[string]Team)
 var employees = make(map[string]Employee)
 
-// Pricing configuration
+// Service catalog
 var serviceConfigs = map[string]ServiceConfig{
 	"lawn_care": {
 		ID:         "lawn_care",
@@ -236,112 +238,27 @@ var serviceConfigs = map[string]ServiceConfig{
 	},
 }
 
+// Base hourly rates by property size (square feet)
+var propertySizeRates = []struct {
+	Name     string
+	MaxSize  float64 // exclusive upper bound; zero for the largest tier
+	BaseRate float64
+}{
+	{"Small property", 2000, 45.0},
+	{"Medium property", 5001, 55.0},
+	{"Large property", 0, 65.0},
+}
+
 // Geographic zones for pricing
 var geographicZones = map[string]float64{
 	// Zone A (local) - base rate
 	"12345": 1.0, "12346": 1.0, "12347": 1.0,
 	// Zone B (15+ miles) - 25% surcharge
 	"12400": 1.25, "12401": 1.25, "12402": 1.25,
-	// Zone C (30+ miles) - 50% surcharge  
+	// Zone C (30+ miles) - 50% surcharge
 	"12500": 1.5, "12501": 1.5, "12502": 1.5,
 }
 
-// Intelligent pricing algorithm
-func calculateIntelligentPricing(req PricingRequest) PricingResponse {
-	response := PricingResponse{
-		ServiceBreakdown: make(map[string]float64),
-		Discounts:        make(map[string]float64),
-		Surcharges:       make(map[string]float64),
-	}
-	
-	// 1. Determine base rate by property size
-	baseRate := getBaseRateByPropertySize(req.PropertySize)
-	response.BaseRate = baseRate
-	
-	var totalHours float64
-	var subtotal float64
-	
-	// 2. Calculate service costs with complexity multipliers
-	for _, serviceType := range req.ServiceTypes {
-		if config, exists := serviceConfigs[serviceType]; exists {
-			// Calculate service cost: base_rate * multiplier * estimated_hours
-			serviceCost := baseRate * config.Multiplier * config.BaseHours
-			response.ServiceBreakdown[config.Name] = serviceCost
-			subtotal += serviceCost
-			totalHours += config.BaseHours
-		}
-	}
-	
-	response.Subtotal = subtotal
-	response.EstimatedHours = totalHours
-	
-	// 3. Apply frequency discounts
-	if discount := getFrequencyDiscount(req.Frequency); discount > 0 {
-		discountAmount := subtotal * discount
-		response.Discounts["Frequency Discount"] = discountAmount
-		subtotal -= discountAmount
-	}
-	
-	// 4. Apply geographic zone pricing
-	if zoneMultiplier, exists := geographicZones[req.ZipCode]; exists && zoneMultiplier > 1.0 {
-		surchargeAmount := subtotal * (zoneMultiplier - 1.0)
-		response.Surcharges["Geographic Zone"] = surchargeAmount
-		subtotal += surchargeAmount
-	}
-	
-	// 5. Apply smart discounting
-	
-	// Same-day discount: 15% off if booking within same geographic area
-	if req.SameDayBooking {
-		discountAmount := subtotal * 0.15
-		response.Discounts["Same-Day Booking"] = discountAmount
-		subtotal -= discountAmount
-	}
-	
-	// Route premium: 25% surcharge for jobs outside efficient routes
-	if req.Distance > 15 {
-		surchargeAmount := subtotal * 0.25
-		response.Surcharges["Route Premium"] = surchargeAmount
-		subtotal += surchargeAmount
-	}
-	
-	// Bulk discount: 10% off for multiple services
-	if req.MultipleServices && len(req.ServiceTypes) > 1 {
-		discountAmount := subtotal * 0.10
-		response.Discounts["Multiple Services"] = discountAmount
-		subtotal -= discountAmount
-	}
-	
-	response.TotalAmount = math.Round(subtotal*100) / 100 // Round to 2 decimal places
-	
-	return response
-}
-
-// Get base hourly rate by property size
-func getBaseRateByPropertySize(squareFootage int) float64 {
-	if squareFootage < 2000 {
-		return 45.0 // Small properties
-	} else if squareFootage <= 5000 {
-		return 55.0 // Medium properties  
-	} else {
-		return 65.0 // Large properties
-	}
-}
-
-// Get frequency discount percentage
-func getFrequencyDiscount(frequency string) float64 {
-	switch strings.ToLower(frequency) {
-	case "weekly":
-		return 0.15 // 15% off
-	case "bi-weekly", "biweekly":
-		return 0.10 // 10% off
-	case "monthly":
-		return 0.05 // 5% off
-	default:
-		return 0.0 // No discount for one-time
-	}
-}
-
 // Admin middleware
 func requireAuth(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -392,6 +309,9 @@ func requireAdmin(next http.HandlerFunc) http.HandlerFunc {
 func main() {
 	// Initialize basic teams and employees only
 	initializeTeamsAndEmployees()
+	initPricing()
+	initLeads()
+	initBooking()
 	
 	r := mux.NewRouter()
 
@@ -538,6 +458,8 @@ func main() {
 	r.HandleFunc("/api/testimonials/featured", getFeaturedTestimonials).Methods("GET")
 	r.HandleFunc("/api/auth/status", getAuthStatus).Methods("GET")
 	r.HandleFunc("/api/booking/submit", submitBooking).Methods("POST")
+	r.HandleFunc("/api/booking/hold", holdBookingSlot).Methods("POST")
+	r.HandleFunc("/booking/slots", getBookingSlots).Methods("GET")
 	r.HandleFunc("/booking/form", getBookingForm).Methods("GET")
 	r.HandleFunc("/booking/consultation", getConsultationForm).Methods("GET")
 	
@@ -897,6 +819,25 @@ func getAuthStatus(w http.ResponseWriter, r *http.Request) {
 }
 
 func getBookingForm(w http.ResponseWriter, r *http.Request) {
+	renderLeadForm(w, "Request a Quote", domain.LeadSourceWebsiteBooking)
+}
+
+func getConsultationForm(w http.ResponseWriter, r *http.Request) {
+	renderLeadForm(w, "Schedule a Consultation", domain.LeadSourceWebsiteConsultation)
+}
+
+// renderLeadForm renders the booking form. The hidden "website" field and render
+// time let the lead service spot automated submissions. Booking requests can also
+// pick an arrival window.
+func renderLeadForm(w http.ResponseWriter, title, source string) {
+	serviceAttrs, slotPicker := "", ""
+	if source == domain.LeadSourceWebsiteBooking {
+		serviceAttrs = ` hx-get="/booking/slots" hx-trigger="change" hx-target="#booking-slots"`
+		slotPicker = `
+				<div id="booking-slots"></div>
+				`
+	}
+
 	html := `
 	<div class="fixed inset-0 bg-black bg-opacity-50 flex items-center justify-center p-4 z-50"
 	     x-data="{ open: true }"
@@ -904,7 +845,7 @@ func getBookingForm(w http.ResponseWriter, r *http.Request) {
 	     @click.away="open = false">
 		<div class="bg-white rounded-lg max-w-md w-full p-6" @click.stop>
 			<div class="flex justify-between items-center mb-4">
-				<h2 class="text-2xl font-bold">Request a Quote</h2>
+				<h2 class="text-2xl font-bold">` + title + `</h2>
 				<button @click="open = false" class="text-gray-500 hover:text-gray-700">
 					<svg class="w-6 h-6" fill="none" stroke="currentColor" viewBox="0 0 24 24">
 						<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M6 18L18 6M6 6l12 12"></path>
@@ -915,6 +856,13 @@ func getBookingForm(w http.ResponseWriter, r *http.Request) {
 			<form hx-post="/api/booking/submit" 
 			      hx-swap="outerHTML"
 			      class="space-y-4">
+				<input type="hidden" name="source" value="` + source + `">
+				<input type="hidden" name="form_rendered_at" value="` + strconv.FormatInt(time.Now().Unix(), 10) + `">
+				<div style="position:absolute;left:-10000px" aria-hidden="true">
+					<label>Website</label>
+					<input type="text" name="website" tabindex="-1" autocomplete="off">
+				</div>
+				
 				<div>
 					<label class="block text-sm font-medium text-gray-700 mb-1">Name</label>
 					<input type="text" name="name" required 
@@ -933,9 +881,22 @@ func getBookingForm(w http.ResponseWriter, r *http.Request) {
 					       class="w-full px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-primary">
 				</div>
 				
+				<div class="grid grid-cols-3 gap-2">
+					<div class="col-span-2">
+						<label class="block text-sm font-medium text-gray-700 mb-1">Street Address</label>
+						<input type="text" name="address"
+						       class="w-full px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-primary">
+					</div>
+					<div>
+						<label class="block text-sm font-medium text-gray-700 mb-1">ZIP</label>
+						<input type="text" name="zip_code"
+						       class="w-full px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-primary">
+					</div>
+				</div>
+				
 				<div>
 					<label class="block text-sm font-medium text-gray-700 mb-1">Service Needed</label>
-					<select name="service" required
+					<select name="service" required` + serviceAttrs + `
 					        class="w-full px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-primary">
 						<option value="">Select a service</option>
 						<option value="lawn_care">Lawn Care</option>
@@ -945,7 +906,7 @@ func getBookingForm(w http.ResponseWriter, r *http.Request) {
 						<option value="other">Other</option>
 					</select>
 				</div>
-				
+				` + slotPicker + `
 				<div>
 					<label class="block text-sm font-medium text-gray-700 mb-1">Message</label>
 					<textarea name="message" rows="3"
@@ -964,11 +925,6 @@ func getBookingForm(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(html))
 }
 
-func getConsultationForm(w http.ResponseWriter, r *http.Request) {
-	// Similar to booking form but for consultation
-	getBookingForm(w, r)
-}
-
 func submitBooking(w http.ResponseWriter, r *http.Request) {
 	// Parse form data
 	err := r.ParseForm()
@@ -977,70 +933,43 @@ func submitBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	name := r.FormValue("name")
-	email := r.FormValue("email")
-	phone := r.FormValue("phone")
-	service := r.FormValue("service")
-	message := r.FormValue("message")
-
-	// Create real service request
-	requestID := fmt.Sprintf("req_%d", len(serviceRequests)+1)
-	serviceRequest := ServiceRequest{
-		ID:           requestID,
-		CustomerName: name,
-		CustomerEmail: email,
-		CustomerPhone: phone,
-		ServiceType:  service,
-		Message:      message,
-		Status:       "pending",
-		CreatedAt:    time.Now(),
-		PropertyInfo: map[string]interface{}{
-			"contact_method": "web_form",
-		},
+	ipAddress, userAgent := leadClientInfo(r)
+	submission := &services.LeadSubmission{
+		Source:       r.FormValue("source"),
+		Name:         r.FormValue("name"),
+		Email:        r.FormValue("email"),
+		Phone:        r.FormValue("phone"),
+		ServiceKey:   r.FormValue("service"),
+		Message:      r.FormValue("message"),
+		AddressLine1: r.FormValue("address"),
+		ZipCode:      r.FormValue("zip_code"),
+		Honeypot:     r.FormValue("website"),
+		IPAddress:    ipAddress,
+		UserAgent:    userAgent,
 	}
-
-	// Store the service request
-	serviceRequests[requestID] = serviceRequest
-
-	// Check if customer already exists or create new one
-	var customerID string
-	customerExists := false
-	
-	// Look for existing customer by email
-	for id, customer := range customers {
-		if customer.Email == email {
-			customerID = id
-			customerExists = true
-			break
-		}
+	if renderedAt, err := strconv.ParseInt(r.FormValue("form_rendered_at"), 10, 64); err == nil {
+		t := time.Unix(renderedAt, 0)
+		submission.FormRenderedAt = &t
 	}
 
-	// Create new customer if doesn't exist
-	if !customerExists {
-		customerID = fmt.Sprintf("cust_%d", len(customers)+1)
-		customer := Customer{
-			ID:           customerID,
-			FirstName:    name, // We'll split this later if needed
-			LastName:     "",   // Single name field for now
-			Email:        email,
-			Phone:        phone,
-			Address:      "",   // Will be collected later
-			City:         "",
-			State:        "",
-			ZipCode:      "",
-			CreatedAt:    time.Now(),
-			LastService:  time.Time{}, // Empty initially
-			TotalSpent:   0.0,
-			ServiceCount: 0,
-		}
-		customers[customerID] = customer
+	// Spam and duplicates are stored for review; the visitor sees the same confirmation
+	lead, err := leadService.SubmitLead(leadContext(r), submission)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to submit request: %v", err), http.StatusBadRequest)
+		return
 	}
 
-	// Update service request with customer ID
-	serviceRequest.CustomerID = customerID
-	serviceRequests[requestID] = serviceRequest
+	log.Printf("New service request created: %s from %s (%s) for %s", lead.ID, lead.FullName(), submission.Email, submission.ServiceKey)
 
-	log.Printf("New service request created: %s from %s (%s) for %s", requestID, name, email, service)
+	followUp := "We'll contact you within 24 hours at " + template.HTMLEscapeString(submission.Email) + " to schedule your consultation."
+	if r.FormValue("hold_id") != "" {
+		if hold := confirmWebHold(r, lead); hold != nil {
+			followUp = "You're booked for " + template.HTMLEscapeString(services.FormatArrivalWindow(hold.WindowStart, hold.WindowEnd, time.Local)) +
+				". We'll confirm your appointment at " + template.HTMLEscapeString(submission.Email) + "."
+		} else if lead.Status != domain.LeadStatusSpam {
+			followUp = "Your arrival window expired before the request was sent, so we'll contact you at " + template.HTMLEscapeString(submission.Email) + " to schedule."
+		}
+	}
 
 	// Return success response that closes the modal
 	html := `
@@ -1049,7 +978,7 @@ func submitBooking(w http.ResponseWriter, r *http.Request) {
 			<div class="text-center">
 				<div class="text-green-600 text-4xl mb-4">✅</div>
 				<h3 class="text-xl font-bold text-green-800 mb-2">Request Submitted!</h3>
-				<p class="text-green-700 mb-4">Thank you ` + name + `! We'll contact you within 24 hours at ` + email + ` to schedule your consultation. Your request ID is: ` + requestID + `</p>
+				<p class="text-green-700 mb-4">Thank you ` + template.HTMLEscapeString(lead.FirstName) + `! ` + followUp + ` Your request ID is: ` + lead.ID.String()[:8] + `</p>
 				<button onclick="document.getElementById('booking-modal').innerHTML = ''" 
 				        class="bg-green-600 text-white px-6 py-2 rounded-lg hover:bg-green-700 transition">
 					Close
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// Price books are read from the database when DATABASE_URL and PRICING_TENANT_ID are
+// set, so the public calculator prices with the same rules as quotes. Otherwise the
+// built-in default price book is used.
+var (
+	pricingDB       *sql.DB
+	pricingTenantID uuid.UUID
+)
+
+// initPricing connects to the tenant's price books if configured
+func initPricing() {
+	databaseURL := os.Getenv("DATABASE_URL")
+	tenantIDStr := os.Getenv("PRICING_TENANT_ID")
+	if databaseURL == "" || tenantIDStr == "" {
+		log.Println("Pricing: using default price book")
+		return
+	}
+
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		log.Printf("Pricing: invalid PRICING_TENANT_ID, using default price book: %v", err)
+		return
+	}
+
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		log.Printf("Pricing: failed to open database, using default price book: %v", err)
+		return
+	}
+
+	pricingDB = db
+	pricingTenantID = tenantID
+	log.Printf("Pricing: using price books for tenant %s", tenantID)
+}
+
+// currentPriceBooks returns the price books in effect on a date, falling back to the
+// default price book when the database is unavailable or has none
+func currentPriceBooks(date time.Time) []*domain.PriceBook {
+	if pricingDB != nil {
+		books, err := services.LoadPriceBooksInEffect(context.Background(), pricingDB, pricingTenantID, date)
+		if err != nil {
+			log.Printf("Pricing: failed to load price books, using default: %v", err)
+		} else if len(books) > 0 {
+			return books
+		}
+	}
+	return []*domain.PriceBook{defaultPriceBook()}
+}
+
+// defaultPriceBook is the standard price book: hourly rates by property size and
+// service complexity, frequency and bundle discounts, and zone and route surcharges
+func defaultPriceBook() *domain.PriceBook {
+	hour := domain.PricingUnitHour
+	book := &domain.PriceBook{
+		Name:          "Standard pricing",
+		EffectiveFrom: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		IsActive:      true,
+	}
+
+	rule := func(name, ruleType string, rate float64) *domain.PricingRule {
+		book.Rules = append(book.Rules, domain.PricingRule{Name: name, RuleType: ruleType, Rate: rate, IsActive: true})
+		return &book.Rules[len(book.Rules)-1]
+	}
+	float := func(v float64) *float64 { return &v }
+	str := func(v string) *string { return &v }
+
+	// Hourly rates: small, medium and large properties, scaled by service complexity
+	for key, config := range serviceConfigs {
+		var lower *float64
+		for _, tier := range propertySizeRates {
+			r := rule(tier.Name, domain.PricingRuleSizeTier, tier.BaseRate*config.Multiplier)
+			r.ServiceKey, r.Unit, r.MinValue = str(key), &hour, lower
+			if tier.MaxSize > 0 {
+				r.MaxValue = float(tier.MaxSize)
+			}
+			lower = r.MaxValue
+		}
+	}
+
+	for frequency, rate := range map[string]float64{"weekly": 0.15, "biweekly": 0.10, "monthly": 0.05} {
+		rule("Frequency Discount", domain.PricingRuleFrequencyDiscount, rate).Frequency = str(frequency)
+	}
+
+	// One surcharge rule per zone outside the local area
+	zones := make(map[float64][]string)
+	for zipCode, multiplier := range geographicZones {
+		if multiplier > 1.0 {
+			zones[multiplier] = append(zones[multiplier], zipCode)
+		}
+	}
+	multipliers := make([]float64, 0, len(zones))
+	for multiplier := range zones {
+		multipliers = append(multipliers, multiplier)
+	}
+	sort.Float64s(multipliers)
+	for _, multiplier := range multipliers {
+		zipCodes := zones[multiplier]
+		sort.Strings(zipCodes)
+		rule("Geographic Zone", domain.PricingRuleZoneSurcharge, multiplier-1.0).ZipCodes = zipCodes
+	}
+
+	rule("Same-Day Booking", domain.PricingRuleSameDayDiscount, 0.15)
+	rule("Route Premium", domain.PricingRuleDistanceSurcharge, 0.25).MinValue = float(15)
+	rule("Multiple Services", domain.PricingRuleBundleDiscount, 0.10).MinValue = float(2)
+
+	return book
+}
+
+// calculateIntelligentPricing prices the requested services against the price book
+func calculateIntelligentPricing(req PricingRequest) PricingResponse {
+	response := PricingResponse{
+		ServiceBreakdown: make(map[string]float64),
+		Discounts:        make(map[string]float64),
+		Surcharges:       make(map[string]float64),
+	}
+
+	now := time.Now()
+	books := currentPriceBooks(now)
+
+	serviceCount := 1
+	if req.MultipleServices {
+		serviceCount = len(req.ServiceTypes)
+	}
+
+	for _, serviceType := range req.ServiceTypes {
+		config, exists := serviceConfigs[serviceType]
+		if !exists {
+			continue
+		}
+
+		breakdown := services.ApplyPricingRules(books, &services.PriceRequest{
+			ServiceKey:   config.ID,
+			ServiceName:  config.Name,
+			Unit:         domain.PricingUnitHour,
+			Quantity:     config.BaseHours,
+			PropertySize: float64(req.PropertySize),
+			Frequency:    req.Frequency,
+			ZipCode:      req.ZipCode,
+			Distance:     req.Distance,
+			SameDay:      req.SameDayBooking,
+			ServiceCount: serviceCount,
+			Date:         now,
+		})
+
+		if response.BaseRate == 0 {
+			response.BaseRate = breakdown.UnitRate
+		}
+		response.ServiceBreakdown[config.Name] = breakdown.BaseAmount
+		response.Subtotal += breakdown.BaseAmount
+		response.EstimatedHours += config.BaseHours
+		response.TotalAmount += breakdown.Total
+
+		for _, adjustment := range breakdown.Adjustments {
+			if adjustment.Amount < 0 {
+				response.Discounts[adjustment.Description] += -adjustment.Amount
+			} else {
+				response.Surcharges[adjustment.Description] += adjustment.Amount
+			}
+		}
+	}
+
+	response.TotalAmount = math.Round(response.TotalAmount*100) / 100
+	return response
+}
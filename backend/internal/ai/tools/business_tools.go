@@ -837,6 +837,77 @@ func (b *BusinessTools) checkEquipmentStatusHandler(ctx context.Context, params
 	return result, nil
 }
 
+// Quote Conversion Tool
+func (b *BusinessTools) getQuoteConversionTool() *ai.Function {
+	return &ai.Function{
+		Name:        "analyze_quote_conversion",
+		Description: "Analyze sales pipeline and quote-to-job conversion rates, stage drop-off and win/loss reasons",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"period": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"month", "quarter", "year"},
+					"description": "Analysis period",
+					"default":     "quarter",
+				},
+			},
+		},
+		Handler:     b.analyzeQuoteConversionHandler,
+		Permissions: []string{"business:view_quotes", "admin"},
+	}
+}
+
+func (b *BusinessTools) analyzeQuoteConversionHandler(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	period := "quarter"
+	if p, ok := params["period"].(string); ok {
+		period = p
+	}
+
+	// Calculate date range
+	now := time.Now()
+	startDate := now.AddDate(0, -3, 0)
+	switch period {
+	case "month":
+		startDate = now.AddDate(0, -1, 0)
+	case "year":
+		startDate = now.AddDate(-1, 0, 0)
+	}
+
+	if b.services.Pipeline == nil {
+		return nil, fmt.Errorf("sales pipeline is not configured")
+	}
+
+	metrics, err := b.services.Pipeline.GetPipelineMetrics(ctx, &services.PipelineMetricsFilter{
+		TimeRange: services.TimeRange{Start: startDate, End: now},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline metrics: %w", err)
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"analysis": map[string]interface{}{
+			"period":              period,
+			"start_date":          startDate.Format("2006-01-02"),
+			"end_date":            now.Format("2006-01-02"),
+			"total_opportunities": metrics.TotalOpportunities,
+			"win_rate":            metrics.WinRate,
+			"conversion_rate":     metrics.ConversionRate,
+			"open_value":          metrics.OpenValue,
+			"weighted_value":      metrics.WeightedValue,
+			"won_value":           metrics.WonValue,
+			"average_deal_size":   metrics.AverageDealSize,
+			"average_days_close":  metrics.AverageDaysToClose,
+			"stages":              metrics.Stages,
+			"loss_reasons":        metrics.LossReasons,
+			"win_reasons":         metrics.WinReasons,
+			"sources":             metrics.Sources,
+		},
+		"insights": b.generateConversionInsights(metrics),
+	}, nil
+}
+
 // Helper methods for analysis and insights generation
 
 func (b *BusinessTools) generateMetricsSummary(dashboard *services.DashboardData, revenue *services.RevenueReport, jobs *services.JobsReport) string {
@@ -933,30 +1004,39 @@ func (b *BusinessTools) analyzeOverdueInvoices(invoices []*domain.Invoice) map[s
 	}
 }
 
-// Stub implementations for remaining tools
+func (b *BusinessTools) generateConversionInsights(metrics *services.PipelineMetrics) []string {
+	if metrics.TotalOpportunities == 0 {
+		return []string{"No opportunities were created in this period"}
+	}
 
-func (b *BusinessTools) getQuoteConversionTool() *ai.Function {
-	return &ai.Function{
-		Name:        "analyze_quote_conversion",
-		Description: "Analyze quote-to-job conversion rates and patterns",
-		Parameters: map[string]interface{}{
-			"type": "object",
-			"properties": map[string]interface{}{
-				"period": map[string]interface{}{
-					"type":        "string",
-					"enum":        []string{"month", "quarter", "year"},
-					"description": "Analysis period",
-					"default":     "quarter",
-				},
-			},
-		},
-		Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
-			return map[string]interface{}{"success": true, "message": "Feature coming soon"}, nil
-		},
-		Permissions: []string{"business:view_quotes", "admin"},
+	insights := []string{
+		fmt.Sprintf("%.0f%% of closed opportunities were won (%d won, %d lost)", metrics.WinRate*100, metrics.WonCount, metrics.LostCount),
 	}
+
+	// Largest drop-off between stages (only open stages count as reached)
+	var weakest *services.PipelineStageMetrics
+	for i := range metrics.Stages {
+		stage := &metrics.Stages[i]
+		if stage.Reached > 0 && (weakest == nil || stage.ConversionRate < weakest.ConversionRate) {
+			weakest = stage
+		}
+	}
+	if weakest != nil {
+		insights = append(insights, fmt.Sprintf("Biggest drop-off is at %s: only %.0f%% move on", weakest.Name, weakest.ConversionRate*100))
+	}
+	if len(metrics.LossReasons) > 0 {
+		top := metrics.LossReasons[0]
+		insights = append(insights, fmt.Sprintf("Most common loss reason is %s (%d deals, $%.2f)", top.Reason, top.Count, top.Value))
+	}
+	if metrics.OpenCount > 0 {
+		insights = append(insights, fmt.Sprintf("%d open opportunities worth $%.2f ($%.2f weighted)", metrics.OpenCount, metrics.OpenValue, metrics.WeightedValue))
+	}
+
+	return insights
 }
 
+// Stub implementations for remaining tools
+
 func (b *BusinessTools) getCustomerRetentionTool() *ai.Function {
 	return &ai.Function{
 		Name:        "analyze_customer_retention",
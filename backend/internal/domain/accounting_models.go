@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountMapping maps a customer type, service, tax or payment method to a chart-of-accounts entry
+type AccountMapping struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	TenantID    uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	MappingType string    `json:"mapping_type" db:"mapping_type"`
+	MappingKey  string    `json:"mapping_key" db:"mapping_key"`
+	AccountCode *string   `json:"account_code" db:"account_code"`
+	AccountName string    `json:"account_name" db:"account_name"`
+	AccountType string    `json:"account_type" db:"account_type"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// AccountingConnection is a tenant's API connection to an accounting provider
+type AccountingConnection struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	TenantID         uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	Provider         string     `json:"provider" db:"provider"`
+	ExternalTenantID *string    `json:"external_tenant_id" db:"external_tenant_id"`
+	AccessToken      *string    `json:"-" db:"access_token"`
+	RefreshToken     *string    `json:"-" db:"refresh_token"`
+	TokenExpiresAt   *time.Time `json:"token_expires_at" db:"token_expires_at"`
+	Status           string     `json:"status" db:"status"`
+	AutoSync         bool       `json:"auto_sync" db:"auto_sync"`
+	LastSyncAt       *time.Time `json:"last_sync_at" db:"last_sync_at"`
+	LastError        *string    `json:"last_error" db:"last_error"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// AccountingSyncRecord tracks a record pushed to an accounting provider
+type AccountingSyncRecord struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	TenantID        uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	Provider        string     `json:"provider" db:"provider"`
+	EntityType      string     `json:"entity_type" db:"entity_type"`
+	EntityID        uuid.UUID  `json:"entity_id" db:"entity_id"`
+	ExternalID      *string    `json:"external_id" db:"external_id"`
+	Status          string     `json:"status" db:"status"`
+	SourceUpdatedAt *time.Time `json:"source_updated_at" db:"source_updated_at"`
+	Attempts        int        `json:"attempts" db:"attempts"`
+	LastError       *string    `json:"last_error" db:"last_error"`
+	SyncedAt        *time.Time `json:"synced_at" db:"synced_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Accounting constants
+const (
+	// Account mapping types
+	AccountMappingTypeReceivable     = "receivable"      // keyed by customer type
+	AccountMappingTypeIncome         = "income"          // keyed by service ID
+	AccountMappingTypeIncomeCategory = "income_category" // keyed by service category
+	AccountMappingTypeTax            = "tax"
+	AccountMappingTypeDeposit        = "deposit" // keyed by payment method
+	AccountMappingTypeLateFee        = "late_fee"
+
+	// Account types
+	AccountTypeReceivable     = "accounts_receivable"
+	AccountTypeIncome         = "income"
+	AccountTypeLiability      = "other_current_liability"
+	AccountTypeBank           = "bank"
+	AccountTypeOtherCurrAsset = "other_current_asset"
+
+	// Accounting providers
+	AccountingProviderQuickBooks = "quickbooks"
+	AccountingProviderXero       = "xero"
+
+	// Connection statuses
+	AccountingConnectionStatusActive       = "active"
+	AccountingConnectionStatusDisconnected = "disconnected"
+	AccountingConnectionStatusError        = "error"
+
+	// Synced entity types
+	AccountingEntityCustomer = "customer"
+	AccountingEntityService  = "service"
+	AccountingEntityInvoice  = "invoice"
+	AccountingEntityPayment  = "payment"
+
+	// Sync record statuses
+	AccountingSyncStatusSynced = "synced"
+	AccountingSyncStatusFailed = "failed"
+
+	// Export formats
+	AccountingExportFormatIIF             = "iif"
+	AccountingExportFormatQBO             = "qbo"
+	AccountingExportFormatOFX             = "ofx"
+	AccountingExportFormatXeroInvoicesCSV = "xero_invoices_csv"
+	AccountingExportFormatXeroPaymentsCSV = "xero_payments_csv"
+)
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Time off types
+const (
+	TimeOffVacation = "vacation"
+	TimeOffSick     = "sick"
+	TimeOffPersonal = "personal"
+	TimeOffUnpaid   = "unpaid"
+)
+
+// TimeOffTypes lists the kinds of time off a user can request
+var TimeOffTypes = []string{TimeOffVacation, TimeOffSick, TimeOffPersonal, TimeOffUnpaid}
+
+// Time off request statuses
+const (
+	TimeOffPending   = "pending"
+	TimeOffApproved  = "approved"
+	TimeOffDenied    = "denied"
+	TimeOffCancelled = "cancelled"
+)
+
+// WorkingHours is one shift in a user's weekly working-hour template. A user
+// with no template works the default business day. Seasonal staff have
+// shifts bounded by an effective date range.
+type WorkingHours struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	TenantID      uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
+	Weekday       int        `json:"weekday" db:"weekday"`       // time.Weekday value
+	StartTime     string     `json:"start_time" db:"start_time"` // "HH:MM" local time
+	EndTime       string     `json:"end_time" db:"end_time"`
+	EffectiveFrom *time.Time `json:"effective_from,omitempty" db:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty" db:"effective_to"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsEffectiveOn reports whether the shift applies on the date
+func (h *WorkingHours) IsEffectiveOn(date time.Time) bool {
+	day := date.Format("2006-01-02")
+	if h.EffectiveFrom != nil && day < h.EffectiveFrom.Format("2006-01-02") {
+		return false
+	}
+	if h.EffectiveTo != nil && day > h.EffectiveTo.Format("2006-01-02") {
+		return false
+	}
+	return true
+}
+
+// TimeOffRequest is a user's request for vacation, sick or other leave.
+// Only approved time off takes the user out of the schedule.
+type TimeOffRequest struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	TenantID    uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
+	Type        string     `json:"type" db:"type"`
+	StartsAt    time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at" db:"ends_at"`
+	Reason      *string    `json:"reason,omitempty" db:"reason"`
+	Status      string     `json:"status" db:"status"`
+	RequestedBy *uuid.UUID `json:"requested_by,omitempty" db:"requested_by"`
+	ReviewedBy  *uuid.UUID `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	ReviewNotes *string    `json:"review_notes,omitempty" db:"review_notes"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// TenantHoliday is a day the whole business is closed
+type TenantHoliday struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	TenantID  uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	Date      time.Time  `json:"date" db:"date"`
+	Name      string     `json:"name" db:"name"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
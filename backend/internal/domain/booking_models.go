@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookingSettings controls online self-scheduling for a tenant
+type BookingSettings struct {
+	TenantID               uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	Enabled                bool      `json:"enabled" db:"enabled"`
+	Timezone               string    `json:"timezone" db:"timezone"`
+	WorkdayStart           string    `json:"workday_start" db:"workday_start"` // "HH:MM" local time
+	WorkdayEnd             string    `json:"workday_end" db:"workday_end"`
+	WorkingDays            []int     `json:"working_days" db:"working_days"` // time.Weekday values
+	ArrivalWindowMinutes   int       `json:"arrival_window_minutes" db:"arrival_window_minutes"`
+	DefaultDurationMinutes int       `json:"default_duration_minutes" db:"default_duration_minutes"`
+	LeadTimeHours          int       `json:"lead_time_hours" db:"lead_time_hours"` // earliest bookable time from now
+	MaxDaysAhead           int       `json:"max_days_ahead" db:"max_days_ahead"`
+	HoldMinutes            int       `json:"hold_minutes" db:"hold_minutes"`
+	MaxJobsPerCrewDay      int       `json:"max_jobs_per_crew_day" db:"max_jobs_per_crew_day"` // used when a crew has no capacity set
+	TravelSpeedMph         float64   `json:"travel_speed_mph" db:"travel_speed_mph"`
+	DefaultTravelMinutes   int       `json:"default_travel_minutes" db:"default_travel_minutes"` // when a location is unknown
+	AllowedOrigins         []string  `json:"allowed_origins" db:"allowed_origins"`               // sites allowed to embed the booking API
+	CreatedAt              time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// BookingBlackout is a day that can't be booked online, for every crew or just one
+type BookingBlackout struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	TenantID  uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	Date      time.Time  `json:"date" db:"date"`
+	CrewID    *uuid.UUID `json:"crew_id" db:"crew_id"`
+	Reason    *string    `json:"reason" db:"reason"`
+	CreatedBy *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// BookingHold reserves an arrival window for a visitor while they finish booking.
+// The crew is planned to arrive at JobStart, the start of the window.
+type BookingHold struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	TenantID        uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	CrewID          uuid.UUID  `json:"crew_id" db:"crew_id"`
+	WindowStart     time.Time  `json:"window_start" db:"window_start"`
+	WindowEnd       time.Time  `json:"window_end" db:"window_end"`
+	JobStart        time.Time  `json:"job_start" db:"job_start"`
+	JobEnd          time.Time  `json:"job_end" db:"job_end"`
+	ServiceID       *uuid.UUID `json:"service_id" db:"service_id"`
+	ServiceKey      *string    `json:"service_key" db:"service_key"`
+	DurationMinutes int        `json:"duration_minutes" db:"duration_minutes"`
+	Latitude        *float64   `json:"latitude" db:"latitude"`
+	Longitude       *float64   `json:"longitude" db:"longitude"`
+	Token           string     `json:"-" db:"token"`
+	Status          string     `json:"status" db:"status"`
+	ExpiresAt       time.Time  `json:"expires_at" db:"expires_at"`
+	LeadID          *uuid.UUID `json:"lead_id" db:"lead_id"`
+	JobID           *uuid.UUID `json:"job_id" db:"job_id"`
+	IPAddress       *string    `json:"-" db:"ip_address"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsActive reports whether the hold still blocks its window
+func (h *BookingHold) IsActive(now time.Time) bool {
+	return h.Status == BookingHoldStatusHeld && now.Before(h.ExpiresAt)
+}
+
+// Booking hold statuses
+const (
+	BookingHoldStatusHeld      = "held"
+	BookingHoldStatusConfirmed = "confirmed"
+	BookingHoldStatusReleased  = "released"
+	BookingHoldStatusExpired   = "expired"
+)
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Certification types
+const (
+	CertificationPesticideApplicator = "pesticide_applicator"
+	CertificationCDL                 = "cdl"
+	CertificationIrrigation          = "irrigation"
+	CertificationOSHA                = "osha"
+)
+
+// CertificationTypes lists the supported certification types
+var CertificationTypes = []string{
+	CertificationPesticideApplicator,
+	CertificationCDL,
+	CertificationIrrigation,
+	CertificationOSHA,
+}
+
+// Certification enforcement levels. A blocking requirement refuses assignments
+// to workers who do not hold the certification; a warning one lets the
+// assignment through and flags it.
+const (
+	CertificationEnforcementBlock = "block"
+	CertificationEnforcementWarn  = "warn"
+)
+
+// AttachmentEntityUserCertification is the file attachment entity type for certification documents
+const AttachmentEntityUserCertification = "user_certification"
+
+// UserCertification is a licence or certification held by a user, such as a
+// pesticide applicator licence or a CDL. Certifications without an expiry date
+// never lapse.
+type UserCertification struct {
+	ID                   uuid.UUID  `json:"id" db:"id"`
+	TenantID             uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	UserID               uuid.UUID  `json:"user_id" db:"user_id"`
+	CertificationType    string     `json:"certification_type" db:"certification_type"`
+	Name                 *string    `json:"name" db:"name"`
+	LicenseNumber        *string    `json:"license_number" db:"license_number"`
+	IssuingAuthority     *string    `json:"issuing_authority" db:"issuing_authority"`
+	IssuedOn             *time.Time `json:"issued_on" db:"issued_on"`
+	ExpiresOn            *time.Time `json:"expires_on" db:"expires_on"`
+	DocumentAttachmentID *uuid.UUID `json:"document_attachment_id" db:"document_attachment_id"`
+	ReminderDaysSent     *int       `json:"-" db:"reminder_days_sent"`
+	CreatedBy            *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
+
+	Document *FileAttachment `json:"document,omitempty" db:"-"`
+}
+
+// IsValidOn reports whether the certification is still current on the date.
+// A certification is good through the end of its expiry date.
+func (c *UserCertification) IsValidOn(date time.Time) bool {
+	return c.ExpiresOn == nil || date.Before(c.ExpiresOn.AddDate(0, 0, 1))
+}
+
+// ServiceCertificationRequirement is a certification a service requires of
+// the workers assigned to jobs that include it
+type ServiceCertificationRequirement struct {
+	TenantID          uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	ServiceID         uuid.UUID `json:"service_id" db:"service_id"`
+	CertificationType string    `json:"certification_type" db:"certification_type"`
+	Enforcement       string    `json:"enforcement" db:"enforcement"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CollectionPolicy holds a tenant's reminder cadence, late fee and suspension rules
+type CollectionPolicy struct {
+	ID                       uuid.UUID      `json:"id" db:"id"`
+	TenantID                 uuid.UUID      `json:"tenant_id" db:"tenant_id"`
+	RemindersEnabled         bool           `json:"reminders_enabled" db:"reminders_enabled"`
+	ReminderSteps            []ReminderStep `json:"reminder_steps" db:"reminder_steps"`
+	LateFeeEnabled           bool           `json:"late_fee_enabled" db:"late_fee_enabled"`
+	LateFeeType              string         `json:"late_fee_type" db:"late_fee_type"`
+	LateFeeAmount            float64        `json:"late_fee_amount" db:"late_fee_amount"`
+	LateFeeGraceDays         int            `json:"late_fee_grace_days" db:"late_fee_grace_days"`
+	LateFeeRepeatDays        int            `json:"late_fee_repeat_days" db:"late_fee_repeat_days"`
+	LateFeeMaxApplications   int            `json:"late_fee_max_applications" db:"late_fee_max_applications"`
+	SuspendSchedulingEnabled bool           `json:"suspend_scheduling_enabled" db:"suspend_scheduling_enabled"`
+	SuspendAfterDays         int            `json:"suspend_after_days" db:"suspend_after_days"`
+	SuspendMinBalance        float64        `json:"suspend_min_balance" db:"suspend_min_balance"`
+	CreatedAt                time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt                time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// ReminderStep is one step of an escalating overdue reminder cadence
+type ReminderStep struct {
+	DaysOverdue int     `json:"days_overdue"`
+	Template    string  `json:"template"`
+	Subject     *string `json:"subject,omitempty"`
+	Body        *string `json:"body,omitempty"`
+}
+
+// InvoiceReminder records an overdue reminder sent for an invoice
+type InvoiceReminder struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	TenantID    uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	InvoiceID   uuid.UUID `json:"invoice_id" db:"invoice_id"`
+	DaysOverdue int       `json:"days_overdue" db:"days_overdue"`
+	Template    string    `json:"template" db:"template"`
+	Channel     string    `json:"channel" db:"channel"`
+	Recipient   *string   `json:"recipient" db:"recipient"`
+	SentAt      time.Time `json:"sent_at" db:"sent_at"`
+}
+
+// InvoiceLateFee is a late fee applied to an overdue invoice
+type InvoiceLateFee struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	TenantID    uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	InvoiceID   uuid.UUID  `json:"invoice_id" db:"invoice_id"`
+	Amount      float64    `json:"amount" db:"amount"`
+	DaysOverdue int        `json:"days_overdue" db:"days_overdue"`
+	Reason      *string    `json:"reason" db:"reason"`
+	Waived      bool       `json:"waived" db:"waived"`
+	WaivedBy    *uuid.UUID `json:"waived_by" db:"waived_by"`
+	WaivedAt    *time.Time `json:"waived_at" db:"waived_at"`
+	AppliedAt   time.Time  `json:"applied_at" db:"applied_at"`
+}
+
+// CollectionNote is a note recorded while working a delinquent account
+type CollectionNote struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	TenantID   uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	CustomerID uuid.UUID  `json:"customer_id" db:"customer_id"`
+	InvoiceID  *uuid.UUID `json:"invoice_id" db:"invoice_id"`
+	UserID     *uuid.UUID `json:"user_id" db:"user_id"`
+	NoteType   string     `json:"note_type" db:"note_type"`
+	Content    string     `json:"content" db:"content"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// PaymentPromise tracks a customer's promise to pay by a given date
+type PaymentPromise struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	TenantID     uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	CustomerID   uuid.UUID  `json:"customer_id" db:"customer_id"`
+	InvoiceID    *uuid.UUID `json:"invoice_id" db:"invoice_id"`
+	Amount       float64    `json:"amount" db:"amount"`
+	PromisedDate time.Time  `json:"promised_date" db:"promised_date"`
+	Status       string     `json:"status" db:"status"`
+	Notes        *string    `json:"notes" db:"notes"`
+	CreatedBy    *uuid.UUID `json:"created_by" db:"created_by"`
+	ResolvedAt   *time.Time `json:"resolved_at" db:"resolved_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Collections constants
+const (
+	// Late fee types
+	LateFeeTypeFlat       = "flat"
+	LateFeeTypePercentage = "percentage"
+
+	// Reminder templates
+	ReminderTemplateFriendly = "friendly"
+	ReminderTemplateFirm     = "firm"
+	ReminderTemplateFinal    = "final"
+
+	// Collection note types
+	CollectionNoteTypeNote       = "note"
+	CollectionNoteTypeCall       = "call"
+	CollectionNoteTypeEmail      = "email"
+	CollectionNoteTypePromise    = "promise"
+	CollectionNoteTypeSuspension = "suspension"
+
+	// Payment promise statuses
+	PaymentPromiseStatusPending   = "pending"
+	PaymentPromiseStatusKept      = "kept"
+	PaymentPromiseStatusBroken    = "broken"
+	PaymentPromiseStatusCancelled = "cancelled"
+)
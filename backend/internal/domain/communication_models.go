@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommunicationThread is a two-way SMS or email conversation with one address.
+// Inbound messages from an address no customer uses start a thread without a
+// customer; staff can link it once they know who it is.
+type CommunicationThread struct {
+	ID                 uuid.UUID  `json:"id" db:"id"`
+	TenantID           uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	CustomerID         *uuid.UUID `json:"customer_id" db:"customer_id"`
+	Channel            string     `json:"channel" db:"channel"`
+	Address            string     `json:"address" db:"address"` // customer's phone digits or lowercased email
+	Subject            *string    `json:"subject" db:"subject"` // email threads only, without Re:/Fwd: prefixes
+	Status             string     `json:"status" db:"status"`
+	UnreadCount        int        `json:"unread_count" db:"unread_count"`
+	LastMessageAt      *time.Time `json:"last_message_at" db:"last_message_at"`
+	LastMessagePreview *string    `json:"last_message_preview" db:"last_message_preview"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CommunicationMessage is one SMS or email sent to or received from a customer
+type CommunicationMessage struct {
+	ID                uuid.UUID  `json:"id" db:"id"`
+	TenantID          uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	ThreadID          uuid.UUID  `json:"thread_id" db:"thread_id"`
+	CustomerID        *uuid.UUID `json:"customer_id" db:"customer_id"`
+	Channel           string     `json:"channel" db:"channel"`
+	Direction         string     `json:"direction" db:"direction"`
+	FromAddress       string     `json:"from_address" db:"from_address"`
+	ToAddress         string     `json:"to_address" db:"to_address"`
+	Subject           *string    `json:"subject" db:"subject"`
+	Body              string     `json:"body" db:"body"`
+	ProviderMessageID *string    `json:"provider_message_id" db:"provider_message_id"`
+	InReplyTo         *string    `json:"in_reply_to" db:"in_reply_to"` // provider message ID an email replies to
+	Status            string     `json:"status" db:"status"`
+	Error             *string    `json:"error" db:"error"`
+	SentBy            *uuid.UUID `json:"sent_by" db:"sent_by"`
+	ReadAt            *time.Time `json:"read_at" db:"read_at"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CustomerNote is a free-form note staff add to a customer's timeline
+type CustomerNote struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	TenantID   uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	CustomerID uuid.UUID  `json:"customer_id" db:"customer_id"`
+	Body       string     `json:"body" db:"body"`
+	CreatedBy  *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Communication channels
+const (
+	CommunicationChannelSMS   = "sms"
+	CommunicationChannelEmail = "email"
+)
+
+// Message directions
+const (
+	MessageDirectionInbound  = "inbound"
+	MessageDirectionOutbound = "outbound"
+)
+
+// Message statuses. Outbound messages move from queued to sent and then delivered
+// or failed as the provider reports back; inbound messages are received.
+const (
+	MessageStatusQueued    = "queued"
+	MessageStatusSent      = "sent"
+	MessageStatusDelivered = "delivered"
+	MessageStatusFailed    = "failed"
+	MessageStatusReceived  = "received"
+)
+
+// Thread statuses
+const (
+	ThreadStatusOpen   = "open"
+	ThreadStatusClosed = "closed"
+)
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CustomerMerge records one customer folded into another. Everything that pointed
+// at the merged customer is re-parented onto the survivor; the IDs moved and the
+// survivor fields changed are kept so the merge can be undone within the undo window.
+type CustomerMerge struct {
+	ID            uuid.UUID                      `json:"id" db:"id"`
+	TenantID      uuid.UUID                      `json:"tenant_id" db:"tenant_id"`
+	SurvivorID    uuid.UUID                      `json:"survivor_id" db:"survivor_id"`
+	MergedID      uuid.UUID                      `json:"merged_id" db:"merged_id"`
+	Score         *float64                       `json:"score" db:"score"`                 // duplicate score when merged from a suggestion
+	FieldChanges  map[string]CustomerFieldChange `json:"field_changes" db:"field_changes"` // survivor fields the merge changed
+	MovedRecords  map[string][]uuid.UUID         `json:"moved_records" db:"moved_records"` // "table.column" -> re-parented record IDs
+	MergedStatus  string                         `json:"merged_status" db:"merged_status"` // the merged customer's status before the merge
+	Status        string                         `json:"status" db:"status"`
+	UndoExpiresAt time.Time                      `json:"undo_expires_at" db:"undo_expires_at"`
+	MergedBy      *uuid.UUID                     `json:"merged_by" db:"merged_by"`
+	UndoneBy      *uuid.UUID                     `json:"undone_by" db:"undone_by"`
+	UndoneAt      *time.Time                     `json:"undone_at" db:"undone_at"`
+	CreatedAt     time.Time                      `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time                      `json:"updated_at" db:"updated_at"`
+}
+
+// CustomerFieldChange is a survivor field's value before and after a merge
+type CustomerFieldChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// CanUndo reports whether the merge can still be undone
+func (m *CustomerMerge) CanUndo(now time.Time) bool {
+	return m.Status == CustomerMergeStatusMerged && now.Before(m.UndoExpiresAt)
+}
+
+// CustomerDuplicateDismissal marks two customers as reviewed and not duplicates, so
+// the duplicate finder stops suggesting them
+type CustomerDuplicateDismissal struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	TenantID    uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	CustomerAID uuid.UUID  `json:"customer_a_id" db:"customer_a_id"` // the lower of the two IDs
+	CustomerBID uuid.UUID  `json:"customer_b_id" db:"customer_b_id"`
+	DismissedBy *uuid.UUID `json:"dismissed_by" db:"dismissed_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Customer merge statuses
+const (
+	CustomerMergeStatusMerged = "merged"
+	CustomerMergeStatusUndone = "undone"
+)
+
+// CustomerStatusMerged is the status of a customer merged into another
+const CustomerStatusMerged = "merged"
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Crew roster roles
+const (
+	CrewRoleLead   = "lead"
+	CrewRoleMember = "member"
+)
+
+// CrewRoster is who rides with a crew on one day and what equipment they
+// take. A roster overrides the crew's standing membership for its date; days
+// without one fall back to the crew_members table and the crew's equipment.
+type CrewRoster struct {
+	ID           uuid.UUID           `json:"id" db:"id"`
+	TenantID     uuid.UUID           `json:"tenant_id" db:"tenant_id"`
+	CrewID       uuid.UUID           `json:"crew_id" db:"crew_id"`
+	Date         time.Time           `json:"date" db:"date"`
+	EquipmentIDs []uuid.UUID         `json:"equipment_ids" db:"equipment_ids"`
+	Notes        *string             `json:"notes,omitempty" db:"notes"`
+	CreatedBy    *uuid.UUID          `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt    time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at" db:"updated_at"`
+	Members      []*CrewRosterMember `json:"members" db:"-"`
+}
+
+// HasMember reports whether the user rides with the crew on the roster's date
+func (r *CrewRoster) HasMember(userID uuid.UUID) bool {
+	for _, member := range r.Members {
+		if member.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// CrewRosterMember is one person on a day's crew roster
+type CrewRosterMember struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	RosterID uuid.UUID `json:"roster_id" db:"roster_id"`
+	UserID   uuid.UUID `json:"user_id" db:"user_id"`
+	Role     string    `json:"role" db:"role"`
+}
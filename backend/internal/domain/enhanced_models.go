@@ -45,6 +45,7 @@ type EnhancedCustomer struct {
 	CustomerType            string  `json:"customer_type" db:"customer_type"`
 	CreditLimit             *float64 `json:"credit_limit" db:"credit_limit"`
 	PaymentTerms            int     `json:"payment_terms" db:"payment_terms"`
+	SchedulingSuspended     bool    `json:"scheduling_suspended" db:"scheduling_suspended"`
 }
 
 // Enhanced Property model with location and details
@@ -72,6 +73,9 @@ type EnhancedJob struct {
 	CustomerSignature  *string                `json:"customer_signature" db:"customer_signature"`
 	GPSCheckIn         map[string]interface{} `json:"gps_check_in" db:"gps_check_in"`
 	GPSCheckOut        map[string]interface{} `json:"gps_check_out" db:"gps_check_out"`
+	PONumber           *string                `json:"po_number" db:"po_number"`
+	QuoteID            *uuid.UUID             `json:"quote_id" db:"quote_id"`
+	QuoteVersionID     *uuid.UUID             `json:"quote_version_id" db:"quote_version_id"`
 }
 
 // API Key for external integrations
@@ -191,38 +195,43 @@ type CrewMember struct {
 
 // Quote for pricing estimates
 type Quote struct {
-	ID                 uuid.UUID  `json:"id" db:"id"`
-	TenantID           uuid.UUID  `json:"tenant_id" db:"tenant_id"`
-	CustomerID         uuid.UUID  `json:"customer_id" db:"customer_id"`
-	PropertyID         uuid.UUID  `json:"property_id" db:"property_id"`
-	QuoteNumber        string     `json:"quote_number" db:"quote_number"`
-	Title              string     `json:"title" db:"title"`
-	Description        *string    `json:"description" db:"description"`
-	Subtotal           float64    `json:"subtotal" db:"subtotal"`
-	TaxRate            float64    `json:"tax_rate" db:"tax_rate"`
-	TaxAmount          float64    `json:"tax_amount" db:"tax_amount"`
-	TotalAmount        float64    `json:"total_amount" db:"total_amount"`
-	Status             string     `json:"status" db:"status"`
-	ValidUntil         *time.Time `json:"valid_until" db:"valid_until"`
-	TermsAndConditions *string    `json:"terms_and_conditions" db:"terms_and_conditions"`
-	Notes              *string    `json:"notes" db:"notes"`
-	CreatedBy          *uuid.UUID `json:"created_by" db:"created_by"`
-	ApprovedAt         *time.Time `json:"approved_at" db:"approved_at"`
-	ApprovedBy         *uuid.UUID `json:"approved_by" db:"approved_by"`
-	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+	ID                 uuid.UUID       `json:"id" db:"id"`
+	TenantID           uuid.UUID       `json:"tenant_id" db:"tenant_id"`
+	CustomerID         uuid.UUID       `json:"customer_id" db:"customer_id"`
+	PropertyID         uuid.UUID       `json:"property_id" db:"property_id"`
+	QuoteNumber        string          `json:"quote_number" db:"quote_number"`
+	Title              string          `json:"title" db:"title"`
+	Description        *string         `json:"description" db:"description"`
+	Subtotal           float64         `json:"subtotal" db:"subtotal"`
+	TaxRate            float64         `json:"tax_rate" db:"tax_rate"`
+	TaxAmount          float64         `json:"tax_amount" db:"tax_amount"`
+	TotalAmount        float64         `json:"total_amount" db:"total_amount"`
+	Status             string          `json:"status" db:"status"`
+	ValidUntil         *time.Time      `json:"valid_until" db:"valid_until"`
+	TermsAndConditions *string         `json:"terms_and_conditions" db:"terms_and_conditions"`
+	Notes              *string         `json:"notes" db:"notes"`
+	CreatedBy          *uuid.UUID      `json:"created_by" db:"created_by"`
+	ApprovedAt         *time.Time      `json:"approved_at" db:"approved_at"`
+	ApprovedBy         *uuid.UUID      `json:"approved_by" db:"approved_by"`
+	CurrentVersion     int             `json:"current_version" db:"current_version"`
+	ApprovedVersionID  *uuid.UUID      `json:"approved_version_id" db:"approved_version_id"`
+	ApprovedSelection  *QuoteSelection `json:"approved_selection" db:"approved_selection"`
+	CreatedAt          time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at" db:"updated_at"`
 }
 
 // Quote Service for quote line items
 type QuoteService struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	QuoteID     uuid.UUID `json:"quote_id" db:"quote_id"`
-	ServiceID   uuid.UUID `json:"service_id" db:"service_id"`
-	Quantity    float64   `json:"quantity" db:"quantity"`
-	UnitPrice   float64   `json:"unit_price" db:"unit_price"`
-	TotalPrice  float64   `json:"total_price" db:"total_price"`
-	Description *string   `json:"description" db:"description"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ID          uuid.UUID  `json:"id" db:"id"`
+	QuoteID     uuid.UUID  `json:"quote_id" db:"quote_id"`
+	ServiceID   uuid.UUID  `json:"service_id" db:"service_id"`
+	OptionID    *uuid.UUID `json:"option_id" db:"option_id"`
+	IsOptional  bool       `json:"is_optional" db:"is_optional"`
+	Quantity    float64    `json:"quantity" db:"quantity"`
+	UnitPrice   float64    `json:"unit_price" db:"unit_price"`
+	TotalPrice  float64    `json:"total_price" db:"total_price"`
+	Description *string    `json:"description" db:"description"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 }
 
 // Schedule Template for recurring job patterns
@@ -359,6 +368,7 @@ type CreateJobRequest struct {
 	CrewSize           int         `json:"crew_size" validate:"min=1"`
 	WeatherDependent   bool        `json:"weather_dependent"`
 	RequiresEquipment  []uuid.UUID `json:"requires_equipment,omitempty"`
+	PONumber           *string     `json:"po_number,omitempty"`
 }
 
 type UpdateJobRequest struct {
@@ -371,7 +381,9 @@ type UpdateJobRequest struct {
 	EstimatedDuration *int        `json:"estimated_duration,omitempty"`
 	AssignedUserID    *uuid.UUID  `json:"assigned_user_id,omitempty"`
 	CrewSize          *int        `json:"crew_size,omitempty"`
+	RequiresEquipment []uuid.UUID `json:"requires_equipment,omitempty"`
 	Notes             *string     `json:"notes,omitempty"`
+	PONumber          *string     `json:"po_number,omitempty"`
 }
 
 // Common response structures
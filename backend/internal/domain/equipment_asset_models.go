@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Depreciation methods
+const (
+	DepreciationStraightLine     = "straight_line"
+	DepreciationDecliningBalance = "declining_balance"
+	DepreciationMACRS            = "macrs"
+)
+
+// DepreciationMethods lists the supported depreciation methods
+var DepreciationMethods = []string{DepreciationStraightLine, DepreciationDecliningBalance, DepreciationMACRS}
+
+// EquipmentAssetProfile holds how a piece of equipment is carried as a fixed
+// asset: its depreciation method and life, what it costs to run and what it
+// costs the business while it is down, what it would cost to replace, and its
+// disposal once sold.
+type EquipmentAssetProfile struct {
+	EquipmentID          uuid.UUID  `json:"equipment_id" db:"equipment_id"`
+	TenantID             uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	DepreciationMethod   string     `json:"depreciation_method" db:"depreciation_method"`
+	UsefulLifeYears      int        `json:"useful_life_years" db:"useful_life_years"` // the MACRS recovery period for MACRS
+	SalvageValue         float64    `json:"salvage_value" db:"salvage_value"`         // not used by MACRS
+	DecliningBalanceRate float64    `json:"declining_balance_rate" db:"declining_balance_rate"`
+	InServiceDate        *time.Time `json:"in_service_date" db:"in_service_date"` // defaults to the purchase date
+	ReplacementCost      *float64   `json:"replacement_cost" db:"replacement_cost"`
+	FuelCostPerHour      float64    `json:"fuel_cost_per_hour" db:"fuel_cost_per_hour"` // per engine hour on the hours meter
+	DowntimeCostPerHour  float64    `json:"downtime_cost_per_hour" db:"downtime_cost_per_hour"`
+	DisposedAt           *time.Time `json:"disposed_at" db:"disposed_at"`
+	DisposalProceeds     *float64   `json:"disposal_proceeds" db:"disposal_proceeds"`
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EquipmentAssetTag is the code printed on a piece of equipment's QR label.
+// Scanning the label resolves the code back to the equipment.
+type EquipmentAssetTag struct {
+	EquipmentID uuid.UUID `json:"equipment_id" db:"equipment_id"`
+	TenantID    uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	Code        string    `json:"code" db:"code"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// EquipmentCheckout records equipment taken from the shop by a user or a crew.
+// The checkout stays open until the equipment is checked back in, and the
+// database allows only one open checkout per piece of equipment.
+type EquipmentCheckout struct {
+	ID                uuid.UUID  `json:"id" db:"id"`
+	TenantID          uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	EquipmentID       uuid.UUID  `json:"equipment_id" db:"equipment_id"`
+	UserID            *uuid.UUID `json:"user_id" db:"user_id"`
+	CrewID            *uuid.UUID `json:"crew_id" db:"crew_id"`
+	CheckedOutAt      time.Time  `json:"checked_out_at" db:"checked_out_at"`
+	CheckedOutBy      *uuid.UUID `json:"checked_out_by" db:"checked_out_by"`
+	DueBackAt         *time.Time `json:"due_back_at" db:"due_back_at"`
+	CheckoutNotes     *string    `json:"checkout_notes" db:"checkout_notes"`
+	CheckedInAt       *time.Time `json:"checked_in_at" db:"checked_in_at"`
+	CheckedInBy       *uuid.UUID `json:"checked_in_by" db:"checked_in_by"`
+	ReturnCondition   *string    `json:"return_condition" db:"return_condition"`
+	ReturnNotes       *string    `json:"return_notes" db:"return_notes"`
+	OverdueNotifiedAt *time.Time `json:"overdue_notified_at" db:"overdue_notified_at"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsOverdue reports whether the equipment is still out past its due time
+func (c *EquipmentCheckout) IsOverdue(now time.Time) bool {
+	return c.CheckedInAt == nil && c.DueBackAt != nil && now.After(*c.DueBackAt)
+}
+
+// Equipment return conditions
+const (
+	ReturnConditionGood         = "good"
+	ReturnConditionNeedsService = "needs_service"
+	ReturnConditionDamaged      = "damaged"
+)
+
+// ReturnConditions lists the conditions equipment can be returned in
+var ReturnConditions = []string{ReturnConditionGood, ReturnConditionNeedsService, ReturnConditionDamaged}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fuel types
+const (
+	FuelTypeGasoline = "gasoline"
+	FuelTypeDiesel   = "diesel"
+)
+
+// FuelTypes lists the supported fuel types
+var FuelTypes = []string{FuelTypeGasoline, FuelTypeDiesel}
+
+// Fuel entry anomaly flags
+const (
+	FuelAnomalyLowEfficiency = "low_efficiency" // burned notably more fuel per mile or hour than usual
+	FuelAnomalyNoUsage       = "no_usage"       // fueled although the meter has not moved since the last fill
+	FuelAnomalyUnusualVolume = "unusual_volume" // far more fuel than the equipment usually takes
+)
+
+// FuelEntry is a fuel purchase for a vehicle or piece of equipment. Usage is how
+// far the odometer or hour meter moved since the previous fill with a reading on
+// the same meter, and Efficiency is miles per gallon on the miles meter or
+// gallons per hour on the hours meter. Entries that look like theft or a leak
+// are flagged.
+type FuelEntry struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	TenantID       uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	EquipmentID    uuid.UUID  `json:"equipment_id" db:"equipment_id"`
+	FueledAt       time.Time  `json:"fueled_at" db:"fueled_at"`
+	FuelType       string     `json:"fuel_type" db:"fuel_type"`
+	Gallons        float64    `json:"gallons" db:"gallons"`
+	PricePerGallon float64    `json:"price_per_gallon" db:"price_per_gallon"`
+	TotalCost      float64    `json:"total_cost" db:"total_cost"`
+	Odometer       *float64   `json:"odometer" db:"odometer"`
+	EngineHours    *float64   `json:"engine_hours" db:"engine_hours"`
+	Meter          *string    `json:"meter" db:"meter"`
+	Usage          *float64   `json:"usage" db:"usage"`
+	Efficiency     *float64   `json:"efficiency" db:"efficiency"`
+	AnomalyFlags   []string   `json:"anomaly_flags" db:"anomaly_flags"`
+	Vendor         *string    `json:"vendor" db:"vendor"`
+	RecordedBy     *uuid.UUID `json:"recorded_by" db:"recorded_by"`
+	Notes          *string    `json:"notes" db:"notes"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
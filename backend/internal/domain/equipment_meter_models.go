@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EquipmentMeterReading is a cumulative meter reading for a piece of equipment,
+// such as a mower's engine hours or a truck's odometer. Usage is the amount the
+// meter moved since the previous reading on the same meter, so job usage can be
+// averaged to project when usage-based maintenance will come due.
+type EquipmentMeterReading struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	TenantID    uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	EquipmentID uuid.UUID  `json:"equipment_id" db:"equipment_id"`
+	Meter       string     `json:"meter" db:"meter"`
+	Reading     float64    `json:"reading" db:"reading"`
+	Usage       float64    `json:"usage" db:"usage"`
+	Source      string     `json:"source" db:"source"`
+	JobID       *uuid.UUID `json:"job_id" db:"job_id"`
+	RecordedBy  *uuid.UUID `json:"recorded_by" db:"recorded_by"`
+	RecordedAt  time.Time  `json:"recorded_at" db:"recorded_at"`
+	Notes       *string    `json:"notes" db:"notes"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// MaintenanceUsageTrigger schedules maintenance every Interval units on a meter,
+// alongside the equipment's calendar schedule. Maintenance is due at whichever
+// of the calendar date or NextDueReading comes first.
+type MaintenanceUsageTrigger struct {
+	ID                 uuid.UUID `json:"id" db:"id"`
+	TenantID           uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	EquipmentID        uuid.UUID `json:"equipment_id" db:"equipment_id"`
+	Meter              string    `json:"meter" db:"meter"`
+	Interval           float64   `json:"interval" db:"service_interval"`
+	LastServiceReading float64   `json:"last_service_reading" db:"last_service_reading"`
+	NextDueReading     float64   `json:"next_due_reading" db:"next_due_reading"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Equipment meters
+const (
+	EquipmentMeterHours = "hours"
+	EquipmentMeterMiles = "miles"
+	EquipmentMeterAcres = "acres"
+)
+
+// EquipmentMeters lists the supported meters
+var EquipmentMeters = []string{
+	EquipmentMeterHours,
+	EquipmentMeterMiles,
+	EquipmentMeterAcres,
+}
+
+// Meter reading sources
+const (
+	MeterReadingSourceManual = "manual"
+	MeterReadingSourceJob    = "job"
+	MeterReadingSourceFuel   = "fuel"
+)
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EquipmentReservation books a piece of equipment for a time window. Jobs
+// reserve the equipment they require when they are scheduled or assigned, and
+// the reservation records the crew taking it once the job is given to one.
+// Reservations without a job block equipment out for other reasons, such as a
+// loan or a trip to the dealer. The database rejects overlapping reservations
+// for the same equipment.
+type EquipmentReservation struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	TenantID    uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	EquipmentID uuid.UUID  `json:"equipment_id" db:"equipment_id"`
+	JobID       *uuid.UUID `json:"job_id" db:"job_id"`
+	CrewID      *uuid.UUID `json:"crew_id" db:"crew_id"`
+	StartsAt    time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at" db:"ends_at"`
+	Notes       *string    `json:"notes" db:"notes"`
+	CreatedBy   *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Overlaps reports whether the reservation overlaps the window from start to end
+func (r *EquipmentReservation) Overlaps(start, end time.Time) bool {
+	return r.StartsAt.Before(end) && r.EndsAt.After(start)
+}
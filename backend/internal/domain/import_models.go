@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportJob is a bulk import of customers and properties from a spreadsheet. Rows are
+// mapped to customer fields, validated in a dry run, then processed in the background
+// in batches; everything the import created can be rolled back together.
+type ImportJob struct {
+	ID                uuid.UUID         `json:"id" db:"id"`
+	TenantID          uuid.UUID         `json:"tenant_id" db:"tenant_id"`
+	FileName          string            `json:"file_name" db:"file_name"`
+	FileFormat        string            `json:"file_format" db:"file_format"`
+	Headers           []string          `json:"headers" db:"headers"`
+	Mapping           map[string]string `json:"mapping" db:"mapping"` // import field -> spreadsheet column
+	DuplicateStrategy string            `json:"duplicate_strategy" db:"duplicate_strategy"`
+	Status            string            `json:"status" db:"status"`
+	TotalRows         int               `json:"total_rows" db:"total_rows"`
+	ValidRows         int               `json:"valid_rows" db:"valid_rows"`
+	InvalidRows       int               `json:"invalid_rows" db:"invalid_rows"`
+	DuplicateRows     int               `json:"duplicate_rows" db:"duplicate_rows"`
+	ProcessedRows     int               `json:"processed_rows" db:"processed_rows"` // rows handled by background processing so far
+	CreatedCustomers  int               `json:"created_customers" db:"created_customers"`
+	UpdatedCustomers  int               `json:"updated_customers" db:"updated_customers"`
+	CreatedProperties int               `json:"created_properties" db:"created_properties"`
+	FailedRows        int               `json:"failed_rows" db:"failed_rows"`
+	GeocodedAt        *time.Time        `json:"geocoded_at" db:"geocoded_at"`
+	ValidatedAt       *time.Time        `json:"validated_at" db:"validated_at"`
+	StartedAt         *time.Time        `json:"started_at" db:"started_at"`
+	CompletedAt       *time.Time        `json:"completed_at" db:"completed_at"`
+	RolledBackAt      *time.Time        `json:"rolled_back_at" db:"rolled_back_at"`
+	Error             *string           `json:"error" db:"error"`
+	CreatedBy         *uuid.UUID        `json:"created_by" db:"created_by"`
+	CreatedAt         time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// ImportRow is one spreadsheet row and what the import did with it
+type ImportRow struct {
+	ID              uuid.UUID         `json:"id" db:"id"`
+	TenantID        uuid.UUID         `json:"tenant_id" db:"tenant_id"`
+	ImportID        uuid.UUID         `json:"import_id" db:"import_id"`
+	RowNumber       int               `json:"row_number" db:"row_number"` // spreadsheet row, counting the header as 1
+	Data            map[string]string `json:"data" db:"data"`             // column -> cell value
+	Status          string            `json:"status" db:"status"`
+	Errors          []string          `json:"errors" db:"errors"`
+	Warnings        []string          `json:"warnings" db:"warnings"`
+	DuplicateOfID   *uuid.UUID        `json:"duplicate_of_id" db:"duplicate_of_id"`
+	DuplicateScore  *float64          `json:"duplicate_score" db:"duplicate_score"`
+	CustomerID      *uuid.UUID        `json:"customer_id" db:"customer_id"`
+	PropertyID      *uuid.UUID        `json:"property_id" db:"property_id"`
+	CreatedCustomer bool              `json:"created_customer" db:"created_customer"`
+	CreatedProperty bool              `json:"created_property" db:"created_property"`
+	FilledFields    []string          `json:"filled_fields" db:"filled_fields"` // blank customer fields a merge filled in
+	CreatedAt       time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// Import file formats
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatXLSX = "xlsx"
+)
+
+// Import job statuses
+const (
+	ImportStatusUploaded   = "uploaded"
+	ImportStatusValidated  = "validated"
+	ImportStatusProcessing = "processing"
+	ImportStatusGeocoding  = "geocoding"
+	ImportStatusCompleted  = "completed"
+	ImportStatusFailed     = "failed"
+	ImportStatusRolledBack = "rolled_back"
+)
+
+// Import row statuses
+const (
+	ImportRowStatusPending    = "pending"
+	ImportRowStatusValid      = "valid"
+	ImportRowStatusInvalid    = "invalid"
+	ImportRowStatusDuplicate  = "duplicate"
+	ImportRowStatusImported   = "imported"
+	ImportRowStatusSkipped    = "skipped"
+	ImportRowStatusFailed     = "failed"
+	ImportRowStatusRolledBack = "rolled_back"
+)
+
+// How rows matching an existing customer are handled
+const (
+	ImportDuplicateSkip   = "skip"   // leave the existing customer alone
+	ImportDuplicateMerge  = "merge"  // fill the existing customer's blank fields and add the property
+	ImportDuplicateCreate = "create" // import as a new customer anyway
+)
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Lead is an inbound request for work, e.g. a booking or consultation form from the
+// public site, tracked until it is converted into a customer, property and quote
+type Lead struct {
+	ID                uuid.UUID              `json:"id" db:"id"`
+	TenantID          uuid.UUID              `json:"tenant_id" db:"tenant_id"`
+	Source            string                 `json:"source" db:"source"`
+	Status            string                 `json:"status" db:"status"`
+	FirstName         string                 `json:"first_name" db:"first_name"`
+	LastName          string                 `json:"last_name" db:"last_name"`
+	Email             *string                `json:"email" db:"email"`
+	Phone             *string                `json:"phone" db:"phone"`
+	ServiceKey        *string                `json:"service_key" db:"service_key"`
+	ServiceID         *uuid.UUID             `json:"service_id" db:"service_id"`
+	Message           *string                `json:"message" db:"message"`
+	AddressLine1      *string                `json:"address_line1" db:"address_line1"`
+	City              *string                `json:"city" db:"city"`
+	State             *string                `json:"state" db:"state"`
+	ZipCode           *string                `json:"zip_code" db:"zip_code"`
+	PropertySize      *float64               `json:"property_size" db:"property_size"`
+	EstimatedPrice    *float64               `json:"estimated_price" db:"estimated_price"`
+	SpamScore         int                    `json:"spam_score" db:"spam_score"`
+	SpamReasons       []string               `json:"spam_reasons" db:"spam_reasons"`
+	DuplicateOfID     *uuid.UUID             `json:"duplicate_of_id" db:"duplicate_of_id"`
+	MatchedCustomerID *uuid.UUID             `json:"matched_customer_id" db:"matched_customer_id"` // existing customer with the same email or phone
+	CustomerID        *uuid.UUID             `json:"customer_id" db:"customer_id"`
+	PropertyID        *uuid.UUID             `json:"property_id" db:"property_id"`
+	QuoteID           *uuid.UUID             `json:"quote_id" db:"quote_id"`
+	IPAddress         *string                `json:"ip_address" db:"ip_address"`
+	UserAgent         *string                `json:"user_agent" db:"user_agent"`
+	Metadata          map[string]interface{} `json:"metadata" db:"metadata"`
+	ReviewedBy        *uuid.UUID             `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt        *time.Time             `json:"reviewed_at" db:"reviewed_at"`
+	DenialReason      *string                `json:"denial_reason" db:"denial_reason"`
+	CreatedAt         time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at" db:"updated_at"`
+}
+
+// FullName returns the lead's contact name
+func (l *Lead) FullName() string {
+	if l.LastName == "" {
+		return l.FirstName
+	}
+	return l.FirstName + " " + l.LastName
+}
+
+// Lead sources; also recorded as the customer's LeadSource on conversion
+const (
+	LeadSourceWebsiteBooking      = "website_booking"
+	LeadSourceWebsiteConsultation = "website_consultation"
+	LeadSourceOnlineBooking       = "online_booking"
+	LeadSourcePhone               = "phone"
+	LeadSourceReferral            = "referral"
+	LeadSourceOther               = "other"
+)
+
+// Lead statuses
+const (
+	LeadStatusPending   = "pending"
+	LeadStatusAccepted  = "accepted"
+	LeadStatusDenied    = "denied"
+	LeadStatusScheduled = "scheduled"
+	LeadStatusSpam      = "spam"
+	LeadStatusDuplicate = "duplicate"
+)
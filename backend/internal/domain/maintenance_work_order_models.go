@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Maintenance work order statuses
+const (
+	WorkOrderStatusOpen       = "open"
+	WorkOrderStatusInProgress = "in_progress"
+	WorkOrderStatusClosed     = "closed"
+)
+
+// Maintenance work order types
+const (
+	WorkOrderTypePreventive = "preventive"
+	WorkOrderTypeRepair     = "repair"
+	WorkOrderTypeInspection = "inspection"
+)
+
+// WorkOrderTypes lists the supported work order types
+var WorkOrderTypes = []string{WorkOrderTypePreventive, WorkOrderTypeRepair, WorkOrderTypeInspection}
+
+// AttachmentEntityVendorRepair is the file attachment entity type for vendor repair invoices
+const AttachmentEntityVendorRepair = "work_order_vendor_repair"
+
+// MaintenanceWorkOrder tracks a piece of maintenance from opening to close:
+// the parts used, the mechanics' labor, outside vendor repairs and how long the
+// equipment was out of service. Costs are totalled from the line items.
+type MaintenanceWorkOrder struct {
+	ID                uuid.UUID  `json:"id" db:"id"`
+	TenantID          uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	EquipmentID       uuid.UUID  `json:"equipment_id" db:"equipment_id"`
+	Title             string     `json:"title" db:"title"`
+	Description       *string    `json:"description" db:"description"`
+	Type              string     `json:"type" db:"type"`
+	Priority          string     `json:"priority" db:"priority"`
+	Status            string     `json:"status" db:"status"`
+	AssignedTo        *uuid.UUID `json:"assigned_to" db:"assigned_to"`
+	ScheduledDate     *time.Time `json:"scheduled_date" db:"scheduled_date"`
+	OpenedBy          *uuid.UUID `json:"opened_by" db:"opened_by"`
+	StartedAt         *time.Time `json:"started_at" db:"started_at"`
+	ClosedAt          *time.Time `json:"closed_at" db:"closed_at"`
+	DowntimeStartedAt *time.Time `json:"downtime_started_at" db:"downtime_started_at"`
+	ExpectedReturnAt  *time.Time `json:"expected_return_at" db:"expected_return_at"`
+	DowntimeEndedAt   *time.Time `json:"downtime_ended_at" db:"downtime_ended_at"`
+	PartsCost         float64    `json:"parts_cost" db:"parts_cost"`
+	LaborCost         float64    `json:"labor_cost" db:"labor_cost"`
+	VendorCost        float64    `json:"vendor_cost" db:"vendor_cost"`
+	TotalCost         float64    `json:"total_cost" db:"total_cost"`
+	ResolutionNotes   *string    `json:"resolution_notes" db:"resolution_notes"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+
+	Parts         []*WorkOrderPart         `json:"parts,omitempty" db:"-"`
+	Labor         []*WorkOrderLabor        `json:"labor,omitempty" db:"-"`
+	VendorRepairs []*WorkOrderVendorRepair `json:"vendor_repairs,omitempty" db:"-"`
+}
+
+// Part is a stocked part or consumable that work orders draw from
+type Part struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	TenantID       uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	SKU            string    `json:"sku" db:"sku"`
+	Name           string    `json:"name" db:"name"`
+	Description    *string   `json:"description" db:"description"`
+	Unit           string    `json:"unit" db:"unit"`
+	UnitCost       float64   `json:"unit_cost" db:"unit_cost"`
+	QuantityOnHand float64   `json:"quantity_on_hand" db:"quantity_on_hand"`
+	ReorderPoint   float64   `json:"reorder_point" db:"reorder_point"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// WorkOrderPart records parts drawn from inventory for a work order. The name
+// and unit cost are copied from the part when it is used.
+type WorkOrderPart struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	TenantID    uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	WorkOrderID uuid.UUID  `json:"work_order_id" db:"work_order_id"`
+	PartID      uuid.UUID  `json:"part_id" db:"part_id"`
+	PartName    string     `json:"part_name" db:"part_name"`
+	Quantity    float64    `json:"quantity" db:"quantity"`
+	UnitCost    float64    `json:"unit_cost" db:"unit_cost"`
+	TotalCost   float64    `json:"total_cost" db:"total_cost"`
+	CreatedBy   *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// WorkOrderLabor records a mechanic's hours on a work order
+type WorkOrderLabor struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	TenantID    uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	WorkOrderID uuid.UUID `json:"work_order_id" db:"work_order_id"`
+	MechanicID  uuid.UUID `json:"mechanic_id" db:"mechanic_id"`
+	WorkDate    time.Time `json:"work_date" db:"work_date"`
+	Hours       float64   `json:"hours" db:"hours"`
+	HourlyRate  float64   `json:"hourly_rate" db:"hourly_rate"`
+	TotalCost   float64   `json:"total_cost" db:"total_cost"`
+	Notes       *string   `json:"notes" db:"notes"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// WorkOrderVendorRepair records work sent to an outside shop and its invoice
+type WorkOrderVendorRepair struct {
+	ID                  uuid.UUID       `json:"id" db:"id"`
+	TenantID            uuid.UUID       `json:"tenant_id" db:"tenant_id"`
+	WorkOrderID         uuid.UUID       `json:"work_order_id" db:"work_order_id"`
+	VendorName          string          `json:"vendor_name" db:"vendor_name"`
+	InvoiceNumber       *string         `json:"invoice_number" db:"invoice_number"`
+	Description         *string         `json:"description" db:"description"`
+	Amount              float64         `json:"amount" db:"amount"`
+	InvoiceAttachmentID *uuid.UUID      `json:"invoice_attachment_id" db:"invoice_attachment_id"`
+	CompletedAt         *time.Time      `json:"completed_at" db:"completed_at"`
+	CreatedAt           time.Time       `json:"created_at" db:"created_at"`
+	InvoiceAttachment   *FileAttachment `json:"invoice_attachment,omitempty" db:"-"`
+}
+
+// IsDown reports whether the work order currently has the equipment out of service
+func (wo *MaintenanceWorkOrder) IsDown() bool {
+	return wo.DowntimeStartedAt != nil && wo.DowntimeEndedAt == nil
+}
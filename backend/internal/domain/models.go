@@ -176,6 +176,9 @@ type Equipment struct {
 	LastMaintenance      *time.Time `json:"last_maintenance" db:"last_maintenance"`
 	NextMaintenance      *time.Time `json:"next_maintenance" db:"next_maintenance"`
 	Notes                *string    `json:"notes" db:"notes"`
+	WeightLbs            *float64   `json:"weight_lbs" db:"weight_lbs"`
+	PayloadCapacityLbs   *float64   `json:"payload_capacity_lbs" db:"payload_capacity_lbs"` // what a truck bed or trailer deck carries
+	TowingCapacityLbs    *float64   `json:"towing_capacity_lbs" db:"towing_capacity_lbs"`   // what a truck can tow
 	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
 }
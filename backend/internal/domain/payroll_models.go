@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pay rule types
+const (
+	PayRuleHourly         = "hourly"          // Rate per hour worked, time and a half over 40 hours a week
+	PayRulePieceRate      = "piece_rate"      // Rate per unit of a service, or per job without a service
+	PayRuleRevenuePercent = "revenue_percent" // Rate percent of the revenue of completed jobs
+	PayRuleCrewLeadBonus  = "crew_lead_bonus" // Rate per completed job the user led a crew on
+)
+
+// PayRuleTypes lists the ways a user can be paid
+var PayRuleTypes = []string{PayRuleHourly, PayRulePieceRate, PayRuleRevenuePercent, PayRuleCrewLeadBonus}
+
+// Earning types on a payroll register
+const (
+	EarningRegular    = "regular"
+	EarningOvertime   = "overtime"
+	EarningPieceRate  = "piece_rate"
+	EarningCommission = "commission"
+	EarningBonus      = "bonus"
+)
+
+// Pay period statuses. An approved period is locked: its register cannot be
+// recalculated and time entries inside it cannot change.
+const (
+	PayPeriodDraft    = "draft"
+	PayPeriodApproved = "approved"
+)
+
+// Payroll export formats
+const (
+	PayrollExportFormatRegisterCSV = "register_csv"
+	PayrollExportFormatADPCSV      = "adp_csv"
+	PayrollExportFormatGustoCSV    = "gusto_csv"
+)
+
+// PayPlan is how a user is paid: their employee number in the payroll
+// provider and the pay rules that apply to them
+type PayPlan struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	TenantID       uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	UserID         uuid.UUID  `json:"user_id" db:"user_id"`
+	EmployeeNumber *string    `json:"employee_number,omitempty" db:"employee_number"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+	Rules          []*PayRule `json:"rules" db:"-"`
+}
+
+// PayRule is one way a user earns. Piece rates are for a service, or per job
+// when ServiceID is empty. Rules apply between their effective dates.
+type PayRule struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	TenantID      uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
+	Type          string     `json:"type" db:"type"`
+	Rate          float64    `json:"rate" db:"rate"`
+	ServiceID     *uuid.UUID `json:"service_id,omitempty" db:"service_id"`
+	EffectiveFrom *time.Time `json:"effective_from,omitempty" db:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty" db:"effective_to"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsEffectiveOn reports whether the rule applies on the date
+func (r *PayRule) IsEffectiveOn(date time.Time) bool {
+	day := date.Format("2006-01-02")
+	if r.EffectiveFrom != nil && day < r.EffectiveFrom.Format("2006-01-02") {
+		return false
+	}
+	if r.EffectiveTo != nil && day > r.EffectiveTo.Format("2006-01-02") {
+		return false
+	}
+	return true
+}
+
+// TimeEntry is a stretch of time a user clocked, optionally on a job. An
+// entry without a clock out is still running and is not paid.
+type TimeEntry struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	TenantID     uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
+	JobID        *uuid.UUID `json:"job_id,omitempty" db:"job_id"`
+	ClockIn      time.Time  `json:"clock_in" db:"clock_in"`
+	ClockOut     *time.Time `json:"clock_out,omitempty" db:"clock_out"`
+	BreakMinutes int        `json:"break_minutes" db:"break_minutes"`
+	Notes        *string    `json:"notes,omitempty" db:"notes"`
+	CreatedBy    *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Hours returns the hours worked, less breaks
+func (e *TimeEntry) Hours() float64 {
+	if e.ClockOut == nil {
+		return 0
+	}
+	hours := e.ClockOut.Sub(e.ClockIn).Hours() - float64(e.BreakMinutes)/60
+	if hours < 0 {
+		return 0
+	}
+	return hours
+}
+
+// PayPeriod is a run of days paid together. StartDate and EndDate are both
+// included.
+type PayPeriod struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	TenantID     uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	StartDate    time.Time  `json:"start_date" db:"start_date"`
+	EndDate      time.Time  `json:"end_date" db:"end_date"`
+	Status       string     `json:"status" db:"status"`
+	TotalGross   float64    `json:"total_gross" db:"total_gross"`
+	CalculatedAt *time.Time `json:"calculated_at,omitempty" db:"calculated_at"`
+	ApprovedBy   *uuid.UUID `json:"approved_by,omitempty" db:"approved_by"`
+	ApprovedAt   *time.Time `json:"approved_at,omitempty" db:"approved_at"`
+	ExportedAt   *time.Time `json:"exported_at,omitempty" db:"exported_at"`
+	CreatedBy    *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Contains reports whether the time falls on one of the period's days
+func (p *PayPeriod) Contains(t time.Time) bool {
+	day := t.Format("2006-01-02")
+	return day >= p.StartDate.Format("2006-01-02") && day <= p.EndDate.Format("2006-01-02")
+}
+
+// PayrollRegisterEntry is one user's pay for a period
+type PayrollRegisterEntry struct {
+	ID             uuid.UUID      `json:"id" db:"id"`
+	TenantID       uuid.UUID      `json:"tenant_id" db:"tenant_id"`
+	PayPeriodID    uuid.UUID      `json:"pay_period_id" db:"pay_period_id"`
+	UserID         uuid.UUID      `json:"user_id" db:"user_id"`
+	FirstName      string         `json:"first_name" db:"first_name"`
+	LastName       string         `json:"last_name" db:"last_name"`
+	EmployeeNumber *string        `json:"employee_number,omitempty" db:"employee_number"`
+	HoursWorked    float64        `json:"hours_worked" db:"hours_worked"`
+	RegularHours   float64        `json:"regular_hours" db:"regular_hours"`
+	OvertimeHours  float64        `json:"overtime_hours" db:"overtime_hours"`
+	RegularPay     float64        `json:"regular_pay" db:"regular_pay"`
+	OvertimePay    float64        `json:"overtime_pay" db:"overtime_pay"`
+	PieceRatePay   float64        `json:"piece_rate_pay" db:"piece_rate_pay"`
+	CommissionPay  float64        `json:"commission_pay" db:"commission_pay"`
+	BonusPay       float64        `json:"bonus_pay" db:"bonus_pay"`
+	GrossPay       float64        `json:"gross_pay" db:"gross_pay"`
+	Lines          []*PayrollLine `json:"lines" db:"-"`
+}
+
+// PayrollLine is one earning behind a register entry
+type PayrollLine struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	EntryID     uuid.UUID  `json:"entry_id" db:"entry_id"`
+	Type        string     `json:"type" db:"type"`
+	Description string     `json:"description" db:"description"`
+	JobID       *uuid.UUID `json:"job_id,omitempty" db:"job_id"`
+	ServiceID   *uuid.UUID `json:"service_id,omitempty" db:"service_id"`
+	Quantity    float64    `json:"quantity" db:"quantity"`
+	Rate        float64    `json:"rate" db:"rate"`
+	Amount      float64    `json:"amount" db:"amount"`
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PipelineStage is a configurable step in a tenant's sales pipeline. Won and lost
+// stages close the opportunity.
+type PipelineStage struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	TenantID    uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	Key         string    `json:"key" db:"key"`
+	Name        string    `json:"name" db:"name"`
+	StageType   string    `json:"stage_type" db:"stage_type"`
+	Position    int       `json:"position" db:"position"`
+	Probability int       `json:"probability" db:"probability"` // percent chance of winning, used to weight pipeline value
+	IsActive    bool      `json:"is_active" db:"is_active"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// IsClosed reports whether opportunities in the stage are won or lost
+func (s *PipelineStage) IsClosed() bool {
+	return s.StageType == PipelineStageWon || s.StageType == PipelineStageLost
+}
+
+// Opportunity is a potential sale tracked through the pipeline
+type Opportunity struct {
+	ID                uuid.UUID  `json:"id" db:"id"`
+	TenantID          uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	Title             string     `json:"title" db:"title"`
+	StageID           uuid.UUID  `json:"stage_id" db:"stage_id"`
+	Status            string     `json:"status" db:"status"`
+	LeadID            *uuid.UUID `json:"lead_id" db:"lead_id"`
+	CustomerID        *uuid.UUID `json:"customer_id" db:"customer_id"`
+	PropertyID        *uuid.UUID `json:"property_id" db:"property_id"`
+	QuoteID           *uuid.UUID `json:"quote_id" db:"quote_id"`
+	ContactName       *string    `json:"contact_name" db:"contact_name"`
+	ContactEmail      *string    `json:"contact_email" db:"contact_email"`
+	ContactPhone      *string    `json:"contact_phone" db:"contact_phone"`
+	Source            *string    `json:"source" db:"source"`
+	EstimatedValue    float64    `json:"estimated_value" db:"estimated_value"`
+	Probability       *int       `json:"probability" db:"probability"` // overrides the stage probability when set
+	ExpectedCloseDate *time.Time `json:"expected_close_date" db:"expected_close_date"`
+	AssignedTo        *uuid.UUID `json:"assigned_to" db:"assigned_to"`
+	CloseReason       *string    `json:"close_reason" db:"close_reason"`
+	CloseNotes        *string    `json:"close_notes" db:"close_notes"`
+	ClosedAt          *time.Time `json:"closed_at" db:"closed_at"`
+	StageChangedAt    time.Time  `json:"stage_changed_at" db:"stage_changed_at"`
+	Notes             *string    `json:"notes" db:"notes"`
+	CreatedBy         *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// OpportunityStageChange records an opportunity moving between stages
+type OpportunityStageChange struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	TenantID      uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	OpportunityID uuid.UUID  `json:"opportunity_id" db:"opportunity_id"`
+	FromStageID   *uuid.UUID `json:"from_stage_id" db:"from_stage_id"`
+	ToStageID     uuid.UUID  `json:"to_stage_id" db:"to_stage_id"`
+	ChangedBy     *uuid.UUID `json:"changed_by" db:"changed_by"`
+	ChangedAt     time.Time  `json:"changed_at" db:"changed_at"`
+}
+
+// FollowUpTask is a sales to-do with a due date, e.g. calling back a prospect
+type FollowUpTask struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	TenantID       uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	OpportunityID  *uuid.UUID `json:"opportunity_id" db:"opportunity_id"`
+	CustomerID     *uuid.UUID `json:"customer_id" db:"customer_id"`
+	AssignedTo     *uuid.UUID `json:"assigned_to" db:"assigned_to"`
+	Title          string     `json:"title" db:"title"`
+	Description    *string    `json:"description" db:"description"`
+	TaskType       string     `json:"task_type" db:"task_type"`
+	DueAt          time.Time  `json:"due_at" db:"due_at"`
+	RemindAt       *time.Time `json:"remind_at" db:"remind_at"` // defaults to the due time
+	ReminderSentAt *time.Time `json:"reminder_sent_at" db:"reminder_sent_at"`
+	Status         string     `json:"status" db:"status"`
+	CompletedAt    *time.Time `json:"completed_at" db:"completed_at"`
+	CompletedBy    *uuid.UUID `json:"completed_by" db:"completed_by"`
+	Outcome        *string    `json:"outcome" db:"outcome"`
+	CreatedBy      *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Pipeline stage types
+const (
+	PipelineStageOpen = "open"
+	PipelineStageWon  = "won"
+	PipelineStageLost = "lost"
+)
+
+// Opportunity statuses
+const (
+	OpportunityStatusOpen = "open"
+	OpportunityStatusWon  = "won"
+	OpportunityStatusLost = "lost"
+)
+
+// Follow-up task statuses
+const (
+	FollowUpStatusOpen      = "open"
+	FollowUpStatusCompleted = "completed"
+	FollowUpStatusCancelled = "cancelled"
+)
+
+// Follow-up task types
+const (
+	FollowUpTypeCall      = "call"
+	FollowUpTypeEmail     = "email"
+	FollowUpTypeSiteVisit = "site_visit"
+	FollowUpTypeOther     = "other"
+)
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PortalSettings holds a tenant's customer portal rules
+type PortalSettings struct {
+	ID                   uuid.UUID `json:"id" db:"id"`
+	TenantID             uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	Enabled              bool      `json:"enabled" db:"enabled"`
+	PortalURL            *string   `json:"portal_url" db:"portal_url"`
+	MagicLinkTTLMinutes  int       `json:"magic_link_ttl_minutes" db:"magic_link_ttl_minutes"`
+	SessionTTLHours      int       `json:"session_ttl_hours" db:"session_ttl_hours"`
+	AllowOnlinePayment   bool      `json:"allow_online_payment" db:"allow_online_payment"`
+	AllowQuoteAcceptance bool      `json:"allow_quote_acceptance" db:"allow_quote_acceptance"`
+	AllowSkip            bool      `json:"allow_skip" db:"allow_skip"`
+	AllowReschedule      bool      `json:"allow_reschedule" db:"allow_reschedule"`
+	MinNoticeHours       int       `json:"min_notice_hours" db:"min_notice_hours"`       // required notice before the visit
+	MaxRescheduleDays    int       `json:"max_reschedule_days" db:"max_reschedule_days"` // how far a visit can move; 0 is unlimited
+	MaxSkipsPerYear      int       `json:"max_skips_per_year" db:"max_skips_per_year"`   // 0 is unlimited
+	AutoApproveChanges   bool      `json:"auto_approve_changes" db:"auto_approve_changes"`
+	SignatureConsentText *string   `json:"signature_consent_text" db:"signature_consent_text"`
+	CreatedAt            time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PortalLoginToken is a single-use magic link sent to a customer
+type PortalLoginToken struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	TenantID   uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	CustomerID uuid.UUID  `json:"customer_id" db:"customer_id"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	Email      string     `json:"email" db:"email"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt     *time.Time `json:"used_at" db:"used_at"`
+	IPAddress  *string    `json:"ip_address" db:"ip_address"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// PortalSession is a signed-in customer portal session
+type PortalSession struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	TenantID   uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	CustomerID uuid.UUID  `json:"customer_id" db:"customer_id"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	LastSeenAt *time.Time `json:"last_seen_at" db:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`
+	IPAddress  *string    `json:"ip_address" db:"ip_address"`
+	UserAgent  *string    `json:"user_agent" db:"user_agent"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// QuoteSignature records a customer's e-signature accepting a quote revision
+type QuoteSignature struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	TenantID       uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	QuoteID        uuid.UUID  `json:"quote_id" db:"quote_id"`
+	QuoteVersionID *uuid.UUID `json:"quote_version_id" db:"quote_version_id"`
+	CustomerID     uuid.UUID  `json:"customer_id" db:"customer_id"`
+	SignerName     string     `json:"signer_name" db:"signer_name"`
+	SignerEmail    *string    `json:"signer_email" db:"signer_email"`
+	SignatureType  string     `json:"signature_type" db:"signature_type"`
+	SignatureData  string     `json:"signature_data" db:"signature_data"` // typed name or drawn image data URL
+	ConsentText    string     `json:"consent_text" db:"consent_text"`
+	TotalAmount    float64    `json:"total_amount" db:"total_amount"`
+	IPAddress      *string    `json:"ip_address" db:"ip_address"`
+	UserAgent      *string    `json:"user_agent" db:"user_agent"`
+	SignedAt       time.Time  `json:"signed_at" db:"signed_at"`
+}
+
+// PortalScheduleChange is a customer's request to skip or reschedule a visit
+type PortalScheduleChange struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	TenantID      uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	CustomerID    uuid.UUID  `json:"customer_id" db:"customer_id"`
+	JobID         uuid.UUID  `json:"job_id" db:"job_id"`
+	ChangeType    string     `json:"change_type" db:"change_type"`
+	OriginalDate  *time.Time `json:"original_date" db:"original_date"`
+	RequestedDate *time.Time `json:"requested_date" db:"requested_date"`
+	Reason        *string    `json:"reason" db:"reason"`
+	Status        string     `json:"status" db:"status"`
+	AutoApproved  bool       `json:"auto_approved" db:"auto_approved"`
+	ReviewedBy    *uuid.UUID `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt    *time.Time `json:"reviewed_at" db:"reviewed_at"`
+	ResponseNote  *string    `json:"response_note" db:"response_note"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// PortalServiceRequest is a service request a customer submitted through the portal
+type PortalServiceRequest struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	TenantID      uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	CustomerID    uuid.UUID  `json:"customer_id" db:"customer_id"`
+	PropertyID    *uuid.UUID `json:"property_id" db:"property_id"`
+	ServiceID     *uuid.UUID `json:"service_id" db:"service_id"`
+	Description   string     `json:"description" db:"description"`
+	PreferredDate *time.Time `json:"preferred_date" db:"preferred_date"`
+	Status        string     `json:"status" db:"status"`
+	AdminNotes    *string    `json:"admin_notes" db:"admin_notes"`
+	AssignedTo    *uuid.UUID `json:"assigned_to" db:"assigned_to"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Signature types
+const (
+	SignatureTypeTyped = "typed"
+	SignatureTypeDrawn = "drawn"
+)
+
+// Schedule change types
+const (
+	ScheduleChangeSkip       = "skip"
+	ScheduleChangeReschedule = "reschedule"
+)
+
+// Schedule change statuses
+const (
+	ScheduleChangeStatusPending   = "pending"
+	ScheduleChangeStatusApproved  = "approved"
+	ScheduleChangeStatusDeclined  = "declined"
+	ScheduleChangeStatusCancelled = "cancelled"
+)
+
+// Portal service request statuses
+const (
+	PortalRequestStatusNew       = "new"
+	PortalRequestStatusInReview  = "in_review"
+	PortalRequestStatusQuoted    = "quoted"
+	PortalRequestStatusScheduled = "scheduled"
+	PortalRequestStatusClosed    = "closed"
+)
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceBook is a set of pricing rules in effect for a date range. A seasonal book
+// overrides the standing book's rules of the same type while it is in effect.
+type PriceBook struct {
+	ID            uuid.UUID     `json:"id" db:"id"`
+	TenantID      uuid.UUID     `json:"tenant_id" db:"tenant_id"`
+	Name          string        `json:"name" db:"name"`
+	Description   *string       `json:"description" db:"description"`
+	EffectiveFrom time.Time     `json:"effective_from" db:"effective_from"`
+	EffectiveTo   *time.Time    `json:"effective_to" db:"effective_to"`
+	IsActive      bool          `json:"is_active" db:"is_active"`
+	Rules         []PricingRule `json:"rules,omitempty" db:"-"`
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// PricingRule is a single pricing rule in a price book. Rules are scoped to a
+// service, a public catalog key, a category, or every service when no scope is set;
+// the most specific scope wins.
+type PricingRule struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	TenantID     uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	PriceBookID  uuid.UUID  `json:"price_book_id" db:"price_book_id"`
+	Name         string     `json:"name" db:"name"`
+	RuleType     string     `json:"rule_type" db:"rule_type"`
+	ServiceID    *uuid.UUID `json:"service_id" db:"service_id"`
+	ServiceKey   *string    `json:"service_key" db:"service_key"` // public catalog key, e.g. lawn_care
+	Category     *string    `json:"category" db:"category"`
+	Unit         *string    `json:"unit" db:"unit"`
+	MeasuredArea *string    `json:"measured_area" db:"measured_area"` // site map feature type a square-foot rule prices, e.g. turf
+	Rate         float64    `json:"rate" db:"rate"`                   // per-unit rate, minimum charge, or fraction for percentages
+	MinValue     *float64   `json:"min_value" db:"min_value"`         // size tier floor (sq ft), distance (miles) or service count
+	MaxValue     *float64   `json:"max_value" db:"max_value"`         // size tier ceiling, exclusive
+	Frequency    *string    `json:"frequency" db:"frequency"`
+	ZipCodes     []string   `json:"zip_codes" db:"zip_codes"`
+	IsActive     bool       `json:"is_active" db:"is_active"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Pricing rule types
+const (
+	PricingRuleUnitRate          = "unit_rate"
+	PricingRuleSizeTier          = "size_tier"
+	PricingRuleMinimumCharge     = "minimum_charge"
+	PricingRuleFrequencyDiscount = "frequency_discount"
+	PricingRuleZoneSurcharge     = "zone_surcharge"
+	PricingRuleBundleDiscount    = "bundle_discount"
+	PricingRuleSameDayDiscount   = "same_day_discount"
+	PricingRuleDistanceSurcharge = "distance_surcharge"
+)
+
+// Pricing units
+const (
+	PricingUnitSqFt  = "sq_ft"
+	PricingUnitHour  = "hour"
+	PricingUnitYard  = "yard"
+	PricingUnitVisit = "visit"
+)
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuoteOption is a package the customer can choose within a quote option group,
+// e.g. "Basic mow" versus "Full-service maintenance"
+type QuoteOption struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	TenantID    uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	QuoteID     uuid.UUID `json:"quote_id" db:"quote_id"`
+	GroupName   string    `json:"group_name" db:"group_name"`
+	Name        string    `json:"name" db:"name"`
+	Description *string   `json:"description" db:"description"`
+	SortOrder   int       `json:"sort_order" db:"sort_order"`
+	IsDefault   bool      `json:"is_default" db:"is_default"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// QuoteSelection records the options and add-ons a customer chose when approving a quote
+type QuoteSelection struct {
+	VersionID    uuid.UUID   `json:"version_id"`
+	OptionIDs    []uuid.UUID `json:"option_ids"`
+	AddOnLineIDs []uuid.UUID `json:"add_on_line_ids"`
+	Subtotal     float64     `json:"subtotal"`
+	TaxAmount    float64     `json:"tax_amount"`
+	TotalAmount  float64     `json:"total_amount"`
+	SelectedAt   time.Time   `json:"selected_at"`
+}
+
+// Default quote option group name
+const QuoteOptionGroupDefault = "Options"
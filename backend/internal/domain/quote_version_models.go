@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuoteVersion is an immutable snapshot of a quote's line items and terms
+type QuoteVersion struct {
+	ID                 uuid.UUID          `json:"id" db:"id"`
+	TenantID           uuid.UUID          `json:"tenant_id" db:"tenant_id"`
+	QuoteID            uuid.UUID          `json:"quote_id" db:"quote_id"`
+	VersionNumber      int                `json:"version_number" db:"version_number"`
+	Revision           string             `json:"revision" db:"revision"`
+	Title              string             `json:"title" db:"title"`
+	Description        *string            `json:"description" db:"description"`
+	Subtotal           float64            `json:"subtotal" db:"subtotal"`
+	TaxRate            float64            `json:"tax_rate" db:"tax_rate"`
+	TaxAmount          float64            `json:"tax_amount" db:"tax_amount"`
+	TotalAmount        float64            `json:"total_amount" db:"total_amount"`
+	ValidUntil         *time.Time         `json:"valid_until" db:"valid_until"`
+	TermsAndConditions *string            `json:"terms_and_conditions" db:"terms_and_conditions"`
+	Notes              *string            `json:"notes" db:"notes"`
+	LineItems          []QuoteVersionLine `json:"line_items" db:"line_items"`
+	Options            []QuoteOption      `json:"options" db:"options"`
+	ChangeSummary      *string            `json:"change_summary" db:"change_summary"`
+	SentAt             *time.Time         `json:"sent_at" db:"sent_at"`
+	SentTo             *string            `json:"sent_to" db:"sent_to"`
+	CreatedBy          *uuid.UUID         `json:"created_by" db:"created_by"`
+	CreatedAt          time.Time          `json:"created_at" db:"created_at"`
+}
+
+// QuoteVersionLine is a line item captured in a quote version
+type QuoteVersionLine struct {
+	LineID      uuid.UUID  `json:"line_id"`
+	ServiceID   uuid.UUID  `json:"service_id"`
+	ServiceName string     `json:"service_name"`
+	OptionID    *uuid.UUID `json:"option_id,omitempty"`
+	IsOptional  bool       `json:"is_optional"`
+	Quantity    float64    `json:"quantity"`
+	UnitPrice   float64    `json:"unit_price"`
+	TotalPrice  float64    `json:"total_price"`
+	Description *string    `json:"description,omitempty"`
+}
+
+// Quote version line change types
+const (
+	QuoteLineChangeAdded    = "added"
+	QuoteLineChangeRemoved  = "removed"
+	QuoteLineChangeModified = "modified"
+)
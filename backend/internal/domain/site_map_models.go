@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PropertyFeature is one measured area on a property's site map: a turf zone, a
+// bed, a tree canopy, an irrigation zone, hardscape or an obstacle. The geometry
+// is a GeoJSON Polygon or MultiPolygon in WGS84 longitude/latitude; area and
+// perimeter are computed from it whenever it changes. Cut-outs such as a shed in
+// the middle of a lawn are drawn as polygon holes.
+type PropertyFeature struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	TenantID    uuid.UUID       `json:"tenant_id" db:"tenant_id"`
+	PropertyID  uuid.UUID       `json:"property_id" db:"property_id"`
+	FeatureType string          `json:"feature_type" db:"feature_type"`
+	Name        *string         `json:"name" db:"name"`
+	Geometry    json.RawMessage `json:"geometry" db:"geometry"`
+	AreaSqFt    float64         `json:"area_sq_ft" db:"area_sq_ft"`
+	PerimeterFt float64         `json:"perimeter_ft" db:"perimeter_ft"`
+	Notes       *string         `json:"notes" db:"notes"`
+	CreatedBy   *uuid.UUID      `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Property feature types
+const (
+	PropertyFeatureTurf           = "turf"
+	PropertyFeatureBed            = "bed"
+	PropertyFeatureTree           = "tree"
+	PropertyFeatureIrrigationZone = "irrigation_zone"
+	PropertyFeatureHardscape      = "hardscape"
+	PropertyFeatureObstacle       = "obstacle"
+)
+
+// PropertyFeatureTypes lists the feature types in display order
+var PropertyFeatureTypes = []string{
+	PropertyFeatureTurf,
+	PropertyFeatureBed,
+	PropertyFeatureTree,
+	PropertyFeatureIrrigationZone,
+	PropertyFeatureHardscape,
+	PropertyFeatureObstacle,
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatementRun records one execution of the monthly statement/consolidated invoicing process
+type StatementRun struct {
+	ID                 uuid.UUID  `json:"id" db:"id"`
+	TenantID           uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	PeriodStart        time.Time  `json:"period_start" db:"period_start"`
+	PeriodEnd          time.Time  `json:"period_end" db:"period_end"`
+	GroupBy            string     `json:"group_by" db:"group_by"`
+	Status             string     `json:"status" db:"status"`
+	CustomersProcessed int        `json:"customers_processed" db:"customers_processed"`
+	InvoicesCreated    int        `json:"invoices_created" db:"invoices_created"`
+	StatementsSent     int        `json:"statements_sent" db:"statements_sent"`
+	TotalAmount        float64    `json:"total_amount" db:"total_amount"`
+	ErrorMessage       *string    `json:"error_message" db:"error_message"`
+	TriggeredBy        *uuid.UUID `json:"triggered_by" db:"triggered_by"`
+	StartedAt          time.Time  `json:"started_at" db:"started_at"`
+	CompletedAt        *time.Time `json:"completed_at" db:"completed_at"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+}
+
+// StatementSchedule holds a tenant's automatic statement run settings
+type StatementSchedule struct {
+	ID                  uuid.UUID  `json:"id" db:"id"`
+	TenantID            uuid.UUID  `json:"tenant_id" db:"tenant_id"`
+	Enabled             bool       `json:"enabled" db:"enabled"`
+	DayOfMonth          int        `json:"day_of_month" db:"day_of_month"`
+	GroupBy             string     `json:"group_by" db:"group_by"`
+	AutoSendInvoices    bool       `json:"auto_send_invoices" db:"auto_send_invoices"`
+	SendAgingStatements bool       `json:"send_aging_statements" db:"send_aging_statements"`
+	PaymentTermsDays    int        `json:"payment_terms_days" db:"payment_terms_days"`
+	LastRunAt           *time.Time `json:"last_run_at" db:"last_run_at"`
+	NextRunAt           *time.Time `json:"next_run_at" db:"next_run_at"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// InvoiceJob links a completed job to the consolidated invoice that billed it
+type InvoiceJob struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	TenantID   uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	InvoiceID  uuid.UUID `json:"invoice_id" db:"invoice_id"`
+	JobID      uuid.UUID `json:"job_id" db:"job_id"`
+	PropertyID uuid.UUID `json:"property_id" db:"property_id"`
+	PONumber   *string   `json:"po_number" db:"po_number"`
+	Amount     float64   `json:"amount" db:"amount"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// Statement constants
+const (
+	// Statement grouping
+	StatementGroupByCustomer = "customer"
+	StatementGroupByProperty = "property"
+	StatementGroupByPONumber = "po_number"
+
+	// Statement run statuses
+	StatementRunStatusRunning   = "running"
+	StatementRunStatusCompleted = "completed"
+	StatementRunStatusFailed    = "failed"
+)
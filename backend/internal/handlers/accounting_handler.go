@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// AccountingHandler handles accounting export and sync operations
+type AccountingHandler struct {
+	accountingService services.AccountingService
+}
+
+// NewAccountingHandler creates a new accounting handler
+func NewAccountingHandler(accountingService services.AccountingService) *AccountingHandler {
+	return &AccountingHandler{
+		accountingService: accountingService,
+	}
+}
+
+// SetupAccountingRoutes sets up accounting routes
+func (h *AccountingHandler) SetupAccountingRoutes(router *mux.Router) {
+	accounting := router.PathPrefix("/accounting").Subrouter()
+
+	// Chart-of-accounts mappings
+	accounting.HandleFunc("/mappings", h.ListAccountMappings).Methods("GET")
+	accounting.HandleFunc("/mappings", h.SaveAccountMapping).Methods("PUT")
+	accounting.HandleFunc("/mappings/{id}", h.DeleteAccountMapping).Methods("DELETE")
+
+	// File exports
+	accounting.HandleFunc("/export", h.ExportAccountingData).Methods("GET")
+
+	// Provider connections and sync
+	accounting.HandleFunc("/connections", h.ListConnections).Methods("GET")
+	accounting.HandleFunc("/connections", h.ConnectProvider).Methods("POST")
+	accounting.HandleFunc("/connections/{provider}", h.DisconnectProvider).Methods("DELETE")
+	accounting.HandleFunc("/sync/{provider}", h.SyncProvider).Methods("POST")
+	accounting.HandleFunc("/sync-records", h.ListSyncRecords).Methods("GET")
+}
+
+// Mappings
+
+func (h *AccountingHandler) ListAccountMappings(w http.ResponseWriter, r *http.Request) {
+	mappings, err := h.accountingService.ListAccountMappings(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list account mappings: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, mappings)
+}
+
+func (h *AccountingHandler) SaveAccountMapping(w http.ResponseWriter, r *http.Request) {
+	var req services.AccountMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	mapping, err := h.accountingService.SaveAccountMapping(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to save account mapping: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, mapping)
+}
+
+func (h *AccountingHandler) DeleteAccountMapping(w http.ResponseWriter, r *http.Request) {
+	mappingID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid mapping ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.accountingService.DeleteAccountMapping(r.Context(), mappingID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete account mapping: %v", err), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Exports
+
+func (h *AccountingHandler) ExportAccountingData(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	startDate, err := time.Parse("2006-01-02", query.Get("start_date"))
+	if err != nil {
+		http.Error(w, "Invalid start_date", http.StatusBadRequest)
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", query.Get("end_date"))
+	if err != nil {
+		http.Error(w, "Invalid end_date", http.StatusBadRequest)
+		return
+	}
+
+	export, err := h.accountingService.ExportAccountingData(r.Context(), &services.AccountingExportRequest{
+		Format:    query.Get("format"),
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to export accounting data: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", export.Filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(export.Data)
+}
+
+// Connections and sync
+
+func (h *AccountingHandler) ListConnections(w http.ResponseWriter, r *http.Request) {
+	connections, err := h.accountingService.ListConnections(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list accounting connections: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, connections)
+}
+
+func (h *AccountingHandler) ConnectProvider(w http.ResponseWriter, r *http.Request) {
+	var req services.AccountingConnectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	connection, err := h.accountingService.ConnectProvider(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to connect accounting provider: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, connection)
+}
+
+func (h *AccountingHandler) DisconnectProvider(w http.ResponseWriter, r *http.Request) {
+	provider := mux.Vars(r)["provider"]
+
+	if err := h.accountingService.DisconnectProvider(r.Context(), provider); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to disconnect accounting provider: %v", err), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AccountingHandler) SyncProvider(w http.ResponseWriter, r *http.Request) {
+	provider := mux.Vars(r)["provider"]
+
+	result, err := h.accountingService.SyncProvider(r.Context(), provider)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to sync accounting data: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+func (h *AccountingHandler) ListSyncRecords(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &services.AccountingSyncRecordFilter{
+		BaseFilter: services.BaseFilter{
+			Page:    getIntQueryParam(r, "page", 1),
+			PerPage: getIntQueryParam(r, "per_page", 50),
+		},
+		Provider:   query.Get("provider"),
+		EntityType: query.Get("entity_type"),
+		Status:     query.Get("status"),
+	}
+
+	records, err := h.accountingService.ListSyncRecords(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list sync records: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, records)
+}
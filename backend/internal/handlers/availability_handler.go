@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// AvailabilityHandler handles working hours, time off requests and holidays
+type AvailabilityHandler struct {
+	availabilityService services.AvailabilityService
+}
+
+// NewAvailabilityHandler creates a new availability handler
+func NewAvailabilityHandler(availabilityService services.AvailabilityService) *AvailabilityHandler {
+	return &AvailabilityHandler{
+		availabilityService: availabilityService,
+	}
+}
+
+// SetupAvailabilityRoutes sets up the availability routes
+func (h *AvailabilityHandler) SetupAvailabilityRoutes(router *mux.Router) {
+	availability := router.PathPrefix("/availability").Subrouter()
+	availability.HandleFunc("/users/{id}/working-hours", h.GetWorkingHours).Methods("GET")
+	availability.HandleFunc("/users/{id}/working-hours", h.SetWorkingHours).Methods("PUT")
+	availability.HandleFunc("/users/{id}/calendar", h.GetShiftCalendar).Methods("GET")
+	availability.HandleFunc("/time-off", h.ListTimeOff).Methods("GET")
+	availability.HandleFunc("/time-off", h.RequestTimeOff).Methods("POST")
+	availability.HandleFunc("/time-off/{id}/approve", h.ApproveTimeOff).Methods("POST")
+	availability.HandleFunc("/time-off/{id}/deny", h.DenyTimeOff).Methods("POST")
+	availability.HandleFunc("/time-off/{id}/cancel", h.CancelTimeOff).Methods("POST")
+	availability.HandleFunc("/holidays", h.ListHolidays).Methods("GET")
+	availability.HandleFunc("/holidays", h.CreateHoliday).Methods("POST")
+	availability.HandleFunc("/holidays/{id}", h.DeleteHoliday).Methods("DELETE")
+}
+
+func (h *AvailabilityHandler) GetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	hours, err := h.availabilityService.GetWorkingHours(r.Context(), userID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get working hours: %v", err), availabilityErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, hours)
+}
+
+// SetWorkingHours replaces the user's working-hour template
+func (h *AvailabilityHandler) SetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req []services.WorkingHoursRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	hours, err := h.availabilityService.SetWorkingHours(r.Context(), userID, req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to set working hours: %v", err), availabilityErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, hours)
+}
+
+// GetShiftCalendar returns the user's shifts from ?from= to ?to=, two weeks
+// from today by default
+func (h *AvailabilityHandler) GetShiftCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	from, to, ok := parseAvailabilityDateRange(w, r, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), 14)
+	if !ok {
+		return
+	}
+
+	calendar, err := h.availabilityService.GetShiftCalendar(r.Context(), userID, from, to)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get shift calendar: %v", err), availabilityErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, calendar)
+}
+
+// ListTimeOff lists time off requests by ?user_id=, ?status= and overlap with ?from= to ?to=
+func (h *AvailabilityHandler) ListTimeOff(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &services.TimeOffFilter{Status: query.Get("status")}
+
+	if value := query.Get("user_id"); value != "" {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			http.Error(w, "Invalid user_id", http.StatusBadRequest)
+			return
+		}
+		filter.UserID = &userID
+	}
+	if value := query.Get("from"); value != "" {
+		from, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			http.Error(w, "Invalid from format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		filter.From = &from
+	}
+	if value := query.Get("to"); value != "" {
+		to, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			http.Error(w, "Invalid to format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		// Include the whole end day
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+
+	requests, err := h.availabilityService.ListTimeOff(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list time off requests: %v", err), availabilityErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, requests)
+}
+
+func (h *AvailabilityHandler) RequestTimeOff(w http.ResponseWriter, r *http.Request) {
+	var req services.TimeOffCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	request, err := h.availabilityService.RequestTimeOff(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to request time off: %v", err), availabilityErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, request)
+}
+
+func (h *AvailabilityHandler) ApproveTimeOff(w http.ResponseWriter, r *http.Request) {
+	h.reviewTimeOff(w, r, h.availabilityService.ApproveTimeOff, "approve")
+}
+
+func (h *AvailabilityHandler) DenyTimeOff(w http.ResponseWriter, r *http.Request) {
+	h.reviewTimeOff(w, r, h.availabilityService.DenyTimeOff, "deny")
+}
+
+func (h *AvailabilityHandler) CancelTimeOff(w http.ResponseWriter, r *http.Request) {
+	requestID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid time off request ID", http.StatusBadRequest)
+		return
+	}
+
+	request, err := h.availabilityService.CancelTimeOff(r.Context(), requestID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to cancel time off: %v", err), availabilityErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, request)
+}
+
+// ListHolidays lists holidays from ?from= to ?to=, the coming year by default
+func (h *AvailabilityHandler) ListHolidays(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	from, to, ok := parseAvailabilityDateRange(w, r, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), 365)
+	if !ok {
+		return
+	}
+
+	holidays, err := h.availabilityService.ListHolidays(r.Context(), from, to)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list holidays: %v", err), availabilityErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, holidays)
+}
+
+func (h *AvailabilityHandler) CreateHoliday(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Date string `json:"date"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		http.Error(w, "Invalid date format, use YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	holiday, err := h.availabilityService.CreateHoliday(r.Context(), &services.HolidayRequest{
+		Date: date,
+		Name: req.Name,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create holiday: %v", err), availabilityErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, holiday)
+}
+
+func (h *AvailabilityHandler) DeleteHoliday(w http.ResponseWriter, r *http.Request) {
+	holidayID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid holiday ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.availabilityService.DeleteHoliday(r.Context(), holidayID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete holiday: %v", err), availabilityErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AvailabilityHandler) reviewTimeOff(w http.ResponseWriter, r *http.Request, review func(ctx context.Context, requestID uuid.UUID, notes *string) (*domain.TimeOffRequest, error), action string) {
+	requestID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid time off request ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Notes *string `json:"notes,omitempty"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	request, err := review(r.Context(), requestID, req.Notes)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to %s time off: %v", action, err), availabilityErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, request)
+}
+
+// parseAvailabilityDateRange reads ?from= and ?to= as dates, returning the
+// range through the end of the to day
+func parseAvailabilityDateRange(w http.ResponseWriter, r *http.Request, defaultFrom time.Time, defaultDays int) (time.Time, time.Time, bool) {
+	query := r.URL.Query()
+	from := defaultFrom
+	to := from.AddDate(0, 0, defaultDays-1)
+	var err error
+	if value := query.Get("from"); value != "" {
+		if from, err = time.Parse("2006-01-02", value); err != nil {
+			http.Error(w, "Invalid from format, use YYYY-MM-DD", http.StatusBadRequest)
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	if value := query.Get("to"); value != "" {
+		if to, err = time.Parse("2006-01-02", value); err != nil {
+			http.Error(w, "Invalid to format, use YYYY-MM-DD", http.StatusBadRequest)
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	return from, to.AddDate(0, 0, 1), true
+}
+
+func availabilityErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	case strings.Contains(message, "cannot be"), strings.Contains(message, "already exists"):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
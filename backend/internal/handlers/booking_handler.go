@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// BookingHandler handles online self-scheduling. The public routes can be called
+// from a tenant's own website when its origin is in the booking settings.
+type BookingHandler struct {
+	bookingService services.BookingService
+}
+
+// NewBookingHandler creates a new booking handler
+func NewBookingHandler(bookingService services.BookingService) *BookingHandler {
+	return &BookingHandler{
+		bookingService: bookingService,
+	}
+}
+
+// SetupBookingRoutes sets up the public slot and hold routes and the protected
+// settings routes
+func (h *BookingHandler) SetupBookingRoutes(public, protected *mux.Router) {
+	booking := public.PathPrefix("/public/booking").Subrouter()
+	booking.HandleFunc("/slots", h.GetAvailableSlots).Methods("GET")
+	booking.HandleFunc("/holds", h.HoldSlot).Methods("POST")
+	booking.HandleFunc("/holds/{id}", h.ReleaseHold).Methods("DELETE")
+	booking.HandleFunc("/holds/{id}/confirm", h.ConfirmBooking).Methods("POST")
+	booking.PathPrefix("/").HandlerFunc(h.Preflight).Methods("OPTIONS")
+
+	settings := protected.PathPrefix("/booking").Subrouter()
+	settings.HandleFunc("/settings", h.GetSettings).Methods("GET")
+	settings.HandleFunc("/settings", h.UpdateSettings).Methods("PUT")
+	settings.HandleFunc("/blackouts", h.ListBlackouts).Methods("GET")
+	settings.HandleFunc("/blackouts", h.CreateBlackout).Methods("POST")
+	settings.HandleFunc("/blackouts/{id}", h.DeleteBlackout).Methods("DELETE")
+}
+
+// Preflight answers CORS preflight requests from embedding sites. The tenant must be
+// given in the query string since preflight requests have no body.
+func (h *BookingHandler) Preflight(w http.ResponseWriter, r *http.Request) {
+	tenantID, _ := uuid.Parse(r.URL.Query().Get("tenant_id"))
+	if !h.allowEmbed(w, r, tenantID) {
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Set("Access-Control-Max-Age", "600")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BookingHandler) GetAvailableSlots(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	tenantID, err := uuid.Parse(query.Get("tenant_id"))
+	if err != nil {
+		http.Error(w, "Invalid tenant ID", http.StatusBadRequest)
+		return
+	}
+	h.allowEmbed(w, r, tenantID)
+
+	req := &services.BookingSlotRequest{
+		TenantID:  tenantID,
+		ServiceID: parseOptionalUUID(query.Get("service_id")),
+	}
+	if from := query.Get("from"); from != "" {
+		if req.From, err = time.Parse("2006-01-02", from); err != nil {
+			http.Error(w, "Invalid from format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	if to := query.Get("to"); to != "" {
+		if req.To, err = time.Parse("2006-01-02", to); err != nil {
+			http.Error(w, "Invalid to format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	req.DurationMinutes, _ = strconv.Atoi(query.Get("duration_minutes"))
+	if lat, err := strconv.ParseFloat(query.Get("lat"), 64); err == nil {
+		req.Latitude = &lat
+	}
+	if lng, err := strconv.ParseFloat(query.Get("lng"), 64); err == nil {
+		req.Longitude = &lng
+	}
+
+	slots, err := h.bookingService.GetAvailableSlots(r.Context(), req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get available slots: %v", err), bookingErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, slots)
+}
+
+func (h *BookingHandler) HoldSlot(w http.ResponseWriter, r *http.Request) {
+	var req services.BookingHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	h.allowEmbed(w, r, req.TenantID)
+	req.IPAddress = portalClientInfo(r).IPAddress
+
+	hold, err := h.bookingService.HoldSlot(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to hold arrival window: %v", err), bookingErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, hold)
+}
+
+func (h *BookingHandler) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	holdID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid hold ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	tenantID, err := uuid.Parse(query.Get("tenant_id"))
+	if err != nil {
+		http.Error(w, "Invalid tenant ID", http.StatusBadRequest)
+		return
+	}
+	h.allowEmbed(w, r, tenantID)
+
+	if err := h.bookingService.ReleaseHold(r.Context(), &services.BookingHoldReference{
+		TenantID: tenantID,
+		HoldID:   holdID,
+		Token:    query.Get("token"),
+	}); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to release hold: %v", err), bookingErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ConfirmBooking completes a held booking with the visitor's contact details
+func (h *BookingHandler) ConfirmBooking(w http.ResponseWriter, r *http.Request) {
+	holdID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid hold ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.BookingConfirmRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.HoldID = holdID
+	h.allowEmbed(w, r, req.TenantID)
+
+	client := portalClientInfo(r)
+	req.Contact.IPAddress = client.IPAddress
+	req.Contact.UserAgent = client.UserAgent
+
+	confirmation, err := h.bookingService.ConfirmBooking(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to confirm booking: %v", err), bookingErrorStatus(err))
+		return
+	}
+
+	// Visitors see the window they booked, not the internal job and quote
+	respondWithJSON(w, http.StatusCreated, map[string]interface{}{
+		"message":      "You're booked! We'll confirm your appointment shortly",
+		"window_start": confirmation.Hold.WindowStart,
+		"window_end":   confirmation.Hold.WindowEnd,
+	})
+}
+
+func (h *BookingHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.bookingService.GetSettings(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get booking settings: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, settings)
+}
+
+func (h *BookingHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req services.BookingSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.bookingService.UpdateSettings(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update booking settings: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, settings)
+}
+
+func (h *BookingHandler) ListBlackouts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from := time.Now()
+	to := from.AddDate(0, 3, 0)
+	var err error
+	if value := query.Get("from"); value != "" {
+		if from, err = time.Parse("2006-01-02", value); err != nil {
+			http.Error(w, "Invalid from format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	if value := query.Get("to"); value != "" {
+		if to, err = time.Parse("2006-01-02", value); err != nil {
+			http.Error(w, "Invalid to format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+
+	blackouts, err := h.bookingService.ListBlackouts(r.Context(), from, to.AddDate(0, 0, 1))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list blackout days: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, blackouts)
+}
+
+func (h *BookingHandler) CreateBlackout(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Date   string     `json:"date"`
+		CrewID *uuid.UUID `json:"crew_id,omitempty"`
+		Reason *string    `json:"reason,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		http.Error(w, "Invalid date format, use YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	blackout, err := h.bookingService.CreateBlackout(r.Context(), &services.BookingBlackoutRequest{
+		Date:   date,
+		CrewID: req.CrewID,
+		Reason: req.Reason,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create blackout day: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, blackout)
+}
+
+func (h *BookingHandler) DeleteBlackout(w http.ResponseWriter, r *http.Request) {
+	blackoutID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid blackout ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.bookingService.DeleteBlackout(r.Context(), blackoutID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete blackout day: %v", err), bookingErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// allowEmbed adds CORS headers when the request comes from a site the tenant allows
+// to embed booking. Requests without an Origin header are same-site and always pass.
+func (h *BookingHandler) allowEmbed(w http.ResponseWriter, r *http.Request, tenantID uuid.UUID) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if tenantID == uuid.Nil {
+		return false
+	}
+
+	allowed, err := h.bookingService.IsEmbedOriginAllowed(r.Context(), tenantID, origin)
+	if err != nil || !allowed {
+		return false
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Add("Vary", "Origin")
+	return true
+}
+
+func bookingErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "no longer available"), strings.Contains(message, "expired"), strings.Contains(message, "already been confirmed"):
+		return http.StatusConflict
+	case strings.Contains(message, "not available"):
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// CertificationHandler handles user certifications and the certifications
+// services require
+type CertificationHandler struct {
+	certificationService services.CertificationService
+}
+
+// NewCertificationHandler creates a new certification handler
+func NewCertificationHandler(certificationService services.CertificationService) *CertificationHandler {
+	return &CertificationHandler{
+		certificationService: certificationService,
+	}
+}
+
+// SetupCertificationRoutes sets up the certification routes
+func (h *CertificationHandler) SetupCertificationRoutes(router *mux.Router) {
+	certifications := router.PathPrefix("/certifications").Subrouter()
+	certifications.HandleFunc("/expiring", h.ListExpiringCertifications).Methods("GET")
+	certifications.HandleFunc("/users/{id}", h.ListUserCertifications).Methods("GET")
+	certifications.HandleFunc("/users/{id}", h.AddCertification).Methods("POST")
+	certifications.HandleFunc("/services/{id}/requirements", h.GetServiceRequirements).Methods("GET")
+	certifications.HandleFunc("/services/{id}/requirements", h.SetServiceRequirements).Methods("PUT")
+	certifications.HandleFunc("/{id}", h.UpdateCertification).Methods("PUT")
+	certifications.HandleFunc("/{id}", h.DeleteCertification).Methods("DELETE")
+	certifications.HandleFunc("/{id}/document", h.UploadCertificationDocument).Methods("POST")
+	certifications.HandleFunc("/{id}/document", h.GetCertificationDocument).Methods("GET")
+}
+
+func (h *CertificationHandler) ListUserCertifications(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	certifications, err := h.certificationService.ListUserCertifications(r.Context(), userID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list certifications: %v", err), certificationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, certifications)
+}
+
+func (h *CertificationHandler) AddCertification(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.CertificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	certification, err := h.certificationService.AddCertification(r.Context(), userID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to add certification: %v", err), certificationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, certification)
+}
+
+func (h *CertificationHandler) UpdateCertification(w http.ResponseWriter, r *http.Request) {
+	certificationID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid certification ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.CertificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	certification, err := h.certificationService.UpdateCertification(r.Context(), certificationID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update certification: %v", err), certificationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, certification)
+}
+
+func (h *CertificationHandler) DeleteCertification(w http.ResponseWriter, r *http.Request) {
+	certificationID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid certification ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.certificationService.DeleteCertification(r.Context(), certificationID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete certification: %v", err), certificationErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListExpiringCertifications lists certifications expired or expiring within
+// ?days=, 30 by default
+func (h *CertificationHandler) ListExpiringCertifications(w http.ResponseWriter, r *http.Request) {
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+
+	certifications, err := h.certificationService.ListExpiringCertifications(r.Context(), days)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list expiring certifications: %v", err), certificationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, certifications)
+}
+
+// UploadCertificationDocument stores the multipart "document" file as the
+// certification's document
+func (h *CertificationHandler) UploadCertificationDocument(w http.ResponseWriter, r *http.Request) {
+	certificationID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid certification ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10MB limit
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("document")
+	if err != nil {
+		http.Error(w, "Document file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "Failed to read document file", http.StatusInternalServerError)
+		return
+	}
+
+	certification, err := h.certificationService.UploadCertificationDocument(r.Context(), certificationID, &services.CertificationDocumentUpload{
+		Filename:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Data:        data,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to upload certification document: %v", err), certificationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, certification)
+}
+
+// GetCertificationDocument returns a short-lived link to the certification's document
+func (h *CertificationHandler) GetCertificationDocument(w http.ResponseWriter, r *http.Request) {
+	certificationID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid certification ID", http.StatusBadRequest)
+		return
+	}
+
+	url, err := h.certificationService.GetCertificationDocumentURL(r.Context(), certificationID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get certification document: %v", err), certificationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"url": url})
+}
+
+func (h *CertificationHandler) GetServiceRequirements(w http.ResponseWriter, r *http.Request) {
+	serviceID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid service ID", http.StatusBadRequest)
+		return
+	}
+
+	requirements, err := h.certificationService.GetServiceRequirements(r.Context(), serviceID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get service requirements: %v", err), certificationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, requirements)
+}
+
+// SetServiceRequirements replaces the certifications the service requires
+func (h *CertificationHandler) SetServiceRequirements(w http.ResponseWriter, r *http.Request) {
+	serviceID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid service ID", http.StatusBadRequest)
+		return
+	}
+
+	var req []services.ServiceRequirementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	requirements, err := h.certificationService.SetServiceRequirements(r.Context(), serviceID, req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to set service requirements: %v", err), certificationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, requirements)
+}
+
+func certificationErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
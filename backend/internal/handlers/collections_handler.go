@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// CollectionsHandler handles accounts receivable and collections operations
+type CollectionsHandler struct {
+	collectionsService services.CollectionsService
+}
+
+// NewCollectionsHandler creates a new collections handler
+func NewCollectionsHandler(collectionsService services.CollectionsService) *CollectionsHandler {
+	return &CollectionsHandler{
+		collectionsService: collectionsService,
+	}
+}
+
+// SetupCollectionsRoutes sets up collections routes
+func (h *CollectionsHandler) SetupCollectionsRoutes(router *mux.Router) {
+	collections := router.PathPrefix("/collections").Subrouter()
+
+	// Reporting
+	collections.HandleFunc("/aging-report", h.GetARAgingReport).Methods("GET")
+
+	// Policy
+	collections.HandleFunc("/policy", h.GetCollectionPolicy).Methods("GET")
+	collections.HandleFunc("/policy", h.UpdateCollectionPolicy).Methods("PUT")
+
+	// Manual runs of the automated steps
+	collections.HandleFunc("/reminders/send", h.SendDueReminders).Methods("POST")
+	collections.HandleFunc("/late-fees/apply", h.ApplyLateFees).Methods("POST")
+	collections.HandleFunc("/late-fees/{id}/waive", h.WaiveLateFee).Methods("POST")
+	collections.HandleFunc("/suspensions/enforce", h.EnforceSchedulingSuspensions).Methods("POST")
+
+	// Notes and promises to pay
+	collections.HandleFunc("/notes", h.AddCollectionNote).Methods("POST")
+	collections.HandleFunc("/promises", h.ListPaymentPromises).Methods("GET")
+	collections.HandleFunc("/promises", h.CreatePaymentPromise).Methods("POST")
+	collections.HandleFunc("/promises/{id}/status", h.UpdatePaymentPromiseStatus).Methods("PUT")
+
+	// Customer accounts
+	customers := collections.PathPrefix("/customers").Subrouter()
+	customers.HandleFunc("/{id}/notes", h.ListCollectionNotes).Methods("GET")
+	customers.HandleFunc("/{id}/suspend", h.SuspendScheduling).Methods("POST")
+	customers.HandleFunc("/{id}/resume", h.ResumeScheduling).Methods("POST")
+}
+
+// Reporting
+
+func (h *CollectionsHandler) GetARAgingReport(w http.ResponseWriter, r *http.Request) {
+	asOf, err := parseAsOfParam(r)
+	if err != nil {
+		http.Error(w, "Invalid as_of date", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.collectionsService.GetARAgingReport(r.Context(), asOf)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get aging report: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+// Policy
+
+func (h *CollectionsHandler) GetCollectionPolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := h.collectionsService.GetCollectionPolicy(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get collection policy: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, policy)
+}
+
+func (h *CollectionsHandler) UpdateCollectionPolicy(w http.ResponseWriter, r *http.Request) {
+	var req services.CollectionPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	policy, err := h.collectionsService.UpdateCollectionPolicy(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update collection policy: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, policy)
+}
+
+// Automated steps
+
+func (h *CollectionsHandler) SendDueReminders(w http.ResponseWriter, r *http.Request) {
+	asOf, err := parseAsOfParam(r)
+	if err != nil {
+		http.Error(w, "Invalid as_of date", http.StatusBadRequest)
+		return
+	}
+
+	sent, err := h.collectionsService.SendDueReminders(r.Context(), asOf)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to send reminders: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]int{"reminders_sent": sent})
+}
+
+func (h *CollectionsHandler) ApplyLateFees(w http.ResponseWriter, r *http.Request) {
+	asOf, err := parseAsOfParam(r)
+	if err != nil {
+		http.Error(w, "Invalid as_of date", http.StatusBadRequest)
+		return
+	}
+
+	fees, err := h.collectionsService.ApplyLateFees(r.Context(), asOf)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to apply late fees: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, fees)
+}
+
+func (h *CollectionsHandler) WaiveLateFee(w http.ResponseWriter, r *http.Request) {
+	feeID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid late fee ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.collectionsService.WaiveLateFee(r.Context(), feeID, req.Reason); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to waive late fee: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Late fee waived"})
+}
+
+func (h *CollectionsHandler) EnforceSchedulingSuspensions(w http.ResponseWriter, r *http.Request) {
+	asOf, err := parseAsOfParam(r)
+	if err != nil {
+		http.Error(w, "Invalid as_of date", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.collectionsService.EnforceSchedulingSuspensions(r.Context(), asOf)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to enforce suspensions: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// Notes and promises
+
+func (h *CollectionsHandler) AddCollectionNote(w http.ResponseWriter, r *http.Request) {
+	var req services.CollectionNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	note, err := h.collectionsService.AddCollectionNote(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to add collection note: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, note)
+}
+
+func (h *CollectionsHandler) ListCollectionNotes(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	notes, err := h.collectionsService.ListCollectionNotes(r.Context(), customerID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list collection notes: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, notes)
+}
+
+func (h *CollectionsHandler) ListPaymentPromises(w http.ResponseWriter, r *http.Request) {
+	filter := &services.PaymentPromiseFilter{
+		Status: r.URL.Query().Get("status"),
+	}
+	if customerIDStr := r.URL.Query().Get("customer_id"); customerIDStr != "" {
+		customerID, err := uuid.Parse(customerIDStr)
+		if err != nil {
+			http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+			return
+		}
+		filter.CustomerID = &customerID
+	}
+
+	promises, err := h.collectionsService.ListPaymentPromises(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list payment promises: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, promises)
+}
+
+func (h *CollectionsHandler) CreatePaymentPromise(w http.ResponseWriter, r *http.Request) {
+	var req services.PaymentPromiseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	promise, err := h.collectionsService.CreatePaymentPromise(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create payment promise: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, promise)
+}
+
+func (h *CollectionsHandler) UpdatePaymentPromiseStatus(w http.ResponseWriter, r *http.Request) {
+	promiseID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid payment promise ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	promise, err := h.collectionsService.UpdatePaymentPromiseStatus(r.Context(), promiseID, req.Status)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update payment promise: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, promise)
+}
+
+// Customer accounts
+
+func (h *CollectionsHandler) SuspendScheduling(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.collectionsService.SuspendScheduling(r.Context(), customerID, req.Reason); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to suspend scheduling: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Scheduling suspended"})
+}
+
+func (h *CollectionsHandler) ResumeScheduling(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.collectionsService.ResumeScheduling(r.Context(), customerID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to resume scheduling: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Scheduling resumed"})
+}
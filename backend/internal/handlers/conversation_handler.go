@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// ConversationHandler handles customer timelines, message threads and the comms
+// provider's inbound webhook
+type ConversationHandler struct {
+	conversationService services.ConversationService
+}
+
+// NewConversationHandler creates a new conversation handler
+func NewConversationHandler(conversationService services.ConversationService) *ConversationHandler {
+	return &ConversationHandler{
+		conversationService: conversationService,
+	}
+}
+
+// SetupConversationRoutes sets up the public webhook route and the staff timeline
+// and thread routes
+func (h *ConversationHandler) SetupConversationRoutes(public, protected *mux.Router) {
+	public.HandleFunc("/public/communications/{tenant_id}/webhook", h.ReceiveWebhook).Methods("POST")
+
+	communications := protected.PathPrefix("/communications").Subrouter()
+	communications.HandleFunc("/customers/{id}/timeline", h.GetCustomerTimeline).Methods("GET")
+	communications.HandleFunc("/customers/{id}/notes", h.AddCustomerNote).Methods("POST")
+	communications.HandleFunc("/messages", h.SendMessage).Methods("POST")
+	communications.HandleFunc("/threads", h.ListThreads).Methods("GET")
+	communications.HandleFunc("/threads/{id}", h.GetThread).Methods("GET")
+	communications.HandleFunc("/threads/{id}", h.UpdateThread).Methods("PUT")
+	communications.HandleFunc("/threads/{id}/read", h.MarkThreadRead).Methods("POST")
+}
+
+// ReceiveWebhook accepts inbound messages and delivery updates from the comms provider
+func (h *ConversationHandler) ReceiveWebhook(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := uuid.Parse(mux.Vars(r)["tenant_id"])
+	if err != nil {
+		http.Error(w, "Invalid tenant ID", http.StatusBadRequest)
+		return
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(r.Body, 1<<20)) // 1MB limit
+	if err != nil {
+		http.Error(w, "Failed to read webhook", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.conversationService.ReceiveWebhook(r.Context(), tenantID, r.Header, payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to process webhook: %v", err), webhookErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+func (h *ConversationHandler) GetCustomerTimeline(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := &services.TimelineFilter{}
+	for _, eventType := range strings.Split(query.Get("types"), ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			filter.Types = append(filter.Types, eventType)
+		}
+	}
+	if before := query.Get("before"); before != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, before)
+		if err != nil {
+			http.Error(w, "Invalid before time", http.StatusBadRequest)
+			return
+		}
+		filter.Before = &parsed
+	}
+	filter.Limit, _ = strconv.Atoi(query.Get("limit"))
+
+	timeline, err := h.conversationService.GetCustomerTimeline(r.Context(), customerID, filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get timeline: %v", err), conversationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, timeline)
+}
+
+func (h *ConversationHandler) AddCustomerNote(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.CustomerNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	note, err := h.conversationService.AddCustomerNote(r.Context(), customerID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to add note: %v", err), conversationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, note)
+}
+
+func (h *ConversationHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
+	var req services.SendMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	message, err := h.conversationService.SendMessage(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to send message: %v", err), conversationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, message)
+}
+
+func (h *ConversationHandler) ListThreads(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &services.ThreadFilter{
+		Status:     query.Get("status"),
+		Channel:    query.Get("channel"),
+		CustomerID: parseOptionalUUID(query.Get("customer_id")),
+		UnreadOnly: query.Get("unread") == "true",
+	}
+	filter.Search = query.Get("search")
+	filter.Page, _ = strconv.Atoi(query.Get("page"))
+	filter.PerPage, _ = strconv.Atoi(query.Get("per_page"))
+
+	threads, err := h.conversationService.ListThreads(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list threads: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, threads)
+}
+
+func (h *ConversationHandler) GetThread(w http.ResponseWriter, r *http.Request) {
+	threadID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid thread ID", http.StatusBadRequest)
+		return
+	}
+
+	thread, err := h.conversationService.GetThread(r.Context(), threadID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get thread: %v", err), conversationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, thread)
+}
+
+func (h *ConversationHandler) UpdateThread(w http.ResponseWriter, r *http.Request) {
+	threadID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid thread ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.ThreadUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	thread, err := h.conversationService.UpdateThread(r.Context(), threadID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update thread: %v", err), conversationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, thread)
+}
+
+func (h *ConversationHandler) MarkThreadRead(w http.ResponseWriter, r *http.Request) {
+	threadID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid thread ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.conversationService.MarkThreadRead(r.Context(), threadID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to mark thread read: %v", err), conversationErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func conversationErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	case strings.Contains(message, "not configured"):
+		return http.StatusServiceUnavailable
+	case strings.HasPrefix(message, "failed to send"):
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
+
+// webhookErrorStatus maps webhook errors to status codes. Providers retry on 5xx, so
+// payloads that will never be accepted get a 4xx.
+func webhookErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "invalid webhook signature"):
+		return http.StatusUnauthorized
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	case strings.Contains(message, "not configured"):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// CustomerMergeHandler handles duplicate customer detection and merges
+type CustomerMergeHandler struct {
+	mergeService services.CustomerMergeService
+}
+
+// NewCustomerMergeHandler creates a new customer merge handler
+func NewCustomerMergeHandler(mergeService services.CustomerMergeService) *CustomerMergeHandler {
+	return &CustomerMergeHandler{
+		mergeService: mergeService,
+	}
+}
+
+// SetupCustomerMergeRoutes sets up the duplicate and merge routes
+func (h *CustomerMergeHandler) SetupCustomerMergeRoutes(router *mux.Router) {
+	duplicates := router.PathPrefix("/customer-duplicates").Subrouter()
+	duplicates.HandleFunc("", h.FindDuplicates).Methods("GET")
+	duplicates.HandleFunc("/dismiss", h.DismissDuplicate).Methods("POST")
+
+	merges := router.PathPrefix("/customer-merges").Subrouter()
+	merges.HandleFunc("", h.ListMerges).Methods("GET")
+	merges.HandleFunc("", h.MergeCustomers).Methods("POST")
+	merges.HandleFunc("/preview", h.PreviewMerge).Methods("POST")
+	merges.HandleFunc("/{id}", h.GetMerge).Methods("GET")
+	merges.HandleFunc("/{id}/undo", h.UndoMerge).Methods("POST")
+}
+
+func (h *CustomerMergeHandler) FindDuplicates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &services.CustomerDuplicateFilter{
+		CustomerID: parseOptionalUUID(query.Get("customer_id")),
+	}
+	filter.MinScore, _ = strconv.ParseFloat(query.Get("min_score"), 64)
+	filter.Limit, _ = strconv.Atoi(query.Get("limit"))
+
+	duplicates, err := h.mergeService.FindDuplicates(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to find duplicates: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, duplicates)
+}
+
+func (h *CustomerMergeHandler) DismissDuplicate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		CustomerAID uuid.UUID `json:"customer_a_id"`
+		CustomerBID uuid.UUID `json:"customer_b_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.mergeService.DismissDuplicate(r.Context(), req.CustomerAID, req.CustomerBID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to dismiss duplicate: %v", err), customerMergeErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CustomerMergeHandler) PreviewMerge(w http.ResponseWriter, r *http.Request) {
+	var req services.CustomerMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	preview, err := h.mergeService.PreviewMerge(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to preview merge: %v", err), customerMergeErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, preview)
+}
+
+func (h *CustomerMergeHandler) MergeCustomers(w http.ResponseWriter, r *http.Request) {
+	var req services.CustomerMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	merge, err := h.mergeService.MergeCustomers(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to merge customers: %v", err), customerMergeErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, merge)
+}
+
+func (h *CustomerMergeHandler) ListMerges(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &services.CustomerMergeFilter{
+		CustomerID: parseOptionalUUID(query.Get("customer_id")),
+		Status:     query.Get("status"),
+	}
+	filter.Page, _ = strconv.Atoi(query.Get("page"))
+	filter.PerPage, _ = strconv.Atoi(query.Get("per_page"))
+
+	merges, err := h.mergeService.ListMerges(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list merges: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, merges)
+}
+
+func (h *CustomerMergeHandler) GetMerge(w http.ResponseWriter, r *http.Request) {
+	mergeID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid merge ID", http.StatusBadRequest)
+		return
+	}
+
+	merge, err := h.mergeService.GetMerge(r.Context(), mergeID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get merge: %v", err), customerMergeErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, merge)
+}
+
+func (h *CustomerMergeHandler) UndoMerge(w http.ResponseWriter, r *http.Request) {
+	mergeID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid merge ID", http.StatusBadRequest)
+		return
+	}
+
+	merge, err := h.mergeService.UndoMerge(r.Context(), mergeID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to undo merge: %v", err), customerMergeErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, merge)
+}
+
+func customerMergeErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	case strings.HasPrefix(message, "failed to"):
+		return http.StatusInternalServerError
+	}
+	return http.StatusConflict
+}
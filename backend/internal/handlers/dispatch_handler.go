@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// DispatchHandler handles the dispatch board, daily crew rosters and
+// drag-and-drop moves between crews
+type DispatchHandler struct {
+	dispatchService services.DispatchService
+}
+
+// NewDispatchHandler creates a new dispatch handler
+func NewDispatchHandler(dispatchService services.DispatchService) *DispatchHandler {
+	return &DispatchHandler{
+		dispatchService: dispatchService,
+	}
+}
+
+// SetupDispatchRoutes sets up the dispatch routes
+func (h *DispatchHandler) SetupDispatchRoutes(router *mux.Router) {
+	dispatch := router.PathPrefix("/dispatch").Subrouter()
+	dispatch.HandleFunc("/board", h.GetBoard).Methods("GET")
+	dispatch.HandleFunc("/crews/{id}/roster", h.SetRoster).Methods("PUT")
+	dispatch.HandleFunc("/crews/{id}/roster", h.ClearRoster).Methods("DELETE")
+	dispatch.HandleFunc("/moves/member", h.MoveMember).Methods("POST")
+	dispatch.HandleFunc("/moves/job", h.MoveJob).Methods("POST")
+	dispatch.HandleFunc("/moves/equipment", h.MoveEquipment).Methods("POST")
+}
+
+// GetBoard returns the dispatch board for ?date=, today by default
+func (h *DispatchHandler) GetBoard(w http.ResponseWriter, r *http.Request) {
+	date, ok := parseDispatchDate(w, r.URL.Query().Get("date"))
+	if !ok {
+		return
+	}
+
+	board, err := h.dispatchService.GetBoard(r.Context(), date)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get dispatch board: %v", err), dispatchErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, board)
+}
+
+// SetRoster replaces the crew's roster for ?date=
+func (h *DispatchHandler) SetRoster(w http.ResponseWriter, r *http.Request) {
+	crewID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid crew ID", http.StatusBadRequest)
+		return
+	}
+
+	date, ok := parseDispatchDate(w, r.URL.Query().Get("date"))
+	if !ok {
+		return
+	}
+
+	var req services.CrewRosterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	change, err := h.dispatchService.SetRoster(r.Context(), crewID, date, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to set crew roster: %v", err), dispatchErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, change)
+}
+
+// ClearRoster returns the crew to its standing membership for ?date=
+func (h *DispatchHandler) ClearRoster(w http.ResponseWriter, r *http.Request) {
+	crewID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid crew ID", http.StatusBadRequest)
+		return
+	}
+
+	date, ok := parseDispatchDate(w, r.URL.Query().Get("date"))
+	if !ok {
+		return
+	}
+
+	change, err := h.dispatchService.ClearRoster(r.Context(), crewID, date)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to clear crew roster: %v", err), dispatchErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, change)
+}
+
+func (h *DispatchHandler) MoveMember(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Date       string     `json:"date"`
+		UserID     uuid.UUID  `json:"user_id"`
+		FromCrewID *uuid.UUID `json:"from_crew_id,omitempty"`
+		ToCrewID   *uuid.UUID `json:"to_crew_id,omitempty"`
+		Role       string     `json:"role,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	date, ok := parseDispatchDate(w, req.Date)
+	if !ok {
+		return
+	}
+
+	change, err := h.dispatchService.MoveMember(r.Context(), &services.DispatchMemberMove{
+		Date:       date,
+		UserID:     req.UserID,
+		FromCrewID: req.FromCrewID,
+		ToCrewID:   req.ToCrewID,
+		Role:       req.Role,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to move crew member: %v", err), dispatchErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, change)
+}
+
+func (h *DispatchHandler) MoveJob(w http.ResponseWriter, r *http.Request) {
+	var req services.DispatchJobMove
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	change, err := h.dispatchService.MoveJob(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to move job: %v", err), dispatchErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, change)
+}
+
+func (h *DispatchHandler) MoveEquipment(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Date        string     `json:"date"`
+		EquipmentID uuid.UUID  `json:"equipment_id"`
+		FromCrewID  *uuid.UUID `json:"from_crew_id,omitempty"`
+		ToCrewID    *uuid.UUID `json:"to_crew_id,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	date, ok := parseDispatchDate(w, req.Date)
+	if !ok {
+		return
+	}
+
+	change, err := h.dispatchService.MoveEquipment(r.Context(), &services.DispatchEquipmentMove{
+		Date:        date,
+		EquipmentID: req.EquipmentID,
+		FromCrewID:  req.FromCrewID,
+		ToCrewID:    req.ToCrewID,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to move equipment: %v", err), dispatchErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, change)
+}
+
+// parseDispatchDate reads a YYYY-MM-DD date, today when empty
+func parseDispatchDate(w http.ResponseWriter, value string) (time.Time, bool) {
+	if value == "" {
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), true
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		http.Error(w, "Invalid date format, use YYYY-MM-DD", http.StatusBadRequest)
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+func dispatchErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	case strings.Contains(message, "cannot be"), strings.Contains(message, "already exists"):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// EquipmentAssetHandler handles equipment depreciation, cost of ownership,
+// replacement analysis and the fixed asset register
+type EquipmentAssetHandler struct {
+	equipmentService services.EquipmentService
+}
+
+// NewEquipmentAssetHandler creates a new equipment asset handler
+func NewEquipmentAssetHandler(equipmentService services.EquipmentService) *EquipmentAssetHandler {
+	return &EquipmentAssetHandler{
+		equipmentService: equipmentService,
+	}
+}
+
+// SetupEquipmentAssetRoutes sets up the equipment asset routes
+func (h *EquipmentAssetHandler) SetupEquipmentAssetRoutes(router *mux.Router) {
+	equipment := router.PathPrefix("/equipment").Subrouter()
+	equipment.HandleFunc("/fixed-assets/register", h.GetFixedAssetRegister).Methods("GET")
+	equipment.HandleFunc("/fixed-assets/register/export", h.ExportFixedAssetRegister).Methods("GET")
+	equipment.HandleFunc("/{id}/asset-profile", h.GetAssetProfile).Methods("GET")
+	equipment.HandleFunc("/{id}/asset-profile", h.SetAssetProfile).Methods("PUT")
+	equipment.HandleFunc("/{id}/dispose", h.DisposeEquipment).Methods("POST")
+	equipment.HandleFunc("/{id}/depreciation", h.GetDepreciationSchedule).Methods("GET")
+	equipment.HandleFunc("/{id}/total-cost", h.GetTotalCostOfOwnership).Methods("GET")
+	equipment.HandleFunc("/{id}/replace-or-repair", h.AnalyzeReplaceOrRepair).Methods("GET")
+}
+
+func (h *EquipmentAssetHandler) GetAssetProfile(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.equipmentService.GetAssetProfile(r.Context(), equipmentID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get asset profile: %v", err), equipmentAssetErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, profile)
+}
+
+func (h *EquipmentAssetHandler) SetAssetProfile(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.EquipmentAssetProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.equipmentService.SetAssetProfile(r.Context(), equipmentID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to set asset profile: %v", err), equipmentAssetErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, profile)
+}
+
+func (h *EquipmentAssetHandler) DisposeEquipment(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.EquipmentDisposalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.equipmentService.DisposeEquipment(r.Context(), equipmentID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to dispose of equipment: %v", err), equipmentAssetErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, profile)
+}
+
+func (h *EquipmentAssetHandler) GetDepreciationSchedule(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	schedule, err := h.equipmentService.GetDepreciationSchedule(r.Context(), equipmentID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get depreciation schedule: %v", err), equipmentAssetErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, schedule)
+}
+
+// GetTotalCostOfOwnership totals the equipment's costs between the optional
+// ?start_date= and ?end_date=, over its whole life by default
+func (h *EquipmentAssetHandler) GetTotalCostOfOwnership(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	var startDate, endDate time.Time
+	query := r.URL.Query()
+	if value := query.Get("start_date"); value != "" {
+		if startDate, err = time.Parse("2006-01-02", value); err != nil {
+			http.Error(w, "Invalid start_date", http.StatusBadRequest)
+			return
+		}
+	}
+	if value := query.Get("end_date"); value != "" {
+		if endDate, err = time.Parse("2006-01-02", value); err != nil {
+			http.Error(w, "Invalid end_date", http.StatusBadRequest)
+			return
+		}
+	}
+
+	tco, err := h.equipmentService.GetTotalCostOfOwnership(r.Context(), equipmentID, startDate, endDate)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get total cost of ownership: %v", err), equipmentAssetErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tco)
+}
+
+// AnalyzeReplaceOrRepair recommends replacing or repairing the equipment, given
+// an optional ?repair_estimate= for a pending repair
+func (h *EquipmentAssetHandler) AnalyzeReplaceOrRepair(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	req := &services.ReplaceOrRepairRequest{}
+	if value := r.URL.Query().Get("repair_estimate"); value != "" {
+		if req.RepairEstimate, err = strconv.ParseFloat(value, 64); err != nil {
+			http.Error(w, "Invalid repair_estimate", http.StatusBadRequest)
+			return
+		}
+	}
+
+	analysis, err := h.equipmentService.AnalyzeReplaceOrRepair(r.Context(), equipmentID, req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to analyze replace or repair: %v", err), equipmentAssetErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, analysis)
+}
+
+// GetFixedAssetRegister returns the fixed asset register for ?year=, this year by default
+func (h *EquipmentAssetHandler) GetFixedAssetRegister(w http.ResponseWriter, r *http.Request) {
+	year, _ := strconv.Atoi(r.URL.Query().Get("year"))
+
+	register, err := h.equipmentService.GetFixedAssetRegister(r.Context(), year)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get fixed asset register: %v", err), equipmentAssetErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, register)
+}
+
+// ExportFixedAssetRegister downloads the fixed asset register for ?year= as CSV
+func (h *EquipmentAssetHandler) ExportFixedAssetRegister(w http.ResponseWriter, r *http.Request) {
+	year, _ := strconv.Atoi(r.URL.Query().Get("year"))
+
+	export, err := h.equipmentService.ExportFixedAssetRegister(r.Context(), year)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to export fixed asset register: %v", err), equipmentAssetErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", export.Filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(export.Data)
+}
+
+func equipmentAssetErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "already disposed"):
+		return http.StatusConflict
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// EquipmentCheckoutHandler handles asset tags, label sheets and equipment
+// checkout and check-in
+type EquipmentCheckoutHandler struct {
+	equipmentService services.EquipmentService
+}
+
+// NewEquipmentCheckoutHandler creates a new equipment checkout handler
+func NewEquipmentCheckoutHandler(equipmentService services.EquipmentService) *EquipmentCheckoutHandler {
+	return &EquipmentCheckoutHandler{
+		equipmentService: equipmentService,
+	}
+}
+
+// SetupEquipmentCheckoutRoutes sets up the equipment checkout routes
+func (h *EquipmentCheckoutHandler) SetupEquipmentCheckoutRoutes(router *mux.Router) {
+	equipment := router.PathPrefix("/equipment").Subrouter()
+	equipment.HandleFunc("/whereabouts", h.GetEquipmentWhereabouts).Methods("GET")
+	equipment.HandleFunc("/labels", h.GenerateAssetLabels).Methods("POST")
+	equipment.HandleFunc("/scan/{code}", h.ScanAssetTag).Methods("GET")
+	equipment.HandleFunc("/checkout", h.CheckOutEquipment).Methods("POST")
+	equipment.HandleFunc("/checkin", h.CheckInEquipment).Methods("POST")
+	equipment.HandleFunc("/{id}/asset-tag", h.GetAssetTag).Methods("GET")
+	equipment.HandleFunc("/{id}/checkouts", h.GetCheckoutHistory).Methods("GET")
+}
+
+func (h *EquipmentCheckoutHandler) GetAssetTag(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	tag, err := h.equipmentService.GetAssetTag(r.Context(), equipmentID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get asset tag: %v", err), equipmentCheckoutErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tag)
+}
+
+// GenerateAssetLabels downloads a PDF sheet of QR labels for the equipment IDs
+// in the request body
+func (h *EquipmentCheckoutHandler) GenerateAssetLabels(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		EquipmentIDs []uuid.UUID `json:"equipment_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	sheet, err := h.equipmentService.GenerateAssetLabels(r.Context(), req.EquipmentIDs)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to generate asset labels: %v", err), equipmentCheckoutErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", sheet.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", sheet.Filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(sheet.Data)
+}
+
+func (h *EquipmentCheckoutHandler) ScanAssetTag(w http.ResponseWriter, r *http.Request) {
+	whereabouts, err := h.equipmentService.ScanAssetTag(r.Context(), mux.Vars(r)["code"])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to scan asset tag: %v", err), equipmentCheckoutErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, whereabouts)
+}
+
+func (h *EquipmentCheckoutHandler) CheckOutEquipment(w http.ResponseWriter, r *http.Request) {
+	var req services.EquipmentCheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	checkout, err := h.equipmentService.CheckOutEquipment(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to check out equipment: %v", err), equipmentCheckoutErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, checkout)
+}
+
+func (h *EquipmentCheckoutHandler) CheckInEquipment(w http.ResponseWriter, r *http.Request) {
+	var req services.EquipmentCheckinRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	checkout, err := h.equipmentService.CheckInEquipment(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to check in equipment: %v", err), equipmentCheckoutErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, checkout)
+}
+
+// GetEquipmentWhereabouts lists the equipment out of the shop, optionally only
+// that held by ?assigned_user_id= or ?assigned_crew_id=, or ?overdue=true
+func (h *EquipmentCheckoutHandler) GetEquipmentWhereabouts(w http.ResponseWriter, r *http.Request) {
+	filter := &services.EquipmentWhereaboutsFilter{}
+	query := r.URL.Query()
+	if value := query.Get("assigned_user_id"); value != "" {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			http.Error(w, "Invalid assigned_user_id", http.StatusBadRequest)
+			return
+		}
+		filter.AssignedUserID = &userID
+	}
+	if value := query.Get("assigned_crew_id"); value != "" {
+		crewID, err := uuid.Parse(value)
+		if err != nil {
+			http.Error(w, "Invalid assigned_crew_id", http.StatusBadRequest)
+			return
+		}
+		filter.AssignedCrewID = &crewID
+	}
+	filter.OverdueOnly, _ = strconv.ParseBool(query.Get("overdue"))
+
+	whereabouts, err := h.equipmentService.GetEquipmentWhereabouts(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get equipment whereabouts: %v", err), equipmentCheckoutErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, whereabouts)
+}
+
+// GetCheckoutHistory lists the equipment's most recent checkouts, up to ?limit=
+func (h *EquipmentCheckoutHandler) GetCheckoutHistory(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	checkouts, err := h.equipmentService.GetCheckoutHistory(r.Context(), equipmentID, limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get checkout history: %v", err), equipmentCheckoutErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, checkouts)
+}
+
+func equipmentCheckoutErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "already checked out"),
+		strings.Contains(message, "not checked out"),
+		strings.Contains(message, "cannot be checked out"):
+		return http.StatusConflict
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
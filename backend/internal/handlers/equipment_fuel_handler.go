@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// EquipmentFuelHandler handles fuel entries and fuel efficiency reporting
+type EquipmentFuelHandler struct {
+	equipmentService services.EquipmentService
+}
+
+// NewEquipmentFuelHandler creates a new equipment fuel handler
+func NewEquipmentFuelHandler(equipmentService services.EquipmentService) *EquipmentFuelHandler {
+	return &EquipmentFuelHandler{
+		equipmentService: equipmentService,
+	}
+}
+
+// SetupEquipmentFuelRoutes sets up the equipment fuel routes
+func (h *EquipmentFuelHandler) SetupEquipmentFuelRoutes(router *mux.Router) {
+	equipment := router.PathPrefix("/equipment").Subrouter()
+	equipment.HandleFunc("/fuel/anomalies", h.ListFuelAnomalies).Methods("GET")
+	equipment.HandleFunc("/{id}/fuel", h.RecordFuelEntry).Methods("POST")
+	equipment.HandleFunc("/{id}/fuel", h.ListFuelEntries).Methods("GET")
+	equipment.HandleFunc("/{id}/fuel/efficiency", h.GetFuelEfficiencyReport).Methods("GET")
+}
+
+func (h *EquipmentFuelHandler) RecordFuelEntry(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.FuelEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.equipmentService.RecordFuelEntry(r.Context(), equipmentID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to record fuel entry: %v", err), equipmentFuelErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, entry)
+}
+
+// ListFuelEntries lists the equipment's fuel entries between the optional
+// ?start_date= and ?end_date=
+func (h *EquipmentFuelHandler) ListFuelEntries(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	startDate, endDate, ok := parseFuelDateRange(w, r)
+	if !ok {
+		return
+	}
+
+	entries, err := h.equipmentService.ListFuelEntries(r.Context(), equipmentID, startDate, endDate)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list fuel entries: %v", err), equipmentFuelErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, entries)
+}
+
+// ListFuelAnomalies lists the flagged fuel entries between the optional
+// ?start_date= and ?end_date=
+func (h *EquipmentFuelHandler) ListFuelAnomalies(w http.ResponseWriter, r *http.Request) {
+	startDate, endDate, ok := parseFuelDateRange(w, r)
+	if !ok {
+		return
+	}
+
+	entries, err := h.equipmentService.ListFuelAnomalies(r.Context(), startDate, endDate)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list fuel anomalies: %v", err), equipmentFuelErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, entries)
+}
+
+// GetFuelEfficiencyReport trends the equipment's fuel efficiency between the
+// optional ?start_date= and ?end_date=, over the last year by default
+func (h *EquipmentFuelHandler) GetFuelEfficiencyReport(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	startDate, endDate, ok := parseFuelDateRange(w, r)
+	if !ok {
+		return
+	}
+
+	var start, end time.Time
+	if startDate != nil {
+		start = *startDate
+	}
+	if endDate != nil {
+		end = *endDate
+	}
+
+	report, err := h.equipmentService.GetFuelEfficiencyReport(r.Context(), equipmentID, start, end)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get fuel efficiency report: %v", err), equipmentFuelErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+func parseFuelDateRange(w http.ResponseWriter, r *http.Request) (*time.Time, *time.Time, bool) {
+	var startDate, endDate *time.Time
+	query := r.URL.Query()
+	if value := query.Get("start_date"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			http.Error(w, "Invalid start_date", http.StatusBadRequest)
+			return nil, nil, false
+		}
+		startDate = &date
+	}
+	if value := query.Get("end_date"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			http.Error(w, "Invalid end_date", http.StatusBadRequest)
+			return nil, nil, false
+		}
+		// Include the whole end day
+		date = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		endDate = &date
+	}
+	return startDate, endDate, true
+}
+
+func equipmentFuelErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
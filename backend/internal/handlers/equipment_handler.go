@@ -525,6 +525,20 @@ func (h *EquipmentHandler) parseEquipmentFilter(r *http.Request) (*services.Equi
 	filter.Status = r.URL.Query().Get("status")
 	filter.Type = r.URL.Query().Get("type")
 	filter.Search = r.URL.Query().Get("search")
+	if assignedUserID := r.URL.Query().Get("assigned_user_id"); assignedUserID != "" {
+		id, err := uuid.Parse(assignedUserID)
+		if err != nil {
+			return nil, err
+		}
+		filter.AssignedUserID = &id
+	}
+	if assignedCrewID := r.URL.Query().Get("assigned_crew_id"); assignedCrewID != "" {
+		id, err := uuid.Parse(assignedCrewID)
+		if err != nil {
+			return nil, err
+		}
+		filter.AssignedCrewID = &id
+	}
 
 	return filter, nil
 }
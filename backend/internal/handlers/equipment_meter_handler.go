@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// EquipmentMeterHandler handles equipment meter readings and usage-based maintenance
+type EquipmentMeterHandler struct {
+	equipmentService services.EquipmentService
+}
+
+// NewEquipmentMeterHandler creates a new equipment meter handler
+func NewEquipmentMeterHandler(equipmentService services.EquipmentService) *EquipmentMeterHandler {
+	return &EquipmentMeterHandler{
+		equipmentService: equipmentService,
+	}
+}
+
+// SetupEquipmentMeterRoutes sets up the equipment meter routes
+func (h *EquipmentMeterHandler) SetupEquipmentMeterRoutes(router *mux.Router) {
+	equipment := router.PathPrefix("/equipment").Subrouter()
+	equipment.HandleFunc("/maintenance/forecast", h.GetMaintenanceForecast).Methods("GET")
+	equipment.HandleFunc("/{id}/meter-readings", h.GetMeterReadings).Methods("GET")
+	equipment.HandleFunc("/{id}/meter-readings", h.RecordMeterReading).Methods("POST")
+	equipment.HandleFunc("/{id}/usage-triggers", h.GetUsageTriggers).Methods("GET")
+	equipment.HandleFunc("/{id}/usage-triggers", h.SetUsageTriggers).Methods("PUT")
+}
+
+func (h *EquipmentMeterHandler) GetMeterReadings(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	readings, err := h.equipmentService.GetMeterReadings(r.Context(), equipmentID, r.URL.Query().Get("meter"), limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get meter readings: %v", err), equipmentMeterErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, readings)
+}
+
+func (h *EquipmentMeterHandler) RecordMeterReading(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.MeterReadingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	reading, err := h.equipmentService.RecordMeterReading(r.Context(), equipmentID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to record meter reading: %v", err), equipmentMeterErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, reading)
+}
+
+func (h *EquipmentMeterHandler) GetUsageTriggers(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	triggers, err := h.equipmentService.GetUsageTriggers(r.Context(), equipmentID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get usage triggers: %v", err), equipmentMeterErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, triggers)
+}
+
+// SetUsageTriggers replaces the equipment's usage-based maintenance triggers
+func (h *EquipmentMeterHandler) SetUsageTriggers(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	var reqs []*services.UsageTriggerRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	triggers, err := h.equipmentService.SetUsageTriggers(r.Context(), equipmentID, reqs)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to set usage triggers: %v", err), equipmentMeterErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, triggers)
+}
+
+// GetMaintenanceForecast projects maintenance due dates over the next ?days= days
+func (h *EquipmentMeterHandler) GetMaintenanceForecast(w http.ResponseWriter, r *http.Request) {
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+
+	forecasts, err := h.equipmentService.GetMaintenanceForecast(r.Context(), days)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get maintenance forecast: %v", err), equipmentMeterErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, forecasts)
+}
+
+func equipmentMeterErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
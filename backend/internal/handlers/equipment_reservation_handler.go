@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// reservationListWindow is how far ahead reservations are listed when no end is given
+const reservationListWindow = 30 * 24 * time.Hour
+
+// EquipmentReservationHandler handles equipment reservations
+type EquipmentReservationHandler struct {
+	equipmentService services.EquipmentService
+}
+
+// NewEquipmentReservationHandler creates a new equipment reservation handler
+func NewEquipmentReservationHandler(equipmentService services.EquipmentService) *EquipmentReservationHandler {
+	return &EquipmentReservationHandler{
+		equipmentService: equipmentService,
+	}
+}
+
+// SetupEquipmentReservationRoutes sets up the equipment reservation routes
+func (h *EquipmentReservationHandler) SetupEquipmentReservationRoutes(router *mux.Router) {
+	equipment := router.PathPrefix("/equipment").Subrouter()
+	equipment.HandleFunc("/reservations/{reservationId}", h.CancelReservation).Methods("DELETE")
+	equipment.HandleFunc("/{id}/reservations", h.GetReservations).Methods("GET")
+	equipment.HandleFunc("/{id}/reservations", h.ReserveEquipment).Methods("POST")
+}
+
+// GetReservations lists the equipment's reservations between ?start= and ?end=,
+// defaulting to the next 30 days
+func (h *EquipmentReservationHandler) GetReservations(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	start := time.Now()
+	if value := query.Get("start"); value != "" {
+		if start, err = time.Parse(time.RFC3339, value); err != nil {
+			http.Error(w, "Invalid start time", http.StatusBadRequest)
+			return
+		}
+	}
+	end := start.Add(reservationListWindow)
+	if value := query.Get("end"); value != "" {
+		if end, err = time.Parse(time.RFC3339, value); err != nil {
+			http.Error(w, "Invalid end time", http.StatusBadRequest)
+			return
+		}
+	}
+
+	reservations, err := h.equipmentService.GetEquipmentReservations(r.Context(), equipmentID, start, end)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get equipment reservations: %v", err), equipmentReservationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, reservations)
+}
+
+// ReserveEquipment blocks the equipment out for a window outside of a job
+func (h *EquipmentReservationHandler) ReserveEquipment(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.EquipmentReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	reservation, err := h.equipmentService.ReserveEquipment(r.Context(), equipmentID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to reserve equipment: %v", err), equipmentReservationErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, reservation)
+}
+
+func (h *EquipmentReservationHandler) CancelReservation(w http.ResponseWriter, r *http.Request) {
+	reservationID, err := uuid.Parse(mux.Vars(r)["reservationId"])
+	if err != nil {
+		http.Error(w, "Invalid reservation ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.equipmentService.CancelEquipmentReservation(r.Context(), reservationID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to cancel equipment reservation: %v", err), equipmentReservationErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func equipmentReservationErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "already reserved"):
+		return http.StatusConflict
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
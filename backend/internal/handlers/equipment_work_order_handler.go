@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// EquipmentWorkOrderHandler handles maintenance work orders and the parts inventory
+type EquipmentWorkOrderHandler struct {
+	equipmentService services.EquipmentService
+}
+
+// NewEquipmentWorkOrderHandler creates a new equipment work order handler
+func NewEquipmentWorkOrderHandler(equipmentService services.EquipmentService) *EquipmentWorkOrderHandler {
+	return &EquipmentWorkOrderHandler{
+		equipmentService: equipmentService,
+	}
+}
+
+// SetupEquipmentWorkOrderRoutes sets up the work order and parts routes
+func (h *EquipmentWorkOrderHandler) SetupEquipmentWorkOrderRoutes(router *mux.Router) {
+	router.HandleFunc("/equipment/{id}/work-orders", h.OpenWorkOrder).Methods("POST")
+
+	workOrders := router.PathPrefix("/maintenance/work-orders").Subrouter()
+	workOrders.HandleFunc("", h.ListWorkOrders).Methods("GET")
+	workOrders.HandleFunc("/{id}", h.GetWorkOrder).Methods("GET")
+	workOrders.HandleFunc("/{id}/start", h.StartWorkOrder).Methods("POST")
+	workOrders.HandleFunc("/{id}/close", h.CloseWorkOrder).Methods("POST")
+	workOrders.HandleFunc("/{id}/parts", h.AddPart).Methods("POST")
+	workOrders.HandleFunc("/{id}/labor", h.AddLabor).Methods("POST")
+	workOrders.HandleFunc("/{id}/vendor-repairs", h.AddVendorRepair).Methods("POST")
+
+	parts := router.PathPrefix("/parts").Subrouter()
+	parts.HandleFunc("", h.ListParts).Methods("GET")
+	parts.HandleFunc("", h.CreatePart).Methods("POST")
+	parts.HandleFunc("/{id}", h.UpdatePart).Methods("PUT")
+}
+
+func (h *EquipmentWorkOrderHandler) OpenWorkOrder(w http.ResponseWriter, r *http.Request) {
+	equipmentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.WorkOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	workOrder, err := h.equipmentService.OpenWorkOrder(r.Context(), equipmentID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to open work order: %v", err), workOrderErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, workOrder)
+}
+
+// ListWorkOrders lists work orders, filtered by ?equipment_id= and ?status=
+func (h *EquipmentWorkOrderHandler) ListWorkOrders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &services.WorkOrderFilter{Status: query.Get("status")}
+	if value := query.Get("equipment_id"); value != "" {
+		equipmentID, err := uuid.Parse(value)
+		if err != nil {
+			http.Error(w, "Invalid equipment ID", http.StatusBadRequest)
+			return
+		}
+		filter.EquipmentID = &equipmentID
+	}
+
+	workOrders, err := h.equipmentService.ListWorkOrders(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list work orders: %v", err), workOrderErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, workOrders)
+}
+
+func (h *EquipmentWorkOrderHandler) GetWorkOrder(w http.ResponseWriter, r *http.Request) {
+	workOrderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid work order ID", http.StatusBadRequest)
+		return
+	}
+
+	workOrder, err := h.equipmentService.GetWorkOrder(r.Context(), workOrderID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get work order: %v", err), workOrderErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, workOrder)
+}
+
+func (h *EquipmentWorkOrderHandler) StartWorkOrder(w http.ResponseWriter, r *http.Request) {
+	workOrderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid work order ID", http.StatusBadRequest)
+		return
+	}
+
+	workOrder, err := h.equipmentService.StartWorkOrder(r.Context(), workOrderID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to start work order: %v", err), workOrderErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, workOrder)
+}
+
+func (h *EquipmentWorkOrderHandler) CloseWorkOrder(w http.ResponseWriter, r *http.Request) {
+	workOrderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid work order ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.WorkOrderCloseRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	workOrder, err := h.equipmentService.CloseWorkOrder(r.Context(), workOrderID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to close work order: %v", err), workOrderErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, workOrder)
+}
+
+func (h *EquipmentWorkOrderHandler) AddPart(w http.ResponseWriter, r *http.Request) {
+	workOrderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid work order ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.WorkOrderPartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	workOrder, err := h.equipmentService.AddWorkOrderPart(r.Context(), workOrderID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to add part: %v", err), workOrderErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, workOrder)
+}
+
+func (h *EquipmentWorkOrderHandler) AddLabor(w http.ResponseWriter, r *http.Request) {
+	workOrderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid work order ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.WorkOrderLaborRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	workOrder, err := h.equipmentService.AddWorkOrderLabor(r.Context(), workOrderID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to log labor: %v", err), workOrderErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, workOrder)
+}
+
+// AddVendorRepair records an outside repair. It takes a JSON body, or a
+// multipart form with the same fields and the vendor's invoice as "invoice".
+func (h *EquipmentWorkOrderHandler) AddVendorRepair(w http.ResponseWriter, r *http.Request) {
+	workOrderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid work order ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.VendorRepairRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(10 << 20); err != nil { // 10MB limit
+			http.Error(w, "Failed to parse form", http.StatusBadRequest)
+			return
+		}
+
+		req.VendorName = r.FormValue("vendor_name")
+		if value := r.FormValue("invoice_number"); value != "" {
+			req.InvoiceNumber = &value
+		}
+		if value := r.FormValue("description"); value != "" {
+			req.Description = &value
+		}
+		if req.Amount, err = strconv.ParseFloat(r.FormValue("amount"), 64); err != nil {
+			http.Error(w, "Invalid amount", http.StatusBadRequest)
+			return
+		}
+		if value := r.FormValue("completed_at"); value != "" {
+			completedAt, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				http.Error(w, "Invalid completed_at time", http.StatusBadRequest)
+				return
+			}
+			req.CompletedAt = &completedAt
+		}
+
+		if file, header, err := r.FormFile("invoice"); err == nil {
+			defer file.Close()
+			data, err := io.ReadAll(file)
+			if err != nil {
+				http.Error(w, "Failed to read invoice file", http.StatusInternalServerError)
+				return
+			}
+			req.Invoice = &services.VendorInvoiceUpload{
+				Filename:    header.Filename,
+				ContentType: header.Header.Get("Content-Type"),
+				Data:        data,
+			}
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	workOrder, err := h.equipmentService.AddVendorRepair(r.Context(), workOrderID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to record vendor repair: %v", err), workOrderErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, workOrder)
+}
+
+// ListParts lists the parts inventory, only low stock with ?low_stock=true
+func (h *EquipmentWorkOrderHandler) ListParts(w http.ResponseWriter, r *http.Request) {
+	lowStockOnly, _ := strconv.ParseBool(r.URL.Query().Get("low_stock"))
+
+	parts, err := h.equipmentService.ListParts(r.Context(), lowStockOnly)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list parts: %v", err), workOrderErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, parts)
+}
+
+func (h *EquipmentWorkOrderHandler) CreatePart(w http.ResponseWriter, r *http.Request) {
+	var req services.PartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	part, err := h.equipmentService.CreatePart(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create part: %v", err), workOrderErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, part)
+}
+
+func (h *EquipmentWorkOrderHandler) UpdatePart(w http.ResponseWriter, r *http.Request) {
+	partID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid part ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.PartUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	part, err := h.equipmentService.UpdatePart(r.Context(), partID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update part: %v", err), workOrderErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, part)
+}
+
+func workOrderErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "insufficient stock"), strings.Contains(message, "work order is closed"), strings.Contains(message, "cannot move from"):
+		return http.StatusConflict
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
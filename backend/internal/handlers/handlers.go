@@ -18,6 +18,29 @@ type Handlers struct {
 	billingHandler         *BillingHandler
 	whiteLabelHandler      *WhiteLabelHandler
 	supportHandler         *SupportHandler
+	statementHandler       *StatementHandler
+	collectionsHandler     *CollectionsHandler
+	accountingHandler      *AccountingHandler
+	pricingHandler         *PricingHandler
+	portalHandler          *PortalHandler
+	leadHandler            *LeadHandler
+	pipelineHandler        *PipelineHandler
+	bookingHandler         *BookingHandler
+	importHandler          *ImportHandler
+	customerMergeHandler   *CustomerMergeHandler
+	searchHandler          *SearchHandler
+	conversationHandler    *ConversationHandler
+	siteMapHandler         *SiteMapHandler
+	equipmentMeterHandler  *EquipmentMeterHandler
+	equipmentReservationHandler *EquipmentReservationHandler
+	equipmentWorkOrderHandler   *EquipmentWorkOrderHandler
+	equipmentAssetHandler       *EquipmentAssetHandler
+	equipmentFuelHandler        *EquipmentFuelHandler
+	equipmentCheckoutHandler    *EquipmentCheckoutHandler
+	certificationHandler        *CertificationHandler
+	availabilityHandler         *AvailabilityHandler
+	dispatchHandler             *DispatchHandler
+	payrollHandler              *PayrollHandler
 }
 
 // NewHandlers creates a new handlers instance
@@ -28,6 +51,29 @@ func NewHandlers(services *services.Services, config *config.Config) *Handlers {
 	billingHandler := NewBillingHandler(services.EnhancedBilling)
 	whiteLabelHandler := NewWhiteLabelHandler(services.WhiteLabel)
 	supportHandler := NewSupportHandler(services.Support)
+	statementHandler := NewStatementHandler(services.Statement)
+	collectionsHandler := NewCollectionsHandler(services.Collections)
+	accountingHandler := NewAccountingHandler(services.Accounting)
+	pricingHandler := NewPricingHandler(services.Pricing)
+	portalHandler := NewPortalHandler(services.Portal)
+	leadHandler := NewLeadHandler(services.Lead)
+	pipelineHandler := NewPipelineHandler(services.Pipeline)
+	bookingHandler := NewBookingHandler(services.Booking)
+	importHandler := NewImportHandler(services.Import)
+	customerMergeHandler := NewCustomerMergeHandler(services.CustomerMerge)
+	searchHandler := NewSearchHandler(services.Search)
+	conversationHandler := NewConversationHandler(services.Conversation)
+	siteMapHandler := NewSiteMapHandler(services.SiteMap)
+	equipmentMeterHandler := NewEquipmentMeterHandler(services.Equipment)
+	equipmentReservationHandler := NewEquipmentReservationHandler(services.Equipment)
+	equipmentWorkOrderHandler := NewEquipmentWorkOrderHandler(services.Equipment)
+	equipmentAssetHandler := NewEquipmentAssetHandler(services.Equipment)
+	equipmentFuelHandler := NewEquipmentFuelHandler(services.Equipment)
+	equipmentCheckoutHandler := NewEquipmentCheckoutHandler(services.Equipment)
+	certificationHandler := NewCertificationHandler(services.Certification)
+	availabilityHandler := NewAvailabilityHandler(services.Availability)
+	dispatchHandler := NewDispatchHandler(services.Dispatch)
+	payrollHandler := NewPayrollHandler(services.Payroll)
 	
 	return &Handlers{
 		services:               services,
@@ -37,6 +83,29 @@ func NewHandlers(services *services.Services, config *config.Config) *Handlers {
 		billingHandler:         billingHandler,
 		whiteLabelHandler:      whiteLabelHandler,
 		supportHandler:         supportHandler,
+		statementHandler:       statementHandler,
+		collectionsHandler:     collectionsHandler,
+		accountingHandler:      accountingHandler,
+		pricingHandler:         pricingHandler,
+		portalHandler:          portalHandler,
+		leadHandler:            leadHandler,
+		pipelineHandler:        pipelineHandler,
+		bookingHandler:         bookingHandler,
+		importHandler:          importHandler,
+		customerMergeHandler:   customerMergeHandler,
+		searchHandler:          searchHandler,
+		conversationHandler:    conversationHandler,
+		siteMapHandler:         siteMapHandler,
+		equipmentMeterHandler:  equipmentMeterHandler,
+		equipmentReservationHandler: equipmentReservationHandler,
+		equipmentWorkOrderHandler:   equipmentWorkOrderHandler,
+		equipmentAssetHandler:       equipmentAssetHandler,
+		equipmentFuelHandler:        equipmentFuelHandler,
+		equipmentCheckoutHandler:    equipmentCheckoutHandler,
+		certificationHandler:        certificationHandler,
+		availabilityHandler:         availabilityHandler,
+		dispatchHandler:             dispatchHandler,
+		payrollHandler:              payrollHandler,
 	}
 }
 
@@ -162,6 +231,75 @@ func (h *Handlers) SetupRoutes(mw *middleware.Middleware) http.Handler {
 	// Support Ticket Management Routes
 	h.supportHandler.SetupSupportRoutes(protected)
 
+	// Customer Statements and Consolidated Invoicing Routes
+	h.statementHandler.SetupStatementRoutes(protected)
+
+	// Accounts Receivable Collections Routes
+	h.collectionsHandler.SetupCollectionsRoutes(protected)
+
+	// Accounting Export and Sync Routes
+	h.accountingHandler.SetupAccountingRoutes(protected)
+
+	// Price Book and Pricing Rule Routes
+	h.pricingHandler.SetupPricingRoutes(protected)
+
+	// Customer Self-Service Portal Routes (portal session auth) and staff queues
+	h.portalHandler.SetupPortalRoutes(v1, protected)
+
+	// Lead Intake (public booking form) and Review Queue Routes
+	h.leadHandler.SetupLeadRoutes(v1, protected)
+
+	// Sales Pipeline, Follow-up Task and Conversion Metric Routes
+	h.pipelineHandler.SetupPipelineRoutes(protected)
+
+	// Online Booking (embeddable slot and hold API) and Booking Settings Routes
+	h.bookingHandler.SetupBookingRoutes(v1, protected)
+
+	// Bulk Customer and Property Import Routes
+	h.importHandler.SetupImportRoutes(protected)
+
+	// Duplicate Customer Detection and Merge Routes
+	h.customerMergeHandler.SetupCustomerMergeRoutes(protected)
+
+	// Global Search (command palette) Routes
+	h.searchHandler.SetupSearchRoutes(protected)
+
+	// Customer Timeline, Message Thread and Inbound Comms Webhook Routes
+	h.conversationHandler.SetupConversationRoutes(v1, protected)
+
+	// Property Site Map and Measured Feature Routes
+	h.siteMapHandler.SetupSiteMapRoutes(protected)
+
+	// Equipment Meter Reading and Usage-Based Maintenance Routes
+	h.equipmentMeterHandler.SetupEquipmentMeterRoutes(protected)
+
+	// Equipment Reservation Routes
+	h.equipmentReservationHandler.SetupEquipmentReservationRoutes(protected)
+
+	// Maintenance Work Order and Parts Inventory Routes
+	h.equipmentWorkOrderHandler.SetupEquipmentWorkOrderRoutes(protected)
+
+	// Equipment Depreciation, Cost of Ownership and Fixed Asset Register Routes
+	h.equipmentAssetHandler.SetupEquipmentAssetRoutes(protected)
+
+	// Fuel Logging and Efficiency Routes
+	h.equipmentFuelHandler.SetupEquipmentFuelRoutes(protected)
+
+	// Equipment Asset Tag and Checkout Routes
+	h.equipmentCheckoutHandler.SetupEquipmentCheckoutRoutes(protected)
+
+	// Certification and Service Requirement Routes
+	h.certificationHandler.SetupCertificationRoutes(protected)
+
+	// Working Hours, Time Off and Holiday Routes
+	h.availabilityHandler.SetupAvailabilityRoutes(protected)
+
+	// Crew Roster and Dispatch Board Routes
+	h.dispatchHandler.SetupDispatchRoutes(protected)
+
+	// Payroll Routes
+	h.payrollHandler.SetupPayrollRoutes(protected)
+
 	return router
 }
 
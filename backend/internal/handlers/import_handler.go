@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// ImportHandler handles bulk customer and property imports
+type ImportHandler struct {
+	importService services.ImportService
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(importService services.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// SetupImportRoutes sets up the import routes
+func (h *ImportHandler) SetupImportRoutes(router *mux.Router) {
+	imports := router.PathPrefix("/imports").Subrouter()
+	imports.HandleFunc("", h.ListImports).Methods("GET")
+	imports.HandleFunc("", h.CreateImport).Methods("POST")
+	imports.HandleFunc("/fields", h.GetImportFields).Methods("GET")
+	imports.HandleFunc("/{id}", h.GetImport).Methods("GET")
+	imports.HandleFunc("/{id}/mapping", h.UpdateMapping).Methods("PUT")
+	imports.HandleFunc("/{id}/validate", h.ValidateImport).Methods("POST")
+	imports.HandleFunc("/{id}/rows", h.ListImportRows).Methods("GET")
+	imports.HandleFunc("/{id}/start", h.StartImport).Methods("POST")
+	imports.HandleFunc("/{id}/rollback", h.RollbackImport).Methods("POST")
+}
+
+func (h *ImportHandler) CreateImport(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(10 << 20); err != nil { // 10MB limit
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Import file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "Failed to read import file", http.StatusInternalServerError)
+		return
+	}
+
+	job, err := h.importService.CreateImport(r.Context(), &services.ImportUploadRequest{
+		FileName: header.Filename,
+		Content:  content,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create import: %v", err), importErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, job)
+}
+
+func (h *ImportHandler) ListImports(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &services.ImportFilter{Status: query.Get("status")}
+	filter.Search = query.Get("search")
+	filter.Page, _ = strconv.Atoi(query.Get("page"))
+	filter.PerPage, _ = strconv.Atoi(query.Get("per_page"))
+
+	imports, err := h.importService.ListImports(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list imports: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, imports)
+}
+
+func (h *ImportHandler) GetImportFields(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, services.ImportFields)
+}
+
+func (h *ImportHandler) GetImport(w http.ResponseWriter, r *http.Request) {
+	importID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid import ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.importService.GetImport(r.Context(), importID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get import: %v", err), importErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, job)
+}
+
+func (h *ImportHandler) UpdateMapping(w http.ResponseWriter, r *http.Request) {
+	importID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid import ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.ImportMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.importService.UpdateMapping(r.Context(), importID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update mapping: %v", err), importErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, job)
+}
+
+func (h *ImportHandler) ValidateImport(w http.ResponseWriter, r *http.Request) {
+	importID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid import ID", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.importService.ValidateImport(r.Context(), importID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to validate import: %v", err), importErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+func (h *ImportHandler) ListImportRows(w http.ResponseWriter, r *http.Request) {
+	importID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid import ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := &services.ImportRowFilter{Status: query.Get("status")}
+	filter.Search = query.Get("search")
+	filter.Page, _ = strconv.Atoi(query.Get("page"))
+	filter.PerPage, _ = strconv.Atoi(query.Get("per_page"))
+
+	rows, err := h.importService.ListImportRows(r.Context(), importID, filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list import rows: %v", err), importErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, rows)
+}
+
+func (h *ImportHandler) StartImport(w http.ResponseWriter, r *http.Request) {
+	importID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid import ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.importService.StartImport(r.Context(), importID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to start import: %v", err), importErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, job)
+}
+
+func (h *ImportHandler) RollbackImport(w http.ResponseWriter, r *http.Request) {
+	importID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid import ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.importService.RollbackImport(r.Context(), importID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to roll back import: %v", err), importErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, job)
+}
+
+// importErrorStatus maps import service errors to HTTP status codes. Steps taken in
+// the wrong order conflict with the import's current status.
+func importErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	case strings.HasPrefix(message, "failed to"):
+		return http.StatusInternalServerError
+	}
+	return http.StatusConflict
+}
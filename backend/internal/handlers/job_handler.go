@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -52,6 +53,12 @@ func (h *JobHandler) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc("/jobs/{id}/services", h.UpdateJobServices).Methods("PUT")
 	router.HandleFunc("/jobs/{id}/photos", h.UploadJobPhotos).Methods("POST")
 	router.HandleFunc("/jobs/{id}/signature", h.AddJobSignature).Methods("POST")
+
+	// Job costing
+	router.HandleFunc("/jobs/{id}/costing", h.GetJobCosting).Methods("GET")
+
+	// Qualifications
+	router.HandleFunc("/jobs/{id}/qualifications", h.CheckJobQualifications).Methods("GET")
 	
 	// Scheduling and calendar routes
 	router.HandleFunc("/jobs/schedule", h.GetJobSchedule).Methods("GET")
@@ -413,6 +420,10 @@ func (h *JobHandler) AssignJob(w http.ResponseWriter, r *http.Request) {
 			h.respondWithError(w, http.StatusNotFound, "Job not found", nil)
 			return
 		}
+		if strings.HasPrefix(err.Error(), "not qualified") {
+			h.respondWithError(w, http.StatusConflict, "Assignee is not qualified for job", err)
+			return
+		}
 		h.logger.Error("Failed to assign job", "error", err, "job_id", jobID, "user_id", userID)
 		h.respondWithError(w, http.StatusInternalServerError, "Failed to assign job", err)
 		return
@@ -500,6 +511,10 @@ func (h *JobHandler) AssignJobToCrew(w http.ResponseWriter, r *http.Request) {
 			h.respondWithError(w, http.StatusNotFound, "Job not found", nil)
 			return
 		}
+		if strings.HasPrefix(err.Error(), "not qualified") {
+			h.respondWithError(w, http.StatusConflict, "Crew is not qualified for job", err)
+			return
+		}
 		h.logger.Error("Failed to assign job to crew", "error", err, "job_id", jobID, "crew_id", crewID)
 		h.respondWithError(w, http.StatusInternalServerError, "Failed to assign job to crew", err)
 		return
@@ -542,6 +557,98 @@ func (h *JobHandler) GetJobServices(w http.ResponseWriter, r *http.Request) {
 	h.respondWithJSON(w, http.StatusOK, services)
 }
 
+// GetJobCosting gets the costs and gross profit of a job
+// @Summary Get job costing
+// @Description Retrieve a job's revenue, costs including allocated fuel, and gross profit
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} services.JobCosting
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 404 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Router /jobs/{id}/costing [get]
+func (h *JobHandler) GetJobCosting(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	jobID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid job ID", err)
+		return
+	}
+
+	costing, err := h.jobService.GetJobCosting(r.Context(), jobID)
+	if err != nil {
+		if err.Error() == "job not found" {
+			h.respondWithError(w, http.StatusNotFound, "Job not found", nil)
+			return
+		}
+		h.logger.Error("Failed to get job costing", "error", err, "job_id", jobID)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to get job costing", err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, costing)
+}
+
+// CheckJobQualifications checks an assignee against the job's required certifications
+// @Summary Check job qualifications
+// @Description Check whether a user or crew holds the certifications the job's services require
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param user_id query string false "User ID"
+// @Param crew_id query string false "Crew ID"
+// @Success 200 {object} services.QualificationCheck
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 404 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Router /jobs/{id}/qualifications [get]
+func (h *JobHandler) CheckJobQualifications(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	jobID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid job ID", err)
+		return
+	}
+
+	var userID, crewID *uuid.UUID
+	if value := r.URL.Query().Get("user_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			h.respondWithError(w, http.StatusBadRequest, "Invalid user ID", err)
+			return
+		}
+		userID = &id
+	}
+	if value := r.URL.Query().Get("crew_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			h.respondWithError(w, http.StatusBadRequest, "Invalid crew ID", err)
+			return
+		}
+		crewID = &id
+	}
+
+	check, err := h.jobService.CheckJobQualifications(r.Context(), jobID, userID, crewID)
+	if err != nil {
+		if err.Error() == "job not found" {
+			h.respondWithError(w, http.StatusNotFound, "Job not found", nil)
+			return
+		}
+		if strings.HasPrefix(err.Error(), "validation failed") {
+			h.respondWithError(w, http.StatusBadRequest, "Either user_id or crew_id is required", err)
+			return
+		}
+		h.logger.Error("Failed to check job qualifications", "error", err, "job_id", jobID)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to check job qualifications", err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, check)
+}
+
 // UpdateJobServices updates services for a job
 // @Summary Update job services
 // @Description Update the services associated with a job
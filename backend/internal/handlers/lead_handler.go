@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// LeadHandler handles lead intake from public forms and the staff review queue
+type LeadHandler struct {
+	leadService services.LeadService
+}
+
+// NewLeadHandler creates a new lead handler
+func NewLeadHandler(leadService services.LeadService) *LeadHandler {
+	return &LeadHandler{
+		leadService: leadService,
+	}
+}
+
+// SetupLeadRoutes sets up the public intake route and the protected review routes
+func (h *LeadHandler) SetupLeadRoutes(public, protected *mux.Router) {
+	public.HandleFunc("/public/leads", h.SubmitLead).Methods("POST")
+
+	leads := protected.PathPrefix("/leads").Subrouter()
+	leads.HandleFunc("", h.ListLeads).Methods("GET")
+	leads.HandleFunc("", h.CreateLead).Methods("POST")
+	leads.HandleFunc("/{id}", h.GetLead).Methods("GET")
+	leads.HandleFunc("/{id}/accept", h.AcceptLead).Methods("POST")
+	leads.HandleFunc("/{id}/deny", h.DenyLead).Methods("POST")
+	leads.HandleFunc("/{id}/status", h.UpdateLeadStatus).Methods("PUT")
+}
+
+// SubmitLead accepts a booking or consultation request from a public form. The
+// response doesn't reveal how the lead was screened.
+func (h *LeadHandler) SubmitLead(w http.ResponseWriter, r *http.Request) {
+	var req services.LeadSubmission
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	client := portalClientInfo(r)
+	req.IPAddress = client.IPAddress
+	req.UserAgent = client.UserAgent
+
+	if _, err := h.leadService.SubmitLead(r.Context(), &req); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to submit request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, map[string]string{
+		"message": "Thanks! We'll be in touch within 24 hours",
+	})
+}
+
+// CreateLead records a lead taken by staff, e.g. over the phone
+func (h *LeadHandler) CreateLead(w http.ResponseWriter, r *http.Request) {
+	var req services.LeadSubmission
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Source == "" {
+		req.Source = domain.LeadSourcePhone
+	}
+
+	lead, err := h.leadService.SubmitLead(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create lead: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, lead)
+}
+
+func (h *LeadHandler) ListLeads(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &services.LeadFilter{
+		Status: query.Get("status"),
+		Source: query.Get("source"),
+	}
+	filter.Search = query.Get("search")
+	filter.Page, _ = strconv.Atoi(query.Get("page"))
+	filter.PerPage, _ = strconv.Atoi(query.Get("per_page"))
+
+	leads, err := h.leadService.ListLeads(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list leads: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, leads)
+}
+
+func (h *LeadHandler) GetLead(w http.ResponseWriter, r *http.Request) {
+	leadID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid lead ID", http.StatusBadRequest)
+		return
+	}
+
+	lead, err := h.leadService.GetLead(r.Context(), leadID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get lead: %v", err), leadErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, lead)
+}
+
+func (h *LeadHandler) AcceptLead(w http.ResponseWriter, r *http.Request) {
+	leadID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid lead ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.LeadAcceptRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	conversion, err := h.leadService.AcceptLead(r.Context(), leadID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to accept lead: %v", err), leadErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, conversion)
+}
+
+func (h *LeadHandler) DenyLead(w http.ResponseWriter, r *http.Request) {
+	leadID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid lead ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	lead, err := h.leadService.DenyLead(r.Context(), leadID, req.Reason)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to deny lead: %v", err), leadErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, lead)
+}
+
+func (h *LeadHandler) UpdateLeadStatus(w http.ResponseWriter, r *http.Request) {
+	leadID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid lead ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	lead, err := h.leadService.UpdateLeadStatus(r.Context(), leadID, req.Status)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update lead: %v", err), leadErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, lead)
+}
+
+func leadErrorStatus(err error) int {
+	if strings.Contains(err.Error(), "not found") {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// PayrollHandler handles pay plans, time entries and pay periods
+type PayrollHandler struct {
+	payrollService services.PayrollService
+}
+
+// NewPayrollHandler creates a new payroll handler
+func NewPayrollHandler(payrollService services.PayrollService) *PayrollHandler {
+	return &PayrollHandler{
+		payrollService: payrollService,
+	}
+}
+
+// SetupPayrollRoutes sets up the payroll routes
+func (h *PayrollHandler) SetupPayrollRoutes(router *mux.Router) {
+	payroll := router.PathPrefix("/payroll").Subrouter()
+	payroll.HandleFunc("/users/{id}/pay-plan", h.GetPayPlan).Methods("GET")
+	payroll.HandleFunc("/users/{id}/pay-plan", h.SetPayPlan).Methods("PUT")
+
+	payroll.HandleFunc("/time-entries", h.ListTimeEntries).Methods("GET")
+	payroll.HandleFunc("/time-entries", h.CreateTimeEntry).Methods("POST")
+	payroll.HandleFunc("/time-entries/{id}", h.UpdateTimeEntry).Methods("PUT")
+	payroll.HandleFunc("/time-entries/{id}", h.DeleteTimeEntry).Methods("DELETE")
+
+	payroll.HandleFunc("/periods", h.ListPayPeriods).Methods("GET")
+	payroll.HandleFunc("/periods", h.CreatePayPeriod).Methods("POST")
+	payroll.HandleFunc("/periods/{id}/register", h.GetPayrollRegister).Methods("GET")
+	payroll.HandleFunc("/periods/{id}/calculate", h.CalculatePayPeriod).Methods("POST")
+	payroll.HandleFunc("/periods/{id}/approve", h.ApprovePayPeriod).Methods("POST")
+	payroll.HandleFunc("/periods/{id}/reopen", h.ReopenPayPeriod).Methods("POST")
+	payroll.HandleFunc("/periods/{id}/export", h.ExportPayPeriod).Methods("GET")
+}
+
+func (h *PayrollHandler) GetPayPlan(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.payrollService.GetPayPlan(r.Context(), userID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get pay plan: %v", err), payrollErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, plan)
+}
+
+// SetPayPlan replaces the user's employee number and pay rules
+func (h *PayrollHandler) SetPayPlan(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.PayPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.payrollService.SetPayPlan(r.Context(), userID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to set pay plan: %v", err), payrollErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, plan)
+}
+
+// ListTimeEntries lists time entries by ?user_id=, ?job_id= and clock-in
+// ?start_date= to ?end_date=
+func (h *PayrollHandler) ListTimeEntries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &services.TimeEntryFilter{}
+
+	if value := query.Get("user_id"); value != "" {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+		filter.UserID = &userID
+	}
+	if value := query.Get("job_id"); value != "" {
+		jobID, err := uuid.Parse(value)
+		if err != nil {
+			http.Error(w, "Invalid job ID", http.StatusBadRequest)
+			return
+		}
+		filter.JobID = &jobID
+	}
+	if value := query.Get("start_date"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			http.Error(w, "Invalid start_date", http.StatusBadRequest)
+			return
+		}
+		filter.From = &date
+	}
+	if value := query.Get("end_date"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			http.Error(w, "Invalid end_date", http.StatusBadRequest)
+			return
+		}
+		// Include the whole end day
+		date = date.AddDate(0, 0, 1)
+		filter.To = &date
+	}
+
+	entries, err := h.payrollService.ListTimeEntries(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list time entries: %v", err), payrollErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, entries)
+}
+
+func (h *PayrollHandler) CreateTimeEntry(w http.ResponseWriter, r *http.Request) {
+	var req services.TimeEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.payrollService.CreateTimeEntry(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create time entry: %v", err), payrollErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, entry)
+}
+
+func (h *PayrollHandler) UpdateTimeEntry(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid time entry ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.TimeEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.payrollService.UpdateTimeEntry(r.Context(), entryID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update time entry: %v", err), payrollErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, entry)
+}
+
+func (h *PayrollHandler) DeleteTimeEntry(w http.ResponseWriter, r *http.Request) {
+	entryID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid time entry ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.payrollService.DeleteTimeEntry(r.Context(), entryID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete time entry: %v", err), payrollErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PayrollHandler) ListPayPeriods(w http.ResponseWriter, r *http.Request) {
+	periods, err := h.payrollService.ListPayPeriods(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list pay periods: %v", err), payrollErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, periods)
+}
+
+func (h *PayrollHandler) CreatePayPeriod(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		StartDate string `json:"start_date"`
+		EndDate   string `json:"end_date"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		http.Error(w, "Invalid start_date format, use YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		http.Error(w, "Invalid end_date format, use YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	period, err := h.payrollService.CreatePayPeriod(r.Context(), &services.PayPeriodRequest{
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create pay period: %v", err), payrollErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, period)
+}
+
+func (h *PayrollHandler) GetPayrollRegister(w http.ResponseWriter, r *http.Request) {
+	periodID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid pay period ID", http.StatusBadRequest)
+		return
+	}
+
+	register, err := h.payrollService.GetPayrollRegister(r.Context(), periodID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get payroll register: %v", err), payrollErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, register)
+}
+
+func (h *PayrollHandler) CalculatePayPeriod(w http.ResponseWriter, r *http.Request) {
+	periodID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid pay period ID", http.StatusBadRequest)
+		return
+	}
+
+	register, err := h.payrollService.CalculatePayPeriod(r.Context(), periodID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to calculate pay period: %v", err), payrollErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, register)
+}
+
+func (h *PayrollHandler) ApprovePayPeriod(w http.ResponseWriter, r *http.Request) {
+	periodID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid pay period ID", http.StatusBadRequest)
+		return
+	}
+
+	period, err := h.payrollService.ApprovePayPeriod(r.Context(), periodID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to approve pay period: %v", err), payrollErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, period)
+}
+
+func (h *PayrollHandler) ReopenPayPeriod(w http.ResponseWriter, r *http.Request) {
+	periodID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid pay period ID", http.StatusBadRequest)
+		return
+	}
+
+	period, err := h.payrollService.ReopenPayPeriod(r.Context(), periodID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to reopen pay period: %v", err), payrollErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, period)
+}
+
+// ExportPayPeriod downloads an approved period as ?format=register_csv,
+// adp_csv or gusto_csv. ADP exports take ?company_code= and ?batch_id=.
+func (h *PayrollHandler) ExportPayPeriod(w http.ResponseWriter, r *http.Request) {
+	periodID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid pay period ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		http.Error(w, "format parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	export, err := h.payrollService.ExportPayPeriod(r.Context(), periodID, &services.PayrollExportRequest{
+		Format:      format,
+		CompanyCode: query.Get("company_code"),
+		BatchID:     query.Get("batch_id"),
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to export pay period: %v", err), payrollErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", export.Filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(export.Data)
+}
+
+func payrollErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	case strings.Contains(message, "cannot be"), strings.Contains(message, "already exists"):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,427 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// PipelineHandler handles sales pipeline stages, opportunities, follow-up tasks and
+// conversion metrics
+type PipelineHandler struct {
+	pipelineService services.PipelineService
+}
+
+// NewPipelineHandler creates a new pipeline handler
+func NewPipelineHandler(pipelineService services.PipelineService) *PipelineHandler {
+	return &PipelineHandler{
+		pipelineService: pipelineService,
+	}
+}
+
+// SetupPipelineRoutes sets up the sales pipeline routes
+func (h *PipelineHandler) SetupPipelineRoutes(router *mux.Router) {
+	pipeline := router.PathPrefix("/pipeline").Subrouter()
+
+	pipeline.HandleFunc("/stages", h.ListStages).Methods("GET")
+	pipeline.HandleFunc("/stages", h.CreateStage).Methods("POST")
+	pipeline.HandleFunc("/stages/order", h.ReorderStages).Methods("PUT")
+	pipeline.HandleFunc("/stages/{id}", h.UpdateStage).Methods("PUT")
+	pipeline.HandleFunc("/stages/{id}", h.DeleteStage).Methods("DELETE")
+
+	pipeline.HandleFunc("/opportunities", h.ListOpportunities).Methods("GET")
+	pipeline.HandleFunc("/opportunities", h.CreateOpportunity).Methods("POST")
+	pipeline.HandleFunc("/opportunities/{id}", h.GetOpportunity).Methods("GET")
+	pipeline.HandleFunc("/opportunities/{id}", h.UpdateOpportunity).Methods("PUT")
+	pipeline.HandleFunc("/opportunities/{id}/move", h.MoveOpportunity).Methods("POST")
+	pipeline.HandleFunc("/opportunities/{id}/assign", h.AssignOpportunity).Methods("POST")
+
+	pipeline.HandleFunc("/tasks", h.ListFollowUpTasks).Methods("GET")
+	pipeline.HandleFunc("/tasks", h.CreateFollowUpTask).Methods("POST")
+	pipeline.HandleFunc("/tasks/{id}", h.UpdateFollowUpTask).Methods("PUT")
+	pipeline.HandleFunc("/tasks/{id}/complete", h.CompleteFollowUpTask).Methods("POST")
+	pipeline.HandleFunc("/tasks/{id}/cancel", h.CancelFollowUpTask).Methods("POST")
+
+	pipeline.HandleFunc("/metrics", h.GetPipelineMetrics).Methods("GET")
+	pipeline.HandleFunc("/close-reasons", h.GetCloseReasons).Methods("GET")
+}
+
+func (h *PipelineHandler) ListStages(w http.ResponseWriter, r *http.Request) {
+	stages, err := h.pipelineService.ListStages(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list stages: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, stages)
+}
+
+func (h *PipelineHandler) CreateStage(w http.ResponseWriter, r *http.Request) {
+	var req services.PipelineStageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	stage, err := h.pipelineService.CreateStage(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create stage: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, stage)
+}
+
+func (h *PipelineHandler) UpdateStage(w http.ResponseWriter, r *http.Request) {
+	stageID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid stage ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.PipelineStageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	stage, err := h.pipelineService.UpdateStage(r.Context(), stageID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update stage: %v", err), pipelineErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, stage)
+}
+
+func (h *PipelineHandler) ReorderStages(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		StageIDs []uuid.UUID `json:"stage_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	stages, err := h.pipelineService.ReorderStages(r.Context(), req.StageIDs)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to reorder stages: %v", err), pipelineErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, stages)
+}
+
+func (h *PipelineHandler) DeleteStage(w http.ResponseWriter, r *http.Request) {
+	stageID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid stage ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.pipelineService.DeleteStage(r.Context(), stageID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete stage: %v", err), pipelineErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PipelineHandler) ListOpportunities(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &services.OpportunityFilter{
+		Status: query.Get("status"),
+		Source: query.Get("source"),
+	}
+	filter.StageID = parseOptionalUUID(query.Get("stage_id"))
+	filter.AssignedTo = parseOptionalUUID(query.Get("assigned_to"))
+	filter.CustomerID = parseOptionalUUID(query.Get("customer_id"))
+	filter.Search = query.Get("search")
+	filter.Page, _ = strconv.Atoi(query.Get("page"))
+	filter.PerPage, _ = strconv.Atoi(query.Get("per_page"))
+
+	opportunities, err := h.pipelineService.ListOpportunities(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list opportunities: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, opportunities)
+}
+
+func (h *PipelineHandler) CreateOpportunity(w http.ResponseWriter, r *http.Request) {
+	var req services.OpportunityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	opportunity, err := h.pipelineService.CreateOpportunity(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create opportunity: %v", err), pipelineErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, opportunity)
+}
+
+func (h *PipelineHandler) GetOpportunity(w http.ResponseWriter, r *http.Request) {
+	opportunityID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid opportunity ID", http.StatusBadRequest)
+		return
+	}
+
+	opportunity, err := h.pipelineService.GetOpportunity(r.Context(), opportunityID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get opportunity: %v", err), pipelineErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, opportunity)
+}
+
+func (h *PipelineHandler) UpdateOpportunity(w http.ResponseWriter, r *http.Request) {
+	opportunityID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid opportunity ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.OpportunityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	opportunity, err := h.pipelineService.UpdateOpportunity(r.Context(), opportunityID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update opportunity: %v", err), pipelineErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, opportunity)
+}
+
+// MoveOpportunity moves an opportunity to another stage, closing it as won or lost
+func (h *PipelineHandler) MoveOpportunity(w http.ResponseWriter, r *http.Request) {
+	opportunityID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid opportunity ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.OpportunityMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	opportunity, err := h.pipelineService.MoveOpportunity(r.Context(), opportunityID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to move opportunity: %v", err), pipelineErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, opportunity)
+}
+
+func (h *PipelineHandler) AssignOpportunity(w http.ResponseWriter, r *http.Request) {
+	opportunityID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid opportunity ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		UserID *uuid.UUID `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	opportunity, err := h.pipelineService.AssignOpportunity(r.Context(), opportunityID, req.UserID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to assign opportunity: %v", err), pipelineErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, opportunity)
+}
+
+func (h *PipelineHandler) ListFollowUpTasks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &services.FollowUpTaskFilter{
+		Status: query.Get("status"),
+	}
+	filter.AssignedTo = parseOptionalUUID(query.Get("assigned_to"))
+	filter.OpportunityID = parseOptionalUUID(query.Get("opportunity_id"))
+	filter.CustomerID = parseOptionalUUID(query.Get("customer_id"))
+	if dueBefore := query.Get("due_before"); dueBefore != "" {
+		date, err := time.Parse("2006-01-02", dueBefore)
+		if err != nil {
+			http.Error(w, "Invalid due_before format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		filter.DueBefore = &date
+	}
+	filter.Page, _ = strconv.Atoi(query.Get("page"))
+	filter.PerPage, _ = strconv.Atoi(query.Get("per_page"))
+
+	tasks, err := h.pipelineService.ListFollowUpTasks(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list tasks: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tasks)
+}
+
+func (h *PipelineHandler) CreateFollowUpTask(w http.ResponseWriter, r *http.Request) {
+	var req services.FollowUpTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.pipelineService.CreateFollowUpTask(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create task: %v", err), pipelineErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, task)
+}
+
+func (h *PipelineHandler) UpdateFollowUpTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.FollowUpTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.pipelineService.UpdateFollowUpTask(r.Context(), taskID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update task: %v", err), pipelineErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, task)
+}
+
+func (h *PipelineHandler) CompleteFollowUpTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Outcome string `json:"outcome"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	task, err := h.pipelineService.CompleteFollowUpTask(r.Context(), taskID, req.Outcome)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to complete task: %v", err), pipelineErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, task)
+}
+
+func (h *PipelineHandler) CancelFollowUpTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.pipelineService.CancelFollowUpTask(r.Context(), taskID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to cancel task: %v", err), pipelineErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, task)
+}
+
+// GetPipelineMetrics reports conversion for opportunities created between
+// start_date and end_date (default: the last three months)
+func (h *PipelineHandler) GetPipelineMetrics(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &services.PipelineMetricsFilter{
+		Source: query.Get("source"),
+	}
+	filter.AssignedTo = parseOptionalUUID(query.Get("assigned_to"))
+
+	if startDate := query.Get("start_date"); startDate != "" {
+		date, err := time.Parse("2006-01-02", startDate)
+		if err != nil {
+			http.Error(w, "Invalid start_date format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		filter.Start = date
+	}
+	if endDate := query.Get("end_date"); endDate != "" {
+		date, err := time.Parse("2006-01-02", endDate)
+		if err != nil {
+			http.Error(w, "Invalid end_date format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		filter.End = date.AddDate(0, 0, 1)
+	}
+
+	metrics, err := h.pipelineService.GetPipelineMetrics(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get pipeline metrics: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, metrics)
+}
+
+// GetCloseReasons lists the suggested win and loss reasons
+func (h *PipelineHandler) GetCloseReasons(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, map[string][]string{
+		"win_reasons":  services.DefaultWinReasons,
+		"loss_reasons": services.DefaultLossReasons,
+	})
+}
+
+func pipelineErrorStatus(err error) int {
+	if strings.Contains(err.Error(), "not found") {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+func parseOptionalUUID(value string) *uuid.UUID {
+	if value == "" {
+		return nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
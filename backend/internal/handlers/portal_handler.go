@@ -0,0 +1,495 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// portalSessionCookie carries the portal session token for browser clients
+const portalSessionCookie = "portal_session"
+
+// PortalHandler handles the customer self-service portal and its staff queues
+type PortalHandler struct {
+	portalService services.PortalService
+}
+
+// NewPortalHandler creates a new portal handler
+func NewPortalHandler(portalService services.PortalService) *PortalHandler {
+	return &PortalHandler{
+		portalService: portalService,
+	}
+}
+
+// SetupPortalRoutes sets up customer portal routes on the public router, which
+// authenticate with a portal session, and the staff routes on the protected router
+func (h *PortalHandler) SetupPortalRoutes(public, protected *mux.Router) {
+	portal := public.PathPrefix("/portal").Subrouter()
+
+	// Passwordless sign-in
+	portal.HandleFunc("/auth/magic-link", h.RequestMagicLink).Methods("POST")
+	portal.HandleFunc("/auth/verify", h.VerifyMagicLink).Methods("POST")
+
+	// Signed-in customer routes
+	customer := portal.PathPrefix("").Subrouter()
+	customer.Use(h.RequirePortalSession)
+	customer.HandleFunc("/auth/logout", h.Logout).Methods("POST")
+	customer.HandleFunc("/me", h.GetProfile).Methods("GET")
+	customer.HandleFunc("/properties", h.ListProperties).Methods("GET")
+	customer.HandleFunc("/jobs", h.ListJobs).Methods("GET")
+	customer.HandleFunc("/jobs/{id}/schedule-changes", h.RequestScheduleChange).Methods("POST")
+	customer.HandleFunc("/schedule-changes", h.ListMyScheduleChanges).Methods("GET")
+	customer.HandleFunc("/invoices", h.ListInvoices).Methods("GET")
+	customer.HandleFunc("/invoices/{id}", h.GetInvoice).Methods("GET")
+	customer.HandleFunc("/invoices/{id}/pay", h.PayInvoice).Methods("POST")
+	customer.HandleFunc("/quotes", h.ListQuotes).Methods("GET")
+	customer.HandleFunc("/quotes/{id}", h.GetQuote).Methods("GET")
+	customer.HandleFunc("/quotes/{id}/accept", h.AcceptQuote).Methods("POST")
+	customer.HandleFunc("/quotes/{id}/decline", h.DeclineQuote).Methods("POST")
+	customer.HandleFunc("/service-requests", h.ListMyServiceRequests).Methods("GET")
+	customer.HandleFunc("/service-requests", h.SubmitServiceRequest).Methods("POST")
+
+	// Staff administration
+	admin := protected.PathPrefix("/portal-admin").Subrouter()
+	admin.HandleFunc("/settings", h.GetPortalSettings).Methods("GET")
+	admin.HandleFunc("/settings", h.UpdatePortalSettings).Methods("PUT")
+	admin.HandleFunc("/schedule-changes", h.ListScheduleChanges).Methods("GET")
+	admin.HandleFunc("/schedule-changes/{id}/review", h.ReviewScheduleChange).Methods("POST")
+	admin.HandleFunc("/service-requests", h.ListServiceRequests).Methods("GET")
+	admin.HandleFunc("/service-requests/{id}", h.UpdateServiceRequest).Methods("PUT")
+}
+
+// RequirePortalSession authenticates the portal session from the Authorization
+// header or session cookie and scopes the request to that customer
+func (h *PortalHandler) RequirePortalSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, err := h.portalService.AuthenticateSession(r.Context(), portalSessionToken(r))
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(services.WithPortalCustomer(r.Context(), session)))
+	})
+}
+
+// Authentication
+
+func (h *PortalHandler) RequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	var req services.PortalLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.portalService.RequestMagicLink(r.Context(), &req, portalClientInfo(r)); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to send sign-in link: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Same response whether or not the email matched a customer
+	respondWithJSON(w, http.StatusAccepted, map[string]string{
+		"message": "If that email belongs to an account, a sign-in link is on its way",
+	})
+}
+
+func (h *PortalHandler) VerifyMagicLink(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.portalService.VerifyMagicLink(r.Context(), req.Token, portalClientInfo(r))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to sign in: %v", err), http.StatusUnauthorized)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     portalSessionCookie,
+		Value:    result.SessionToken,
+		Path:     "/",
+		Expires:  result.ExpiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+func (h *PortalHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := h.portalService.Logout(r.Context(), portalSessionToken(r)); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to sign out: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{Name: portalSessionCookie, Value: "", Path: "/", MaxAge: -1})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Customer views
+
+func (h *PortalHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	profile, err := h.portalService.GetProfile(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get profile: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, profile)
+}
+
+func (h *PortalHandler) ListProperties(w http.ResponseWriter, r *http.Request) {
+	properties, err := h.portalService.ListProperties(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list properties: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, properties)
+}
+
+func (h *PortalHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	upcomingOnly := r.URL.Query().Get("upcoming") == "true"
+
+	jobs, err := h.portalService.ListJobs(r.Context(), upcomingOnly)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list jobs: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, jobs)
+}
+
+func (h *PortalHandler) ListInvoices(w http.ResponseWriter, r *http.Request) {
+	invoices, err := h.portalService.ListInvoices(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list invoices: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, invoices)
+}
+
+func (h *PortalHandler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	invoiceID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid invoice ID", http.StatusBadRequest)
+		return
+	}
+
+	invoice, err := h.portalService.GetInvoice(r.Context(), invoiceID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get invoice: %v", err), http.StatusNotFound)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, invoice)
+}
+
+func (h *PortalHandler) ListQuotes(w http.ResponseWriter, r *http.Request) {
+	quotes, err := h.portalService.ListQuotes(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list quotes: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, quotes)
+}
+
+func (h *PortalHandler) GetQuote(w http.ResponseWriter, r *http.Request) {
+	quoteID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid quote ID", http.StatusBadRequest)
+		return
+	}
+
+	quote, err := h.portalService.GetQuote(r.Context(), quoteID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get quote: %v", err), http.StatusNotFound)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, quote)
+}
+
+// Customer actions
+
+func (h *PortalHandler) AcceptQuote(w http.ResponseWriter, r *http.Request) {
+	quoteID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid quote ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.PortalQuoteAcceptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	signature, err := h.portalService.AcceptQuote(r.Context(), quoteID, &req, portalClientInfo(r))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to accept quote: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, signature)
+}
+
+func (h *PortalHandler) DeclineQuote(w http.ResponseWriter, r *http.Request) {
+	quoteID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid quote ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.portalService.DeclineQuote(r.Context(), quoteID, req.Reason); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to decline quote: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PortalHandler) PayInvoice(w http.ResponseWriter, r *http.Request) {
+	invoiceID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid invoice ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.PortalPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	payment, err := h.portalService.PayInvoice(r.Context(), invoiceID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to pay invoice: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, payment)
+}
+
+func (h *PortalHandler) RequestScheduleChange(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.PortalScheduleChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	change, err := h.portalService.RequestScheduleChange(r.Context(), jobID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to request schedule change: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, change)
+}
+
+func (h *PortalHandler) ListMyScheduleChanges(w http.ResponseWriter, r *http.Request) {
+	changes, err := h.portalService.ListMyScheduleChanges(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list schedule changes: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, changes)
+}
+
+func (h *PortalHandler) SubmitServiceRequest(w http.ResponseWriter, r *http.Request) {
+	var req services.PortalServiceRequestInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	request, err := h.portalService.SubmitServiceRequest(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to submit service request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, request)
+}
+
+func (h *PortalHandler) ListMyServiceRequests(w http.ResponseWriter, r *http.Request) {
+	requests, err := h.portalService.ListMyServiceRequests(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list service requests: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, requests)
+}
+
+// Staff administration
+
+func (h *PortalHandler) GetPortalSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.portalService.GetPortalSettings(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get portal settings: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, settings)
+}
+
+func (h *PortalHandler) UpdatePortalSettings(w http.ResponseWriter, r *http.Request) {
+	var req services.PortalSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.portalService.UpdatePortalSettings(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update portal settings: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, settings)
+}
+
+func (h *PortalHandler) ListScheduleChanges(w http.ResponseWriter, r *http.Request) {
+	filter, err := portalRequestFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	changes, err := h.portalService.ListScheduleChanges(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list schedule changes: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, changes)
+}
+
+func (h *PortalHandler) ReviewScheduleChange(w http.ResponseWriter, r *http.Request) {
+	changeID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid schedule change ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.ScheduleChangeReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	change, err := h.portalService.ReviewScheduleChange(r.Context(), changeID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to review schedule change: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, change)
+}
+
+func (h *PortalHandler) ListServiceRequests(w http.ResponseWriter, r *http.Request) {
+	filter, err := portalRequestFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	requests, err := h.portalService.ListServiceRequests(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list service requests: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, requests)
+}
+
+func (h *PortalHandler) UpdateServiceRequest(w http.ResponseWriter, r *http.Request) {
+	requestID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid service request ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.PortalServiceRequestUpdate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	request, err := h.portalService.UpdateServiceRequest(r.Context(), requestID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update service request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, request)
+}
+
+// Helper functions
+
+func portalSessionToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	if cookie, err := r.Cookie(portalSessionCookie); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+func portalClientInfo(r *http.Request) *services.PortalClientInfo {
+	ip := r.RemoteAddr
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		ip = strings.TrimSpace(strings.Split(xff, ",")[0])
+	} else if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+
+	return &services.PortalClientInfo{IPAddress: ip, UserAgent: r.UserAgent()}
+}
+
+func portalRequestFilter(r *http.Request) (*services.PortalRequestFilter, error) {
+	query := r.URL.Query()
+	filter := &services.PortalRequestFilter{Status: query.Get("status")}
+
+	if customer := query.Get("customer_id"); customer != "" {
+		customerID, err := uuid.Parse(customer)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid customer ID")
+		}
+		filter.CustomerID = &customerID
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// PricingHandler handles price book management and price calculation
+type PricingHandler struct {
+	pricingService services.PricingService
+}
+
+// NewPricingHandler creates a new pricing handler
+func NewPricingHandler(pricingService services.PricingService) *PricingHandler {
+	return &PricingHandler{
+		pricingService: pricingService,
+	}
+}
+
+// SetupPricingRoutes sets up pricing routes
+func (h *PricingHandler) SetupPricingRoutes(router *mux.Router) {
+	pricing := router.PathPrefix("/pricing").Subrouter()
+
+	// Price books
+	pricing.HandleFunc("/price-books", h.ListPriceBooks).Methods("GET")
+	pricing.HandleFunc("/price-books", h.CreatePriceBook).Methods("POST")
+	pricing.HandleFunc("/price-books/{id}", h.GetPriceBook).Methods("GET")
+	pricing.HandleFunc("/price-books/{id}", h.UpdatePriceBook).Methods("PUT")
+	pricing.HandleFunc("/price-books/{id}", h.DeletePriceBook).Methods("DELETE")
+
+	// Rules
+	pricing.HandleFunc("/price-books/{id}/rules", h.AddPricingRule).Methods("POST")
+	pricing.HandleFunc("/rules/{id}", h.UpdatePricingRule).Methods("PUT")
+	pricing.HandleFunc("/rules/{id}", h.DeletePricingRule).Methods("DELETE")
+
+	// Calculation
+	pricing.HandleFunc("/calculate", h.CalculatePrice).Methods("POST")
+}
+
+// Price books
+
+func (h *PricingHandler) ListPriceBooks(w http.ResponseWriter, r *http.Request) {
+	books, err := h.pricingService.ListPriceBooks(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list price books: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, books)
+}
+
+func (h *PricingHandler) CreatePriceBook(w http.ResponseWriter, r *http.Request) {
+	var req services.PriceBookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	book, err := h.pricingService.CreatePriceBook(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create price book: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, book)
+}
+
+func (h *PricingHandler) GetPriceBook(w http.ResponseWriter, r *http.Request) {
+	bookID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid price book ID", http.StatusBadRequest)
+		return
+	}
+
+	book, err := h.pricingService.GetPriceBook(r.Context(), bookID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get price book: %v", err), http.StatusNotFound)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, book)
+}
+
+func (h *PricingHandler) UpdatePriceBook(w http.ResponseWriter, r *http.Request) {
+	bookID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid price book ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.PriceBookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	book, err := h.pricingService.UpdatePriceBook(r.Context(), bookID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update price book: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, book)
+}
+
+func (h *PricingHandler) DeletePriceBook(w http.ResponseWriter, r *http.Request) {
+	bookID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid price book ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.pricingService.DeletePriceBook(r.Context(), bookID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete price book: %v", err), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Rules
+
+func (h *PricingHandler) AddPricingRule(w http.ResponseWriter, r *http.Request) {
+	bookID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid price book ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.PricingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.pricingService.AddPricingRule(r.Context(), bookID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to add pricing rule: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, rule)
+}
+
+func (h *PricingHandler) UpdatePricingRule(w http.ResponseWriter, r *http.Request) {
+	ruleID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid pricing rule ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.PricingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.pricingService.UpdatePricingRule(r.Context(), ruleID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update pricing rule: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, rule)
+}
+
+func (h *PricingHandler) DeletePricingRule(w http.ResponseWriter, r *http.Request) {
+	ruleID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid pricing rule ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.pricingService.DeletePricingRule(r.Context(), ruleID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete pricing rule: %v", err), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Calculation
+
+func (h *PricingHandler) CalculatePrice(w http.ResponseWriter, r *http.Request) {
+	var req services.PriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	breakdown, err := h.pricingService.CalculatePrice(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to calculate price: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, breakdown)
+}
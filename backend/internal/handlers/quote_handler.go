@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/google/uuid"
@@ -41,6 +43,14 @@ func (h *QuoteHandler) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc("/quotes/{id}/reject", h.RejectQuote).Methods("POST")
 	router.HandleFunc("/quotes/{id}/convert", h.ConvertQuoteToJob).Methods("POST")
 	
+	// Quote revision routes
+	router.HandleFunc("/quotes/{id}/versions", h.ListQuoteVersions).Methods("GET")
+	router.HandleFunc("/quotes/{id}/versions/compare", h.CompareQuoteVersions).Methods("GET")
+	router.HandleFunc("/quotes/{id}/versions/{version:[0-9]+}", h.GetQuoteVersion).Methods("GET")
+
+	// Quote options
+	router.HandleFunc("/quotes/{id}/pricing", h.GetQuotePricing).Methods("GET")
+	
 	// Quote document and communication routes
 	router.HandleFunc("/quotes/{id}/pdf", h.GenerateQuotePDF).Methods("GET")
 	router.HandleFunc("/quotes/{id}/send", h.SendQuote).Methods("POST")
@@ -224,11 +234,12 @@ func (h *QuoteHandler) ListQuotes(w http.ResponseWriter, r *http.Request) {
 
 // ApproveQuote approves a quote
 // @Summary Approve a quote
-// @Description Approve a quote for conversion to a job
+// @Description Approve a quote for conversion to a job. The body may select one option per group and any add-ons; without a body the default options are approved.
 // @Tags quotes
 // @Accept json
 // @Produce json
 // @Param id path string true "Quote ID"
+// @Param request body services.QuoteSelectionRequest false "Selected options and add-ons"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} domain.ErrorResponse
 // @Failure 404 {object} domain.ErrorResponse
@@ -242,12 +253,25 @@ func (h *QuoteHandler) ApproveQuote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.quoteService.ApproveQuote(r.Context(), quoteID)
+	var selection *services.QuoteSelectionRequest
+	var request services.QuoteSelectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err == nil {
+		selection = &request
+	} else if err != io.EOF {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	err = h.quoteService.ApproveQuoteWithSelection(r.Context(), quoteID, selection)
 	if err != nil {
 		if err.Error() == "quote not found" {
 			h.respondWithError(w, http.StatusNotFound, "Quote not found", nil)
 			return
 		}
+		if strings.HasPrefix(err.Error(), "invalid selection") {
+			h.respondWithError(w, http.StatusBadRequest, "Invalid selection", err)
+			return
+		}
 		h.logger.Error("Failed to approve quote", "error", err, "quote_id", quoteID)
 		h.respondWithError(w, http.StatusInternalServerError, "Failed to approve quote", err)
 		return
@@ -337,6 +361,166 @@ func (h *QuoteHandler) ConvertQuoteToJob(w http.ResponseWriter, r *http.Request)
 	h.respondWithJSON(w, http.StatusCreated, job)
 }
 
+// ListQuoteVersions lists a quote's revisions
+// @Summary List quote revisions
+// @Description List the immutable revisions of a quote, newest first
+// @Tags quotes
+// @Accept json
+// @Produce json
+// @Param id path string true "Quote ID"
+// @Success 200 {array} domain.QuoteVersion
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 404 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Router /quotes/{id}/versions [get]
+func (h *QuoteHandler) ListQuoteVersions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	quoteID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid quote ID", err)
+		return
+	}
+
+	versions, err := h.quoteService.ListQuoteVersions(r.Context(), quoteID)
+	if err != nil {
+		if err.Error() == "quote not found" {
+			h.respondWithError(w, http.StatusNotFound, "Quote not found", nil)
+			return
+		}
+		h.logger.Printf("Failed to list quote versions for quote %s: %v", quoteID, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to list quote versions", err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, versions)
+}
+
+// GetQuoteVersion retrieves a single quote revision
+// @Summary Get a quote revision
+// @Description Retrieve a quote revision by version number
+// @Tags quotes
+// @Accept json
+// @Produce json
+// @Param id path string true "Quote ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} domain.QuoteVersion
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 404 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Router /quotes/{id}/versions/{version} [get]
+func (h *QuoteHandler) GetQuoteVersion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	quoteID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid quote ID", err)
+		return
+	}
+
+	versionNumber, err := strconv.Atoi(vars["version"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid version number", err)
+		return
+	}
+
+	version, err := h.quoteService.GetQuoteVersion(r.Context(), quoteID, versionNumber)
+	if err != nil {
+		if err.Error() == "quote version not found" {
+			h.respondWithError(w, http.StatusNotFound, "Quote version not found", nil)
+			return
+		}
+		h.logger.Printf("Failed to get quote version %d for quote %s: %v", versionNumber, quoteID, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to get quote version", err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, version)
+}
+
+// CompareQuoteVersions diffs two quote revisions
+// @Summary Compare quote revisions
+// @Description Diff two revisions of a quote; omit "to" to compare against unsent changes
+// @Tags quotes
+// @Accept json
+// @Produce json
+// @Param id path string true "Quote ID"
+// @Param from query int true "Version number to compare from"
+// @Param to query int false "Version number to compare to (default: working copy)"
+// @Success 200 {object} services.QuoteVersionDiff
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 404 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Router /quotes/{id}/versions/compare [get]
+func (h *QuoteHandler) CompareQuoteVersions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	quoteID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid quote ID", err)
+		return
+	}
+
+	fromVersion, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil || fromVersion <= 0 {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid from version", err)
+		return
+	}
+
+	toVersion := 0
+	if to := r.URL.Query().Get("to"); to != "" {
+		toVersion, err = strconv.Atoi(to)
+		if err != nil || toVersion <= 0 {
+			h.respondWithError(w, http.StatusBadRequest, "Invalid to version", err)
+			return
+		}
+	}
+
+	diff, err := h.quoteService.CompareQuoteVersions(r.Context(), quoteID, fromVersion, toVersion)
+	if err != nil {
+		if err.Error() == "quote not found" || err.Error() == "quote version not found" {
+			h.respondWithError(w, http.StatusNotFound, "Quote version not found", nil)
+			return
+		}
+		h.logger.Printf("Failed to compare quote versions for quote %s: %v", quoteID, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to compare quote versions", err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, diff)
+}
+
+// GetQuotePricing prices a quote's options and add-ons
+// @Summary Get quote option pricing
+// @Description Price each option and add-on on a quote, with quote totals for each choice
+// @Tags quotes
+// @Accept json
+// @Produce json
+// @Param id path string true "Quote ID"
+// @Success 200 {object} services.QuotePricing
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 404 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Router /quotes/{id}/pricing [get]
+func (h *QuoteHandler) GetQuotePricing(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	quoteID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid quote ID", err)
+		return
+	}
+
+	pricing, err := h.quoteService.GetQuotePricing(r.Context(), quoteID)
+	if err != nil {
+		if err.Error() == "quote not found" {
+			h.respondWithError(w, http.StatusNotFound, "Quote not found", nil)
+			return
+		}
+		h.logger.Printf("Failed to get pricing for quote %s: %v", quoteID, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to get quote pricing", err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, pricing)
+}
+
 // GenerateQuotePDF generates a PDF for a quote
 // @Summary Generate quote PDF
 // @Description Generate a PDF document for a quote
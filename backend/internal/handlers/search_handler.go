@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// SearchHandler handles the global search used by the command palette
+type SearchHandler struct {
+	searchService services.SearchService
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(searchService services.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// SetupSearchRoutes sets up the search routes
+func (h *SearchHandler) SetupSearchRoutes(router *mux.Router) {
+	router.HandleFunc("/search", h.Search).Methods("GET")
+}
+
+// Search searches customers, properties, jobs, quotes and invoices. Query
+// parameters: q, types (comma-separated) and limit.
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	types, err := services.ParseSearchTypes(query.Get("types"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid types: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	req := &services.GlobalSearchRequest{
+		Query: query.Get("q"),
+		Types: types,
+	}
+	req.Limit, _ = strconv.Atoi(query.Get("limit"))
+
+	response, err := h.searchService.Search(r.Context(), req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to search: %v", err), searchErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+func searchErrorStatus(err error) int {
+	if strings.Contains(err.Error(), "validation failed") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// SiteMapHandler handles property site maps and their measured features
+type SiteMapHandler struct {
+	siteMapService services.SiteMapService
+}
+
+// NewSiteMapHandler creates a new site map handler
+func NewSiteMapHandler(siteMapService services.SiteMapService) *SiteMapHandler {
+	return &SiteMapHandler{
+		siteMapService: siteMapService,
+	}
+}
+
+// SetupSiteMapRoutes sets up the site map routes
+func (h *SiteMapHandler) SetupSiteMapRoutes(router *mux.Router) {
+	siteMaps := router.PathPrefix("/site-maps").Subrouter()
+	siteMaps.HandleFunc("/properties/{id}", h.GetSiteMap).Methods("GET")
+	siteMaps.HandleFunc("/properties/{id}/geojson", h.ExportSiteMap).Methods("GET")
+	siteMaps.HandleFunc("/properties/{id}/geojson", h.ImportSiteMap).Methods("PUT")
+	siteMaps.HandleFunc("/properties/{id}/features", h.AddFeature).Methods("POST")
+	siteMaps.HandleFunc("/features/{id}", h.UpdateFeature).Methods("PUT")
+	siteMaps.HandleFunc("/features/{id}", h.DeleteFeature).Methods("DELETE")
+}
+
+func (h *SiteMapHandler) GetSiteMap(w http.ResponseWriter, r *http.Request) {
+	propertyID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid property ID", http.StatusBadRequest)
+		return
+	}
+
+	siteMap, err := h.siteMapService.GetSiteMap(r.Context(), propertyID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get site map: %v", err), siteMapErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, siteMap)
+}
+
+// ExportSiteMap returns the site map as a GeoJSON FeatureCollection
+func (h *SiteMapHandler) ExportSiteMap(w http.ResponseWriter, r *http.Request) {
+	propertyID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid property ID", http.StatusBadRequest)
+		return
+	}
+
+	collection, err := h.siteMapService.ExportSiteMap(r.Context(), propertyID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to export site map: %v", err), siteMapErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, collection)
+}
+
+// ImportSiteMap replaces the site map with a GeoJSON FeatureCollection
+func (h *SiteMapHandler) ImportSiteMap(w http.ResponseWriter, r *http.Request) {
+	propertyID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid property ID", http.StatusBadRequest)
+		return
+	}
+
+	var collection services.GeoJSONFeatureCollection
+	if err := json.NewDecoder(io.LimitReader(r.Body, 10<<20)).Decode(&collection); err != nil { // 10MB limit
+		http.Error(w, "Invalid GeoJSON", http.StatusBadRequest)
+		return
+	}
+
+	siteMap, err := h.siteMapService.ImportSiteMap(r.Context(), propertyID, &collection)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to import site map: %v", err), siteMapErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, siteMap)
+}
+
+func (h *SiteMapHandler) AddFeature(w http.ResponseWriter, r *http.Request) {
+	propertyID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid property ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.PropertyFeatureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	feature, err := h.siteMapService.AddFeature(r.Context(), propertyID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to add feature: %v", err), siteMapErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, feature)
+}
+
+func (h *SiteMapHandler) UpdateFeature(w http.ResponseWriter, r *http.Request) {
+	featureID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid feature ID", http.StatusBadRequest)
+		return
+	}
+
+	var req services.PropertyFeatureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	feature, err := h.siteMapService.UpdateFeature(r.Context(), featureID, &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update feature: %v", err), siteMapErrorStatus(err))
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, feature)
+}
+
+func (h *SiteMapHandler) DeleteFeature(w http.ResponseWriter, r *http.Request) {
+	featureID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid feature ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.siteMapService.DeleteFeature(r.Context(), featureID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete feature: %v", err), siteMapErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func siteMapErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "validation failed"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// StatementHandler handles customer statement and consolidated invoicing operations
+type StatementHandler struct {
+	statementService services.StatementService
+}
+
+// NewStatementHandler creates a new statement handler
+func NewStatementHandler(statementService services.StatementService) *StatementHandler {
+	return &StatementHandler{
+		statementService: statementService,
+	}
+}
+
+// SetupStatementRoutes sets up statement routes
+func (h *StatementHandler) SetupStatementRoutes(router *mux.Router) {
+	statements := router.PathPrefix("/statements").Subrouter()
+
+	// Statement runs
+	runs := statements.PathPrefix("/runs").Subrouter()
+	runs.HandleFunc("", h.ListStatementRuns).Methods("GET")
+	runs.HandleFunc("", h.RunStatements).Methods("POST")
+	runs.HandleFunc("/preview", h.PreviewStatementRun).Methods("POST")
+	runs.HandleFunc("/{id}", h.GetStatementRun).Methods("GET")
+
+	// Consolidated invoices
+	statements.HandleFunc("/invoices/{id}", h.GetConsolidatedInvoice).Methods("GET")
+	statements.HandleFunc("/invoices/{id}/pdf", h.GetConsolidatedInvoicePDF).Methods("GET")
+
+	// Aging statements
+	customers := statements.PathPrefix("/customers").Subrouter()
+	customers.HandleFunc("/{id}/aging", h.GetAgingStatement).Methods("GET")
+	customers.HandleFunc("/{id}/aging/pdf", h.GetAgingStatementPDF).Methods("GET")
+	customers.HandleFunc("/{id}/send", h.SendAgingStatement).Methods("POST")
+
+	// Schedule
+	statements.HandleFunc("/schedule", h.GetStatementSchedule).Methods("GET")
+	statements.HandleFunc("/schedule", h.UpdateStatementSchedule).Methods("PUT")
+}
+
+// Statement Runs
+
+func (h *StatementHandler) ListStatementRuns(w http.ResponseWriter, r *http.Request) {
+	filter := &services.StatementRunFilter{
+		BaseFilter: services.BaseFilter{
+			Page:    getIntQueryParam(r, "page", 1),
+			PerPage: getIntQueryParam(r, "per_page", 25),
+		},
+		Status: r.URL.Query().Get("status"),
+	}
+
+	runs, err := h.statementService.ListStatementRuns(r.Context(), filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list statement runs: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, runs)
+}
+
+func (h *StatementHandler) RunStatements(w http.ResponseWriter, r *http.Request) {
+	var req services.StatementRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	run, err := h.statementService.RunStatements(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to run statements: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, run)
+}
+
+func (h *StatementHandler) PreviewStatementRun(w http.ResponseWriter, r *http.Request) {
+	var req services.StatementRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	preview, err := h.statementService.PreviewStatementRun(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to preview statement run: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, preview)
+}
+
+func (h *StatementHandler) GetStatementRun(w http.ResponseWriter, r *http.Request) {
+	runID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid statement run ID", http.StatusBadRequest)
+		return
+	}
+
+	run, err := h.statementService.GetStatementRun(r.Context(), runID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get statement run: %v", err), http.StatusNotFound)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, run)
+}
+
+// Consolidated Invoices
+
+func (h *StatementHandler) GetConsolidatedInvoice(w http.ResponseWriter, r *http.Request) {
+	invoiceID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid invoice ID", http.StatusBadRequest)
+		return
+	}
+
+	invoice, err := h.statementService.GetConsolidatedInvoice(r.Context(), invoiceID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get consolidated invoice: %v", err), http.StatusNotFound)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, invoice)
+}
+
+func (h *StatementHandler) GetConsolidatedInvoicePDF(w http.ResponseWriter, r *http.Request) {
+	invoiceID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid invoice ID", http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.statementService.GenerateConsolidatedInvoicePDF(r.Context(), invoiceID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to generate invoice: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=invoice_%s.pdf", invoiceID))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// Aging Statements
+
+func (h *StatementHandler) GetAgingStatement(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	asOf, err := parseAsOfParam(r)
+	if err != nil {
+		http.Error(w, "Invalid as_of date", http.StatusBadRequest)
+		return
+	}
+
+	statement, err := h.statementService.GetAgingStatement(r.Context(), customerID, asOf)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get aging statement: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, statement)
+}
+
+func (h *StatementHandler) GetAgingStatementPDF(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	asOf, err := parseAsOfParam(r)
+	if err != nil {
+		http.Error(w, "Invalid as_of date", http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.statementService.GenerateAgingStatementPDF(r.Context(), customerID, asOf)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to generate statement: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=statement_%s.pdf", asOf.Format("2006-01-02")))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *StatementHandler) SendAgingStatement(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.statementService.SendAgingStatement(r.Context(), customerID); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to send statement: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Statement sent"})
+}
+
+// Schedule
+
+func (h *StatementHandler) GetStatementSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, err := h.statementService.GetStatementSchedule(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get statement schedule: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, schedule)
+}
+
+func (h *StatementHandler) UpdateStatementSchedule(w http.ResponseWriter, r *http.Request) {
+	var req services.StatementScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	schedule, err := h.statementService.UpdateStatementSchedule(r.Context(), &req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to update statement schedule: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, schedule)
+}
+
+// parseAsOfParam reads an optional as_of=YYYY-MM-DD query parameter, defaulting to now
+func parseAsOfParam(r *http.Request) (time.Time, error) {
+	value := r.URL.Query().Get("as_of")
+	if value == "" {
+		return time.Now(), nil
+	}
+	return time.Parse("2006-01-02", value)
+}
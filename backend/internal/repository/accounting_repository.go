@@ -0,0 +1,551 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// AccountingRepositoryImpl implements the accounting repository interface
+type AccountingRepositoryImpl struct {
+	db *Database
+}
+
+// NewAccountingRepository creates a new accounting repository instance
+func NewAccountingRepository(db *Database) services.AccountingRepository {
+	return &AccountingRepositoryImpl{db: db}
+}
+
+// accountingEntitySources describes the table and eligibility condition of each synced entity
+var accountingEntitySources = map[string]struct {
+	table     string
+	condition string
+}{
+	domain.AccountingEntityCustomer: {"customers", "e.status != 'deleted'"},
+	domain.AccountingEntityService:  {"services", "e.status != 'deleted'"},
+	// Cancelled invoices are only pushed when they were synced before, so they can be voided
+	domain.AccountingEntityInvoice: {"invoices", "e.status NOT IN ('draft', 'deleted') AND NOT (e.status = 'cancelled' AND sr.id IS NULL)"},
+	domain.AccountingEntityPayment: {"payments", "e.status = 'completed'"},
+}
+
+const accountConnectionColumns = `
+	id, tenant_id, provider, external_tenant_id, access_token, refresh_token, token_expires_at,
+	status, auto_sync, last_sync_at, last_error, created_at, updated_at`
+
+const accountingSyncRecordColumns = `
+	id, tenant_id, provider, entity_type, entity_id, external_id, status, source_updated_at,
+	attempts, last_error, synced_at, created_at, updated_at`
+
+// ListMappings lists a tenant's chart-of-accounts mappings
+func (r *AccountingRepositoryImpl) ListMappings(ctx context.Context, tenantID uuid.UUID) ([]*domain.AccountMapping, error) {
+	query := `
+		SELECT id, tenant_id, mapping_type, mapping_key, account_code, account_name, account_type,
+			   created_at, updated_at
+		FROM accounting_account_mappings
+		WHERE tenant_id = $1
+		ORDER BY mapping_type, mapping_key`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list account mappings: %w", err)
+	}
+	defer rows.Close()
+
+	var mappings []*domain.AccountMapping
+	for rows.Next() {
+		mapping, err := scanAccountMapping(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan account mapping: %w", err)
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate account mappings: %w", err)
+	}
+
+	return mappings, nil
+}
+
+// GetMapping retrieves an account mapping by ID
+func (r *AccountingRepositoryImpl) GetMapping(ctx context.Context, tenantID, mappingID uuid.UUID) (*domain.AccountMapping, error) {
+	query := `
+		SELECT id, tenant_id, mapping_type, mapping_key, account_code, account_name, account_type,
+			   created_at, updated_at
+		FROM accounting_account_mappings
+		WHERE id = $1 AND tenant_id = $2`
+
+	mapping, err := scanAccountMapping(r.db.QueryRowContext(ctx, query, mappingID, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get account mapping: %w", err)
+	}
+
+	return mapping, nil
+}
+
+// UpsertMapping creates or replaces the mapping for a type and key
+func (r *AccountingRepositoryImpl) UpsertMapping(ctx context.Context, mapping *domain.AccountMapping) error {
+	query := `
+		INSERT INTO accounting_account_mappings (
+			id, tenant_id, mapping_type, mapping_key, account_code, account_name, account_type,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (tenant_id, mapping_type, mapping_key) DO UPDATE SET
+			account_code = EXCLUDED.account_code,
+			account_name = EXCLUDED.account_name,
+			account_type = EXCLUDED.account_type,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		mapping.ID,
+		mapping.TenantID,
+		mapping.MappingType,
+		mapping.MappingKey,
+		mapping.AccountCode,
+		mapping.AccountName,
+		mapping.AccountType,
+		mapping.CreatedAt,
+		mapping.UpdatedAt,
+	).Scan(&mapping.ID, &mapping.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert account mapping: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMapping removes an account mapping
+func (r *AccountingRepositoryImpl) DeleteMapping(ctx context.Context, tenantID, mappingID uuid.UUID) error {
+	query := `DELETE FROM accounting_account_mappings WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, mappingID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete account mapping: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("account mapping not found")
+	}
+
+	return nil
+}
+
+// GetInvoicesForPeriod retrieves issued invoices dated within a period
+func (r *AccountingRepositoryImpl) GetInvoicesForPeriod(ctx context.Context, tenantID uuid.UUID, start, end time.Time) ([]*domain.Invoice, error) {
+	query := `
+		SELECT id, tenant_id, customer_id, job_id, invoice_number, status,
+			   subtotal, tax_rate, tax_amount, total_amount, issued_date, due_date,
+			   paid_date, notes, created_at, updated_at
+		FROM invoices
+		WHERE tenant_id = $1
+		  AND status NOT IN ('draft', 'cancelled', 'deleted')
+		  AND COALESCE(issued_date, created_at) >= $2
+		  AND COALESCE(issued_date, created_at) < $3
+		ORDER BY COALESCE(issued_date, created_at), invoice_number`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, start, end.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoices for period: %w", err)
+	}
+	defer rows.Close()
+
+	var invoices []*domain.Invoice
+	for rows.Next() {
+		var invoice domain.Invoice
+		if err := rows.Scan(
+			&invoice.ID,
+			&invoice.TenantID,
+			&invoice.CustomerID,
+			&invoice.JobID,
+			&invoice.InvoiceNumber,
+			&invoice.Status,
+			&invoice.Subtotal,
+			&invoice.TaxRate,
+			&invoice.TaxAmount,
+			&invoice.TotalAmount,
+			&invoice.IssuedDate,
+			&invoice.DueDate,
+			&invoice.PaidDate,
+			&invoice.Notes,
+			&invoice.CreatedAt,
+			&invoice.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice: %w", err)
+		}
+		invoices = append(invoices, &invoice)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate invoices: %w", err)
+	}
+
+	return invoices, nil
+}
+
+// GetPaymentsForPeriod retrieves completed payments processed within a period
+func (r *AccountingRepositoryImpl) GetPaymentsForPeriod(ctx context.Context, tenantID uuid.UUID, start, end time.Time) ([]*domain.Payment, error) {
+	query := `
+		SELECT id, tenant_id, invoice_id, amount, payment_method, payment_gateway,
+			   gateway_transaction_id, status, processed_at, notes, created_at, updated_at
+		FROM payments
+		WHERE tenant_id = $1
+		  AND status = 'completed'
+		  AND COALESCE(processed_at, created_at) >= $2
+		  AND COALESCE(processed_at, created_at) < $3
+		ORDER BY COALESCE(processed_at, created_at)`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, start, end.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments for period: %w", err)
+	}
+	defer rows.Close()
+
+	var payments []*domain.Payment
+	for rows.Next() {
+		var payment domain.Payment
+		if err := rows.Scan(
+			&payment.ID,
+			&payment.TenantID,
+			&payment.InvoiceID,
+			&payment.Amount,
+			&payment.PaymentMethod,
+			&payment.PaymentGateway,
+			&payment.GatewayTransactionID,
+			&payment.Status,
+			&payment.ProcessedAt,
+			&payment.Notes,
+			&payment.CreatedAt,
+			&payment.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan payment: %w", err)
+		}
+		payments = append(payments, &payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate payments: %w", err)
+	}
+
+	return payments, nil
+}
+
+// GetConnection retrieves a tenant's connection to a provider
+func (r *AccountingRepositoryImpl) GetConnection(ctx context.Context, tenantID uuid.UUID, provider string) (*domain.AccountingConnection, error) {
+	query := `SELECT` + accountConnectionColumns + ` FROM accounting_connections WHERE tenant_id = $1 AND provider = $2`
+
+	connection, err := scanAccountingConnection(r.db.QueryRowContext(ctx, query, tenantID, provider))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get accounting connection: %w", err)
+	}
+
+	return connection, nil
+}
+
+// ListConnections lists a tenant's accounting connections
+func (r *AccountingRepositoryImpl) ListConnections(ctx context.Context, tenantID uuid.UUID) ([]*domain.AccountingConnection, error) {
+	query := `SELECT` + accountConnectionColumns + ` FROM accounting_connections WHERE tenant_id = $1 ORDER BY provider`
+	return r.queryConnections(ctx, query, tenantID)
+}
+
+// ListAutoSyncConnections lists connected providers with automatic sync enabled across all tenants
+func (r *AccountingRepositoryImpl) ListAutoSyncConnections(ctx context.Context) ([]*domain.AccountingConnection, error) {
+	query := `SELECT` + accountConnectionColumns + `
+		FROM accounting_connections
+		WHERE auto_sync = TRUE AND status != 'disconnected'`
+	return r.queryConnections(ctx, query)
+}
+
+// UpsertConnection creates or updates a provider connection
+func (r *AccountingRepositoryImpl) UpsertConnection(ctx context.Context, connection *domain.AccountingConnection) error {
+	query := `
+		INSERT INTO accounting_connections (` + accountConnectionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (tenant_id, provider) DO UPDATE SET
+			external_tenant_id = EXCLUDED.external_tenant_id,
+			access_token = EXCLUDED.access_token,
+			refresh_token = EXCLUDED.refresh_token,
+			token_expires_at = EXCLUDED.token_expires_at,
+			status = EXCLUDED.status,
+			auto_sync = EXCLUDED.auto_sync,
+			last_sync_at = EXCLUDED.last_sync_at,
+			last_error = EXCLUDED.last_error,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.ExecContext(ctx, query,
+		connection.ID,
+		connection.TenantID,
+		connection.Provider,
+		connection.ExternalTenantID,
+		connection.AccessToken,
+		connection.RefreshToken,
+		connection.TokenExpiresAt,
+		connection.Status,
+		connection.AutoSync,
+		connection.LastSyncAt,
+		connection.LastError,
+		connection.CreatedAt,
+		connection.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert accounting connection: %w", err)
+	}
+
+	return nil
+}
+
+// GetPendingChanges returns IDs of records never pushed to the provider, changed since their
+// last push, or whose last push failed and may be retried
+func (r *AccountingRepositoryImpl) GetPendingChanges(ctx context.Context, tenantID uuid.UUID, provider, entityType string, maxAttempts, limit int) ([]uuid.UUID, error) {
+	source, ok := accountingEntitySources[entityType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported entity type: %s", entityType)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT e.id
+		FROM %s e
+		LEFT JOIN accounting_sync_records sr
+			ON sr.tenant_id = e.tenant_id AND sr.provider = $2 AND sr.entity_type = $3 AND sr.entity_id = e.id
+		WHERE e.tenant_id = $1 AND %s
+		  AND (
+			sr.id IS NULL
+			OR e.updated_at > sr.source_updated_at
+			OR (sr.status = 'failed' AND sr.attempts < $4)
+		  )
+		ORDER BY e.updated_at
+		LIMIT $5`, source.table, source.condition)
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, provider, entityType, maxAttempts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending changes: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan pending change: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate pending changes: %w", err)
+	}
+
+	return ids, nil
+}
+
+// GetSyncRecord retrieves the sync record of an entity for a provider
+func (r *AccountingRepositoryImpl) GetSyncRecord(ctx context.Context, tenantID uuid.UUID, provider, entityType string, entityID uuid.UUID) (*domain.AccountingSyncRecord, error) {
+	query := `SELECT` + accountingSyncRecordColumns + `
+		FROM accounting_sync_records
+		WHERE tenant_id = $1 AND provider = $2 AND entity_type = $3 AND entity_id = $4`
+
+	record, err := scanAccountingSyncRecord(r.db.QueryRowContext(ctx, query, tenantID, provider, entityType, entityID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get sync record: %w", err)
+	}
+
+	return record, nil
+}
+
+// UpsertSyncRecord creates or updates an entity's sync record
+func (r *AccountingRepositoryImpl) UpsertSyncRecord(ctx context.Context, record *domain.AccountingSyncRecord) error {
+	query := `
+		INSERT INTO accounting_sync_records (` + accountingSyncRecordColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (tenant_id, provider, entity_type, entity_id) DO UPDATE SET
+			external_id = EXCLUDED.external_id,
+			status = EXCLUDED.status,
+			source_updated_at = EXCLUDED.source_updated_at,
+			attempts = EXCLUDED.attempts,
+			last_error = EXCLUDED.last_error,
+			synced_at = EXCLUDED.synced_at,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.ExecContext(ctx, query,
+		record.ID,
+		record.TenantID,
+		record.Provider,
+		record.EntityType,
+		record.EntityID,
+		record.ExternalID,
+		record.Status,
+		record.SourceUpdatedAt,
+		record.Attempts,
+		record.LastError,
+		record.SyncedAt,
+		record.CreatedAt,
+		record.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert sync record: %w", err)
+	}
+
+	return nil
+}
+
+// ListSyncRecords lists sync records with filtering and pagination
+func (r *AccountingRepositoryImpl) ListSyncRecords(ctx context.Context, tenantID uuid.UUID, filter *services.AccountingSyncRecordFilter) ([]*domain.AccountingSyncRecord, int64, error) {
+	whereClause := " FROM accounting_sync_records WHERE tenant_id = $1"
+	args := []interface{}{tenantID}
+	argIndex := 2
+
+	if filter.Provider != "" {
+		whereClause += fmt.Sprintf(" AND provider = $%d", argIndex)
+		args = append(args, filter.Provider)
+		argIndex++
+	}
+	if filter.EntityType != "" {
+		whereClause += fmt.Sprintf(" AND entity_type = $%d", argIndex)
+		args = append(args, filter.EntityType)
+		argIndex++
+	}
+	if filter.Status != "" {
+		whereClause += fmt.Sprintf(" AND status = $%d", argIndex)
+		args = append(args, filter.Status)
+		argIndex++
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*)"+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count sync records: %w", err)
+	}
+
+	query := "SELECT" + accountingSyncRecordColumns + whereClause +
+		fmt.Sprintf(" ORDER BY updated_at DESC LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list sync records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*domain.AccountingSyncRecord
+	for rows.Next() {
+		record, err := scanAccountingSyncRecord(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan sync record: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate sync records: %w", err)
+	}
+
+	return records, total, nil
+}
+
+// Helper functions
+
+func (r *AccountingRepositoryImpl) queryConnections(ctx context.Context, query string, args ...interface{}) ([]*domain.AccountingConnection, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounting connections: %w", err)
+	}
+	defer rows.Close()
+
+	var connections []*domain.AccountingConnection
+	for rows.Next() {
+		connection, err := scanAccountingConnection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan accounting connection: %w", err)
+		}
+		connections = append(connections, connection)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate accounting connections: %w", err)
+	}
+
+	return connections, nil
+}
+
+func scanAccountMapping(row rowScanner) (*domain.AccountMapping, error) {
+	var mapping domain.AccountMapping
+	if err := row.Scan(
+		&mapping.ID,
+		&mapping.TenantID,
+		&mapping.MappingType,
+		&mapping.MappingKey,
+		&mapping.AccountCode,
+		&mapping.AccountName,
+		&mapping.AccountType,
+		&mapping.CreatedAt,
+		&mapping.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+func scanAccountingConnection(row rowScanner) (*domain.AccountingConnection, error) {
+	var connection domain.AccountingConnection
+	if err := row.Scan(
+		&connection.ID,
+		&connection.TenantID,
+		&connection.Provider,
+		&connection.ExternalTenantID,
+		&connection.AccessToken,
+		&connection.RefreshToken,
+		&connection.TokenExpiresAt,
+		&connection.Status,
+		&connection.AutoSync,
+		&connection.LastSyncAt,
+		&connection.LastError,
+		&connection.CreatedAt,
+		&connection.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &connection, nil
+}
+
+func scanAccountingSyncRecord(row rowScanner) (*domain.AccountingSyncRecord, error) {
+	var record domain.AccountingSyncRecord
+	if err := row.Scan(
+		&record.ID,
+		&record.TenantID,
+		&record.Provider,
+		&record.EntityType,
+		&record.EntityID,
+		&record.ExternalID,
+		&record.Status,
+		&record.SourceUpdatedAt,
+		&record.Attempts,
+		&record.LastError,
+		&record.SyncedAt,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
@@ -0,0 +1,353 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// AvailabilityRepositoryImpl implements the availability repository interface
+type AvailabilityRepositoryImpl struct {
+	db *Database
+}
+
+// NewAvailabilityRepository creates a new availability repository instance
+func NewAvailabilityRepository(db *Database) services.AvailabilityRepository {
+	return &AvailabilityRepositoryImpl{db: db}
+}
+
+const workingHoursColumns = `
+	id, tenant_id, user_id, weekday, start_time, end_time,
+	effective_from, effective_to, created_at, updated_at`
+
+const timeOffColumns = `
+	id, tenant_id, user_id, type, starts_at, ends_at, reason, status,
+	requested_by, reviewed_by, reviewed_at, review_notes, created_at, updated_at`
+
+const holidayColumns = `id, tenant_id, date, name, created_by, created_at`
+
+// ListWorkingHours lists the users' working-hour templates
+func (r *AvailabilityRepositoryImpl) ListWorkingHours(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) ([]*domain.WorkingHours, error) {
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT ` + workingHoursColumns + `
+		FROM user_working_hours
+		WHERE tenant_id = $1 AND user_id = ANY($2::uuid[])
+		ORDER BY user_id, weekday, start_time`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list working hours: %w", err)
+	}
+	defer rows.Close()
+
+	hours := []*domain.WorkingHours{}
+	for rows.Next() {
+		var shift domain.WorkingHours
+		if err := rows.Scan(
+			&shift.ID,
+			&shift.TenantID,
+			&shift.UserID,
+			&shift.Weekday,
+			&shift.StartTime,
+			&shift.EndTime,
+			&shift.EffectiveFrom,
+			&shift.EffectiveTo,
+			&shift.CreatedAt,
+			&shift.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan working hours: %w", err)
+		}
+		hours = append(hours, &shift)
+	}
+
+	return hours, rows.Err()
+}
+
+// ReplaceWorkingHours swaps a user's working-hour template for a new set
+func (r *AvailabilityRepositoryImpl) ReplaceWorkingHours(ctx context.Context, tenantID, userID uuid.UUID, hours []*domain.WorkingHours) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_working_hours WHERE tenant_id = $1 AND user_id = $2`, tenantID, userID); err != nil {
+		return fmt.Errorf("failed to clear working hours: %w", err)
+	}
+
+	query := `
+		INSERT INTO user_working_hours (` + workingHoursColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	for _, shift := range hours {
+		if _, err := tx.ExecContext(ctx, query,
+			shift.ID,
+			shift.TenantID,
+			shift.UserID,
+			shift.Weekday,
+			shift.StartTime,
+			shift.EndTime,
+			shift.EffectiveFrom,
+			shift.EffectiveTo,
+			shift.CreatedAt,
+			shift.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to create working hours: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTimeOff stores a time off request
+func (r *AvailabilityRepositoryImpl) CreateTimeOff(ctx context.Context, request *domain.TimeOffRequest) error {
+	query := `
+		INSERT INTO time_off_requests (` + timeOffColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		request.ID,
+		request.TenantID,
+		request.UserID,
+		request.Type,
+		request.StartsAt,
+		request.EndsAt,
+		request.Reason,
+		request.Status,
+		request.RequestedBy,
+		request.ReviewedBy,
+		request.ReviewedAt,
+		request.ReviewNotes,
+		request.CreatedAt,
+		request.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create time off request: %w", err)
+	}
+
+	return nil
+}
+
+// GetTimeOff retrieves a time off request by ID
+func (r *AvailabilityRepositoryImpl) GetTimeOff(ctx context.Context, tenantID, requestID uuid.UUID) (*domain.TimeOffRequest, error) {
+	query := `
+		SELECT ` + timeOffColumns + `
+		FROM time_off_requests
+		WHERE tenant_id = $1 AND id = $2`
+
+	request, err := scanTimeOffRequest(r.db.QueryRowContext(ctx, query, tenantID, requestID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get time off request: %w", err)
+	}
+
+	return request, nil
+}
+
+// UpdateTimeOff updates a time off request's status and review
+func (r *AvailabilityRepositoryImpl) UpdateTimeOff(ctx context.Context, request *domain.TimeOffRequest) error {
+	query := `
+		UPDATE time_off_requests SET
+			status = $3, reviewed_by = $4, reviewed_at = $5, review_notes = $6, updated_at = $7
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		request.TenantID,
+		request.ID,
+		request.Status,
+		request.ReviewedBy,
+		request.ReviewedAt,
+		request.ReviewNotes,
+		request.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update time off request: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("time off request not found")
+	}
+
+	return nil
+}
+
+// ListTimeOff lists time off requests, latest first
+func (r *AvailabilityRepositoryImpl) ListTimeOff(ctx context.Context, tenantID uuid.UUID, filter *services.TimeOffFilter) ([]*domain.TimeOffRequest, error) {
+	query := `
+		SELECT ` + timeOffColumns + `
+		FROM time_off_requests
+		WHERE tenant_id = $1`
+	args := []interface{}{tenantID}
+
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		query += fmt.Sprintf(" AND user_id = $%d", len(args))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		query += fmt.Sprintf(" AND ends_at > $%d", len(args))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		query += fmt.Sprintf(" AND starts_at < $%d", len(args))
+	}
+	query += " ORDER BY starts_at DESC"
+
+	return r.listTimeOff(ctx, query, args...)
+}
+
+// ListApprovedTimeOff lists the users' approved time off overlapping the range
+func (r *AvailabilityRepositoryImpl) ListApprovedTimeOff(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID, start, end time.Time) ([]*domain.TimeOffRequest, error) {
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT ` + timeOffColumns + `
+		FROM time_off_requests
+		WHERE tenant_id = $1 AND user_id = ANY($2::uuid[]) AND status = 'approved'
+			AND starts_at < $4 AND ends_at > $3
+		ORDER BY starts_at`
+
+	return r.listTimeOff(ctx, query, tenantID, pq.Array(ids), start, end)
+}
+
+// CreateHoliday stores a tenant holiday
+func (r *AvailabilityRepositoryImpl) CreateHoliday(ctx context.Context, holiday *domain.TenantHoliday) error {
+	query := `
+		INSERT INTO tenant_holidays (` + holidayColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		holiday.ID,
+		holiday.TenantID,
+		holiday.Date,
+		holiday.Name,
+		holiday.CreatedBy,
+		holiday.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create holiday: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteHoliday deletes a tenant holiday
+func (r *AvailabilityRepositoryImpl) DeleteHoliday(ctx context.Context, tenantID, holidayID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tenant_holidays WHERE tenant_id = $1 AND id = $2`, tenantID, holidayID)
+	if err != nil {
+		return fmt.Errorf("failed to delete holiday: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("holiday not found")
+	}
+
+	return nil
+}
+
+// ListHolidays lists the tenant's holidays falling in the range
+func (r *AvailabilityRepositoryImpl) ListHolidays(ctx context.Context, tenantID uuid.UUID, start, end time.Time) ([]*domain.TenantHoliday, error) {
+	query := `
+		SELECT ` + holidayColumns + `
+		FROM tenant_holidays
+		WHERE tenant_id = $1 AND date >= $2::date AND date < $3
+		ORDER BY date`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holidays: %w", err)
+	}
+	defer rows.Close()
+
+	holidays := []*domain.TenantHoliday{}
+	for rows.Next() {
+		var holiday domain.TenantHoliday
+		if err := rows.Scan(
+			&holiday.ID,
+			&holiday.TenantID,
+			&holiday.Date,
+			&holiday.Name,
+			&holiday.CreatedBy,
+			&holiday.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan holiday: %w", err)
+		}
+		holidays = append(holidays, &holiday)
+	}
+
+	return holidays, rows.Err()
+}
+
+func (r *AvailabilityRepositoryImpl) listTimeOff(ctx context.Context, query string, args ...interface{}) ([]*domain.TimeOffRequest, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time off requests: %w", err)
+	}
+	defer rows.Close()
+
+	requests := []*domain.TimeOffRequest{}
+	for rows.Next() {
+		request, err := scanTimeOffRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time off request: %w", err)
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, rows.Err()
+}
+
+func scanTimeOffRequest(row rowScanner) (*domain.TimeOffRequest, error) {
+	var request domain.TimeOffRequest
+	if err := row.Scan(
+		&request.ID,
+		&request.TenantID,
+		&request.UserID,
+		&request.Type,
+		&request.StartsAt,
+		&request.EndsAt,
+		&request.Reason,
+		&request.Status,
+		&request.RequestedBy,
+		&request.ReviewedBy,
+		&request.ReviewedAt,
+		&request.ReviewNotes,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// BookingRepositoryImpl implements the online booking repository interface
+type BookingRepositoryImpl struct {
+	db *Database
+}
+
+// NewBookingRepository creates a new booking repository instance
+func NewBookingRepository(db *Database) services.BookingRepository {
+	return &BookingRepositoryImpl{db: db}
+}
+
+const bookingSettingsColumns = `
+	tenant_id, enabled, timezone, workday_start, workday_end, working_days,
+	arrival_window_minutes, default_duration_minutes, lead_time_hours, max_days_ahead,
+	hold_minutes, max_jobs_per_crew_day, travel_speed_mph, default_travel_minutes,
+	allowed_origins, created_at, updated_at`
+
+const bookingBlackoutColumns = `
+	id, tenant_id, date, crew_id, reason, created_by, created_at`
+
+const bookingHoldColumns = `
+	id, tenant_id, crew_id, window_start, window_end, job_start, job_end, service_id,
+	service_key, duration_minutes, latitude, longitude, token, status, expires_at,
+	lead_id, job_id, ip_address, created_at, updated_at`
+
+// GetSettings retrieves a tenant's booking settings
+func (r *BookingRepositoryImpl) GetSettings(ctx context.Context, tenantID uuid.UUID) (*domain.BookingSettings, error) {
+	query := `SELECT ` + bookingSettingsColumns + ` FROM booking_settings WHERE tenant_id = $1`
+
+	var settings domain.BookingSettings
+	var workingDays pq.Int64Array
+	var allowedOrigins pq.StringArray
+	err := r.db.QueryRowContext(ctx, query, tenantID).Scan(
+		&settings.TenantID,
+		&settings.Enabled,
+		&settings.Timezone,
+		&settings.WorkdayStart,
+		&settings.WorkdayEnd,
+		&workingDays,
+		&settings.ArrivalWindowMinutes,
+		&settings.DefaultDurationMinutes,
+		&settings.LeadTimeHours,
+		&settings.MaxDaysAhead,
+		&settings.HoldMinutes,
+		&settings.MaxJobsPerCrewDay,
+		&settings.TravelSpeedMph,
+		&settings.DefaultTravelMinutes,
+		&allowedOrigins,
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get booking settings: %w", err)
+	}
+
+	settings.WorkingDays = make([]int, 0, len(workingDays))
+	for _, day := range workingDays {
+		settings.WorkingDays = append(settings.WorkingDays, int(day))
+	}
+	settings.AllowedOrigins = []string(allowedOrigins)
+
+	return &settings, nil
+}
+
+// UpsertSettings creates or replaces a tenant's booking settings
+func (r *BookingRepositoryImpl) UpsertSettings(ctx context.Context, settings *domain.BookingSettings) error {
+	query := `
+		INSERT INTO booking_settings (` + bookingSettingsColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			timezone = EXCLUDED.timezone,
+			workday_start = EXCLUDED.workday_start,
+			workday_end = EXCLUDED.workday_end,
+			working_days = EXCLUDED.working_days,
+			arrival_window_minutes = EXCLUDED.arrival_window_minutes,
+			default_duration_minutes = EXCLUDED.default_duration_minutes,
+			lead_time_hours = EXCLUDED.lead_time_hours,
+			max_days_ahead = EXCLUDED.max_days_ahead,
+			hold_minutes = EXCLUDED.hold_minutes,
+			max_jobs_per_crew_day = EXCLUDED.max_jobs_per_crew_day,
+			travel_speed_mph = EXCLUDED.travel_speed_mph,
+			default_travel_minutes = EXCLUDED.default_travel_minutes,
+			allowed_origins = EXCLUDED.allowed_origins,
+			updated_at = EXCLUDED.updated_at`
+
+	workingDays := make([]int64, 0, len(settings.WorkingDays))
+	for _, day := range settings.WorkingDays {
+		workingDays = append(workingDays, int64(day))
+	}
+
+	_, err := r.db.ExecContext(ctx, query,
+		settings.TenantID,
+		settings.Enabled,
+		settings.Timezone,
+		settings.WorkdayStart,
+		settings.WorkdayEnd,
+		pq.Array(workingDays),
+		settings.ArrivalWindowMinutes,
+		settings.DefaultDurationMinutes,
+		settings.LeadTimeHours,
+		settings.MaxDaysAhead,
+		settings.HoldMinutes,
+		settings.MaxJobsPerCrewDay,
+		settings.TravelSpeedMph,
+		settings.DefaultTravelMinutes,
+		pq.Array(settings.AllowedOrigins),
+		settings.CreatedAt,
+		settings.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save booking settings: %w", err)
+	}
+
+	return nil
+}
+
+// ListBlackouts lists blackout days from from (inclusive) to to (exclusive)
+func (r *BookingRepositoryImpl) ListBlackouts(ctx context.Context, tenantID uuid.UUID, from, to time.Time) ([]*domain.BookingBlackout, error) {
+	query := `
+		SELECT ` + bookingBlackoutColumns + ` FROM booking_blackouts
+		WHERE tenant_id = $1 AND date >= $2::date AND date < $3::date
+		ORDER BY date, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blackout days: %w", err)
+	}
+	defer rows.Close()
+
+	var blackouts []*domain.BookingBlackout
+	for rows.Next() {
+		var blackout domain.BookingBlackout
+		if err := rows.Scan(
+			&blackout.ID,
+			&blackout.TenantID,
+			&blackout.Date,
+			&blackout.CrewID,
+			&blackout.Reason,
+			&blackout.CreatedBy,
+			&blackout.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan blackout day: %w", err)
+		}
+		blackouts = append(blackouts, &blackout)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate blackout days: %w", err)
+	}
+
+	return blackouts, nil
+}
+
+// CreateBlackout stores a blackout day
+func (r *BookingRepositoryImpl) CreateBlackout(ctx context.Context, blackout *domain.BookingBlackout) error {
+	query := `
+		INSERT INTO booking_blackouts (` + bookingBlackoutColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		blackout.ID,
+		blackout.TenantID,
+		blackout.Date.Format("2006-01-02"),
+		blackout.CrewID,
+		blackout.Reason,
+		blackout.CreatedBy,
+		blackout.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create blackout day: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBlackout removes a blackout day
+func (r *BookingRepositoryImpl) DeleteBlackout(ctx context.Context, tenantID, blackoutID uuid.UUID) error {
+	query := `DELETE FROM booking_blackouts WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, tenantID, blackoutID)
+	if err != nil {
+		return fmt.Errorf("failed to delete blackout day: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("blackout day not found")
+	}
+
+	return nil
+}
+
+// CreateHold stores a hold unless an active hold for the same crew overlaps it. The
+// check and insert are a single statement so two visitors can't take the same crew.
+func (r *BookingRepositoryImpl) CreateHold(ctx context.Context, hold *domain.BookingHold, now time.Time) (bool, error) {
+	query := `
+		INSERT INTO booking_holds (` + bookingHoldColumns + `)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
+		WHERE NOT EXISTS (
+			SELECT 1 FROM booking_holds
+			WHERE tenant_id = $2 AND crew_id = $3 AND status = 'held' AND expires_at > $21
+				AND job_start < $7 AND job_end > $6
+		)`
+
+	result, err := r.db.ExecContext(ctx, query,
+		hold.ID,
+		hold.TenantID,
+		hold.CrewID,
+		hold.WindowStart,
+		hold.WindowEnd,
+		hold.JobStart,
+		hold.JobEnd,
+		hold.ServiceID,
+		hold.ServiceKey,
+		hold.DurationMinutes,
+		hold.Latitude,
+		hold.Longitude,
+		hold.Token,
+		hold.Status,
+		hold.ExpiresAt,
+		hold.LeadID,
+		hold.JobID,
+		hold.IPAddress,
+		hold.CreatedAt,
+		hold.UpdatedAt,
+		now,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create booking hold: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetHold retrieves a booking hold by ID
+func (r *BookingRepositoryImpl) GetHold(ctx context.Context, tenantID, holdID uuid.UUID) (*domain.BookingHold, error) {
+	query := `SELECT ` + bookingHoldColumns + ` FROM booking_holds WHERE tenant_id = $1 AND id = $2`
+
+	hold, err := scanBookingHold(r.db.QueryRowContext(ctx, query, tenantID, holdID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get booking hold: %w", err)
+	}
+
+	return hold, nil
+}
+
+// UpdateHold saves a hold's status and outcome
+func (r *BookingRepositoryImpl) UpdateHold(ctx context.Context, hold *domain.BookingHold) error {
+	query := `
+		UPDATE booking_holds SET
+			status = $3,
+			lead_id = $4,
+			job_id = $5,
+			updated_at = $6
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		hold.TenantID,
+		hold.ID,
+		hold.Status,
+		hold.LeadID,
+		hold.JobID,
+		hold.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update booking hold: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("booking hold not found")
+	}
+
+	return nil
+}
+
+// ListActiveHolds lists unexpired holds whose jobs start in [from, to)
+func (r *BookingRepositoryImpl) ListActiveHolds(ctx context.Context, tenantID uuid.UUID, from, to, now time.Time) ([]*domain.BookingHold, error) {
+	query := `
+		SELECT ` + bookingHoldColumns + ` FROM booking_holds
+		WHERE tenant_id = $1 AND status = 'held' AND expires_at > $4
+			AND job_start >= $2 AND job_start < $3
+		ORDER BY job_start`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, from, to, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list booking holds: %w", err)
+	}
+	defer rows.Close()
+
+	var holds []*domain.BookingHold
+	for rows.Next() {
+		hold, err := scanBookingHold(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking hold: %w", err)
+		}
+		holds = append(holds, hold)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate booking holds: %w", err)
+	}
+
+	return holds, nil
+}
+
+// ExpireHolds marks lapsed holds expired across all tenants
+func (r *BookingRepositoryImpl) ExpireHolds(ctx context.Context, now time.Time) (int, error) {
+	query := `UPDATE booking_holds SET status = 'expired', updated_at = $1 WHERE status = 'held' AND expires_at <= $1`
+
+	result, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire booking holds: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+func scanBookingHold(row rowScanner) (*domain.BookingHold, error) {
+	var hold domain.BookingHold
+	if err := row.Scan(
+		&hold.ID,
+		&hold.TenantID,
+		&hold.CrewID,
+		&hold.WindowStart,
+		&hold.WindowEnd,
+		&hold.JobStart,
+		&hold.JobEnd,
+		&hold.ServiceID,
+		&hold.ServiceKey,
+		&hold.DurationMinutes,
+		&hold.Latitude,
+		&hold.Longitude,
+		&hold.Token,
+		&hold.Status,
+		&hold.ExpiresAt,
+		&hold.LeadID,
+		&hold.JobID,
+		&hold.IPAddress,
+		&hold.CreatedAt,
+		&hold.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// CertificationRepositoryImpl implements the certification repository interface
+type CertificationRepositoryImpl struct {
+	db *Database
+}
+
+// NewCertificationRepository creates a new certification repository instance
+func NewCertificationRepository(db *Database) services.CertificationRepository {
+	return &CertificationRepositoryImpl{db: db}
+}
+
+const userCertificationColumns = `
+	id, tenant_id, user_id, certification_type, name, license_number,
+	issuing_authority, issued_on, expires_on, document_attachment_id,
+	reminder_days_sent, created_by, created_at, updated_at`
+
+const serviceRequirementColumns = `tenant_id, service_id, certification_type, enforcement, created_at`
+
+// CreateCertification stores a certification
+func (r *CertificationRepositoryImpl) CreateCertification(ctx context.Context, certification *domain.UserCertification) error {
+	query := `
+		INSERT INTO user_certifications (` + userCertificationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		certification.ID,
+		certification.TenantID,
+		certification.UserID,
+		certification.CertificationType,
+		certification.Name,
+		certification.LicenseNumber,
+		certification.IssuingAuthority,
+		certification.IssuedOn,
+		certification.ExpiresOn,
+		certification.DocumentAttachmentID,
+		certification.ReminderDaysSent,
+		certification.CreatedBy,
+		certification.CreatedAt,
+		certification.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create certification: %w", err)
+	}
+
+	return nil
+}
+
+// GetCertification retrieves a certification by ID
+func (r *CertificationRepositoryImpl) GetCertification(ctx context.Context, tenantID, certificationID uuid.UUID) (*domain.UserCertification, error) {
+	query := `
+		SELECT ` + userCertificationColumns + `
+		FROM user_certifications
+		WHERE tenant_id = $1 AND id = $2`
+
+	certification, err := scanUserCertification(r.db.QueryRowContext(ctx, query, tenantID, certificationID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get certification: %w", err)
+	}
+
+	return certification, nil
+}
+
+// UpdateCertification updates a certification
+func (r *CertificationRepositoryImpl) UpdateCertification(ctx context.Context, certification *domain.UserCertification) error {
+	query := `
+		UPDATE user_certifications SET
+			certification_type = $3, name = $4, license_number = $5, issuing_authority = $6,
+			issued_on = $7, expires_on = $8, document_attachment_id = $9,
+			reminder_days_sent = $10, updated_at = $11
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		certification.TenantID,
+		certification.ID,
+		certification.CertificationType,
+		certification.Name,
+		certification.LicenseNumber,
+		certification.IssuingAuthority,
+		certification.IssuedOn,
+		certification.ExpiresOn,
+		certification.DocumentAttachmentID,
+		certification.ReminderDaysSent,
+		certification.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update certification: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("certification not found")
+	}
+
+	return nil
+}
+
+// DeleteCertification deletes a certification
+func (r *CertificationRepositoryImpl) DeleteCertification(ctx context.Context, tenantID, certificationID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM user_certifications WHERE tenant_id = $1 AND id = $2`, tenantID, certificationID)
+	if err != nil {
+		return fmt.Errorf("failed to delete certification: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("certification not found")
+	}
+
+	return nil
+}
+
+// ListCertifications lists the certifications held by the users
+func (r *CertificationRepositoryImpl) ListCertifications(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) ([]*domain.UserCertification, error) {
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT ` + userCertificationColumns + `
+		FROM user_certifications
+		WHERE tenant_id = $1 AND user_id = ANY($2::uuid[])
+		ORDER BY certification_type, expires_on DESC NULLS FIRST`
+
+	return r.listCertifications(ctx, query, tenantID, pq.Array(ids))
+}
+
+// ListExpiringCertifications lists certifications expiring before the date, soonest first
+func (r *CertificationRepositoryImpl) ListExpiringCertifications(ctx context.Context, tenantID uuid.UUID, before time.Time) ([]*domain.UserCertification, error) {
+	query := `
+		SELECT ` + userCertificationColumns + `
+		FROM user_certifications
+		WHERE tenant_id = $1 AND expires_on < $2
+		ORDER BY expires_on`
+
+	return r.listCertifications(ctx, query, tenantID, before)
+}
+
+// ListCertificationsForReminder lists certifications across all tenants
+// expiring before the date that have not had their expired reminder
+func (r *CertificationRepositoryImpl) ListCertificationsForReminder(ctx context.Context, before time.Time) ([]*domain.UserCertification, error) {
+	query := `
+		SELECT ` + userCertificationColumns + `
+		FROM user_certifications
+		WHERE expires_on < $1 AND (reminder_days_sent IS NULL OR reminder_days_sent > 0)
+		ORDER BY expires_on`
+
+	return r.listCertifications(ctx, query, before)
+}
+
+// MarkReminderSent records the reminder threshold last sent for a certification
+func (r *CertificationRepositoryImpl) MarkReminderSent(ctx context.Context, tenantID, certificationID uuid.UUID, days int) error {
+	query := `
+		UPDATE user_certifications SET reminder_days_sent = $3
+		WHERE tenant_id = $1 AND id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, tenantID, certificationID, days); err != nil {
+		return fmt.Errorf("failed to mark certification reminder sent: %w", err)
+	}
+
+	return nil
+}
+
+// CreateAttachment stores a certification document's file attachment
+func (r *CertificationRepositoryImpl) CreateAttachment(ctx context.Context, attachment *domain.FileAttachment) error {
+	query := `
+		INSERT INTO file_attachments (` + attachmentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		attachment.ID,
+		attachment.TenantID,
+		attachment.EntityType,
+		attachment.EntityID,
+		attachment.Filename,
+		attachment.OriginalFilename,
+		attachment.FileSize,
+		attachment.ContentType,
+		attachment.StoragePath,
+		attachment.UploadedBy,
+		attachment.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create file attachment: %w", err)
+	}
+
+	return nil
+}
+
+// GetAttachment retrieves a file attachment by ID
+func (r *CertificationRepositoryImpl) GetAttachment(ctx context.Context, tenantID, attachmentID uuid.UUID) (*domain.FileAttachment, error) {
+	query := `
+		SELECT ` + attachmentColumns + `
+		FROM file_attachments
+		WHERE tenant_id = $1 AND id = $2`
+
+	var attachment domain.FileAttachment
+	err := r.db.QueryRowContext(ctx, query, tenantID, attachmentID).Scan(
+		&attachment.ID,
+		&attachment.TenantID,
+		&attachment.EntityType,
+		&attachment.EntityID,
+		&attachment.Filename,
+		&attachment.OriginalFilename,
+		&attachment.FileSize,
+		&attachment.ContentType,
+		&attachment.StoragePath,
+		&attachment.UploadedBy,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get file attachment: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// ListServiceRequirements lists the certifications the services require
+func (r *CertificationRepositoryImpl) ListServiceRequirements(ctx context.Context, tenantID uuid.UUID, serviceIDs []uuid.UUID) ([]*domain.ServiceCertificationRequirement, error) {
+	ids := make([]string, len(serviceIDs))
+	for i, id := range serviceIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT ` + serviceRequirementColumns + `
+		FROM service_certification_requirements
+		WHERE tenant_id = $1 AND service_id = ANY($2::uuid[])
+		ORDER BY service_id, certification_type`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service requirements: %w", err)
+	}
+	defer rows.Close()
+
+	requirements := []*domain.ServiceCertificationRequirement{}
+	for rows.Next() {
+		var requirement domain.ServiceCertificationRequirement
+		if err := rows.Scan(
+			&requirement.TenantID,
+			&requirement.ServiceID,
+			&requirement.CertificationType,
+			&requirement.Enforcement,
+			&requirement.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan service requirement: %w", err)
+		}
+		requirements = append(requirements, &requirement)
+	}
+
+	return requirements, rows.Err()
+}
+
+// ReplaceServiceRequirements swaps a service's requirements for a new set
+func (r *CertificationRepositoryImpl) ReplaceServiceRequirements(ctx context.Context, tenantID, serviceID uuid.UUID, requirements []*domain.ServiceCertificationRequirement) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM service_certification_requirements WHERE tenant_id = $1 AND service_id = $2`, tenantID, serviceID); err != nil {
+		return fmt.Errorf("failed to clear service requirements: %w", err)
+	}
+
+	query := `
+		INSERT INTO service_certification_requirements (` + serviceRequirementColumns + `)
+		VALUES ($1, $2, $3, $4, $5)`
+	for _, requirement := range requirements {
+		if _, err := tx.ExecContext(ctx, query,
+			requirement.TenantID,
+			requirement.ServiceID,
+			requirement.CertificationType,
+			requirement.Enforcement,
+			requirement.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to create service requirement: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CertificationRepositoryImpl) listCertifications(ctx context.Context, query string, args ...interface{}) ([]*domain.UserCertification, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list certifications: %w", err)
+	}
+	defer rows.Close()
+
+	certifications := []*domain.UserCertification{}
+	for rows.Next() {
+		certification, err := scanUserCertification(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan certification: %w", err)
+		}
+		certifications = append(certifications, certification)
+	}
+
+	return certifications, rows.Err()
+}
+
+func scanUserCertification(row rowScanner) (*domain.UserCertification, error) {
+	var certification domain.UserCertification
+	if err := row.Scan(
+		&certification.ID,
+		&certification.TenantID,
+		&certification.UserID,
+		&certification.CertificationType,
+		&certification.Name,
+		&certification.LicenseNumber,
+		&certification.IssuingAuthority,
+		&certification.IssuedOn,
+		&certification.ExpiresOn,
+		&certification.DocumentAttachmentID,
+		&certification.ReminderDaysSent,
+		&certification.CreatedBy,
+		&certification.CreatedAt,
+		&certification.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &certification, nil
+}
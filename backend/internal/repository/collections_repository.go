@@ -0,0 +1,697 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// CollectionsRepositoryImpl implements the collections repository interface
+type CollectionsRepositoryImpl struct {
+	db *Database
+}
+
+// NewCollectionsRepository creates a new collections repository instance
+func NewCollectionsRepository(db *Database) services.CollectionsRepository {
+	return &CollectionsRepositoryImpl{db: db}
+}
+
+const collectionPolicyColumns = `
+	id, tenant_id, reminders_enabled, reminder_steps, late_fee_enabled, late_fee_type,
+	late_fee_amount, late_fee_grace_days, late_fee_repeat_days, late_fee_max_applications,
+	suspend_scheduling_enabled, suspend_after_days, suspend_min_balance, created_at, updated_at`
+
+// GetPolicy retrieves the collections policy for a tenant
+func (r *CollectionsRepositoryImpl) GetPolicy(ctx context.Context, tenantID uuid.UUID) (*domain.CollectionPolicy, error) {
+	query := `SELECT` + collectionPolicyColumns + ` FROM collection_policies WHERE tenant_id = $1`
+
+	policy, err := scanCollectionPolicy(r.db.QueryRowContext(ctx, query, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get collection policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// UpsertPolicy creates or updates a tenant's collections policy
+func (r *CollectionsRepositoryImpl) UpsertPolicy(ctx context.Context, policy *domain.CollectionPolicy) error {
+	stepsJSON, err := json.Marshal(policy.ReminderSteps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reminder steps: %w", err)
+	}
+
+	query := `
+		INSERT INTO collection_policies (` + collectionPolicyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			reminders_enabled = EXCLUDED.reminders_enabled,
+			reminder_steps = EXCLUDED.reminder_steps,
+			late_fee_enabled = EXCLUDED.late_fee_enabled,
+			late_fee_type = EXCLUDED.late_fee_type,
+			late_fee_amount = EXCLUDED.late_fee_amount,
+			late_fee_grace_days = EXCLUDED.late_fee_grace_days,
+			late_fee_repeat_days = EXCLUDED.late_fee_repeat_days,
+			late_fee_max_applications = EXCLUDED.late_fee_max_applications,
+			suspend_scheduling_enabled = EXCLUDED.suspend_scheduling_enabled,
+			suspend_after_days = EXCLUDED.suspend_after_days,
+			suspend_min_balance = EXCLUDED.suspend_min_balance,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err = r.db.ExecContext(ctx, query,
+		policy.ID,
+		policy.TenantID,
+		policy.RemindersEnabled,
+		stepsJSON,
+		policy.LateFeeEnabled,
+		policy.LateFeeType,
+		policy.LateFeeAmount,
+		policy.LateFeeGraceDays,
+		policy.LateFeeRepeatDays,
+		policy.LateFeeMaxApplications,
+		policy.SuspendSchedulingEnabled,
+		policy.SuspendAfterDays,
+		policy.SuspendMinBalance,
+		policy.CreatedAt,
+		policy.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert collection policy: %w", err)
+	}
+
+	return nil
+}
+
+// ListPolicies returns the collections policies of all tenants
+func (r *CollectionsRepositoryImpl) ListPolicies(ctx context.Context) ([]*domain.CollectionPolicy, error) {
+	query := `SELECT` + collectionPolicyColumns + ` FROM collection_policies`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collection policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []*domain.CollectionPolicy
+	for rows.Next() {
+		policy, err := scanCollectionPolicy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan collection policy: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate collection policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// CreateReminder records a sent overdue reminder
+func (r *CollectionsRepositoryImpl) CreateReminder(ctx context.Context, reminder *domain.InvoiceReminder) error {
+	query := `
+		INSERT INTO invoice_reminders (
+			id, tenant_id, invoice_id, days_overdue, template, channel, recipient, sent_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (invoice_id, days_overdue) DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, query,
+		reminder.ID,
+		reminder.TenantID,
+		reminder.InvoiceID,
+		reminder.DaysOverdue,
+		reminder.Template,
+		reminder.Channel,
+		reminder.Recipient,
+		reminder.SentAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create invoice reminder: %w", err)
+	}
+
+	return nil
+}
+
+// GetReminders retrieves the reminders sent for an invoice
+func (r *CollectionsRepositoryImpl) GetReminders(ctx context.Context, tenantID, invoiceID uuid.UUID) ([]*domain.InvoiceReminder, error) {
+	query := `
+		SELECT id, tenant_id, invoice_id, days_overdue, template, channel, recipient, sent_at
+		FROM invoice_reminders
+		WHERE tenant_id = $1 AND invoice_id = $2
+		ORDER BY days_overdue`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []*domain.InvoiceReminder
+	for rows.Next() {
+		var reminder domain.InvoiceReminder
+		if err := rows.Scan(
+			&reminder.ID,
+			&reminder.TenantID,
+			&reminder.InvoiceID,
+			&reminder.DaysOverdue,
+			&reminder.Template,
+			&reminder.Channel,
+			&reminder.Recipient,
+			&reminder.SentAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice reminder: %w", err)
+		}
+		reminders = append(reminders, &reminder)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate invoice reminders: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// ApplyLateFee records a late fee and adds it to the invoice total in one transaction
+func (r *CollectionsRepositoryImpl) ApplyLateFee(ctx context.Context, fee *domain.InvoiceLateFee) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO invoice_late_fees (
+			id, tenant_id, invoice_id, amount, days_overdue, reason, waived, applied_at
+		) VALUES ($1, $2, $3, $4, $5, $6, FALSE, $7)`,
+		fee.ID,
+		fee.TenantID,
+		fee.InvoiceID,
+		fee.Amount,
+		fee.DaysOverdue,
+		fee.Reason,
+		fee.AppliedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create late fee: %w", err)
+	}
+
+	if err := adjustInvoiceLateFees(ctx, tx, fee.TenantID, fee.InvoiceID, fee.Amount); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetLateFee retrieves a late fee by ID
+func (r *CollectionsRepositoryImpl) GetLateFee(ctx context.Context, tenantID, feeID uuid.UUID) (*domain.InvoiceLateFee, error) {
+	query := `
+		SELECT id, tenant_id, invoice_id, amount, days_overdue, reason,
+			   waived, waived_by, waived_at, applied_at
+		FROM invoice_late_fees
+		WHERE id = $1 AND tenant_id = $2`
+
+	fee, err := scanInvoiceLateFee(r.db.QueryRowContext(ctx, query, feeID, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get late fee: %w", err)
+	}
+
+	return fee, nil
+}
+
+// GetLateFees retrieves all late fees applied to an invoice
+func (r *CollectionsRepositoryImpl) GetLateFees(ctx context.Context, tenantID, invoiceID uuid.UUID) ([]*domain.InvoiceLateFee, error) {
+	query := `
+		SELECT id, tenant_id, invoice_id, amount, days_overdue, reason,
+			   waived, waived_by, waived_at, applied_at
+		FROM invoice_late_fees
+		WHERE tenant_id = $1 AND invoice_id = $2
+		ORDER BY applied_at`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get late fees: %w", err)
+	}
+	defer rows.Close()
+
+	var fees []*domain.InvoiceLateFee
+	for rows.Next() {
+		fee, err := scanInvoiceLateFee(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan late fee: %w", err)
+		}
+		fees = append(fees, fee)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate late fees: %w", err)
+	}
+
+	return fees, nil
+}
+
+// WaiveLateFee marks a late fee waived and removes it from the invoice total in one transaction
+func (r *CollectionsRepositoryImpl) WaiveLateFee(ctx context.Context, fee *domain.InvoiceLateFee) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE invoice_late_fees SET waived = TRUE, waived_by = $3, waived_at = $4
+		WHERE id = $1 AND tenant_id = $2 AND waived = FALSE`,
+		fee.ID, fee.TenantID, fee.WaivedBy, fee.WaivedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to waive late fee: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("late fee not found or already waived")
+	}
+
+	if err := adjustInvoiceLateFees(ctx, tx, fee.TenantID, fee.InvoiceID, -fee.Amount); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CreateNote records a collections note
+func (r *CollectionsRepositoryImpl) CreateNote(ctx context.Context, note *domain.CollectionNote) error {
+	query := `
+		INSERT INTO collection_notes (
+			id, tenant_id, customer_id, invoice_id, user_id, note_type, content, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		note.ID,
+		note.TenantID,
+		note.CustomerID,
+		note.InvoiceID,
+		note.UserID,
+		note.NoteType,
+		note.Content,
+		note.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create collection note: %w", err)
+	}
+
+	return nil
+}
+
+// ListNotes lists a customer's collections notes, newest first
+func (r *CollectionsRepositoryImpl) ListNotes(ctx context.Context, tenantID, customerID uuid.UUID) ([]*domain.CollectionNote, error) {
+	query := `
+		SELECT id, tenant_id, customer_id, invoice_id, user_id, note_type, content, created_at
+		FROM collection_notes
+		WHERE tenant_id = $1 AND customer_id = $2
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collection notes: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []*domain.CollectionNote
+	for rows.Next() {
+		var note domain.CollectionNote
+		if err := rows.Scan(
+			&note.ID,
+			&note.TenantID,
+			&note.CustomerID,
+			&note.InvoiceID,
+			&note.UserID,
+			&note.NoteType,
+			&note.Content,
+			&note.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan collection note: %w", err)
+		}
+		notes = append(notes, &note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate collection notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// CreatePromise records a promise to pay
+func (r *CollectionsRepositoryImpl) CreatePromise(ctx context.Context, promise *domain.PaymentPromise) error {
+	query := `
+		INSERT INTO payment_promises (
+			id, tenant_id, customer_id, invoice_id, amount, promised_date, status,
+			notes, created_by, resolved_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		promise.ID,
+		promise.TenantID,
+		promise.CustomerID,
+		promise.InvoiceID,
+		promise.Amount,
+		promise.PromisedDate,
+		promise.Status,
+		promise.Notes,
+		promise.CreatedBy,
+		promise.ResolvedAt,
+		promise.CreatedAt,
+		promise.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create payment promise: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePromise updates the status of a promise to pay
+func (r *CollectionsRepositoryImpl) UpdatePromise(ctx context.Context, promise *domain.PaymentPromise) error {
+	query := `
+		UPDATE payment_promises SET
+			status = $3,
+			notes = $4,
+			resolved_at = $5,
+			updated_at = $6
+		WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		promise.ID,
+		promise.TenantID,
+		promise.Status,
+		promise.Notes,
+		promise.ResolvedAt,
+		promise.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update payment promise: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("payment promise not found")
+	}
+
+	return nil
+}
+
+// GetPromise retrieves a promise to pay by ID
+func (r *CollectionsRepositoryImpl) GetPromise(ctx context.Context, tenantID, promiseID uuid.UUID) (*domain.PaymentPromise, error) {
+	query := `
+		SELECT id, tenant_id, customer_id, invoice_id, amount, promised_date, status,
+			   notes, created_by, resolved_at, created_at, updated_at
+		FROM payment_promises
+		WHERE id = $1 AND tenant_id = $2`
+
+	promise, err := scanPaymentPromise(r.db.QueryRowContext(ctx, query, promiseID, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get payment promise: %w", err)
+	}
+
+	return promise, nil
+}
+
+// ListPromises lists promises to pay with optional customer and status filters
+func (r *CollectionsRepositoryImpl) ListPromises(ctx context.Context, tenantID uuid.UUID, filter *services.PaymentPromiseFilter) ([]*domain.PaymentPromise, error) {
+	query := `
+		SELECT id, tenant_id, customer_id, invoice_id, amount, promised_date, status,
+			   notes, created_by, resolved_at, created_at, updated_at
+		FROM payment_promises
+		WHERE tenant_id = $1`
+	args := []interface{}{tenantID}
+	argIndex := 2
+
+	if filter.CustomerID != nil {
+		query += fmt.Sprintf(" AND customer_id = $%d", argIndex)
+		args = append(args, *filter.CustomerID)
+		argIndex++
+	}
+	if filter.Status != "" {
+		query += fmt.Sprintf(" AND status = $%d", argIndex)
+		args = append(args, filter.Status)
+	}
+	query += " ORDER BY promised_date ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment promises: %w", err)
+	}
+	defer rows.Close()
+
+	var promises []*domain.PaymentPromise
+	for rows.Next() {
+		promise, err := scanPaymentPromise(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payment promise: %w", err)
+		}
+		promises = append(promises, promise)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate payment promises: %w", err)
+	}
+
+	return promises, nil
+}
+
+// ResolveExpiredPromises closes pending promises whose date has passed. A promise is kept when
+// its invoice is paid, or for account-level promises when the customer has paid at least the
+// promised amount since the promise was made; otherwise it is broken.
+func (r *CollectionsRepositoryImpl) ResolveExpiredPromises(ctx context.Context, tenantID uuid.UUID, asOf time.Time) (int, int, error) {
+	keptQuery := `
+		UPDATE payment_promises pp SET status = 'kept', resolved_at = $3, updated_at = $3
+		WHERE pp.tenant_id = $1 AND pp.status = 'pending' AND pp.promised_date < $2::date
+		  AND (
+			(pp.invoice_id IS NOT NULL AND EXISTS (
+				SELECT 1 FROM invoices i WHERE i.id = pp.invoice_id AND i.status = 'paid'))
+			OR
+			(pp.invoice_id IS NULL AND COALESCE((
+				SELECT SUM(p.amount) FROM payments p
+				JOIN invoices i ON i.id = p.invoice_id
+				WHERE i.customer_id = pp.customer_id AND p.tenant_id = pp.tenant_id
+				  AND p.status = 'completed' AND p.created_at >= pp.created_at), 0) >= pp.amount)
+		  )`
+
+	result, err := r.db.ExecContext(ctx, keptQuery, tenantID, asOf, time.Now())
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to resolve kept payment promises: %w", err)
+	}
+	kept, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	brokenQuery := `
+		UPDATE payment_promises SET status = 'broken', resolved_at = $3, updated_at = $3
+		WHERE tenant_id = $1 AND status = 'pending' AND promised_date < $2::date`
+
+	result, err = r.db.ExecContext(ctx, brokenQuery, tenantID, asOf, time.Now())
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to resolve broken payment promises: %w", err)
+	}
+	broken, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(kept), int(broken), nil
+}
+
+// SetSchedulingSuspended suspends or resumes scheduling for a customer
+func (r *CollectionsRepositoryImpl) SetSchedulingSuspended(ctx context.Context, tenantID, customerID uuid.UUID, suspended bool, reason *string) error {
+	var suspendedAt *time.Time
+	if suspended {
+		now := time.Now()
+		suspendedAt = &now
+	}
+
+	query := `
+		UPDATE customers SET
+			scheduling_suspended = $3,
+			scheduling_suspended_at = $4,
+			scheduling_suspended_reason = $5,
+			updated_at = NOW()
+		WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, customerID, tenantID, suspended, suspendedAt, reason)
+	if err != nil {
+		return fmt.Errorf("failed to update scheduling suspension: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("customer not found")
+	}
+
+	return nil
+}
+
+// GetSuspendedCustomers returns suspended customers keyed by ID with their suspension reason
+func (r *CollectionsRepositoryImpl) GetSuspendedCustomers(ctx context.Context, tenantID uuid.UUID) (map[uuid.UUID]string, error) {
+	query := `
+		SELECT id, COALESCE(scheduling_suspended_reason, '')
+		FROM customers
+		WHERE tenant_id = $1 AND scheduling_suspended = TRUE`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suspended customers: %w", err)
+	}
+	defer rows.Close()
+
+	suspended := make(map[uuid.UUID]string)
+	for rows.Next() {
+		var id uuid.UUID
+		var reason string
+		if err := rows.Scan(&id, &reason); err != nil {
+			return nil, fmt.Errorf("failed to scan suspended customer: %w", err)
+		}
+		suspended[id] = reason
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate suspended customers: %w", err)
+	}
+
+	return suspended, nil
+}
+
+// Helper functions
+
+func adjustInvoiceLateFees(ctx context.Context, tx *sql.Tx, tenantID, invoiceID uuid.UUID, amount float64) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE invoices SET
+			total_amount = total_amount + $3,
+			late_fee_total = COALESCE(late_fee_total, 0) + $3,
+			updated_at = NOW()
+		WHERE id = $1 AND tenant_id = $2`,
+		invoiceID, tenantID, amount,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update invoice late fees: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("invoice not found")
+	}
+
+	return nil
+}
+
+func scanCollectionPolicy(row rowScanner) (*domain.CollectionPolicy, error) {
+	var policy domain.CollectionPolicy
+	var stepsJSON []byte
+	if err := row.Scan(
+		&policy.ID,
+		&policy.TenantID,
+		&policy.RemindersEnabled,
+		&stepsJSON,
+		&policy.LateFeeEnabled,
+		&policy.LateFeeType,
+		&policy.LateFeeAmount,
+		&policy.LateFeeGraceDays,
+		&policy.LateFeeRepeatDays,
+		&policy.LateFeeMaxApplications,
+		&policy.SuspendSchedulingEnabled,
+		&policy.SuspendAfterDays,
+		&policy.SuspendMinBalance,
+		&policy.CreatedAt,
+		&policy.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if len(stepsJSON) > 0 {
+		if err := json.Unmarshal(stepsJSON, &policy.ReminderSteps); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal reminder steps: %w", err)
+		}
+	}
+
+	return &policy, nil
+}
+
+func scanInvoiceLateFee(row rowScanner) (*domain.InvoiceLateFee, error) {
+	var fee domain.InvoiceLateFee
+	if err := row.Scan(
+		&fee.ID,
+		&fee.TenantID,
+		&fee.InvoiceID,
+		&fee.Amount,
+		&fee.DaysOverdue,
+		&fee.Reason,
+		&fee.Waived,
+		&fee.WaivedBy,
+		&fee.WaivedAt,
+		&fee.AppliedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &fee, nil
+}
+
+func scanPaymentPromise(row rowScanner) (*domain.PaymentPromise, error) {
+	var promise domain.PaymentPromise
+	if err := row.Scan(
+		&promise.ID,
+		&promise.TenantID,
+		&promise.CustomerID,
+		&promise.InvoiceID,
+		&promise.Amount,
+		&promise.PromisedDate,
+		&promise.Status,
+		&promise.Notes,
+		&promise.CreatedBy,
+		&promise.ResolvedAt,
+		&promise.CreatedAt,
+		&promise.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &promise, nil
+}
@@ -0,0 +1,620 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// ConversationRepositoryImpl implements the conversation repository interface
+type ConversationRepositoryImpl struct {
+	db *Database
+}
+
+// NewConversationRepository creates a new conversation repository instance
+func NewConversationRepository(db *Database) services.ConversationRepository {
+	return &ConversationRepositoryImpl{db: db}
+}
+
+const communicationThreadColumns = `
+	id, tenant_id, customer_id, channel, address, subject, status, unread_count,
+	last_message_at, last_message_preview, created_at, updated_at`
+
+const communicationMessageColumns = `
+	id, tenant_id, thread_id, customer_id, channel, direction, from_address, to_address,
+	subject, body, provider_message_id, in_reply_to, status, error, sent_by, read_at,
+	created_at, updated_at`
+
+// CreateThread stores a new thread
+func (r *ConversationRepositoryImpl) CreateThread(ctx context.Context, thread *domain.CommunicationThread) error {
+	query := `
+		INSERT INTO communication_threads (` + communicationThreadColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		thread.ID,
+		thread.TenantID,
+		thread.CustomerID,
+		thread.Channel,
+		thread.Address,
+		thread.Subject,
+		thread.Status,
+		thread.UnreadCount,
+		thread.LastMessageAt,
+		thread.LastMessagePreview,
+		thread.CreatedAt,
+		thread.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create thread: %w", err)
+	}
+
+	return nil
+}
+
+// GetThread retrieves a thread by ID
+func (r *ConversationRepositoryImpl) GetThread(ctx context.Context, tenantID, threadID uuid.UUID) (*domain.CommunicationThread, error) {
+	query := `SELECT ` + communicationThreadColumns + ` FROM communication_threads WHERE tenant_id = $1 AND id = $2`
+
+	thread, err := scanCommunicationThread(r.db.QueryRowContext(ctx, query, tenantID, threadID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get thread: %w", err)
+	}
+
+	return thread, nil
+}
+
+// UpdateThread saves a thread's status and customer. The thread's messages are
+// linked to the same customer.
+func (r *ConversationRepositoryImpl) UpdateThread(ctx context.Context, thread *domain.CommunicationThread) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE communication_threads SET
+			customer_id = $3,
+			status = $4,
+			updated_at = $5
+		WHERE tenant_id = $1 AND id = $2`,
+		thread.TenantID, thread.ID, thread.CustomerID, thread.Status, thread.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update thread: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("thread not found")
+	}
+
+	if thread.CustomerID != nil {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE communication_messages SET customer_id = $3
+			WHERE tenant_id = $1 AND thread_id = $2 AND customer_id IS DISTINCT FROM $3`,
+			thread.TenantID, thread.ID, thread.CustomerID); err != nil {
+			return fmt.Errorf("failed to link thread messages: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListThreads lists threads, most recent message first
+func (r *ConversationRepositoryImpl) ListThreads(ctx context.Context, tenantID uuid.UUID, filter *services.ThreadFilter) ([]*domain.CommunicationThread, int64, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Channel != "" {
+		args = append(args, filter.Channel)
+		conditions = append(conditions, fmt.Sprintf("channel = $%d", len(args)))
+	}
+	if filter.CustomerID != nil {
+		args = append(args, *filter.CustomerID)
+		conditions = append(conditions, fmt.Sprintf("customer_id = $%d", len(args)))
+	}
+	if filter.UnreadOnly {
+		conditions = append(conditions, "unread_count > 0")
+	}
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf(
+			"(address ILIKE $%[1]d OR subject ILIKE $%[1]d OR last_message_preview ILIKE $%[1]d)", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM communication_threads WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count threads: %w", err)
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	query := `
+		SELECT ` + communicationThreadColumns + `
+		FROM communication_threads
+		WHERE ` + where + fmt.Sprintf(`
+		ORDER BY COALESCE(last_message_at, created_at) DESC
+		LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list threads: %w", err)
+	}
+	defer rows.Close()
+
+	threads, err := scanCommunicationThreads(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return threads, total, nil
+}
+
+// FindThreads lists the threads with an address on a channel, most recent first
+func (r *ConversationRepositoryImpl) FindThreads(ctx context.Context, tenantID uuid.UUID, channel, address string) ([]*domain.CommunicationThread, error) {
+	query := `
+		SELECT ` + communicationThreadColumns + `
+		FROM communication_threads
+		WHERE tenant_id = $1 AND channel = $2 AND address = $3
+		ORDER BY COALESCE(last_message_at, created_at) DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, channel, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find threads: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCommunicationThreads(rows)
+}
+
+// MarkThreadRead marks a thread's inbound messages read and clears its unread count
+func (r *ConversationRepositoryImpl) MarkThreadRead(ctx context.Context, tenantID, threadID uuid.UUID, readAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE communication_messages SET read_at = $3
+		WHERE tenant_id = $1 AND thread_id = $2 AND direction = 'inbound' AND read_at IS NULL`,
+		tenantID, threadID, readAt); err != nil {
+		return fmt.Errorf("failed to mark messages read: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE communication_threads SET unread_count = 0
+		WHERE tenant_id = $1 AND id = $2`,
+		tenantID, threadID); err != nil {
+		return fmt.Errorf("failed to update thread: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AddMessage stores a message and moves its thread's last message, preview and
+// unread count. Messages can arrive out of order, so only a newer message replaces
+// the preview.
+func (r *ConversationRepositoryImpl) AddMessage(ctx context.Context, message *domain.CommunicationMessage) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO communication_messages (` + communicationMessageColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+
+	if _, err := tx.ExecContext(ctx, query,
+		message.ID,
+		message.TenantID,
+		message.ThreadID,
+		message.CustomerID,
+		message.Channel,
+		message.Direction,
+		message.FromAddress,
+		message.ToAddress,
+		message.Subject,
+		message.Body,
+		message.ProviderMessageID,
+		message.InReplyTo,
+		message.Status,
+		message.Error,
+		message.SentBy,
+		message.ReadAt,
+		message.CreatedAt,
+		message.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+
+	unread := 0
+	if message.Direction == domain.MessageDirectionInbound && message.ReadAt == nil {
+		unread = 1
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE communication_threads SET
+			unread_count = unread_count + $4,
+			status = 'open',
+			last_message_preview = CASE
+				WHEN last_message_at IS NULL OR last_message_at <= $3 THEN $5
+				ELSE last_message_preview
+			END,
+			last_message_at = GREATEST(last_message_at, $3),
+			updated_at = NOW()
+		WHERE tenant_id = $1 AND id = $2`,
+		message.TenantID, message.ThreadID, message.CreatedAt, unread, services.MessagePreview(message.Body))
+	if err != nil {
+		return fmt.Errorf("failed to update thread: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("thread not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateMessageStatus saves a message's delivery status
+func (r *ConversationRepositoryImpl) UpdateMessageStatus(ctx context.Context, message *domain.CommunicationMessage) error {
+	query := `
+		UPDATE communication_messages SET
+			status = $3,
+			provider_message_id = $4,
+			error = $5,
+			updated_at = $6
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		message.TenantID,
+		message.ID,
+		message.Status,
+		message.ProviderMessageID,
+		message.Error,
+		message.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update message: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("message not found")
+	}
+
+	return nil
+}
+
+// GetMessageByProviderID retrieves a message by the provider's ID for it
+func (r *ConversationRepositoryImpl) GetMessageByProviderID(ctx context.Context, tenantID uuid.UUID, providerMessageID string) (*domain.CommunicationMessage, error) {
+	query := `SELECT ` + communicationMessageColumns + ` FROM communication_messages WHERE tenant_id = $1 AND provider_message_id = $2`
+
+	message, err := scanCommunicationMessage(r.db.QueryRowContext(ctx, query, tenantID, providerMessageID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	return message, nil
+}
+
+// ListMessages lists a thread's messages, oldest first
+func (r *ConversationRepositoryImpl) ListMessages(ctx context.Context, tenantID, threadID uuid.UUID) ([]*domain.CommunicationMessage, error) {
+	query := `
+		SELECT ` + communicationMessageColumns + `
+		FROM communication_messages
+		WHERE tenant_id = $1 AND thread_id = $2
+		ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*domain.CommunicationMessage
+	for rows.Next() {
+		message, err := scanCommunicationMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// FindCustomerByContact matches an email address or phone digits to a customer,
+// preferring active customers
+func (r *ConversationRepositoryImpl) FindCustomerByContact(ctx context.Context, tenantID uuid.UUID, email, phoneDigits string) (*uuid.UUID, error) {
+	if email == "" && phoneDigits == "" {
+		return nil, nil
+	}
+
+	query := `
+		SELECT id
+		FROM customers
+		WHERE tenant_id = $1 AND status NOT IN ('deleted', 'merged')
+			AND (
+				($2 <> '' AND LOWER(email) = LOWER($2)) OR
+				($3 <> '' AND regexp_replace(phone, '\D', '', 'g') IN ($3, '1' || $3))
+			)
+		ORDER BY (status = 'active') DESC, created_at
+		LIMIT 1`
+
+	var customerID uuid.UUID
+	if err := r.db.QueryRowContext(ctx, query, tenantID, email, phoneDigits).Scan(&customerID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find customer: %w", err)
+	}
+
+	return &customerID, nil
+}
+
+// CreateNote stores a customer note
+func (r *ConversationRepositoryImpl) CreateNote(ctx context.Context, note *domain.CustomerNote) error {
+	query := `
+		INSERT INTO customer_notes (id, tenant_id, customer_id, body, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		note.ID,
+		note.TenantID,
+		note.CustomerID,
+		note.Body,
+		note.CreatedBy,
+		note.CreatedAt,
+		note.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create note: %w", err)
+	}
+
+	return nil
+}
+
+// timelineSources select a customer's touchpoints as timeline rows: id, type, action,
+// reference, detail, occurred_at, resource_type, resource_id, thread_id, channel,
+// direction and actor_id. $1 is the tenant and $2 the customer. Job, quote and
+// invoice history comes from the audit log.
+var timelineSources = []string{
+	// Messages sent and received
+	`SELECT m.id, 'message', m.channel || '.' || m.direction, COALESCE(m.subject, ''), m.body,
+		m.created_at, 'communication_thread', m.thread_id, m.thread_id, m.channel, m.direction, m.sent_by
+	FROM communication_messages m
+	WHERE m.tenant_id = $1 AND m.customer_id = $2`,
+
+	// Staff notes
+	`SELECT n.id, 'note', 'note.create', '', n.body,
+		n.created_at, 'customer', n.customer_id, NULL::uuid, '', '', n.created_by
+	FROM customer_notes n
+	WHERE n.tenant_id = $1 AND n.customer_id = $2`,
+
+	// Collections notes
+	`SELECT cn.id, 'note', 'collections.' || cn.note_type, COALESCE(i.invoice_number, ''), cn.content,
+		cn.created_at, CASE WHEN cn.invoice_id IS NULL THEN 'customer' ELSE 'invoice' END,
+		COALESCE(cn.invoice_id, cn.customer_id), NULL::uuid, '', '', cn.user_id
+	FROM collection_notes cn
+	LEFT JOIN invoices i ON i.id = cn.invoice_id
+	WHERE cn.tenant_id = $1 AND cn.customer_id = $2`,
+
+	// Payments
+	`SELECT p.id, 'payment', 'payment.' || p.status, i.invoice_number,
+		TO_CHAR(p.amount, 'FM999999990.00') || ' by ' || p.payment_method,
+		COALESCE(p.processed_at, p.created_at), 'invoice', p.invoice_id, NULL::uuid, '', '', NULL::uuid
+	FROM payments p
+	JOIN invoices i ON i.id = p.invoice_id
+	WHERE p.tenant_id = $1 AND i.customer_id = $2`,
+
+	// Overdue invoice reminders
+	`SELECT ir.id, 'reminder', 'invoice.reminder', i.invoice_number, COALESCE(ir.recipient, ''),
+		ir.sent_at, 'invoice', ir.invoice_id, NULL::uuid, ir.channel, 'outbound', NULL::uuid
+	FROM invoice_reminders ir
+	JOIN invoices i ON i.id = ir.invoice_id
+	WHERE ir.tenant_id = $1 AND i.customer_id = $2`,
+
+	// Job history
+	`SELECT a.id, 'job', a.action, COALESCE(NULLIF(j.job_number, ''), j.title), COALESCE(a.new_values->>'status', ''),
+		a.created_at, 'job', a.resource_id, NULL::uuid, '', '', a.user_id
+	FROM audit_logs a
+	JOIN jobs j ON j.id = a.resource_id
+	WHERE a.tenant_id = $1 AND a.resource_type = 'job' AND j.customer_id = $2`,
+
+	// Quote history, including portal views and acceptance
+	`SELECT a.id, 'quote', a.action, q.quote_number, COALESCE(a.new_values->>'status', ''),
+		a.created_at, 'quote', a.resource_id, NULL::uuid, '', '', a.user_id
+	FROM audit_logs a
+	JOIN quotes q ON q.id = a.resource_id
+	WHERE a.tenant_id = $1 AND a.resource_type = 'quote' AND q.customer_id = $2`,
+
+	// Invoice history, including portal views and payments
+	`SELECT a.id, 'invoice', a.action, i.invoice_number, COALESCE(a.new_values->>'status', ''),
+		a.created_at, 'invoice', a.resource_id, NULL::uuid, '', '', a.user_id
+	FROM audit_logs a
+	JOIN invoices i ON i.id = a.resource_id
+	WHERE a.tenant_id = $1 AND a.resource_type = 'invoice' AND i.customer_id = $2`,
+
+	// Customer record history
+	`SELECT a.id, 'customer', a.action, '', '',
+		a.created_at, 'customer', a.resource_id, NULL::uuid, '', '', a.user_id
+	FROM audit_logs a
+	WHERE a.tenant_id = $1 AND a.resource_type = 'customer' AND a.resource_id = $2`,
+}
+
+// ListTimeline lists a customer's timeline events, newest first
+func (r *ConversationRepositoryImpl) ListTimeline(ctx context.Context, tenantID, customerID uuid.UUID, filter *services.TimelineFilter) ([]*services.TimelineEvent, error) {
+	var conditions []string
+	args := []interface{}{tenantID, customerID}
+
+	if len(filter.Types) > 0 {
+		args = append(args, pq.Array(filter.Types))
+		conditions = append(conditions, fmt.Sprintf("type = ANY($%d)", len(args)))
+	}
+	if filter.Before != nil {
+		args = append(args, *filter.Before)
+		conditions = append(conditions, fmt.Sprintf("occurred_at < $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, filter.Limit)
+	query := `
+		SELECT id, type, action, reference, detail, occurred_at, resource_type, resource_id,
+			thread_id, channel, direction, actor_id
+		FROM (
+			` + strings.Join(timelineSources, "\n\t\t\tUNION ALL\n\t\t\t") + `
+		) AS timeline (id, type, action, reference, detail, occurred_at, resource_type,
+			resource_id, thread_id, channel, direction, actor_id)
+		` + where + fmt.Sprintf(`
+		ORDER BY occurred_at DESC, id
+		LIMIT $%d`, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list timeline: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*services.TimelineEvent
+	for rows.Next() {
+		event := &services.TimelineEvent{}
+		if err := rows.Scan(
+			&event.ID,
+			&event.Type,
+			&event.Action,
+			&event.Reference,
+			&event.Detail,
+			&event.OccurredAt,
+			&event.ResourceType,
+			&event.ResourceID,
+			&event.ThreadID,
+			&event.Channel,
+			&event.Direction,
+			&event.ActorID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan timeline event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate timeline: %w", err)
+	}
+
+	return events, nil
+}
+
+func scanCommunicationThreads(rows *sql.Rows) ([]*domain.CommunicationThread, error) {
+	var threads []*domain.CommunicationThread
+	for rows.Next() {
+		thread, err := scanCommunicationThread(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan thread: %w", err)
+		}
+		threads = append(threads, thread)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate threads: %w", err)
+	}
+
+	return threads, nil
+}
+
+func scanCommunicationThread(row rowScanner) (*domain.CommunicationThread, error) {
+	var thread domain.CommunicationThread
+	if err := row.Scan(
+		&thread.ID,
+		&thread.TenantID,
+		&thread.CustomerID,
+		&thread.Channel,
+		&thread.Address,
+		&thread.Subject,
+		&thread.Status,
+		&thread.UnreadCount,
+		&thread.LastMessageAt,
+		&thread.LastMessagePreview,
+		&thread.CreatedAt,
+		&thread.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &thread, nil
+}
+
+func scanCommunicationMessage(row rowScanner) (*domain.CommunicationMessage, error) {
+	var message domain.CommunicationMessage
+	if err := row.Scan(
+		&message.ID,
+		&message.TenantID,
+		&message.ThreadID,
+		&message.CustomerID,
+		&message.Channel,
+		&message.Direction,
+		&message.FromAddress,
+		&message.ToAddress,
+		&message.Subject,
+		&message.Body,
+		&message.ProviderMessageID,
+		&message.InReplyTo,
+		&message.Status,
+		&message.Error,
+		&message.SentBy,
+		&message.ReadAt,
+		&message.CreatedAt,
+		&message.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
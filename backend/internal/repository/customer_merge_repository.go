@@ -0,0 +1,444 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// CustomerMergeRepositoryImpl implements the customer merge repository interface
+type CustomerMergeRepositoryImpl struct {
+	db *Database
+}
+
+// NewCustomerMergeRepository creates a new customer merge repository instance
+func NewCustomerMergeRepository(db *Database) services.CustomerMergeRepository {
+	return &CustomerMergeRepositoryImpl{db: db}
+}
+
+// customerReference is a column pointing at a customer
+type customerReference struct {
+	table  string
+	column string
+	filter string // extra condition, for polymorphic references
+}
+
+func (ref customerReference) key() string {
+	return ref.table + "." + ref.column
+}
+
+// customerReferences are the records re-parented by a merge. Payments belong to
+// invoices and move with them.
+var customerReferences = []customerReference{
+	{table: "properties", column: "customer_id"},
+	{table: "jobs", column: "customer_id"},
+	{table: "quotes", column: "customer_id"},
+	{table: "invoices", column: "customer_id"},
+	{table: "file_attachments", column: "entity_id", filter: "entity_type = 'customer'"},
+	{table: "collection_notes", column: "customer_id"},
+	{table: "payment_promises", column: "customer_id"},
+	{table: "quote_signatures", column: "customer_id"},
+	{table: "portal_schedule_changes", column: "customer_id"},
+	{table: "portal_service_requests", column: "customer_id"},
+	{table: "leads", column: "customer_id"},
+	{table: "leads", column: "matched_customer_id"},
+	{table: "opportunities", column: "customer_id"},
+	{table: "follow_up_tasks", column: "customer_id"},
+	{table: "ai_conversations", column: "customer_id"},
+	{table: "communication_threads", column: "customer_id"},
+	{table: "communication_messages", column: "customer_id"},
+	{table: "customer_notes", column: "customer_id"},
+}
+
+const customerMergeColumns = `
+	id, tenant_id, survivor_id, merged_id, score, field_changes, moved_records,
+	merged_status, status, undo_expires_at, merged_by, undone_by, undone_at,
+	created_at, updated_at`
+
+// CountRelated counts the records that reference a customer
+func (r *CustomerMergeRepositoryImpl) CountRelated(ctx context.Context, tenantID, customerID uuid.UUID) (map[string]int, error) {
+	counts := make(map[string]int, len(customerReferences))
+	for _, ref := range customerReferences {
+		query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE tenant_id = $1 AND %s = $2`, ref.table, ref.column)
+		if ref.filter != "" {
+			query += " AND " + ref.filter
+		}
+
+		var count int
+		if err := r.db.QueryRowContext(ctx, query, tenantID, customerID).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", ref.key(), err)
+		}
+		if count > 0 {
+			counts[ref.key()] = count
+		}
+	}
+
+	return counts, nil
+}
+
+// MergeCustomers re-parents the merged customer's records, saves the survivor, retires
+// the merged customer and records the merge in one transaction
+func (r *CustomerMergeRepositoryImpl) MergeCustomers(ctx context.Context, merge *domain.CustomerMerge, survivor *domain.EnhancedCustomer) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock both customers so concurrent merges of either one wait for this one
+	var locked int
+	if err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM (
+			SELECT id FROM customers
+			WHERE tenant_id = $1 AND id IN ($2, $3) AND status <> $4
+			FOR UPDATE
+		) customers`,
+		merge.TenantID, merge.SurvivorID, merge.MergedID, domain.CustomerStatusMerged,
+	).Scan(&locked); err != nil {
+		return fmt.Errorf("failed to lock customers: %w", err)
+	}
+	if locked != 2 {
+		return fmt.Errorf("customer not found or already merged")
+	}
+
+	merge.MovedRecords = map[string][]uuid.UUID{}
+	for _, ref := range customerReferences {
+		query := fmt.Sprintf(`UPDATE %s SET %s = $1 WHERE tenant_id = $2 AND %s = $3`, ref.table, ref.column, ref.column)
+		if ref.filter != "" {
+			query += " AND " + ref.filter
+		}
+		ids, err := updateReturningIDs(ctx, tx, query+" RETURNING id", merge.SurvivorID, merge.TenantID, merge.MergedID)
+		if err != nil {
+			return fmt.Errorf("failed to move %s: %w", ref.key(), err)
+		}
+		if len(ids) > 0 {
+			merge.MovedRecords[ref.key()] = ids
+		}
+	}
+
+	if err := updateMergedCustomerFields(ctx, tx, survivor); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE customers SET status = $3, updated_at = $4
+		WHERE id = $1 AND tenant_id = $2`,
+		merge.MergedID, merge.TenantID, domain.CustomerStatusMerged, merge.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to retire merged customer: %w", err)
+	}
+
+	fieldChangesJSON, err := json.Marshal(merge.FieldChanges)
+	if err != nil {
+		return fmt.Errorf("failed to marshal field changes: %w", err)
+	}
+	movedJSON, err := json.Marshal(merge.MovedRecords)
+	if err != nil {
+		return fmt.Errorf("failed to marshal moved records: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO customer_merges (`+customerMergeColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		merge.ID,
+		merge.TenantID,
+		merge.SurvivorID,
+		merge.MergedID,
+		merge.Score,
+		fieldChangesJSON,
+		movedJSON,
+		merge.MergedStatus,
+		merge.Status,
+		merge.UndoExpiresAt,
+		merge.MergedBy,
+		merge.UndoneBy,
+		merge.UndoneAt,
+		merge.CreatedAt,
+		merge.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create merge: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UndoMerge moves the recorded records back to the merged customer, saves the
+// survivor, restores the merged customer and updates the merge in one transaction.
+// Records moved to a third customer since the merge are left where they are.
+func (r *CustomerMergeRepositoryImpl) UndoMerge(ctx context.Context, merge *domain.CustomerMerge, survivor *domain.EnhancedCustomer) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE customer_merges SET status = $3, undone_by = $4, undone_at = $5, updated_at = $6
+		WHERE id = $1 AND tenant_id = $2 AND status = $7`,
+		merge.ID, merge.TenantID, merge.Status, merge.UndoneBy, merge.UndoneAt, merge.UpdatedAt,
+		domain.CustomerMergeStatusMerged,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update merge: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("merge not found or already undone")
+	}
+
+	for _, ref := range customerReferences {
+		ids := merge.MovedRecords[ref.key()]
+		if len(ids) == 0 {
+			continue
+		}
+		query := fmt.Sprintf(`UPDATE %s SET %s = $1 WHERE tenant_id = $2 AND %s = $3 AND id = ANY($4)`, ref.table, ref.column, ref.column)
+		if _, err := tx.ExecContext(ctx, query, merge.MergedID, merge.TenantID, merge.SurvivorID, pq.Array(ids)); err != nil {
+			return fmt.Errorf("failed to move back %s: %w", ref.key(), err)
+		}
+	}
+
+	if err := updateMergedCustomerFields(ctx, tx, survivor); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE customers SET status = $3, updated_at = $4
+		WHERE id = $1 AND tenant_id = $2`,
+		merge.MergedID, merge.TenantID, merge.MergedStatus, merge.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to restore merged customer: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetMerge retrieves a merge by ID
+func (r *CustomerMergeRepositoryImpl) GetMerge(ctx context.Context, tenantID, mergeID uuid.UUID) (*domain.CustomerMerge, error) {
+	query := `SELECT ` + customerMergeColumns + ` FROM customer_merges WHERE id = $1 AND tenant_id = $2`
+
+	merge, err := scanCustomerMerge(r.db.QueryRowContext(ctx, query, mergeID, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get merge: %w", err)
+	}
+
+	return merge, nil
+}
+
+// ListMerges lists a tenant's merges, newest first
+func (r *CustomerMergeRepositoryImpl) ListMerges(ctx context.Context, tenantID uuid.UUID, filter *services.CustomerMergeFilter) ([]*domain.CustomerMerge, int64, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.CustomerID != nil {
+		args = append(args, *filter.CustomerID)
+		conditions = append(conditions, fmt.Sprintf("(survivor_id = $%[1]d OR merged_id = $%[1]d)", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM customer_merges WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count merges: %w", err)
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	query := `
+		SELECT ` + customerMergeColumns + `
+		FROM customer_merges
+		WHERE ` + where + fmt.Sprintf(`
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list merges: %w", err)
+	}
+	defer rows.Close()
+
+	var merges []*domain.CustomerMerge
+	for rows.Next() {
+		merge, err := scanCustomerMerge(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan merge: %w", err)
+		}
+		merges = append(merges, merge)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate merges: %w", err)
+	}
+
+	return merges, total, nil
+}
+
+// CreateDismissal records a pair of customers as not duplicates
+func (r *CustomerMergeRepositoryImpl) CreateDismissal(ctx context.Context, dismissal *domain.CustomerDuplicateDismissal) error {
+	query := `
+		INSERT INTO customer_duplicate_dismissals (
+			id, tenant_id, customer_a_id, customer_b_id, dismissed_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (tenant_id, customer_a_id, customer_b_id) DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, query,
+		dismissal.ID,
+		dismissal.TenantID,
+		dismissal.CustomerAID,
+		dismissal.CustomerBID,
+		dismissal.DismissedBy,
+		dismissal.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create dismissal: %w", err)
+	}
+
+	return nil
+}
+
+// ListDismissals lists a tenant's dismissed duplicate pairs
+func (r *CustomerMergeRepositoryImpl) ListDismissals(ctx context.Context, tenantID uuid.UUID) ([]*domain.CustomerDuplicateDismissal, error) {
+	query := `
+		SELECT id, tenant_id, customer_a_id, customer_b_id, dismissed_by, created_at
+		FROM customer_duplicate_dismissals
+		WHERE tenant_id = $1`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dismissals: %w", err)
+	}
+	defer rows.Close()
+
+	var dismissals []*domain.CustomerDuplicateDismissal
+	for rows.Next() {
+		var dismissal domain.CustomerDuplicateDismissal
+		if err := rows.Scan(
+			&dismissal.ID,
+			&dismissal.TenantID,
+			&dismissal.CustomerAID,
+			&dismissal.CustomerBID,
+			&dismissal.DismissedBy,
+			&dismissal.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan dismissal: %w", err)
+		}
+		dismissals = append(dismissals, &dismissal)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate dismissals: %w", err)
+	}
+
+	return dismissals, nil
+}
+
+// Helper functions
+
+// updateMergedCustomerFields saves the customer fields a merge or undo changes
+func updateMergedCustomerFields(ctx context.Context, tx *sql.Tx, customer *domain.EnhancedCustomer) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE customers SET
+			first_name = $3, last_name = $4, email = $5, phone = $6, company_name = $7,
+			tax_id = $8, address_line1 = $9, address_line2 = $10, city = $11, state = $12,
+			zip_code = $13, notes = $14, updated_at = $15
+		WHERE id = $1 AND tenant_id = $2`,
+		customer.ID,
+		customer.TenantID,
+		customer.FirstName,
+		customer.LastName,
+		customer.Email,
+		customer.Phone,
+		customer.CompanyName,
+		customer.TaxID,
+		customer.AddressLine1,
+		customer.AddressLine2,
+		customer.City,
+		customer.State,
+		customer.ZipCode,
+		customer.Notes,
+		customer.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update surviving customer: %w", err)
+	}
+	return nil
+}
+
+func updateReturningIDs(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func scanCustomerMerge(row rowScanner) (*domain.CustomerMerge, error) {
+	var merge domain.CustomerMerge
+	var fieldChangesJSON, movedJSON []byte
+	if err := row.Scan(
+		&merge.ID,
+		&merge.TenantID,
+		&merge.SurvivorID,
+		&merge.MergedID,
+		&merge.Score,
+		&fieldChangesJSON,
+		&movedJSON,
+		&merge.MergedStatus,
+		&merge.Status,
+		&merge.UndoExpiresAt,
+		&merge.MergedBy,
+		&merge.UndoneBy,
+		&merge.UndoneAt,
+		&merge.CreatedAt,
+		&merge.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if len(fieldChangesJSON) > 0 {
+		if err := json.Unmarshal(fieldChangesJSON, &merge.FieldChanges); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal field changes: %w", err)
+		}
+	}
+	if len(movedJSON) > 0 {
+		if err := json.Unmarshal(movedJSON, &merge.MovedRecords); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal moved records: %w", err)
+		}
+	}
+
+	return &merge, nil
+}
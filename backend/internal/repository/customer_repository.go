@@ -76,7 +76,7 @@ func (r *CustomerRepositoryImpl) GetByID(ctx context.Context, tenantID, customer
 			id, tenant_id, first_name, last_name, email, phone, company_name,
 			address_line1, address_line2, city, state, zip_code, country,
 			preferred_contact_method, lead_source, customer_type, credit_limit,
-			payment_terms, notes, status, created_at, updated_at
+			payment_terms, notes, status, scheduling_suspended, created_at, updated_at
 		FROM customers
 		WHERE id = $1 AND tenant_id = $2 AND status != 'deleted'`
 
@@ -104,6 +104,7 @@ func (r *CustomerRepositoryImpl) GetByID(ctx context.Context, tenantID, customer
 		&customer.PaymentTerms,
 		&customer.Notes,
 		&customer.Status,
+		&customer.SchedulingSuspended,
 		&customer.CreatedAt,
 		&customer.UpdatedAt,
 	)
@@ -281,7 +282,7 @@ func (r *CustomerRepositoryImpl) List(ctx context.Context, tenantID uuid.UUID, f
 			id, tenant_id, first_name, last_name, email, phone, company_name,
 			address_line1, address_line2, city, state, zip_code, country,
 			preferred_contact_method, lead_source, customer_type, credit_limit,
-			payment_terms, notes, status, created_at, updated_at
+			payment_terms, notes, status, scheduling_suspended, created_at, updated_at
 		FROM customers ` + whereClause + paginationClause
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -314,6 +315,7 @@ func (r *CustomerRepositoryImpl) List(ctx context.Context, tenantID uuid.UUID, f
 			&customer.PaymentTerms,
 			&customer.Notes,
 			&customer.Status,
+			&customer.SchedulingSuspended,
 			&customer.CreatedAt,
 			&customer.UpdatedAt,
 		)
@@ -348,7 +350,7 @@ func (r *CustomerRepositoryImpl) GetByEmail(ctx context.Context, tenantID uuid.U
 			id, tenant_id, first_name, last_name, email, phone, company_name,
 			address_line1, address_line2, city, state, zip_code, country,
 			preferred_contact_method, lead_source, customer_type, credit_limit,
-			payment_terms, notes, status, created_at, updated_at
+			payment_terms, notes, status, scheduling_suspended, created_at, updated_at
 		FROM customers
 		WHERE tenant_id = $1 AND email = $2 AND status != 'deleted'`
 
@@ -376,6 +378,7 @@ func (r *CustomerRepositoryImpl) GetByEmail(ctx context.Context, tenantID uuid.U
 		&customer.PaymentTerms,
 		&customer.Notes,
 		&customer.Status,
+		&customer.SchedulingSuspended,
 		&customer.CreatedAt,
 		&customer.UpdatedAt,
 	)
@@ -397,7 +400,7 @@ func (r *CustomerRepositoryImpl) GetByPhone(ctx context.Context, tenantID uuid.U
 			id, tenant_id, first_name, last_name, email, phone, company_name,
 			address_line1, address_line2, city, state, zip_code, country,
 			preferred_contact_method, lead_source, customer_type, credit_limit,
-			payment_terms, notes, status, created_at, updated_at
+			payment_terms, notes, status, scheduling_suspended, created_at, updated_at
 		FROM customers
 		WHERE tenant_id = $1 AND phone = $2 AND status != 'deleted'`
 
@@ -425,6 +428,7 @@ func (r *CustomerRepositoryImpl) GetByPhone(ctx context.Context, tenantID uuid.U
 		&customer.PaymentTerms,
 		&customer.Notes,
 		&customer.Status,
+		&customer.SchedulingSuspended,
 		&customer.CreatedAt,
 		&customer.UpdatedAt,
 	)
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// DispatchRepositoryImpl implements the dispatch repository interface
+type DispatchRepositoryImpl struct {
+	db *Database
+}
+
+// NewDispatchRepository creates a new dispatch repository instance
+func NewDispatchRepository(db *Database) services.DispatchRepository {
+	return &DispatchRepositoryImpl{db: db}
+}
+
+const crewRosterColumns = `
+	id, tenant_id, crew_id, date, equipment_ids, notes, created_by, created_at, updated_at`
+
+// ListActiveCrews lists the tenant's active crews by name
+func (r *DispatchRepositoryImpl) ListActiveCrews(ctx context.Context, tenantID uuid.UUID) ([]*domain.Crew, error) {
+	query := `
+		SELECT id, tenant_id, name, description, capacity, specializations, equipment_ids,
+			status, created_at, updated_at
+		FROM crews
+		WHERE tenant_id = $1 AND status = 'active'
+		ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list crews: %w", err)
+	}
+	defer rows.Close()
+
+	crews := []*domain.Crew{}
+	for rows.Next() {
+		var crew domain.Crew
+		var specializations, equipmentIDs []byte
+		if err := rows.Scan(
+			&crew.ID,
+			&crew.TenantID,
+			&crew.Name,
+			&crew.Description,
+			&crew.Capacity,
+			&specializations,
+			&equipmentIDs,
+			&crew.Status,
+			&crew.CreatedAt,
+			&crew.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan crew: %w", err)
+		}
+		if len(specializations) > 0 {
+			if err := json.Unmarshal(specializations, &crew.Specializations); err != nil {
+				return nil, fmt.Errorf("failed to decode crew specializations: %w", err)
+			}
+		}
+		if len(equipmentIDs) > 0 {
+			if err := json.Unmarshal(equipmentIDs, &crew.EquipmentIDs); err != nil {
+				return nil, fmt.Errorf("failed to decode crew equipment: %w", err)
+			}
+		}
+		crews = append(crews, &crew)
+	}
+
+	return crews, rows.Err()
+}
+
+// ListStandingMembers lists the current members of the tenant's crews, longest serving first
+func (r *DispatchRepositoryImpl) ListStandingMembers(ctx context.Context, tenantID uuid.UUID) ([]*domain.CrewMember, error) {
+	query := `
+		SELECT cm.id, cm.crew_id, cm.user_id, cm.role, cm.joined_at, cm.left_at
+		FROM crew_members cm
+		JOIN crews c ON c.id = cm.crew_id
+		WHERE c.tenant_id = $1 AND cm.left_at IS NULL
+		ORDER BY cm.crew_id, cm.joined_at`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list crew members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []*domain.CrewMember{}
+	for rows.Next() {
+		var member domain.CrewMember
+		if err := rows.Scan(
+			&member.ID,
+			&member.CrewID,
+			&member.UserID,
+			&member.Role,
+			&member.JoinedAt,
+			&member.LeftAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan crew member: %w", err)
+		}
+		members = append(members, &member)
+	}
+
+	return members, rows.Err()
+}
+
+// ListRosters lists the rosters saved for the date, with their members
+func (r *DispatchRepositoryImpl) ListRosters(ctx context.Context, tenantID uuid.UUID, date time.Time) ([]*domain.CrewRoster, error) {
+	query := `
+		SELECT ` + crewRosterColumns + `
+		FROM crew_rosters
+		WHERE tenant_id = $1 AND date = $2::date`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list crew rosters: %w", err)
+	}
+	defer rows.Close()
+
+	rosters := []*domain.CrewRoster{}
+	byID := make(map[uuid.UUID]*domain.CrewRoster)
+	for rows.Next() {
+		roster, err := scanCrewRoster(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan crew roster: %w", err)
+		}
+		rosters = append(rosters, roster)
+		byID[roster.ID] = roster
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(rosters) == 0 {
+		return rosters, nil
+	}
+
+	ids := make([]string, 0, len(rosters))
+	for _, roster := range rosters {
+		ids = append(ids, roster.ID.String())
+	}
+
+	memberQuery := `
+		SELECT id, roster_id, user_id, role
+		FROM crew_roster_members
+		WHERE tenant_id = $1 AND roster_id = ANY($2::uuid[])
+		ORDER BY roster_id, role = 'lead' DESC, id`
+
+	memberRows, err := r.db.QueryContext(ctx, memberQuery, tenantID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list crew roster members: %w", err)
+	}
+	defer memberRows.Close()
+
+	for memberRows.Next() {
+		var member domain.CrewRosterMember
+		if err := memberRows.Scan(&member.ID, &member.RosterID, &member.UserID, &member.Role); err != nil {
+			return nil, fmt.Errorf("failed to scan crew roster member: %w", err)
+		}
+		if roster := byID[member.RosterID]; roster != nil {
+			roster.Members = append(roster.Members, &member)
+		}
+	}
+
+	return rosters, memberRows.Err()
+}
+
+// SaveRosters creates or replaces the rosters and their members in one transaction
+func (r *DispatchRepositoryImpl) SaveRosters(ctx context.Context, tenantID uuid.UUID, rosters []*domain.CrewRoster) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO crew_rosters (` + crewRosterColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (crew_id, date) DO UPDATE SET
+			equipment_ids = EXCLUDED.equipment_ids,
+			notes = EXCLUDED.notes,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id`
+
+	memberQuery := `
+		INSERT INTO crew_roster_members (id, tenant_id, roster_id, user_id, role)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	for _, roster := range rosters {
+		equipmentIDs := make([]string, len(roster.EquipmentIDs))
+		for i, id := range roster.EquipmentIDs {
+			equipmentIDs[i] = id.String()
+		}
+
+		var rosterID uuid.UUID
+		if err := tx.QueryRowContext(ctx, query,
+			roster.ID,
+			tenantID,
+			roster.CrewID,
+			roster.Date,
+			pq.Array(equipmentIDs),
+			roster.Notes,
+			roster.CreatedBy,
+			roster.CreatedAt,
+			roster.UpdatedAt,
+		).Scan(&rosterID); err != nil {
+			return fmt.Errorf("failed to save crew roster: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM crew_roster_members WHERE tenant_id = $1 AND roster_id = $2`, tenantID, rosterID); err != nil {
+			return fmt.Errorf("failed to clear crew roster members: %w", err)
+		}
+		for _, member := range roster.Members {
+			if _, err := tx.ExecContext(ctx, memberQuery, member.ID, tenantID, rosterID, member.UserID, member.Role); err != nil {
+				return fmt.Errorf("failed to add crew roster member: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRoster deletes a crew's roster for the date
+func (r *DispatchRepositoryImpl) DeleteRoster(ctx context.Context, tenantID, crewID uuid.UUID, date time.Time) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM crew_rosters WHERE tenant_id = $1 AND crew_id = $2 AND date = $3::date`, tenantID, crewID, date)
+	if err != nil {
+		return fmt.Errorf("failed to delete crew roster: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("crew roster not found")
+	}
+
+	return nil
+}
+
+// GetUserNames returns the users' display names
+func (r *DispatchRepositoryImpl) GetUserNames(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	names := make(map[uuid.UUID]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return names, nil
+	}
+
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, first_name, last_name
+		FROM users
+		WHERE tenant_id = $1 AND id = ANY($2::uuid[])`, tenantID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user names: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var firstName, lastName string
+		if err := rows.Scan(&id, &firstName, &lastName); err != nil {
+			return nil, fmt.Errorf("failed to scan user name: %w", err)
+		}
+		names[id] = strings.TrimSpace(firstName + " " + lastName)
+	}
+
+	return names, rows.Err()
+}
+
+func scanCrewRoster(row rowScanner) (*domain.CrewRoster, error) {
+	var roster domain.CrewRoster
+	var equipmentIDs pq.StringArray
+	if err := row.Scan(
+		&roster.ID,
+		&roster.TenantID,
+		&roster.CrewID,
+		&roster.Date,
+		&equipmentIDs,
+		&roster.Notes,
+		&roster.CreatedBy,
+		&roster.CreatedAt,
+		&roster.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	roster.EquipmentIDs = make([]uuid.UUID, 0, len(equipmentIDs))
+	for _, value := range equipmentIDs {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid roster equipment ID %q: %w", value, err)
+		}
+		roster.EquipmentIDs = append(roster.EquipmentIDs, id)
+	}
+	roster.Members = []*domain.CrewRosterMember{}
+	return &roster, nil
+}
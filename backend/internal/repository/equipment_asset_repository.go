@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// EquipmentAssetRepositoryImpl implements the equipment asset repository interface
+type EquipmentAssetRepositoryImpl struct {
+	db *Database
+}
+
+// NewEquipmentAssetRepository creates a new equipment asset repository instance
+func NewEquipmentAssetRepository(db *Database) services.EquipmentAssetRepository {
+	return &EquipmentAssetRepositoryImpl{db: db}
+}
+
+const equipmentAssetProfileColumns = `
+	equipment_id, tenant_id, depreciation_method, useful_life_years, salvage_value,
+	declining_balance_rate, in_service_date, replacement_cost, fuel_cost_per_hour,
+	downtime_cost_per_hour, disposed_at, disposal_proceeds, created_at, updated_at`
+
+// GetAssetProfile retrieves the asset profile of a piece of equipment
+func (r *EquipmentAssetRepositoryImpl) GetAssetProfile(ctx context.Context, tenantID, equipmentID uuid.UUID) (*domain.EquipmentAssetProfile, error) {
+	query := `
+		SELECT ` + equipmentAssetProfileColumns + `
+		FROM equipment_asset_profiles
+		WHERE tenant_id = $1 AND equipment_id = $2`
+
+	profile, err := scanEquipmentAssetProfile(r.db.QueryRowContext(ctx, query, tenantID, equipmentID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get equipment asset profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+// UpsertAssetProfile creates or updates the asset profile of a piece of equipment
+func (r *EquipmentAssetRepositoryImpl) UpsertAssetProfile(ctx context.Context, profile *domain.EquipmentAssetProfile) error {
+	query := `
+		INSERT INTO equipment_asset_profiles (` + equipmentAssetProfileColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (equipment_id) DO UPDATE SET
+			depreciation_method = EXCLUDED.depreciation_method,
+			useful_life_years = EXCLUDED.useful_life_years,
+			salvage_value = EXCLUDED.salvage_value,
+			declining_balance_rate = EXCLUDED.declining_balance_rate,
+			in_service_date = EXCLUDED.in_service_date,
+			replacement_cost = EXCLUDED.replacement_cost,
+			fuel_cost_per_hour = EXCLUDED.fuel_cost_per_hour,
+			downtime_cost_per_hour = EXCLUDED.downtime_cost_per_hour,
+			disposed_at = EXCLUDED.disposed_at,
+			disposal_proceeds = EXCLUDED.disposal_proceeds,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.ExecContext(ctx, query,
+		profile.EquipmentID,
+		profile.TenantID,
+		profile.DepreciationMethod,
+		profile.UsefulLifeYears,
+		profile.SalvageValue,
+		profile.DecliningBalanceRate,
+		profile.InServiceDate,
+		profile.ReplacementCost,
+		profile.FuelCostPerHour,
+		profile.DowntimeCostPerHour,
+		profile.DisposedAt,
+		profile.DisposalProceeds,
+		profile.CreatedAt,
+		profile.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert equipment asset profile: %w", err)
+	}
+
+	return nil
+}
+
+// ListAssetProfiles lists the tenant's equipment asset profiles
+func (r *EquipmentAssetRepositoryImpl) ListAssetProfiles(ctx context.Context, tenantID uuid.UUID) ([]*domain.EquipmentAssetProfile, error) {
+	query := `
+		SELECT ` + equipmentAssetProfileColumns + `
+		FROM equipment_asset_profiles
+		WHERE tenant_id = $1
+		ORDER BY in_service_date, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list equipment asset profiles: %w", err)
+	}
+	defer rows.Close()
+
+	profiles := []*domain.EquipmentAssetProfile{}
+	for rows.Next() {
+		profile, err := scanEquipmentAssetProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan equipment asset profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, rows.Err()
+}
+
+func scanEquipmentAssetProfile(row rowScanner) (*domain.EquipmentAssetProfile, error) {
+	var profile domain.EquipmentAssetProfile
+	if err := row.Scan(
+		&profile.EquipmentID,
+		&profile.TenantID,
+		&profile.DepreciationMethod,
+		&profile.UsefulLifeYears,
+		&profile.SalvageValue,
+		&profile.DecliningBalanceRate,
+		&profile.InServiceDate,
+		&profile.ReplacementCost,
+		&profile.FuelCostPerHour,
+		&profile.DowntimeCostPerHour,
+		&profile.DisposedAt,
+		&profile.DisposalProceeds,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
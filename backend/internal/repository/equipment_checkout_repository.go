@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// EquipmentCheckoutRepositoryImpl implements the equipment checkout repository interface
+type EquipmentCheckoutRepositoryImpl struct {
+	db *Database
+}
+
+// NewEquipmentCheckoutRepository creates a new equipment checkout repository instance
+func NewEquipmentCheckoutRepository(db *Database) services.EquipmentCheckoutRepository {
+	return &EquipmentCheckoutRepositoryImpl{db: db}
+}
+
+const equipmentAssetTagColumns = `equipment_id, tenant_id, code, created_at`
+
+const equipmentCheckoutColumns = `
+	id, tenant_id, equipment_id, user_id, crew_id, checked_out_at, checked_out_by,
+	due_back_at, checkout_notes, checked_in_at, checked_in_by, return_condition,
+	return_notes, overdue_notified_at, created_at, updated_at`
+
+// CreateAssetTag stores an asset tag
+func (r *EquipmentCheckoutRepositoryImpl) CreateAssetTag(ctx context.Context, tag *domain.EquipmentAssetTag) error {
+	query := `
+		INSERT INTO equipment_asset_tags (` + equipmentAssetTagColumns + `)
+		VALUES ($1, $2, $3, $4)`
+
+	if _, err := r.db.ExecContext(ctx, query, tag.EquipmentID, tag.TenantID, tag.Code, tag.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create asset tag: %w", err)
+	}
+
+	return nil
+}
+
+// GetAssetTag retrieves the asset tag of a piece of equipment
+func (r *EquipmentCheckoutRepositoryImpl) GetAssetTag(ctx context.Context, tenantID, equipmentID uuid.UUID) (*domain.EquipmentAssetTag, error) {
+	query := `
+		SELECT ` + equipmentAssetTagColumns + `
+		FROM equipment_asset_tags
+		WHERE tenant_id = $1 AND equipment_id = $2`
+
+	tag, err := scanEquipmentAssetTag(r.db.QueryRowContext(ctx, query, tenantID, equipmentID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get asset tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// GetAssetTagByCode retrieves an asset tag by its code
+func (r *EquipmentCheckoutRepositoryImpl) GetAssetTagByCode(ctx context.Context, tenantID uuid.UUID, code string) (*domain.EquipmentAssetTag, error) {
+	query := `
+		SELECT ` + equipmentAssetTagColumns + `
+		FROM equipment_asset_tags
+		WHERE tenant_id = $1 AND code = $2`
+
+	tag, err := scanEquipmentAssetTag(r.db.QueryRowContext(ctx, query, tenantID, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get asset tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// ListAssetTags lists the asset tags of the equipment
+func (r *EquipmentCheckoutRepositoryImpl) ListAssetTags(ctx context.Context, tenantID uuid.UUID, equipmentIDs []uuid.UUID) ([]*domain.EquipmentAssetTag, error) {
+	ids := make([]string, len(equipmentIDs))
+	for i, id := range equipmentIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT ` + equipmentAssetTagColumns + `
+		FROM equipment_asset_tags
+		WHERE tenant_id = $1 AND equipment_id = ANY($2::uuid[])`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list asset tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []*domain.EquipmentAssetTag{}
+	for rows.Next() {
+		tag, err := scanEquipmentAssetTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan asset tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// CreateCheckout stores a checkout. The open checkout index rejects a second
+// open checkout for the same equipment.
+func (r *EquipmentCheckoutRepositoryImpl) CreateCheckout(ctx context.Context, checkout *domain.EquipmentCheckout) error {
+	query := `
+		INSERT INTO equipment_checkouts (` + equipmentCheckoutColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		checkout.ID,
+		checkout.TenantID,
+		checkout.EquipmentID,
+		checkout.UserID,
+		checkout.CrewID,
+		checkout.CheckedOutAt,
+		checkout.CheckedOutBy,
+		checkout.DueBackAt,
+		checkout.CheckoutNotes,
+		checkout.CheckedInAt,
+		checkout.CheckedInBy,
+		checkout.ReturnCondition,
+		checkout.ReturnNotes,
+		checkout.OverdueNotifiedAt,
+		checkout.CreatedAt,
+		checkout.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("equipment is already checked out")
+		}
+		return fmt.Errorf("failed to create checkout: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateCheckout updates a checkout
+func (r *EquipmentCheckoutRepositoryImpl) UpdateCheckout(ctx context.Context, checkout *domain.EquipmentCheckout) error {
+	query := `
+		UPDATE equipment_checkouts SET
+			user_id = $3, crew_id = $4, due_back_at = $5, checkout_notes = $6,
+			checked_in_at = $7, checked_in_by = $8, return_condition = $9,
+			return_notes = $10, overdue_notified_at = $11, updated_at = $12
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		checkout.TenantID,
+		checkout.ID,
+		checkout.UserID,
+		checkout.CrewID,
+		checkout.DueBackAt,
+		checkout.CheckoutNotes,
+		checkout.CheckedInAt,
+		checkout.CheckedInBy,
+		checkout.ReturnCondition,
+		checkout.ReturnNotes,
+		checkout.OverdueNotifiedAt,
+		checkout.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update checkout: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("checkout not found")
+	}
+
+	return nil
+}
+
+// GetOpenCheckout retrieves the equipment's open checkout, if it is checked out
+func (r *EquipmentCheckoutRepositoryImpl) GetOpenCheckout(ctx context.Context, tenantID, equipmentID uuid.UUID) (*domain.EquipmentCheckout, error) {
+	query := `
+		SELECT ` + equipmentCheckoutColumns + `
+		FROM equipment_checkouts
+		WHERE tenant_id = $1 AND equipment_id = $2 AND checked_in_at IS NULL`
+
+	checkout, err := scanEquipmentCheckout(r.db.QueryRowContext(ctx, query, tenantID, equipmentID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get open checkout: %w", err)
+	}
+
+	return checkout, nil
+}
+
+// ListCheckouts lists checkouts most recent first
+func (r *EquipmentCheckoutRepositoryImpl) ListCheckouts(ctx context.Context, tenantID uuid.UUID, filter *services.EquipmentCheckoutFilter) ([]*domain.EquipmentCheckout, error) {
+	var limit interface{}
+	if filter.Limit > 0 {
+		limit = filter.Limit
+	}
+
+	query := `
+		SELECT ` + equipmentCheckoutColumns + `
+		FROM equipment_checkouts
+		WHERE tenant_id = $1
+			AND ($2::uuid IS NULL OR equipment_id = $2)
+			AND ($3::uuid IS NULL OR user_id = $3)
+			AND ($4::uuid IS NULL OR crew_id = $4)
+			AND (NOT $5 OR checked_in_at IS NULL)
+		ORDER BY checked_out_at DESC
+		LIMIT $6`
+
+	return r.listCheckouts(ctx, query, tenantID, filter.EquipmentID, filter.UserID, filter.CrewID, filter.OpenOnly, limit)
+}
+
+// ListOverdueCheckouts lists open checkouts across all tenants that were due
+// back before asOf and have not been reported overdue
+func (r *EquipmentCheckoutRepositoryImpl) ListOverdueCheckouts(ctx context.Context, asOf time.Time) ([]*domain.EquipmentCheckout, error) {
+	query := `
+		SELECT ` + equipmentCheckoutColumns + `
+		FROM equipment_checkouts
+		WHERE checked_in_at IS NULL AND overdue_notified_at IS NULL AND due_back_at < $1
+		ORDER BY due_back_at`
+
+	return r.listCheckouts(ctx, query, asOf)
+}
+
+// MarkOverdueNotified records that a checkout was reported overdue
+func (r *EquipmentCheckoutRepositoryImpl) MarkOverdueNotified(ctx context.Context, tenantID, checkoutID uuid.UUID, notifiedAt time.Time) error {
+	query := `
+		UPDATE equipment_checkouts SET overdue_notified_at = $3, updated_at = $3
+		WHERE tenant_id = $1 AND id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, tenantID, checkoutID, notifiedAt); err != nil {
+		return fmt.Errorf("failed to mark checkout overdue notified: %w", err)
+	}
+
+	return nil
+}
+
+func (r *EquipmentCheckoutRepositoryImpl) listCheckouts(ctx context.Context, query string, args ...interface{}) ([]*domain.EquipmentCheckout, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkouts: %w", err)
+	}
+	defer rows.Close()
+
+	checkouts := []*domain.EquipmentCheckout{}
+	for rows.Next() {
+		checkout, err := scanEquipmentCheckout(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan checkout: %w", err)
+		}
+		checkouts = append(checkouts, checkout)
+	}
+
+	return checkouts, rows.Err()
+}
+
+func scanEquipmentAssetTag(row rowScanner) (*domain.EquipmentAssetTag, error) {
+	var tag domain.EquipmentAssetTag
+	if err := row.Scan(&tag.EquipmentID, &tag.TenantID, &tag.Code, &tag.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func scanEquipmentCheckout(row rowScanner) (*domain.EquipmentCheckout, error) {
+	var checkout domain.EquipmentCheckout
+	if err := row.Scan(
+		&checkout.ID,
+		&checkout.TenantID,
+		&checkout.EquipmentID,
+		&checkout.UserID,
+		&checkout.CrewID,
+		&checkout.CheckedOutAt,
+		&checkout.CheckedOutBy,
+		&checkout.DueBackAt,
+		&checkout.CheckoutNotes,
+		&checkout.CheckedInAt,
+		&checkout.CheckedInBy,
+		&checkout.ReturnCondition,
+		&checkout.ReturnNotes,
+		&checkout.OverdueNotifiedAt,
+		&checkout.CreatedAt,
+		&checkout.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &checkout, nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// FuelRepositoryImpl implements the fuel repository interface
+type FuelRepositoryImpl struct {
+	db *Database
+}
+
+// NewFuelRepository creates a new fuel repository instance
+func NewFuelRepository(db *Database) services.FuelRepository {
+	return &FuelRepositoryImpl{db: db}
+}
+
+const fuelEntryColumns = `
+	id, tenant_id, equipment_id, fueled_at, fuel_type, gallons, price_per_gallon,
+	total_cost, odometer, engine_hours, meter, usage, efficiency, anomaly_flags,
+	vendor, recorded_by, notes, created_at`
+
+// CreateFuelEntry stores a fuel entry
+func (r *FuelRepositoryImpl) CreateFuelEntry(ctx context.Context, entry *domain.FuelEntry) error {
+	query := `
+		INSERT INTO fuel_entries (` + fuelEntryColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		entry.ID,
+		entry.TenantID,
+		entry.EquipmentID,
+		entry.FueledAt,
+		entry.FuelType,
+		entry.Gallons,
+		entry.PricePerGallon,
+		entry.TotalCost,
+		entry.Odometer,
+		entry.EngineHours,
+		entry.Meter,
+		entry.Usage,
+		entry.Efficiency,
+		pq.Array(entry.AnomalyFlags),
+		entry.Vendor,
+		entry.RecordedBy,
+		entry.Notes,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create fuel entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListFuelEntries lists fuel entries oldest first, keeping the most recent
+// filter.Limit entries when a limit is set
+func (r *FuelRepositoryImpl) ListFuelEntries(ctx context.Context, tenantID uuid.UUID, filter *services.FuelEntryFilter) ([]*domain.FuelEntry, error) {
+	ids := make([]string, len(filter.EquipmentIDs))
+	for i, id := range filter.EquipmentIDs {
+		ids[i] = id.String()
+	}
+
+	var limit interface{}
+	if filter.Limit > 0 {
+		limit = filter.Limit
+	}
+
+	query := `
+		SELECT ` + fuelEntryColumns + `
+		FROM (
+			SELECT ` + fuelEntryColumns + `
+			FROM fuel_entries
+			WHERE tenant_id = $1
+				AND (cardinality($2::uuid[]) = 0 OR equipment_id = ANY($2::uuid[]))
+				AND ($3::timestamptz IS NULL OR fueled_at >= $3)
+				AND ($4::timestamptz IS NULL OR fueled_at <= $4)
+				AND (NOT $5 OR cardinality(anomaly_flags) > 0)
+			ORDER BY fueled_at DESC, created_at DESC
+			LIMIT $6
+		) recent
+		ORDER BY fueled_at, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, pq.Array(ids), filter.StartDate, filter.EndDate, filter.AnomaliesOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fuel entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.FuelEntry{}
+	for rows.Next() {
+		entry, err := scanFuelEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan fuel entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// GetPreviousFuelEntry retrieves the equipment's latest fuel entry before the
+// given time with a reading on the meter
+func (r *FuelRepositoryImpl) GetPreviousFuelEntry(ctx context.Context, tenantID, equipmentID uuid.UUID, meter string, before time.Time) (*domain.FuelEntry, error) {
+	query := `
+		SELECT ` + fuelEntryColumns + `
+		FROM fuel_entries
+		WHERE tenant_id = $1 AND equipment_id = $2 AND meter = $3 AND fueled_at < $4
+		ORDER BY fueled_at DESC, created_at DESC
+		LIMIT 1`
+
+	entry, err := scanFuelEntry(r.db.QueryRowContext(ctx, query, tenantID, equipmentID, meter, before))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get previous fuel entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+func scanFuelEntry(row rowScanner) (*domain.FuelEntry, error) {
+	var entry domain.FuelEntry
+	var anomalyFlags pq.StringArray
+	if err := row.Scan(
+		&entry.ID,
+		&entry.TenantID,
+		&entry.EquipmentID,
+		&entry.FueledAt,
+		&entry.FuelType,
+		&entry.Gallons,
+		&entry.PricePerGallon,
+		&entry.TotalCost,
+		&entry.Odometer,
+		&entry.EngineHours,
+		&entry.Meter,
+		&entry.Usage,
+		&entry.Efficiency,
+		&anomalyFlags,
+		&entry.Vendor,
+		&entry.RecordedBy,
+		&entry.Notes,
+		&entry.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	entry.AnomalyFlags = []string(anomalyFlags)
+	return &entry, nil
+}
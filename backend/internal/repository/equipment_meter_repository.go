@@ -0,0 +1,341 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// EquipmentMeterRepositoryImpl implements the equipment meter repository interface
+type EquipmentMeterRepositoryImpl struct {
+	db *Database
+}
+
+// NewEquipmentMeterRepository creates a new equipment meter repository instance
+func NewEquipmentMeterRepository(db *Database) services.EquipmentMeterRepository {
+	return &EquipmentMeterRepositoryImpl{db: db}
+}
+
+const meterReadingColumns = `
+	id, tenant_id, equipment_id, meter, reading, usage, source, job_id,
+	recorded_by, recorded_at, notes, created_at`
+
+const usageTriggerColumns = `
+	id, tenant_id, equipment_id, meter, service_interval, last_service_reading,
+	next_due_reading, created_at, updated_at`
+
+const insertUsageTriggerQuery = `
+	INSERT INTO maintenance_usage_triggers (` + usageTriggerColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+// CreateReading stores a meter reading
+func (r *EquipmentMeterRepositoryImpl) CreateReading(ctx context.Context, reading *domain.EquipmentMeterReading) error {
+	query := `
+		INSERT INTO equipment_meter_readings (` + meterReadingColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		reading.ID,
+		reading.TenantID,
+		reading.EquipmentID,
+		reading.Meter,
+		reading.Reading,
+		reading.Usage,
+		reading.Source,
+		reading.JobID,
+		reading.RecordedBy,
+		reading.RecordedAt,
+		reading.Notes,
+		reading.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create meter reading: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestReading retrieves the highest reading on one of an equipment's meters
+func (r *EquipmentMeterRepositoryImpl) GetLatestReading(ctx context.Context, tenantID, equipmentID uuid.UUID, meter string) (*domain.EquipmentMeterReading, error) {
+	query := `
+		SELECT ` + meterReadingColumns + `
+		FROM equipment_meter_readings
+		WHERE tenant_id = $1 AND equipment_id = $2 AND meter = $3
+		ORDER BY reading DESC, recorded_at DESC
+		LIMIT 1`
+
+	reading, err := scanMeterReading(r.db.QueryRowContext(ctx, query, tenantID, equipmentID, meter))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest meter reading: %w", err)
+	}
+
+	return reading, nil
+}
+
+// ListReadings lists an equipment's most recent readings, on every meter when meter is empty
+func (r *EquipmentMeterRepositoryImpl) ListReadings(ctx context.Context, tenantID, equipmentID uuid.UUID, meter string, limit int) ([]*domain.EquipmentMeterReading, error) {
+	query := `
+		SELECT ` + meterReadingColumns + `
+		FROM equipment_meter_readings
+		WHERE tenant_id = $1 AND equipment_id = $2 AND ($3 = '' OR meter = $3)
+		ORDER BY recorded_at DESC, reading DESC
+		LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, equipmentID, meter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list meter readings: %w", err)
+	}
+	defer rows.Close()
+
+	readings := []*domain.EquipmentMeterReading{}
+	for rows.Next() {
+		reading, err := scanMeterReading(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan meter reading: %w", err)
+		}
+		readings = append(readings, reading)
+	}
+
+	return readings, rows.Err()
+}
+
+// GetCurrentReadings returns the highest reading on each of the equipment's meters
+func (r *EquipmentMeterRepositoryImpl) GetCurrentReadings(ctx context.Context, tenantID, equipmentID uuid.UUID) (map[string]float64, error) {
+	query := `
+		SELECT meter, MAX(reading)
+		FROM equipment_meter_readings
+		WHERE tenant_id = $1 AND equipment_id = $2
+		GROUP BY meter`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, equipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current meter readings: %w", err)
+	}
+	defer rows.Close()
+
+	readings := make(map[string]float64)
+	for rows.Next() {
+		var meter string
+		var reading float64
+		if err := rows.Scan(&meter, &reading); err != nil {
+			return nil, fmt.Errorf("failed to scan meter reading: %w", err)
+		}
+		readings[meter] = reading
+	}
+
+	return readings, rows.Err()
+}
+
+// AverageJobUsage averages the usage of the most recent job readings on a meter
+func (r *EquipmentMeterRepositoryImpl) AverageJobUsage(ctx context.Context, tenantID, equipmentID uuid.UUID, meter string, sampleSize int) (float64, error) {
+	query := `
+		SELECT COALESCE(AVG(usage), 0)
+		FROM (
+			SELECT usage
+			FROM equipment_meter_readings
+			WHERE tenant_id = $1 AND equipment_id = $2 AND meter = $3 AND source = $4
+			ORDER BY recorded_at DESC
+			LIMIT $5
+		) recent`
+
+	var average float64
+	if err := r.db.QueryRowContext(ctx, query, tenantID, equipmentID, meter, domain.MeterReadingSourceJob, sampleSize).Scan(&average); err != nil {
+		return 0, fmt.Errorf("failed to get average job usage: %w", err)
+	}
+
+	return average, nil
+}
+
+// SumUsage totals the usage recorded on a meter between start and end
+func (r *EquipmentMeterRepositoryImpl) SumUsage(ctx context.Context, tenantID, equipmentID uuid.UUID, meter string, start, end time.Time) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(usage), 0)
+		FROM equipment_meter_readings
+		WHERE tenant_id = $1 AND equipment_id = $2 AND meter = $3
+			AND recorded_at >= $4 AND recorded_at < $5`
+
+	var total float64
+	if err := r.db.QueryRowContext(ctx, query, tenantID, equipmentID, meter, start, end).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to sum meter usage: %w", err)
+	}
+
+	return total, nil
+}
+
+// ListJobReadings lists the readings recorded against jobs between the optional
+// dates, for every job when jobIDs is empty
+func (r *EquipmentMeterRepositoryImpl) ListJobReadings(ctx context.Context, tenantID uuid.UUID, jobIDs []uuid.UUID, start, end *time.Time) ([]*domain.EquipmentMeterReading, error) {
+	ids := make([]string, len(jobIDs))
+	for i, id := range jobIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT ` + meterReadingColumns + `
+		FROM equipment_meter_readings
+		WHERE tenant_id = $1 AND job_id IS NOT NULL
+			AND (cardinality($2::uuid[]) = 0 OR job_id = ANY($2::uuid[]))
+			AND ($3::timestamptz IS NULL OR recorded_at >= $3)
+			AND ($4::timestamptz IS NULL OR recorded_at < $4)
+		ORDER BY recorded_at, job_id`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, pq.Array(ids), start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job meter readings: %w", err)
+	}
+	defer rows.Close()
+
+	readings := []*domain.EquipmentMeterReading{}
+	for rows.Next() {
+		reading, err := scanMeterReading(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan meter reading: %w", err)
+		}
+		readings = append(readings, reading)
+	}
+
+	return readings, rows.Err()
+}
+
+// ListUsageTriggers lists usage triggers for one equipment, or the whole tenant when equipmentID is nil
+func (r *EquipmentMeterRepositoryImpl) ListUsageTriggers(ctx context.Context, tenantID uuid.UUID, equipmentID *uuid.UUID) ([]*domain.MaintenanceUsageTrigger, error) {
+	query := `
+		SELECT ` + usageTriggerColumns + `
+		FROM maintenance_usage_triggers
+		WHERE tenant_id = $1 AND ($2::uuid IS NULL OR equipment_id = $2)
+		ORDER BY equipment_id, meter`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, equipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage triggers: %w", err)
+	}
+	defer rows.Close()
+
+	triggers := []*domain.MaintenanceUsageTrigger{}
+	for rows.Next() {
+		trigger, err := scanUsageTrigger(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan usage trigger: %w", err)
+		}
+		triggers = append(triggers, trigger)
+	}
+
+	return triggers, rows.Err()
+}
+
+// ReplaceUsageTriggers deletes an equipment's usage triggers and stores the new set
+func (r *EquipmentMeterRepositoryImpl) ReplaceUsageTriggers(ctx context.Context, tenantID, equipmentID uuid.UUID, triggers []*domain.MaintenanceUsageTrigger) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM maintenance_usage_triggers WHERE tenant_id = $1 AND equipment_id = $2`, tenantID, equipmentID); err != nil {
+		return fmt.Errorf("failed to clear usage triggers: %w", err)
+	}
+
+	for _, trigger := range triggers {
+		if _, err := tx.ExecContext(ctx, insertUsageTriggerQuery,
+			trigger.ID,
+			trigger.TenantID,
+			trigger.EquipmentID,
+			trigger.Meter,
+			trigger.Interval,
+			trigger.LastServiceReading,
+			trigger.NextDueReading,
+			trigger.CreatedAt,
+			trigger.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to create usage trigger: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateUsageTrigger saves a trigger's interval and service readings
+func (r *EquipmentMeterRepositoryImpl) UpdateUsageTrigger(ctx context.Context, trigger *domain.MaintenanceUsageTrigger) error {
+	query := `
+		UPDATE maintenance_usage_triggers SET
+			service_interval = $3,
+			last_service_reading = $4,
+			next_due_reading = $5,
+			updated_at = $6
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		trigger.TenantID,
+		trigger.ID,
+		trigger.Interval,
+		trigger.LastServiceReading,
+		trigger.NextDueReading,
+		trigger.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update usage trigger: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("usage trigger not found")
+	}
+
+	return nil
+}
+
+func scanMeterReading(row rowScanner) (*domain.EquipmentMeterReading, error) {
+	var reading domain.EquipmentMeterReading
+	if err := row.Scan(
+		&reading.ID,
+		&reading.TenantID,
+		&reading.EquipmentID,
+		&reading.Meter,
+		&reading.Reading,
+		&reading.Usage,
+		&reading.Source,
+		&reading.JobID,
+		&reading.RecordedBy,
+		&reading.RecordedAt,
+		&reading.Notes,
+		&reading.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &reading, nil
+}
+
+func scanUsageTrigger(row rowScanner) (*domain.MaintenanceUsageTrigger, error) {
+	var trigger domain.MaintenanceUsageTrigger
+	if err := row.Scan(
+		&trigger.ID,
+		&trigger.TenantID,
+		&trigger.EquipmentID,
+		&trigger.Meter,
+		&trigger.Interval,
+		&trigger.LastServiceReading,
+		&trigger.NextDueReading,
+		&trigger.CreatedAt,
+		&trigger.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &trigger, nil
+}
@@ -29,9 +29,10 @@ func (r *EquipmentRepositoryImpl) Create(ctx context.Context, equipment *domain.
 		INSERT INTO equipment (
 			id, tenant_id, name, type, model, serial_number, purchase_date, purchase_price,
 			status, maintenance_schedule, last_maintenance, next_maintenance, notes,
+			weight_lbs, payload_capacity_lbs, towing_capacity_lbs,
 			created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
 		)`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -48,6 +49,9 @@ func (r *EquipmentRepositoryImpl) Create(ctx context.Context, equipment *domain.
 		equipment.LastMaintenance,
 		equipment.NextMaintenance,
 		equipment.Notes,
+		equipment.WeightLbs,
+		equipment.PayloadCapacityLbs,
+		equipment.TowingCapacityLbs,
 		equipment.CreatedAt,
 		equipment.UpdatedAt,
 	)
@@ -64,6 +68,7 @@ func (r *EquipmentRepositoryImpl) GetByID(ctx context.Context, tenantID, equipme
 	query := `
 		SELECT id, tenant_id, name, type, model, serial_number, purchase_date, purchase_price,
 			   status, maintenance_schedule, last_maintenance, next_maintenance, notes,
+			   weight_lbs, payload_capacity_lbs, towing_capacity_lbs,
 			   created_at, updated_at
 		FROM equipment
 		WHERE id = $1 AND tenant_id = $2 AND status != 'deleted'`
@@ -83,6 +88,9 @@ func (r *EquipmentRepositoryImpl) GetByID(ctx context.Context, tenantID, equipme
 		&equipment.LastMaintenance,
 		&equipment.NextMaintenance,
 		&equipment.Notes,
+		&equipment.WeightLbs,
+		&equipment.PayloadCapacityLbs,
+		&equipment.TowingCapacityLbs,
 		&equipment.CreatedAt,
 		&equipment.UpdatedAt,
 	)
@@ -103,7 +111,8 @@ func (r *EquipmentRepositoryImpl) Update(ctx context.Context, equipment *domain.
 		UPDATE equipment SET
 			name = $3, type = $4, model = $5, serial_number = $6, purchase_date = $7,
 			purchase_price = $8, status = $9, maintenance_schedule = $10,
-			last_maintenance = $11, next_maintenance = $12, notes = $13, updated_at = $14
+			last_maintenance = $11, next_maintenance = $12, notes = $13, weight_lbs = $14,
+			payload_capacity_lbs = $15, towing_capacity_lbs = $16, updated_at = $17
 		WHERE id = $1 AND tenant_id = $2`
 
 	result, err := r.db.ExecContext(ctx, query,
@@ -120,6 +129,9 @@ func (r *EquipmentRepositoryImpl) Update(ctx context.Context, equipment *domain.
 		equipment.LastMaintenance,
 		equipment.NextMaintenance,
 		equipment.Notes,
+		equipment.WeightLbs,
+		equipment.PayloadCapacityLbs,
+		equipment.TowingCapacityLbs,
 		equipment.UpdatedAt,
 	)
 
@@ -191,6 +203,19 @@ func (r *EquipmentRepositoryImpl) List(ctx context.Context, tenantID uuid.UUID,
 	// Note: Available and Maintenance fields don't exist in EquipmentFilter
 	// These filters have been removed
 
+	// Equipment currently checked out to the user or crew
+	if filter.AssignedUserID != nil {
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM equipment_checkouts c WHERE c.equipment_id = equipment.id AND c.checked_in_at IS NULL AND c.user_id = $%d)", argIndex))
+		args = append(args, *filter.AssignedUserID)
+		argIndex++
+	}
+
+	if filter.AssignedCrewID != nil {
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM equipment_checkouts c WHERE c.equipment_id = equipment.id AND c.checked_in_at IS NULL AND c.crew_id = $%d)", argIndex))
+		args = append(args, *filter.AssignedCrewID)
+		argIndex++
+	}
+
 	if filter.Search != nil && *filter.Search != "" {
 		conditions = append(conditions, fmt.Sprintf("(name ILIKE $%d OR type ILIKE $%d OR model ILIKE $%d)", argIndex, argIndex, argIndex))
 		args = append(args, "%"+*filter.Search+"%")
@@ -214,6 +239,7 @@ func (r *EquipmentRepositoryImpl) List(ctx context.Context, tenantID uuid.UUID,
 	selectFields := `
 		SELECT id, tenant_id, name, type, model, serial_number, purchase_date, purchase_price,
 			   status, maintenance_schedule, last_maintenance, next_maintenance, notes,
+			   weight_lbs, payload_capacity_lbs, towing_capacity_lbs,
 			   created_at, updated_at`
 
 	orderBy := " ORDER BY name ASC"
@@ -249,6 +275,9 @@ func (r *EquipmentRepositoryImpl) List(ctx context.Context, tenantID uuid.UUID,
 			&eq.LastMaintenance,
 			&eq.NextMaintenance,
 			&eq.Notes,
+			&eq.WeightLbs,
+			&eq.PayloadCapacityLbs,
+			&eq.TowingCapacityLbs,
 			&eq.CreatedAt,
 			&eq.UpdatedAt,
 		)
@@ -270,6 +299,7 @@ func (r *EquipmentRepositoryImpl) GetByType(ctx context.Context, tenantID uuid.U
 	query := `
 		SELECT id, tenant_id, name, type, model, serial_number, purchase_date, purchase_price,
 			   status, maintenance_schedule, last_maintenance, next_maintenance, notes,
+			   weight_lbs, payload_capacity_lbs, towing_capacity_lbs,
 			   created_at, updated_at
 		FROM equipment
 		WHERE tenant_id = $1 AND type = $2 AND status != 'deleted'
@@ -298,6 +328,9 @@ func (r *EquipmentRepositoryImpl) GetByType(ctx context.Context, tenantID uuid.U
 			&eq.LastMaintenance,
 			&eq.NextMaintenance,
 			&eq.Notes,
+			&eq.WeightLbs,
+			&eq.PayloadCapacityLbs,
+			&eq.TowingCapacityLbs,
 			&eq.CreatedAt,
 			&eq.UpdatedAt,
 		)
@@ -315,6 +348,7 @@ func (r *EquipmentRepositoryImpl) GetByStatus(ctx context.Context, tenantID uuid
 	query := `
 		SELECT id, tenant_id, name, type, model, serial_number, purchase_date, purchase_price,
 			   status, maintenance_schedule, last_maintenance, next_maintenance, notes,
+			   weight_lbs, payload_capacity_lbs, towing_capacity_lbs,
 			   created_at, updated_at
 		FROM equipment
 		WHERE tenant_id = $1 AND status = $2
@@ -343,6 +377,9 @@ func (r *EquipmentRepositoryImpl) GetByStatus(ctx context.Context, tenantID uuid
 			&eq.LastMaintenance,
 			&eq.NextMaintenance,
 			&eq.Notes,
+			&eq.WeightLbs,
+			&eq.PayloadCapacityLbs,
+			&eq.TowingCapacityLbs,
 			&eq.CreatedAt,
 			&eq.UpdatedAt,
 		)
@@ -360,6 +397,7 @@ func (r *EquipmentRepositoryImpl) GetByAssignedUser(ctx context.Context, tenantI
 	query := `
 		SELECT id, tenant_id, name, type, model, serial_number, purchase_date, purchase_price,
 			   status, maintenance_schedule, last_maintenance, next_maintenance, notes,
+			   weight_lbs, payload_capacity_lbs, towing_capacity_lbs,
 			   created_at, updated_at
 		FROM equipment
 		WHERE tenant_id = $1 AND assigned_user_id = $2 AND status != 'deleted'
@@ -388,6 +426,9 @@ func (r *EquipmentRepositoryImpl) GetByAssignedUser(ctx context.Context, tenantI
 			&eq.LastMaintenance,
 			&eq.NextMaintenance,
 			&eq.Notes,
+			&eq.WeightLbs,
+			&eq.PayloadCapacityLbs,
+			&eq.TowingCapacityLbs,
 			&eq.CreatedAt,
 			&eq.UpdatedAt,
 		)
@@ -405,6 +446,7 @@ func (r *EquipmentRepositoryImpl) GetByLocation(ctx context.Context, tenantID uu
 	query := `
 		SELECT id, tenant_id, name, type, model, serial_number, purchase_date, purchase_price,
 			   status, maintenance_schedule, last_maintenance, next_maintenance, notes,
+			   weight_lbs, payload_capacity_lbs, towing_capacity_lbs,
 			   created_at, updated_at
 		FROM equipment
 		WHERE tenant_id = $1 AND location = $2 AND status != 'deleted'
@@ -433,6 +475,9 @@ func (r *EquipmentRepositoryImpl) GetByLocation(ctx context.Context, tenantID uu
 			&eq.LastMaintenance,
 			&eq.NextMaintenance,
 			&eq.Notes,
+			&eq.WeightLbs,
+			&eq.PayloadCapacityLbs,
+			&eq.TowingCapacityLbs,
 			&eq.CreatedAt,
 			&eq.UpdatedAt,
 		)
@@ -563,6 +608,7 @@ func (r *EquipmentRepositoryImpl) GetByIDs(ctx context.Context, tenantID uuid.UU
 	query := `
 		SELECT id, tenant_id, name, type, model, serial_number, purchase_date, purchase_price,
 			   status, maintenance_schedule, last_maintenance, next_maintenance, notes,
+			   weight_lbs, payload_capacity_lbs, towing_capacity_lbs,
 			   created_at, updated_at
 		FROM equipment
 		WHERE tenant_id = $1 AND id = ANY($2) AND status != 'deleted'
@@ -597,6 +643,9 @@ func (r *EquipmentRepositoryImpl) GetByIDs(ctx context.Context, tenantID uuid.UU
 			&eq.LastMaintenance,
 			&eq.NextMaintenance,
 			&eq.Notes,
+			&eq.WeightLbs,
+			&eq.PayloadCapacityLbs,
+			&eq.TowingCapacityLbs,
 			&eq.CreatedAt,
 			&eq.UpdatedAt,
 		)
@@ -611,24 +660,19 @@ func (r *EquipmentRepositoryImpl) GetByIDs(ctx context.Context, tenantID uuid.UU
 
 // GetAvailable retrieves available equipment for a time period
 func (r *EquipmentRepositoryImpl) GetAvailable(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time) ([]*domain.Equipment, error) {
-	// This is a simplified version - in reality, you'd need to check job assignments and maintenance schedules
 	query := `
 		SELECT e.id, e.tenant_id, e.name, e.type, e.model, e.serial_number, e.purchase_date, e.purchase_price,
 			   e.status, e.maintenance_schedule, e.last_maintenance, e.next_maintenance, e.notes,
+			   e.weight_lbs, e.payload_capacity_lbs, e.towing_capacity_lbs,
 			   e.created_at, e.updated_at
 		FROM equipment e
 		WHERE e.tenant_id = $1 
 		  AND e.status = 'available'
 		  AND (e.next_maintenance IS NULL OR e.next_maintenance > $3)
 		  AND NOT EXISTS (
-			  SELECT 1 FROM job_equipment je
-			  JOIN jobs j ON je.job_id = j.id
-			  WHERE je.equipment_id = e.id
-			    AND j.status IN ('in_progress', 'scheduled')
-			    AND (
-			        (j.scheduled_start_time <= $3 AND j.scheduled_end_time >= $2) OR
-			        (j.actual_start_time <= $3 AND (j.actual_end_time IS NULL OR j.actual_end_time >= $2))
-			    )
+			  SELECT 1 FROM equipment_reservations er
+			  WHERE er.equipment_id = e.id
+			    AND er.starts_at < $3 AND er.ends_at > $2
 		  )
 		ORDER BY e.name ASC`
 
@@ -655,6 +699,9 @@ func (r *EquipmentRepositoryImpl) GetAvailable(ctx context.Context, tenantID uui
 			&eq.LastMaintenance,
 			&eq.NextMaintenance,
 			&eq.Notes,
+			&eq.WeightLbs,
+			&eq.PayloadCapacityLbs,
+			&eq.TowingCapacityLbs,
 			&eq.CreatedAt,
 			&eq.UpdatedAt,
 		)
@@ -685,14 +732,9 @@ func (r *EquipmentRepositoryImpl) CheckAvailability(ctx context.Context, equipme
 		           WHEN e.status != 'available' THEN false
 		           WHEN e.next_maintenance IS NOT NULL AND e.next_maintenance < $2 THEN false
 		           WHEN EXISTS (
-		               SELECT 1 FROM job_equipment je
-		               JOIN jobs j ON je.job_id = j.id
-		               WHERE je.equipment_id = e.id
-		                 AND j.status IN ('in_progress', 'scheduled')
-		                 AND (
-		                     (j.scheduled_start_time <= $3 AND j.scheduled_end_time >= $2) OR
-		                     (j.actual_start_time <= $3 AND (j.actual_end_time IS NULL OR j.actual_end_time >= $2))
-		                 )
+		               SELECT 1 FROM equipment_reservations er
+		               WHERE er.equipment_id = e.id
+		                 AND er.starts_at < $3 AND er.ends_at > $2
 		           ) THEN false
 		           ELSE true
 		       END as available
@@ -731,6 +773,7 @@ func (r *EquipmentRepositoryImpl) GetMaintenanceDue(ctx context.Context, tenantI
 	query := `
 		SELECT id, tenant_id, name, type, model, serial_number, purchase_date, purchase_price,
 			   status, maintenance_schedule, last_maintenance, next_maintenance, notes,
+			   weight_lbs, payload_capacity_lbs, towing_capacity_lbs,
 			   created_at, updated_at
 		FROM equipment
 		WHERE tenant_id = $1 
@@ -761,6 +804,9 @@ func (r *EquipmentRepositoryImpl) GetMaintenanceDue(ctx context.Context, tenantI
 			&eq.LastMaintenance,
 			&eq.NextMaintenance,
 			&eq.Notes,
+			&eq.WeightLbs,
+			&eq.PayloadCapacityLbs,
+			&eq.TowingCapacityLbs,
 			&eq.CreatedAt,
 			&eq.UpdatedAt,
 		)
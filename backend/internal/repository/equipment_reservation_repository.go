@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// EquipmentReservationRepositoryImpl implements the equipment reservation repository interface
+type EquipmentReservationRepositoryImpl struct {
+	db *Database
+}
+
+// NewEquipmentReservationRepository creates a new equipment reservation repository instance
+func NewEquipmentReservationRepository(db *Database) services.EquipmentReservationRepository {
+	return &EquipmentReservationRepositoryImpl{db: db}
+}
+
+const equipmentReservationColumns = `
+	id, tenant_id, equipment_id, job_id, crew_id, starts_at, ends_at, notes,
+	created_by, created_at, updated_at`
+
+const insertEquipmentReservationQuery = `
+	INSERT INTO equipment_reservations (` + equipmentReservationColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+// CreateReservation stores a reservation
+func (r *EquipmentReservationRepositoryImpl) CreateReservation(ctx context.Context, reservation *domain.EquipmentReservation) error {
+	if err := insertEquipmentReservation(ctx, r.db, reservation); err != nil {
+		return reservationError(err)
+	}
+
+	return nil
+}
+
+// GetReservation retrieves a reservation by ID
+func (r *EquipmentReservationRepositoryImpl) GetReservation(ctx context.Context, tenantID, reservationID uuid.UUID) (*domain.EquipmentReservation, error) {
+	query := `
+		SELECT ` + equipmentReservationColumns + `
+		FROM equipment_reservations
+		WHERE tenant_id = $1 AND id = $2`
+
+	reservation, err := scanEquipmentReservation(r.db.QueryRowContext(ctx, query, tenantID, reservationID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get equipment reservation: %w", err)
+	}
+
+	return reservation, nil
+}
+
+// DeleteReservation removes a reservation
+func (r *EquipmentReservationRepositoryImpl) DeleteReservation(ctx context.Context, tenantID, reservationID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM equipment_reservations WHERE tenant_id = $1 AND id = $2`, tenantID, reservationID)
+	if err != nil {
+		return fmt.Errorf("failed to delete equipment reservation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("equipment reservation not found")
+	}
+
+	return nil
+}
+
+// ListReservations lists reservations overlapping the window, for all equipment when equipmentIDs is empty
+func (r *EquipmentReservationRepositoryImpl) ListReservations(ctx context.Context, tenantID uuid.UUID, equipmentIDs []uuid.UUID, start, end time.Time) ([]*domain.EquipmentReservation, error) {
+	ids := make([]string, len(equipmentIDs))
+	for i, id := range equipmentIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT ` + equipmentReservationColumns + `
+		FROM equipment_reservations
+		WHERE tenant_id = $1
+			AND (cardinality($2::uuid[]) = 0 OR equipment_id = ANY($2::uuid[]))
+			AND starts_at < $4 AND ends_at > $3
+		ORDER BY starts_at, equipment_id`
+
+	return r.listReservations(ctx, query, tenantID, pq.Array(ids), start, end)
+}
+
+// ListCrewReservations lists the reservations a crew holds that overlap the window
+func (r *EquipmentReservationRepositoryImpl) ListCrewReservations(ctx context.Context, tenantID, crewID uuid.UUID, start, end time.Time) ([]*domain.EquipmentReservation, error) {
+	query := `
+		SELECT ` + equipmentReservationColumns + `
+		FROM equipment_reservations
+		WHERE tenant_id = $1 AND crew_id = $2 AND starts_at < $4 AND ends_at > $3
+		ORDER BY starts_at, equipment_id`
+
+	return r.listReservations(ctx, query, tenantID, crewID, start, end)
+}
+
+// ListJobReservations lists the reservations held by a job
+func (r *EquipmentReservationRepositoryImpl) ListJobReservations(ctx context.Context, tenantID, jobID uuid.UUID) ([]*domain.EquipmentReservation, error) {
+	query := `
+		SELECT ` + equipmentReservationColumns + `
+		FROM equipment_reservations
+		WHERE tenant_id = $1 AND job_id = $2
+		ORDER BY starts_at, equipment_id`
+
+	return r.listReservations(ctx, query, tenantID, jobID)
+}
+
+// ReplaceJobReservations deletes a job's reservations and stores the new set
+func (r *EquipmentReservationRepositoryImpl) ReplaceJobReservations(ctx context.Context, tenantID, jobID uuid.UUID, reservations []*domain.EquipmentReservation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM equipment_reservations WHERE tenant_id = $1 AND job_id = $2`, tenantID, jobID); err != nil {
+		return fmt.Errorf("failed to clear job reservations: %w", err)
+	}
+
+	for _, reservation := range reservations {
+		if err := insertEquipmentReservation(ctx, tx, reservation); err != nil {
+			return reservationError(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteJobReservations releases everything a job has reserved
+func (r *EquipmentReservationRepositoryImpl) DeleteJobReservations(ctx context.Context, tenantID, jobID uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM equipment_reservations WHERE tenant_id = $1 AND job_id = $2`, tenantID, jobID); err != nil {
+		return fmt.Errorf("failed to delete job reservations: %w", err)
+	}
+
+	return nil
+}
+
+func (r *EquipmentReservationRepositoryImpl) listReservations(ctx context.Context, query string, args ...interface{}) ([]*domain.EquipmentReservation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list equipment reservations: %w", err)
+	}
+	defer rows.Close()
+
+	reservations := []*domain.EquipmentReservation{}
+	for rows.Next() {
+		reservation, err := scanEquipmentReservation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan equipment reservation: %w", err)
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	return reservations, rows.Err()
+}
+
+type reservationExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertEquipmentReservation(ctx context.Context, db reservationExecer, reservation *domain.EquipmentReservation) error {
+	_, err := db.ExecContext(ctx, insertEquipmentReservationQuery,
+		reservation.ID,
+		reservation.TenantID,
+		reservation.EquipmentID,
+		reservation.JobID,
+		reservation.CrewID,
+		reservation.StartsAt,
+		reservation.EndsAt,
+		reservation.Notes,
+		reservation.CreatedBy,
+		reservation.CreatedAt,
+		reservation.UpdatedAt,
+	)
+	return err
+}
+
+// reservationError turns the overlap constraint into a conflict the handlers can report
+func reservationError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code {
+		case "23P01": // exclusion_violation
+			if pqErr.Constraint == "equipment_reservations_no_overlap" {
+				return fmt.Errorf("equipment is already reserved for that time")
+			}
+		}
+	}
+	return fmt.Errorf("failed to create equipment reservation: %w", err)
+}
+
+func scanEquipmentReservation(row rowScanner) (*domain.EquipmentReservation, error) {
+	var reservation domain.EquipmentReservation
+	if err := row.Scan(
+		&reservation.ID,
+		&reservation.TenantID,
+		&reservation.EquipmentID,
+		&reservation.JobID,
+		&reservation.CrewID,
+		&reservation.StartsAt,
+		&reservation.EndsAt,
+		&reservation.Notes,
+		&reservation.CreatedBy,
+		&reservation.CreatedAt,
+		&reservation.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
@@ -0,0 +1,493 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// ImportRepositoryImpl implements the bulk import repository interface
+type ImportRepositoryImpl struct {
+	db *Database
+}
+
+// NewImportRepository creates a new import repository instance
+func NewImportRepository(db *Database) services.ImportRepository {
+	return &ImportRepositoryImpl{db: db}
+}
+
+const importJobColumns = `
+	id, tenant_id, file_name, file_format, headers, mapping, duplicate_strategy, status,
+	total_rows, valid_rows, invalid_rows, duplicate_rows, processed_rows,
+	created_customers, updated_customers, created_properties, failed_rows,
+	geocoded_at, validated_at, started_at, completed_at, rolled_back_at, error,
+	created_by, created_at, updated_at`
+
+const importRowColumns = `
+	id, tenant_id, import_id, row_number, data, status, errors, warnings,
+	duplicate_of_id, duplicate_score, customer_id, property_id, created_customer,
+	created_property, filled_fields, created_at, updated_at`
+
+// CreateImport stores an import job and its rows in one transaction
+func (r *ImportRepositoryImpl) CreateImport(ctx context.Context, job *domain.ImportJob, rows []*domain.ImportRow) error {
+	mappingJSON, err := json.Marshal(job.Mapping)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mapping: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO import_jobs (`+importJobColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
+			$18, $19, $20, $21, $22, $23, $24, $25, $26)`,
+		job.ID,
+		job.TenantID,
+		job.FileName,
+		job.FileFormat,
+		pq.Array(job.Headers),
+		mappingJSON,
+		job.DuplicateStrategy,
+		job.Status,
+		job.TotalRows,
+		job.ValidRows,
+		job.InvalidRows,
+		job.DuplicateRows,
+		job.ProcessedRows,
+		job.CreatedCustomers,
+		job.UpdatedCustomers,
+		job.CreatedProperties,
+		job.FailedRows,
+		job.GeocodedAt,
+		job.ValidatedAt,
+		job.StartedAt,
+		job.CompletedAt,
+		job.RolledBackAt,
+		job.Error,
+		job.CreatedBy,
+		job.CreatedAt,
+		job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create import: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO import_rows (`+importRowColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare import rows: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		dataJSON, err := json.Marshal(row.Data)
+		if err != nil {
+			return fmt.Errorf("failed to marshal row data: %w", err)
+		}
+		_, err = stmt.ExecContext(ctx,
+			row.ID,
+			row.TenantID,
+			row.ImportID,
+			row.RowNumber,
+			dataJSON,
+			row.Status,
+			pq.Array(nonNilStrings(row.Errors)),
+			pq.Array(nonNilStrings(row.Warnings)),
+			row.DuplicateOfID,
+			row.DuplicateScore,
+			row.CustomerID,
+			row.PropertyID,
+			row.CreatedCustomer,
+			row.CreatedProperty,
+			pq.Array(nonNilStrings(row.FilledFields)),
+			row.CreatedAt,
+			row.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create import row %d: %w", row.RowNumber, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetImport retrieves an import job by ID
+func (r *ImportRepositoryImpl) GetImport(ctx context.Context, tenantID, importID uuid.UUID) (*domain.ImportJob, error) {
+	query := `SELECT ` + importJobColumns + ` FROM import_jobs WHERE id = $1 AND tenant_id = $2`
+
+	job, err := scanImportJob(r.db.QueryRowContext(ctx, query, importID, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get import: %w", err)
+	}
+
+	return job, nil
+}
+
+// UpdateImport updates an import job's mapping, status and counters
+func (r *ImportRepositoryImpl) UpdateImport(ctx context.Context, job *domain.ImportJob) error {
+	mappingJSON, err := json.Marshal(job.Mapping)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mapping: %w", err)
+	}
+
+	query := `
+		UPDATE import_jobs SET
+			mapping = $3, duplicate_strategy = $4, status = $5, valid_rows = $6,
+			invalid_rows = $7, duplicate_rows = $8, processed_rows = $9,
+			created_customers = $10, updated_customers = $11, created_properties = $12,
+			failed_rows = $13, geocoded_at = $14, validated_at = $15, started_at = $16,
+			completed_at = $17, rolled_back_at = $18, error = $19, updated_at = $20
+		WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		job.ID,
+		job.TenantID,
+		mappingJSON,
+		job.DuplicateStrategy,
+		job.Status,
+		job.ValidRows,
+		job.InvalidRows,
+		job.DuplicateRows,
+		job.ProcessedRows,
+		job.CreatedCustomers,
+		job.UpdatedCustomers,
+		job.CreatedProperties,
+		job.FailedRows,
+		job.GeocodedAt,
+		job.ValidatedAt,
+		job.StartedAt,
+		job.CompletedAt,
+		job.RolledBackAt,
+		job.Error,
+		job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update import: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("import not found")
+	}
+
+	return nil
+}
+
+// ListImports lists a tenant's imports, newest first
+func (r *ImportRepositoryImpl) ListImports(ctx context.Context, tenantID uuid.UUID, filter *services.ImportFilter) ([]*domain.ImportJob, int64, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf("file_name ILIKE $%d", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM import_jobs WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count imports: %w", err)
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	query := `
+		SELECT ` + importJobColumns + `
+		FROM import_jobs
+		WHERE ` + where + fmt.Sprintf(`
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list imports: %w", err)
+	}
+	defer rows.Close()
+
+	jobs, err := scanImportJobs(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return jobs, total, nil
+}
+
+// ListActiveImports lists imports being processed or geocoded across all tenants,
+// oldest first
+func (r *ImportRepositoryImpl) ListActiveImports(ctx context.Context, limit int) ([]*domain.ImportJob, error) {
+	query := `
+		SELECT ` + importJobColumns + `
+		FROM import_jobs
+		WHERE status IN ('processing', 'geocoding')
+		ORDER BY started_at
+		LIMIT $1`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active imports: %w", err)
+	}
+	defer rows.Close()
+
+	return scanImportJobs(rows)
+}
+
+// ListRows lists an import's rows in spreadsheet order
+func (r *ImportRepositoryImpl) ListRows(ctx context.Context, tenantID, importID uuid.UUID, filter *services.ImportRowFilter) ([]*domain.ImportRow, int64, error) {
+	conditions := []string{"tenant_id = $1", "import_id = $2"}
+	args := []interface{}{tenantID, importID}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf("data::text ILIKE $%d", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM import_rows WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count import rows: %w", err)
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	query := `
+		SELECT ` + importRowColumns + `
+		FROM import_rows
+		WHERE ` + where + fmt.Sprintf(`
+		ORDER BY row_number
+		LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list import rows: %w", err)
+	}
+	defer rows.Close()
+
+	importRows, err := scanImportRows(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return importRows, total, nil
+}
+
+// ListRowBatch lists up to limit rows in the given statuses after a row number
+func (r *ImportRepositoryImpl) ListRowBatch(ctx context.Context, tenantID, importID uuid.UUID, statuses []string, afterRow, limit int) ([]*domain.ImportRow, error) {
+	query := `
+		SELECT ` + importRowColumns + `
+		FROM import_rows
+		WHERE tenant_id = $1 AND import_id = $2 AND status = ANY($3) AND row_number > $4
+		ORDER BY row_number
+		LIMIT $5`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, importID, pq.Array(statuses), afterRow, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import rows: %w", err)
+	}
+	defer rows.Close()
+
+	return scanImportRows(rows)
+}
+
+// UpdateRows saves the outcome of a batch of rows in one transaction
+func (r *ImportRepositoryImpl) UpdateRows(ctx context.Context, rows []*domain.ImportRow) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		UPDATE import_rows SET
+			status = $3, errors = $4, warnings = $5, duplicate_of_id = $6,
+			duplicate_score = $7, customer_id = $8, property_id = $9,
+			created_customer = $10, created_property = $11, filled_fields = $12,
+			updated_at = $13
+		WHERE id = $1 AND tenant_id = $2`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare import rows: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		_, err := stmt.ExecContext(ctx,
+			row.ID,
+			row.TenantID,
+			row.Status,
+			pq.Array(nonNilStrings(row.Errors)),
+			pq.Array(nonNilStrings(row.Warnings)),
+			row.DuplicateOfID,
+			row.DuplicateScore,
+			row.CustomerID,
+			row.PropertyID,
+			row.CreatedCustomer,
+			row.CreatedProperty,
+			pq.Array(nonNilStrings(row.FilledFields)),
+			row.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update import row %d: %w", row.RowNumber, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Helper functions
+
+func scanImportJobs(rows *sql.Rows) ([]*domain.ImportJob, error) {
+	var jobs []*domain.ImportJob
+	for rows.Next() {
+		job, err := scanImportJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate imports: %w", err)
+	}
+
+	return jobs, nil
+}
+
+func scanImportJob(row rowScanner) (*domain.ImportJob, error) {
+	var job domain.ImportJob
+	var headers pq.StringArray
+	var mappingJSON []byte
+	if err := row.Scan(
+		&job.ID,
+		&job.TenantID,
+		&job.FileName,
+		&job.FileFormat,
+		&headers,
+		&mappingJSON,
+		&job.DuplicateStrategy,
+		&job.Status,
+		&job.TotalRows,
+		&job.ValidRows,
+		&job.InvalidRows,
+		&job.DuplicateRows,
+		&job.ProcessedRows,
+		&job.CreatedCustomers,
+		&job.UpdatedCustomers,
+		&job.CreatedProperties,
+		&job.FailedRows,
+		&job.GeocodedAt,
+		&job.ValidatedAt,
+		&job.StartedAt,
+		&job.CompletedAt,
+		&job.RolledBackAt,
+		&job.Error,
+		&job.CreatedBy,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	job.Headers = []string(headers)
+	if len(mappingJSON) > 0 {
+		if err := json.Unmarshal(mappingJSON, &job.Mapping); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal mapping: %w", err)
+		}
+	}
+
+	return &job, nil
+}
+
+func scanImportRows(rows *sql.Rows) ([]*domain.ImportRow, error) {
+	var importRows []*domain.ImportRow
+	for rows.Next() {
+		row, err := scanImportRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import row: %w", err)
+		}
+		importRows = append(importRows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate import rows: %w", err)
+	}
+
+	return importRows, nil
+}
+
+func scanImportRow(row rowScanner) (*domain.ImportRow, error) {
+	var importRow domain.ImportRow
+	var dataJSON []byte
+	var errs, warnings, filledFields pq.StringArray
+	if err := row.Scan(
+		&importRow.ID,
+		&importRow.TenantID,
+		&importRow.ImportID,
+		&importRow.RowNumber,
+		&dataJSON,
+		&importRow.Status,
+		&errs,
+		&warnings,
+		&importRow.DuplicateOfID,
+		&importRow.DuplicateScore,
+		&importRow.CustomerID,
+		&importRow.PropertyID,
+		&importRow.CreatedCustomer,
+		&importRow.CreatedProperty,
+		&filledFields,
+		&importRow.CreatedAt,
+		&importRow.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	importRow.Errors = []string(errs)
+	importRow.Warnings = []string(warnings)
+	importRow.FilledFields = []string(filledFields)
+	if len(dataJSON) > 0 {
+		if err := json.Unmarshal(dataJSON, &importRow.Data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal row data: %w", err)
+		}
+	}
+
+	return &importRow, nil
+}
+
+// nonNilStrings keeps NOT NULL array columns from receiving NULL
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
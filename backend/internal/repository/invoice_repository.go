@@ -26,39 +26,7 @@ func NewInvoiceRepositoryFull(db *Database) services.InvoiceRepositoryFull {
 
 // Create creates a new invoice
 func (r *InvoiceRepositoryImpl) Create(ctx context.Context, invoice *domain.Invoice) error {
-	query := `
-		INSERT INTO invoices (
-			id, tenant_id, customer_id, job_id, invoice_number, status,
-			subtotal, tax_rate, tax_amount, total_amount, issued_date, due_date,
-			paid_date, notes, created_at, updated_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
-		)`
-
-	_, err := r.db.ExecContext(ctx, query,
-		invoice.ID,
-		invoice.TenantID,
-		invoice.CustomerID,
-		invoice.JobID,
-		invoice.InvoiceNumber,
-		invoice.Status,
-		invoice.Subtotal,
-		invoice.TaxRate,
-		invoice.TaxAmount,
-		invoice.TotalAmount,
-		invoice.IssuedDate,
-		invoice.DueDate,
-		invoice.PaidDate,
-		invoice.Notes,
-		invoice.CreatedAt,
-		invoice.UpdatedAt,
-	)
-
-	if err != nil {
-		return fmt.Errorf("failed to create invoice: %w", err)
-	}
-
-	return nil
+	return insertInvoice(ctx, r.db, invoice)
 }
 
 // GetByID retrieves an invoice by ID
@@ -432,27 +400,7 @@ func (r *InvoiceRepositoryImpl) GetOverdue(ctx context.Context, tenantID uuid.UU
 
 // CreateInvoiceService creates an invoice service line item
 func (r *InvoiceRepositoryImpl) CreateInvoiceService(ctx context.Context, invoiceService *services.InvoiceLineItem) error {
-	query := `
-		INSERT INTO invoice_services (
-			id, invoice_id, service_id, quantity, unit_price, total_price, description, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
-
-	_, err := r.db.ExecContext(ctx, query,
-		invoiceService.ID,
-		invoiceService.InvoiceID,
-		invoiceService.ServiceID,
-		invoiceService.Quantity,
-		invoiceService.UnitPrice,
-		invoiceService.TotalPrice,
-		invoiceService.Description,
-		invoiceService.CreatedAt,
-	)
-
-	if err != nil {
-		return fmt.Errorf("failed to create invoice service: %w", err)
-	}
-
-	return nil
+	return insertInvoiceService(ctx, r.db, invoiceService)
 }
 
 // UpdateInvoiceService updates an invoice service line item
@@ -568,4 +516,70 @@ func (r *InvoiceRepositoryImpl) GetNextInvoiceNumber(ctx context.Context, tenant
 	invoiceNumber := fmt.Sprintf("INV-%d-%04d", currentYear, nextNumber)
 
 	return invoiceNumber, nil
-}
\ No newline at end of file
+}
+
+type invoiceExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertInvoice stores an invoice on the database or inside a transaction
+func insertInvoice(ctx context.Context, db invoiceExecer, invoice *domain.Invoice) error {
+	query := `
+		INSERT INTO invoices (
+			id, tenant_id, customer_id, job_id, invoice_number, status,
+			subtotal, tax_rate, tax_amount, total_amount, issued_date, due_date,
+			paid_date, notes, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+		)`
+
+	_, err := db.ExecContext(ctx, query,
+		invoice.ID,
+		invoice.TenantID,
+		invoice.CustomerID,
+		invoice.JobID,
+		invoice.InvoiceNumber,
+		invoice.Status,
+		invoice.Subtotal,
+		invoice.TaxRate,
+		invoice.TaxAmount,
+		invoice.TotalAmount,
+		invoice.IssuedDate,
+		invoice.DueDate,
+		invoice.PaidDate,
+		invoice.Notes,
+		invoice.CreatedAt,
+		invoice.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	return nil
+}
+
+// insertInvoiceService stores an invoice line item on the database or inside a transaction
+func insertInvoiceService(ctx context.Context, db invoiceExecer, invoiceService *services.InvoiceLineItem) error {
+	query := `
+		INSERT INTO invoice_services (
+			id, invoice_id, service_id, quantity, unit_price, total_price, description, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := db.ExecContext(ctx, query,
+		invoiceService.ID,
+		invoiceService.InvoiceID,
+		invoiceService.ServiceID,
+		invoiceService.Quantity,
+		invoiceService.UnitPrice,
+		invoiceService.TotalPrice,
+		invoiceService.Description,
+		invoiceService.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create invoice service: %w", err)
+	}
+
+	return nil
+}
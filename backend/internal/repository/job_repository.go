@@ -32,10 +32,10 @@ func (r *JobRepositoryImpl) Create(ctx context.Context, job *domain.EnhancedJob)
 			actual_start_time, actual_end_time, total_amount, notes, job_number,
 			recurring_schedule, parent_job_id, weather_dependent, requires_equipment,
 			crew_size, completion_photos, customer_signature, gps_check_in, gps_check_out,
-			created_at, updated_at
+			po_number, quote_id, quote_version_id, created_at, updated_at
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
-			$17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28
+			$17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31
 		)`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -65,6 +65,9 @@ func (r *JobRepositoryImpl) Create(ctx context.Context, job *domain.EnhancedJob)
 		job.CustomerSignature,
 		job.GPSCheckIn,
 		job.GPSCheckOut,
+		job.PONumber,
+		job.QuoteID,
+		job.QuoteVersionID,
 		job.CreatedAt,
 		job.UpdatedAt,
 	)
@@ -85,7 +88,7 @@ func (r *JobRepositoryImpl) GetByID(ctx context.Context, tenantID, jobID uuid.UU
 			actual_start_time, actual_end_time, total_amount, notes, job_number,
 			recurring_schedule, parent_job_id, weather_dependent, requires_equipment,
 			crew_size, completion_photos, customer_signature, gps_check_in, gps_check_out,
-			created_at, updated_at
+			po_number, quote_id, quote_version_id, created_at, updated_at
 		FROM jobs
 		WHERE id = $1 AND tenant_id = $2`
 
@@ -122,6 +125,9 @@ func (r *JobRepositoryImpl) GetByID(ctx context.Context, tenantID, jobID uuid.UU
 		&job.CustomerSignature,
 		&job.GPSCheckIn,
 		&job.GPSCheckOut,
+		&job.PONumber,
+		&job.QuoteID,
+		&job.QuoteVersionID,
 		&job.CreatedAt,
 		&job.UpdatedAt,
 	)
@@ -168,7 +174,8 @@ func (r *JobRepositoryImpl) Update(ctx context.Context, job *domain.EnhancedJob)
 			customer_signature = $24,
 			gps_check_in = $25,
 			gps_check_out = $26,
-			updated_at = $27
+			po_number = $27,
+			updated_at = $28
 		WHERE id = $1 AND tenant_id = $2`
 
 	result, err := r.db.ExecContext(ctx, query,
@@ -198,6 +205,7 @@ func (r *JobRepositoryImpl) Update(ctx context.Context, job *domain.EnhancedJob)
 		job.CustomerSignature,
 		job.GPSCheckIn,
 		job.GPSCheckOut,
+		job.PONumber,
 		job.UpdatedAt,
 	)
 
@@ -336,7 +344,7 @@ func (r *JobRepositoryImpl) List(ctx context.Context, tenantID uuid.UUID, filter
 			actual_start_time, actual_end_time, total_amount, notes, job_number,
 			recurring_schedule, parent_job_id, weather_dependent, requires_equipment,
 			crew_size, completion_photos, customer_signature, gps_check_in, gps_check_out,
-			created_at, updated_at
+			po_number, quote_id, quote_version_id, created_at, updated_at
 		FROM jobs ` + whereClause + paginationClause
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -378,6 +386,9 @@ func (r *JobRepositoryImpl) List(ctx context.Context, tenantID uuid.UUID, filter
 			&job.CustomerSignature,
 			&job.GPSCheckIn,
 			&job.GPSCheckOut,
+			&job.PONumber,
+			&job.QuoteID,
+			&job.QuoteVersionID,
 			&job.CreatedAt,
 			&job.UpdatedAt,
 		)
@@ -452,7 +463,7 @@ func (r *JobRepositoryImpl) GetByDateRange(ctx context.Context, tenantID uuid.UU
 			actual_start_time, actual_end_time, total_amount, notes, job_number,
 			recurring_schedule, parent_job_id, weather_dependent, requires_equipment,
 			crew_size, completion_photos, customer_signature, gps_check_in, gps_check_out,
-			created_at, updated_at
+			po_number, quote_id, quote_version_id, created_at, updated_at
 		FROM jobs
 		WHERE tenant_id = $1 AND scheduled_date BETWEEN $2 AND $3
 		ORDER BY scheduled_date ASC`
@@ -496,6 +507,9 @@ func (r *JobRepositoryImpl) GetByDateRange(ctx context.Context, tenantID uuid.UU
 			&job.CustomerSignature,
 			&job.GPSCheckIn,
 			&job.GPSCheckOut,
+			&job.PONumber,
+			&job.QuoteID,
+			&job.QuoteVersionID,
 			&job.CreatedAt,
 			&job.UpdatedAt,
 		)
@@ -749,10 +763,22 @@ func (r *JobRepositoryImpl) GetJobStatistics(ctx context.Context, tenantID uuid.
 
 // GetByEquipmentID gets jobs that used specific equipment within date range
 func (r *JobRepositoryImpl) GetByEquipmentID(ctx context.Context, tenantID uuid.UUID, equipmentID uuid.UUID, startDate, endDate time.Time) ([]*domain.EnhancedJob, error) {
-	// TODO: Implement proper equipment-job relationship query
-	// For now, return empty list to prevent compilation errors
-	// This would typically join with a job_equipment table or similar
-	return []*domain.EnhancedJob{}, nil
+	jobs, err := r.GetByDateRange(ctx, tenantID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs by equipment: %w", err)
+	}
+
+	equipmentJobs := make([]*domain.EnhancedJob, 0)
+	for _, job := range jobs {
+		for _, id := range job.RequiresEquipment {
+			if id == equipmentID {
+				equipmentJobs = append(equipmentJobs, job)
+				break
+			}
+		}
+	}
+
+	return equipmentJobs, nil
 }
 
 
@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// LeadRepositoryImpl implements the lead repository interface
+type LeadRepositoryImpl struct {
+	db *Database
+}
+
+// NewLeadRepository creates a new lead repository instance
+func NewLeadRepository(db *Database) services.LeadRepository {
+	return &LeadRepositoryImpl{db: db}
+}
+
+const leadColumns = `
+	id, tenant_id, source, status, first_name, last_name, email, phone, service_key,
+	service_id, message, address_line1, city, state, zip_code, property_size,
+	estimated_price, spam_score, spam_reasons, duplicate_of_id, matched_customer_id,
+	customer_id, property_id, quote_id, ip_address, user_agent, metadata, reviewed_by,
+	reviewed_at, denial_reason, created_at, updated_at`
+
+// Create stores a new lead
+func (r *LeadRepositoryImpl) Create(ctx context.Context, lead *domain.Lead) error {
+	reasonsJSON, err := json.Marshal(lead.SpamReasons)
+	if err != nil {
+		return fmt.Errorf("failed to marshal spam reasons: %w", err)
+	}
+	metadataJSON, err := json.Marshal(lead.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	query := `
+		INSERT INTO leads (` + leadColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+			$17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		lead.ID,
+		lead.TenantID,
+		lead.Source,
+		lead.Status,
+		lead.FirstName,
+		lead.LastName,
+		lead.Email,
+		lead.Phone,
+		lead.ServiceKey,
+		lead.ServiceID,
+		lead.Message,
+		lead.AddressLine1,
+		lead.City,
+		lead.State,
+		lead.ZipCode,
+		lead.PropertySize,
+		lead.EstimatedPrice,
+		lead.SpamScore,
+		reasonsJSON,
+		lead.DuplicateOfID,
+		lead.MatchedCustomerID,
+		lead.CustomerID,
+		lead.PropertyID,
+		lead.QuoteID,
+		lead.IPAddress,
+		lead.UserAgent,
+		metadataJSON,
+		lead.ReviewedBy,
+		lead.ReviewedAt,
+		lead.DenialReason,
+		lead.CreatedAt,
+		lead.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create lead: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a lead by ID
+func (r *LeadRepositoryImpl) GetByID(ctx context.Context, tenantID, leadID uuid.UUID) (*domain.Lead, error) {
+	query := `SELECT ` + leadColumns + ` FROM leads WHERE tenant_id = $1 AND id = $2`
+
+	lead, err := scanLead(r.db.QueryRowContext(ctx, query, tenantID, leadID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get lead: %w", err)
+	}
+
+	return lead, nil
+}
+
+// Update saves a lead's review state and conversion links
+func (r *LeadRepositoryImpl) Update(ctx context.Context, lead *domain.Lead) error {
+	metadataJSON, err := json.Marshal(lead.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	query := `
+		UPDATE leads SET
+			status = $3,
+			service_id = $4,
+			duplicate_of_id = $5,
+			matched_customer_id = $6,
+			customer_id = $7,
+			property_id = $8,
+			quote_id = $9,
+			metadata = $10,
+			reviewed_by = $11,
+			reviewed_at = $12,
+			denial_reason = $13,
+			updated_at = $14
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		lead.TenantID,
+		lead.ID,
+		lead.Status,
+		lead.ServiceID,
+		lead.DuplicateOfID,
+		lead.MatchedCustomerID,
+		lead.CustomerID,
+		lead.PropertyID,
+		lead.QuoteID,
+		metadataJSON,
+		lead.ReviewedBy,
+		lead.ReviewedAt,
+		lead.DenialReason,
+		lead.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update lead: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("lead not found")
+	}
+
+	return nil
+}
+
+// List lists leads with pending ones first. Spam is excluded unless filtered for.
+func (r *LeadRepositoryImpl) List(ctx context.Context, tenantID uuid.UUID, filter *services.LeadFilter) ([]*domain.Lead, int64, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	} else {
+		conditions = append(conditions, "status <> 'spam'")
+	}
+	if filter.Source != "" {
+		args = append(args, filter.Source)
+		conditions = append(conditions, fmt.Sprintf("source = $%d", len(args)))
+	}
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf(
+			"(first_name ILIKE $%[1]d OR last_name ILIKE $%[1]d OR email ILIKE $%[1]d OR phone ILIKE $%[1]d)", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM leads WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count leads: %w", err)
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	query := `
+		SELECT ` + leadColumns + `
+		FROM leads
+		WHERE ` + where + fmt.Sprintf(`
+		ORDER BY (status = 'pending') DESC, created_at DESC
+		LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list leads: %w", err)
+	}
+	defer rows.Close()
+
+	leads, err := scanLeads(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return leads, total, nil
+}
+
+// FindRecentByContact returns recent leads with the same email or phone digits
+func (r *LeadRepositoryImpl) FindRecentByContact(ctx context.Context, tenantID uuid.UUID, email, phoneDigits string, since time.Time) ([]*domain.Lead, error) {
+	if email == "" && phoneDigits == "" {
+		return nil, nil
+	}
+
+	query := `
+		SELECT ` + leadColumns + `
+		FROM leads
+		WHERE tenant_id = $1 AND created_at >= $2
+			AND (
+				($3 <> '' AND LOWER(email) = LOWER($3)) OR
+				($4 <> '' AND regexp_replace(phone, '\D', '', 'g') IN ($4, '1' || $4))
+			)
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, since, email, phoneDigits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find leads by contact: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLeads(rows)
+}
+
+// CountByIPSince counts leads submitted from an address since a time
+func (r *LeadRepositoryImpl) CountByIPSince(ctx context.Context, tenantID uuid.UUID, ipAddress string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM leads WHERE tenant_id = $1 AND ip_address = $2::inet AND created_at >= $3`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, tenantID, ipAddress, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count leads by address: %w", err)
+	}
+
+	return count, nil
+}
+
+func scanLeads(rows *sql.Rows) ([]*domain.Lead, error) {
+	var leads []*domain.Lead
+	for rows.Next() {
+		lead, err := scanLead(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan lead: %w", err)
+		}
+		leads = append(leads, lead)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate leads: %w", err)
+	}
+
+	return leads, nil
+}
+
+func scanLead(row rowScanner) (*domain.Lead, error) {
+	var lead domain.Lead
+	var reasonsJSON, metadataJSON []byte
+	if err := row.Scan(
+		&lead.ID,
+		&lead.TenantID,
+		&lead.Source,
+		&lead.Status,
+		&lead.FirstName,
+		&lead.LastName,
+		&lead.Email,
+		&lead.Phone,
+		&lead.ServiceKey,
+		&lead.ServiceID,
+		&lead.Message,
+		&lead.AddressLine1,
+		&lead.City,
+		&lead.State,
+		&lead.ZipCode,
+		&lead.PropertySize,
+		&lead.EstimatedPrice,
+		&lead.SpamScore,
+		&reasonsJSON,
+		&lead.DuplicateOfID,
+		&lead.MatchedCustomerID,
+		&lead.CustomerID,
+		&lead.PropertyID,
+		&lead.QuoteID,
+		&lead.IPAddress,
+		&lead.UserAgent,
+		&metadataJSON,
+		&lead.ReviewedBy,
+		&lead.ReviewedAt,
+		&lead.DenialReason,
+		&lead.CreatedAt,
+		&lead.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if len(reasonsJSON) > 0 {
+		if err := json.Unmarshal(reasonsJSON, &lead.SpamReasons); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal spam reasons: %w", err)
+		}
+	}
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &lead.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+	}
+
+	return &lead, nil
+}
@@ -0,0 +1,647 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// MaintenanceWorkOrderRepositoryImpl implements the maintenance work order repository interface
+type MaintenanceWorkOrderRepositoryImpl struct {
+	db *Database
+}
+
+// NewMaintenanceWorkOrderRepository creates a new maintenance work order repository instance
+func NewMaintenanceWorkOrderRepository(db *Database) services.MaintenanceWorkOrderRepository {
+	return &MaintenanceWorkOrderRepositoryImpl{db: db}
+}
+
+const workOrderColumns = `
+	id, tenant_id, equipment_id, title, description, type, priority, status,
+	assigned_to, scheduled_date, opened_by, started_at, closed_at,
+	downtime_started_at, expected_return_at, downtime_ended_at,
+	parts_cost, labor_cost, vendor_cost, total_cost, resolution_notes,
+	created_at, updated_at`
+
+const workOrderPartColumns = `
+	id, tenant_id, work_order_id, part_id, part_name, quantity, unit_cost,
+	total_cost, created_by, created_at`
+
+const workOrderLaborColumns = `
+	id, tenant_id, work_order_id, mechanic_id, work_date, hours, hourly_rate,
+	total_cost, notes, created_at`
+
+const vendorRepairColumns = `
+	id, tenant_id, work_order_id, vendor_name, invoice_number, description,
+	amount, invoice_attachment_id, completed_at, created_at`
+
+const partColumns = `
+	id, tenant_id, sku, name, description, unit, unit_cost, quantity_on_hand,
+	reorder_point, created_at, updated_at`
+
+const attachmentColumns = `
+	id, tenant_id, entity_type, entity_id, filename, original_filename,
+	file_size, content_type, storage_path, uploaded_by, created_at`
+
+// CreateWorkOrder stores a new work order
+func (r *MaintenanceWorkOrderRepositoryImpl) CreateWorkOrder(ctx context.Context, workOrder *domain.MaintenanceWorkOrder) error {
+	query := `
+		INSERT INTO maintenance_work_orders (` + workOrderColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		workOrder.ID,
+		workOrder.TenantID,
+		workOrder.EquipmentID,
+		workOrder.Title,
+		workOrder.Description,
+		workOrder.Type,
+		workOrder.Priority,
+		workOrder.Status,
+		workOrder.AssignedTo,
+		workOrder.ScheduledDate,
+		workOrder.OpenedBy,
+		workOrder.StartedAt,
+		workOrder.ClosedAt,
+		workOrder.DowntimeStartedAt,
+		workOrder.ExpectedReturnAt,
+		workOrder.DowntimeEndedAt,
+		workOrder.PartsCost,
+		workOrder.LaborCost,
+		workOrder.VendorCost,
+		workOrder.TotalCost,
+		workOrder.ResolutionNotes,
+		workOrder.CreatedAt,
+		workOrder.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create work order: %w", err)
+	}
+
+	return nil
+}
+
+// GetWorkOrder retrieves a work order by ID
+func (r *MaintenanceWorkOrderRepositoryImpl) GetWorkOrder(ctx context.Context, tenantID, workOrderID uuid.UUID) (*domain.MaintenanceWorkOrder, error) {
+	query := `
+		SELECT ` + workOrderColumns + `
+		FROM maintenance_work_orders
+		WHERE tenant_id = $1 AND id = $2`
+
+	workOrder, err := scanWorkOrder(r.db.QueryRowContext(ctx, query, tenantID, workOrderID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get work order: %w", err)
+	}
+
+	return workOrder, nil
+}
+
+// UpdateWorkOrder saves a work order's status, downtime and costs
+func (r *MaintenanceWorkOrderRepositoryImpl) UpdateWorkOrder(ctx context.Context, workOrder *domain.MaintenanceWorkOrder) error {
+	query := `
+		UPDATE maintenance_work_orders SET
+			title = $3,
+			description = $4,
+			priority = $5,
+			status = $6,
+			assigned_to = $7,
+			scheduled_date = $8,
+			started_at = $9,
+			closed_at = $10,
+			downtime_started_at = $11,
+			expected_return_at = $12,
+			downtime_ended_at = $13,
+			parts_cost = $14,
+			labor_cost = $15,
+			vendor_cost = $16,
+			total_cost = $17,
+			resolution_notes = $18,
+			updated_at = $19
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		workOrder.TenantID,
+		workOrder.ID,
+		workOrder.Title,
+		workOrder.Description,
+		workOrder.Priority,
+		workOrder.Status,
+		workOrder.AssignedTo,
+		workOrder.ScheduledDate,
+		workOrder.StartedAt,
+		workOrder.ClosedAt,
+		workOrder.DowntimeStartedAt,
+		workOrder.ExpectedReturnAt,
+		workOrder.DowntimeEndedAt,
+		workOrder.PartsCost,
+		workOrder.LaborCost,
+		workOrder.VendorCost,
+		workOrder.TotalCost,
+		workOrder.ResolutionNotes,
+		workOrder.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update work order: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("work order not found")
+	}
+
+	return nil
+}
+
+// ListWorkOrders lists work orders newest first
+func (r *MaintenanceWorkOrderRepositoryImpl) ListWorkOrders(ctx context.Context, tenantID uuid.UUID, filter *services.WorkOrderFilter) ([]*domain.MaintenanceWorkOrder, error) {
+	query := `
+		SELECT ` + workOrderColumns + `
+		FROM maintenance_work_orders
+		WHERE tenant_id = $1
+			AND ($2::uuid IS NULL OR equipment_id = $2)
+			AND ($3 = '' OR status = $3)
+		ORDER BY created_at DESC`
+
+	return r.listWorkOrders(ctx, query, tenantID, filter.EquipmentID, filter.Status)
+}
+
+// ListDowntime lists work orders whose downtime overlaps the window, for all equipment when equipmentIDs is empty
+func (r *MaintenanceWorkOrderRepositoryImpl) ListDowntime(ctx context.Context, tenantID uuid.UUID, equipmentIDs []uuid.UUID, start, end time.Time) ([]*domain.MaintenanceWorkOrder, error) {
+	ids := make([]string, len(equipmentIDs))
+	for i, id := range equipmentIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT ` + workOrderColumns + `
+		FROM maintenance_work_orders
+		WHERE tenant_id = $1
+			AND (cardinality($2::uuid[]) = 0 OR equipment_id = ANY($2::uuid[]))
+			AND downtime_started_at IS NOT NULL
+			AND downtime_started_at <= $4
+			AND (downtime_ended_at IS NULL OR downtime_ended_at > $3)
+		ORDER BY downtime_started_at`
+
+	return r.listWorkOrders(ctx, query, tenantID, pq.Array(ids), start, end)
+}
+
+// ConsumePart draws the quantity from stock and records it against the work order
+func (r *MaintenanceWorkOrderRepositoryImpl) ConsumePart(ctx context.Context, usage *domain.WorkOrderPart) (*domain.Part, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE parts SET quantity_on_hand = quantity_on_hand - $3, updated_at = NOW()
+		WHERE tenant_id = $1 AND id = $2 AND quantity_on_hand >= $3
+		RETURNING ` + partColumns
+
+	part, err := scanPart(tx.QueryRowContext(ctx, query, usage.TenantID, usage.PartID, usage.Quantity))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("insufficient stock")
+		}
+		return nil, fmt.Errorf("failed to update part stock: %w", err)
+	}
+
+	insert := `
+		INSERT INTO work_order_parts (` + workOrderPartColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	if _, err := tx.ExecContext(ctx, insert,
+		usage.ID,
+		usage.TenantID,
+		usage.WorkOrderID,
+		usage.PartID,
+		usage.PartName,
+		usage.Quantity,
+		usage.UnitCost,
+		usage.TotalCost,
+		usage.CreatedBy,
+		usage.CreatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("failed to create work order part: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return part, nil
+}
+
+// ListWorkOrderParts lists the parts used on a work order
+func (r *MaintenanceWorkOrderRepositoryImpl) ListWorkOrderParts(ctx context.Context, tenantID, workOrderID uuid.UUID) ([]*domain.WorkOrderPart, error) {
+	query := `
+		SELECT ` + workOrderPartColumns + `
+		FROM work_order_parts
+		WHERE tenant_id = $1 AND work_order_id = $2
+		ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, workOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list work order parts: %w", err)
+	}
+	defer rows.Close()
+
+	parts := []*domain.WorkOrderPart{}
+	for rows.Next() {
+		var part domain.WorkOrderPart
+		if err := rows.Scan(
+			&part.ID,
+			&part.TenantID,
+			&part.WorkOrderID,
+			&part.PartID,
+			&part.PartName,
+			&part.Quantity,
+			&part.UnitCost,
+			&part.TotalCost,
+			&part.CreatedBy,
+			&part.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan work order part: %w", err)
+		}
+		parts = append(parts, &part)
+	}
+
+	return parts, rows.Err()
+}
+
+// CreateLabor stores a mechanic's hours on a work order
+func (r *MaintenanceWorkOrderRepositoryImpl) CreateLabor(ctx context.Context, labor *domain.WorkOrderLabor) error {
+	query := `
+		INSERT INTO work_order_labor (` + workOrderLaborColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		labor.ID,
+		labor.TenantID,
+		labor.WorkOrderID,
+		labor.MechanicID,
+		labor.WorkDate,
+		labor.Hours,
+		labor.HourlyRate,
+		labor.TotalCost,
+		labor.Notes,
+		labor.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create work order labor: %w", err)
+	}
+
+	return nil
+}
+
+// ListWorkOrderLabor lists the labor logged on a work order
+func (r *MaintenanceWorkOrderRepositoryImpl) ListWorkOrderLabor(ctx context.Context, tenantID, workOrderID uuid.UUID) ([]*domain.WorkOrderLabor, error) {
+	query := `
+		SELECT ` + workOrderLaborColumns + `
+		FROM work_order_labor
+		WHERE tenant_id = $1 AND work_order_id = $2
+		ORDER BY work_date, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, workOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list work order labor: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.WorkOrderLabor{}
+	for rows.Next() {
+		var labor domain.WorkOrderLabor
+		if err := rows.Scan(
+			&labor.ID,
+			&labor.TenantID,
+			&labor.WorkOrderID,
+			&labor.MechanicID,
+			&labor.WorkDate,
+			&labor.Hours,
+			&labor.HourlyRate,
+			&labor.TotalCost,
+			&labor.Notes,
+			&labor.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan work order labor: %w", err)
+		}
+		entries = append(entries, &labor)
+	}
+
+	return entries, rows.Err()
+}
+
+// CreateVendorRepair stores an outside repair on a work order
+func (r *MaintenanceWorkOrderRepositoryImpl) CreateVendorRepair(ctx context.Context, repair *domain.WorkOrderVendorRepair) error {
+	query := `
+		INSERT INTO work_order_vendor_repairs (` + vendorRepairColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		repair.ID,
+		repair.TenantID,
+		repair.WorkOrderID,
+		repair.VendorName,
+		repair.InvoiceNumber,
+		repair.Description,
+		repair.Amount,
+		repair.InvoiceAttachmentID,
+		repair.CompletedAt,
+		repair.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create vendor repair: %w", err)
+	}
+
+	return nil
+}
+
+// ListVendorRepairs lists the outside repairs on a work order
+func (r *MaintenanceWorkOrderRepositoryImpl) ListVendorRepairs(ctx context.Context, tenantID, workOrderID uuid.UUID) ([]*domain.WorkOrderVendorRepair, error) {
+	query := `
+		SELECT ` + vendorRepairColumns + `
+		FROM work_order_vendor_repairs
+		WHERE tenant_id = $1 AND work_order_id = $2
+		ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, workOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vendor repairs: %w", err)
+	}
+	defer rows.Close()
+
+	repairs := []*domain.WorkOrderVendorRepair{}
+	for rows.Next() {
+		var repair domain.WorkOrderVendorRepair
+		if err := rows.Scan(
+			&repair.ID,
+			&repair.TenantID,
+			&repair.WorkOrderID,
+			&repair.VendorName,
+			&repair.InvoiceNumber,
+			&repair.Description,
+			&repair.Amount,
+			&repair.InvoiceAttachmentID,
+			&repair.CompletedAt,
+			&repair.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan vendor repair: %w", err)
+		}
+		repairs = append(repairs, &repair)
+	}
+
+	return repairs, rows.Err()
+}
+
+// CreateAttachment stores a file attachment record
+func (r *MaintenanceWorkOrderRepositoryImpl) CreateAttachment(ctx context.Context, attachment *domain.FileAttachment) error {
+	query := `
+		INSERT INTO file_attachments (` + attachmentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		attachment.ID,
+		attachment.TenantID,
+		attachment.EntityType,
+		attachment.EntityID,
+		attachment.Filename,
+		attachment.OriginalFilename,
+		attachment.FileSize,
+		attachment.ContentType,
+		attachment.StoragePath,
+		attachment.UploadedBy,
+		attachment.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create file attachment: %w", err)
+	}
+
+	return nil
+}
+
+// GetAttachment retrieves a file attachment by ID
+func (r *MaintenanceWorkOrderRepositoryImpl) GetAttachment(ctx context.Context, tenantID, attachmentID uuid.UUID) (*domain.FileAttachment, error) {
+	query := `
+		SELECT ` + attachmentColumns + `
+		FROM file_attachments
+		WHERE tenant_id = $1 AND id = $2`
+
+	var attachment domain.FileAttachment
+	err := r.db.QueryRowContext(ctx, query, tenantID, attachmentID).Scan(
+		&attachment.ID,
+		&attachment.TenantID,
+		&attachment.EntityType,
+		&attachment.EntityID,
+		&attachment.Filename,
+		&attachment.OriginalFilename,
+		&attachment.FileSize,
+		&attachment.ContentType,
+		&attachment.StoragePath,
+		&attachment.UploadedBy,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get file attachment: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// CreatePart adds a part to inventory
+func (r *MaintenanceWorkOrderRepositoryImpl) CreatePart(ctx context.Context, part *domain.Part) error {
+	query := `
+		INSERT INTO parts (` + partColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		part.ID,
+		part.TenantID,
+		part.SKU,
+		part.Name,
+		part.Description,
+		part.Unit,
+		part.UnitCost,
+		part.QuantityOnHand,
+		part.ReorderPoint,
+		part.CreatedAt,
+		part.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return fmt.Errorf("validation failed: a part with SKU %s already exists", part.SKU)
+		}
+		return fmt.Errorf("failed to create part: %w", err)
+	}
+
+	return nil
+}
+
+// GetPart retrieves a part by ID
+func (r *MaintenanceWorkOrderRepositoryImpl) GetPart(ctx context.Context, tenantID, partID uuid.UUID) (*domain.Part, error) {
+	query := `
+		SELECT ` + partColumns + `
+		FROM parts
+		WHERE tenant_id = $1 AND id = $2`
+
+	part, err := scanPart(r.db.QueryRowContext(ctx, query, tenantID, partID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get part: %w", err)
+	}
+
+	return part, nil
+}
+
+// UpdatePart saves a part's details and stock count
+func (r *MaintenanceWorkOrderRepositoryImpl) UpdatePart(ctx context.Context, part *domain.Part) error {
+	query := `
+		UPDATE parts SET
+			name = $3,
+			description = $4,
+			unit = $5,
+			unit_cost = $6,
+			quantity_on_hand = $7,
+			reorder_point = $8,
+			updated_at = $9
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		part.TenantID,
+		part.ID,
+		part.Name,
+		part.Description,
+		part.Unit,
+		part.UnitCost,
+		part.QuantityOnHand,
+		part.ReorderPoint,
+		part.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update part: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("part not found")
+	}
+
+	return nil
+}
+
+// ListParts lists the parts inventory by name, only parts at or below their reorder point when lowStockOnly is set
+func (r *MaintenanceWorkOrderRepositoryImpl) ListParts(ctx context.Context, tenantID uuid.UUID, lowStockOnly bool) ([]*domain.Part, error) {
+	query := `
+		SELECT ` + partColumns + `
+		FROM parts
+		WHERE tenant_id = $1 AND (NOT $2 OR quantity_on_hand <= reorder_point)
+		ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, lowStockOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list parts: %w", err)
+	}
+	defer rows.Close()
+
+	parts := []*domain.Part{}
+	for rows.Next() {
+		part, err := scanPart(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan part: %w", err)
+		}
+		parts = append(parts, part)
+	}
+
+	return parts, rows.Err()
+}
+
+func (r *MaintenanceWorkOrderRepositoryImpl) listWorkOrders(ctx context.Context, query string, args ...interface{}) ([]*domain.MaintenanceWorkOrder, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list work orders: %w", err)
+	}
+	defer rows.Close()
+
+	workOrders := []*domain.MaintenanceWorkOrder{}
+	for rows.Next() {
+		workOrder, err := scanWorkOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan work order: %w", err)
+		}
+		workOrders = append(workOrders, workOrder)
+	}
+
+	return workOrders, rows.Err()
+}
+
+func scanWorkOrder(row rowScanner) (*domain.MaintenanceWorkOrder, error) {
+	var workOrder domain.MaintenanceWorkOrder
+	if err := row.Scan(
+		&workOrder.ID,
+		&workOrder.TenantID,
+		&workOrder.EquipmentID,
+		&workOrder.Title,
+		&workOrder.Description,
+		&workOrder.Type,
+		&workOrder.Priority,
+		&workOrder.Status,
+		&workOrder.AssignedTo,
+		&workOrder.ScheduledDate,
+		&workOrder.OpenedBy,
+		&workOrder.StartedAt,
+		&workOrder.ClosedAt,
+		&workOrder.DowntimeStartedAt,
+		&workOrder.ExpectedReturnAt,
+		&workOrder.DowntimeEndedAt,
+		&workOrder.PartsCost,
+		&workOrder.LaborCost,
+		&workOrder.VendorCost,
+		&workOrder.TotalCost,
+		&workOrder.ResolutionNotes,
+		&workOrder.CreatedAt,
+		&workOrder.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &workOrder, nil
+}
+
+func scanPart(row rowScanner) (*domain.Part, error) {
+	var part domain.Part
+	if err := row.Scan(
+		&part.ID,
+		&part.TenantID,
+		&part.SKU,
+		&part.Name,
+		&part.Description,
+		&part.Unit,
+		&part.UnitCost,
+		&part.QuantityOnHand,
+		&part.ReorderPoint,
+		&part.CreatedAt,
+		&part.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &part, nil
+}
@@ -0,0 +1,697 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// PayrollRepositoryImpl implements the payroll repository interface
+type PayrollRepositoryImpl struct {
+	db *Database
+}
+
+// NewPayrollRepository creates a new payroll repository instance
+func NewPayrollRepository(db *Database) services.PayrollRepository {
+	return &PayrollRepositoryImpl{db: db}
+}
+
+const payPlanColumns = `id, tenant_id, user_id, employee_number, created_at, updated_at`
+
+const payRuleColumns = `
+	id, tenant_id, user_id, type, rate, service_id,
+	effective_from, effective_to, created_at`
+
+const timeEntryColumns = `
+	id, tenant_id, user_id, job_id, clock_in, clock_out, break_minutes,
+	notes, created_by, created_at, updated_at`
+
+const payPeriodColumns = `
+	id, tenant_id, start_date, end_date, status, total_gross, calculated_at,
+	approved_by, approved_at, exported_at, created_by, created_at, updated_at`
+
+const payrollEntryColumns = `
+	id, tenant_id, pay_period_id, user_id, employee_number, hours_worked,
+	regular_hours, overtime_hours, regular_pay, overtime_pay, piece_rate_pay,
+	commission_pay, bonus_pay, gross_pay`
+
+const payrollLineColumns = `
+	id, entry_id, type, description, job_id, service_id, quantity, rate, amount`
+
+// GetPayPlan retrieves a user's pay plan with its rules
+func (r *PayrollRepositoryImpl) GetPayPlan(ctx context.Context, tenantID, userID uuid.UUID) (*domain.PayPlan, error) {
+	query := `
+		SELECT ` + payPlanColumns + `
+		FROM user_pay_plans
+		WHERE tenant_id = $1 AND user_id = $2`
+
+	plan, err := scanPayPlan(r.db.QueryRowContext(ctx, query, tenantID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pay plan: %w", err)
+	}
+
+	rules, err := r.listPayRules(ctx, tenantID, []uuid.UUID{userID})
+	if err != nil {
+		return nil, err
+	}
+	plan.Rules = rules[userID]
+	if plan.Rules == nil {
+		plan.Rules = []*domain.PayRule{}
+	}
+
+	return plan, nil
+}
+
+// SavePayPlan upserts a pay plan and swaps its rules for the plan's set
+func (r *PayrollRepositoryImpl) SavePayPlan(ctx context.Context, plan *domain.PayPlan) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO user_pay_plans (` + payPlanColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+			employee_number = EXCLUDED.employee_number, updated_at = EXCLUDED.updated_at`
+	if _, err := tx.ExecContext(ctx, query,
+		plan.ID,
+		plan.TenantID,
+		plan.UserID,
+		plan.EmployeeNumber,
+		plan.CreatedAt,
+		plan.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to save pay plan: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM pay_rules WHERE tenant_id = $1 AND user_id = $2`, plan.TenantID, plan.UserID); err != nil {
+		return fmt.Errorf("failed to clear pay rules: %w", err)
+	}
+
+	query = `
+		INSERT INTO pay_rules (` + payRuleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	for _, rule := range plan.Rules {
+		if _, err := tx.ExecContext(ctx, query,
+			rule.ID,
+			rule.TenantID,
+			rule.UserID,
+			rule.Type,
+			rule.Rate,
+			rule.ServiceID,
+			rule.EffectiveFrom,
+			rule.EffectiveTo,
+			rule.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to create pay rule: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListPayPlans lists every pay plan in the tenant with its rules
+func (r *PayrollRepositoryImpl) ListPayPlans(ctx context.Context, tenantID uuid.UUID) ([]*domain.PayPlan, error) {
+	query := `
+		SELECT ` + payPlanColumns + `
+		FROM user_pay_plans
+		WHERE tenant_id = $1
+		ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pay plans: %w", err)
+	}
+	defer rows.Close()
+
+	plans := []*domain.PayPlan{}
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		plan, err := scanPayPlan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pay plan: %w", err)
+		}
+		plans = append(plans, plan)
+		userIDs = append(userIDs, plan.UserID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(userIDs) == 0 {
+		return plans, nil
+	}
+
+	rules, err := r.listPayRules(ctx, tenantID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, plan := range plans {
+		plan.Rules = rules[plan.UserID]
+		if plan.Rules == nil {
+			plan.Rules = []*domain.PayRule{}
+		}
+	}
+
+	return plans, nil
+}
+
+// CreateTimeEntry stores a time entry
+func (r *PayrollRepositoryImpl) CreateTimeEntry(ctx context.Context, entry *domain.TimeEntry) error {
+	query := `
+		INSERT INTO time_entries (` + timeEntryColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		entry.ID,
+		entry.TenantID,
+		entry.UserID,
+		entry.JobID,
+		entry.ClockIn,
+		entry.ClockOut,
+		entry.BreakMinutes,
+		entry.Notes,
+		entry.CreatedBy,
+		entry.CreatedAt,
+		entry.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create time entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetTimeEntry retrieves a time entry by ID
+func (r *PayrollRepositoryImpl) GetTimeEntry(ctx context.Context, tenantID, entryID uuid.UUID) (*domain.TimeEntry, error) {
+	query := `
+		SELECT ` + timeEntryColumns + `
+		FROM time_entries
+		WHERE tenant_id = $1 AND id = $2`
+
+	entry, err := scanTimeEntry(r.db.QueryRowContext(ctx, query, tenantID, entryID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get time entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// UpdateTimeEntry updates a time entry's job, times and notes
+func (r *PayrollRepositoryImpl) UpdateTimeEntry(ctx context.Context, entry *domain.TimeEntry) error {
+	query := `
+		UPDATE time_entries SET
+			job_id = $3, clock_in = $4, clock_out = $5, break_minutes = $6, notes = $7, updated_at = $8
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		entry.TenantID,
+		entry.ID,
+		entry.JobID,
+		entry.ClockIn,
+		entry.ClockOut,
+		entry.BreakMinutes,
+		entry.Notes,
+		entry.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update time entry: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("time entry not found")
+	}
+
+	return nil
+}
+
+// DeleteTimeEntry deletes a time entry
+func (r *PayrollRepositoryImpl) DeleteTimeEntry(ctx context.Context, tenantID, entryID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM time_entries WHERE tenant_id = $1 AND id = $2`, tenantID, entryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete time entry: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("time entry not found")
+	}
+
+	return nil
+}
+
+// ListTimeEntries lists time entries clocked in within the filter's range, latest first
+func (r *PayrollRepositoryImpl) ListTimeEntries(ctx context.Context, tenantID uuid.UUID, filter *services.TimeEntryFilter) ([]*domain.TimeEntry, error) {
+	query := `
+		SELECT ` + timeEntryColumns + `
+		FROM time_entries
+		WHERE tenant_id = $1`
+	args := []interface{}{tenantID}
+
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		query += fmt.Sprintf(" AND user_id = $%d", len(args))
+	}
+	if filter.JobID != nil {
+		args = append(args, *filter.JobID)
+		query += fmt.Sprintf(" AND job_id = $%d", len(args))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		query += fmt.Sprintf(" AND clock_in >= $%d", len(args))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		query += fmt.Sprintf(" AND clock_in < $%d", len(args))
+	}
+	query += " ORDER BY clock_in DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.TimeEntry{}
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// CreatePayPeriod stores a pay period
+func (r *PayrollRepositoryImpl) CreatePayPeriod(ctx context.Context, period *domain.PayPeriod) error {
+	query := `
+		INSERT INTO pay_periods (` + payPeriodColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		period.ID,
+		period.TenantID,
+		period.StartDate,
+		period.EndDate,
+		period.Status,
+		period.TotalGross,
+		period.CalculatedAt,
+		period.ApprovedBy,
+		period.ApprovedAt,
+		period.ExportedAt,
+		period.CreatedBy,
+		period.CreatedAt,
+		period.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pay period: %w", err)
+	}
+
+	return nil
+}
+
+// GetPayPeriod retrieves a pay period by ID
+func (r *PayrollRepositoryImpl) GetPayPeriod(ctx context.Context, tenantID, periodID uuid.UUID) (*domain.PayPeriod, error) {
+	query := `
+		SELECT ` + payPeriodColumns + `
+		FROM pay_periods
+		WHERE tenant_id = $1 AND id = $2`
+
+	period, err := scanPayPeriod(r.db.QueryRowContext(ctx, query, tenantID, periodID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pay period: %w", err)
+	}
+
+	return period, nil
+}
+
+// UpdatePayPeriod updates a pay period's status, totals and approval
+func (r *PayrollRepositoryImpl) UpdatePayPeriod(ctx context.Context, period *domain.PayPeriod) error {
+	return updatePayPeriod(ctx, r.db, period)
+}
+
+// ListPayPeriods lists the tenant's pay periods, latest first
+func (r *PayrollRepositoryImpl) ListPayPeriods(ctx context.Context, tenantID uuid.UUID) ([]*domain.PayPeriod, error) {
+	query := `
+		SELECT ` + payPeriodColumns + `
+		FROM pay_periods
+		WHERE tenant_id = $1
+		ORDER BY start_date DESC`
+
+	return r.queryPayPeriods(ctx, query, tenantID)
+}
+
+// ListOverlappingPayPeriods lists the pay periods sharing a day with the range
+func (r *PayrollRepositoryImpl) ListOverlappingPayPeriods(ctx context.Context, tenantID uuid.UUID, start, end time.Time) ([]*domain.PayPeriod, error) {
+	query := `
+		SELECT ` + payPeriodColumns + `
+		FROM pay_periods
+		WHERE tenant_id = $1 AND start_date <= $3::date AND end_date >= $2::date
+		ORDER BY start_date`
+
+	return r.queryPayPeriods(ctx, query, tenantID, start, end)
+}
+
+// ReplaceRegister swaps a period's register for a new calculation and saves the period
+func (r *PayrollRepositoryImpl) ReplaceRegister(ctx context.Context, period *domain.PayPeriod, entries []*domain.PayrollRegisterEntry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lines go with their entries
+	if _, err := tx.ExecContext(ctx, `DELETE FROM payroll_register_entries WHERE tenant_id = $1 AND pay_period_id = $2`, period.TenantID, period.ID); err != nil {
+		return fmt.Errorf("failed to clear payroll register: %w", err)
+	}
+
+	entryQuery := `
+		INSERT INTO payroll_register_entries (` + payrollEntryColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+	lineQuery := `
+		INSERT INTO payroll_lines (tenant_id, ` + payrollLineColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	for _, entry := range entries {
+		if _, err := tx.ExecContext(ctx, entryQuery,
+			entry.ID,
+			entry.TenantID,
+			entry.PayPeriodID,
+			entry.UserID,
+			entry.EmployeeNumber,
+			entry.HoursWorked,
+			entry.RegularHours,
+			entry.OvertimeHours,
+			entry.RegularPay,
+			entry.OvertimePay,
+			entry.PieceRatePay,
+			entry.CommissionPay,
+			entry.BonusPay,
+			entry.GrossPay,
+		); err != nil {
+			return fmt.Errorf("failed to create payroll register entry: %w", err)
+		}
+
+		for _, line := range entry.Lines {
+			if _, err := tx.ExecContext(ctx, lineQuery,
+				entry.TenantID,
+				line.ID,
+				line.EntryID,
+				line.Type,
+				line.Description,
+				line.JobID,
+				line.ServiceID,
+				line.Quantity,
+				line.Rate,
+				line.Amount,
+			); err != nil {
+				return fmt.Errorf("failed to create payroll line: %w", err)
+			}
+		}
+	}
+
+	if err := updatePayPeriod(ctx, tx, period); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListRegister lists a period's register entries with their lines, by name
+func (r *PayrollRepositoryImpl) ListRegister(ctx context.Context, tenantID, periodID uuid.UUID) ([]*domain.PayrollRegisterEntry, error) {
+	query := `
+		SELECT e.id, e.tenant_id, e.pay_period_id, e.user_id, u.first_name, u.last_name,
+			e.employee_number, e.hours_worked, e.regular_hours, e.overtime_hours,
+			e.regular_pay, e.overtime_pay, e.piece_rate_pay, e.commission_pay,
+			e.bonus_pay, e.gross_pay
+		FROM payroll_register_entries e
+		JOIN users u ON u.id = e.user_id
+		WHERE e.tenant_id = $1 AND e.pay_period_id = $2
+		ORDER BY u.last_name, u.first_name, e.user_id`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, periodID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payroll register: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.PayrollRegisterEntry{}
+	byID := make(map[uuid.UUID]*domain.PayrollRegisterEntry)
+	var entryIDs []string
+	for rows.Next() {
+		entry := &domain.PayrollRegisterEntry{Lines: []*domain.PayrollLine{}}
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.TenantID,
+			&entry.PayPeriodID,
+			&entry.UserID,
+			&entry.FirstName,
+			&entry.LastName,
+			&entry.EmployeeNumber,
+			&entry.HoursWorked,
+			&entry.RegularHours,
+			&entry.OvertimeHours,
+			&entry.RegularPay,
+			&entry.OvertimePay,
+			&entry.PieceRatePay,
+			&entry.CommissionPay,
+			&entry.BonusPay,
+			&entry.GrossPay,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan payroll register entry: %w", err)
+		}
+		entries = append(entries, entry)
+		byID[entry.ID] = entry
+		entryIDs = append(entryIDs, entry.ID.String())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(entryIDs) == 0 {
+		return entries, nil
+	}
+
+	lineQuery := `
+		SELECT ` + payrollLineColumns + `
+		FROM payroll_lines
+		WHERE tenant_id = $1 AND entry_id = ANY($2::uuid[])
+		ORDER BY type, description`
+
+	lineRows, err := r.db.QueryContext(ctx, lineQuery, tenantID, pq.Array(entryIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payroll lines: %w", err)
+	}
+	defer lineRows.Close()
+
+	for lineRows.Next() {
+		var line domain.PayrollLine
+		if err := lineRows.Scan(
+			&line.ID,
+			&line.EntryID,
+			&line.Type,
+			&line.Description,
+			&line.JobID,
+			&line.ServiceID,
+			&line.Quantity,
+			&line.Rate,
+			&line.Amount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan payroll line: %w", err)
+		}
+		if entry := byID[line.EntryID]; entry != nil {
+			entry.Lines = append(entry.Lines, &line)
+		}
+	}
+
+	return entries, lineRows.Err()
+}
+
+// listPayRules lists the users' pay rules by user
+func (r *PayrollRepositoryImpl) listPayRules(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID][]*domain.PayRule, error) {
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		SELECT ` + payRuleColumns + `
+		FROM pay_rules
+		WHERE tenant_id = $1 AND user_id = ANY($2::uuid[])
+		ORDER BY user_id, type, effective_from NULLS FIRST`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pay rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make(map[uuid.UUID][]*domain.PayRule)
+	for rows.Next() {
+		var rule domain.PayRule
+		if err := rows.Scan(
+			&rule.ID,
+			&rule.TenantID,
+			&rule.UserID,
+			&rule.Type,
+			&rule.Rate,
+			&rule.ServiceID,
+			&rule.EffectiveFrom,
+			&rule.EffectiveTo,
+			&rule.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan pay rule: %w", err)
+		}
+		rules[rule.UserID] = append(rules[rule.UserID], &rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func (r *PayrollRepositoryImpl) queryPayPeriods(ctx context.Context, query string, args ...interface{}) ([]*domain.PayPeriod, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pay periods: %w", err)
+	}
+	defer rows.Close()
+
+	periods := []*domain.PayPeriod{}
+	for rows.Next() {
+		period, err := scanPayPeriod(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pay period: %w", err)
+		}
+		periods = append(periods, period)
+	}
+
+	return periods, rows.Err()
+}
+
+type payPeriodExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// updatePayPeriod saves a pay period on the database or inside a transaction
+func updatePayPeriod(ctx context.Context, db payPeriodExecer, period *domain.PayPeriod) error {
+	query := `
+		UPDATE pay_periods SET
+			status = $3, total_gross = $4, calculated_at = $5, approved_by = $6,
+			approved_at = $7, exported_at = $8, updated_at = $9
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := db.ExecContext(ctx, query,
+		period.TenantID,
+		period.ID,
+		period.Status,
+		period.TotalGross,
+		period.CalculatedAt,
+		period.ApprovedBy,
+		period.ApprovedAt,
+		period.ExportedAt,
+		period.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update pay period: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("pay period not found")
+	}
+
+	return nil
+}
+
+func scanPayPlan(row rowScanner) (*domain.PayPlan, error) {
+	var plan domain.PayPlan
+	if err := row.Scan(
+		&plan.ID,
+		&plan.TenantID,
+		&plan.UserID,
+		&plan.EmployeeNumber,
+		&plan.CreatedAt,
+		&plan.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func scanTimeEntry(row rowScanner) (*domain.TimeEntry, error) {
+	var entry domain.TimeEntry
+	if err := row.Scan(
+		&entry.ID,
+		&entry.TenantID,
+		&entry.UserID,
+		&entry.JobID,
+		&entry.ClockIn,
+		&entry.ClockOut,
+		&entry.BreakMinutes,
+		&entry.Notes,
+		&entry.CreatedBy,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func scanPayPeriod(row rowScanner) (*domain.PayPeriod, error) {
+	var period domain.PayPeriod
+	if err := row.Scan(
+		&period.ID,
+		&period.TenantID,
+		&period.StartDate,
+		&period.EndDate,
+		&period.Status,
+		&period.TotalGross,
+		&period.CalculatedAt,
+		&period.ApprovedBy,
+		&period.ApprovedAt,
+		&period.ExportedAt,
+		&period.CreatedBy,
+		&period.CreatedAt,
+		&period.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
@@ -0,0 +1,722 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// PipelineRepositoryImpl implements the sales pipeline repository interface
+type PipelineRepositoryImpl struct {
+	db *Database
+}
+
+// NewPipelineRepository creates a new pipeline repository instance
+func NewPipelineRepository(db *Database) services.PipelineRepository {
+	return &PipelineRepositoryImpl{db: db}
+}
+
+const pipelineStageColumns = `
+	id, tenant_id, key, name, stage_type, position, probability, is_active, created_at, updated_at`
+
+const opportunityColumns = `
+	id, tenant_id, title, stage_id, status, lead_id, customer_id, property_id, quote_id,
+	contact_name, contact_email, contact_phone, source, estimated_value, probability,
+	expected_close_date, assigned_to, close_reason, close_notes, closed_at, stage_changed_at,
+	notes, created_by, created_at, updated_at`
+
+const followUpTaskColumns = `
+	id, tenant_id, opportunity_id, customer_id, assigned_to, title, description, task_type,
+	due_at, remind_at, reminder_sent_at, status, completed_at, completed_by, outcome,
+	created_by, created_at, updated_at`
+
+// ListStages lists a tenant's pipeline stages in order
+func (r *PipelineRepositoryImpl) ListStages(ctx context.Context, tenantID uuid.UUID) ([]*domain.PipelineStage, error) {
+	query := `SELECT ` + pipelineStageColumns + ` FROM pipeline_stages WHERE tenant_id = $1 ORDER BY position, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipeline stages: %w", err)
+	}
+	defer rows.Close()
+
+	var stages []*domain.PipelineStage
+	for rows.Next() {
+		var stage domain.PipelineStage
+		if err := rows.Scan(
+			&stage.ID,
+			&stage.TenantID,
+			&stage.Key,
+			&stage.Name,
+			&stage.StageType,
+			&stage.Position,
+			&stage.Probability,
+			&stage.IsActive,
+			&stage.CreatedAt,
+			&stage.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan pipeline stage: %w", err)
+		}
+		stages = append(stages, &stage)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate pipeline stages: %w", err)
+	}
+
+	return stages, nil
+}
+
+// CreateStage stores a new pipeline stage
+func (r *PipelineRepositoryImpl) CreateStage(ctx context.Context, stage *domain.PipelineStage) error {
+	query := `
+		INSERT INTO pipeline_stages (` + pipelineStageColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		stage.ID,
+		stage.TenantID,
+		stage.Key,
+		stage.Name,
+		stage.StageType,
+		stage.Position,
+		stage.Probability,
+		stage.IsActive,
+		stage.CreatedAt,
+		stage.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pipeline stage: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStage saves a pipeline stage
+func (r *PipelineRepositoryImpl) UpdateStage(ctx context.Context, stage *domain.PipelineStage) error {
+	query := `
+		UPDATE pipeline_stages SET
+			name = $3,
+			stage_type = $4,
+			position = $5,
+			probability = $6,
+			is_active = $7,
+			updated_at = $8
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		stage.TenantID,
+		stage.ID,
+		stage.Name,
+		stage.StageType,
+		stage.Position,
+		stage.Probability,
+		stage.IsActive,
+		stage.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update pipeline stage: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("stage not found")
+	}
+
+	return nil
+}
+
+// DeleteStage removes a pipeline stage
+func (r *PipelineRepositoryImpl) DeleteStage(ctx context.Context, tenantID, stageID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM pipeline_stages WHERE tenant_id = $1 AND id = $2`, tenantID, stageID)
+	if err != nil {
+		return fmt.Errorf("failed to delete pipeline stage: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("stage not found")
+	}
+
+	return nil
+}
+
+// CountOpportunitiesInStage counts opportunities currently in a stage
+func (r *PipelineRepositoryImpl) CountOpportunitiesInStage(ctx context.Context, tenantID, stageID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM opportunities WHERE tenant_id = $1 AND stage_id = $2`
+	if err := r.db.QueryRowContext(ctx, query, tenantID, stageID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count opportunities: %w", err)
+	}
+
+	return count, nil
+}
+
+// CreateOpportunity stores a new opportunity
+func (r *PipelineRepositoryImpl) CreateOpportunity(ctx context.Context, opportunity *domain.Opportunity) error {
+	query := `
+		INSERT INTO opportunities (` + opportunityColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+			$17, $18, $19, $20, $21, $22, $23, $24, $25)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		opportunity.ID,
+		opportunity.TenantID,
+		opportunity.Title,
+		opportunity.StageID,
+		opportunity.Status,
+		opportunity.LeadID,
+		opportunity.CustomerID,
+		opportunity.PropertyID,
+		opportunity.QuoteID,
+		opportunity.ContactName,
+		opportunity.ContactEmail,
+		opportunity.ContactPhone,
+		opportunity.Source,
+		opportunity.EstimatedValue,
+		opportunity.Probability,
+		opportunity.ExpectedCloseDate,
+		opportunity.AssignedTo,
+		opportunity.CloseReason,
+		opportunity.CloseNotes,
+		opportunity.ClosedAt,
+		opportunity.StageChangedAt,
+		opportunity.Notes,
+		opportunity.CreatedBy,
+		opportunity.CreatedAt,
+		opportunity.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create opportunity: %w", err)
+	}
+
+	return nil
+}
+
+// GetOpportunity retrieves an opportunity by ID
+func (r *PipelineRepositoryImpl) GetOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) (*domain.Opportunity, error) {
+	query := `SELECT ` + opportunityColumns + ` FROM opportunities WHERE tenant_id = $1 AND id = $2`
+
+	opportunity, err := scanOpportunity(r.db.QueryRowContext(ctx, query, tenantID, opportunityID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get opportunity: %w", err)
+	}
+
+	return opportunity, nil
+}
+
+// UpdateOpportunity saves an opportunity
+func (r *PipelineRepositoryImpl) UpdateOpportunity(ctx context.Context, opportunity *domain.Opportunity) error {
+	query := `
+		UPDATE opportunities SET
+			title = $3,
+			stage_id = $4,
+			status = $5,
+			customer_id = $6,
+			property_id = $7,
+			quote_id = $8,
+			contact_name = $9,
+			contact_email = $10,
+			contact_phone = $11,
+			source = $12,
+			estimated_value = $13,
+			probability = $14,
+			expected_close_date = $15,
+			assigned_to = $16,
+			close_reason = $17,
+			close_notes = $18,
+			closed_at = $19,
+			stage_changed_at = $20,
+			notes = $21,
+			updated_at = $22
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		opportunity.TenantID,
+		opportunity.ID,
+		opportunity.Title,
+		opportunity.StageID,
+		opportunity.Status,
+		opportunity.CustomerID,
+		opportunity.PropertyID,
+		opportunity.QuoteID,
+		opportunity.ContactName,
+		opportunity.ContactEmail,
+		opportunity.ContactPhone,
+		opportunity.Source,
+		opportunity.EstimatedValue,
+		opportunity.Probability,
+		opportunity.ExpectedCloseDate,
+		opportunity.AssignedTo,
+		opportunity.CloseReason,
+		opportunity.CloseNotes,
+		opportunity.ClosedAt,
+		opportunity.StageChangedAt,
+		opportunity.Notes,
+		opportunity.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update opportunity: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("opportunity not found")
+	}
+
+	return nil
+}
+
+// ListOpportunities lists opportunities, most recently moved first
+func (r *PipelineRepositoryImpl) ListOpportunities(ctx context.Context, tenantID uuid.UUID, filter *services.OpportunityFilter) ([]*domain.Opportunity, int64, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.StageID != nil {
+		args = append(args, *filter.StageID)
+		conditions = append(conditions, fmt.Sprintf("stage_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.AssignedTo != nil {
+		args = append(args, *filter.AssignedTo)
+		conditions = append(conditions, fmt.Sprintf("assigned_to = $%d", len(args)))
+	}
+	if filter.CustomerID != nil {
+		args = append(args, *filter.CustomerID)
+		conditions = append(conditions, fmt.Sprintf("customer_id = $%d", len(args)))
+	}
+	if filter.Source != "" {
+		args = append(args, filter.Source)
+		conditions = append(conditions, fmt.Sprintf("source = $%d", len(args)))
+	}
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf(
+			"(title ILIKE $%[1]d OR contact_name ILIKE $%[1]d OR contact_email ILIKE $%[1]d OR contact_phone ILIKE $%[1]d)", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM opportunities WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count opportunities: %w", err)
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	query := `
+		SELECT ` + opportunityColumns + `
+		FROM opportunities
+		WHERE ` + where + fmt.Sprintf(`
+		ORDER BY stage_changed_at DESC
+		LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list opportunities: %w", err)
+	}
+	defer rows.Close()
+
+	opportunities, err := scanOpportunities(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return opportunities, total, nil
+}
+
+// ListOpportunitiesCreated returns opportunities created in a period
+func (r *PipelineRepositoryImpl) ListOpportunitiesCreated(ctx context.Context, tenantID uuid.UUID, filter *services.PipelineMetricsFilter) ([]*domain.Opportunity, error) {
+	conditions := []string{"tenant_id = $1", "created_at >= $2", "created_at < $3"}
+	args := []interface{}{tenantID, filter.Start, filter.End}
+
+	if filter.AssignedTo != nil {
+		args = append(args, *filter.AssignedTo)
+		conditions = append(conditions, fmt.Sprintf("assigned_to = $%d", len(args)))
+	}
+	if filter.Source != "" {
+		args = append(args, filter.Source)
+		conditions = append(conditions, fmt.Sprintf("source = $%d", len(args)))
+	}
+
+	query := `
+		SELECT ` + opportunityColumns + `
+		FROM opportunities
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list opportunities: %w", err)
+	}
+	defer rows.Close()
+
+	return scanOpportunities(rows)
+}
+
+// CreateStageChange records an opportunity moving between stages
+func (r *PipelineRepositoryImpl) CreateStageChange(ctx context.Context, change *domain.OpportunityStageChange) error {
+	query := `
+		INSERT INTO opportunity_stage_changes (
+			id, tenant_id, opportunity_id, from_stage_id, to_stage_id, changed_by, changed_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		change.ID,
+		change.TenantID,
+		change.OpportunityID,
+		change.FromStageID,
+		change.ToStageID,
+		change.ChangedBy,
+		change.ChangedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create stage change: %w", err)
+	}
+
+	return nil
+}
+
+// ListStageChanges returns the stage history of the given opportunities
+func (r *PipelineRepositoryImpl) ListStageChanges(ctx context.Context, tenantID uuid.UUID, opportunityIDs []uuid.UUID) ([]*domain.OpportunityStageChange, error) {
+	ids := make([]string, 0, len(opportunityIDs))
+	for _, id := range opportunityIDs {
+		ids = append(ids, id.String())
+	}
+
+	query := `
+		SELECT id, tenant_id, opportunity_id, from_stage_id, to_stage_id, changed_by, changed_at
+		FROM opportunity_stage_changes
+		WHERE tenant_id = $1 AND opportunity_id = ANY($2::uuid[])
+		ORDER BY changed_at`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stage changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*domain.OpportunityStageChange
+	for rows.Next() {
+		var change domain.OpportunityStageChange
+		if err := rows.Scan(
+			&change.ID,
+			&change.TenantID,
+			&change.OpportunityID,
+			&change.FromStageID,
+			&change.ToStageID,
+			&change.ChangedBy,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan stage change: %w", err)
+		}
+		changes = append(changes, &change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate stage changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// CreateTask stores a new follow-up task
+func (r *PipelineRepositoryImpl) CreateTask(ctx context.Context, task *domain.FollowUpTask) error {
+	query := `
+		INSERT INTO follow_up_tasks (` + followUpTaskColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		task.ID,
+		task.TenantID,
+		task.OpportunityID,
+		task.CustomerID,
+		task.AssignedTo,
+		task.Title,
+		task.Description,
+		task.TaskType,
+		task.DueAt,
+		task.RemindAt,
+		task.ReminderSentAt,
+		task.Status,
+		task.CompletedAt,
+		task.CompletedBy,
+		task.Outcome,
+		task.CreatedBy,
+		task.CreatedAt,
+		task.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create follow-up task: %w", err)
+	}
+
+	return nil
+}
+
+// GetTask retrieves a follow-up task by ID
+func (r *PipelineRepositoryImpl) GetTask(ctx context.Context, tenantID, taskID uuid.UUID) (*domain.FollowUpTask, error) {
+	query := `SELECT ` + followUpTaskColumns + ` FROM follow_up_tasks WHERE tenant_id = $1 AND id = $2`
+
+	task, err := scanFollowUpTask(r.db.QueryRowContext(ctx, query, tenantID, taskID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get follow-up task: %w", err)
+	}
+
+	return task, nil
+}
+
+// UpdateTask saves a follow-up task
+func (r *PipelineRepositoryImpl) UpdateTask(ctx context.Context, task *domain.FollowUpTask) error {
+	query := `
+		UPDATE follow_up_tasks SET
+			opportunity_id = $3,
+			customer_id = $4,
+			assigned_to = $5,
+			title = $6,
+			description = $7,
+			task_type = $8,
+			due_at = $9,
+			remind_at = $10,
+			reminder_sent_at = $11,
+			status = $12,
+			completed_at = $13,
+			completed_by = $14,
+			outcome = $15,
+			updated_at = $16
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		task.TenantID,
+		task.ID,
+		task.OpportunityID,
+		task.CustomerID,
+		task.AssignedTo,
+		task.Title,
+		task.Description,
+		task.TaskType,
+		task.DueAt,
+		task.RemindAt,
+		task.ReminderSentAt,
+		task.Status,
+		task.CompletedAt,
+		task.CompletedBy,
+		task.Outcome,
+		task.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update follow-up task: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("follow-up task not found")
+	}
+
+	return nil
+}
+
+// ListTasks lists follow-up tasks, soonest due first
+func (r *PipelineRepositoryImpl) ListTasks(ctx context.Context, tenantID uuid.UUID, filter *services.FollowUpTaskFilter) ([]*domain.FollowUpTask, int64, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.AssignedTo != nil {
+		args = append(args, *filter.AssignedTo)
+		conditions = append(conditions, fmt.Sprintf("assigned_to = $%d", len(args)))
+	}
+	if filter.OpportunityID != nil {
+		args = append(args, *filter.OpportunityID)
+		conditions = append(conditions, fmt.Sprintf("opportunity_id = $%d", len(args)))
+	}
+	if filter.CustomerID != nil {
+		args = append(args, *filter.CustomerID)
+		conditions = append(conditions, fmt.Sprintf("customer_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.DueBefore != nil {
+		args = append(args, *filter.DueBefore)
+		conditions = append(conditions, fmt.Sprintf("due_at < $%d", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM follow_up_tasks WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count follow-up tasks: %w", err)
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	query := `
+		SELECT ` + followUpTaskColumns + `
+		FROM follow_up_tasks
+		WHERE ` + where + fmt.Sprintf(`
+		ORDER BY (status = 'open') DESC, due_at
+		LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list follow-up tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks, err := scanFollowUpTasks(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return tasks, total, nil
+}
+
+// ListDueReminders returns open tasks across tenants whose reminder time has passed
+func (r *PipelineRepositoryImpl) ListDueReminders(ctx context.Context, asOf time.Time) ([]*domain.FollowUpTask, error) {
+	query := `
+		SELECT ` + followUpTaskColumns + `
+		FROM follow_up_tasks
+		WHERE status = 'open' AND reminder_sent_at IS NULL AND COALESCE(remind_at, due_at) <= $1
+		ORDER BY COALESCE(remind_at, due_at)
+		LIMIT 500`
+
+	rows, err := r.db.QueryContext(ctx, query, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due reminders: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFollowUpTasks(rows)
+}
+
+// MarkReminderSent records that a task's reminder went out
+func (r *PipelineRepositoryImpl) MarkReminderSent(ctx context.Context, tenantID, taskID uuid.UUID, sentAt time.Time) error {
+	query := `UPDATE follow_up_tasks SET reminder_sent_at = $3 WHERE tenant_id = $1 AND id = $2`
+	if _, err := r.db.ExecContext(ctx, query, tenantID, taskID, sentAt); err != nil {
+		return fmt.Errorf("failed to mark reminder sent: %w", err)
+	}
+
+	return nil
+}
+
+func scanOpportunities(rows *sql.Rows) ([]*domain.Opportunity, error) {
+	var opportunities []*domain.Opportunity
+	for rows.Next() {
+		opportunity, err := scanOpportunity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan opportunity: %w", err)
+		}
+		opportunities = append(opportunities, opportunity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate opportunities: %w", err)
+	}
+
+	return opportunities, nil
+}
+
+func scanOpportunity(row rowScanner) (*domain.Opportunity, error) {
+	var opportunity domain.Opportunity
+	if err := row.Scan(
+		&opportunity.ID,
+		&opportunity.TenantID,
+		&opportunity.Title,
+		&opportunity.StageID,
+		&opportunity.Status,
+		&opportunity.LeadID,
+		&opportunity.CustomerID,
+		&opportunity.PropertyID,
+		&opportunity.QuoteID,
+		&opportunity.ContactName,
+		&opportunity.ContactEmail,
+		&opportunity.ContactPhone,
+		&opportunity.Source,
+		&opportunity.EstimatedValue,
+		&opportunity.Probability,
+		&opportunity.ExpectedCloseDate,
+		&opportunity.AssignedTo,
+		&opportunity.CloseReason,
+		&opportunity.CloseNotes,
+		&opportunity.ClosedAt,
+		&opportunity.StageChangedAt,
+		&opportunity.Notes,
+		&opportunity.CreatedBy,
+		&opportunity.CreatedAt,
+		&opportunity.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return &opportunity, nil
+}
+
+func scanFollowUpTasks(rows *sql.Rows) ([]*domain.FollowUpTask, error) {
+	var tasks []*domain.FollowUpTask
+	for rows.Next() {
+		task, err := scanFollowUpTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan follow-up task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate follow-up tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+func scanFollowUpTask(row rowScanner) (*domain.FollowUpTask, error) {
+	var task domain.FollowUpTask
+	if err := row.Scan(
+		&task.ID,
+		&task.TenantID,
+		&task.OpportunityID,
+		&task.CustomerID,
+		&task.AssignedTo,
+		&task.Title,
+		&task.Description,
+		&task.TaskType,
+		&task.DueAt,
+		&task.RemindAt,
+		&task.ReminderSentAt,
+		&task.Status,
+		&task.CompletedAt,
+		&task.CompletedBy,
+		&task.Outcome,
+		&task.CreatedBy,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
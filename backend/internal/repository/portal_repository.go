@@ -0,0 +1,646 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// PortalRepositoryImpl implements the customer portal repository interface
+type PortalRepositoryImpl struct {
+	db *Database
+}
+
+// NewPortalRepository creates a new portal repository instance
+func NewPortalRepository(db *Database) services.PortalRepository {
+	return &PortalRepositoryImpl{db: db}
+}
+
+const portalSettingsColumns = `
+	id, tenant_id, enabled, portal_url, magic_link_ttl_minutes, session_ttl_hours,
+	allow_online_payment, allow_quote_acceptance, allow_skip, allow_reschedule,
+	min_notice_hours, max_reschedule_days, max_skips_per_year, auto_approve_changes,
+	signature_consent_text, created_at, updated_at`
+
+const portalLoginTokenColumns = `
+	id, tenant_id, customer_id, token_hash, email, expires_at, used_at, ip_address, created_at`
+
+const portalSessionColumns = `
+	id, tenant_id, customer_id, token_hash, expires_at, last_seen_at, revoked_at,
+	ip_address, user_agent, created_at`
+
+const quoteSignatureColumns = `
+	id, tenant_id, quote_id, quote_version_id, customer_id, signer_name, signer_email,
+	signature_type, signature_data, consent_text, total_amount, ip_address, user_agent, signed_at`
+
+const portalScheduleChangeColumns = `
+	id, tenant_id, customer_id, job_id, change_type, original_date, requested_date, reason,
+	status, auto_approved, reviewed_by, reviewed_at, response_note, created_at, updated_at`
+
+const portalServiceRequestColumns = `
+	id, tenant_id, customer_id, property_id, service_id, description, preferred_date,
+	status, admin_notes, assigned_to, created_at, updated_at`
+
+// GetSettings retrieves a tenant's portal settings
+func (r *PortalRepositoryImpl) GetSettings(ctx context.Context, tenantID uuid.UUID) (*domain.PortalSettings, error) {
+	query := `SELECT ` + portalSettingsColumns + ` FROM portal_settings WHERE tenant_id = $1`
+
+	var settings domain.PortalSettings
+	err := r.db.QueryRowContext(ctx, query, tenantID).Scan(
+		&settings.ID,
+		&settings.TenantID,
+		&settings.Enabled,
+		&settings.PortalURL,
+		&settings.MagicLinkTTLMinutes,
+		&settings.SessionTTLHours,
+		&settings.AllowOnlinePayment,
+		&settings.AllowQuoteAcceptance,
+		&settings.AllowSkip,
+		&settings.AllowReschedule,
+		&settings.MinNoticeHours,
+		&settings.MaxRescheduleDays,
+		&settings.MaxSkipsPerYear,
+		&settings.AutoApproveChanges,
+		&settings.SignatureConsentText,
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get portal settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// UpsertSettings creates or updates a tenant's portal settings
+func (r *PortalRepositoryImpl) UpsertSettings(ctx context.Context, settings *domain.PortalSettings) error {
+	query := `
+		INSERT INTO portal_settings (` + portalSettingsColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			portal_url = EXCLUDED.portal_url,
+			magic_link_ttl_minutes = EXCLUDED.magic_link_ttl_minutes,
+			session_ttl_hours = EXCLUDED.session_ttl_hours,
+			allow_online_payment = EXCLUDED.allow_online_payment,
+			allow_quote_acceptance = EXCLUDED.allow_quote_acceptance,
+			allow_skip = EXCLUDED.allow_skip,
+			allow_reschedule = EXCLUDED.allow_reschedule,
+			min_notice_hours = EXCLUDED.min_notice_hours,
+			max_reschedule_days = EXCLUDED.max_reschedule_days,
+			max_skips_per_year = EXCLUDED.max_skips_per_year,
+			auto_approve_changes = EXCLUDED.auto_approve_changes,
+			signature_consent_text = EXCLUDED.signature_consent_text,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.ExecContext(ctx, query,
+		settings.ID,
+		settings.TenantID,
+		settings.Enabled,
+		settings.PortalURL,
+		settings.MagicLinkTTLMinutes,
+		settings.SessionTTLHours,
+		settings.AllowOnlinePayment,
+		settings.AllowQuoteAcceptance,
+		settings.AllowSkip,
+		settings.AllowReschedule,
+		settings.MinNoticeHours,
+		settings.MaxRescheduleDays,
+		settings.MaxSkipsPerYear,
+		settings.AutoApproveChanges,
+		settings.SignatureConsentText,
+		settings.CreatedAt,
+		settings.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert portal settings: %w", err)
+	}
+
+	return nil
+}
+
+// CreateLoginToken stores a magic link token hash
+func (r *PortalRepositoryImpl) CreateLoginToken(ctx context.Context, token *domain.PortalLoginToken) error {
+	query := `
+		INSERT INTO portal_login_tokens (` + portalLoginTokenColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		token.ID,
+		token.TenantID,
+		token.CustomerID,
+		token.TokenHash,
+		token.Email,
+		token.ExpiresAt,
+		token.UsedAt,
+		token.IPAddress,
+		token.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create login token: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeLoginToken marks an unused, unexpired token as used and returns it. A token
+// that is unknown, expired or already used returns nil, so each link works once.
+func (r *PortalRepositoryImpl) ConsumeLoginToken(ctx context.Context, tokenHash string, usedAt time.Time) (*domain.PortalLoginToken, error) {
+	query := `
+		UPDATE portal_login_tokens
+		SET used_at = $2
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING ` + portalLoginTokenColumns
+
+	var token domain.PortalLoginToken
+	err := r.db.QueryRowContext(ctx, query, tokenHash, usedAt).Scan(
+		&token.ID,
+		&token.TenantID,
+		&token.CustomerID,
+		&token.TokenHash,
+		&token.Email,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.IPAddress,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to consume login token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// CountLoginTokensSince counts magic links issued to a customer since a time
+func (r *PortalRepositoryImpl) CountLoginTokensSince(ctx context.Context, customerID uuid.UUID, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM portal_login_tokens WHERE customer_id = $1 AND created_at >= $2`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, customerID, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count login tokens: %w", err)
+	}
+
+	return count, nil
+}
+
+// CreateSession stores a portal session
+func (r *PortalRepositoryImpl) CreateSession(ctx context.Context, session *domain.PortalSession) error {
+	query := `
+		INSERT INTO portal_sessions (` + portalSessionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		session.ID,
+		session.TenantID,
+		session.CustomerID,
+		session.TokenHash,
+		session.ExpiresAt,
+		session.LastSeenAt,
+		session.RevokedAt,
+		session.IPAddress,
+		session.UserAgent,
+		session.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create portal session: %w", err)
+	}
+
+	return nil
+}
+
+// GetSessionByTokenHash retrieves a portal session by its token hash
+func (r *PortalRepositoryImpl) GetSessionByTokenHash(ctx context.Context, tokenHash string) (*domain.PortalSession, error) {
+	query := `SELECT ` + portalSessionColumns + ` FROM portal_sessions WHERE token_hash = $1`
+
+	var session domain.PortalSession
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&session.ID,
+		&session.TenantID,
+		&session.CustomerID,
+		&session.TokenHash,
+		&session.ExpiresAt,
+		&session.LastSeenAt,
+		&session.RevokedAt,
+		&session.IPAddress,
+		&session.UserAgent,
+		&session.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get portal session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// TouchSession records session activity
+func (r *PortalRepositoryImpl) TouchSession(ctx context.Context, sessionID uuid.UUID, seenAt time.Time) error {
+	query := `UPDATE portal_sessions SET last_seen_at = $2 WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, sessionID, seenAt); err != nil {
+		return fmt.Errorf("failed to update portal session: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeSession revokes a portal session
+func (r *PortalRepositoryImpl) RevokeSession(ctx context.Context, sessionID uuid.UUID, revokedAt time.Time) error {
+	query := `UPDATE portal_sessions SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`
+
+	if _, err := r.db.ExecContext(ctx, query, sessionID, revokedAt); err != nil {
+		return fmt.Errorf("failed to revoke portal session: %w", err)
+	}
+
+	return nil
+}
+
+// GetQuoteSignature retrieves the latest signature on a quote
+func (r *PortalRepositoryImpl) GetQuoteSignature(ctx context.Context, tenantID, quoteID uuid.UUID) (*domain.QuoteSignature, error) {
+	query := `
+		SELECT ` + quoteSignatureColumns + `
+		FROM quote_signatures
+		WHERE quote_id = $1 AND tenant_id = $2
+		ORDER BY signed_at DESC
+		LIMIT 1`
+
+	var signature domain.QuoteSignature
+	err := r.db.QueryRowContext(ctx, query, quoteID, tenantID).Scan(
+		&signature.ID,
+		&signature.TenantID,
+		&signature.QuoteID,
+		&signature.QuoteVersionID,
+		&signature.CustomerID,
+		&signature.SignerName,
+		&signature.SignerEmail,
+		&signature.SignatureType,
+		&signature.SignatureData,
+		&signature.ConsentText,
+		&signature.TotalAmount,
+		&signature.IPAddress,
+		&signature.UserAgent,
+		&signature.SignedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get quote signature: %w", err)
+	}
+
+	return &signature, nil
+}
+
+// CreateScheduleChange stores a skip or reschedule request
+func (r *PortalRepositoryImpl) CreateScheduleChange(ctx context.Context, change *domain.PortalScheduleChange) error {
+	query := `
+		INSERT INTO portal_schedule_changes (` + portalScheduleChangeColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		change.ID,
+		change.TenantID,
+		change.CustomerID,
+		change.JobID,
+		change.ChangeType,
+		change.OriginalDate,
+		change.RequestedDate,
+		change.Reason,
+		change.Status,
+		change.AutoApproved,
+		change.ReviewedBy,
+		change.ReviewedAt,
+		change.ResponseNote,
+		change.CreatedAt,
+		change.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create schedule change: %w", err)
+	}
+
+	return nil
+}
+
+// GetScheduleChange retrieves a schedule change by ID
+func (r *PortalRepositoryImpl) GetScheduleChange(ctx context.Context, tenantID, changeID uuid.UUID) (*domain.PortalScheduleChange, error) {
+	query := `SELECT ` + portalScheduleChangeColumns + ` FROM portal_schedule_changes WHERE id = $1 AND tenant_id = $2`
+
+	change, err := scanPortalScheduleChange(r.db.QueryRowContext(ctx, query, changeID, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get schedule change: %w", err)
+	}
+
+	return change, nil
+}
+
+// UpdateScheduleChange updates a schedule change's review
+func (r *PortalRepositoryImpl) UpdateScheduleChange(ctx context.Context, change *domain.PortalScheduleChange) error {
+	query := `
+		UPDATE portal_schedule_changes
+		SET status = $3, auto_approved = $4, reviewed_by = $5, reviewed_at = $6,
+			response_note = $7, updated_at = $8
+		WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		change.ID,
+		change.TenantID,
+		change.Status,
+		change.AutoApproved,
+		change.ReviewedBy,
+		change.ReviewedAt,
+		change.ResponseNote,
+		change.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update schedule change: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("schedule change not found")
+	}
+
+	return nil
+}
+
+// ListScheduleChanges lists schedule changes, oldest pending first
+func (r *PortalRepositoryImpl) ListScheduleChanges(ctx context.Context, tenantID uuid.UUID, filter *services.PortalRequestFilter) ([]*domain.PortalScheduleChange, error) {
+	where, args := portalRequestConditions(tenantID, filter)
+	query := `
+		SELECT ` + portalScheduleChangeColumns + `
+		FROM portal_schedule_changes
+		WHERE ` + where + `
+		ORDER BY (status = 'pending') DESC, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedule changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*domain.PortalScheduleChange
+	for rows.Next() {
+		change, err := scanPortalScheduleChange(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate schedule changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetPendingScheduleChange retrieves the pending change for a job, if any
+func (r *PortalRepositoryImpl) GetPendingScheduleChange(ctx context.Context, tenantID, jobID uuid.UUID) (*domain.PortalScheduleChange, error) {
+	query := `
+		SELECT ` + portalScheduleChangeColumns + `
+		FROM portal_schedule_changes
+		WHERE job_id = $1 AND tenant_id = $2 AND status = 'pending'
+		LIMIT 1`
+
+	change, err := scanPortalScheduleChange(r.db.QueryRowContext(ctx, query, jobID, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pending schedule change: %w", err)
+	}
+
+	return change, nil
+}
+
+// CountApprovedSkipsSince counts a customer's approved skips since a time
+func (r *PortalRepositoryImpl) CountApprovedSkipsSince(ctx context.Context, tenantID, customerID uuid.UUID, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM portal_schedule_changes
+		WHERE tenant_id = $1 AND customer_id = $2 AND change_type = 'skip'
+		  AND status = 'approved' AND created_at >= $3`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, tenantID, customerID, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count skips: %w", err)
+	}
+
+	return count, nil
+}
+
+// CreateServiceRequest stores a customer service request
+func (r *PortalRepositoryImpl) CreateServiceRequest(ctx context.Context, request *domain.PortalServiceRequest) error {
+	query := `
+		INSERT INTO portal_service_requests (` + portalServiceRequestColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		request.ID,
+		request.TenantID,
+		request.CustomerID,
+		request.PropertyID,
+		request.ServiceID,
+		request.Description,
+		request.PreferredDate,
+		request.Status,
+		request.AdminNotes,
+		request.AssignedTo,
+		request.CreatedAt,
+		request.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create service request: %w", err)
+	}
+
+	return nil
+}
+
+// GetServiceRequest retrieves a service request by ID
+func (r *PortalRepositoryImpl) GetServiceRequest(ctx context.Context, tenantID, requestID uuid.UUID) (*domain.PortalServiceRequest, error) {
+	query := `SELECT ` + portalServiceRequestColumns + ` FROM portal_service_requests WHERE id = $1 AND tenant_id = $2`
+
+	request, err := scanPortalServiceRequest(r.db.QueryRowContext(ctx, query, requestID, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get service request: %w", err)
+	}
+
+	return request, nil
+}
+
+// UpdateServiceRequest updates a service request
+func (r *PortalRepositoryImpl) UpdateServiceRequest(ctx context.Context, request *domain.PortalServiceRequest) error {
+	query := `
+		UPDATE portal_service_requests
+		SET status = $3, admin_notes = $4, assigned_to = $5, updated_at = $6
+		WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		request.ID,
+		request.TenantID,
+		request.Status,
+		request.AdminNotes,
+		request.AssignedTo,
+		request.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update service request: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("service request not found")
+	}
+
+	return nil
+}
+
+// ListServiceRequests lists service requests, oldest new requests first
+func (r *PortalRepositoryImpl) ListServiceRequests(ctx context.Context, tenantID uuid.UUID, filter *services.PortalRequestFilter) ([]*domain.PortalServiceRequest, error) {
+	where, args := portalRequestConditions(tenantID, filter)
+	query := `
+		SELECT ` + portalServiceRequestColumns + `
+		FROM portal_service_requests
+		WHERE ` + where + `
+		ORDER BY (status = 'new') DESC, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service requests: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []*domain.PortalServiceRequest
+	for rows.Next() {
+		request, err := scanPortalServiceRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan service request: %w", err)
+		}
+		requests = append(requests, request)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate service requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+func portalRequestConditions(tenantID uuid.UUID, filter *services.PortalRequestFilter) (string, []interface{}) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter != nil {
+		if filter.CustomerID != nil {
+			args = append(args, *filter.CustomerID)
+			conditions = append(conditions, fmt.Sprintf("customer_id = $%d", len(args)))
+		}
+		if filter.Status != "" {
+			args = append(args, filter.Status)
+			conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+func scanPortalScheduleChange(row rowScanner) (*domain.PortalScheduleChange, error) {
+	var change domain.PortalScheduleChange
+	if err := row.Scan(
+		&change.ID,
+		&change.TenantID,
+		&change.CustomerID,
+		&change.JobID,
+		&change.ChangeType,
+		&change.OriginalDate,
+		&change.RequestedDate,
+		&change.Reason,
+		&change.Status,
+		&change.AutoApproved,
+		&change.ReviewedBy,
+		&change.ReviewedAt,
+		&change.ResponseNote,
+		&change.CreatedAt,
+		&change.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+func scanPortalServiceRequest(row rowScanner) (*domain.PortalServiceRequest, error) {
+	var request domain.PortalServiceRequest
+	if err := row.Scan(
+		&request.ID,
+		&request.TenantID,
+		&request.CustomerID,
+		&request.PropertyID,
+		&request.ServiceID,
+		&request.Description,
+		&request.PreferredDate,
+		&request.Status,
+		&request.AdminNotes,
+		&request.AssignedTo,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// insertQuoteSignature stores a quote acceptance signature; see QuoteRepositoryImpl.ApproveWithSignature
+func insertQuoteSignature(ctx context.Context, db quoteExecer, signature *domain.QuoteSignature) error {
+	query := `
+		INSERT INTO quote_signatures (` + quoteSignatureColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+
+	_, err := db.ExecContext(ctx, query,
+		signature.ID,
+		signature.TenantID,
+		signature.QuoteID,
+		signature.QuoteVersionID,
+		signature.CustomerID,
+		signature.SignerName,
+		signature.SignerEmail,
+		signature.SignatureType,
+		signature.SignatureData,
+		signature.ConsentText,
+		signature.TotalAmount,
+		signature.IPAddress,
+		signature.UserAgent,
+		signature.SignedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create quote signature: %w", err)
+	}
+
+	return nil
+}
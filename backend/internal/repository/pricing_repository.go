@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// PricingRepositoryImpl implements the pricing repository interface
+type PricingRepositoryImpl struct {
+	db *Database
+}
+
+// NewPricingRepository creates a new pricing repository instance
+func NewPricingRepository(db *Database) services.PricingRepository {
+	return &PricingRepositoryImpl{db: db}
+}
+
+// CreatePriceBook creates a price book
+func (r *PricingRepositoryImpl) CreatePriceBook(ctx context.Context, book *domain.PriceBook) error {
+	query := `
+		INSERT INTO price_books (` + services.PriceBookColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		book.ID,
+		book.TenantID,
+		book.Name,
+		book.Description,
+		book.EffectiveFrom,
+		book.EffectiveTo,
+		book.IsActive,
+		book.CreatedAt,
+		book.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create price book: %w", err)
+	}
+
+	return nil
+}
+
+// GetPriceBook retrieves a price book with its rules
+func (r *PricingRepositoryImpl) GetPriceBook(ctx context.Context, tenantID, bookID uuid.UUID) (*domain.PriceBook, error) {
+	query := `SELECT ` + services.PriceBookColumns + ` FROM price_books WHERE id = $1 AND tenant_id = $2`
+
+	book, err := services.ScanPriceBook(r.db.QueryRowContext(ctx, query, bookID, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get price book: %w", err)
+	}
+
+	if err := services.LoadPricingRules(ctx, r.db, tenantID, []*domain.PriceBook{book}); err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
+
+// UpdatePriceBook updates a price book
+func (r *PricingRepositoryImpl) UpdatePriceBook(ctx context.Context, book *domain.PriceBook) error {
+	query := `
+		UPDATE price_books
+		SET name = $3, description = $4, effective_from = $5, effective_to = $6,
+			is_active = $7, updated_at = $8
+		WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		book.ID,
+		book.TenantID,
+		book.Name,
+		book.Description,
+		book.EffectiveFrom,
+		book.EffectiveTo,
+		book.IsActive,
+		book.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update price book: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("price book not found")
+	}
+
+	return nil
+}
+
+// DeletePriceBook deletes a price book; its rules are removed by cascade
+func (r *PricingRepositoryImpl) DeletePriceBook(ctx context.Context, tenantID, bookID uuid.UUID) error {
+	query := `DELETE FROM price_books WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, bookID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete price book: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("price book not found")
+	}
+
+	return nil
+}
+
+// ListPriceBooks lists a tenant's price books with their rules, newest first
+func (r *PricingRepositoryImpl) ListPriceBooks(ctx context.Context, tenantID uuid.UUID) ([]*domain.PriceBook, error) {
+	query := `
+		SELECT ` + services.PriceBookColumns + `
+		FROM price_books
+		WHERE tenant_id = $1
+		ORDER BY effective_from DESC, name`
+
+	return services.LoadPriceBooks(ctx, r.db, tenantID, query, tenantID)
+}
+
+// GetPriceBooksInEffect retrieves the active price books covering a date, with their rules
+func (r *PricingRepositoryImpl) GetPriceBooksInEffect(ctx context.Context, tenantID uuid.UUID, date time.Time) ([]*domain.PriceBook, error) {
+	return services.LoadPriceBooksInEffect(ctx, r.db, tenantID, date)
+}
+
+// CreatePricingRule creates a pricing rule
+func (r *PricingRepositoryImpl) CreatePricingRule(ctx context.Context, rule *domain.PricingRule) error {
+	zipCodes, err := json.Marshal(rule.ZipCodes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal zip codes: %w", err)
+	}
+
+	query := `
+		INSERT INTO pricing_rules (` + services.PricingRuleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		rule.ID,
+		rule.TenantID,
+		rule.PriceBookID,
+		rule.Name,
+		rule.RuleType,
+		rule.ServiceID,
+		rule.ServiceKey,
+		rule.Category,
+		rule.Unit,
+		rule.MeasuredArea,
+		rule.Rate,
+		rule.MinValue,
+		rule.MaxValue,
+		rule.Frequency,
+		zipCodes,
+		rule.IsActive,
+		rule.CreatedAt,
+		rule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pricing rule: %w", err)
+	}
+
+	return nil
+}
+
+// GetPricingRule retrieves a pricing rule by ID
+func (r *PricingRepositoryImpl) GetPricingRule(ctx context.Context, tenantID, ruleID uuid.UUID) (*domain.PricingRule, error) {
+	query := `SELECT ` + services.PricingRuleColumns + ` FROM pricing_rules WHERE id = $1 AND tenant_id = $2`
+
+	rule, err := services.ScanPricingRule(r.db.QueryRowContext(ctx, query, ruleID, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pricing rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// UpdatePricingRule updates a pricing rule
+func (r *PricingRepositoryImpl) UpdatePricingRule(ctx context.Context, rule *domain.PricingRule) error {
+	zipCodes, err := json.Marshal(rule.ZipCodes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal zip codes: %w", err)
+	}
+
+	query := `
+		UPDATE pricing_rules
+		SET name = $3, rule_type = $4, service_id = $5, service_key = $6, category = $7,
+			unit = $8, measured_area = $9, rate = $10, min_value = $11, max_value = $12,
+			frequency = $13, zip_codes = $14, is_active = $15, updated_at = $16
+		WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		rule.ID,
+		rule.TenantID,
+		rule.Name,
+		rule.RuleType,
+		rule.ServiceID,
+		rule.ServiceKey,
+		rule.Category,
+		rule.Unit,
+		rule.MeasuredArea,
+		rule.Rate,
+		rule.MinValue,
+		rule.MaxValue,
+		rule.Frequency,
+		zipCodes,
+		rule.IsActive,
+		rule.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update pricing rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("pricing rule not found")
+	}
+
+	return nil
+}
+
+// DeletePricingRule deletes a pricing rule
+func (r *PricingRepositoryImpl) DeletePricingRule(ctx context.Context, tenantID, ruleID uuid.UUID) error {
+	query := `DELETE FROM pricing_rules WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, ruleID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete pricing rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("pricing rule not found")
+	}
+
+	return nil
+}
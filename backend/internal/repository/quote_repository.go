@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -71,11 +72,12 @@ func (r *QuoteRepositoryImpl) GetByID(ctx context.Context, tenantID, quoteID uui
 		SELECT id, tenant_id, customer_id, property_id, quote_number, title, description,
 			   subtotal, tax_rate, tax_amount, total_amount, status, valid_until,
 			   terms_and_conditions, notes, created_by, approved_by, approved_at,
-			   created_at, updated_at
+			   current_version, approved_version_id, approved_selection, created_at, updated_at
 		FROM quotes
 		WHERE id = $1 AND tenant_id = $2`
 
 	var quote domain.Quote
+	var approvedSelection []byte
 	err := r.db.QueryRowContext(ctx, query, quoteID, tenantID).Scan(
 		&quote.ID,
 		&quote.TenantID,
@@ -95,6 +97,9 @@ func (r *QuoteRepositoryImpl) GetByID(ctx context.Context, tenantID, quoteID uui
 		&quote.CreatedBy,
 		&quote.ApprovedBy,
 		&quote.ApprovedAt,
+		&quote.CurrentVersion,
+		&quote.ApprovedVersionID,
+		&approvedSelection,
 		&quote.CreatedAt,
 		&quote.UpdatedAt,
 	)
@@ -106,52 +111,36 @@ func (r *QuoteRepositoryImpl) GetByID(ctx context.Context, tenantID, quoteID uui
 		return nil, fmt.Errorf("failed to get quote: %w", err)
 	}
 
+	if quote.ApprovedSelection, err = decodeQuoteSelection(approvedSelection); err != nil {
+		return nil, err
+	}
+
 	return &quote, nil
 }
 
 // Update updates an existing quote
 func (r *QuoteRepositoryImpl) Update(ctx context.Context, quote *domain.Quote) error {
-	query := `
-		UPDATE quotes SET
-			customer_id = $3, property_id = $4, quote_number = $5, title = $6, description = $7,
-			subtotal = $8, tax_rate = $9, tax_amount = $10, total_amount = $11, status = $12,
-			valid_until = $13, terms_and_conditions = $14, notes = $15, created_by = $16,
-			approved_by = $17, approved_at = $18, updated_at = $19
-		WHERE id = $1 AND tenant_id = $2`
-
-	result, err := r.db.ExecContext(ctx, query,
-		quote.ID,
-		quote.TenantID,
-		quote.CustomerID,
-		quote.PropertyID,
-		quote.QuoteNumber,
-		quote.Title,
-		quote.Description,
-		quote.Subtotal,
-		quote.TaxRate,
-		quote.TaxAmount,
-		quote.TotalAmount,
-		quote.Status,
-		quote.ValidUntil,
-		quote.TermsAndConditions,
-		quote.Notes,
-		quote.CreatedBy,
-		quote.ApprovedBy,
-		quote.ApprovedAt,
-		quote.UpdatedAt,
-	)
+	return updateQuote(ctx, r.db, quote)
+}
 
+// ApproveWithSignature saves an approved quote and the customer's signature together, so a
+// quote is never approved without its signature on record
+func (r *QuoteRepositoryImpl) ApproveWithSignature(ctx context.Context, quote *domain.Quote, signature *domain.QuoteSignature) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update quote: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	if err := updateQuote(ctx, tx, quote); err != nil {
+		return err
+	}
+	if err := insertQuoteSignature(ctx, tx, signature); err != nil {
+		return err
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("quote not found")
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit quote approval: %w", err)
 	}
 
 	return nil
@@ -242,7 +231,7 @@ func (r *QuoteRepositoryImpl) List(ctx context.Context, tenantID uuid.UUID, filt
 		SELECT id, tenant_id, customer_id, property_id, quote_number, title, description,
 			   subtotal, tax_rate, tax_amount, total_amount, status, valid_until,
 			   terms_and_conditions, notes, created_by, approved_by, approved_at,
-			   created_at, updated_at`
+			   current_version, approved_version_id, approved_selection, created_at, updated_at`
 
 	orderBy := " ORDER BY created_at DESC"
 	if filter.SortBy != "" {
@@ -267,6 +256,7 @@ func (r *QuoteRepositoryImpl) List(ctx context.Context, tenantID uuid.UUID, filt
 	var quotes []*domain.Quote
 	for rows.Next() {
 		var quote domain.Quote
+		var approvedSelection []byte
 		err := rows.Scan(
 			&quote.ID,
 			&quote.TenantID,
@@ -286,12 +276,18 @@ func (r *QuoteRepositoryImpl) List(ctx context.Context, tenantID uuid.UUID, filt
 			&quote.CreatedBy,
 			&quote.ApprovedBy,
 			&quote.ApprovedAt,
+			&quote.CurrentVersion,
+			&quote.ApprovedVersionID,
+			&approvedSelection,
 			&quote.CreatedAt,
 			&quote.UpdatedAt,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan quote: %w", err)
 		}
+		if quote.ApprovedSelection, err = decodeQuoteSelection(approvedSelection); err != nil {
+			return nil, 0, err
+		}
 		quotes = append(quotes, &quote)
 	}
 
@@ -326,7 +322,7 @@ func (r *QuoteRepositoryImpl) GetByStatus(ctx context.Context, tenantID uuid.UUI
 		SELECT id, tenant_id, customer_id, property_id, quote_number, title, description,
 			   subtotal, tax_rate, tax_amount, total_amount, status, valid_until,
 			   terms_and_conditions, notes, created_by, approved_by, approved_at,
-			   created_at, updated_at
+			   current_version, approved_version_id, approved_selection, created_at, updated_at
 		FROM quotes
 		WHERE tenant_id = $1 AND status = $2
 		ORDER BY created_at DESC`
@@ -340,6 +336,7 @@ func (r *QuoteRepositoryImpl) GetByStatus(ctx context.Context, tenantID uuid.UUI
 	var quotes []*domain.Quote
 	for rows.Next() {
 		var quote domain.Quote
+		var approvedSelection []byte
 		err := rows.Scan(
 			&quote.ID,
 			&quote.TenantID,
@@ -359,12 +356,18 @@ func (r *QuoteRepositoryImpl) GetByStatus(ctx context.Context, tenantID uuid.UUI
 			&quote.CreatedBy,
 			&quote.ApprovedBy,
 			&quote.ApprovedAt,
+			&quote.CurrentVersion,
+			&quote.ApprovedVersionID,
+			&approvedSelection,
 			&quote.CreatedAt,
 			&quote.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan quote: %w", err)
 		}
+		if quote.ApprovedSelection, err = decodeQuoteSelection(approvedSelection); err != nil {
+			return nil, err
+		}
 		quotes = append(quotes, &quote)
 	}
 
@@ -375,13 +378,16 @@ func (r *QuoteRepositoryImpl) GetByStatus(ctx context.Context, tenantID uuid.UUI
 func (r *QuoteRepositoryImpl) CreateQuoteService(ctx context.Context, quoteService *domain.QuoteService) error {
 	query := `
 		INSERT INTO quote_services (
-			id, quote_id, service_id, quantity, unit_price, total_price, description, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+			id, quote_id, service_id, option_id, is_optional, quantity, unit_price, total_price,
+			description, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 
 	_, err := r.db.ExecContext(ctx, query,
 		quoteService.ID,
 		quoteService.QuoteID,
 		quoteService.ServiceID,
+		quoteService.OptionID,
+		quoteService.IsOptional,
 		quoteService.Quantity,
 		quoteService.UnitPrice,
 		quoteService.TotalPrice,
@@ -400,7 +406,8 @@ func (r *QuoteRepositoryImpl) CreateQuoteService(ctx context.Context, quoteServi
 func (r *QuoteRepositoryImpl) UpdateQuoteService(ctx context.Context, quoteService *domain.QuoteService) error {
 	query := `
 		UPDATE quote_services SET
-			service_id = $3, quantity = $4, unit_price = $5, total_price = $6, description = $7
+			service_id = $3, quantity = $4, unit_price = $5, total_price = $6, description = $7,
+			option_id = $8, is_optional = $9
 		WHERE id = $1 AND quote_id = $2`
 
 	result, err := r.db.ExecContext(ctx, query,
@@ -411,6 +418,8 @@ func (r *QuoteRepositoryImpl) UpdateQuoteService(ctx context.Context, quoteServi
 		quoteService.UnitPrice,
 		quoteService.TotalPrice,
 		quoteService.Description,
+		quoteService.OptionID,
+		quoteService.IsOptional,
 	)
 
 	if err != nil {
@@ -453,7 +462,8 @@ func (r *QuoteRepositoryImpl) DeleteQuoteService(ctx context.Context, quoteServi
 // GetQuoteServices retrieves all services for a quote
 func (r *QuoteRepositoryImpl) GetQuoteServices(ctx context.Context, quoteID uuid.UUID) ([]*domain.QuoteService, error) {
 	query := `
-		SELECT id, quote_id, service_id, quantity, unit_price, total_price, description, created_at
+		SELECT id, quote_id, service_id, option_id, is_optional, quantity, unit_price, total_price,
+			   description, created_at
 		FROM quote_services
 		WHERE quote_id = $1
 		ORDER BY created_at ASC`
@@ -471,6 +481,8 @@ func (r *QuoteRepositoryImpl) GetQuoteServices(ctx context.Context, quoteID uuid
 			&service.ID,
 			&service.QuoteID,
 			&service.ServiceID,
+			&service.OptionID,
+			&service.IsOptional,
 			&service.Quantity,
 			&service.UnitPrice,
 			&service.TotalPrice,
@@ -486,6 +498,84 @@ func (r *QuoteRepositoryImpl) GetQuoteServices(ctx context.Context, quoteID uuid
 	return services, nil
 }
 
+// CreateQuoteOption creates an option a customer can choose on a quote
+func (r *QuoteRepositoryImpl) CreateQuoteOption(ctx context.Context, option *domain.QuoteOption) error {
+	query := `
+		INSERT INTO quote_options (
+			id, tenant_id, quote_id, group_name, name, description, sort_order, is_default,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		option.ID,
+		option.TenantID,
+		option.QuoteID,
+		option.GroupName,
+		option.Name,
+		option.Description,
+		option.SortOrder,
+		option.IsDefault,
+		option.CreatedAt,
+		option.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create quote option: %w", err)
+	}
+
+	return nil
+}
+
+// GetQuoteOptions retrieves all options for a quote in display order
+func (r *QuoteRepositoryImpl) GetQuoteOptions(ctx context.Context, quoteID uuid.UUID) ([]*domain.QuoteOption, error) {
+	query := `
+		SELECT id, tenant_id, quote_id, group_name, name, description, sort_order, is_default,
+			   created_at, updated_at
+		FROM quote_options
+		WHERE quote_id = $1
+		ORDER BY sort_order ASC, created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote options: %w", err)
+	}
+	defer rows.Close()
+
+	var options []*domain.QuoteOption
+	for rows.Next() {
+		var option domain.QuoteOption
+		err := rows.Scan(
+			&option.ID,
+			&option.TenantID,
+			&option.QuoteID,
+			&option.GroupName,
+			&option.Name,
+			&option.Description,
+			&option.SortOrder,
+			&option.IsDefault,
+			&option.CreatedAt,
+			&option.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote option: %w", err)
+		}
+		options = append(options, &option)
+	}
+
+	return options, nil
+}
+
+// DeleteQuoteOptions deletes a quote's options along with their line items
+func (r *QuoteRepositoryImpl) DeleteQuoteOptions(ctx context.Context, quoteID uuid.UUID) error {
+	query := `DELETE FROM quote_options WHERE quote_id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, quoteID); err != nil {
+		return fmt.Errorf("failed to delete quote options: %w", err)
+	}
+
+	return nil
+}
+
 // GetNextQuoteNumber generates the next quote number for a tenant
 func (r *QuoteRepositoryImpl) GetNextQuoteNumber(ctx context.Context, tenantID uuid.UUID) (string, error) {
 	// Get the current year
@@ -511,3 +601,308 @@ func (r *QuoteRepositoryImpl) GetNextQuoteNumber(ctx context.Context, tenantID u
 	return quoteNumber, nil
 }
 
+const quoteVersionColumns = `
+	id, tenant_id, quote_id, version_number, revision, title, description,
+	subtotal, tax_rate, tax_amount, total_amount, valid_until, terms_and_conditions,
+	notes, line_items, options, change_summary, sent_at, sent_to, created_by, created_at`
+
+// CreateQuoteVersion saves a quote revision, assigning the next version number and
+// advancing the quote's current version in the same transaction
+func (r *QuoteRepositoryImpl) CreateQuoteVersion(ctx context.Context, version *domain.QuoteVersion) error {
+	lineItems, err := json.Marshal(version.LineItems)
+	if err != nil {
+		return fmt.Errorf("failed to encode quote version line items: %w", err)
+	}
+
+	options, err := json.Marshal(version.Options)
+	if err != nil {
+		return fmt.Errorf("failed to encode quote version options: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var currentVersion int
+	err = tx.QueryRowContext(ctx, `
+		SELECT current_version FROM quotes
+		WHERE id = $1 AND tenant_id = $2
+		FOR UPDATE`,
+		version.QuoteID, version.TenantID,
+	).Scan(&currentVersion)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("quote not found")
+		}
+		return fmt.Errorf("failed to lock quote: %w", err)
+	}
+
+	version.VersionNumber = currentVersion + 1
+	version.Revision = services.QuoteRevisionLabel(version.VersionNumber)
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO quote_versions (`+quoteVersionColumns+`
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21
+		)`,
+		version.ID,
+		version.TenantID,
+		version.QuoteID,
+		version.VersionNumber,
+		version.Revision,
+		version.Title,
+		version.Description,
+		version.Subtotal,
+		version.TaxRate,
+		version.TaxAmount,
+		version.TotalAmount,
+		version.ValidUntil,
+		version.TermsAndConditions,
+		version.Notes,
+		lineItems,
+		options,
+		version.ChangeSummary,
+		version.SentAt,
+		version.SentTo,
+		version.CreatedBy,
+		version.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create quote version: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE quotes SET current_version = $3, updated_at = $4
+		WHERE id = $1 AND tenant_id = $2`,
+		version.QuoteID, version.TenantID, version.VersionNumber, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update quote version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// MarkQuoteVersionSent records delivery of a quote revision
+func (r *QuoteRepositoryImpl) MarkQuoteVersionSent(ctx context.Context, tenantID, versionID uuid.UUID, sentTo string, sentAt time.Time) error {
+	query := `
+		UPDATE quote_versions SET sent_to = $3, sent_at = $4
+		WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, versionID, tenantID, sentTo, sentAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark quote version sent: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("quote version not found")
+	}
+
+	return nil
+}
+
+// GetQuoteVersion retrieves a quote revision by version number
+func (r *QuoteRepositoryImpl) GetQuoteVersion(ctx context.Context, tenantID, quoteID uuid.UUID, versionNumber int) (*domain.QuoteVersion, error) {
+	query := `SELECT ` + quoteVersionColumns + `
+		FROM quote_versions
+		WHERE tenant_id = $1 AND quote_id = $2 AND version_number = $3`
+
+	version, err := scanQuoteVersion(r.db.QueryRowContext(ctx, query, tenantID, quoteID, versionNumber))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get quote version: %w", err)
+	}
+
+	return version, nil
+}
+
+// GetQuoteVersionByID retrieves a quote revision by ID
+func (r *QuoteRepositoryImpl) GetQuoteVersionByID(ctx context.Context, tenantID, versionID uuid.UUID) (*domain.QuoteVersion, error) {
+	query := `SELECT ` + quoteVersionColumns + `
+		FROM quote_versions
+		WHERE tenant_id = $1 AND id = $2`
+
+	version, err := scanQuoteVersion(r.db.QueryRowContext(ctx, query, tenantID, versionID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get quote version: %w", err)
+	}
+
+	return version, nil
+}
+
+// GetLatestQuoteVersion retrieves the most recent revision of a quote
+func (r *QuoteRepositoryImpl) GetLatestQuoteVersion(ctx context.Context, tenantID, quoteID uuid.UUID) (*domain.QuoteVersion, error) {
+	query := `SELECT ` + quoteVersionColumns + `
+		FROM quote_versions
+		WHERE tenant_id = $1 AND quote_id = $2
+		ORDER BY version_number DESC
+		LIMIT 1`
+
+	version, err := scanQuoteVersion(r.db.QueryRowContext(ctx, query, tenantID, quoteID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest quote version: %w", err)
+	}
+
+	return version, nil
+}
+
+// ListQuoteVersions lists a quote's revisions, newest first
+func (r *QuoteRepositoryImpl) ListQuoteVersions(ctx context.Context, tenantID, quoteID uuid.UUID) ([]*domain.QuoteVersion, error) {
+	query := `SELECT ` + quoteVersionColumns + `
+		FROM quote_versions
+		WHERE tenant_id = $1 AND quote_id = $2
+		ORDER BY version_number DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quote versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]*domain.QuoteVersion, 0)
+	for rows.Next() {
+		version, err := scanQuoteVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quote version: %w", err)
+		}
+		versions = append(versions, version)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating quote version rows: %w", err)
+	}
+
+	return versions, nil
+}
+
+func scanQuoteVersion(row rowScanner) (*domain.QuoteVersion, error) {
+	var version domain.QuoteVersion
+	var lineItems, options []byte
+
+	err := row.Scan(
+		&version.ID,
+		&version.TenantID,
+		&version.QuoteID,
+		&version.VersionNumber,
+		&version.Revision,
+		&version.Title,
+		&version.Description,
+		&version.Subtotal,
+		&version.TaxRate,
+		&version.TaxAmount,
+		&version.TotalAmount,
+		&version.ValidUntil,
+		&version.TermsAndConditions,
+		&version.Notes,
+		&lineItems,
+		&options,
+		&version.ChangeSummary,
+		&version.SentAt,
+		&version.SentTo,
+		&version.CreatedBy,
+		&version.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(lineItems, &version.LineItems); err != nil {
+		return nil, fmt.Errorf("failed to decode quote version line items: %w", err)
+	}
+	if err := json.Unmarshal(options, &version.Options); err != nil {
+		return nil, fmt.Errorf("failed to decode quote version options: %w", err)
+	}
+
+	return &version, nil
+}
+
+func decodeQuoteSelection(data []byte) (*domain.QuoteSelection, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var selection domain.QuoteSelection
+	if err := json.Unmarshal(data, &selection); err != nil {
+		return nil, fmt.Errorf("failed to decode approved selection: %w", err)
+	}
+	return &selection, nil
+}
+
+// quoteExecer is satisfied by both *Database and *sql.Tx
+type quoteExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func updateQuote(ctx context.Context, db quoteExecer, quote *domain.Quote) error {
+	var approvedSelection []byte
+	if quote.ApprovedSelection != nil {
+		data, err := json.Marshal(quote.ApprovedSelection)
+		if err != nil {
+			return fmt.Errorf("failed to encode approved selection: %w", err)
+		}
+		approvedSelection = data
+	}
+
+	query := `
+		UPDATE quotes SET
+			customer_id = $3, property_id = $4, quote_number = $5, title = $6, description = $7,
+			subtotal = $8, tax_rate = $9, tax_amount = $10, total_amount = $11, status = $12,
+			valid_until = $13, terms_and_conditions = $14, notes = $15, created_by = $16,
+			approved_by = $17, approved_at = $18, approved_version_id = $19, approved_selection = $20,
+			updated_at = $21
+		WHERE id = $1 AND tenant_id = $2`
+
+	result, err := db.ExecContext(ctx, query,
+		quote.ID,
+		quote.TenantID,
+		quote.CustomerID,
+		quote.PropertyID,
+		quote.QuoteNumber,
+		quote.Title,
+		quote.Description,
+		quote.Subtotal,
+		quote.TaxRate,
+		quote.TaxAmount,
+		quote.TotalAmount,
+		quote.Status,
+		quote.ValidUntil,
+		quote.TermsAndConditions,
+		quote.Notes,
+		quote.CreatedBy,
+		quote.ApprovedBy,
+		quote.ApprovedAt,
+		quote.ApprovedVersionID,
+		approvedSelection,
+		quote.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update quote: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("quote not found")
+	}
+
+	return nil
+}
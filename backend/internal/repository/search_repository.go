@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// SearchRepositoryImpl implements the global search repository interface
+type SearchRepositoryImpl struct {
+	db *Database
+}
+
+// NewSearchRepository creates a new search repository instance
+func NewSearchRepository(db *Database) services.SearchRepository {
+	return &SearchRepositoryImpl{db: db}
+}
+
+// searchSource describes how one record type is searched. Every table has the
+// generated search_vector and search_text columns added by the global search
+// migration.
+type searchSource struct {
+	table      string
+	title      string
+	subtitle   string
+	number     string
+	customerID string
+	filter     string // extra condition, such as leaving out deleted records
+}
+
+var searchSources = map[string]searchSource{
+	services.SearchTypeCustomer: {
+		table:      "customers",
+		title:      "TRIM(first_name || ' ' || last_name)",
+		subtitle:   "CONCAT_WS(' · ', NULLIF(company_name, ''), NULLIF(email, ''), NULLIF(phone, ''))",
+		number:     "''",
+		customerID: "id",
+		filter:     "status NOT IN ('deleted', 'merged')",
+	},
+	services.SearchTypeProperty: {
+		table:      "properties",
+		title:      "address_line1",
+		subtitle:   "CONCAT_WS(', ', NULLIF(name, ''), NULLIF(city, ''), NULLIF(state || ' ' || zip_code, ' '))",
+		number:     "''",
+		customerID: "customer_id",
+		filter:     "status != 'deleted'",
+	},
+	services.SearchTypeJob: {
+		table:      "jobs",
+		title:      "title",
+		subtitle:   "COALESCE(TO_CHAR(scheduled_date, 'YYYY-MM-DD'), '')",
+		number:     "COALESCE(job_number, '')",
+		customerID: "customer_id",
+	},
+	services.SearchTypeQuote: {
+		table:      "quotes",
+		title:      "title",
+		subtitle:   "quote_number",
+		number:     "quote_number",
+		customerID: "customer_id",
+	},
+	services.SearchTypeInvoice: {
+		table:      "invoices",
+		title:      "invoice_number",
+		subtitle:   "TO_CHAR(total_amount, 'FM999999990.00')",
+		number:     "invoice_number",
+		customerID: "customer_id",
+	},
+}
+
+// Search runs one ranked, tenant-scoped query per record type and combines them.
+// A record matches when every query word is a prefix of one of its words, or when
+// the query is trigram-similar to part of its search text (pg_trgm's <% operator,
+// which tolerates typos). Both conditions are served by GIN indexes.
+func (r *SearchRepositoryImpl) Search(ctx context.Context, tenantID uuid.UUID, query *services.SearchQuery) ([]*services.SearchResult, error) {
+	var branches []string
+	for _, searchType := range query.Types {
+		source, ok := searchSources[searchType]
+		if !ok {
+			return nil, fmt.Errorf("unknown search type %q", searchType)
+		}
+
+		conditions := []string{
+			"tenant_id = $1",
+			"(search_vector @@ to_tsquery('simple', $2) OR $3 <% search_text)",
+		}
+		if source.filter != "" {
+			conditions = append(conditions, source.filter)
+		}
+
+		branches = append(branches, fmt.Sprintf(`
+		(SELECT '%s', id, %s, %s, %s, status, %s,
+			ts_rank(search_vector, to_tsquery('simple', $2)) AS text_rank,
+			word_similarity($3, search_text) AS similarity
+		FROM %s
+		WHERE %s
+		ORDER BY text_rank + similarity DESC
+		LIMIT $4)`,
+			searchType, source.title, source.subtitle, source.number, source.customerID,
+			source.table, strings.Join(conditions, " AND ")))
+	}
+	if len(branches) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, strings.Join(branches, "\n\t\tUNION ALL"),
+		tenantID, query.TSQuery, query.Text, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*services.SearchResult
+	for rows.Next() {
+		result := &services.SearchResult{}
+		var customerID uuid.UUID
+		if err := rows.Scan(
+			&result.Type, &result.ID, &result.Title, &result.Subtitle, &result.Number,
+			&result.Status, &customerID, &result.TextRank, &result.Similarity,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		result.CustomerID = &customerID
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read search results: %w", err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// SiteMapRepositoryImpl implements the site map repository interface
+type SiteMapRepositoryImpl struct {
+	db *Database
+}
+
+// NewSiteMapRepository creates a new site map repository instance
+func NewSiteMapRepository(db *Database) services.SiteMapRepository {
+	return &SiteMapRepositoryImpl{db: db}
+}
+
+const propertyFeatureColumns = `
+	id, tenant_id, property_id, feature_type, name, geometry, area_sq_ft, perimeter_ft,
+	notes, created_by, created_at, updated_at`
+
+const insertPropertyFeatureQuery = `
+	INSERT INTO property_features (` + propertyFeatureColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+// CreateFeature stores a new feature
+func (r *SiteMapRepositoryImpl) CreateFeature(ctx context.Context, feature *domain.PropertyFeature) error {
+	if _, err := r.db.ExecContext(ctx, insertPropertyFeatureQuery, propertyFeatureArgs(feature)...); err != nil {
+		return fmt.Errorf("failed to create property feature: %w", err)
+	}
+	return nil
+}
+
+// GetFeature retrieves a feature by ID
+func (r *SiteMapRepositoryImpl) GetFeature(ctx context.Context, tenantID, featureID uuid.UUID) (*domain.PropertyFeature, error) {
+	query := `SELECT ` + propertyFeatureColumns + ` FROM property_features WHERE tenant_id = $1 AND id = $2`
+
+	feature, err := scanPropertyFeature(r.db.QueryRowContext(ctx, query, tenantID, featureID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get property feature: %w", err)
+	}
+
+	return feature, nil
+}
+
+// UpdateFeature saves a feature's type, name, geometry and measurements
+func (r *SiteMapRepositoryImpl) UpdateFeature(ctx context.Context, feature *domain.PropertyFeature) error {
+	query := `
+		UPDATE property_features SET
+			feature_type = $3,
+			name = $4,
+			geometry = $5,
+			area_sq_ft = $6,
+			perimeter_ft = $7,
+			notes = $8,
+			updated_at = $9
+		WHERE tenant_id = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		feature.TenantID,
+		feature.ID,
+		feature.FeatureType,
+		feature.Name,
+		[]byte(feature.Geometry),
+		feature.AreaSqFt,
+		feature.PerimeterFt,
+		feature.Notes,
+		feature.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update property feature: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("property feature not found")
+	}
+
+	return nil
+}
+
+// DeleteFeature deletes a feature
+func (r *SiteMapRepositoryImpl) DeleteFeature(ctx context.Context, tenantID, featureID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM property_features WHERE tenant_id = $1 AND id = $2`, tenantID, featureID)
+	if err != nil {
+		return fmt.Errorf("failed to delete property feature: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("property feature not found")
+	}
+
+	return nil
+}
+
+// ListFeatures lists a property's features, grouped by type
+func (r *SiteMapRepositoryImpl) ListFeatures(ctx context.Context, tenantID, propertyID uuid.UUID) ([]*domain.PropertyFeature, error) {
+	query := `
+		SELECT ` + propertyFeatureColumns + `
+		FROM property_features
+		WHERE tenant_id = $1 AND property_id = $2
+		ORDER BY feature_type, created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list property features: %w", err)
+	}
+	defer rows.Close()
+
+	features := []*domain.PropertyFeature{}
+	for rows.Next() {
+		feature, err := scanPropertyFeature(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan property feature: %w", err)
+		}
+		features = append(features, feature)
+	}
+
+	return features, rows.Err()
+}
+
+// ReplaceFeatures deletes a property's features and stores the new set
+func (r *SiteMapRepositoryImpl) ReplaceFeatures(ctx context.Context, tenantID, propertyID uuid.UUID, features []*domain.PropertyFeature) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM property_features WHERE tenant_id = $1 AND property_id = $2`, tenantID, propertyID); err != nil {
+		return fmt.Errorf("failed to clear property features: %w", err)
+	}
+
+	for _, feature := range features {
+		if _, err := tx.ExecContext(ctx, insertPropertyFeatureQuery, propertyFeatureArgs(feature)...); err != nil {
+			return fmt.Errorf("failed to create property feature: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetMeasuredAreas totals a property's feature areas by type
+func (r *SiteMapRepositoryImpl) GetMeasuredAreas(ctx context.Context, tenantID, propertyID uuid.UUID) (map[string]float64, error) {
+	query := `
+		SELECT feature_type, SUM(area_sq_ft)
+		FROM property_features
+		WHERE tenant_id = $1 AND property_id = $2
+		GROUP BY feature_type`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get measured areas: %w", err)
+	}
+	defer rows.Close()
+
+	areas := make(map[string]float64)
+	for rows.Next() {
+		var featureType string
+		var area float64
+		if err := rows.Scan(&featureType, &area); err != nil {
+			return nil, fmt.Errorf("failed to scan measured area: %w", err)
+		}
+		areas[featureType] = area
+	}
+
+	return areas, rows.Err()
+}
+
+func propertyFeatureArgs(feature *domain.PropertyFeature) []interface{} {
+	return []interface{}{
+		feature.ID,
+		feature.TenantID,
+		feature.PropertyID,
+		feature.FeatureType,
+		feature.Name,
+		[]byte(feature.Geometry),
+		feature.AreaSqFt,
+		feature.PerimeterFt,
+		feature.Notes,
+		feature.CreatedBy,
+		feature.CreatedAt,
+		feature.UpdatedAt,
+	}
+}
+
+func scanPropertyFeature(row rowScanner) (*domain.PropertyFeature, error) {
+	var feature domain.PropertyFeature
+	var geometry []byte
+	if err := row.Scan(
+		&feature.ID,
+		&feature.TenantID,
+		&feature.PropertyID,
+		&feature.FeatureType,
+		&feature.Name,
+		&geometry,
+		&feature.AreaSqFt,
+		&feature.PerimeterFt,
+		&feature.Notes,
+		&feature.CreatedBy,
+		&feature.CreatedAt,
+		&feature.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	feature.Geometry = geometry
+	return &feature, nil
+}
@@ -0,0 +1,590 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// StatementRepositoryImpl implements the statement repository interface
+type StatementRepositoryImpl struct {
+	db *Database
+}
+
+// NewStatementRepository creates a new statement repository instance
+func NewStatementRepository(db *Database) services.StatementRepository {
+	return &StatementRepositoryImpl{db: db}
+}
+
+// openInvoiceCondition matches invoices that have been issued and still carry a balance
+const openInvoiceCondition = `i.status NOT IN ('draft', 'paid', 'cancelled', 'deleted')`
+
+// CreateRun creates a new statement run
+func (r *StatementRepositoryImpl) CreateRun(ctx context.Context, run *domain.StatementRun) error {
+	query := `
+		INSERT INTO statement_runs (
+			id, tenant_id, period_start, period_end, group_by, status,
+			customers_processed, invoices_created, statements_sent, total_amount,
+			error_message, triggered_by, started_at, completed_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		run.ID,
+		run.TenantID,
+		run.PeriodStart,
+		run.PeriodEnd,
+		run.GroupBy,
+		run.Status,
+		run.CustomersProcessed,
+		run.InvoicesCreated,
+		run.StatementsSent,
+		run.TotalAmount,
+		run.ErrorMessage,
+		run.TriggeredBy,
+		run.StartedAt,
+		run.CompletedAt,
+		run.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create statement run: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateRun updates the progress and outcome of a statement run
+func (r *StatementRepositoryImpl) UpdateRun(ctx context.Context, run *domain.StatementRun) error {
+	query := `
+		UPDATE statement_runs SET
+			status = $3,
+			customers_processed = $4,
+			invoices_created = $5,
+			statements_sent = $6,
+			total_amount = $7,
+			error_message = $8,
+			completed_at = $9
+		WHERE id = $1 AND tenant_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		run.ID,
+		run.TenantID,
+		run.Status,
+		run.CustomersProcessed,
+		run.InvoicesCreated,
+		run.StatementsSent,
+		run.TotalAmount,
+		run.ErrorMessage,
+		run.CompletedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update statement run: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("statement run not found or not authorized")
+	}
+
+	return nil
+}
+
+// GetRun retrieves a statement run by ID
+func (r *StatementRepositoryImpl) GetRun(ctx context.Context, tenantID, runID uuid.UUID) (*domain.StatementRun, error) {
+	query := `
+		SELECT id, tenant_id, period_start, period_end, group_by, status,
+			   customers_processed, invoices_created, statements_sent, total_amount,
+			   error_message, triggered_by, started_at, completed_at, created_at
+		FROM statement_runs
+		WHERE id = $1 AND tenant_id = $2`
+
+	run, err := scanStatementRun(r.db.QueryRowContext(ctx, query, runID, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get statement run: %w", err)
+	}
+
+	return run, nil
+}
+
+// ListRuns lists statement runs with filtering and pagination
+func (r *StatementRepositoryImpl) ListRuns(ctx context.Context, tenantID uuid.UUID, filter *services.StatementRunFilter) ([]*domain.StatementRun, int64, error) {
+	whereClause := " FROM statement_runs WHERE tenant_id = $1"
+	args := []interface{}{tenantID}
+	argIndex := 2
+
+	if filter.Status != "" {
+		whereClause += fmt.Sprintf(" AND status = $%d", argIndex)
+		args = append(args, filter.Status)
+		argIndex++
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*)"+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count statement runs: %w", err)
+	}
+
+	query := `
+		SELECT id, tenant_id, period_start, period_end, group_by, status,
+			   customers_processed, invoices_created, statements_sent, total_amount,
+			   error_message, triggered_by, started_at, completed_at, created_at` +
+		whereClause +
+		fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list statement runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*domain.StatementRun
+	for rows.Next() {
+		run, err := scanStatementRun(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan statement run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate statement runs: %w", err)
+	}
+
+	return runs, total, nil
+}
+
+// GetUninvoicedJobs returns completed jobs in the period that are not on any active invoice
+func (r *StatementRepositoryImpl) GetUninvoicedJobs(ctx context.Context, tenantID uuid.UUID, periodStart, periodEnd time.Time, customerIDs []uuid.UUID) ([]*services.UninvoicedJob, error) {
+	query := `
+		SELECT j.id, j.customer_id, j.property_id, j.job_number, j.title, j.po_number,
+			   p.name, CONCAT_WS(', ', p.address_line1, p.city, p.state),
+			   j.actual_end_time,
+			   COALESCE(NULLIF((SELECT SUM(js.total_price) FROM job_services js WHERE js.job_id = j.id), 0), j.total_amount, 0)
+		FROM jobs j
+		JOIN properties p ON p.id = j.property_id
+		WHERE j.tenant_id = $1
+		  AND j.status = 'completed'
+		  AND COALESCE(j.actual_end_time, j.updated_at) BETWEEN $2 AND $3
+		  AND NOT EXISTS (
+			  SELECT 1 FROM invoices i
+			  WHERE i.job_id = j.id AND i.status NOT IN ('cancelled', 'deleted')
+		  )
+		  AND NOT EXISTS (
+			  SELECT 1 FROM invoice_jobs ij
+			  JOIN invoices i ON i.id = ij.invoice_id
+			  WHERE ij.job_id = j.id AND i.status NOT IN ('cancelled', 'deleted')
+		  )`
+
+	args := []interface{}{tenantID, periodStart, periodEnd}
+	if len(customerIDs) > 0 {
+		query += " AND j.customer_id = ANY($4)"
+		args = append(args, pq.Array(customerIDs))
+	}
+	query += " ORDER BY j.customer_id, p.name, j.actual_end_time"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get uninvoiced jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*services.UninvoicedJob
+	for rows.Next() {
+		var job services.UninvoicedJob
+		if err := rows.Scan(
+			&job.JobID,
+			&job.CustomerID,
+			&job.PropertyID,
+			&job.JobNumber,
+			&job.Title,
+			&job.PONumber,
+			&job.PropertyName,
+			&job.PropertyAddress,
+			&job.CompletedAt,
+			&job.Amount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan uninvoiced job: %w", err)
+		}
+		jobs = append(jobs, &job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate uninvoiced jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// CreateConsolidatedInvoice stores an invoice with its job links and line
+// items and attaches it to the statement run in one transaction, so a failure
+// leaves none of its jobs billed
+func (r *StatementRepositoryImpl) CreateConsolidatedInvoice(ctx context.Context, invoice *domain.Invoice, invoiceJobs []*domain.InvoiceJob, lineItems []*services.InvoiceLineItem, runID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertInvoice(ctx, tx, invoice); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO invoice_jobs (
+			id, tenant_id, invoice_id, job_id, property_id, po_number, amount, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	for _, invoiceJob := range invoiceJobs {
+		if _, err := tx.ExecContext(ctx, query,
+			invoiceJob.ID,
+			invoiceJob.TenantID,
+			invoiceJob.InvoiceID,
+			invoiceJob.JobID,
+			invoiceJob.PropertyID,
+			invoiceJob.PONumber,
+			invoiceJob.Amount,
+			invoiceJob.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to link job %s to invoice: %w", invoiceJob.JobID, err)
+		}
+	}
+
+	for _, lineItem := range lineItems {
+		if err := insertInvoiceService(ctx, tx, lineItem); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE invoices SET statement_run_id = $3 WHERE id = $1 AND tenant_id = $2`, invoice.ID, invoice.TenantID, runID); err != nil {
+		return fmt.Errorf("failed to set invoice statement run: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetInvoiceJobs retrieves the jobs billed on an invoice
+func (r *StatementRepositoryImpl) GetInvoiceJobs(ctx context.Context, tenantID, invoiceID uuid.UUID) ([]*domain.InvoiceJob, error) {
+	query := `
+		SELECT id, tenant_id, invoice_id, job_id, property_id, po_number, amount, created_at
+		FROM invoice_jobs
+		WHERE tenant_id = $1 AND invoice_id = $2
+		ORDER BY created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var invoiceJobs []*domain.InvoiceJob
+	for rows.Next() {
+		var invoiceJob domain.InvoiceJob
+		if err := rows.Scan(
+			&invoiceJob.ID,
+			&invoiceJob.TenantID,
+			&invoiceJob.InvoiceID,
+			&invoiceJob.JobID,
+			&invoiceJob.PropertyID,
+			&invoiceJob.PONumber,
+			&invoiceJob.Amount,
+			&invoiceJob.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice job: %w", err)
+		}
+		invoiceJobs = append(invoiceJobs, &invoiceJob)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate invoice jobs: %w", err)
+	}
+
+	return invoiceJobs, nil
+}
+
+// GetInvoicePropertySubtotals sums the jobs on an invoice per property
+func (r *StatementRepositoryImpl) GetInvoicePropertySubtotals(ctx context.Context, tenantID, invoiceID uuid.UUID) ([]*services.PropertySubtotal, error) {
+	query := `
+		SELECT ij.property_id, p.name, CONCAT_WS(', ', p.address_line1, p.city, p.state),
+			   COUNT(*), SUM(ij.amount)
+		FROM invoice_jobs ij
+		JOIN properties p ON p.id = ij.property_id
+		WHERE ij.tenant_id = $1 AND ij.invoice_id = $2
+		GROUP BY ij.property_id, p.name, p.address_line1, p.city, p.state
+		ORDER BY p.name`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get property subtotals: %w", err)
+	}
+	defer rows.Close()
+
+	var subtotals []*services.PropertySubtotal
+	for rows.Next() {
+		var subtotal services.PropertySubtotal
+		if err := rows.Scan(
+			&subtotal.PropertyID,
+			&subtotal.PropertyName,
+			&subtotal.PropertyAddress,
+			&subtotal.JobCount,
+			&subtotal.Subtotal,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan property subtotal: %w", err)
+		}
+		subtotals = append(subtotals, &subtotal)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate property subtotals: %w", err)
+	}
+
+	return subtotals, nil
+}
+
+// GetOpenInvoices retrieves issued, unpaid invoices with the amount paid to date
+func (r *StatementRepositoryImpl) GetOpenInvoices(ctx context.Context, tenantID uuid.UUID, customerID *uuid.UUID) ([]*services.OpenInvoice, error) {
+	query := `
+		SELECT i.id, i.tenant_id, i.customer_id, i.job_id, i.invoice_number, i.status,
+			   i.subtotal, i.tax_rate, i.tax_amount, i.total_amount, i.issued_date, i.due_date,
+			   i.paid_date, i.notes, i.created_at, i.updated_at,
+			   COALESCE((SELECT SUM(p.amount) FROM payments p WHERE p.invoice_id = i.id AND p.status = 'completed'), 0)
+		FROM invoices i
+		WHERE i.tenant_id = $1 AND ` + openInvoiceCondition
+
+	args := []interface{}{tenantID}
+	if customerID != nil {
+		query += " AND i.customer_id = $2"
+		args = append(args, *customerID)
+	}
+	query += " ORDER BY i.due_date ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open invoices: %w", err)
+	}
+	defer rows.Close()
+
+	var openInvoices []*services.OpenInvoice
+	for rows.Next() {
+		var invoice domain.Invoice
+		var amountPaid float64
+		if err := rows.Scan(
+			&invoice.ID,
+			&invoice.TenantID,
+			&invoice.CustomerID,
+			&invoice.JobID,
+			&invoice.InvoiceNumber,
+			&invoice.Status,
+			&invoice.Subtotal,
+			&invoice.TaxRate,
+			&invoice.TaxAmount,
+			&invoice.TotalAmount,
+			&invoice.IssuedDate,
+			&invoice.DueDate,
+			&invoice.PaidDate,
+			&invoice.Notes,
+			&invoice.CreatedAt,
+			&invoice.UpdatedAt,
+			&amountPaid,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan open invoice: %w", err)
+		}
+		openInvoices = append(openInvoices, &services.OpenInvoice{
+			Invoice:    &invoice,
+			AmountPaid: amountPaid,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate open invoices: %w", err)
+	}
+
+	return openInvoices, nil
+}
+
+// GetCustomersWithOpenBalances returns the customers that have at least one open invoice
+func (r *StatementRepositoryImpl) GetCustomersWithOpenBalances(ctx context.Context, tenantID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT i.customer_id
+		FROM invoices i
+		WHERE i.tenant_id = $1 AND ` + openInvoiceCondition
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customers with open balances: %w", err)
+	}
+	defer rows.Close()
+
+	var customerIDs []uuid.UUID
+	for rows.Next() {
+		var customerID uuid.UUID
+		if err := rows.Scan(&customerID); err != nil {
+			return nil, fmt.Errorf("failed to scan customer ID: %w", err)
+		}
+		customerIDs = append(customerIDs, customerID)
+	}
+
+	return customerIDs, rows.Err()
+}
+
+// GetSchedule retrieves a tenant's statement schedule
+func (r *StatementRepositoryImpl) GetSchedule(ctx context.Context, tenantID uuid.UUID) (*domain.StatementSchedule, error) {
+	query := `
+		SELECT id, tenant_id, enabled, day_of_month, group_by, auto_send_invoices,
+			   send_aging_statements, payment_terms_days, last_run_at, next_run_at,
+			   created_at, updated_at
+		FROM statement_schedules
+		WHERE tenant_id = $1`
+
+	schedule, err := scanStatementSchedule(r.db.QueryRowContext(ctx, query, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get statement schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// UpsertSchedule creates or updates a tenant's statement schedule
+func (r *StatementRepositoryImpl) UpsertSchedule(ctx context.Context, schedule *domain.StatementSchedule) error {
+	query := `
+		INSERT INTO statement_schedules (
+			id, tenant_id, enabled, day_of_month, group_by, auto_send_invoices,
+			send_aging_statements, payment_terms_days, last_run_at, next_run_at,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			day_of_month = EXCLUDED.day_of_month,
+			group_by = EXCLUDED.group_by,
+			auto_send_invoices = EXCLUDED.auto_send_invoices,
+			send_aging_statements = EXCLUDED.send_aging_statements,
+			payment_terms_days = EXCLUDED.payment_terms_days,
+			last_run_at = EXCLUDED.last_run_at,
+			next_run_at = EXCLUDED.next_run_at,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.ExecContext(ctx, query,
+		schedule.ID,
+		schedule.TenantID,
+		schedule.Enabled,
+		schedule.DayOfMonth,
+		schedule.GroupBy,
+		schedule.AutoSendInvoices,
+		schedule.SendAgingStatements,
+		schedule.PaymentTermsDays,
+		schedule.LastRunAt,
+		schedule.NextRunAt,
+		schedule.CreatedAt,
+		schedule.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to save statement schedule: %w", err)
+	}
+
+	return nil
+}
+
+// GetDueSchedules retrieves enabled schedules across all tenants that are due to run
+func (r *StatementRepositoryImpl) GetDueSchedules(ctx context.Context, asOf time.Time) ([]*domain.StatementSchedule, error) {
+	query := `
+		SELECT id, tenant_id, enabled, day_of_month, group_by, auto_send_invoices,
+			   send_aging_statements, payment_terms_days, last_run_at, next_run_at,
+			   created_at, updated_at
+		FROM statement_schedules
+		WHERE enabled = TRUE AND next_run_at <= $1
+		ORDER BY next_run_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due statement schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []*domain.StatementSchedule
+	for rows.Next() {
+		schedule, err := scanStatementSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan statement schedule: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate statement schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanStatementRun(row rowScanner) (*domain.StatementRun, error) {
+	var run domain.StatementRun
+	err := row.Scan(
+		&run.ID,
+		&run.TenantID,
+		&run.PeriodStart,
+		&run.PeriodEnd,
+		&run.GroupBy,
+		&run.Status,
+		&run.CustomersProcessed,
+		&run.InvoicesCreated,
+		&run.StatementsSent,
+		&run.TotalAmount,
+		&run.ErrorMessage,
+		&run.TriggeredBy,
+		&run.StartedAt,
+		&run.CompletedAt,
+		&run.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func scanStatementSchedule(row rowScanner) (*domain.StatementSchedule, error) {
+	var schedule domain.StatementSchedule
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.TenantID,
+		&schedule.Enabled,
+		&schedule.DayOfMonth,
+		&schedule.GroupBy,
+		&schedule.AutoSendInvoices,
+		&schedule.SendAgingStatements,
+		&schedule.PaymentTermsDays,
+		&schedule.LastRunAt,
+		&schedule.NextRunAt,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
@@ -0,0 +1,339 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// QuickBooks Online API hosts
+const (
+	QuickBooksProductionURL = "https://quickbooks.api.intuit.com"
+	QuickBooksSandboxURL    = "https://sandbox-quickbooks.api.intuit.com"
+	QuickBooksTokenURL      = "https://oauth.platform.intuit.com/oauth2/v1/tokens/bearer"
+
+	// quickBooksMinorVersion pins the API's response shape
+	quickBooksMinorVersion = "65"
+)
+
+// QuickBooksSyncAdapter pushes records to the QuickBooks Online accounting API. The
+// connection's external tenant ID is the company's realm ID. Account mappings synced to
+// QuickBooks use the QuickBooks account ID as their account code.
+type QuickBooksSyncAdapter struct {
+	baseURL      string
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+}
+
+// NewQuickBooksSyncAdapter creates a QuickBooks Online adapter. The client ID and secret
+// are the app's OAuth credentials, used to refresh expired access tokens.
+func NewQuickBooksSyncAdapter(baseURL, tokenURL, clientID, clientSecret string, httpClient *http.Client) *QuickBooksSyncAdapter {
+	if baseURL == "" {
+		baseURL = QuickBooksProductionURL
+	}
+	if tokenURL == "" {
+		tokenURL = QuickBooksTokenURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &QuickBooksSyncAdapter{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		httpClient:   httpClient,
+	}
+}
+
+// Provider returns the provider this adapter syncs to
+func (a *QuickBooksSyncAdapter) Provider() string {
+	return domain.AccountingProviderQuickBooks
+}
+
+// PushCustomer creates or updates a QuickBooks customer. QuickBooks books receivables
+// on the invoice, so the receivable account is sent with each invoice instead.
+func (a *QuickBooksSyncAdapter) PushCustomer(ctx context.Context, conn *domain.AccountingConnection, customer *domain.EnhancedCustomer, receivable AccountRef, externalID *string) (string, error) {
+	body := map[string]interface{}{
+		"DisplayName": customerDisplayName(customer),
+		"GivenName":   customer.FirstName,
+		"FamilyName":  customer.LastName,
+	}
+	if customer.CompanyName != nil {
+		body["CompanyName"] = *customer.CompanyName
+	}
+	if customer.Email != nil {
+		body["PrimaryEmailAddr"] = map[string]string{"Address": *customer.Email}
+	}
+	if customer.Phone != nil {
+		body["PrimaryPhone"] = map[string]string{"FreeFormNumber": *customer.Phone}
+	}
+	if customer.AddressLine1 != nil {
+		body["BillAddr"] = map[string]string{
+			"Line1":                  stringValue(customer.AddressLine1),
+			"Line2":                  stringValue(customer.AddressLine2),
+			"City":                   stringValue(customer.City),
+			"CountrySubDivisionCode": stringValue(customer.State),
+			"PostalCode":             stringValue(customer.ZipCode),
+		}
+	}
+
+	return a.push(ctx, conn, "Customer", body, externalID)
+}
+
+// PushItem creates or updates a QuickBooks service item
+func (a *QuickBooksSyncAdapter) PushItem(ctx context.Context, conn *domain.AccountingConnection, service *domain.Service, income AccountRef, externalID *string) (string, error) {
+	body := map[string]interface{}{
+		"Name":             service.Name,
+		"Type":             "Service",
+		"IncomeAccountRef": quickBooksRef(income.Code, income.Name),
+		"Active":           service.Status != "inactive",
+	}
+	if service.Description != nil {
+		body["Description"] = *service.Description
+	}
+	if service.BasePrice != nil {
+		body["UnitPrice"] = roundCents(*service.BasePrice)
+	}
+
+	return a.push(ctx, conn, "Item", body, externalID)
+}
+
+// PushInvoice creates or updates a QuickBooks invoice
+func (a *QuickBooksSyncAdapter) PushInvoice(ctx context.Context, conn *domain.AccountingConnection, invoice *AccountingInvoice, externalID *string) (string, error) {
+	if invoice.ExternalCustomerID == nil {
+		return "", fmt.Errorf("invoice customer has not been synced")
+	}
+
+	lines := make([]map[string]interface{}, 0, len(invoice.Lines))
+	for _, line := range invoice.Lines {
+		detail := map[string]interface{}{
+			"Qty":       line.Quantity,
+			"UnitPrice": roundCents(line.UnitPrice),
+		}
+		if line.ExternalItemID != nil {
+			detail["ItemRef"] = quickBooksRef(*line.ExternalItemID, line.ItemName)
+		}
+		lines = append(lines, map[string]interface{}{
+			"DetailType":          "SalesItemLineDetail",
+			"Amount":              roundCents(line.Amount),
+			"Description":         line.Description,
+			"SalesItemLineDetail": detail,
+		})
+	}
+
+	body := map[string]interface{}{
+		"CustomerRef":  quickBooksRef(*invoice.ExternalCustomerID, invoice.CustomerName),
+		"DocNumber":    invoice.Invoice.InvoiceNumber,
+		"TxnDate":      invoiceDate(invoice.Invoice).Format("2006-01-02"),
+		"Line":         lines,
+		"ARAccountRef": quickBooksRef(invoice.Receivable.Code, invoice.Receivable.Name),
+		"TxnTaxDetail": map[string]interface{}{"TotalTax": roundCents(invoice.Invoice.TaxAmount)},
+	}
+	if invoice.Invoice.DueDate != nil {
+		body["DueDate"] = invoice.Invoice.DueDate.Format("2006-01-02")
+	}
+	if invoice.Invoice.Notes != nil {
+		body["CustomerMemo"] = map[string]string{"value": *invoice.Invoice.Notes}
+	}
+
+	return a.push(ctx, conn, "Invoice", body, externalID)
+}
+
+// PushPayment creates or updates a QuickBooks payment applied to its invoice
+func (a *QuickBooksSyncAdapter) PushPayment(ctx context.Context, conn *domain.AccountingConnection, payment *AccountingPayment, externalID *string) (string, error) {
+	if payment.ExternalCustomerID == nil || payment.ExternalInvoiceID == nil {
+		return "", fmt.Errorf("payment invoice has not been synced")
+	}
+
+	amount := roundCents(payment.Payment.Amount)
+	body := map[string]interface{}{
+		"CustomerRef":         quickBooksRef(*payment.ExternalCustomerID, payment.CustomerName),
+		"TotalAmt":            amount,
+		"TxnDate":             paymentDate(payment.Payment).Format("2006-01-02"),
+		"DepositToAccountRef": quickBooksRef(payment.Deposit.Code, payment.Deposit.Name),
+		"Line": []map[string]interface{}{{
+			"Amount":    amount,
+			"LinkedTxn": []map[string]string{{"TxnId": *payment.ExternalInvoiceID, "TxnType": "Invoice"}},
+		}},
+	}
+	if payment.Payment.GatewayTransactionID != nil {
+		body["PaymentRefNum"] = *payment.Payment.GatewayTransactionID
+	}
+
+	return a.push(ctx, conn, "Payment", body, externalID)
+}
+
+// push creates the entity, or sparse-updates it at its current sync token when it has
+// been pushed before, and returns its QuickBooks ID
+func (a *QuickBooksSyncAdapter) push(ctx context.Context, conn *domain.AccountingConnection, entity string, body map[string]interface{}, externalID *string) (string, error) {
+	if conn.ExternalTenantID == nil || *conn.ExternalTenantID == "" {
+		return "", fmt.Errorf("quickbooks connection has no realm ID")
+	}
+	if err := a.refreshToken(ctx, conn); err != nil {
+		return "", err
+	}
+
+	path := "/" + strings.ToLower(entity)
+	if externalID != nil {
+		current, err := a.do(ctx, conn, http.MethodGet, path+"/"+url.PathEscape(*externalID), nil, entity)
+		if err != nil {
+			return "", err
+		}
+		body["Id"] = current.ID
+		body["SyncToken"] = current.SyncToken
+		body["sparse"] = true
+	}
+
+	saved, err := a.do(ctx, conn, http.MethodPost, path, body, entity)
+	if err != nil {
+		return "", err
+	}
+	return saved.ID, nil
+}
+
+type quickBooksEntity struct {
+	ID        string `json:"Id"`
+	SyncToken string `json:"SyncToken"`
+}
+
+type quickBooksFault struct {
+	Fault struct {
+		Error []struct {
+			Message string `json:"Message"`
+			Detail  string `json:"Detail"`
+		} `json:"Error"`
+	} `json:"Fault"`
+}
+
+// do calls the company's API and decodes the entity from the response
+func (a *QuickBooksSyncAdapter) do(ctx context.Context, conn *domain.AccountingConnection, method, path string, body interface{}, entity string) (*quickBooksEntity, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode quickbooks %s: %w", strings.ToLower(entity), err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	endpoint := fmt.Sprintf("%s/v3/company/%s%s?minorversion=%s", a.baseURL, url.PathEscape(*conn.ExternalTenantID), path, quickBooksMinorVersion)
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create quickbooks request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+stringValue(conn.AccessToken))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("quickbooks request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quickbooks response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, quickBooksError(resp.StatusCode, data)
+	}
+
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode quickbooks response: %w", err)
+	}
+	var result quickBooksEntity
+	if raw, ok := decoded[entity]; ok {
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode quickbooks %s: %w", strings.ToLower(entity), err)
+		}
+	}
+	if result.ID == "" {
+		return nil, fmt.Errorf("quickbooks response has no %s ID", strings.ToLower(entity))
+	}
+
+	return &result, nil
+}
+
+// refreshToken renews the connection's access token when it has expired. The caller
+// saves the connection after the sync, keeping the new tokens.
+func (a *QuickBooksSyncAdapter) refreshToken(ctx context.Context, conn *domain.AccountingConnection) error {
+	if conn.TokenExpiresAt == nil || time.Now().Add(time.Minute).Before(*conn.TokenExpiresAt) {
+		return nil
+	}
+	if conn.RefreshToken == nil || a.clientID == "" {
+		return fmt.Errorf("quickbooks access token has expired")
+	}
+
+	form := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {*conn.RefreshToken}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.SetBasicAuth(a.clientID, a.clientSecret)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("quickbooks token refresh failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var token struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("quickbooks token refresh failed with status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("failed to decode quickbooks token: %w", err)
+	}
+
+	expiresAt := time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	conn.AccessToken = &token.AccessToken
+	if token.RefreshToken != "" {
+		conn.RefreshToken = &token.RefreshToken
+	}
+	conn.TokenExpiresAt = &expiresAt
+
+	return nil
+}
+
+func quickBooksError(status int, body []byte) error {
+	var fault quickBooksFault
+	if err := json.Unmarshal(body, &fault); err == nil && len(fault.Fault.Error) > 0 {
+		detail := fault.Fault.Error[0]
+		if detail.Detail != "" {
+			return fmt.Errorf("quickbooks error (status %d): %s: %s", status, detail.Message, detail.Detail)
+		}
+		return fmt.Errorf("quickbooks error (status %d): %s", status, detail.Message)
+	}
+	return fmt.Errorf("quickbooks error (status %d)", status)
+}
+
+// quickBooksRef is a QuickBooks reference to another record by ID
+func quickBooksRef(id, name string) map[string]string {
+	ref := map[string]string{"value": id}
+	if name != "" {
+		ref["name"] = name
+	}
+	return ref
+}
@@ -0,0 +1,1511 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// AccountingService maps billing records to a chart of accounts, exports them in accounting
+// file formats and keeps connected accounting providers in sync
+type AccountingService interface {
+	// Chart-of-accounts mapping
+	ListAccountMappings(ctx context.Context) ([]*domain.AccountMapping, error)
+	SaveAccountMapping(ctx context.Context, req *AccountMappingRequest) (*domain.AccountMapping, error)
+	DeleteAccountMapping(ctx context.Context, mappingID uuid.UUID) error
+
+	// File exports
+	ExportAccountingData(ctx context.Context, req *AccountingExportRequest) (*AccountingExport, error)
+
+	// Provider connections and sync
+	ListConnections(ctx context.Context) ([]*domain.AccountingConnection, error)
+	ConnectProvider(ctx context.Context, req *AccountingConnectionRequest) (*domain.AccountingConnection, error)
+	DisconnectProvider(ctx context.Context, provider string) error
+	SyncProvider(ctx context.Context, provider string) (*AccountingSyncResult, error)
+	ListSyncRecords(ctx context.Context, filter *AccountingSyncRecordFilter) (*domain.PaginatedResponse, error)
+	ProcessAccountingSync(ctx context.Context, now time.Time) error
+}
+
+// AccountingRepository defines data access for accounting integration
+type AccountingRepository interface {
+	// Account mappings
+	ListMappings(ctx context.Context, tenantID uuid.UUID) ([]*domain.AccountMapping, error)
+	GetMapping(ctx context.Context, tenantID, mappingID uuid.UUID) (*domain.AccountMapping, error)
+	UpsertMapping(ctx context.Context, mapping *domain.AccountMapping) error
+	DeleteMapping(ctx context.Context, tenantID, mappingID uuid.UUID) error
+
+	// Export data
+	GetInvoicesForPeriod(ctx context.Context, tenantID uuid.UUID, start, end time.Time) ([]*domain.Invoice, error)
+	GetPaymentsForPeriod(ctx context.Context, tenantID uuid.UUID, start, end time.Time) ([]*domain.Payment, error)
+
+	// Connections
+	GetConnection(ctx context.Context, tenantID uuid.UUID, provider string) (*domain.AccountingConnection, error)
+	ListConnections(ctx context.Context, tenantID uuid.UUID) ([]*domain.AccountingConnection, error)
+	ListAutoSyncConnections(ctx context.Context) ([]*domain.AccountingConnection, error)
+	UpsertConnection(ctx context.Context, connection *domain.AccountingConnection) error
+
+	// Change tracking
+	GetPendingChanges(ctx context.Context, tenantID uuid.UUID, provider, entityType string, maxAttempts, limit int) ([]uuid.UUID, error)
+	GetSyncRecord(ctx context.Context, tenantID uuid.UUID, provider, entityType string, entityID uuid.UUID) (*domain.AccountingSyncRecord, error)
+	UpsertSyncRecord(ctx context.Context, record *domain.AccountingSyncRecord) error
+	ListSyncRecords(ctx context.Context, tenantID uuid.UUID, filter *AccountingSyncRecordFilter) ([]*domain.AccountingSyncRecord, int64, error)
+}
+
+// AccountingSyncAdapter pushes records to an accounting provider's API. A nil externalID
+// means the record has not been pushed before and should be created; otherwise the existing
+// provider record is updated. Adapters return the provider's ID for the record.
+type AccountingSyncAdapter interface {
+	Provider() string
+	PushCustomer(ctx context.Context, conn *domain.AccountingConnection, customer *domain.EnhancedCustomer, receivable AccountRef, externalID *string) (string, error)
+	PushItem(ctx context.Context, conn *domain.AccountingConnection, service *domain.Service, income AccountRef, externalID *string) (string, error)
+	PushInvoice(ctx context.Context, conn *domain.AccountingConnection, invoice *AccountingInvoice, externalID *string) (string, error)
+	PushPayment(ctx context.Context, conn *domain.AccountingConnection, payment *AccountingPayment, externalID *string) (string, error)
+}
+
+// AccountRef identifies an account in the tenant's chart of accounts
+type AccountRef struct {
+	Code string `json:"code,omitempty"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// AccountingInvoice is an invoice resolved against the chart of accounts
+type AccountingInvoice struct {
+	Invoice            *domain.Invoice          `json:"invoice"`
+	Customer           *domain.EnhancedCustomer `json:"customer"`
+	CustomerName       string                   `json:"customer_name"`
+	Receivable         AccountRef               `json:"receivable"`
+	Lines              []*AccountingLine        `json:"lines"`
+	TaxAccount         AccountRef               `json:"tax_account"`
+	ExternalCustomerID *string                  `json:"external_customer_id,omitempty"`
+}
+
+// AccountingLine is an income line of an invoice
+type AccountingLine struct {
+	ServiceID      *uuid.UUID `json:"service_id,omitempty"`
+	ItemName       string     `json:"item_name"`
+	Description    string     `json:"description"`
+	Quantity       float64    `json:"quantity"`
+	UnitPrice      float64    `json:"unit_price"`
+	Amount         float64    `json:"amount"`
+	Account        AccountRef `json:"account"`
+	ExternalItemID *string    `json:"external_item_id,omitempty"`
+}
+
+// AccountingPayment is a payment resolved against the chart of accounts
+type AccountingPayment struct {
+	Payment            *domain.Payment `json:"payment"`
+	InvoiceNumber      string          `json:"invoice_number"`
+	CustomerName       string          `json:"customer_name"`
+	Receivable         AccountRef      `json:"receivable"`
+	Deposit            AccountRef      `json:"deposit"`
+	ExternalCustomerID *string         `json:"external_customer_id,omitempty"`
+	ExternalInvoiceID  *string         `json:"external_invoice_id,omitempty"`
+}
+
+// AccountMappingRequest creates or updates a chart-of-accounts mapping
+type AccountMappingRequest struct {
+	MappingType string  `json:"mapping_type" validate:"required"`
+	MappingKey  string  `json:"mapping_key,omitempty"`
+	AccountCode *string `json:"account_code,omitempty"`
+	AccountName string  `json:"account_name" validate:"required"`
+	AccountType string  `json:"account_type,omitempty"`
+}
+
+// AccountingExportRequest selects the format and period of an export
+type AccountingExportRequest struct {
+	Format    string    `json:"format" validate:"required"`
+	StartDate time.Time `json:"start_date" validate:"required"`
+	EndDate   time.Time `json:"end_date" validate:"required"`
+}
+
+// AccountingExport is a generated accounting file
+type AccountingExport struct {
+	Format       string `json:"format"`
+	Filename     string `json:"filename"`
+	ContentType  string `json:"content_type"`
+	Data         []byte `json:"-"`
+	InvoiceCount int    `json:"invoice_count"`
+	PaymentCount int    `json:"payment_count"`
+}
+
+// AccountingConnectionRequest connects a tenant to an accounting provider
+type AccountingConnectionRequest struct {
+	Provider         string     `json:"provider" validate:"required"`
+	ExternalTenantID *string    `json:"external_tenant_id,omitempty"`
+	AccessToken      string     `json:"access_token" validate:"required"`
+	RefreshToken     *string    `json:"refresh_token,omitempty"`
+	TokenExpiresAt   *time.Time `json:"token_expires_at,omitempty"`
+	AutoSync         *bool      `json:"auto_sync,omitempty"`
+}
+
+// AccountingSyncRecordFilter filters sync tracking records
+type AccountingSyncRecordFilter struct {
+	BaseFilter
+	Provider   string `json:"provider,omitempty"`
+	EntityType string `json:"entity_type,omitempty"`
+	Status     string `json:"status,omitempty"`
+}
+
+// AccountingSyncResult summarizes a sync run
+type AccountingSyncResult struct {
+	Provider  string                `json:"provider"`
+	Created   int                   `json:"created"`
+	Updated   int                   `json:"updated"`
+	Failed    int                   `json:"failed"`
+	Errors    []AccountingSyncError `json:"errors,omitempty"`
+	StartedAt time.Time             `json:"started_at"`
+	EndedAt   time.Time             `json:"ended_at"`
+}
+
+// AccountingSyncError describes a record that failed to sync
+type AccountingSyncError struct {
+	EntityType string    `json:"entity_type"`
+	EntityID   uuid.UUID `json:"entity_id"`
+	Error      string    `json:"error"`
+}
+
+const (
+	// accountingSyncBatchSize limits the records of each type pushed per sync run
+	accountingSyncBatchSize = 200
+	// accountingSyncMaxAttempts stops retrying a failing record until it changes again
+	accountingSyncMaxAttempts = 5
+	// qboDefaultBankID is the Intuit bank ID QuickBooks accepts for Web Connect imports
+	qboDefaultBankID = "3000"
+	// accountingDateFormat is the US date format used by IIF and Xero CSV imports
+	accountingDateFormat = "01/02/2006"
+)
+
+// accountingSyncOrder pushes records after the records they reference
+var accountingSyncOrder = []string{
+	domain.AccountingEntityCustomer,
+	domain.AccountingEntityService,
+	domain.AccountingEntityInvoice,
+	domain.AccountingEntityPayment,
+}
+
+// defaultAccounts are used when a tenant has not mapped an account
+var defaultAccounts = map[string]AccountRef{
+	domain.AccountMappingTypeReceivable: {Code: "1100", Name: "Accounts Receivable", Type: domain.AccountTypeReceivable},
+	domain.AccountMappingTypeDeposit:    {Code: "1200", Name: "Undeposited Funds", Type: domain.AccountTypeOtherCurrAsset},
+	domain.AccountMappingTypeTax:        {Code: "2200", Name: "Sales Tax Payable", Type: domain.AccountTypeLiability},
+	domain.AccountMappingTypeIncome:     {Code: "4000", Name: "Landscaping Services", Type: domain.AccountTypeIncome},
+	domain.AccountMappingTypeLateFee:    {Code: "4900", Name: "Late Fee Income", Type: domain.AccountTypeIncome},
+}
+
+// ChartOfAccounts resolves records to accounts using a tenant's mappings, falling back to defaults
+type ChartOfAccounts struct {
+	mappings map[string]map[string]AccountRef
+}
+
+// NewChartOfAccounts builds a chart of accounts from a tenant's mappings
+func NewChartOfAccounts(mappings []*domain.AccountMapping) *ChartOfAccounts {
+	chart := &ChartOfAccounts{mappings: make(map[string]map[string]AccountRef)}
+	for _, mapping := range mappings {
+		if chart.mappings[mapping.MappingType] == nil {
+			chart.mappings[mapping.MappingType] = make(map[string]AccountRef)
+		}
+		ref := AccountRef{Name: mapping.AccountName, Type: mapping.AccountType}
+		if mapping.AccountCode != nil {
+			ref.Code = *mapping.AccountCode
+		}
+		chart.mappings[mapping.MappingType][mapping.MappingKey] = ref
+	}
+	return chart
+}
+
+// Receivable returns the receivables account for a customer
+func (c *ChartOfAccounts) Receivable(customer *domain.EnhancedCustomer) AccountRef {
+	if customer != nil {
+		if ref, ok := c.lookup(domain.AccountMappingTypeReceivable, customer.CustomerType); ok {
+			return ref
+		}
+	}
+	return c.fallback(domain.AccountMappingTypeReceivable)
+}
+
+// Income returns the income account for a service, by service then category
+func (c *ChartOfAccounts) Income(service *domain.Service) AccountRef {
+	if service != nil {
+		if ref, ok := c.lookup(domain.AccountMappingTypeIncome, service.ID.String()); ok {
+			return ref
+		}
+		if ref, ok := c.lookup(domain.AccountMappingTypeIncomeCategory, service.Category); ok {
+			return ref
+		}
+	}
+	return c.fallback(domain.AccountMappingTypeIncome)
+}
+
+// Tax returns the sales tax liability account
+func (c *ChartOfAccounts) Tax() AccountRef {
+	return c.fallback(domain.AccountMappingTypeTax)
+}
+
+// LateFee returns the income account for late fees
+func (c *ChartOfAccounts) LateFee() AccountRef {
+	return c.fallback(domain.AccountMappingTypeLateFee)
+}
+
+// Deposit returns the account payments are deposited to for a payment method
+func (c *ChartOfAccounts) Deposit(paymentMethod string) AccountRef {
+	if ref, ok := c.lookup(domain.AccountMappingTypeDeposit, paymentMethod); ok {
+		return ref
+	}
+	return c.fallback(domain.AccountMappingTypeDeposit)
+}
+
+func (c *ChartOfAccounts) lookup(mappingType, key string) (AccountRef, bool) {
+	if key == "" {
+		return AccountRef{}, false
+	}
+	ref, ok := c.mappings[mappingType][key]
+	return ref, ok
+}
+
+// fallback returns the tenant's default mapping for a type, or the built-in default
+func (c *ChartOfAccounts) fallback(mappingType string) AccountRef {
+	if ref, ok := c.mappings[mappingType][""]; ok {
+		return ref
+	}
+	return defaultAccounts[mappingType]
+}
+
+// accountingServiceImpl implements AccountingService
+type accountingServiceImpl struct {
+	accountingRepo AccountingRepository
+	invoiceRepo    InvoiceRepositoryFull
+	paymentRepo    PaymentRepositoryFull
+	customerRepo   CustomerRepository
+	serviceRepo    ServiceRepository
+	auditService   AuditService
+	adapters       map[string]AccountingSyncAdapter
+	logger         *log.Logger
+}
+
+// NewAccountingService creates a new accounting service instance with the given sync adapters
+func NewAccountingService(
+	accountingRepo AccountingRepository,
+	invoiceRepo InvoiceRepositoryFull,
+	paymentRepo PaymentRepositoryFull,
+	customerRepo CustomerRepository,
+	serviceRepo ServiceRepository,
+	auditService AuditService,
+	logger *log.Logger,
+	adapters ...AccountingSyncAdapter,
+) AccountingService {
+	adapterMap := make(map[string]AccountingSyncAdapter, len(adapters))
+	for _, adapter := range adapters {
+		adapterMap[adapter.Provider()] = adapter
+	}
+
+	return &accountingServiceImpl{
+		accountingRepo: accountingRepo,
+		invoiceRepo:    invoiceRepo,
+		paymentRepo:    paymentRepo,
+		customerRepo:   customerRepo,
+		serviceRepo:    serviceRepo,
+		auditService:   auditService,
+		adapters:       adapterMap,
+		logger:         logger,
+	}
+}
+
+// ListAccountMappings lists the tenant's chart-of-accounts mappings
+func (s *accountingServiceImpl) ListAccountMappings(ctx context.Context) ([]*domain.AccountMapping, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	mappings, err := s.accountingRepo.ListMappings(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list account mappings: %w", err)
+	}
+
+	return mappings, nil
+}
+
+// SaveAccountMapping creates or replaces the mapping for a type and key
+func (s *accountingServiceImpl) SaveAccountMapping(ctx context.Context, req *AccountMappingRequest) (*domain.AccountMapping, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	defaultRef, ok := defaultAccounts[req.MappingType]
+	if !ok && req.MappingType != domain.AccountMappingTypeIncomeCategory {
+		return nil, fmt.Errorf("invalid mapping type: %s", req.MappingType)
+	}
+	if req.MappingType == domain.AccountMappingTypeIncomeCategory {
+		defaultRef = defaultAccounts[domain.AccountMappingTypeIncome]
+		if req.MappingKey == "" {
+			return nil, fmt.Errorf("service category is required for income category mappings")
+		}
+	}
+	if req.MappingType == domain.AccountMappingTypeIncome && req.MappingKey != "" {
+		if _, err := uuid.Parse(req.MappingKey); err != nil {
+			return nil, fmt.Errorf("income mappings must be keyed by service ID")
+		}
+	}
+	if strings.TrimSpace(req.AccountName) == "" {
+		return nil, fmt.Errorf("account name is required")
+	}
+
+	accountType := req.AccountType
+	if accountType == "" {
+		accountType = defaultRef.Type
+	}
+
+	mapping := &domain.AccountMapping{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		MappingType: req.MappingType,
+		MappingKey:  req.MappingKey,
+		AccountCode: req.AccountCode,
+		AccountName: req.AccountName,
+		AccountType: accountType,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.accountingRepo.UpsertMapping(ctx, mapping); err != nil {
+		return nil, fmt.Errorf("failed to save account mapping: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "accounting.mapping_saved",
+		ResourceType: "account_mapping",
+		ResourceID:   &mapping.ID,
+		NewValues: map[string]interface{}{
+			"mapping_type": mapping.MappingType,
+			"mapping_key":  mapping.MappingKey,
+			"account_name": mapping.AccountName,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return mapping, nil
+}
+
+// DeleteAccountMapping removes a mapping so the default account applies again
+func (s *accountingServiceImpl) DeleteAccountMapping(ctx context.Context, mappingID uuid.UUID) error {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("tenant ID not found in context")
+	}
+
+	mapping, err := s.accountingRepo.GetMapping(ctx, tenantID, mappingID)
+	if err != nil {
+		return fmt.Errorf("failed to get account mapping: %w", err)
+	}
+	if mapping == nil {
+		return fmt.Errorf("account mapping not found")
+	}
+
+	if err := s.accountingRepo.DeleteMapping(ctx, tenantID, mappingID); err != nil {
+		return fmt.Errorf("failed to delete account mapping: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "accounting.mapping_deleted",
+		ResourceType: "account_mapping",
+		ResourceID:   &mappingID,
+		OldValues: map[string]interface{}{
+			"mapping_type": mapping.MappingType,
+			"mapping_key":  mapping.MappingKey,
+			"account_name": mapping.AccountName,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return nil
+}
+
+// ExportAccountingData generates an accounting file for invoices and payments in a period
+func (s *accountingServiceImpl) ExportAccountingData(ctx context.Context, req *AccountingExportRequest) (*AccountingExport, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if req.StartDate.IsZero() || req.EndDate.IsZero() || req.EndDate.Before(req.StartDate) {
+		return nil, fmt.Errorf("a valid start and end date are required")
+	}
+
+	chart, err := s.loadChartOfAccounts(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := newAccountingCache()
+	export := &AccountingExport{Format: req.Format}
+	period := fmt.Sprintf("%s_%s", req.StartDate.Format("20060102"), req.EndDate.Format("20060102"))
+
+	var invoices []*AccountingInvoice
+	var payments []*AccountingPayment
+
+	switch req.Format {
+	case domain.AccountingExportFormatIIF, domain.AccountingExportFormatXeroInvoicesCSV:
+		invoices, err = s.loadInvoicesForPeriod(ctx, tenantID, req.StartDate, req.EndDate, chart, cache)
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch req.Format {
+	case domain.AccountingExportFormatIIF, domain.AccountingExportFormatQBO, domain.AccountingExportFormatOFX, domain.AccountingExportFormatXeroPaymentsCSV:
+		payments, err = s.loadPaymentsForPeriod(ctx, tenantID, req.StartDate, req.EndDate, chart, cache)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch req.Format {
+	case domain.AccountingExportFormatIIF:
+		export.Data = BuildIIFExport(invoices, payments, chart)
+		export.Filename = "accounting_" + period + ".iif"
+		export.ContentType = "text/plain"
+	case domain.AccountingExportFormatQBO, domain.AccountingExportFormatOFX:
+		options := OFXExportOptions{
+			Start:       req.StartDate,
+			End:         req.EndDate,
+			GeneratedAt: time.Now(),
+			AccountID:   chart.Deposit("").Code,
+		}
+		export.Filename = "payments_" + period + ".ofx"
+		export.ContentType = "application/x-ofx"
+		if req.Format == domain.AccountingExportFormatQBO {
+			options.IntuitBankID = qboDefaultBankID
+			export.Filename = "payments_" + period + ".qbo"
+			export.ContentType = "application/vnd.intu.qbo"
+		}
+		export.Data = BuildOFXExport(payments, options)
+	case domain.AccountingExportFormatXeroInvoicesCSV:
+		if export.Data, err = BuildXeroInvoicesCSV(invoices); err != nil {
+			return nil, fmt.Errorf("failed to build Xero invoice export: %w", err)
+		}
+		export.Filename = "xero_invoices_" + period + ".csv"
+		export.ContentType = "text/csv"
+	case domain.AccountingExportFormatXeroPaymentsCSV:
+		if export.Data, err = BuildXeroPaymentsCSV(payments); err != nil {
+			return nil, fmt.Errorf("failed to build Xero payment export: %w", err)
+		}
+		export.Filename = "xero_payments_" + period + ".csv"
+		export.ContentType = "text/csv"
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", req.Format)
+	}
+
+	export.InvoiceCount = len(invoices)
+	export.PaymentCount = len(payments)
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "accounting.export",
+		ResourceType: "accounting_export",
+		NewValues: map[string]interface{}{
+			"format":        req.Format,
+			"start_date":    req.StartDate,
+			"end_date":      req.EndDate,
+			"invoice_count": export.InvoiceCount,
+			"payment_count": export.PaymentCount,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return export, nil
+}
+
+// ListConnections lists the tenant's accounting provider connections
+func (s *accountingServiceImpl) ListConnections(ctx context.Context) ([]*domain.AccountingConnection, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	connections, err := s.accountingRepo.ListConnections(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounting connections: %w", err)
+	}
+
+	return connections, nil
+}
+
+// ConnectProvider stores the credentials for an accounting provider
+func (s *accountingServiceImpl) ConnectProvider(ctx context.Context, req *AccountingConnectionRequest) (*domain.AccountingConnection, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if _, ok := s.adapters[req.Provider]; !ok {
+		return nil, fmt.Errorf("unsupported accounting provider: %s", req.Provider)
+	}
+	if req.AccessToken == "" {
+		return nil, fmt.Errorf("access token is required")
+	}
+
+	connection, err := s.accountingRepo.GetConnection(ctx, tenantID, req.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounting connection: %w", err)
+	}
+	if connection == nil {
+		connection = &domain.AccountingConnection{
+			ID:        uuid.New(),
+			TenantID:  tenantID,
+			Provider:  req.Provider,
+			AutoSync:  true,
+			CreatedAt: time.Now(),
+		}
+	}
+
+	connection.ExternalTenantID = req.ExternalTenantID
+	connection.AccessToken = &req.AccessToken
+	connection.RefreshToken = req.RefreshToken
+	connection.TokenExpiresAt = req.TokenExpiresAt
+	connection.Status = domain.AccountingConnectionStatusActive
+	connection.LastError = nil
+	if req.AutoSync != nil {
+		connection.AutoSync = *req.AutoSync
+	}
+	connection.UpdatedAt = time.Now()
+
+	if err := s.accountingRepo.UpsertConnection(ctx, connection); err != nil {
+		return nil, fmt.Errorf("failed to save accounting connection: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "accounting.connected",
+		ResourceType: "accounting_connection",
+		ResourceID:   &connection.ID,
+		NewValues: map[string]interface{}{
+			"provider":  connection.Provider,
+			"auto_sync": connection.AutoSync,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return connection, nil
+}
+
+// DisconnectProvider clears a provider's credentials; sync history is kept for reconnection
+func (s *accountingServiceImpl) DisconnectProvider(ctx context.Context, provider string) error {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("tenant ID not found in context")
+	}
+
+	connection, err := s.accountingRepo.GetConnection(ctx, tenantID, provider)
+	if err != nil {
+		return fmt.Errorf("failed to get accounting connection: %w", err)
+	}
+	if connection == nil {
+		return fmt.Errorf("accounting connection not found")
+	}
+
+	connection.AccessToken = nil
+	connection.RefreshToken = nil
+	connection.TokenExpiresAt = nil
+	connection.Status = domain.AccountingConnectionStatusDisconnected
+	connection.UpdatedAt = time.Now()
+
+	if err := s.accountingRepo.UpsertConnection(ctx, connection); err != nil {
+		return fmt.Errorf("failed to update accounting connection: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "accounting.disconnected",
+		ResourceType: "accounting_connection",
+		ResourceID:   &connection.ID,
+		NewValues:    map[string]interface{}{"provider": provider},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return nil
+}
+
+// SyncProvider pushes new and changed records to a connected provider
+func (s *accountingServiceImpl) SyncProvider(ctx context.Context, provider string) (*AccountingSyncResult, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	adapter, ok := s.adapters[provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported accounting provider: %s", provider)
+	}
+
+	connection, err := s.accountingRepo.GetConnection(ctx, tenantID, provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounting connection: %w", err)
+	}
+	if connection == nil || connection.Status == domain.AccountingConnectionStatusDisconnected {
+		return nil, fmt.Errorf("%s is not connected", provider)
+	}
+
+	chart, err := s.loadChartOfAccounts(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AccountingSyncResult{Provider: provider, StartedAt: time.Now()}
+	cache := newAccountingCache()
+
+	for _, entityType := range accountingSyncOrder {
+		entityIDs, err := s.accountingRepo.GetPendingChanges(ctx, tenantID, provider, entityType, accountingSyncMaxAttempts, accountingSyncBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pending %s changes: %w", entityType, err)
+		}
+
+		for _, entityID := range entityIDs {
+			s.syncRecord(ctx, adapter, connection, chart, cache, entityType, entityID, result)
+		}
+	}
+
+	result.EndedAt = time.Now()
+	connection.LastSyncAt = &result.EndedAt
+	connection.Status = domain.AccountingConnectionStatusActive
+	connection.LastError = nil
+	if result.Failed > 0 {
+		connection.Status = domain.AccountingConnectionStatusError
+		lastError := fmt.Sprintf("%d records failed to sync", result.Failed)
+		connection.LastError = &lastError
+	}
+	connection.UpdatedAt = time.Now()
+
+	// Adapters may have refreshed the connection's tokens, so always save it
+	if err := s.accountingRepo.UpsertConnection(ctx, connection); err != nil {
+		s.logger.Printf("Failed to update accounting connection: %v", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "accounting.sync",
+		ResourceType: "accounting_connection",
+		ResourceID:   &connection.ID,
+		NewValues: map[string]interface{}{
+			"provider": provider,
+			"created":  result.Created,
+			"updated":  result.Updated,
+			"failed":   result.Failed,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return result, nil
+}
+
+// ListSyncRecords lists sync tracking records with pagination
+func (s *accountingServiceImpl) ListSyncRecords(ctx context.Context, filter *AccountingSyncRecordFilter) (*domain.PaginatedResponse, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if filter == nil {
+		filter = &AccountingSyncRecordFilter{}
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PerPage <= 0 {
+		filter.PerPage = 50
+	}
+	if filter.PerPage > 100 {
+		filter.PerPage = 100
+	}
+
+	records, total, err := s.accountingRepo.ListSyncRecords(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sync records: %w", err)
+	}
+
+	totalPages := int((total + int64(filter.PerPage) - 1) / int64(filter.PerPage))
+
+	return &domain.PaginatedResponse{
+		Data:       records,
+		Total:      total,
+		Page:       filter.Page,
+		PerPage:    filter.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// ProcessAccountingSync syncs every auto-sync connection. It is called periodically by the
+// worker and is not tenant-scoped.
+func (s *accountingServiceImpl) ProcessAccountingSync(ctx context.Context, now time.Time) error {
+	connections, err := s.accountingRepo.ListAutoSyncConnections(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list accounting connections: %w", err)
+	}
+
+	for _, connection := range connections {
+		tenantCtx := context.WithValue(ctx, "tenant_id", connection.TenantID)
+
+		result, err := s.SyncProvider(tenantCtx, connection.Provider)
+		if err != nil {
+			s.logger.Printf("Failed to sync %s for tenant %s: %v", connection.Provider, connection.TenantID, err)
+			continue
+		}
+		if result.Failed > 0 {
+			s.logger.Printf("Accounting sync for tenant %s had %d failures", connection.TenantID, result.Failed)
+		}
+	}
+
+	return nil
+}
+
+// Helper methods
+
+// accountingCache avoids reloading customers and services shared by many invoices
+type accountingCache struct {
+	customers map[uuid.UUID]*domain.EnhancedCustomer
+	services  map[uuid.UUID]*domain.Service
+	invoices  map[uuid.UUID]*domain.Invoice
+}
+
+func newAccountingCache() *accountingCache {
+	return &accountingCache{
+		customers: make(map[uuid.UUID]*domain.EnhancedCustomer),
+		services:  make(map[uuid.UUID]*domain.Service),
+		invoices:  make(map[uuid.UUID]*domain.Invoice),
+	}
+}
+
+func (s *accountingServiceImpl) loadChartOfAccounts(ctx context.Context, tenantID uuid.UUID) (*ChartOfAccounts, error) {
+	mappings, err := s.accountingRepo.ListMappings(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account mappings: %w", err)
+	}
+	return NewChartOfAccounts(mappings), nil
+}
+
+func (s *accountingServiceImpl) getCustomer(ctx context.Context, tenantID, customerID uuid.UUID, cache *accountingCache) (*domain.EnhancedCustomer, error) {
+	if customer, ok := cache.customers[customerID]; ok {
+		return customer, nil
+	}
+	customer, err := s.customerRepo.GetByID(ctx, tenantID, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	if customer == nil {
+		return nil, fmt.Errorf("customer not found")
+	}
+	cache.customers[customerID] = customer
+	return customer, nil
+}
+
+func (s *accountingServiceImpl) getInvoice(ctx context.Context, tenantID, invoiceID uuid.UUID, cache *accountingCache) (*domain.Invoice, error) {
+	if invoice, ok := cache.invoices[invoiceID]; ok {
+		return invoice, nil
+	}
+	invoice, err := s.invoiceRepo.GetByID(ctx, tenantID, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	if invoice == nil {
+		return nil, fmt.Errorf("invoice not found")
+	}
+	cache.invoices[invoiceID] = invoice
+	return invoice, nil
+}
+
+func (s *accountingServiceImpl) getServices(ctx context.Context, tenantID uuid.UUID, lines []*InvoiceLineItem, cache *accountingCache) (map[uuid.UUID]*domain.Service, error) {
+	var missing []uuid.UUID
+	for _, line := range lines {
+		if _, ok := cache.services[line.ServiceID]; !ok && !containsUUID(missing, line.ServiceID) {
+			missing = append(missing, line.ServiceID)
+		}
+	}
+	if len(missing) > 0 {
+		services, err := s.serviceRepo.GetByIDs(ctx, tenantID, missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get services: %w", err)
+		}
+		for _, service := range services {
+			cache.services[service.ID] = service
+		}
+	}
+	return cache.services, nil
+}
+
+func (s *accountingServiceImpl) buildInvoice(ctx context.Context, invoice *domain.Invoice, chart *ChartOfAccounts, cache *accountingCache) (*AccountingInvoice, error) {
+	customer, err := s.getCustomer(ctx, invoice.TenantID, invoice.CustomerID, cache)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := s.invoiceRepo.GetInvoiceServices(ctx, invoice.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice services: %w", err)
+	}
+
+	services, err := s.getServices(ctx, invoice.TenantID, lines, cache)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildAccountingInvoice(invoice, customer, lines, services, chart), nil
+}
+
+func (s *accountingServiceImpl) buildPayment(ctx context.Context, payment *domain.Payment, chart *ChartOfAccounts, cache *accountingCache) (*AccountingPayment, error) {
+	invoice, err := s.getInvoice(ctx, payment.TenantID, payment.InvoiceID, cache)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.getCustomer(ctx, payment.TenantID, invoice.CustomerID, cache)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountingPayment{
+		Payment:       payment,
+		InvoiceNumber: invoice.InvoiceNumber,
+		CustomerName:  customerDisplayName(customer),
+		Receivable:    chart.Receivable(customer),
+		Deposit:       chart.Deposit(payment.PaymentMethod),
+	}, nil
+}
+
+func (s *accountingServiceImpl) loadInvoicesForPeriod(ctx context.Context, tenantID uuid.UUID, start, end time.Time, chart *ChartOfAccounts, cache *accountingCache) ([]*AccountingInvoice, error) {
+	invoices, err := s.accountingRepo.GetInvoicesForPeriod(ctx, tenantID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoices: %w", err)
+	}
+
+	result := make([]*AccountingInvoice, 0, len(invoices))
+	for _, invoice := range invoices {
+		built, err := s.buildInvoice(ctx, invoice, chart, cache)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare invoice %s: %w", invoice.InvoiceNumber, err)
+		}
+		result = append(result, built)
+	}
+	return result, nil
+}
+
+func (s *accountingServiceImpl) loadPaymentsForPeriod(ctx context.Context, tenantID uuid.UUID, start, end time.Time, chart *ChartOfAccounts, cache *accountingCache) ([]*AccountingPayment, error) {
+	payments, err := s.accountingRepo.GetPaymentsForPeriod(ctx, tenantID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments: %w", err)
+	}
+
+	result := make([]*AccountingPayment, 0, len(payments))
+	for _, payment := range payments {
+		built, err := s.buildPayment(ctx, payment, chart, cache)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare payment %s: %w", payment.ID, err)
+		}
+		result = append(result, built)
+	}
+	return result, nil
+}
+
+// externalID returns the provider ID of an already-synced record
+func (s *accountingServiceImpl) externalID(ctx context.Context, tenantID uuid.UUID, provider, entityType string, entityID uuid.UUID) (*string, error) {
+	record, err := s.accountingRepo.GetSyncRecord(ctx, tenantID, provider, entityType, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync record: %w", err)
+	}
+	if record == nil || record.ExternalID == nil {
+		return nil, nil
+	}
+	return record.ExternalID, nil
+}
+
+// requireExternalID returns the provider ID of a record that must be synced first
+func (s *accountingServiceImpl) requireExternalID(ctx context.Context, tenantID uuid.UUID, provider, entityType string, entityID uuid.UUID) (*string, error) {
+	id, err := s.externalID(ctx, tenantID, provider, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if id == nil {
+		return nil, fmt.Errorf("%s %s has not been synced", entityType, entityID)
+	}
+	return id, nil
+}
+
+// syncRecord pushes one record and records the outcome for change tracking
+func (s *accountingServiceImpl) syncRecord(
+	ctx context.Context,
+	adapter AccountingSyncAdapter,
+	conn *domain.AccountingConnection,
+	chart *ChartOfAccounts,
+	cache *accountingCache,
+	entityType string,
+	entityID uuid.UUID,
+	result *AccountingSyncResult,
+) {
+	tenantID := conn.TenantID
+	provider := conn.Provider
+
+	record, err := s.accountingRepo.GetSyncRecord(ctx, tenantID, provider, entityType, entityID)
+	if err != nil {
+		s.logger.Printf("Failed to get sync record for %s %s: %v", entityType, entityID, err)
+		return
+	}
+	if record == nil {
+		record = &domain.AccountingSyncRecord{
+			ID:         uuid.New(),
+			TenantID:   tenantID,
+			Provider:   provider,
+			EntityType: entityType,
+			EntityID:   entityID,
+			CreatedAt:  time.Now(),
+		}
+	}
+
+	existingID := record.ExternalID
+	sourceUpdatedAt, newID, pushErr := s.pushEntity(ctx, adapter, conn, chart, cache, entityType, entityID, existingID)
+
+	now := time.Now()
+	record.Attempts++
+	record.UpdatedAt = now
+	if !sourceUpdatedAt.IsZero() {
+		record.SourceUpdatedAt = &sourceUpdatedAt
+	}
+
+	if pushErr != nil {
+		errMsg := pushErr.Error()
+		record.Status = domain.AccountingSyncStatusFailed
+		record.LastError = &errMsg
+		result.Failed++
+		result.Errors = append(result.Errors, AccountingSyncError{EntityType: entityType, EntityID: entityID, Error: errMsg})
+	} else {
+		record.Status = domain.AccountingSyncStatusSynced
+		record.ExternalID = &newID
+		record.LastError = nil
+		record.Attempts = 0
+		record.SyncedAt = &now
+		if existingID == nil {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+
+	if err := s.accountingRepo.UpsertSyncRecord(ctx, record); err != nil {
+		s.logger.Printf("Failed to save sync record for %s %s: %v", entityType, entityID, err)
+	}
+}
+
+// pushEntity loads a record, resolves its references and hands it to the adapter. It returns
+// the record's updated_at so later edits can be detected.
+func (s *accountingServiceImpl) pushEntity(
+	ctx context.Context,
+	adapter AccountingSyncAdapter,
+	conn *domain.AccountingConnection,
+	chart *ChartOfAccounts,
+	cache *accountingCache,
+	entityType string,
+	entityID uuid.UUID,
+	externalID *string,
+) (time.Time, string, error) {
+	tenantID := conn.TenantID
+	provider := conn.Provider
+
+	switch entityType {
+	case domain.AccountingEntityCustomer:
+		customer, err := s.getCustomer(ctx, tenantID, entityID, cache)
+		if err != nil {
+			return time.Time{}, "", err
+		}
+		id, err := adapter.PushCustomer(ctx, conn, customer, chart.Receivable(customer), externalID)
+		return customer.UpdatedAt, id, err
+
+	case domain.AccountingEntityService:
+		service, err := s.serviceRepo.GetByID(ctx, tenantID, entityID)
+		if err != nil {
+			return time.Time{}, "", fmt.Errorf("failed to get service: %w", err)
+		}
+		if service == nil {
+			return time.Time{}, "", fmt.Errorf("service not found")
+		}
+		cache.services[service.ID] = service
+		id, err := adapter.PushItem(ctx, conn, service, chart.Income(service), externalID)
+		return service.UpdatedAt, id, err
+
+	case domain.AccountingEntityInvoice:
+		invoice, err := s.getInvoice(ctx, tenantID, entityID, cache)
+		if err != nil {
+			return time.Time{}, "", err
+		}
+		built, err := s.buildInvoice(ctx, invoice, chart, cache)
+		if err != nil {
+			return invoice.UpdatedAt, "", err
+		}
+		if built.ExternalCustomerID, err = s.requireExternalID(ctx, tenantID, provider, domain.AccountingEntityCustomer, invoice.CustomerID); err != nil {
+			return invoice.UpdatedAt, "", err
+		}
+		for _, line := range built.Lines {
+			if line.ServiceID == nil {
+				continue
+			}
+			if line.ExternalItemID, err = s.externalID(ctx, tenantID, provider, domain.AccountingEntityService, *line.ServiceID); err != nil {
+				return invoice.UpdatedAt, "", err
+			}
+		}
+		id, err := adapter.PushInvoice(ctx, conn, built, externalID)
+		return invoice.UpdatedAt, id, err
+
+	case domain.AccountingEntityPayment:
+		payment, err := s.paymentRepo.GetByID(ctx, tenantID, entityID)
+		if err != nil {
+			return time.Time{}, "", fmt.Errorf("failed to get payment: %w", err)
+		}
+		if payment == nil {
+			return time.Time{}, "", fmt.Errorf("payment not found")
+		}
+		built, err := s.buildPayment(ctx, payment, chart, cache)
+		if err != nil {
+			return payment.UpdatedAt, "", err
+		}
+		invoice := cache.invoices[payment.InvoiceID]
+		if built.ExternalCustomerID, err = s.requireExternalID(ctx, tenantID, provider, domain.AccountingEntityCustomer, invoice.CustomerID); err != nil {
+			return payment.UpdatedAt, "", err
+		}
+		if built.ExternalInvoiceID, err = s.requireExternalID(ctx, tenantID, provider, domain.AccountingEntityInvoice, invoice.ID); err != nil {
+			return payment.UpdatedAt, "", err
+		}
+		id, err := adapter.PushPayment(ctx, conn, built, externalID)
+		return payment.UpdatedAt, id, err
+	}
+
+	return time.Time{}, "", fmt.Errorf("unsupported entity type: %s", entityType)
+}
+
+// BuildAccountingInvoice resolves an invoice's lines to income accounts. Any difference between
+// the invoice total and its lines plus tax (late fees, manual adjustments) is booked as a
+// separate line so that every export balances.
+func BuildAccountingInvoice(invoice *domain.Invoice, customer *domain.EnhancedCustomer, lines []*InvoiceLineItem, services map[uuid.UUID]*domain.Service, chart *ChartOfAccounts) *AccountingInvoice {
+	result := &AccountingInvoice{
+		Invoice:    invoice,
+		Customer:   customer,
+		Receivable: chart.Receivable(customer),
+		TaxAccount: chart.Tax(),
+	}
+	if customer != nil {
+		result.CustomerName = customerDisplayName(customer)
+	}
+
+	linesTotal := 0.0
+	for _, line := range lines {
+		service := services[line.ServiceID]
+		serviceID := line.ServiceID
+		accountingLine := &AccountingLine{
+			ServiceID: &serviceID,
+			Quantity:  line.Quantity,
+			UnitPrice: line.UnitPrice,
+			Amount:    line.TotalPrice,
+			Account:   chart.Income(service),
+		}
+		if service != nil {
+			accountingLine.ItemName = service.Name
+			accountingLine.Description = service.Name
+		}
+		if line.Description != nil && *line.Description != "" {
+			accountingLine.Description = *line.Description
+		}
+		if accountingLine.Description == "" {
+			accountingLine.Description = "Services"
+		}
+		result.Lines = append(result.Lines, accountingLine)
+		linesTotal += line.TotalPrice
+	}
+
+	if len(lines) == 0 && invoice.Subtotal != 0 {
+		result.Lines = append(result.Lines, &AccountingLine{
+			Description: "Services",
+			Quantity:    1,
+			UnitPrice:   invoice.Subtotal,
+			Amount:      invoice.Subtotal,
+			Account:     chart.Income(nil),
+		})
+		linesTotal = invoice.Subtotal
+	}
+
+	if adjustment := roundCents(invoice.Subtotal - linesTotal); len(lines) > 0 && adjustment != 0 {
+		result.Lines = append(result.Lines, &AccountingLine{
+			Description: "Adjustment",
+			Quantity:    1,
+			UnitPrice:   adjustment,
+			Amount:      adjustment,
+			Account:     chart.Income(nil),
+		})
+	}
+
+	if lateFees := roundCents(invoice.TotalAmount - invoice.Subtotal - invoice.TaxAmount); lateFees != 0 {
+		result.Lines = append(result.Lines, &AccountingLine{
+			Description: "Late fees",
+			Quantity:    1,
+			UnitPrice:   lateFees,
+			Amount:      lateFees,
+			Account:     chart.LateFee(),
+		})
+	}
+
+	return result
+}
+
+// BuildIIFExport writes invoices and payments as a QuickBooks Desktop IIF file, including the
+// account and customer lists they reference
+func BuildIIFExport(invoices []*AccountingInvoice, payments []*AccountingPayment, chart *ChartOfAccounts) []byte {
+	var b bytes.Buffer
+	row := func(fields ...string) {
+		for i, field := range fields {
+			fields[i] = iifField(field)
+		}
+		b.WriteString(strings.Join(fields, "\t"))
+		b.WriteString("\r\n")
+	}
+
+	// Accounts
+	accounts := make(map[string]AccountRef)
+	addAccount := func(ref AccountRef) { accounts[ref.Name] = ref }
+	for _, invoice := range invoices {
+		addAccount(invoice.Receivable)
+		addAccount(invoice.TaxAccount)
+		for _, line := range invoice.Lines {
+			addAccount(line.Account)
+		}
+	}
+	for _, payment := range payments {
+		addAccount(payment.Receivable)
+		addAccount(payment.Deposit)
+	}
+	accountNames := make([]string, 0, len(accounts))
+	for name := range accounts {
+		accountNames = append(accountNames, name)
+	}
+	sort.Strings(accountNames)
+
+	row("!ACCNT", "NAME", "ACCNTTYPE", "ACCNUM")
+	for _, name := range accountNames {
+		ref := accounts[name]
+		row("ACCNT", ref.Name, iifAccountType(ref.Type), ref.Code)
+	}
+
+	// Customers
+	row("!CUST", "NAME", "BADDR1", "BADDR2", "BADDR3", "EMAIL", "PHONE1")
+	seen := make(map[uuid.UUID]bool)
+	for _, invoice := range invoices {
+		if invoice.Customer == nil || seen[invoice.Customer.ID] {
+			continue
+		}
+		seen[invoice.Customer.ID] = true
+		customer := invoice.Customer
+		row("CUST", invoice.CustomerName, stringValue(customer.AddressLine1), stringValue(customer.AddressLine2),
+			cityStateZip(customer), stringValue(customer.Email), stringValue(customer.Phone))
+	}
+
+	// Transactions
+	row("!TRNS", "TRNSID", "TRNSTYPE", "DATE", "ACCNT", "NAME", "AMOUNT", "DOCNUM", "MEMO")
+	row("!SPL", "SPLID", "TRNSTYPE", "DATE", "ACCNT", "NAME", "AMOUNT", "DOCNUM", "MEMO", "QNTY", "PRICE", "INVITEM")
+	row("!ENDTRNS")
+
+	for _, invoice := range invoices {
+		date := invoiceDate(invoice.Invoice).Format(accountingDateFormat)
+		number := invoice.Invoice.InvoiceNumber
+		row("TRNS", "", "INVOICE", date, invoice.Receivable.Name, invoice.CustomerName, formatAmount(invoice.Invoice.TotalAmount), number, "")
+		for _, line := range invoice.Lines {
+			row("SPL", "", "INVOICE", date, line.Account.Name, invoice.CustomerName, formatAmount(-line.Amount), number,
+				line.Description, formatQuantity(-line.Quantity), formatAmount(line.UnitPrice), line.ItemName)
+		}
+		if invoice.Invoice.TaxAmount != 0 {
+			row("SPL", "", "INVOICE", date, invoice.TaxAccount.Name, invoice.CustomerName, formatAmount(-invoice.Invoice.TaxAmount), number,
+				"Sales tax", "", "", "")
+		}
+		row("ENDTRNS")
+	}
+
+	for _, payment := range payments {
+		date := paymentDate(payment.Payment).Format(accountingDateFormat)
+		memo := "Payment for invoice " + payment.InvoiceNumber
+		row("TRNS", "", "PAYMENT", date, payment.Deposit.Name, payment.CustomerName, formatAmount(payment.Payment.Amount), payment.InvoiceNumber, memo)
+		row("SPL", "", "PAYMENT", date, payment.Receivable.Name, payment.CustomerName, formatAmount(-payment.Payment.Amount), payment.InvoiceNumber, memo, "", "", "")
+		row("ENDTRNS")
+	}
+
+	return b.Bytes()
+}
+
+// OFXExportOptions controls an OFX/QBO statement export
+type OFXExportOptions struct {
+	Start        time.Time
+	End          time.Time
+	GeneratedAt  time.Time
+	AccountID    string
+	IntuitBankID string // set for QuickBooks Web Connect (.qbo) files
+}
+
+// BuildOFXExport writes payments as deposits on an OFX 1.02 bank statement, which QuickBooks
+// (as .qbo) and most accounting packages can import for reconciliation
+func BuildOFXExport(payments []*AccountingPayment, options OFXExportOptions) []byte {
+	var b strings.Builder
+
+	b.WriteString("OFXHEADER:100\r\nDATA:OFXSGML\r\nVERSION:102\r\nSECURITY:NONE\r\nENCODING:USASCII\r\n")
+	b.WriteString("CHARSET:1252\r\nCOMPRESSION:NONE\r\nOLDFILEUID:NONE\r\nNEWFILEUID:NONE\r\n\r\n")
+
+	b.WriteString("<OFX>\r\n<SIGNONMSGSRSV1>\r\n<SONRS>\r\n<STATUS>\r\n<CODE>0\r\n<SEVERITY>INFO\r\n</STATUS>\r\n")
+	b.WriteString("<DTSERVER>" + options.GeneratedAt.Format("20060102150405") + "\r\n<LANGUAGE>ENG\r\n")
+	if options.IntuitBankID != "" {
+		b.WriteString("<INTU.BID>" + options.IntuitBankID + "\r\n")
+	}
+	b.WriteString("</SONRS>\r\n</SIGNONMSGSRSV1>\r\n")
+
+	accountID := options.AccountID
+	if accountID == "" {
+		accountID = "UNDEPOSITED"
+	}
+
+	b.WriteString("<BANKMSGSRSV1>\r\n<STMTTRNRS>\r\n<TRNUID>1\r\n<STATUS>\r\n<CODE>0\r\n<SEVERITY>INFO\r\n</STATUS>\r\n")
+	b.WriteString("<STMTRS>\r\n<CURDEF>USD\r\n<BANKACCTFROM>\r\n<BANKID>000000000\r\n")
+	b.WriteString("<ACCTID>" + ofxText(accountID, 22) + "\r\n<ACCTTYPE>CHECKING\r\n</BANKACCTFROM>\r\n")
+	b.WriteString("<BANKTRANLIST>\r\n<DTSTART>" + options.Start.Format("20060102") + "\r\n<DTEND>" + options.End.Format("20060102") + "\r\n")
+
+	total := 0.0
+	for _, payment := range payments {
+		total += payment.Payment.Amount
+		b.WriteString("<STMTTRN>\r\n<TRNTYPE>CREDIT\r\n")
+		b.WriteString("<DTPOSTED>" + paymentDate(payment.Payment).Format("20060102") + "\r\n")
+		b.WriteString("<TRNAMT>" + formatAmount(payment.Payment.Amount) + "\r\n")
+		b.WriteString("<FITID>" + payment.Payment.ID.String() + "\r\n")
+		b.WriteString("<NAME>" + ofxText(payment.CustomerName, 32) + "\r\n")
+		b.WriteString("<MEMO>" + ofxText("Payment for invoice "+payment.InvoiceNumber, 255) + "\r\n")
+		b.WriteString("</STMTTRN>\r\n")
+	}
+
+	b.WriteString("</BANKTRANLIST>\r\n<LEDGERBAL>\r\n<BALAMT>" + formatAmount(total) + "\r\n")
+	b.WriteString("<DTASOF>" + options.End.Format("20060102") + "\r\n</LEDGERBAL>\r\n")
+	b.WriteString("</STMTRS>\r\n</STMTTRNRS>\r\n</BANKMSGSRSV1>\r\n</OFX>\r\n")
+
+	return []byte(b.String())
+}
+
+// BuildXeroInvoicesCSV writes invoices in Xero's sales invoice import template, one row per line
+func BuildXeroInvoicesCSV(invoices []*AccountingInvoice) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+
+	if err := w.Write([]string{
+		"*ContactName", "EmailAddress", "POAddressLine1", "POAddressLine2", "POCity", "PORegion", "POPostalCode",
+		"*InvoiceNumber", "Reference", "*InvoiceDate", "*DueDate", "InventoryItemCode", "*Description",
+		"*Quantity", "*UnitAmount", "*AccountCode", "*TaxType", "TaxAmount", "Currency",
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, invoice := range invoices {
+		var email, address1, address2, city, region, postalCode string
+		if customer := invoice.Customer; customer != nil {
+			email = stringValue(customer.Email)
+			address1 = stringValue(customer.AddressLine1)
+			address2 = stringValue(customer.AddressLine2)
+			city = stringValue(customer.City)
+			region = stringValue(customer.State)
+			postalCode = stringValue(customer.ZipCode)
+		}
+
+		issued := invoiceDate(invoice.Invoice)
+		due := issued
+		if invoice.Invoice.DueDate != nil {
+			due = *invoice.Invoice.DueDate
+		}
+
+		taxType := "Tax Exempt"
+		if invoice.Invoice.TaxAmount != 0 {
+			taxType = "Tax on Sales"
+		}
+		lineTaxes := allocateTax(invoice.Invoice.TaxAmount, invoice.Lines)
+
+		for i, line := range invoice.Lines {
+			if err := w.Write([]string{
+				invoice.CustomerName, email, address1, address2, city, region, postalCode,
+				invoice.Invoice.InvoiceNumber, "", issued.Format(accountingDateFormat), due.Format(accountingDateFormat),
+				"", line.Description, formatQuantity(line.Quantity), formatAmount(line.UnitPrice),
+				accountCode(line.Account), taxType, formatAmount(lineTaxes[i]), "USD",
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// BuildXeroPaymentsCSV writes payments as a Xero bank statement import so deposits can be
+// reconciled against the matching invoices
+func BuildXeroPaymentsCSV(payments []*AccountingPayment) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+
+	if err := w.Write([]string{"*Date", "*Amount", "Payee", "Description", "Reference", "Check Number"}); err != nil {
+		return nil, err
+	}
+
+	for _, payment := range payments {
+		description := "Payment"
+		if payment.Payment.PaymentMethod != "" {
+			description = "Payment by " + payment.Payment.PaymentMethod
+		}
+		if err := w.Write([]string{
+			paymentDate(payment.Payment).Format(accountingDateFormat),
+			formatAmount(payment.Payment.Amount),
+			payment.CustomerName,
+			description,
+			payment.InvoiceNumber,
+			"",
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// allocateTax spreads an invoice's tax across its lines in proportion to their amounts,
+// putting any rounding remainder on the last line
+func allocateTax(tax float64, lines []*AccountingLine) []float64 {
+	allocations := make([]float64, len(lines))
+	if tax == 0 || len(lines) == 0 {
+		return allocations
+	}
+
+	total := 0.0
+	for _, line := range lines {
+		total += line.Amount
+	}
+
+	allocated := 0.0
+	for i, line := range lines {
+		if i == len(lines)-1 {
+			allocations[i] = roundCents(tax - allocated)
+			break
+		}
+		share := 0.0
+		if total != 0 {
+			share = roundCents(tax * line.Amount / total)
+		}
+		allocations[i] = share
+		allocated += share
+	}
+	return allocations
+}
+
+func iifAccountType(accountType string) string {
+	switch accountType {
+	case domain.AccountTypeReceivable:
+		return "AR"
+	case domain.AccountTypeIncome:
+		return "INC"
+	case domain.AccountTypeLiability:
+		return "OCLIAB"
+	case domain.AccountTypeBank:
+		return "BANK"
+	default:
+		return "OCASSET"
+	}
+}
+
+// iifField strips characters that would break IIF's tab-delimited rows
+func iifField(value string) string {
+	return strings.NewReplacer("\t", " ", "\r", " ", "\n", " ", "\"", "'").Replace(value)
+}
+
+// ofxText escapes SGML markup characters and truncates to the OFX field length
+func ofxText(value string, maxLen int) string {
+	value = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", " ", "\n", " ").Replace(value)
+	if len(value) > maxLen {
+		value = value[:maxLen]
+	}
+	return value
+}
+
+func accountCode(ref AccountRef) string {
+	if ref.Code != "" {
+		return ref.Code
+	}
+	return ref.Name
+}
+
+func invoiceDate(invoice *domain.Invoice) time.Time {
+	if invoice.IssuedDate != nil {
+		return *invoice.IssuedDate
+	}
+	return invoice.CreatedAt
+}
+
+func paymentDate(payment *domain.Payment) time.Time {
+	if payment.ProcessedAt != nil {
+		return *payment.ProcessedAt
+	}
+	return payment.CreatedAt
+}
+
+func cityStateZip(customer *domain.EnhancedCustomer) string {
+	parts := []string{}
+	if city := stringValue(customer.City); city != "" {
+		parts = append(parts, city)
+	}
+	stateZip := strings.TrimSpace(stringValue(customer.State) + " " + stringValue(customer.ZipCode))
+	if stateZip != "" {
+		parts = append(parts, stateZip)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func formatAmount(amount float64) string {
+	amount = roundCents(amount)
+	if amount == 0 {
+		amount = 0 // avoid printing negative zero
+	}
+	return fmt.Sprintf("%.2f", amount)
+}
+
+func formatQuantity(quantity float64) string {
+	if quantity == 0 {
+		return "0"
+	}
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.4f", quantity), "0"), ".")
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,769 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// AvailabilityService manages when employees are scheduled to work: weekly
+// working-hour templates, time off requests approved by a manager, and the
+// tenant's holidays
+type AvailabilityService interface {
+	GetWorkingHours(ctx context.Context, userID uuid.UUID) ([]*domain.WorkingHours, error)
+	SetWorkingHours(ctx context.Context, userID uuid.UUID, shifts []WorkingHoursRequest) ([]*domain.WorkingHours, error)
+
+	RequestTimeOff(ctx context.Context, req *TimeOffCreateRequest) (*domain.TimeOffRequest, error)
+	ApproveTimeOff(ctx context.Context, requestID uuid.UUID, notes *string) (*domain.TimeOffRequest, error)
+	DenyTimeOff(ctx context.Context, requestID uuid.UUID, notes *string) (*domain.TimeOffRequest, error)
+	CancelTimeOff(ctx context.Context, requestID uuid.UUID) (*domain.TimeOffRequest, error)
+	ListTimeOff(ctx context.Context, filter *TimeOffFilter) ([]*domain.TimeOffRequest, error)
+
+	CreateHoliday(ctx context.Context, req *HolidayRequest) (*domain.TenantHoliday, error)
+	DeleteHoliday(ctx context.Context, holidayID uuid.UUID) error
+	ListHolidays(ctx context.Context, from, to time.Time) ([]*domain.TenantHoliday, error)
+
+	// GetShiftCalendar lays out a user's shifts day by day, with the holidays
+	// and time off that take them out
+	GetShiftCalendar(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*ShiftCalendarDay, error)
+}
+
+// AvailabilityRepository defines data access for working hours, time off and holidays
+type AvailabilityRepository interface {
+	ListWorkingHours(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) ([]*domain.WorkingHours, error)
+
+	// ReplaceWorkingHours swaps a user's template for a new set in one transaction
+	ReplaceWorkingHours(ctx context.Context, tenantID, userID uuid.UUID, hours []*domain.WorkingHours) error
+
+	CreateTimeOff(ctx context.Context, request *domain.TimeOffRequest) error
+	GetTimeOff(ctx context.Context, tenantID, requestID uuid.UUID) (*domain.TimeOffRequest, error)
+	UpdateTimeOff(ctx context.Context, request *domain.TimeOffRequest) error
+	ListTimeOff(ctx context.Context, tenantID uuid.UUID, filter *TimeOffFilter) ([]*domain.TimeOffRequest, error)
+
+	// ListApprovedTimeOff returns the users' approved time off overlapping the range
+	ListApprovedTimeOff(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID, start, end time.Time) ([]*domain.TimeOffRequest, error)
+
+	CreateHoliday(ctx context.Context, holiday *domain.TenantHoliday) error
+	DeleteHoliday(ctx context.Context, tenantID, holidayID uuid.UUID) error
+	ListHolidays(ctx context.Context, tenantID uuid.UUID, start, end time.Time) ([]*domain.TenantHoliday, error)
+}
+
+// The shift worked by users without a working-hour template
+const (
+	DefaultShiftStart = "08:00"
+	DefaultShiftEnd   = "17:00"
+)
+
+// WorkingHoursRequest is one shift in a working-hour template
+type WorkingHoursRequest struct {
+	Weekday       int        `json:"weekday"`
+	StartTime     string     `json:"start_time" validate:"required"`
+	EndTime       string     `json:"end_time" validate:"required"`
+	EffectiveFrom *time.Time `json:"effective_from,omitempty"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+}
+
+// TimeOffCreateRequest asks for time off, for the caller unless a user is given
+type TimeOffCreateRequest struct {
+	UserID   *uuid.UUID `json:"user_id,omitempty"`
+	Type     string     `json:"type" validate:"required"`
+	StartsAt time.Time  `json:"starts_at" validate:"required"`
+	EndsAt   time.Time  `json:"ends_at" validate:"required"`
+	Reason   *string    `json:"reason,omitempty"`
+}
+
+// TimeOffFilter narrows time off requests by user, status and overlap with a date range
+type TimeOffFilter struct {
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+	Status string     `json:"status,omitempty"`
+	From   *time.Time `json:"from,omitempty"`
+	To     *time.Time `json:"to,omitempty"`
+}
+
+// HolidayRequest closes the business for a day
+type HolidayRequest struct {
+	Date time.Time `json:"date" validate:"required"`
+	Name string    `json:"name" validate:"required"`
+}
+
+// ShiftCalendarDay is one day of a user's shift calendar. Shifts are what is
+// left of the template after holidays and approved time off.
+type ShiftCalendarDay struct {
+	Date    time.Time                `json:"date"`
+	Shifts  []TimeRange              `json:"shifts"`
+	Holiday *string                  `json:"holiday,omitempty"`
+	TimeOff []*domain.TimeOffRequest `json:"time_off"`
+}
+
+// AvailabilityServiceImpl implements AvailabilityService
+type AvailabilityServiceImpl struct {
+	availabilityRepo    AvailabilityRepository
+	userRepo            UserRepository
+	notificationService NotificationService
+	auditService        AuditService
+	logger              *log.Logger
+}
+
+// NewAvailabilityService creates a new availability service
+func NewAvailabilityService(
+	availabilityRepo AvailabilityRepository,
+	userRepo UserRepository,
+	notificationService NotificationService,
+	auditService AuditService,
+	logger *log.Logger,
+) AvailabilityService {
+	return &AvailabilityServiceImpl{
+		availabilityRepo:    availabilityRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		auditService:        auditService,
+		logger:              logger,
+	}
+}
+
+// GetWorkingHours returns a user's working-hour template
+func (s *AvailabilityServiceImpl) GetWorkingHours(ctx context.Context, userID uuid.UUID) ([]*domain.WorkingHours, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	hours, err := s.availabilityRepo.ListWorkingHours(ctx, tenantID, []uuid.UUID{userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list working hours: %w", err)
+	}
+
+	return hours, nil
+}
+
+// SetWorkingHours replaces a user's working-hour template. An empty template
+// puts the user back on the default shift.
+func (s *AvailabilityServiceImpl) SetWorkingHours(ctx context.Context, userID uuid.UUID, shifts []WorkingHoursRequest) ([]*domain.WorkingHours, error) {
+	tenantID, err := s.checkUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateWorkingHours(shifts); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	now := time.Now()
+	hours := make([]*domain.WorkingHours, len(shifts))
+	for i, shift := range shifts {
+		hours[i] = &domain.WorkingHours{
+			ID:            uuid.New(),
+			TenantID:      tenantID,
+			UserID:        userID,
+			Weekday:       shift.Weekday,
+			StartTime:     shift.StartTime,
+			EndTime:       shift.EndTime,
+			EffectiveFrom: shift.EffectiveFrom,
+			EffectiveTo:   shift.EffectiveTo,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+	}
+
+	if err := s.availabilityRepo.ReplaceWorkingHours(ctx, tenantID, userID, hours); err != nil {
+		return nil, fmt.Errorf("failed to save working hours: %w", err)
+	}
+
+	s.logAvailabilityAction(ctx, "working_hours.update", "user", userID, nil, map[string]interface{}{
+		"shifts": len(hours),
+	})
+
+	return hours, nil
+}
+
+// RequestTimeOff records a pending time off request. It takes the user out of
+// the schedule only once a manager approves it.
+func (s *AvailabilityServiceImpl) RequestTimeOff(ctx context.Context, req *TimeOffCreateRequest) (*domain.TimeOffRequest, error) {
+	requestedBy := GetUserIDFromContext(ctx)
+	userID := req.UserID
+	if userID == nil {
+		userID = requestedBy
+	}
+	if userID == nil {
+		return nil, fmt.Errorf("validation failed: user is required")
+	}
+
+	tenantID, err := s.checkUser(ctx, *userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateTimeOffRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	now := time.Now()
+	request := &domain.TimeOffRequest{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		UserID:      *userID,
+		Type:        req.Type,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		Reason:      req.Reason,
+		Status:      domain.TimeOffPending,
+		RequestedBy: requestedBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.availabilityRepo.CreateTimeOff(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to create time off request: %w", err)
+	}
+
+	s.logAvailabilityAction(ctx, "time_off.request", "time_off_request", request.ID, nil, map[string]interface{}{
+		"user_id":   request.UserID,
+		"type":      request.Type,
+		"starts_at": request.StartsAt,
+		"ends_at":   request.EndsAt,
+	})
+
+	return request, nil
+}
+
+// ApproveTimeOff approves a pending request, taking the user out of the schedule
+func (s *AvailabilityServiceImpl) ApproveTimeOff(ctx context.Context, requestID uuid.UUID, notes *string) (*domain.TimeOffRequest, error) {
+	return s.reviewTimeOff(ctx, requestID, domain.TimeOffApproved, notes)
+}
+
+// DenyTimeOff denies a pending request
+func (s *AvailabilityServiceImpl) DenyTimeOff(ctx context.Context, requestID uuid.UUID, notes *string) (*domain.TimeOffRequest, error) {
+	return s.reviewTimeOff(ctx, requestID, domain.TimeOffDenied, notes)
+}
+
+// CancelTimeOff withdraws a pending or approved request, putting the user
+// back on the schedule
+func (s *AvailabilityServiceImpl) CancelTimeOff(ctx context.Context, requestID uuid.UUID) (*domain.TimeOffRequest, error) {
+	request, err := s.getTimeOff(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Status != domain.TimeOffPending && request.Status != domain.TimeOffApproved {
+		return nil, fmt.Errorf("time off request cannot be cancelled: it is %s", request.Status)
+	}
+
+	oldStatus := request.Status
+	request.Status = domain.TimeOffCancelled
+	request.UpdatedAt = time.Now()
+	if err := s.availabilityRepo.UpdateTimeOff(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to update time off request: %w", err)
+	}
+
+	s.logAvailabilityAction(ctx, "time_off.cancel", "time_off_request", request.ID,
+		map[string]interface{}{"status": oldStatus},
+		map[string]interface{}{"status": request.Status})
+
+	return request, nil
+}
+
+// ListTimeOff lists time off requests, latest first
+func (s *AvailabilityServiceImpl) ListTimeOff(ctx context.Context, filter *TimeOffFilter) ([]*domain.TimeOffRequest, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if filter == nil {
+		filter = &TimeOffFilter{}
+	}
+
+	requests, err := s.availabilityRepo.ListTimeOff(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time off requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+// CreateHoliday closes the business for a day
+func (s *AvailabilityServiceImpl) CreateHoliday(ctx context.Context, req *HolidayRequest) (*domain.TenantHoliday, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("validation failed: holiday name is required")
+	}
+	if req.Date.IsZero() {
+		return nil, fmt.Errorf("validation failed: holiday date is required")
+	}
+	date := time.Date(req.Date.Year(), req.Date.Month(), req.Date.Day(), 0, 0, 0, 0, time.UTC)
+
+	existing, err := s.availabilityRepo.ListHolidays(ctx, tenantID, date, date.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holidays: %w", err)
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("holiday already exists on %s: %s", date.Format("2006-01-02"), existing[0].Name)
+	}
+
+	holiday := &domain.TenantHoliday{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Date:      date,
+		Name:      name,
+		CreatedBy: GetUserIDFromContext(ctx),
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.availabilityRepo.CreateHoliday(ctx, holiday); err != nil {
+		return nil, fmt.Errorf("failed to create holiday: %w", err)
+	}
+
+	s.logAvailabilityAction(ctx, "holiday.create", "tenant_holiday", holiday.ID, nil, map[string]interface{}{
+		"date": holiday.Date.Format("2006-01-02"),
+		"name": holiday.Name,
+	})
+
+	return holiday, nil
+}
+
+// DeleteHoliday reopens the business on a holiday
+func (s *AvailabilityServiceImpl) DeleteHoliday(ctx context.Context, holidayID uuid.UUID) error {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("tenant ID not found in context")
+	}
+
+	if err := s.availabilityRepo.DeleteHoliday(ctx, tenantID, holidayID); err != nil {
+		return fmt.Errorf("failed to delete holiday: %w", err)
+	}
+
+	s.logAvailabilityAction(ctx, "holiday.delete", "tenant_holiday", holidayID, nil, nil)
+
+	return nil
+}
+
+// ListHolidays lists the tenant's holidays in the date range
+func (s *AvailabilityServiceImpl) ListHolidays(ctx context.Context, from, to time.Time) ([]*domain.TenantHoliday, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	holidays, err := s.availabilityRepo.ListHolidays(ctx, tenantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holidays: %w", err)
+	}
+
+	return holidays, nil
+}
+
+// GetShiftCalendar lays out a user's shifts for each day in the range
+func (s *AvailabilityServiceImpl) GetShiftCalendar(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*ShiftCalendarDay, error) {
+	tenantID, err := s.checkUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !to.After(from) {
+		return nil, fmt.Errorf("validation failed: end date must be after start date")
+	}
+	if to.Sub(from) > 92*24*time.Hour {
+		return nil, fmt.Errorf("validation failed: shift calendar is limited to 92 days")
+	}
+
+	hours, err := s.availabilityRepo.ListWorkingHours(ctx, tenantID, []uuid.UUID{userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list working hours: %w", err)
+	}
+	holidays, err := s.availabilityRepo.ListHolidays(ctx, tenantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holidays: %w", err)
+	}
+	// Pending requests show on the calendar but only approved ones take shifts away
+	timeOff, err := s.availabilityRepo.ListTimeOff(ctx, tenantID, &TimeOffFilter{UserID: &userID, From: &from, To: &to})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time off requests: %w", err)
+	}
+
+	days := make([]*ShiftCalendarDay, 0)
+	for day := startOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		shifts, _ := WorkingWindows(userID, hours, timeOff, holidays, TimeRange{Start: day, End: next})
+
+		calendarDay := &ShiftCalendarDay{
+			Date:    day,
+			Shifts:  shifts,
+			TimeOff: []*domain.TimeOffRequest{},
+		}
+		if holiday := holidayOn(holidays, day); holiday != nil {
+			calendarDay.Holiday = &holiday.Name
+		}
+		for _, request := range timeOff {
+			if request.Status != domain.TimeOffDenied && request.Status != domain.TimeOffCancelled &&
+				request.StartsAt.Before(next) && request.EndsAt.After(day) {
+				calendarDay.TimeOff = append(calendarDay.TimeOff, request)
+			}
+		}
+		if calendarDay.Shifts == nil {
+			calendarDay.Shifts = []TimeRange{}
+		}
+		days = append(days, calendarDay)
+	}
+
+	return days, nil
+}
+
+// ValidateWorkingHours checks a working-hour template for bad times and
+// shifts that overlap on the same day
+func ValidateWorkingHours(shifts []WorkingHoursRequest) error {
+	for i, shift := range shifts {
+		if shift.Weekday < 0 || shift.Weekday > 6 {
+			return fmt.Errorf("weekday must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		start, ok := parseBookingClock(shift.StartTime)
+		if !ok {
+			return fmt.Errorf("start time %q must be HH:MM", shift.StartTime)
+		}
+		end, ok := parseBookingClock(shift.EndTime)
+		if !ok {
+			return fmt.Errorf("end time %q must be HH:MM", shift.EndTime)
+		}
+		if end <= start {
+			return fmt.Errorf("shift on %s must end after it starts", time.Weekday(shift.Weekday))
+		}
+		if shift.EffectiveFrom != nil && shift.EffectiveTo != nil && shift.EffectiveTo.Before(*shift.EffectiveFrom) {
+			return fmt.Errorf("shift on %s must be effective to a date after it is effective from", time.Weekday(shift.Weekday))
+		}
+
+		for _, other := range shifts[:i] {
+			if other.Weekday != shift.Weekday || !effectiveRangesOverlap(shift, other) {
+				continue
+			}
+			otherStart, _ := parseBookingClock(other.StartTime)
+			otherEnd, _ := parseBookingClock(other.EndTime)
+			if start < otherEnd && otherStart < end {
+				return fmt.Errorf("shifts on %s overlap", time.Weekday(shift.Weekday))
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateTimeOffRequest checks a time off request's type and dates
+func ValidateTimeOffRequest(req *TimeOffCreateRequest) error {
+	if !containsString(domain.TimeOffTypes, req.Type) {
+		return fmt.Errorf("unknown time off type %q", req.Type)
+	}
+	if req.StartsAt.IsZero() || req.EndsAt.IsZero() {
+		return fmt.Errorf("start and end are required")
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return fmt.Errorf("time off must end after it starts")
+	}
+	return nil
+}
+
+// ShiftsOn returns the shifts a user works on a day from their template, or
+// the default shift when they have no template at all. Seasonal staff outside
+// their season have no shifts.
+func ShiftsOn(hours []*domain.WorkingHours, day time.Time) []TimeRange {
+	midnight := startOfDay(day)
+	if len(hours) == 0 {
+		return []TimeRange{{
+			Start: bookingClock(midnight, DefaultShiftStart, 8*60),
+			End:   bookingClock(midnight, DefaultShiftEnd, 17*60),
+		}}
+	}
+
+	shifts := make([]TimeRange, 0)
+	for _, shift := range hours {
+		if time.Weekday(shift.Weekday) != midnight.Weekday() || !shift.IsEffectiveOn(midnight) {
+			continue
+		}
+		start, startOK := parseBookingClock(shift.StartTime)
+		end, endOK := parseBookingClock(shift.EndTime)
+		if !startOK || !endOK || end <= start {
+			continue
+		}
+		shifts = append(shifts, TimeRange{
+			Start: midnight.Add(time.Duration(start) * time.Minute),
+			End:   midnight.Add(time.Duration(end) * time.Minute),
+		})
+	}
+
+	sort.Slice(shifts, func(i, j int) bool { return shifts[i].Start.Before(shifts[j].Start) })
+	return shifts
+}
+
+// WorkingWindows returns the times in the range a user is on shift, less
+// tenant holidays and their approved time off, with conflicts explaining the
+// holidays and time off. Days run in the location of the range start.
+func WorkingWindows(userID uuid.UUID, hours []*domain.WorkingHours, timeOff []*domain.TimeOffRequest, holidays []*domain.TenantHoliday, timeRange TimeRange) ([]TimeRange, []AvailabilityConflict) {
+	var windows []TimeRange
+	var conflicts []AvailabilityConflict
+
+	userHours := make([]*domain.WorkingHours, 0, len(hours))
+	for _, shift := range hours {
+		if shift.UserID == userID {
+			userHours = append(userHours, shift)
+		}
+	}
+
+	for day := startOfDay(timeRange.Start); day.Before(timeRange.End); day = day.AddDate(0, 0, 1) {
+		if holiday := holidayOn(holidays, day); holiday != nil {
+			if closed, ok := clipTimeRange(TimeRange{Start: day, End: day.AddDate(0, 0, 1)}, timeRange); ok {
+				conflicts = append(conflicts, AvailabilityConflict{
+					ResourceID:   userID,
+					ResourceType: "user",
+					ConflictTime: closed,
+					Reason:       fmt.Sprintf("Holiday: %s", holiday.Name),
+				})
+			}
+			continue
+		}
+
+		for _, shift := range ShiftsOn(userHours, day) {
+			if window, ok := clipTimeRange(shift, timeRange); ok {
+				windows = append(windows, window)
+			}
+		}
+	}
+
+	off := make([]TimeRange, 0)
+	for _, request := range timeOff {
+		if request.UserID != userID || request.Status != domain.TimeOffApproved {
+			continue
+		}
+		away, ok := clipTimeRange(TimeRange{Start: request.StartsAt, End: request.EndsAt}, timeRange)
+		if !ok {
+			continue
+		}
+		off = append(off, away)
+		conflicts = append(conflicts, AvailabilityConflict{
+			ResourceID:   userID,
+			ResourceType: "user",
+			ConflictTime: away,
+			Reason:       fmt.Sprintf("Time off: %s", request.Type),
+		})
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].ConflictTime.Start.Before(conflicts[j].ConflictTime.Start)
+	})
+
+	return SubtractTimeRanges(windows, off), conflicts
+}
+
+// SubtractTimeRanges removes the busy times from the windows
+func SubtractTimeRanges(windows, busy []TimeRange) []TimeRange {
+	sorted := make([]TimeRange, len(busy))
+	copy(sorted, busy)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var result []TimeRange
+	for _, window := range windows {
+		free := window.Start
+		for _, b := range sorted {
+			if !b.End.After(free) || !b.Start.Before(window.End) {
+				continue
+			}
+			if b.Start.After(free) {
+				result = append(result, TimeRange{Start: free, End: b.Start})
+			}
+			free = b.End
+		}
+		if window.End.After(free) {
+			result = append(result, TimeRange{Start: free, End: window.End})
+		}
+	}
+	return result
+}
+
+// MergeTimeRanges combines overlapping and touching ranges, in start order
+func MergeTimeRanges(ranges []TimeRange) []TimeRange {
+	sorted := make([]TimeRange, len(ranges))
+	copy(sorted, ranges)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var merged []TimeRange
+	for _, r := range sorted {
+		if n := len(merged); n > 0 && !r.Start.After(merged[n-1].End) {
+			if r.End.After(merged[n-1].End) {
+				merged[n-1].End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// loadWorkingWindows loads the users' working hours, approved time off and
+// the tenant's holidays over the range, and returns each user's working
+// windows and the conflicts taking time out of them. Without a repository
+// everyone works the default shift.
+func loadWorkingWindows(ctx context.Context, availabilityRepo AvailabilityRepository, tenantID uuid.UUID, userIDs []uuid.UUID, timeRange TimeRange) (map[uuid.UUID][]TimeRange, map[uuid.UUID][]AvailabilityConflict, error) {
+	var hours []*domain.WorkingHours
+	var timeOff []*domain.TimeOffRequest
+	var holidays []*domain.TenantHoliday
+
+	if availabilityRepo != nil && len(userIDs) > 0 {
+		var err error
+		if hours, err = availabilityRepo.ListWorkingHours(ctx, tenantID, userIDs); err != nil {
+			return nil, nil, fmt.Errorf("failed to list working hours: %w", err)
+		}
+		if timeOff, err = availabilityRepo.ListApprovedTimeOff(ctx, tenantID, userIDs, timeRange.Start, timeRange.End); err != nil {
+			return nil, nil, fmt.Errorf("failed to list time off: %w", err)
+		}
+		if holidays, err = availabilityRepo.ListHolidays(ctx, tenantID, startOfDay(timeRange.Start), timeRange.End); err != nil {
+			return nil, nil, fmt.Errorf("failed to list holidays: %w", err)
+		}
+	}
+
+	windows := make(map[uuid.UUID][]TimeRange, len(userIDs))
+	conflicts := make(map[uuid.UUID][]AvailabilityConflict, len(userIDs))
+	for _, userID := range userIDs {
+		windows[userID], conflicts[userID] = WorkingWindows(userID, hours, timeOff, holidays, timeRange)
+	}
+
+	return windows, conflicts, nil
+}
+
+func (s *AvailabilityServiceImpl) reviewTimeOff(ctx context.Context, requestID uuid.UUID, status string, notes *string) (*domain.TimeOffRequest, error) {
+	request, err := s.getTimeOff(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Status != domain.TimeOffPending {
+		return nil, fmt.Errorf("time off request cannot be reviewed: it is %s", request.Status)
+	}
+
+	reviewerID := GetUserIDFromContext(ctx)
+	if reviewerID != nil && *reviewerID == request.UserID {
+		return nil, fmt.Errorf("validation failed: time off must be reviewed by a manager, not the requester")
+	}
+
+	now := time.Now()
+	request.Status = status
+	request.ReviewedBy = reviewerID
+	request.ReviewedAt = &now
+	request.ReviewNotes = notes
+	request.UpdatedAt = now
+	if err := s.availabilityRepo.UpdateTimeOff(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to update time off request: %w", err)
+	}
+
+	s.logAvailabilityAction(ctx, "time_off."+status, "time_off_request", request.ID,
+		map[string]interface{}{"status": domain.TimeOffPending},
+		map[string]interface{}{"status": request.Status, "review_notes": request.ReviewNotes})
+
+	message := fmt.Sprintf("Your %s time off from %s to %s was %s", request.Type,
+		request.StartsAt.Format("Jan 2, 2006 3:04 PM"), request.EndsAt.Format("Jan 2, 2006 3:04 PM"), status)
+	if notes != nil && *notes != "" {
+		message += ": " + *notes
+	}
+	if err := s.notificationService.SendNotification(ctx, &NotificationRequest{
+		UserID:  &request.UserID,
+		Type:    "time_off." + status,
+		Title:   "Time Off " + strings.Title(status),
+		Message: message,
+		Data: map[string]interface{}{
+			"time_off_request_id": request.ID,
+			"status":              request.Status,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to send time off notification for request %s: %v", request.ID, err)
+	}
+
+	return request, nil
+}
+
+func (s *AvailabilityServiceImpl) getTimeOff(ctx context.Context, requestID uuid.UUID) (*domain.TimeOffRequest, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	request, err := s.availabilityRepo.GetTimeOff(ctx, tenantID, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time off request: %w", err)
+	}
+	if request == nil {
+		return nil, fmt.Errorf("time off request not found")
+	}
+
+	return request, nil
+}
+
+func (s *AvailabilityServiceImpl) checkUser(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return uuid.Nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, tenantID, userID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return uuid.Nil, fmt.Errorf("user not found")
+	}
+
+	return tenantID, nil
+}
+
+func (s *AvailabilityServiceImpl) logAvailabilityAction(ctx context.Context, action, resourceType string, resourceID uuid.UUID, oldValues, newValues map[string]interface{}) {
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   &resourceID,
+		OldValues:    oldValues,
+		NewValues:    newValues,
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+}
+
+// holidayOn returns the holiday falling on the day, if any
+func holidayOn(holidays []*domain.TenantHoliday, day time.Time) *domain.TenantHoliday {
+	date := day.Format("2006-01-02")
+	for _, holiday := range holidays {
+		if holiday.Date.Format("2006-01-02") == date {
+			return holiday
+		}
+	}
+	return nil
+}
+
+func effectiveRangesOverlap(a, b WorkingHoursRequest) bool {
+	if a.EffectiveTo != nil && b.EffectiveFrom != nil && a.EffectiveTo.Before(*b.EffectiveFrom) {
+		return false
+	}
+	if b.EffectiveTo != nil && a.EffectiveFrom != nil && b.EffectiveTo.Before(*a.EffectiveFrom) {
+		return false
+	}
+	return true
+}
+
+func clipTimeRange(r, bounds TimeRange) (TimeRange, bool) {
+	if r.Start.Before(bounds.Start) {
+		r.Start = bounds.Start
+	}
+	if r.End.After(bounds.End) {
+		r.End = bounds.End
+	}
+	return r, r.Start.Before(r.End)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,1231 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// BookingService provides online self-scheduling: bookable arrival windows computed
+// from crew availability, short-lived holds, and confirmation into a tentative job.
+// Public methods take the tenant from the request so they can serve embedded widgets.
+type BookingService interface {
+	// Staff configuration
+	GetSettings(ctx context.Context) (*domain.BookingSettings, error)
+	UpdateSettings(ctx context.Context, req *BookingSettingsRequest) (*domain.BookingSettings, error)
+	ListBlackouts(ctx context.Context, from, to time.Time) ([]*domain.BookingBlackout, error)
+	CreateBlackout(ctx context.Context, req *BookingBlackoutRequest) (*domain.BookingBlackout, error)
+	DeleteBlackout(ctx context.Context, blackoutID uuid.UUID) error
+
+	// Public self-scheduling
+	IsEmbedOriginAllowed(ctx context.Context, tenantID uuid.UUID, origin string) (bool, error)
+	GetAvailableSlots(ctx context.Context, req *BookingSlotRequest) (*BookingSlotsResponse, error)
+	HoldSlot(ctx context.Context, req *BookingHoldRequest) (*BookingHoldResult, error)
+	ReleaseHold(ctx context.Context, req *BookingHoldReference) error
+	ConfirmBooking(ctx context.Context, req *BookingConfirmRequest) (*BookingConfirmation, error)
+
+	// Background processing
+	ProcessExpiredHolds(ctx context.Context, now time.Time) error
+}
+
+// BookingRepository defines data access for online booking
+type BookingRepository interface {
+	GetSettings(ctx context.Context, tenantID uuid.UUID) (*domain.BookingSettings, error)
+	UpsertSettings(ctx context.Context, settings *domain.BookingSettings) error
+
+	ListBlackouts(ctx context.Context, tenantID uuid.UUID, from, to time.Time) ([]*domain.BookingBlackout, error)
+	CreateBlackout(ctx context.Context, blackout *domain.BookingBlackout) error
+	DeleteBlackout(ctx context.Context, tenantID, blackoutID uuid.UUID) error
+
+	// CreateHold stores the hold unless an active hold for the same crew overlaps it,
+	// returning false in that case
+	CreateHold(ctx context.Context, hold *domain.BookingHold, now time.Time) (bool, error)
+	GetHold(ctx context.Context, tenantID, holdID uuid.UUID) (*domain.BookingHold, error)
+	UpdateHold(ctx context.Context, hold *domain.BookingHold) error
+	ListActiveHolds(ctx context.Context, tenantID uuid.UUID, from, to, now time.Time) ([]*domain.BookingHold, error)
+	// ExpireHolds marks lapsed holds expired across all tenants
+	ExpireHolds(ctx context.Context, now time.Time) (int, error)
+}
+
+// BookingSettingsRequest updates booking settings; unset fields are left unchanged
+type BookingSettingsRequest struct {
+	Enabled                *bool    `json:"enabled,omitempty"`
+	Timezone               *string  `json:"timezone,omitempty"`
+	WorkdayStart           *string  `json:"workday_start,omitempty"`
+	WorkdayEnd             *string  `json:"workday_end,omitempty"`
+	WorkingDays            []int    `json:"working_days,omitempty"`
+	ArrivalWindowMinutes   *int     `json:"arrival_window_minutes,omitempty"`
+	DefaultDurationMinutes *int     `json:"default_duration_minutes,omitempty"`
+	LeadTimeHours          *int     `json:"lead_time_hours,omitempty"`
+	MaxDaysAhead           *int     `json:"max_days_ahead,omitempty"`
+	HoldMinutes            *int     `json:"hold_minutes,omitempty"`
+	MaxJobsPerCrewDay      *int     `json:"max_jobs_per_crew_day,omitempty"`
+	TravelSpeedMph         *float64 `json:"travel_speed_mph,omitempty"`
+	DefaultTravelMinutes   *int     `json:"default_travel_minutes,omitempty"`
+	AllowedOrigins         []string `json:"allowed_origins,omitempty"`
+}
+
+// BookingBlackoutRequest closes a day to online booking
+type BookingBlackoutRequest struct {
+	Date   time.Time  `json:"date"`
+	CrewID *uuid.UUID `json:"crew_id,omitempty"` // all crews when empty
+	Reason *string    `json:"reason,omitempty"`
+}
+
+// BookingSlotRequest asks for bookable arrival windows. The location is used to
+// account for travel from the crew's other jobs that day.
+type BookingSlotRequest struct {
+	TenantID        uuid.UUID  `json:"tenant_id"`
+	From            time.Time  `json:"from"` // first day; defaults to today
+	To              time.Time  `json:"to"`   // last day; defaults to a week after From
+	ServiceID       *uuid.UUID `json:"service_id,omitempty"`
+	DurationMinutes int        `json:"duration_minutes,omitempty"` // defaults to the service duration
+	Latitude        *float64   `json:"latitude,omitempty"`
+	Longitude       *float64   `json:"longitude,omitempty"`
+}
+
+// BookingSlotsResponse lists bookable windows in the tenant's timezone
+type BookingSlotsResponse struct {
+	Timezone             string        `json:"timezone"`
+	ArrivalWindowMinutes int           `json:"arrival_window_minutes"`
+	DurationMinutes      int           `json:"duration_minutes"`
+	HoldMinutes          int           `json:"hold_minutes"`
+	Slots                []BookingSlot `json:"slots"`
+}
+
+// BookingSlot is an arrival window. CrewIDs lists the crews that can take it, best
+// fit first, and isn't shown to visitors.
+type BookingSlot struct {
+	Start         time.Time   `json:"start"`
+	End           time.Time   `json:"end"`
+	Available     int         `json:"available"`
+	TravelMinutes int         `json:"-"`
+	CrewIDs       []uuid.UUID `json:"-"`
+}
+
+// BookingHoldRequest holds the arrival window starting at Start
+type BookingHoldRequest struct {
+	TenantID        uuid.UUID `json:"tenant_id"`
+	Start           time.Time `json:"start"`
+	ServiceID       uuid.UUID `json:"service_id"`
+	ServiceKey      string    `json:"service_key,omitempty"`
+	DurationMinutes int       `json:"duration_minutes,omitempty"`
+	Latitude        *float64  `json:"latitude,omitempty"`
+	Longitude       *float64  `json:"longitude,omitempty"`
+	IPAddress       string    `json:"-"`
+}
+
+// BookingHoldResult is a new hold and the token needed to confirm or release it
+type BookingHoldResult struct {
+	Hold  *domain.BookingHold `json:"hold"`
+	Token string              `json:"token"`
+}
+
+// BookingHoldReference identifies a hold from a public request
+type BookingHoldReference struct {
+	TenantID uuid.UUID `json:"tenant_id"`
+	HoldID   uuid.UUID `json:"hold_id"`
+	Token    string    `json:"token"`
+}
+
+// BookingConfirmRequest completes a booking with the visitor's details
+type BookingConfirmRequest struct {
+	BookingHoldReference
+	Contact LeadSubmission `json:"contact"`
+}
+
+// BookingConfirmation is the result of a confirmed booking
+type BookingConfirmation struct {
+	Hold  *domain.BookingHold `json:"hold"`
+	Lead  *domain.Lead        `json:"lead"`
+	Job   *domain.EnhancedJob `json:"job"`
+	Quote *domain.Quote       `json:"quote,omitempty"`
+}
+
+// BookingCrewDay is one crew's day as input to slot generation. Free is when the
+// crew is available (nil for the whole workday) and Stops are its existing jobs.
+type BookingCrewDay struct {
+	CrewID   uuid.UUID
+	Date     time.Time
+	Capacity int // jobs per day; the settings default when zero
+	Free     []TimeRange
+	Stops    []BookingStop
+}
+
+// BookingStop is a scheduled visit that occupies a crew
+type BookingStop struct {
+	Start    time.Time
+	End      time.Time
+	Location *Location
+}
+
+// BookingSlotInput is everything GenerateBookingSlots needs
+type BookingSlotInput struct {
+	Settings  *domain.BookingSettings
+	CrewDays  []BookingCrewDay
+	Holds     []*domain.BookingHold
+	Blackouts []*domain.BookingBlackout
+	Duration  time.Duration
+	Target    *Location
+	Now       time.Time
+}
+
+// DefaultBookingSettings returns the settings used until a tenant saves its own.
+// Online booking starts disabled.
+func DefaultBookingSettings(tenantID uuid.UUID) *domain.BookingSettings {
+	now := time.Now()
+	return &domain.BookingSettings{
+		TenantID:               tenantID,
+		Enabled:                false,
+		Timezone:               "UTC",
+		WorkdayStart:           "08:00",
+		WorkdayEnd:             "17:00",
+		WorkingDays:            []int{1, 2, 3, 4, 5},
+		ArrivalWindowMinutes:   120,
+		DefaultDurationMinutes: 60,
+		LeadTimeHours:          24,
+		MaxDaysAhead:           30,
+		HoldMinutes:            10,
+		MaxJobsPerCrewDay:      6,
+		TravelSpeedMph:         25,
+		DefaultTravelMinutes:   20,
+		AllowedOrigins:         []string{},
+		CreatedAt:              now,
+		UpdatedAt:              now,
+	}
+}
+
+// bookingServiceImpl implements BookingService
+type bookingServiceImpl struct {
+	bookingRepo     BookingRepository
+	scheduleService ScheduleService
+	crewService     CrewService
+	jobRepo         JobRepositoryComplete
+	propertyRepo    PropertyRepositoryExtended
+	serviceRepo     ServiceRepository
+	leadService     LeadService
+	auditService    AuditService
+	logger          *log.Logger
+}
+
+// NewBookingService creates a new online booking service
+func NewBookingService(
+	bookingRepo BookingRepository,
+	scheduleService ScheduleService,
+	crewService CrewService,
+	jobRepo JobRepositoryComplete,
+	propertyRepo PropertyRepositoryExtended,
+	serviceRepo ServiceRepository,
+	leadService LeadService,
+	auditService AuditService,
+	logger *log.Logger,
+) BookingService {
+	return &bookingServiceImpl{
+		bookingRepo:     bookingRepo,
+		scheduleService: scheduleService,
+		crewService:     crewService,
+		jobRepo:         jobRepo,
+		propertyRepo:    propertyRepo,
+		serviceRepo:     serviceRepo,
+		leadService:     leadService,
+		auditService:    auditService,
+		logger:          logger,
+	}
+}
+
+// GetSettings returns the tenant's booking settings
+func (s *bookingServiceImpl) GetSettings(ctx context.Context) (*domain.BookingSettings, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+	return s.settings(ctx, tenantID)
+}
+
+// UpdateSettings saves the tenant's booking settings
+func (s *bookingServiceImpl) UpdateSettings(ctx context.Context, req *BookingSettingsRequest) (*domain.BookingSettings, error) {
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	oldEnabled := settings.Enabled
+
+	if req.Enabled != nil {
+		settings.Enabled = *req.Enabled
+	}
+	if req.Timezone != nil {
+		settings.Timezone = *req.Timezone
+	}
+	if req.WorkdayStart != nil {
+		settings.WorkdayStart = *req.WorkdayStart
+	}
+	if req.WorkdayEnd != nil {
+		settings.WorkdayEnd = *req.WorkdayEnd
+	}
+	if req.WorkingDays != nil {
+		settings.WorkingDays = req.WorkingDays
+	}
+	if req.ArrivalWindowMinutes != nil {
+		settings.ArrivalWindowMinutes = *req.ArrivalWindowMinutes
+	}
+	if req.DefaultDurationMinutes != nil {
+		settings.DefaultDurationMinutes = *req.DefaultDurationMinutes
+	}
+	if req.LeadTimeHours != nil {
+		settings.LeadTimeHours = *req.LeadTimeHours
+	}
+	if req.MaxDaysAhead != nil {
+		settings.MaxDaysAhead = *req.MaxDaysAhead
+	}
+	if req.HoldMinutes != nil {
+		settings.HoldMinutes = *req.HoldMinutes
+	}
+	if req.MaxJobsPerCrewDay != nil {
+		settings.MaxJobsPerCrewDay = *req.MaxJobsPerCrewDay
+	}
+	if req.TravelSpeedMph != nil {
+		settings.TravelSpeedMph = *req.TravelSpeedMph
+	}
+	if req.DefaultTravelMinutes != nil {
+		settings.DefaultTravelMinutes = *req.DefaultTravelMinutes
+	}
+	if req.AllowedOrigins != nil {
+		settings.AllowedOrigins = make([]string, 0, len(req.AllowedOrigins))
+		for _, origin := range req.AllowedOrigins {
+			if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+				settings.AllowedOrigins = append(settings.AllowedOrigins, origin)
+			}
+		}
+	}
+	settings.UpdatedAt = time.Now()
+
+	if err := validateBookingSettings(settings); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := s.bookingRepo.UpsertSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to save booking settings: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "booking_settings.update",
+		ResourceType: "booking_settings",
+		ResourceID:   &settings.TenantID,
+		OldValues:    map[string]interface{}{"enabled": oldEnabled},
+		NewValues: map[string]interface{}{
+			"enabled":                settings.Enabled,
+			"arrival_window_minutes": settings.ArrivalWindowMinutes,
+			"hold_minutes":           settings.HoldMinutes,
+			"allowed_origins":        settings.AllowedOrigins,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return settings, nil
+}
+
+// ListBlackouts lists blackout days in a date range
+func (s *bookingServiceImpl) ListBlackouts(ctx context.Context, from, to time.Time) ([]*domain.BookingBlackout, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	blackouts, err := s.bookingRepo.ListBlackouts(ctx, tenantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blackout days: %w", err)
+	}
+
+	return blackouts, nil
+}
+
+// CreateBlackout closes a day to online booking. Existing jobs are not affected.
+func (s *bookingServiceImpl) CreateBlackout(ctx context.Context, req *BookingBlackoutRequest) (*domain.BookingBlackout, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+	if req.Date.IsZero() {
+		return nil, fmt.Errorf("validation failed: date is required")
+	}
+
+	blackout := &domain.BookingBlackout{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Date:      time.Date(req.Date.Year(), req.Date.Month(), req.Date.Day(), 0, 0, 0, 0, time.UTC),
+		CrewID:    req.CrewID,
+		Reason:    req.Reason,
+		CreatedBy: GetUserIDFromContext(ctx),
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.bookingRepo.CreateBlackout(ctx, blackout); err != nil {
+		return nil, fmt.Errorf("failed to create blackout day: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       blackout.CreatedBy,
+		Action:       "booking_blackout.create",
+		ResourceType: "booking_blackout",
+		ResourceID:   &blackout.ID,
+		NewValues: map[string]interface{}{
+			"date":    blackout.Date.Format("2006-01-02"),
+			"crew_id": blackout.CrewID,
+			"reason":  blackout.Reason,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return blackout, nil
+}
+
+// DeleteBlackout reopens a blackout day
+func (s *bookingServiceImpl) DeleteBlackout(ctx context.Context, blackoutID uuid.UUID) error {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("tenant ID not found in context")
+	}
+
+	if err := s.bookingRepo.DeleteBlackout(ctx, tenantID, blackoutID); err != nil {
+		return fmt.Errorf("failed to delete blackout day: %w", err)
+	}
+
+	return nil
+}
+
+// IsEmbedOriginAllowed reports whether a site may call the booking API from the browser
+func (s *bookingServiceImpl) IsEmbedOriginAllowed(ctx context.Context, tenantID uuid.UUID, origin string) (bool, error) {
+	settings, err := s.settings(ctx, tenantID)
+	if err != nil {
+		return false, err
+	}
+	return settings.Enabled && BookingOriginAllowed(settings, origin), nil
+}
+
+// GetAvailableSlots returns bookable arrival windows
+func (s *bookingServiceImpl) GetAvailableSlots(ctx context.Context, req *BookingSlotRequest) (*BookingSlotsResponse, error) {
+	ctx, tenantID, err := bookingTenantContext(ctx, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.enabledSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	duration, err := s.bookingDuration(ctx, tenantID, settings, req.ServiceID, req.DurationMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	slots, err := s.availableSlots(ctx, tenantID, settings, req.From, req.To, duration, bookingTarget(req.Latitude, req.Longitude))
+	if err != nil {
+		return nil, err
+	}
+
+	return &BookingSlotsResponse{
+		Timezone:             settings.Timezone,
+		ArrivalWindowMinutes: settings.ArrivalWindowMinutes,
+		DurationMinutes:      int(duration.Minutes()),
+		HoldMinutes:          settings.HoldMinutes,
+		Slots:                slots,
+	}, nil
+}
+
+// HoldSlot reserves an arrival window for the hold period, giving it to the best-fit
+// crew that is still free
+func (s *bookingServiceImpl) HoldSlot(ctx context.Context, req *BookingHoldRequest) (*BookingHoldResult, error) {
+	ctx, tenantID, err := bookingTenantContext(ctx, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if req.ServiceID == uuid.Nil {
+		return nil, fmt.Errorf("validation failed: service is required")
+	}
+
+	settings, err := s.enabledSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	duration, err := s.bookingDuration(ctx, tenantID, settings, &req.ServiceID, req.DurationMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	target := bookingTarget(req.Latitude, req.Longitude)
+	slots, err := s.availableSlots(ctx, tenantID, settings, req.Start, req.Start, duration, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var slot *BookingSlot
+	for i := range slots {
+		if slots[i].Start.Equal(req.Start) {
+			slot = &slots[i]
+			break
+		}
+	}
+	if slot == nil {
+		return nil, fmt.Errorf("that arrival window is no longer available")
+	}
+
+	token, err := generatePortalToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	hold := &domain.BookingHold{
+		ID:              uuid.New(),
+		TenantID:        tenantID,
+		WindowStart:     slot.Start,
+		WindowEnd:       slot.End,
+		JobStart:        slot.Start,
+		JobEnd:          slot.Start.Add(duration),
+		ServiceID:       &req.ServiceID,
+		ServiceKey:      optionalString(strings.TrimSpace(req.ServiceKey)),
+		DurationMinutes: int(duration.Minutes()),
+		Latitude:        req.Latitude,
+		Longitude:       req.Longitude,
+		Token:           HashPortalToken(token),
+		Status:          domain.BookingHoldStatusHeld,
+		ExpiresAt:       now.Add(time.Duration(settings.HoldMinutes) * time.Minute),
+		IPAddress:       optionalString(req.IPAddress),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	// Another visitor may have taken a crew since the slots were computed
+	for _, crewID := range slot.CrewIDs {
+		hold.CrewID = crewID
+		created, err := s.bookingRepo.CreateHold(ctx, hold, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hold arrival window: %w", err)
+		}
+		if created {
+			return &BookingHoldResult{Hold: hold, Token: token}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("that arrival window is no longer available")
+}
+
+// ReleaseHold gives up a hold before it expires
+func (s *bookingServiceImpl) ReleaseHold(ctx context.Context, req *BookingHoldReference) error {
+	ctx, tenantID, err := bookingTenantContext(ctx, req.TenantID)
+	if err != nil {
+		return err
+	}
+
+	hold, err := s.verifiedHold(ctx, tenantID, req)
+	if err != nil {
+		return err
+	}
+	if hold.Status != domain.BookingHoldStatusHeld {
+		return nil
+	}
+
+	hold.Status = domain.BookingHoldStatusReleased
+	hold.UpdatedAt = time.Now()
+	if err := s.bookingRepo.UpdateHold(ctx, hold); err != nil {
+		return fmt.Errorf("failed to release hold: %w", err)
+	}
+
+	return nil
+}
+
+// ConfirmBooking turns a hold into a tentative job. The visitor's details go through
+// lead screening; the lead is converted to a customer, property and draft quote, and
+// the job is created pending staff confirmation.
+func (s *bookingServiceImpl) ConfirmBooking(ctx context.Context, req *BookingConfirmRequest) (*BookingConfirmation, error) {
+	ctx, tenantID, err := bookingTenantContext(ctx, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err := s.verifiedHold(ctx, tenantID, &req.BookingHoldReference)
+	if err != nil {
+		return nil, err
+	}
+	if !hold.IsActive(time.Now()) {
+		return nil, fmt.Errorf("the hold on this arrival window has expired; please choose another")
+	}
+
+	settings, err := s.settings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lead intake, with the same spam screening as the booking form
+	submission := req.Contact
+	submission.TenantID = tenantID
+	submission.Source = domain.LeadSourceOnlineBooking
+	submission.ServiceID = hold.ServiceID
+	if hold.ServiceKey != nil {
+		submission.ServiceKey = *hold.ServiceKey
+	}
+	if submission.IPAddress == "" && hold.IPAddress != nil {
+		submission.IPAddress = *hold.IPAddress
+	}
+
+	lead, err := s.leadService.SubmitLead(ctx, &submission)
+	if err != nil {
+		return nil, err
+	}
+	if lead.Status == domain.LeadStatusSpam {
+		s.releaseHold(ctx, hold)
+		return nil, fmt.Errorf("we couldn't complete this booking online; please contact us")
+	}
+
+	conversion, err := s.leadService.AcceptLead(ctx, lead.ID, &LeadAcceptRequest{ServiceID: hold.ServiceID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert booking: %w", err)
+	}
+
+	// Tentative job for the crew's lead; pending until staff confirm it
+	duration := hold.DurationMinutes
+	scheduledTime := hold.JobStart.In(bookingLocation(settings)).Format("15:04")
+	title := "Online booking for " + customerDisplayName(conversion.Customer)
+	if hold.ServiceKey != nil && *hold.ServiceKey != "" {
+		title = leadServiceLabel(*hold.ServiceKey) + " for " + customerDisplayName(conversion.Customer)
+	}
+	notes := "Booked online. Arrival window " + FormatArrivalWindow(hold.WindowStart, hold.WindowEnd, bookingLocation(settings))
+
+	now := time.Now()
+	job := &domain.EnhancedJob{
+		Job: domain.Job{
+			ID:                uuid.New(),
+			TenantID:          tenantID,
+			CustomerID:        conversion.Customer.ID,
+			PropertyID:        conversion.Property.ID,
+			AssignedUserID:    s.crewLead(ctx, hold.CrewID),
+			Title:             title,
+			Description:       lead.Message,
+			Status:            domain.JobStatusPending,
+			Priority:          "medium",
+			ScheduledDate:     &hold.JobStart,
+			ScheduledTime:     &scheduledTime,
+			EstimatedDuration: &duration,
+			Notes:             &notes,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		},
+		CrewSize: 1,
+	}
+	if conversion.Quote != nil {
+		job.QuoteID = &conversion.Quote.ID
+		total := conversion.Quote.TotalAmount
+		job.TotalAmount = &total
+	}
+
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	hold.Status = domain.BookingHoldStatusConfirmed
+	hold.LeadID = &lead.ID
+	hold.JobID = &job.ID
+	hold.UpdatedAt = now
+	if err := s.bookingRepo.UpdateHold(ctx, hold); err != nil {
+		s.logger.Printf("Failed to confirm booking hold %s: %v", hold.ID, err)
+	}
+
+	if updated, err := s.leadService.UpdateLeadStatus(ctx, lead.ID, domain.LeadStatusScheduled); err != nil {
+		s.logger.Printf("Failed to mark lead %s scheduled: %v", lead.ID, err)
+	} else {
+		lead = updated
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		Action:       "booking.confirm",
+		ResourceType: "job",
+		ResourceID:   &job.ID,
+		NewValues: map[string]interface{}{
+			"hold_id":      hold.ID,
+			"lead_id":      lead.ID,
+			"crew_id":      hold.CrewID,
+			"window_start": hold.WindowStart,
+			"window_end":   hold.WindowEnd,
+		},
+		IPAddress: hold.IPAddress,
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return &BookingConfirmation{Hold: hold, Lead: lead, Job: job, Quote: conversion.Quote}, nil
+}
+
+// ProcessExpiredHolds marks lapsed holds expired. Availability already ignores them;
+// this keeps the table tidy. It is called periodically by the worker.
+func (s *bookingServiceImpl) ProcessExpiredHolds(ctx context.Context, now time.Time) error {
+	expired, err := s.bookingRepo.ExpireHolds(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to expire booking holds: %w", err)
+	}
+	if expired > 0 {
+		s.logger.Printf("Expired %d booking holds", expired)
+	}
+	return nil
+}
+
+// GenerateBookingSlots computes arrival windows from crew availability. A window is
+// offered when the job fits in a crew's free time with travel from the previous stop
+// and to the next one, the crew is under its daily capacity, and the day is a
+// working day that isn't blacked out. Windows are merged across crews.
+func GenerateBookingSlots(input *BookingSlotInput) []BookingSlot {
+	settings := input.Settings
+	loc := bookingLocation(settings)
+	now := input.Now.In(loc)
+	earliest := now.Add(time.Duration(settings.LeadTimeHours) * time.Hour)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	lastDay := today.AddDate(0, 0, settings.MaxDaysAhead)
+	window := time.Duration(settings.ArrivalWindowMinutes) * time.Minute
+	if window <= 0 || input.Duration <= 0 {
+		return []BookingSlot{}
+	}
+
+	type candidate struct {
+		crewID uuid.UUID
+		travel int
+	}
+	byStart := map[time.Time][]candidate{}
+	ends := map[time.Time]time.Time{}
+
+	for _, crewDay := range input.CrewDays {
+		local := crewDay.Date.In(loc)
+		day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		if day.Before(today) || day.After(lastDay) {
+			continue
+		}
+		if !bookingWorkingDay(settings, day.Weekday()) || bookingBlackedOut(input.Blackouts, day, crewDay.CrewID) {
+			continue
+		}
+
+		workStart := bookingClock(day, settings.WorkdayStart, 8*60)
+		workEnd := bookingClock(day, settings.WorkdayEnd, 17*60)
+
+		stops := append([]BookingStop{}, crewDay.Stops...)
+		for _, hold := range input.Holds {
+			if hold.CrewID == crewDay.CrewID && hold.IsActive(input.Now) && hold.JobStart.Before(workEnd) && hold.JobEnd.After(workStart) {
+				stops = append(stops, BookingStop{Start: hold.JobStart, End: hold.JobEnd, Location: bookingTarget(hold.Latitude, hold.Longitude)})
+			}
+		}
+		sort.Slice(stops, func(i, j int) bool { return stops[i].Start.Before(stops[j].Start) })
+
+		capacity := crewDay.Capacity
+		if capacity <= 0 {
+			capacity = settings.MaxJobsPerCrewDay
+		}
+		if len(stops) >= capacity {
+			continue
+		}
+
+		for start := workStart; !start.Add(input.Duration).After(workEnd); start = start.Add(window) {
+			if start.Before(earliest) {
+				continue
+			}
+			end := start.Add(input.Duration)
+			if !bookingWithinFree(crewDay.Free, start, end) {
+				continue
+			}
+			travel, ok := bookingFitsBetweenStops(stops, start, end, input.Target, settings)
+			if !ok {
+				continue
+			}
+
+			windowEnd := start.Add(window)
+			if windowEnd.After(workEnd) {
+				windowEnd = workEnd
+			}
+			byStart[start] = append(byStart[start], candidate{crewID: crewDay.CrewID, travel: travel})
+			ends[start] = windowEnd
+		}
+	}
+
+	slots := make([]BookingSlot, 0, len(byStart))
+	for start, candidates := range byStart {
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].travel < candidates[j].travel })
+		slot := BookingSlot{Start: start, End: ends[start], Available: len(candidates), TravelMinutes: candidates[0].travel}
+		for _, c := range candidates {
+			slot.CrewIDs = append(slot.CrewIDs, c.crewID)
+		}
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Start.Before(slots[j].Start) })
+
+	return slots
+}
+
+// BookingTravelMinutes estimates driving time between two locations, using the
+// default when either is unknown
+func BookingTravelMinutes(from, to *Location, settings *domain.BookingSettings) int {
+	if from == nil || to == nil || settings.TravelSpeedMph <= 0 {
+		return settings.DefaultTravelMinutes
+	}
+	miles := haversineDistance(from.Latitude, from.Longitude, to.Latitude, to.Longitude)
+	return int(math.Ceil(miles / settings.TravelSpeedMph * 60))
+}
+
+// BookingOriginAllowed reports whether an embedding site's origin is allowed
+func BookingOriginAllowed(settings *domain.BookingSettings, origin string) bool {
+	origin = strings.TrimRight(origin, "/")
+	if origin == "" {
+		return false
+	}
+	for _, allowed := range settings.AllowedOrigins {
+		if strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatArrivalWindow formats a window like "Tue Jun 2, 8:00 AM - 10:00 AM"
+func FormatArrivalWindow(start, end time.Time, loc *time.Location) string {
+	return start.In(loc).Format("Mon Jan 2, 3:04 PM") + " - " + end.In(loc).Format("3:04 PM")
+}
+
+// Helper functions
+
+func (s *bookingServiceImpl) settings(ctx context.Context, tenantID uuid.UUID) (*domain.BookingSettings, error) {
+	settings, err := s.bookingRepo.GetSettings(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking settings: %w", err)
+	}
+	if settings == nil {
+		settings = DefaultBookingSettings(tenantID)
+	}
+	return settings, nil
+}
+
+func (s *bookingServiceImpl) enabledSettings(ctx context.Context, tenantID uuid.UUID) (*domain.BookingSettings, error) {
+	settings, err := s.settings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Enabled {
+		return nil, fmt.Errorf("online booking is not available")
+	}
+	return settings, nil
+}
+
+// bookingDuration is the requested duration, else the service's, else the default
+func (s *bookingServiceImpl) bookingDuration(ctx context.Context, tenantID uuid.UUID, settings *domain.BookingSettings, serviceID *uuid.UUID, minutes int) (time.Duration, error) {
+	if minutes <= 0 && serviceID != nil {
+		service, err := s.serviceRepo.GetByID(ctx, tenantID, *serviceID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get service: %w", err)
+		}
+		if service == nil {
+			return 0, fmt.Errorf("service not found")
+		}
+		if service.DurationMinutes != nil {
+			minutes = *service.DurationMinutes
+		}
+	}
+	if minutes <= 0 {
+		minutes = settings.DefaultDurationMinutes
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
+
+// availableSlots gathers each crew's day from the scheduler and job list and
+// generates the windows between from and to (inclusive days)
+func (s *bookingServiceImpl) availableSlots(ctx context.Context, tenantID uuid.UUID, settings *domain.BookingSettings, from, to time.Time, duration time.Duration, target *Location) ([]BookingSlot, error) {
+	loc := bookingLocation(settings)
+	now := time.Now()
+	today := time.Date(now.In(loc).Year(), now.In(loc).Month(), now.In(loc).Day(), 0, 0, 0, 0, loc)
+
+	if from.IsZero() || from.Before(today) {
+		from = today
+	}
+	from = time.Date(from.In(loc).Year(), from.In(loc).Month(), from.In(loc).Day(), 0, 0, 0, 0, loc)
+	if to.IsZero() {
+		to = from.AddDate(0, 0, 6)
+	}
+	if lastDay := today.AddDate(0, 0, settings.MaxDaysAhead); to.After(lastDay) {
+		to = lastDay
+	}
+	if to.Before(from) {
+		return []BookingSlot{}, nil
+	}
+
+	blackouts, err := s.bookingRepo.ListBlackouts(ctx, tenantID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blackout days: %w", err)
+	}
+	holds, err := s.bookingRepo.ListActiveHolds(ctx, tenantID, from, to.AddDate(0, 0, 1), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list booking holds: %w", err)
+	}
+
+	locations := map[uuid.UUID]*Location{}
+	var crewDays []BookingCrewDay
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if !bookingWorkingDay(settings, day.Weekday()) || bookingBlackedOut(blackouts, day, uuid.Nil) {
+			continue
+		}
+		workStart := bookingClock(day, settings.WorkdayStart, 8*60)
+		workEnd := bookingClock(day, settings.WorkdayEnd, 17*60)
+
+		crews, err := s.crewService.GetAvailableCrews(ctx, workStart, workEnd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get available crews: %w", err)
+		}
+		if len(crews) == 0 {
+			continue
+		}
+
+		jobs, err := s.jobRepo.GetByDateRange(ctx, tenantID, workStart, workEnd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get jobs: %w", err)
+		}
+
+		for _, crew := range crews {
+			crewDay, err := s.crewDay(ctx, tenantID, crew, day, TimeRange{Start: workStart, End: workEnd}, jobs, locations)
+			if err != nil {
+				s.logger.Printf("Failed to check availability for crew %s: %v", crew.ID, err)
+				continue
+			}
+			crewDays = append(crewDays, *crewDay)
+		}
+	}
+
+	return GenerateBookingSlots(&BookingSlotInput{
+		Settings:  settings,
+		CrewDays:  crewDays,
+		Holds:     holds,
+		Blackouts: blackouts,
+		Duration:  duration,
+		Target:    target,
+		Now:       now,
+	}), nil
+}
+
+// crewDay builds a crew's free time from CheckAvailability for its members and the
+// crew, and its stops from jobs assigned to its members
+func (s *bookingServiceImpl) crewDay(ctx context.Context, tenantID uuid.UUID, crew *domain.Crew, day time.Time, workday TimeRange, jobs []*domain.EnhancedJob, locations map[uuid.UUID]*Location) (*BookingCrewDay, error) {
+	members, err := s.crewService.GetCrewMembers(ctx, crew.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get crew members: %w", err)
+	}
+	memberIDs := activeCrewMemberIDs(members)
+
+	availability, err := s.scheduleService.CheckAvailability(ctx, &AvailabilityRequest{
+		UserIDs:   memberIDs,
+		CrewIDs:   []uuid.UUID{crew.ID},
+		TimeRange: workday,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check availability: %w", err)
+	}
+
+	crewDay := &BookingCrewDay{
+		CrewID:   crew.ID,
+		Date:     day,
+		Capacity: crew.Capacity,
+		Free:     crewFreeTime(availability.AvailableSlots, crew.ID, memberIDs),
+	}
+
+	for _, job := range jobs {
+		if job.ScheduledDate == nil || job.AssignedUserID == nil || !containsUUID(memberIDs, *job.AssignedUserID) {
+			continue
+		}
+		if job.Status == domain.JobStatusCancelled || job.Status == domain.JobStatusCompleted {
+			continue
+		}
+		minutes := 120
+		if job.EstimatedDuration != nil {
+			minutes = *job.EstimatedDuration
+		}
+		crewDay.Stops = append(crewDay.Stops, BookingStop{
+			Start:    *job.ScheduledDate,
+			End:      job.ScheduledDate.Add(time.Duration(minutes) * time.Minute),
+			Location: s.propertyLocation(ctx, tenantID, job.PropertyID, locations),
+		})
+	}
+
+	return crewDay, nil
+}
+
+func (s *bookingServiceImpl) propertyLocation(ctx context.Context, tenantID, propertyID uuid.UUID, cache map[uuid.UUID]*Location) *Location {
+	if location, ok := cache[propertyID]; ok {
+		return location
+	}
+
+	var location *Location
+	property, err := s.propertyRepo.GetByID(ctx, tenantID, propertyID)
+	if err != nil {
+		s.logger.Printf("Failed to get property %s for travel time: %v", propertyID, err)
+	} else if property != nil {
+		location = bookingTarget(property.Latitude, property.Longitude)
+	}
+	cache[propertyID] = location
+	return location
+}
+
+// crewLead picks the member a booked job is assigned to: the crew lead if there is
+// one, otherwise the longest-serving member
+func (s *bookingServiceImpl) crewLead(ctx context.Context, crewID uuid.UUID) *uuid.UUID {
+	members, err := s.crewService.GetCrewMembers(ctx, crewID)
+	if err != nil {
+		s.logger.Printf("Failed to get members of crew %s: %v", crewID, err)
+		return nil
+	}
+
+	var lead *CrewMemberDetails
+	for _, member := range members {
+		if member.LeftAt != nil {
+			continue
+		}
+		if strings.EqualFold(member.Role, "lead") || strings.EqualFold(member.Role, "leader") || strings.EqualFold(member.Role, "foreman") {
+			return &member.UserID
+		}
+		if lead == nil || member.JoinedAt.Before(lead.JoinedAt) {
+			lead = member
+		}
+	}
+	if lead == nil {
+		return nil
+	}
+	return &lead.UserID
+}
+
+func (s *bookingServiceImpl) verifiedHold(ctx context.Context, tenantID uuid.UUID, ref *BookingHoldReference) (*domain.BookingHold, error) {
+	hold, err := s.bookingRepo.GetHold(ctx, tenantID, ref.HoldID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+	if hold == nil || ref.Token == "" || hold.Token != HashPortalToken(ref.Token) {
+		return nil, fmt.Errorf("hold not found")
+	}
+	if hold.Status == domain.BookingHoldStatusConfirmed {
+		return nil, fmt.Errorf("this booking has already been confirmed")
+	}
+	return hold, nil
+}
+
+func (s *bookingServiceImpl) releaseHold(ctx context.Context, hold *domain.BookingHold) {
+	hold.Status = domain.BookingHoldStatusReleased
+	hold.UpdatedAt = time.Now()
+	if err := s.bookingRepo.UpdateHold(ctx, hold); err != nil {
+		s.logger.Printf("Failed to release booking hold %s: %v", hold.ID, err)
+	}
+}
+
+func bookingTenantContext(ctx context.Context, tenantID uuid.UUID) (context.Context, uuid.UUID, error) {
+	if contextTenantID, ok := GetTenantIDFromContext(ctx); ok {
+		return ctx, contextTenantID, nil
+	}
+	if tenantID == uuid.Nil {
+		return ctx, uuid.Nil, fmt.Errorf("tenant ID not found in context")
+	}
+	return context.WithValue(ctx, "tenant_id", tenantID), tenantID, nil
+}
+
+func validateBookingSettings(settings *domain.BookingSettings) error {
+	if _, err := time.LoadLocation(settings.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone: %s", settings.Timezone)
+	}
+	start, ok := parseBookingClock(settings.WorkdayStart)
+	if !ok {
+		return fmt.Errorf("workday start must be HH:MM")
+	}
+	end, ok := parseBookingClock(settings.WorkdayEnd)
+	if !ok {
+		return fmt.Errorf("workday end must be HH:MM")
+	}
+	if end <= start {
+		return fmt.Errorf("workday end must be after workday start")
+	}
+	for _, day := range settings.WorkingDays {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("working days must be 0 (Sunday) to 6 (Saturday)")
+		}
+	}
+	if settings.ArrivalWindowMinutes <= 0 || settings.DefaultDurationMinutes <= 0 || settings.HoldMinutes <= 0 {
+		return fmt.Errorf("arrival window, default duration and hold time must be positive")
+	}
+	if settings.MaxDaysAhead <= 0 || settings.MaxJobsPerCrewDay <= 0 {
+		return fmt.Errorf("max days ahead and max jobs per crew day must be positive")
+	}
+	if settings.LeadTimeHours < 0 || settings.DefaultTravelMinutes < 0 || settings.TravelSpeedMph <= 0 {
+		return fmt.Errorf("lead time and travel settings must not be negative")
+	}
+	return nil
+}
+
+func bookingLocation(settings *domain.BookingSettings) *time.Location {
+	if loc, err := time.LoadLocation(settings.Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+func bookingTarget(latitude, longitude *float64) *Location {
+	if latitude == nil || longitude == nil {
+		return nil
+	}
+	return &Location{Latitude: *latitude, Longitude: *longitude}
+}
+
+// parseBookingClock parses "HH:MM" into minutes after midnight
+func parseBookingClock(value string) (int, bool) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+func bookingClock(day time.Time, value string, fallback int) time.Time {
+	minutes, ok := parseBookingClock(value)
+	if !ok {
+		minutes = fallback
+	}
+	return day.Add(time.Duration(minutes) * time.Minute)
+}
+
+func bookingWorkingDay(settings *domain.BookingSettings, weekday time.Weekday) bool {
+	for _, day := range settings.WorkingDays {
+		if time.Weekday(day) == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// bookingBlackedOut reports whether a day is closed for the crew; uuid.Nil only
+// matches tenant-wide blackouts
+func bookingBlackedOut(blackouts []*domain.BookingBlackout, day time.Time, crewID uuid.UUID) bool {
+	date := day.Format("2006-01-02")
+	for _, blackout := range blackouts {
+		if blackout.Date.Format("2006-01-02") != date {
+			continue
+		}
+		if blackout.CrewID == nil || *blackout.CrewID == crewID {
+			return true
+		}
+	}
+	return false
+}
+
+func bookingWithinFree(free []TimeRange, start, end time.Time) bool {
+	if free == nil {
+		return true
+	}
+	for _, r := range free {
+		if !start.Before(r.Start) && !end.After(r.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// bookingFitsBetweenStops checks the job leaves time to travel from the previous stop
+// and on to the next, returning the travel minutes from the previous stop
+func bookingFitsBetweenStops(stops []BookingStop, start, end time.Time, target *Location, settings *domain.BookingSettings) (int, bool) {
+	var previous *BookingStop
+	for i := range stops {
+		stop := &stops[i]
+		switch {
+		case !stop.End.After(start):
+			if previous == nil || stop.End.After(previous.End) {
+				previous = stop
+			}
+		case !stop.Start.Before(end):
+			travel := time.Duration(BookingTravelMinutes(target, stop.Location, settings)) * time.Minute
+			if end.Add(travel).After(stop.Start) {
+				return 0, false
+			}
+		default:
+			return 0, false
+		}
+	}
+
+	if previous == nil {
+		return 0, true
+	}
+	travel := BookingTravelMinutes(previous.Location, target, settings)
+	if previous.End.Add(time.Duration(travel) * time.Minute).After(start) {
+		return 0, false
+	}
+	return travel, true
+}
+
+func activeCrewMemberIDs(members []*CrewMemberDetails) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		if member.LeftAt == nil {
+			ids = append(ids, member.UserID)
+		}
+	}
+	return ids
+}
+
+// crewFreeTime intersects the free time of the crew and every member, so a window
+// is offered only when the whole crew is available
+func crewFreeTime(slots []AvailabilitySlot, crewID uuid.UUID, memberIDs []uuid.UUID) []TimeRange {
+	var free []TimeRange
+	first := true
+
+	intersectWith := func(ranges []TimeRange) {
+		if first {
+			free = ranges
+			first = false
+			return
+		}
+		var result []TimeRange
+		for _, a := range free {
+			for _, b := range ranges {
+				start, end := a.Start, a.End
+				if b.Start.After(start) {
+					start = b.Start
+				}
+				if b.End.Before(end) {
+					end = b.End
+				}
+				if start.Before(end) {
+					result = append(result, TimeRange{Start: start, End: end})
+				}
+			}
+		}
+		free = result
+	}
+
+	var crewRanges []TimeRange
+	for _, slot := range slots {
+		if slot.CrewID != nil && *slot.CrewID == crewID {
+			crewRanges = append(crewRanges, TimeRange{Start: slot.StartTime, End: slot.EndTime})
+		}
+	}
+	intersectWith(crewRanges)
+
+	for _, memberID := range memberIDs {
+		var memberRanges []TimeRange
+		for _, slot := range slots {
+			if slot.UserID != nil && *slot.UserID == memberID {
+				memberRanges = append(memberRanges, TimeRange{Start: slot.StartTime, End: slot.EndTime})
+			}
+		}
+		intersectWith(memberRanges)
+	}
+
+	if free == nil {
+		return []TimeRange{}
+	}
+	return free
+}
@@ -0,0 +1,712 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// CertificationService manages the licences and certifications users hold,
+// such as pesticide applicator licences and CDLs, and the certifications
+// services require of the workers assigned to them
+type CertificationService interface {
+	AddCertification(ctx context.Context, userID uuid.UUID, req *CertificationRequest) (*domain.UserCertification, error)
+	UpdateCertification(ctx context.Context, certificationID uuid.UUID, req *CertificationRequest) (*domain.UserCertification, error)
+	DeleteCertification(ctx context.Context, certificationID uuid.UUID) error
+	ListUserCertifications(ctx context.Context, userID uuid.UUID) ([]*domain.UserCertification, error)
+	ListExpiringCertifications(ctx context.Context, withinDays int) ([]*domain.UserCertification, error)
+
+	UploadCertificationDocument(ctx context.Context, certificationID uuid.UUID, document *CertificationDocumentUpload) (*domain.UserCertification, error)
+	GetCertificationDocumentURL(ctx context.Context, certificationID uuid.UUID) (string, error)
+
+	GetServiceRequirements(ctx context.Context, serviceID uuid.UUID) ([]*domain.ServiceCertificationRequirement, error)
+	SetServiceRequirements(ctx context.Context, serviceID uuid.UUID, requirements []ServiceRequirementRequest) ([]*domain.ServiceCertificationRequirement, error)
+
+	// ProcessExpiryReminders reminds users, and whoever recorded their
+	// certification, as it approaches expiry and when it lapses
+	ProcessExpiryReminders(ctx context.Context, now time.Time) error
+}
+
+// CertificationRepository defines data access for user certifications and
+// service certification requirements
+type CertificationRepository interface {
+	CreateCertification(ctx context.Context, certification *domain.UserCertification) error
+	GetCertification(ctx context.Context, tenantID, certificationID uuid.UUID) (*domain.UserCertification, error)
+	UpdateCertification(ctx context.Context, certification *domain.UserCertification) error
+	DeleteCertification(ctx context.Context, tenantID, certificationID uuid.UUID) error
+	ListCertifications(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) ([]*domain.UserCertification, error)
+	ListExpiringCertifications(ctx context.Context, tenantID uuid.UUID, before time.Time) ([]*domain.UserCertification, error)
+
+	// ListCertificationsForReminder returns certifications across all tenants
+	// expiring before the date that have not had their expired reminder
+	ListCertificationsForReminder(ctx context.Context, before time.Time) ([]*domain.UserCertification, error)
+	MarkReminderSent(ctx context.Context, tenantID, certificationID uuid.UUID, days int) error
+
+	CreateAttachment(ctx context.Context, attachment *domain.FileAttachment) error
+	GetAttachment(ctx context.Context, tenantID, attachmentID uuid.UUID) (*domain.FileAttachment, error)
+
+	ListServiceRequirements(ctx context.Context, tenantID uuid.UUID, serviceIDs []uuid.UUID) ([]*domain.ServiceCertificationRequirement, error)
+
+	// ReplaceServiceRequirements swaps a service's requirements for a new set in one transaction
+	ReplaceServiceRequirements(ctx context.Context, tenantID, serviceID uuid.UUID, requirements []*domain.ServiceCertificationRequirement) error
+}
+
+// CertificationReminderDays are the days before expiry a reminder goes out.
+// A final reminder goes out once the certification has expired.
+var CertificationReminderDays = []int{60, 30, 7}
+
+// certificationDocumentURLExpiry is how long a certification document link stays valid
+const certificationDocumentURLExpiry = 15 * time.Minute
+
+// CertificationRequest records or updates a certification
+type CertificationRequest struct {
+	CertificationType string     `json:"certification_type" validate:"required"`
+	Name              *string    `json:"name,omitempty"`
+	LicenseNumber     *string    `json:"license_number,omitempty"`
+	IssuingAuthority  *string    `json:"issuing_authority,omitempty"`
+	IssuedOn          *time.Time `json:"issued_on,omitempty"`
+	ExpiresOn         *time.Time `json:"expires_on,omitempty"`
+}
+
+// CertificationDocumentUpload is a scan or photo of a certification
+type CertificationDocumentUpload struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// ServiceRequirementRequest declares a certification a service requires
+type ServiceRequirementRequest struct {
+	CertificationType string `json:"certification_type" validate:"required"`
+	Enforcement       string `json:"enforcement,omitempty"` // defaults to block
+}
+
+// QualificationGap is a certification an assignment is missing
+type QualificationGap struct {
+	CertificationType string      `json:"certification_type"`
+	Enforcement       string      `json:"enforcement"`
+	Reason            string      `json:"reason"` // missing or expired
+	UserIDs           []uuid.UUID `json:"user_ids,omitempty"`
+	ServiceIDs        []uuid.UUID `json:"service_ids"`
+}
+
+// QualificationCheck is whether the workers assigned to a job hold the
+// certifications its services require. Blocking gaps refuse the assignment;
+// warnings let it through.
+type QualificationCheck struct {
+	JobID     uuid.UUID          `json:"job_id"`
+	WorkDate  time.Time          `json:"work_date"`
+	Qualified bool               `json:"qualified"`
+	Gaps      []QualificationGap `json:"gaps"`
+	Warnings  []QualificationGap `json:"warnings"`
+}
+
+// Qualification gap reasons
+const (
+	QualificationMissing = "missing"
+	QualificationExpired = "expired"
+)
+
+// CertificationServiceImpl implements CertificationService
+type CertificationServiceImpl struct {
+	certificationRepo   CertificationRepository
+	userRepo            UserRepository
+	serviceRepo         ServiceRepository
+	storageService      StorageService
+	notificationService NotificationService
+	auditService        AuditService
+	logger              *log.Logger
+}
+
+// NewCertificationService creates a new certification service
+func NewCertificationService(
+	certificationRepo CertificationRepository,
+	userRepo UserRepository,
+	serviceRepo ServiceRepository,
+	storageService StorageService,
+	notificationService NotificationService,
+	auditService AuditService,
+	logger *log.Logger,
+) CertificationService {
+	return &CertificationServiceImpl{
+		certificationRepo:   certificationRepo,
+		userRepo:            userRepo,
+		serviceRepo:         serviceRepo,
+		storageService:      storageService,
+		notificationService: notificationService,
+		auditService:        auditService,
+		logger:              logger,
+	}
+}
+
+// AddCertification records a certification held by a user
+func (s *CertificationServiceImpl) AddCertification(ctx context.Context, userID uuid.UUID, req *CertificationRequest) (*domain.UserCertification, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if err := ValidateCertificationRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, tenantID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	now := time.Now()
+	certification := &domain.UserCertification{
+		ID:                uuid.New(),
+		TenantID:          tenantID,
+		UserID:            userID,
+		CertificationType: req.CertificationType,
+		Name:              req.Name,
+		LicenseNumber:     req.LicenseNumber,
+		IssuingAuthority:  req.IssuingAuthority,
+		IssuedOn:          req.IssuedOn,
+		ExpiresOn:         req.ExpiresOn,
+		CreatedBy:         GetUserIDFromContext(ctx),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	if err := s.certificationRepo.CreateCertification(ctx, certification); err != nil {
+		return nil, fmt.Errorf("failed to create certification: %w", err)
+	}
+
+	s.logCertificationAction(ctx, "certification.create", certification.ID, nil, map[string]interface{}{
+		"user_id":            userID,
+		"certification_type": certification.CertificationType,
+		"expires_on":         certification.ExpiresOn,
+	})
+
+	return certification, nil
+}
+
+// UpdateCertification updates a certification. Renewing it with a new expiry
+// date starts its reminders over.
+func (s *CertificationServiceImpl) UpdateCertification(ctx context.Context, certificationID uuid.UUID, req *CertificationRequest) (*domain.UserCertification, error) {
+	certification, err := s.getCertification(ctx, certificationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateCertificationRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	oldExpiresOn := certification.ExpiresOn
+	if !sameDate(oldExpiresOn, req.ExpiresOn) {
+		certification.ReminderDaysSent = nil
+	}
+
+	certification.CertificationType = req.CertificationType
+	certification.Name = req.Name
+	certification.LicenseNumber = req.LicenseNumber
+	certification.IssuingAuthority = req.IssuingAuthority
+	certification.IssuedOn = req.IssuedOn
+	certification.ExpiresOn = req.ExpiresOn
+	certification.UpdatedAt = time.Now()
+
+	if err := s.certificationRepo.UpdateCertification(ctx, certification); err != nil {
+		return nil, fmt.Errorf("failed to update certification: %w", err)
+	}
+
+	s.logCertificationAction(ctx, "certification.update", certification.ID,
+		map[string]interface{}{"expires_on": oldExpiresOn},
+		map[string]interface{}{"expires_on": certification.ExpiresOn})
+
+	return certification, nil
+}
+
+// DeleteCertification removes a certification
+func (s *CertificationServiceImpl) DeleteCertification(ctx context.Context, certificationID uuid.UUID) error {
+	certification, err := s.getCertification(ctx, certificationID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.certificationRepo.DeleteCertification(ctx, certification.TenantID, certification.ID); err != nil {
+		return fmt.Errorf("failed to delete certification: %w", err)
+	}
+
+	s.logCertificationAction(ctx, "certification.delete", certification.ID, map[string]interface{}{
+		"user_id":            certification.UserID,
+		"certification_type": certification.CertificationType,
+	}, nil)
+
+	return nil
+}
+
+// ListUserCertifications lists the certifications a user holds
+func (s *CertificationServiceImpl) ListUserCertifications(ctx context.Context, userID uuid.UUID) ([]*domain.UserCertification, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	certifications, err := s.certificationRepo.ListCertifications(ctx, tenantID, []uuid.UUID{userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list certifications: %w", err)
+	}
+
+	for _, certification := range certifications {
+		if certification.DocumentAttachmentID == nil {
+			continue
+		}
+		if certification.Document, err = s.certificationRepo.GetAttachment(ctx, tenantID, *certification.DocumentAttachmentID); err != nil {
+			return nil, fmt.Errorf("failed to get certification document: %w", err)
+		}
+	}
+
+	return certifications, nil
+}
+
+// ListExpiringCertifications lists certifications that have expired or will
+// within the number of days, 30 by default, soonest first
+func (s *CertificationServiceImpl) ListExpiringCertifications(ctx context.Context, withinDays int) ([]*domain.UserCertification, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if withinDays <= 0 {
+		withinDays = 30
+	}
+
+	certifications, err := s.certificationRepo.ListExpiringCertifications(ctx, tenantID, time.Now().AddDate(0, 0, withinDays))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expiring certifications: %w", err)
+	}
+
+	return certifications, nil
+}
+
+// UploadCertificationDocument stores a scan or photo of the certification,
+// replacing any earlier document
+func (s *CertificationServiceImpl) UploadCertificationDocument(ctx context.Context, certificationID uuid.UUID, document *CertificationDocumentUpload) (*domain.UserCertification, error) {
+	certification, err := s.getCertification(ctx, certificationID)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := document.ContentType
+	if contentType != "application/pdf" && !strings.HasPrefix(contentType, "image/") {
+		return nil, fmt.Errorf("validation failed: document must be a PDF or image")
+	}
+	if len(document.Data) == 0 {
+		return nil, fmt.Errorf("validation failed: document is empty")
+	}
+
+	originalName := path.Base(document.Filename)
+	if originalName == "." || originalName == "/" {
+		originalName = "certification"
+	}
+	attachmentID := uuid.New()
+	fileName := attachmentID.String() + strings.ToLower(path.Ext(originalName))
+	storagePath := fmt.Sprintf("users/%s/certifications/%s/%s", certification.UserID, certification.ID, fileName)
+
+	if _, err := s.storageService.Upload(ctx, storagePath, document.Data, contentType); err != nil {
+		return nil, fmt.Errorf("failed to upload certification document: %w", err)
+	}
+
+	attachment := &domain.FileAttachment{
+		ID:               attachmentID,
+		TenantID:         certification.TenantID,
+		EntityType:       domain.AttachmentEntityUserCertification,
+		EntityID:         certification.ID,
+		Filename:         fileName,
+		OriginalFilename: originalName,
+		FileSize:         int64(len(document.Data)),
+		ContentType:      contentType,
+		StoragePath:      storagePath,
+		UploadedBy:       GetUserIDFromContext(ctx),
+		CreatedAt:        time.Now(),
+	}
+	if err := s.certificationRepo.CreateAttachment(ctx, attachment); err != nil {
+		return nil, fmt.Errorf("failed to save certification document: %w", err)
+	}
+
+	certification.DocumentAttachmentID = &attachment.ID
+	certification.UpdatedAt = time.Now()
+	if err := s.certificationRepo.UpdateCertification(ctx, certification); err != nil {
+		return nil, fmt.Errorf("failed to update certification: %w", err)
+	}
+	certification.Document = attachment
+
+	s.logCertificationAction(ctx, "certification.document_upload", certification.ID, nil, map[string]interface{}{
+		"attachment_id": attachment.ID,
+		"filename":      originalName,
+	})
+
+	return certification, nil
+}
+
+// GetCertificationDocumentURL returns a short-lived link to the certification's document
+func (s *CertificationServiceImpl) GetCertificationDocumentURL(ctx context.Context, certificationID uuid.UUID) (string, error) {
+	certification, err := s.getCertification(ctx, certificationID)
+	if err != nil {
+		return "", err
+	}
+	if certification.DocumentAttachmentID == nil {
+		return "", fmt.Errorf("certification document not found")
+	}
+
+	attachment, err := s.certificationRepo.GetAttachment(ctx, certification.TenantID, *certification.DocumentAttachmentID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get certification document: %w", err)
+	}
+	if attachment == nil {
+		return "", fmt.Errorf("certification document not found")
+	}
+
+	url, err := s.storageService.GetSignedURL(ctx, attachment.StoragePath, certificationDocumentURLExpiry)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign certification document URL: %w", err)
+	}
+
+	return url, nil
+}
+
+// GetServiceRequirements lists the certifications a service requires
+func (s *CertificationServiceImpl) GetServiceRequirements(ctx context.Context, serviceID uuid.UUID) ([]*domain.ServiceCertificationRequirement, error) {
+	tenantID, err := s.checkService(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	requirements, err := s.certificationRepo.ListServiceRequirements(ctx, tenantID, []uuid.UUID{serviceID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service requirements: %w", err)
+	}
+
+	return requirements, nil
+}
+
+// SetServiceRequirements replaces the certifications a service requires
+func (s *CertificationServiceImpl) SetServiceRequirements(ctx context.Context, serviceID uuid.UUID, requests []ServiceRequirementRequest) ([]*domain.ServiceCertificationRequirement, error) {
+	tenantID, err := s.checkService(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	requirements := make([]*domain.ServiceCertificationRequirement, 0, len(requests))
+	seen := make(map[string]bool, len(requests))
+	for _, req := range requests {
+		if !containsString(domain.CertificationTypes, req.CertificationType) {
+			return nil, fmt.Errorf("validation failed: unknown certification type %q", req.CertificationType)
+		}
+		if seen[req.CertificationType] {
+			return nil, fmt.Errorf("validation failed: %s is listed more than once", req.CertificationType)
+		}
+		seen[req.CertificationType] = true
+
+		enforcement := req.Enforcement
+		if enforcement == "" {
+			enforcement = domain.CertificationEnforcementBlock
+		}
+		if enforcement != domain.CertificationEnforcementBlock && enforcement != domain.CertificationEnforcementWarn {
+			return nil, fmt.Errorf("validation failed: unknown enforcement %q", req.Enforcement)
+		}
+
+		requirements = append(requirements, &domain.ServiceCertificationRequirement{
+			TenantID:          tenantID,
+			ServiceID:         serviceID,
+			CertificationType: req.CertificationType,
+			Enforcement:       enforcement,
+			CreatedAt:         now,
+		})
+	}
+
+	if err := s.certificationRepo.ReplaceServiceRequirements(ctx, tenantID, serviceID, requirements); err != nil {
+		return nil, fmt.Errorf("failed to save service requirements: %w", err)
+	}
+
+	s.logCertificationAction(ctx, "service.certification_requirements", serviceID, nil, map[string]interface{}{
+		"requirements": requirements,
+	})
+
+	return requirements, nil
+}
+
+// ProcessExpiryReminders sends each due certification reminder once
+func (s *CertificationServiceImpl) ProcessExpiryReminders(ctx context.Context, now time.Time) error {
+	horizon := now.AddDate(0, 0, CertificationReminderDays[0]+1)
+	certifications, err := s.certificationRepo.ListCertificationsForReminder(ctx, horizon)
+	if err != nil {
+		return fmt.Errorf("failed to list certifications for reminder: %w", err)
+	}
+
+	for _, certification := range certifications {
+		days, due := CertificationReminderDue(certification, now)
+		if !due {
+			continue
+		}
+
+		tenantCtx := context.WithValue(ctx, "tenant_id", certification.TenantID)
+
+		if err := s.sendExpiryReminder(tenantCtx, certification, days, now); err != nil {
+			s.logger.Printf("Failed to send expiry reminder for certification %s: %v", certification.ID, err)
+			continue
+		}
+
+		if err := s.certificationRepo.MarkReminderSent(tenantCtx, certification.TenantID, certification.ID, days); err != nil {
+			s.logger.Printf("Failed to mark reminder sent for certification %s: %v", certification.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateCertificationRequest checks the certification type and dates
+func ValidateCertificationRequest(req *CertificationRequest) error {
+	if !containsString(domain.CertificationTypes, req.CertificationType) {
+		return fmt.Errorf("unknown certification type %q", req.CertificationType)
+	}
+	if req.IssuedOn != nil && req.ExpiresOn != nil && req.ExpiresOn.Before(*req.IssuedOn) {
+		return fmt.Errorf("expiry date is before the issue date")
+	}
+	return nil
+}
+
+// CertificationReminderDue returns the reminder threshold a certification has
+// reached, in days before expiry or 0 once expired, and whether that reminder
+// is still to be sent
+func CertificationReminderDue(certification *domain.UserCertification, now time.Time) (int, bool) {
+	if certification.ExpiresOn == nil {
+		return 0, false
+	}
+
+	daysLeft := daysBetween(now, *certification.ExpiresOn)
+	if daysLeft > CertificationReminderDays[0] {
+		return 0, false
+	}
+
+	threshold := 0
+	if daysLeft >= 0 {
+		for _, days := range CertificationReminderDays {
+			if daysLeft <= days {
+				threshold = days
+			}
+		}
+	}
+
+	sent := certification.ReminderDaysSent
+	return threshold, sent == nil || threshold < *sent
+}
+
+// CheckQualifications checks workers against the certifications a job's
+// services require, as of the work date. Each worker must hold every required
+// certification; for a crew, at least one member must. Where services disagree
+// on enforcement for the same certification the strictest wins.
+func CheckQualifications(requirements []*domain.ServiceCertificationRequirement, workerIDs []uuid.UUID, certifications []*domain.UserCertification, workDate time.Time, crew bool) ([]QualificationGap, []QualificationGap) {
+	type requirement struct {
+		enforcement string
+		serviceIDs  []uuid.UUID
+	}
+	required := make(map[string]*requirement)
+	var types []string
+	for _, req := range requirements {
+		r, ok := required[req.CertificationType]
+		if !ok {
+			r = &requirement{enforcement: req.Enforcement}
+			required[req.CertificationType] = r
+			types = append(types, req.CertificationType)
+		}
+		if req.Enforcement == domain.CertificationEnforcementBlock {
+			r.enforcement = domain.CertificationEnforcementBlock
+		}
+		r.serviceIDs = append(r.serviceIDs, req.ServiceID)
+	}
+	sort.Strings(types)
+
+	// held[type][user] is true when the user's certification is current
+	held := make(map[string]map[uuid.UUID]bool)
+	for _, certification := range certifications {
+		if held[certification.CertificationType] == nil {
+			held[certification.CertificationType] = make(map[uuid.UUID]bool)
+		}
+		if certification.IsValidOn(workDate) {
+			held[certification.CertificationType][certification.UserID] = true
+		} else if _, ok := held[certification.CertificationType][certification.UserID]; !ok {
+			held[certification.CertificationType][certification.UserID] = false
+		}
+	}
+
+	gaps := []QualificationGap{}
+	warnings := []QualificationGap{}
+	for _, certificationType := range types {
+		r := required[certificationType]
+
+		var expired, missing []uuid.UUID
+		qualified := 0
+		for _, workerID := range workerIDs {
+			valid, ok := held[certificationType][workerID]
+			switch {
+			case valid:
+				qualified++
+			case ok:
+				expired = append(expired, workerID)
+			default:
+				missing = append(missing, workerID)
+			}
+		}
+
+		if (crew && qualified > 0) || (!crew && qualified == len(workerIDs) && len(workerIDs) > 0) {
+			continue
+		}
+
+		gap := QualificationGap{
+			CertificationType: certificationType,
+			Enforcement:       r.enforcement,
+			Reason:            QualificationMissing,
+			UserIDs:           append(expired, missing...),
+			ServiceIDs:        r.serviceIDs,
+		}
+		if len(expired) > 0 && len(missing) == 0 {
+			gap.Reason = QualificationExpired
+		}
+		if crew {
+			// No member holds it, so there is no one to name
+			gap.UserIDs = nil
+		}
+
+		if r.enforcement == domain.CertificationEnforcementBlock {
+			gaps = append(gaps, gap)
+		} else {
+			warnings = append(warnings, gap)
+		}
+	}
+
+	return gaps, warnings
+}
+
+// DescribeQualificationGaps summarizes gaps for an error or notification
+func DescribeQualificationGaps(gaps []QualificationGap) string {
+	parts := make([]string, len(gaps))
+	for i, gap := range gaps {
+		parts[i] = fmt.Sprintf("%s %s", strings.ReplaceAll(gap.CertificationType, "_", " "), gap.Reason)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (s *CertificationServiceImpl) getCertification(ctx context.Context, certificationID uuid.UUID) (*domain.UserCertification, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	certification, err := s.certificationRepo.GetCertification(ctx, tenantID, certificationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get certification: %w", err)
+	}
+	if certification == nil {
+		return nil, fmt.Errorf("certification not found")
+	}
+
+	return certification, nil
+}
+
+func (s *CertificationServiceImpl) checkService(ctx context.Context, serviceID uuid.UUID) (uuid.UUID, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return uuid.Nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	service, err := s.serviceRepo.GetByID(ctx, tenantID, serviceID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	if service == nil {
+		return uuid.Nil, fmt.Errorf("service not found")
+	}
+
+	return tenantID, nil
+}
+
+func (s *CertificationServiceImpl) sendExpiryReminder(ctx context.Context, certification *domain.UserCertification, days int, now time.Time) error {
+	name := stringValue(certification.Name)
+	if name == "" {
+		name = strings.ReplaceAll(certification.CertificationType, "_", " ")
+	}
+
+	notificationType := "certification.expiring"
+	title := "Certification Expiring"
+	message := fmt.Sprintf("Your %s expires on %s (%d days)", name, certification.ExpiresOn.Format("Jan 2, 2006"), daysBetween(now, *certification.ExpiresOn))
+	if days == 0 {
+		notificationType = "certification.expired"
+		title = "Certification Expired"
+		message = fmt.Sprintf("Your %s expired on %s", name, certification.ExpiresOn.Format("Jan 2, 2006"))
+	}
+	data := map[string]interface{}{
+		"certification_id":   certification.ID,
+		"certification_type": certification.CertificationType,
+		"user_id":            certification.UserID,
+		"expires_on":         certification.ExpiresOn,
+	}
+
+	if err := s.notificationService.SendNotification(ctx, &NotificationRequest{
+		UserID:  &certification.UserID,
+		Type:    notificationType,
+		Title:   title,
+		Message: message,
+		Data:    data,
+	}); err != nil {
+		return err
+	}
+
+	// Whoever recorded the certification keeps track of it too
+	if certification.CreatedBy != nil && *certification.CreatedBy != certification.UserID {
+		if err := s.notificationService.SendNotification(ctx, &NotificationRequest{
+			UserID:  certification.CreatedBy,
+			Type:    notificationType,
+			Title:   title,
+			Message: fmt.Sprintf("An employee's %s expires on %s", name, certification.ExpiresOn.Format("Jan 2, 2006")),
+			Data:    data,
+		}); err != nil {
+			s.logger.Printf("Failed to send expiry reminder for certification %s to %s: %v", certification.ID, *certification.CreatedBy, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *CertificationServiceImpl) logCertificationAction(ctx context.Context, action string, resourceID uuid.UUID, oldValues, newValues map[string]interface{}) {
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       action,
+		ResourceType: "user_certification",
+		ResourceID:   &resourceID,
+		OldValues:    oldValues,
+		NewValues:    newValues,
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+}
+
+// daysBetween counts the calendar days from now to the date, negative once it has passed
+func daysBetween(now, date time.Time) int {
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
@@ -0,0 +1,1075 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// CollectionsService handles accounts receivable aging, overdue reminders, late fees and delinquency
+type CollectionsService interface {
+	// Reporting
+	GetARAgingReport(ctx context.Context, asOf time.Time) (*ARAgingReport, error)
+
+	// Policy
+	GetCollectionPolicy(ctx context.Context) (*domain.CollectionPolicy, error)
+	UpdateCollectionPolicy(ctx context.Context, req *CollectionPolicyRequest) (*domain.CollectionPolicy, error)
+
+	// Automated collections
+	SendDueReminders(ctx context.Context, asOf time.Time) (int, error)
+	ApplyLateFees(ctx context.Context, asOf time.Time) ([]*domain.InvoiceLateFee, error)
+	WaiveLateFee(ctx context.Context, feeID uuid.UUID, reason string) error
+	EnforceSchedulingSuspensions(ctx context.Context, asOf time.Time) (*SuspensionResult, error)
+	ProcessCollections(ctx context.Context, asOf time.Time) error
+
+	// Collections notes and promises to pay
+	AddCollectionNote(ctx context.Context, req *CollectionNoteRequest) (*domain.CollectionNote, error)
+	ListCollectionNotes(ctx context.Context, customerID uuid.UUID) ([]*domain.CollectionNote, error)
+	CreatePaymentPromise(ctx context.Context, req *PaymentPromiseRequest) (*domain.PaymentPromise, error)
+	UpdatePaymentPromiseStatus(ctx context.Context, promiseID uuid.UUID, status string) (*domain.PaymentPromise, error)
+	ListPaymentPromises(ctx context.Context, filter *PaymentPromiseFilter) ([]*domain.PaymentPromise, error)
+
+	// Scheduling suspension
+	SuspendScheduling(ctx context.Context, customerID uuid.UUID, reason string) error
+	ResumeScheduling(ctx context.Context, customerID uuid.UUID) error
+}
+
+// CollectionsRepository defines data access for collections
+type CollectionsRepository interface {
+	// Policies
+	GetPolicy(ctx context.Context, tenantID uuid.UUID) (*domain.CollectionPolicy, error)
+	UpsertPolicy(ctx context.Context, policy *domain.CollectionPolicy) error
+	ListPolicies(ctx context.Context) ([]*domain.CollectionPolicy, error)
+
+	// Reminders
+	CreateReminder(ctx context.Context, reminder *domain.InvoiceReminder) error
+	GetReminders(ctx context.Context, tenantID, invoiceID uuid.UUID) ([]*domain.InvoiceReminder, error)
+
+	// Late fees
+	ApplyLateFee(ctx context.Context, fee *domain.InvoiceLateFee) error
+	GetLateFee(ctx context.Context, tenantID, feeID uuid.UUID) (*domain.InvoiceLateFee, error)
+	GetLateFees(ctx context.Context, tenantID, invoiceID uuid.UUID) ([]*domain.InvoiceLateFee, error)
+	WaiveLateFee(ctx context.Context, fee *domain.InvoiceLateFee) error
+
+	// Notes
+	CreateNote(ctx context.Context, note *domain.CollectionNote) error
+	ListNotes(ctx context.Context, tenantID, customerID uuid.UUID) ([]*domain.CollectionNote, error)
+
+	// Promises to pay
+	CreatePromise(ctx context.Context, promise *domain.PaymentPromise) error
+	UpdatePromise(ctx context.Context, promise *domain.PaymentPromise) error
+	GetPromise(ctx context.Context, tenantID, promiseID uuid.UUID) (*domain.PaymentPromise, error)
+	ListPromises(ctx context.Context, tenantID uuid.UUID, filter *PaymentPromiseFilter) ([]*domain.PaymentPromise, error)
+	ResolveExpiredPromises(ctx context.Context, tenantID uuid.UUID, asOf time.Time) (kept int, broken int, err error)
+
+	// Scheduling suspension
+	SetSchedulingSuspended(ctx context.Context, tenantID, customerID uuid.UUID, suspended bool, reason *string) error
+	GetSuspendedCustomers(ctx context.Context, tenantID uuid.UUID) (map[uuid.UUID]string, error)
+}
+
+// ARAgingReport is the tenant-wide receivables aging broken down by customer
+type ARAgingReport struct {
+	AsOf      time.Time               `json:"as_of"`
+	Totals    AgingBuckets            `json:"totals"`
+	Customers []*CustomerAgingSummary `json:"customers"`
+}
+
+// CustomerAgingSummary is one customer's row on the AR aging report
+type CustomerAgingSummary struct {
+	CustomerID          uuid.UUID    `json:"customer_id"`
+	CustomerName        string       `json:"customer_name"`
+	Buckets             AgingBuckets `json:"buckets"`
+	InvoiceCount        int          `json:"invoice_count"`
+	OldestDaysPastDue   int          `json:"oldest_days_past_due"`
+	SchedulingSuspended bool         `json:"scheduling_suspended"`
+}
+
+// CollectionPolicyRequest updates a tenant's collections policy
+type CollectionPolicyRequest struct {
+	RemindersEnabled         *bool                 `json:"reminders_enabled,omitempty"`
+	ReminderSteps            []domain.ReminderStep `json:"reminder_steps,omitempty"`
+	LateFeeEnabled           *bool                 `json:"late_fee_enabled,omitempty"`
+	LateFeeType              *string               `json:"late_fee_type,omitempty"`
+	LateFeeAmount            *float64              `json:"late_fee_amount,omitempty"`
+	LateFeeGraceDays         *int                  `json:"late_fee_grace_days,omitempty"`
+	LateFeeRepeatDays        *int                  `json:"late_fee_repeat_days,omitempty"`
+	LateFeeMaxApplications   *int                  `json:"late_fee_max_applications,omitempty"`
+	SuspendSchedulingEnabled *bool                 `json:"suspend_scheduling_enabled,omitempty"`
+	SuspendAfterDays         *int                  `json:"suspend_after_days,omitempty"`
+	SuspendMinBalance        *float64              `json:"suspend_min_balance,omitempty"`
+}
+
+// CollectionNoteRequest records a collections note
+type CollectionNoteRequest struct {
+	CustomerID uuid.UUID  `json:"customer_id" validate:"required"`
+	InvoiceID  *uuid.UUID `json:"invoice_id,omitempty"`
+	NoteType   string     `json:"note_type,omitempty"`
+	Content    string     `json:"content" validate:"required"`
+}
+
+// PaymentPromiseRequest records a promise to pay
+type PaymentPromiseRequest struct {
+	CustomerID   uuid.UUID  `json:"customer_id" validate:"required"`
+	InvoiceID    *uuid.UUID `json:"invoice_id,omitempty"`
+	Amount       float64    `json:"amount" validate:"required,gt=0"`
+	PromisedDate time.Time  `json:"promised_date" validate:"required"`
+	Notes        *string    `json:"notes,omitempty"`
+}
+
+// PaymentPromiseFilter filters promise-to-pay listings
+type PaymentPromiseFilter struct {
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"`
+	Status     string     `json:"status,omitempty"`
+}
+
+// SuspensionResult summarizes an automatic suspension pass
+type SuspensionResult struct {
+	Suspended []uuid.UUID `json:"suspended"`
+	Resumed   []uuid.UUID `json:"resumed"`
+}
+
+// autoSuspensionReason marks suspensions made by policy so they can be lifted automatically
+const autoSuspensionReason = "Automatically suspended: account past due"
+
+// reminderTemplate is a built-in overdue reminder template
+type reminderTemplate struct {
+	Subject string
+	Body    string
+}
+
+// defaultReminderTemplates escalate in tone from friendly to final notice
+var defaultReminderTemplates = map[string]reminderTemplate{
+	domain.ReminderTemplateFriendly: {
+		Subject: "Friendly reminder: invoice {{invoice_number}} is past due",
+		Body:    "Hi {{customer_name}},\n\nThis is a friendly reminder that invoice {{invoice_number}} for ${{balance}} was due on {{due_date}}. If you've already sent payment, thank you and please disregard this message.\n\nThank you for your business!",
+	},
+	domain.ReminderTemplateFirm: {
+		Subject: "Second notice: invoice {{invoice_number}} is {{days_overdue}} days overdue",
+		Body:    "Dear {{customer_name}},\n\nOur records show that invoice {{invoice_number}} with a balance of ${{balance}} is now {{days_overdue}} days past due. Please submit payment at your earliest convenience or contact us to discuss your account.",
+	},
+	domain.ReminderTemplateFinal: {
+		Subject: "Final notice: invoice {{invoice_number}} is seriously overdue",
+		Body:    "Dear {{customer_name}},\n\nInvoice {{invoice_number}} with a balance of ${{balance}} is {{days_overdue}} days past due. Please pay immediately to avoid late fees and suspension of scheduled services.",
+	},
+}
+
+// DefaultReminderSteps is the cadence used when a tenant has not configured one
+func DefaultReminderSteps() []domain.ReminderStep {
+	return []domain.ReminderStep{
+		{DaysOverdue: 3, Template: domain.ReminderTemplateFriendly},
+		{DaysOverdue: 10, Template: domain.ReminderTemplateFirm},
+		{DaysOverdue: 30, Template: domain.ReminderTemplateFinal},
+	}
+}
+
+// collectionsServiceImpl implements CollectionsService
+type collectionsServiceImpl struct {
+	collectionsRepo      CollectionsRepository
+	statementRepo        StatementRepository
+	invoiceRepo          InvoiceRepositoryFull
+	customerRepo         CustomerRepository
+	auditService         AuditService
+	communicationService CommunicationService
+	logger               *log.Logger
+}
+
+// NewCollectionsService creates a new collections service instance
+func NewCollectionsService(
+	collectionsRepo CollectionsRepository,
+	statementRepo StatementRepository,
+	invoiceRepo InvoiceRepositoryFull,
+	customerRepo CustomerRepository,
+	auditService AuditService,
+	communicationService CommunicationService,
+	logger *log.Logger,
+) CollectionsService {
+	return &collectionsServiceImpl{
+		collectionsRepo:      collectionsRepo,
+		statementRepo:        statementRepo,
+		invoiceRepo:          invoiceRepo,
+		customerRepo:         customerRepo,
+		auditService:         auditService,
+		communicationService: communicationService,
+		logger:               logger,
+	}
+}
+
+// GetARAgingReport builds the receivables aging report for the tenant
+func (s *collectionsServiceImpl) GetARAgingReport(ctx context.Context, asOf time.Time) (*ARAgingReport, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	openInvoices, err := s.statementRepo.GetOpenInvoices(ctx, tenantID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open invoices: %w", err)
+	}
+
+	report := BuildARAgingReport(openInvoices, asOf)
+
+	suspended, err := s.collectionsRepo.GetSuspendedCustomers(ctx, tenantID)
+	if err != nil {
+		s.logger.Printf("Failed to get suspended customers: %v", err)
+	}
+
+	for _, summary := range report.Customers {
+		_, summary.SchedulingSuspended = suspended[summary.CustomerID]
+		customer, err := s.customerRepo.GetByID(ctx, tenantID, summary.CustomerID)
+		if err != nil || customer == nil {
+			continue
+		}
+		summary.CustomerName = customerDisplayName(customer)
+	}
+
+	return report, nil
+}
+
+// BuildARAgingReport buckets open invoices per customer, largest balances first
+func BuildARAgingReport(openInvoices []*OpenInvoice, asOf time.Time) *ARAgingReport {
+	report := &ARAgingReport{AsOf: asOf}
+	byCustomer := make(map[uuid.UUID]*CustomerAgingSummary)
+
+	for _, open := range openInvoices {
+		balance := open.Balance()
+		if balance <= 0 {
+			continue
+		}
+
+		summary, exists := byCustomer[open.Invoice.CustomerID]
+		if !exists {
+			summary = &CustomerAgingSummary{CustomerID: open.Invoice.CustomerID}
+			byCustomer[open.Invoice.CustomerID] = summary
+			report.Customers = append(report.Customers, summary)
+		}
+
+		bucket, daysPastDue := AgingBucketFor(open.Invoice.DueDate, asOf)
+		summary.Buckets.Add(bucket, balance)
+		summary.InvoiceCount++
+		if daysPastDue > summary.OldestDaysPastDue {
+			summary.OldestDaysPastDue = daysPastDue
+		}
+		report.Totals.Add(bucket, balance)
+	}
+
+	sort.SliceStable(report.Customers, func(i, j int) bool {
+		return report.Customers[i].Buckets.Total > report.Customers[j].Buckets.Total
+	})
+
+	return report
+}
+
+// GetCollectionPolicy returns the tenant's collections policy, or defaults if none is saved
+func (s *collectionsServiceImpl) GetCollectionPolicy(ctx context.Context) (*domain.CollectionPolicy, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	policy, err := s.collectionsRepo.GetPolicy(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection policy: %w", err)
+	}
+	if policy == nil {
+		policy = &domain.CollectionPolicy{
+			ID:                     uuid.New(),
+			TenantID:               tenantID,
+			RemindersEnabled:       true,
+			ReminderSteps:          DefaultReminderSteps(),
+			LateFeeType:            domain.LateFeeTypeFlat,
+			LateFeeMaxApplications: 1,
+			SuspendAfterDays:       60,
+			CreatedAt:              time.Now(),
+			UpdatedAt:              time.Now(),
+		}
+	}
+
+	return policy, nil
+}
+
+// UpdateCollectionPolicy updates the tenant's collections policy
+func (s *collectionsServiceImpl) UpdateCollectionPolicy(ctx context.Context, req *CollectionPolicyRequest) (*domain.CollectionPolicy, error) {
+	policy, err := s.GetCollectionPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	oldValues := map[string]interface{}{
+		"reminder_steps":             policy.ReminderSteps,
+		"late_fee_enabled":           policy.LateFeeEnabled,
+		"late_fee_amount":            policy.LateFeeAmount,
+		"suspend_scheduling_enabled": policy.SuspendSchedulingEnabled,
+	}
+
+	if req.RemindersEnabled != nil {
+		policy.RemindersEnabled = *req.RemindersEnabled
+	}
+	if req.ReminderSteps != nil {
+		steps := append([]domain.ReminderStep(nil), req.ReminderSteps...)
+		sort.Slice(steps, func(i, j int) bool { return steps[i].DaysOverdue < steps[j].DaysOverdue })
+		for i, step := range steps {
+			if step.DaysOverdue < 0 {
+				return nil, fmt.Errorf("reminder days overdue cannot be negative")
+			}
+			if i > 0 && steps[i-1].DaysOverdue == step.DaysOverdue {
+				return nil, fmt.Errorf("duplicate reminder step at %d days", step.DaysOverdue)
+			}
+			if _, ok := defaultReminderTemplates[step.Template]; !ok && (step.Subject == nil || step.Body == nil) {
+				return nil, fmt.Errorf("reminder step at %d days needs a known template or a custom subject and body", step.DaysOverdue)
+			}
+		}
+		policy.ReminderSteps = steps
+	}
+	if req.LateFeeEnabled != nil {
+		policy.LateFeeEnabled = *req.LateFeeEnabled
+	}
+	if req.LateFeeType != nil {
+		if *req.LateFeeType != domain.LateFeeTypeFlat && *req.LateFeeType != domain.LateFeeTypePercentage {
+			return nil, fmt.Errorf("invalid late fee type: %s", *req.LateFeeType)
+		}
+		policy.LateFeeType = *req.LateFeeType
+	}
+	if req.LateFeeAmount != nil {
+		if *req.LateFeeAmount < 0 {
+			return nil, fmt.Errorf("late fee amount cannot be negative")
+		}
+		policy.LateFeeAmount = *req.LateFeeAmount
+	}
+	if policy.LateFeeType == domain.LateFeeTypePercentage && policy.LateFeeAmount > 100 {
+		return nil, fmt.Errorf("percentage late fee cannot exceed 100")
+	}
+	if req.LateFeeGraceDays != nil {
+		if *req.LateFeeGraceDays < 0 {
+			return nil, fmt.Errorf("late fee grace days cannot be negative")
+		}
+		policy.LateFeeGraceDays = *req.LateFeeGraceDays
+	}
+	if req.LateFeeRepeatDays != nil {
+		if *req.LateFeeRepeatDays < 1 {
+			return nil, fmt.Errorf("late fee repeat days must be at least 1")
+		}
+		policy.LateFeeRepeatDays = *req.LateFeeRepeatDays
+	}
+	if req.LateFeeMaxApplications != nil {
+		if *req.LateFeeMaxApplications < 0 {
+			return nil, fmt.Errorf("late fee max applications cannot be negative")
+		}
+		policy.LateFeeMaxApplications = *req.LateFeeMaxApplications
+	}
+	if req.SuspendSchedulingEnabled != nil {
+		policy.SuspendSchedulingEnabled = *req.SuspendSchedulingEnabled
+	}
+	if req.SuspendAfterDays != nil {
+		if *req.SuspendAfterDays <= 0 {
+			return nil, fmt.Errorf("suspend after days must be positive")
+		}
+		policy.SuspendAfterDays = *req.SuspendAfterDays
+	}
+	if req.SuspendMinBalance != nil {
+		if *req.SuspendMinBalance < 0 {
+			return nil, fmt.Errorf("suspend min balance cannot be negative")
+		}
+		policy.SuspendMinBalance = *req.SuspendMinBalance
+	}
+	policy.UpdatedAt = time.Now()
+
+	if err := s.collectionsRepo.UpsertPolicy(ctx, policy); err != nil {
+		return nil, fmt.Errorf("failed to save collection policy: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "collection_policy.update",
+		ResourceType: "collection_policy",
+		ResourceID:   &policy.ID,
+		OldValues:    oldValues,
+		NewValues: map[string]interface{}{
+			"reminder_steps":             policy.ReminderSteps,
+			"late_fee_enabled":           policy.LateFeeEnabled,
+			"late_fee_amount":            policy.LateFeeAmount,
+			"suspend_scheduling_enabled": policy.SuspendSchedulingEnabled,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return policy, nil
+}
+
+// SendDueReminders sends the next cadence reminder for each overdue invoice
+func (s *collectionsServiceImpl) SendDueReminders(ctx context.Context, asOf time.Time) (int, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return 0, fmt.Errorf("tenant ID not found in context")
+	}
+
+	policy, err := s.GetCollectionPolicy(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !policy.RemindersEnabled || len(policy.ReminderSteps) == 0 {
+		return 0, nil
+	}
+
+	openInvoices, err := s.statementRepo.GetOpenInvoices(ctx, tenantID, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get open invoices: %w", err)
+	}
+
+	promised, err := s.promisedCustomers(ctx, tenantID, asOf)
+	if err != nil {
+		s.logger.Printf("Failed to get pending payment promises: %v", err)
+	}
+
+	sent := 0
+	for _, open := range openInvoices {
+		if open.Balance() <= 0 {
+			continue
+		}
+		// Customers with an active promise to pay are not reminded until it lapses
+		if promised[open.Invoice.CustomerID] {
+			continue
+		}
+
+		_, daysOverdue := AgingBucketFor(open.Invoice.DueDate, asOf)
+		if daysOverdue <= 0 {
+			continue
+		}
+
+		history, err := s.collectionsRepo.GetReminders(ctx, tenantID, open.Invoice.ID)
+		if err != nil {
+			s.logger.Printf("Failed to get reminder history for invoice %s: %v", open.Invoice.ID, err)
+			continue
+		}
+		sentSteps := make(map[int]bool, len(history))
+		for _, reminder := range history {
+			sentSteps[reminder.DaysOverdue] = true
+		}
+
+		step := DueReminderStep(policy.ReminderSteps, daysOverdue, sentSteps)
+		if step == nil {
+			continue
+		}
+
+		if err := s.sendReminder(ctx, open, step, daysOverdue); err != nil {
+			s.logger.Printf("Failed to send reminder for invoice %s: %v", open.Invoice.InvoiceNumber, err)
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// DueReminderStep returns the cadence step to send for an invoice, or nil if nothing is due.
+// Only the most escalated step reached is sent, so a late first run never sends a burst of reminders.
+func DueReminderStep(steps []domain.ReminderStep, daysOverdue int, sentSteps map[int]bool) *domain.ReminderStep {
+	var due *domain.ReminderStep
+	for i := range steps {
+		if steps[i].DaysOverdue <= daysOverdue && (due == nil || steps[i].DaysOverdue > due.DaysOverdue) {
+			due = &steps[i]
+		}
+	}
+	if due == nil || sentSteps[due.DaysOverdue] {
+		return nil
+	}
+	return due
+}
+
+// ApplyLateFees applies policy late fees to overdue invoices
+func (s *collectionsServiceImpl) ApplyLateFees(ctx context.Context, asOf time.Time) ([]*domain.InvoiceLateFee, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	policy, err := s.GetCollectionPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.LateFeeEnabled || policy.LateFeeAmount <= 0 {
+		return nil, nil
+	}
+
+	openInvoices, err := s.statementRepo.GetOpenInvoices(ctx, tenantID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open invoices: %w", err)
+	}
+
+	var applied []*domain.InvoiceLateFee
+	for _, open := range openInvoices {
+		balance := open.Balance()
+		if balance <= 0 {
+			continue
+		}
+
+		_, daysOverdue := AgingBucketFor(open.Invoice.DueDate, asOf)
+
+		existing, err := s.collectionsRepo.GetLateFees(ctx, tenantID, open.Invoice.ID)
+		if err != nil {
+			s.logger.Printf("Failed to get late fees for invoice %s: %v", open.Invoice.ID, err)
+			continue
+		}
+
+		// Late fees are charged on the original balance, not on earlier fees
+		appliedCount, lastAppliedDays, feeTotal := 0, 0, 0.0
+		for _, fee := range existing {
+			if fee.Waived {
+				continue
+			}
+			appliedCount++
+			feeTotal += fee.Amount
+			if fee.DaysOverdue > lastAppliedDays {
+				lastAppliedDays = fee.DaysOverdue
+			}
+		}
+
+		amount, due := CalculateLateFee(policy, balance-feeTotal, daysOverdue, appliedCount, lastAppliedDays)
+		if !due {
+			continue
+		}
+
+		reason := fmt.Sprintf("Late fee: %d days past due", daysOverdue)
+		fee := &domain.InvoiceLateFee{
+			ID:          uuid.New(),
+			TenantID:    tenantID,
+			InvoiceID:   open.Invoice.ID,
+			Amount:      amount,
+			DaysOverdue: daysOverdue,
+			Reason:      &reason,
+			AppliedAt:   asOf,
+		}
+
+		if err := s.collectionsRepo.ApplyLateFee(ctx, fee); err != nil {
+			s.logger.Printf("Failed to apply late fee to invoice %s: %v", open.Invoice.InvoiceNumber, err)
+			continue
+		}
+
+		if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+			Action:       "invoice.late_fee_applied",
+			ResourceType: "invoice",
+			ResourceID:   &open.Invoice.ID,
+			NewValues: map[string]interface{}{
+				"late_fee_id":  fee.ID,
+				"amount":       fee.Amount,
+				"days_overdue": fee.DaysOverdue,
+			},
+		}); err != nil {
+			s.logger.Printf("Failed to log audit event: %v", err)
+		}
+
+		applied = append(applied, fee)
+	}
+
+	return applied, nil
+}
+
+// CalculateLateFee returns the fee to apply to an invoice under the policy and whether one is due
+func CalculateLateFee(policy *domain.CollectionPolicy, balance float64, daysOverdue, appliedCount, lastAppliedDays int) (float64, bool) {
+	if !policy.LateFeeEnabled || balance <= 0 || daysOverdue <= policy.LateFeeGraceDays {
+		return 0, false
+	}
+	if policy.LateFeeMaxApplications > 0 && appliedCount >= policy.LateFeeMaxApplications {
+		return 0, false
+	}
+	if appliedCount > 0 {
+		if policy.LateFeeRepeatDays <= 0 || daysOverdue-lastAppliedDays < policy.LateFeeRepeatDays {
+			return 0, false
+		}
+	}
+
+	amount := policy.LateFeeAmount
+	if policy.LateFeeType == domain.LateFeeTypePercentage {
+		amount = balance * policy.LateFeeAmount / 100
+	}
+	amount = math.Round(amount*100) / 100
+
+	return amount, amount > 0
+}
+
+// WaiveLateFee waives a previously applied late fee and removes it from the invoice total
+func (s *collectionsServiceImpl) WaiveLateFee(ctx context.Context, feeID uuid.UUID, reason string) error {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("tenant ID not found in context")
+	}
+
+	fee, err := s.collectionsRepo.GetLateFee(ctx, tenantID, feeID)
+	if err != nil {
+		return fmt.Errorf("failed to get late fee: %w", err)
+	}
+	if fee == nil {
+		return fmt.Errorf("late fee not found")
+	}
+	if fee.Waived {
+		return fmt.Errorf("late fee already waived")
+	}
+
+	now := time.Now()
+	fee.Waived = true
+	fee.WaivedBy = GetUserIDFromContext(ctx)
+	fee.WaivedAt = &now
+
+	if err := s.collectionsRepo.WaiveLateFee(ctx, fee); err != nil {
+		return fmt.Errorf("failed to waive late fee: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       fee.WaivedBy,
+		Action:       "invoice.late_fee_waived",
+		ResourceType: "invoice",
+		ResourceID:   &fee.InvoiceID,
+		NewValues: map[string]interface{}{
+			"late_fee_id": fee.ID,
+			"amount":      fee.Amount,
+			"reason":      reason,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return nil
+}
+
+// EnforceSchedulingSuspensions suspends or resumes scheduling for customers per the delinquency policy
+func (s *collectionsServiceImpl) EnforceSchedulingSuspensions(ctx context.Context, asOf time.Time) (*SuspensionResult, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	result := &SuspensionResult{}
+
+	policy, err := s.GetCollectionPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.SuspendSchedulingEnabled {
+		return result, nil
+	}
+
+	openInvoices, err := s.statementRepo.GetOpenInvoices(ctx, tenantID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open invoices: %w", err)
+	}
+
+	suspended, err := s.collectionsRepo.GetSuspendedCustomers(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suspended customers: %w", err)
+	}
+
+	delinquent := DelinquentCustomers(openInvoices, asOf, policy.SuspendAfterDays, policy.SuspendMinBalance)
+
+	for customerID := range delinquent {
+		if _, already := suspended[customerID]; already {
+			continue
+		}
+		if err := s.setSuspension(ctx, tenantID, customerID, true, autoSuspensionReason); err != nil {
+			s.logger.Printf("Failed to suspend scheduling for customer %s: %v", customerID, err)
+			continue
+		}
+		result.Suspended = append(result.Suspended, customerID)
+	}
+
+	// Lift automatic suspensions once the account is no longer delinquent; manual ones stay
+	for customerID, reason := range suspended {
+		if reason != autoSuspensionReason || delinquent[customerID] {
+			continue
+		}
+		if err := s.setSuspension(ctx, tenantID, customerID, false, ""); err != nil {
+			s.logger.Printf("Failed to resume scheduling for customer %s: %v", customerID, err)
+			continue
+		}
+		result.Resumed = append(result.Resumed, customerID)
+	}
+
+	return result, nil
+}
+
+// DelinquentCustomers returns customers with an invoice at least suspendAfterDays past due
+// and a total past-due balance of at least minBalance
+func DelinquentCustomers(openInvoices []*OpenInvoice, asOf time.Time, suspendAfterDays int, minBalance float64) map[uuid.UUID]bool {
+	oldest := make(map[uuid.UUID]int)
+	pastDue := make(map[uuid.UUID]float64)
+
+	for _, open := range openInvoices {
+		balance := open.Balance()
+		if balance <= 0 {
+			continue
+		}
+		_, daysOverdue := AgingBucketFor(open.Invoice.DueDate, asOf)
+		if daysOverdue <= 0 {
+			continue
+		}
+		pastDue[open.Invoice.CustomerID] += balance
+		if daysOverdue > oldest[open.Invoice.CustomerID] {
+			oldest[open.Invoice.CustomerID] = daysOverdue
+		}
+	}
+
+	delinquent := make(map[uuid.UUID]bool)
+	for customerID, days := range oldest {
+		if days >= suspendAfterDays && pastDue[customerID] >= minBalance {
+			delinquent[customerID] = true
+		}
+	}
+	return delinquent
+}
+
+// ProcessCollections runs reminders, late fees, promise resolution and suspensions for every tenant
+// with a collections policy. It is called periodically by the worker and is not tenant-scoped.
+func (s *collectionsServiceImpl) ProcessCollections(ctx context.Context, asOf time.Time) error {
+	policies, err := s.collectionsRepo.ListPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list collection policies: %w", err)
+	}
+
+	for _, policy := range policies {
+		tenantCtx := context.WithValue(ctx, "tenant_id", policy.TenantID)
+
+		if kept, broken, err := s.collectionsRepo.ResolveExpiredPromises(tenantCtx, policy.TenantID, asOf); err != nil {
+			s.logger.Printf("Failed to resolve payment promises for tenant %s: %v", policy.TenantID, err)
+		} else if kept+broken > 0 {
+			s.logger.Printf("Resolved payment promises for tenant %s: %d kept, %d broken", policy.TenantID, kept, broken)
+		}
+
+		if _, err := s.ApplyLateFees(tenantCtx, asOf); err != nil {
+			s.logger.Printf("Failed to apply late fees for tenant %s: %v", policy.TenantID, err)
+		}
+
+		if _, err := s.SendDueReminders(tenantCtx, asOf); err != nil {
+			s.logger.Printf("Failed to send reminders for tenant %s: %v", policy.TenantID, err)
+		}
+
+		if _, err := s.EnforceSchedulingSuspensions(tenantCtx, asOf); err != nil {
+			s.logger.Printf("Failed to enforce suspensions for tenant %s: %v", policy.TenantID, err)
+		}
+	}
+
+	return nil
+}
+
+// AddCollectionNote records a collections note against a customer
+func (s *collectionsServiceImpl) AddCollectionNote(ctx context.Context, req *CollectionNoteRequest) (*domain.CollectionNote, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if strings.TrimSpace(req.Content) == "" {
+		return nil, fmt.Errorf("note content is required")
+	}
+
+	customer, err := s.customerRepo.GetByID(ctx, tenantID, req.CustomerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	if customer == nil {
+		return nil, fmt.Errorf("customer not found")
+	}
+
+	noteType := req.NoteType
+	if noteType == "" {
+		noteType = domain.CollectionNoteTypeNote
+	}
+
+	note := &domain.CollectionNote{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		CustomerID: req.CustomerID,
+		InvoiceID:  req.InvoiceID,
+		UserID:     GetUserIDFromContext(ctx),
+		NoteType:   noteType,
+		Content:    req.Content,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := s.collectionsRepo.CreateNote(ctx, note); err != nil {
+		return nil, fmt.Errorf("failed to create collection note: %w", err)
+	}
+
+	return note, nil
+}
+
+// ListCollectionNotes lists a customer's collections notes, newest first
+func (s *collectionsServiceImpl) ListCollectionNotes(ctx context.Context, customerID uuid.UUID) ([]*domain.CollectionNote, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	notes, err := s.collectionsRepo.ListNotes(ctx, tenantID, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collection notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// CreatePaymentPromise records a customer's promise to pay
+func (s *collectionsServiceImpl) CreatePaymentPromise(ctx context.Context, req *PaymentPromiseRequest) (*domain.PaymentPromise, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("promised amount must be positive")
+	}
+	if req.PromisedDate.IsZero() {
+		return nil, fmt.Errorf("promised date is required")
+	}
+
+	if req.InvoiceID != nil {
+		invoice, err := s.invoiceRepo.GetByID(ctx, tenantID, *req.InvoiceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get invoice: %w", err)
+		}
+		if invoice == nil || invoice.CustomerID != req.CustomerID {
+			return nil, fmt.Errorf("invoice not found for customer")
+		}
+	}
+
+	userID := GetUserIDFromContext(ctx)
+	promise := &domain.PaymentPromise{
+		ID:           uuid.New(),
+		TenantID:     tenantID,
+		CustomerID:   req.CustomerID,
+		InvoiceID:    req.InvoiceID,
+		Amount:       req.Amount,
+		PromisedDate: req.PromisedDate,
+		Status:       domain.PaymentPromiseStatusPending,
+		Notes:        req.Notes,
+		CreatedBy:    userID,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := s.collectionsRepo.CreatePromise(ctx, promise); err != nil {
+		return nil, fmt.Errorf("failed to create payment promise: %w", err)
+	}
+
+	// Keep the collections timeline complete
+	if _, err := s.AddCollectionNote(ctx, &CollectionNoteRequest{
+		CustomerID: req.CustomerID,
+		InvoiceID:  req.InvoiceID,
+		NoteType:   domain.CollectionNoteTypePromise,
+		Content:    fmt.Sprintf("Promised to pay $%.2f by %s", req.Amount, req.PromisedDate.Format("January 2, 2006")),
+	}); err != nil {
+		s.logger.Printf("Failed to record promise note: %v", err)
+	}
+
+	return promise, nil
+}
+
+// UpdatePaymentPromiseStatus marks a promise kept, broken or cancelled
+func (s *collectionsServiceImpl) UpdatePaymentPromiseStatus(ctx context.Context, promiseID uuid.UUID, status string) (*domain.PaymentPromise, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	switch status {
+	case domain.PaymentPromiseStatusKept, domain.PaymentPromiseStatusBroken, domain.PaymentPromiseStatusCancelled:
+	default:
+		return nil, fmt.Errorf("invalid promise status: %s", status)
+	}
+
+	promise, err := s.collectionsRepo.GetPromise(ctx, tenantID, promiseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment promise: %w", err)
+	}
+	if promise == nil {
+		return nil, fmt.Errorf("payment promise not found")
+	}
+	if promise.Status != domain.PaymentPromiseStatusPending {
+		return nil, fmt.Errorf("payment promise already %s", promise.Status)
+	}
+
+	now := time.Now()
+	promise.Status = status
+	promise.ResolvedAt = &now
+	promise.UpdatedAt = now
+
+	if err := s.collectionsRepo.UpdatePromise(ctx, promise); err != nil {
+		return nil, fmt.Errorf("failed to update payment promise: %w", err)
+	}
+
+	return promise, nil
+}
+
+// ListPaymentPromises lists promises to pay
+func (s *collectionsServiceImpl) ListPaymentPromises(ctx context.Context, filter *PaymentPromiseFilter) ([]*domain.PaymentPromise, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if filter == nil {
+		filter = &PaymentPromiseFilter{}
+	}
+
+	promises, err := s.collectionsRepo.ListPromises(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment promises: %w", err)
+	}
+
+	return promises, nil
+}
+
+// SuspendScheduling manually suspends scheduling for a customer
+func (s *collectionsServiceImpl) SuspendScheduling(ctx context.Context, customerID uuid.UUID, reason string) error {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("tenant ID not found in context")
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("suspension reason is required")
+	}
+
+	return s.setSuspension(ctx, tenantID, customerID, true, reason)
+}
+
+// ResumeScheduling lifts a scheduling suspension for a customer
+func (s *collectionsServiceImpl) ResumeScheduling(ctx context.Context, customerID uuid.UUID) error {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("tenant ID not found in context")
+	}
+
+	return s.setSuspension(ctx, tenantID, customerID, false, "")
+}
+
+// Helper methods
+
+func (s *collectionsServiceImpl) setSuspension(ctx context.Context, tenantID, customerID uuid.UUID, suspended bool, reason string) error {
+	var reasonPtr *string
+	if suspended {
+		reasonPtr = &reason
+	}
+
+	if err := s.collectionsRepo.SetSchedulingSuspended(ctx, tenantID, customerID, suspended, reasonPtr); err != nil {
+		return fmt.Errorf("failed to update scheduling suspension: %w", err)
+	}
+
+	action, content := "customer.scheduling_resumed", "Scheduling resumed"
+	if suspended {
+		action, content = "customer.scheduling_suspended", "Scheduling suspended: "+reason
+	}
+
+	if err := s.collectionsRepo.CreateNote(ctx, &domain.CollectionNote{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		CustomerID: customerID,
+		UserID:     GetUserIDFromContext(ctx),
+		NoteType:   domain.CollectionNoteTypeSuspension,
+		Content:    content,
+		CreatedAt:  time.Now(),
+	}); err != nil {
+		s.logger.Printf("Failed to record suspension note: %v", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       action,
+		ResourceType: "customer",
+		ResourceID:   &customerID,
+		NewValues: map[string]interface{}{
+			"scheduling_suspended": suspended,
+			"reason":               reason,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return nil
+}
+
+func (s *collectionsServiceImpl) promisedCustomers(ctx context.Context, tenantID uuid.UUID, asOf time.Time) (map[uuid.UUID]bool, error) {
+	promises, err := s.collectionsRepo.ListPromises(ctx, tenantID, &PaymentPromiseFilter{Status: domain.PaymentPromiseStatusPending})
+	if err != nil {
+		return nil, err
+	}
+
+	promised := make(map[uuid.UUID]bool)
+	for _, promise := range promises {
+		if !promise.PromisedDate.Before(asOf.Truncate(24 * time.Hour)) {
+			promised[promise.CustomerID] = true
+		}
+	}
+	return promised, nil
+}
+
+func (s *collectionsServiceImpl) sendReminder(ctx context.Context, open *OpenInvoice, step *domain.ReminderStep, daysOverdue int) error {
+	customer, err := s.customerRepo.GetByID(ctx, open.Invoice.TenantID, open.Invoice.CustomerID)
+	if err != nil {
+		return fmt.Errorf("failed to get customer: %w", err)
+	}
+	if customer == nil || customer.Email == nil || *customer.Email == "" {
+		return fmt.Errorf("no email address available for customer")
+	}
+
+	subject, body := RenderReminder(step, customer, open, daysOverdue)
+
+	if err := s.communicationService.SendEmail(ctx, &EmailRequest{
+		To:      []string{*customer.Email},
+		Subject: subject,
+		Body:    body,
+		IsHTML:  false,
+	}); err != nil {
+		return fmt.Errorf("failed to send reminder email: %w", err)
+	}
+
+	if err := s.collectionsRepo.CreateReminder(ctx, &domain.InvoiceReminder{
+		ID:          uuid.New(),
+		TenantID:    open.Invoice.TenantID,
+		InvoiceID:   open.Invoice.ID,
+		DaysOverdue: step.DaysOverdue,
+		Template:    step.Template,
+		Channel:     "email",
+		Recipient:   customer.Email,
+		SentAt:      time.Now(),
+	}); err != nil {
+		return fmt.Errorf("failed to record reminder: %w", err)
+	}
+
+	return nil
+}
+
+// RenderReminder fills a reminder step's template for an invoice
+func RenderReminder(step *domain.ReminderStep, customer *domain.EnhancedCustomer, open *OpenInvoice, daysOverdue int) (string, string) {
+	template, ok := defaultReminderTemplates[step.Template]
+	if !ok {
+		template = defaultReminderTemplates[domain.ReminderTemplateFriendly]
+	}
+	if step.Subject != nil {
+		template.Subject = *step.Subject
+	}
+	if step.Body != nil {
+		template.Body = *step.Body
+	}
+
+	dueDate := ""
+	if open.Invoice.DueDate != nil {
+		dueDate = open.Invoice.DueDate.Format("January 2, 2006")
+	}
+
+	replacer := strings.NewReplacer(
+		"{{customer_name}}", customerDisplayName(customer),
+		"{{invoice_number}}", open.Invoice.InvoiceNumber,
+		"{{balance}}", fmt.Sprintf("%.2f", open.Balance()),
+		"{{due_date}}", dueDate,
+		"{{days_overdue}}", fmt.Sprintf("%d", daysOverdue),
+	)
+
+	return replacer.Replace(template.Subject), replacer.Replace(template.Body)
+}
@@ -0,0 +1,1023 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// ConversationService keeps each customer's communication timeline and the two-way
+// SMS and email threads behind it. Staff messages go out through a MessagingProvider;
+// the provider's inbound webhook delivers replies and delivery updates, and replies
+// are threaded onto the conversation they answer so office staff can respond from
+// within the app.
+type ConversationService interface {
+	// Timeline
+	GetCustomerTimeline(ctx context.Context, customerID uuid.UUID, filter *TimelineFilter) (*CustomerTimeline, error)
+	AddCustomerNote(ctx context.Context, customerID uuid.UUID, req *CustomerNoteRequest) (*domain.CustomerNote, error)
+
+	// Threads
+	ListThreads(ctx context.Context, filter *ThreadFilter) (*domain.PaginatedResponse, error)
+	GetThread(ctx context.Context, threadID uuid.UUID) (*ConversationThread, error)
+	UpdateThread(ctx context.Context, threadID uuid.UUID, req *ThreadUpdateRequest) (*domain.CommunicationThread, error)
+	MarkThreadRead(ctx context.Context, threadID uuid.UUID) error
+	SendMessage(ctx context.Context, req *SendMessageRequest) (*domain.CommunicationMessage, error)
+
+	// Provider webhooks; tenantID comes from the webhook URL
+	ReceiveWebhook(ctx context.Context, tenantID uuid.UUID, header http.Header, payload []byte) (*MessagingWebhookResult, error)
+}
+
+// ConversationRepository defines data access for threads, messages, notes and the timeline
+type ConversationRepository interface {
+	CreateThread(ctx context.Context, thread *domain.CommunicationThread) error
+	GetThread(ctx context.Context, tenantID, threadID uuid.UUID) (*domain.CommunicationThread, error)
+	UpdateThread(ctx context.Context, thread *domain.CommunicationThread) error
+	ListThreads(ctx context.Context, tenantID uuid.UUID, filter *ThreadFilter) ([]*domain.CommunicationThread, int64, error)
+	// FindThreads lists the threads with an address on a channel, most recent first
+	FindThreads(ctx context.Context, tenantID uuid.UUID, channel, address string) ([]*domain.CommunicationThread, error)
+	MarkThreadRead(ctx context.Context, tenantID, threadID uuid.UUID, readAt time.Time) error
+
+	// AddMessage stores a message and moves its thread's last message, preview and,
+	// for inbound messages, unread count. A closed thread is reopened.
+	AddMessage(ctx context.Context, message *domain.CommunicationMessage) error
+	UpdateMessageStatus(ctx context.Context, message *domain.CommunicationMessage) error
+	GetMessageByProviderID(ctx context.Context, tenantID uuid.UUID, providerMessageID string) (*domain.CommunicationMessage, error)
+	ListMessages(ctx context.Context, tenantID, threadID uuid.UUID) ([]*domain.CommunicationMessage, error)
+
+	// FindCustomerByContact matches an email address or phone digits to a customer
+	FindCustomerByContact(ctx context.Context, tenantID uuid.UUID, email, phoneDigits string) (*uuid.UUID, error)
+
+	CreateNote(ctx context.Context, note *domain.CustomerNote) error
+	// ListTimeline lists a customer's timeline events, newest first, before filter.Before
+	ListTimeline(ctx context.Context, tenantID, customerID uuid.UUID, filter *TimelineFilter) ([]*TimelineEvent, error)
+}
+
+// MessagingProvider sends SMS and email through the comms provider and reads its
+// webhooks. TwilioMessagingProvider is the production provider;
+// LocalMessagingProvider stands in for it in development and tests.
+type MessagingProvider interface {
+	Name() string
+	// SenderAddress is the number or address customers see messages come from
+	SenderAddress(channel string) string
+	// Send delivers a message and returns the provider's ID for it
+	Send(ctx context.Context, msg *OutboundMessage) (string, error)
+	VerifyWebhook(webhook *MessagingWebhook) error
+	// ParseWebhook reads inbound messages and delivery updates from a webhook payload
+	ParseWebhook(webhook *MessagingWebhook) ([]*MessagingEvent, error)
+}
+
+// MessagingWebhook is a webhook delivery as the provider posted it. Providers sign
+// different parts of the request, so the headers are passed through as received.
+type MessagingWebhook struct {
+	TenantID uuid.UUID
+	Header   http.Header
+	Payload  []byte
+}
+
+// MessagingSignatureHeader carries LocalMessagingProvider's webhook signature
+const MessagingSignatureHeader = "X-Comms-Signature"
+
+// Messaging event types
+const (
+	MessagingEventMessage = "message"
+	MessagingEventStatus  = "status"
+)
+
+// Timeline event types
+const (
+	TimelineTypeMessage  = "message"
+	TimelineTypeNote     = "note"
+	TimelineTypeJob      = "job"
+	TimelineTypeQuote    = "quote"
+	TimelineTypeInvoice  = "invoice"
+	TimelineTypePayment  = "payment"
+	TimelineTypeReminder = "reminder"
+	TimelineTypeCustomer = "customer"
+)
+
+// TimelineTypes lists the timeline event types
+var TimelineTypes = []string{
+	TimelineTypeMessage, TimelineTypeNote, TimelineTypeJob, TimelineTypeQuote,
+	TimelineTypeInvoice, TimelineTypePayment, TimelineTypeReminder, TimelineTypeCustomer,
+}
+
+// Message limits
+const (
+	MaxSMSLength         = 1600 // ten concatenated segments
+	MessagePreviewLength = 140
+)
+
+// OutboundMessage is a message handed to the provider
+type OutboundMessage struct {
+	Channel   string `json:"channel"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Subject   string `json:"subject,omitempty"`
+	Body      string `json:"body"`
+	InReplyTo string `json:"in_reply_to,omitempty"` // provider message ID of the email being answered
+}
+
+// MessagingEvent is an inbound message or a delivery update from the provider
+type MessagingEvent struct {
+	Type              string    `json:"type"`
+	Channel           string    `json:"channel"`
+	ProviderMessageID string    `json:"provider_message_id"`
+	From              string    `json:"from,omitempty"`
+	To                string    `json:"to,omitempty"`
+	Subject           string    `json:"subject,omitempty"`
+	Body              string    `json:"body,omitempty"`
+	InReplyTo         string    `json:"in_reply_to,omitempty"`
+	Status            string    `json:"status,omitempty"` // delivered or failed, for status events
+	Error             string    `json:"error,omitempty"`
+	OccurredAt        time.Time `json:"occurred_at"`
+}
+
+// MessagingWebhookResult counts what a webhook delivery changed
+type MessagingWebhookResult struct {
+	Received       int `json:"received"`
+	StatusUpdates  int `json:"status_updates"`
+	Duplicates     int `json:"duplicates"`
+	UnknownUpdates int `json:"unknown_updates"` // updates for messages not sent from here
+}
+
+// TimelineFilter pages back through a customer's timeline
+type TimelineFilter struct {
+	Types  []string   `json:"types,omitempty"`
+	Before *time.Time `json:"before,omitempty"`
+	Limit  int        `json:"limit,omitempty"`
+}
+
+// TimelineEvent is one touchpoint with a customer
+type TimelineEvent struct {
+	ID           uuid.UUID  `json:"id"`
+	Type         string     `json:"type"`
+	Action       string     `json:"action"`
+	Title        string     `json:"title"`
+	Reference    string     `json:"reference,omitempty"` // quote, invoice or job number, or email subject
+	Detail       string     `json:"detail,omitempty"`
+	OccurredAt   time.Time  `json:"occurred_at"`
+	ResourceType string     `json:"resource_type"`
+	ResourceID   *uuid.UUID `json:"resource_id,omitempty"`
+	ThreadID     *uuid.UUID `json:"thread_id,omitempty"`
+	Channel      string     `json:"channel,omitempty"`
+	Direction    string     `json:"direction,omitempty"`
+	ActorID      *uuid.UUID `json:"actor_id,omitempty"`
+}
+
+// CustomerTimeline is a page of a customer's timeline. Pass NextBefore as the next
+// page's Before.
+type CustomerTimeline struct {
+	CustomerID uuid.UUID        `json:"customer_id"`
+	Events     []*TimelineEvent `json:"events"`
+	NextBefore *time.Time       `json:"next_before,omitempty"`
+}
+
+// CustomerNoteRequest adds a note to a customer's timeline
+type CustomerNoteRequest struct {
+	Body string `json:"body"`
+}
+
+// ThreadFilter filters the thread inbox
+type ThreadFilter struct {
+	BaseFilter
+	Status     string     `json:"status,omitempty"`
+	Channel    string     `json:"channel,omitempty"`
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"`
+	UnreadOnly bool       `json:"unread_only,omitempty"`
+}
+
+// ConversationThread is a thread with its messages, oldest first
+type ConversationThread struct {
+	Thread   *domain.CommunicationThread    `json:"thread"`
+	Messages []*domain.CommunicationMessage `json:"messages"`
+}
+
+// ThreadUpdateRequest closes or reopens a thread, or links it to a customer
+type ThreadUpdateRequest struct {
+	Status     *string    `json:"status,omitempty"`
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"`
+}
+
+// SendMessageRequest sends an SMS or email. With ThreadID it replies on the thread;
+// otherwise it goes to the customer's phone or email on Channel.
+type SendMessageRequest struct {
+	ThreadID   *uuid.UUID `json:"thread_id,omitempty"`
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"`
+	Channel    string     `json:"channel,omitempty"`
+	Subject    string     `json:"subject,omitempty"`
+	Body       string     `json:"body"`
+}
+
+// conversationServiceImpl implements ConversationService
+type conversationServiceImpl struct {
+	conversationRepo ConversationRepository
+	customerRepo     CustomerRepository
+	provider         MessagingProvider
+	auditService     AuditService
+	logger           *log.Logger
+}
+
+// NewConversationService creates a new conversation service
+func NewConversationService(
+	conversationRepo ConversationRepository,
+	customerRepo CustomerRepository,
+	provider MessagingProvider,
+	auditService AuditService,
+	logger *log.Logger,
+) ConversationService {
+	return &conversationServiceImpl{
+		conversationRepo: conversationRepo,
+		customerRepo:     customerRepo,
+		provider:         provider,
+		auditService:     auditService,
+		logger:           logger,
+	}
+}
+
+// GetCustomerTimeline returns a page of the customer's timeline, newest first
+func (s *conversationServiceImpl) GetCustomerTimeline(ctx context.Context, customerID uuid.UUID, filter *TimelineFilter) (*CustomerTimeline, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	// Set defaults
+	if filter == nil {
+		filter = &TimelineFilter{}
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	if filter.Limit > 200 {
+		filter.Limit = 200
+	}
+	for _, eventType := range filter.Types {
+		if !containsString(TimelineTypes, eventType) {
+			return nil, fmt.Errorf("validation failed: unknown timeline type %q", eventType)
+		}
+	}
+
+	if _, err := s.getCustomer(ctx, tenantID, customerID); err != nil {
+		return nil, err
+	}
+
+	// Ask for one extra event to know whether there is another page
+	limit := filter.Limit
+	events, err := s.conversationRepo.ListTimeline(ctx, tenantID, customerID, &TimelineFilter{
+		Types:  filter.Types,
+		Before: filter.Before,
+		Limit:  limit + 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list timeline: %w", err)
+	}
+
+	timeline := &CustomerTimeline{CustomerID: customerID, Events: events}
+	if len(events) > limit {
+		timeline.Events = events[:limit]
+		next := timeline.Events[limit-1].OccurredAt
+		timeline.NextBefore = &next
+	}
+	for _, event := range timeline.Events {
+		event.Title = TimelineEventTitle(event.Action)
+	}
+	if timeline.Events == nil {
+		timeline.Events = []*TimelineEvent{}
+	}
+
+	return timeline, nil
+}
+
+// AddCustomerNote adds a staff note to the customer's timeline
+func (s *conversationServiceImpl) AddCustomerNote(ctx context.Context, customerID uuid.UUID, req *CustomerNoteRequest) (*domain.CustomerNote, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+	if req == nil || strings.TrimSpace(req.Body) == "" {
+		return nil, fmt.Errorf("validation failed: note is required")
+	}
+
+	if _, err := s.getCustomer(ctx, tenantID, customerID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	note := &domain.CustomerNote{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		CustomerID: customerID,
+		Body:       strings.TrimSpace(req.Body),
+		CreatedBy:  GetUserIDFromContext(ctx),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.conversationRepo.CreateNote(ctx, note); err != nil {
+		return nil, fmt.Errorf("failed to create note: %w", err)
+	}
+
+	return note, nil
+}
+
+// ListThreads lists threads, most recent message first
+func (s *conversationServiceImpl) ListThreads(ctx context.Context, filter *ThreadFilter) (*domain.PaginatedResponse, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	// Set defaults
+	if filter == nil {
+		filter = &ThreadFilter{}
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PerPage <= 0 {
+		filter.PerPage = 50
+	}
+	if filter.PerPage > 100 {
+		filter.PerPage = 100
+	}
+
+	threads, total, err := s.conversationRepo.ListThreads(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list threads: %w", err)
+	}
+
+	totalPages := int((total + int64(filter.PerPage) - 1) / int64(filter.PerPage))
+
+	return &domain.PaginatedResponse{
+		Data:       threads,
+		Total:      total,
+		Page:       filter.Page,
+		PerPage:    filter.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetThread returns a thread with its messages
+func (s *conversationServiceImpl) GetThread(ctx context.Context, threadID uuid.UUID) (*ConversationThread, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	thread, err := s.getThread(ctx, tenantID, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.conversationRepo.ListMessages(ctx, tenantID, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	return &ConversationThread{Thread: thread, Messages: messages}, nil
+}
+
+// UpdateThread closes or reopens a thread or links it to a customer. Linking also
+// links the thread's earlier messages, so they appear on the customer's timeline.
+func (s *conversationServiceImpl) UpdateThread(ctx context.Context, threadID uuid.UUID, req *ThreadUpdateRequest) (*domain.CommunicationThread, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: nothing to update")
+	}
+
+	thread, err := s.getThread(ctx, tenantID, threadID)
+	if err != nil {
+		return nil, err
+	}
+	oldValues := map[string]interface{}{"status": thread.Status, "customer_id": thread.CustomerID}
+
+	if req.Status != nil {
+		if *req.Status != domain.ThreadStatusOpen && *req.Status != domain.ThreadStatusClosed {
+			return nil, fmt.Errorf("validation failed: invalid thread status %q", *req.Status)
+		}
+		thread.Status = *req.Status
+	}
+	if req.CustomerID != nil {
+		if _, err := s.getCustomer(ctx, tenantID, *req.CustomerID); err != nil {
+			return nil, err
+		}
+		thread.CustomerID = req.CustomerID
+	}
+	thread.UpdatedAt = time.Now()
+
+	if err := s.conversationRepo.UpdateThread(ctx, thread); err != nil {
+		return nil, fmt.Errorf("failed to update thread: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "communication_thread.update",
+		ResourceType: "communication_thread",
+		ResourceID:   &thread.ID,
+		OldValues:    oldValues,
+		NewValues:    map[string]interface{}{"status": thread.Status, "customer_id": thread.CustomerID},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return thread, nil
+}
+
+// MarkThreadRead marks a thread's inbound messages read
+func (s *conversationServiceImpl) MarkThreadRead(ctx context.Context, threadID uuid.UUID) error {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("tenant ID not found in context")
+	}
+
+	if _, err := s.getThread(ctx, tenantID, threadID); err != nil {
+		return err
+	}
+	if err := s.conversationRepo.MarkThreadRead(ctx, tenantID, threadID, time.Now()); err != nil {
+		return fmt.Errorf("failed to mark thread read: %w", err)
+	}
+	return nil
+}
+
+// SendMessage sends an SMS or email from staff. The message is stored before it is
+// handed to the provider, so a failed send stays visible on the thread.
+func (s *conversationServiceImpl) SendMessage(ctx context.Context, req *SendMessageRequest) (*domain.CommunicationMessage, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+	if s.provider == nil {
+		return nil, fmt.Errorf("messaging provider not configured")
+	}
+	if req == nil {
+		return nil, fmt.Errorf("validation failed: message is required")
+	}
+
+	body := strings.TrimSpace(req.Body)
+	subject := strings.TrimSpace(req.Subject)
+	if body == "" {
+		return nil, fmt.Errorf("validation failed: message body is required")
+	}
+
+	var (
+		thread    *domain.CommunicationThread
+		inReplyTo string
+		err       error
+	)
+	if req.ThreadID != nil {
+		thread, err = s.getThread(ctx, tenantID, *req.ThreadID)
+		if err != nil {
+			return nil, err
+		}
+		if thread.Channel == domain.CommunicationChannelEmail {
+			if subject == "" {
+				subject = ReplySubject(stringValue(thread.Subject))
+			}
+			inReplyTo, err = s.lastInboundProviderID(ctx, tenantID, thread.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		thread, err = s.customerThread(ctx, tenantID, req, subject)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if thread.Channel == domain.CommunicationChannelSMS {
+		subject = ""
+		if len([]rune(body)) > MaxSMSLength {
+			return nil, fmt.Errorf("validation failed: text messages are limited to %d characters", MaxSMSLength)
+		}
+	} else if subject == "" {
+		return nil, fmt.Errorf("validation failed: email subject is required")
+	}
+
+	now := time.Now()
+	message := &domain.CommunicationMessage{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		ThreadID:    thread.ID,
+		CustomerID:  thread.CustomerID,
+		Channel:     thread.Channel,
+		Direction:   domain.MessageDirectionOutbound,
+		FromAddress: s.provider.SenderAddress(thread.Channel),
+		ToAddress:   thread.Address,
+		Subject:     optionalString(subject),
+		Body:        body,
+		InReplyTo:   optionalString(inReplyTo),
+		Status:      domain.MessageStatusQueued,
+		SentBy:      GetUserIDFromContext(ctx),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.conversationRepo.AddMessage(ctx, message); err != nil {
+		return nil, fmt.Errorf("failed to save message: %w", err)
+	}
+
+	providerID, sendErr := s.provider.Send(ctx, &OutboundMessage{
+		Channel:   message.Channel,
+		From:      message.FromAddress,
+		To:        message.ToAddress,
+		Subject:   subject,
+		Body:      body,
+		InReplyTo: inReplyTo,
+	})
+	if sendErr != nil {
+		message.Status = domain.MessageStatusFailed
+		message.Error = stringPtr(sendErr.Error())
+	} else {
+		message.Status = domain.MessageStatusSent
+		message.ProviderMessageID = &providerID
+	}
+	message.UpdatedAt = time.Now()
+	if err := s.conversationRepo.UpdateMessageStatus(ctx, message); err != nil {
+		return nil, fmt.Errorf("failed to update message: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       message.SentBy,
+		Action:       "message.send",
+		ResourceType: "communication_message",
+		ResourceID:   &message.ID,
+		NewValues: map[string]interface{}{
+			"thread_id": message.ThreadID,
+			"channel":   message.Channel,
+			"to":        message.ToAddress,
+			"status":    message.Status,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	if sendErr != nil {
+		return message, fmt.Errorf("failed to send message: %w", sendErr)
+	}
+	return message, nil
+}
+
+// ReceiveWebhook verifies and applies a provider webhook: inbound messages are
+// threaded and delivery updates applied. Providers retry webhooks, so messages and
+// updates already applied are skipped.
+func (s *conversationServiceImpl) ReceiveWebhook(ctx context.Context, tenantID uuid.UUID, header http.Header, payload []byte) (*MessagingWebhookResult, error) {
+	if s.provider == nil {
+		return nil, fmt.Errorf("messaging provider not configured")
+	}
+	webhook := &MessagingWebhook{TenantID: tenantID, Header: header, Payload: payload}
+	if err := s.provider.VerifyWebhook(webhook); err != nil {
+		return nil, fmt.Errorf("invalid webhook signature: %w", err)
+	}
+
+	events, err := s.provider.ParseWebhook(webhook)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	ctx = context.WithValue(ctx, "tenant_id", tenantID)
+	result := &MessagingWebhookResult{}
+	for _, event := range events {
+		switch event.Type {
+		case MessagingEventMessage:
+			received, err := s.receiveMessage(ctx, tenantID, event)
+			if err != nil {
+				return result, err
+			}
+			if received {
+				result.Received++
+			} else {
+				result.Duplicates++
+			}
+		case MessagingEventStatus:
+			updated, err := s.applyStatus(ctx, tenantID, event)
+			if err != nil {
+				return result, err
+			}
+			if updated {
+				result.StatusUpdates++
+			} else {
+				result.UnknownUpdates++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (s *conversationServiceImpl) receiveMessage(ctx context.Context, tenantID uuid.UUID, event *MessagingEvent) (bool, error) {
+	if event.ProviderMessageID != "" {
+		existing, err := s.conversationRepo.GetMessageByProviderID(ctx, tenantID, event.ProviderMessageID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get message: %w", err)
+		}
+		if existing != nil {
+			return false, nil
+		}
+	}
+
+	address := NormalizeMessageAddress(event.Channel, event.From)
+	if address == "" {
+		return false, fmt.Errorf("validation failed: inbound message has no sender")
+	}
+
+	var replyThreadID *uuid.UUID
+	if event.InReplyTo != "" {
+		original, err := s.conversationRepo.GetMessageByProviderID(ctx, tenantID, event.InReplyTo)
+		if err != nil {
+			return false, fmt.Errorf("failed to get message: %w", err)
+		}
+		if original != nil {
+			replyThreadID = &original.ThreadID
+		}
+	}
+
+	threads, err := s.conversationRepo.FindThreads(ctx, tenantID, event.Channel, address)
+	if err != nil {
+		return false, fmt.Errorf("failed to find threads: %w", err)
+	}
+
+	receivedAt := event.OccurredAt
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
+	}
+
+	thread := MatchInboundThread(threads, event.Channel, event.Subject, replyThreadID)
+	if thread == nil {
+		thread = &domain.CommunicationThread{
+			ID:        uuid.New(),
+			TenantID:  tenantID,
+			Channel:   event.Channel,
+			Address:   address,
+			Status:    domain.ThreadStatusOpen,
+			CreatedAt: receivedAt,
+			UpdatedAt: receivedAt,
+		}
+		if event.Channel == domain.CommunicationChannelEmail {
+			thread.Subject = optionalString(NormalizeThreadSubject(event.Subject))
+		}
+		if thread.CustomerID, err = s.findCustomer(ctx, tenantID, event.Channel, address); err != nil {
+			return false, err
+		}
+		if err := s.conversationRepo.CreateThread(ctx, thread); err != nil {
+			return false, fmt.Errorf("failed to create thread: %w", err)
+		}
+	} else if thread.CustomerID == nil {
+		if thread.CustomerID, err = s.findCustomer(ctx, tenantID, event.Channel, address); err != nil {
+			return false, err
+		}
+		if thread.CustomerID != nil {
+			if err := s.conversationRepo.UpdateThread(ctx, thread); err != nil {
+				return false, fmt.Errorf("failed to update thread: %w", err)
+			}
+		}
+	}
+
+	body := strings.TrimSpace(event.Body)
+	if event.Channel == domain.CommunicationChannelEmail {
+		if stripped := StripQuotedReply(body); stripped != "" {
+			body = stripped
+		}
+	}
+
+	message := &domain.CommunicationMessage{
+		ID:                uuid.New(),
+		TenantID:          tenantID,
+		ThreadID:          thread.ID,
+		CustomerID:        thread.CustomerID,
+		Channel:           event.Channel,
+		Direction:         domain.MessageDirectionInbound,
+		FromAddress:       address,
+		ToAddress:         strings.TrimSpace(event.To),
+		Subject:           optionalString(strings.TrimSpace(event.Subject)),
+		Body:              body,
+		ProviderMessageID: optionalString(event.ProviderMessageID),
+		InReplyTo:         optionalString(event.InReplyTo),
+		Status:            domain.MessageStatusReceived,
+		CreatedAt:         receivedAt,
+		UpdatedAt:         receivedAt,
+	}
+	if err := s.conversationRepo.AddMessage(ctx, message); err != nil {
+		return false, fmt.Errorf("failed to save message: %w", err)
+	}
+
+	return true, nil
+}
+
+func (s *conversationServiceImpl) applyStatus(ctx context.Context, tenantID uuid.UUID, event *MessagingEvent) (bool, error) {
+	message, err := s.conversationRepo.GetMessageByProviderID(ctx, tenantID, event.ProviderMessageID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get message: %w", err)
+	}
+	if message == nil || message.Direction != domain.MessageDirectionOutbound {
+		return false, nil
+	}
+
+	switch event.Status {
+	case domain.MessageStatusDelivered:
+		// A late "delivered" must not hide an earlier failure report
+		if message.Status == domain.MessageStatusFailed {
+			return true, nil
+		}
+		message.Status = domain.MessageStatusDelivered
+	case domain.MessageStatusFailed:
+		message.Status = domain.MessageStatusFailed
+		message.Error = optionalString(event.Error)
+	default:
+		return false, nil
+	}
+	message.UpdatedAt = time.Now()
+
+	if err := s.conversationRepo.UpdateMessageStatus(ctx, message); err != nil {
+		return false, fmt.Errorf("failed to update message: %w", err)
+	}
+	return true, nil
+}
+
+// customerThread finds or starts the thread for a message to a customer
+func (s *conversationServiceImpl) customerThread(ctx context.Context, tenantID uuid.UUID, req *SendMessageRequest, subject string) (*domain.CommunicationThread, error) {
+	if req.CustomerID == nil {
+		return nil, fmt.Errorf("validation failed: choose a thread or a customer")
+	}
+	customer, err := s.getCustomer(ctx, tenantID, *req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var address string
+	switch req.Channel {
+	case domain.CommunicationChannelSMS:
+		address = NormalizeMessageAddress(req.Channel, stringValue(customer.Phone))
+		if address == "" {
+			return nil, fmt.Errorf("validation failed: customer has no phone number")
+		}
+	case domain.CommunicationChannelEmail:
+		address = NormalizeMessageAddress(req.Channel, stringValue(customer.Email))
+		if address == "" {
+			return nil, fmt.Errorf("validation failed: customer has no email address")
+		}
+	default:
+		return nil, fmt.Errorf("validation failed: channel must be sms or email")
+	}
+
+	threads, err := s.conversationRepo.FindThreads(ctx, tenantID, req.Channel, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find threads: %w", err)
+	}
+	if thread := MatchInboundThread(threads, req.Channel, subject, nil); thread != nil {
+		return thread, nil
+	}
+
+	now := time.Now()
+	thread := &domain.CommunicationThread{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		CustomerID: &customer.ID,
+		Channel:    req.Channel,
+		Address:    address,
+		Status:     domain.ThreadStatusOpen,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if req.Channel == domain.CommunicationChannelEmail {
+		thread.Subject = optionalString(NormalizeThreadSubject(subject))
+	}
+	if err := s.conversationRepo.CreateThread(ctx, thread); err != nil {
+		return nil, fmt.Errorf("failed to create thread: %w", err)
+	}
+	return thread, nil
+}
+
+func (s *conversationServiceImpl) lastInboundProviderID(ctx context.Context, tenantID, threadID uuid.UUID) (string, error) {
+	messages, err := s.conversationRepo.ListMessages(ctx, tenantID, threadID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list messages: %w", err)
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Direction == domain.MessageDirectionInbound && messages[i].ProviderMessageID != nil {
+			return *messages[i].ProviderMessageID, nil
+		}
+	}
+	return "", nil
+}
+
+func (s *conversationServiceImpl) findCustomer(ctx context.Context, tenantID uuid.UUID, channel, address string) (*uuid.UUID, error) {
+	var email, phone string
+	if channel == domain.CommunicationChannelEmail {
+		email = address
+	} else {
+		phone = address
+	}
+	customerID, err := s.conversationRepo.FindCustomerByContact(ctx, tenantID, email, phone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match customer: %w", err)
+	}
+	return customerID, nil
+}
+
+func (s *conversationServiceImpl) getThread(ctx context.Context, tenantID, threadID uuid.UUID) (*domain.CommunicationThread, error) {
+	thread, err := s.conversationRepo.GetThread(ctx, tenantID, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread: %w", err)
+	}
+	if thread == nil {
+		return nil, fmt.Errorf("thread not found")
+	}
+	return thread, nil
+}
+
+func (s *conversationServiceImpl) getCustomer(ctx context.Context, tenantID, customerID uuid.UUID) (*domain.EnhancedCustomer, error) {
+	customer, err := s.customerRepo.GetByID(ctx, tenantID, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	if customer == nil {
+		return nil, fmt.Errorf("customer not found")
+	}
+	return customer, nil
+}
+
+// NormalizeMessageAddress reduces a phone number to its digits (without a leading US
+// country code) or an email address to its lowercased address, dropping any display
+// name: "Jane Doe <Jane@Example.com>" gives "jane@example.com"
+func NormalizeMessageAddress(channel, raw string) string {
+	raw = strings.TrimSpace(raw)
+	if channel == domain.CommunicationChannelSMS {
+		return leadPhoneDigits(raw)
+	}
+	if start, end := strings.LastIndex(raw, "<"), strings.LastIndex(raw, ">"); start >= 0 && end > start {
+		raw = raw[start+1 : end]
+	}
+	return strings.ToLower(strings.TrimSpace(raw))
+}
+
+var threadSubjectPrefix = regexp.MustCompile(`(?i)^\s*((re|fw|fwd|aw|sv)\s*(\[\d+\])?\s*:|\[[^\]]*\])\s*`)
+
+// NormalizeThreadSubject strips reply and forward prefixes and bracketed tags from an
+// email subject, so "RE: Fwd: [External] Spring cleanup" gives "Spring cleanup"
+func NormalizeThreadSubject(subject string) string {
+	subject = strings.TrimSpace(subject)
+	for {
+		stripped := threadSubjectPrefix.ReplaceAllString(subject, "")
+		if stripped == subject {
+			break
+		}
+		subject = stripped
+	}
+	return strings.Join(strings.Fields(subject), " ")
+}
+
+// ReplySubject prefixes a subject with "Re:" unless it is already a reply
+func ReplySubject(subject string) string {
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return ""
+	}
+	if strings.HasPrefix(strings.ToLower(subject), "re:") {
+		return subject
+	}
+	return "Re: " + subject
+}
+
+// MatchInboundThread picks the thread a message belongs to from the threads with its
+// address (most recent first). A reply to a known message goes on that message's
+// thread. Texts continue the address's latest thread; emails continue the latest
+// thread with the same subject. Nil means the message starts a new thread.
+func MatchInboundThread(threads []*domain.CommunicationThread, channel, subject string, replyThreadID *uuid.UUID) *domain.CommunicationThread {
+	if replyThreadID != nil {
+		for _, thread := range threads {
+			if thread.ID == *replyThreadID {
+				return thread
+			}
+		}
+	}
+
+	sorted := append([]*domain.CommunicationThread(nil), threads...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return threadActivity(sorted[i]).After(threadActivity(sorted[j]))
+	})
+
+	subject = strings.ToLower(NormalizeThreadSubject(subject))
+	for _, thread := range sorted {
+		if thread.Channel != channel {
+			continue
+		}
+		if channel == domain.CommunicationChannelSMS {
+			return thread
+		}
+		if strings.ToLower(NormalizeThreadSubject(stringValue(thread.Subject))) == subject {
+			return thread
+		}
+	}
+	return nil
+}
+
+var quotedReplyHeader = regexp.MustCompile(`(?im)^(on\b.{0,200}\bwrote:\s*$|-{2,}\s*original message\s*-{2,}|from:\s.+\n\s*(sent|date):)`)
+
+// StripQuotedReply removes the quoted earlier conversation from an email reply: the
+// "On ... wrote:", "Original Message" or Outlook "From:/Sent:" header and everything
+// after it, and quoted "> " lines
+func StripQuotedReply(body string) string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	if loc := quotedReplyHeader.FindStringIndex(body); loc != nil {
+		body = body[:loc[0]]
+	}
+
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), ">") {
+			continue
+		}
+		lines = append(lines, strings.TrimRightFunc(line, unicode.IsSpace))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// MessagePreview flattens a message body to one line of at most MessagePreviewLength
+// characters
+func MessagePreview(body string) string {
+	preview := strings.Join(strings.Fields(body), " ")
+	if runes := []rune(preview); len(runes) > MessagePreviewLength {
+		preview = strings.TrimSpace(string(runes[:MessagePreviewLength-1])) + "…"
+	}
+	return preview
+}
+
+var timelineTitles = map[string]string{
+	"sms.inbound":                    "Text received",
+	"sms.outbound":                   "Text sent",
+	"email.inbound":                  "Email received",
+	"email.outbound":                 "Email sent",
+	"note.create":                    "Note added",
+	"payment.pending":                "Payment started",
+	"payment.completed":              "Payment received",
+	"payment.failed":                 "Payment failed",
+	"payment.refunded":               "Payment refunded",
+	"invoice.reminder":               "Payment reminder sent",
+	"invoice.create":                 "Invoice created",
+	"invoice.send":                   "Invoice sent",
+	"invoice.mark_paid":              "Invoice marked paid",
+	"invoice.late_fee_applied":       "Late fee applied",
+	"invoice.late_fee_waived":        "Late fee waived",
+	"portal.invoice_view":            "Invoice viewed",
+	"portal.invoice_payment":         "Invoice paid online",
+	"quote.create":                   "Quote created",
+	"quote.send":                     "Quote sent",
+	"quote.approve":                  "Quote approved",
+	"quote.reject":                   "Quote declined",
+	"portal.quote_view":              "Quote viewed",
+	"portal.quote_accept":            "Quote accepted online",
+	"job.create":                     "Job created",
+	"job.update":                     "Job updated",
+	"job.start":                      "Job started",
+	"job.complete":                   "Job completed",
+	"job.cancel":                     "Job cancelled",
+	"job.assign":                     "Job assigned",
+	"job.unassign":                   "Job unassigned",
+	"job.assign_crew":                "Crew assigned",
+	"portal.schedule_change_request": "Schedule change requested",
+	"portal.login":                   "Signed in to the portal",
+	"customer.create":                "Customer created",
+	"customer.update":                "Customer updated",
+	"customer.merge":                 "Duplicate customer merged in",
+	"customer.merge_undo":            "Customer merge undone",
+}
+
+// TimelineEventTitle describes a timeline action such as "quote.send" ("Quote sent").
+// Actions without a title of their own are spelled out: "job.update_services" gives
+// "Job update services".
+func TimelineEventTitle(action string) string {
+	if title, ok := timelineTitles[action]; ok {
+		return title
+	}
+	if noteType, ok := strings.CutPrefix(action, "collections."); ok {
+		return "Collections " + strings.ReplaceAll(noteType, "_", " ")
+	}
+
+	words := strings.Fields(strings.NewReplacer(".", " ", "_", " ").Replace(action))
+	if len(words) == 0 {
+		return ""
+	}
+	title := strings.Join(words, " ")
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// Helper functions
+
+func threadActivity(thread *domain.CommunicationThread) time.Time {
+	if thread.LastMessageAt != nil {
+		return *thread.LastMessageAt
+	}
+	return thread.CreatedAt
+}
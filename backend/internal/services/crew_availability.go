@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// availabilityCrewService wraps a CrewService so GetAvailableCrews also drops
+// crews with nobody working: every active member is off shift, on approved
+// time off or it is a holiday
+type availabilityCrewService struct {
+	CrewService
+	availabilityRepo AvailabilityRepository
+}
+
+// NewAvailabilityCrewService makes a crew service honour working hours, time
+// off and holidays when listing available crews
+func NewAvailabilityCrewService(crewService CrewService, availabilityRepo AvailabilityRepository) CrewService {
+	return &availabilityCrewService{
+		CrewService:      crewService,
+		availabilityRepo: availabilityRepo,
+	}
+}
+
+// GetAvailableCrews lists the crews the wrapped service finds available that
+// have at least one member working during the range
+func (s *availabilityCrewService) GetAvailableCrews(ctx context.Context, startDate, endDate time.Time) ([]*domain.Crew, error) {
+	crews, err := s.CrewService.GetAvailableCrews(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	available := make([]*domain.Crew, 0, len(crews))
+	for _, crew := range crews {
+		members, err := s.CrewService.GetCrewMembers(ctx, crew.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get crew members: %w", err)
+		}
+		memberIDs := activeCrewMemberIDs(members)
+		// Membership is the wrapped service's call when a crew has none
+		if len(memberIDs) == 0 {
+			available = append(available, crew)
+			continue
+		}
+
+		windows, _, err := loadWorkingWindows(ctx, s.availabilityRepo, tenantID, memberIDs, TimeRange{Start: startDate, End: endDate})
+		if err != nil {
+			return nil, err
+		}
+		if anyoneWorking(memberIDs, windows) {
+			available = append(available, crew)
+		}
+	}
+
+	return available, nil
+}
+
+func anyoneWorking(userIDs []uuid.UUID, windows map[uuid.UUID][]TimeRange) bool {
+	for _, userID := range userIDs {
+		if len(windows[userID]) > 0 {
+			return true
+		}
+	}
+	return false
+}
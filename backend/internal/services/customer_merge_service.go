@@ -0,0 +1,857 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// CustomerMergeService finds customers entered more than once and merges them. A merge
+// re-parents the duplicate's properties, jobs, quotes, invoices (and with them their
+// payments) and attachments onto the surviving customer in one transaction, and can
+// be undone within CustomerMergeUndoWindow.
+type CustomerMergeService interface {
+	FindDuplicates(ctx context.Context, filter *CustomerDuplicateFilter) ([]*CustomerDuplicate, error)
+	DismissDuplicate(ctx context.Context, customerAID, customerBID uuid.UUID) error
+
+	PreviewMerge(ctx context.Context, req *CustomerMergeRequest) (*CustomerMergePreview, error)
+	MergeCustomers(ctx context.Context, req *CustomerMergeRequest) (*domain.CustomerMerge, error)
+	UndoMerge(ctx context.Context, mergeID uuid.UUID) (*domain.CustomerMerge, error)
+	GetMerge(ctx context.Context, mergeID uuid.UUID) (*domain.CustomerMerge, error)
+	ListMerges(ctx context.Context, filter *CustomerMergeFilter) (*domain.PaginatedResponse, error)
+}
+
+// CustomerMergeRepository defines data access for customer merges
+type CustomerMergeRepository interface {
+	// CountRelated counts the records that reference a customer, by "table.column"
+	CountRelated(ctx context.Context, tenantID, customerID uuid.UUID) (map[string]int, error)
+	// MergeCustomers re-parents the merged customer's records onto the survivor, saves
+	// the survivor, marks the merged customer merged and stores the merge in one
+	// transaction. The IDs of the moved records are set on merge.MovedRecords.
+	MergeCustomers(ctx context.Context, merge *domain.CustomerMerge, survivor *domain.EnhancedCustomer) error
+	// UndoMerge moves the recorded records back, saves the survivor, restores the
+	// merged customer's status and updates the merge in one transaction
+	UndoMerge(ctx context.Context, merge *domain.CustomerMerge, survivor *domain.EnhancedCustomer) error
+	GetMerge(ctx context.Context, tenantID, mergeID uuid.UUID) (*domain.CustomerMerge, error)
+	ListMerges(ctx context.Context, tenantID uuid.UUID, filter *CustomerMergeFilter) ([]*domain.CustomerMerge, int64, error)
+
+	CreateDismissal(ctx context.Context, dismissal *domain.CustomerDuplicateDismissal) error
+	ListDismissals(ctx context.Context, tenantID uuid.UUID) ([]*domain.CustomerDuplicateDismissal, error)
+}
+
+// CustomerDuplicateFilter narrows the duplicate finder
+type CustomerDuplicateFilter struct {
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"` // only pairs including this customer
+	MinScore   float64    `json:"min_score,omitempty"`
+	Limit      int        `json:"limit,omitempty"`
+}
+
+// CustomerDuplicate is a pair of customers that look like the same person
+type CustomerDuplicate struct {
+	CustomerA *domain.EnhancedCustomer `json:"customer_a"`
+	CustomerB *domain.EnhancedCustomer `json:"customer_b"`
+	Score     float64                  `json:"score"`
+	Reasons   []string                 `json:"reasons"`
+}
+
+// CustomerMergeRequest merges MergedID into SurvivorID. The survivor keeps its own
+// values except for fields listed in Prefer with "merged"; blank survivor fields are
+// filled from the merged customer either way.
+type CustomerMergeRequest struct {
+	SurvivorID uuid.UUID         `json:"survivor_id"`
+	MergedID   uuid.UUID         `json:"merged_id"`
+	Prefer     map[string]string `json:"prefer,omitempty"` // field -> "survivor" or "merged"
+}
+
+// CustomerMergePreview shows the result of a merge before it is made
+type CustomerMergePreview struct {
+	Survivor       *domain.EnhancedCustomer              `json:"survivor"`
+	Merged         *domain.EnhancedCustomer              `json:"merged"`
+	Result         *domain.EnhancedCustomer              `json:"result"`
+	FieldChanges   map[string]domain.CustomerFieldChange `json:"field_changes"`
+	RecordsToMove  map[string]int                        `json:"records_to_move"`
+	Score          float64                               `json:"score"`
+	Reasons        []string                              `json:"reasons"`
+	UndoWindowDays int                                   `json:"undo_window_days"`
+}
+
+// CustomerMergeFilter filters the merge history
+type CustomerMergeFilter struct {
+	BaseFilter
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"` // merges with this customer on either side
+	Status     string     `json:"status,omitempty"`
+}
+
+// CustomerDuplicateThreshold is the default score from which customers are suggested
+// as duplicates
+const CustomerDuplicateThreshold = 0.75
+
+// CustomerMergeUndoWindow is how long a merge can be undone
+const CustomerMergeUndoWindow = 7 * 24 * time.Hour
+
+// CustomerMergeFields are the customer fields a merge combines. The address fields
+// move together so a merge never mixes two addresses.
+var CustomerMergeFields = []string{
+	"first_name", "last_name", "email", "phone", "company_name", "tax_id",
+	"address", "notes",
+}
+
+var customerAddressFields = []string{"address_line1", "address_line2", "city", "state", "zip_code"}
+
+// customerMergeServiceImpl implements CustomerMergeService
+type customerMergeServiceImpl struct {
+	mergeRepo    CustomerMergeRepository
+	customerRepo CustomerRepository
+	auditService AuditService
+	logger       *log.Logger
+}
+
+// NewCustomerMergeService creates a new customer merge service
+func NewCustomerMergeService(
+	mergeRepo CustomerMergeRepository,
+	customerRepo CustomerRepository,
+	auditService AuditService,
+	logger *log.Logger,
+) CustomerMergeService {
+	return &customerMergeServiceImpl{
+		mergeRepo:    mergeRepo,
+		customerRepo: customerRepo,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// FindDuplicates scores likely duplicate pairs among the tenant's customers, best
+// first. Pairs dismissed as not duplicates are left out.
+func (s *customerMergeServiceImpl) FindDuplicates(ctx context.Context, filter *CustomerDuplicateFilter) ([]*CustomerDuplicate, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	// Set defaults
+	if filter == nil {
+		filter = &CustomerDuplicateFilter{}
+	}
+	if filter.MinScore <= 0 {
+		filter.MinScore = CustomerDuplicateThreshold
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	if filter.Limit > 500 {
+		filter.Limit = 500
+	}
+
+	customers, err := s.activeCustomers(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	dismissals, err := s.mergeRepo.ListDismissals(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dismissed duplicates: %w", err)
+	}
+	dismissed := make(map[[2]uuid.UUID]bool, len(dismissals))
+	for _, dismissal := range dismissals {
+		dismissed[[2]uuid.UUID{dismissal.CustomerAID, dismissal.CustomerBID}] = true
+	}
+
+	duplicates := FindCustomerDuplicates(customers, filter.MinScore, func(a, b uuid.UUID) bool {
+		if filter.CustomerID != nil && a != *filter.CustomerID && b != *filter.CustomerID {
+			return true
+		}
+		return dismissed[customerPairKey(a, b)]
+	})
+	if len(duplicates) > filter.Limit {
+		duplicates = duplicates[:filter.Limit]
+	}
+
+	return duplicates, nil
+}
+
+// DismissDuplicate records that two customers are not the same
+func (s *customerMergeServiceImpl) DismissDuplicate(ctx context.Context, customerAID, customerBID uuid.UUID) error {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("tenant ID not found in context")
+	}
+	if customerAID == customerBID {
+		return fmt.Errorf("validation failed: choose two different customers")
+	}
+
+	for _, id := range []uuid.UUID{customerAID, customerBID} {
+		customer, err := s.customerRepo.GetByID(ctx, tenantID, id)
+		if err != nil {
+			return fmt.Errorf("failed to get customer: %w", err)
+		}
+		if customer == nil {
+			return fmt.Errorf("customer not found")
+		}
+	}
+
+	pair := customerPairKey(customerAID, customerBID)
+	dismissal := &domain.CustomerDuplicateDismissal{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		CustomerAID: pair[0],
+		CustomerBID: pair[1],
+		DismissedBy: GetUserIDFromContext(ctx),
+		CreatedAt:   time.Now(),
+	}
+	if err := s.mergeRepo.CreateDismissal(ctx, dismissal); err != nil {
+		return fmt.Errorf("failed to dismiss duplicate: %w", err)
+	}
+
+	return nil
+}
+
+// PreviewMerge shows the merged customer and the records that would move
+func (s *customerMergeServiceImpl) PreviewMerge(ctx context.Context, req *CustomerMergeRequest) (*CustomerMergePreview, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	survivor, merged, err := s.mergePair(ctx, tenantID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.mergeRepo.CountRelated(ctx, tenantID, merged.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count related records: %w", err)
+	}
+
+	result, changes := MergeCustomerFields(survivor, merged, req.Prefer)
+	score, reasons := ScoreCustomerDuplicate(survivor, merged)
+
+	return &CustomerMergePreview{
+		Survivor:       survivor,
+		Merged:         merged,
+		Result:         result,
+		FieldChanges:   changes,
+		RecordsToMove:  counts,
+		Score:          score,
+		Reasons:        reasons,
+		UndoWindowDays: int(CustomerMergeUndoWindow / (24 * time.Hour)),
+	}, nil
+}
+
+// MergeCustomers merges one customer into another
+func (s *customerMergeServiceImpl) MergeCustomers(ctx context.Context, req *CustomerMergeRequest) (*domain.CustomerMerge, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	survivor, merged, err := s.mergePair(ctx, tenantID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, changes := MergeCustomerFields(survivor, merged, req.Prefer)
+	score, _ := ScoreCustomerDuplicate(survivor, merged)
+
+	now := time.Now()
+	result.UpdatedAt = now
+	merge := &domain.CustomerMerge{
+		ID:            uuid.New(),
+		TenantID:      tenantID,
+		SurvivorID:    survivor.ID,
+		MergedID:      merged.ID,
+		Score:         &score,
+		FieldChanges:  changes,
+		MovedRecords:  map[string][]uuid.UUID{},
+		MergedStatus:  merged.Status,
+		Status:        domain.CustomerMergeStatusMerged,
+		UndoExpiresAt: now.Add(CustomerMergeUndoWindow),
+		MergedBy:      GetUserIDFromContext(ctx),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.mergeRepo.MergeCustomers(ctx, merge, result); err != nil {
+		return nil, fmt.Errorf("failed to merge customers: %w", err)
+	}
+
+	moved := make(map[string]int, len(merge.MovedRecords))
+	for key, ids := range merge.MovedRecords {
+		moved[key] = len(ids)
+	}
+	s.logAudit(ctx, "customer.merge", survivor.ID, map[string]interface{}{
+		"fields": customerFieldValues(changes, false),
+	}, map[string]interface{}{
+		"merge_id":      merge.ID,
+		"merged_id":     merged.ID,
+		"merged_name":   customerDisplayName(merged),
+		"fields":        customerFieldValues(changes, true),
+		"moved_records": moved,
+	})
+	s.logAudit(ctx, "customer.merged_into", merged.ID, map[string]interface{}{
+		"status": merged.Status,
+	}, map[string]interface{}{
+		"status":      domain.CustomerStatusMerged,
+		"merge_id":    merge.ID,
+		"survivor_id": survivor.ID,
+	})
+
+	return merge, nil
+}
+
+// UndoMerge reverses a merge within the undo window. Records re-parented by the merge
+// move back to the restored customer; anything added to the survivor since stays.
+// Survivor fields edited since the merge keep their new values.
+func (s *customerMergeServiceImpl) UndoMerge(ctx context.Context, mergeID uuid.UUID) (*domain.CustomerMerge, error) {
+	merge, err := s.GetMerge(ctx, mergeID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if merge.Status != domain.CustomerMergeStatusMerged {
+		return nil, fmt.Errorf("the merge has already been undone")
+	}
+	if !merge.CanUndo(now) {
+		return nil, fmt.Errorf("the undo window for this merge has expired")
+	}
+
+	survivor, err := s.customerRepo.GetByID(ctx, merge.TenantID, merge.SurvivorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	if survivor == nil {
+		return nil, fmt.Errorf("customer not found")
+	}
+	if survivor.Status == domain.CustomerStatusMerged {
+		return nil, fmt.Errorf("the surviving customer has since been merged into another customer; undo that merge first")
+	}
+
+	kept := RevertCustomerMergeFields(survivor, merge.FieldChanges)
+	survivor.UpdatedAt = now
+
+	merge.Status = domain.CustomerMergeStatusUndone
+	merge.UndoneBy = GetUserIDFromContext(ctx)
+	merge.UndoneAt = &now
+	merge.UpdatedAt = now
+
+	if err := s.mergeRepo.UndoMerge(ctx, merge, survivor); err != nil {
+		return nil, fmt.Errorf("failed to undo merge: %w", err)
+	}
+
+	s.logAudit(ctx, "customer.merge_undo", merge.SurvivorID, map[string]interface{}{
+		"merge_id": merge.ID,
+	}, map[string]interface{}{
+		"restored_id":  merge.MergedID,
+		"kept_changes": kept,
+	})
+	s.logAudit(ctx, "customer.merge_undo", merge.MergedID, map[string]interface{}{
+		"status": domain.CustomerStatusMerged,
+	}, map[string]interface{}{
+		"status":   merge.MergedStatus,
+		"merge_id": merge.ID,
+	})
+
+	return merge, nil
+}
+
+// GetMerge retrieves a merge
+func (s *customerMergeServiceImpl) GetMerge(ctx context.Context, mergeID uuid.UUID) (*domain.CustomerMerge, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	merge, err := s.mergeRepo.GetMerge(ctx, tenantID, mergeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge: %w", err)
+	}
+	if merge == nil {
+		return nil, fmt.Errorf("merge not found")
+	}
+
+	return merge, nil
+}
+
+// ListMerges lists the tenant's merges, newest first
+func (s *customerMergeServiceImpl) ListMerges(ctx context.Context, filter *CustomerMergeFilter) (*domain.PaginatedResponse, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	// Set defaults
+	if filter == nil {
+		filter = &CustomerMergeFilter{}
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PerPage <= 0 {
+		filter.PerPage = 50
+	}
+	if filter.PerPage > 100 {
+		filter.PerPage = 100
+	}
+
+	merges, total, err := s.mergeRepo.ListMerges(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merges: %w", err)
+	}
+
+	totalPages := int((total + int64(filter.PerPage) - 1) / int64(filter.PerPage))
+
+	return &domain.PaginatedResponse{
+		Data:       merges,
+		Total:      total,
+		Page:       filter.Page,
+		PerPage:    filter.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// FindCustomerDuplicates scores pairs of customers sharing an email, phone number, ZIP
+// code or the start of a last name, returning pairs scoring at least minScore, best
+// first. skip leaves out pairs, e.g. ones already dismissed.
+func FindCustomerDuplicates(customers []*domain.EnhancedCustomer, minScore float64, skip func(a, b uuid.UUID) bool) []*CustomerDuplicate {
+	blocks := map[string][]*domain.EnhancedCustomer{}
+	for _, customer := range customers {
+		for _, key := range importBlockingKeys(stringValue(customer.Email), stringValue(customer.Phone), stringValue(customer.ZipCode), customer.LastName) {
+			blocks[key] = append(blocks[key], customer)
+		}
+	}
+
+	seen := map[[2]uuid.UUID]bool{}
+	var duplicates []*CustomerDuplicate
+	for _, block := range blocks {
+		for i := 0; i < len(block); i++ {
+			for j := i + 1; j < len(block); j++ {
+				a, b := block[i], block[j]
+				pair := customerPairKey(a.ID, b.ID)
+				if a.ID == b.ID || seen[pair] {
+					continue
+				}
+				seen[pair] = true
+				if skip != nil && skip(a.ID, b.ID) {
+					continue
+				}
+
+				score, reasons := ScoreCustomerDuplicate(a, b)
+				if score < minScore {
+					continue
+				}
+				// The older record is listed first as the likely survivor
+				if b.CreatedAt.Before(a.CreatedAt) {
+					a, b = b, a
+				}
+				duplicates = append(duplicates, &CustomerDuplicate{CustomerA: a, CustomerB: b, Score: score, Reasons: reasons})
+			}
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Score != duplicates[j].Score {
+			return duplicates[i].Score > duplicates[j].Score
+		}
+		return duplicates[i].CustomerA.ID.String() < duplicates[j].CustomerA.ID.String()
+	})
+
+	return duplicates
+}
+
+// ScoreCustomerDuplicate scores how likely two customers are the same, from 0 to 1,
+// with the reasons. Name, email, phone and address are weighted and averaged over the
+// signals both customers have. A differing email or phone counts against the pair at
+// half weight, since people often have more than one.
+// Common nicknames match their full name, so "Bob Smith" matches "Robert Smith". A
+// matching name alone scores at most 0.6 since names are often shared.
+func ScoreCustomerDuplicate(a, b *domain.EnhancedCustomer) (float64, []string) {
+	reasons := []string{}
+	total, weight := 0.0, 0.0
+
+	name := customerNameSimilarity(a, b)
+	total += 0.5 * name
+	weight += 0.5
+	switch {
+	case name == 1:
+		reasons = append(reasons, "same name")
+	case name >= 0.8:
+		reasons = append(reasons, "similar name")
+	}
+
+	if emailA, emailB := strings.ToLower(stringValue(a.Email)), strings.ToLower(stringValue(b.Email)); emailA != "" && emailB != "" {
+		if emailA == emailB {
+			total += 0.3
+			weight += 0.3
+			reasons = append(reasons, "same email")
+		} else {
+			weight += 0.15
+		}
+	}
+
+	if phoneA, phoneB := leadPhoneDigits(stringValue(a.Phone)), leadPhoneDigits(stringValue(b.Phone)); phoneA != "" && phoneB != "" {
+		if phoneA == phoneB {
+			total += 0.25
+			weight += 0.25
+			reasons = append(reasons, "same phone")
+		} else {
+			weight += 0.125
+		}
+	}
+
+	if addressA, addressB := stringValue(a.AddressLine1), stringValue(b.AddressLine1); addressA != "" && addressB != "" {
+		address := ImportSimilarity(addressA, addressB)
+		if zipA, zipB := stringValue(a.ZipCode), stringValue(b.ZipCode); len(zipA) >= 5 && len(zipB) >= 5 && zipA[:5] != zipB[:5] {
+			address /= 2
+		}
+		weight += 0.2
+		total += 0.2 * address
+		switch {
+		case address == 1:
+			reasons = append(reasons, "same address")
+		case address >= 0.85:
+			reasons = append(reasons, "similar address")
+		}
+	}
+
+	score := total / weight
+	if weight == 0.5 && score > 0.6 {
+		score = 0.6
+	}
+
+	return float64(int(score*1000+0.5)) / 1000, reasons
+}
+
+// MergeCustomerFields returns a copy of the survivor with the merged customer's values
+// folded in, and the fields that changed. Blank survivor fields are filled in; fields
+// preferred as "merged" take the merged value. Notes are combined, and an email or
+// phone number the result doesn't keep is added to the notes so nothing is lost.
+func MergeCustomerFields(survivor, merged *domain.EnhancedCustomer, prefer map[string]string) (*domain.EnhancedCustomer, map[string]domain.CustomerFieldChange) {
+	result := *survivor
+	changes := map[string]domain.CustomerFieldChange{}
+
+	set := func(field, value string) {
+		old := customerFieldValue(&result, field)
+		if old == value {
+			return
+		}
+		setCustomerFieldValue(&result, field, value)
+		change, ok := changes[field]
+		if !ok {
+			change.Old = old
+		}
+		change.New = value
+		changes[field] = change
+	}
+
+	for _, field := range []string{"first_name", "last_name", "email", "phone", "company_name", "tax_id"} {
+		value := customerFieldValue(merged, field)
+		if value == "" {
+			continue
+		}
+		if customerFieldValue(&result, field) == "" || prefer[field] == "merged" {
+			set(field, value)
+		}
+	}
+
+	if customerFieldValue(merged, "address_line1") != "" &&
+		(customerFieldValue(&result, "address_line1") == "" || prefer["address"] == "merged") {
+		for _, field := range customerAddressFields {
+			set(field, customerFieldValue(merged, field))
+		}
+	}
+
+	var notes []string
+	if existing := customerFieldValue(&result, "notes"); existing != "" {
+		notes = append(notes, existing)
+	}
+	if mergedNotes := customerFieldValue(merged, "notes"); mergedNotes != "" && mergedNotes != customerFieldValue(&result, "notes") {
+		notes = append(notes, mergedNotes)
+	}
+	var otherContact []string
+	for _, field := range []string{"email", "phone"} {
+		kept := customerFieldValue(&result, field)
+		for _, value := range []string{customerFieldValue(survivor, field), customerFieldValue(merged, field)} {
+			if value != "" && value != kept {
+				otherContact = append(otherContact, field+" "+value)
+			}
+		}
+	}
+	if len(otherContact) > 0 {
+		notes = append(notes, fmt.Sprintf("Merged from %s: %s", customerDisplayName(merged), strings.Join(otherContact, ", ")))
+	}
+	set("notes", strings.Join(notes, "\n\n"))
+
+	return &result, changes
+}
+
+// RevertCustomerMergeFields puts back the survivor values a merge changed. A field
+// edited since the merge keeps its current value; those fields are returned.
+func RevertCustomerMergeFields(customer *domain.EnhancedCustomer, changes map[string]domain.CustomerFieldChange) []string {
+	kept := []string{}
+	for field, change := range changes {
+		if customerFieldValue(customer, field) != change.New {
+			kept = append(kept, field)
+			continue
+		}
+		setCustomerFieldValue(customer, field, change.Old)
+	}
+	sort.Strings(kept)
+	return kept
+}
+
+// Helper functions
+
+// mergePair loads and checks the two customers of a merge request
+func (s *customerMergeServiceImpl) mergePair(ctx context.Context, tenantID uuid.UUID, req *CustomerMergeRequest) (*domain.EnhancedCustomer, *domain.EnhancedCustomer, error) {
+	if req.SurvivorID == uuid.Nil || req.MergedID == uuid.Nil {
+		return nil, nil, fmt.Errorf("validation failed: survivor and merged customers are required")
+	}
+	if req.SurvivorID == req.MergedID {
+		return nil, nil, fmt.Errorf("validation failed: a customer can't be merged into itself")
+	}
+	for field, choice := range req.Prefer {
+		if !containsString(CustomerMergeFields, field) {
+			return nil, nil, fmt.Errorf("validation failed: unknown merge field %q", field)
+		}
+		if choice != "survivor" && choice != "merged" {
+			return nil, nil, fmt.Errorf("validation failed: prefer %s must be survivor or merged", field)
+		}
+	}
+
+	survivor, err := s.customerRepo.GetByID(ctx, tenantID, req.SurvivorID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	merged, err := s.customerRepo.GetByID(ctx, tenantID, req.MergedID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	if survivor == nil || merged == nil {
+		return nil, nil, fmt.Errorf("customer not found")
+	}
+	if survivor.Status == domain.CustomerStatusMerged || merged.Status == domain.CustomerStatusMerged {
+		return nil, nil, fmt.Errorf("customer has already been merged into another customer")
+	}
+
+	return survivor, merged, nil
+}
+
+// activeCustomers loads the tenant's customers that aren't deleted or merged
+func (s *customerMergeServiceImpl) activeCustomers(ctx context.Context, tenantID uuid.UUID) ([]*domain.EnhancedCustomer, error) {
+	var customers []*domain.EnhancedCustomer
+	filter := &CustomerFilter{BaseFilter: BaseFilter{Page: 1, PerPage: 500}}
+	loaded := 0
+	for {
+		page, total, err := s.customerRepo.List(ctx, tenantID, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list customers: %w", err)
+		}
+		for _, customer := range page {
+			if customer.Status != "deleted" && customer.Status != domain.CustomerStatusMerged {
+				customers = append(customers, customer)
+			}
+		}
+		loaded += len(page)
+		if len(page) < filter.PerPage || int64(loaded) >= total {
+			break
+		}
+		filter.Page++
+	}
+	return customers, nil
+}
+
+func (s *customerMergeServiceImpl) logAudit(ctx context.Context, action string, customerID uuid.UUID, oldValues, newValues map[string]interface{}) {
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       action,
+		ResourceType: "customer",
+		ResourceID:   &customerID,
+		OldValues:    oldValues,
+		NewValues:    newValues,
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+}
+
+// customerNameSimilarity compares first and last names, nickname-aware, or company
+// names when both customers have one
+func customerNameSimilarity(a, b *domain.EnhancedCustomer) float64 {
+	last := ImportSimilarity(a.LastName, b.LastName)
+
+	score := last * 0.9
+	if a.FirstName != "" && b.FirstName != "" {
+		score = 0.5*last + 0.5*firstNameSimilarity(a.FirstName, b.FirstName)
+	}
+
+	if companyA, companyB := stringValue(a.CompanyName), stringValue(b.CompanyName); companyA != "" && companyB != "" {
+		score = maxFloat(score, ImportSimilarity(companyA, companyB))
+	}
+
+	return score
+}
+
+func firstNameSimilarity(a, b string) float64 {
+	a, b = importNormalize(a), importNormalize(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+	for _, canonical := range customerNicknames(a) {
+		if containsString(customerNicknames(b), canonical) {
+			return 1
+		}
+	}
+	// An initial matches a name starting with it
+	if (len(a) == 1 || len(b) == 1) && a[0] == b[0] {
+		return 0.9
+	}
+	return ImportSimilarity(a, b)
+}
+
+// customerNicknames lists the full names a first name or nickname can stand for;
+// "chris" is both Christopher and Christine
+func customerNicknames(name string) []string {
+	var names []string
+	for canonical, nicknames := range firstNameNicknames {
+		if name == canonical || containsString(nicknames, name) {
+			names = append(names, canonical)
+		}
+	}
+	return names
+}
+
+var firstNameNicknames = map[string][]string{
+	"robert":      {"bob", "bobby", "rob", "robbie", "bert"},
+	"william":     {"bill", "billy", "will", "willie", "liam"},
+	"richard":     {"rick", "ricky", "dick", "rich"},
+	"james":       {"jim", "jimmy", "jamie"},
+	"john":        {"jack", "johnny", "jon"},
+	"joseph":      {"joe", "joey"},
+	"michael":     {"mike", "mikey", "mick"},
+	"thomas":      {"tom", "tommy"},
+	"charles":     {"charlie", "chuck", "chas"},
+	"christopher": {"chris", "topher"},
+	"daniel":      {"dan", "danny"},
+	"david":       {"dave", "davey"},
+	"edward":      {"ed", "eddie", "ted", "ned"},
+	"anthony":     {"tony"},
+	"andrew":      {"andy", "drew"},
+	"matthew":     {"matt"},
+	"nicholas":    {"nick", "nicky"},
+	"steven":      {"steve", "stephen"},
+	"gregory":     {"greg"},
+	"jonathan":    {"jon", "jonny"},
+	"benjamin":    {"ben", "benny"},
+	"samuel":      {"sam", "sammy"},
+	"alexander":   {"alex", "al"},
+	"timothy":     {"tim", "timmy"},
+	"kenneth":     {"ken", "kenny"},
+	"ronald":      {"ron", "ronnie"},
+	"donald":      {"don", "donnie"},
+	"lawrence":    {"larry"},
+	"elizabeth":   {"liz", "beth", "betty", "eliza", "lizzie", "betsy"},
+	"margaret":    {"maggie", "meg", "peggy", "marge"},
+	"katherine":   {"kate", "katie", "kathy", "cathy", "catherine", "kathryn"},
+	"jennifer":    {"jen", "jenny"},
+	"patricia":    {"pat", "patty", "trish"},
+	"susan":       {"sue", "susie"},
+	"deborah":     {"deb", "debbie", "debra"},
+	"rebecca":     {"becky", "becca"},
+	"victoria":    {"vicky", "tori"},
+	"jessica":     {"jess", "jessie"},
+	"christine":   {"chris", "christina", "tina"},
+	"barbara":     {"barb", "barbie"},
+	"dorothy":     {"dot", "dottie"},
+	"abigail":     {"abby"},
+	"samantha":    {"sam", "sammy"},
+}
+
+// customerPairKey orders two customer IDs so a pair has one key
+func customerPairKey(a, b uuid.UUID) [2]uuid.UUID {
+	if b.String() < a.String() {
+		a, b = b, a
+	}
+	return [2]uuid.UUID{a, b}
+}
+
+func customerFieldValue(customer *domain.EnhancedCustomer, field string) string {
+	switch field {
+	case "first_name":
+		return customer.FirstName
+	case "last_name":
+		return customer.LastName
+	case "email":
+		return stringValue(customer.Email)
+	case "phone":
+		return stringValue(customer.Phone)
+	case "company_name":
+		return stringValue(customer.CompanyName)
+	case "tax_id":
+		return stringValue(customer.TaxID)
+	case "address_line1":
+		return stringValue(customer.AddressLine1)
+	case "address_line2":
+		return stringValue(customer.AddressLine2)
+	case "city":
+		return stringValue(customer.City)
+	case "state":
+		return stringValue(customer.State)
+	case "zip_code":
+		return stringValue(customer.ZipCode)
+	case "notes":
+		return stringValue(customer.Notes)
+	}
+	return ""
+}
+
+func setCustomerFieldValue(customer *domain.EnhancedCustomer, field, value string) {
+	switch field {
+	case "first_name":
+		customer.FirstName = value
+	case "last_name":
+		customer.LastName = value
+	case "email":
+		customer.Email = optionalString(value)
+	case "phone":
+		customer.Phone = optionalString(value)
+	case "company_name":
+		customer.CompanyName = optionalString(value)
+	case "tax_id":
+		customer.TaxID = optionalString(value)
+	case "address_line1":
+		customer.AddressLine1 = optionalString(value)
+	case "address_line2":
+		customer.AddressLine2 = optionalString(value)
+	case "city":
+		customer.City = optionalString(value)
+	case "state":
+		customer.State = optionalString(value)
+	case "zip_code":
+		customer.ZipCode = optionalString(value)
+	case "notes":
+		customer.Notes = optionalString(value)
+	}
+}
+
+// customerFieldValues flattens field changes to their old or new values for the audit log
+func customerFieldValues(changes map[string]domain.CustomerFieldChange, newValues bool) map[string]string {
+	values := make(map[string]string, len(changes))
+	for field, change := range changes {
+		if newValues {
+			values[field] = change.New
+		} else {
+			values[field] = change.Old
+		}
+	}
+	return values
+}
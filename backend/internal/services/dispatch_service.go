@@ -0,0 +1,1392 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// DispatchService runs the dispatch board: every crew's members, equipment,
+// jobs and route for a day. Daily rosters override standing crew membership,
+// and people, jobs and equipment can be dragged between crews. Each move is
+// checked for conflicts before it is saved and returns the board as it now
+// stands so connected clients can be updated.
+type DispatchService interface {
+	GetBoard(ctx context.Context, date time.Time) (*DispatchBoard, error)
+
+	// SetRoster replaces who rides with the crew on the date and what they take
+	SetRoster(ctx context.Context, crewID uuid.UUID, date time.Time, req *CrewRosterRequest) (*DispatchChange, error)
+	// ClearRoster returns the crew to its standing membership for the date
+	ClearRoster(ctx context.Context, crewID uuid.UUID, date time.Time) (*DispatchChange, error)
+
+	MoveMember(ctx context.Context, req *DispatchMemberMove) (*DispatchChange, error)
+	MoveJob(ctx context.Context, req *DispatchJobMove) (*DispatchChange, error)
+	MoveEquipment(ctx context.Context, req *DispatchEquipmentMove) (*DispatchChange, error)
+}
+
+// DispatchRepository defines data access for crews and their daily rosters
+type DispatchRepository interface {
+	ListActiveCrews(ctx context.Context, tenantID uuid.UUID) ([]*domain.Crew, error)
+
+	// ListStandingMembers lists the current members of the tenant's crews
+	ListStandingMembers(ctx context.Context, tenantID uuid.UUID) ([]*domain.CrewMember, error)
+
+	// ListRosters lists the rosters saved for the date, with their members
+	ListRosters(ctx context.Context, tenantID uuid.UUID, date time.Time) ([]*domain.CrewRoster, error)
+	// SaveRosters creates or replaces the rosters and their members in one transaction
+	SaveRosters(ctx context.Context, tenantID uuid.UUID, rosters []*domain.CrewRoster) error
+	DeleteRoster(ctx context.Context, tenantID, crewID uuid.UUID, date time.Time) error
+
+	// GetUserNames returns the users' display names
+	GetUserNames(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]string, error)
+}
+
+// Dispatch conflict types
+const (
+	DispatchConflictMember    = "member"
+	DispatchConflictJob       = "job"
+	DispatchConflictEquipment = "equipment"
+)
+
+// dispatchJobMinutes is how long a job without an estimate occupies its crew
+const dispatchJobMinutes = 120
+
+// CrewRosterRequest sets a crew's roster for a day
+type CrewRosterRequest struct {
+	Members      []CrewRosterMemberRequest `json:"members"`
+	EquipmentIDs []uuid.UUID               `json:"equipment_ids,omitempty"` // the crew's own equipment when nil
+	Notes        *string                   `json:"notes,omitempty"`
+}
+
+// CrewRosterMemberRequest puts a user on a roster, as a member unless a role is given
+type CrewRosterMemberRequest struct {
+	UserID uuid.UUID `json:"user_id" validate:"required"`
+	Role   string    `json:"role,omitempty"`
+}
+
+// DispatchMemberMove drags a person between crews for a day. FromCrewID is the
+// crew the board showed them on, nil when they were on none; the move is
+// refused if the board was stale. A nil ToCrewID takes them off every crew.
+type DispatchMemberMove struct {
+	Date       time.Time  `json:"date" validate:"required"`
+	UserID     uuid.UUID  `json:"user_id" validate:"required"`
+	FromCrewID *uuid.UUID `json:"from_crew_id,omitempty"`
+	ToCrewID   *uuid.UUID `json:"to_crew_id,omitempty"`
+	Role       string     `json:"role,omitempty"`
+}
+
+// DispatchJobMove drags a job onto a crew or a person, optionally at a new
+// time. The crew lead takes the job unless a user is given; with neither the
+// job is unassigned.
+type DispatchJobMove struct {
+	JobID         uuid.UUID  `json:"job_id" validate:"required"`
+	CrewID        *uuid.UUID `json:"crew_id,omitempty"`
+	UserID        *uuid.UUID `json:"user_id,omitempty"`
+	ScheduledDate *time.Time `json:"scheduled_date,omitempty"`
+}
+
+// DispatchEquipmentMove drags equipment between crews for a day, with the same
+// stale-board check as DispatchMemberMove
+type DispatchEquipmentMove struct {
+	Date        time.Time  `json:"date" validate:"required"`
+	EquipmentID uuid.UUID  `json:"equipment_id" validate:"required"`
+	FromCrewID  *uuid.UUID `json:"from_crew_id,omitempty"`
+	ToCrewID    *uuid.UUID `json:"to_crew_id,omitempty"`
+}
+
+// DispatchBoard is the day's dispatch: each crew with its route, and the jobs
+// and people not on any crew
+type DispatchBoard struct {
+	Date       time.Time             `json:"date"`
+	Crews      []*DispatchCrew       `json:"crews"`
+	Unassigned []*domain.EnhancedJob `json:"unassigned_jobs"`
+	Bench      []*DispatchMember     `json:"bench"`
+}
+
+// DispatchCrew is one crew's day. Rostered is set when a daily roster
+// overrides the crew's standing membership.
+type DispatchCrew struct {
+	Crew      *domain.Crew          `json:"crew"`
+	Rostered  bool                  `json:"rostered"`
+	Notes     *string               `json:"notes,omitempty"`
+	Members   []*DispatchMember     `json:"members"`
+	Equipment []*DispatchEquipment  `json:"equipment"`
+	Jobs      []*domain.EnhancedJob `json:"jobs"`
+	Route     []RouteStop           `json:"route"`
+	Conflicts []DispatchConflict    `json:"conflicts"`
+}
+
+// DispatchMember is a person on the board with when they are working that day
+type DispatchMember struct {
+	UserID      uuid.UUID              `json:"user_id"`
+	Name        string                 `json:"name"`
+	Role        string                 `json:"role"`
+	Working     []TimeRange            `json:"working"`
+	Unavailable []AvailabilityConflict `json:"unavailable,omitempty"`
+}
+
+// DispatchEquipment is equipment riding with a crew and when it is out of service
+type DispatchEquipment struct {
+	EquipmentID uuid.UUID   `json:"equipment_id"`
+	Name        string      `json:"name"`
+	Downtime    []TimeRange `json:"downtime,omitempty"`
+}
+
+// DispatchConflict is a problem with a crew's day that a dispatcher should fix
+type DispatchConflict struct {
+	Type       string     `json:"type"`
+	ResourceID uuid.UUID  `json:"resource_id"`
+	JobID      *uuid.UUID `json:"job_id,omitempty"`
+	Reason     string     `json:"reason"`
+}
+
+// DispatchChange is the result of a board edit, pushed to connected clients.
+// CrewIDs are the crews the edit touched and Board is the whole day after it.
+type DispatchChange struct {
+	Action   string         `json:"action"`
+	Date     time.Time      `json:"date"`
+	CrewIDs  []uuid.UUID    `json:"crew_ids"`
+	Warnings []string       `json:"warnings"`
+	Board    *DispatchBoard `json:"board"`
+}
+
+// DispatchBoardInput is everything BuildDispatchBoard needs. Windows and
+// Unavailable come from WorkingWindows, Downtime is keyed by equipment and
+// Locations by property.
+type DispatchBoardInput struct {
+	Date         time.Time
+	Crews        []*domain.Crew
+	Standing     []*domain.CrewMember
+	Rosters      map[uuid.UUID]*domain.CrewRoster
+	Jobs         []*domain.EnhancedJob
+	Windows      map[uuid.UUID][]TimeRange
+	Unavailable  map[uuid.UUID][]AvailabilityConflict
+	Reservations []*domain.EquipmentReservation
+	Downtime     map[uuid.UUID][]TimeRange
+	Equipment    map[uuid.UUID]*domain.Equipment
+	Names        map[uuid.UUID]string
+	Locations    map[uuid.UUID]*Location
+}
+
+// DispatchServiceImpl implements DispatchService
+type DispatchServiceImpl struct {
+	dispatchRepo      DispatchRepository
+	jobRepo           JobRepositoryComplete
+	propertyRepo      PropertyRepositoryExtended
+	equipmentRepo     EquipmentRepository
+	reservationRepo   EquipmentReservationRepository
+	workOrderRepo     MaintenanceWorkOrderRepository
+	availabilityRepo  AvailabilityRepository
+	customerRepo      CustomerRepository
+	certificationRepo CertificationRepository
+	auditService      AuditService
+	logger            *log.Logger
+}
+
+// NewDispatchService creates a new dispatch service
+func NewDispatchService(
+	dispatchRepo DispatchRepository,
+	jobRepo JobRepositoryComplete,
+	propertyRepo PropertyRepositoryExtended,
+	equipmentRepo EquipmentRepository,
+	reservationRepo EquipmentReservationRepository,
+	workOrderRepo MaintenanceWorkOrderRepository,
+	availabilityRepo AvailabilityRepository,
+	customerRepo CustomerRepository,
+	certificationRepo CertificationRepository,
+	auditService AuditService,
+	logger *log.Logger,
+) DispatchService {
+	return &DispatchServiceImpl{
+		dispatchRepo:      dispatchRepo,
+		jobRepo:           jobRepo,
+		propertyRepo:      propertyRepo,
+		equipmentRepo:     equipmentRepo,
+		reservationRepo:   reservationRepo,
+		workOrderRepo:     workOrderRepo,
+		availabilityRepo:  availabilityRepo,
+		customerRepo:      customerRepo,
+		certificationRepo: certificationRepo,
+		auditService:      auditService,
+		logger:            logger,
+	}
+}
+
+// GetBoard returns the dispatch board for the date
+func (s *DispatchServiceImpl) GetBoard(ctx context.Context, date time.Time) (*DispatchBoard, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	input, err := s.loadDay(ctx, tenantID, startOfDay(date))
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildDispatchBoard(input), nil
+}
+
+// SetRoster replaces the crew's roster for the date. People and equipment
+// cannot be on another crew's saved roster, and everyone rostered must be
+// working that day.
+func (s *DispatchServiceImpl) SetRoster(ctx context.Context, crewID uuid.UUID, date time.Time, req *CrewRosterRequest) (*DispatchChange, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if err := ValidateCrewRoster(req); err != nil {
+		return nil, err
+	}
+
+	day := startOfDay(date)
+	input, err := s.loadDay(ctx, tenantID, day)
+	if err != nil {
+		return nil, err
+	}
+	crew := findCrew(input.Crews, crewID)
+	if crew == nil {
+		return nil, fmt.Errorf("crew not found")
+	}
+
+	roster := s.savedRoster(ctx, tenantID, input.Rosters[crewID], crewID, day)
+	roster.Notes = req.Notes
+	roster.Members = make([]*domain.CrewRosterMember, 0, len(req.Members))
+	for _, member := range req.Members {
+		roster.Members = append(roster.Members, &domain.CrewRosterMember{
+			ID:       uuid.New(),
+			RosterID: roster.ID,
+			UserID:   member.UserID,
+			Role:     rosterRole(member.Role),
+		})
+	}
+	roster.EquipmentIDs = req.EquipmentIDs
+	if roster.EquipmentIDs == nil {
+		roster.EquipmentIDs = append([]uuid.UUID{}, crew.EquipmentIDs...)
+	}
+
+	if clashes := RosterClashes(roster, input.Rosters); len(clashes) > 0 {
+		return nil, fmt.Errorf("roster cannot be saved: %s", clashes[0].Reason)
+	}
+	userIDs := make([]uuid.UUID, 0, len(roster.Members))
+	for _, member := range roster.Members {
+		userIDs = append(userIDs, member.UserID)
+	}
+	if err := s.checkWorking(ctx, tenantID, userIDs, day); err != nil {
+		return nil, err
+	}
+
+	if err := s.dispatchRepo.SaveRosters(ctx, tenantID, []*domain.CrewRoster{roster}); err != nil {
+		return nil, fmt.Errorf("failed to save crew roster: %w", err)
+	}
+
+	s.logDispatchAction(ctx, "crew_roster.set", "crew", crewID, nil, map[string]interface{}{
+		"date":          day.Format("2006-01-02"),
+		"members":       userIDs,
+		"equipment_ids": roster.EquipmentIDs,
+	})
+
+	return s.change(ctx, tenantID, "roster.set", day, []uuid.UUID{crewID}, s.downtimeWarnings(input, roster.EquipmentIDs))
+}
+
+// ClearRoster deletes the crew's roster for the date
+func (s *DispatchServiceImpl) ClearRoster(ctx context.Context, crewID uuid.UUID, date time.Time) (*DispatchChange, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	day := startOfDay(date)
+	if err := s.dispatchRepo.DeleteRoster(ctx, tenantID, crewID, day); err != nil {
+		return nil, fmt.Errorf("failed to clear crew roster: %w", err)
+	}
+
+	s.logDispatchAction(ctx, "crew_roster.cleared", "crew", crewID, map[string]interface{}{
+		"date": day.Format("2006-01-02"),
+	}, nil)
+
+	return s.change(ctx, tenantID, "roster.cleared", day, []uuid.UUID{crewID}, nil)
+}
+
+// MoveMember moves a person onto another crew, or off every crew, for the day.
+// Their jobs that day stay assigned to them and so move with them.
+func (s *DispatchServiceImpl) MoveMember(ctx context.Context, req *DispatchMemberMove) (*DispatchChange, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if req.Date.IsZero() || req.UserID == uuid.Nil {
+		return nil, fmt.Errorf("validation failed: date and user_id are required")
+	}
+
+	day := startOfDay(req.Date)
+	input, err := s.loadDay(ctx, tenantID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	current := RosterCrewOf(input.Rosters, req.UserID)
+	if !sameCrew(current, req.FromCrewID) {
+		return nil, fmt.Errorf("member cannot be moved: they are %s, not %s", crewLabel(input.Crews, current), crewLabel(input.Crews, req.FromCrewID))
+	}
+	if sameCrew(current, req.ToCrewID) {
+		return nil, fmt.Errorf("validation failed: member is already %s", crewLabel(input.Crews, current))
+	}
+
+	var rosters []*domain.CrewRoster
+	var crewIDs []uuid.UUID
+	if current != nil {
+		source := input.Rosters[*current]
+		// A default roster loses the member on its own once they are saved
+		// onto another crew; it only needs saving when they leave every crew
+		if source.ID != uuid.Nil || req.ToCrewID == nil {
+			roster := s.savedRoster(ctx, tenantID, source, *current, day)
+			roster.Members = withoutMember(roster.Members, req.UserID)
+			rosters = append(rosters, roster)
+		}
+		crewIDs = append(crewIDs, *current)
+	}
+
+	if req.ToCrewID != nil {
+		if findCrew(input.Crews, *req.ToCrewID) == nil {
+			return nil, fmt.Errorf("crew not found")
+		}
+		if err := s.checkWorking(ctx, tenantID, []uuid.UUID{req.UserID}, day); err != nil {
+			return nil, err
+		}
+
+		roster := s.savedRoster(ctx, tenantID, input.Rosters[*req.ToCrewID], *req.ToCrewID, day)
+		role := rosterRole(req.Role)
+		if role == domain.CrewRoleLead {
+			// Only one lead per crew; the old one stays on as a member
+			for _, member := range roster.Members {
+				if isCrewLeadRole(member.Role) {
+					member.Role = domain.CrewRoleMember
+				}
+			}
+		}
+		roster.Members = append(roster.Members, &domain.CrewRosterMember{
+			ID:       uuid.New(),
+			RosterID: roster.ID,
+			UserID:   req.UserID,
+			Role:     role,
+		})
+		rosters = append(rosters, roster)
+		crewIDs = append(crewIDs, *req.ToCrewID)
+	}
+
+	if err := s.dispatchRepo.SaveRosters(ctx, tenantID, rosters); err != nil {
+		return nil, fmt.Errorf("failed to save crew rosters: %w", err)
+	}
+
+	s.logDispatchAction(ctx, "crew_roster.member_moved", "user", req.UserID,
+		map[string]interface{}{"crew_id": current, "date": day.Format("2006-01-02")},
+		map[string]interface{}{"crew_id": req.ToCrewID, "date": day.Format("2006-01-02")})
+
+	var warnings []string
+	jobs := 0
+	for _, job := range input.Jobs {
+		if job.AssignedUserID != nil && *job.AssignedUserID == req.UserID && job.Status != domain.JobStatusCancelled {
+			jobs++
+		}
+	}
+	if jobs > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d job(s) assigned to %s move with them", jobs, memberName(input.Names, req.UserID)))
+	}
+
+	return s.change(ctx, tenantID, "member.moved", day, crewIDs, warnings)
+}
+
+// MoveJob assigns a job to a crew or person, optionally at a new time. The
+// assignee must be working through the job, the crew must be free, and the
+// job's equipment must not be reserved elsewhere or out of service.
+func (s *DispatchServiceImpl) MoveJob(ctx context.Context, req *DispatchJobMove) (*DispatchChange, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	job, err := s.jobRepo.GetByID(ctx, tenantID, req.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job not found")
+	}
+	if job.Status == domain.JobStatusCompleted || job.Status == domain.JobStatusCancelled {
+		return nil, fmt.Errorf("job cannot be moved: it is %s", job.Status)
+	}
+
+	previousStart := job.ScheduledDate
+	if req.ScheduledDate != nil {
+		// Rescheduling is blocked while the customer's account is suspended
+		if previousStart == nil || !req.ScheduledDate.Equal(*previousStart) {
+			customer, err := s.customerRepo.GetByID(ctx, tenantID, job.CustomerID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to verify customer: %w", err)
+			}
+			if customer != nil && customer.SchedulingSuspended {
+				return nil, fmt.Errorf("job cannot be moved: scheduling is suspended for this customer due to a delinquent account")
+			}
+		}
+		job.ScheduledDate = req.ScheduledDate
+	}
+	if job.ScheduledDate == nil {
+		return nil, fmt.Errorf("validation failed: job must be scheduled before it is dispatched")
+	}
+
+	day := startOfDay(*job.ScheduledDate)
+	input, err := s.loadDay(ctx, tenantID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	var previousCrew *uuid.UUID
+	if job.AssignedUserID != nil {
+		previousCrew = RosterCrewOf(input.Rosters, *job.AssignedUserID)
+	}
+
+	assignee := req.UserID
+	if req.CrewID != nil {
+		roster, ok := input.Rosters[*req.CrewID]
+		if !ok {
+			return nil, fmt.Errorf("crew not found")
+		}
+		if assignee == nil {
+			if assignee = RosterLead(roster); assignee == nil {
+				return nil, fmt.Errorf("job cannot be moved: nobody is rostered on %s", crewLabel(input.Crews, req.CrewID))
+			}
+		} else if !roster.HasMember(*assignee) {
+			return nil, fmt.Errorf("validation failed: %s is not on %s", memberName(input.Names, *assignee), crewLabel(input.Crews, req.CrewID))
+		}
+	}
+
+	var warnings []string
+	window, _ := dispatchJobWindow(job)
+	if assignee != nil {
+		if !coversTimeRange(input.Windows[*assignee], window) {
+			if _, known := input.Windows[*assignee]; !known {
+				if err := s.checkWorkingThrough(ctx, tenantID, *assignee, window); err != nil {
+					return nil, err
+				}
+			} else {
+				return nil, fmt.Errorf("job cannot be moved: %s is not working from %s to %s", memberName(input.Names, *assignee),
+					window.Start.Format("3:04 PM"), window.End.Format("3:04 PM"))
+			}
+		}
+
+		// The crew's other jobs, or the assignee's when they ride alone
+		var busy []*domain.EnhancedJob
+		crewID := RosterCrewOf(input.Rosters, *assignee)
+		for _, other := range input.Jobs {
+			if other.ID == job.ID || other.AssignedUserID == nil || other.Status == domain.JobStatusCancelled {
+				continue
+			}
+			if *other.AssignedUserID == *assignee || (crewID != nil && input.Rosters[*crewID].HasMember(*other.AssignedUserID)) {
+				busy = append(busy, other)
+			}
+		}
+		if overlaps := JobOverlaps(job, busy); len(overlaps) > 0 {
+			return nil, fmt.Errorf("job cannot be moved: %s", overlaps[0].Reason)
+		}
+
+		// The whole crew is checked when the assignee rides with one, as when
+		// a job is assigned to a crew
+		workerIDs, assignedTo := []uuid.UUID{*assignee}, memberName(input.Names, *assignee)
+		if crewID != nil {
+			workerIDs, assignedTo = nil, crewLabel(input.Crews, crewID)
+			for _, member := range input.Rosters[*crewID].Members {
+				workerIDs = append(workerIDs, member.UserID)
+			}
+		}
+		check, err := checkJobQualifications(ctx, s.jobRepo, s.certificationRepo, job, workerIDs, crewID != nil)
+		if err != nil {
+			return nil, err
+		}
+		if !check.Qualified {
+			return nil, fmt.Errorf("job cannot be moved: %s is not qualified: %s", assignedTo, DescribeQualificationGaps(check.Gaps))
+		}
+		if len(check.Warnings) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s assigned without: %s", job.Title, DescribeQualificationGaps(check.Warnings)))
+		}
+	}
+
+	if conflicts := jobEquipmentConflicts(job, input.Reservations, input.Downtime, input.Equipment, nil); len(conflicts) > 0 {
+		return nil, fmt.Errorf("job cannot be moved: %s", conflicts[0].Reason)
+	}
+
+	previousAssignee := job.AssignedUserID
+	job.AssignedUserID = assignee
+	job.UpdatedAt = time.Now()
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to update job: %w", err)
+	}
+
+	var crewID *uuid.UUID
+	if assignee != nil {
+		crewID = RosterCrewOf(input.Rosters, *assignee)
+	}
+	if err := s.reserveJobEquipment(ctx, job, crewID); err != nil {
+		s.logger.Printf("Failed to reserve equipment for job %s: %v", job.ID, err)
+	}
+
+	s.logDispatchAction(ctx, "job.dispatched", "job", job.ID,
+		map[string]interface{}{"assigned_user_id": previousAssignee, "crew_id": previousCrew, "scheduled_date": previousStart},
+		map[string]interface{}{"assigned_user_id": assignee, "crew_id": crewID, "scheduled_date": job.ScheduledDate})
+
+	var crewIDs []uuid.UUID
+	for _, id := range []*uuid.UUID{previousCrew, crewID} {
+		if id != nil && !containsUUID(crewIDs, *id) {
+			crewIDs = append(crewIDs, *id)
+		}
+	}
+
+	return s.change(ctx, tenantID, "job.moved", day, crewIDs, warnings)
+}
+
+// MoveEquipment moves equipment onto another crew, or off every crew, for the
+// day. It cannot leave a crew whose jobs have it reserved.
+func (s *DispatchServiceImpl) MoveEquipment(ctx context.Context, req *DispatchEquipmentMove) (*DispatchChange, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if req.Date.IsZero() || req.EquipmentID == uuid.Nil {
+		return nil, fmt.Errorf("validation failed: date and equipment_id are required")
+	}
+
+	day := startOfDay(req.Date)
+	input, err := s.loadDay(ctx, tenantID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	current := RosterCrewHolding(input.Rosters, req.EquipmentID)
+	if !sameCrew(current, req.FromCrewID) {
+		return nil, fmt.Errorf("equipment cannot be moved: it is %s, not %s", crewLabel(input.Crews, current), crewLabel(input.Crews, req.FromCrewID))
+	}
+	if sameCrew(current, req.ToCrewID) {
+		return nil, fmt.Errorf("validation failed: equipment is already %s", crewLabel(input.Crews, current))
+	}
+
+	for _, reservation := range input.Reservations {
+		if reservation.EquipmentID != req.EquipmentID || reservation.JobID == nil {
+			continue
+		}
+		if reservation.CrewID != nil && req.ToCrewID != nil && *reservation.CrewID == *req.ToCrewID {
+			continue
+		}
+		return nil, fmt.Errorf("equipment cannot be moved: it is reserved for a job from %s to %s",
+			reservation.StartsAt.Format("3:04 PM"), reservation.EndsAt.Format("3:04 PM"))
+	}
+
+	var rosters []*domain.CrewRoster
+	var crewIDs []uuid.UUID
+	if current != nil {
+		source := input.Rosters[*current]
+		if source.ID != uuid.Nil || req.ToCrewID == nil {
+			roster := s.savedRoster(ctx, tenantID, source, *current, day)
+			roster.EquipmentIDs = withoutUUID(roster.EquipmentIDs, req.EquipmentID)
+			rosters = append(rosters, roster)
+		}
+		crewIDs = append(crewIDs, *current)
+	}
+
+	if req.ToCrewID != nil {
+		if findCrew(input.Crews, *req.ToCrewID) == nil {
+			return nil, fmt.Errorf("crew not found")
+		}
+		roster := s.savedRoster(ctx, tenantID, input.Rosters[*req.ToCrewID], *req.ToCrewID, day)
+		roster.EquipmentIDs = append(roster.EquipmentIDs, req.EquipmentID)
+		rosters = append(rosters, roster)
+		crewIDs = append(crewIDs, *req.ToCrewID)
+	}
+
+	if err := s.dispatchRepo.SaveRosters(ctx, tenantID, rosters); err != nil {
+		return nil, fmt.Errorf("failed to save crew rosters: %w", err)
+	}
+
+	s.logDispatchAction(ctx, "crew_roster.equipment_moved", "equipment", req.EquipmentID,
+		map[string]interface{}{"crew_id": current, "date": day.Format("2006-01-02")},
+		map[string]interface{}{"crew_id": req.ToCrewID, "date": day.Format("2006-01-02")})
+
+	var warnings []string
+	if req.ToCrewID != nil {
+		warnings = s.downtimeWarnings(input, []uuid.UUID{req.EquipmentID})
+	}
+
+	return s.change(ctx, tenantID, "equipment.moved", day, crewIDs, warnings)
+}
+
+// ValidateCrewRoster checks a roster has no one twice, no equipment twice and
+// at most one lead
+func ValidateCrewRoster(req *CrewRosterRequest) error {
+	users := make(map[uuid.UUID]bool)
+	leads := 0
+	for _, member := range req.Members {
+		if member.UserID == uuid.Nil {
+			return fmt.Errorf("validation failed: every member needs a user_id")
+		}
+		if users[member.UserID] {
+			return fmt.Errorf("validation failed: user %s is on the roster twice", member.UserID)
+		}
+		users[member.UserID] = true
+		if isCrewLeadRole(member.Role) {
+			leads++
+		}
+	}
+	if leads > 1 {
+		return fmt.Errorf("validation failed: a crew can only have one lead")
+	}
+
+	equipment := make(map[uuid.UUID]bool)
+	for _, equipmentID := range req.EquipmentIDs {
+		if equipment[equipmentID] {
+			return fmt.Errorf("validation failed: equipment %s is on the roster twice", equipmentID)
+		}
+		equipment[equipmentID] = true
+	}
+
+	return nil
+}
+
+// EffectiveRosters returns each crew's roster for the date. Saved rosters win;
+// crews without one get a roster built from their standing members and own
+// equipment, less anyone or anything a saved roster has taken for the day.
+// Built rosters have no ID.
+func EffectiveRosters(date time.Time, crews []*domain.Crew, standing []*domain.CrewMember, saved []*domain.CrewRoster) map[uuid.UUID]*domain.CrewRoster {
+	rosters := make(map[uuid.UUID]*domain.CrewRoster, len(crews))
+	takenUsers := make(map[uuid.UUID]bool)
+	takenEquipment := make(map[uuid.UUID]bool)
+	for _, roster := range saved {
+		rosters[roster.CrewID] = roster
+		for _, member := range roster.Members {
+			takenUsers[member.UserID] = true
+		}
+		for _, equipmentID := range roster.EquipmentIDs {
+			takenEquipment[equipmentID] = true
+		}
+	}
+
+	for _, crew := range crews {
+		if _, ok := rosters[crew.ID]; ok {
+			continue
+		}
+
+		roster := &domain.CrewRoster{
+			TenantID:     crew.TenantID,
+			CrewID:       crew.ID,
+			Date:         date,
+			EquipmentIDs: []uuid.UUID{},
+			Members:      []*domain.CrewRosterMember{},
+		}
+		for _, member := range standing {
+			if member.CrewID != crew.ID || member.LeftAt != nil || takenUsers[member.UserID] {
+				continue
+			}
+			roster.Members = append(roster.Members, &domain.CrewRosterMember{
+				UserID: member.UserID,
+				Role:   member.Role,
+			})
+		}
+		for _, equipmentID := range crew.EquipmentIDs {
+			if !takenEquipment[equipmentID] {
+				roster.EquipmentIDs = append(roster.EquipmentIDs, equipmentID)
+			}
+		}
+		rosters[crew.ID] = roster
+	}
+
+	return rosters
+}
+
+// RosterClashes lists the people and equipment on the roster that another
+// crew's saved roster already has for the day. Built rosters do not clash:
+// saving a roster takes people and equipment from them.
+func RosterClashes(roster *domain.CrewRoster, rosters map[uuid.UUID]*domain.CrewRoster) []DispatchConflict {
+	clashes := make([]DispatchConflict, 0)
+	for _, other := range sortedRosters(rosters) {
+		if other.CrewID == roster.CrewID || other.ID == uuid.Nil {
+			continue
+		}
+		for _, member := range roster.Members {
+			if other.HasMember(member.UserID) {
+				clashes = append(clashes, DispatchConflict{
+					Type:       DispatchConflictMember,
+					ResourceID: member.UserID,
+					Reason:     fmt.Sprintf("user %s is already on crew %s", member.UserID, other.CrewID),
+				})
+			}
+		}
+		for _, equipmentID := range roster.EquipmentIDs {
+			if containsUUID(other.EquipmentIDs, equipmentID) {
+				clashes = append(clashes, DispatchConflict{
+					Type:       DispatchConflictEquipment,
+					ResourceID: equipmentID,
+					Reason:     fmt.Sprintf("equipment %s is already with crew %s", equipmentID, other.CrewID),
+				})
+			}
+		}
+	}
+	return clashes
+}
+
+// RosterCrewOf returns the crew the user rides with, if any
+func RosterCrewOf(rosters map[uuid.UUID]*domain.CrewRoster, userID uuid.UUID) *uuid.UUID {
+	for _, roster := range sortedRosters(rosters) {
+		if roster.HasMember(userID) {
+			crewID := roster.CrewID
+			return &crewID
+		}
+	}
+	return nil
+}
+
+// RosterCrewHolding returns the crew taking the equipment out, if any
+func RosterCrewHolding(rosters map[uuid.UUID]*domain.CrewRoster, equipmentID uuid.UUID) *uuid.UUID {
+	for _, roster := range sortedRosters(rosters) {
+		if containsUUID(roster.EquipmentIDs, equipmentID) {
+			crewID := roster.CrewID
+			return &crewID
+		}
+	}
+	return nil
+}
+
+// RosterLead picks who takes jobs dispatched to the crew: its lead if it has
+// one, otherwise the first member rostered
+func RosterLead(roster *domain.CrewRoster) *uuid.UUID {
+	if roster == nil || len(roster.Members) == 0 {
+		return nil
+	}
+	for _, member := range roster.Members {
+		if isCrewLeadRole(member.Role) {
+			return &member.UserID
+		}
+	}
+	return &roster.Members[0].UserID
+}
+
+// JobOverlaps lists the jobs that are scheduled at the same time as the job
+func JobOverlaps(job *domain.EnhancedJob, others []*domain.EnhancedJob) []DispatchConflict {
+	overlaps := make([]DispatchConflict, 0)
+	window, ok := dispatchJobWindow(job)
+	if !ok {
+		return overlaps
+	}
+
+	for _, other := range others {
+		if other.ID == job.ID {
+			continue
+		}
+		otherWindow, ok := dispatchJobWindow(other)
+		if !ok || !otherWindow.Start.Before(window.End) || !otherWindow.End.After(window.Start) {
+			continue
+		}
+		otherID := other.ID
+		overlaps = append(overlaps, DispatchConflict{
+			Type:       DispatchConflictJob,
+			ResourceID: job.ID,
+			JobID:      &otherID,
+			Reason: fmt.Sprintf("%s overlaps %s from %s to %s", job.Title, other.Title,
+				otherWindow.Start.Format("3:04 PM"), otherWindow.End.Format("3:04 PM")),
+		})
+	}
+	return overlaps
+}
+
+// DispatchRoute lays out the jobs as stops in the order they are scheduled,
+// with the straight-line distance in miles from the previous located stop.
+// Locations are keyed by property.
+func DispatchRoute(jobs []*domain.EnhancedJob, locations map[uuid.UUID]*Location) []RouteStop {
+	ordered := make([]*domain.EnhancedJob, 0, len(jobs))
+	for _, job := range jobs {
+		if job.ScheduledDate != nil && job.Status != domain.JobStatusCancelled {
+			ordered = append(ordered, job)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].ScheduledDate.Before(*ordered[j].ScheduledDate)
+	})
+
+	route := make([]RouteStop, 0, len(ordered))
+	var previous *Location
+	for i, job := range ordered {
+		window, _ := dispatchJobWindow(job)
+		stop := RouteStop{
+			JobID:       job.ID,
+			Sequence:    i + 1,
+			ArrivalTime: window.Start,
+			Duration:    int(window.End.Sub(window.Start).Minutes()),
+		}
+		if location := locations[job.PropertyID]; location != nil {
+			stop.Address = location.Address
+			if previous != nil {
+				stop.Distance = haversineDistance(previous.Latitude, previous.Longitude, location.Latitude, location.Longitude)
+			}
+			previous = location
+		}
+		route = append(route, stop)
+	}
+	return route
+}
+
+// BuildDispatchBoard lays out the day: each crew's members and equipment, the
+// jobs assigned to its members in route order and the conflicts to fix. Jobs
+// assigned to no one or to someone on no crew are unassigned, and standing
+// crew members rostered off every crew are on the bench.
+func BuildDispatchBoard(input *DispatchBoardInput) *DispatchBoard {
+	board := &DispatchBoard{
+		Date:       input.Date,
+		Crews:      make([]*DispatchCrew, 0, len(input.Crews)),
+		Unassigned: make([]*domain.EnhancedJob, 0),
+		Bench:      make([]*DispatchMember, 0),
+	}
+
+	dispatched := make(map[uuid.UUID]bool)
+	for _, crew := range input.Crews {
+		roster := input.Rosters[crew.ID]
+		if roster == nil {
+			roster = &domain.CrewRoster{CrewID: crew.ID, Date: input.Date}
+		}
+
+		dispatchCrew := &DispatchCrew{
+			Crew:      crew,
+			Rostered:  roster.ID != uuid.Nil,
+			Notes:     roster.Notes,
+			Members:   make([]*DispatchMember, 0, len(roster.Members)),
+			Equipment: make([]*DispatchEquipment, 0, len(roster.EquipmentIDs)),
+			Jobs:      make([]*domain.EnhancedJob, 0),
+			Conflicts: make([]DispatchConflict, 0),
+		}
+
+		for _, member := range roster.Members {
+			dispatchMember := newDispatchMember(input, member.UserID, member.Role)
+			dispatchCrew.Members = append(dispatchCrew.Members, dispatchMember)
+			if len(dispatchMember.Working) == 0 {
+				reason := dispatchMember.Name + " is not working"
+				if len(dispatchMember.Unavailable) > 0 {
+					reason += ": " + dispatchMember.Unavailable[0].Reason
+				}
+				dispatchCrew.Conflicts = append(dispatchCrew.Conflicts, DispatchConflict{
+					Type:       DispatchConflictMember,
+					ResourceID: member.UserID,
+					Reason:     reason,
+				})
+			}
+			if other := otherCrewWith(input.Rosters, crew.ID, member.UserID); other != nil {
+				dispatchCrew.Conflicts = append(dispatchCrew.Conflicts, DispatchConflict{
+					Type:       DispatchConflictMember,
+					ResourceID: member.UserID,
+					Reason:     fmt.Sprintf("%s is also on %s", dispatchMember.Name, crewLabel(input.Crews, other)),
+				})
+			}
+		}
+
+		for _, equipmentID := range roster.EquipmentIDs {
+			equipment := &DispatchEquipment{
+				EquipmentID: equipmentID,
+				Name:        equipmentName(input.Equipment, equipmentID),
+				Downtime:    input.Downtime[equipmentID],
+			}
+			dispatchCrew.Equipment = append(dispatchCrew.Equipment, equipment)
+			if len(equipment.Downtime) > 0 {
+				dispatchCrew.Conflicts = append(dispatchCrew.Conflicts, DispatchConflict{
+					Type:       DispatchConflictEquipment,
+					ResourceID: equipmentID,
+					Reason: fmt.Sprintf("%s is out of service from %s to %s", equipment.Name,
+						equipment.Downtime[0].Start.Format("Jan 2 3:04 PM"), equipment.Downtime[0].End.Format("Jan 2 3:04 PM")),
+				})
+			}
+		}
+
+		for _, job := range input.Jobs {
+			if job.AssignedUserID == nil || job.Status == domain.JobStatusCancelled || !roster.HasMember(*job.AssignedUserID) {
+				continue
+			}
+			dispatched[job.ID] = true
+			dispatchCrew.Jobs = append(dispatchCrew.Jobs, job)
+		}
+		sortJobsByStart(dispatchCrew.Jobs)
+
+		crewID := crew.ID
+		for i, job := range dispatchCrew.Jobs {
+			dispatchCrew.Conflicts = append(dispatchCrew.Conflicts, JobOverlaps(job, dispatchCrew.Jobs[i+1:])...)
+			if window, ok := dispatchJobWindow(job); ok && !coversTimeRange(input.Windows[*job.AssignedUserID], window) {
+				jobID := job.ID
+				dispatchCrew.Conflicts = append(dispatchCrew.Conflicts, DispatchConflict{
+					Type:       DispatchConflictJob,
+					ResourceID: *job.AssignedUserID,
+					JobID:      &jobID,
+					Reason:     fmt.Sprintf("%s is outside %s's working hours", job.Title, memberName(input.Names, *job.AssignedUserID)),
+				})
+			}
+			dispatchCrew.Conflicts = append(dispatchCrew.Conflicts,
+				jobEquipmentConflicts(job, input.Reservations, input.Downtime, input.Equipment, &crewID)...)
+		}
+		dispatchCrew.Route = DispatchRoute(dispatchCrew.Jobs, input.Locations)
+
+		board.Crews = append(board.Crews, dispatchCrew)
+	}
+
+	for _, job := range input.Jobs {
+		if dispatched[job.ID] || job.Status == domain.JobStatusCancelled || job.Status == domain.JobStatusCompleted {
+			continue
+		}
+		board.Unassigned = append(board.Unassigned, job)
+	}
+	sortJobsByStart(board.Unassigned)
+
+	benched := make(map[uuid.UUID]bool)
+	for _, member := range input.Standing {
+		if member.LeftAt != nil || benched[member.UserID] || RosterCrewOf(input.Rosters, member.UserID) != nil {
+			continue
+		}
+		benched[member.UserID] = true
+		board.Bench = append(board.Bench, newDispatchMember(input, member.UserID, member.Role))
+	}
+
+	return board
+}
+
+// loadDay gathers everything on the board for the day
+func (s *DispatchServiceImpl) loadDay(ctx context.Context, tenantID uuid.UUID, day time.Time) (*DispatchBoardInput, error) {
+	dayRange := TimeRange{Start: day, End: day.AddDate(0, 0, 1)}
+
+	crews, err := s.dispatchRepo.ListActiveCrews(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list crews: %w", err)
+	}
+	standing, err := s.dispatchRepo.ListStandingMembers(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list crew members: %w", err)
+	}
+	saved, err := s.dispatchRepo.ListRosters(ctx, tenantID, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list crew rosters: %w", err)
+	}
+	jobs, err := s.jobRepo.GetByDateRange(ctx, tenantID, dayRange.Start, dayRange.End)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs: %w", err)
+	}
+
+	input := &DispatchBoardInput{
+		Date:      day,
+		Crews:     crews,
+		Standing:  standing,
+		Rosters:   EffectiveRosters(day, crews, standing, saved),
+		Jobs:      jobs,
+		Downtime:  make(map[uuid.UUID][]TimeRange),
+		Equipment: make(map[uuid.UUID]*domain.Equipment),
+		Locations: make(map[uuid.UUID]*Location),
+	}
+
+	var userIDs, equipmentIDs []uuid.UUID
+	addUser := func(userID uuid.UUID) {
+		if !containsUUID(userIDs, userID) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	for _, member := range standing {
+		addUser(member.UserID)
+	}
+	for _, roster := range input.Rosters {
+		for _, member := range roster.Members {
+			addUser(member.UserID)
+		}
+		for _, equipmentID := range roster.EquipmentIDs {
+			if !containsUUID(equipmentIDs, equipmentID) {
+				equipmentIDs = append(equipmentIDs, equipmentID)
+			}
+		}
+	}
+	for _, job := range jobs {
+		if job.AssignedUserID != nil {
+			addUser(*job.AssignedUserID)
+		}
+		for _, equipmentID := range job.RequiresEquipment {
+			if !containsUUID(equipmentIDs, equipmentID) {
+				equipmentIDs = append(equipmentIDs, equipmentID)
+			}
+		}
+		if _, ok := input.Locations[job.PropertyID]; !ok {
+			input.Locations[job.PropertyID] = s.propertyLocation(ctx, tenantID, job.PropertyID)
+		}
+	}
+
+	if input.Windows, input.Unavailable, err = loadWorkingWindows(ctx, s.availabilityRepo, tenantID, userIDs, dayRange); err != nil {
+		return nil, err
+	}
+	if input.Names, err = s.dispatchRepo.GetUserNames(ctx, tenantID, userIDs); err != nil {
+		return nil, fmt.Errorf("failed to get user names: %w", err)
+	}
+
+	if len(equipmentIDs) > 0 {
+		equipment, err := s.equipmentRepo.GetByIDs(ctx, tenantID, equipmentIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get equipment: %w", err)
+		}
+		for _, item := range equipment {
+			input.Equipment[item.ID] = item
+		}
+
+		if input.Reservations, err = s.reservationRepo.ListReservations(ctx, tenantID, equipmentIDs, dayRange.Start, dayRange.End); err != nil {
+			return nil, fmt.Errorf("failed to list equipment reservations: %w", err)
+		}
+
+		downtime, err := s.workOrderRepo.ListDowntime(ctx, tenantID, equipmentIDs, dayRange.Start, dayRange.End)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list equipment downtime: %w", err)
+		}
+		now := time.Now()
+		for _, workOrder := range downtime {
+			if window, ok := DowntimeWindow(workOrder, now, dayRange.End); ok {
+				if window, ok = clipTimeRange(window, dayRange); ok {
+					input.Downtime[workOrder.EquipmentID] = append(input.Downtime[workOrder.EquipmentID], window)
+				}
+			}
+		}
+	}
+
+	return input, nil
+}
+
+// change rebuilds the board after an edit
+func (s *DispatchServiceImpl) change(ctx context.Context, tenantID uuid.UUID, action string, day time.Time, crewIDs []uuid.UUID, warnings []string) (*DispatchChange, error) {
+	input, err := s.loadDay(ctx, tenantID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	if crewIDs == nil {
+		crewIDs = []uuid.UUID{}
+	}
+	if warnings == nil {
+		warnings = []string{}
+	}
+
+	return &DispatchChange{
+		Action:   action,
+		Date:     day,
+		CrewIDs:  crewIDs,
+		Warnings: warnings,
+		Board:    BuildDispatchBoard(input),
+	}, nil
+}
+
+// savedRoster copies a crew's effective roster so it can be edited and saved,
+// giving a built roster an ID
+func (s *DispatchServiceImpl) savedRoster(ctx context.Context, tenantID uuid.UUID, roster *domain.CrewRoster, crewID uuid.UUID, day time.Time) *domain.CrewRoster {
+	now := time.Now()
+	saved := &domain.CrewRoster{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		CrewID:    crewID,
+		Date:      day,
+		CreatedBy: GetUserIDFromContext(ctx),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if roster != nil {
+		if roster.ID != uuid.Nil {
+			saved.ID = roster.ID
+			saved.CreatedBy = roster.CreatedBy
+			saved.CreatedAt = roster.CreatedAt
+		}
+		saved.Notes = roster.Notes
+		saved.EquipmentIDs = append([]uuid.UUID{}, roster.EquipmentIDs...)
+		for _, member := range roster.Members {
+			saved.Members = append(saved.Members, &domain.CrewRosterMember{
+				ID:       uuid.New(),
+				RosterID: saved.ID,
+				UserID:   member.UserID,
+				Role:     rosterRole(member.Role),
+			})
+		}
+	}
+	return saved
+}
+
+// checkWorking refuses to roster anyone who is not working at all that day
+func (s *DispatchServiceImpl) checkWorking(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID, day time.Time) error {
+	windows, conflicts, err := loadWorkingWindows(ctx, s.availabilityRepo, tenantID, userIDs, TimeRange{Start: day, End: day.AddDate(0, 0, 1)})
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		if len(windows[userID]) > 0 {
+			continue
+		}
+		reason := "no shift"
+		if len(conflicts[userID]) > 0 {
+			reason = conflicts[userID][0].Reason
+		}
+		return fmt.Errorf("user %s cannot be rostered on %s: %s", userID, day.Format("2006-01-02"), reason)
+	}
+	return nil
+}
+
+// checkWorkingThrough refuses to assign a job to someone not working through it
+func (s *DispatchServiceImpl) checkWorkingThrough(ctx context.Context, tenantID, userID uuid.UUID, window TimeRange) error {
+	day := startOfDay(window.Start)
+	windows, _, err := loadWorkingWindows(ctx, s.availabilityRepo, tenantID, []uuid.UUID{userID}, TimeRange{Start: day, End: day.AddDate(0, 0, 1)})
+	if err != nil {
+		return err
+	}
+	if !coversTimeRange(windows[userID], window) {
+		return fmt.Errorf("job cannot be moved: user %s is not working from %s to %s", userID,
+			window.Start.Format("3:04 PM"), window.End.Format("3:04 PM"))
+	}
+	return nil
+}
+
+// reserveJobEquipment reserves the job's equipment for its new crew and time,
+// or releases it when the job is unassigned
+func (s *DispatchServiceImpl) reserveJobEquipment(ctx context.Context, job *domain.EnhancedJob, crewID *uuid.UUID) error {
+	var reservations []*domain.EquipmentReservation
+	if job.AssignedUserID != nil {
+		reservations = BuildJobReservations(job, crewID, GetUserIDFromContext(ctx), time.Now())
+	}
+	if len(reservations) == 0 {
+		return s.reservationRepo.DeleteJobReservations(ctx, job.TenantID, job.ID)
+	}
+	return s.reservationRepo.ReplaceJobReservations(ctx, job.TenantID, job.ID, reservations)
+}
+
+func (s *DispatchServiceImpl) downtimeWarnings(input *DispatchBoardInput, equipmentIDs []uuid.UUID) []string {
+	var warnings []string
+	for _, equipmentID := range equipmentIDs {
+		for _, window := range input.Downtime[equipmentID] {
+			warnings = append(warnings, fmt.Sprintf("%s is out of service from %s to %s", equipmentName(input.Equipment, equipmentID),
+				window.Start.Format("Jan 2 3:04 PM"), window.End.Format("Jan 2 3:04 PM")))
+		}
+	}
+	return warnings
+}
+
+func (s *DispatchServiceImpl) propertyLocation(ctx context.Context, tenantID, propertyID uuid.UUID) *Location {
+	property, err := s.propertyRepo.GetByID(ctx, tenantID, propertyID)
+	if err != nil {
+		s.logger.Printf("Failed to get property %s for dispatch route: %v", propertyID, err)
+		return nil
+	}
+	if property == nil || property.Latitude == nil || property.Longitude == nil {
+		return nil
+	}
+	return &Location{
+		Latitude:  *property.Latitude,
+		Longitude: *property.Longitude,
+		Address:   fmt.Sprintf("%s, %s, %s", property.AddressLine1, property.City, property.State),
+	}
+}
+
+func (s *DispatchServiceImpl) logDispatchAction(ctx context.Context, action, resourceType string, resourceID uuid.UUID, oldValues, newValues map[string]interface{}) {
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   &resourceID,
+		OldValues:    oldValues,
+		NewValues:    newValues,
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+}
+
+// jobEquipmentConflicts lists the job's equipment that is out of service or
+// reserved by something other than the job or its crew during the job
+func jobEquipmentConflicts(job *domain.EnhancedJob, reservations []*domain.EquipmentReservation, downtime map[uuid.UUID][]TimeRange, equipment map[uuid.UUID]*domain.Equipment, crewID *uuid.UUID) []DispatchConflict {
+	conflicts := make([]DispatchConflict, 0)
+	start, end, ok := JobReservationWindow(job)
+	if !ok {
+		return conflicts
+	}
+
+	jobID := job.ID
+	for _, equipmentID := range job.RequiresEquipment {
+		var held []*domain.EquipmentReservation
+		for _, reservation := range reservations {
+			if reservation.EquipmentID != equipmentID {
+				continue
+			}
+			// The crew's own blocks, such as equipment checked out to it, are not a conflict
+			if reservation.JobID == nil && crewID != nil && reservation.CrewID != nil && *reservation.CrewID == *crewID {
+				continue
+			}
+			held = append(held, reservation)
+		}
+		if taken := ReservationConflicts(held, job.ID, start, end); len(taken) > 0 {
+			conflicts = append(conflicts, DispatchConflict{
+				Type:       DispatchConflictEquipment,
+				ResourceID: equipmentID,
+				JobID:      &jobID,
+				Reason: fmt.Sprintf("%s is already reserved from %s to %s", equipmentName(equipment, equipmentID),
+					taken[0].StartsAt.Format("3:04 PM"), taken[0].EndsAt.Format("3:04 PM")),
+			})
+		}
+		for _, window := range downtime[equipmentID] {
+			if window.Start.Before(end) && window.End.After(start) {
+				conflicts = append(conflicts, DispatchConflict{
+					Type:       DispatchConflictEquipment,
+					ResourceID: equipmentID,
+					JobID:      &jobID,
+					Reason:     fmt.Sprintf("%s is out of service during %s", equipmentName(equipment, equipmentID), job.Title),
+				})
+				break
+			}
+		}
+	}
+	return conflicts
+}
+
+// dispatchJobWindow is when a scheduled job occupies its crew
+func dispatchJobWindow(job *domain.EnhancedJob) (TimeRange, bool) {
+	if job.ScheduledDate == nil {
+		return TimeRange{}, false
+	}
+	minutes := dispatchJobMinutes
+	if job.EstimatedDuration != nil && *job.EstimatedDuration > 0 {
+		minutes = *job.EstimatedDuration
+	}
+	return TimeRange{Start: *job.ScheduledDate, End: job.ScheduledDate.Add(time.Duration(minutes) * time.Minute)}, true
+}
+
+func newDispatchMember(input *DispatchBoardInput, userID uuid.UUID, role string) *DispatchMember {
+	working := input.Windows[userID]
+	if working == nil {
+		working = []TimeRange{}
+	}
+	return &DispatchMember{
+		UserID:      userID,
+		Name:        memberName(input.Names, userID),
+		Role:        rosterRole(role),
+		Working:     working,
+		Unavailable: input.Unavailable[userID],
+	}
+}
+
+// coversTimeRange reports whether one of the windows holds the whole range
+func coversTimeRange(windows []TimeRange, r TimeRange) bool {
+	for _, window := range windows {
+		if !window.Start.After(r.Start) && !window.End.Before(r.End) {
+			return true
+		}
+	}
+	return false
+}
+
+func otherCrewWith(rosters map[uuid.UUID]*domain.CrewRoster, crewID, userID uuid.UUID) *uuid.UUID {
+	for _, roster := range sortedRosters(rosters) {
+		if roster.CrewID != crewID && roster.HasMember(userID) {
+			otherID := roster.CrewID
+			return &otherID
+		}
+	}
+	return nil
+}
+
+func sortedRosters(rosters map[uuid.UUID]*domain.CrewRoster) []*domain.CrewRoster {
+	sorted := make([]*domain.CrewRoster, 0, len(rosters))
+	for _, roster := range rosters {
+		sorted = append(sorted, roster)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CrewID.String() < sorted[j].CrewID.String()
+	})
+	return sorted
+}
+
+func sortJobsByStart(jobs []*domain.EnhancedJob) {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if jobs[i].ScheduledDate == nil || jobs[j].ScheduledDate == nil {
+			return jobs[j].ScheduledDate == nil && jobs[i].ScheduledDate != nil
+		}
+		return jobs[i].ScheduledDate.Before(*jobs[j].ScheduledDate)
+	})
+}
+
+func findCrew(crews []*domain.Crew, crewID uuid.UUID) *domain.Crew {
+	for _, crew := range crews {
+		if crew.ID == crewID {
+			return crew
+		}
+	}
+	return nil
+}
+
+func crewLabel(crews []*domain.Crew, crewID *uuid.UUID) string {
+	if crewID == nil {
+		return "on no crew"
+	}
+	if crew := findCrew(crews, *crewID); crew != nil {
+		return "on " + crew.Name
+	}
+	return "on crew " + crewID.String()
+}
+
+func sameCrew(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func memberName(names map[uuid.UUID]string, userID uuid.UUID) string {
+	if name := names[userID]; name != "" {
+		return name
+	}
+	return "user " + userID.String()
+}
+
+func equipmentName(equipment map[uuid.UUID]*domain.Equipment, equipmentID uuid.UUID) string {
+	if item := equipment[equipmentID]; item != nil {
+		return item.Name
+	}
+	return "equipment " + equipmentID.String()
+}
+
+func rosterRole(role string) string {
+	if role == "" {
+		return domain.CrewRoleMember
+	}
+	if isCrewLeadRole(role) {
+		return domain.CrewRoleLead
+	}
+	return role
+}
+
+// isCrewLeadRole matches the lead titles used in standing crew membership
+func isCrewLeadRole(role string) bool {
+	return strings.EqualFold(role, "lead") || strings.EqualFold(role, "leader") || strings.EqualFold(role, "foreman")
+}
+
+func withoutMember(members []*domain.CrewRosterMember, userID uuid.UUID) []*domain.CrewRosterMember {
+	kept := make([]*domain.CrewRosterMember, 0, len(members))
+	for _, member := range members {
+		if member.UserID != userID {
+			kept = append(kept, member)
+		}
+	}
+	return kept
+}
+
+func withoutUUID(ids []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	kept := make([]uuid.UUID, 0, len(ids))
+	for _, candidate := range ids {
+		if candidate != id {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
+}
@@ -157,10 +157,13 @@ type PropertyUpdateRequest struct {
 }
 
 type PropertyDetails struct {
-	LotSize       *float64 `json:"lot_size,omitempty"`
-	SquareFootage *int     `json:"square_footage,omitempty"`
-	PropertyType  string   `json:"property_type"`
-	Accessibility string   `json:"accessibility,omitempty"`
+	PropertyID    *uuid.UUID         `json:"property_id,omitempty"`
+	LotSize       *float64           `json:"lot_size,omitempty"`
+	SquareFootage *int               `json:"square_footage,omitempty"`
+	MeasuredAreas map[string]float64 `json:"measured_areas,omitempty"` // site map square feet by feature type
+	PropertyType  string             `json:"property_type"`
+	Accessibility string             `json:"accessibility,omitempty"`
+	ZipCode       string             `json:"zip_code,omitempty"`
 }
 
 type PropertyValuation struct {
@@ -259,6 +262,7 @@ type JobCompletionDetails struct {
 	Photos          []string  `json:"photos,omitempty"`
 	CustomerSatisfaction *int `json:"customer_satisfaction,omitempty"`
 	RequiresFollowUp bool     `json:"requires_follow_up"`
+	EquipmentUsage  []JobEquipmentUsage `json:"equipment_usage,omitempty"` // meter readings for equipment used on the job
 }
 
 type JobServiceUpdate struct {
@@ -347,15 +351,21 @@ type QuoteCreateRequest struct {
 	Title              string    `json:"title" validate:"required"`
 	Description        *string   `json:"description,omitempty"`
 	Services           []QuoteServiceRequest `json:"services" validate:"required"`
+	Options            []QuoteOptionRequest  `json:"options,omitempty"`
+	Frequency          string    `json:"frequency,omitempty"` // service frequency for rule-priced lines
 	ValidUntil         *time.Time `json:"valid_until,omitempty"`
 	TermsAndConditions *string   `json:"terms_and_conditions,omitempty"`
 	Notes              *string   `json:"notes,omitempty"`
 }
 
+// QuoteUpdateRequest updates a quote. Services and Options together replace the
+// quote's full set of line items when either is provided.
 type QuoteUpdateRequest struct {
 	Title              *string   `json:"title,omitempty"`
 	Description        *string   `json:"description,omitempty"`
 	Services           []QuoteServiceRequest `json:"services,omitempty"`
+	Options            []QuoteOptionRequest  `json:"options,omitempty"`
+	Frequency          string    `json:"frequency,omitempty"`
 	ValidUntil         *time.Time `json:"valid_until,omitempty"`
 	TermsAndConditions *string   `json:"terms_and_conditions,omitempty"`
 	Notes              *string   `json:"notes,omitempty"`
@@ -366,6 +376,25 @@ type QuoteServiceRequest struct {
 	Quantity    float64   `json:"quantity"`
 	UnitPrice   float64   `json:"unit_price"`
 	Description *string   `json:"description,omitempty"`
+	IsOptional  bool      `json:"is_optional,omitempty"` // add-on the customer can toggle
+	// UsePricingRules prices the line from the tenant's price books and the quote's
+	// property instead of UnitPrice
+	UsePricingRules bool `json:"use_pricing_rules,omitempty"`
+}
+
+// QuoteOptionRequest defines a package within an option group, e.g. good/better/best
+type QuoteOptionRequest struct {
+	GroupName   string                `json:"group_name,omitempty"`
+	Name        string                `json:"name"`
+	Description *string               `json:"description,omitempty"`
+	IsDefault   bool                  `json:"is_default,omitempty"`
+	Services    []QuoteServiceRequest `json:"services"`
+}
+
+// QuoteSelectionRequest is the customer's choice of one option per group plus any add-ons
+type QuoteSelectionRequest struct {
+	OptionIDs    []uuid.UUID `json:"option_ids"`
+	AddOnLineIDs []uuid.UUID `json:"add_on_line_ids"`
 }
 
 type QuoteSendOptions struct {
@@ -489,6 +518,8 @@ type EquipmentFilter struct {
 	Status       string `json:"status,omitempty"`
 	Available    bool   `json:"available,omitempty"`
 	Maintenance  bool   `json:"maintenance,omitempty"`
+	AssignedUserID *uuid.UUID `json:"assigned_user_id,omitempty"` // checked out to the user
+	AssignedCrewID *uuid.UUID `json:"assigned_crew_id,omitempty"` // checked out to the crew
 }
 
 type EquipmentCreateRequest struct {
@@ -500,6 +531,9 @@ type EquipmentCreateRequest struct {
 	PurchasePrice       *float64   `json:"purchase_price,omitempty"`
 	MaintenanceSchedule *string    `json:"maintenance_schedule,omitempty"`
 	Notes               *string    `json:"notes,omitempty"`
+	WeightLbs           *float64   `json:"weight_lbs,omitempty"`
+	PayloadCapacityLbs  *float64   `json:"payload_capacity_lbs,omitempty"`
+	TowingCapacityLbs   *float64   `json:"towing_capacity_lbs,omitempty"`
 }
 
 type EquipmentUpdateRequest struct {
@@ -512,6 +546,9 @@ type EquipmentUpdateRequest struct {
 	Status              *string    `json:"status,omitempty"`
 	MaintenanceSchedule *string    `json:"maintenance_schedule,omitempty"`
 	Notes               *string    `json:"notes,omitempty"`
+	WeightLbs           *float64   `json:"weight_lbs,omitempty"`
+	PayloadCapacityLbs  *float64   `json:"payload_capacity_lbs,omitempty"`
+	TowingCapacityLbs   *float64   `json:"towing_capacity_lbs,omitempty"`
 }
 
 type MaintenanceScheduleRequest struct {
@@ -779,6 +816,7 @@ type CustomersReport struct {
 	AverageValue  float64             `json:"average_value"`
 	GrowthTrend   []CustomerGrowth    `json:"growth_trend"`
 	TypeBreakdown []CustomerTypeCount `json:"type_breakdown"`
+	Conversion    *PipelineMetrics    `json:"conversion,omitempty"` // sales pipeline conversion for the period
 }
 
 type CustomerGrowth struct {
@@ -903,10 +941,11 @@ type ScheduleMetrics struct {
 }
 
 type AvailabilityRequest struct {
-	UserIDs   []uuid.UUID `json:"user_ids,omitempty"`
-	CrewIDs   []uuid.UUID `json:"crew_ids,omitempty"`
-	TimeRange TimeRange   `json:"time_range"`
-	JobType   string      `json:"job_type,omitempty"`
+	UserIDs      []uuid.UUID `json:"user_ids,omitempty"`
+	CrewIDs      []uuid.UUID `json:"crew_ids,omitempty"`
+	EquipmentIDs []uuid.UUID `json:"equipment_ids,omitempty"`
+	TimeRange    TimeRange   `json:"time_range"`
+	JobType      string      `json:"job_type,omitempty"`
 }
 
 type AvailabilityResponse struct {
@@ -915,11 +954,12 @@ type AvailabilityResponse struct {
 }
 
 type AvailabilitySlot struct {
-	UserID    *uuid.UUID `json:"user_id,omitempty"`
-	CrewID    *uuid.UUID `json:"crew_id,omitempty"`
-	StartTime time.Time  `json:"start_time"`
-	EndTime   time.Time  `json:"end_time"`
-	Capacity  int        `json:"capacity"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	CrewID      *uuid.UUID `json:"crew_id,omitempty"`
+	EquipmentID *uuid.UUID `json:"equipment_id,omitempty"`
+	StartTime   time.Time  `json:"start_time"`
+	EndTime     time.Time  `json:"end_time"`
+	Capacity    int        `json:"capacity"`
 }
 
 type AvailabilityConflict struct {
@@ -1290,6 +1330,34 @@ type EfficiencyMetrics struct {
 	Overall    float64 `json:"overall"`
 	ByService  map[string]float64 `json:"by_service"`
 	Trends     []EfficiencyTrend `json:"trends"`
+	Fuel       *FuelEfficiencyMetrics `json:"fuel,omitempty"`
+}
+
+// FuelEfficiencyMetrics summarizes the fleet's fuel use over a period and the
+// fuel cost charged to jobs
+type FuelEfficiencyMetrics struct {
+	Gallons             float64                    `json:"gallons"`
+	Cost                float64                    `json:"cost"`
+	AnomalyCount        int                        `json:"anomaly_count"`
+	ByEquipment         []*EquipmentFuelEfficiency `json:"by_equipment"`
+	JobFuelCost         float64                    `json:"job_fuel_cost"`
+	JobsCosted          int                        `json:"jobs_costed"`
+	AverageJobFuelCost  float64                    `json:"average_job_fuel_cost"`
+	ByJob               map[uuid.UUID]float64      `json:"by_job"`
+}
+
+// EquipmentFuelEfficiency is one equipment's fuel use over a period
+type EquipmentFuelEfficiency struct {
+	EquipmentID   uuid.UUID `json:"equipment_id"`
+	Meter         string    `json:"meter,omitempty"`
+	Unit          string    `json:"unit,omitempty"`
+	Gallons       float64   `json:"gallons"`
+	Cost          float64   `json:"cost"`
+	Usage         float64   `json:"usage"`
+	Efficiency    *float64  `json:"efficiency,omitempty"`
+	ChangePercent *float64  `json:"change_percent,omitempty"`
+	Deteriorating bool      `json:"deteriorating"`
+	Anomalies     int       `json:"anomalies"`
 }
 
 type EfficiencyTrend struct {
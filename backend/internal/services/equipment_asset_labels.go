@@ -0,0 +1,179 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/boombuler/barcode/qr"
+)
+
+// AssetLabel is one QR label on an asset label sheet
+type AssetLabel struct {
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	SerialNumber string `json:"serial_number,omitempty"`
+}
+
+// Label sheet layout in PDF points for US Letter stock with 30 labels of
+// 2 5/8" by 1" in three columns, such as Avery 5160
+const (
+	labelPageWidth    = 612.0
+	labelPageHeight   = 792.0
+	labelColumns      = 3
+	labelRows         = 10
+	labelWidth        = 189.0
+	labelHeight       = 72.0
+	labelLeftMargin   = 13.5
+	labelTopMargin    = 36.0
+	labelColumnPitch  = 198.0
+	labelPadding      = 6.0
+	labelQuietModules = 2  // white modules kept around the QR code
+	labelNameLength   = 26 // characters of the equipment name that fit beside the code
+	labelsPerPage     = labelColumns * labelRows
+)
+
+// BuildAssetLabelSheetPDF renders QR asset labels onto US Letter label sheets,
+// thirty to a page. Each label carries the QR-encoded tag code with the
+// equipment name, code and serial number beside it.
+func BuildAssetLabelSheetPDF(labels []AssetLabel) ([]byte, error) {
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("no labels to print")
+	}
+
+	var pages []string
+	for start := 0; start < len(labels); start += labelsPerPage {
+		end := start + labelsPerPage
+		if end > len(labels) {
+			end = len(labels)
+		}
+
+		var content strings.Builder
+		for i, label := range labels[start:end] {
+			column, row := i%labelColumns, i/labelColumns
+			x := labelLeftMargin + float64(column)*labelColumnPitch
+			y := labelPageHeight - labelTopMargin - float64(row+1)*labelHeight
+			if err := writeAssetLabel(&content, label, x, y); err != nil {
+				return nil, err
+			}
+		}
+		pages = append(pages, content.String())
+	}
+
+	return buildLetterPDF(pages), nil
+}
+
+// writeAssetLabel draws a label with its lower left corner at x, y
+func writeAssetLabel(content *strings.Builder, label AssetLabel, x, y float64) error {
+	code, err := qr.Encode(label.Code, qr.M, qr.Auto)
+	if err != nil {
+		return fmt.Errorf("failed to encode QR code for %s: %w", label.Code, err)
+	}
+
+	size := labelHeight - 2*labelPadding
+	bounds := code.Bounds()
+	modules := bounds.Dx() + 2*labelQuietModules
+	module := size / float64(modules)
+	originX := x + labelPadding + labelQuietModules*module
+	originY := y + labelPadding + labelQuietModules*module
+
+	content.WriteString("0 g\n")
+	for row := 0; row < bounds.Dy(); row++ {
+		for column := 0; column < bounds.Dx(); column++ {
+			if r, _, _, _ := code.At(bounds.Min.X+column, bounds.Min.Y+row).RGBA(); r != 0 {
+				continue
+			}
+			// Image rows run top down and PDF coordinates bottom up
+			fmt.Fprintf(content, "%.2f %.2f %.2f %.2f re\n",
+				originX+float64(column)*module,
+				originY+float64(bounds.Dy()-1-row)*module,
+				module, module)
+		}
+	}
+	content.WriteString("f\n")
+
+	name := label.Name
+	if len(name) > labelNameLength {
+		name = name[:labelNameLength-3] + "..."
+	}
+	textX := x + labelPadding + size + labelPadding
+	lines := []struct {
+		font string
+		size float64
+		text string
+	}{
+		{"F2", 10, name},
+		{"F1", 9, label.Code},
+	}
+	if label.SerialNumber != "" {
+		lines = append(lines, struct {
+			font string
+			size float64
+			text string
+		}{"F1", 8, "S/N " + label.SerialNumber})
+	}
+
+	textY := y + labelHeight - labelPadding - 12
+	for _, line := range lines {
+		fmt.Fprintf(content, "BT /%s %.0f Tf %.2f %.2f Td (%s) Tj ET\n", line.font, line.size, textX, textY, pdfString(line.text))
+		textY -= line.size + 5
+	}
+
+	return nil
+}
+
+// buildLetterPDF assembles the page content streams into a US Letter PDF
+// document using the standard Helvetica fonts, F1 regular and F2 bold
+func buildLetterPDF(pages []string) []byte {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// Objects 1-4 are the catalog, page tree and fonts; each page is followed by
+	// its content stream
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, content := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			labelPageWidth, labelPageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+// pdfString escapes text for a PDF string literal, replacing characters the
+// standard fonts cannot show
+func pdfString(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			escaped.WriteRune('\\')
+			escaped.WriteRune(r)
+		case r < 32 || r > 126:
+			escaped.WriteRune('?')
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+	return escaped.String()
+}
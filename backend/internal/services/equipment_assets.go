@@ -0,0 +1,882 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// EquipmentAssetRepository defines data access for equipment asset profiles
+type EquipmentAssetRepository interface {
+	GetAssetProfile(ctx context.Context, tenantID, equipmentID uuid.UUID) (*domain.EquipmentAssetProfile, error)
+	UpsertAssetProfile(ctx context.Context, profile *domain.EquipmentAssetProfile) error
+	ListAssetProfiles(ctx context.Context, tenantID uuid.UUID) ([]*domain.EquipmentAssetProfile, error)
+}
+
+// Replace-or-repair recommendations
+const (
+	ReplaceOrRepairRepair          = "repair"
+	ReplaceOrRepairPlanReplacement = "plan_replacement"
+	ReplaceOrRepairReplace         = "replace"
+)
+
+// macrsHalfYearRates are the IRS GDS percentage tables (half-year convention)
+// for each recovery period, one entry per tax year
+var macrsHalfYearRates = map[int][]float64{
+	3:  {33.33, 44.45, 14.81, 7.41},
+	5:  {20.00, 32.00, 19.20, 11.52, 11.52, 5.76},
+	7:  {14.29, 24.49, 17.49, 12.49, 8.93, 8.92, 8.93, 4.46},
+	10: {10.00, 18.00, 14.40, 11.52, 9.22, 7.37, 6.55, 6.55, 6.56, 6.55, 3.28},
+	15: {5.00, 9.50, 8.55, 7.70, 6.93, 6.23, 5.90, 5.90, 5.91, 5.90, 5.91, 5.90, 5.91, 5.90, 5.91, 2.95},
+	20: {3.750, 7.219, 6.677, 6.177, 5.713, 5.285, 4.888, 4.522, 4.462, 4.461, 4.462, 4.461, 4.462, 4.461, 4.462, 4.461, 4.462, 4.461, 4.462, 4.461, 2.231},
+}
+
+// EquipmentAssetProfileRequest sets how equipment is carried as a fixed asset
+type EquipmentAssetProfileRequest struct {
+	DepreciationMethod   string     `json:"depreciation_method"`
+	UsefulLifeYears      int        `json:"useful_life_years"`
+	SalvageValue         float64    `json:"salvage_value"`
+	DecliningBalanceRate float64    `json:"declining_balance_rate,omitempty"` // defaults to 2, double declining balance
+	InServiceDate        *time.Time `json:"in_service_date,omitempty"`
+	ReplacementCost      *float64   `json:"replacement_cost,omitempty"`
+	FuelCostPerHour      float64    `json:"fuel_cost_per_hour"`
+	DowntimeCostPerHour  float64    `json:"downtime_cost_per_hour"`
+}
+
+// EquipmentDisposalRequest records equipment being sold or scrapped
+type EquipmentDisposalRequest struct {
+	DisposedAt time.Time `json:"disposed_at"`
+	Proceeds   float64   `json:"proceeds"`
+}
+
+// ReplaceOrRepairRequest asks whether a pending repair is worth making
+type ReplaceOrRepairRequest struct {
+	RepairEstimate float64 `json:"repair_estimate"`
+}
+
+// DepreciationPeriod is one calendar year of an equipment's depreciation. The
+// first period starts in the month the equipment went into service and the
+// last ends when it was disposed of.
+type DepreciationPeriod struct {
+	Year                    int       `json:"year"`
+	PeriodStart             time.Time `json:"period_start"`
+	PeriodEnd               time.Time `json:"period_end"`
+	OpeningBookValue        float64   `json:"opening_book_value"`
+	Depreciation            float64   `json:"depreciation"`
+	AccumulatedDepreciation float64   `json:"accumulated_depreciation"`
+	ClosingBookValue        float64   `json:"closing_book_value"`
+}
+
+// DepreciationSchedule is the depreciation of a piece of equipment over its life
+type DepreciationSchedule struct {
+	EquipmentID     uuid.UUID            `json:"equipment_id"`
+	EquipmentName   string               `json:"equipment_name"`
+	Method          string               `json:"method"`
+	Cost            float64              `json:"cost"`
+	SalvageValue    float64              `json:"salvage_value"`
+	UsefulLifeYears int                  `json:"useful_life_years"`
+	InServiceDate   time.Time            `json:"in_service_date"`
+	DisposedAt      *time.Time           `json:"disposed_at,omitempty"`
+	BookValue       float64              `json:"book_value"`
+	Periods         []DepreciationPeriod `json:"periods"`
+}
+
+// TotalCostOfOwnership totals what a piece of equipment cost the business over a period
+type TotalCostOfOwnership struct {
+	EquipmentID          uuid.UUID `json:"equipment_id"`
+	EquipmentName        string    `json:"equipment_name"`
+	Period               TimeRange `json:"period"`
+	CapitalCost          float64   `json:"capital_cost"` // value lost to depreciation and on disposal
+	MaintenanceCost      float64   `json:"maintenance_cost"`
+	FuelCost             float64   `json:"fuel_cost"`
+	DowntimeHours        float64   `json:"downtime_hours"`
+	DowntimeCost         float64   `json:"downtime_cost"`
+	TotalCost            float64   `json:"total_cost"`
+	OperatingHours       float64   `json:"operating_hours"`
+	CostPerOperatingHour float64   `json:"cost_per_operating_hour"`
+}
+
+// ReplaceOrRepairAnalysis weighs what it costs to keep a piece of equipment
+// running against what it would cost to replace it
+type ReplaceOrRepairAnalysis struct {
+	EquipmentID             uuid.UUID `json:"equipment_id"`
+	EquipmentName           string    `json:"equipment_name"`
+	AgeYears                float64   `json:"age_years"`
+	UsefulLifeYears         int       `json:"useful_life_years"`
+	BookValue               float64   `json:"book_value"`
+	ReplacementCost         float64   `json:"replacement_cost"`
+	AnnualReplacementCost   float64   `json:"annual_replacement_cost"` // the replacement cost spread over its useful life
+	RepairEstimate          float64   `json:"repair_estimate"`
+	TrailingMaintenanceCost float64   `json:"trailing_maintenance_cost"` // the last 12 months
+	TrailingDowntimeCost    float64   `json:"trailing_downtime_cost"`
+	Recommendation          string    `json:"recommendation"`
+	Reasons                 []string  `json:"reasons"`
+	GeneratedAt             time.Time `json:"generated_at"`
+}
+
+// FixedAssetRegisterEntry is one piece of equipment's line in the fixed asset register
+type FixedAssetRegisterEntry struct {
+	EquipmentID                    uuid.UUID  `json:"equipment_id"`
+	Name                           string     `json:"name"`
+	Type                           string     `json:"type"`
+	SerialNumber                   *string    `json:"serial_number,omitempty"`
+	InServiceDate                  time.Time  `json:"in_service_date"`
+	Method                         string     `json:"method"`
+	UsefulLifeYears                int        `json:"useful_life_years"`
+	Cost                           float64    `json:"cost"`
+	SalvageValue                   float64    `json:"salvage_value"`
+	OpeningAccumulatedDepreciation float64    `json:"opening_accumulated_depreciation"`
+	Depreciation                   float64    `json:"depreciation"`
+	ClosingAccumulatedDepreciation float64    `json:"closing_accumulated_depreciation"`
+	NetBookValue                   float64    `json:"net_book_value"`
+	DisposedAt                     *time.Time `json:"disposed_at,omitempty"`
+	DisposalProceeds               *float64   `json:"disposal_proceeds,omitempty"`
+	GainOnDisposal                 *float64   `json:"gain_on_disposal,omitempty"` // negative for a loss
+}
+
+// FixedAssetRegister lists the equipment carried as fixed assets during a calendar year
+type FixedAssetRegister struct {
+	Year              int                        `json:"year"`
+	Entries           []*FixedAssetRegisterEntry `json:"entries"`
+	TotalCost         float64                    `json:"total_cost"`
+	TotalDepreciation float64                    `json:"total_depreciation"`
+	TotalNetBookValue float64                    `json:"total_net_book_value"`
+}
+
+// FixedAssetRegisterExport is a generated fixed asset register file
+type FixedAssetRegisterExport struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"-"`
+}
+
+// GetAssetProfile retrieves how equipment is carried as a fixed asset
+func (s *EquipmentServiceImpl) GetAssetProfile(ctx context.Context, equipmentID uuid.UUID) (*domain.EquipmentAssetProfile, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if _, err := s.getEquipment(ctx, tenantID, equipmentID); err != nil {
+		return nil, err
+	}
+
+	return s.getAssetProfile(ctx, tenantID, equipmentID)
+}
+
+// SetAssetProfile sets the equipment's depreciation and running costs. A
+// recorded disposal is kept.
+func (s *EquipmentServiceImpl) SetAssetProfile(ctx context.Context, equipmentID uuid.UUID, req *EquipmentAssetProfileRequest) (*domain.EquipmentAssetProfile, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if err := ValidateAssetProfileRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	equipment, err := s.getEquipment(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if req.InServiceDate == nil && equipment.PurchaseDate == nil {
+		return nil, fmt.Errorf("validation failed: in-service date is required when the equipment has no purchase date")
+	}
+
+	existing, err := s.assetRepo.GetAssetProfile(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset profile: %w", err)
+	}
+
+	now := time.Now()
+	profile := &domain.EquipmentAssetProfile{
+		EquipmentID:          equipmentID,
+		TenantID:             tenantID,
+		DepreciationMethod:   req.DepreciationMethod,
+		UsefulLifeYears:      req.UsefulLifeYears,
+		SalvageValue:         roundCents(req.SalvageValue),
+		DecliningBalanceRate: req.DecliningBalanceRate,
+		InServiceDate:        req.InServiceDate,
+		ReplacementCost:      req.ReplacementCost,
+		FuelCostPerHour:      roundCents(req.FuelCostPerHour),
+		DowntimeCostPerHour:  roundCents(req.DowntimeCostPerHour),
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	if profile.DecliningBalanceRate == 0 {
+		profile.DecliningBalanceRate = 2
+	}
+	if existing != nil {
+		profile.DisposedAt = existing.DisposedAt
+		profile.DisposalProceeds = existing.DisposalProceeds
+		profile.CreatedAt = existing.CreatedAt
+	}
+
+	if err := s.assetRepo.UpsertAssetProfile(ctx, profile); err != nil {
+		return nil, fmt.Errorf("failed to save asset profile: %w", err)
+	}
+
+	var oldValues map[string]interface{}
+	if existing != nil {
+		oldValues = assetProfileAuditValues(existing)
+	}
+	s.logEquipmentAction(ctx, "equipment.asset_profile_set", equipmentID, oldValues, assetProfileAuditValues(profile))
+
+	return profile, nil
+}
+
+// DisposeEquipment records equipment being sold or scrapped and retires it.
+// Depreciation stops at the disposal date.
+func (s *EquipmentServiceImpl) DisposeEquipment(ctx context.Context, equipmentID uuid.UUID, req *EquipmentDisposalRequest) (*domain.EquipmentAssetProfile, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if req.DisposedAt.IsZero() {
+		return nil, fmt.Errorf("validation failed: disposal date is required")
+	}
+	if req.Proceeds < 0 {
+		return nil, fmt.Errorf("validation failed: proceeds cannot be negative")
+	}
+
+	equipment, err := s.getEquipment(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.getAssetProfile(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if profile.DisposedAt != nil {
+		return nil, fmt.Errorf("equipment was already disposed of on %s", profile.DisposedAt.Format("2006-01-02"))
+	}
+	if req.DisposedAt.Before(assetInServiceDate(equipment, profile)) {
+		return nil, fmt.Errorf("validation failed: disposal date is before the equipment went into service")
+	}
+
+	proceeds := roundCents(req.Proceeds)
+	profile.DisposedAt = &req.DisposedAt
+	profile.DisposalProceeds = &proceeds
+	profile.UpdatedAt = time.Now()
+
+	if err := s.assetRepo.UpsertAssetProfile(ctx, profile); err != nil {
+		return nil, fmt.Errorf("failed to save asset profile: %w", err)
+	}
+
+	if equipment.Status != "retired" {
+		oldStatus := equipment.Status
+		equipment.Status = "retired"
+		equipment.UpdatedAt = time.Now()
+		if err := s.equipmentRepo.Update(ctx, equipment); err != nil {
+			return nil, fmt.Errorf("failed to retire equipment: %w", err)
+		}
+		s.logEquipmentAction(ctx, "equipment.retire", equipmentID,
+			map[string]interface{}{"status": oldStatus},
+			map[string]interface{}{"status": equipment.Status})
+	}
+
+	s.logEquipmentAction(ctx, "equipment.dispose", equipmentID, nil, map[string]interface{}{
+		"disposed_at": profile.DisposedAt,
+		"proceeds":    proceeds,
+	})
+
+	return profile, nil
+}
+
+// GetDepreciationSchedule builds the equipment's depreciation schedule from its
+// purchase price and asset profile
+func (s *EquipmentServiceImpl) GetDepreciationSchedule(ctx context.Context, equipmentID uuid.UUID) (*DepreciationSchedule, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	equipment, err := s.getEquipment(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.getAssetProfile(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildEquipmentDepreciation(equipment, profile, time.Now())
+}
+
+// GetTotalCostOfOwnership totals the equipment's depreciation, maintenance,
+// fuel and downtime over a period. A zero start runs from when the equipment
+// went into service and a zero end runs to now.
+func (s *EquipmentServiceImpl) GetTotalCostOfOwnership(ctx context.Context, equipmentID uuid.UUID, startDate, endDate time.Time) (*TotalCostOfOwnership, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	equipment, err := s.getEquipment(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.getAssetProfile(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if startDate.IsZero() {
+		startDate = assetInServiceDate(equipment, profile)
+	}
+	if endDate.IsZero() {
+		endDate = now
+	}
+	if !endDate.After(startDate) {
+		return nil, fmt.Errorf("validation failed: end date must be after start date")
+	}
+
+	return s.totalCostOfOwnership(ctx, equipment, profile, startDate, endDate, now)
+}
+
+// AnalyzeReplaceOrRepair recommends whether to keep repairing equipment or
+// replace it, given an optional estimate for a pending repair
+func (s *EquipmentServiceImpl) AnalyzeReplaceOrRepair(ctx context.Context, equipmentID uuid.UUID, req *ReplaceOrRepairRequest) (*ReplaceOrRepairAnalysis, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	var repairEstimate float64
+	if req != nil {
+		repairEstimate = req.RepairEstimate
+	}
+	if repairEstimate < 0 {
+		return nil, fmt.Errorf("validation failed: repair estimate cannot be negative")
+	}
+
+	equipment, err := s.getEquipment(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.getAssetProfile(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.analyzeReplaceOrRepair(ctx, equipment, profile, repairEstimate, time.Now())
+}
+
+// GetFixedAssetRegister lists the equipment in service during a calendar year
+// with its cost, depreciation for the year and net book value at year end
+func (s *EquipmentServiceImpl) GetFixedAssetRegister(ctx context.Context, year int) (*FixedAssetRegister, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if year == 0 {
+		year = time.Now().Year()
+	}
+
+	profiles, err := s.assetRepo.ListAssetProfiles(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list asset profiles: %w", err)
+	}
+
+	equipmentIDs := make([]uuid.UUID, 0, len(profiles))
+	for _, profile := range profiles {
+		equipmentIDs = append(equipmentIDs, profile.EquipmentID)
+	}
+	equipmentList, err := s.equipmentRepo.GetByIDs(ctx, tenantID, equipmentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get equipment: %w", err)
+	}
+	equipmentByID := make(map[uuid.UUID]*domain.Equipment, len(equipmentList))
+	for _, equipment := range equipmentList {
+		equipmentByID[equipment.ID] = equipment
+	}
+
+	register := &FixedAssetRegister{Year: year, Entries: []*FixedAssetRegisterEntry{}}
+	for _, profile := range profiles {
+		equipment := equipmentByID[profile.EquipmentID]
+		if equipment == nil {
+			continue
+		}
+
+		schedule, err := buildEquipmentDepreciation(equipment, profile, time.Now())
+		if err != nil {
+			s.logger.Printf("Skipping equipment %s in fixed asset register: %v", equipment.ID, err)
+			continue
+		}
+
+		entry, ok := BuildFixedAssetRegisterEntry(equipment, profile, schedule, year)
+		if !ok {
+			continue
+		}
+		register.Entries = append(register.Entries, entry)
+		register.TotalCost += entry.Cost
+		register.TotalDepreciation += entry.Depreciation
+		if entry.DisposedAt == nil {
+			register.TotalNetBookValue += entry.NetBookValue
+		}
+	}
+
+	register.TotalCost = roundCents(register.TotalCost)
+	register.TotalDepreciation = roundCents(register.TotalDepreciation)
+	register.TotalNetBookValue = roundCents(register.TotalNetBookValue)
+
+	return register, nil
+}
+
+// ExportFixedAssetRegister generates the fixed asset register for a calendar year as CSV
+func (s *EquipmentServiceImpl) ExportFixedAssetRegister(ctx context.Context, year int) (*FixedAssetRegisterExport, error) {
+	register, err := s.GetFixedAssetRegister(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := BuildFixedAssetRegisterCSV(register)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build fixed asset register: %w", err)
+	}
+
+	return &FixedAssetRegisterExport{
+		Filename:    fmt.Sprintf("fixed_asset_register_%d.csv", register.Year),
+		ContentType: "text/csv",
+		Data:        data,
+	}, nil
+}
+
+// ValidateAssetProfileRequest checks a request to set an asset profile
+func ValidateAssetProfileRequest(req *EquipmentAssetProfileRequest) error {
+	if !containsString(domain.DepreciationMethods, req.DepreciationMethod) {
+		return fmt.Errorf("depreciation method must be one of %s", strings.Join(domain.DepreciationMethods, ", "))
+	}
+	if req.UsefulLifeYears <= 0 {
+		return fmt.Errorf("useful life must be positive")
+	}
+	if req.DepreciationMethod == domain.DepreciationMACRS && macrsHalfYearRates[req.UsefulLifeYears] == nil {
+		return fmt.Errorf("MACRS recovery period must be 3, 5, 7, 10, 15 or 20 years")
+	}
+	if req.DecliningBalanceRate < 0 || req.DecliningBalanceRate > 4 {
+		return fmt.Errorf("declining balance rate must be between 0 and 4")
+	}
+	if req.SalvageValue < 0 || req.FuelCostPerHour < 0 || req.DowntimeCostPerHour < 0 || floatValue(req.ReplacementCost) < 0 {
+		return fmt.Errorf("costs cannot be negative")
+	}
+	return nil
+}
+
+// BuildDepreciationSchedule depreciates cost by calendar year from the in-service
+// date. Straight-line and declining balance depreciate the months in service
+// each year down to the salvage value, with declining balance switching to
+// straight-line once that depreciates more. MACRS uses the IRS half-year tables
+// and ignores salvage value. A disposal ends the schedule in the disposal year,
+// which under MACRS takes half a year's depreciation.
+func BuildDepreciationSchedule(cost float64, inService time.Time, profile *domain.EquipmentAssetProfile) ([]DepreciationPeriod, error) {
+	method := profile.DepreciationMethod
+	salvage := math.Min(profile.SalvageValue, cost)
+	var macrsRates []float64
+	if method == domain.DepreciationMACRS {
+		if macrsRates = macrsHalfYearRates[profile.UsefulLifeYears]; macrsRates == nil {
+			return nil, fmt.Errorf("MACRS recovery period must be 3, 5, 7, 10, 15 or 20 years")
+		}
+		salvage = 0
+	}
+	if profile.UsefulLifeYears <= 0 {
+		return nil, fmt.Errorf("useful life must be positive")
+	}
+
+	life := float64(profile.UsefulLifeYears)
+	remainingLife := life
+	bookValue := roundCents(cost)
+	periods := []DepreciationPeriod{}
+
+	for year := inService.Year(); bookValue-salvage >= 0.005; year++ {
+		index := year - inService.Year()
+		start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		if index == 0 {
+			start = time.Date(year, inService.Month(), 1, 0, 0, 0, 0, time.UTC)
+		}
+		end := time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC)
+		disposed := profile.DisposedAt != nil && profile.DisposedAt.Year() == year
+		if disposed {
+			end = time.Date(year, profile.DisposedAt.Month(), profile.DisposedAt.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		fraction := float64(monthsInPeriod(start, end)) / 12
+
+		var amount float64
+		switch method {
+		case domain.DepreciationMACRS:
+			amount = cost * macrsRates[index] / 100
+			if index == len(macrsRates)-1 {
+				amount = bookValue
+			}
+			if disposed {
+				// No depreciation in the year placed in service, half a year otherwise
+				if index == 0 {
+					amount = 0
+				} else {
+					amount = cost * macrsRates[index] / 200
+				}
+			}
+		case domain.DepreciationDecliningBalance:
+			amount = bookValue * profile.DecliningBalanceRate / life * fraction
+			if straightLine := (bookValue - salvage) * math.Min(fraction/remainingLife, 1); straightLine > amount {
+				amount = straightLine
+			}
+		default:
+			amount = (cost - salvage) / life * fraction
+		}
+
+		remainingLife -= fraction
+		if method != domain.DepreciationMACRS && !disposed && remainingLife <= 1e-9 {
+			amount = bookValue - salvage
+		}
+		amount = roundCents(math.Min(amount, bookValue-salvage))
+
+		period := DepreciationPeriod{
+			Year:             year,
+			PeriodStart:      start,
+			PeriodEnd:        end,
+			OpeningBookValue: bookValue,
+			Depreciation:     amount,
+		}
+		bookValue = roundCents(bookValue - amount)
+		period.ClosingBookValue = bookValue
+		period.AccumulatedDepreciation = roundCents(cost - bookValue)
+		periods = append(periods, period)
+
+		if disposed {
+			break
+		}
+	}
+
+	return periods, nil
+}
+
+// BookValueAt returns the book value at a date, depreciating the period it
+// falls in for the whole months that have passed
+func BookValueAt(cost float64, periods []DepreciationPeriod, at time.Time) float64 {
+	bookValue := roundCents(cost)
+	for _, period := range periods {
+		if at.Before(period.PeriodStart) {
+			break
+		}
+		if !at.Before(period.PeriodEnd) {
+			bookValue = period.ClosingBookValue
+			continue
+		}
+		elapsed := monthsInPeriod(period.PeriodStart, at) - 1
+		return roundCents(period.OpeningBookValue - period.Depreciation*float64(elapsed)/float64(monthsInPeriod(period.PeriodStart, period.PeriodEnd)))
+	}
+	return bookValue
+}
+
+// RecommendReplaceOrRepair sets the analysis's recommendation. Equipment should
+// be replaced when the last year's maintenance plus the pending repair reach
+// half its replacement cost, when it is past its useful life and costs more a
+// year to keep running than a replacement costs a year, or when a repair on
+// ageing equipment costs more than the equipment is worth. Replacement should
+// be planned for equipment near the end of its life or whose running costs
+// have reached half of a replacement's annual cost.
+func RecommendReplaceOrRepair(analysis *ReplaceOrRepairAnalysis) {
+	life := float64(analysis.UsefulLifeYears)
+	runningCost := analysis.TrailingMaintenanceCost + analysis.TrailingDowntimeCost + analysis.RepairEstimate
+	repairsWithEstimate := analysis.TrailingMaintenanceCost + analysis.RepairEstimate
+
+	var replace, plan []string
+	if analysis.ReplacementCost > 0 {
+		if repairsWithEstimate >= analysis.ReplacementCost/2 {
+			replace = append(replace, fmt.Sprintf("repairs over the last year and the pending repair come to $%.2f, at least half the $%.2f replacement cost", repairsWithEstimate, analysis.ReplacementCost))
+		}
+		if analysis.AgeYears >= life && runningCost > analysis.AnnualReplacementCost {
+			replace = append(replace, fmt.Sprintf("past its %d-year useful life and costing $%.2f a year to keep running against $%.2f a year to replace", analysis.UsefulLifeYears, runningCost, analysis.AnnualReplacementCost))
+		} else if runningCost >= analysis.AnnualReplacementCost/2 {
+			plan = append(plan, fmt.Sprintf("costing $%.2f a year to keep running, over half the $%.2f a year a replacement costs", runningCost, analysis.AnnualReplacementCost))
+		}
+	}
+	if analysis.RepairEstimate > analysis.BookValue && analysis.AgeYears >= life*0.75 {
+		replace = append(replace, fmt.Sprintf("the $%.2f repair costs more than the $%.2f book value", analysis.RepairEstimate, analysis.BookValue))
+	}
+	if analysis.AgeYears >= life*0.75 && analysis.AgeYears < life {
+		plan = append(plan, fmt.Sprintf("%.1f years into a %d-year useful life", analysis.AgeYears, analysis.UsefulLifeYears))
+	}
+
+	switch {
+	case len(replace) > 0:
+		analysis.Recommendation = ReplaceOrRepairReplace
+		analysis.Reasons = replace
+	case len(plan) > 0:
+		analysis.Recommendation = ReplaceOrRepairPlanReplacement
+		analysis.Reasons = plan
+	default:
+		analysis.Recommendation = ReplaceOrRepairRepair
+		analysis.Reasons = []string{"repairs remain cheaper than replacing"}
+	}
+}
+
+// BuildFixedAssetRegisterEntry builds the equipment's register line for a
+// calendar year, reporting false when it was not in service that year
+func BuildFixedAssetRegisterEntry(equipment *domain.Equipment, profile *domain.EquipmentAssetProfile, schedule *DepreciationSchedule, year int) (*FixedAssetRegisterEntry, bool) {
+	if schedule.InServiceDate.Year() > year || (profile.DisposedAt != nil && profile.DisposedAt.Year() < year) {
+		return nil, false
+	}
+
+	yearStart := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd := time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC)
+	if profile.DisposedAt != nil && profile.DisposedAt.Year() == year {
+		yearEnd = *profile.DisposedAt
+	}
+
+	openingBookValue := BookValueAt(schedule.Cost, schedule.Periods, yearStart)
+	closingBookValue := BookValueAt(schedule.Cost, schedule.Periods, yearEnd)
+
+	entry := &FixedAssetRegisterEntry{
+		EquipmentID:                    equipment.ID,
+		Name:                           equipment.Name,
+		Type:                           equipment.Type,
+		SerialNumber:                   equipment.SerialNumber,
+		InServiceDate:                  schedule.InServiceDate,
+		Method:                         schedule.Method,
+		UsefulLifeYears:                schedule.UsefulLifeYears,
+		Cost:                           schedule.Cost,
+		SalvageValue:                   schedule.SalvageValue,
+		OpeningAccumulatedDepreciation: roundCents(schedule.Cost - openingBookValue),
+		Depreciation:                   roundCents(openingBookValue - closingBookValue),
+		ClosingAccumulatedDepreciation: roundCents(schedule.Cost - closingBookValue),
+		NetBookValue:                   closingBookValue,
+	}
+	if profile.DisposedAt != nil && profile.DisposedAt.Year() == year {
+		proceeds := floatValue(profile.DisposalProceeds)
+		gain := roundCents(proceeds - closingBookValue)
+		entry.DisposedAt = profile.DisposedAt
+		entry.DisposalProceeds = &proceeds
+		entry.GainOnDisposal = &gain
+	}
+
+	return entry, true
+}
+
+// BuildFixedAssetRegisterCSV writes the fixed asset register as CSV with a totals row
+func BuildFixedAssetRegisterCSV(register *FixedAssetRegister) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+
+	if err := w.Write([]string{
+		"Asset ID", "Name", "Type", "Serial Number", "In Service Date", "Method", "Useful Life (Years)",
+		"Cost", "Salvage Value", "Opening Accumulated Depreciation", "Depreciation " + strconv.Itoa(register.Year),
+		"Closing Accumulated Depreciation", "Net Book Value", "Disposal Date", "Disposal Proceeds", "Gain (Loss) on Disposal",
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range register.Entries {
+		var disposedAt, proceeds, gain string
+		if entry.DisposedAt != nil {
+			disposedAt = entry.DisposedAt.Format(accountingDateFormat)
+			proceeds = formatAmount(floatValue(entry.DisposalProceeds))
+			gain = formatAmount(floatValue(entry.GainOnDisposal))
+		}
+
+		if err := w.Write([]string{
+			entry.EquipmentID.String(), entry.Name, entry.Type, stringValue(entry.SerialNumber),
+			entry.InServiceDate.Format(accountingDateFormat), entry.Method, strconv.Itoa(entry.UsefulLifeYears),
+			formatAmount(entry.Cost), formatAmount(entry.SalvageValue), formatAmount(entry.OpeningAccumulatedDepreciation),
+			formatAmount(entry.Depreciation), formatAmount(entry.ClosingAccumulatedDepreciation), formatAmount(entry.NetBookValue),
+			disposedAt, proceeds, gain,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Write([]string{
+		"", "Total", "", "", "", "", "",
+		formatAmount(register.TotalCost), "", "", formatAmount(register.TotalDepreciation), "", formatAmount(register.TotalNetBookValue),
+		"", "", "",
+	}); err != nil {
+		return nil, err
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// Helper functions
+
+func (s *EquipmentServiceImpl) getAssetProfile(ctx context.Context, tenantID, equipmentID uuid.UUID) (*domain.EquipmentAssetProfile, error) {
+	profile, err := s.assetRepo.GetAssetProfile(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset profile: %w", err)
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("asset profile not found")
+	}
+	return profile, nil
+}
+
+// totalCostOfOwnership totals the equipment's costs over the period. Fuel is
+// costed from the hours meter at the profile's fuel cost per hour.
+func (s *EquipmentServiceImpl) totalCostOfOwnership(ctx context.Context, equipment *domain.Equipment, profile *domain.EquipmentAssetProfile, startDate, endDate, now time.Time) (*TotalCostOfOwnership, error) {
+	schedule, err := buildEquipmentDepreciation(equipment, profile, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Equipment disposed of during the period lost whatever its sale did not
+	// recover, and a sale above book value reduces the cost
+	closingValue := BookValueAt(schedule.Cost, schedule.Periods, endDate)
+	if profile.DisposedAt != nil && !profile.DisposedAt.Before(startDate) && !profile.DisposedAt.After(endDate) {
+		closingValue = floatValue(profile.DisposalProceeds)
+	}
+
+	maintenance, err := s.GetMaintenanceCosts(ctx, equipment.ID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	workOrders, err := s.workOrderRepo.ListWorkOrders(ctx, equipment.TenantID, &WorkOrderFilter{EquipmentID: &equipment.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work orders: %w", err)
+	}
+	workOrderSummary := SummarizeWorkOrders(workOrders, startDate, endDate, now)
+
+	operatingHours, err := s.meterRepo.SumUsage(ctx, equipment.TenantID, equipment.ID, domain.EquipmentMeterHours, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operating hours: %w", err)
+	}
+
+	// Fuel bought during the period is used where it was logged, otherwise it
+	// is estimated from the hours run
+	fuelCost, logged, err := s.loggedFuelCost(ctx, equipment.TenantID, equipment.ID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	if !logged {
+		fuelCost = roundCents(operatingHours * profile.FuelCostPerHour)
+	}
+
+	tco := &TotalCostOfOwnership{
+		EquipmentID:     equipment.ID,
+		EquipmentName:   equipment.Name,
+		Period:          TimeRange{Start: startDate, End: endDate},
+		CapitalCost:     roundCents(BookValueAt(schedule.Cost, schedule.Periods, startDate) - closingValue),
+		MaintenanceCost: roundCents(maintenance.TotalCost + workOrderSummary.Cost),
+		FuelCost:        fuelCost,
+		DowntimeHours:   workOrderSummary.DowntimeHours,
+		DowntimeCost:    roundCents(workOrderSummary.DowntimeHours * profile.DowntimeCostPerHour),
+		OperatingHours:  operatingHours,
+	}
+	tco.TotalCost = roundCents(tco.CapitalCost + tco.MaintenanceCost + tco.FuelCost + tco.DowntimeCost)
+	if operatingHours > 0 {
+		tco.CostPerOperatingHour = roundCents(tco.TotalCost / operatingHours)
+	}
+
+	return tco, nil
+}
+
+func (s *EquipmentServiceImpl) analyzeReplaceOrRepair(ctx context.Context, equipment *domain.Equipment, profile *domain.EquipmentAssetProfile, repairEstimate float64, now time.Time) (*ReplaceOrRepairAnalysis, error) {
+	schedule, err := buildEquipmentDepreciation(equipment, profile, now)
+	if err != nil {
+		return nil, err
+	}
+
+	trailing, err := s.totalCostOfOwnership(ctx, equipment, profile, now.AddDate(-1, 0, 0), now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	replacementCost := schedule.Cost
+	if profile.ReplacementCost != nil {
+		replacementCost = *profile.ReplacementCost
+	}
+
+	analysis := &ReplaceOrRepairAnalysis{
+		EquipmentID:             equipment.ID,
+		EquipmentName:           equipment.Name,
+		AgeYears:                math.Round(now.Sub(schedule.InServiceDate).Hours()/24/365.25*10) / 10,
+		UsefulLifeYears:         profile.UsefulLifeYears,
+		BookValue:               schedule.BookValue,
+		ReplacementCost:         replacementCost,
+		AnnualReplacementCost:   roundCents(replacementCost / float64(profile.UsefulLifeYears)),
+		RepairEstimate:          roundCents(repairEstimate),
+		TrailingMaintenanceCost: trailing.MaintenanceCost,
+		TrailingDowntimeCost:    trailing.DowntimeCost,
+		GeneratedAt:             now,
+	}
+	RecommendReplaceOrRepair(analysis)
+
+	return analysis, nil
+}
+
+// buildEquipmentDepreciation builds the depreciation schedule for equipment
+// with its book value at now
+func buildEquipmentDepreciation(equipment *domain.Equipment, profile *domain.EquipmentAssetProfile, now time.Time) (*DepreciationSchedule, error) {
+	if equipment.PurchasePrice == nil {
+		return nil, fmt.Errorf("validation failed: equipment has no purchase price to depreciate")
+	}
+
+	inService := assetInServiceDate(equipment, profile)
+	periods, err := BuildDepreciationSchedule(*equipment.PurchasePrice, inService, profile)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	schedule := &DepreciationSchedule{
+		EquipmentID:     equipment.ID,
+		EquipmentName:   equipment.Name,
+		Method:          profile.DepreciationMethod,
+		Cost:            roundCents(*equipment.PurchasePrice),
+		SalvageValue:    profile.SalvageValue,
+		UsefulLifeYears: profile.UsefulLifeYears,
+		InServiceDate:   inService,
+		DisposedAt:      profile.DisposedAt,
+		Periods:         periods,
+	}
+	if profile.DepreciationMethod == domain.DepreciationMACRS {
+		schedule.SalvageValue = 0
+	}
+	schedule.BookValue = BookValueAt(schedule.Cost, periods, now)
+
+	return schedule, nil
+}
+
+// assetInServiceDate is when the equipment started depreciating, its purchase
+// date unless the profile says otherwise
+func assetInServiceDate(equipment *domain.Equipment, profile *domain.EquipmentAssetProfile) time.Time {
+	if profile.InServiceDate != nil {
+		return *profile.InServiceDate
+	}
+	if equipment.PurchaseDate != nil {
+		return *equipment.PurchaseDate
+	}
+	return equipment.CreatedAt
+}
+
+// monthsInPeriod counts the calendar months from start's month to end's month inclusive
+func monthsInPeriod(start, end time.Time) int {
+	return (end.Year()-start.Year())*12 + int(end.Month()-start.Month()) + 1
+}
+
+func assetProfileAuditValues(profile *domain.EquipmentAssetProfile) map[string]interface{} {
+	return map[string]interface{}{
+		"depreciation_method":    profile.DepreciationMethod,
+		"useful_life_years":      profile.UsefulLifeYears,
+		"salvage_value":          profile.SalvageValue,
+		"in_service_date":        profile.InServiceDate,
+		"replacement_cost":       profile.ReplacementCost,
+		"fuel_cost_per_hour":     profile.FuelCostPerHour,
+		"downtime_cost_per_hour": profile.DowntimeCostPerHour,
+	}
+}
@@ -0,0 +1,573 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// EquipmentCheckoutRepository defines data access for equipment asset tags and
+// checkouts
+type EquipmentCheckoutRepository interface {
+	CreateAssetTag(ctx context.Context, tag *domain.EquipmentAssetTag) error
+	GetAssetTag(ctx context.Context, tenantID, equipmentID uuid.UUID) (*domain.EquipmentAssetTag, error)
+	GetAssetTagByCode(ctx context.Context, tenantID uuid.UUID, code string) (*domain.EquipmentAssetTag, error)
+	ListAssetTags(ctx context.Context, tenantID uuid.UUID, equipmentIDs []uuid.UUID) ([]*domain.EquipmentAssetTag, error)
+
+	// CreateCheckout stores a checkout, failing if the equipment is already out
+	CreateCheckout(ctx context.Context, checkout *domain.EquipmentCheckout) error
+	UpdateCheckout(ctx context.Context, checkout *domain.EquipmentCheckout) error
+	GetOpenCheckout(ctx context.Context, tenantID, equipmentID uuid.UUID) (*domain.EquipmentCheckout, error)
+	ListCheckouts(ctx context.Context, tenantID uuid.UUID, filter *EquipmentCheckoutFilter) ([]*domain.EquipmentCheckout, error)
+
+	// ListOverdueCheckouts returns open checkouts across all tenants that were
+	// due back before asOf and have not been reported overdue
+	ListOverdueCheckouts(ctx context.Context, asOf time.Time) ([]*domain.EquipmentCheckout, error)
+	MarkOverdueNotified(ctx context.Context, tenantID, checkoutID uuid.UUID, notifiedAt time.Time) error
+}
+
+// EquipmentCheckoutFilter narrows a checkout listing, most recent first
+type EquipmentCheckoutFilter struct {
+	EquipmentID *uuid.UUID `json:"equipment_id,omitempty"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	CrewID      *uuid.UUID `json:"crew_id,omitempty"`
+	OpenOnly    bool       `json:"open_only"`
+	Limit       int        `json:"limit,omitempty"`
+}
+
+// Where a piece of equipment is
+const (
+	EquipmentLocationInShop     = "in_shop"
+	EquipmentLocationCheckedOut = "checked_out"
+	EquipmentLocationOverdue    = "overdue"
+)
+
+// assetTagPrefix starts every asset tag code
+const assetTagPrefix = "EQ-"
+
+// EquipmentScanRequest identifies equipment by its scanned asset tag code, or
+// by ID when the label cannot be read
+type EquipmentScanRequest struct {
+	Code        string     `json:"code,omitempty"`
+	EquipmentID *uuid.UUID `json:"equipment_id,omitempty"`
+}
+
+// EquipmentCheckoutRequest checks equipment out to a user or a crew
+type EquipmentCheckoutRequest struct {
+	EquipmentScanRequest
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	CrewID    *uuid.UUID `json:"crew_id,omitempty"`
+	DueBackAt *time.Time `json:"due_back_at,omitempty"`
+	Notes     *string    `json:"notes,omitempty"`
+}
+
+// EquipmentCheckinRequest checks equipment back in to the shop. Equipment
+// returned needing service or damaged is flagged to the shop.
+type EquipmentCheckinRequest struct {
+	EquipmentScanRequest
+	Condition string  `json:"condition,omitempty"` // defaults to good
+	Notes     *string `json:"notes,omitempty"`
+}
+
+// EquipmentWhereaboutsFilter narrows the where-is-it-now view to equipment held
+// by a user or a crew, or to equipment overdue back
+type EquipmentWhereaboutsFilter struct {
+	AssignedUserID *uuid.UUID `json:"assigned_user_id,omitempty"`
+	AssignedCrewID *uuid.UUID `json:"assigned_crew_id,omitempty"`
+	OverdueOnly    bool       `json:"overdue_only"`
+}
+
+// EquipmentWhereabouts is where a piece of equipment is now and who has it
+type EquipmentWhereabouts struct {
+	Equipment    *domain.Equipment         `json:"equipment"`
+	AssetTag     string                    `json:"asset_tag,omitempty"`
+	Location     string                    `json:"location"`
+	Checkout     *domain.EquipmentCheckout `json:"checkout,omitempty"`
+	HoursOut     float64                   `json:"hours_out,omitempty"`
+	HoursOverdue float64                   `json:"hours_overdue,omitempty"`
+}
+
+// AssetLabelSheet is a printable sheet of equipment QR labels
+type AssetLabelSheet struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"-"`
+}
+
+// GetAssetTag returns the equipment's asset tag, issuing one if it has none
+func (s *EquipmentServiceImpl) GetAssetTag(ctx context.Context, equipmentID uuid.UUID) (*domain.EquipmentAssetTag, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if _, err := s.getEquipment(ctx, tenantID, equipmentID); err != nil {
+		return nil, err
+	}
+
+	return s.ensureAssetTag(ctx, tenantID, equipmentID)
+}
+
+// GenerateAssetLabels renders QR asset labels for the equipment as a PDF label
+// sheet, issuing tags to equipment that has none
+func (s *EquipmentServiceImpl) GenerateAssetLabels(ctx context.Context, equipmentIDs []uuid.UUID) (*AssetLabelSheet, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if len(equipmentIDs) == 0 {
+		return nil, fmt.Errorf("validation failed: at least one piece of equipment is required")
+	}
+
+	equipment, err := s.equipmentRepo.GetByIDs(ctx, tenantID, equipmentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get equipment: %w", err)
+	}
+	byID := make(map[uuid.UUID]*domain.Equipment, len(equipment))
+	for _, eq := range equipment {
+		byID[eq.ID] = eq
+	}
+
+	// Labels print in the order requested
+	labels := make([]AssetLabel, 0, len(equipmentIDs))
+	for _, equipmentID := range equipmentIDs {
+		eq, ok := byID[equipmentID]
+		if !ok {
+			return nil, fmt.Errorf("equipment %s not found", equipmentID)
+		}
+		tag, err := s.ensureAssetTag(ctx, tenantID, equipmentID)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, AssetLabel{Code: tag.Code, Name: eq.Name, SerialNumber: stringValue(eq.SerialNumber)})
+	}
+
+	data, err := BuildAssetLabelSheetPDF(labels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render asset labels: %w", err)
+	}
+
+	return &AssetLabelSheet{
+		Filename:    fmt.Sprintf("asset_labels_%s.pdf", time.Now().Format("2006-01-02")),
+		ContentType: "application/pdf",
+		Data:        data,
+	}, nil
+}
+
+// ScanAssetTag looks up the equipment behind a scanned asset tag and where it is now
+func (s *EquipmentServiceImpl) ScanAssetTag(ctx context.Context, code string) (*EquipmentWhereabouts, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	equipment, tag, err := s.resolveScan(ctx, tenantID, &EquipmentScanRequest{Code: code})
+	if err != nil {
+		return nil, err
+	}
+
+	checkout, err := s.checkoutRepo.GetOpenCheckout(ctx, tenantID, equipment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkout: %w", err)
+	}
+
+	return BuildEquipmentWhereabouts(equipment, tag, checkout, time.Now()), nil
+}
+
+// CheckOutEquipment checks equipment out of the shop to a user or a crew
+func (s *EquipmentServiceImpl) CheckOutEquipment(ctx context.Context, req *EquipmentCheckoutRequest) (*domain.EquipmentCheckout, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	now := time.Now()
+	if err := ValidateEquipmentCheckoutRequest(req, now); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	equipment, _, err := s.resolveScan(ctx, tenantID, &req.EquipmentScanRequest)
+	if err != nil {
+		return nil, err
+	}
+	if equipment.Status == "retired" || equipment.Status == "maintenance" {
+		return nil, fmt.Errorf("equipment is %s and cannot be checked out", equipment.Status)
+	}
+
+	open, err := s.checkoutRepo.GetOpenCheckout(ctx, tenantID, equipment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkout: %w", err)
+	}
+	if open != nil {
+		return nil, fmt.Errorf("equipment is already checked out since %s", open.CheckedOutAt.Format(time.RFC3339))
+	}
+
+	checkout := &domain.EquipmentCheckout{
+		ID:            uuid.New(),
+		TenantID:      tenantID,
+		EquipmentID:   equipment.ID,
+		UserID:        req.UserID,
+		CrewID:        req.CrewID,
+		CheckedOutAt:  now,
+		CheckedOutBy:  GetUserIDFromContext(ctx),
+		DueBackAt:     req.DueBackAt,
+		CheckoutNotes: req.Notes,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.checkoutRepo.CreateCheckout(ctx, checkout); err != nil {
+		return nil, fmt.Errorf("failed to check out equipment: %w", err)
+	}
+
+	if equipment.Status == "available" {
+		s.setEquipmentStatus(ctx, equipment, "in_use")
+	}
+
+	s.logEquipmentAction(ctx, "equipment.checked_out", equipment.ID, nil, map[string]interface{}{
+		"checkout_id": checkout.ID,
+		"user_id":     checkout.UserID,
+		"crew_id":     checkout.CrewID,
+		"due_back_at": checkout.DueBackAt,
+	})
+
+	return checkout, nil
+}
+
+// CheckInEquipment returns checked out equipment to the shop
+func (s *EquipmentServiceImpl) CheckInEquipment(ctx context.Context, req *EquipmentCheckinRequest) (*domain.EquipmentCheckout, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if req.Condition == "" {
+		req.Condition = domain.ReturnConditionGood
+	}
+	if !containsString(domain.ReturnConditions, req.Condition) {
+		return nil, fmt.Errorf("validation failed: unknown return condition %q", req.Condition)
+	}
+
+	equipment, _, err := s.resolveScan(ctx, tenantID, &req.EquipmentScanRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	checkout, err := s.checkoutRepo.GetOpenCheckout(ctx, tenantID, equipment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkout: %w", err)
+	}
+	if checkout == nil {
+		return nil, fmt.Errorf("equipment is not checked out")
+	}
+
+	now := time.Now()
+	checkout.CheckedInAt = &now
+	checkout.CheckedInBy = GetUserIDFromContext(ctx)
+	checkout.ReturnCondition = &req.Condition
+	checkout.ReturnNotes = req.Notes
+	checkout.UpdatedAt = now
+	if err := s.checkoutRepo.UpdateCheckout(ctx, checkout); err != nil {
+		return nil, fmt.Errorf("failed to check in equipment: %w", err)
+	}
+
+	if equipment.Status == "in_use" {
+		s.setEquipmentStatus(ctx, equipment, "available")
+	}
+
+	s.logEquipmentAction(ctx, "equipment.checked_in", equipment.ID, nil, map[string]interface{}{
+		"checkout_id":      checkout.ID,
+		"return_condition": req.Condition,
+		"overdue":          checkout.DueBackAt != nil && now.After(*checkout.DueBackAt),
+	})
+
+	if req.Condition != domain.ReturnConditionGood {
+		s.notifyEquipmentReturnCondition(ctx, equipment, checkout)
+	}
+
+	return checkout, nil
+}
+
+// GetEquipmentWhereabouts lists the equipment out of the shop now, who has it
+// and whether it is overdue back
+func (s *EquipmentServiceImpl) GetEquipmentWhereabouts(ctx context.Context, filter *EquipmentWhereaboutsFilter) ([]*EquipmentWhereabouts, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if filter == nil {
+		filter = &EquipmentWhereaboutsFilter{}
+	}
+
+	checkouts, err := s.checkoutRepo.ListCheckouts(ctx, tenantID, &EquipmentCheckoutFilter{
+		UserID:   filter.AssignedUserID,
+		CrewID:   filter.AssignedCrewID,
+		OpenOnly: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkouts: %w", err)
+	}
+
+	equipmentIDs := make([]uuid.UUID, len(checkouts))
+	for i, checkout := range checkouts {
+		equipmentIDs[i] = checkout.EquipmentID
+	}
+	whereabouts := []*EquipmentWhereabouts{}
+	if len(equipmentIDs) == 0 {
+		return whereabouts, nil
+	}
+
+	equipment, err := s.equipmentRepo.GetByIDs(ctx, tenantID, equipmentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get equipment: %w", err)
+	}
+	byID := make(map[uuid.UUID]*domain.Equipment, len(equipment))
+	for _, eq := range equipment {
+		byID[eq.ID] = eq
+	}
+
+	tags, err := s.checkoutRepo.ListAssetTags(ctx, tenantID, equipmentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list asset tags: %w", err)
+	}
+	tagsByID := make(map[uuid.UUID]*domain.EquipmentAssetTag, len(tags))
+	for _, tag := range tags {
+		tagsByID[tag.EquipmentID] = tag
+	}
+
+	now := time.Now()
+	for _, checkout := range checkouts {
+		eq, ok := byID[checkout.EquipmentID]
+		if !ok {
+			continue
+		}
+		entry := BuildEquipmentWhereabouts(eq, tagsByID[eq.ID], checkout, now)
+		if filter.OverdueOnly && entry.Location != EquipmentLocationOverdue {
+			continue
+		}
+		whereabouts = append(whereabouts, entry)
+	}
+
+	return whereabouts, nil
+}
+
+// GetCheckoutHistory lists an equipment's most recent checkouts
+func (s *EquipmentServiceImpl) GetCheckoutHistory(ctx context.Context, equipmentID uuid.UUID, limit int) ([]*domain.EquipmentCheckout, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if _, err := s.getEquipment(ctx, tenantID, equipmentID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	checkouts, err := s.checkoutRepo.ListCheckouts(ctx, tenantID, &EquipmentCheckoutFilter{EquipmentID: &equipmentID, Limit: limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkouts: %w", err)
+	}
+
+	return checkouts, nil
+}
+
+// ProcessOverdueCheckouts alerts whoever holds equipment that is overdue back
+// to the shop. Each checkout is reported once.
+func (s *EquipmentServiceImpl) ProcessOverdueCheckouts(ctx context.Context, now time.Time) error {
+	checkouts, err := s.checkoutRepo.ListOverdueCheckouts(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to list overdue checkouts: %w", err)
+	}
+
+	for _, checkout := range checkouts {
+		tenantCtx := context.WithValue(ctx, "tenant_id", checkout.TenantID)
+
+		equipment, err := s.equipmentRepo.GetByID(tenantCtx, checkout.TenantID, checkout.EquipmentID)
+		if err != nil || equipment == nil {
+			s.logger.Printf("Failed to get equipment for overdue checkout %s: %v", checkout.ID, err)
+			continue
+		}
+
+		if err := s.notifyOverdueCheckout(tenantCtx, equipment, checkout, now); err != nil {
+			s.logger.Printf("Failed to send overdue alert for checkout %s: %v", checkout.ID, err)
+			continue
+		}
+
+		if err := s.checkoutRepo.MarkOverdueNotified(tenantCtx, checkout.TenantID, checkout.ID, now); err != nil {
+			s.logger.Printf("Failed to mark checkout %s overdue notified: %v", checkout.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateEquipmentCheckoutRequest checks that the equipment is identified, that
+// it goes to exactly one of a user or a crew, and that any due time is ahead
+func ValidateEquipmentCheckoutRequest(req *EquipmentCheckoutRequest, now time.Time) error {
+	if strings.TrimSpace(req.Code) == "" && req.EquipmentID == nil {
+		return fmt.Errorf("an asset tag code or equipment ID is required")
+	}
+	if (req.UserID == nil) == (req.CrewID == nil) {
+		return fmt.Errorf("equipment must be checked out to either a user or a crew")
+	}
+	if req.DueBackAt != nil && !req.DueBackAt.After(now) {
+		return fmt.Errorf("due back time must be in the future")
+	}
+	return nil
+}
+
+// NormalizeAssetTagCode tidies a scanned code, which scanners and people may
+// give in lower case, with spaces or without the prefix
+func NormalizeAssetTagCode(code string) string {
+	code = strings.ToUpper(strings.Join(strings.Fields(code), ""))
+	if code != "" && !strings.HasPrefix(code, assetTagPrefix) {
+		code = assetTagPrefix + code
+	}
+	return code
+}
+
+// BuildEquipmentWhereabouts works out where equipment is from its open
+// checkout, if it has one
+func BuildEquipmentWhereabouts(equipment *domain.Equipment, tag *domain.EquipmentAssetTag, checkout *domain.EquipmentCheckout, now time.Time) *EquipmentWhereabouts {
+	whereabouts := &EquipmentWhereabouts{
+		Equipment: equipment,
+		Location:  EquipmentLocationInShop,
+	}
+	if tag != nil {
+		whereabouts.AssetTag = tag.Code
+	}
+	if checkout == nil || checkout.CheckedInAt != nil {
+		return whereabouts
+	}
+
+	whereabouts.Checkout = checkout
+	whereabouts.Location = EquipmentLocationCheckedOut
+	whereabouts.HoursOut = math.Round(now.Sub(checkout.CheckedOutAt).Hours()*10) / 10
+	if checkout.IsOverdue(now) {
+		whereabouts.Location = EquipmentLocationOverdue
+		whereabouts.HoursOverdue = math.Round(now.Sub(*checkout.DueBackAt).Hours()*10) / 10
+	}
+	return whereabouts
+}
+
+// resolveScan finds the equipment a scan identifies, along with its asset tag
+func (s *EquipmentServiceImpl) resolveScan(ctx context.Context, tenantID uuid.UUID, req *EquipmentScanRequest) (*domain.Equipment, *domain.EquipmentAssetTag, error) {
+	if code := NormalizeAssetTagCode(req.Code); code != "" {
+		tag, err := s.checkoutRepo.GetAssetTagByCode(ctx, tenantID, code)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get asset tag: %w", err)
+		}
+		if tag == nil {
+			return nil, nil, fmt.Errorf("asset tag %s not found", code)
+		}
+		equipment, err := s.getEquipment(ctx, tenantID, tag.EquipmentID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return equipment, tag, nil
+	}
+
+	if req.EquipmentID == nil {
+		return nil, nil, fmt.Errorf("validation failed: an asset tag code or equipment ID is required")
+	}
+	equipment, err := s.getEquipment(ctx, tenantID, *req.EquipmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	tag, err := s.checkoutRepo.GetAssetTag(ctx, tenantID, equipment.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get asset tag: %w", err)
+	}
+	return equipment, tag, nil
+}
+
+func (s *EquipmentServiceImpl) ensureAssetTag(ctx context.Context, tenantID, equipmentID uuid.UUID) (*domain.EquipmentAssetTag, error) {
+	tag, err := s.checkoutRepo.GetAssetTag(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset tag: %w", err)
+	}
+	if tag != nil {
+		return tag, nil
+	}
+
+	code, err := generateAssetTagCode()
+	if err != nil {
+		return nil, err
+	}
+	tag = &domain.EquipmentAssetTag{
+		EquipmentID: equipmentID,
+		TenantID:    tenantID,
+		Code:        code,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.checkoutRepo.CreateAssetTag(ctx, tag); err != nil {
+		return nil, fmt.Errorf("failed to create asset tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// generateAssetTagCode issues a short code that is easy to read off a label
+// and type in when the QR code is damaged
+func generateAssetTagCode() (string, error) {
+	random := make([]byte, 5)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate asset tag code: %w", err)
+	}
+	return assetTagPrefix + base32.StdEncoding.EncodeToString(random), nil
+}
+
+func (s *EquipmentServiceImpl) setEquipmentStatus(ctx context.Context, equipment *domain.Equipment, status string) {
+	oldStatus := equipment.Status
+	equipment.Status = status
+	equipment.UpdatedAt = time.Now()
+	if err := s.equipmentRepo.Update(ctx, equipment); err != nil {
+		s.logger.Printf("Failed to update equipment %s status from %s to %s: %v", equipment.ID, oldStatus, status, err)
+	}
+}
+
+func (s *EquipmentServiceImpl) notifyOverdueCheckout(ctx context.Context, equipment *domain.Equipment, checkout *domain.EquipmentCheckout, now time.Time) error {
+	return s.notificationService.SendNotification(ctx, &NotificationRequest{
+		UserID:  checkout.UserID,
+		Type:    "equipment.checkout_overdue",
+		Title:   "Equipment Overdue",
+		Message: fmt.Sprintf("%s was due back at the shop %s", equipment.Name, checkout.DueBackAt.Format("Jan 2 3:04 PM")),
+		Data: map[string]interface{}{
+			"checkout_id":    checkout.ID,
+			"equipment_id":   equipment.ID,
+			"equipment_name": equipment.Name,
+			"user_id":        checkout.UserID,
+			"crew_id":        checkout.CrewID,
+			"due_back_at":    checkout.DueBackAt,
+			"hours_overdue":  math.Round(now.Sub(*checkout.DueBackAt).Hours()*10) / 10,
+		},
+	})
+}
+
+func (s *EquipmentServiceImpl) notifyEquipmentReturnCondition(ctx context.Context, equipment *domain.Equipment, checkout *domain.EquipmentCheckout) {
+	if err := s.notificationService.SendNotification(ctx, &NotificationRequest{
+		Type:    "equipment.returned_" + *checkout.ReturnCondition,
+		Title:   "Equipment Returned for Review",
+		Message: fmt.Sprintf("%s was checked in marked %s", equipment.Name, strings.ReplaceAll(*checkout.ReturnCondition, "_", " ")),
+		Data: map[string]interface{}{
+			"checkout_id":    checkout.ID,
+			"equipment_id":   equipment.ID,
+			"equipment_name": equipment.Name,
+			"notes":          checkout.ReturnNotes,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to send equipment return notification: %v", err)
+	}
+}
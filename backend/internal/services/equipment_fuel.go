@@ -0,0 +1,787 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// FuelRepository defines data access for fuel entries
+type FuelRepository interface {
+	CreateFuelEntry(ctx context.Context, entry *domain.FuelEntry) error
+	ListFuelEntries(ctx context.Context, tenantID uuid.UUID, filter *FuelEntryFilter) ([]*domain.FuelEntry, error)
+
+	// GetPreviousFuelEntry returns the equipment's latest fuel entry before the
+	// given time with a reading on the meter
+	GetPreviousFuelEntry(ctx context.Context, tenantID, equipmentID uuid.UUID, meter string, before time.Time) (*domain.FuelEntry, error)
+}
+
+// FuelEntryFilter narrows a fuel entry listing. Entries are listed oldest first,
+// for all equipment when EquipmentIDs is empty, and Limit keeps the most recent.
+type FuelEntryFilter struct {
+	EquipmentIDs  []uuid.UUID `json:"equipment_ids,omitempty"`
+	StartDate     *time.Time  `json:"start_date,omitempty"`
+	EndDate       *time.Time  `json:"end_date,omitempty"`
+	AnomaliesOnly bool        `json:"anomalies_only"`
+	Limit         int         `json:"limit,omitempty"`
+}
+
+// fuelBaselineSampleSize is how many recent fills make up an equipment's normal
+// fuel efficiency and fill volume
+const fuelBaselineSampleSize = 10
+
+// fuelBaselineMinSamples is how many earlier fills are needed before a fill is
+// compared against the baseline
+const fuelBaselineMinSamples = 3
+
+// Anomaly thresholds relative to the baseline
+const (
+	fuelLowEfficiencyTolerance = 0.25 // 25% worse than the median efficiency
+	fuelUnusualVolumeFactor    = 1.5  // half again the median fill
+)
+
+// fuelTrendTolerance is how far the latest month's efficiency may fall from the
+// period's before the trend is reported as deteriorating
+const fuelTrendTolerance = 0.10
+
+// fuelRateWindowDays is how far back fuel entries are averaged to price a mile
+// or hour of equipment use on a job
+const fuelRateWindowDays = 180
+
+// Fuel efficiency units
+const (
+	FuelEfficiencyMPG = "mpg" // miles per gallon, higher is better
+	FuelEfficiencyGPH = "gph" // gallons per hour, lower is better
+)
+
+// FuelEntryRequest records a fuel purchase. Give the odometer for vehicles and
+// the engine hours for equipment without one; fuel efficiency is worked out on
+// the odometer when both are given. TotalCost defaults to gallons times price.
+type FuelEntryRequest struct {
+	FueledAt       *time.Time `json:"fueled_at,omitempty"`
+	FuelType       string     `json:"fuel_type"`
+	Gallons        float64    `json:"gallons"`
+	PricePerGallon float64    `json:"price_per_gallon"`
+	TotalCost      *float64   `json:"total_cost,omitempty"`
+	Odometer       *float64   `json:"odometer,omitempty"`
+	EngineHours    *float64   `json:"engine_hours,omitempty"`
+	Vendor         *string    `json:"vendor,omitempty"`
+	Notes          *string    `json:"notes,omitempty"`
+}
+
+// FuelBaseline is an equipment's normal fuel efficiency and fill volume, the
+// medians of its recent fills
+type FuelBaseline struct {
+	Meter      string  `json:"meter"`
+	Efficiency float64 `json:"efficiency"`
+	Gallons    float64 `json:"gallons"`
+	Samples    int     `json:"samples"`
+}
+
+// FuelTrendPoint is an equipment's fuel use for one month
+type FuelTrendPoint struct {
+	Month      time.Time `json:"month"`
+	Gallons    float64   `json:"gallons"`
+	Cost       float64   `json:"cost"`
+	Usage      float64   `json:"usage"`
+	Efficiency *float64  `json:"efficiency,omitempty"`
+}
+
+// FuelEfficiencyReport trends an equipment's fuel efficiency month by month in
+// miles per gallon, or gallons per hour for equipment without an odometer.
+// Deteriorating is set when the latest month is notably worse than the period
+// as a whole, which can point to a leak, theft or a maintenance problem.
+type FuelEfficiencyReport struct {
+	EquipmentID   uuid.UUID           `json:"equipment_id"`
+	EquipmentName string              `json:"equipment_name"`
+	Period        TimeRange           `json:"period"`
+	Meter         string              `json:"meter,omitempty"`
+	Unit          string              `json:"unit,omitempty"`
+	Gallons       float64             `json:"gallons"`
+	Cost          float64             `json:"cost"`
+	Usage         float64             `json:"usage"`
+	Efficiency    *float64            `json:"efficiency,omitempty"`
+	CostPerUnit   *float64            `json:"cost_per_unit,omitempty"`
+	Trend         []FuelTrendPoint    `json:"trend"`
+	ChangePercent *float64            `json:"change_percent,omitempty"` // latest month against the period
+	Deteriorating bool                `json:"deteriorating"`
+	Anomalies     []*domain.FuelEntry `json:"anomalies"`
+}
+
+// FuelRate is what a mile or hour of an equipment's use costs in fuel
+type FuelRate struct {
+	EquipmentID uuid.UUID `json:"equipment_id"`
+	Meter       string    `json:"meter"`
+	CostPerUnit float64   `json:"cost_per_unit"`
+}
+
+// JobFuelAllocation is the fuel cost charged to a job for one piece of
+// equipment, by the miles driven or hours run on the job
+type JobFuelAllocation struct {
+	JobID       uuid.UUID `json:"job_id"`
+	EquipmentID uuid.UUID `json:"equipment_id"`
+	Meter       string    `json:"meter"`
+	Usage       float64   `json:"usage"`
+	CostPerUnit float64   `json:"cost_per_unit"`
+	Cost        float64   `json:"cost"`
+}
+
+// RecordFuelEntry records a fuel purchase. The odometer or hour meter moves
+// forward with it, and fills that look like theft or a leak are flagged and
+// reported.
+func (s *EquipmentServiceImpl) RecordFuelEntry(ctx context.Context, equipmentID uuid.UUID, req *FuelEntryRequest) (*domain.FuelEntry, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if err := ValidateFuelEntryRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	equipment, err := s.getEquipment(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entry := NewFuelEntry(tenantID, equipmentID, req, now)
+	entry.RecordedBy = GetUserIDFromContext(ctx)
+
+	if entry.Meter != nil {
+		previous, err := s.fuelRepo.GetPreviousFuelEntry(ctx, tenantID, equipmentID, *entry.Meter, entry.FueledAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get previous fuel entry: %w", err)
+		}
+		if err := ResolveFuelEfficiency(entry, previous); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
+	history, err := s.fuelRepo.ListFuelEntries(ctx, tenantID, &FuelEntryFilter{
+		EquipmentIDs: []uuid.UUID{equipmentID},
+		EndDate:      &entry.FueledAt,
+		Limit:        fuelBaselineSampleSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fuel history: %w", err)
+	}
+	entry.AnomalyFlags = DetectFuelAnomalies(entry, BuildFuelBaseline(history, entry.Meter))
+
+	if err := s.fuelRepo.CreateFuelEntry(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to record fuel entry: %w", err)
+	}
+
+	s.recordFuelMeterReadings(ctx, entry)
+
+	s.logEquipmentAction(ctx, "equipment.fuel_entry", equipmentID, nil, map[string]interface{}{
+		"fuel_entry_id": entry.ID,
+		"gallons":       entry.Gallons,
+		"total_cost":    entry.TotalCost,
+		"anomaly_flags": entry.AnomalyFlags,
+	})
+
+	if len(entry.AnomalyFlags) > 0 {
+		s.notifyFuelAnomaly(ctx, equipment, entry)
+	}
+
+	return entry, nil
+}
+
+// ListFuelEntries lists an equipment's fuel entries between the optional dates
+func (s *EquipmentServiceImpl) ListFuelEntries(ctx context.Context, equipmentID uuid.UUID, startDate, endDate *time.Time) ([]*domain.FuelEntry, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if _, err := s.getEquipment(ctx, tenantID, equipmentID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.fuelRepo.ListFuelEntries(ctx, tenantID, &FuelEntryFilter{
+		EquipmentIDs: []uuid.UUID{equipmentID},
+		StartDate:    startDate,
+		EndDate:      endDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fuel entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// ListFuelAnomalies lists the tenant's flagged fuel entries between the optional dates
+func (s *EquipmentServiceImpl) ListFuelAnomalies(ctx context.Context, startDate, endDate *time.Time) ([]*domain.FuelEntry, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	entries, err := s.fuelRepo.ListFuelEntries(ctx, tenantID, &FuelEntryFilter{
+		StartDate:     startDate,
+		EndDate:       endDate,
+		AnomaliesOnly: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fuel anomalies: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetFuelEfficiencyReport trends an equipment's fuel efficiency between the
+// dates, over the last year by default
+func (s *EquipmentServiceImpl) GetFuelEfficiencyReport(ctx context.Context, equipmentID uuid.UUID, startDate, endDate time.Time) (*FuelEfficiencyReport, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	equipment, err := s.getEquipment(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if endDate.IsZero() {
+		endDate = time.Now()
+	}
+	if startDate.IsZero() {
+		startDate = endDate.AddDate(-1, 0, 0)
+	}
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("validation failed: end date is before start date")
+	}
+
+	entries, err := s.fuelRepo.ListFuelEntries(ctx, tenantID, &FuelEntryFilter{
+		EquipmentIDs: []uuid.UUID{equipmentID},
+		StartDate:    &startDate,
+		EndDate:      &endDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fuel entries: %w", err)
+	}
+
+	report := BuildFuelEfficiencyReport(entries)
+	report.EquipmentID = equipment.ID
+	report.EquipmentName = equipment.Name
+	report.Period = TimeRange{Start: startDate, End: endDate}
+
+	return report, nil
+}
+
+// ValidateFuelEntryRequest checks a fuel purchase
+func ValidateFuelEntryRequest(req *FuelEntryRequest) error {
+	if !containsString(domain.FuelTypes, req.FuelType) {
+		return fmt.Errorf("unknown fuel type %q", req.FuelType)
+	}
+	if req.Gallons <= 0 {
+		return fmt.Errorf("gallons must be positive")
+	}
+	if req.PricePerGallon < 0 {
+		return fmt.Errorf("price per gallon cannot be negative")
+	}
+	if req.TotalCost != nil && *req.TotalCost < 0 {
+		return fmt.Errorf("total cost cannot be negative")
+	}
+	if req.Odometer != nil && *req.Odometer < 0 {
+		return fmt.Errorf("odometer cannot be negative")
+	}
+	if req.EngineHours != nil && *req.EngineHours < 0 {
+		return fmt.Errorf("engine hours cannot be negative")
+	}
+	return nil
+}
+
+// NewFuelEntry builds a fuel entry from a request. Efficiency is tracked on the
+// odometer when one is given, otherwise on the engine hours.
+func NewFuelEntry(tenantID, equipmentID uuid.UUID, req *FuelEntryRequest, now time.Time) *domain.FuelEntry {
+	fueledAt := now
+	if req.FueledAt != nil {
+		fueledAt = *req.FueledAt
+	}
+
+	totalCost := roundCents(req.Gallons * req.PricePerGallon)
+	if req.TotalCost != nil {
+		totalCost = roundCents(*req.TotalCost)
+	}
+
+	entry := &domain.FuelEntry{
+		ID:             uuid.New(),
+		TenantID:       tenantID,
+		EquipmentID:    equipmentID,
+		FueledAt:       fueledAt,
+		FuelType:       req.FuelType,
+		Gallons:        req.Gallons,
+		PricePerGallon: req.PricePerGallon,
+		TotalCost:      totalCost,
+		Odometer:       req.Odometer,
+		EngineHours:    req.EngineHours,
+		AnomalyFlags:   []string{},
+		Vendor:         req.Vendor,
+		Notes:          req.Notes,
+		CreatedAt:      now,
+	}
+
+	switch {
+	case req.Odometer != nil:
+		meter := domain.EquipmentMeterMiles
+		entry.Meter = &meter
+	case req.EngineHours != nil:
+		meter := domain.EquipmentMeterHours
+		entry.Meter = &meter
+	}
+
+	return entry
+}
+
+// ResolveFuelEfficiency works out the miles driven or hours run since the
+// previous fill and the fuel efficiency over them. Each fill replaces the fuel
+// burned since the last one, so its gallons are set against that usage. The
+// first fill on a meter has no usage yet.
+func ResolveFuelEfficiency(entry, previous *domain.FuelEntry) error {
+	if entry.Meter == nil || previous == nil {
+		return nil
+	}
+
+	reading, last := fuelEntryReading(entry), fuelEntryReading(previous)
+	if reading == nil || last == nil {
+		return nil
+	}
+	if *reading < *last {
+		return fmt.Errorf("%s reading %.1f is below the previous fill's reading of %.1f", *entry.Meter, *reading, *last)
+	}
+
+	usage := roundMeasurement(*reading - *last)
+	entry.Usage = &usage
+	if efficiency, ok := fuelEfficiency(*entry.Meter, usage, entry.Gallons); ok {
+		entry.Efficiency = &efficiency
+	}
+	return nil
+}
+
+// BuildFuelBaseline takes the medians of the fills' efficiency on the meter and
+// their volume
+func BuildFuelBaseline(history []*domain.FuelEntry, meter *string) *FuelBaseline {
+	baseline := &FuelBaseline{}
+	if meter != nil {
+		baseline.Meter = *meter
+	}
+
+	var efficiencies, gallons []float64
+	for _, entry := range history {
+		gallons = append(gallons, entry.Gallons)
+		if meter != nil && entry.Meter != nil && *entry.Meter == *meter && entry.Efficiency != nil {
+			efficiencies = append(efficiencies, *entry.Efficiency)
+		}
+	}
+
+	baseline.Samples = len(gallons)
+	baseline.Gallons = medianValue(gallons)
+	if len(efficiencies) >= fuelBaselineMinSamples {
+		baseline.Efficiency = medianValue(efficiencies)
+	}
+	return baseline
+}
+
+// DetectFuelAnomalies flags a fill that burned notably more fuel per mile or
+// hour than the baseline, that took far more fuel than usual, or that came
+// without the meter having moved since the previous fill
+func DetectFuelAnomalies(entry *domain.FuelEntry, baseline *FuelBaseline) []string {
+	flags := []string{}
+
+	if entry.Usage != nil && *entry.Usage == 0 {
+		flags = append(flags, domain.FuelAnomalyNoUsage)
+	} else if entry.Efficiency != nil && baseline.Efficiency > 0 && entry.Meter != nil {
+		switch *entry.Meter {
+		case domain.EquipmentMeterMiles:
+			if *entry.Efficiency < baseline.Efficiency*(1-fuelLowEfficiencyTolerance) {
+				flags = append(flags, domain.FuelAnomalyLowEfficiency)
+			}
+		case domain.EquipmentMeterHours:
+			if *entry.Efficiency > baseline.Efficiency*(1+fuelLowEfficiencyTolerance) {
+				flags = append(flags, domain.FuelAnomalyLowEfficiency)
+			}
+		}
+	}
+
+	if baseline.Samples >= fuelBaselineMinSamples && baseline.Gallons > 0 && entry.Gallons > baseline.Gallons*fuelUnusualVolumeFactor {
+		flags = append(flags, domain.FuelAnomalyUnusualVolume)
+	}
+
+	return flags
+}
+
+// BuildFuelEfficiencyReport totals the fuel entries and trends their efficiency
+// by month. The report follows the odometer if any fill has one, otherwise the
+// hour meter.
+func BuildFuelEfficiencyReport(entries []*domain.FuelEntry) *FuelEfficiencyReport {
+	report := &FuelEfficiencyReport{
+		Trend:     []FuelTrendPoint{},
+		Anomalies: []*domain.FuelEntry{},
+	}
+
+	for _, entry := range entries {
+		if entry.Meter == nil {
+			continue
+		}
+		if *entry.Meter == domain.EquipmentMeterMiles || report.Meter == "" {
+			report.Meter = *entry.Meter
+		}
+	}
+	switch report.Meter {
+	case domain.EquipmentMeterMiles:
+		report.Unit = FuelEfficiencyMPG
+	case domain.EquipmentMeterHours:
+		report.Unit = FuelEfficiencyGPH
+	}
+
+	sorted := make([]*domain.FuelEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].FueledAt.Before(sorted[j].FueledAt) })
+
+	// Efficiency only counts fills with usage on the report's meter, so a first
+	// fill does not skew it
+	var measuredGallons, measuredCost float64
+	months := make(map[time.Time]*FuelTrendPoint)
+	monthGallons := make(map[time.Time]float64)
+	for _, entry := range sorted {
+		report.Gallons += entry.Gallons
+		report.Cost += entry.TotalCost
+		if len(entry.AnomalyFlags) > 0 {
+			report.Anomalies = append(report.Anomalies, entry)
+		}
+
+		month := time.Date(entry.FueledAt.Year(), entry.FueledAt.Month(), 1, 0, 0, 0, 0, entry.FueledAt.Location())
+		point, ok := months[month]
+		if !ok {
+			point = &FuelTrendPoint{Month: month}
+			months[month] = point
+			report.Trend = append(report.Trend, FuelTrendPoint{Month: month})
+		}
+		point.Gallons += entry.Gallons
+		point.Cost += entry.TotalCost
+
+		if entry.Meter == nil || *entry.Meter != report.Meter || entry.Usage == nil {
+			continue
+		}
+		report.Usage += *entry.Usage
+		measuredGallons += entry.Gallons
+		measuredCost += entry.TotalCost
+		point.Usage += *entry.Usage
+		monthGallons[month] += entry.Gallons
+	}
+
+	for i := range report.Trend {
+		point := months[report.Trend[i].Month]
+		point.Gallons = roundMeasurement(point.Gallons)
+		point.Cost = roundCents(point.Cost)
+		point.Usage = roundMeasurement(point.Usage)
+		if efficiency, ok := fuelEfficiency(report.Meter, point.Usage, monthGallons[point.Month]); ok {
+			point.Efficiency = &efficiency
+		}
+		report.Trend[i] = *point
+	}
+
+	report.Gallons = roundMeasurement(report.Gallons)
+	report.Cost = roundCents(report.Cost)
+	report.Usage = roundMeasurement(report.Usage)
+	if efficiency, ok := fuelEfficiency(report.Meter, report.Usage, measuredGallons); ok {
+		report.Efficiency = &efficiency
+	}
+	if report.Usage > 0 {
+		costPerUnit := roundCents(measuredCost / report.Usage)
+		report.CostPerUnit = &costPerUnit
+	}
+
+	// Compare the latest month with efficiency against the period
+	if report.Efficiency != nil && *report.Efficiency > 0 {
+		for i := len(report.Trend) - 1; i >= 0; i-- {
+			latest := report.Trend[i].Efficiency
+			if latest == nil {
+				continue
+			}
+			change := (*latest - *report.Efficiency) / *report.Efficiency
+			changePercent := math.Round(change*1000) / 10
+			report.ChangePercent = &changePercent
+			if report.Unit == FuelEfficiencyMPG {
+				report.Deteriorating = change < -fuelTrendTolerance
+			} else {
+				report.Deteriorating = change > fuelTrendTolerance
+			}
+			break
+		}
+	}
+
+	return report
+}
+
+// CalculateFuelRates prices a mile or hour of each equipment's use from its
+// fuel entries. Equipment fueled by the odometer is priced per mile, so route
+// distance carries its fuel cost rather than its engine hours.
+func CalculateFuelRates(entries []*domain.FuelEntry) []FuelRate {
+	type totals struct{ cost, usage float64 }
+	byEquipment := make(map[uuid.UUID]map[string]*totals)
+	var order []uuid.UUID
+	for _, entry := range entries {
+		if entry.Meter == nil || entry.Usage == nil || *entry.Usage <= 0 {
+			continue
+		}
+		meters, ok := byEquipment[entry.EquipmentID]
+		if !ok {
+			meters = make(map[string]*totals)
+			byEquipment[entry.EquipmentID] = meters
+			order = append(order, entry.EquipmentID)
+		}
+		t, ok := meters[*entry.Meter]
+		if !ok {
+			t = &totals{}
+			meters[*entry.Meter] = t
+		}
+		t.cost += entry.TotalCost
+		t.usage += *entry.Usage
+	}
+
+	rates := make([]FuelRate, 0, len(order))
+	for _, equipmentID := range order {
+		meters := byEquipment[equipmentID]
+		meter := domain.EquipmentMeterMiles
+		t, ok := meters[meter]
+		if !ok {
+			meter = domain.EquipmentMeterHours
+			t = meters[meter]
+		}
+		rates = append(rates, FuelRate{
+			EquipmentID: equipmentID,
+			Meter:       meter,
+			CostPerUnit: math.Round(t.cost/t.usage*10000) / 10000,
+		})
+	}
+	return rates
+}
+
+// AllocateJobFuel charges jobs for fuel by the miles driven or hours run on
+// them, at each equipment's fuel rate. Readings on a meter the equipment is not
+// priced on are skipped.
+func AllocateJobFuel(rates []FuelRate, readings []*domain.EquipmentMeterReading) []JobFuelAllocation {
+	byEquipment := make(map[uuid.UUID]FuelRate, len(rates))
+	for _, rate := range rates {
+		byEquipment[rate.EquipmentID] = rate
+	}
+
+	type key struct {
+		jobID       uuid.UUID
+		equipmentID uuid.UUID
+	}
+	allocations := []JobFuelAllocation{}
+	index := make(map[key]int)
+	for _, reading := range readings {
+		rate, ok := byEquipment[reading.EquipmentID]
+		if !ok || reading.JobID == nil || reading.Meter != rate.Meter || reading.Usage <= 0 {
+			continue
+		}
+		k := key{jobID: *reading.JobID, equipmentID: reading.EquipmentID}
+		i, ok := index[k]
+		if !ok {
+			i = len(allocations)
+			index[k] = i
+			allocations = append(allocations, JobFuelAllocation{
+				JobID:       *reading.JobID,
+				EquipmentID: reading.EquipmentID,
+				Meter:       rate.Meter,
+				CostPerUnit: rate.CostPerUnit,
+			})
+		}
+		allocations[i].Usage = roundMeasurement(allocations[i].Usage + reading.Usage)
+	}
+
+	for i := range allocations {
+		allocations[i].Cost = roundCents(allocations[i].Usage * allocations[i].CostPerUnit)
+	}
+	return allocations
+}
+
+// SummarizeFuelEfficiency totals the fleet's fuel entries by equipment, with
+// the fuel cost charged to jobs
+func SummarizeFuelEfficiency(entries []*domain.FuelEntry, allocations []JobFuelAllocation) *FuelEfficiencyMetrics {
+	metrics := &FuelEfficiencyMetrics{
+		ByEquipment: []*EquipmentFuelEfficiency{},
+		ByJob:       make(map[uuid.UUID]float64),
+	}
+
+	byEquipment := make(map[uuid.UUID][]*domain.FuelEntry)
+	var order []uuid.UUID
+	for _, entry := range entries {
+		if _, ok := byEquipment[entry.EquipmentID]; !ok {
+			order = append(order, entry.EquipmentID)
+		}
+		byEquipment[entry.EquipmentID] = append(byEquipment[entry.EquipmentID], entry)
+	}
+
+	for _, equipmentID := range order {
+		report := BuildFuelEfficiencyReport(byEquipment[equipmentID])
+		metrics.Gallons += report.Gallons
+		metrics.Cost += report.Cost
+		metrics.AnomalyCount += len(report.Anomalies)
+		metrics.ByEquipment = append(metrics.ByEquipment, &EquipmentFuelEfficiency{
+			EquipmentID:   equipmentID,
+			Meter:         report.Meter,
+			Unit:          report.Unit,
+			Gallons:       report.Gallons,
+			Cost:          report.Cost,
+			Usage:         report.Usage,
+			Efficiency:    report.Efficiency,
+			ChangePercent: report.ChangePercent,
+			Deteriorating: report.Deteriorating,
+			Anomalies:     len(report.Anomalies),
+		})
+	}
+
+	for _, allocation := range allocations {
+		metrics.ByJob[allocation.JobID] = roundCents(metrics.ByJob[allocation.JobID] + allocation.Cost)
+		metrics.JobFuelCost += allocation.Cost
+	}
+
+	metrics.Gallons = roundMeasurement(metrics.Gallons)
+	metrics.Cost = roundCents(metrics.Cost)
+	metrics.JobFuelCost = roundCents(metrics.JobFuelCost)
+	metrics.JobsCosted = len(metrics.ByJob)
+	if metrics.JobsCosted > 0 {
+		metrics.AverageJobFuelCost = roundCents(metrics.JobFuelCost / float64(metrics.JobsCosted))
+	}
+
+	return metrics
+}
+
+// allocateJobFuel charges fuel to the jobs, or to every job with readings in the
+// window when jobIDs is empty. Fuel rates come from the fills in the rate window
+// before asOf, and from any earlier part of the window.
+func allocateJobFuel(ctx context.Context, fuelRepo FuelRepository, meterRepo EquipmentMeterRepository, tenantID uuid.UUID, jobIDs []uuid.UUID, startDate, endDate *time.Time, asOf time.Time) ([]JobFuelAllocation, error) {
+	readings, err := meterRepo.ListJobReadings(ctx, tenantID, jobIDs, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job meter readings: %w", err)
+	}
+	if len(readings) == 0 {
+		return []JobFuelAllocation{}, nil
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	var equipmentIDs []uuid.UUID
+	for _, reading := range readings {
+		if !seen[reading.EquipmentID] {
+			seen[reading.EquipmentID] = true
+			equipmentIDs = append(equipmentIDs, reading.EquipmentID)
+		}
+	}
+
+	rateStart := asOf.AddDate(0, 0, -fuelRateWindowDays)
+	if startDate != nil && startDate.Before(rateStart) {
+		rateStart = *startDate
+	}
+	entries, err := fuelRepo.ListFuelEntries(ctx, tenantID, &FuelEntryFilter{
+		EquipmentIDs: equipmentIDs,
+		StartDate:    &rateStart,
+		EndDate:      &asOf,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fuel entries: %w", err)
+	}
+
+	return AllocateJobFuel(CalculateFuelRates(entries), readings), nil
+}
+
+// loggedFuelCost totals the fuel bought for the equipment between the dates,
+// reporting whether any was logged
+func (s *EquipmentServiceImpl) loggedFuelCost(ctx context.Context, tenantID, equipmentID uuid.UUID, startDate, endDate time.Time) (float64, bool, error) {
+	entries, err := s.fuelRepo.ListFuelEntries(ctx, tenantID, &FuelEntryFilter{
+		EquipmentIDs: []uuid.UUID{equipmentID},
+		StartDate:    &startDate,
+		EndDate:      &endDate,
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to list fuel entries: %w", err)
+	}
+
+	var cost float64
+	for _, entry := range entries {
+		cost += entry.TotalCost
+	}
+	return roundCents(cost), len(entries) > 0, nil
+}
+
+// recordFuelMeterReadings moves the equipment's meters forward to the readings
+// taken at the pump. A reading behind the meter is left off rather than failing
+// the fuel entry.
+func (s *EquipmentServiceImpl) recordFuelMeterReadings(ctx context.Context, entry *domain.FuelEntry) {
+	readings := map[string]*float64{
+		domain.EquipmentMeterMiles: entry.Odometer,
+		domain.EquipmentMeterHours: entry.EngineHours,
+	}
+	for _, meter := range []string{domain.EquipmentMeterMiles, domain.EquipmentMeterHours} {
+		if readings[meter] == nil {
+			continue
+		}
+		req := &MeterReadingRequest{Meter: meter, Reading: readings[meter], RecordedAt: &entry.FueledAt}
+		if _, err := recordMeterReading(ctx, s.meterRepo, entry.TenantID, entry.EquipmentID, req, domain.MeterReadingSourceFuel, nil); err != nil {
+			s.logger.Printf("Failed to record %s reading for fuel entry %s: %v", meter, entry.ID, err)
+		}
+	}
+}
+
+func (s *EquipmentServiceImpl) notifyFuelAnomaly(ctx context.Context, equipment *domain.Equipment, entry *domain.FuelEntry) {
+	if err := s.notificationService.SendNotification(ctx, &NotificationRequest{
+		Type:    "equipment.fuel_anomaly",
+		Title:   "Unusual Fuel Entry",
+		Message: fmt.Sprintf("A %.1f gallon fill for %s was flagged for review", entry.Gallons, equipment.Name),
+		Data: map[string]interface{}{
+			"fuel_entry_id":  entry.ID,
+			"equipment_id":   equipment.ID,
+			"equipment_name": equipment.Name,
+			"anomaly_flags":  entry.AnomalyFlags,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to send fuel anomaly notification: %v", err)
+	}
+}
+
+func fuelEntryReading(entry *domain.FuelEntry) *float64 {
+	if entry.Meter == nil {
+		return nil
+	}
+	if *entry.Meter == domain.EquipmentMeterMiles {
+		return entry.Odometer
+	}
+	return entry.EngineHours
+}
+
+// fuelEfficiency is miles per gallon on the miles meter and gallons per hour on
+// the hours meter
+func fuelEfficiency(meter string, usage, gallons float64) (float64, bool) {
+	if usage <= 0 || gallons <= 0 {
+		return 0, false
+	}
+	if meter == domain.EquipmentMeterMiles {
+		return math.Round(usage/gallons*100) / 100, true
+	}
+	return math.Round(gallons/usage*100) / 100, true
+}
+
+func medianValue(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
@@ -0,0 +1,645 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// EquipmentMeterRepository defines data access for equipment meter readings and
+// usage-based maintenance triggers
+type EquipmentMeterRepository interface {
+	CreateReading(ctx context.Context, reading *domain.EquipmentMeterReading) error
+	GetLatestReading(ctx context.Context, tenantID, equipmentID uuid.UUID, meter string) (*domain.EquipmentMeterReading, error)
+	ListReadings(ctx context.Context, tenantID, equipmentID uuid.UUID, meter string, limit int) ([]*domain.EquipmentMeterReading, error)
+
+	// GetCurrentReadings returns the latest reading on each of the equipment's meters
+	GetCurrentReadings(ctx context.Context, tenantID, equipmentID uuid.UUID) (map[string]float64, error)
+
+	// AverageJobUsage averages the usage of the most recent job readings on a meter
+	AverageJobUsage(ctx context.Context, tenantID, equipmentID uuid.UUID, meter string, sampleSize int) (float64, error)
+
+	// SumUsage totals the usage recorded on a meter between start and end
+	SumUsage(ctx context.Context, tenantID, equipmentID uuid.UUID, meter string, start, end time.Time) (float64, error)
+
+	// ListJobReadings lists the readings recorded against jobs between the
+	// optional dates, for every job when jobIDs is empty
+	ListJobReadings(ctx context.Context, tenantID uuid.UUID, jobIDs []uuid.UUID, start, end *time.Time) ([]*domain.EquipmentMeterReading, error)
+
+	ListUsageTriggers(ctx context.Context, tenantID uuid.UUID, equipmentID *uuid.UUID) ([]*domain.MaintenanceUsageTrigger, error)
+	ReplaceUsageTriggers(ctx context.Context, tenantID, equipmentID uuid.UUID, triggers []*domain.MaintenanceUsageTrigger) error
+	UpdateUsageTrigger(ctx context.Context, trigger *domain.MaintenanceUsageTrigger) error
+}
+
+// DefaultMaintenanceForecastDays is how far ahead scheduled jobs are projected
+// when checking for maintenance coming due
+const DefaultMaintenanceForecastDays = 14
+
+// jobUsageSampleSize is how many recent job readings are averaged to estimate
+// the usage of an upcoming job
+const jobUsageSampleSize = 10
+
+// What brings maintenance due first
+const (
+	MaintenanceDueByDate  = "date"
+	MaintenanceDueByUsage = "usage"
+)
+
+// MeterReadingRequest records a meter either as a new cumulative reading or as
+// usage since the last reading
+type MeterReadingRequest struct {
+	Meter      string     `json:"meter"`
+	Reading    *float64   `json:"reading,omitempty"`
+	Usage      *float64   `json:"usage,omitempty"`
+	RecordedAt *time.Time `json:"recorded_at,omitempty"`
+	Notes      *string    `json:"notes,omitempty"`
+}
+
+// JobEquipmentUsage is a meter reading reported for equipment used on a job
+type JobEquipmentUsage struct {
+	EquipmentID uuid.UUID `json:"equipment_id"`
+	MeterReadingRequest
+}
+
+// UsageTriggerRequest schedules maintenance every Interval units on a meter. The
+// interval is counted from LastServiceReading, or the current reading if unset.
+type UsageTriggerRequest struct {
+	Meter              string   `json:"meter"`
+	Interval           float64  `json:"interval"`
+	LastServiceReading *float64 `json:"last_service_reading,omitempty"`
+}
+
+// ProjectedJobUsage is the usage an upcoming job is expected to put on a meter
+type ProjectedJobUsage struct {
+	JobID uuid.UUID `json:"job_id"`
+	Date  time.Time `json:"date"`
+	Usage float64   `json:"usage"`
+}
+
+// UsageProjection projects a usage trigger's meter across upcoming jobs
+type UsageProjection struct {
+	Meter            string     `json:"meter"`
+	Interval         float64    `json:"interval"`
+	CurrentReading   float64    `json:"current_reading"`
+	DueReading       float64    `json:"due_reading"`
+	RemainingUsage   float64    `json:"remaining_usage"`
+	ProjectedReading float64    `json:"projected_reading"` // after the last upcoming job
+	ProjectedDueDate *time.Time `json:"projected_due_date,omitempty"`
+	ReachedOnJobID   *uuid.UUID `json:"reached_on_job_id,omitempty"` // the job expected to pass the due reading
+	Overdue          bool       `json:"overdue"`
+}
+
+// MaintenanceForecast is when a piece of equipment next needs maintenance, by
+// whichever of its calendar schedule or usage triggers comes first
+type MaintenanceForecast struct {
+	Equipment        *domain.Equipment `json:"equipment"`
+	Usage            []UsageProjection `json:"usage"`
+	DueDate          *time.Time        `json:"due_date,omitempty"`
+	DueBy            string            `json:"due_by,omitempty"`
+	Overdue          bool              `json:"overdue"`
+	DueWithinHorizon bool              `json:"due_within_horizon"`
+}
+
+// RecordMeterReading records a manual meter reading
+func (s *EquipmentServiceImpl) RecordMeterReading(ctx context.Context, equipmentID uuid.UUID, req *MeterReadingRequest) (*domain.EquipmentMeterReading, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if err := ValidateMeterReadingRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if _, err := s.getEquipment(ctx, tenantID, equipmentID); err != nil {
+		return nil, err
+	}
+
+	reading, err := recordMeterReading(ctx, s.meterRepo, tenantID, equipmentID, req, domain.MeterReadingSourceManual, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logEquipmentAction(ctx, "equipment.meter_reading", equipmentID, nil, map[string]interface{}{
+		"meter":   reading.Meter,
+		"reading": reading.Reading,
+		"usage":   reading.Usage,
+	})
+
+	return reading, nil
+}
+
+// GetMeterReadings lists an equipment's most recent readings, optionally on one meter
+func (s *EquipmentServiceImpl) GetMeterReadings(ctx context.Context, equipmentID uuid.UUID, meter string, limit int) ([]*domain.EquipmentMeterReading, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if meter != "" && !isEquipmentMeter(meter) {
+		return nil, fmt.Errorf("validation failed: unknown meter %q", meter)
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	if _, err := s.getEquipment(ctx, tenantID, equipmentID); err != nil {
+		return nil, err
+	}
+
+	readings, err := s.meterRepo.ListReadings(ctx, tenantID, equipmentID, meter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list meter readings: %w", err)
+	}
+
+	return readings, nil
+}
+
+// GetUsageTriggers lists an equipment's usage-based maintenance triggers
+func (s *EquipmentServiceImpl) GetUsageTriggers(ctx context.Context, equipmentID uuid.UUID) ([]*domain.MaintenanceUsageTrigger, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if _, err := s.getEquipment(ctx, tenantID, equipmentID); err != nil {
+		return nil, err
+	}
+
+	triggers, err := s.meterRepo.ListUsageTriggers(ctx, tenantID, &equipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage triggers: %w", err)
+	}
+
+	return triggers, nil
+}
+
+// SetUsageTriggers replaces an equipment's usage-based maintenance triggers
+func (s *EquipmentServiceImpl) SetUsageTriggers(ctx context.Context, equipmentID uuid.UUID, reqs []*UsageTriggerRequest) ([]*domain.MaintenanceUsageTrigger, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if err := ValidateUsageTriggerRequests(reqs); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if _, err := s.getEquipment(ctx, tenantID, equipmentID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.meterRepo.ListUsageTriggers(ctx, tenantID, &equipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage triggers: %w", err)
+	}
+
+	current, err := s.meterRepo.GetCurrentReadings(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current meter readings: %w", err)
+	}
+
+	now := time.Now()
+	triggers := make([]*domain.MaintenanceUsageTrigger, 0, len(reqs))
+	for _, req := range reqs {
+		lastService := current[req.Meter]
+		if req.LastServiceReading != nil {
+			lastService = *req.LastServiceReading
+		}
+		triggers = append(triggers, &domain.MaintenanceUsageTrigger{
+			ID:                 uuid.New(),
+			TenantID:           tenantID,
+			EquipmentID:        equipmentID,
+			Meter:              req.Meter,
+			Interval:           req.Interval,
+			LastServiceReading: lastService,
+			NextDueReading:     roundMeasurement(lastService + req.Interval),
+			CreatedAt:          now,
+			UpdatedAt:          now,
+		})
+	}
+
+	if err := s.meterRepo.ReplaceUsageTriggers(ctx, tenantID, equipmentID, triggers); err != nil {
+		return nil, fmt.Errorf("failed to save usage triggers: %w", err)
+	}
+
+	s.logEquipmentAction(ctx, "equipment.usage_triggers_set", equipmentID,
+		usageTriggerAuditValues(existing), usageTriggerAuditValues(triggers))
+
+	return triggers, nil
+}
+
+// GetMaintenanceForecast projects when usage-tracked equipment, and equipment
+// already due by date, next needs maintenance. Usage is projected from the jobs
+// scheduled over the next horizonDays days.
+func (s *EquipmentServiceImpl) GetMaintenanceForecast(ctx context.Context, horizonDays int) ([]*MaintenanceForecast, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if horizonDays <= 0 {
+		horizonDays = DefaultMaintenanceForecastDays
+	}
+	now := time.Now()
+	horizonEnd := now.AddDate(0, 0, horizonDays)
+
+	triggers, err := s.meterRepo.ListUsageTriggers(ctx, tenantID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage triggers: %w", err)
+	}
+
+	dateDue, err := s.equipmentRepo.GetMaintenanceDue(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get equipment with maintenance due: %w", err)
+	}
+
+	triggersByEquipment := make(map[uuid.UUID][]*domain.MaintenanceUsageTrigger)
+	equipmentIDs := make([]uuid.UUID, 0)
+	for _, trigger := range triggers {
+		if _, ok := triggersByEquipment[trigger.EquipmentID]; !ok {
+			equipmentIDs = append(equipmentIDs, trigger.EquipmentID)
+		}
+		triggersByEquipment[trigger.EquipmentID] = append(triggersByEquipment[trigger.EquipmentID], trigger)
+	}
+
+	equipment := make([]*domain.Equipment, 0, len(dateDue))
+	for _, eq := range dateDue {
+		if _, ok := triggersByEquipment[eq.ID]; !ok {
+			equipment = append(equipment, eq)
+		}
+	}
+	if len(equipmentIDs) > 0 {
+		tracked, err := s.equipmentRepo.GetByIDs(ctx, tenantID, equipmentIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get equipment: %w", err)
+		}
+		equipment = append(equipment, tracked...)
+	}
+
+	forecasts := make([]*MaintenanceForecast, 0, len(equipment))
+	for _, eq := range equipment {
+		projections, err := s.projectUsageTriggers(ctx, tenantID, eq.ID, triggersByEquipment[eq.ID], now, horizonEnd)
+		if err != nil {
+			return nil, err
+		}
+		forecasts = append(forecasts, ForecastMaintenance(eq, projections, now, horizonEnd))
+	}
+
+	sort.SliceStable(forecasts, func(i, j int) bool {
+		a, b := forecasts[i].DueDate, forecasts[j].DueDate
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.Before(*b)
+	})
+
+	return forecasts, nil
+}
+
+// ResolveMeterReading works out the cumulative reading and the usage since the
+// previous reading. Meters only count up, so a reading below the previous one
+// is rejected.
+func ResolveMeterReading(previous *domain.EquipmentMeterReading, req *MeterReadingRequest) (float64, float64, error) {
+	var last float64
+	if previous != nil {
+		last = previous.Reading
+	}
+
+	if req.Usage != nil {
+		return roundMeasurement(last + *req.Usage), roundMeasurement(*req.Usage), nil
+	}
+
+	reading := *req.Reading
+	if reading < last {
+		return 0, 0, fmt.Errorf("%s reading %.2f is below the last reading of %.2f", req.Meter, reading, last)
+	}
+	if previous == nil {
+		return roundMeasurement(reading), 0, nil
+	}
+	return roundMeasurement(reading), roundMeasurement(reading - last), nil
+}
+
+// ValidateMeterReadingRequest checks the meter and that exactly one of a
+// reading or usage is given
+func ValidateMeterReadingRequest(req *MeterReadingRequest) error {
+	if !isEquipmentMeter(req.Meter) {
+		return fmt.Errorf("unknown meter %q", req.Meter)
+	}
+	if (req.Reading == nil) == (req.Usage == nil) {
+		return fmt.Errorf("either a reading or usage is required")
+	}
+	if req.Reading != nil && *req.Reading < 0 {
+		return fmt.Errorf("reading cannot be negative")
+	}
+	if req.Usage != nil && *req.Usage < 0 {
+		return fmt.Errorf("usage cannot be negative")
+	}
+	return nil
+}
+
+// ValidateUsageTriggerRequests checks each trigger and that no meter is repeated
+func ValidateUsageTriggerRequests(reqs []*UsageTriggerRequest) error {
+	seen := make(map[string]bool)
+	for i, req := range reqs {
+		if !isEquipmentMeter(req.Meter) {
+			return fmt.Errorf("trigger %d: unknown meter %q", i+1, req.Meter)
+		}
+		if seen[req.Meter] {
+			return fmt.Errorf("trigger %d: %s already has a trigger", i+1, req.Meter)
+		}
+		seen[req.Meter] = true
+		if req.Interval <= 0 {
+			return fmt.Errorf("trigger %d: interval must be positive", i+1)
+		}
+		if req.LastServiceReading != nil && *req.LastServiceReading < 0 {
+			return fmt.Errorf("trigger %d: last service reading cannot be negative", i+1)
+		}
+	}
+	return nil
+}
+
+// EstimateJobUsage estimates the usage an upcoming job will put on a meter. The
+// hours meter uses the job's estimated duration when it has one; otherwise the
+// equipment's average job usage on the meter is used.
+func EstimateJobUsage(meter string, job *domain.EnhancedJob, averageUsage float64) float64 {
+	if meter == domain.EquipmentMeterHours && job.EstimatedDuration != nil && *job.EstimatedDuration > 0 {
+		return roundMeasurement(float64(*job.EstimatedDuration) / 60)
+	}
+	return averageUsage
+}
+
+// JobUsageHours returns the hours between a job's actual start and end, or zero
+// if either is missing
+func JobUsageHours(job *domain.EnhancedJob) float64 {
+	if job.ActualStartTime == nil || job.ActualEndTime == nil || !job.ActualEndTime.After(*job.ActualStartTime) {
+		return 0
+	}
+	return roundMeasurement(job.ActualEndTime.Sub(*job.ActualStartTime).Hours())
+}
+
+// ProjectUsageTrigger runs a trigger's meter forward through upcoming jobs in
+// date order to find the job, and so the date, that takes it past the due reading
+func ProjectUsageTrigger(trigger *domain.MaintenanceUsageTrigger, currentReading float64, jobs []ProjectedJobUsage, now time.Time) UsageProjection {
+	projection := UsageProjection{
+		Meter:            trigger.Meter,
+		Interval:         trigger.Interval,
+		CurrentReading:   currentReading,
+		DueReading:       trigger.NextDueReading,
+		ProjectedReading: currentReading,
+	}
+
+	if currentReading >= trigger.NextDueReading {
+		projection.Overdue = true
+		projection.ProjectedDueDate = &now
+		return projection
+	}
+	projection.RemainingUsage = roundMeasurement(trigger.NextDueReading - currentReading)
+
+	upcoming := make([]ProjectedJobUsage, len(jobs))
+	copy(upcoming, jobs)
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].Date.Before(upcoming[j].Date)
+	})
+
+	for _, job := range upcoming {
+		projection.ProjectedReading += job.Usage
+		if projection.ProjectedDueDate == nil && projection.ProjectedReading >= trigger.NextDueReading {
+			date, jobID := job.Date, job.JobID
+			projection.ProjectedDueDate = &date
+			projection.ReachedOnJobID = &jobID
+		}
+	}
+	projection.ProjectedReading = roundMeasurement(projection.ProjectedReading)
+
+	return projection
+}
+
+// ForecastMaintenance picks whichever of the equipment's calendar date or usage
+// projections brings maintenance due first
+func ForecastMaintenance(equipment *domain.Equipment, projections []UsageProjection, now, horizonEnd time.Time) *MaintenanceForecast {
+	forecast := &MaintenanceForecast{
+		Equipment: equipment,
+		Usage:     projections,
+	}
+
+	if equipment.NextMaintenance != nil {
+		forecast.DueDate = equipment.NextMaintenance
+		forecast.DueBy = MaintenanceDueByDate
+		forecast.Overdue = equipment.NextMaintenance.Before(now)
+	}
+
+	for i := range projections {
+		projection := &projections[i]
+		if projection.Overdue {
+			forecast.Overdue = true
+		}
+		if projection.ProjectedDueDate == nil {
+			continue
+		}
+		if forecast.DueDate == nil || projection.ProjectedDueDate.Before(*forecast.DueDate) {
+			forecast.DueDate = projection.ProjectedDueDate
+			forecast.DueBy = MaintenanceDueByUsage
+		}
+	}
+
+	forecast.DueWithinHorizon = forecast.DueDate != nil && !forecast.DueDate.After(horizonEnd)
+	return forecast
+}
+
+// Helper functions
+
+// projectUsageTriggers projects each trigger across the equipment's upcoming jobs
+func (s *EquipmentServiceImpl) projectUsageTriggers(ctx context.Context, tenantID, equipmentID uuid.UUID, triggers []*domain.MaintenanceUsageTrigger, now, horizonEnd time.Time) ([]UsageProjection, error) {
+	if len(triggers) == 0 {
+		return nil, nil
+	}
+
+	current, err := s.meterRepo.GetCurrentReadings(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current meter readings: %w", err)
+	}
+
+	jobs, err := s.jobRepo.GetByEquipmentID(ctx, tenantID, equipmentID, now, horizonEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upcoming jobs: %w", err)
+	}
+
+	projections := make([]UsageProjection, 0, len(triggers))
+	for _, trigger := range triggers {
+		averageUsage, err := s.meterRepo.AverageJobUsage(ctx, tenantID, equipmentID, trigger.Meter, jobUsageSampleSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get average job usage: %w", err)
+		}
+
+		upcoming := make([]ProjectedJobUsage, 0, len(jobs))
+		for _, job := range jobs {
+			if job.ScheduledDate == nil || job.Status == domain.JobStatusCompleted || job.Status == domain.JobStatusCancelled {
+				continue
+			}
+			upcoming = append(upcoming, ProjectedJobUsage{
+				JobID: job.ID,
+				Date:  *job.ScheduledDate,
+				Usage: EstimateJobUsage(trigger.Meter, job, averageUsage),
+			})
+		}
+
+		projections = append(projections, ProjectUsageTrigger(trigger, current[trigger.Meter], upcoming, now))
+	}
+
+	return projections, nil
+}
+
+// resetUsageTriggers starts each usage trigger's next interval from the current
+// reading after maintenance is performed
+func (s *EquipmentServiceImpl) resetUsageTriggers(ctx context.Context, tenantID, equipmentID uuid.UUID) error {
+	triggers, err := s.meterRepo.ListUsageTriggers(ctx, tenantID, &equipmentID)
+	if err != nil {
+		return fmt.Errorf("failed to list usage triggers: %w", err)
+	}
+	if len(triggers) == 0 {
+		return nil
+	}
+
+	current, err := s.meterRepo.GetCurrentReadings(ctx, tenantID, equipmentID)
+	if err != nil {
+		return fmt.Errorf("failed to get current meter readings: %w", err)
+	}
+
+	for _, trigger := range triggers {
+		trigger.LastServiceReading = current[trigger.Meter]
+		trigger.NextDueReading = roundMeasurement(trigger.LastServiceReading + trigger.Interval)
+		trigger.UpdatedAt = time.Now()
+		if err := s.meterRepo.UpdateUsageTrigger(ctx, trigger); err != nil {
+			return fmt.Errorf("failed to update usage trigger: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *EquipmentServiceImpl) getEquipment(ctx context.Context, tenantID, equipmentID uuid.UUID) (*domain.Equipment, error) {
+	equipment, err := s.equipmentRepo.GetByID(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get equipment: %w", err)
+	}
+	if equipment == nil {
+		return nil, fmt.Errorf("equipment not found")
+	}
+	return equipment, nil
+}
+
+func (s *EquipmentServiceImpl) logEquipmentAction(ctx context.Context, action string, equipmentID uuid.UUID, oldValues, newValues map[string]interface{}) {
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       action,
+		ResourceType: "equipment",
+		ResourceID:   &equipmentID,
+		OldValues:    oldValues,
+		NewValues:    newValues,
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+}
+
+// recordJobEquipmentUsage stores the meter readings reported for equipment used
+// on a completed job. Equipment the job required but nobody reported on is
+// charged the job's actual hours, if it has an hours meter.
+func recordJobEquipmentUsage(ctx context.Context, meterRepo EquipmentMeterRepository, job *domain.EnhancedJob, usage []JobEquipmentUsage) error {
+	reported := make(map[uuid.UUID]bool)
+	for i := range usage {
+		entry := &usage[i]
+		if err := ValidateMeterReadingRequest(&entry.MeterReadingRequest); err != nil {
+			return fmt.Errorf("validation failed: equipment %s: %w", entry.EquipmentID, err)
+		}
+		if _, err := recordMeterReading(ctx, meterRepo, job.TenantID, entry.EquipmentID, &entry.MeterReadingRequest, domain.MeterReadingSourceJob, &job.ID); err != nil {
+			return err
+		}
+		reported[entry.EquipmentID] = true
+	}
+
+	hours := JobUsageHours(job)
+	if hours <= 0 {
+		return nil
+	}
+
+	for _, equipmentID := range job.RequiresEquipment {
+		if reported[equipmentID] {
+			continue
+		}
+		previous, err := meterRepo.GetLatestReading(ctx, job.TenantID, equipmentID, domain.EquipmentMeterHours)
+		if err != nil {
+			return fmt.Errorf("failed to get latest meter reading: %w", err)
+		}
+		if previous == nil {
+			continue
+		}
+		req := &MeterReadingRequest{Meter: domain.EquipmentMeterHours, Usage: &hours, RecordedAt: job.ActualEndTime}
+		if _, err := recordMeterReading(ctx, meterRepo, job.TenantID, equipmentID, req, domain.MeterReadingSourceJob, &job.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func recordMeterReading(ctx context.Context, meterRepo EquipmentMeterRepository, tenantID, equipmentID uuid.UUID, req *MeterReadingRequest, source string, jobID *uuid.UUID) (*domain.EquipmentMeterReading, error) {
+	previous, err := meterRepo.GetLatestReading(ctx, tenantID, equipmentID, req.Meter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest meter reading: %w", err)
+	}
+
+	value, usage, err := ResolveMeterReading(previous, req)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	now := time.Now()
+	recordedAt := now
+	if req.RecordedAt != nil {
+		recordedAt = *req.RecordedAt
+	}
+
+	reading := &domain.EquipmentMeterReading{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		EquipmentID: equipmentID,
+		Meter:       req.Meter,
+		Reading:     value,
+		Usage:       usage,
+		Source:      source,
+		JobID:       jobID,
+		RecordedBy:  GetUserIDFromContext(ctx),
+		RecordedAt:  recordedAt,
+		Notes:       req.Notes,
+		CreatedAt:   now,
+	}
+
+	if err := meterRepo.CreateReading(ctx, reading); err != nil {
+		return nil, fmt.Errorf("failed to record meter reading: %w", err)
+	}
+
+	return reading, nil
+}
+
+func isEquipmentMeter(meter string) bool {
+	for _, m := range domain.EquipmentMeters {
+		if m == meter {
+			return true
+		}
+	}
+	return false
+}
+
+func usageTriggerAuditValues(triggers []*domain.MaintenanceUsageTrigger) map[string]interface{} {
+	intervals := make(map[string]float64, len(triggers))
+	for _, trigger := range triggers {
+		intervals[trigger.Meter] = trigger.Interval
+	}
+	return map[string]interface{}{"usage_triggers": intervals}
+}
@@ -0,0 +1,448 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// EquipmentReservationRepository defines data access for equipment reservations.
+// Creating or replacing reservations fails if they overlap another reservation
+// for the same equipment.
+type EquipmentReservationRepository interface {
+	CreateReservation(ctx context.Context, reservation *domain.EquipmentReservation) error
+	GetReservation(ctx context.Context, tenantID, reservationID uuid.UUID) (*domain.EquipmentReservation, error)
+	DeleteReservation(ctx context.Context, tenantID, reservationID uuid.UUID) error
+
+	// ListReservations lists reservations overlapping the window, for all
+	// equipment when equipmentIDs is empty
+	ListReservations(ctx context.Context, tenantID uuid.UUID, equipmentIDs []uuid.UUID, start, end time.Time) ([]*domain.EquipmentReservation, error)
+	ListCrewReservations(ctx context.Context, tenantID, crewID uuid.UUID, start, end time.Time) ([]*domain.EquipmentReservation, error)
+
+	ListJobReservations(ctx context.Context, tenantID, jobID uuid.UUID) ([]*domain.EquipmentReservation, error)
+	ReplaceJobReservations(ctx context.Context, tenantID, jobID uuid.UUID, reservations []*domain.EquipmentReservation) error
+	DeleteJobReservations(ctx context.Context, tenantID, jobID uuid.UUID) error
+}
+
+// EquipmentReservationRequest blocks equipment out for a window outside of a job
+type EquipmentReservationRequest struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Notes    *string   `json:"notes,omitempty"`
+}
+
+// ReserveEquipment blocks equipment out for a window, such as a loan or a trip to the dealer
+func (s *EquipmentServiceImpl) ReserveEquipment(ctx context.Context, equipmentID uuid.UUID, req *EquipmentReservationRequest) (*domain.EquipmentReservation, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, fmt.Errorf("validation failed: reservation must end after it starts")
+	}
+
+	equipment, err := s.getEquipment(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.reservationRepo.ListReservations(ctx, tenantID, []uuid.UUID{equipmentID}, req.StartsAt, req.EndsAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check equipment reservations: %w", err)
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("%s is already reserved from %s to %s", equipment.Name,
+			existing[0].StartsAt.Format(time.RFC3339), existing[0].EndsAt.Format(time.RFC3339))
+	}
+
+	now := time.Now()
+	reservation := &domain.EquipmentReservation{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		EquipmentID: equipmentID,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		Notes:       req.Notes,
+		CreatedBy:   GetUserIDFromContext(ctx),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.reservationRepo.CreateReservation(ctx, reservation); err != nil {
+		return nil, fmt.Errorf("failed to reserve equipment: %w", err)
+	}
+
+	s.logEquipmentAction(ctx, "equipment.reserve", equipmentID, nil, map[string]interface{}{
+		"reservation_id": reservation.ID,
+		"starts_at":      reservation.StartsAt,
+		"ends_at":        reservation.EndsAt,
+	})
+
+	return reservation, nil
+}
+
+// GetEquipmentReservations lists an equipment's reservations overlapping the window
+func (s *EquipmentServiceImpl) GetEquipmentReservations(ctx context.Context, equipmentID uuid.UUID, start, end time.Time) ([]*domain.EquipmentReservation, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if _, err := s.getEquipment(ctx, tenantID, equipmentID); err != nil {
+		return nil, err
+	}
+
+	reservations, err := s.reservationRepo.ListReservations(ctx, tenantID, []uuid.UUID{equipmentID}, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list equipment reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// CancelEquipmentReservation removes a reservation made outside of a job. Job
+// reservations follow the job and are released by rescheduling or cancelling it.
+func (s *EquipmentServiceImpl) CancelEquipmentReservation(ctx context.Context, reservationID uuid.UUID) error {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("tenant ID not found in context")
+	}
+
+	reservation, err := s.reservationRepo.GetReservation(ctx, tenantID, reservationID)
+	if err != nil {
+		return fmt.Errorf("failed to get equipment reservation: %w", err)
+	}
+	if reservation == nil {
+		return fmt.Errorf("equipment reservation not found")
+	}
+	if reservation.JobID != nil {
+		return fmt.Errorf("validation failed: job reservations are released by rescheduling or cancelling the job")
+	}
+
+	if err := s.reservationRepo.DeleteReservation(ctx, tenantID, reservationID); err != nil {
+		return fmt.Errorf("failed to cancel equipment reservation: %w", err)
+	}
+
+	s.logEquipmentAction(ctx, "equipment.reservation_cancel", reservation.EquipmentID, map[string]interface{}{
+		"reservation_id": reservation.ID,
+		"starts_at":      reservation.StartsAt,
+		"ends_at":        reservation.EndsAt,
+	}, nil)
+
+	return nil
+}
+
+// JobReservationWindow returns the window a scheduled job holds its equipment
+// for. Jobs without an estimated duration hold it for the rest of the day.
+func JobReservationWindow(job *domain.EnhancedJob) (time.Time, time.Time, bool) {
+	if job.ScheduledDate == nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	start := *job.ScheduledDate
+	if job.EstimatedDuration != nil && *job.EstimatedDuration > 0 {
+		return start, start.Add(time.Duration(*job.EstimatedDuration) * time.Minute), true
+	}
+
+	endOfDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location()).AddDate(0, 0, 1)
+	return start, endOfDay, true
+}
+
+// BuildJobReservations reserves each piece of equipment the job requires for the
+// job's window. Completed, cancelled and unscheduled jobs hold no equipment.
+func BuildJobReservations(job *domain.EnhancedJob, crewID, createdBy *uuid.UUID, now time.Time) []*domain.EquipmentReservation {
+	start, end, ok := JobReservationWindow(job)
+	if !ok || job.Status == domain.JobStatusCompleted || job.Status == domain.JobStatusCancelled {
+		return nil
+	}
+
+	reservations := make([]*domain.EquipmentReservation, 0, len(job.RequiresEquipment))
+	seen := make(map[uuid.UUID]bool)
+	for _, equipmentID := range job.RequiresEquipment {
+		if seen[equipmentID] {
+			continue
+		}
+		seen[equipmentID] = true
+
+		jobID := job.ID
+		reservations = append(reservations, &domain.EquipmentReservation{
+			ID:          uuid.New(),
+			TenantID:    job.TenantID,
+			EquipmentID: equipmentID,
+			JobID:       &jobID,
+			CrewID:      crewID,
+			StartsAt:    start,
+			EndsAt:      end,
+			CreatedBy:   createdBy,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+	}
+
+	return reservations
+}
+
+// ReservationConflicts returns the reservations overlapping the window that
+// belong to something other than the given job
+func ReservationConflicts(reservations []*domain.EquipmentReservation, jobID uuid.UUID, start, end time.Time) []*domain.EquipmentReservation {
+	conflicts := make([]*domain.EquipmentReservation, 0)
+	for _, reservation := range reservations {
+		if reservation.JobID != nil && *reservation.JobID == jobID {
+			continue
+		}
+		if reservation.Overlaps(start, end) {
+			conflicts = append(conflicts, reservation)
+		}
+	}
+	return conflicts
+}
+
+// CheckTransportCapacity warns when a crew's equipment cannot travel together.
+// Equipment with a towing capacity is a truck; equipment with only a payload
+// capacity is a trailer; everything else with a weight is cargo. Cargo fills the
+// truck beds first and the trailers after, and each trailer needs its own truck.
+func CheckTransportCapacity(equipment []*domain.Equipment) []string {
+	var cargo, truckPayload, towing, trailerPayload, trailerWeight float64
+	var trucks, trailers int
+
+	seen := make(map[uuid.UUID]bool)
+	for _, eq := range equipment {
+		if seen[eq.ID] {
+			continue
+		}
+		seen[eq.ID] = true
+
+		weight := floatValue(eq.WeightLbs)
+		payload := floatValue(eq.PayloadCapacityLbs)
+		switch {
+		case floatValue(eq.TowingCapacityLbs) > 0:
+			trucks++
+			truckPayload += payload
+			towing += *eq.TowingCapacityLbs
+		case payload > 0:
+			trailers++
+			trailerPayload += payload
+			trailerWeight += weight
+		default:
+			cargo += weight
+		}
+	}
+
+	warnings := make([]string, 0)
+	if cargo > 0 && truckPayload+trailerPayload == 0 {
+		warnings = append(warnings, fmt.Sprintf("%.0f lbs of equipment has no truck or trailer to carry it", cargo))
+	} else if cargo > truckPayload+trailerPayload {
+		warnings = append(warnings, fmt.Sprintf("equipment weighs %.0f lbs but the trucks and trailers carry %.0f lbs", cargo, truckPayload+trailerPayload))
+	}
+
+	if trailers > trucks {
+		warnings = append(warnings, fmt.Sprintf("%d trailers but only %d trucks to tow them", trailers, trucks))
+	}
+
+	if trailers > 0 && trucks > 0 {
+		towed := trailerWeight + math.Min(math.Max(cargo-truckPayload, 0), trailerPayload)
+		if towed > towing {
+			warnings = append(warnings, fmt.Sprintf("loaded trailers weigh %.0f lbs but the trucks tow %.0f lbs", towed, towing))
+		}
+	}
+
+	return warnings
+}
+
+// NextFreeSlot finds the earliest start at or after start where a job of the
+// given duration overlaps none of the busy windows and ends by maxTime
+func NextFreeSlot(start time.Time, duration time.Duration, maxTime time.Time, busy []TimeRange) *time.Time {
+	windows := make([]TimeRange, len(busy))
+	copy(windows, busy)
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+
+	candidate := start
+	for _, window := range windows {
+		if !window.End.After(candidate) {
+			continue
+		}
+		if !candidate.Add(duration).After(window.Start) {
+			break
+		}
+		candidate = window.End
+	}
+
+	if candidate.Add(duration).After(maxTime) {
+		return nil
+	}
+	return &candidate
+}
+
+// Helper functions
+
+// checkEquipmentReservations fails if equipment the job requires is reserved
+// by something else during the job's window
+func (s *JobServiceImpl) checkEquipmentReservations(ctx context.Context, job *domain.EnhancedJob) error {
+	start, end, ok := JobReservationWindow(job)
+	if !ok || len(job.RequiresEquipment) == 0 {
+		return nil
+	}
+
+	reservations, err := s.reservationRepo.ListReservations(ctx, job.TenantID, job.RequiresEquipment, start, end)
+	if err != nil {
+		return fmt.Errorf("failed to check equipment reservations: %w", err)
+	}
+
+	if conflicts := ReservationConflicts(reservations, job.ID, start, end); len(conflicts) > 0 {
+		return fmt.Errorf("equipment %s is already reserved from %s to %s", conflicts[0].EquipmentID,
+			conflicts[0].StartsAt.Format(time.RFC3339), conflicts[0].EndsAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// reserveJobEquipment replaces the job's reservations to match its schedule and
+// required equipment. The crew is kept from the existing reservations unless a
+// new one is given.
+func (s *JobServiceImpl) reserveJobEquipment(ctx context.Context, job *domain.EnhancedJob, crewID *uuid.UUID) error {
+	if crewID == nil {
+		existing, err := s.reservationRepo.ListJobReservations(ctx, job.TenantID, job.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list job reservations: %w", err)
+		}
+		if len(existing) > 0 {
+			crewID = existing[0].CrewID
+		}
+	}
+
+	reservations := BuildJobReservations(job, crewID, GetUserIDFromContext(ctx), time.Now())
+	if len(reservations) == 0 {
+		return s.releaseJobEquipment(ctx, job)
+	}
+
+	if err := s.reservationRepo.ReplaceJobReservations(ctx, job.TenantID, job.ID, reservations); err != nil {
+		return fmt.Errorf("failed to reserve job equipment: %w", err)
+	}
+	return nil
+}
+
+func (s *JobServiceImpl) releaseJobEquipment(ctx context.Context, job *domain.EnhancedJob) error {
+	if err := s.reservationRepo.DeleteJobReservations(ctx, job.TenantID, job.ID); err != nil {
+		return fmt.Errorf("failed to release job equipment: %w", err)
+	}
+	return nil
+}
+
+// equipmentConflicts reports equipment the job requires that is reserved by
+// something else during the window
+func (s *JobServiceImpl) equipmentConflicts(ctx context.Context, job *domain.EnhancedJob, start, end time.Time) ([]SchedulingConflict, error) {
+	conflicts := make([]SchedulingConflict, 0)
+	if len(job.RequiresEquipment) == 0 {
+		return conflicts, nil
+	}
+
+	reservations, err := s.reservationRepo.ListReservations(ctx, job.TenantID, job.RequiresEquipment, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check equipment reservations: %w", err)
+	}
+	reserved := ReservationConflicts(reservations, job.ID, start, end)
+	if len(reserved) == 0 {
+		return conflicts, nil
+	}
+
+	equipment, err := s.equipmentRepo.GetByIDs(ctx, job.TenantID, job.RequiresEquipment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get equipment: %w", err)
+	}
+	names := make(map[uuid.UUID]string, len(equipment))
+	for _, eq := range equipment {
+		names[eq.ID] = eq.Name
+	}
+
+	for _, reservation := range reserved {
+		conflict := SchedulingConflict{
+			Type:         "equipment_conflict",
+			ConflictTime: TimeRange{Start: reservation.StartsAt, End: reservation.EndsAt},
+			Severity:     "high",
+			Message:      fmt.Sprintf("%s is reserved during this time", names[reservation.EquipmentID]),
+		}
+		if reservation.JobID != nil {
+			conflict.ConflictingJobID = *reservation.JobID
+			if other, err := s.jobRepo.GetByID(ctx, job.TenantID, *reservation.JobID); err == nil && other != nil {
+				conflict.ConflictingJobTitle = other.Title
+				conflict.Message = fmt.Sprintf("%s is reserved for job '%s' during this time", names[reservation.EquipmentID], other.Title)
+			}
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	return conflicts, nil
+}
+
+// transportConflicts warns when the equipment the job's crew takes out that day,
+// including its own trucks and trailers, cannot travel together
+func (s *JobServiceImpl) transportConflicts(ctx context.Context, job *domain.EnhancedJob, start time.Time) ([]SchedulingConflict, error) {
+	conflicts := make([]SchedulingConflict, 0)
+
+	jobReservations, err := s.reservationRepo.ListJobReservations(ctx, job.TenantID, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job reservations: %w", err)
+	}
+	var crewID *uuid.UUID
+	for _, reservation := range jobReservations {
+		if reservation.CrewID != nil {
+			crewID = reservation.CrewID
+			break
+		}
+	}
+	if crewID == nil {
+		return conflicts, nil
+	}
+
+	crew, err := s.crewRepo.GetByID(ctx, job.TenantID, *crewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get crew: %w", err)
+	}
+	if crew == nil {
+		return conflicts, nil
+	}
+
+	dayStart := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	crewReservations, err := s.reservationRepo.ListCrewReservations(ctx, job.TenantID, crew.ID, dayStart, dayStart.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list crew reservations: %w", err)
+	}
+
+	equipmentIDs := append([]uuid.UUID{}, crew.EquipmentIDs...)
+	equipmentIDs = append(equipmentIDs, job.RequiresEquipment...)
+	for _, reservation := range crewReservations {
+		equipmentIDs = append(equipmentIDs, reservation.EquipmentID)
+	}
+
+	equipment, err := s.equipmentRepo.GetByIDs(ctx, job.TenantID, equipmentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get equipment: %w", err)
+	}
+
+	for _, warning := range CheckTransportCapacity(equipment) {
+		conflicts = append(conflicts, SchedulingConflict{
+			Type:         "transport_capacity",
+			ConflictTime: TimeRange{Start: dayStart, End: dayStart.AddDate(0, 0, 1)},
+			Severity:     "medium",
+			Message:      fmt.Sprintf("Crew '%s' cannot haul its equipment together: %s", crew.Name, warning),
+		})
+	}
+
+	return conflicts, nil
+}
+
+func floatValue(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"strings"
 	"time"
 
@@ -17,8 +18,15 @@ type EquipmentServiceImpl struct {
 	equipmentRepo       EquipmentRepositoryFull
 	jobRepo             JobRepositoryComplete
 	maintenanceRepo     MaintenanceRepository
+	meterRepo           EquipmentMeterRepository
+	reservationRepo     EquipmentReservationRepository
+	workOrderRepo       MaintenanceWorkOrderRepository
+	assetRepo           EquipmentAssetRepository
+	fuelRepo            FuelRepository
+	checkoutRepo        EquipmentCheckoutRepository
 	auditService        AuditService
 	notificationService NotificationService
+	storageService      StorageService
 	logger              *log.Logger
 }
 
@@ -71,16 +79,30 @@ func NewEquipmentService(
 	equipmentRepo EquipmentRepositoryFull,
 	jobRepo JobRepositoryComplete,
 	maintenanceRepo MaintenanceRepository,
+	meterRepo EquipmentMeterRepository,
+	reservationRepo EquipmentReservationRepository,
+	workOrderRepo MaintenanceWorkOrderRepository,
+	assetRepo EquipmentAssetRepository,
+	fuelRepo FuelRepository,
+	checkoutRepo EquipmentCheckoutRepository,
 	auditService AuditService,
 	notificationService NotificationService,
+	storageService StorageService,
 	logger *log.Logger,
 ) EquipmentService {
 	return &EquipmentServiceImpl{
 		equipmentRepo:       equipmentRepo,
 		jobRepo:             jobRepo,
 		maintenanceRepo:     maintenanceRepo,
+		meterRepo:           meterRepo,
+		reservationRepo:     reservationRepo,
+		workOrderRepo:       workOrderRepo,
+		assetRepo:           assetRepo,
+		fuelRepo:            fuelRepo,
+		checkoutRepo:        checkoutRepo,
 		auditService:        auditService,
 		notificationService: notificationService,
+		storageService:      storageService,
 		logger:              logger,
 	}
 }
@@ -111,6 +133,9 @@ func (s *EquipmentServiceImpl) CreateEquipment(ctx context.Context, req *Equipme
 		Status:              "available",
 		MaintenanceSchedule: req.MaintenanceSchedule,
 		Notes:               req.Notes,
+		WeightLbs:           req.WeightLbs,
+		PayloadCapacityLbs:  req.PayloadCapacityLbs,
+		TowingCapacityLbs:   req.TowingCapacityLbs,
 		CreatedAt:           time.Now(),
 		UpdatedAt:           time.Now(),
 	}
@@ -227,6 +252,15 @@ func (s *EquipmentServiceImpl) UpdateEquipment(ctx context.Context, equipmentID
 	if req.Notes != nil {
 		equipment.Notes = req.Notes
 	}
+	if req.WeightLbs != nil {
+		equipment.WeightLbs = req.WeightLbs
+	}
+	if req.PayloadCapacityLbs != nil {
+		equipment.PayloadCapacityLbs = req.PayloadCapacityLbs
+	}
+	if req.TowingCapacityLbs != nil {
+		equipment.TowingCapacityLbs = req.TowingCapacityLbs
+	}
 
 	equipment.UpdatedAt = time.Now()
 
@@ -561,6 +595,11 @@ func (s *EquipmentServiceImpl) PerformMaintenance(ctx context.Context, equipment
 		s.logger.Printf("Failed to update equipment maintenance dates", "error", err, "equipment_id", equipmentID)
 	}
 
+	// Start the next usage interval from the current meter readings
+	if err := s.resetUsageTriggers(ctx, tenantID, equipmentID); err != nil {
+		s.logger.Printf("Failed to reset usage triggers: %v", err)
+	}
+
 	// Update equipment status back to available if it was in maintenance
 	if equipment.Status == "maintenance" {
 		equipment.Status = "available"
@@ -591,34 +630,44 @@ func (s *EquipmentServiceImpl) PerformMaintenance(ctx context.Context, equipment
 	return nil
 }
 
-// CheckMaintenanceDue checks for equipment with maintenance due
+// CheckMaintenanceDue checks for equipment with maintenance due by date or
+// usage, including usage projected from the jobs scheduled over the next
+// DefaultMaintenanceForecastDays days
 func (s *EquipmentServiceImpl) CheckMaintenanceDue(ctx context.Context) ([]*domain.Equipment, error) {
-	tenantID, ok := GetTenantIDFromContext(ctx)
-	if !ok {
-		return nil, fmt.Errorf("tenant ID not found in context")
-	}
-
-	equipment, err := s.equipmentRepo.GetMaintenanceDue(ctx, tenantID)
+	forecasts, err := s.GetMaintenanceForecast(ctx, DefaultMaintenanceForecastDays)
 	if err != nil {
-		s.logger.Printf("Failed to get equipment with maintenance due", "error", err, "tenant_id", tenantID)
+		s.logger.Printf("Failed to forecast equipment maintenance: %v", err)
 		return nil, fmt.Errorf("failed to get equipment with maintenance due: %w", err)
 	}
 
-	// Send notifications for overdue maintenance
-	for _, eq := range equipment {
-		if eq.NextMaintenance != nil && eq.NextMaintenance.Before(time.Now()) {
-			if err := s.notificationService.SendNotification(ctx, &NotificationRequest{
-				Type:    "maintenance.overdue",
-				Title:   "Maintenance Overdue",
-				Message: fmt.Sprintf("Maintenance is overdue for %s", eq.Name),
-				Data: map[string]interface{}{
-					"equipment_id":   eq.ID,
-					"equipment_name": eq.Name,
-					"due_date":      eq.NextMaintenance,
-				},
-			}); err != nil {
-				s.logger.Printf("Failed to send overdue maintenance notification", "error", err, "equipment_id", eq.ID)
-			}
+	equipment := make([]*domain.Equipment, 0)
+	for _, forecast := range forecasts {
+		if !forecast.Overdue && !forecast.DueWithinHorizon {
+			continue
+		}
+		eq := forecast.Equipment
+		equipment = append(equipment, eq)
+
+		notification := &NotificationRequest{
+			Type:    "maintenance.due_soon",
+			Title:   "Maintenance Due Soon",
+			Message: fmt.Sprintf("Maintenance for %s is due by %s", eq.Name, forecast.DueDate.Format("January 2, 2006")),
+			Data: map[string]interface{}{
+				"equipment_id":   eq.ID,
+				"equipment_name": eq.Name,
+				"due_date":       forecast.DueDate,
+				"due_by":         forecast.DueBy,
+				"usage":          forecast.Usage,
+			},
+		}
+		if forecast.Overdue {
+			notification.Type = "maintenance.overdue"
+			notification.Title = "Maintenance Overdue"
+			notification.Message = fmt.Sprintf("Maintenance is overdue for %s", eq.Name)
+		}
+
+		if err := s.notificationService.SendNotification(ctx, notification); err != nil {
+			s.logger.Printf("Failed to send %s notification for equipment %s: %v", notification.Type, eq.ID, err)
 		}
 	}
 
@@ -813,6 +862,28 @@ func (s *EquipmentServiceImpl) PredictMaintenanceNeeds(ctx context.Context, equi
 		}
 	}
 
+	// Equipment carried as a fixed asset is weighed for replacement
+	profile, err := s.assetRepo.GetAssetProfile(ctx, tenantID, equipmentID)
+	if err != nil {
+		s.logger.Printf("Failed to get asset profile for prediction: %v", err)
+	} else if profile != nil && profile.DisposedAt == nil {
+		analysis, err := s.analyzeReplaceOrRepair(ctx, equipment, profile, 0, time.Now())
+		if err != nil {
+			s.logger.Printf("Failed to analyze replacement for prediction: %v", err)
+		} else if analysis.Recommendation != ReplaceOrRepairRepair {
+			priority := "medium"
+			if analysis.Recommendation == ReplaceOrRepairReplace {
+				priority = "high"
+			}
+			prediction.Recommendations = append(prediction.Recommendations, MaintenanceRecommendation{
+				Type:          "replacement",
+				Priority:      priority,
+				Description:   "Consider replacing: " + strings.Join(analysis.Reasons, "; "),
+				EstimatedCost: analysis.ReplacementCost,
+			})
+		}
+	}
+
 	return prediction, nil
 }
 
@@ -844,6 +915,15 @@ func (s *EquipmentServiceImpl) GetEquipmentPerformanceMetrics(ctx context.Contex
 		return nil, fmt.Errorf("failed to get maintenance costs: %w", err)
 	}
 
+	// Work orders add their parts, labor and vendor costs and the time the
+	// equipment spent out of service
+	workOrders, err := s.workOrderRepo.ListWorkOrders(ctx, tenantID, &WorkOrderFilter{EquipmentID: &equipmentID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work orders: %w", err)
+	}
+	workOrderSummary := SummarizeWorkOrders(workOrders, oneYearAgo, time.Now(), time.Now())
+	costAnalysis.TotalCost += workOrderSummary.Cost
+
 	// Calculate ROI
 	var roi float64
 	if equipment.PurchasePrice != nil && *equipment.PurchasePrice > 0 {
@@ -862,7 +942,7 @@ func (s *EquipmentServiceImpl) GetEquipmentPerformanceMetrics(ctx context.Contex
 		MaintenanceCost:   costAnalysis.TotalCost,
 		ROI:              roi,
 		EfficiencyScore:  efficiencyScore,
-		DowntimeHours:    0, // Would calculate from maintenance records
+		DowntimeHours:    int(math.Round(workOrderSummary.DowntimeHours)),
 		ReliabilityScore: s.calculateReliabilityScore(costAnalysis.Records),
 	}
 
@@ -881,6 +961,9 @@ func (s *EquipmentServiceImpl) validateCreateEquipmentRequest(req *EquipmentCrea
 	if req.PurchasePrice != nil && *req.PurchasePrice < 0 {
 		return fmt.Errorf("purchase price cannot be negative")
 	}
+	if floatValue(req.WeightLbs) < 0 || floatValue(req.PayloadCapacityLbs) < 0 || floatValue(req.TowingCapacityLbs) < 0 {
+		return fmt.Errorf("weight and capacities cannot be negative")
+	}
 	return nil
 }
 
@@ -902,6 +985,9 @@ func (s *EquipmentServiceImpl) validateEquipment(equipment *domain.Equipment) er
 	if !isValidStatus {
 		return fmt.Errorf("invalid equipment status: %s", equipment.Status)
 	}
+	if floatValue(equipment.WeightLbs) < 0 || floatValue(equipment.PayloadCapacityLbs) < 0 || floatValue(equipment.TowingCapacityLbs) < 0 {
+		return fmt.Errorf("weight and capacities cannot be negative")
+	}
 	return nil
 }
 
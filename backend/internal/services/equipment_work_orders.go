@@ -0,0 +1,910 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// MaintenanceWorkOrderRepository defines data access for maintenance work
+// orders, their line items and the parts inventory they draw from
+type MaintenanceWorkOrderRepository interface {
+	CreateWorkOrder(ctx context.Context, workOrder *domain.MaintenanceWorkOrder) error
+	GetWorkOrder(ctx context.Context, tenantID, workOrderID uuid.UUID) (*domain.MaintenanceWorkOrder, error)
+	UpdateWorkOrder(ctx context.Context, workOrder *domain.MaintenanceWorkOrder) error
+	ListWorkOrders(ctx context.Context, tenantID uuid.UUID, filter *WorkOrderFilter) ([]*domain.MaintenanceWorkOrder, error)
+
+	// ListDowntime lists work orders whose downtime overlaps the window, for all
+	// equipment when equipmentIDs is empty. Downtime that has not ended overlaps
+	// every window after it started.
+	ListDowntime(ctx context.Context, tenantID uuid.UUID, equipmentIDs []uuid.UUID, start, end time.Time) ([]*domain.MaintenanceWorkOrder, error)
+
+	// ConsumePart draws the quantity from stock and records it against the work
+	// order, failing if there is not enough on hand. It returns the part with its
+	// remaining stock.
+	ConsumePart(ctx context.Context, usage *domain.WorkOrderPart) (*domain.Part, error)
+	ListWorkOrderParts(ctx context.Context, tenantID, workOrderID uuid.UUID) ([]*domain.WorkOrderPart, error)
+	CreateLabor(ctx context.Context, labor *domain.WorkOrderLabor) error
+	ListWorkOrderLabor(ctx context.Context, tenantID, workOrderID uuid.UUID) ([]*domain.WorkOrderLabor, error)
+	CreateVendorRepair(ctx context.Context, repair *domain.WorkOrderVendorRepair) error
+	ListVendorRepairs(ctx context.Context, tenantID, workOrderID uuid.UUID) ([]*domain.WorkOrderVendorRepair, error)
+
+	CreateAttachment(ctx context.Context, attachment *domain.FileAttachment) error
+	GetAttachment(ctx context.Context, tenantID, attachmentID uuid.UUID) (*domain.FileAttachment, error)
+
+	CreatePart(ctx context.Context, part *domain.Part) error
+	GetPart(ctx context.Context, tenantID, partID uuid.UUID) (*domain.Part, error)
+	UpdatePart(ctx context.Context, part *domain.Part) error
+	ListParts(ctx context.Context, tenantID uuid.UUID, lowStockOnly bool) ([]*domain.Part, error)
+}
+
+// WorkOrderFilter narrows a work order listing
+type WorkOrderFilter struct {
+	EquipmentID *uuid.UUID `json:"equipment_id,omitempty"`
+	Status      string     `json:"status,omitempty"`
+}
+
+// WorkOrderRequest opens a maintenance work order. TakeOutOfService marks the
+// equipment down from now until the work order is closed.
+type WorkOrderRequest struct {
+	Title            string     `json:"title"`
+	Description      *string    `json:"description,omitempty"`
+	Type             string     `json:"type"`
+	Priority         string     `json:"priority,omitempty"`
+	AssignedTo       *uuid.UUID `json:"assigned_to,omitempty"`
+	ScheduledDate    *time.Time `json:"scheduled_date,omitempty"`
+	TakeOutOfService bool       `json:"take_out_of_service"`
+	ExpectedReturnAt *time.Time `json:"expected_return_at,omitempty"`
+}
+
+// WorkOrderCloseRequest closes a work order
+type WorkOrderCloseRequest struct {
+	ResolutionNotes *string `json:"resolution_notes,omitempty"`
+}
+
+// WorkOrderPartRequest draws parts from inventory for a work order
+type WorkOrderPartRequest struct {
+	PartID   uuid.UUID `json:"part_id"`
+	Quantity float64   `json:"quantity"`
+}
+
+// WorkOrderLaborRequest logs a mechanic's hours on a work order
+type WorkOrderLaborRequest struct {
+	MechanicID uuid.UUID  `json:"mechanic_id"`
+	WorkDate   *time.Time `json:"work_date,omitempty"`
+	Hours      float64    `json:"hours"`
+	HourlyRate float64    `json:"hourly_rate"`
+	Notes      *string    `json:"notes,omitempty"`
+}
+
+// VendorRepairRequest records work done by an outside shop
+type VendorRepairRequest struct {
+	VendorName    string               `json:"vendor_name"`
+	InvoiceNumber *string              `json:"invoice_number,omitempty"`
+	Description   *string              `json:"description,omitempty"`
+	Amount        float64              `json:"amount"`
+	CompletedAt   *time.Time           `json:"completed_at,omitempty"`
+	Invoice       *VendorInvoiceUpload `json:"-"`
+}
+
+// VendorInvoiceUpload is a vendor's invoice file
+type VendorInvoiceUpload struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// PartRequest adds a part to inventory
+type PartRequest struct {
+	SKU            string  `json:"sku"`
+	Name           string  `json:"name"`
+	Description    *string `json:"description,omitempty"`
+	Unit           string  `json:"unit,omitempty"`
+	UnitCost       float64 `json:"unit_cost"`
+	QuantityOnHand float64 `json:"quantity_on_hand"`
+	ReorderPoint   float64 `json:"reorder_point"`
+}
+
+// PartUpdateRequest updates a part or corrects its stock count
+type PartUpdateRequest struct {
+	Name           *string  `json:"name,omitempty"`
+	Description    *string  `json:"description,omitempty"`
+	Unit           *string  `json:"unit,omitempty"`
+	UnitCost       *float64 `json:"unit_cost,omitempty"`
+	QuantityOnHand *float64 `json:"quantity_on_hand,omitempty"`
+	ReorderPoint   *float64 `json:"reorder_point,omitempty"`
+}
+
+// WorkOrderSummary totals the maintenance cost and downtime of work orders over a period
+type WorkOrderSummary struct {
+	WorkOrders    int     `json:"work_orders"`
+	Cost          float64 `json:"cost"`
+	DowntimeHours float64 `json:"downtime_hours"`
+}
+
+// OpenWorkOrder opens a maintenance work order for equipment
+func (s *EquipmentServiceImpl) OpenWorkOrder(ctx context.Context, equipmentID uuid.UUID, req *WorkOrderRequest) (*domain.MaintenanceWorkOrder, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if err := ValidateWorkOrderRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	equipment, err := s.getEquipment(ctx, tenantID, equipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	workOrder := &domain.MaintenanceWorkOrder{
+		ID:            uuid.New(),
+		TenantID:      tenantID,
+		EquipmentID:   equipmentID,
+		Title:         strings.TrimSpace(req.Title),
+		Description:   req.Description,
+		Type:          req.Type,
+		Priority:      req.Priority,
+		Status:        domain.WorkOrderStatusOpen,
+		AssignedTo:    req.AssignedTo,
+		ScheduledDate: req.ScheduledDate,
+		OpenedBy:      GetUserIDFromContext(ctx),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if workOrder.Priority == "" {
+		workOrder.Priority = "medium"
+	}
+	if req.TakeOutOfService {
+		workOrder.DowntimeStartedAt = &now
+		workOrder.ExpectedReturnAt = req.ExpectedReturnAt
+	}
+
+	if err := s.workOrderRepo.CreateWorkOrder(ctx, workOrder); err != nil {
+		return nil, fmt.Errorf("failed to create work order: %w", err)
+	}
+
+	if workOrder.IsDown() {
+		s.takeOutOfService(ctx, equipment)
+	}
+
+	if workOrder.AssignedTo != nil {
+		if err := s.notificationService.SendNotification(ctx, &NotificationRequest{
+			UserID:  workOrder.AssignedTo,
+			Type:    "maintenance.work_order_assigned",
+			Title:   "Work Order Assigned",
+			Message: fmt.Sprintf("You have been assigned %s on %s", workOrder.Title, equipment.Name),
+			Data: map[string]interface{}{
+				"work_order_id":  workOrder.ID,
+				"equipment_id":   equipmentID,
+				"equipment_name": equipment.Name,
+			},
+		}); err != nil {
+			s.logger.Printf("Failed to send work order notification: %v", err)
+		}
+	}
+
+	s.logEquipmentAction(ctx, "equipment.work_order_open", equipmentID, nil, map[string]interface{}{
+		"work_order_id":       workOrder.ID,
+		"type":                workOrder.Type,
+		"take_out_of_service": req.TakeOutOfService,
+	})
+
+	return workOrder, nil
+}
+
+// GetWorkOrder retrieves a work order with its parts, labor and vendor repairs
+func (s *EquipmentServiceImpl) GetWorkOrder(ctx context.Context, workOrderID uuid.UUID) (*domain.MaintenanceWorkOrder, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	workOrder, err := s.getWorkOrder(ctx, tenantID, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadWorkOrderLines(ctx, workOrder); err != nil {
+		return nil, err
+	}
+
+	for _, repair := range workOrder.VendorRepairs {
+		if repair.InvoiceAttachmentID == nil {
+			continue
+		}
+		attachment, err := s.workOrderRepo.GetAttachment(ctx, tenantID, *repair.InvoiceAttachmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get invoice attachment: %w", err)
+		}
+		repair.InvoiceAttachment = attachment
+	}
+
+	return workOrder, nil
+}
+
+// ListWorkOrders lists work orders, newest first
+func (s *EquipmentServiceImpl) ListWorkOrders(ctx context.Context, filter *WorkOrderFilter) ([]*domain.MaintenanceWorkOrder, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if filter == nil {
+		filter = &WorkOrderFilter{}
+	}
+
+	workOrders, err := s.workOrderRepo.ListWorkOrders(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list work orders: %w", err)
+	}
+
+	return workOrders, nil
+}
+
+// StartWorkOrder moves an open work order to in progress. Equipment being worked
+// on is out of service, so downtime starts now if it had not already.
+func (s *EquipmentServiceImpl) StartWorkOrder(ctx context.Context, workOrderID uuid.UUID) (*domain.MaintenanceWorkOrder, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	workOrder, err := s.getWorkOrder(ctx, tenantID, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateWorkOrderTransition(workOrder.Status, domain.WorkOrderStatusInProgress); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	now := time.Now()
+	workOrder.Status = domain.WorkOrderStatusInProgress
+	workOrder.StartedAt = &now
+	if workOrder.DowntimeStartedAt == nil {
+		workOrder.DowntimeStartedAt = &now
+	}
+	workOrder.UpdatedAt = now
+
+	if err := s.workOrderRepo.UpdateWorkOrder(ctx, workOrder); err != nil {
+		return nil, fmt.Errorf("failed to update work order: %w", err)
+	}
+
+	equipment, err := s.getEquipment(ctx, tenantID, workOrder.EquipmentID)
+	if err != nil {
+		return nil, err
+	}
+	s.takeOutOfService(ctx, equipment)
+
+	s.logEquipmentAction(ctx, "equipment.work_order_start", workOrder.EquipmentID, nil, map[string]interface{}{
+		"work_order_id": workOrder.ID,
+	})
+
+	return workOrder, nil
+}
+
+// CloseWorkOrder closes a work order, ends its downtime and returns the
+// equipment to service once nothing else has it down. Closing preventive
+// maintenance restarts the equipment's calendar and usage schedules.
+func (s *EquipmentServiceImpl) CloseWorkOrder(ctx context.Context, workOrderID uuid.UUID, req *WorkOrderCloseRequest) (*domain.MaintenanceWorkOrder, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	workOrder, err := s.getWorkOrder(ctx, tenantID, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateWorkOrderTransition(workOrder.Status, domain.WorkOrderStatusClosed); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.loadWorkOrderLines(ctx, workOrder); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	wasDown := workOrder.IsDown()
+	workOrder.Status = domain.WorkOrderStatusClosed
+	workOrder.ClosedAt = &now
+	if wasDown {
+		workOrder.DowntimeEndedAt = &now
+	}
+	if req != nil && req.ResolutionNotes != nil {
+		workOrder.ResolutionNotes = req.ResolutionNotes
+	}
+	CalculateWorkOrderCosts(workOrder)
+	workOrder.UpdatedAt = now
+
+	if err := s.workOrderRepo.UpdateWorkOrder(ctx, workOrder); err != nil {
+		return nil, fmt.Errorf("failed to update work order: %w", err)
+	}
+
+	equipment, err := s.getEquipment(ctx, tenantID, workOrder.EquipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if workOrder.Type == domain.WorkOrderTypePreventive {
+		nextMaintenance := now.AddDate(0, 3, 0)
+		if equipment.MaintenanceSchedule != nil {
+			nextMaintenance = s.calculateNextMaintenanceDate(*equipment.MaintenanceSchedule, now)
+		}
+		if err := s.equipmentRepo.UpdateMaintenanceDate(ctx, equipment.ID, now, nextMaintenance); err != nil {
+			s.logger.Printf("Failed to update equipment maintenance dates: %v", err)
+		}
+		if err := s.resetUsageTriggers(ctx, tenantID, equipment.ID); err != nil {
+			s.logger.Printf("Failed to reset usage triggers: %v", err)
+		}
+	}
+
+	if wasDown {
+		if err := s.returnToService(ctx, equipment); err != nil {
+			s.logger.Printf("Failed to return equipment %s to service: %v", equipment.ID, err)
+		}
+	}
+
+	s.logEquipmentAction(ctx, "equipment.work_order_close", workOrder.EquipmentID, nil, map[string]interface{}{
+		"work_order_id": workOrder.ID,
+		"total_cost":    workOrder.TotalCost,
+	})
+
+	return workOrder, nil
+}
+
+// AddWorkOrderPart draws parts from inventory for a work order
+func (s *EquipmentServiceImpl) AddWorkOrderPart(ctx context.Context, workOrderID uuid.UUID, req *WorkOrderPartRequest) (*domain.MaintenanceWorkOrder, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if req.Quantity <= 0 {
+		return nil, fmt.Errorf("validation failed: quantity must be positive")
+	}
+
+	workOrder, err := s.getOpenWorkOrder(ctx, tenantID, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	part, err := s.workOrderRepo.GetPart(ctx, tenantID, req.PartID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get part: %w", err)
+	}
+	if part == nil {
+		return nil, fmt.Errorf("part not found")
+	}
+	if part.QuantityOnHand < req.Quantity {
+		return nil, fmt.Errorf("validation failed: insufficient stock of %s: %.2f on hand", part.Name, part.QuantityOnHand)
+	}
+
+	usage := &domain.WorkOrderPart{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		WorkOrderID: workOrderID,
+		PartID:      part.ID,
+		PartName:    part.Name,
+		Quantity:    req.Quantity,
+		UnitCost:    part.UnitCost,
+		TotalCost:   roundCents(req.Quantity * part.UnitCost),
+		CreatedBy:   GetUserIDFromContext(ctx),
+		CreatedAt:   time.Now(),
+	}
+
+	remaining, err := s.workOrderRepo.ConsumePart(ctx, usage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use part: %w", err)
+	}
+	if remaining != nil && remaining.QuantityOnHand <= remaining.ReorderPoint {
+		s.notifyLowStock(ctx, remaining)
+	}
+
+	return s.recalculateWorkOrder(ctx, workOrder)
+}
+
+// AddWorkOrderLabor logs a mechanic's hours on a work order
+func (s *EquipmentServiceImpl) AddWorkOrderLabor(ctx context.Context, workOrderID uuid.UUID, req *WorkOrderLaborRequest) (*domain.MaintenanceWorkOrder, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if req.MechanicID == uuid.Nil {
+		return nil, fmt.Errorf("validation failed: mechanic is required")
+	}
+	if req.Hours <= 0 {
+		return nil, fmt.Errorf("validation failed: hours must be positive")
+	}
+	if req.HourlyRate < 0 {
+		return nil, fmt.Errorf("validation failed: hourly rate cannot be negative")
+	}
+
+	workOrder, err := s.getOpenWorkOrder(ctx, tenantID, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	workDate := now
+	if req.WorkDate != nil {
+		workDate = *req.WorkDate
+	}
+
+	labor := &domain.WorkOrderLabor{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		WorkOrderID: workOrderID,
+		MechanicID:  req.MechanicID,
+		WorkDate:    workDate,
+		Hours:       req.Hours,
+		HourlyRate:  req.HourlyRate,
+		TotalCost:   roundCents(req.Hours * req.HourlyRate),
+		Notes:       req.Notes,
+		CreatedAt:   now,
+	}
+
+	if err := s.workOrderRepo.CreateLabor(ctx, labor); err != nil {
+		return nil, fmt.Errorf("failed to log labor: %w", err)
+	}
+
+	return s.recalculateWorkOrder(ctx, workOrder)
+}
+
+// AddVendorRepair records outside repair work on a work order, storing the
+// vendor's invoice as a file attachment
+func (s *EquipmentServiceImpl) AddVendorRepair(ctx context.Context, workOrderID uuid.UUID, req *VendorRepairRequest) (*domain.MaintenanceWorkOrder, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if strings.TrimSpace(req.VendorName) == "" {
+		return nil, fmt.Errorf("validation failed: vendor name is required")
+	}
+	if req.Amount < 0 {
+		return nil, fmt.Errorf("validation failed: amount cannot be negative")
+	}
+
+	workOrder, err := s.getOpenWorkOrder(ctx, tenantID, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	repair := &domain.WorkOrderVendorRepair{
+		ID:            uuid.New(),
+		TenantID:      tenantID,
+		WorkOrderID:   workOrderID,
+		VendorName:    strings.TrimSpace(req.VendorName),
+		InvoiceNumber: req.InvoiceNumber,
+		Description:   req.Description,
+		Amount:        roundCents(req.Amount),
+		CompletedAt:   req.CompletedAt,
+		CreatedAt:     now,
+	}
+
+	if req.Invoice != nil && len(req.Invoice.Data) > 0 {
+		attachment, err := s.storeVendorInvoice(ctx, workOrder, repair.ID, req.Invoice)
+		if err != nil {
+			return nil, err
+		}
+		repair.InvoiceAttachmentID = &attachment.ID
+	}
+
+	if err := s.workOrderRepo.CreateVendorRepair(ctx, repair); err != nil {
+		return nil, fmt.Errorf("failed to record vendor repair: %w", err)
+	}
+
+	return s.recalculateWorkOrder(ctx, workOrder)
+}
+
+// CreatePart adds a part to inventory
+func (s *EquipmentServiceImpl) CreatePart(ctx context.Context, req *PartRequest) (*domain.Part, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if strings.TrimSpace(req.SKU) == "" || strings.TrimSpace(req.Name) == "" {
+		return nil, fmt.Errorf("validation failed: SKU and name are required")
+	}
+	if req.UnitCost < 0 || req.QuantityOnHand < 0 || req.ReorderPoint < 0 {
+		return nil, fmt.Errorf("validation failed: cost and quantities cannot be negative")
+	}
+
+	now := time.Now()
+	part := &domain.Part{
+		ID:             uuid.New(),
+		TenantID:       tenantID,
+		SKU:            strings.TrimSpace(req.SKU),
+		Name:           strings.TrimSpace(req.Name),
+		Description:    req.Description,
+		Unit:           req.Unit,
+		UnitCost:       roundCents(req.UnitCost),
+		QuantityOnHand: req.QuantityOnHand,
+		ReorderPoint:   req.ReorderPoint,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if part.Unit == "" {
+		part.Unit = "each"
+	}
+
+	if err := s.workOrderRepo.CreatePart(ctx, part); err != nil {
+		return nil, fmt.Errorf("failed to create part: %w", err)
+	}
+
+	return part, nil
+}
+
+// UpdatePart updates a part or corrects its stock count
+func (s *EquipmentServiceImpl) UpdatePart(ctx context.Context, partID uuid.UUID, req *PartUpdateRequest) (*domain.Part, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	part, err := s.workOrderRepo.GetPart(ctx, tenantID, partID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get part: %w", err)
+	}
+	if part == nil {
+		return nil, fmt.Errorf("part not found")
+	}
+
+	oldQuantity := part.QuantityOnHand
+	if req.Name != nil {
+		part.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		part.Description = req.Description
+	}
+	if req.Unit != nil {
+		part.Unit = *req.Unit
+	}
+	if req.UnitCost != nil {
+		part.UnitCost = roundCents(*req.UnitCost)
+	}
+	if req.QuantityOnHand != nil {
+		part.QuantityOnHand = *req.QuantityOnHand
+	}
+	if req.ReorderPoint != nil {
+		part.ReorderPoint = *req.ReorderPoint
+	}
+
+	if part.Name == "" {
+		return nil, fmt.Errorf("validation failed: name is required")
+	}
+	if part.UnitCost < 0 || part.QuantityOnHand < 0 || part.ReorderPoint < 0 {
+		return nil, fmt.Errorf("validation failed: cost and quantities cannot be negative")
+	}
+	part.UpdatedAt = time.Now()
+
+	if err := s.workOrderRepo.UpdatePart(ctx, part); err != nil {
+		return nil, fmt.Errorf("failed to update part: %w", err)
+	}
+
+	if part.QuantityOnHand != oldQuantity {
+		if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+			UserID:       GetUserIDFromContext(ctx),
+			Action:       "part.stock_adjust",
+			ResourceType: "part",
+			ResourceID:   &part.ID,
+			OldValues:    map[string]interface{}{"quantity_on_hand": oldQuantity},
+			NewValues:    map[string]interface{}{"quantity_on_hand": part.QuantityOnHand},
+		}); err != nil {
+			s.logger.Printf("Failed to log audit event: %v", err)
+		}
+	}
+
+	return part, nil
+}
+
+// ListParts lists the parts inventory, only parts at or below their reorder point when lowStockOnly is set
+func (s *EquipmentServiceImpl) ListParts(ctx context.Context, lowStockOnly bool) ([]*domain.Part, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	parts, err := s.workOrderRepo.ListParts(ctx, tenantID, lowStockOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list parts: %w", err)
+	}
+
+	return parts, nil
+}
+
+// ValidateWorkOrderRequest checks a request to open a work order
+func ValidateWorkOrderRequest(req *WorkOrderRequest) error {
+	if strings.TrimSpace(req.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if !containsString(domain.WorkOrderTypes, req.Type) {
+		return fmt.Errorf("type must be one of %s", strings.Join(domain.WorkOrderTypes, ", "))
+	}
+	if req.Priority != "" && !containsString([]string{"low", "medium", "high", "urgent"}, req.Priority) {
+		return fmt.Errorf("invalid priority: %s", req.Priority)
+	}
+	if req.ExpectedReturnAt != nil && !req.TakeOutOfService {
+		return fmt.Errorf("expected return only applies when taking equipment out of service")
+	}
+	return nil
+}
+
+// ValidateWorkOrderTransition checks a work order can move between statuses.
+// Work orders move from open to in progress to closed, and may be closed
+// straight from open.
+func ValidateWorkOrderTransition(from, to string) error {
+	switch {
+	case from == domain.WorkOrderStatusOpen && to == domain.WorkOrderStatusInProgress:
+		return nil
+	case from != domain.WorkOrderStatusClosed && to == domain.WorkOrderStatusClosed:
+		return nil
+	case from == domain.WorkOrderStatusClosed:
+		return fmt.Errorf("work order is closed")
+	}
+	return fmt.Errorf("work order cannot move from %s to %s", from, to)
+}
+
+// CalculateWorkOrderCosts totals the work order's parts, labor and vendor repairs
+func CalculateWorkOrderCosts(workOrder *domain.MaintenanceWorkOrder) {
+	var parts, labor, vendor float64
+	for _, part := range workOrder.Parts {
+		parts += part.TotalCost
+	}
+	for _, entry := range workOrder.Labor {
+		labor += entry.TotalCost
+	}
+	for _, repair := range workOrder.VendorRepairs {
+		vendor += repair.Amount
+	}
+
+	workOrder.PartsCost = roundCents(parts)
+	workOrder.LaborCost = roundCents(labor)
+	workOrder.VendorCost = roundCents(vendor)
+	workOrder.TotalCost = roundCents(parts + labor + vendor)
+}
+
+// DowntimeWindow returns when a work order has its equipment out of service.
+// Downtime that has not ended runs until the expected return, or horizonEnd if
+// there is none or it has passed.
+func DowntimeWindow(workOrder *domain.MaintenanceWorkOrder, now, horizonEnd time.Time) (TimeRange, bool) {
+	if workOrder.DowntimeStartedAt == nil {
+		return TimeRange{}, false
+	}
+
+	window := TimeRange{Start: *workOrder.DowntimeStartedAt, End: horizonEnd}
+	switch {
+	case workOrder.DowntimeEndedAt != nil:
+		window.End = *workOrder.DowntimeEndedAt
+	case workOrder.ExpectedReturnAt != nil && workOrder.ExpectedReturnAt.After(now):
+		window.End = *workOrder.ExpectedReturnAt
+	}
+
+	return window, window.End.After(window.Start)
+}
+
+// SummarizeWorkOrders totals the cost of work orders closed during the period
+// or still open, and the hours the equipment was down during the period.
+// Downtime from overlapping work orders is only counted once.
+func SummarizeWorkOrders(workOrders []*domain.MaintenanceWorkOrder, start, end, now time.Time) WorkOrderSummary {
+	var summary WorkOrderSummary
+	downtime := make([]TimeRange, 0)
+
+	for _, workOrder := range workOrders {
+		if workOrder.ClosedAt == nil || (!workOrder.ClosedAt.Before(start) && workOrder.ClosedAt.Before(end)) {
+			summary.WorkOrders++
+			summary.Cost += workOrder.TotalCost
+		}
+
+		window, ok := DowntimeWindow(workOrder, now, now)
+		if !ok {
+			continue
+		}
+		if window.Start.Before(start) {
+			window.Start = start
+		}
+		if window.End.After(end) {
+			window.End = end
+		}
+		if window.End.After(window.Start) {
+			downtime = append(downtime, window)
+		}
+	}
+
+	sort.Slice(downtime, func(i, j int) bool {
+		return downtime[i].Start.Before(downtime[j].Start)
+	})
+
+	var hours float64
+	var current *TimeRange
+	for i := range downtime {
+		window := downtime[i]
+		if current != nil && !window.Start.After(current.End) {
+			if window.End.After(current.End) {
+				current.End = window.End
+			}
+			continue
+		}
+		if current != nil {
+			hours += current.End.Sub(current.Start).Hours()
+		}
+		current = &window
+	}
+	if current != nil {
+		hours += current.End.Sub(current.Start).Hours()
+	}
+
+	summary.Cost = roundCents(summary.Cost)
+	summary.DowntimeHours = roundMeasurement(hours)
+	return summary
+}
+
+// Helper functions
+
+func (s *EquipmentServiceImpl) getWorkOrder(ctx context.Context, tenantID, workOrderID uuid.UUID) (*domain.MaintenanceWorkOrder, error) {
+	workOrder, err := s.workOrderRepo.GetWorkOrder(ctx, tenantID, workOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work order: %w", err)
+	}
+	if workOrder == nil {
+		return nil, fmt.Errorf("work order not found")
+	}
+	return workOrder, nil
+}
+
+// getOpenWorkOrder gets a work order that can still take parts, labor and repairs
+func (s *EquipmentServiceImpl) getOpenWorkOrder(ctx context.Context, tenantID, workOrderID uuid.UUID) (*domain.MaintenanceWorkOrder, error) {
+	workOrder, err := s.getWorkOrder(ctx, tenantID, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if workOrder.Status == domain.WorkOrderStatusClosed {
+		return nil, fmt.Errorf("validation failed: work order is closed")
+	}
+	return workOrder, nil
+}
+
+func (s *EquipmentServiceImpl) loadWorkOrderLines(ctx context.Context, workOrder *domain.MaintenanceWorkOrder) error {
+	var err error
+	if workOrder.Parts, err = s.workOrderRepo.ListWorkOrderParts(ctx, workOrder.TenantID, workOrder.ID); err != nil {
+		return fmt.Errorf("failed to list work order parts: %w", err)
+	}
+	if workOrder.Labor, err = s.workOrderRepo.ListWorkOrderLabor(ctx, workOrder.TenantID, workOrder.ID); err != nil {
+		return fmt.Errorf("failed to list work order labor: %w", err)
+	}
+	if workOrder.VendorRepairs, err = s.workOrderRepo.ListVendorRepairs(ctx, workOrder.TenantID, workOrder.ID); err != nil {
+		return fmt.Errorf("failed to list vendor repairs: %w", err)
+	}
+	return nil
+}
+
+// recalculateWorkOrder reloads the work order's lines and saves its new totals
+func (s *EquipmentServiceImpl) recalculateWorkOrder(ctx context.Context, workOrder *domain.MaintenanceWorkOrder) (*domain.MaintenanceWorkOrder, error) {
+	if err := s.loadWorkOrderLines(ctx, workOrder); err != nil {
+		return nil, err
+	}
+
+	CalculateWorkOrderCosts(workOrder)
+	workOrder.UpdatedAt = time.Now()
+
+	if err := s.workOrderRepo.UpdateWorkOrder(ctx, workOrder); err != nil {
+		return nil, fmt.Errorf("failed to update work order costs: %w", err)
+	}
+
+	return workOrder, nil
+}
+
+func (s *EquipmentServiceImpl) storeVendorInvoice(ctx context.Context, workOrder *domain.MaintenanceWorkOrder, repairID uuid.UUID, invoice *VendorInvoiceUpload) (*domain.FileAttachment, error) {
+	contentType := invoice.ContentType
+	if contentType != "application/pdf" && !strings.HasPrefix(contentType, "image/") {
+		return nil, fmt.Errorf("validation failed: invoice must be a PDF or image")
+	}
+
+	originalName := path.Base(invoice.Filename)
+	if originalName == "." || originalName == "/" {
+		originalName = "invoice"
+	}
+	fileName := repairID.String() + strings.ToLower(path.Ext(originalName))
+	storagePath := fmt.Sprintf("equipment/%s/work-orders/%s/invoices/%s", workOrder.EquipmentID, workOrder.ID, fileName)
+
+	if _, err := s.storageService.Upload(ctx, storagePath, invoice.Data, contentType); err != nil {
+		return nil, fmt.Errorf("failed to upload invoice: %w", err)
+	}
+
+	attachment := &domain.FileAttachment{
+		ID:               uuid.New(),
+		TenantID:         workOrder.TenantID,
+		EntityType:       domain.AttachmentEntityVendorRepair,
+		EntityID:         repairID,
+		Filename:         fileName,
+		OriginalFilename: originalName,
+		FileSize:         int64(len(invoice.Data)),
+		ContentType:      contentType,
+		StoragePath:      storagePath,
+		UploadedBy:       GetUserIDFromContext(ctx),
+		CreatedAt:        time.Now(),
+	}
+
+	if err := s.workOrderRepo.CreateAttachment(ctx, attachment); err != nil {
+		return nil, fmt.Errorf("failed to save invoice attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// takeOutOfService marks equipment as in maintenance, which keeps it out of
+// availability checks until it is returned to service
+func (s *EquipmentServiceImpl) takeOutOfService(ctx context.Context, equipment *domain.Equipment) {
+	if equipment.Status == "maintenance" || equipment.Status == "retired" {
+		return
+	}
+
+	oldStatus := equipment.Status
+	equipment.Status = "maintenance"
+	equipment.UpdatedAt = time.Now()
+	if err := s.equipmentRepo.Update(ctx, equipment); err != nil {
+		s.logger.Printf("Failed to take equipment %s out of service: %v", equipment.ID, err)
+		return
+	}
+
+	s.logEquipmentAction(ctx, "equipment.out_of_service", equipment.ID,
+		map[string]interface{}{"status": oldStatus},
+		map[string]interface{}{"status": equipment.Status})
+}
+
+// returnToService makes equipment available again unless another work order still has it down
+func (s *EquipmentServiceImpl) returnToService(ctx context.Context, equipment *domain.Equipment) error {
+	if equipment.Status != "maintenance" {
+		return nil
+	}
+
+	now := time.Now()
+	downtime, err := s.workOrderRepo.ListDowntime(ctx, equipment.TenantID, []uuid.UUID{equipment.ID}, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to check equipment downtime: %w", err)
+	}
+	for _, workOrder := range downtime {
+		if workOrder.IsDown() {
+			return nil
+		}
+	}
+
+	equipment.Status = "available"
+	equipment.UpdatedAt = now
+	if err := s.equipmentRepo.Update(ctx, equipment); err != nil {
+		return fmt.Errorf("failed to update equipment: %w", err)
+	}
+
+	s.logEquipmentAction(ctx, "equipment.return_to_service", equipment.ID,
+		map[string]interface{}{"status": "maintenance"},
+		map[string]interface{}{"status": equipment.Status})
+
+	return nil
+}
+
+func (s *EquipmentServiceImpl) notifyLowStock(ctx context.Context, part *domain.Part) {
+	if err := s.notificationService.SendNotification(ctx, &NotificationRequest{
+		Type:    "inventory.low_stock",
+		Title:   "Part Low on Stock",
+		Message: fmt.Sprintf("%s (%s) is down to %.2f %s", part.Name, part.SKU, part.QuantityOnHand, part.Unit),
+		Data: map[string]interface{}{
+			"part_id":          part.ID,
+			"sku":              part.SKU,
+			"quantity_on_hand": part.QuantityOnHand,
+			"reorder_point":    part.ReorderPoint,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to send low stock notification: %v", err)
+	}
+}
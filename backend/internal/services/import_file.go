@@ -0,0 +1,251 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// ImportMaxRows limits the rows accepted in one import file
+const ImportMaxRows = 20000
+
+// ImportSheet is a parsed spreadsheet: a header row and the data rows beneath it
+type ImportSheet struct {
+	Format  string     `json:"format"`
+	Headers []string   `json:"headers"`
+	Rows    [][]string `json:"rows"`
+}
+
+// ParseImportFile reads a CSV or XLSX file by its extension. Blank rows are dropped,
+// blank or repeated headers are given unique names, and short rows are padded so
+// every row has a cell per header. Only the first worksheet of a workbook is read.
+func ParseImportFile(fileName string, content []byte) (*ImportSheet, error) {
+	var records [][]string
+	var format string
+	var err error
+
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".csv", ".txt":
+		format = domain.ImportFormatCSV
+		records, err = parseImportCSV(content)
+	case ".xlsx":
+		format = domain.ImportFormatXLSX
+		records, err = parseImportXLSX(content)
+	default:
+		return nil, fmt.Errorf("unsupported file type; upload a .csv or .xlsx file")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, record := range records {
+		if !importRowBlank(record) {
+			rows = append(rows, record)
+		}
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the file has no header row")
+	}
+	if len(rows)-1 > ImportMaxRows {
+		return nil, fmt.Errorf("the file has %d rows; imports are limited to %d", len(rows)-1, ImportMaxRows)
+	}
+
+	sheet := &ImportSheet{Format: format, Headers: importHeaders(rows[0]), Rows: rows[1:]}
+	for i, row := range sheet.Rows {
+		for len(row) < len(sheet.Headers) {
+			row = append(row, "")
+		}
+		sheet.Rows[i] = row[:len(sheet.Headers)]
+	}
+
+	return sheet, nil
+}
+
+// Helper functions
+
+func parseImportCSV(content []byte) ([][]string, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	// Spreadsheets exported with a European locale use semicolons
+	firstLine := content
+	if i := bytes.IndexByte(content, '\n'); i >= 0 {
+		firstLine = content[:i]
+	}
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV file: %w", err)
+	}
+	return records, nil
+}
+
+type xlsxSharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline struct {
+				Text string `xml:"t"`
+			} `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// parseImportXLSX reads cell values from the first worksheet of an XLSX workbook
+func parseImportXLSX(content []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open XLSX file: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	var sheets []string
+	for _, file := range archive.File {
+		files[file.Name] = file
+		if strings.HasPrefix(file.Name, "xl/worksheets/") && strings.HasSuffix(file.Name, ".xml") {
+			sheets = append(sheets, file.Name)
+		}
+	}
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("the XLSX file has no worksheets")
+	}
+	sheetName := "xl/worksheets/sheet1.xml"
+	if files[sheetName] == nil {
+		sort.Strings(sheets)
+		sheetName = sheets[0]
+	}
+
+	var shared []string
+	if file := files["xl/sharedStrings.xml"]; file != nil {
+		var table xlsxSharedStrings
+		if err := readXLSXPart(file, &table); err != nil {
+			return nil, err
+		}
+		for _, item := range table.Items {
+			text := item.Text
+			for _, run := range item.Runs {
+				text += run.Text
+			}
+			shared = append(shared, text)
+		}
+	}
+
+	var sheet xlsxWorksheet
+	if err := readXLSXPart(files[sheetName], &sheet); err != nil {
+		return nil, err
+	}
+
+	records := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		var record []string
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				column = xlsxColumnIndex(cell.Ref)
+			}
+			for len(record) <= column {
+				record = append(record, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(strings.TrimSpace(cell.Value))
+				if err == nil && index >= 0 && index < len(shared) {
+					record[column] = shared[index]
+				}
+			case "inlineStr":
+				record[column] = cell.Inline.Text
+			case "b":
+				record[column] = map[string]string{"1": "TRUE", "0": "FALSE"}[cell.Value]
+			default:
+				record[column] = cell.Value
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func readXLSXPart(file *zip.File, v interface{}) error {
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, 64<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file.Name, err)
+	}
+	return nil
+}
+
+// xlsxColumnIndex converts a cell reference such as "AB12" to a zero-based column
+func xlsxColumnIndex(ref string) int {
+	column := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+	}
+	return column - 1
+}
+
+func importHeaders(row []string) []string {
+	headers := make([]string, len(row))
+	seen := map[string]int{}
+	for i, header := range row {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			header = fmt.Sprintf("Column %d", i+1)
+		}
+		seen[strings.ToLower(header)]++
+		if n := seen[strings.ToLower(header)]; n > 1 {
+			header = fmt.Sprintf("%s (%d)", header, n)
+		}
+		headers[i] = header
+	}
+	return headers
+}
+
+func importRowBlank(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,1458 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/pkg/security"
+)
+
+// ImportService moves customers and properties in from spreadsheets when onboarding a
+// tenant. An upload is mapped to import fields, validated in a dry run that flags
+// invalid rows and likely duplicates of existing customers, then processed in the
+// background in resumable batches. A finished import can be rolled back as a batch.
+type ImportService interface {
+	CreateImport(ctx context.Context, req *ImportUploadRequest) (*domain.ImportJob, error)
+	GetImport(ctx context.Context, importID uuid.UUID) (*domain.ImportJob, error)
+	ListImports(ctx context.Context, filter *ImportFilter) (*domain.PaginatedResponse, error)
+	ListImportRows(ctx context.Context, importID uuid.UUID, filter *ImportRowFilter) (*domain.PaginatedResponse, error)
+	UpdateMapping(ctx context.Context, importID uuid.UUID, req *ImportMappingRequest) (*domain.ImportJob, error)
+	ValidateImport(ctx context.Context, importID uuid.UUID) (*ImportValidationReport, error)
+	StartImport(ctx context.Context, importID uuid.UUID) (*domain.ImportJob, error)
+	RollbackImport(ctx context.Context, importID uuid.UUID) (*domain.ImportJob, error)
+
+	// Background processing
+	ProcessImports(ctx context.Context, now time.Time) error
+}
+
+// ImportRepository defines data access for imports
+type ImportRepository interface {
+	// CreateImport stores the job and its rows together
+	CreateImport(ctx context.Context, job *domain.ImportJob, rows []*domain.ImportRow) error
+	GetImport(ctx context.Context, tenantID, importID uuid.UUID) (*domain.ImportJob, error)
+	UpdateImport(ctx context.Context, job *domain.ImportJob) error
+	ListImports(ctx context.Context, tenantID uuid.UUID, filter *ImportFilter) ([]*domain.ImportJob, int64, error)
+	// ListActiveImports lists processing and geocoding imports across all tenants
+	ListActiveImports(ctx context.Context, limit int) ([]*domain.ImportJob, error)
+
+	ListRows(ctx context.Context, tenantID, importID uuid.UUID, filter *ImportRowFilter) ([]*domain.ImportRow, int64, error)
+	// ListRowBatch lists rows in the given statuses after a row number, in row order
+	ListRowBatch(ctx context.Context, tenantID, importID uuid.UUID, statuses []string, afterRow, limit int) ([]*domain.ImportRow, error)
+	UpdateRows(ctx context.Context, rows []*domain.ImportRow) error
+}
+
+// PropertyGeocoder geocodes properties that have no coordinates yet
+type PropertyGeocoder interface {
+	BatchGeocodeProperties(ctx context.Context, limit int) error
+}
+
+// ImportUploadRequest is an uploaded spreadsheet
+type ImportUploadRequest struct {
+	FileName string `json:"file_name"`
+	Content  []byte `json:"-"`
+}
+
+// ImportMappingRequest maps import fields to spreadsheet columns
+type ImportMappingRequest struct {
+	Mapping           map[string]string `json:"mapping"`
+	DuplicateStrategy string            `json:"duplicate_strategy,omitempty"`
+}
+
+// ImportFilter filters the import history
+type ImportFilter struct {
+	BaseFilter
+	Status string `json:"status,omitempty"`
+}
+
+// ImportRowFilter filters an import's rows
+type ImportRowFilter struct {
+	BaseFilter
+	Status string `json:"status,omitempty"`
+}
+
+// ImportField is a customer or property field a column can be mapped to
+type ImportField struct {
+	Key     string   `json:"key"`
+	Label   string   `json:"label"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// ImportRecord is a row mapped to import fields
+type ImportRecord struct {
+	FirstName    string
+	LastName     string
+	Email        string
+	Phone        string
+	CompanyName  string
+	CustomerType string
+	Notes        string
+	AddressLine1 string
+	AddressLine2 string
+	City         string
+	State        string
+	ZipCode      string
+	PropertyName string
+	PropertyType string
+	LotSize      string
+}
+
+// HasAddress reports whether the row has any property address
+func (r *ImportRecord) HasAddress() bool {
+	return r.AddressLine1 != "" || r.City != "" || r.State != "" || r.ZipCode != ""
+}
+
+// FullName is the contact's name as it would be displayed
+func (r *ImportRecord) FullName() string {
+	return strings.TrimSpace(r.FirstName + " " + r.LastName)
+}
+
+// ImportValidationReport is the dry-run result for an import
+type ImportValidationReport struct {
+	ImportID        uuid.UUID        `json:"import_id"`
+	TotalRows       int              `json:"total_rows"`
+	ValidRows       int              `json:"valid_rows"`
+	InvalidRows     int              `json:"invalid_rows"`
+	DuplicateRows   int              `json:"duplicate_rows"`
+	RowsWithAddress int              `json:"rows_with_address"`
+	FieldErrors     map[string]int   `json:"field_errors"`
+	UnmappedColumns []string         `json:"unmapped_columns"`
+	Issues          []ImportRowIssue `json:"issues"` // the first rows with errors, warnings or duplicates
+}
+
+// ImportRowIssue describes a problem row in the validation report
+type ImportRowIssue struct {
+	RowNumber      int        `json:"row_number"`
+	Status         string     `json:"status"`
+	Errors         []string   `json:"errors,omitempty"`
+	Warnings       []string   `json:"warnings,omitempty"`
+	DuplicateOfID  *uuid.UUID `json:"duplicate_of_id,omitempty"`
+	DuplicateName  string     `json:"duplicate_name,omitempty"`
+	DuplicateScore float64    `json:"duplicate_score,omitempty"`
+}
+
+// ImportFields lists the fields spreadsheet columns can be mapped to. A full name
+// column is split into first and last name when those aren't mapped.
+var ImportFields = []ImportField{
+	{Key: "first_name", Label: "First Name", Aliases: []string{"first", "firstname", "given name"}},
+	{Key: "last_name", Label: "Last Name", Aliases: []string{"last", "lastname", "surname", "family name"}},
+	{Key: "full_name", Label: "Full Name", Aliases: []string{"name", "customer", "customer name", "contact", "contact name", "client", "client name"}},
+	{Key: "email", Label: "Email", Aliases: []string{"e-mail", "email address", "mail"}},
+	{Key: "phone", Label: "Phone", Aliases: []string{"phone number", "telephone", "tel", "mobile", "cell", "primary phone"}},
+	{Key: "company_name", Label: "Company", Aliases: []string{"company", "business", "business name", "organization"}},
+	{Key: "customer_type", Label: "Customer Type", Aliases: []string{"type", "account type"}},
+	{Key: "notes", Label: "Notes", Aliases: []string{"note", "comments", "comment"}},
+	{Key: "address_line1", Label: "Street Address", Aliases: []string{"address", "street", "address 1", "service address", "property address"}},
+	{Key: "address_line2", Label: "Address Line 2", Aliases: []string{"address 2", "unit", "suite", "apt"}},
+	{Key: "city", Label: "City", Aliases: []string{"town"}},
+	{Key: "state", Label: "State", Aliases: []string{"province", "region", "st"}},
+	{Key: "zip_code", Label: "ZIP Code", Aliases: []string{"zip", "postal code", "postcode", "zipcode"}},
+	{Key: "property_name", Label: "Property Name", Aliases: []string{"property", "site", "site name"}},
+	{Key: "property_type", Label: "Property Type"},
+	{Key: "lot_size", Label: "Lot Size", Aliases: []string{"lot", "acreage", "lot size sq ft"}},
+}
+
+// ImportDuplicateThreshold is the score above which a row is treated as an existing customer
+const ImportDuplicateThreshold = 0.85
+
+const (
+	importBatchSize     = 200
+	importTickBudget    = 20 * time.Second
+	importReportIssues  = 200
+	importGeocodeBatch  = 100
+	importActiveImports = 5
+)
+
+// importServiceImpl implements ImportService
+type importServiceImpl struct {
+	importRepo   ImportRepository
+	customerRepo CustomerRepository
+	propertyRepo PropertyRepositoryExtended
+	geocoder     PropertyGeocoder
+	validator    *security.InputValidator
+	auditService AuditService
+	logger       *log.Logger
+}
+
+// NewImportService creates a new import service
+func NewImportService(
+	importRepo ImportRepository,
+	customerRepo CustomerRepository,
+	propertyRepo PropertyRepositoryExtended,
+	geocoder PropertyGeocoder,
+	auditService AuditService,
+	logger *log.Logger,
+) ImportService {
+	return &importServiceImpl{
+		importRepo:   importRepo,
+		customerRepo: customerRepo,
+		propertyRepo: propertyRepo,
+		geocoder:     geocoder,
+		validator:    security.NewInputValidator(),
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// CreateImport parses an uploaded spreadsheet and stores its rows with a suggested
+// column mapping
+func (s *importServiceImpl) CreateImport(ctx context.Context, req *ImportUploadRequest) (*domain.ImportJob, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	fileName, err := s.validator.SanitizeFilename(req.FileName)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	sheet, err := ParseImportFile(fileName, req.Content)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if len(sheet.Rows) == 0 {
+		return nil, fmt.Errorf("validation failed: the file has no data rows")
+	}
+
+	now := time.Now()
+	job := &domain.ImportJob{
+		ID:                uuid.New(),
+		TenantID:          tenantID,
+		FileName:          fileName,
+		FileFormat:        sheet.Format,
+		Headers:           sheet.Headers,
+		Mapping:           SuggestImportMapping(sheet.Headers),
+		DuplicateStrategy: domain.ImportDuplicateSkip,
+		Status:            domain.ImportStatusUploaded,
+		TotalRows:         len(sheet.Rows),
+		CreatedBy:         GetUserIDFromContext(ctx),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	rows := make([]*domain.ImportRow, 0, len(sheet.Rows))
+	for i, cells := range sheet.Rows {
+		data := make(map[string]string, len(sheet.Headers))
+		for j, header := range sheet.Headers {
+			if value := strings.TrimSpace(cells[j]); value != "" {
+				data[header] = value
+			}
+		}
+		rows = append(rows, &domain.ImportRow{
+			ID:        uuid.New(),
+			TenantID:  tenantID,
+			ImportID:  job.ID,
+			RowNumber: i + 2,
+			Data:      data,
+			Status:    domain.ImportRowStatusPending,
+			Errors:    []string{},
+			Warnings:  []string{},
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+
+	if err := s.importRepo.CreateImport(ctx, job, rows); err != nil {
+		return nil, fmt.Errorf("failed to create import: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       job.CreatedBy,
+		Action:       "import.create",
+		ResourceType: "import",
+		ResourceID:   &job.ID,
+		NewValues: map[string]interface{}{
+			"file_name":  job.FileName,
+			"total_rows": job.TotalRows,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return job, nil
+}
+
+// GetImport retrieves an import
+func (s *importServiceImpl) GetImport(ctx context.Context, importID uuid.UUID) (*domain.ImportJob, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	job, err := s.importRepo.GetImport(ctx, tenantID, importID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import: %w", err)
+	}
+	if job == nil {
+		return nil, fmt.Errorf("import not found")
+	}
+
+	return job, nil
+}
+
+// ListImports lists the tenant's imports, newest first
+func (s *importServiceImpl) ListImports(ctx context.Context, filter *ImportFilter) (*domain.PaginatedResponse, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	// Set defaults
+	if filter == nil {
+		filter = &ImportFilter{}
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PerPage <= 0 {
+		filter.PerPage = 50
+	}
+	if filter.PerPage > 100 {
+		filter.PerPage = 100
+	}
+
+	jobs, total, err := s.importRepo.ListImports(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list imports: %w", err)
+	}
+
+	totalPages := int((total + int64(filter.PerPage) - 1) / int64(filter.PerPage))
+
+	return &domain.PaginatedResponse{
+		Data:       jobs,
+		Total:      total,
+		Page:       filter.Page,
+		PerPage:    filter.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// ListImportRows lists an import's rows in spreadsheet order
+func (s *importServiceImpl) ListImportRows(ctx context.Context, importID uuid.UUID, filter *ImportRowFilter) (*domain.PaginatedResponse, error) {
+	job, err := s.GetImport(ctx, importID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set defaults
+	if filter == nil {
+		filter = &ImportRowFilter{}
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PerPage <= 0 {
+		filter.PerPage = 50
+	}
+	if filter.PerPage > 100 {
+		filter.PerPage = 100
+	}
+
+	rows, total, err := s.importRepo.ListRows(ctx, job.TenantID, job.ID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import rows: %w", err)
+	}
+
+	totalPages := int((total + int64(filter.PerPage) - 1) / int64(filter.PerPage))
+
+	return &domain.PaginatedResponse{
+		Data:       rows,
+		Total:      total,
+		Page:       filter.Page,
+		PerPage:    filter.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// UpdateMapping changes the column mapping or duplicate handling. The import has to
+// be validated again afterwards.
+func (s *importServiceImpl) UpdateMapping(ctx context.Context, importID uuid.UUID, req *ImportMappingRequest) (*domain.ImportJob, error) {
+	job, err := s.GetImport(ctx, importID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.ImportStatusUploaded && job.Status != domain.ImportStatusValidated {
+		return nil, fmt.Errorf("the mapping can't be changed once the import has started")
+	}
+
+	if req.Mapping != nil {
+		mapping := make(map[string]string, len(req.Mapping))
+		for field, column := range req.Mapping {
+			if column == "" {
+				continue
+			}
+			if !isImportField(field) {
+				return nil, fmt.Errorf("validation failed: unknown import field %q", field)
+			}
+			if !containsString(job.Headers, column) {
+				return nil, fmt.Errorf("validation failed: the file has no column %q", column)
+			}
+			mapping[field] = column
+		}
+		job.Mapping = mapping
+	}
+	if req.DuplicateStrategy != "" {
+		switch req.DuplicateStrategy {
+		case domain.ImportDuplicateSkip, domain.ImportDuplicateMerge, domain.ImportDuplicateCreate:
+			job.DuplicateStrategy = req.DuplicateStrategy
+		default:
+			return nil, fmt.Errorf("validation failed: duplicate strategy must be skip, merge or create")
+		}
+	}
+	if err := validateImportMapping(job.Mapping); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	job.Status = domain.ImportStatusUploaded
+	job.ValidatedAt = nil
+	job.UpdatedAt = time.Now()
+	if err := s.importRepo.UpdateImport(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to update import: %w", err)
+	}
+
+	return job, nil
+}
+
+// ValidateImport is the dry run: every row is mapped and validated and checked for
+// duplicates against existing customers and earlier rows, without changing any
+// customer data
+func (s *importServiceImpl) ValidateImport(ctx context.Context, importID uuid.UUID) (*ImportValidationReport, error) {
+	job, err := s.GetImport(ctx, importID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.ImportStatusUploaded && job.Status != domain.ImportStatusValidated {
+		return nil, fmt.Errorf("the import has already started")
+	}
+	if err := validateImportMapping(job.Mapping); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	index, err := s.customerIndex(ctx, job.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportValidationReport{
+		ImportID:        job.ID,
+		FieldErrors:     map[string]int{},
+		UnmappedColumns: unmappedImportColumns(job.Headers, job.Mapping),
+		Issues:          []ImportRowIssue{},
+	}
+	statuses := []string{
+		domain.ImportRowStatusPending, domain.ImportRowStatusValid,
+		domain.ImportRowStatusInvalid, domain.ImportRowStatusDuplicate,
+	}
+
+	// Rows seen earlier in the file, so a repeated contact is flagged once
+	seen := newImportCustomerIndex(nil)
+	seenRows := map[uuid.UUID]int{}
+
+	afterRow := 0
+	for {
+		rows, err := s.importRepo.ListRowBatch(ctx, job.TenantID, job.ID, statuses, afterRow, importBatchSize*5)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list import rows: %w", err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		for _, row := range rows {
+			afterRow = row.RowNumber
+			record := MapImportRow(job.Mapping, row.Data)
+			row.Errors, row.Warnings = ValidateImportRecord(s.validator, record)
+			row.DuplicateOfID, row.DuplicateScore = nil, nil
+			row.UpdatedAt = time.Now()
+
+			issue := ImportRowIssue{RowNumber: row.RowNumber, Errors: row.Errors, Warnings: row.Warnings}
+			switch {
+			case len(row.Errors) > 0:
+				row.Status = domain.ImportRowStatusInvalid
+				report.InvalidRows++
+				for _, message := range row.Errors {
+					report.FieldErrors[strings.SplitN(message, ":", 2)[0]]++
+				}
+			default:
+				row.Status = domain.ImportRowStatusValid
+				report.ValidRows++
+				if customer, score := FindDuplicateCustomer(record, index.candidates(record)); customer != nil {
+					row.Status = domain.ImportRowStatusDuplicate
+					row.DuplicateOfID = &customer.ID
+					row.DuplicateScore = &score
+					issue.DuplicateOfID = &customer.ID
+					issue.DuplicateName = customerDisplayName(customer)
+					issue.DuplicateScore = score
+					report.DuplicateRows++
+				} else if earlier, _ := FindDuplicateCustomer(record, seen.candidates(record)); earlier != nil {
+					row.Warnings = append(row.Warnings, fmt.Sprintf("row: same contact as row %d; it will be treated as a duplicate of that row", seenRows[earlier.ID]))
+				}
+				if record.HasAddress() {
+					report.RowsWithAddress++
+				}
+				customer := importRecordCustomer(record)
+				seenRows[customer.ID] = row.RowNumber
+				seen.add(customer)
+			}
+			issue.Status = row.Status
+			issue.Warnings = row.Warnings
+
+			if (row.Status != domain.ImportRowStatusValid || len(row.Warnings) > 0) && len(report.Issues) < importReportIssues {
+				report.Issues = append(report.Issues, issue)
+			}
+		}
+
+		if err := s.importRepo.UpdateRows(ctx, rows); err != nil {
+			return nil, fmt.Errorf("failed to save validation results: %w", err)
+		}
+	}
+
+	now := time.Now()
+	report.TotalRows = report.ValidRows + report.InvalidRows
+	report.ValidRows -= report.DuplicateRows
+	job.ValidRows = report.ValidRows
+	job.InvalidRows = report.InvalidRows
+	job.DuplicateRows = report.DuplicateRows
+	job.Status = domain.ImportStatusValidated
+	job.ValidatedAt = &now
+	job.UpdatedAt = now
+	if err := s.importRepo.UpdateImport(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to update import: %w", err)
+	}
+
+	return report, nil
+}
+
+// StartImport queues a validated import for background processing. Invalid rows are
+// left out; duplicates are handled by the import's duplicate strategy.
+func (s *importServiceImpl) StartImport(ctx context.Context, importID uuid.UUID) (*domain.ImportJob, error) {
+	job, err := s.GetImport(ctx, importID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.ImportStatusValidated {
+		return nil, fmt.Errorf("run the dry-run validation before starting the import")
+	}
+	if job.ValidRows+job.DuplicateRows == 0 {
+		return nil, fmt.Errorf("the import has no valid rows")
+	}
+
+	now := time.Now()
+	job.Status = domain.ImportStatusProcessing
+	job.StartedAt = &now
+	job.Error = nil
+	job.UpdatedAt = now
+	if err := s.importRepo.UpdateImport(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to start import: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "import.start",
+		ResourceType: "import",
+		ResourceID:   &job.ID,
+		NewValues: map[string]interface{}{
+			"valid_rows":         job.ValidRows,
+			"duplicate_rows":     job.DuplicateRows,
+			"duplicate_strategy": job.DuplicateStrategy,
+			"mapping":            job.Mapping,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return job, nil
+}
+
+// RollbackImport undoes an import: properties and customers it created are deleted
+// and blank fields it filled in on existing customers are cleared again. Records
+// that have since been used elsewhere may fail to delete; those rows are marked
+// failed and the rest of the rollback continues.
+func (s *importServiceImpl) RollbackImport(ctx context.Context, importID uuid.UUID) (*domain.ImportJob, error) {
+	job, err := s.GetImport(ctx, importID)
+	if err != nil {
+		return nil, err
+	}
+	switch job.Status {
+	case domain.ImportStatusProcessing, domain.ImportStatusGeocoding, domain.ImportStatusCompleted, domain.ImportStatusFailed:
+	default:
+		return nil, fmt.Errorf("only started imports can be rolled back")
+	}
+
+	// Stop background processing before undoing its work
+	oldStatus := job.Status
+	job.Status = domain.ImportStatusRolledBack
+	job.UpdatedAt = time.Now()
+	if err := s.importRepo.UpdateImport(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to update import: %w", err)
+	}
+
+	deletedCustomers, deletedProperties, failures := 0, 0, 0
+	afterRow := 0
+	for {
+		rows, err := s.importRepo.ListRowBatch(ctx, job.TenantID, job.ID, []string{domain.ImportRowStatusImported}, afterRow, importBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list import rows: %w", err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		for _, row := range rows {
+			afterRow = row.RowNumber
+			if err := s.rollbackRow(ctx, job.TenantID, row); err != nil {
+				s.logger.Printf("Failed to roll back import %s row %d: %v", job.ID, row.RowNumber, err)
+				row.Status = domain.ImportRowStatusFailed
+				row.Errors = append(row.Errors, "rollback: "+err.Error())
+				failures++
+			} else {
+				if row.CreatedProperty {
+					deletedProperties++
+				}
+				if row.CreatedCustomer {
+					deletedCustomers++
+				}
+				row.Status = domain.ImportRowStatusRolledBack
+			}
+			row.UpdatedAt = time.Now()
+		}
+
+		if err := s.importRepo.UpdateRows(ctx, rows); err != nil {
+			return nil, fmt.Errorf("failed to save rollback results: %w", err)
+		}
+	}
+
+	now := time.Now()
+	job.RolledBackAt = &now
+	job.UpdatedAt = now
+	if failures > 0 {
+		message := fmt.Sprintf("%d rows could not be rolled back", failures)
+		job.Error = &message
+	}
+	if err := s.importRepo.UpdateImport(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to update import: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "import.rollback",
+		ResourceType: "import",
+		ResourceID:   &job.ID,
+		OldValues:    map[string]interface{}{"status": oldStatus},
+		NewValues: map[string]interface{}{
+			"deleted_customers":  deletedCustomers,
+			"deleted_properties": deletedProperties,
+			"failed_rows":        failures,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return job, nil
+}
+
+// ProcessImports works through running imports in batches. Progress is saved after
+// every batch, so an interrupted import resumes where it stopped. It is called
+// periodically by the worker.
+func (s *importServiceImpl) ProcessImports(ctx context.Context, now time.Time) error {
+	jobs, err := s.importRepo.ListActiveImports(ctx, importActiveImports)
+	if err != nil {
+		return fmt.Errorf("failed to list active imports: %w", err)
+	}
+
+	deadline := time.Now().Add(importTickBudget)
+	for _, job := range jobs {
+		tenantCtx := context.WithValue(ctx, "tenant_id", job.TenantID)
+		if job.CreatedBy != nil {
+			tenantCtx = context.WithValue(tenantCtx, "user_id", *job.CreatedBy)
+		}
+
+		var err error
+		switch job.Status {
+		case domain.ImportStatusProcessing:
+			err = s.processRows(tenantCtx, job, deadline)
+		case domain.ImportStatusGeocoding:
+			err = s.geocodeImport(tenantCtx, job)
+		}
+		if err != nil {
+			s.logger.Printf("Failed to process import %s: %v", job.ID, err)
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// SuggestImportMapping matches spreadsheet headers to import fields by name
+func SuggestImportMapping(headers []string) map[string]string {
+	mapping := map[string]string{}
+	for _, field := range ImportFields {
+		names := append([]string{field.Key, field.Label}, field.Aliases...)
+		for _, header := range headers {
+			if _, taken := mappedImportField(mapping, header); taken {
+				continue
+			}
+			key := importHeaderKey(header)
+			for _, name := range names {
+				if key == importHeaderKey(name) {
+					mapping[field.Key] = header
+					break
+				}
+			}
+			if _, ok := mapping[field.Key]; ok {
+				break
+			}
+		}
+	}
+
+	// A full name column is only needed when first and last name aren't both mapped
+	if mapping["first_name"] != "" && mapping["last_name"] != "" {
+		delete(mapping, "full_name")
+	}
+
+	return mapping
+}
+
+// MapImportRow maps a row's cells to import fields. A full name is split on the last
+// space when first and last name aren't mapped.
+func MapImportRow(mapping map[string]string, data map[string]string) *ImportRecord {
+	value := func(field string) string {
+		if column, ok := mapping[field]; ok {
+			return strings.Join(strings.Fields(data[column]), " ")
+		}
+		return ""
+	}
+
+	record := &ImportRecord{
+		FirstName:    value("first_name"),
+		LastName:     value("last_name"),
+		Email:        value("email"),
+		Phone:        value("phone"),
+		CompanyName:  value("company_name"),
+		CustomerType: value("customer_type"),
+		Notes:        strings.TrimSpace(data[mapping["notes"]]), // notes keep their line breaks
+		AddressLine1: value("address_line1"),
+		AddressLine2: value("address_line2"),
+		City:         value("city"),
+		State:        value("state"),
+		ZipCode:      value("zip_code"),
+		PropertyName: value("property_name"),
+		PropertyType: value("property_type"),
+		LotSize:      value("lot_size"),
+	}
+
+	if fullName := value("full_name"); fullName != "" && record.FirstName == "" && record.LastName == "" {
+		// "Last, First" as exported by many tools
+		if parts := strings.SplitN(fullName, ",", 2); len(parts) == 2 {
+			record.LastName, record.FirstName = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		} else if i := strings.LastIndex(fullName, " "); i > 0 {
+			record.FirstName, record.LastName = fullName[:i], fullName[i+1:]
+		} else {
+			record.LastName = fullName
+		}
+	}
+
+	return record
+}
+
+// ValidateImportRecord checks a mapped row and normalizes it in place. Emails and
+// phone numbers go through the security input validator. Each error and warning is
+// prefixed with its field, e.g. "email: invalid email format".
+func ValidateImportRecord(validator *security.InputValidator, record *ImportRecord) (errs []string, warnings []string) {
+	errs, warnings = []string{}, []string{}
+
+	if record.LastName == "" && record.CompanyName != "" {
+		record.LastName = record.CompanyName
+		warnings = append(warnings, "last_name: missing; using the company name")
+	}
+	if record.LastName == "" {
+		errs = append(errs, "last_name: a last name or company name is required")
+	}
+
+	if record.Email != "" {
+		email, err := validator.ValidateEmail(record.Email)
+		if err != nil {
+			errs = append(errs, "email: "+err.Error())
+		} else {
+			record.Email = email
+		}
+	}
+	if record.Phone != "" {
+		phone, err := validator.ValidatePhoneNumber(record.Phone)
+		if err != nil {
+			errs = append(errs, "phone: "+err.Error())
+		} else {
+			record.Phone = phone
+		}
+	}
+	if record.Email == "" && record.Phone == "" {
+		warnings = append(warnings, "contact: no email or phone number")
+	}
+
+	switch strings.ToLower(record.CustomerType) {
+	case "", "residential", "residence", "home", "homeowner":
+		record.CustomerType = "residential"
+	case "commercial", "business", "hoa", "municipal":
+		record.CustomerType = "commercial"
+	default:
+		warnings = append(warnings, fmt.Sprintf("customer_type: %q is not residential or commercial; using residential", record.CustomerType))
+		record.CustomerType = "residential"
+	}
+	if record.CustomerType == "residential" && record.CompanyName != "" && record.LastName == record.CompanyName {
+		record.CustomerType = "commercial"
+	}
+
+	if record.HasAddress() {
+		if record.AddressLine1 == "" || record.City == "" || record.State == "" || record.ZipCode == "" {
+			errs = append(errs, "address: street address, city, state and ZIP code are all required for a property")
+		}
+		record.State = strings.ToUpper(record.State)
+		if len(record.ZipCode) == 4 && importDigits(record.ZipCode) == record.ZipCode {
+			// Spreadsheets drop the leading zero of New England ZIP codes
+			record.ZipCode = "0" + record.ZipCode
+			warnings = append(warnings, "zip_code: restored a leading zero")
+		}
+		if record.ZipCode != "" && !importZipPattern.MatchString(record.ZipCode) {
+			warnings = append(warnings, fmt.Sprintf("zip_code: %q is not a US ZIP code", record.ZipCode))
+		}
+	} else if record.PropertyName != "" || record.LotSize != "" {
+		warnings = append(warnings, "address: property details without an address are ignored")
+	}
+
+	if record.LotSize != "" {
+		if size, err := strconv.ParseFloat(strings.ReplaceAll(record.LotSize, ",", ""), 64); err != nil || size < 0 {
+			errs = append(errs, fmt.Sprintf("lot_size: %q is not a number", record.LotSize))
+		}
+	}
+	if record.PropertyType != "" {
+		record.PropertyType = strings.ToLower(record.PropertyType)
+		if record.PropertyType != "residential" && record.PropertyType != "commercial" {
+			warnings = append(warnings, fmt.Sprintf("property_type: %q is not residential or commercial; using the customer type", record.PropertyType))
+			record.PropertyType = ""
+		}
+	}
+
+	return errs, warnings
+}
+
+// FindDuplicateCustomer returns the existing customer that best matches a row, with
+// its score from 0 to 1, when the score reaches ImportDuplicateThreshold. A matching
+// email or phone number is a duplicate; otherwise the name must be very similar and
+// the address or ZIP code must agree, since names alone are often shared.
+func FindDuplicateCustomer(record *ImportRecord, candidates []*domain.EnhancedCustomer) (*domain.EnhancedCustomer, float64) {
+	var best *domain.EnhancedCustomer
+	bestScore := 0.0
+
+	email := strings.ToLower(record.Email)
+	phone := leadPhoneDigits(record.Phone)
+	name := record.FullName()
+
+	for _, candidate := range candidates {
+		score := 0.0
+		switch {
+		case email != "" && strings.EqualFold(stringValue(candidate.Email), email):
+			score = 1
+		case phone != "" && leadPhoneDigits(stringValue(candidate.Phone)) == phone:
+			score = 0.95
+		default:
+			nameScore := ImportSimilarity(name, strings.TrimSpace(candidate.FirstName+" "+candidate.LastName))
+			if record.CompanyName != "" && candidate.CompanyName != nil {
+				nameScore = maxFloat(nameScore, ImportSimilarity(record.CompanyName, *candidate.CompanyName))
+			}
+
+			addressScore := 0.0
+			if record.AddressLine1 != "" && candidate.AddressLine1 != nil {
+				addressScore = ImportSimilarity(record.AddressLine1, *candidate.AddressLine1)
+			}
+			if record.ZipCode != "" && candidate.ZipCode != nil && strings.HasPrefix(*candidate.ZipCode, record.ZipCode[:minInt(5, len(record.ZipCode))]) {
+				addressScore = maxFloat(addressScore, 0.9)
+			}
+
+			score = nameScore*0.6 + addressScore*0.4
+		}
+
+		if score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+
+	if best == nil || bestScore < ImportDuplicateThreshold {
+		return nil, 0
+	}
+	return best, float64(int(bestScore*1000+0.5)) / 1000
+}
+
+// ImportSimilarity compares two names or addresses from 0 (different) to 1 (same),
+// ignoring case, punctuation, word order and common street abbreviations
+func ImportSimilarity(a, b string) float64 {
+	a, b = importNormalize(a), importNormalize(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	distance := levenshtein([]rune(a), []rune(b))
+	longest := len([]rune(a))
+	if n := len([]rune(b)); n > longest {
+		longest = n
+	}
+	return 1 - float64(distance)/float64(longest)
+}
+
+// Helper functions
+
+func (s *importServiceImpl) processRows(ctx context.Context, job *domain.ImportJob, deadline time.Time) error {
+	statuses := []string{domain.ImportRowStatusValid, domain.ImportRowStatusDuplicate}
+
+	for time.Now().Before(deadline) {
+		rows, err := s.importRepo.ListRowBatch(ctx, job.TenantID, job.ID, statuses, 0, importBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list import rows: %w", err)
+		}
+
+		if len(rows) == 0 {
+			job.Status = domain.ImportStatusGeocoding
+			job.UpdatedAt = time.Now()
+			if err := s.importRepo.UpdateImport(ctx, job); err != nil {
+				return fmt.Errorf("failed to update import: %w", err)
+			}
+			return s.geocodeImport(ctx, job)
+		}
+
+		for _, row := range rows {
+			s.importRow(ctx, job, row)
+			job.ProcessedRows++
+		}
+
+		if err := s.importRepo.UpdateRows(ctx, rows); err != nil {
+			return fmt.Errorf("failed to save import progress: %w", err)
+		}
+
+		// Rolled back or otherwise stopped while this batch ran
+		current, err := s.importRepo.GetImport(ctx, job.TenantID, job.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get import: %w", err)
+		}
+		if current == nil || current.Status != domain.ImportStatusProcessing {
+			return nil
+		}
+
+		job.UpdatedAt = time.Now()
+		if err := s.importRepo.UpdateImport(ctx, job); err != nil {
+			return fmt.Errorf("failed to update import: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// importRow creates or merges the row's customer and property, recording the outcome
+// on the row
+func (s *importServiceImpl) importRow(ctx context.Context, job *domain.ImportJob, row *domain.ImportRow) {
+	record := MapImportRow(job.Mapping, row.Data)
+	if errs, _ := ValidateImportRecord(s.validator, record); len(errs) > 0 {
+		row.Status = domain.ImportRowStatusFailed
+		row.Errors = errs
+		job.FailedRows++
+		return
+	}
+	row.UpdatedAt = time.Now()
+
+	existing, err := s.existingCustomer(ctx, job.TenantID, record, row)
+	if err != nil {
+		s.failRow(job, row, err)
+		return
+	}
+
+	customer := existing
+	switch {
+	case existing != nil && job.DuplicateStrategy == domain.ImportDuplicateSkip:
+		row.Status = domain.ImportRowStatusSkipped
+		row.CustomerID = &existing.ID
+		return
+	case existing != nil && job.DuplicateStrategy == domain.ImportDuplicateMerge:
+		row.FilledFields = fillImportCustomer(existing, record)
+		if len(row.FilledFields) > 0 {
+			existing.UpdatedAt = time.Now()
+			if err := s.customerRepo.Update(ctx, existing); err != nil {
+				s.failRow(job, row, fmt.Errorf("failed to update customer: %w", err))
+				return
+			}
+			job.UpdatedCustomers++
+		}
+	default:
+		customer = importRecordCustomer(record)
+		customer.TenantID = job.TenantID
+		if err := s.customerRepo.Create(ctx, customer); err != nil {
+			s.failRow(job, row, fmt.Errorf("failed to create customer: %w", err))
+			return
+		}
+		row.CreatedCustomer = true
+		job.CreatedCustomers++
+	}
+	row.CustomerID = &customer.ID
+
+	if record.HasAddress() {
+		property, created, err := s.importProperty(ctx, job.TenantID, customer, record, !row.CreatedCustomer)
+		if err != nil {
+			// The customer stays with the row so a rollback removes it
+			row.Status = domain.ImportRowStatusImported
+			row.Warnings = append(row.Warnings, "property: "+err.Error())
+			return
+		}
+		row.PropertyID = &property.ID
+		row.CreatedProperty = created
+		if created {
+			job.CreatedProperties++
+		}
+	}
+
+	row.Status = domain.ImportRowStatusImported
+}
+
+// existingCustomer finds the customer a row duplicates: the match found during
+// validation, or one with the same email or phone, which catches rows repeated
+// within the file
+func (s *importServiceImpl) existingCustomer(ctx context.Context, tenantID uuid.UUID, record *ImportRecord, row *domain.ImportRow) (*domain.EnhancedCustomer, error) {
+	if row.DuplicateOfID != nil {
+		customer, err := s.customerRepo.GetByID(ctx, tenantID, *row.DuplicateOfID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get customer: %w", err)
+		}
+		if customer != nil {
+			return customer, nil
+		}
+	}
+	if record.Email != "" {
+		if customer, err := s.customerRepo.GetByEmail(ctx, tenantID, record.Email); err == nil && customer != nil {
+			return customer, nil
+		}
+	}
+	if record.Phone != "" {
+		if customer, err := s.customerRepo.GetByPhone(ctx, tenantID, record.Phone); err == nil && customer != nil {
+			return customer, nil
+		}
+	}
+	return nil, nil
+}
+
+// importProperty adds the row's property, reusing one of an existing customer's
+// properties at the same address. Coordinates are filled in by the geocoding stage.
+func (s *importServiceImpl) importProperty(ctx context.Context, tenantID uuid.UUID, customer *domain.EnhancedCustomer, record *ImportRecord, checkExisting bool) (*domain.EnhancedProperty, bool, error) {
+	if checkExisting {
+		properties, err := s.propertyRepo.GetByCustomerID(ctx, tenantID, customer.ID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get customer properties: %w", err)
+		}
+		for _, property := range properties {
+			if ImportSimilarity(property.AddressLine1, record.AddressLine1) >= 0.9 && property.ZipCode == record.ZipCode {
+				return property, false, nil
+			}
+		}
+	}
+
+	name := record.PropertyName
+	if name == "" {
+		name = record.AddressLine1
+	}
+	propertyType := record.PropertyType
+	if propertyType == "" {
+		propertyType = customer.CustomerType
+	}
+	if propertyType != "commercial" {
+		propertyType = "residential"
+	}
+	var lotSize *float64
+	if size, err := strconv.ParseFloat(strings.ReplaceAll(record.LotSize, ",", ""), 64); err == nil && record.LotSize != "" {
+		lotSize = &size
+	}
+
+	now := time.Now()
+	property := &domain.EnhancedProperty{
+		Property: domain.Property{
+			ID:           uuid.New(),
+			TenantID:     tenantID,
+			CustomerID:   customer.ID,
+			Name:         name,
+			AddressLine1: record.AddressLine1,
+			AddressLine2: optionalString(record.AddressLine2),
+			City:         record.City,
+			State:        record.State,
+			ZipCode:      record.ZipCode,
+			Country:      "US",
+			PropertyType: propertyType,
+			LotSize:      lotSize,
+			Status:       "active",
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		},
+	}
+	if err := s.propertyRepo.Create(ctx, property); err != nil {
+		return nil, false, fmt.Errorf("failed to create property: %w", err)
+	}
+
+	return property, true, nil
+}
+
+// geocodeImport geocodes the properties the import created, then completes it
+func (s *importServiceImpl) geocodeImport(ctx context.Context, job *domain.ImportJob) error {
+	if s.geocoder != nil && job.CreatedProperties > 0 {
+		for batches := job.CreatedProperties/importGeocodeBatch + 1; batches > 0; batches-- {
+			remaining, err := s.propertyRepo.GetPropertiesNeedingGeocoding(ctx, job.TenantID, 1)
+			if err != nil {
+				return fmt.Errorf("failed to get properties needing geocoding: %w", err)
+			}
+			if len(remaining) == 0 {
+				break
+			}
+			if err := s.geocoder.BatchGeocodeProperties(ctx, importGeocodeBatch); err != nil {
+				return fmt.Errorf("failed to geocode properties: %w", err)
+			}
+		}
+	}
+
+	now := time.Now()
+	job.GeocodedAt = &now
+	job.Status = domain.ImportStatusCompleted
+	job.CompletedAt = &now
+	job.UpdatedAt = now
+	if err := s.importRepo.UpdateImport(ctx, job); err != nil {
+		return fmt.Errorf("failed to update import: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       job.CreatedBy,
+		Action:       "import.complete",
+		ResourceType: "import",
+		ResourceID:   &job.ID,
+		NewValues: map[string]interface{}{
+			"created_customers":  job.CreatedCustomers,
+			"updated_customers":  job.UpdatedCustomers,
+			"created_properties": job.CreatedProperties,
+			"failed_rows":        job.FailedRows,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return nil
+}
+
+func (s *importServiceImpl) rollbackRow(ctx context.Context, tenantID uuid.UUID, row *domain.ImportRow) error {
+	if row.CreatedProperty && row.PropertyID != nil {
+		if err := s.propertyRepo.Delete(ctx, tenantID, *row.PropertyID); err != nil {
+			return fmt.Errorf("failed to delete property: %w", err)
+		}
+	}
+
+	if row.CustomerID == nil {
+		return nil
+	}
+	if row.CreatedCustomer {
+		if err := s.customerRepo.Delete(ctx, tenantID, *row.CustomerID); err != nil {
+			return fmt.Errorf("failed to delete customer: %w", err)
+		}
+		return nil
+	}
+
+	if len(row.FilledFields) > 0 {
+		customer, err := s.customerRepo.GetByID(ctx, tenantID, *row.CustomerID)
+		if err != nil {
+			return fmt.Errorf("failed to get customer: %w", err)
+		}
+		if customer == nil {
+			return nil
+		}
+		clearImportCustomerFields(customer, row.FilledFields)
+		customer.UpdatedAt = time.Now()
+		if err := s.customerRepo.Update(ctx, customer); err != nil {
+			return fmt.Errorf("failed to update customer: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *importServiceImpl) failRow(job *domain.ImportJob, row *domain.ImportRow, err error) {
+	s.logger.Printf("Failed to import row %d of import %s: %v", row.RowNumber, job.ID, err)
+	row.Status = domain.ImportRowStatusFailed
+	row.Errors = append(row.Errors, err.Error())
+	job.FailedRows++
+}
+
+// customerIndex loads the tenant's customers for duplicate detection
+func (s *importServiceImpl) customerIndex(ctx context.Context, tenantID uuid.UUID) (*importCustomerIndex, error) {
+	var customers []*domain.EnhancedCustomer
+	filter := &CustomerFilter{BaseFilter: BaseFilter{Page: 1, PerPage: 500}}
+	for {
+		page, total, err := s.customerRepo.List(ctx, tenantID, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list customers: %w", err)
+		}
+		customers = append(customers, page...)
+		if len(page) < filter.PerPage || int64(len(customers)) >= total {
+			break
+		}
+		filter.Page++
+	}
+	return newImportCustomerIndex(customers), nil
+}
+
+// importCustomerIndex narrows duplicate candidates to customers sharing an email,
+// phone, ZIP code or the start of the last name
+type importCustomerIndex struct {
+	byKey map[string][]*domain.EnhancedCustomer
+}
+
+func newImportCustomerIndex(customers []*domain.EnhancedCustomer) *importCustomerIndex {
+	index := &importCustomerIndex{byKey: map[string][]*domain.EnhancedCustomer{}}
+	for _, customer := range customers {
+		index.add(customer)
+	}
+	return index
+}
+
+func (i *importCustomerIndex) add(customer *domain.EnhancedCustomer) {
+	for _, key := range importBlockingKeys(stringValue(customer.Email), stringValue(customer.Phone), stringValue(customer.ZipCode), customer.LastName) {
+		i.byKey[key] = append(i.byKey[key], customer)
+	}
+}
+
+func (i *importCustomerIndex) candidates(record *ImportRecord) []*domain.EnhancedCustomer {
+	seen := map[uuid.UUID]bool{}
+	var candidates []*domain.EnhancedCustomer
+	for _, key := range importBlockingKeys(record.Email, record.Phone, record.ZipCode, record.LastName) {
+		for _, customer := range i.byKey[key] {
+			if !seen[customer.ID] {
+				seen[customer.ID] = true
+				candidates = append(candidates, customer)
+			}
+		}
+	}
+	return candidates
+}
+
+func importBlockingKeys(email, phone, zipCode, lastName string) []string {
+	var keys []string
+	if email != "" {
+		keys = append(keys, "e:"+strings.ToLower(email))
+	}
+	if digits := leadPhoneDigits(phone); digits != "" {
+		keys = append(keys, "p:"+digits)
+	}
+	if len(zipCode) >= 5 {
+		keys = append(keys, "z:"+zipCode[:5])
+	}
+	if name := importNormalize(lastName); name != "" {
+		runes := []rune(name)
+		keys = append(keys, "n:"+string(runes[:minInt(2, len(runes))]))
+	}
+	return keys
+}
+
+// importRecordCustomer builds a new customer from a row
+func importRecordCustomer(record *ImportRecord) *domain.EnhancedCustomer {
+	now := time.Now()
+	contactMethod := "email"
+	if record.Email == "" && record.Phone != "" {
+		contactMethod = "phone"
+	}
+	source := "import"
+	customer := &domain.EnhancedCustomer{
+		Customer: domain.Customer{
+			ID:           uuid.New(),
+			FirstName:    record.FirstName,
+			LastName:     record.LastName,
+			Email:        optionalString(record.Email),
+			Phone:        optionalString(record.Phone),
+			AddressLine1: optionalString(record.AddressLine1),
+			AddressLine2: optionalString(record.AddressLine2),
+			City:         optionalString(record.City),
+			State:        optionalString(record.State),
+			ZipCode:      optionalString(record.ZipCode),
+			Country:      "US",
+			Notes:        optionalString(record.Notes),
+			Status:       "active",
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		},
+		CompanyName:            optionalString(record.CompanyName),
+		PreferredContactMethod: contactMethod,
+		LeadSource:             &source,
+		CustomerType:           record.CustomerType,
+		PaymentTerms:           30,
+	}
+	if customer.CustomerType == "" {
+		customer.CustomerType = "residential"
+	}
+	return customer
+}
+
+// fillImportCustomer copies row values into the customer's blank fields, returning
+// the fields it filled
+func fillImportCustomer(customer *domain.EnhancedCustomer, record *ImportRecord) []string {
+	filled := []string{}
+	fill := func(name string, field **string, value string) {
+		if value != "" && stringValue(*field) == "" {
+			v := value
+			*field = &v
+			filled = append(filled, name)
+		}
+	}
+	fill("email", &customer.Email, record.Email)
+	fill("phone", &customer.Phone, record.Phone)
+	fill("company_name", &customer.CompanyName, record.CompanyName)
+	fill("address_line1", &customer.AddressLine1, record.AddressLine1)
+	fill("address_line2", &customer.AddressLine2, record.AddressLine2)
+	fill("city", &customer.City, record.City)
+	fill("state", &customer.State, record.State)
+	fill("zip_code", &customer.ZipCode, record.ZipCode)
+	fill("notes", &customer.Notes, record.Notes)
+	return filled
+}
+
+func clearImportCustomerFields(customer *domain.EnhancedCustomer, fields []string) {
+	for _, field := range fields {
+		switch field {
+		case "email":
+			customer.Email = nil
+		case "phone":
+			customer.Phone = nil
+		case "company_name":
+			customer.CompanyName = nil
+		case "address_line1":
+			customer.AddressLine1 = nil
+		case "address_line2":
+			customer.AddressLine2 = nil
+		case "city":
+			customer.City = nil
+		case "state":
+			customer.State = nil
+		case "zip_code":
+			customer.ZipCode = nil
+		case "notes":
+			customer.Notes = nil
+		}
+	}
+}
+
+func validateImportMapping(mapping map[string]string) error {
+	if mapping["last_name"] == "" && mapping["full_name"] == "" && mapping["company_name"] == "" {
+		return fmt.Errorf("map a last name, full name or company column")
+	}
+	columns := map[string]string{}
+	for field, column := range mapping {
+		if other, ok := columns[column]; ok {
+			return fmt.Errorf("column %q is mapped to both %s and %s", column, other, field)
+		}
+		columns[column] = field
+	}
+	return nil
+}
+
+func isImportField(key string) bool {
+	for _, field := range ImportFields {
+		if field.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func mappedImportField(mapping map[string]string, column string) (string, bool) {
+	for field, mapped := range mapping {
+		if mapped == column {
+			return field, true
+		}
+	}
+	return "", false
+}
+
+func unmappedImportColumns(headers []string, mapping map[string]string) []string {
+	unmapped := []string{}
+	for _, header := range headers {
+		if _, ok := mappedImportField(mapping, header); !ok {
+			unmapped = append(unmapped, header)
+		}
+	}
+	sort.Strings(unmapped)
+	return unmapped
+}
+
+var (
+	importNonAlnumPattern = regexp.MustCompile(`[^a-z0-9]+`)
+	importZipPattern      = regexp.MustCompile(`^\d{5}(-\d{4})?$`)
+)
+
+var importAbbreviations = map[string]string{
+	"street": "st", "avenue": "ave", "road": "rd", "drive": "dr", "lane": "ln",
+	"boulevard": "blvd", "court": "ct", "place": "pl", "circle": "cir", "highway": "hwy",
+	"north": "n", "south": "s", "east": "e", "west": "w", "apartment": "apt", "suite": "ste",
+}
+
+func importHeaderKey(header string) string {
+	return importNonAlnumPattern.ReplaceAllString(strings.ToLower(header), "")
+}
+
+// importNormalize lowercases, strips punctuation, abbreviates street words and sorts
+// the words so "Smith, John" matches "John Smith"
+func importNormalize(value string) string {
+	words := strings.Fields(importNonAlnumPattern.ReplaceAllString(strings.ToLower(value), " "))
+	for i, word := range words {
+		if short, ok := importAbbreviations[word]; ok {
+			words[i] = short
+		}
+	}
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+func importDigits(value string) string {
+	return regexp.MustCompile(`\D`).ReplaceAllString(value, "")
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
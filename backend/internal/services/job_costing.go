@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// JobCosting sets a job's revenue against what it cost to do. Fuel is charged
+// by the miles driven and hours run on the job at each equipment's fuel rate.
+type JobCosting struct {
+	JobID         uuid.UUID           `json:"job_id"`
+	Revenue       float64             `json:"revenue"`
+	FuelCost      float64             `json:"fuel_cost"`
+	Fuel          []JobFuelAllocation `json:"fuel"`
+	TotalCost     float64             `json:"total_cost"`
+	GrossProfit   float64             `json:"gross_profit"`
+	MarginPercent *float64            `json:"margin_percent,omitempty"`
+}
+
+// GetJobCosting works out a job's costs and gross profit
+func (s *JobServiceImpl) GetJobCosting(ctx context.Context, jobID uuid.UUID) (*JobCosting, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	job, err := s.jobRepo.GetByID(ctx, tenantID, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job not found")
+	}
+
+	// Fuel is priced at the rates when the job was done
+	asOf := time.Now()
+	if job.ActualEndTime != nil {
+		asOf = *job.ActualEndTime
+	}
+
+	fuel, err := allocateJobFuel(ctx, s.fuelRepo, s.meterRepo, tenantID, []uuid.UUID{job.ID}, nil, nil, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildJobCosting(job, fuel), nil
+}
+
+// BuildJobCosting totals a job's costs against its revenue
+func BuildJobCosting(job *domain.EnhancedJob, fuel []JobFuelAllocation) *JobCosting {
+	costing := &JobCosting{
+		JobID:   job.ID,
+		Revenue: roundCents(floatValue(job.TotalAmount)),
+		Fuel:    []JobFuelAllocation{},
+	}
+
+	for _, allocation := range fuel {
+		if allocation.JobID != job.ID {
+			continue
+		}
+		costing.Fuel = append(costing.Fuel, allocation)
+		costing.FuelCost += allocation.Cost
+	}
+
+	costing.FuelCost = roundCents(costing.FuelCost)
+	costing.TotalCost = costing.FuelCost
+	costing.GrossProfit = roundCents(costing.Revenue - costing.TotalCost)
+	if costing.Revenue > 0 {
+		margin := math.Round(costing.GrossProfit/costing.Revenue*1000) / 10
+		costing.MarginPercent = &margin
+	}
+
+	return costing
+}
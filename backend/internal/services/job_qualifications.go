@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// CheckJobQualifications checks whether a user, or a crew, holds the
+// certifications the job's services require, without assigning the job
+func (s *JobServiceImpl) CheckJobQualifications(ctx context.Context, jobID uuid.UUID, userID, crewID *uuid.UUID) (*QualificationCheck, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if (userID == nil) == (crewID == nil) {
+		return nil, fmt.Errorf("validation failed: either a user or a crew is required")
+	}
+
+	job, err := s.jobRepo.GetByID(ctx, tenantID, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	if job == nil {
+		return nil, fmt.Errorf("job not found")
+	}
+
+	if userID != nil {
+		return checkJobQualifications(ctx, s.jobRepo, s.certificationRepo, job, []uuid.UUID{*userID}, false)
+	}
+
+	memberIDs, err := s.crewRepo.GetActiveMemberIDs(ctx, tenantID, *crewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get crew members: %w", err)
+	}
+	return checkJobQualifications(ctx, s.jobRepo, s.certificationRepo, job, memberIDs, true)
+}
+
+// checkJobQualifications checks workers against the job's service
+// requirements as of the day the job is scheduled, or today if unscheduled
+func checkJobQualifications(ctx context.Context, jobRepo JobRepositoryComplete, certificationRepo CertificationRepository, job *domain.EnhancedJob, workerIDs []uuid.UUID, crew bool) (*QualificationCheck, error) {
+	workDate := time.Now()
+	if job.ScheduledDate != nil {
+		workDate = *job.ScheduledDate
+	}
+
+	check := &QualificationCheck{
+		JobID:     job.ID,
+		WorkDate:  workDate,
+		Qualified: true,
+		Gaps:      []QualificationGap{},
+		Warnings:  []QualificationGap{},
+	}
+	if certificationRepo == nil {
+		return check, nil
+	}
+
+	jobServices, err := jobRepo.GetJobServices(ctx, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job services: %w", err)
+	}
+	if len(jobServices) == 0 {
+		return check, nil
+	}
+	serviceIDs := make([]uuid.UUID, len(jobServices))
+	for i, jobService := range jobServices {
+		serviceIDs[i] = jobService.ServiceID
+	}
+
+	requirements, err := certificationRepo.ListServiceRequirements(ctx, job.TenantID, serviceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service requirements: %w", err)
+	}
+	if len(requirements) == 0 {
+		return check, nil
+	}
+
+	var certifications []*domain.UserCertification
+	if len(workerIDs) > 0 {
+		if certifications, err = certificationRepo.ListCertifications(ctx, job.TenantID, workerIDs); err != nil {
+			return nil, fmt.Errorf("failed to list certifications: %w", err)
+		}
+	}
+
+	check.Gaps, check.Warnings = CheckQualifications(requirements, workerIDs, certifications, workDate, crew)
+	check.Qualified = len(check.Gaps) == 0
+	return check, nil
+}
+
+// enforceJobQualifications refuses an assignment with blocking gaps, and lets
+// one with only warnings through while flagging it to whoever made it
+func (s *JobServiceImpl) enforceJobQualifications(ctx context.Context, job *domain.EnhancedJob, workerIDs []uuid.UUID, crew bool) error {
+	check, err := checkJobQualifications(ctx, s.jobRepo, s.certificationRepo, job, workerIDs, crew)
+	if err != nil {
+		return err
+	}
+
+	if !check.Qualified {
+		return fmt.Errorf("not qualified for job: %s", DescribeQualificationGaps(check.Gaps))
+	}
+	if len(check.Warnings) == 0 {
+		return nil
+	}
+
+	summary := DescribeQualificationGaps(check.Warnings)
+	s.logger.Printf("Job %s assigned with qualification warnings: %s", job.ID, summary)
+
+	if assignerID := GetUserIDFromContext(ctx); assignerID != nil {
+		if err := s.notificationService.SendNotification(ctx, &NotificationRequest{
+			UserID:  assignerID,
+			Type:    "job.qualification_warning",
+			Title:   "Assignment Missing Certifications",
+			Message: fmt.Sprintf("Job %s was assigned without: %s", job.Title, summary),
+			Data: map[string]interface{}{
+				"job_id":   job.ID,
+				"warnings": check.Warnings,
+			},
+		}); err != nil {
+			s.logger.Printf("Failed to send qualification warning for job %s: %v", job.ID, err)
+		}
+	}
+
+	return nil
+}
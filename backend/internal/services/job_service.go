@@ -21,6 +21,10 @@ type JobServiceImpl struct {
 	userRepo           UserRepository
 	crewRepo           CrewRepository
 	equipmentRepo      EquipmentRepository
+	meterRepo          EquipmentMeterRepository
+	reservationRepo    EquipmentReservationRepository
+	fuelRepo           FuelRepository
+	certificationRepo  CertificationRepository
 	auditService       AuditService
 	notificationService NotificationService
 	storageService     StorageService
@@ -72,6 +76,7 @@ type UserRepository interface {
 type CrewRepository interface {
 	GetByID(ctx context.Context, tenantID, crewID uuid.UUID) (*domain.Crew, error)
 	CheckAvailability(ctx context.Context, crewID uuid.UUID, startTime, endTime time.Time) (bool, error)
+	GetActiveMemberIDs(ctx context.Context, tenantID, crewID uuid.UUID) ([]uuid.UUID, error)
 }
 
 type EquipmentRepository interface {
@@ -88,6 +93,10 @@ func NewJobService(
 	userRepo UserRepository,
 	crewRepo CrewRepository,
 	equipmentRepo EquipmentRepository,
+	meterRepo EquipmentMeterRepository,
+	reservationRepo EquipmentReservationRepository,
+	fuelRepo FuelRepository,
+	certificationRepo CertificationRepository,
 	auditService AuditService,
 	notificationService NotificationService,
 	storageService StorageService,
@@ -102,6 +111,10 @@ func NewJobService(
 		userRepo:            userRepo,
 		crewRepo:            crewRepo,
 		equipmentRepo:       equipmentRepo,
+		meterRepo:           meterRepo,
+		reservationRepo:     reservationRepo,
+		fuelRepo:            fuelRepo,
+		certificationRepo:   certificationRepo,
 		auditService:        auditService,
 		notificationService: notificationService,
 		storageService:      storageService,
@@ -131,6 +144,9 @@ func (s *JobServiceImpl) CreateJob(ctx context.Context, req *domain.CreateJobReq
 	if customer == nil {
 		return nil, fmt.Errorf("customer not found")
 	}
+	if customer.SchedulingSuspended {
+		return nil, fmt.Errorf("scheduling is suspended for this customer due to a delinquent account")
+	}
 
 	// Verify property exists and belongs to customer
 	property, err := s.propertyRepo.GetByID(ctx, tenantID, req.PropertyID)
@@ -221,6 +237,7 @@ func (s *JobServiceImpl) CreateJob(ctx context.Context, req *domain.CreateJobReq
 		CrewSize:          req.CrewSize,
 		WeatherDependent:  req.WeatherDependent,
 		RequiresEquipment: req.RequiresEquipment,
+		PONumber:          req.PONumber,
 	}
 
 	// Save to database
@@ -229,6 +246,11 @@ func (s *JobServiceImpl) CreateJob(ctx context.Context, req *domain.CreateJobReq
 		return nil, fmt.Errorf("failed to create job: %w", err)
 	}
 
+	// Reserve the required equipment for the scheduled time
+	if err := s.reserveJobEquipment(ctx, job, nil); err != nil {
+		s.logger.Printf("Failed to reserve equipment for job %s: %v", job.ID, err)
+	}
+
 	// Create job services if specified
 	if len(req.ServiceIDs) > 0 {
 		services, err := s.serviceRepo.GetByIDs(ctx, tenantID, req.ServiceIDs)
@@ -355,6 +377,14 @@ func (s *JobServiceImpl) UpdateJob(ctx context.Context, jobID uuid.UUID, req *do
 		job.Priority = *req.Priority
 	}
 	if req.ScheduledDate != nil {
+		// Rescheduling is blocked while the customer's account is suspended
+		customer, err := s.customerRepo.GetByID(ctx, tenantID, job.CustomerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify customer: %w", err)
+		}
+		if customer != nil && customer.SchedulingSuspended {
+			return nil, fmt.Errorf("scheduling is suspended for this customer due to a delinquent account")
+		}
 		job.ScheduledDate = req.ScheduledDate
 	}
 	if req.ScheduledTime != nil {
@@ -398,9 +428,24 @@ func (s *JobServiceImpl) UpdateJob(ctx context.Context, jobID uuid.UUID, req *do
 	if req.CrewSize != nil {
 		job.CrewSize = *req.CrewSize
 	}
+	if req.RequiresEquipment != nil {
+		if len(req.RequiresEquipment) > 0 {
+			equipment, err := s.equipmentRepo.GetByIDs(ctx, tenantID, req.RequiresEquipment)
+			if err != nil {
+				return nil, fmt.Errorf("failed to verify equipment: %w", err)
+			}
+			if len(equipment) != len(req.RequiresEquipment) {
+				return nil, fmt.Errorf("one or more equipment not found")
+			}
+		}
+		job.RequiresEquipment = req.RequiresEquipment
+	}
 	if req.Notes != nil {
 		job.Notes = req.Notes
 	}
+	if req.PONumber != nil {
+		job.PONumber = req.PONumber
+	}
 
 	job.UpdatedAt = time.Now()
 
@@ -409,12 +454,21 @@ func (s *JobServiceImpl) UpdateJob(ctx context.Context, jobID uuid.UUID, req *do
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
+	// Rescheduled or re-equipped jobs must not take equipment booked elsewhere
+	if err := s.checkEquipmentReservations(ctx, job); err != nil {
+		return nil, err
+	}
+
 	// Save to database
 	if err := s.jobRepo.Update(ctx, job); err != nil {
 		s.logger.Printf("Failed to update job", "error", err, "job_id", jobID, "tenant_id", tenantID)
 		return nil, fmt.Errorf("failed to update job: %w", err)
 	}
 
+	if err := s.reserveJobEquipment(ctx, job, nil); err != nil {
+		s.logger.Printf("Failed to update equipment reservations for job %s: %v", jobID, err)
+	}
+
 	// Log audit event
 	newValues := map[string]interface{}{
 		"title":            job.Title,
@@ -471,6 +525,10 @@ func (s *JobServiceImpl) DeleteJob(ctx context.Context, jobID uuid.UUID) error {
 		return fmt.Errorf("failed to delete job: %w", err)
 	}
 
+	if err := s.releaseJobEquipment(ctx, job); err != nil {
+		s.logger.Printf("Failed to release equipment for job %s: %v", jobID, err)
+	}
+
 	// Log audit event
 	userID := GetUserIDFromContext(ctx)
 	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
@@ -648,6 +706,12 @@ func (s *JobServiceImpl) CompleteJob(ctx context.Context, jobID uuid.UUID, compl
 		return fmt.Errorf("job must be in progress to complete")
 	}
 
+	for _, usage := range completionDetails.EquipmentUsage {
+		if err := ValidateMeterReadingRequest(&usage.MeterReadingRequest); err != nil {
+			return fmt.Errorf("validation failed: equipment %s: %w", usage.EquipmentID, err)
+		}
+	}
+
 	// Update job status and end time
 	job.Status = domain.JobStatusCompleted
 	job.ActualEndTime = &completionDetails.EndTime
@@ -696,6 +760,15 @@ func (s *JobServiceImpl) CompleteJob(ctx context.Context, jobID uuid.UUID, compl
 		}
 	}
 
+	if err := s.releaseJobEquipment(ctx, job); err != nil {
+		s.logger.Printf("Failed to release equipment for job %s: %v", jobID, err)
+	}
+
+	// Record equipment meter usage for usage-based maintenance
+	if err := recordJobEquipmentUsage(ctx, s.meterRepo, job, completionDetails.EquipmentUsage); err != nil {
+		s.logger.Printf("Failed to record equipment usage for job %s: %v", jobID, err)
+	}
+
 	// Send notifications
 	if job.AssignedUserID != nil {
 		if err := s.notificationService.SendNotification(ctx, &NotificationRequest{
@@ -774,6 +847,10 @@ func (s *JobServiceImpl) CancelJob(ctx context.Context, jobID uuid.UUID, reason
 		return fmt.Errorf("failed to cancel job: %w", err)
 	}
 
+	if err := s.releaseJobEquipment(ctx, job); err != nil {
+		s.logger.Printf("Failed to release equipment for job %s: %v", jobID, err)
+	}
+
 	// Send notifications
 	if job.AssignedUserID != nil {
 		if err := s.notificationService.SendNotification(ctx, &NotificationRequest{
@@ -892,6 +969,14 @@ func (s *JobServiceImpl) AssignJob(ctx context.Context, jobID, userID uuid.UUID)
 		return fmt.Errorf("user not found")
 	}
 
+	if err := s.enforceJobQualifications(ctx, job, []uuid.UUID{userID}, false); err != nil {
+		return err
+	}
+
+	if err := s.checkEquipmentReservations(ctx, job); err != nil {
+		return err
+	}
+
 	// Update job assignment
 	oldUserID := job.AssignedUserID
 	job.AssignedUserID = &userID
@@ -902,6 +987,10 @@ func (s *JobServiceImpl) AssignJob(ctx context.Context, jobID, userID uuid.UUID)
 		return fmt.Errorf("failed to assign job: %w", err)
 	}
 
+	if err := s.reserveJobEquipment(ctx, job, nil); err != nil {
+		s.logger.Printf("Failed to reserve equipment for job %s: %v", jobID, err)
+	}
+
 	// Send notification
 	if err := s.notificationService.SendNotification(ctx, &NotificationRequest{
 		UserID:  &userID,
@@ -1035,6 +1124,18 @@ func (s *JobServiceImpl) AssignJobToCrew(ctx context.Context, jobID, crewID uuid
 		}
 	}
 
+	memberIDs, err := s.crewRepo.GetActiveMemberIDs(ctx, tenantID, crewID)
+	if err != nil {
+		return fmt.Errorf("failed to get crew members: %w", err)
+	}
+	if err := s.enforceJobQualifications(ctx, job, memberIDs, true); err != nil {
+		return err
+	}
+
+	if err := s.checkEquipmentReservations(ctx, job); err != nil {
+		return err
+	}
+
 	// Update job with crew assignment
 	// Note: This would require adding CrewID to the job model
 	job.UpdatedAt = time.Now()
@@ -1044,6 +1145,11 @@ func (s *JobServiceImpl) AssignJobToCrew(ctx context.Context, jobID, crewID uuid
 		return fmt.Errorf("failed to assign job to crew: %w", err)
 	}
 
+	// The crew takes the job's equipment out with it
+	if err := s.reserveJobEquipment(ctx, job, &crewID); err != nil {
+		s.logger.Printf("Failed to reserve equipment for job %s: %v", jobID, err)
+	}
+
 	// Log audit event
 	userID := GetUserIDFromContext(ctx)
 	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
@@ -1584,6 +1690,26 @@ func (s *JobServiceImpl) CheckSchedulingConflicts(ctx context.Context, jobID uui
 		}
 	}
 
+	// Check the job's equipment against other reservations and whether the
+	// crew's trucks and trailers can haul it
+	job, err := s.jobRepo.GetByID(ctx, tenantID, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	if job != nil {
+		equipmentConflicts, err := s.equipmentConflicts(ctx, job, scheduledDate, endTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check equipment scheduling conflicts: %w", err)
+		}
+		conflicts = append(conflicts, equipmentConflicts...)
+
+		transportConflicts, err := s.transportConflicts(ctx, job, scheduledDate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check equipment transport: %w", err)
+		}
+		conflicts = append(conflicts, transportConflicts...)
+	}
+
 	return conflicts, nil
 }
 
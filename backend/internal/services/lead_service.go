@@ -0,0 +1,802 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// LeadService manages inbound leads: intake with spam and duplicate screening, the
+// review queue, and conversion into a customer, property and draft quote
+type LeadService interface {
+	SubmitLead(ctx context.Context, req *LeadSubmission) (*domain.Lead, error)
+	GetLead(ctx context.Context, leadID uuid.UUID) (*domain.Lead, error)
+	ListLeads(ctx context.Context, filter *LeadFilter) (*domain.PaginatedResponse, error)
+	AcceptLead(ctx context.Context, leadID uuid.UUID, req *LeadAcceptRequest) (*LeadConversion, error)
+	DenyLead(ctx context.Context, leadID uuid.UUID, reason string) (*domain.Lead, error)
+	UpdateLeadStatus(ctx context.Context, leadID uuid.UUID, status string) (*domain.Lead, error)
+}
+
+// LeadRepository defines data access for leads
+type LeadRepository interface {
+	Create(ctx context.Context, lead *domain.Lead) error
+	GetByID(ctx context.Context, tenantID, leadID uuid.UUID) (*domain.Lead, error)
+	Update(ctx context.Context, lead *domain.Lead) error
+	List(ctx context.Context, tenantID uuid.UUID, filter *LeadFilter) ([]*domain.Lead, int64, error)
+	// FindRecentByContact returns leads since a time whose email (case-insensitive)
+	// or phone digits match, newest first
+	FindRecentByContact(ctx context.Context, tenantID uuid.UUID, email, phoneDigits string, since time.Time) ([]*domain.Lead, error)
+	CountByIPSince(ctx context.Context, tenantID uuid.UUID, ipAddress string, since time.Time) (int, error)
+}
+
+// LeadSubmission is a booking or consultation request. TenantID is used when the
+// request comes from a public form with no tenant in the context.
+type LeadSubmission struct {
+	TenantID       uuid.UUID  `json:"tenant_id,omitempty"`
+	Source         string     `json:"source,omitempty"`
+	Name           string     `json:"name,omitempty"` // split into first and last name when those are empty
+	FirstName      string     `json:"first_name,omitempty"`
+	LastName       string     `json:"last_name,omitempty"`
+	Email          string     `json:"email,omitempty"`
+	Phone          string     `json:"phone,omitempty"`
+	ServiceKey     string     `json:"service_key,omitempty"`
+	ServiceID      *uuid.UUID `json:"service_id,omitempty"`
+	Message        string     `json:"message,omitempty"`
+	AddressLine1   string     `json:"address_line1,omitempty"`
+	City           string     `json:"city,omitempty"`
+	State          string     `json:"state,omitempty"`
+	ZipCode        string     `json:"zip_code,omitempty"`
+	PropertySize   *float64   `json:"property_size,omitempty"`
+	EstimatedPrice *float64   `json:"estimated_price,omitempty"`
+	// Bot signals from the form: a hidden field people leave empty and when the form was shown
+	Honeypot       string     `json:"website,omitempty"`
+	FormRenderedAt *time.Time `json:"form_rendered_at,omitempty"`
+	IPAddress      string     `json:"-"`
+	UserAgent      string     `json:"-"`
+}
+
+// LeadFilter filters the lead queue. Spam is hidden unless asked for by status.
+type LeadFilter struct {
+	BaseFilter
+	Status string `json:"status,omitempty"`
+	Source string `json:"source,omitempty"`
+}
+
+// LeadAcceptRequest supplies what the lead is missing to create the customer,
+// property and quote. Empty fields fall back to the lead's own details.
+type LeadAcceptRequest struct {
+	FirstName    string     `json:"first_name,omitempty"`
+	LastName     string     `json:"last_name,omitempty"`
+	CompanyName  *string    `json:"company_name,omitempty"`
+	CustomerType string     `json:"customer_type,omitempty"`
+	PropertyID   *uuid.UUID `json:"property_id,omitempty"` // existing property of a matched customer
+	PropertyName string     `json:"property_name,omitempty"`
+	AddressLine1 string     `json:"address_line1,omitempty"`
+	AddressLine2 *string    `json:"address_line2,omitempty"`
+	City         string     `json:"city,omitempty"`
+	State        string     `json:"state,omitempty"`
+	ZipCode      string     `json:"zip_code,omitempty"`
+	LotSize      *float64   `json:"lot_size,omitempty"`
+	ServiceID    *uuid.UUID `json:"service_id,omitempty"`
+	Quantity     float64    `json:"quantity,omitempty"`
+	UnitPrice    *float64   `json:"unit_price,omitempty"` // priced from the price books when omitted
+	Frequency    string     `json:"frequency,omitempty"`
+	QuoteTitle   string     `json:"quote_title,omitempty"`
+	Notes        *string    `json:"notes,omitempty"`
+}
+
+// LeadConversion is the result of accepting a lead
+type LeadConversion struct {
+	Lead            *domain.Lead             `json:"lead"`
+	Customer        *domain.EnhancedCustomer `json:"customer"`
+	CustomerCreated bool                     `json:"customer_created"`
+	Property        *domain.EnhancedProperty `json:"property"`
+	PropertyCreated bool                     `json:"property_created"`
+	Quote           *domain.Quote            `json:"quote"`
+}
+
+// LeadScreening is the spam assessment of a submission
+type LeadScreening struct {
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons"`
+	IsSpam  bool     `json:"is_spam"`
+}
+
+// LeadSpamThreshold is the screening score at which a lead is filed as spam
+const LeadSpamThreshold = 50
+
+// Window in which a second request from the same contact is treated as a duplicate
+const leadDuplicateWindow = 30 * 24 * time.Hour
+
+var (
+	leadLinkPattern     = regexp.MustCompile(`(?i)(https?://|www\.)`)
+	leadNonDigitPattern = regexp.MustCompile(`\D`)
+
+	leadSpamPhrases = []string{"casino", "viagra", "crypto", "bitcoin", "seo services", "backlinks", "payday loan", "guest post"}
+
+	disposableEmailDomains = []string{"mailinator.com", "guerrillamail.com", "10minutemail.com", "tempmail.com", "yopmail.com", "trashmail.com"}
+)
+
+// leadServiceImpl implements LeadService
+type leadServiceImpl struct {
+	leadRepo        LeadRepository
+	customerRepo    CustomerRepository
+	propertyRepo    PropertyRepositoryExtended
+	customerService CustomerService
+	propertyService PropertyService
+	quoteService    QuoteService
+	auditService    AuditService
+	logger          *log.Logger
+}
+
+// NewLeadService creates a new lead service. Conversion goes through the customer,
+// property and quote services so leads get the same validation as manual entry.
+func NewLeadService(
+	leadRepo LeadRepository,
+	customerRepo CustomerRepository,
+	propertyRepo PropertyRepositoryExtended,
+	customerService CustomerService,
+	propertyService PropertyService,
+	quoteService QuoteService,
+	auditService AuditService,
+	logger *log.Logger,
+) LeadService {
+	return &leadServiceImpl{
+		leadRepo:        leadRepo,
+		customerRepo:    customerRepo,
+		propertyRepo:    propertyRepo,
+		customerService: customerService,
+		propertyService: propertyService,
+		quoteService:    quoteService,
+		auditService:    auditService,
+		logger:          logger,
+	}
+}
+
+// SubmitLead records a lead. Submissions that look automated are filed as spam and
+// repeat requests from the same contact are filed as duplicates of the earlier lead;
+// both stay visible to staff.
+func (s *leadServiceImpl) SubmitLead(ctx context.Context, req *LeadSubmission) (*domain.Lead, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		if req.TenantID == uuid.Nil {
+			return nil, fmt.Errorf("tenant ID not found in context")
+		}
+		tenantID = req.TenantID
+		ctx = context.WithValue(ctx, "tenant_id", tenantID)
+	}
+
+	normalizeLeadSubmission(req)
+	if err := validateLeadSubmission(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	now := time.Now()
+
+	recentFromIP := 0
+	if req.IPAddress != "" {
+		count, err := s.leadRepo.CountByIPSince(ctx, tenantID, req.IPAddress, now.Add(-time.Hour))
+		if err != nil {
+			s.logger.Printf("Failed to count recent leads from address: %v", err)
+		}
+		recentFromIP = count
+	}
+	screening := ScreenLead(req, recentFromIP, now)
+
+	lead := &domain.Lead{
+		ID:             uuid.New(),
+		TenantID:       tenantID,
+		Source:         req.Source,
+		Status:         domain.LeadStatusPending,
+		FirstName:      req.FirstName,
+		LastName:       req.LastName,
+		Email:          optionalString(req.Email),
+		Phone:          optionalString(req.Phone),
+		ServiceKey:     optionalString(req.ServiceKey),
+		ServiceID:      req.ServiceID,
+		Message:        optionalString(req.Message),
+		AddressLine1:   optionalString(req.AddressLine1),
+		City:           optionalString(req.City),
+		State:          optionalString(req.State),
+		ZipCode:        optionalString(req.ZipCode),
+		PropertySize:   req.PropertySize,
+		EstimatedPrice: req.EstimatedPrice,
+		SpamScore:      screening.Score,
+		SpamReasons:    screening.Reasons,
+		IPAddress:      optionalString(req.IPAddress),
+		UserAgent:      optionalString(req.UserAgent),
+		Metadata:       map[string]interface{}{},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if screening.IsSpam {
+		lead.Status = domain.LeadStatusSpam
+	} else {
+		recent, err := s.leadRepo.FindRecentByContact(ctx, tenantID, req.Email, leadPhoneDigits(req.Phone), now.Add(-leadDuplicateWindow))
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for duplicate leads: %w", err)
+		}
+		if original := FindDuplicateLead(recent, req.Email, req.Phone); original != nil {
+			lead.Status = domain.LeadStatusDuplicate
+			lead.DuplicateOfID = &original.ID
+		}
+
+		lead.MatchedCustomerID = s.matchCustomer(ctx, tenantID, req.Email, req.Phone)
+	}
+
+	if err := s.leadRepo.Create(ctx, lead); err != nil {
+		return nil, fmt.Errorf("failed to create lead: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "lead.create",
+		ResourceType: "lead",
+		ResourceID:   &lead.ID,
+		NewValues: map[string]interface{}{
+			"source":     lead.Source,
+			"status":     lead.Status,
+			"spam_score": lead.SpamScore,
+		},
+		IPAddress: lead.IPAddress,
+		UserAgent: lead.UserAgent,
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	s.logger.Printf("Lead %s received from %s (%s, status %s)", lead.ID, lead.Source, lead.FullName(), lead.Status)
+
+	return lead, nil
+}
+
+// GetLead retrieves a lead
+func (s *leadServiceImpl) GetLead(ctx context.Context, leadID uuid.UUID) (*domain.Lead, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	lead, err := s.leadRepo.GetByID(ctx, tenantID, leadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lead: %w", err)
+	}
+	if lead == nil {
+		return nil, fmt.Errorf("lead not found")
+	}
+
+	return lead, nil
+}
+
+// ListLeads lists the lead queue
+func (s *leadServiceImpl) ListLeads(ctx context.Context, filter *LeadFilter) (*domain.PaginatedResponse, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	// Set defaults
+	if filter == nil {
+		filter = &LeadFilter{}
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PerPage <= 0 {
+		filter.PerPage = 50
+	}
+	if filter.PerPage > 100 {
+		filter.PerPage = 100
+	}
+
+	leads, total, err := s.leadRepo.List(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leads: %w", err)
+	}
+
+	totalPages := int((total + int64(filter.PerPage) - 1) / int64(filter.PerPage))
+
+	return &domain.PaginatedResponse{
+		Data:       leads,
+		Total:      total,
+		Page:       filter.Page,
+		PerPage:    filter.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// AcceptLead converts a lead into a customer, property and draft quote. An existing
+// customer with the lead's email or phone is reused, as is a property of theirs at
+// the same address, so accepting a returning customer's request doesn't duplicate them.
+func (s *leadServiceImpl) AcceptLead(ctx context.Context, leadID uuid.UUID, req *LeadAcceptRequest) (*LeadConversion, error) {
+	lead, err := s.GetLead(ctx, leadID)
+	if err != nil {
+		return nil, err
+	}
+	if lead.Status == domain.LeadStatusAccepted || lead.Status == domain.LeadStatusScheduled {
+		return nil, fmt.Errorf("lead has already been accepted")
+	}
+	if req == nil {
+		req = &LeadAcceptRequest{}
+	}
+
+	serviceID := req.ServiceID
+	if serviceID == nil {
+		serviceID = lead.ServiceID
+	}
+	if serviceID == nil {
+		return nil, fmt.Errorf("a service is required to create the quote")
+	}
+
+	conversion := &LeadConversion{}
+
+	// Customer
+	conversion.Customer, conversion.CustomerCreated, err = s.leadCustomer(ctx, lead, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Property
+	conversion.Property, conversion.PropertyCreated, err = s.leadProperty(ctx, lead, conversion.Customer, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Draft quote
+	quantity := req.Quantity
+	if quantity <= 0 {
+		quantity = 1
+	}
+	line := QuoteServiceRequest{ServiceID: *serviceID, Quantity: quantity, UsePricingRules: req.UnitPrice == nil}
+	if req.UnitPrice != nil {
+		line.UnitPrice = *req.UnitPrice
+	}
+
+	title := strings.TrimSpace(req.QuoteTitle)
+	if title == "" {
+		title = "Service quote for " + customerDisplayName(conversion.Customer)
+		if lead.ServiceKey != nil && *lead.ServiceKey != "" {
+			title = leadServiceLabel(*lead.ServiceKey) + " for " + customerDisplayName(conversion.Customer)
+		}
+	}
+
+	conversion.Quote, err = s.quoteService.CreateQuote(ctx, &QuoteCreateRequest{
+		CustomerID:  conversion.Customer.ID,
+		PropertyID:  conversion.Property.ID,
+		Title:       title,
+		Description: lead.Message,
+		Services:    []QuoteServiceRequest{line},
+		Frequency:   req.Frequency,
+		Notes:       req.Notes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create quote: %w", err)
+	}
+
+	now := time.Now()
+	oldStatus := lead.Status
+	lead.Status = domain.LeadStatusAccepted
+	lead.CustomerID = &conversion.Customer.ID
+	lead.PropertyID = &conversion.Property.ID
+	lead.QuoteID = &conversion.Quote.ID
+	lead.ReviewedBy = GetUserIDFromContext(ctx)
+	lead.ReviewedAt = &now
+	lead.UpdatedAt = now
+
+	if err := s.leadRepo.Update(ctx, lead); err != nil {
+		return nil, fmt.Errorf("failed to update lead: %w", err)
+	}
+	conversion.Lead = lead
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       lead.ReviewedBy,
+		Action:       "lead.accept",
+		ResourceType: "lead",
+		ResourceID:   &lead.ID,
+		OldValues:    map[string]interface{}{"status": oldStatus},
+		NewValues: map[string]interface{}{
+			"status":           lead.Status,
+			"customer_id":      lead.CustomerID,
+			"customer_created": conversion.CustomerCreated,
+			"property_id":      lead.PropertyID,
+			"property_created": conversion.PropertyCreated,
+			"quote_id":         lead.QuoteID,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return conversion, nil
+}
+
+// DenyLead declines a lead
+func (s *leadServiceImpl) DenyLead(ctx context.Context, leadID uuid.UUID, reason string) (*domain.Lead, error) {
+	lead, err := s.GetLead(ctx, leadID)
+	if err != nil {
+		return nil, err
+	}
+	if lead.Status == domain.LeadStatusAccepted || lead.Status == domain.LeadStatusScheduled {
+		return nil, fmt.Errorf("accepted leads cannot be denied")
+	}
+
+	if reason = strings.TrimSpace(reason); reason != "" {
+		lead.DenialReason = &reason
+	}
+	return s.setLeadStatus(ctx, lead, domain.LeadStatusDenied, "lead.deny")
+}
+
+// UpdateLeadStatus moves a lead through the queue, e.g. to scheduled once work is
+// booked or back to pending when it was wrongly filed as spam. It does not convert
+// the lead; use AcceptLead for that.
+func (s *leadServiceImpl) UpdateLeadStatus(ctx context.Context, leadID uuid.UUID, status string) (*domain.Lead, error) {
+	if !isLeadStatus(status) {
+		return nil, fmt.Errorf("invalid lead status: %s", status)
+	}
+
+	lead, err := s.GetLead(ctx, leadID)
+	if err != nil {
+		return nil, err
+	}
+	if lead.Status == status {
+		return lead, nil
+	}
+
+	return s.setLeadStatus(ctx, lead, status, "lead.status_change")
+}
+
+func (s *leadServiceImpl) setLeadStatus(ctx context.Context, lead *domain.Lead, status, action string) (*domain.Lead, error) {
+	now := time.Now()
+	oldStatus := lead.Status
+	lead.Status = status
+	lead.ReviewedBy = GetUserIDFromContext(ctx)
+	lead.ReviewedAt = &now
+	lead.UpdatedAt = now
+
+	if err := s.leadRepo.Update(ctx, lead); err != nil {
+		return nil, fmt.Errorf("failed to update lead: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       lead.ReviewedBy,
+		Action:       action,
+		ResourceType: "lead",
+		ResourceID:   &lead.ID,
+		OldValues:    map[string]interface{}{"status": oldStatus},
+		NewValues:    map[string]interface{}{"status": status},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return lead, nil
+}
+
+// leadCustomer returns the customer for a lead, creating one when no existing
+// customer matches
+func (s *leadServiceImpl) leadCustomer(ctx context.Context, lead *domain.Lead, req *LeadAcceptRequest) (*domain.EnhancedCustomer, bool, error) {
+	candidates := []*uuid.UUID{lead.CustomerID, lead.MatchedCustomerID}
+	for _, customerID := range candidates {
+		if customerID == nil {
+			continue
+		}
+		customer, err := s.customerRepo.GetByID(ctx, lead.TenantID, *customerID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get customer: %w", err)
+		}
+		if customer != nil {
+			return customer, false, nil
+		}
+	}
+
+	// The contact may have become a customer since the lead came in
+	email, phone := stringValue(lead.Email), stringValue(lead.Phone)
+	if matchedID := s.matchCustomer(ctx, lead.TenantID, email, phone); matchedID != nil {
+		customer, err := s.customerRepo.GetByID(ctx, lead.TenantID, *matchedID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get customer: %w", err)
+		}
+		if customer != nil {
+			return customer, false, nil
+		}
+	}
+
+	firstName, lastName := lead.FirstName, lead.LastName
+	if req.FirstName != "" {
+		firstName = req.FirstName
+	}
+	if req.LastName != "" {
+		lastName = req.LastName
+	}
+	if lastName == "" {
+		return nil, false, fmt.Errorf("last name is required to create the customer")
+	}
+
+	contactMethod := "email"
+	if email == "" {
+		contactMethod = "phone"
+	}
+	customerType := req.CustomerType
+	if customerType == "" {
+		customerType = "residential"
+	}
+	source := lead.Source
+
+	customer, err := s.customerService.CreateCustomer(ctx, &domain.CreateCustomerRequest{
+		FirstName:              firstName,
+		LastName:               lastName,
+		Email:                  lead.Email,
+		Phone:                  lead.Phone,
+		CompanyName:            req.CompanyName,
+		AddressLine1:           optionalString(firstNonEmpty(req.AddressLine1, stringValue(lead.AddressLine1))),
+		City:                   optionalString(firstNonEmpty(req.City, stringValue(lead.City))),
+		State:                  optionalString(firstNonEmpty(req.State, stringValue(lead.State))),
+		ZipCode:                optionalString(firstNonEmpty(req.ZipCode, stringValue(lead.ZipCode))),
+		PreferredContactMethod: contactMethod,
+		LeadSource:             &source,
+		CustomerType:           customerType,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	return customer, true, nil
+}
+
+// leadProperty returns the property for the quote, reusing one of the customer's
+// properties at the same address
+func (s *leadServiceImpl) leadProperty(ctx context.Context, lead *domain.Lead, customer *domain.EnhancedCustomer, req *LeadAcceptRequest) (*domain.EnhancedProperty, bool, error) {
+	if req.PropertyID != nil {
+		property, err := s.propertyRepo.GetByID(ctx, lead.TenantID, *req.PropertyID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get property: %w", err)
+		}
+		if property == nil || property.CustomerID != customer.ID {
+			return nil, false, fmt.Errorf("property not found")
+		}
+		return property, false, nil
+	}
+
+	address := firstNonEmpty(req.AddressLine1, stringValue(lead.AddressLine1))
+	city := firstNonEmpty(req.City, stringValue(lead.City))
+	state := firstNonEmpty(req.State, stringValue(lead.State))
+	zipCode := firstNonEmpty(req.ZipCode, stringValue(lead.ZipCode))
+
+	existing, err := s.propertyRepo.GetByCustomerID(ctx, lead.TenantID, customer.ID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get customer properties: %w", err)
+	}
+	for _, property := range existing {
+		if address != "" && strings.EqualFold(strings.TrimSpace(property.AddressLine1), address) &&
+			(zipCode == "" || property.ZipCode == zipCode) {
+			return property, false, nil
+		}
+	}
+	// A returning customer with a single property needs no address
+	if address == "" && len(existing) == 1 {
+		return existing[0], false, nil
+	}
+
+	if address == "" || city == "" || state == "" || zipCode == "" {
+		return nil, false, fmt.Errorf("property address, city, state and zip code are required")
+	}
+
+	name := strings.TrimSpace(req.PropertyName)
+	if name == "" {
+		name = address
+	}
+	propertyType := req.CustomerType
+	if propertyType == "" {
+		propertyType = customer.CustomerType
+	}
+	if propertyType != "commercial" {
+		propertyType = "residential"
+	}
+
+	lotSize := req.LotSize
+	if lotSize == nil {
+		lotSize = lead.PropertySize
+	}
+
+	property, err := s.propertyService.CreateProperty(ctx, &domain.CreatePropertyRequest{
+		CustomerID:   customer.ID,
+		Name:         name,
+		AddressLine1: address,
+		AddressLine2: req.AddressLine2,
+		City:         city,
+		State:        state,
+		ZipCode:      zipCode,
+		PropertyType: propertyType,
+		LotSize:      lotSize,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create property: %w", err)
+	}
+
+	return property, true, nil
+}
+
+func (s *leadServiceImpl) matchCustomer(ctx context.Context, tenantID uuid.UUID, email, phone string) *uuid.UUID {
+	if s.customerRepo == nil {
+		return nil
+	}
+	if email != "" {
+		if customer, err := s.customerRepo.GetByEmail(ctx, tenantID, email); err == nil && customer != nil {
+			return &customer.ID
+		}
+	}
+	if phone != "" {
+		if customer, err := s.customerRepo.GetByPhone(ctx, tenantID, phone); err == nil && customer != nil {
+			return &customer.ID
+		}
+	}
+	return nil
+}
+
+// ScreenLead scores how likely a submission is to be spam. recentFromIP is the number
+// of leads from the same address in the last hour.
+func ScreenLead(req *LeadSubmission, recentFromIP int, now time.Time) *LeadScreening {
+	screening := &LeadScreening{Reasons: []string{}}
+	flag := func(score int, reason string) {
+		screening.Score += score
+		screening.Reasons = append(screening.Reasons, reason)
+	}
+
+	if strings.TrimSpace(req.Honeypot) != "" {
+		flag(100, "hidden form field was filled in")
+	}
+	if req.FormRenderedAt != nil && now.Sub(*req.FormRenderedAt) < 3*time.Second {
+		flag(50, "form submitted too quickly")
+	}
+
+	if links := len(leadLinkPattern.FindAllString(req.Message, -1)); links >= 2 {
+		flag(40, fmt.Sprintf("message contains %d links", links))
+	} else if links == 1 {
+		flag(10, "message contains a link")
+	}
+	if leadLinkPattern.MatchString(req.FirstName + " " + req.LastName) {
+		flag(60, "name contains a link")
+	}
+
+	message := strings.ToLower(req.Message)
+	for _, phrase := range leadSpamPhrases {
+		if strings.Contains(message, phrase) {
+			flag(30, fmt.Sprintf("message mentions %q", phrase))
+			break
+		}
+	}
+
+	if req.Email != "" {
+		if !isValidEmail(req.Email) {
+			flag(30, "email address is invalid")
+		} else {
+			domainPart := req.Email[strings.LastIndex(req.Email, "@")+1:]
+			if containsString(disposableEmailDomains, domainPart) {
+				flag(30, "disposable email address")
+			}
+		}
+	}
+	if req.Email == "" && leadPhoneDigits(req.Phone) == "" {
+		flag(40, "no email or phone number")
+	}
+
+	if recentFromIP >= 3 {
+		flag(40, fmt.Sprintf("%d submissions from this address in the last hour", recentFromIP))
+	}
+
+	screening.IsSpam = screening.Score >= LeadSpamThreshold
+	return screening
+}
+
+// FindDuplicateLead returns the newest lead from the same contact, matching email
+// case-insensitively or phone by digits. Spam leads are ignored.
+func FindDuplicateLead(candidates []*domain.Lead, email, phone string) *domain.Lead {
+	email = strings.ToLower(strings.TrimSpace(email))
+	phoneDigits := leadPhoneDigits(phone)
+
+	var newest *domain.Lead
+	for _, candidate := range candidates {
+		if candidate.Status == domain.LeadStatusSpam {
+			continue
+		}
+		sameEmail := email != "" && candidate.Email != nil && strings.ToLower(*candidate.Email) == email
+		samePhone := phoneDigits != "" && candidate.Phone != nil && leadPhoneDigits(*candidate.Phone) == phoneDigits
+		if !sameEmail && !samePhone {
+			continue
+		}
+		if newest == nil || candidate.CreatedAt.After(newest.CreatedAt) {
+			newest = candidate
+		}
+	}
+	return newest
+}
+
+// Helper functions
+
+func normalizeLeadSubmission(req *LeadSubmission) {
+	req.FirstName = strings.TrimSpace(req.FirstName)
+	req.LastName = strings.TrimSpace(req.LastName)
+	if req.FirstName == "" && req.LastName == "" {
+		parts := strings.Fields(req.Name)
+		if len(parts) > 0 {
+			req.FirstName = parts[0]
+			req.LastName = strings.Join(parts[1:], " ")
+		}
+	}
+
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	req.Phone = strings.TrimSpace(req.Phone)
+	req.ServiceKey = strings.TrimSpace(req.ServiceKey)
+	req.Message = strings.TrimSpace(req.Message)
+	req.AddressLine1 = strings.TrimSpace(req.AddressLine1)
+	req.City = strings.TrimSpace(req.City)
+	req.State = strings.TrimSpace(req.State)
+	req.ZipCode = strings.TrimSpace(req.ZipCode)
+	if req.Source == "" {
+		req.Source = domain.LeadSourceWebsiteBooking
+	}
+}
+
+func validateLeadSubmission(req *LeadSubmission) error {
+	if req.FirstName == "" {
+		return fmt.Errorf("name is required")
+	}
+	switch req.Source {
+	case domain.LeadSourceWebsiteBooking, domain.LeadSourceWebsiteConsultation, domain.LeadSourcePhone,
+		domain.LeadSourceReferral, domain.LeadSourceOther:
+	default:
+		return fmt.Errorf("invalid lead source: %s", req.Source)
+	}
+	if len(req.Message) > 5000 {
+		return fmt.Errorf("message is too long")
+	}
+	return nil
+}
+
+func isLeadStatus(status string) bool {
+	switch status {
+	case domain.LeadStatusPending, domain.LeadStatusAccepted, domain.LeadStatusDenied,
+		domain.LeadStatusScheduled, domain.LeadStatusSpam, domain.LeadStatusDuplicate:
+		return true
+	}
+	return false
+}
+
+func leadPhoneDigits(phone string) string {
+	digits := leadNonDigitPattern.ReplaceAllString(phone, "")
+	// Treat a leading US country code as the same number
+	if len(digits) == 11 && strings.HasPrefix(digits, "1") {
+		digits = digits[1:]
+	}
+	return digits
+}
+
+// leadServiceLabel turns a catalog key such as lawn_care into "Lawn Care"
+func leadServiceLabel(key string) string {
+	words := strings.Fields(strings.ReplaceAll(key, "_", " "))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// LocalMessagingProvider stands in for the comms provider in development and tests.
+// It records messages instead of delivering them and accepts webhooks in the
+// provider-neutral LocalWebhookPayload format, signed with SignWebhookPayload.
+type LocalMessagingProvider struct {
+	secret    string
+	smsFrom   string
+	emailFrom string
+
+	mu   sync.Mutex
+	sent []*LocalSentMessage
+}
+
+// LocalSentMessage is a message recorded by LocalMessagingProvider
+type LocalSentMessage struct {
+	ProviderMessageID string `json:"provider_message_id"`
+	OutboundMessage
+}
+
+// LocalWebhookPayload is the webhook body LocalMessagingProvider accepts
+type LocalWebhookPayload struct {
+	Events []*MessagingEvent `json:"events"`
+}
+
+// NewLocalMessagingProvider creates a local messaging provider. Webhooks must be
+// signed with secret.
+func NewLocalMessagingProvider(secret, smsFrom, emailFrom string) *LocalMessagingProvider {
+	return &LocalMessagingProvider{
+		secret:    secret,
+		smsFrom:   smsFrom,
+		emailFrom: emailFrom,
+	}
+}
+
+// Name returns the provider name
+func (p *LocalMessagingProvider) Name() string {
+	return "local"
+}
+
+// SenderAddress returns the configured number or email address
+func (p *LocalMessagingProvider) SenderAddress(channel string) string {
+	if channel == domain.CommunicationChannelSMS {
+		return p.smsFrom
+	}
+	return p.emailFrom
+}
+
+// Send records the message and returns a new provider message ID
+func (p *LocalMessagingProvider) Send(ctx context.Context, msg *OutboundMessage) (string, error) {
+	if msg.To == "" {
+		return "", fmt.Errorf("recipient is required")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id := "local-" + uuid.NewString()
+	p.sent = append(p.sent, &LocalSentMessage{ProviderMessageID: id, OutboundMessage: *msg})
+	return id, nil
+}
+
+// Sent returns the messages sent so far, oldest first
+func (p *LocalMessagingProvider) Sent() []*LocalSentMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*LocalSentMessage(nil), p.sent...)
+}
+
+// VerifyWebhook checks the payload's HMAC-SHA256 signature in MessagingSignatureHeader
+func (p *LocalMessagingProvider) VerifyWebhook(webhook *MessagingWebhook) error {
+	return VerifyWebhookSignature(p.secret, webhook.Payload, webhook.Header.Get(MessagingSignatureHeader))
+}
+
+// ParseWebhook reads a LocalWebhookPayload
+func (p *LocalMessagingProvider) ParseWebhook(webhook *MessagingWebhook) ([]*MessagingEvent, error) {
+	var body LocalWebhookPayload
+	if err := json.Unmarshal(webhook.Payload, &body); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+
+	for i, event := range body.Events {
+		if event == nil {
+			return nil, fmt.Errorf("event %d is empty", i+1)
+		}
+		if event.Channel != domain.CommunicationChannelSMS && event.Channel != domain.CommunicationChannelEmail {
+			return nil, fmt.Errorf("event %d has unknown channel %q", i+1, event.Channel)
+		}
+		switch event.Type {
+		case MessagingEventMessage:
+			if event.From == "" {
+				return nil, fmt.Errorf("event %d has no sender", i+1)
+			}
+		case MessagingEventStatus:
+			if event.ProviderMessageID == "" {
+				return nil, fmt.Errorf("event %d has no provider message ID", i+1)
+			}
+		default:
+			return nil, fmt.Errorf("event %d has unknown type %q", i+1, event.Type)
+		}
+	}
+	return body.Events, nil
+}
+
+// Webhook builds a signed webhook delivery for the events, as the provider would
+// post it to the inbound webhook URL
+func (p *LocalMessagingProvider) Webhook(events ...*MessagingEvent) ([]byte, string, error) {
+	payload, err := json.Marshal(&LocalWebhookPayload{Events: events})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode webhook: %w", err)
+	}
+	return payload, SignWebhookPayload(p.secret, payload), nil
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of a webhook payload
+func SignWebhookPayload(secret string, payload []byte) string {
+	return hex.EncodeToString(webhookMAC(secret, payload))
+}
+
+// VerifyWebhookSignature checks a webhook payload against its hex HMAC-SHA256 signature
+func VerifyWebhookSignature(secret string, payload []byte, signature string) error {
+	if secret == "" {
+		return fmt.Errorf("webhook secret not configured")
+	}
+	actual, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(webhookMAC(secret, payload), actual) {
+		return fmt.Errorf("signature does not match")
+	}
+	return nil
+}
+
+func webhookMAC(secret string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
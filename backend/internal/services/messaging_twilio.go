@@ -0,0 +1,423 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// Twilio and Twilio SendGrid API hosts
+const (
+	TwilioProductionURL   = "https://api.twilio.com"
+	SendGridProductionURL = "https://api.sendgrid.com"
+
+	// TwilioSignatureHeader carries Twilio's webhook signature
+	TwilioSignatureHeader = "X-Twilio-Signature"
+)
+
+// TwilioMessagingConfig configures TwilioMessagingProvider
+type TwilioMessagingConfig struct {
+	APIURL     string
+	AccountSID string
+	AuthToken  string
+	SMSFrom    string
+
+	SendGridAPIURL string
+	SendGridAPIKey string
+	EmailFrom      string
+	EmailFromName  string
+	// InboundParsePassword is the basic auth password in the SendGrid Inbound Parse URL
+	InboundParsePassword string
+
+	// WebhookURL is the webhook URL configured in Twilio, with {tenant_id} in place of
+	// the tenant. Twilio signs requests against it and SMS status callbacks go to it.
+	WebhookURL string
+}
+
+// TwilioMessagingProvider sends SMS through Twilio and email through Twilio SendGrid.
+// Inbound SMS and SMS status callbacks arrive as Twilio's signed form posts; inbound
+// email arrives from SendGrid Inbound Parse, which is authenticated with basic auth
+// because SendGrid does not sign it. Email delivery events are not tracked, so sent
+// emails stay sent.
+type TwilioMessagingProvider struct {
+	config     TwilioMessagingConfig
+	httpClient *http.Client
+}
+
+// NewTwilioMessagingProvider creates a Twilio messaging provider
+func NewTwilioMessagingProvider(config TwilioMessagingConfig, httpClient *http.Client) *TwilioMessagingProvider {
+	if config.APIURL == "" {
+		config.APIURL = TwilioProductionURL
+	}
+	if config.SendGridAPIURL == "" {
+		config.SendGridAPIURL = SendGridProductionURL
+	}
+	config.APIURL = strings.TrimRight(config.APIURL, "/")
+	config.SendGridAPIURL = strings.TrimRight(config.SendGridAPIURL, "/")
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &TwilioMessagingProvider{
+		config:     config,
+		httpClient: httpClient,
+	}
+}
+
+// Name returns the provider name
+func (p *TwilioMessagingProvider) Name() string {
+	return "twilio"
+}
+
+// SenderAddress returns the configured number or email address
+func (p *TwilioMessagingProvider) SenderAddress(channel string) string {
+	if channel == domain.CommunicationChannelSMS {
+		return p.config.SMSFrom
+	}
+	return p.config.EmailFrom
+}
+
+// Send delivers an SMS through Twilio or an email through SendGrid
+func (p *TwilioMessagingProvider) Send(ctx context.Context, msg *OutboundMessage) (string, error) {
+	if msg.To == "" {
+		return "", fmt.Errorf("recipient is required")
+	}
+
+	switch msg.Channel {
+	case domain.CommunicationChannelSMS:
+		return p.sendSMS(ctx, msg)
+	case domain.CommunicationChannelEmail:
+		return p.sendEmail(ctx, msg)
+	default:
+		return "", fmt.Errorf("unsupported channel: %s", msg.Channel)
+	}
+}
+
+// sendSMS creates a Twilio message and returns its SID
+func (p *TwilioMessagingProvider) sendSMS(ctx context.Context, msg *OutboundMessage) (string, error) {
+	from := msg.From
+	if from == "" {
+		from = p.config.SMSFrom
+	}
+
+	form := url.Values{
+		"From": {TwilioPhoneNumber(from)},
+		"To":   {TwilioPhoneNumber(msg.To)},
+		"Body": {msg.Body},
+	}
+	if tenantID, ok := GetTenantIDFromContext(ctx); ok && p.config.WebhookURL != "" {
+		form.Set("StatusCallback", p.webhookURL(tenantID))
+	}
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", p.config.APIURL, url.PathEscape(p.config.AccountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(p.config.AccountSID, p.config.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("twilio request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		SID     string `json:"sid"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode twilio response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("twilio returned %d: %s (code %d)", resp.StatusCode, result.Message, result.Code)
+	}
+	if result.SID == "" {
+		return "", fmt.Errorf("twilio response has no message SID")
+	}
+
+	return result.SID, nil
+}
+
+// sendEmail sends an email through SendGrid. SendGrid's own message IDs do not appear
+// in replies, so the email gets its own Message-ID, which is returned as the provider
+// message ID and comes back in the In-Reply-To header of the customer's reply.
+func (p *TwilioMessagingProvider) sendEmail(ctx context.Context, msg *OutboundMessage) (string, error) {
+	from := msg.From
+	if from == "" {
+		from = p.config.EmailFrom
+	}
+
+	messageID := uuid.NewString() + "@" + emailDomain(from)
+	headers := map[string]string{"Message-ID": "<" + messageID + ">"}
+	if msg.InReplyTo != "" {
+		headers["In-Reply-To"] = "<" + msg.InReplyTo + ">"
+		headers["References"] = "<" + msg.InReplyTo + ">"
+	}
+
+	sender := map[string]string{"email": from}
+	if p.config.EmailFromName != "" {
+		sender["name"] = p.config.EmailFromName
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"personalizations": []map[string]interface{}{
+			{"to": []map[string]string{{"email": msg.To}}},
+		},
+		"from":    sender,
+		"subject": msg.Subject,
+		"content": []map[string]string{{"type": "text/plain", "value": msg.Body}},
+		"headers": headers,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode email: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.SendGridAPIURL+"/v3/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.config.SendGridAPIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("sendgrid request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var failure struct {
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if err := json.Unmarshal(data, &failure); err == nil && len(failure.Errors) > 0 {
+			return "", fmt.Errorf("sendgrid returned %d: %s", resp.StatusCode, failure.Errors[0].Message)
+		}
+		return "", fmt.Errorf("sendgrid returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	return messageID, nil
+}
+
+// VerifyWebhook checks Twilio's signature on SMS webhooks and the Inbound Parse
+// credentials on inbound email
+func (p *TwilioMessagingProvider) VerifyWebhook(webhook *MessagingWebhook) error {
+	if isInboundParse(webhook) {
+		if p.config.InboundParsePassword == "" {
+			return fmt.Errorf("inbound parse password not configured")
+		}
+		_, password, ok := (&http.Request{Header: webhook.Header}).BasicAuth()
+		if !ok || subtle.ConstantTimeCompare([]byte(password), []byte(p.config.InboundParsePassword)) != 1 {
+			return fmt.Errorf("inbound parse credentials do not match")
+		}
+		return nil
+	}
+
+	if p.config.AuthToken == "" {
+		return fmt.Errorf("twilio auth token not configured")
+	}
+	if p.config.WebhookURL == "" {
+		return fmt.Errorf("webhook URL not configured")
+	}
+	params, err := url.ParseQuery(string(webhook.Payload))
+	if err != nil {
+		return fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	expected := TwilioSignature(p.config.AuthToken, p.webhookURL(webhook.TenantID), params)
+	if !hmac.Equal([]byte(expected), []byte(webhook.Header.Get(TwilioSignatureHeader))) {
+		return fmt.Errorf("signature does not match")
+	}
+	return nil
+}
+
+// ParseWebhook reads a Twilio SMS webhook or a SendGrid inbound email
+func (p *TwilioMessagingProvider) ParseWebhook(webhook *MessagingWebhook) ([]*MessagingEvent, error) {
+	if isInboundParse(webhook) {
+		return parseInboundEmail(webhook)
+	}
+
+	params, err := url.ParseQuery(string(webhook.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	return parseTwilioSMS(params)
+}
+
+func (p *TwilioMessagingProvider) webhookURL(tenantID uuid.UUID) string {
+	return strings.ReplaceAll(p.config.WebhookURL, "{tenant_id}", tenantID.String())
+}
+
+// parseTwilioSMS reads an inbound SMS or a status callback. Only final statuses are
+// reported; queued, sending and sent callbacks produce no events.
+func parseTwilioSMS(params url.Values) ([]*MessagingEvent, error) {
+	sid := params.Get("MessageSid")
+	if sid == "" {
+		sid = params.Get("SmsSid")
+	}
+	if sid == "" {
+		return nil, fmt.Errorf("webhook has no message SID")
+	}
+
+	status := params.Get("MessageStatus")
+	if status == "" {
+		status = params.Get("SmsStatus")
+	}
+
+	event := &MessagingEvent{
+		Channel:           domain.CommunicationChannelSMS,
+		ProviderMessageID: sid,
+		OccurredAt:        time.Now(),
+	}
+	switch status {
+	case "", "received":
+		if params.Get("From") == "" {
+			return nil, fmt.Errorf("inbound message has no sender")
+		}
+		event.Type = MessagingEventMessage
+		event.From = params.Get("From")
+		event.To = params.Get("To")
+		event.Body = params.Get("Body")
+	case "delivered":
+		event.Type = MessagingEventStatus
+		event.Status = domain.MessageStatusDelivered
+	case "failed", "undelivered":
+		event.Type = MessagingEventStatus
+		event.Status = domain.MessageStatusFailed
+		event.Error = "message " + status
+		if code := params.Get("ErrorCode"); code != "" {
+			event.Error += " (twilio error " + code + ")"
+		}
+	default:
+		return nil, nil
+	}
+
+	return []*MessagingEvent{event}, nil
+}
+
+// parseInboundEmail reads a SendGrid Inbound Parse post. Its Message-ID becomes the
+// provider message ID so staff replies can set In-Reply-To.
+func parseInboundEmail(webhook *MessagingWebhook) ([]*MessagingEvent, error) {
+	_, params, err := mime.ParseMediaType(webhook.Header.Get("Content-Type"))
+	if err != nil || params["boundary"] == "" {
+		return nil, fmt.Errorf("invalid inbound email content type")
+	}
+
+	form, err := multipart.NewReader(bytes.NewReader(webhook.Payload), params["boundary"]).ReadForm(1 << 20)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inbound email: %w", err)
+	}
+	defer form.RemoveAll()
+
+	field := func(name string) string {
+		if values := form.Value[name]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	from := field("from")
+	if from == "" {
+		return nil, fmt.Errorf("inbound email has no sender")
+	}
+
+	event := &MessagingEvent{
+		Type:       MessagingEventMessage,
+		Channel:    domain.CommunicationChannelEmail,
+		From:       from,
+		To:         field("to"),
+		Subject:    field("subject"),
+		Body:       field("text"),
+		OccurredAt: time.Now(),
+	}
+	if headers, err := mail.ReadMessage(strings.NewReader(strings.TrimSpace(field("headers")) + "\r\n\r\n")); err == nil {
+		event.ProviderMessageID = messageIDValue(headers.Header.Get("Message-ID"))
+		event.InReplyTo = messageIDValue(headers.Header.Get("In-Reply-To"))
+	}
+
+	return []*MessagingEvent{event}, nil
+}
+
+func isInboundParse(webhook *MessagingWebhook) bool {
+	mediaType, _, _ := mime.ParseMediaType(webhook.Header.Get("Content-Type"))
+	return mediaType == "multipart/form-data"
+}
+
+// messageIDValue returns the first message ID in a header without its angle brackets
+func messageIDValue(header string) string {
+	fields := strings.Fields(header)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.Trim(fields[0], "<>")
+}
+
+func emailDomain(address string) string {
+	if at := strings.LastIndex(address, "@"); at >= 0 {
+		return strings.TrimRight(address[at+1:], ">")
+	}
+	return "localhost"
+}
+
+// TwilioSignature returns Twilio's webhook signature: the base64 HMAC-SHA1 of the
+// webhook URL followed by each POST parameter's name and value, sorted by name
+func TwilioSignature(authToken, webhookURL string, params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var signed strings.Builder
+	signed.WriteString(webhookURL)
+	for _, key := range keys {
+		values := append([]string(nil), params[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			signed.WriteString(key)
+			signed.WriteString(value)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(signed.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// TwilioPhoneNumber formats a number in E.164, assuming North American numbers when
+// no country code is given
+func TwilioPhoneNumber(number string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, number)
+	if len(digits) == 10 && !strings.HasPrefix(strings.TrimSpace(number), "+") {
+		return "+1" + digits
+	}
+	return "+" + digits
+}
@@ -0,0 +1,1403 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// PayrollService pays users from their time entries and the completed jobs
+// they worked on. Each user has a pay plan of hourly, piece-rate, revenue
+// percentage and crew-lead bonus rules. A pay period's register is calculated
+// from the plans, approved, which locks it, and exported for the payroll
+// provider.
+type PayrollService interface {
+	GetPayPlan(ctx context.Context, userID uuid.UUID) (*domain.PayPlan, error)
+	SetPayPlan(ctx context.Context, userID uuid.UUID, req *PayPlanRequest) (*domain.PayPlan, error)
+
+	CreateTimeEntry(ctx context.Context, req *TimeEntryRequest) (*domain.TimeEntry, error)
+	UpdateTimeEntry(ctx context.Context, entryID uuid.UUID, req *TimeEntryRequest) (*domain.TimeEntry, error)
+	DeleteTimeEntry(ctx context.Context, entryID uuid.UUID) error
+	ListTimeEntries(ctx context.Context, filter *TimeEntryFilter) ([]*domain.TimeEntry, error)
+
+	CreatePayPeriod(ctx context.Context, req *PayPeriodRequest) (*domain.PayPeriod, error)
+	ListPayPeriods(ctx context.Context) ([]*domain.PayPeriod, error)
+	CalculatePayPeriod(ctx context.Context, periodID uuid.UUID) (*PayrollRegister, error)
+	GetPayrollRegister(ctx context.Context, periodID uuid.UUID) (*PayrollRegister, error)
+	ApprovePayPeriod(ctx context.Context, periodID uuid.UUID) (*domain.PayPeriod, error)
+	// ReopenPayPeriod unlocks an approved period that has not been exported
+	ReopenPayPeriod(ctx context.Context, periodID uuid.UUID) (*domain.PayPeriod, error)
+	ExportPayPeriod(ctx context.Context, periodID uuid.UUID, req *PayrollExportRequest) (*PayrollExport, error)
+}
+
+// PayrollRepository defines data access for pay plans, time entries and pay periods
+type PayrollRepository interface {
+	// GetPayPlan returns the user's plan with its rules, nil when they have none
+	GetPayPlan(ctx context.Context, tenantID, userID uuid.UUID) (*domain.PayPlan, error)
+	// SavePayPlan creates or updates the plan and replaces its rules in one transaction
+	SavePayPlan(ctx context.Context, plan *domain.PayPlan) error
+	ListPayPlans(ctx context.Context, tenantID uuid.UUID) ([]*domain.PayPlan, error)
+
+	CreateTimeEntry(ctx context.Context, entry *domain.TimeEntry) error
+	GetTimeEntry(ctx context.Context, tenantID, entryID uuid.UUID) (*domain.TimeEntry, error)
+	UpdateTimeEntry(ctx context.Context, entry *domain.TimeEntry) error
+	DeleteTimeEntry(ctx context.Context, tenantID, entryID uuid.UUID) error
+	ListTimeEntries(ctx context.Context, tenantID uuid.UUID, filter *TimeEntryFilter) ([]*domain.TimeEntry, error)
+
+	CreatePayPeriod(ctx context.Context, period *domain.PayPeriod) error
+	GetPayPeriod(ctx context.Context, tenantID, periodID uuid.UUID) (*domain.PayPeriod, error)
+	UpdatePayPeriod(ctx context.Context, period *domain.PayPeriod) error
+	ListPayPeriods(ctx context.Context, tenantID uuid.UUID) ([]*domain.PayPeriod, error)
+	// ListOverlappingPayPeriods lists the periods with a day between the dates
+	ListOverlappingPayPeriods(ctx context.Context, tenantID uuid.UUID, start, end time.Time) ([]*domain.PayPeriod, error)
+
+	// ReplaceRegister swaps the period's register entries and lines and saves
+	// the period's totals in one transaction
+	ReplaceRegister(ctx context.Context, period *domain.PayPeriod, entries []*domain.PayrollRegisterEntry) error
+	// ListRegister lists the period's entries with their lines and the users' names
+	ListRegister(ctx context.Context, tenantID, periodID uuid.UUID) ([]*domain.PayrollRegisterEntry, error)
+}
+
+// Overtime is paid at time and a half for hours over 40 in a Monday to
+// Sunday week
+const (
+	PayrollOvertimeThreshold  = 40.0
+	PayrollOvertimeMultiplier = 1.5
+)
+
+// adpEarningCodes are the ADP earnings codes piece-rate, commission and bonus
+// pay are exported under. Hours go in the Reg and O/T columns.
+var adpEarningCodes = map[string]string{
+	domain.EarningPieceRate:  "P",
+	domain.EarningCommission: "C",
+	domain.EarningBonus:      "B",
+}
+
+// PayPlanRequest replaces a user's pay plan
+type PayPlanRequest struct {
+	EmployeeNumber *string          `json:"employee_number,omitempty"`
+	Rules          []PayRuleRequest `json:"rules"`
+}
+
+// PayRuleRequest is one rule in a pay plan
+type PayRuleRequest struct {
+	Type          string     `json:"type" validate:"required"`
+	Rate          float64    `json:"rate"`
+	ServiceID     *uuid.UUID `json:"service_id,omitempty"`
+	EffectiveFrom *time.Time `json:"effective_from,omitempty"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+}
+
+// TimeEntryRequest records time, for the caller unless a user is given
+type TimeEntryRequest struct {
+	UserID       *uuid.UUID `json:"user_id,omitempty"`
+	JobID        *uuid.UUID `json:"job_id,omitempty"`
+	ClockIn      time.Time  `json:"clock_in" validate:"required"`
+	ClockOut     *time.Time `json:"clock_out,omitempty"`
+	BreakMinutes int        `json:"break_minutes"`
+	Notes        *string    `json:"notes,omitempty"`
+}
+
+// TimeEntryFilter narrows time entries by user, job and clock-in date range
+type TimeEntryFilter struct {
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+	JobID  *uuid.UUID `json:"job_id,omitempty"`
+	From   *time.Time `json:"from,omitempty"`
+	To     *time.Time `json:"to,omitempty"`
+}
+
+// PayPeriodRequest opens a pay period over the dates, both included
+type PayPeriodRequest struct {
+	StartDate time.Time `json:"start_date" validate:"required"`
+	EndDate   time.Time `json:"end_date" validate:"required"`
+}
+
+// PayrollRegister is a pay period and what everyone in it earned
+type PayrollRegister struct {
+	Period  *domain.PayPeriod              `json:"period"`
+	Entries []*domain.PayrollRegisterEntry `json:"entries"`
+}
+
+// PayrollExportRequest picks the export format. ADP needs the company code
+// and takes an optional batch ID.
+type PayrollExportRequest struct {
+	Format      string `json:"format" validate:"required"`
+	CompanyCode string `json:"company_code,omitempty"`
+	BatchID     string `json:"batch_id,omitempty"`
+}
+
+// PayrollExport is a generated payroll file
+type PayrollExport struct {
+	Format      string `json:"format"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"-"`
+	EntryCount  int    `json:"entry_count"`
+}
+
+// PayrollInput is everything CalculatePayroll needs. Jobs are the completed
+// jobs with their service lines; Users supplies names and is optional.
+type PayrollInput struct {
+	Period      *domain.PayPeriod
+	Plans       []*domain.PayPlan
+	TimeEntries []*domain.TimeEntry
+	Jobs        []*PayrollJob
+	Services    map[uuid.UUID]*domain.Service
+	Users       map[uuid.UUID]*domain.User
+}
+
+// PayrollJob is a completed job and its service lines
+type PayrollJob struct {
+	Job      *domain.EnhancedJob
+	Services []*domain.JobService
+}
+
+// payrollRateHours are a user's regular and overtime hours at one hourly rate
+type payrollRateHours struct {
+	regular  float64
+	overtime float64
+}
+
+// PayrollServiceImpl implements PayrollService
+type PayrollServiceImpl struct {
+	payrollRepo  PayrollRepository
+	jobRepo      JobRepositoryComplete
+	serviceRepo  ServiceRepository
+	userRepo     UserRepository
+	auditService AuditService
+	logger       *log.Logger
+}
+
+// NewPayrollService creates a new payroll service
+func NewPayrollService(
+	payrollRepo PayrollRepository,
+	jobRepo JobRepositoryComplete,
+	serviceRepo ServiceRepository,
+	userRepo UserRepository,
+	auditService AuditService,
+	logger *log.Logger,
+) PayrollService {
+	return &PayrollServiceImpl{
+		payrollRepo:  payrollRepo,
+		jobRepo:      jobRepo,
+		serviceRepo:  serviceRepo,
+		userRepo:     userRepo,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// GetPayPlan returns a user's pay plan, empty if they have none
+func (s *PayrollServiceImpl) GetPayPlan(ctx context.Context, userID uuid.UUID) (*domain.PayPlan, error) {
+	tenantID, err := s.checkUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.payrollRepo.GetPayPlan(ctx, tenantID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pay plan: %w", err)
+	}
+	if plan == nil {
+		plan = &domain.PayPlan{TenantID: tenantID, UserID: userID, Rules: []*domain.PayRule{}}
+	}
+
+	return plan, nil
+}
+
+// SetPayPlan replaces a user's employee number and pay rules. Registers
+// already calculated keep the pay they were calculated with.
+func (s *PayrollServiceImpl) SetPayPlan(ctx context.Context, userID uuid.UUID, req *PayPlanRequest) (*domain.PayPlan, error) {
+	if err := ValidatePayPlan(req); err != nil {
+		return nil, err
+	}
+
+	tenantID, err := s.checkUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.payrollRepo.GetPayPlan(ctx, tenantID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pay plan: %w", err)
+	}
+
+	now := time.Now()
+	plan := &domain.PayPlan{
+		ID:             uuid.New(),
+		TenantID:       tenantID,
+		UserID:         userID,
+		EmployeeNumber: req.EmployeeNumber,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Rules:          make([]*domain.PayRule, 0, len(req.Rules)),
+	}
+	if existing != nil {
+		plan.ID = existing.ID
+		plan.CreatedAt = existing.CreatedAt
+	}
+	for _, rule := range req.Rules {
+		plan.Rules = append(plan.Rules, &domain.PayRule{
+			ID:            uuid.New(),
+			TenantID:      tenantID,
+			UserID:        userID,
+			Type:          rule.Type,
+			Rate:          rule.Rate,
+			ServiceID:     rule.ServiceID,
+			EffectiveFrom: rule.EffectiveFrom,
+			EffectiveTo:   rule.EffectiveTo,
+			CreatedAt:     now,
+		})
+	}
+
+	if err := s.payrollRepo.SavePayPlan(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to save pay plan: %w", err)
+	}
+
+	var oldRules []*domain.PayRule
+	if existing != nil {
+		oldRules = existing.Rules
+	}
+	s.logPayrollAction(ctx, "pay_plan.set", "user", userID,
+		map[string]interface{}{"rules": oldRules},
+		map[string]interface{}{"employee_number": plan.EmployeeNumber, "rules": plan.Rules})
+
+	return plan, nil
+}
+
+// CreateTimeEntry records time for a user. Time cannot be added to an
+// approved pay period or overlap the user's other entries.
+func (s *PayrollServiceImpl) CreateTimeEntry(ctx context.Context, req *TimeEntryRequest) (*domain.TimeEntry, error) {
+	if err := ValidateTimeEntry(req); err != nil {
+		return nil, err
+	}
+
+	userID := GetUserIDFromContext(ctx)
+	if req.UserID != nil {
+		userID = req.UserID
+	}
+	if userID == nil {
+		return nil, fmt.Errorf("validation failed: user_id is required")
+	}
+
+	tenantID, err := s.checkUser(ctx, *userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entry := &domain.TimeEntry{
+		ID:           uuid.New(),
+		TenantID:     tenantID,
+		UserID:       *userID,
+		JobID:        req.JobID,
+		ClockIn:      req.ClockIn,
+		ClockOut:     req.ClockOut,
+		BreakMinutes: req.BreakMinutes,
+		Notes:        req.Notes,
+		CreatedBy:    GetUserIDFromContext(ctx),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := s.checkTimeEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	if err := s.payrollRepo.CreateTimeEntry(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to create time entry: %w", err)
+	}
+
+	s.logPayrollAction(ctx, "time_entry.create", "time_entry", entry.ID, nil, map[string]interface{}{
+		"user_id":   entry.UserID,
+		"job_id":    entry.JobID,
+		"clock_in":  entry.ClockIn,
+		"clock_out": entry.ClockOut,
+	})
+
+	return entry, nil
+}
+
+// UpdateTimeEntry changes an entry's times, job and notes, such as to clock out
+func (s *PayrollServiceImpl) UpdateTimeEntry(ctx context.Context, entryID uuid.UUID, req *TimeEntryRequest) (*domain.TimeEntry, error) {
+	if err := ValidateTimeEntry(req); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.getTimeEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUnlocked(ctx, entry.TenantID, entry.ClockIn); err != nil {
+		return nil, err
+	}
+
+	old := map[string]interface{}{
+		"job_id":        entry.JobID,
+		"clock_in":      entry.ClockIn,
+		"clock_out":     entry.ClockOut,
+		"break_minutes": entry.BreakMinutes,
+	}
+
+	entry.JobID = req.JobID
+	entry.ClockIn = req.ClockIn
+	entry.ClockOut = req.ClockOut
+	entry.BreakMinutes = req.BreakMinutes
+	entry.Notes = req.Notes
+	entry.UpdatedAt = time.Now()
+
+	if err := s.checkTimeEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	if err := s.payrollRepo.UpdateTimeEntry(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to update time entry: %w", err)
+	}
+
+	s.logPayrollAction(ctx, "time_entry.update", "time_entry", entry.ID, old, map[string]interface{}{
+		"job_id":        entry.JobID,
+		"clock_in":      entry.ClockIn,
+		"clock_out":     entry.ClockOut,
+		"break_minutes": entry.BreakMinutes,
+	})
+
+	return entry, nil
+}
+
+// DeleteTimeEntry deletes an entry outside any approved pay period
+func (s *PayrollServiceImpl) DeleteTimeEntry(ctx context.Context, entryID uuid.UUID) error {
+	entry, err := s.getTimeEntry(ctx, entryID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkUnlocked(ctx, entry.TenantID, entry.ClockIn); err != nil {
+		return err
+	}
+
+	if err := s.payrollRepo.DeleteTimeEntry(ctx, entry.TenantID, entry.ID); err != nil {
+		return fmt.Errorf("failed to delete time entry: %w", err)
+	}
+
+	s.logPayrollAction(ctx, "time_entry.delete", "time_entry", entry.ID, map[string]interface{}{
+		"user_id":   entry.UserID,
+		"clock_in":  entry.ClockIn,
+		"clock_out": entry.ClockOut,
+	}, nil)
+
+	return nil
+}
+
+// ListTimeEntries lists time entries, latest first
+func (s *PayrollServiceImpl) ListTimeEntries(ctx context.Context, filter *TimeEntryFilter) ([]*domain.TimeEntry, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if filter == nil {
+		filter = &TimeEntryFilter{}
+	}
+
+	entries, err := s.payrollRepo.ListTimeEntries(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CreatePayPeriod opens a draft pay period. Periods cannot overlap.
+func (s *PayrollServiceImpl) CreatePayPeriod(ctx context.Context, req *PayPeriodRequest) (*domain.PayPeriod, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if req.StartDate.IsZero() || req.EndDate.IsZero() {
+		return nil, fmt.Errorf("validation failed: start_date and end_date are required")
+	}
+	start, end := startOfDay(req.StartDate), startOfDay(req.EndDate)
+	if end.Before(start) {
+		return nil, fmt.Errorf("validation failed: pay period must end on or after it starts")
+	}
+
+	overlapping, err := s.payrollRepo.ListOverlappingPayPeriods(ctx, tenantID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check pay periods: %w", err)
+	}
+	if len(overlapping) > 0 {
+		return nil, fmt.Errorf("pay period cannot be created: it overlaps the period from %s to %s",
+			overlapping[0].StartDate.Format("2006-01-02"), overlapping[0].EndDate.Format("2006-01-02"))
+	}
+
+	now := time.Now()
+	period := &domain.PayPeriod{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		StartDate: start,
+		EndDate:   end,
+		Status:    domain.PayPeriodDraft,
+		CreatedBy: GetUserIDFromContext(ctx),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.payrollRepo.CreatePayPeriod(ctx, period); err != nil {
+		return nil, fmt.Errorf("failed to create pay period: %w", err)
+	}
+
+	s.logPayrollAction(ctx, "pay_period.create", "pay_period", period.ID, nil, map[string]interface{}{
+		"start_date": period.StartDate.Format("2006-01-02"),
+		"end_date":   period.EndDate.Format("2006-01-02"),
+	})
+
+	return period, nil
+}
+
+// ListPayPeriods lists the tenant's pay periods, latest first
+func (s *PayrollServiceImpl) ListPayPeriods(ctx context.Context) ([]*domain.PayPeriod, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	periods, err := s.payrollRepo.ListPayPeriods(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pay periods: %w", err)
+	}
+
+	return periods, nil
+}
+
+// CalculatePayPeriod builds the register for a draft period from the pay
+// plans, the period's time entries and the jobs completed in it, replacing
+// any earlier calculation
+func (s *PayrollServiceImpl) CalculatePayPeriod(ctx context.Context, periodID uuid.UUID) (*PayrollRegister, error) {
+	period, err := s.getPayPeriod(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+	if period.Status != domain.PayPeriodDraft {
+		return nil, fmt.Errorf("pay period cannot be recalculated: it is %s", period.Status)
+	}
+
+	input, err := s.loadPayrollInput(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+	entries := CalculatePayroll(input)
+
+	now := time.Now()
+	period.TotalGross = 0
+	for _, entry := range entries {
+		period.TotalGross += entry.GrossPay
+	}
+	period.TotalGross = roundCents(period.TotalGross)
+	period.CalculatedAt = &now
+	period.UpdatedAt = now
+
+	if err := s.payrollRepo.ReplaceRegister(ctx, period, entries); err != nil {
+		return nil, fmt.Errorf("failed to save payroll register: %w", err)
+	}
+
+	s.logPayrollAction(ctx, "pay_period.calculate", "pay_period", period.ID, nil, map[string]interface{}{
+		"entries":     len(entries),
+		"total_gross": period.TotalGross,
+	})
+
+	return &PayrollRegister{Period: period, Entries: entries}, nil
+}
+
+// GetPayrollRegister returns the period's last calculated register
+func (s *PayrollServiceImpl) GetPayrollRegister(ctx context.Context, periodID uuid.UUID) (*PayrollRegister, error) {
+	period, err := s.getPayPeriod(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.payrollRepo.ListRegister(ctx, period.TenantID, period.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payroll register: %w", err)
+	}
+
+	return &PayrollRegister{Period: period, Entries: entries}, nil
+}
+
+// ApprovePayPeriod locks a calculated period
+func (s *PayrollServiceImpl) ApprovePayPeriod(ctx context.Context, periodID uuid.UUID) (*domain.PayPeriod, error) {
+	period, err := s.getPayPeriod(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+	if period.Status != domain.PayPeriodDraft {
+		return nil, fmt.Errorf("pay period cannot be approved: it is %s", period.Status)
+	}
+	if period.CalculatedAt == nil {
+		return nil, fmt.Errorf("pay period cannot be approved: it has not been calculated")
+	}
+
+	now := time.Now()
+	period.Status = domain.PayPeriodApproved
+	period.ApprovedBy = GetUserIDFromContext(ctx)
+	period.ApprovedAt = &now
+	period.UpdatedAt = now
+	if err := s.payrollRepo.UpdatePayPeriod(ctx, period); err != nil {
+		return nil, fmt.Errorf("failed to update pay period: %w", err)
+	}
+
+	s.logPayrollAction(ctx, "pay_period.approve", "pay_period", period.ID,
+		map[string]interface{}{"status": domain.PayPeriodDraft},
+		map[string]interface{}{"status": period.Status, "total_gross": period.TotalGross})
+
+	return period, nil
+}
+
+// ReopenPayPeriod returns an approved period to draft so it can be corrected.
+// Exported periods stay locked.
+func (s *PayrollServiceImpl) ReopenPayPeriod(ctx context.Context, periodID uuid.UUID) (*domain.PayPeriod, error) {
+	period, err := s.getPayPeriod(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+	if period.Status != domain.PayPeriodApproved {
+		return nil, fmt.Errorf("pay period cannot be reopened: it is %s", period.Status)
+	}
+	if period.ExportedAt != nil {
+		return nil, fmt.Errorf("pay period cannot be reopened: it was exported on %s", period.ExportedAt.Format("2006-01-02"))
+	}
+
+	period.Status = domain.PayPeriodDraft
+	period.ApprovedBy = nil
+	period.ApprovedAt = nil
+	period.UpdatedAt = time.Now()
+	if err := s.payrollRepo.UpdatePayPeriod(ctx, period); err != nil {
+		return nil, fmt.Errorf("failed to update pay period: %w", err)
+	}
+
+	s.logPayrollAction(ctx, "pay_period.reopen", "pay_period", period.ID,
+		map[string]interface{}{"status": domain.PayPeriodApproved},
+		map[string]interface{}{"status": period.Status})
+
+	return period, nil
+}
+
+// ExportPayPeriod generates a payroll file for an approved period
+func (s *PayrollServiceImpl) ExportPayPeriod(ctx context.Context, periodID uuid.UUID, req *PayrollExportRequest) (*PayrollExport, error) {
+	period, err := s.getPayPeriod(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+	if period.Status != domain.PayPeriodApproved {
+		return nil, fmt.Errorf("pay period cannot be exported: it must be approved first")
+	}
+
+	entries, err := s.payrollRepo.ListRegister(ctx, period.TenantID, period.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payroll register: %w", err)
+	}
+
+	export := &PayrollExport{Format: req.Format, EntryCount: len(entries)}
+	name := fmt.Sprintf("%s_%s", period.StartDate.Format("20060102"), period.EndDate.Format("20060102"))
+
+	switch req.Format {
+	case domain.PayrollExportFormatRegisterCSV:
+		if export.Data, err = BuildPayrollRegisterCSV(entries); err != nil {
+			return nil, fmt.Errorf("failed to build payroll register export: %w", err)
+		}
+		export.Filename = "payroll_register_" + name + ".csv"
+	case domain.PayrollExportFormatADPCSV:
+		if req.CompanyCode == "" {
+			return nil, fmt.Errorf("validation failed: company_code is required for ADP exports")
+		}
+		if export.Data, err = BuildADPPaydataCSV(entries, req.CompanyCode, req.BatchID); err != nil {
+			return nil, fmt.Errorf("failed to build ADP export: %w", err)
+		}
+		export.Filename = "adp_paydata_" + name + ".csv"
+	case domain.PayrollExportFormatGustoCSV:
+		if export.Data, err = BuildGustoHoursCSV(entries); err != nil {
+			return nil, fmt.Errorf("failed to build Gusto export: %w", err)
+		}
+		export.Filename = "gusto_hours_" + name + ".csv"
+	default:
+		return nil, fmt.Errorf("validation failed: unsupported export format: %s", req.Format)
+	}
+	export.ContentType = "text/csv"
+
+	now := time.Now()
+	period.ExportedAt = &now
+	period.UpdatedAt = now
+	if err := s.payrollRepo.UpdatePayPeriod(ctx, period); err != nil {
+		return nil, fmt.Errorf("failed to update pay period: %w", err)
+	}
+
+	s.logPayrollAction(ctx, "pay_period.export", "pay_period", period.ID, nil, map[string]interface{}{
+		"format":  req.Format,
+		"entries": export.EntryCount,
+	})
+
+	return export, nil
+}
+
+// ValidatePayPlan checks each rule has a known type and a sensible rate, and
+// that no two hourly rules are in effect at once
+func ValidatePayPlan(req *PayPlanRequest) error {
+	for i, rule := range req.Rules {
+		if !containsString(domain.PayRuleTypes, rule.Type) {
+			return fmt.Errorf("validation failed: unknown pay rule type %q", rule.Type)
+		}
+		if rule.Rate < 0 {
+			return fmt.Errorf("validation failed: %s rate cannot be negative", rule.Type)
+		}
+		if rule.Type == domain.PayRuleRevenuePercent && rule.Rate > 100 {
+			return fmt.Errorf("validation failed: revenue percentage cannot be over 100")
+		}
+		if rule.ServiceID != nil && rule.Type != domain.PayRulePieceRate {
+			return fmt.Errorf("validation failed: only piece rates are for a service")
+		}
+		if rule.EffectiveFrom != nil && rule.EffectiveTo != nil && rule.EffectiveTo.Before(*rule.EffectiveFrom) {
+			return fmt.Errorf("validation failed: %s rule must end on or after it starts", rule.Type)
+		}
+
+		if rule.Type != domain.PayRuleHourly {
+			continue
+		}
+		for _, other := range req.Rules[i+1:] {
+			if other.Type == domain.PayRuleHourly && payRulesOverlap(rule, other) {
+				return fmt.Errorf("validation failed: only one hourly rate can be in effect at a time")
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateTimeEntry checks an entry clocks out after it clocks in, with
+// breaks shorter than the time worked
+func ValidateTimeEntry(req *TimeEntryRequest) error {
+	if req.ClockIn.IsZero() {
+		return fmt.Errorf("validation failed: clock_in is required")
+	}
+	if req.BreakMinutes < 0 {
+		return fmt.Errorf("validation failed: break_minutes cannot be negative")
+	}
+	if req.ClockOut == nil {
+		return nil
+	}
+	if !req.ClockOut.After(req.ClockIn) {
+		return fmt.Errorf("validation failed: clock_out must be after clock_in")
+	}
+	if time.Duration(req.BreakMinutes)*time.Minute >= req.ClockOut.Sub(req.ClockIn) {
+		return fmt.Errorf("validation failed: breaks must be shorter than the time worked")
+	}
+	return nil
+}
+
+// CalculatePayroll works out everyone's pay for the period.
+//
+// Hourly rules pay the closed time entries clocked in during the period at the
+// rate in effect that day, with hours over the weekly threshold at the
+// overtime multiplier. Completed jobs are credited to the users who clocked
+// time on them, split by their share of the hours, or to the assigned user
+// when nobody did. Piece rates pay the credited share of each matching service
+// line's quantity, or of the job itself for rules without a service; revenue
+// percentages pay the share of the job's revenue; and the crew-lead bonus is
+// paid to the assigned user of each job others worked on. Rules apply on the
+// day the job was completed.
+func CalculatePayroll(input *PayrollInput) []*domain.PayrollRegisterEntry {
+	period := input.Period
+	entries := make(map[uuid.UUID]*domain.PayrollRegisterEntry)
+	entryFor := func(userID uuid.UUID) *domain.PayrollRegisterEntry {
+		if entry, ok := entries[userID]; ok {
+			return entry
+		}
+		entry := &domain.PayrollRegisterEntry{
+			ID:          uuid.New(),
+			TenantID:    period.TenantID,
+			PayPeriodID: period.ID,
+			UserID:      userID,
+			Lines:       []*domain.PayrollLine{},
+		}
+		if user := input.Users[userID]; user != nil {
+			entry.FirstName = user.FirstName
+			entry.LastName = user.LastName
+		}
+		entries[userID] = entry
+		return entry
+	}
+
+	plans := make(map[uuid.UUID]*domain.PayPlan, len(input.Plans))
+	for _, plan := range input.Plans {
+		plans[plan.UserID] = plan
+		entryFor(plan.UserID).EmployeeNumber = plan.EmployeeNumber
+	}
+
+	// Hours, a week at a time for overtime
+	timeEntries := make([]*domain.TimeEntry, 0, len(input.TimeEntries))
+	for _, timeEntry := range input.TimeEntries {
+		if timeEntry.ClockOut != nil && period.Contains(timeEntry.ClockIn) {
+			timeEntries = append(timeEntries, timeEntry)
+		}
+	}
+	sort.SliceStable(timeEntries, func(i, j int) bool {
+		return timeEntries[i].ClockIn.Before(timeEntries[j].ClockIn)
+	})
+
+	hourly := make(map[uuid.UUID]map[float64]*payrollRateHours)
+	weekHours := make(map[string]float64)
+	for _, timeEntry := range timeEntries {
+		hours := timeEntry.Hours()
+		entry := entryFor(timeEntry.UserID)
+		entry.HoursWorked += hours
+
+		rule := effectivePayRule(plans[timeEntry.UserID], domain.PayRuleHourly, timeEntry.ClockIn)
+		if rule == nil {
+			continue
+		}
+
+		year, week := timeEntry.ClockIn.ISOWeek()
+		key := fmt.Sprintf("%s:%d-%d", timeEntry.UserID, year, week)
+		regular := hours
+		if remaining := PayrollOvertimeThreshold - weekHours[key]; regular > remaining {
+			regular = remaining
+			if regular < 0 {
+				regular = 0
+			}
+		}
+		weekHours[key] += hours
+
+		if hourly[timeEntry.UserID] == nil {
+			hourly[timeEntry.UserID] = make(map[float64]*payrollRateHours)
+		}
+		if hourly[timeEntry.UserID][rule.Rate] == nil {
+			hourly[timeEntry.UserID][rule.Rate] = &payrollRateHours{}
+		}
+		hourly[timeEntry.UserID][rule.Rate].regular += regular
+		hourly[timeEntry.UserID][rule.Rate].overtime += hours - regular
+	}
+
+	for userID, rates := range hourly {
+		entry := entryFor(userID)
+		for _, rate := range sortedRates(rates) {
+			hours := rates[rate]
+			if hours.regular > 0 {
+				entry.Lines = append(entry.Lines, &domain.PayrollLine{
+					ID:          uuid.New(),
+					EntryID:     entry.ID,
+					Type:        domain.EarningRegular,
+					Description: "Regular hours",
+					Quantity:    roundHours(hours.regular),
+					Rate:        rate,
+					Amount:      roundCents(hours.regular * rate),
+				})
+			}
+			if hours.overtime > 0 {
+				overtimeRate := rate * PayrollOvertimeMultiplier
+				entry.Lines = append(entry.Lines, &domain.PayrollLine{
+					ID:          uuid.New(),
+					EntryID:     entry.ID,
+					Type:        domain.EarningOvertime,
+					Description: "Overtime hours",
+					Quantity:    roundHours(hours.overtime),
+					Rate:        overtimeRate,
+					Amount:      roundCents(hours.overtime * overtimeRate),
+				})
+			}
+		}
+	}
+
+	// Completed jobs
+	for _, payrollJob := range input.Jobs {
+		job := payrollJob.Job
+		completedOn, ok := jobCompletedOn(job)
+		if !ok || !period.Contains(completedOn) {
+			continue
+		}
+
+		shares := JobCreditShares(job, input.TimeEntries)
+		revenue := 0.0
+		for _, line := range payrollJob.Services {
+			revenue += line.TotalPrice
+		}
+		if len(payrollJob.Services) == 0 && job.TotalAmount != nil {
+			revenue = *job.TotalAmount
+		}
+
+		for _, userID := range sortedUserIDs(shares) {
+			share := shares[userID]
+			plan := plans[userID]
+			if plan == nil {
+				continue
+			}
+			entry := entryFor(userID)
+			jobID := job.ID
+
+			for _, rule := range plan.Rules {
+				if !rule.IsEffectiveOn(completedOn) {
+					continue
+				}
+				switch rule.Type {
+				case domain.PayRulePieceRate:
+					if rule.ServiceID == nil {
+						entry.Lines = append(entry.Lines, &domain.PayrollLine{
+							ID:          uuid.New(),
+							EntryID:     entry.ID,
+							Type:        domain.EarningPieceRate,
+							Description: "Per job: " + job.Title,
+							JobID:       &jobID,
+							Quantity:    roundHours(share),
+							Rate:        rule.Rate,
+							Amount:      roundCents(share * rule.Rate),
+						})
+						continue
+					}
+					for _, line := range payrollJob.Services {
+						if line.ServiceID != *rule.ServiceID {
+							continue
+						}
+						serviceID := line.ServiceID
+						quantity := line.Quantity * share
+						entry.Lines = append(entry.Lines, &domain.PayrollLine{
+							ID:          uuid.New(),
+							EntryID:     entry.ID,
+							Type:        domain.EarningPieceRate,
+							Description: pieceRateDescription(input.Services[serviceID], job),
+							JobID:       &jobID,
+							ServiceID:   &serviceID,
+							Quantity:    roundHours(quantity),
+							Rate:        rule.Rate,
+							Amount:      roundCents(quantity * rule.Rate),
+						})
+					}
+				case domain.PayRuleRevenuePercent:
+					if revenue == 0 {
+						continue
+					}
+					base := revenue * share
+					entry.Lines = append(entry.Lines, &domain.PayrollLine{
+						ID:          uuid.New(),
+						EntryID:     entry.ID,
+						Type:        domain.EarningCommission,
+						Description: fmt.Sprintf("%s%% of %s", strconv.FormatFloat(rule.Rate, 'f', -1, 64), job.Title),
+						JobID:       &jobID,
+						Quantity:    roundCents(base),
+						Rate:        rule.Rate,
+						Amount:      roundCents(base * rule.Rate / 100),
+					})
+				case domain.PayRuleCrewLeadBonus:
+					if job.AssignedUserID == nil || *job.AssignedUserID != userID || len(shares) < 2 {
+						continue
+					}
+					entry.Lines = append(entry.Lines, &domain.PayrollLine{
+						ID:          uuid.New(),
+						EntryID:     entry.ID,
+						Type:        domain.EarningBonus,
+						Description: "Crew lead: " + job.Title,
+						JobID:       &jobID,
+						Quantity:    1,
+						Rate:        rule.Rate,
+						Amount:      roundCents(rule.Rate),
+					})
+				}
+			}
+		}
+	}
+
+	register := make([]*domain.PayrollRegisterEntry, 0, len(entries))
+	for _, entry := range entries {
+		SummarizePayrollEntry(entry)
+		register = append(register, entry)
+	}
+	sort.Slice(register, func(i, j int) bool {
+		if register[i].LastName != register[j].LastName {
+			return register[i].LastName < register[j].LastName
+		}
+		if register[i].FirstName != register[j].FirstName {
+			return register[i].FirstName < register[j].FirstName
+		}
+		return register[i].UserID.String() < register[j].UserID.String()
+	})
+
+	return register
+}
+
+// JobCreditShares splits credit for a job between the users who clocked time
+// on it, by their share of the hours. With no time on the job it all goes to
+// the assigned user. Lead bonuses are paid on jobs with more than one share, so
+// an assigned lead who clocked no time still gets a share entry of zero when
+// others worked the job.
+func JobCreditShares(job *domain.EnhancedJob, timeEntries []*domain.TimeEntry) map[uuid.UUID]float64 {
+	hours := make(map[uuid.UUID]float64)
+	total := 0.0
+	for _, timeEntry := range timeEntries {
+		if timeEntry.JobID == nil || *timeEntry.JobID != job.ID {
+			continue
+		}
+		worked := timeEntry.Hours()
+		if worked <= 0 {
+			continue
+		}
+		hours[timeEntry.UserID] += worked
+		total += worked
+	}
+
+	shares := make(map[uuid.UUID]float64, len(hours)+1)
+	if total == 0 {
+		if job.AssignedUserID != nil {
+			shares[*job.AssignedUserID] = 1
+		}
+		return shares
+	}
+
+	for userID, worked := range hours {
+		shares[userID] = worked / total
+	}
+	if job.AssignedUserID != nil {
+		if _, ok := shares[*job.AssignedUserID]; !ok {
+			shares[*job.AssignedUserID] = 0
+		}
+	}
+	return shares
+}
+
+// SummarizePayrollEntry totals an entry's hours and pay by earning type from
+// its lines
+func SummarizePayrollEntry(entry *domain.PayrollRegisterEntry) {
+	entry.RegularHours, entry.OvertimeHours = 0, 0
+	entry.RegularPay, entry.OvertimePay, entry.PieceRatePay, entry.CommissionPay, entry.BonusPay = 0, 0, 0, 0, 0
+
+	for _, line := range entry.Lines {
+		switch line.Type {
+		case domain.EarningRegular:
+			entry.RegularHours += line.Quantity
+			entry.RegularPay += line.Amount
+		case domain.EarningOvertime:
+			entry.OvertimeHours += line.Quantity
+			entry.OvertimePay += line.Amount
+		case domain.EarningPieceRate:
+			entry.PieceRatePay += line.Amount
+		case domain.EarningCommission:
+			entry.CommissionPay += line.Amount
+		case domain.EarningBonus:
+			entry.BonusPay += line.Amount
+		}
+	}
+
+	entry.HoursWorked = roundHours(entry.HoursWorked)
+	entry.RegularHours = roundHours(entry.RegularHours)
+	entry.OvertimeHours = roundHours(entry.OvertimeHours)
+	entry.RegularPay = roundCents(entry.RegularPay)
+	entry.OvertimePay = roundCents(entry.OvertimePay)
+	entry.PieceRatePay = roundCents(entry.PieceRatePay)
+	entry.CommissionPay = roundCents(entry.CommissionPay)
+	entry.BonusPay = roundCents(entry.BonusPay)
+	entry.GrossPay = roundCents(entry.RegularPay + entry.OvertimePay + entry.PieceRatePay + entry.CommissionPay + entry.BonusPay)
+}
+
+// BuildPayrollRegisterCSV writes every line of the register, one row per earning
+func BuildPayrollRegisterCSV(entries []*domain.PayrollRegisterEntry) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+
+	if err := w.Write([]string{"Employee Number", "Last Name", "First Name", "Earning", "Description", "Job ID", "Quantity", "Rate", "Amount"}); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		for _, line := range entry.Lines {
+			jobID := ""
+			if line.JobID != nil {
+				jobID = line.JobID.String()
+			}
+			if err := w.Write([]string{
+				stringValue(entry.EmployeeNumber),
+				entry.LastName,
+				entry.FirstName,
+				line.Type,
+				line.Description,
+				jobID,
+				strconv.FormatFloat(line.Quantity, 'f', -1, 64),
+				strconv.FormatFloat(line.Rate, 'f', -1, 64),
+				formatAmount(line.Amount),
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// BuildADPPaydataCSV writes an ADP paydata import. Each employee's first row
+// has their regular and overtime hours; piece-rate, commission and bonus pay
+// follow under their earnings codes. Every employee needs an employee number
+// for the File # column.
+func BuildADPPaydataCSV(entries []*domain.PayrollRegisterEntry, companyCode, batchID string) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+
+	if err := w.Write([]string{"Co Code", "Batch ID", "File #", "Reg Hours", "O/T Hours", "Earnings 3 Code", "Earnings 3 Amount"}); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.GrossPay == 0 && entry.RegularHours == 0 && entry.OvertimeHours == 0 {
+			continue
+		}
+		if entry.EmployeeNumber == nil || *entry.EmployeeNumber == "" {
+			return nil, fmt.Errorf("%s %s has no employee number", entry.FirstName, entry.LastName)
+		}
+
+		earnings := []struct {
+			code   string
+			amount float64
+		}{
+			{adpEarningCodes[domain.EarningPieceRate], entry.PieceRatePay},
+			{adpEarningCodes[domain.EarningCommission], entry.CommissionPay},
+			{adpEarningCodes[domain.EarningBonus], entry.BonusPay},
+		}
+
+		row := []string{companyCode, batchID, *entry.EmployeeNumber, formatHours(entry.RegularHours), formatHours(entry.OvertimeHours), "", ""}
+		for _, earning := range earnings {
+			if earning.amount == 0 {
+				continue
+			}
+			if row[5] != "" {
+				if err := w.Write(row); err != nil {
+					return nil, err
+				}
+				row = []string{companyCode, batchID, *entry.EmployeeNumber, "", "", "", ""}
+			}
+			row[5] = earning.code
+			row[6] = formatAmount(earning.amount)
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// BuildGustoHoursCSV writes a Gusto hours and earnings import, one row per
+// employee. Gusto's import has no piece-rate column, so piece-rate pay is
+// reported with commission.
+func BuildGustoHoursCSV(entries []*domain.PayrollRegisterEntry) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+
+	if err := w.Write([]string{"last_name", "first_name", "employee_id", "regular_hours", "overtime_hours", "bonus", "commission"}); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if err := w.Write([]string{
+			entry.LastName,
+			entry.FirstName,
+			stringValue(entry.EmployeeNumber),
+			formatHours(entry.RegularHours),
+			formatHours(entry.OvertimeHours),
+			formatAmount(entry.BonusPay),
+			formatAmount(entry.PieceRatePay + entry.CommissionPay),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// loadPayrollInput gathers the plans, time entries and completed jobs for the period
+func (s *PayrollServiceImpl) loadPayrollInput(ctx context.Context, period *domain.PayPeriod) (*PayrollInput, error) {
+	tenantID := period.TenantID
+	from, to := period.StartDate, period.EndDate.AddDate(0, 0, 1)
+
+	plans, err := s.payrollRepo.ListPayPlans(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pay plans: %w", err)
+	}
+	timeEntries, err := s.payrollRepo.ListTimeEntries(ctx, tenantID, &TimeEntryFilter{From: &from, To: &to})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time entries: %w", err)
+	}
+	jobs, err := s.jobRepo.GetByDateRange(ctx, tenantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs: %w", err)
+	}
+
+	input := &PayrollInput{
+		Period:      period,
+		Plans:       plans,
+		TimeEntries: timeEntries,
+		Services:    make(map[uuid.UUID]*domain.Service),
+		Users:       make(map[uuid.UUID]*domain.User),
+	}
+
+	var serviceIDs []uuid.UUID
+	for _, job := range jobs {
+		if job.Status != domain.JobStatusCompleted {
+			continue
+		}
+		lines, err := s.jobRepo.GetJobServices(ctx, job.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job services: %w", err)
+		}
+		for _, line := range lines {
+			if !containsUUID(serviceIDs, line.ServiceID) {
+				serviceIDs = append(serviceIDs, line.ServiceID)
+			}
+		}
+		input.Jobs = append(input.Jobs, &PayrollJob{Job: job, Services: lines})
+	}
+
+	if len(serviceIDs) > 0 {
+		services, err := s.serviceRepo.GetByIDs(ctx, tenantID, serviceIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get services: %w", err)
+		}
+		for _, service := range services {
+			input.Services[service.ID] = service
+		}
+	}
+
+	var userIDs []uuid.UUID
+	for _, plan := range plans {
+		userIDs = append(userIDs, plan.UserID)
+	}
+	for _, timeEntry := range timeEntries {
+		if !containsUUID(userIDs, timeEntry.UserID) {
+			userIDs = append(userIDs, timeEntry.UserID)
+		}
+	}
+	for _, userID := range userIDs {
+		user, err := s.userRepo.GetByID(ctx, tenantID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if user != nil {
+			input.Users[userID] = &user.User
+		}
+	}
+
+	return input, nil
+}
+
+// checkTimeEntry refuses entries in an approved pay period and entries
+// overlapping the user's other time
+func (s *PayrollServiceImpl) checkTimeEntry(ctx context.Context, entry *domain.TimeEntry) error {
+	if err := s.checkUnlocked(ctx, entry.TenantID, entry.ClockIn); err != nil {
+		return err
+	}
+
+	from := startOfDay(entry.ClockIn).AddDate(0, 0, -1)
+	to := startOfDay(entry.ClockIn).AddDate(0, 0, 2)
+	if entry.ClockOut != nil {
+		to = startOfDay(*entry.ClockOut).AddDate(0, 0, 1)
+	}
+	others, err := s.payrollRepo.ListTimeEntries(ctx, entry.TenantID, &TimeEntryFilter{UserID: &entry.UserID, From: &from, To: &to})
+	if err != nil {
+		return fmt.Errorf("failed to check time entries: %w", err)
+	}
+
+	for _, other := range others {
+		if other.ID == entry.ID {
+			continue
+		}
+		if other.ClockOut == nil && entry.ClockOut == nil {
+			return fmt.Errorf("time entry cannot be started: the user is already clocked in since %s", other.ClockIn.Format("Jan 2 3:04 PM"))
+		}
+		if timeEntriesOverlap(entry, other) {
+			return fmt.Errorf("time entry cannot be saved: it overlaps time from %s", other.ClockIn.Format("Jan 2 3:04 PM"))
+		}
+	}
+	return nil
+}
+
+// checkUnlocked refuses changes to time falling in an approved pay period
+func (s *PayrollServiceImpl) checkUnlocked(ctx context.Context, tenantID uuid.UUID, t time.Time) error {
+	day := startOfDay(t)
+	periods, err := s.payrollRepo.ListOverlappingPayPeriods(ctx, tenantID, day, day)
+	if err != nil {
+		return fmt.Errorf("failed to check pay periods: %w", err)
+	}
+	for _, period := range periods {
+		if period.Status == domain.PayPeriodApproved {
+			return fmt.Errorf("time entry cannot be changed: the pay period from %s to %s is approved",
+				period.StartDate.Format("2006-01-02"), period.EndDate.Format("2006-01-02"))
+		}
+	}
+	return nil
+}
+
+func (s *PayrollServiceImpl) getTimeEntry(ctx context.Context, entryID uuid.UUID) (*domain.TimeEntry, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	entry, err := s.payrollRepo.GetTimeEntry(ctx, tenantID, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entry: %w", err)
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("time entry not found")
+	}
+
+	return entry, nil
+}
+
+func (s *PayrollServiceImpl) getPayPeriod(ctx context.Context, periodID uuid.UUID) (*domain.PayPeriod, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	period, err := s.payrollRepo.GetPayPeriod(ctx, tenantID, periodID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pay period: %w", err)
+	}
+	if period == nil {
+		return nil, fmt.Errorf("pay period not found")
+	}
+
+	return period, nil
+}
+
+func (s *PayrollServiceImpl) checkUser(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return uuid.Nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, tenantID, userID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return uuid.Nil, fmt.Errorf("user not found")
+	}
+
+	return tenantID, nil
+}
+
+func (s *PayrollServiceImpl) logPayrollAction(ctx context.Context, action, resourceType string, resourceID uuid.UUID, oldValues, newValues map[string]interface{}) {
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   &resourceID,
+		OldValues:    oldValues,
+		NewValues:    newValues,
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+}
+
+// effectivePayRule returns the plan's rule of the type in effect on the date
+func effectivePayRule(plan *domain.PayPlan, ruleType string, date time.Time) *domain.PayRule {
+	if plan == nil {
+		return nil
+	}
+	for _, rule := range plan.Rules {
+		if rule.Type == ruleType && rule.IsEffectiveOn(date) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// jobCompletedOn is when a job's pay is earned: when it actually ended, or
+// the day it was scheduled
+func jobCompletedOn(job *domain.EnhancedJob) (time.Time, bool) {
+	if job.Status != domain.JobStatusCompleted {
+		return time.Time{}, false
+	}
+	if job.ActualEndTime != nil {
+		return *job.ActualEndTime, true
+	}
+	if job.ScheduledDate != nil {
+		return *job.ScheduledDate, true
+	}
+	return time.Time{}, false
+}
+
+func pieceRateDescription(service *domain.Service, job *domain.EnhancedJob) string {
+	if service == nil {
+		return "Piece rate: " + job.Title
+	}
+	if service.Unit != nil && *service.Unit != "" {
+		return fmt.Sprintf("%s per %s: %s", service.Name, *service.Unit, job.Title)
+	}
+	return fmt.Sprintf("%s: %s", service.Name, job.Title)
+}
+
+func payRulesOverlap(a, b PayRuleRequest) bool {
+	if a.EffectiveTo != nil && b.EffectiveFrom != nil && a.EffectiveTo.Before(*b.EffectiveFrom) {
+		return false
+	}
+	if b.EffectiveTo != nil && a.EffectiveFrom != nil && b.EffectiveTo.Before(*a.EffectiveFrom) {
+		return false
+	}
+	return true
+}
+
+func timeEntriesOverlap(a, b *domain.TimeEntry) bool {
+	// Running entries are open-ended
+	aEnd, bEnd := time.Time{}, time.Time{}
+	if a.ClockOut != nil {
+		aEnd = *a.ClockOut
+	}
+	if b.ClockOut != nil {
+		bEnd = *b.ClockOut
+	}
+	startsBeforeBEnds := b.ClockOut == nil || a.ClockIn.Before(bEnd)
+	bStartsBeforeAEnds := a.ClockOut == nil || b.ClockIn.Before(aEnd)
+	return startsBeforeBEnds && bStartsBeforeAEnds
+}
+
+func sortedRates(rates map[float64]*payrollRateHours) []float64 {
+	sorted := make([]float64, 0, len(rates))
+	for rate := range rates {
+		sorted = append(sorted, rate)
+	}
+	sort.Float64s(sorted)
+	return sorted
+}
+
+func sortedUserIDs(shares map[uuid.UUID]float64) []uuid.UUID {
+	sorted := make([]uuid.UUID, 0, len(shares))
+	for userID := range shares {
+		sorted = append(sorted, userID)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].String() < sorted[j].String()
+	})
+	return sorted
+}
+
+func roundHours(hours float64) float64 {
+	return roundCents(hours)
+}
+
+func formatHours(hours float64) string {
+	return strconv.FormatFloat(roundHours(hours), 'f', 2, 64)
+}
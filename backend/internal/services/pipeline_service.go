@@ -0,0 +1,1648 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// PipelineService manages the sales pipeline: configurable stages, opportunities
+// assigned to salespeople, follow-up tasks with reminders, and conversion metrics
+type PipelineService interface {
+	// Stages
+	ListStages(ctx context.Context) ([]*domain.PipelineStage, error)
+	CreateStage(ctx context.Context, req *PipelineStageRequest) (*domain.PipelineStage, error)
+	UpdateStage(ctx context.Context, stageID uuid.UUID, req *PipelineStageRequest) (*domain.PipelineStage, error)
+	ReorderStages(ctx context.Context, stageIDs []uuid.UUID) ([]*domain.PipelineStage, error)
+	DeleteStage(ctx context.Context, stageID uuid.UUID) error
+
+	// Opportunities
+	CreateOpportunity(ctx context.Context, req *OpportunityRequest) (*domain.Opportunity, error)
+	GetOpportunity(ctx context.Context, opportunityID uuid.UUID) (*domain.Opportunity, error)
+	UpdateOpportunity(ctx context.Context, opportunityID uuid.UUID, req *OpportunityRequest) (*domain.Opportunity, error)
+	ListOpportunities(ctx context.Context, filter *OpportunityFilter) (*domain.PaginatedResponse, error)
+	MoveOpportunity(ctx context.Context, opportunityID uuid.UUID, req *OpportunityMoveRequest) (*domain.Opportunity, error)
+	AssignOpportunity(ctx context.Context, opportunityID uuid.UUID, userID *uuid.UUID) (*domain.Opportunity, error)
+
+	// Follow-up tasks
+	CreateFollowUpTask(ctx context.Context, req *FollowUpTaskRequest) (*domain.FollowUpTask, error)
+	UpdateFollowUpTask(ctx context.Context, taskID uuid.UUID, req *FollowUpTaskRequest) (*domain.FollowUpTask, error)
+	CompleteFollowUpTask(ctx context.Context, taskID uuid.UUID, outcome string) (*domain.FollowUpTask, error)
+	CancelFollowUpTask(ctx context.Context, taskID uuid.UUID) (*domain.FollowUpTask, error)
+	ListFollowUpTasks(ctx context.Context, filter *FollowUpTaskFilter) (*domain.PaginatedResponse, error)
+	ProcessTaskReminders(ctx context.Context, now time.Time) error
+
+	// Metrics
+	GetPipelineMetrics(ctx context.Context, filter *PipelineMetricsFilter) (*PipelineMetrics, error)
+}
+
+// PipelineRepository defines data access for the sales pipeline
+type PipelineRepository interface {
+	ListStages(ctx context.Context, tenantID uuid.UUID) ([]*domain.PipelineStage, error)
+	CreateStage(ctx context.Context, stage *domain.PipelineStage) error
+	UpdateStage(ctx context.Context, stage *domain.PipelineStage) error
+	DeleteStage(ctx context.Context, tenantID, stageID uuid.UUID) error
+	CountOpportunitiesInStage(ctx context.Context, tenantID, stageID uuid.UUID) (int, error)
+
+	CreateOpportunity(ctx context.Context, opportunity *domain.Opportunity) error
+	GetOpportunity(ctx context.Context, tenantID, opportunityID uuid.UUID) (*domain.Opportunity, error)
+	UpdateOpportunity(ctx context.Context, opportunity *domain.Opportunity) error
+	ListOpportunities(ctx context.Context, tenantID uuid.UUID, filter *OpportunityFilter) ([]*domain.Opportunity, int64, error)
+	// ListOpportunitiesCreated returns opportunities created in a period, for metrics
+	ListOpportunitiesCreated(ctx context.Context, tenantID uuid.UUID, filter *PipelineMetricsFilter) ([]*domain.Opportunity, error)
+
+	CreateStageChange(ctx context.Context, change *domain.OpportunityStageChange) error
+	ListStageChanges(ctx context.Context, tenantID uuid.UUID, opportunityIDs []uuid.UUID) ([]*domain.OpportunityStageChange, error)
+
+	CreateTask(ctx context.Context, task *domain.FollowUpTask) error
+	GetTask(ctx context.Context, tenantID, taskID uuid.UUID) (*domain.FollowUpTask, error)
+	UpdateTask(ctx context.Context, task *domain.FollowUpTask) error
+	ListTasks(ctx context.Context, tenantID uuid.UUID, filter *FollowUpTaskFilter) ([]*domain.FollowUpTask, int64, error)
+	// ListDueReminders returns open tasks across all tenants whose reminder time has
+	// passed and no reminder has been sent
+	ListDueReminders(ctx context.Context, asOf time.Time) ([]*domain.FollowUpTask, error)
+	MarkReminderSent(ctx context.Context, tenantID, taskID uuid.UUID, sentAt time.Time) error
+}
+
+// PipelineStageRequest creates or updates a pipeline stage
+type PipelineStageRequest struct {
+	Key         string `json:"key,omitempty"`
+	Name        string `json:"name"`
+	StageType   string `json:"stage_type,omitempty"`
+	Position    *int   `json:"position,omitempty"`
+	Probability *int   `json:"probability,omitempty"`
+	IsActive    *bool  `json:"is_active,omitempty"`
+}
+
+// OpportunityRequest creates or updates an opportunity. A lead or quote fills in the
+// contact, customer and value when they aren't given.
+type OpportunityRequest struct {
+	Title             string     `json:"title"`
+	StageID           *uuid.UUID `json:"stage_id,omitempty"` // defaults to the first open stage
+	LeadID            *uuid.UUID `json:"lead_id,omitempty"`
+	CustomerID        *uuid.UUID `json:"customer_id,omitempty"`
+	PropertyID        *uuid.UUID `json:"property_id,omitempty"`
+	QuoteID           *uuid.UUID `json:"quote_id,omitempty"`
+	ContactName       *string    `json:"contact_name,omitempty"`
+	ContactEmail      *string    `json:"contact_email,omitempty"`
+	ContactPhone      *string    `json:"contact_phone,omitempty"`
+	Source            *string    `json:"source,omitempty"`
+	EstimatedValue    *float64   `json:"estimated_value,omitempty"`
+	Probability       *int       `json:"probability,omitempty"`
+	ExpectedCloseDate *time.Time `json:"expected_close_date,omitempty"`
+	AssignedTo        *uuid.UUID `json:"assigned_to,omitempty"`
+	Notes             *string    `json:"notes,omitempty"`
+}
+
+// OpportunityMoveRequest moves an opportunity to another stage. A reason is required
+// when closing as lost.
+type OpportunityMoveRequest struct {
+	StageID uuid.UUID `json:"stage_id"`
+	Reason  string    `json:"reason,omitempty"`
+	Notes   string    `json:"notes,omitempty"`
+}
+
+// OpportunityFilter filters the opportunity list
+type OpportunityFilter struct {
+	BaseFilter
+	StageID    *uuid.UUID `json:"stage_id,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	AssignedTo *uuid.UUID `json:"assigned_to,omitempty"`
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"`
+	Source     string     `json:"source,omitempty"`
+}
+
+// FollowUpTaskRequest creates or updates a follow-up task
+type FollowUpTaskRequest struct {
+	OpportunityID *uuid.UUID `json:"opportunity_id,omitempty"`
+	CustomerID    *uuid.UUID `json:"customer_id,omitempty"`
+	AssignedTo    *uuid.UUID `json:"assigned_to,omitempty"` // defaults to the opportunity's salesperson
+	Title         string     `json:"title"`
+	Description   *string    `json:"description,omitempty"`
+	TaskType      string     `json:"task_type,omitempty"`
+	DueAt         time.Time  `json:"due_at"`
+	RemindAt      *time.Time `json:"remind_at,omitempty"`
+}
+
+// FollowUpTaskFilter filters follow-up tasks
+type FollowUpTaskFilter struct {
+	BaseFilter
+	AssignedTo    *uuid.UUID `json:"assigned_to,omitempty"`
+	OpportunityID *uuid.UUID `json:"opportunity_id,omitempty"`
+	CustomerID    *uuid.UUID `json:"customer_id,omitempty"`
+	Status        string     `json:"status,omitempty"`
+	DueBefore     *time.Time `json:"due_before,omitempty"`
+}
+
+// PipelineMetricsFilter selects the opportunities measured: those created in the
+// period, optionally for one salesperson or source
+type PipelineMetricsFilter struct {
+	TimeRange
+	AssignedTo *uuid.UUID `json:"assigned_to,omitempty"`
+	Source     string     `json:"source,omitempty"`
+}
+
+// PipelineMetrics summarizes pipeline value and conversion
+type PipelineMetrics struct {
+	Period             TimeRange                    `json:"period"`
+	TotalOpportunities int                          `json:"total_opportunities"`
+	OpenCount          int                          `json:"open_count"`
+	OpenValue          float64                      `json:"open_value"`
+	WeightedValue      float64                      `json:"weighted_value"`
+	WonCount           int                          `json:"won_count"`
+	WonValue           float64                      `json:"won_value"`
+	LostCount          int                          `json:"lost_count"`
+	LostValue          float64                      `json:"lost_value"`
+	WinRate            float64                      `json:"win_rate"`        // won / closed
+	ConversionRate     float64                      `json:"conversion_rate"` // won / all opportunities
+	AverageDealSize    float64                      `json:"average_deal_size"`
+	AverageDaysToClose float64                      `json:"average_days_to_close"`
+	Stages             []PipelineStageMetrics       `json:"stages"`
+	WinReasons         []PipelineReasonCount        `json:"win_reasons"`
+	LossReasons        []PipelineReasonCount        `json:"loss_reasons"`
+	Sources            []PipelineSourceMetrics      `json:"sources"`
+	Salespeople        []PipelineSalespersonMetrics `json:"salespeople"`
+}
+
+// PipelineStageMetrics describes one stage. Reached counts opportunities that got at
+// least this far; ConversionRate is the share of those that went on to the next
+// stage, or were won for the last open stage.
+type PipelineStageMetrics struct {
+	StageID        uuid.UUID `json:"stage_id"`
+	Key            string    `json:"key"`
+	Name           string    `json:"name"`
+	StageType      string    `json:"stage_type"`
+	Count          int       `json:"count"`
+	Value          float64   `json:"value"`
+	WeightedValue  float64   `json:"weighted_value"`
+	Reached        int       `json:"reached"`
+	ConversionRate float64   `json:"conversion_rate"`
+}
+
+// PipelineReasonCount counts a win or loss reason
+type PipelineReasonCount struct {
+	Reason string  `json:"reason"`
+	Count  int     `json:"count"`
+	Value  float64 `json:"value"`
+}
+
+// PipelineSourceMetrics is conversion by lead source
+type PipelineSourceMetrics struct {
+	Source         string  `json:"source"`
+	Count          int     `json:"count"`
+	WonCount       int     `json:"won_count"`
+	WonValue       float64 `json:"won_value"`
+	ConversionRate float64 `json:"conversion_rate"`
+}
+
+// PipelineSalespersonMetrics is conversion by assigned salesperson
+type PipelineSalespersonMetrics struct {
+	UserID    *uuid.UUID `json:"user_id"`
+	OpenCount int        `json:"open_count"`
+	OpenValue float64    `json:"open_value"`
+	WonCount  int        `json:"won_count"`
+	WonValue  float64    `json:"won_value"`
+	LostCount int        `json:"lost_count"`
+	WinRate   float64    `json:"win_rate"`
+}
+
+// Suggested win and loss reasons offered when closing an opportunity; any reason
+// may be recorded
+var (
+	DefaultWinReasons  = []string{"price", "quality", "referral", "responsiveness", "existing_customer", "other"}
+	DefaultLossReasons = []string{"price", "competitor", "timing", "no_response", "out_of_service_area", "scope", "other"}
+)
+
+// DefaultPipelineStages returns the stages a tenant starts with
+func DefaultPipelineStages(tenantID uuid.UUID) []*domain.PipelineStage {
+	now := time.Now()
+	defaults := []struct {
+		key, name, stageType string
+		probability          int
+	}{
+		{"new", "New", domain.PipelineStageOpen, 10},
+		{"contacted", "Contacted", domain.PipelineStageOpen, 25},
+		{"site_visit", "Site Visit", domain.PipelineStageOpen, 50},
+		{"quoted", "Quoted", domain.PipelineStageOpen, 70},
+		{"won", "Won", domain.PipelineStageWon, 100},
+		{"lost", "Lost", domain.PipelineStageLost, 0},
+	}
+
+	stages := make([]*domain.PipelineStage, 0, len(defaults))
+	for i, d := range defaults {
+		stages = append(stages, &domain.PipelineStage{
+			ID:          uuid.New(),
+			TenantID:    tenantID,
+			Key:         d.key,
+			Name:        d.name,
+			StageType:   d.stageType,
+			Position:    i + 1,
+			Probability: d.probability,
+			IsActive:    true,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+	}
+	return stages
+}
+
+// pipelineServiceImpl implements PipelineService
+type pipelineServiceImpl struct {
+	pipelineRepo         PipelineRepository
+	leadRepo             LeadRepository
+	customerRepo         CustomerRepository
+	quoteRepo            QuoteRepositoryFull
+	userRepo             UserRepository
+	communicationService CommunicationService
+	auditService         AuditService
+	logger               *log.Logger
+}
+
+// NewPipelineService creates a new sales pipeline service
+func NewPipelineService(
+	pipelineRepo PipelineRepository,
+	leadRepo LeadRepository,
+	customerRepo CustomerRepository,
+	quoteRepo QuoteRepositoryFull,
+	userRepo UserRepository,
+	communicationService CommunicationService,
+	auditService AuditService,
+	logger *log.Logger,
+) PipelineService {
+	return &pipelineServiceImpl{
+		pipelineRepo:         pipelineRepo,
+		leadRepo:             leadRepo,
+		customerRepo:         customerRepo,
+		quoteRepo:            quoteRepo,
+		userRepo:             userRepo,
+		communicationService: communicationService,
+		auditService:         auditService,
+		logger:               logger,
+	}
+}
+
+// ListStages returns the tenant's stages in order, creating the defaults on first use
+func (s *pipelineServiceImpl) ListStages(ctx context.Context) ([]*domain.PipelineStage, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	stages, err := s.pipelineRepo.ListStages(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipeline stages: %w", err)
+	}
+	if len(stages) > 0 {
+		sortStages(stages)
+		return stages, nil
+	}
+
+	stages = DefaultPipelineStages(tenantID)
+	for _, stage := range stages {
+		if err := s.pipelineRepo.CreateStage(ctx, stage); err != nil {
+			return nil, fmt.Errorf("failed to create default pipeline stages: %w", err)
+		}
+	}
+	return stages, nil
+}
+
+// CreateStage adds a stage to the end of the pipeline unless a position is given
+func (s *pipelineServiceImpl) CreateStage(ctx context.Context, req *PipelineStageRequest) (*domain.PipelineStage, error) {
+	stages, err := s.ListStages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stage := &domain.PipelineStage{
+		ID:        uuid.New(),
+		TenantID:  stages[0].TenantID,
+		Key:       strings.TrimSpace(req.Key),
+		Name:      strings.TrimSpace(req.Name),
+		StageType: req.StageType,
+		Position:  len(stages) + 1,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if stage.Key == "" {
+		stage.Key = strings.ReplaceAll(strings.ToLower(stage.Name), " ", "_")
+	}
+	if stage.StageType == "" {
+		stage.StageType = domain.PipelineStageOpen
+	}
+	if req.Position != nil {
+		stage.Position = *req.Position
+	}
+	if req.Probability != nil {
+		stage.Probability = *req.Probability
+	}
+	if req.IsActive != nil {
+		stage.IsActive = *req.IsActive
+	}
+
+	if err := validatePipelineStage(stage); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	for _, existing := range stages {
+		if existing.Key == stage.Key {
+			return nil, fmt.Errorf("a stage with key %s already exists", stage.Key)
+		}
+	}
+
+	if err := s.pipelineRepo.CreateStage(ctx, stage); err != nil {
+		return nil, fmt.Errorf("failed to create pipeline stage: %w", err)
+	}
+
+	s.logStageAudit(ctx, "pipeline_stage.create", stage, nil)
+	return stage, nil
+}
+
+// UpdateStage renames or re-weights a stage. The stage type can't change once
+// opportunities are in the stage.
+func (s *pipelineServiceImpl) UpdateStage(ctx context.Context, stageID uuid.UUID, req *PipelineStageRequest) (*domain.PipelineStage, error) {
+	stage, err := s.getStage(ctx, stageID)
+	if err != nil {
+		return nil, err
+	}
+	oldValues := map[string]interface{}{"name": stage.Name, "stage_type": stage.StageType, "probability": stage.Probability}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		stage.Name = name
+	}
+	if req.StageType != "" && req.StageType != stage.StageType {
+		count, err := s.pipelineRepo.CountOpportunitiesInStage(ctx, stage.TenantID, stage.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count opportunities in stage: %w", err)
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("stage type can't be changed while the stage has opportunities")
+		}
+		stage.StageType = req.StageType
+	}
+	if req.Position != nil {
+		stage.Position = *req.Position
+	}
+	if req.Probability != nil {
+		stage.Probability = *req.Probability
+	}
+	if req.IsActive != nil {
+		stage.IsActive = *req.IsActive
+	}
+	stage.UpdatedAt = time.Now()
+
+	if err := validatePipelineStage(stage); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := s.pipelineRepo.UpdateStage(ctx, stage); err != nil {
+		return nil, fmt.Errorf("failed to update pipeline stage: %w", err)
+	}
+
+	s.logStageAudit(ctx, "pipeline_stage.update", stage, oldValues)
+	return stage, nil
+}
+
+// ReorderStages sets stage positions from the order of the given IDs
+func (s *pipelineServiceImpl) ReorderStages(ctx context.Context, stageIDs []uuid.UUID) ([]*domain.PipelineStage, error) {
+	stages, err := s.ListStages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(stageIDs) != len(stages) {
+		return nil, fmt.Errorf("every stage must be included when reordering")
+	}
+
+	byID := make(map[uuid.UUID]*domain.PipelineStage, len(stages))
+	for _, stage := range stages {
+		byID[stage.ID] = stage
+	}
+
+	reordered := make([]*domain.PipelineStage, 0, len(stages))
+	for i, stageID := range stageIDs {
+		stage, ok := byID[stageID]
+		if !ok {
+			return nil, fmt.Errorf("stage %s not found", stageID)
+		}
+		delete(byID, stageID)
+		if stage.Position != i+1 {
+			stage.Position = i + 1
+			stage.UpdatedAt = time.Now()
+			if err := s.pipelineRepo.UpdateStage(ctx, stage); err != nil {
+				return nil, fmt.Errorf("failed to update pipeline stage: %w", err)
+			}
+		}
+		reordered = append(reordered, stage)
+	}
+
+	return reordered, nil
+}
+
+// DeleteStage removes an empty stage. Stages with opportunities should be
+// deactivated instead so history is kept.
+func (s *pipelineServiceImpl) DeleteStage(ctx context.Context, stageID uuid.UUID) error {
+	stage, err := s.getStage(ctx, stageID)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.pipelineRepo.CountOpportunitiesInStage(ctx, stage.TenantID, stage.ID)
+	if err != nil {
+		return fmt.Errorf("failed to count opportunities in stage: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("stage has %d opportunities; move them or deactivate the stage instead", count)
+	}
+
+	if err := s.pipelineRepo.DeleteStage(ctx, stage.TenantID, stage.ID); err != nil {
+		return fmt.Errorf("failed to delete pipeline stage: %w", err)
+	}
+
+	s.logStageAudit(ctx, "pipeline_stage.delete", stage, nil)
+	return nil
+}
+
+// CreateOpportunity adds an opportunity to the pipeline
+func (s *pipelineServiceImpl) CreateOpportunity(ctx context.Context, req *OpportunityRequest) (*domain.Opportunity, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	stages, err := s.ListStages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	opportunity := &domain.Opportunity{
+		ID:             uuid.New(),
+		TenantID:       tenantID,
+		Status:         domain.OpportunityStatusOpen,
+		StageChangedAt: now,
+		CreatedBy:      GetUserIDFromContext(ctx),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	// Lead and quote details fill in whatever the request leaves out
+	if req.LeadID != nil {
+		lead, err := s.leadRepo.GetByID(ctx, tenantID, *req.LeadID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get lead: %w", err)
+		}
+		if lead == nil {
+			return nil, fmt.Errorf("lead not found")
+		}
+		name := lead.FullName()
+		source := lead.Source
+		opportunity.LeadID = &lead.ID
+		opportunity.ContactName = &name
+		opportunity.ContactEmail = lead.Email
+		opportunity.ContactPhone = lead.Phone
+		opportunity.Source = &source
+		opportunity.CustomerID = firstUUID(lead.CustomerID, lead.MatchedCustomerID)
+		opportunity.PropertyID = lead.PropertyID
+		opportunity.QuoteID = lead.QuoteID
+		if lead.EstimatedPrice != nil {
+			opportunity.EstimatedValue = *lead.EstimatedPrice
+		}
+		opportunity.Title = name
+		if lead.ServiceKey != nil {
+			opportunity.Title = leadServiceLabel(*lead.ServiceKey) + " for " + name
+		}
+	}
+
+	if err := s.applyOpportunityRequest(ctx, opportunity, req); err != nil {
+		return nil, err
+	}
+
+	stage := firstOpenStage(stages)
+	if req.StageID != nil {
+		stage = findStage(stages, *req.StageID)
+		if stage == nil {
+			return nil, fmt.Errorf("stage not found")
+		}
+	}
+	if stage == nil {
+		return nil, fmt.Errorf("pipeline has no open stages")
+	}
+	opportunity.StageID = stage.ID
+	opportunity.Status = opportunityStatusForStage(stage)
+	if opportunity.Status != domain.OpportunityStatusOpen {
+		opportunity.ClosedAt = &now
+	}
+
+	if err := validateOpportunity(opportunity); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := s.pipelineRepo.CreateOpportunity(ctx, opportunity); err != nil {
+		return nil, fmt.Errorf("failed to create opportunity: %w", err)
+	}
+
+	if err := s.pipelineRepo.CreateStageChange(ctx, &domain.OpportunityStageChange{
+		ID:            uuid.New(),
+		TenantID:      tenantID,
+		OpportunityID: opportunity.ID,
+		ToStageID:     stage.ID,
+		ChangedBy:     opportunity.CreatedBy,
+		ChangedAt:     now,
+	}); err != nil {
+		s.logger.Printf("Failed to record stage change: %v", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       opportunity.CreatedBy,
+		Action:       "opportunity.create",
+		ResourceType: "opportunity",
+		ResourceID:   &opportunity.ID,
+		NewValues: map[string]interface{}{
+			"title":           opportunity.Title,
+			"stage_id":        opportunity.StageID,
+			"estimated_value": opportunity.EstimatedValue,
+			"assigned_to":     opportunity.AssignedTo,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return opportunity, nil
+}
+
+// GetOpportunity retrieves an opportunity
+func (s *pipelineServiceImpl) GetOpportunity(ctx context.Context, opportunityID uuid.UUID) (*domain.Opportunity, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	opportunity, err := s.pipelineRepo.GetOpportunity(ctx, tenantID, opportunityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get opportunity: %w", err)
+	}
+	if opportunity == nil {
+		return nil, fmt.Errorf("opportunity not found")
+	}
+
+	return opportunity, nil
+}
+
+// UpdateOpportunity updates an opportunity's details. Stage changes go through
+// MoveOpportunity so they are recorded.
+func (s *pipelineServiceImpl) UpdateOpportunity(ctx context.Context, opportunityID uuid.UUID, req *OpportunityRequest) (*domain.Opportunity, error) {
+	opportunity, err := s.GetOpportunity(ctx, opportunityID)
+	if err != nil {
+		return nil, err
+	}
+	oldValues := map[string]interface{}{
+		"title":           opportunity.Title,
+		"estimated_value": opportunity.EstimatedValue,
+		"assigned_to":     opportunity.AssignedTo,
+	}
+
+	if err := s.applyOpportunityRequest(ctx, opportunity, req); err != nil {
+		return nil, err
+	}
+	opportunity.UpdatedAt = time.Now()
+
+	if err := validateOpportunity(opportunity); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := s.pipelineRepo.UpdateOpportunity(ctx, opportunity); err != nil {
+		return nil, fmt.Errorf("failed to update opportunity: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "opportunity.update",
+		ResourceType: "opportunity",
+		ResourceID:   &opportunity.ID,
+		OldValues:    oldValues,
+		NewValues: map[string]interface{}{
+			"title":           opportunity.Title,
+			"estimated_value": opportunity.EstimatedValue,
+			"assigned_to":     opportunity.AssignedTo,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return opportunity, nil
+}
+
+// ListOpportunities lists opportunities
+func (s *pipelineServiceImpl) ListOpportunities(ctx context.Context, filter *OpportunityFilter) (*domain.PaginatedResponse, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	// Set defaults
+	if filter == nil {
+		filter = &OpportunityFilter{}
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PerPage <= 0 {
+		filter.PerPage = 50
+	}
+	if filter.PerPage > 100 {
+		filter.PerPage = 100
+	}
+
+	opportunities, total, err := s.pipelineRepo.ListOpportunities(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list opportunities: %w", err)
+	}
+
+	totalPages := int((total + int64(filter.PerPage) - 1) / int64(filter.PerPage))
+
+	return &domain.PaginatedResponse{
+		Data:       opportunities,
+		Total:      total,
+		Page:       filter.Page,
+		PerPage:    filter.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// MoveOpportunity moves an opportunity to another stage, closing it when the stage
+// is won or lost. Reopening a closed opportunity clears its close reason.
+func (s *pipelineServiceImpl) MoveOpportunity(ctx context.Context, opportunityID uuid.UUID, req *OpportunityMoveRequest) (*domain.Opportunity, error) {
+	opportunity, err := s.GetOpportunity(ctx, opportunityID)
+	if err != nil {
+		return nil, err
+	}
+	if opportunity.StageID == req.StageID {
+		return opportunity, nil
+	}
+
+	stage, err := s.getStage(ctx, req.StageID)
+	if err != nil {
+		return nil, err
+	}
+	if !stage.IsActive {
+		return nil, fmt.Errorf("stage %s is inactive", stage.Name)
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if stage.StageType == domain.PipelineStageLost && reason == "" {
+		return nil, fmt.Errorf("a reason is required to mark an opportunity lost")
+	}
+
+	now := time.Now()
+	fromStageID := opportunity.StageID
+	oldStatus := opportunity.Status
+
+	opportunity.StageID = stage.ID
+	opportunity.Status = opportunityStatusForStage(stage)
+	opportunity.StageChangedAt = now
+	opportunity.UpdatedAt = now
+	if stage.IsClosed() {
+		opportunity.ClosedAt = &now
+		opportunity.CloseReason = optionalString(reason)
+		opportunity.CloseNotes = optionalString(strings.TrimSpace(req.Notes))
+	} else {
+		opportunity.ClosedAt = nil
+		opportunity.CloseReason = nil
+		opportunity.CloseNotes = nil
+	}
+
+	if err := s.pipelineRepo.UpdateOpportunity(ctx, opportunity); err != nil {
+		return nil, fmt.Errorf("failed to update opportunity: %w", err)
+	}
+
+	userID := GetUserIDFromContext(ctx)
+	if err := s.pipelineRepo.CreateStageChange(ctx, &domain.OpportunityStageChange{
+		ID:            uuid.New(),
+		TenantID:      opportunity.TenantID,
+		OpportunityID: opportunity.ID,
+		FromStageID:   &fromStageID,
+		ToStageID:     stage.ID,
+		ChangedBy:     userID,
+		ChangedAt:     now,
+	}); err != nil {
+		s.logger.Printf("Failed to record stage change: %v", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       userID,
+		Action:       "opportunity.stage_change",
+		ResourceType: "opportunity",
+		ResourceID:   &opportunity.ID,
+		OldValues:    map[string]interface{}{"stage_id": fromStageID, "status": oldStatus},
+		NewValues: map[string]interface{}{
+			"stage_id":     stage.ID,
+			"status":       opportunity.Status,
+			"close_reason": opportunity.CloseReason,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return opportunity, nil
+}
+
+// AssignOpportunity assigns an opportunity to a salesperson, or unassigns it
+func (s *pipelineServiceImpl) AssignOpportunity(ctx context.Context, opportunityID uuid.UUID, userID *uuid.UUID) (*domain.Opportunity, error) {
+	return s.UpdateOpportunity(ctx, opportunityID, &OpportunityRequest{AssignedTo: userID})
+}
+
+// CreateFollowUpTask schedules a follow-up
+func (s *pipelineServiceImpl) CreateFollowUpTask(ctx context.Context, req *FollowUpTaskRequest) (*domain.FollowUpTask, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	now := time.Now()
+	task := &domain.FollowUpTask{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Status:    domain.FollowUpStatusOpen,
+		CreatedBy: GetUserIDFromContext(ctx),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.applyTaskRequest(ctx, task, req); err != nil {
+		return nil, err
+	}
+	if task.AssignedTo == nil {
+		task.AssignedTo = task.CreatedBy
+	}
+
+	if err := s.pipelineRepo.CreateTask(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to create follow-up task: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       task.CreatedBy,
+		Action:       "follow_up_task.create",
+		ResourceType: "follow_up_task",
+		ResourceID:   &task.ID,
+		NewValues: map[string]interface{}{
+			"title":          task.Title,
+			"opportunity_id": task.OpportunityID,
+			"assigned_to":    task.AssignedTo,
+			"due_at":         task.DueAt,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return task, nil
+}
+
+// UpdateFollowUpTask updates an open task. Moving the due or reminder time re-arms
+// the reminder.
+func (s *pipelineServiceImpl) UpdateFollowUpTask(ctx context.Context, taskID uuid.UUID, req *FollowUpTaskRequest) (*domain.FollowUpTask, error) {
+	task, err := s.getTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != domain.FollowUpStatusOpen {
+		return nil, fmt.Errorf("only open tasks can be updated")
+	}
+
+	if req.DueAt.IsZero() {
+		req.DueAt = task.DueAt
+	}
+	if req.Title == "" {
+		req.Title = task.Title
+	}
+	if req.TaskType == "" {
+		req.TaskType = task.TaskType
+	}
+	if req.OpportunityID == nil {
+		req.OpportunityID = task.OpportunityID
+	}
+	if req.CustomerID == nil {
+		req.CustomerID = task.CustomerID
+	}
+	oldReminder := taskReminderTime(task)
+
+	if err := s.applyTaskRequest(ctx, task, req); err != nil {
+		return nil, err
+	}
+	if !taskReminderTime(task).Equal(oldReminder) {
+		task.ReminderSentAt = nil
+	}
+	task.UpdatedAt = time.Now()
+
+	if err := s.pipelineRepo.UpdateTask(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to update follow-up task: %w", err)
+	}
+
+	return task, nil
+}
+
+// CompleteFollowUpTask marks a task done with an optional outcome
+func (s *pipelineServiceImpl) CompleteFollowUpTask(ctx context.Context, taskID uuid.UUID, outcome string) (*domain.FollowUpTask, error) {
+	task, err := s.getTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != domain.FollowUpStatusOpen {
+		return nil, fmt.Errorf("task is already %s", task.Status)
+	}
+
+	now := time.Now()
+	task.Status = domain.FollowUpStatusCompleted
+	task.CompletedAt = &now
+	task.CompletedBy = GetUserIDFromContext(ctx)
+	task.Outcome = optionalString(strings.TrimSpace(outcome))
+	task.UpdatedAt = now
+
+	if err := s.pipelineRepo.UpdateTask(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to update follow-up task: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       task.CompletedBy,
+		Action:       "follow_up_task.complete",
+		ResourceType: "follow_up_task",
+		ResourceID:   &task.ID,
+		NewValues:    map[string]interface{}{"status": task.Status, "outcome": task.Outcome},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return task, nil
+}
+
+// CancelFollowUpTask cancels an open task
+func (s *pipelineServiceImpl) CancelFollowUpTask(ctx context.Context, taskID uuid.UUID) (*domain.FollowUpTask, error) {
+	task, err := s.getTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != domain.FollowUpStatusOpen {
+		return nil, fmt.Errorf("task is already %s", task.Status)
+	}
+
+	task.Status = domain.FollowUpStatusCancelled
+	task.UpdatedAt = time.Now()
+
+	if err := s.pipelineRepo.UpdateTask(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to update follow-up task: %w", err)
+	}
+
+	return task, nil
+}
+
+// ListFollowUpTasks lists follow-up tasks, soonest due first
+func (s *pipelineServiceImpl) ListFollowUpTasks(ctx context.Context, filter *FollowUpTaskFilter) (*domain.PaginatedResponse, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	// Set defaults
+	if filter == nil {
+		filter = &FollowUpTaskFilter{}
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PerPage <= 0 {
+		filter.PerPage = 50
+	}
+	if filter.PerPage > 100 {
+		filter.PerPage = 100
+	}
+
+	tasks, total, err := s.pipelineRepo.ListTasks(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list follow-up tasks: %w", err)
+	}
+
+	totalPages := int((total + int64(filter.PerPage) - 1) / int64(filter.PerPage))
+
+	return &domain.PaginatedResponse{
+		Data:       tasks,
+		Total:      total,
+		Page:       filter.Page,
+		PerPage:    filter.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// ProcessTaskReminders emails assignees about follow-ups that have come due. It is
+// called periodically by the worker and is not tenant-scoped.
+func (s *pipelineServiceImpl) ProcessTaskReminders(ctx context.Context, now time.Time) error {
+	tasks, err := s.pipelineRepo.ListDueReminders(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to list due follow-up reminders: %w", err)
+	}
+
+	for _, task := range tasks {
+		tenantCtx := context.WithValue(ctx, "tenant_id", task.TenantID)
+
+		if err := s.sendTaskReminder(tenantCtx, task, now); err != nil {
+			s.logger.Printf("Failed to send reminder for follow-up task %s: %v", task.ID, err)
+			continue
+		}
+
+		if err := s.pipelineRepo.MarkReminderSent(tenantCtx, task.TenantID, task.ID, now); err != nil {
+			s.logger.Printf("Failed to mark reminder sent for follow-up task %s: %v", task.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// GetPipelineMetrics reports pipeline value and conversion for opportunities created
+// in the period
+func (s *pipelineServiceImpl) GetPipelineMetrics(ctx context.Context, filter *PipelineMetricsFilter) (*PipelineMetrics, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if filter == nil {
+		filter = &PipelineMetricsFilter{}
+	}
+	if filter.End.IsZero() {
+		filter.End = time.Now()
+	}
+	if filter.Start.IsZero() {
+		filter.Start = filter.End.AddDate(0, -3, 0)
+	}
+
+	stages, err := s.ListStages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opportunities, err := s.pipelineRepo.ListOpportunitiesCreated(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list opportunities: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(opportunities))
+	for _, opportunity := range opportunities {
+		ids = append(ids, opportunity.ID)
+	}
+	var changes []*domain.OpportunityStageChange
+	if len(ids) > 0 {
+		changes, err = s.pipelineRepo.ListStageChanges(ctx, tenantID, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list stage changes: %w", err)
+		}
+	}
+
+	metrics := CalculatePipelineMetrics(stages, opportunities, changes)
+	metrics.Period = filter.TimeRange
+	return metrics, nil
+}
+
+// CalculatePipelineMetrics summarizes opportunities. The funnel counts an opportunity
+// as having reached every open stage up to the furthest one it visited; won
+// opportunities count as having reached them all.
+func CalculatePipelineMetrics(stages []*domain.PipelineStage, opportunities []*domain.Opportunity, changes []*domain.OpportunityStageChange) *PipelineMetrics {
+	sorted := make([]*domain.PipelineStage, len(stages))
+	copy(sorted, stages)
+	sortStages(sorted)
+
+	stageByID := make(map[uuid.UUID]*domain.PipelineStage, len(sorted))
+	for _, stage := range sorted {
+		stageByID[stage.ID] = stage
+	}
+
+	visited := make(map[uuid.UUID][]uuid.UUID)
+	for _, change := range changes {
+		visited[change.OpportunityID] = append(visited[change.OpportunityID], change.ToStageID)
+		if change.FromStageID != nil {
+			visited[change.OpportunityID] = append(visited[change.OpportunityID], *change.FromStageID)
+		}
+	}
+
+	metrics := &PipelineMetrics{
+		TotalOpportunities: len(opportunities),
+		Stages:             []PipelineStageMetrics{},
+		WinReasons:         []PipelineReasonCount{},
+		LossReasons:        []PipelineReasonCount{},
+		Sources:            []PipelineSourceMetrics{},
+		Salespeople:        []PipelineSalespersonMetrics{},
+	}
+
+	stageMetrics := make(map[uuid.UUID]*PipelineStageMetrics, len(sorted))
+	for _, stage := range sorted {
+		metrics.Stages = append(metrics.Stages, PipelineStageMetrics{
+			StageID:   stage.ID,
+			Key:       stage.Key,
+			Name:      stage.Name,
+			StageType: stage.StageType,
+		})
+	}
+	for i := range metrics.Stages {
+		stageMetrics[metrics.Stages[i].StageID] = &metrics.Stages[i]
+	}
+
+	winReasons := map[string]*PipelineReasonCount{}
+	lossReasons := map[string]*PipelineReasonCount{}
+	sources := map[string]*PipelineSourceMetrics{}
+	salespeople := map[uuid.UUID]*PipelineSalespersonMetrics{}
+	var unassigned *PipelineSalespersonMetrics
+	var daysToClose float64
+
+	for _, opportunity := range opportunities {
+		stage := stageByID[opportunity.StageID]
+		value := opportunity.EstimatedValue
+
+		// Current stage
+		if current := stageMetrics[opportunity.StageID]; current != nil {
+			current.Count++
+			current.Value += value
+			if opportunity.Status == domain.OpportunityStatusOpen {
+				current.WeightedValue += value * float64(opportunityProbability(opportunity, stage)) / 100
+			}
+		}
+
+		// Furthest open stage reached
+		furthest := 0
+		for _, stageID := range append(visited[opportunity.ID], opportunity.StageID) {
+			if visitedStage := stageByID[stageID]; visitedStage != nil && visitedStage.StageType == domain.PipelineStageOpen && visitedStage.Position > furthest {
+				furthest = visitedStage.Position
+			}
+		}
+		for _, s := range sorted {
+			if s.StageType != domain.PipelineStageOpen {
+				continue
+			}
+			if opportunity.Status == domain.OpportunityStatusWon || s.Position <= furthest {
+				stageMetrics[s.ID].Reached++
+			}
+		}
+
+		// Source and salesperson
+		sourceKey := "unknown"
+		if opportunity.Source != nil && *opportunity.Source != "" {
+			sourceKey = *opportunity.Source
+		}
+		source := sources[sourceKey]
+		if source == nil {
+			source = &PipelineSourceMetrics{Source: sourceKey}
+			sources[sourceKey] = source
+		}
+		source.Count++
+
+		var person *PipelineSalespersonMetrics
+		if opportunity.AssignedTo == nil {
+			if unassigned == nil {
+				unassigned = &PipelineSalespersonMetrics{}
+			}
+			person = unassigned
+		} else {
+			person = salespeople[*opportunity.AssignedTo]
+			if person == nil {
+				userID := *opportunity.AssignedTo
+				person = &PipelineSalespersonMetrics{UserID: &userID}
+				salespeople[userID] = person
+			}
+		}
+
+		switch opportunity.Status {
+		case domain.OpportunityStatusOpen:
+			metrics.OpenCount++
+			metrics.OpenValue += value
+			metrics.WeightedValue += value * float64(opportunityProbability(opportunity, stage)) / 100
+			person.OpenCount++
+			person.OpenValue += value
+		case domain.OpportunityStatusWon:
+			metrics.WonCount++
+			metrics.WonValue += value
+			source.WonCount++
+			source.WonValue += value
+			person.WonCount++
+			person.WonValue += value
+			countReason(winReasons, opportunity.CloseReason, value)
+			if opportunity.ClosedAt != nil {
+				daysToClose += opportunity.ClosedAt.Sub(opportunity.CreatedAt).Hours() / 24
+			}
+		case domain.OpportunityStatusLost:
+			metrics.LostCount++
+			metrics.LostValue += value
+			person.LostCount++
+			countReason(lossReasons, opportunity.CloseReason, value)
+		}
+	}
+
+	// Stage conversion: on to the next open stage, or won from the last one
+	openStages := make([]*PipelineStageMetrics, 0, len(metrics.Stages))
+	for i := range metrics.Stages {
+		if metrics.Stages[i].StageType == domain.PipelineStageOpen {
+			openStages = append(openStages, &metrics.Stages[i])
+		}
+	}
+	for i, stage := range openStages {
+		next := metrics.WonCount
+		if i+1 < len(openStages) {
+			next = openStages[i+1].Reached
+		}
+		stage.ConversionRate = ratio(next, stage.Reached)
+		stage.Value = roundCents(stage.Value)
+		stage.WeightedValue = roundCents(stage.WeightedValue)
+	}
+
+	closed := metrics.WonCount + metrics.LostCount
+	metrics.WinRate = ratio(metrics.WonCount, closed)
+	metrics.ConversionRate = ratio(metrics.WonCount, metrics.TotalOpportunities)
+	if metrics.WonCount > 0 {
+		metrics.AverageDealSize = roundCents(metrics.WonValue / float64(metrics.WonCount))
+		metrics.AverageDaysToClose = roundCents(daysToClose / float64(metrics.WonCount))
+	}
+	metrics.OpenValue = roundCents(metrics.OpenValue)
+	metrics.WeightedValue = roundCents(metrics.WeightedValue)
+	metrics.WonValue = roundCents(metrics.WonValue)
+	metrics.LostValue = roundCents(metrics.LostValue)
+
+	metrics.WinReasons = sortedReasons(winReasons)
+	metrics.LossReasons = sortedReasons(lossReasons)
+
+	for _, source := range sources {
+		source.ConversionRate = ratio(source.WonCount, source.Count)
+		source.WonValue = roundCents(source.WonValue)
+		metrics.Sources = append(metrics.Sources, *source)
+	}
+	sort.Slice(metrics.Sources, func(i, j int) bool {
+		if metrics.Sources[i].Count != metrics.Sources[j].Count {
+			return metrics.Sources[i].Count > metrics.Sources[j].Count
+		}
+		return metrics.Sources[i].Source < metrics.Sources[j].Source
+	})
+
+	for _, person := range salespeople {
+		person.WinRate = ratio(person.WonCount, person.WonCount+person.LostCount)
+		metrics.Salespeople = append(metrics.Salespeople, *person)
+	}
+	sort.Slice(metrics.Salespeople, func(i, j int) bool {
+		return metrics.Salespeople[i].WonValue > metrics.Salespeople[j].WonValue
+	})
+	if unassigned != nil {
+		unassigned.WinRate = ratio(unassigned.WonCount, unassigned.WonCount+unassigned.LostCount)
+		metrics.Salespeople = append(metrics.Salespeople, *unassigned)
+	}
+
+	return metrics
+}
+
+// pipelineReportService adds sales pipeline conversion to the customers report
+type pipelineReportService struct {
+	ReportService
+	pipeline PipelineService
+}
+
+// NewPipelineReportService wraps a report service so GetCustomersReport includes
+// pipeline conversion metrics. base may be nil while reporting isn't configured,
+// in which case only the conversion section is filled in.
+func NewPipelineReportService(base ReportService, pipeline PipelineService) ReportService {
+	return &pipelineReportService{ReportService: base, pipeline: pipeline}
+}
+
+// GetCustomersReport returns the customers report with conversion for the same period
+func (s *pipelineReportService) GetCustomersReport(ctx context.Context, filter *CustomerReportFilter) (*CustomersReport, error) {
+	if filter == nil {
+		filter = &CustomerReportFilter{}
+	}
+
+	report := &CustomersReport{Period: filter.TimeRange}
+	if s.ReportService != nil {
+		var err error
+		report, err = s.ReportService.GetCustomersReport(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	conversion, err := s.pipeline.GetPipelineMetrics(ctx, &PipelineMetricsFilter{TimeRange: filter.TimeRange})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline metrics: %w", err)
+	}
+	report.Conversion = conversion
+	if report.Period.Start.IsZero() {
+		report.Period = conversion.Period
+	}
+
+	return report, nil
+}
+
+// Helper functions
+
+func (s *pipelineServiceImpl) getStage(ctx context.Context, stageID uuid.UUID) (*domain.PipelineStage, error) {
+	stages, err := s.ListStages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stage := findStage(stages, stageID)
+	if stage == nil {
+		return nil, fmt.Errorf("stage not found")
+	}
+	return stage, nil
+}
+
+func (s *pipelineServiceImpl) getTask(ctx context.Context, taskID uuid.UUID) (*domain.FollowUpTask, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	task, err := s.pipelineRepo.GetTask(ctx, tenantID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get follow-up task: %w", err)
+	}
+	if task == nil {
+		return nil, fmt.Errorf("follow-up task not found")
+	}
+	return task, nil
+}
+
+// applyOpportunityRequest copies the fields set on the request, checking that linked
+// records belong to the tenant
+func (s *pipelineServiceImpl) applyOpportunityRequest(ctx context.Context, opportunity *domain.Opportunity, req *OpportunityRequest) error {
+	if title := strings.TrimSpace(req.Title); title != "" {
+		opportunity.Title = title
+	}
+
+	if req.CustomerID != nil {
+		customer, err := s.customerRepo.GetByID(ctx, opportunity.TenantID, *req.CustomerID)
+		if err != nil {
+			return fmt.Errorf("failed to get customer: %w", err)
+		}
+		if customer == nil {
+			return fmt.Errorf("customer not found")
+		}
+		opportunity.CustomerID = &customer.ID
+		if opportunity.ContactName == nil {
+			name := customerDisplayName(customer)
+			opportunity.ContactName = &name
+		}
+		if opportunity.ContactEmail == nil {
+			opportunity.ContactEmail = customer.Email
+		}
+		if opportunity.ContactPhone == nil {
+			opportunity.ContactPhone = customer.Phone
+		}
+		if opportunity.Source == nil {
+			opportunity.Source = customer.LeadSource
+		}
+		if opportunity.Title == "" {
+			opportunity.Title = customerDisplayName(customer)
+		}
+	}
+
+	if req.QuoteID != nil {
+		quote, err := s.quoteRepo.GetByID(ctx, opportunity.TenantID, *req.QuoteID)
+		if err != nil {
+			return fmt.Errorf("failed to get quote: %w", err)
+		}
+		if quote == nil {
+			return fmt.Errorf("quote not found")
+		}
+		if opportunity.CustomerID != nil && *opportunity.CustomerID != quote.CustomerID {
+			return fmt.Errorf("quote belongs to a different customer")
+		}
+		opportunity.QuoteID = &quote.ID
+		opportunity.CustomerID = &quote.CustomerID
+		opportunity.PropertyID = &quote.PropertyID
+		if req.EstimatedValue == nil {
+			opportunity.EstimatedValue = quote.TotalAmount
+		}
+		if opportunity.Title == "" {
+			opportunity.Title = quote.Title
+		}
+	}
+
+	if req.AssignedTo != nil {
+		user, err := s.userRepo.GetByID(ctx, opportunity.TenantID, *req.AssignedTo)
+		if err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		if user == nil {
+			return fmt.Errorf("user not found")
+		}
+		opportunity.AssignedTo = &user.ID
+	}
+
+	if req.PropertyID != nil {
+		opportunity.PropertyID = req.PropertyID
+	}
+	if req.ContactName != nil {
+		opportunity.ContactName = req.ContactName
+	}
+	if req.ContactEmail != nil {
+		opportunity.ContactEmail = req.ContactEmail
+	}
+	if req.ContactPhone != nil {
+		opportunity.ContactPhone = req.ContactPhone
+	}
+	if req.Source != nil {
+		opportunity.Source = req.Source
+	}
+	if req.EstimatedValue != nil {
+		opportunity.EstimatedValue = roundCents(*req.EstimatedValue)
+	}
+	if req.Probability != nil {
+		opportunity.Probability = req.Probability
+	}
+	if req.ExpectedCloseDate != nil {
+		opportunity.ExpectedCloseDate = req.ExpectedCloseDate
+	}
+	if req.Notes != nil {
+		opportunity.Notes = req.Notes
+	}
+
+	return nil
+}
+
+// applyTaskRequest copies a task request, defaulting the assignee to the
+// opportunity's salesperson
+func (s *pipelineServiceImpl) applyTaskRequest(ctx context.Context, task *domain.FollowUpTask, req *FollowUpTaskRequest) error {
+	task.Title = strings.TrimSpace(req.Title)
+	task.Description = req.Description
+	task.TaskType = req.TaskType
+	task.DueAt = req.DueAt
+	task.RemindAt = req.RemindAt
+	if task.TaskType == "" {
+		task.TaskType = domain.FollowUpTypeCall
+	}
+
+	if err := validateFollowUpTask(task); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	task.OpportunityID = nil
+	if req.OpportunityID != nil {
+		opportunity, err := s.pipelineRepo.GetOpportunity(ctx, task.TenantID, *req.OpportunityID)
+		if err != nil {
+			return fmt.Errorf("failed to get opportunity: %w", err)
+		}
+		if opportunity == nil {
+			return fmt.Errorf("opportunity not found")
+		}
+		task.OpportunityID = &opportunity.ID
+		if req.CustomerID == nil {
+			task.CustomerID = opportunity.CustomerID
+		}
+		if req.AssignedTo == nil && task.AssignedTo == nil {
+			task.AssignedTo = opportunity.AssignedTo
+		}
+	}
+
+	if req.CustomerID != nil {
+		customer, err := s.customerRepo.GetByID(ctx, task.TenantID, *req.CustomerID)
+		if err != nil {
+			return fmt.Errorf("failed to get customer: %w", err)
+		}
+		if customer == nil {
+			return fmt.Errorf("customer not found")
+		}
+		task.CustomerID = &customer.ID
+	}
+
+	if req.AssignedTo != nil {
+		user, err := s.userRepo.GetByID(ctx, task.TenantID, *req.AssignedTo)
+		if err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		if user == nil {
+			return fmt.Errorf("user not found")
+		}
+		task.AssignedTo = &user.ID
+	}
+
+	return nil
+}
+
+func (s *pipelineServiceImpl) sendTaskReminder(ctx context.Context, task *domain.FollowUpTask, now time.Time) error {
+	if task.AssignedTo == nil {
+		return fmt.Errorf("task is not assigned")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, task.TenantID, *task.AssignedTo)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.Email == "" {
+		return fmt.Errorf("no email address available for assignee")
+	}
+
+	var opportunity *domain.Opportunity
+	if task.OpportunityID != nil {
+		opportunity, err = s.pipelineRepo.GetOpportunity(ctx, task.TenantID, *task.OpportunityID)
+		if err != nil {
+			s.logger.Printf("Failed to get opportunity for follow-up task %s: %v", task.ID, err)
+		}
+	}
+
+	subject, body := RenderTaskReminder(task, opportunity, now)
+	if err := s.communicationService.SendEmail(ctx, &EmailRequest{
+		To:      []string{user.Email},
+		Subject: subject,
+		Body:    body,
+		IsHTML:  false,
+	}); err != nil {
+		return fmt.Errorf("failed to send reminder email: %w", err)
+	}
+
+	return nil
+}
+
+// RenderTaskReminder builds the reminder email for a follow-up task
+func RenderTaskReminder(task *domain.FollowUpTask, opportunity *domain.Opportunity, now time.Time) (string, string) {
+	due := "is due " + task.DueAt.Format("Mon Jan 2 at 3:04 PM")
+	if task.DueAt.Before(now) {
+		due = "was due " + task.DueAt.Format("Mon Jan 2 at 3:04 PM")
+	}
+
+	subject := "Follow-up reminder: " + task.Title
+	body := fmt.Sprintf("Your follow-up \"%s\" %s.\n", task.Title, due)
+	if opportunity != nil {
+		body += fmt.Sprintf("\nOpportunity: %s ($%.2f)\n", opportunity.Title, opportunity.EstimatedValue)
+		if opportunity.ContactName != nil {
+			body += "Contact: " + *opportunity.ContactName
+			if opportunity.ContactPhone != nil {
+				body += ", " + *opportunity.ContactPhone
+			}
+			if opportunity.ContactEmail != nil {
+				body += ", " + *opportunity.ContactEmail
+			}
+			body += "\n"
+		}
+	}
+	if task.Description != nil && *task.Description != "" {
+		body += "\n" + *task.Description + "\n"
+	}
+	return subject, body
+}
+
+func (s *pipelineServiceImpl) logStageAudit(ctx context.Context, action string, stage *domain.PipelineStage, oldValues map[string]interface{}) {
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       action,
+		ResourceType: "pipeline_stage",
+		ResourceID:   &stage.ID,
+		OldValues:    oldValues,
+		NewValues: map[string]interface{}{
+			"key":         stage.Key,
+			"name":        stage.Name,
+			"stage_type":  stage.StageType,
+			"probability": stage.Probability,
+			"is_active":   stage.IsActive,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+}
+
+func validatePipelineStage(stage *domain.PipelineStage) error {
+	if stage.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	switch stage.StageType {
+	case domain.PipelineStageOpen, domain.PipelineStageWon, domain.PipelineStageLost:
+	default:
+		return fmt.Errorf("stage type must be open, won or lost")
+	}
+	if stage.Probability < 0 || stage.Probability > 100 {
+		return fmt.Errorf("probability must be between 0 and 100")
+	}
+	return nil
+}
+
+func validateOpportunity(opportunity *domain.Opportunity) error {
+	if opportunity.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if opportunity.EstimatedValue < 0 {
+		return fmt.Errorf("estimated value cannot be negative")
+	}
+	if opportunity.Probability != nil && (*opportunity.Probability < 0 || *opportunity.Probability > 100) {
+		return fmt.Errorf("probability must be between 0 and 100")
+	}
+	return nil
+}
+
+func validateFollowUpTask(task *domain.FollowUpTask) error {
+	if task.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if task.DueAt.IsZero() {
+		return fmt.Errorf("due date is required")
+	}
+	switch task.TaskType {
+	case domain.FollowUpTypeCall, domain.FollowUpTypeEmail, domain.FollowUpTypeSiteVisit, domain.FollowUpTypeOther:
+	default:
+		return fmt.Errorf("invalid task type: %s", task.TaskType)
+	}
+	if task.RemindAt != nil && task.RemindAt.After(task.DueAt) {
+		return fmt.Errorf("reminder must be before the due date")
+	}
+	return nil
+}
+
+func sortStages(stages []*domain.PipelineStage) {
+	sort.SliceStable(stages, func(i, j int) bool {
+		return stages[i].Position < stages[j].Position
+	})
+}
+
+func findStage(stages []*domain.PipelineStage, stageID uuid.UUID) *domain.PipelineStage {
+	for _, stage := range stages {
+		if stage.ID == stageID {
+			return stage
+		}
+	}
+	return nil
+}
+
+func firstOpenStage(stages []*domain.PipelineStage) *domain.PipelineStage {
+	for _, stage := range stages {
+		if stage.StageType == domain.PipelineStageOpen && stage.IsActive {
+			return stage
+		}
+	}
+	return nil
+}
+
+func opportunityStatusForStage(stage *domain.PipelineStage) string {
+	switch stage.StageType {
+	case domain.PipelineStageWon:
+		return domain.OpportunityStatusWon
+	case domain.PipelineStageLost:
+		return domain.OpportunityStatusLost
+	}
+	return domain.OpportunityStatusOpen
+}
+
+func opportunityProbability(opportunity *domain.Opportunity, stage *domain.PipelineStage) int {
+	if opportunity.Probability != nil {
+		return *opportunity.Probability
+	}
+	if stage != nil {
+		return stage.Probability
+	}
+	return 0
+}
+
+func taskReminderTime(task *domain.FollowUpTask) time.Time {
+	if task.RemindAt != nil {
+		return *task.RemindAt
+	}
+	return task.DueAt
+}
+
+func countReason(reasons map[string]*PipelineReasonCount, reason *string, value float64) {
+	key := "unspecified"
+	if reason != nil && *reason != "" {
+		key = *reason
+	}
+	count := reasons[key]
+	if count == nil {
+		count = &PipelineReasonCount{Reason: key}
+		reasons[key] = count
+	}
+	count.Count++
+	count.Value += value
+}
+
+func sortedReasons(reasons map[string]*PipelineReasonCount) []PipelineReasonCount {
+	sorted := make([]PipelineReasonCount, 0, len(reasons))
+	for _, reason := range reasons {
+		reason.Value = roundCents(reason.Value)
+		sorted = append(sorted, *reason)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Reason < sorted[j].Reason
+	})
+	return sorted
+}
+
+func ratio(numerator, denominator int) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
+
+func firstUUID(ids ...*uuid.UUID) *uuid.UUID {
+	for _, id := range ids {
+		if id != nil {
+			return id
+		}
+	}
+	return nil
+}
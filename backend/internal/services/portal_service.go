@@ -0,0 +1,1429 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// PortalService is the customer self-service portal: passwordless sign-in scoped to a
+// customer, read access to their properties, visits, invoices and quotes, and quote
+// acceptance, payment, skip/reschedule and service requests. Customer methods take a
+// context carrying the tenant and portal customer set by the portal session.
+type PortalService interface {
+	// Authentication
+	RequestMagicLink(ctx context.Context, req *PortalLoginRequest, client *PortalClientInfo) error
+	VerifyMagicLink(ctx context.Context, token string, client *PortalClientInfo) (*PortalLoginResult, error)
+	AuthenticateSession(ctx context.Context, sessionToken string) (*domain.PortalSession, error)
+	Logout(ctx context.Context, sessionToken string) error
+
+	// Customer views
+	GetProfile(ctx context.Context) (*PortalProfile, error)
+	ListProperties(ctx context.Context) ([]*domain.EnhancedProperty, error)
+	ListJobs(ctx context.Context, upcomingOnly bool) ([]*domain.EnhancedJob, error)
+	ListInvoices(ctx context.Context) ([]*PortalInvoice, error)
+	GetInvoice(ctx context.Context, invoiceID uuid.UUID) (*PortalInvoice, error)
+	ListQuotes(ctx context.Context) ([]*domain.Quote, error)
+	GetQuote(ctx context.Context, quoteID uuid.UUID) (*PortalQuote, error)
+
+	// Customer actions
+	AcceptQuote(ctx context.Context, quoteID uuid.UUID, req *PortalQuoteAcceptRequest, client *PortalClientInfo) (*domain.QuoteSignature, error)
+	DeclineQuote(ctx context.Context, quoteID uuid.UUID, reason string) error
+	PayInvoice(ctx context.Context, invoiceID uuid.UUID, req *PortalPaymentRequest) (*domain.Payment, error)
+	RequestScheduleChange(ctx context.Context, jobID uuid.UUID, req *PortalScheduleChangeRequest) (*domain.PortalScheduleChange, error)
+	ListMyScheduleChanges(ctx context.Context) ([]*domain.PortalScheduleChange, error)
+	SubmitServiceRequest(ctx context.Context, req *PortalServiceRequestInput) (*domain.PortalServiceRequest, error)
+	ListMyServiceRequests(ctx context.Context) ([]*domain.PortalServiceRequest, error)
+
+	// Staff administration
+	GetPortalSettings(ctx context.Context) (*domain.PortalSettings, error)
+	UpdatePortalSettings(ctx context.Context, req *PortalSettingsRequest) (*domain.PortalSettings, error)
+	ListScheduleChanges(ctx context.Context, filter *PortalRequestFilter) ([]*domain.PortalScheduleChange, error)
+	ReviewScheduleChange(ctx context.Context, changeID uuid.UUID, req *ScheduleChangeReviewRequest) (*domain.PortalScheduleChange, error)
+	ListServiceRequests(ctx context.Context, filter *PortalRequestFilter) ([]*domain.PortalServiceRequest, error)
+	UpdateServiceRequest(ctx context.Context, requestID uuid.UUID, req *PortalServiceRequestUpdate) (*domain.PortalServiceRequest, error)
+}
+
+// PortalRepository defines data access for the customer portal
+type PortalRepository interface {
+	// Settings
+	GetSettings(ctx context.Context, tenantID uuid.UUID) (*domain.PortalSettings, error)
+	UpsertSettings(ctx context.Context, settings *domain.PortalSettings) error
+
+	// Magic links and sessions; looked up by token hash across tenants
+	CreateLoginToken(ctx context.Context, token *domain.PortalLoginToken) error
+	ConsumeLoginToken(ctx context.Context, tokenHash string, usedAt time.Time) (*domain.PortalLoginToken, error)
+	CountLoginTokensSince(ctx context.Context, customerID uuid.UUID, since time.Time) (int, error)
+	CreateSession(ctx context.Context, session *domain.PortalSession) error
+	GetSessionByTokenHash(ctx context.Context, tokenHash string) (*domain.PortalSession, error)
+	TouchSession(ctx context.Context, sessionID uuid.UUID, seenAt time.Time) error
+	RevokeSession(ctx context.Context, sessionID uuid.UUID, revokedAt time.Time) error
+
+	// Quote signatures
+	GetQuoteSignature(ctx context.Context, tenantID, quoteID uuid.UUID) (*domain.QuoteSignature, error)
+
+	// Schedule changes
+	CreateScheduleChange(ctx context.Context, change *domain.PortalScheduleChange) error
+	GetScheduleChange(ctx context.Context, tenantID, changeID uuid.UUID) (*domain.PortalScheduleChange, error)
+	UpdateScheduleChange(ctx context.Context, change *domain.PortalScheduleChange) error
+	ListScheduleChanges(ctx context.Context, tenantID uuid.UUID, filter *PortalRequestFilter) ([]*domain.PortalScheduleChange, error)
+	GetPendingScheduleChange(ctx context.Context, tenantID, jobID uuid.UUID) (*domain.PortalScheduleChange, error)
+	CountApprovedSkipsSince(ctx context.Context, tenantID, customerID uuid.UUID, since time.Time) (int, error)
+
+	// Service requests
+	CreateServiceRequest(ctx context.Context, request *domain.PortalServiceRequest) error
+	GetServiceRequest(ctx context.Context, tenantID, requestID uuid.UUID) (*domain.PortalServiceRequest, error)
+	UpdateServiceRequest(ctx context.Context, request *domain.PortalServiceRequest) error
+	ListServiceRequests(ctx context.Context, tenantID uuid.UUID, filter *PortalRequestFilter) ([]*domain.PortalServiceRequest, error)
+}
+
+// PortalLoginRequest asks for a magic link. The tenant comes from the portal the
+// customer is signing in to.
+type PortalLoginRequest struct {
+	TenantID uuid.UUID `json:"tenant_id" validate:"required"`
+	Email    string    `json:"email" validate:"required,email"`
+}
+
+// PortalClientInfo identifies the browser making a portal request
+type PortalClientInfo struct {
+	IPAddress string `json:"ip_address,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// PortalLoginResult is returned when a magic link is exchanged for a session
+type PortalLoginResult struct {
+	SessionToken string         `json:"session_token"`
+	ExpiresAt    time.Time      `json:"expires_at"`
+	Profile      *PortalProfile `json:"profile"`
+}
+
+// PortalProfile is the signed-in customer's own details
+type PortalProfile struct {
+	CustomerID          uuid.UUID `json:"customer_id"`
+	Name                string    `json:"name"`
+	Email               *string   `json:"email,omitempty"`
+	Phone               *string   `json:"phone,omitempty"`
+	SchedulingSuspended bool      `json:"scheduling_suspended"`
+}
+
+// PortalInvoice is an invoice with what the customer still owes on it
+type PortalInvoice struct {
+	Invoice    *domain.Invoice `json:"invoice"`
+	AmountPaid float64         `json:"amount_paid"`
+	Balance    float64         `json:"balance"`
+	Payable    bool            `json:"payable"`
+}
+
+// PortalQuote is a quote with its options and, once accepted, the signature
+type PortalQuote struct {
+	Quote     *domain.Quote          `json:"quote"`
+	Pricing   *QuotePricing          `json:"pricing"`
+	Signature *domain.QuoteSignature `json:"signature,omitempty"`
+}
+
+// PortalQuoteAcceptRequest accepts a quote with the customer's selection and e-signature
+type PortalQuoteAcceptRequest struct {
+	Selection     *QuoteSelectionRequest `json:"selection,omitempty"`
+	SignerName    string                 `json:"signer_name" validate:"required"`
+	SignerEmail   *string                `json:"signer_email,omitempty"`
+	SignatureType string                 `json:"signature_type" validate:"required"`
+	SignatureData string                 `json:"signature_data,omitempty"` // drawn signatures as an image data URL
+	AgreeToTerms  bool                   `json:"agree_to_terms"`
+}
+
+// PortalPaymentRequest pays an invoice; a zero amount pays the full balance
+type PortalPaymentRequest struct {
+	Amount        float64 `json:"amount,omitempty"`
+	PaymentMethod string  `json:"payment_method,omitempty"`
+	PaymentToken  string  `json:"payment_token" validate:"required"`
+}
+
+// PortalScheduleChangeRequest asks to skip or reschedule a visit
+type PortalScheduleChangeRequest struct {
+	ChangeType    string     `json:"change_type" validate:"required"`
+	RequestedDate *time.Time `json:"requested_date,omitempty"`
+	Reason        *string    `json:"reason,omitempty"`
+}
+
+// PortalServiceRequestInput is a new service request from a customer
+type PortalServiceRequestInput struct {
+	PropertyID    *uuid.UUID `json:"property_id,omitempty"`
+	ServiceID     *uuid.UUID `json:"service_id,omitempty"`
+	Description   string     `json:"description" validate:"required"`
+	PreferredDate *time.Time `json:"preferred_date,omitempty"`
+}
+
+// PortalSettingsRequest updates a tenant's portal rules
+type PortalSettingsRequest struct {
+	Enabled              *bool   `json:"enabled,omitempty"`
+	PortalURL            *string `json:"portal_url,omitempty"`
+	MagicLinkTTLMinutes  *int    `json:"magic_link_ttl_minutes,omitempty"`
+	SessionTTLHours      *int    `json:"session_ttl_hours,omitempty"`
+	AllowOnlinePayment   *bool   `json:"allow_online_payment,omitempty"`
+	AllowQuoteAcceptance *bool   `json:"allow_quote_acceptance,omitempty"`
+	AllowSkip            *bool   `json:"allow_skip,omitempty"`
+	AllowReschedule      *bool   `json:"allow_reschedule,omitempty"`
+	MinNoticeHours       *int    `json:"min_notice_hours,omitempty"`
+	MaxRescheduleDays    *int    `json:"max_reschedule_days,omitempty"`
+	MaxSkipsPerYear      *int    `json:"max_skips_per_year,omitempty"`
+	AutoApproveChanges   *bool   `json:"auto_approve_changes,omitempty"`
+	SignatureConsentText *string `json:"signature_consent_text,omitempty"`
+}
+
+// PortalRequestFilter filters the schedule change and service request queues
+type PortalRequestFilter struct {
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"`
+	Status     string     `json:"status,omitempty"`
+}
+
+// ScheduleChangeReviewRequest approves or declines a schedule change
+type ScheduleChangeReviewRequest struct {
+	Approve      bool    `json:"approve"`
+	ResponseNote *string `json:"response_note,omitempty"`
+}
+
+// PortalServiceRequestUpdate moves a service request through the admin queue
+type PortalServiceRequestUpdate struct {
+	Status     string     `json:"status,omitempty"`
+	AdminNotes *string    `json:"admin_notes,omitempty"`
+	AssignedTo *uuid.UUID `json:"assigned_to,omitempty"`
+}
+
+// DefaultSignatureConsent is the acceptance statement shown beside the signature
+const DefaultSignatureConsent = "By signing, I accept this quote and its terms and authorize the work described."
+
+// Magic link requests allowed per customer per hour
+const portalMagicLinkHourlyLimit = 5
+
+// portalServiceImpl implements PortalService
+type portalServiceImpl struct {
+	portalRepo           PortalRepository
+	customerRepo         CustomerRepository
+	propertyRepo         PropertyRepositoryExtended
+	jobRepo              JobRepositoryComplete
+	invoiceRepo          InvoiceRepositoryFull
+	paymentRepo          PaymentRepositoryFull
+	quoteRepo            QuoteRepositoryFull
+	quoteService         QuoteService
+	paymentService       PaymentService
+	communicationService CommunicationService
+	auditService         AuditService
+	portalURL            string
+	logger               *log.Logger
+}
+
+// NewPortalService creates a new customer portal service. portalURL is the default
+// portal address used in magic links when a tenant has not set its own.
+func NewPortalService(
+	portalRepo PortalRepository,
+	customerRepo CustomerRepository,
+	propertyRepo PropertyRepositoryExtended,
+	jobRepo JobRepositoryComplete,
+	invoiceRepo InvoiceRepositoryFull,
+	paymentRepo PaymentRepositoryFull,
+	quoteRepo QuoteRepositoryFull,
+	quoteService QuoteService,
+	paymentService PaymentService,
+	communicationService CommunicationService,
+	auditService AuditService,
+	portalURL string,
+	logger *log.Logger,
+) PortalService {
+	return &portalServiceImpl{
+		portalRepo:           portalRepo,
+		customerRepo:         customerRepo,
+		propertyRepo:         propertyRepo,
+		jobRepo:              jobRepo,
+		invoiceRepo:          invoiceRepo,
+		paymentRepo:          paymentRepo,
+		quoteRepo:            quoteRepo,
+		quoteService:         quoteService,
+		paymentService:       paymentService,
+		communicationService: communicationService,
+		auditService:         auditService,
+		portalURL:            portalURL,
+		logger:               logger,
+	}
+}
+
+// WithPortalCustomer returns a context for a signed-in portal customer
+func WithPortalCustomer(ctx context.Context, session *domain.PortalSession) context.Context {
+	ctx = context.WithValue(ctx, "tenant_id", session.TenantID)
+	return context.WithValue(ctx, "portal_customer_id", session.CustomerID)
+}
+
+// GetPortalCustomerIDFromContext returns the signed-in portal customer
+func GetPortalCustomerIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	if customerID, ok := ctx.Value("portal_customer_id").(uuid.UUID); ok {
+		return customerID, true
+	}
+	return uuid.Nil, false
+}
+
+// HashPortalToken hashes a magic link or session token for storage
+func HashPortalToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RequestMagicLink emails a sign-in link to the customer with that email address.
+// Unknown addresses succeed silently so the endpoint can't be used to find customers.
+func (s *portalServiceImpl) RequestMagicLink(ctx context.Context, req *PortalLoginRequest, client *PortalClientInfo) error {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if req.TenantID == uuid.Nil || email == "" {
+		return fmt.Errorf("tenant and email are required")
+	}
+
+	ctx = context.WithValue(ctx, "tenant_id", req.TenantID)
+	settings, err := s.getSettings(ctx, req.TenantID)
+	if err != nil {
+		return err
+	}
+	if !settings.Enabled {
+		return fmt.Errorf("customer portal is not enabled")
+	}
+
+	customer, err := s.customerRepo.GetByEmail(ctx, req.TenantID, email)
+	if err != nil {
+		return fmt.Errorf("failed to look up customer: %w", err)
+	}
+	if customer == nil || customer.Status != "active" {
+		s.logger.Printf("Portal login requested for unknown or inactive email (tenant %s)", req.TenantID)
+		return nil
+	}
+
+	now := time.Now()
+	recent, err := s.portalRepo.CountLoginTokensSince(ctx, customer.ID, now.Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to check login attempts: %w", err)
+	}
+	if recent >= portalMagicLinkHourlyLimit {
+		s.logger.Printf("Portal login rate limit reached for customer %s", customer.ID)
+		return nil
+	}
+
+	token, err := generatePortalToken()
+	if err != nil {
+		return err
+	}
+
+	loginToken := &domain.PortalLoginToken{
+		ID:         uuid.New(),
+		TenantID:   req.TenantID,
+		CustomerID: customer.ID,
+		TokenHash:  HashPortalToken(token),
+		Email:      email,
+		ExpiresAt:  now.Add(time.Duration(settings.MagicLinkTTLMinutes) * time.Minute),
+		IPAddress:  client.ipAddress(),
+		CreatedAt:  now,
+	}
+	if err := s.portalRepo.CreateLoginToken(ctx, loginToken); err != nil {
+		return fmt.Errorf("failed to create login token: %w", err)
+	}
+
+	baseURL := s.portalURL
+	if settings.PortalURL != nil && *settings.PortalURL != "" {
+		baseURL = *settings.PortalURL
+	}
+	link := fmt.Sprintf("%s/login?token=%s", strings.TrimRight(baseURL, "/"), token)
+
+	if err := s.communicationService.SendEmail(ctx, &EmailRequest{
+		To:      []string{email},
+		Subject: "Your sign-in link",
+		Body: fmt.Sprintf("Hello %s,\n\nUse this link to sign in to your customer portal:\n\n%s\n\nThe link expires in %d minutes and can be used once. If you didn't ask to sign in, you can ignore this email.",
+			customer.FirstName, link, settings.MagicLinkTTLMinutes),
+		IsHTML: false,
+	}); err != nil {
+		return fmt.Errorf("failed to send sign-in email: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyMagicLink exchanges a magic link token for a portal session
+func (s *portalServiceImpl) VerifyMagicLink(ctx context.Context, token string, client *PortalClientInfo) (*PortalLoginResult, error) {
+	if token == "" {
+		return nil, fmt.Errorf("invalid or expired sign-in link")
+	}
+
+	now := time.Now()
+	loginToken, err := s.portalRepo.ConsumeLoginToken(ctx, HashPortalToken(token), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify sign-in link: %w", err)
+	}
+	if loginToken == nil {
+		return nil, fmt.Errorf("invalid or expired sign-in link")
+	}
+
+	ctx = context.WithValue(ctx, "tenant_id", loginToken.TenantID)
+	settings, err := s.getSettings(ctx, loginToken.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.customerRepo.GetByID(ctx, loginToken.TenantID, loginToken.CustomerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	if customer == nil || customer.Status != "active" {
+		return nil, fmt.Errorf("invalid or expired sign-in link")
+	}
+
+	sessionToken, err := generatePortalToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &domain.PortalSession{
+		ID:         uuid.New(),
+		TenantID:   loginToken.TenantID,
+		CustomerID: loginToken.CustomerID,
+		TokenHash:  HashPortalToken(sessionToken),
+		ExpiresAt:  now.Add(time.Duration(settings.SessionTTLHours) * time.Hour),
+		LastSeenAt: &now,
+		IPAddress:  client.ipAddress(),
+		UserAgent:  client.userAgent(),
+		CreatedAt:  now,
+	}
+	if err := s.portalRepo.CreateSession(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create portal session: %w", err)
+	}
+
+	s.logPortalAction(ctx, "portal.login", "customer", customer.ID, map[string]interface{}{
+		"session_id": session.ID,
+	})
+
+	return &PortalLoginResult{
+		SessionToken: sessionToken,
+		ExpiresAt:    session.ExpiresAt,
+		Profile:      portalProfile(customer),
+	}, nil
+}
+
+// AuthenticateSession returns the active session for a session token
+func (s *portalServiceImpl) AuthenticateSession(ctx context.Context, sessionToken string) (*domain.PortalSession, error) {
+	if sessionToken == "" {
+		return nil, fmt.Errorf("session token required")
+	}
+
+	session, err := s.portalRepo.GetSessionByTokenHash(ctx, HashPortalToken(sessionToken))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portal session: %w", err)
+	}
+
+	now := time.Now()
+	if session == nil || session.RevokedAt != nil || !now.Before(session.ExpiresAt) {
+		return nil, fmt.Errorf("invalid or expired session")
+	}
+
+	// Record activity at most every few minutes
+	if session.LastSeenAt == nil || now.Sub(*session.LastSeenAt) > 5*time.Minute {
+		if err := s.portalRepo.TouchSession(ctx, session.ID, now); err != nil {
+			s.logger.Printf("Failed to update portal session activity: %v", err)
+		}
+		session.LastSeenAt = &now
+	}
+
+	return session, nil
+}
+
+// Logout revokes a portal session
+func (s *portalServiceImpl) Logout(ctx context.Context, sessionToken string) error {
+	session, err := s.portalRepo.GetSessionByTokenHash(ctx, HashPortalToken(sessionToken))
+	if err != nil {
+		return fmt.Errorf("failed to get portal session: %w", err)
+	}
+	if session == nil || session.RevokedAt != nil {
+		return nil
+	}
+
+	if err := s.portalRepo.RevokeSession(ctx, session.ID, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke portal session: %w", err)
+	}
+	return nil
+}
+
+// GetProfile returns the signed-in customer's details
+func (s *portalServiceImpl) GetProfile(ctx context.Context) (*PortalProfile, error) {
+	customer, err := s.currentCustomer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return portalProfile(customer), nil
+}
+
+// ListProperties lists the customer's properties
+func (s *portalServiceImpl) ListProperties(ctx context.Context) ([]*domain.EnhancedProperty, error) {
+	tenantID, customerID, err := portalCustomerContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err := s.propertyRepo.GetByCustomerID(ctx, tenantID, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list properties: %w", err)
+	}
+	return properties, nil
+}
+
+// ListJobs lists the customer's visits, optionally only those still to come
+func (s *portalServiceImpl) ListJobs(ctx context.Context, upcomingOnly bool) ([]*domain.EnhancedJob, error) {
+	tenantID, customerID, err := portalCustomerContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &JobFilter{BaseFilter: BaseFilter{Page: 1, PerPage: 200, SortBy: "scheduled_date"}}
+	if upcomingOnly {
+		today := time.Now().Truncate(24 * time.Hour)
+		filter.ScheduledStart = &today
+	}
+
+	jobs, _, err := s.jobRepo.GetByCustomerID(ctx, tenantID, customerID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	if !upcomingOnly {
+		return jobs, nil
+	}
+
+	upcoming := make([]*domain.EnhancedJob, 0, len(jobs))
+	for _, job := range jobs {
+		if job.Status == domain.JobStatusPending || job.Status == domain.JobStatusScheduled {
+			upcoming = append(upcoming, job)
+		}
+	}
+	return upcoming, nil
+}
+
+// ListInvoices lists the customer's issued invoices with their balances
+func (s *portalServiceImpl) ListInvoices(ctx context.Context) ([]*PortalInvoice, error) {
+	tenantID, customerID, err := portalCustomerContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, _, err := s.invoiceRepo.GetByCustomerID(ctx, tenantID, customerID, &InvoiceFilter{BaseFilter: BaseFilter{Page: 1, PerPage: 200}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+
+	result := make([]*PortalInvoice, 0, len(invoices))
+	for _, invoice := range invoices {
+		if invoice.Status == "draft" {
+			continue
+		}
+		portalInvoice, err := s.portalInvoice(ctx, invoice)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, portalInvoice)
+	}
+	return result, nil
+}
+
+// GetInvoice returns one of the customer's invoices
+func (s *portalServiceImpl) GetInvoice(ctx context.Context, invoiceID uuid.UUID) (*PortalInvoice, error) {
+	invoice, err := s.customerInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logPortalAction(ctx, "portal.invoice_view", "invoice", invoice.ID, map[string]interface{}{
+		"invoice_number": invoice.InvoiceNumber,
+	})
+
+	return s.portalInvoice(ctx, invoice)
+}
+
+// ListQuotes lists quotes sent to the customer
+func (s *portalServiceImpl) ListQuotes(ctx context.Context) ([]*domain.Quote, error) {
+	tenantID, customerID, err := portalCustomerContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, _, err := s.quoteRepo.GetByCustomerID(ctx, tenantID, customerID, &QuoteFilter{BaseFilter: BaseFilter{Page: 1, PerPage: 200}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quotes: %w", err)
+	}
+
+	// Drafts are still being worked on and have not been sent
+	sent := make([]*domain.Quote, 0, len(quotes))
+	for _, quote := range quotes {
+		if quote.Status != "draft" {
+			sent = append(sent, quote)
+		}
+	}
+	return sent, nil
+}
+
+// GetQuote returns a quote with its options and signature
+func (s *portalServiceImpl) GetQuote(ctx context.Context, quoteID uuid.UUID) (*PortalQuote, error) {
+	quote, err := s.customerQuote(ctx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing, err := s.quoteService.GetQuotePricing(ctx, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote pricing: %w", err)
+	}
+
+	signature, err := s.portalRepo.GetQuoteSignature(ctx, quote.TenantID, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote signature: %w", err)
+	}
+
+	s.logPortalAction(ctx, "portal.quote_view", "quote", quote.ID, map[string]interface{}{
+		"quote_number": quote.QuoteNumber,
+	})
+
+	return &PortalQuote{Quote: quote, Pricing: pricing, Signature: signature}, nil
+}
+
+// AcceptQuote approves a quote with the customer's selection and records their e-signature
+func (s *portalServiceImpl) AcceptQuote(ctx context.Context, quoteID uuid.UUID, req *PortalQuoteAcceptRequest, client *PortalClientInfo) (*domain.QuoteSignature, error) {
+	settings, err := s.portalSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.AllowQuoteAcceptance {
+		return nil, fmt.Errorf("online quote acceptance is not enabled")
+	}
+
+	quote, err := s.customerQuote(ctx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Status != "pending" {
+		return nil, fmt.Errorf("quote is not awaiting acceptance")
+	}
+	if quote.ValidUntil != nil && time.Now().After(*quote.ValidUntil) {
+		return nil, fmt.Errorf("quote has expired")
+	}
+
+	signatureData, err := validateQuoteSignature(req)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	consent := DefaultSignatureConsent
+	if settings.SignatureConsentText != nil && *settings.SignatureConsentText != "" {
+		consent = *settings.SignatureConsentText
+	}
+
+	// The quote service pins the signature to the approved revision and total
+	signature := &domain.QuoteSignature{
+		ID:            uuid.New(),
+		TenantID:      quote.TenantID,
+		QuoteID:       quoteID,
+		CustomerID:    quote.CustomerID,
+		SignerName:    strings.TrimSpace(req.SignerName),
+		SignerEmail:   req.SignerEmail,
+		SignatureType: req.SignatureType,
+		SignatureData: signatureData,
+		ConsentText:   consent,
+		IPAddress:     client.ipAddress(),
+		UserAgent:     client.userAgent(),
+		SignedAt:      time.Now(),
+	}
+	if err := s.quoteService.ApproveQuoteWithSignature(ctx, quoteID, req.Selection, signature); err != nil {
+		return nil, err
+	}
+
+	s.logPortalAction(ctx, "portal.quote_accept", "quote", quoteID, map[string]interface{}{
+		"customer_id":      quote.CustomerID,
+		"signature_id":     signature.ID,
+		"quote_version_id": signature.QuoteVersionID,
+		"total_amount":     signature.TotalAmount,
+	})
+
+	return signature, nil
+}
+
+// DeclineQuote rejects a quote on the customer's behalf
+func (s *portalServiceImpl) DeclineQuote(ctx context.Context, quoteID uuid.UUID, reason string) error {
+	quote, err := s.customerQuote(ctx, quoteID)
+	if err != nil {
+		return err
+	}
+	if quote.Status != "pending" {
+		return fmt.Errorf("quote is not awaiting acceptance")
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		reason = "Declined by customer"
+	}
+	return s.quoteService.RejectQuote(ctx, quoteID, reason)
+}
+
+// PayInvoice charges a payment against one of the customer's invoices
+func (s *portalServiceImpl) PayInvoice(ctx context.Context, invoiceID uuid.UUID, req *PortalPaymentRequest) (*domain.Payment, error) {
+	settings, err := s.portalSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.AllowOnlinePayment || s.paymentService == nil {
+		return nil, fmt.Errorf("online payments are not enabled")
+	}
+
+	invoice, err := s.customerInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	portalInvoice, err := s.portalInvoice(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+	if !portalInvoice.Payable {
+		return nil, fmt.Errorf("invoice has no balance to pay")
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = portalInvoice.Balance
+	}
+	if amount < 0 || roundCents(amount) > roundCents(portalInvoice.Balance) {
+		return nil, fmt.Errorf("payment amount must be between 0 and the balance of %.2f", portalInvoice.Balance)
+	}
+	if req.PaymentToken == "" {
+		return nil, fmt.Errorf("payment token is required")
+	}
+
+	method := req.PaymentMethod
+	if method == "" {
+		method = "card"
+	}
+
+	payment, err := s.paymentService.ProcessPayment(ctx, &PaymentProcessRequest{
+		InvoiceID:     invoice.ID,
+		Amount:        roundCents(amount),
+		PaymentMethod: method,
+		PaymentToken:  req.PaymentToken,
+		CustomerID:    invoice.CustomerID,
+		Description:   stringPtr(fmt.Sprintf("Portal payment for invoice %s", invoice.InvoiceNumber)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("payment failed: %w", err)
+	}
+
+	s.logPortalAction(ctx, "portal.invoice_payment", "invoice", invoice.ID, map[string]interface{}{
+		"customer_id": invoice.CustomerID,
+		"payment_id":  payment.ID,
+		"amount":      payment.Amount,
+	})
+
+	return payment, nil
+}
+
+// RequestScheduleChange asks to skip or reschedule a visit. Requests that break the
+// tenant's rules are refused; the rest are applied immediately when the tenant
+// auto-approves changes, and otherwise queued for staff.
+func (s *portalServiceImpl) RequestScheduleChange(ctx context.Context, jobID uuid.UUID, req *PortalScheduleChangeRequest) (*domain.PortalScheduleChange, error) {
+	tenantID, customerID, err := portalCustomerContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.portalSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.currentCustomer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.jobRepo.GetByID(ctx, tenantID, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	if job == nil || job.CustomerID != customerID {
+		return nil, fmt.Errorf("job not found")
+	}
+
+	pending, err := s.portalRepo.GetPendingScheduleChange(ctx, tenantID, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check pending changes: %w", err)
+	}
+	if pending != nil {
+		return nil, fmt.Errorf("a change request for this visit is already pending")
+	}
+
+	now := time.Now()
+	skips := 0
+	if req.ChangeType == domain.ScheduleChangeSkip && settings.MaxSkipsPerYear > 0 {
+		skips, err = s.portalRepo.CountApprovedSkipsSince(ctx, tenantID, customerID, now.AddDate(-1, 0, 0))
+		if err != nil {
+			return nil, fmt.Errorf("failed to count skipped visits: %w", err)
+		}
+	}
+
+	if err := ValidateScheduleChange(settings, job, req, now, skips, customer.SchedulingSuspended); err != nil {
+		return nil, err
+	}
+
+	change := &domain.PortalScheduleChange{
+		ID:            uuid.New(),
+		TenantID:      tenantID,
+		CustomerID:    customerID,
+		JobID:         jobID,
+		ChangeType:    req.ChangeType,
+		OriginalDate:  job.ScheduledDate,
+		RequestedDate: req.RequestedDate,
+		Reason:        req.Reason,
+		Status:        domain.ScheduleChangeStatusPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if settings.AutoApproveChanges {
+		if err := s.applyScheduleChange(ctx, job, change); err != nil {
+			return nil, err
+		}
+		change.Status = domain.ScheduleChangeStatusApproved
+		change.AutoApproved = true
+		change.ReviewedAt = &now
+	}
+
+	if err := s.portalRepo.CreateScheduleChange(ctx, change); err != nil {
+		return nil, fmt.Errorf("failed to create schedule change: %w", err)
+	}
+
+	s.logPortalAction(ctx, "portal.schedule_change_request", "job", jobID, map[string]interface{}{
+		"customer_id":    customerID,
+		"change_id":      change.ID,
+		"change_type":    change.ChangeType,
+		"requested_date": change.RequestedDate,
+		"status":         change.Status,
+	})
+
+	return change, nil
+}
+
+// ListMyScheduleChanges lists the customer's skip and reschedule requests
+func (s *portalServiceImpl) ListMyScheduleChanges(ctx context.Context) ([]*domain.PortalScheduleChange, error) {
+	tenantID, customerID, err := portalCustomerContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.portalRepo.ListScheduleChanges(ctx, tenantID, &PortalRequestFilter{CustomerID: &customerID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedule changes: %w", err)
+	}
+	return changes, nil
+}
+
+// SubmitServiceRequest adds a customer's service request to the admin queue
+func (s *portalServiceImpl) SubmitServiceRequest(ctx context.Context, req *PortalServiceRequestInput) (*domain.PortalServiceRequest, error) {
+	tenantID, customerID, err := portalCustomerContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		return nil, fmt.Errorf("description is required")
+	}
+
+	if req.PropertyID != nil {
+		property, err := s.propertyRepo.GetByID(ctx, tenantID, *req.PropertyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get property: %w", err)
+		}
+		if property == nil || property.CustomerID != customerID {
+			return nil, fmt.Errorf("property not found")
+		}
+	}
+
+	now := time.Now()
+	request := &domain.PortalServiceRequest{
+		ID:            uuid.New(),
+		TenantID:      tenantID,
+		CustomerID:    customerID,
+		PropertyID:    req.PropertyID,
+		ServiceID:     req.ServiceID,
+		Description:   description,
+		PreferredDate: req.PreferredDate,
+		Status:        domain.PortalRequestStatusNew,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.portalRepo.CreateServiceRequest(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to create service request: %w", err)
+	}
+
+	s.logPortalAction(ctx, "portal.service_request", "portal_service_request", request.ID, map[string]interface{}{
+		"customer_id": customerID,
+		"property_id": request.PropertyID,
+		"service_id":  request.ServiceID,
+	})
+
+	return request, nil
+}
+
+// ListMyServiceRequests lists the customer's service requests
+func (s *portalServiceImpl) ListMyServiceRequests(ctx context.Context) ([]*domain.PortalServiceRequest, error) {
+	tenantID, customerID, err := portalCustomerContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := s.portalRepo.ListServiceRequests(ctx, tenantID, &PortalRequestFilter{CustomerID: &customerID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service requests: %w", err)
+	}
+	return requests, nil
+}
+
+// GetPortalSettings returns the tenant's portal rules, or the defaults if none are saved
+func (s *portalServiceImpl) GetPortalSettings(ctx context.Context) (*domain.PortalSettings, error) {
+	return s.portalSettings(ctx)
+}
+
+// UpdatePortalSettings updates the tenant's portal rules
+func (s *portalServiceImpl) UpdatePortalSettings(ctx context.Context, req *PortalSettingsRequest) (*domain.PortalSettings, error) {
+	settings, err := s.portalSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Enabled != nil {
+		settings.Enabled = *req.Enabled
+	}
+	if req.PortalURL != nil {
+		settings.PortalURL = req.PortalURL
+	}
+	if req.MagicLinkTTLMinutes != nil {
+		if *req.MagicLinkTTLMinutes < 1 || *req.MagicLinkTTLMinutes > 24*60 {
+			return nil, fmt.Errorf("magic link lifetime must be between 1 minute and 24 hours")
+		}
+		settings.MagicLinkTTLMinutes = *req.MagicLinkTTLMinutes
+	}
+	if req.SessionTTLHours != nil {
+		if *req.SessionTTLHours < 1 {
+			return nil, fmt.Errorf("session lifetime must be at least 1 hour")
+		}
+		settings.SessionTTLHours = *req.SessionTTLHours
+	}
+	if req.AllowOnlinePayment != nil {
+		settings.AllowOnlinePayment = *req.AllowOnlinePayment
+	}
+	if req.AllowQuoteAcceptance != nil {
+		settings.AllowQuoteAcceptance = *req.AllowQuoteAcceptance
+	}
+	if req.AllowSkip != nil {
+		settings.AllowSkip = *req.AllowSkip
+	}
+	if req.AllowReschedule != nil {
+		settings.AllowReschedule = *req.AllowReschedule
+	}
+	if req.MinNoticeHours != nil {
+		if *req.MinNoticeHours < 0 {
+			return nil, fmt.Errorf("minimum notice cannot be negative")
+		}
+		settings.MinNoticeHours = *req.MinNoticeHours
+	}
+	if req.MaxRescheduleDays != nil {
+		if *req.MaxRescheduleDays < 0 {
+			return nil, fmt.Errorf("maximum reschedule days cannot be negative")
+		}
+		settings.MaxRescheduleDays = *req.MaxRescheduleDays
+	}
+	if req.MaxSkipsPerYear != nil {
+		if *req.MaxSkipsPerYear < 0 {
+			return nil, fmt.Errorf("maximum skips cannot be negative")
+		}
+		settings.MaxSkipsPerYear = *req.MaxSkipsPerYear
+	}
+	if req.AutoApproveChanges != nil {
+		settings.AutoApproveChanges = *req.AutoApproveChanges
+	}
+	if req.SignatureConsentText != nil {
+		settings.SignatureConsentText = req.SignatureConsentText
+	}
+	settings.UpdatedAt = time.Now()
+
+	if err := s.portalRepo.UpsertSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to update portal settings: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "portal_settings.update",
+		ResourceType: "portal_settings",
+		ResourceID:   &settings.ID,
+		NewValues: map[string]interface{}{
+			"enabled":              settings.Enabled,
+			"allow_skip":           settings.AllowSkip,
+			"allow_reschedule":     settings.AllowReschedule,
+			"min_notice_hours":     settings.MinNoticeHours,
+			"auto_approve_changes": settings.AutoApproveChanges,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return settings, nil
+}
+
+// ListScheduleChanges lists skip and reschedule requests for staff
+func (s *portalServiceImpl) ListScheduleChanges(ctx context.Context, filter *PortalRequestFilter) ([]*domain.PortalScheduleChange, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	changes, err := s.portalRepo.ListScheduleChanges(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedule changes: %w", err)
+	}
+	return changes, nil
+}
+
+// ReviewScheduleChange approves or declines a pending schedule change. Approval
+// applies the change to the visit and the customer is emailed either way.
+func (s *portalServiceImpl) ReviewScheduleChange(ctx context.Context, changeID uuid.UUID, req *ScheduleChangeReviewRequest) (*domain.PortalScheduleChange, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	change, err := s.portalRepo.GetScheduleChange(ctx, tenantID, changeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule change: %w", err)
+	}
+	if change == nil {
+		return nil, fmt.Errorf("schedule change not found")
+	}
+	if change.Status != domain.ScheduleChangeStatusPending {
+		return nil, fmt.Errorf("schedule change has already been reviewed")
+	}
+
+	if req.Approve {
+		job, err := s.jobRepo.GetByID(ctx, tenantID, change.JobID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job: %w", err)
+		}
+		if job == nil {
+			return nil, fmt.Errorf("job not found")
+		}
+		if job.Status != domain.JobStatusPending && job.Status != domain.JobStatusScheduled {
+			return nil, fmt.Errorf("visit can no longer be changed (status %s)", job.Status)
+		}
+		if err := s.applyScheduleChange(ctx, job, change); err != nil {
+			return nil, err
+		}
+		change.Status = domain.ScheduleChangeStatusApproved
+	} else {
+		change.Status = domain.ScheduleChangeStatusDeclined
+	}
+
+	now := time.Now()
+	change.ReviewedBy = GetUserIDFromContext(ctx)
+	change.ReviewedAt = &now
+	change.ResponseNote = req.ResponseNote
+	change.UpdatedAt = now
+
+	if err := s.portalRepo.UpdateScheduleChange(ctx, change); err != nil {
+		return nil, fmt.Errorf("failed to update schedule change: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       change.ReviewedBy,
+		Action:       "portal.schedule_change_review",
+		ResourceType: "portal_schedule_change",
+		ResourceID:   &change.ID,
+		NewValues: map[string]interface{}{
+			"status": change.Status,
+			"job_id": change.JobID,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	s.notifyScheduleChangeReviewed(ctx, change)
+
+	return change, nil
+}
+
+// ListServiceRequests lists the service request queue for staff
+func (s *portalServiceImpl) ListServiceRequests(ctx context.Context, filter *PortalRequestFilter) ([]*domain.PortalServiceRequest, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	requests, err := s.portalRepo.ListServiceRequests(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service requests: %w", err)
+	}
+	return requests, nil
+}
+
+// UpdateServiceRequest updates a service request's status, notes or assignee
+func (s *portalServiceImpl) UpdateServiceRequest(ctx context.Context, requestID uuid.UUID, req *PortalServiceRequestUpdate) (*domain.PortalServiceRequest, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	request, err := s.portalRepo.GetServiceRequest(ctx, tenantID, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service request: %w", err)
+	}
+	if request == nil {
+		return nil, fmt.Errorf("service request not found")
+	}
+
+	oldStatus := request.Status
+	if req.Status != "" {
+		switch req.Status {
+		case domain.PortalRequestStatusNew, domain.PortalRequestStatusInReview, domain.PortalRequestStatusQuoted,
+			domain.PortalRequestStatusScheduled, domain.PortalRequestStatusClosed:
+			request.Status = req.Status
+		default:
+			return nil, fmt.Errorf("invalid service request status: %s", req.Status)
+		}
+	}
+	if req.AdminNotes != nil {
+		request.AdminNotes = req.AdminNotes
+	}
+	if req.AssignedTo != nil {
+		request.AssignedTo = req.AssignedTo
+	}
+	request.UpdatedAt = time.Now()
+
+	if err := s.portalRepo.UpdateServiceRequest(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to update service request: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "portal.service_request_update",
+		ResourceType: "portal_service_request",
+		ResourceID:   &request.ID,
+		OldValues:    map[string]interface{}{"status": oldStatus},
+		NewValues:    map[string]interface{}{"status": request.Status, "assigned_to": request.AssignedTo},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return request, nil
+}
+
+// ValidateScheduleChange checks a skip or reschedule request against the tenant's
+// portal rules. skipsThisYear is the customer's approved skips over the past year.
+func ValidateScheduleChange(settings *domain.PortalSettings, job *domain.EnhancedJob, req *PortalScheduleChangeRequest, now time.Time, skipsThisYear int, schedulingSuspended bool) error {
+	if job.Status != domain.JobStatusPending && job.Status != domain.JobStatusScheduled {
+		return fmt.Errorf("visit can no longer be changed (status %s)", job.Status)
+	}
+	if job.ScheduledDate == nil {
+		return fmt.Errorf("visit has not been scheduled yet")
+	}
+
+	notice := time.Duration(settings.MinNoticeHours) * time.Hour
+	if job.ScheduledDate.Sub(now) < notice {
+		return fmt.Errorf("changes require at least %d hours notice", settings.MinNoticeHours)
+	}
+
+	switch req.ChangeType {
+	case domain.ScheduleChangeSkip:
+		if !settings.AllowSkip {
+			return fmt.Errorf("skipping visits online is not allowed")
+		}
+		if settings.MaxSkipsPerYear > 0 && skipsThisYear >= settings.MaxSkipsPerYear {
+			return fmt.Errorf("skip limit of %d per year reached", settings.MaxSkipsPerYear)
+		}
+
+	case domain.ScheduleChangeReschedule:
+		if !settings.AllowReschedule {
+			return fmt.Errorf("rescheduling visits online is not allowed")
+		}
+		if schedulingSuspended {
+			return fmt.Errorf("scheduling is on hold for this account; please contact us")
+		}
+		if req.RequestedDate == nil {
+			return fmt.Errorf("requested date is required to reschedule")
+		}
+		if req.RequestedDate.Sub(now) < notice {
+			return fmt.Errorf("new date must be at least %d hours from now", settings.MinNoticeHours)
+		}
+		if settings.MaxRescheduleDays > 0 {
+			days := math.Abs(req.RequestedDate.Sub(*job.ScheduledDate).Hours()) / 24
+			if days > float64(settings.MaxRescheduleDays) {
+				return fmt.Errorf("visits can only move up to %d days", settings.MaxRescheduleDays)
+			}
+		}
+
+	default:
+		return fmt.Errorf("change type must be skip or reschedule")
+	}
+
+	return nil
+}
+
+// applyScheduleChange skips or moves the visit
+func (s *portalServiceImpl) applyScheduleChange(ctx context.Context, job *domain.EnhancedJob, change *domain.PortalScheduleChange) error {
+	var note string
+	switch change.ChangeType {
+	case domain.ScheduleChangeSkip:
+		job.Status = domain.JobStatusCancelled
+		note = "Visit skipped at customer request"
+	case domain.ScheduleChangeReschedule:
+		job.ScheduledDate = change.RequestedDate
+		note = fmt.Sprintf("Rescheduled at customer request to %s", change.RequestedDate.Format("Jan 2, 2006"))
+	}
+
+	if job.Notes != nil && *job.Notes != "" {
+		note = *job.Notes + "\n" + note
+	}
+	job.Notes = &note
+	job.UpdatedAt = time.Now()
+
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	return nil
+}
+
+func (s *portalServiceImpl) notifyScheduleChangeReviewed(ctx context.Context, change *domain.PortalScheduleChange) {
+	customer, err := s.customerRepo.GetByID(ctx, change.TenantID, change.CustomerID)
+	if err != nil || customer == nil || customer.Email == nil || *customer.Email == "" {
+		return
+	}
+
+	request := "skip your visit"
+	if change.ChangeType == domain.ScheduleChangeReschedule && change.RequestedDate != nil {
+		request = fmt.Sprintf("move your visit to %s", change.RequestedDate.Format("Monday, Jan 2"))
+	}
+	outcome := "has been approved"
+	if change.Status == domain.ScheduleChangeStatusDeclined {
+		outcome = "could not be approved"
+	}
+
+	body := fmt.Sprintf("Hello %s,\n\nYour request to %s %s.", customer.FirstName, request, outcome)
+	if change.ResponseNote != nil && *change.ResponseNote != "" {
+		body += "\n\n" + *change.ResponseNote
+	}
+
+	if err := s.communicationService.SendEmail(ctx, &EmailRequest{
+		To:      []string{*customer.Email},
+		Subject: "Update on your schedule change request",
+		Body:    body,
+		IsHTML:  false,
+	}); err != nil {
+		s.logger.Printf("Failed to send schedule change email: %v", err)
+	}
+}
+
+// Helper methods
+
+func portalCustomerContext(ctx context.Context) (uuid.UUID, uuid.UUID, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("tenant ID not found in context")
+	}
+	customerID, ok := GetPortalCustomerIDFromContext(ctx)
+	if !ok {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("portal customer not found in context")
+	}
+	return tenantID, customerID, nil
+}
+
+func (s *portalServiceImpl) currentCustomer(ctx context.Context) (*domain.EnhancedCustomer, error) {
+	tenantID, customerID, err := portalCustomerContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.customerRepo.GetByID(ctx, tenantID, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	if customer == nil {
+		return nil, fmt.Errorf("customer not found")
+	}
+	return customer, nil
+}
+
+func (s *portalServiceImpl) customerInvoice(ctx context.Context, invoiceID uuid.UUID) (*domain.Invoice, error) {
+	tenantID, customerID, err := portalCustomerContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(ctx, tenantID, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	if invoice == nil || invoice.CustomerID != customerID || invoice.Status == "draft" {
+		return nil, fmt.Errorf("invoice not found")
+	}
+	return invoice, nil
+}
+
+func (s *portalServiceImpl) customerQuote(ctx context.Context, quoteID uuid.UUID) (*domain.Quote, error) {
+	tenantID, customerID, err := portalCustomerContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := s.quoteRepo.GetByID(ctx, tenantID, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+	if quote == nil || quote.CustomerID != customerID || quote.Status == "draft" {
+		return nil, fmt.Errorf("quote not found")
+	}
+	return quote, nil
+}
+
+func (s *portalServiceImpl) portalInvoice(ctx context.Context, invoice *domain.Invoice) (*PortalInvoice, error) {
+	payments, err := s.paymentRepo.GetByInvoiceID(ctx, invoice.TenantID, invoice.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice payments: %w", err)
+	}
+
+	paid := 0.0
+	for _, payment := range payments {
+		if payment.Status == "completed" {
+			paid += payment.Amount
+		}
+	}
+
+	balance := roundCents(invoice.TotalAmount - paid)
+	if balance < 0 {
+		balance = 0
+	}
+
+	return &PortalInvoice{
+		Invoice:    invoice,
+		AmountPaid: roundCents(paid),
+		Balance:    balance,
+		Payable:    balance > 0 && invoice.Status != "paid" && invoice.Status != "cancelled",
+	}, nil
+}
+
+func (s *portalServiceImpl) portalSettings(ctx context.Context) (*domain.PortalSettings, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+	return s.getSettings(ctx, tenantID)
+}
+
+func (s *portalServiceImpl) getSettings(ctx context.Context, tenantID uuid.UUID) (*domain.PortalSettings, error) {
+	settings, err := s.portalRepo.GetSettings(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portal settings: %w", err)
+	}
+	if settings == nil {
+		settings = DefaultPortalSettings(tenantID)
+	}
+	return settings, nil
+}
+
+// DefaultPortalSettings are the portal rules for a tenant that has not configured any
+func DefaultPortalSettings(tenantID uuid.UUID) *domain.PortalSettings {
+	now := time.Now()
+	return &domain.PortalSettings{
+		ID:                   uuid.New(),
+		TenantID:             tenantID,
+		Enabled:              true,
+		MagicLinkTTLMinutes:  15,
+		SessionTTLHours:      720,
+		AllowOnlinePayment:   true,
+		AllowQuoteAcceptance: true,
+		AllowSkip:            true,
+		AllowReschedule:      true,
+		MinNoticeHours:       48,
+		MaxRescheduleDays:    14,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+}
+
+func (s *portalServiceImpl) logPortalAction(ctx context.Context, action, resourceType string, resourceID uuid.UUID, newValues map[string]interface{}) {
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   &resourceID,
+		NewValues:    newValues,
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+}
+
+// validateQuoteSignature checks the e-signature and returns the data to store
+func validateQuoteSignature(req *PortalQuoteAcceptRequest) (string, error) {
+	if strings.TrimSpace(req.SignerName) == "" {
+		return "", fmt.Errorf("signer name is required")
+	}
+	if !req.AgreeToTerms {
+		return "", fmt.Errorf("you must agree to the terms to accept")
+	}
+
+	switch req.SignatureType {
+	case domain.SignatureTypeTyped:
+		if strings.TrimSpace(req.SignatureData) == "" {
+			return strings.TrimSpace(req.SignerName), nil
+		}
+		return strings.TrimSpace(req.SignatureData), nil
+	case domain.SignatureTypeDrawn:
+		if !strings.HasPrefix(req.SignatureData, "data:image/") {
+			return "", fmt.Errorf("drawn signature must be an image")
+		}
+		return req.SignatureData, nil
+	default:
+		return "", fmt.Errorf("signature type must be typed or drawn")
+	}
+}
+
+func portalProfile(customer *domain.EnhancedCustomer) *PortalProfile {
+	return &PortalProfile{
+		CustomerID:          customer.ID,
+		Name:                customerDisplayName(customer),
+		Email:               customer.Email,
+		Phone:               customer.Phone,
+		SchedulingSuspended: customer.SchedulingSuspended,
+	}
+}
+
+func generatePortalToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (c *PortalClientInfo) ipAddress() *string {
+	if c == nil || c.IPAddress == "" {
+		return nil
+	}
+	return &c.IPAddress
+}
+
+func (c *PortalClientInfo) userAgent() *string {
+	if c == nil || c.UserAgent == "" {
+		return nil
+	}
+	return &c.UserAgent
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// Price books are read the same way by the pricing repository and by the public web
+// calculator, which runs without the full repository layer, so both load them through here.
+
+// PriceBookColumns lists the price_books columns in the order ScanPriceBook reads them
+const PriceBookColumns = `
+	id, tenant_id, name, description, effective_from, effective_to, is_active, created_at, updated_at`
+
+// PricingRuleColumns lists the pricing_rules columns in the order ScanPricingRule reads them
+const PricingRuleColumns = `
+	id, tenant_id, price_book_id, name, rule_type, service_id, service_key, category, unit,
+	measured_area, rate, min_value, max_value, frequency, zip_codes, is_active, created_at, updated_at`
+
+// PriceBookQueryer runs price book queries against a database
+type PriceBookQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// PriceBookScanner scans a single price book or pricing rule row
+type PriceBookScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// LoadPriceBooksInEffect loads a tenant's active price books covering a date, with their rules
+func LoadPriceBooksInEffect(ctx context.Context, db PriceBookQueryer, tenantID uuid.UUID, date time.Time) ([]*domain.PriceBook, error) {
+	query := `
+		SELECT ` + PriceBookColumns + `
+		FROM price_books
+		WHERE tenant_id = $1
+		  AND is_active = TRUE
+		  AND effective_from <= $2::date
+		  AND (effective_to IS NULL OR effective_to >= $2::date)
+		ORDER BY effective_from DESC`
+
+	return LoadPriceBooks(ctx, db, tenantID, query, tenantID, date.Format("2006-01-02"))
+}
+
+// LoadPriceBooks runs a query selecting PriceBookColumns and attaches each book's rules
+func LoadPriceBooks(ctx context.Context, db PriceBookQueryer, tenantID uuid.UUID, query string, args ...interface{}) ([]*domain.PriceBook, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price books: %w", err)
+	}
+	defer rows.Close()
+
+	var books []*domain.PriceBook
+	for rows.Next() {
+		book, err := ScanPriceBook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan price book: %w", err)
+		}
+		books = append(books, book)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate price books: %w", err)
+	}
+
+	if err := LoadPricingRules(ctx, db, tenantID, books); err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// LoadPricingRules attaches each book's rules
+func LoadPricingRules(ctx context.Context, db PriceBookQueryer, tenantID uuid.UUID, books []*domain.PriceBook) error {
+	if len(books) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*domain.PriceBook, len(books))
+	bookIDs := make([]string, 0, len(books))
+	for _, book := range books {
+		byID[book.ID] = book
+		bookIDs = append(bookIDs, book.ID.String())
+	}
+
+	query := `
+		SELECT ` + PricingRuleColumns + `
+		FROM pricing_rules
+		WHERE tenant_id = $1 AND price_book_id::text = ANY($2)
+		ORDER BY rule_type, min_value NULLS FIRST, created_at`
+
+	rows, err := db.QueryContext(ctx, query, tenantID, pq.Array(bookIDs))
+	if err != nil {
+		return fmt.Errorf("failed to get pricing rules: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rule, err := ScanPricingRule(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan pricing rule: %w", err)
+		}
+		if book, ok := byID[rule.PriceBookID]; ok {
+			book.Rules = append(book.Rules, *rule)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate pricing rules: %w", err)
+	}
+
+	return nil
+}
+
+// ScanPriceBook scans a row selected with PriceBookColumns
+func ScanPriceBook(row PriceBookScanner) (*domain.PriceBook, error) {
+	var book domain.PriceBook
+	if err := row.Scan(
+		&book.ID,
+		&book.TenantID,
+		&book.Name,
+		&book.Description,
+		&book.EffectiveFrom,
+		&book.EffectiveTo,
+		&book.IsActive,
+		&book.CreatedAt,
+		&book.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// ScanPricingRule scans a row selected with PricingRuleColumns
+func ScanPricingRule(row PriceBookScanner) (*domain.PricingRule, error) {
+	var rule domain.PricingRule
+	var zipCodes []byte
+	if err := row.Scan(
+		&rule.ID,
+		&rule.TenantID,
+		&rule.PriceBookID,
+		&rule.Name,
+		&rule.RuleType,
+		&rule.ServiceID,
+		&rule.ServiceKey,
+		&rule.Category,
+		&rule.Unit,
+		&rule.MeasuredArea,
+		&rule.Rate,
+		&rule.MinValue,
+		&rule.MaxValue,
+		&rule.Frequency,
+		&zipCodes,
+		&rule.IsActive,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	rule.ZipCodes = []string{}
+	if len(zipCodes) > 0 {
+		if err := json.Unmarshal(zipCodes, &rule.ZipCodes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal zip codes: %w", err)
+		}
+	}
+
+	return &rule, nil
+}
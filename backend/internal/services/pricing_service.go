@@ -0,0 +1,833 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// PricingService manages tenant price books and prices services against them. The
+// public booking calculator, quotes and the service catalog all price through it.
+type PricingService interface {
+	// Price books
+	CreatePriceBook(ctx context.Context, req *PriceBookRequest) (*domain.PriceBook, error)
+	GetPriceBook(ctx context.Context, bookID uuid.UUID) (*domain.PriceBook, error)
+	UpdatePriceBook(ctx context.Context, bookID uuid.UUID, req *PriceBookRequest) (*domain.PriceBook, error)
+	DeletePriceBook(ctx context.Context, bookID uuid.UUID) error
+	ListPriceBooks(ctx context.Context) ([]*domain.PriceBook, error)
+
+	// Rules
+	AddPricingRule(ctx context.Context, bookID uuid.UUID, req *PricingRuleRequest) (*domain.PricingRule, error)
+	UpdatePricingRule(ctx context.Context, ruleID uuid.UUID, req *PricingRuleRequest) (*domain.PricingRule, error)
+	DeletePricingRule(ctx context.Context, ruleID uuid.UUID) error
+
+	// Pricing
+	CalculatePrice(ctx context.Context, req *PriceRequest) (*PriceBreakdown, error)
+	CalculateServicePrice(ctx context.Context, serviceID uuid.UUID, quantity float64, propertyDetails *PropertyDetails) (*ServicePricing, error)
+}
+
+// PricingRepository defines data access for price books and rules
+type PricingRepository interface {
+	// Price books; reads include the book's rules
+	CreatePriceBook(ctx context.Context, book *domain.PriceBook) error
+	GetPriceBook(ctx context.Context, tenantID, bookID uuid.UUID) (*domain.PriceBook, error)
+	UpdatePriceBook(ctx context.Context, book *domain.PriceBook) error
+	DeletePriceBook(ctx context.Context, tenantID, bookID uuid.UUID) error
+	ListPriceBooks(ctx context.Context, tenantID uuid.UUID) ([]*domain.PriceBook, error)
+	GetPriceBooksInEffect(ctx context.Context, tenantID uuid.UUID, date time.Time) ([]*domain.PriceBook, error)
+
+	// Rules
+	CreatePricingRule(ctx context.Context, rule *domain.PricingRule) error
+	GetPricingRule(ctx context.Context, tenantID, ruleID uuid.UUID) (*domain.PricingRule, error)
+	UpdatePricingRule(ctx context.Context, rule *domain.PricingRule) error
+	DeletePricingRule(ctx context.Context, tenantID, ruleID uuid.UUID) error
+}
+
+// PriceBookRequest creates or updates a price book
+type PriceBookRequest struct {
+	Name          string     `json:"name" validate:"required"`
+	Description   *string    `json:"description,omitempty"`
+	EffectiveFrom time.Time  `json:"effective_from" validate:"required"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+	IsActive      *bool      `json:"is_active,omitempty"`
+}
+
+// PricingRuleRequest creates or updates a pricing rule
+type PricingRuleRequest struct {
+	Name         string     `json:"name" validate:"required"`
+	RuleType     string     `json:"rule_type" validate:"required"`
+	ServiceID    *uuid.UUID `json:"service_id,omitempty"`
+	ServiceKey   *string    `json:"service_key,omitempty"`
+	Category     *string    `json:"category,omitempty"`
+	Unit         *string    `json:"unit,omitempty"`
+	MeasuredArea *string    `json:"measured_area,omitempty"` // site map feature type a square-foot rule prices
+	Rate         float64    `json:"rate"`
+	MinValue     *float64   `json:"min_value,omitempty"`
+	MaxValue     *float64   `json:"max_value,omitempty"`
+	Frequency    *string    `json:"frequency,omitempty"`
+	ZipCodes     []string   `json:"zip_codes,omitempty"`
+	IsActive     *bool      `json:"is_active,omitempty"`
+}
+
+// PriceRequest describes a service to price
+type PriceRequest struct {
+	ServiceID    *uuid.UUID `json:"service_id,omitempty"`
+	ServiceKey   string     `json:"service_key,omitempty"` // public catalog key, e.g. lawn_care
+	ServiceName  string     `json:"service_name,omitempty"`
+	Category     string     `json:"category,omitempty"`
+	BasePrice    *float64   `json:"base_price,omitempty"` // catalog price used when no rate rule applies
+	Unit         string     `json:"unit,omitempty"`
+	Quantity     float64    `json:"quantity"`      // hours, yards or visits; square-foot rates use the area
+	PropertySize float64    `json:"property_size"` // square feet
+	PropertyID   *uuid.UUID `json:"property_id,omitempty"`
+	Frequency    string     `json:"frequency,omitempty"`
+	ZipCode      string     `json:"zip_code,omitempty"`
+	Distance     float64    `json:"distance,omitempty"` // miles from the service area
+	SameDay      bool       `json:"same_day,omitempty"`
+	ServiceCount int        `json:"service_count,omitempty"` // services booked together
+	Date         time.Time  `json:"date"`
+
+	// MeasuredAreas are the property's site map areas in square feet by feature type.
+	// They are loaded from the site map when a property ID is given without them.
+	MeasuredAreas map[string]float64 `json:"measured_areas,omitempty"`
+}
+
+// PriceBreakdown is a priced service with each adjustment the rules applied
+type PriceBreakdown struct {
+	ServiceName string            `json:"service_name"`
+	Unit        string            `json:"unit"`
+	UnitRate    float64           `json:"unit_rate"`
+	Quantity    float64           `json:"quantity"`
+	BaseAmount  float64           `json:"base_amount"`
+	Adjustments []PriceAdjustment `json:"adjustments"`
+	Total       float64           `json:"total"`
+}
+
+// ServicePricing converts the breakdown to the service catalog's pricing result
+func (b *PriceBreakdown) ServicePricing() *ServicePricing {
+	return &ServicePricing{
+		BasePrice:   b.UnitRate,
+		Quantity:    b.Quantity,
+		Adjustments: b.Adjustments,
+		TotalPrice:  b.Total,
+	}
+}
+
+// PricingServiceImpl implements PricingService
+type PricingServiceImpl struct {
+	pricingRepo  PricingRepository
+	serviceRepo  ServiceRepository
+	siteMapRepo  SiteMapRepository
+	auditService AuditService
+	logger       *log.Logger
+}
+
+// NewPricingService creates a new pricing service
+func NewPricingService(
+	pricingRepo PricingRepository,
+	serviceRepo ServiceRepository,
+	siteMapRepo SiteMapRepository,
+	auditService AuditService,
+	logger *log.Logger,
+) PricingService {
+	return &PricingServiceImpl{
+		pricingRepo:  pricingRepo,
+		serviceRepo:  serviceRepo,
+		siteMapRepo:  siteMapRepo,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// CreatePriceBook creates a price book
+func (s *PricingServiceImpl) CreatePriceBook(ctx context.Context, req *PriceBookRequest) (*domain.PriceBook, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if err := validatePriceBookRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	now := time.Now()
+	book := &domain.PriceBook{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	applyPriceBookRequest(book, req)
+
+	if err := s.pricingRepo.CreatePriceBook(ctx, book); err != nil {
+		return nil, fmt.Errorf("failed to create price book: %w", err)
+	}
+
+	s.logPricingAction(ctx, "price_book.create", "price_book", book.ID, nil, map[string]interface{}{
+		"name":           book.Name,
+		"effective_from": book.EffectiveFrom,
+		"effective_to":   book.EffectiveTo,
+	})
+
+	return book, nil
+}
+
+// GetPriceBook retrieves a price book with its rules
+func (s *PricingServiceImpl) GetPriceBook(ctx context.Context, bookID uuid.UUID) (*domain.PriceBook, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	book, err := s.pricingRepo.GetPriceBook(ctx, tenantID, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price book: %w", err)
+	}
+	if book == nil {
+		return nil, fmt.Errorf("price book not found")
+	}
+
+	return book, nil
+}
+
+// UpdatePriceBook updates a price book's name, dates and status
+func (s *PricingServiceImpl) UpdatePriceBook(ctx context.Context, bookID uuid.UUID, req *PriceBookRequest) (*domain.PriceBook, error) {
+	book, err := s.GetPriceBook(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validatePriceBookRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	oldValues := map[string]interface{}{
+		"name":           book.Name,
+		"effective_from": book.EffectiveFrom,
+		"effective_to":   book.EffectiveTo,
+		"is_active":      book.IsActive,
+	}
+
+	applyPriceBookRequest(book, req)
+	book.UpdatedAt = time.Now()
+
+	if err := s.pricingRepo.UpdatePriceBook(ctx, book); err != nil {
+		return nil, fmt.Errorf("failed to update price book: %w", err)
+	}
+
+	s.logPricingAction(ctx, "price_book.update", "price_book", book.ID, oldValues, map[string]interface{}{
+		"name":           book.Name,
+		"effective_from": book.EffectiveFrom,
+		"effective_to":   book.EffectiveTo,
+		"is_active":      book.IsActive,
+	})
+
+	return book, nil
+}
+
+// DeletePriceBook deletes a price book and its rules
+func (s *PricingServiceImpl) DeletePriceBook(ctx context.Context, bookID uuid.UUID) error {
+	book, err := s.GetPriceBook(ctx, bookID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.pricingRepo.DeletePriceBook(ctx, book.TenantID, bookID); err != nil {
+		return fmt.Errorf("failed to delete price book: %w", err)
+	}
+
+	s.logPricingAction(ctx, "price_book.delete", "price_book", book.ID, map[string]interface{}{
+		"name":       book.Name,
+		"rule_count": len(book.Rules),
+	}, nil)
+
+	return nil
+}
+
+// ListPriceBooks lists the tenant's price books with their rules
+func (s *PricingServiceImpl) ListPriceBooks(ctx context.Context) ([]*domain.PriceBook, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	books, err := s.pricingRepo.ListPriceBooks(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price books: %w", err)
+	}
+
+	return books, nil
+}
+
+// AddPricingRule adds a rule to a price book
+func (s *PricingServiceImpl) AddPricingRule(ctx context.Context, bookID uuid.UUID, req *PricingRuleRequest) (*domain.PricingRule, error) {
+	book, err := s.GetPriceBook(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidatePricingRuleRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	now := time.Now()
+	rule := &domain.PricingRule{
+		ID:          uuid.New(),
+		TenantID:    book.TenantID,
+		PriceBookID: book.ID,
+		IsActive:    true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	applyPricingRuleRequest(rule, req)
+
+	if err := s.pricingRepo.CreatePricingRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create pricing rule: %w", err)
+	}
+
+	s.logPricingAction(ctx, "pricing_rule.create", "pricing_rule", rule.ID, nil, pricingRuleAuditValues(rule))
+
+	return rule, nil
+}
+
+// UpdatePricingRule updates a pricing rule
+func (s *PricingServiceImpl) UpdatePricingRule(ctx context.Context, ruleID uuid.UUID, req *PricingRuleRequest) (*domain.PricingRule, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	rule, err := s.pricingRepo.GetPricingRule(ctx, tenantID, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pricing rule: %w", err)
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("pricing rule not found")
+	}
+
+	if err := ValidatePricingRuleRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	oldValues := pricingRuleAuditValues(rule)
+	applyPricingRuleRequest(rule, req)
+	rule.UpdatedAt = time.Now()
+
+	if err := s.pricingRepo.UpdatePricingRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to update pricing rule: %w", err)
+	}
+
+	s.logPricingAction(ctx, "pricing_rule.update", "pricing_rule", rule.ID, oldValues, pricingRuleAuditValues(rule))
+
+	return rule, nil
+}
+
+// DeletePricingRule deletes a pricing rule
+func (s *PricingServiceImpl) DeletePricingRule(ctx context.Context, ruleID uuid.UUID) error {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("tenant ID not found in context")
+	}
+
+	rule, err := s.pricingRepo.GetPricingRule(ctx, tenantID, ruleID)
+	if err != nil {
+		return fmt.Errorf("failed to get pricing rule: %w", err)
+	}
+	if rule == nil {
+		return fmt.Errorf("pricing rule not found")
+	}
+
+	if err := s.pricingRepo.DeletePricingRule(ctx, tenantID, ruleID); err != nil {
+		return fmt.Errorf("failed to delete pricing rule: %w", err)
+	}
+
+	s.logPricingAction(ctx, "pricing_rule.delete", "pricing_rule", rule.ID, pricingRuleAuditValues(rule), nil)
+
+	return nil
+}
+
+// CalculatePrice prices a service against the price books in effect on the request
+// date. Catalog details are filled in from the service when a service ID is given.
+func (s *PricingServiceImpl) CalculatePrice(ctx context.Context, req *PriceRequest) (*PriceBreakdown, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	priced := *req
+	if priced.Date.IsZero() {
+		priced.Date = time.Now()
+	}
+
+	if priced.ServiceID != nil {
+		service, err := s.serviceRepo.GetByID(ctx, tenantID, *priced.ServiceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service: %w", err)
+		}
+		if service == nil {
+			return nil, fmt.Errorf("service not found")
+		}
+		fillPriceRequestFromService(&priced, service)
+	}
+
+	if priced.PropertyID != nil && priced.MeasuredAreas == nil && s.siteMapRepo != nil {
+		areas, err := s.siteMapRepo.GetMeasuredAreas(ctx, tenantID, *priced.PropertyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get measured areas: %w", err)
+		}
+		priced.MeasuredAreas = areas
+	}
+
+	books, err := s.pricingRepo.GetPriceBooksInEffect(ctx, tenantID, priced.Date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price books: %w", err)
+	}
+
+	return ApplyPricingRules(books, &priced), nil
+}
+
+// CalculateServicePrice prices a catalog service for a property
+func (s *PricingServiceImpl) CalculateServicePrice(ctx context.Context, serviceID uuid.UUID, quantity float64, propertyDetails *PropertyDetails) (*ServicePricing, error) {
+	req := &PriceRequest{
+		ServiceID: &serviceID,
+		Quantity:  quantity,
+	}
+	if propertyDetails != nil {
+		req.PropertySize = propertyDetails.PricingSize()
+		req.ZipCode = propertyDetails.ZipCode
+		req.PropertyID = propertyDetails.PropertyID
+		req.MeasuredAreas = propertyDetails.MeasuredAreas
+	}
+
+	breakdown, err := s.CalculatePrice(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return breakdown.ServicePricing(), nil
+}
+
+func (s *PricingServiceImpl) logPricingAction(ctx context.Context, action, resourceType string, resourceID uuid.UUID, oldValues, newValues map[string]interface{}) {
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   &resourceID,
+		OldValues:    oldValues,
+		NewValues:    newValues,
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+}
+
+// PricingSize is the area used for size-based pricing: the lot size when known,
+// otherwise the property's square footage
+func (d *PropertyDetails) PricingSize() float64 {
+	if d.LotSize != nil && *d.LotSize > 0 {
+		return *d.LotSize
+	}
+	if d.SquareFootage != nil {
+		return float64(*d.SquareFootage)
+	}
+	return 0
+}
+
+// ApplyPricingRules prices a service. For each rule type, the rules come from the
+// most recent price book in effect that has a rule of that type for the service, and
+// only the most specific scope applies. A size tier rate takes precedence over a unit
+// rate, which takes precedence over the catalog price. Rules tied to a measured area
+// use that area from the property's site map instead of the property size.
+// Adjustments then apply in order: minimum charge, frequency discount, zone
+// surcharge, same-day discount, distance surcharge and bundle discount, each on the
+// running total. A request without a date is priced for today.
+func ApplyPricingRules(books []*domain.PriceBook, req *PriceRequest) *PriceBreakdown {
+	rules := resolvePricingRules(books, req)
+
+	breakdown := &PriceBreakdown{
+		ServiceName: req.ServiceName,
+		Unit:        req.Unit,
+		Adjustments: []PriceAdjustment{},
+	}
+	if req.BasePrice != nil {
+		breakdown.UnitRate = *req.BasePrice
+	}
+
+	rate := matchSizeTier(rules[domain.PricingRuleSizeTier], req)
+	if rate == nil && len(rules[domain.PricingRuleUnitRate]) > 0 {
+		rate = &rules[domain.PricingRuleUnitRate][0]
+	}
+	if rate != nil {
+		breakdown.Unit = stringValue(rate.Unit)
+		breakdown.UnitRate = rate.Rate
+	}
+
+	breakdown.Quantity = pricingQuantity(breakdown.Unit, pricingArea(rate, req), req)
+	breakdown.BaseAmount = roundCents(breakdown.UnitRate * breakdown.Quantity)
+	total := breakdown.BaseAmount
+
+	adjust := func(rule domain.PricingRule, amount float64) {
+		amount = roundCents(amount)
+		if amount == 0 {
+			return
+		}
+		breakdown.Adjustments = append(breakdown.Adjustments, PriceAdjustment{
+			Type:        rule.RuleType,
+			Description: rule.Name,
+			Amount:      amount,
+		})
+		total += amount
+	}
+
+	// Minimum charge: the highest applicable minimum
+	var minimum *domain.PricingRule
+	for i, rule := range rules[domain.PricingRuleMinimumCharge] {
+		if minimum == nil || rule.Rate > minimum.Rate {
+			minimum = &rules[domain.PricingRuleMinimumCharge][i]
+		}
+	}
+	if minimum != nil && total < minimum.Rate {
+		adjust(*minimum, minimum.Rate-total)
+	}
+
+	frequency := normalizePricingFrequency(req.Frequency)
+	for _, rule := range rules[domain.PricingRuleFrequencyDiscount] {
+		if frequency != "" && normalizePricingFrequency(stringValue(rule.Frequency)) == frequency {
+			adjust(rule, -total*rule.Rate)
+			break
+		}
+	}
+
+	for _, rule := range rules[domain.PricingRuleZoneSurcharge] {
+		if req.ZipCode != "" && containsString(rule.ZipCodes, req.ZipCode) {
+			adjust(rule, total*rule.Rate)
+			break
+		}
+	}
+
+	if req.SameDay {
+		for _, rule := range rules[domain.PricingRuleSameDayDiscount] {
+			adjust(rule, -total*rule.Rate)
+			break
+		}
+	}
+
+	if rule := matchThreshold(rules[domain.PricingRuleDistanceSurcharge], req.Distance, 0, false); rule != nil {
+		adjust(*rule, total*rule.Rate)
+	}
+
+	if rule := matchThreshold(rules[domain.PricingRuleBundleDiscount], float64(req.ServiceCount), 2, true); rule != nil {
+		adjust(*rule, -total*rule.Rate)
+	}
+
+	breakdown.Total = roundCents(total)
+	return breakdown
+}
+
+// ValidatePricingRuleRequest checks a rule has the fields its type needs
+func ValidatePricingRuleRequest(req *PricingRuleRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if req.Rate < 0 {
+		return fmt.Errorf("rate cannot be negative")
+	}
+
+	switch req.RuleType {
+	case domain.PricingRuleUnitRate, domain.PricingRuleSizeTier:
+		if req.Unit == nil || !isPricingUnit(*req.Unit) {
+			return fmt.Errorf("unit must be one of sq_ft, hour, yard or visit")
+		}
+		if req.MeasuredArea != nil && !isPropertyFeatureType(*req.MeasuredArea) {
+			return fmt.Errorf("measured area must be one of %s", strings.Join(domain.PropertyFeatureTypes, ", "))
+		}
+		if req.MeasuredArea != nil && req.RuleType == domain.PricingRuleUnitRate && *req.Unit != domain.PricingUnitSqFt {
+			return fmt.Errorf("measured area only applies to square-foot rates")
+		}
+		if req.RuleType == domain.PricingRuleSizeTier {
+			if req.MinValue == nil && req.MaxValue == nil {
+				return fmt.Errorf("size tier needs a minimum or maximum size")
+			}
+			if req.MinValue != nil && req.MaxValue != nil && *req.MinValue >= *req.MaxValue {
+				return fmt.Errorf("size tier minimum must be less than its maximum")
+			}
+		}
+	case domain.PricingRuleMinimumCharge:
+	case domain.PricingRuleFrequencyDiscount:
+		if req.Frequency == nil || normalizePricingFrequency(*req.Frequency) == "" {
+			return fmt.Errorf("frequency is required for a frequency discount")
+		}
+	case domain.PricingRuleZoneSurcharge:
+		if len(req.ZipCodes) == 0 {
+			return fmt.Errorf("zip codes are required for a zone surcharge")
+		}
+	case domain.PricingRuleBundleDiscount, domain.PricingRuleSameDayDiscount, domain.PricingRuleDistanceSurcharge:
+	default:
+		return fmt.Errorf("unsupported rule type: %s", req.RuleType)
+	}
+
+	// Discounts and surcharges are fractions of the running total
+	switch req.RuleType {
+	case domain.PricingRuleFrequencyDiscount, domain.PricingRuleBundleDiscount, domain.PricingRuleSameDayDiscount:
+		if req.Rate > 1 {
+			return fmt.Errorf("discount rate must be a fraction between 0 and 1")
+		}
+	}
+
+	return nil
+}
+
+func validatePriceBookRequest(req *PriceBookRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if req.EffectiveFrom.IsZero() {
+		return fmt.Errorf("effective from date is required")
+	}
+	if req.EffectiveTo != nil && req.EffectiveTo.Before(req.EffectiveFrom) {
+		return fmt.Errorf("effective to date cannot be before the effective from date")
+	}
+	return nil
+}
+
+func applyPriceBookRequest(book *domain.PriceBook, req *PriceBookRequest) {
+	book.Name = strings.TrimSpace(req.Name)
+	book.Description = req.Description
+	book.EffectiveFrom = pricingDay(req.EffectiveFrom)
+	book.EffectiveTo = nil
+	if req.EffectiveTo != nil {
+		effectiveTo := pricingDay(*req.EffectiveTo)
+		book.EffectiveTo = &effectiveTo
+	}
+	if req.IsActive != nil {
+		book.IsActive = *req.IsActive
+	}
+}
+
+func applyPricingRuleRequest(rule *domain.PricingRule, req *PricingRuleRequest) {
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.RuleType = req.RuleType
+	rule.ServiceID = req.ServiceID
+	rule.ServiceKey = req.ServiceKey
+	rule.Category = req.Category
+	rule.Unit = req.Unit
+	rule.MeasuredArea = nil
+	if req.RuleType == domain.PricingRuleUnitRate || req.RuleType == domain.PricingRuleSizeTier {
+		rule.MeasuredArea = req.MeasuredArea
+	}
+	rule.Rate = req.Rate
+	rule.MinValue = req.MinValue
+	rule.MaxValue = req.MaxValue
+	rule.Frequency = req.Frequency
+	rule.ZipCodes = req.ZipCodes
+	if rule.ZipCodes == nil {
+		rule.ZipCodes = []string{}
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+}
+
+func pricingRuleAuditValues(rule *domain.PricingRule) map[string]interface{} {
+	return map[string]interface{}{
+		"price_book_id": rule.PriceBookID,
+		"name":          rule.Name,
+		"rule_type":     rule.RuleType,
+		"rate":          rule.Rate,
+		"is_active":     rule.IsActive,
+	}
+}
+
+func fillPriceRequestFromService(req *PriceRequest, service *domain.Service) {
+	if req.ServiceName == "" {
+		req.ServiceName = service.Name
+	}
+	if req.Category == "" {
+		req.Category = service.Category
+	}
+	if req.BasePrice == nil {
+		req.BasePrice = service.BasePrice
+	}
+	if req.Unit == "" {
+		req.Unit = stringValue(service.Unit)
+	}
+}
+
+// resolvePricingRules picks the rules that apply to a request, keyed by rule type
+func resolvePricingRules(books []*domain.PriceBook, req *PriceRequest) map[string][]domain.PricingRule {
+	date := req.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	day := pricingDay(date)
+
+	var inEffect []*domain.PriceBook
+	for _, book := range books {
+		if !book.IsActive || pricingDay(book.EffectiveFrom).After(day) {
+			continue
+		}
+		if book.EffectiveTo != nil && pricingDay(*book.EffectiveTo).Before(day) {
+			continue
+		}
+		inEffect = append(inEffect, book)
+	}
+
+	// The most recently started book wins, so seasonal books override the standing book
+	sort.SliceStable(inEffect, func(i, j int) bool {
+		return inEffect[i].EffectiveFrom.After(inEffect[j].EffectiveFrom)
+	})
+
+	resolved := make(map[string][]domain.PricingRule)
+	for _, book := range inEffect {
+		byType := make(map[string][]domain.PricingRule)
+		specificity := make(map[string]int)
+
+		for _, rule := range book.Rules {
+			if _, done := resolved[rule.RuleType]; done || !rule.IsActive {
+				continue
+			}
+			score, ok := pricingRuleScope(rule, req)
+			if !ok {
+				continue
+			}
+
+			best, seen := specificity[rule.RuleType]
+			switch {
+			case !seen || score > best:
+				specificity[rule.RuleType] = score
+				byType[rule.RuleType] = []domain.PricingRule{rule}
+			case score == best:
+				byType[rule.RuleType] = append(byType[rule.RuleType], rule)
+			}
+		}
+
+		for ruleType, rules := range byType {
+			resolved[ruleType] = rules
+		}
+	}
+
+	return resolved
+}
+
+// pricingRuleScope reports whether a rule applies to the request and how specific
+// its scope is: service 3, catalog key 2, category 1, everything 0
+func pricingRuleScope(rule domain.PricingRule, req *PriceRequest) (int, bool) {
+	score := 0
+	if rule.Category != nil {
+		if !strings.EqualFold(*rule.Category, req.Category) {
+			return 0, false
+		}
+		score = 1
+	}
+	if rule.ServiceKey != nil {
+		if !strings.EqualFold(*rule.ServiceKey, req.ServiceKey) {
+			return 0, false
+		}
+		score = 2
+	}
+	if rule.ServiceID != nil {
+		if req.ServiceID == nil || *rule.ServiceID != *req.ServiceID {
+			return 0, false
+		}
+		score = 3
+	}
+	return score, true
+}
+
+// matchSizeTier finds the tier whose [min, max) range contains the size it prices
+func matchSizeTier(tiers []domain.PricingRule, req *PriceRequest) *domain.PricingRule {
+	for i, tier := range tiers {
+		size := pricingArea(&tier, req)
+		if tier.MinValue != nil && size < *tier.MinValue {
+			continue
+		}
+		if tier.MaxValue != nil && size >= *tier.MaxValue {
+			continue
+		}
+		return &tiers[i]
+	}
+	return nil
+}
+
+// matchThreshold finds the rule with the highest threshold the value reaches. Rules
+// without a threshold use defaultMin. Inclusive thresholds match at the threshold itself.
+func matchThreshold(rules []domain.PricingRule, value, defaultMin float64, inclusive bool) *domain.PricingRule {
+	var match *domain.PricingRule
+	matchMin := 0.0
+
+	for i, rule := range rules {
+		min := defaultMin
+		if rule.MinValue != nil {
+			min = *rule.MinValue
+		}
+		if value < min || (!inclusive && value == min) {
+			continue
+		}
+		if match == nil || min > matchMin {
+			match = &rules[i]
+			matchMin = min
+		}
+	}
+	return match
+}
+
+// pricingArea is the square footage a rule prices: its measured area from the site
+// map, or the property size when the rule has none or the area was never measured
+func pricingArea(rule *domain.PricingRule, req *PriceRequest) float64 {
+	if rule != nil && rule.MeasuredArea != nil {
+		if area := req.MeasuredAreas[*rule.MeasuredArea]; area > 0 {
+			return area
+		}
+	}
+	return req.PropertySize
+}
+
+// pricingQuantity is the number of units being priced. Square-foot rates price the
+// area; other units use the requested quantity, defaulting to one.
+func pricingQuantity(unit string, area float64, req *PriceRequest) float64 {
+	if unit == domain.PricingUnitSqFt && area > 0 {
+		return area
+	}
+	if req.Quantity > 0 {
+		return req.Quantity
+	}
+	return 1
+}
+
+func normalizePricingFrequency(frequency string) string {
+	frequency = strings.ToLower(strings.TrimSpace(frequency))
+	frequency = strings.NewReplacer("-", "", "_", "", " ", "").Replace(frequency)
+	switch frequency {
+	case "onetime", "once":
+		return ""
+	}
+	return frequency
+}
+
+func isPricingUnit(unit string) bool {
+	switch unit {
+	case domain.PricingUnitSqFt, domain.PricingUnitHour, domain.PricingUnitYard, domain.PricingUnitVisit:
+		return true
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+func pricingDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
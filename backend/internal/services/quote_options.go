@@ -0,0 +1,525 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// QuotePricing breaks a quote down into its base lines, option groups and add-ons
+type QuotePricing struct {
+	QuoteID      uuid.UUID                 `json:"quote_id"`
+	TaxRate      float64                   `json:"tax_rate"`
+	BaseLines    []domain.QuoteVersionLine `json:"base_lines"`
+	BaseSubtotal float64                   `json:"base_subtotal"`
+	Groups       []QuoteOptionGroupPricing `json:"groups"`
+	AddOns       []QuoteAddOnPricing       `json:"add_ons"`
+	Default      *domain.QuoteSelection    `json:"default_selection"`
+}
+
+// QuoteOptionGroupPricing lists the options the customer chooses between in one group
+type QuoteOptionGroupPricing struct {
+	GroupName string               `json:"group_name"`
+	Options   []QuoteOptionPricing `json:"options"`
+}
+
+// QuoteOptionPricing prices a single option. The quote totals assume this option is
+// chosen, the other groups are at their defaults and no add-ons are selected.
+type QuoteOptionPricing struct {
+	Option         domain.QuoteOption        `json:"option"`
+	Lines          []domain.QuoteVersionLine `json:"lines"`
+	Subtotal       float64                   `json:"subtotal"`
+	QuoteSubtotal  float64                   `json:"quote_subtotal"`
+	QuoteTaxAmount float64                   `json:"quote_tax_amount"`
+	QuoteTotal     float64                   `json:"quote_total"`
+}
+
+// QuoteAddOnPricing is an optional line item the customer can toggle
+type QuoteAddOnPricing struct {
+	Line     domain.QuoteVersionLine `json:"line"`
+	OptionID *uuid.UUID              `json:"option_id,omitempty"` // only available with this option
+	Price    float64                 `json:"price"`
+}
+
+// GetQuotePricing prices the quote's options and add-ons
+func (s *QuoteServiceImpl) GetQuotePricing(ctx context.Context, quoteID uuid.UUID) (*QuotePricing, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	quote, err := s.quoteRepo.GetByID(ctx, tenantID, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+	if quote == nil {
+		return nil, fmt.Errorf("quote not found")
+	}
+
+	snapshot, err := s.buildWorkingSnapshot(ctx, tenantID, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildQuotePricing(snapshot), nil
+}
+
+// buildQuoteLines validates the requested line items and options, builds them for the
+// quote and sets the quote's totals to the default selection
+func buildQuoteLines(quote *domain.Quote, lineReqs []QuoteServiceRequest, optionReqs []QuoteOptionRequest) ([]*domain.QuoteOption, []*domain.QuoteService, error) {
+	if err := validateQuoteLineRequests(lineReqs, optionReqs); err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	newLine := func(req QuoteServiceRequest, optionID *uuid.UUID) *domain.QuoteService {
+		return &domain.QuoteService{
+			ID:          uuid.New(),
+			QuoteID:     quote.ID,
+			ServiceID:   req.ServiceID,
+			OptionID:    optionID,
+			IsOptional:  req.IsOptional,
+			Quantity:    req.Quantity,
+			UnitPrice:   req.UnitPrice,
+			TotalPrice:  req.Quantity * req.UnitPrice,
+			Description: req.Description,
+			CreatedAt:   now,
+		}
+	}
+
+	var lines []*domain.QuoteService
+	for _, req := range lineReqs {
+		lines = append(lines, newLine(req, nil))
+	}
+
+	options := make([]*domain.QuoteOption, 0, len(optionReqs))
+	for i, req := range optionReqs {
+		groupName := strings.TrimSpace(req.GroupName)
+		if groupName == "" {
+			groupName = domain.QuoteOptionGroupDefault
+		}
+
+		option := &domain.QuoteOption{
+			ID:          uuid.New(),
+			TenantID:    quote.TenantID,
+			QuoteID:     quote.ID,
+			GroupName:   groupName,
+			Name:        strings.TrimSpace(req.Name),
+			Description: req.Description,
+			SortOrder:   i,
+			IsDefault:   req.IsDefault,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		options = append(options, option)
+
+		for _, lineReq := range req.Services {
+			lines = append(lines, newLine(lineReq, &option.ID))
+		}
+	}
+
+	version := BuildQuoteVersion(quote, lines, options, nil)
+	selection, _, err := ResolveQuoteSelection(version, DefaultQuoteSelection(version))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	quote.Subtotal = selection.Subtotal
+	quote.TaxAmount = selection.TaxAmount
+	quote.TotalAmount = selection.TotalAmount
+
+	return options, lines, nil
+}
+
+// priceQuoteLines sets the unit price of lines flagged to use pricing rules from the
+// tenant's price books, using the property's size, site map areas and zip code
+func (s *QuoteServiceImpl) priceQuoteLines(ctx context.Context, property *domain.EnhancedProperty, frequency string, lineReqs []QuoteServiceRequest, optionReqs []QuoteOptionRequest) error {
+	if !quoteRequestUsesPricingRules(lineReqs, optionReqs) {
+		return nil
+	}
+	if s.pricingService == nil {
+		return fmt.Errorf("pricing rules are not available")
+	}
+
+	details := &PropertyDetails{
+		PropertyID:    &property.ID,
+		LotSize:       property.LotSize,
+		SquareFootage: property.SquareFootage,
+		PropertyType:  property.PropertyType,
+		ZipCode:       property.ZipCode,
+	}
+
+	serviceCount := 0
+	forEachQuoteLine(lineReqs, optionReqs, func(*QuoteServiceRequest) { serviceCount++ })
+
+	var err error
+	forEachQuoteLine(lineReqs, optionReqs, func(req *QuoteServiceRequest) {
+		if err != nil || !req.UsePricingRules {
+			return
+		}
+
+		serviceID := req.ServiceID
+		var breakdown *PriceBreakdown
+		breakdown, err = s.pricingService.CalculatePrice(ctx, &PriceRequest{
+			ServiceID:    &serviceID,
+			Quantity:     req.Quantity,
+			PropertySize: details.PricingSize(),
+			PropertyID:   details.PropertyID,
+			Frequency:    frequency,
+			ZipCode:      details.ZipCode,
+			ServiceCount: serviceCount,
+		})
+		if err != nil {
+			err = fmt.Errorf("failed to price service %s: %w", serviceID, err)
+			return
+		}
+
+		// Quote lines price per requested unit, so spread the rule total over the quantity
+		if req.Quantity > 0 {
+			req.UnitPrice = roundCents(breakdown.Total / req.Quantity)
+		}
+	})
+
+	return err
+}
+
+// quoteRequestUsesPricingRules reports whether any requested line is priced by rules
+func quoteRequestUsesPricingRules(lineReqs []QuoteServiceRequest, optionReqs []QuoteOptionRequest) bool {
+	uses := false
+	forEachQuoteLine(lineReqs, optionReqs, func(req *QuoteServiceRequest) {
+		uses = uses || req.UsePricingRules
+	})
+	return uses
+}
+
+// forEachQuoteLine calls fn with each requested line, in place
+func forEachQuoteLine(lineReqs []QuoteServiceRequest, optionReqs []QuoteOptionRequest, fn func(*QuoteServiceRequest)) {
+	for i := range lineReqs {
+		fn(&lineReqs[i])
+	}
+	for i := range optionReqs {
+		for j := range optionReqs[i].Services {
+			fn(&optionReqs[i].Services[j])
+		}
+	}
+}
+
+// createQuoteLines saves options and line items built by buildQuoteLines
+func (s *QuoteServiceImpl) createQuoteLines(ctx context.Context, options []*domain.QuoteOption, lines []*domain.QuoteService) error {
+	for _, option := range options {
+		if err := s.quoteRepo.CreateQuoteOption(ctx, option); err != nil {
+			return fmt.Errorf("failed to create quote option: %w", err)
+		}
+	}
+
+	for _, line := range lines {
+		if err := s.quoteRepo.CreateQuoteService(ctx, line); err != nil {
+			s.logger.Printf("Failed to create service %s on quote %s: %v", line.ServiceID, line.QuoteID, err)
+		}
+	}
+
+	return nil
+}
+
+func validateQuoteLineRequests(lineReqs []QuoteServiceRequest, optionReqs []QuoteOptionRequest) error {
+	validateLine := func(label string, req QuoteServiceRequest) error {
+		if req.Quantity <= 0 {
+			return fmt.Errorf("%s: quantity must be greater than 0", label)
+		}
+		if req.UnitPrice < 0 {
+			return fmt.Errorf("%s: unit price cannot be negative", label)
+		}
+		return nil
+	}
+
+	lineCount := len(lineReqs)
+	for i, req := range lineReqs {
+		if err := validateLine(fmt.Sprintf("service %d", i+1), req); err != nil {
+			return err
+		}
+	}
+
+	defaults := make(map[string]int)
+	for i, option := range optionReqs {
+		if strings.TrimSpace(option.Name) == "" {
+			return fmt.Errorf("option %d: name is required", i+1)
+		}
+		if option.IsDefault {
+			groupName := strings.TrimSpace(option.GroupName)
+			if groupName == "" {
+				groupName = domain.QuoteOptionGroupDefault
+			}
+			defaults[groupName]++
+			if defaults[groupName] > 1 {
+				return fmt.Errorf("option group %s has more than one default option", groupName)
+			}
+		}
+
+		lineCount += len(option.Services)
+		for j, req := range option.Services {
+			if err := validateLine(fmt.Sprintf("option %s service %d", option.Name, j+1), req); err != nil {
+				return err
+			}
+		}
+	}
+
+	if lineCount == 0 {
+		return fmt.Errorf("at least one service is required")
+	}
+	return nil
+}
+
+// quoteRequestServiceIDs returns the distinct services referenced by a quote request
+func quoteRequestServiceIDs(lineReqs []QuoteServiceRequest, optionReqs []QuoteOptionRequest) []uuid.UUID {
+	var serviceIDs []uuid.UUID
+	add := func(reqs []QuoteServiceRequest) {
+		for _, req := range reqs {
+			if !containsUUID(serviceIDs, req.ServiceID) {
+				serviceIDs = append(serviceIDs, req.ServiceID)
+			}
+		}
+	}
+
+	add(lineReqs)
+	for _, option := range optionReqs {
+		add(option.Services)
+	}
+	return serviceIDs
+}
+
+// QuoteOptionGroups returns a version's options grouped and ordered for display
+func QuoteOptionGroups(version *domain.QuoteVersion) ([]string, map[string][]domain.QuoteOption) {
+	options := make([]domain.QuoteOption, len(version.Options))
+	copy(options, version.Options)
+	sort.SliceStable(options, func(i, j int) bool {
+		return options[i].SortOrder < options[j].SortOrder
+	})
+
+	var groupNames []string
+	groups := make(map[string][]domain.QuoteOption)
+	for _, option := range options {
+		if _, ok := groups[option.GroupName]; !ok {
+			groupNames = append(groupNames, option.GroupName)
+		}
+		groups[option.GroupName] = append(groups[option.GroupName], option)
+	}
+
+	return groupNames, groups
+}
+
+// DefaultQuoteSelection picks each group's default option, or its first option if
+// none is marked default, with no add-ons
+func DefaultQuoteSelection(version *domain.QuoteVersion) *QuoteSelectionRequest {
+	selection := &QuoteSelectionRequest{
+		OptionIDs:    []uuid.UUID{},
+		AddOnLineIDs: []uuid.UUID{},
+	}
+
+	groupNames, groups := QuoteOptionGroups(version)
+	for _, groupName := range groupNames {
+		chosen := groups[groupName][0]
+		for _, option := range groups[groupName] {
+			if option.IsDefault {
+				chosen = option
+				break
+			}
+		}
+		selection.OptionIDs = append(selection.OptionIDs, chosen.ID)
+	}
+
+	return selection
+}
+
+// ResolveQuoteSelection validates a selection against a quote version and returns the
+// selected line items with their totals. Exactly one option must be chosen per group,
+// and an add-on that belongs to an option is only available with that option.
+func ResolveQuoteSelection(version *domain.QuoteVersion, req *QuoteSelectionRequest) (*domain.QuoteSelection, []domain.QuoteVersionLine, error) {
+	optionsByID := make(map[uuid.UUID]domain.QuoteOption, len(version.Options))
+	for _, option := range version.Options {
+		optionsByID[option.ID] = option
+	}
+
+	selectedOptions := make(map[uuid.UUID]bool)
+	selectedGroups := make(map[string]bool)
+	for _, optionID := range req.OptionIDs {
+		option, ok := optionsByID[optionID]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown quote option %s", optionID)
+		}
+		if selectedGroups[option.GroupName] {
+			return nil, nil, fmt.Errorf("more than one option selected for %s", option.GroupName)
+		}
+		selectedGroups[option.GroupName] = true
+		selectedOptions[optionID] = true
+	}
+
+	groupNames, _ := QuoteOptionGroups(version)
+	for _, groupName := range groupNames {
+		if !selectedGroups[groupName] {
+			return nil, nil, fmt.Errorf("no option selected for %s", groupName)
+		}
+	}
+
+	linesByID := make(map[uuid.UUID]domain.QuoteVersionLine, len(version.LineItems))
+	for _, line := range version.LineItems {
+		linesByID[line.LineID] = line
+	}
+
+	selectedAddOns := make(map[uuid.UUID]bool)
+	for _, lineID := range req.AddOnLineIDs {
+		line, ok := linesByID[lineID]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown add-on %s", lineID)
+		}
+		if !line.IsOptional {
+			return nil, nil, fmt.Errorf("line %s is not an optional add-on", lineID)
+		}
+		if line.OptionID != nil && !selectedOptions[*line.OptionID] {
+			return nil, nil, fmt.Errorf("add-on %s requires option %s", lineID, optionsByID[*line.OptionID].Name)
+		}
+		selectedAddOns[lineID] = true
+	}
+
+	var lines []domain.QuoteVersionLine
+	subtotal := 0.0
+	for _, line := range version.LineItems {
+		if line.OptionID != nil && !selectedOptions[*line.OptionID] {
+			continue
+		}
+		if line.IsOptional && !selectedAddOns[line.LineID] {
+			continue
+		}
+		lines = append(lines, line)
+		subtotal += line.TotalPrice
+	}
+
+	subtotal = roundCents(subtotal)
+	taxAmount := roundCents(subtotal * version.TaxRate)
+
+	selection := &domain.QuoteSelection{
+		VersionID:    version.ID,
+		OptionIDs:    append([]uuid.UUID{}, req.OptionIDs...),
+		AddOnLineIDs: append([]uuid.UUID{}, req.AddOnLineIDs...),
+		Subtotal:     subtotal,
+		TaxAmount:    taxAmount,
+		TotalAmount:  roundCents(subtotal + taxAmount),
+		SelectedAt:   time.Now(),
+	}
+
+	return selection, lines, nil
+}
+
+// BuildQuotePricing prices every option and add-on in a quote version
+func BuildQuotePricing(version *domain.QuoteVersion) *QuotePricing {
+	pricing := &QuotePricing{
+		QuoteID:   version.QuoteID,
+		TaxRate:   version.TaxRate,
+		BaseLines: []domain.QuoteVersionLine{},
+		Groups:    []QuoteOptionGroupPricing{},
+		AddOns:    []QuoteAddOnPricing{},
+	}
+
+	linesByOption := make(map[uuid.UUID][]domain.QuoteVersionLine)
+	for _, line := range version.LineItems {
+		switch {
+		case line.IsOptional:
+			pricing.AddOns = append(pricing.AddOns, QuoteAddOnPricing{
+				Line:     line,
+				OptionID: line.OptionID,
+				Price:    roundCents(line.TotalPrice),
+			})
+		case line.OptionID != nil:
+			linesByOption[*line.OptionID] = append(linesByOption[*line.OptionID], line)
+		default:
+			pricing.BaseLines = append(pricing.BaseLines, line)
+			pricing.BaseSubtotal += line.TotalPrice
+		}
+	}
+	pricing.BaseSubtotal = roundCents(pricing.BaseSubtotal)
+
+	defaults := DefaultQuoteSelection(version)
+	pricing.Default, _, _ = ResolveQuoteSelection(version, defaults)
+
+	groupNames, groups := QuoteOptionGroups(version)
+	for _, groupName := range groupNames {
+		group := QuoteOptionGroupPricing{GroupName: groupName}
+
+		for _, option := range groups[groupName] {
+			optionPricing := QuoteOptionPricing{
+				Option: option,
+				Lines:  linesByOption[option.ID],
+			}
+			if optionPricing.Lines == nil {
+				optionPricing.Lines = []domain.QuoteVersionLine{}
+			}
+			for _, line := range optionPricing.Lines {
+				optionPricing.Subtotal += line.TotalPrice
+			}
+			optionPricing.Subtotal = roundCents(optionPricing.Subtotal)
+
+			// Swap this option in for its group's default
+			optionIDs := make([]uuid.UUID, 0, len(defaults.OptionIDs))
+			for _, optionID := range defaults.OptionIDs {
+				if optionID != option.ID && !containsUUID(optionIDsOf(groups[groupName]), optionID) {
+					optionIDs = append(optionIDs, optionID)
+				}
+			}
+			optionIDs = append(optionIDs, option.ID)
+
+			if selection, _, err := ResolveQuoteSelection(version, &QuoteSelectionRequest{OptionIDs: optionIDs}); err == nil {
+				optionPricing.QuoteSubtotal = selection.Subtotal
+				optionPricing.QuoteTaxAmount = selection.TaxAmount
+				optionPricing.QuoteTotal = selection.TotalAmount
+			}
+
+			group.Options = append(group.Options, optionPricing)
+		}
+
+		pricing.Groups = append(pricing.Groups, group)
+	}
+
+	return pricing
+}
+
+// DescribeQuoteSelection names the chosen options and add-ons, e.g.
+// "Full-service maintenance + Aeration"
+func DescribeQuoteSelection(version *domain.QuoteVersion, selection *domain.QuoteSelection) string {
+	var names []string
+
+	groupNames, groups := QuoteOptionGroups(version)
+	for _, groupName := range groupNames {
+		for _, option := range groups[groupName] {
+			if containsUUID(selection.OptionIDs, option.ID) {
+				names = append(names, option.Name)
+			}
+		}
+	}
+
+	for _, line := range version.LineItems {
+		if line.IsOptional && containsUUID(selection.AddOnLineIDs, line.LineID) {
+			name := line.ServiceName
+			if name == "" {
+				name = stringValue(line.Description)
+			}
+			if name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return strings.Join(names, " + ")
+}
+
+func optionIDsOf(options []domain.QuoteOption) []uuid.UUID {
+	ids := make([]uuid.UUID, len(options))
+	for i, option := range options {
+		ids[i] = option.ID
+	}
+	return ids
+}
@@ -20,6 +20,8 @@ type QuoteServiceImpl struct {
 	customerRepo        CustomerRepository
 	propertyRepo        PropertyRepositoryExtended
 	serviceRepo         ServiceRepository
+	jobRepo             JobRepositoryComplete
+	pricingService      PricingService
 	auditService        AuditService
 	communicationService CommunicationService
 	llmService          LLMService
@@ -35,6 +37,7 @@ type QuoteRepositoryFull interface {
 	Update(ctx context.Context, quote *domain.Quote) error
 	Delete(ctx context.Context, tenantID, quoteID uuid.UUID) error
 	List(ctx context.Context, tenantID uuid.UUID, filter *QuoteFilter) ([]*domain.Quote, int64, error)
+	ApproveWithSignature(ctx context.Context, quote *domain.Quote, signature *domain.QuoteSignature) error
 	
 	// Filtering operations
 	GetByCustomerID(ctx context.Context, tenantID, customerID uuid.UUID, filter *QuoteFilter) ([]*domain.Quote, int64, error)
@@ -46,9 +49,22 @@ type QuoteRepositoryFull interface {
 	UpdateQuoteService(ctx context.Context, quoteService *domain.QuoteService) error
 	DeleteQuoteService(ctx context.Context, quoteServiceID uuid.UUID) error
 	GetQuoteServices(ctx context.Context, quoteID uuid.UUID) ([]*domain.QuoteService, error)
+
+	// Quote options
+	CreateQuoteOption(ctx context.Context, option *domain.QuoteOption) error
+	GetQuoteOptions(ctx context.Context, quoteID uuid.UUID) ([]*domain.QuoteOption, error)
+	DeleteQuoteOptions(ctx context.Context, quoteID uuid.UUID) error
 	
 	// Quote numbering
 	GetNextQuoteNumber(ctx context.Context, tenantID uuid.UUID) (string, error)
+
+	// Quote versions
+	CreateQuoteVersion(ctx context.Context, version *domain.QuoteVersion) error
+	MarkQuoteVersionSent(ctx context.Context, tenantID, versionID uuid.UUID, sentTo string, sentAt time.Time) error
+	GetQuoteVersion(ctx context.Context, tenantID, quoteID uuid.UUID, versionNumber int) (*domain.QuoteVersion, error)
+	GetQuoteVersionByID(ctx context.Context, tenantID, versionID uuid.UUID) (*domain.QuoteVersion, error)
+	GetLatestQuoteVersion(ctx context.Context, tenantID, quoteID uuid.UUID) (*domain.QuoteVersion, error)
+	ListQuoteVersions(ctx context.Context, tenantID, quoteID uuid.UUID) ([]*domain.QuoteVersion, error)
 }
 
 // NewQuoteService creates a new quote service instance
@@ -57,6 +73,8 @@ func NewQuoteService(
 	customerRepo CustomerRepository,
 	propertyRepo PropertyRepositoryExtended,
 	serviceRepo ServiceRepository,
+	jobRepo JobRepositoryComplete,
+	pricingService PricingService,
 	auditService AuditService,
 	communicationService CommunicationService,
 	llmService LLMService,
@@ -68,6 +86,8 @@ func NewQuoteService(
 		customerRepo:         customerRepo,
 		propertyRepo:         propertyRepo,
 		serviceRepo:          serviceRepo,
+		jobRepo:              jobRepo,
+		pricingService:       pricingService,
 		auditService:         auditService,
 		communicationService: communicationService,
 		llmService:           llmService,
@@ -111,15 +131,7 @@ func (s *QuoteServiceImpl) CreateQuote(ctx context.Context, req *QuoteCreateRequ
 	}
 
 	// Verify services exist
-	if len(req.Services) == 0 {
-		return nil, fmt.Errorf("at least one service is required")
-	}
-
-	serviceIDs := make([]uuid.UUID, len(req.Services))
-	for i, svc := range req.Services {
-		serviceIDs[i] = svc.ServiceID
-	}
-
+	serviceIDs := quoteRequestServiceIDs(req.Services, req.Options)
 	services, err := s.serviceRepo.GetByIDs(ctx, tenantID, serviceIDs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to verify services: %w", err)
@@ -135,15 +147,7 @@ func (s *QuoteServiceImpl) CreateQuote(ctx context.Context, req *QuoteCreateRequ
 		quoteNumber = fmt.Sprintf("Q-%d", time.Now().Unix())
 	}
 
-	// Calculate totals
-	subtotal := 0.0
-	for _, svc := range req.Services {
-		subtotal += svc.Quantity * svc.UnitPrice
-	}
-
 	taxRate := 0.08 // Default 8% tax rate - this could be configurable
-	taxAmount := subtotal * taxRate
-	totalAmount := subtotal + taxAmount
 
 	// Set valid until date (30 days from now if not specified)
 	validUntil := req.ValidUntil
@@ -161,10 +165,7 @@ func (s *QuoteServiceImpl) CreateQuote(ctx context.Context, req *QuoteCreateRequ
 		QuoteNumber:        quoteNumber,
 		Title:              req.Title,
 		Description:        req.Description,
-		Subtotal:           subtotal,
 		TaxRate:            taxRate,
-		TaxAmount:          taxAmount,
-		TotalAmount:        totalAmount,
 		Status:             "draft",
 		ValidUntil:         validUntil,
 		TermsAndConditions: req.TermsAndConditions,
@@ -174,28 +175,26 @@ func (s *QuoteServiceImpl) CreateQuote(ctx context.Context, req *QuoteCreateRequ
 		UpdatedAt:          time.Now(),
 	}
 
+	// Price rule-priced lines from the tenant's price books
+	if err := s.priceQuoteLines(ctx, property, req.Frequency, req.Services, req.Options); err != nil {
+		return nil, err
+	}
+
+	// Build line items and options; totals reflect the default option in each group
+	options, lines, err := buildQuoteLines(quote, req.Services, req.Options)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
 	// Save quote to database
 	if err := s.quoteRepo.Create(ctx, quote); err != nil {
 		s.logger.Printf("Failed to create quote", "error", err, "tenant_id", tenantID)
 		return nil, fmt.Errorf("failed to create quote: %w", err)
 	}
 
-	// Create quote services
-	for _, svcReq := range req.Services {
-		quoteService := &domain.QuoteService{
-			ID:          uuid.New(),
-			QuoteID:     quote.ID,
-			ServiceID:   svcReq.ServiceID,
-			Quantity:    svcReq.Quantity,
-			UnitPrice:   svcReq.UnitPrice,
-			TotalPrice:  svcReq.Quantity * svcReq.UnitPrice,
-			Description: svcReq.Description,
-			CreatedAt:   time.Now(),
-		}
-
-		if err := s.quoteRepo.CreateQuoteService(ctx, quoteService); err != nil {
-			s.logger.Printf("Failed to create quote service", "error", err, "quote_id", quote.ID, "service_id", svcReq.ServiceID)
-		}
+	// Create quote options and services
+	if err := s.createQuoteLines(ctx, options, lines); err != nil {
+		return nil, err
 	}
 
 	// Log audit event
@@ -266,6 +265,7 @@ func (s *QuoteServiceImpl) UpdateQuote(ctx context.Context, quoteID uuid.UUID, r
 		"title":        quote.Title,
 		"total_amount": quote.TotalAmount,
 		"valid_until":  quote.ValidUntil,
+		"status":       quote.Status,
 	}
 
 	// Update fields
@@ -285,14 +285,10 @@ func (s *QuoteServiceImpl) UpdateQuote(ctx context.Context, quoteID uuid.UUID, r
 		quote.Notes = req.Notes
 	}
 
-	// Update services if provided
-	if len(req.Services) > 0 {
+	// Update services and options if provided
+	if len(req.Services) > 0 || len(req.Options) > 0 {
 		// Verify services exist
-		serviceIDs := make([]uuid.UUID, len(req.Services))
-		for i, svc := range req.Services {
-			serviceIDs[i] = svc.ServiceID
-		}
-
+		serviceIDs := quoteRequestServiceIDs(req.Services, req.Options)
 		services, err := s.serviceRepo.GetByIDs(ctx, tenantID, serviceIDs)
 		if err != nil {
 			return nil, fmt.Errorf("failed to verify services: %w", err)
@@ -301,6 +297,24 @@ func (s *QuoteServiceImpl) UpdateQuote(ctx context.Context, quoteID uuid.UUID, r
 			return nil, fmt.Errorf("one or more services not found")
 		}
 
+		if quoteRequestUsesPricingRules(req.Services, req.Options) {
+			property, err := s.propertyRepo.GetByID(ctx, tenantID, quote.PropertyID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get property: %w", err)
+			}
+			if property == nil {
+				return nil, fmt.Errorf("property not found")
+			}
+			if err := s.priceQuoteLines(ctx, property, req.Frequency, req.Services, req.Options); err != nil {
+				return nil, err
+			}
+		}
+
+		options, lines, err := buildQuoteLines(quote, req.Services, req.Options)
+		if err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+
 		// Delete existing quote services
 		existingServices, err := s.quoteRepo.GetQuoteServices(ctx, quoteID)
 		if err != nil {
@@ -313,32 +327,20 @@ func (s *QuoteServiceImpl) UpdateQuote(ctx context.Context, quoteID uuid.UUID, r
 			}
 		}
 
-		// Create new quote services and recalculate totals
-		subtotal := 0.0
-		for _, svcReq := range req.Services {
-			totalPrice := svcReq.Quantity * svcReq.UnitPrice
-			subtotal += totalPrice
-
-			quoteService := &domain.QuoteService{
-				ID:          uuid.New(),
-				QuoteID:     quote.ID,
-				ServiceID:   svcReq.ServiceID,
-				Quantity:    svcReq.Quantity,
-				UnitPrice:   svcReq.UnitPrice,
-				TotalPrice:  totalPrice,
-				Description: svcReq.Description,
-				CreatedAt:   time.Now(),
-			}
+		if err := s.quoteRepo.DeleteQuoteOptions(ctx, quoteID); err != nil {
+			return nil, fmt.Errorf("failed to delete quote options: %w", err)
+		}
 
-			if err := s.quoteRepo.CreateQuoteService(ctx, quoteService); err != nil {
-				s.logger.Printf("Failed to create updated quote service", "error", err, "quote_id", quote.ID, "service_id", svcReq.ServiceID)
-			}
+		// Create new quote options and services; totals were recalculated when the lines were built
+		if err := s.createQuoteLines(ctx, options, lines); err != nil {
+			return nil, err
 		}
+	}
 
-		// Update quote totals
-		quote.Subtotal = subtotal
-		quote.TaxAmount = subtotal * quote.TaxRate
-		quote.TotalAmount = quote.Subtotal + quote.TaxAmount
+	// Sent revisions are immutable; a pending quote being revised returns to draft
+	// until the new revision is sent
+	if quote.Status == "pending" {
+		quote.Status = "draft"
 	}
 
 	quote.UpdatedAt = time.Now()
@@ -354,6 +356,7 @@ func (s *QuoteServiceImpl) UpdateQuote(ctx context.Context, quoteID uuid.UUID, r
 		"title":        quote.Title,
 		"total_amount": quote.TotalAmount,
 		"valid_until":  quote.ValidUntil,
+		"status":       quote.Status,
 	}
 
 	userID := GetUserIDFromContext(ctx)
@@ -461,8 +464,24 @@ func (s *QuoteServiceImpl) ListQuotes(ctx context.Context, filter *QuoteFilter)
 	}, nil
 }
 
-// ApproveQuote approves a quote
+// ApproveQuote approves a quote with the default option in each group
 func (s *QuoteServiceImpl) ApproveQuote(ctx context.Context, quoteID uuid.UUID) error {
+	return s.ApproveQuoteWithSelection(ctx, quoteID, nil)
+}
+
+// ApproveQuoteWithSelection approves a quote with the customer's chosen options and
+// add-ons. A nil selection approves the default option in each group.
+func (s *QuoteServiceImpl) ApproveQuoteWithSelection(ctx context.Context, quoteID uuid.UUID, req *QuoteSelectionRequest) error {
+	return s.approveQuote(ctx, quoteID, req, nil)
+}
+
+// ApproveQuoteWithSignature approves a quote and records the customer's signature in the
+// same write. The signature is pinned to the approved revision and selection total.
+func (s *QuoteServiceImpl) ApproveQuoteWithSignature(ctx context.Context, quoteID uuid.UUID, req *QuoteSelectionRequest, signature *domain.QuoteSignature) error {
+	return s.approveQuote(ctx, quoteID, req, signature)
+}
+
+func (s *QuoteServiceImpl) approveQuote(ctx context.Context, quoteID uuid.UUID, req *QuoteSelectionRequest, signature *domain.QuoteSignature) error {
 	tenantID, ok := GetTenantIDFromContext(ctx)
 	if !ok {
 		return fmt.Errorf("tenant ID not found in context")
@@ -482,13 +501,37 @@ func (s *QuoteServiceImpl) ApproveQuote(ctx context.Context, quoteID uuid.UUID)
 		return fmt.Errorf("quote must be in pending or draft status to approve")
 	}
 
+	// Pin the approval to the exact revision being approved
+	version, err := s.snapshotQuote(ctx, tenantID, quote)
+	if err != nil {
+		return fmt.Errorf("failed to record quote version: %w", err)
+	}
+	revision := FormatQuoteRevision(quote.QuoteNumber, version.VersionNumber)
+
+	if req == nil {
+		req = DefaultQuoteSelection(version)
+	}
+	selection, _, err := ResolveQuoteSelection(version, req)
+	if err != nil {
+		return fmt.Errorf("invalid selection: %w", err)
+	}
+
 	// Update quote status
+	quote.ApprovedVersionID = &version.ID
+	quote.ApprovedSelection = selection
 	quote.Status = "approved"
 	quote.ApprovedAt = timePtr(time.Now())
 	quote.ApprovedBy = GetUserIDFromContext(ctx)
 	quote.UpdatedAt = time.Now()
 
-	if err := s.quoteRepo.Update(ctx, quote); err != nil {
+	if signature != nil {
+		signature.QuoteVersionID = &version.ID
+		signature.TotalAmount = selection.TotalAmount
+		err = s.quoteRepo.ApproveWithSignature(ctx, quote, signature)
+	} else {
+		err = s.quoteRepo.Update(ctx, quote)
+	}
+	if err != nil {
 		s.logger.Printf("Failed to approve quote", "error", err, "quote_id", quoteID, "tenant_id", tenantID)
 		return fmt.Errorf("failed to approve quote: %w", err)
 	}
@@ -498,8 +541,8 @@ func (s *QuoteServiceImpl) ApproveQuote(ctx context.Context, quoteID uuid.UUID)
 	if err == nil && customer != nil && customer.Email != nil && *customer.Email != "" {
 		if err := s.communicationService.SendEmail(ctx, &EmailRequest{
 			To:      []string{*customer.Email},
-			Subject: fmt.Sprintf("Quote %s Approved", quote.QuoteNumber),
-			Body:    fmt.Sprintf("Your quote %s has been approved. Total amount: $%.2f", quote.QuoteNumber, quote.TotalAmount),
+			Subject: fmt.Sprintf("Quote %s Approved", revision),
+			Body:    fmt.Sprintf("Your quote %s has been approved. Total amount: $%.2f", revision, selection.TotalAmount),
 			IsHTML:  false,
 		}); err != nil {
 			s.logger.Printf("Failed to send quote approval email", "error", err, "quote_id", quoteID)
@@ -514,9 +557,14 @@ func (s *QuoteServiceImpl) ApproveQuote(ctx context.Context, quoteID uuid.UUID)
 		ResourceType: "quote",
 		ResourceID:   &quote.ID,
 		NewValues: map[string]interface{}{
-			"status":      "approved",
-			"approved_at": quote.ApprovedAt,
-			"approved_by": quote.ApprovedBy,
+			"status":              "approved",
+			"approved_at":         quote.ApprovedAt,
+			"approved_by":         quote.ApprovedBy,
+			"approved_version_id": version.ID,
+			"revision":            revision,
+			"option_ids":          selection.OptionIDs,
+			"add_on_line_ids":     selection.AddOnLineIDs,
+			"total_amount":        selection.TotalAmount,
 		},
 	}); err != nil {
 		s.logger.Printf("Failed to log audit event", "error", err)
@@ -617,37 +665,86 @@ func (s *QuoteServiceImpl) ConvertQuoteToJob(ctx context.Context, quoteID uuid.U
 		return nil, fmt.Errorf("only approved quotes can be converted to jobs")
 	}
 
-	// Get quote services
-	quoteServices, err := s.quoteRepo.GetQuoteServices(ctx, quoteID)
+	// The job is built from the approved revision, not the working copy
+	version, err := s.approvedQuoteVersion(ctx, tenantID, quote)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get quote services: %w", err)
+		return nil, err
 	}
 
-	// TODO: Use quote services to create corresponding job services
-	s.logger.Printf("Retrieved quote services for job creation - count: %d", len(quoteServices))
+	// Only the options and add-ons the customer chose carry over to the job
+	selectionReq := DefaultQuoteSelection(version)
+	if quote.ApprovedSelection != nil && quote.ApprovedSelection.VersionID == version.ID {
+		selectionReq = &QuoteSelectionRequest{
+			OptionIDs:    quote.ApprovedSelection.OptionIDs,
+			AddOnLineIDs: quote.ApprovedSelection.AddOnLineIDs,
+		}
+	}
+	selection, lines, err := ResolveQuoteSelection(version, selectionReq)
+	if err != nil {
+		return nil, fmt.Errorf("invalid approved selection: %w", err)
+	}
+	if quote.ApprovedSelection == nil {
+		quote.ApprovedSelection = selection
+	}
+
+	notes := fmt.Sprintf("Created from quote %s", FormatQuoteRevision(quote.QuoteNumber, version.VersionNumber))
+	if description := DescribeQuoteSelection(version, selection); description != "" {
+		notes += ": " + description
+	}
+
+	// Generate job number
+	jobNumber, err := s.jobRepo.GetNextJobNumber(ctx, tenantID)
+	if err != nil {
+		s.logger.Printf("Failed to generate job number: %v", err)
+		jobNumber = fmt.Sprintf("JOB-%d", time.Now().Unix())
+	}
 
 	// Create job from quote
+	totalAmount := selection.TotalAmount
 	job := &domain.EnhancedJob{
 		Job: domain.Job{
 			ID:          uuid.New(),
 			TenantID:    tenantID,
 			CustomerID:  quote.CustomerID,
 			PropertyID:  quote.PropertyID,
-			Title:       quote.Title,
-			Description: quote.Description,
+			Title:       version.Title,
+			Description: version.Description,
 			Status:      domain.JobStatusPending,
 			Priority:    "medium", // Default priority
-			TotalAmount: &quote.TotalAmount,
-			Notes:       stringPtr(fmt.Sprintf("Created from quote %s", quote.QuoteNumber)),
+			TotalAmount: &totalAmount,
+			Notes:       &notes,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		},
-		CrewSize: 1, // Default crew size
+		JobNumber:      &jobNumber,
+		CrewSize:       1, // Default crew size
+		QuoteID:        &quote.ID,
+		QuoteVersionID: &version.ID,
+	}
+
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		s.logger.Printf("Failed to create job from quote %s: %v", quoteID, err)
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	// Create job services from the selected line items
+	for _, line := range lines {
+		jobService := &domain.JobService{
+			ID:         uuid.New(),
+			JobID:      job.ID,
+			ServiceID:  line.ServiceID,
+			Quantity:   line.Quantity,
+			UnitPrice:  line.UnitPrice,
+			TotalPrice: line.TotalPrice,
+			CreatedAt:  time.Now(),
+		}
+
+		if err := s.jobRepo.CreateJobService(ctx, jobService); err != nil {
+			s.logger.Printf("Failed to create service %s on job %s: %v", line.ServiceID, job.ID, err)
+		}
 	}
 
-	// This would typically use a job service to create the job
-	// For now, we'll return a mock job
-	s.logger.Printf("Quote converted to job successfully", "quote_id", quoteID, "job_id", job.ID)
+	s.logger.Printf("Quote %s converted to job %s", quoteID, job.ID)
 
 	// Update quote status
 	quote.Status = "converted"
@@ -692,8 +789,24 @@ func (s *QuoteServiceImpl) GenerateQuotePDF(ctx context.Context, quoteID uuid.UU
 		return nil, fmt.Errorf("failed to get quote services: %w", err)
 	}
 
+	// Label the document with its revision unless it has unsent changes
+	documentNumber := quote.QuoteNumber
+	latest, err := s.quoteRepo.GetLatestQuoteVersion(ctx, tenantID, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest quote version: %w", err)
+	}
+	if latest != nil {
+		snapshot, err := s.buildWorkingSnapshot(ctx, tenantID, quote)
+		if err != nil {
+			return nil, err
+		}
+		if !DiffQuoteVersions(latest, snapshot).HasChanges() {
+			documentNumber = FormatQuoteRevision(quote.QuoteNumber, latest.VersionNumber)
+		}
+	}
+
 	// Generate PDF content (simplified HTML to PDF conversion)
-	pdfContent := s.generateQuotePDFContent(quote, customer, property, quoteServices)
+	pdfContent := s.generateQuotePDFContent(documentNumber, quote, customer, property, quoteServices)
 
 	// In a real implementation, you would use a PDF generation library like wkhtmltopdf or similar
 	// For now, return the HTML content as bytes
@@ -731,13 +844,20 @@ func (s *QuoteServiceImpl) SendQuote(ctx context.Context, quoteID uuid.UUID, sen
 		return fmt.Errorf("no email address available for customer")
 	}
 
+	// Each send is pinned to an immutable revision of the quote
+	version, err := s.snapshotQuote(ctx, tenantID, quote)
+	if err != nil {
+		return fmt.Errorf("failed to record quote version: %w", err)
+	}
+	revision := FormatQuoteRevision(quote.QuoteNumber, version.VersionNumber)
+
 	// Prepare email
-	subject := fmt.Sprintf("Quote %s", quote.QuoteNumber)
+	subject := fmt.Sprintf("Quote %s", revision)
 	if sendOptions.Subject != nil {
 		subject = *sendOptions.Subject
 	}
 
-	body := fmt.Sprintf("Please find attached your quote %s for $%.2f", quote.QuoteNumber, quote.TotalAmount)
+	body := fmt.Sprintf("Please find attached your quote %s for $%.2f", revision, version.TotalAmount)
 	if sendOptions.Message != nil {
 		body = *sendOptions.Message
 	}
@@ -761,7 +881,7 @@ func (s *QuoteServiceImpl) SendQuote(ctx context.Context, quoteID uuid.UUID, sen
 			s.logger.Printf("Failed to generate PDF for quote email", "error", err, "quote_id", quoteID)
 		} else {
 			attachment := Attachment{
-				Name:        fmt.Sprintf("quote_%s.pdf", quote.QuoteNumber),
+				Name:        fmt.Sprintf("quote_%s_rev_%s.pdf", quote.QuoteNumber, version.Revision),
 				ContentType: "application/pdf",
 				Data:        pdfData,
 			}
@@ -775,6 +895,10 @@ func (s *QuoteServiceImpl) SendQuote(ctx context.Context, quoteID uuid.UUID, sen
 		return fmt.Errorf("failed to send quote email: %w", err)
 	}
 
+	if err := s.quoteRepo.MarkQuoteVersionSent(ctx, tenantID, version.ID, recipientEmail, time.Now()); err != nil {
+		s.logger.Printf("Failed to record delivery of quote version %s: %v", version.ID, err)
+	}
+
 	// Update quote status to sent if it was draft
 	if quote.Status == "draft" {
 		quote.Status = "pending"
@@ -794,6 +918,8 @@ func (s *QuoteServiceImpl) SendQuote(ctx context.Context, quoteID uuid.UUID, sen
 		NewValues: map[string]interface{}{
 			"sent_to":     sendOptions.Email,
 			"include_pdf": sendOptions.IncludePDF,
+			"version_id":  version.ID,
+			"revision":    revision,
 		},
 	}); err != nil {
 		s.logger.Printf("Failed to log audit event", "error", err)
@@ -833,14 +959,17 @@ func (s *QuoteServiceImpl) GenerateQuoteFromDescription(ctx context.Context, req
 		return nil, fmt.Errorf("failed to generate AI quote: %w", err)
 	}
 
-	// Convert LLM recommendations to quote services
+	// Convert LLM recommendations to quote services. The recommendation picks the
+	// services and quantities; prices come from the price book when one is configured.
+	usePricingRules := s.pricingService != nil
 	services := make([]QuoteServiceRequest, 0, len(llmResponse.RecommendedServices))
 	for _, rec := range llmResponse.RecommendedServices {
 		services = append(services, QuoteServiceRequest{
-			ServiceID:   rec.ServiceID,
-			Quantity:    rec.Quantity,
-			UnitPrice:   rec.UnitPrice,
-			Description: &rec.Reasoning,
+			ServiceID:       rec.ServiceID,
+			Quantity:        rec.Quantity,
+			UnitPrice:       rec.UnitPrice,
+			Description:     &rec.Reasoning,
+			UsePricingRules: usePricingRules,
 		})
 	}
 
@@ -870,20 +999,35 @@ func (s *QuoteServiceImpl) GenerateQuoteFromDescription(ctx context.Context, req
 
 // Helper methods
 
+// approvedQuoteVersion returns the revision the quote was approved at. Quotes approved
+// before versioning have no pinned revision, so their current state is recorded instead;
+// approved quotes can no longer be edited, so it is what was approved.
+func (s *QuoteServiceImpl) approvedQuoteVersion(ctx context.Context, tenantID uuid.UUID, quote *domain.Quote) (*domain.QuoteVersion, error) {
+	if quote.ApprovedVersionID != nil {
+		version, err := s.quoteRepo.GetQuoteVersionByID(ctx, tenantID, *quote.ApprovedVersionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get approved quote version: %w", err)
+		}
+		if version == nil {
+			return nil, fmt.Errorf("approved quote version not found")
+		}
+		return version, nil
+	}
+
+	version, err := s.snapshotQuote(ctx, tenantID, quote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record quote version: %w", err)
+	}
+	quote.ApprovedVersionID = &version.ID
+	return version, nil
+}
+
 func (s *QuoteServiceImpl) validateCreateQuoteRequest(req *QuoteCreateRequest) error {
 	if strings.TrimSpace(req.Title) == "" {
 		return fmt.Errorf("quote title is required")
 	}
-	if len(req.Services) == 0 {
-		return fmt.Errorf("at least one service is required")
-	}
-	for i, svc := range req.Services {
-		if svc.Quantity <= 0 {
-			return fmt.Errorf("service %d: quantity must be greater than 0", i+1)
-		}
-		if svc.UnitPrice < 0 {
-			return fmt.Errorf("service %d: unit price cannot be negative", i+1)
-		}
+	if err := validateQuoteLineRequests(req.Services, req.Options); err != nil {
+		return err
 	}
 	if req.ValidUntil != nil && req.ValidUntil.Before(time.Now()) {
 		return fmt.Errorf("valid until date cannot be in the past")
@@ -891,12 +1035,12 @@ func (s *QuoteServiceImpl) validateCreateQuoteRequest(req *QuoteCreateRequest) e
 	return nil
 }
 
-func (s *QuoteServiceImpl) generateQuotePDFContent(quote *domain.Quote, customer *domain.EnhancedCustomer, property *domain.EnhancedProperty, services []*domain.QuoteService) string {
+func (s *QuoteServiceImpl) generateQuotePDFContent(documentNumber string, quote *domain.Quote, customer *domain.EnhancedCustomer, property *domain.EnhancedProperty, services []*domain.QuoteService) string {
 	// Generate a simple HTML template for the quote
 	var buf bytes.Buffer
 	
-	buf.WriteString("<html><head><title>Quote " + quote.QuoteNumber + "</title></head><body>")
-	buf.WriteString("<h1>Quote " + quote.QuoteNumber + "</h1>")
+	buf.WriteString("<html><head><title>Quote " + documentNumber + "</title></head><body>")
+	buf.WriteString("<h1>Quote " + documentNumber + "</h1>")
 	
 	buf.WriteString("<h2>Customer Information</h2>")
 	buf.WriteString("<p>" + customer.FirstName + " " + customer.LastName + "</p>")
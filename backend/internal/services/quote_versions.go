@@ -0,0 +1,565 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// QuoteVersionDiff describes what changed between two revisions of a quote
+type QuoteVersionDiff struct {
+	QuoteID       uuid.UUID           `json:"quote_id"`
+	FromVersion   int                 `json:"from_version"`
+	FromRevision  string              `json:"from_revision"`
+	ToVersion     int                 `json:"to_version"`  // 0 is the unsent working copy
+	ToRevision    string              `json:"to_revision"` // empty for the working copy
+	FieldChanges  []QuoteFieldChange  `json:"field_changes"`
+	LineChanges   []QuoteLineChange   `json:"line_changes"`
+	OptionChanges []QuoteOptionChange `json:"option_changes"`
+	SubtotalDelta float64             `json:"subtotal_delta"`
+	TotalDelta    float64             `json:"total_delta"`
+}
+
+// QuoteFieldChange is a changed quote header field
+type QuoteFieldChange struct {
+	Field    string      `json:"field"`
+	OldValue interface{} `json:"old_value"`
+	NewValue interface{} `json:"new_value"`
+}
+
+// QuoteLineChange is an added, removed or modified quote line item
+type QuoteLineChange struct {
+	Change        string                   `json:"change"`
+	ServiceID     uuid.UUID                `json:"service_id"`
+	ServiceName   string                   `json:"service_name"`
+	Option        string                   `json:"option,omitempty"` // "Group: Option" for option lines
+	Old           *domain.QuoteVersionLine `json:"old,omitempty"`
+	New           *domain.QuoteVersionLine `json:"new,omitempty"`
+	ChangedFields []string                 `json:"changed_fields,omitempty"`
+}
+
+// QuoteOptionChange is an added, removed or modified quote option
+type QuoteOptionChange struct {
+	Change        string              `json:"change"`
+	GroupName     string              `json:"group_name"`
+	Name          string              `json:"name"`
+	Old           *domain.QuoteOption `json:"old,omitempty"`
+	New           *domain.QuoteOption `json:"new,omitempty"`
+	ChangedFields []string            `json:"changed_fields,omitempty"`
+}
+
+// HasChanges reports whether the two revisions differ
+func (d *QuoteVersionDiff) HasChanges() bool {
+	return len(d.FieldChanges) > 0 || len(d.LineChanges) > 0 || len(d.OptionChanges) > 0
+}
+
+// quoteDerivedFields are recalculated from the line items and are not reported as edits on their own
+var quoteDerivedFields = map[string]bool{
+	"subtotal":     true,
+	"tax_amount":   true,
+	"total_amount": true,
+}
+
+// ListQuoteVersions lists a quote's revisions, newest first
+func (s *QuoteServiceImpl) ListQuoteVersions(ctx context.Context, quoteID uuid.UUID) ([]*domain.QuoteVersion, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	quote, err := s.quoteRepo.GetByID(ctx, tenantID, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+	if quote == nil {
+		return nil, fmt.Errorf("quote not found")
+	}
+
+	versions, err := s.quoteRepo.ListQuoteVersions(ctx, tenantID, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quote versions: %w", err)
+	}
+
+	return versions, nil
+}
+
+// GetQuoteVersion retrieves a single revision of a quote by version number
+func (s *QuoteServiceImpl) GetQuoteVersion(ctx context.Context, quoteID uuid.UUID, versionNumber int) (*domain.QuoteVersion, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	version, err := s.quoteRepo.GetQuoteVersion(ctx, tenantID, quoteID, versionNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote version: %w", err)
+	}
+	if version == nil {
+		return nil, fmt.Errorf("quote version not found")
+	}
+
+	return version, nil
+}
+
+// CompareQuoteVersions diffs two revisions of a quote. A toVersion of 0 compares
+// against the current working copy, showing changes that have not been sent yet.
+func (s *QuoteServiceImpl) CompareQuoteVersions(ctx context.Context, quoteID uuid.UUID, fromVersion, toVersion int) (*QuoteVersionDiff, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	from, err := s.GetQuoteVersion(ctx, quoteID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var to *domain.QuoteVersion
+	if toVersion == 0 {
+		quote, err := s.quoteRepo.GetByID(ctx, tenantID, quoteID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get quote: %w", err)
+		}
+		if quote == nil {
+			return nil, fmt.Errorf("quote not found")
+		}
+
+		to, err = s.buildWorkingSnapshot(ctx, tenantID, quote)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		to, err = s.GetQuoteVersion(ctx, quoteID, toVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return DiffQuoteVersions(from, to), nil
+}
+
+// snapshotQuote records the quote's current state as a new revision. If nothing has
+// changed since the latest revision, the latest revision is returned instead.
+func (s *QuoteServiceImpl) snapshotQuote(ctx context.Context, tenantID uuid.UUID, quote *domain.Quote) (*domain.QuoteVersion, error) {
+	snapshot, err := s.buildWorkingSnapshot(ctx, tenantID, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := s.quoteRepo.GetLatestQuoteVersion(ctx, tenantID, quote.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest quote version: %w", err)
+	}
+
+	summary := "Initial revision"
+	if latest != nil {
+		diff := DiffQuoteVersions(latest, snapshot)
+		if !diff.HasChanges() {
+			return latest, nil
+		}
+		summary = SummarizeQuoteDiff(diff)
+	}
+
+	snapshot.ChangeSummary = &summary
+	snapshot.CreatedBy = GetUserIDFromContext(ctx)
+
+	if err := s.quoteRepo.CreateQuoteVersion(ctx, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to create quote version: %w", err)
+	}
+	quote.CurrentVersion = snapshot.VersionNumber
+
+	// Log audit event
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       snapshot.CreatedBy,
+		Action:       "quote.revise",
+		ResourceType: "quote",
+		ResourceID:   &quote.ID,
+		NewValues: map[string]interface{}{
+			"version_id":     snapshot.ID,
+			"revision":       FormatQuoteRevision(quote.QuoteNumber, snapshot.VersionNumber),
+			"total_amount":   snapshot.TotalAmount,
+			"change_summary": summary,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return snapshot, nil
+}
+
+// buildWorkingSnapshot builds an unsaved version from the quote's current line items
+func (s *QuoteServiceImpl) buildWorkingSnapshot(ctx context.Context, tenantID uuid.UUID, quote *domain.Quote) (*domain.QuoteVersion, error) {
+	lines, err := s.quoteRepo.GetQuoteServices(ctx, quote.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote services: %w", err)
+	}
+
+	serviceIDs := make([]uuid.UUID, 0, len(lines))
+	for _, line := range lines {
+		if !containsUUID(serviceIDs, line.ServiceID) {
+			serviceIDs = append(serviceIDs, line.ServiceID)
+		}
+	}
+
+	serviceMap := make(map[uuid.UUID]*domain.Service, len(serviceIDs))
+	if len(serviceIDs) > 0 {
+		services, err := s.serviceRepo.GetByIDs(ctx, tenantID, serviceIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get services: %w", err)
+		}
+		for _, service := range services {
+			serviceMap[service.ID] = service
+		}
+	}
+
+	options, err := s.quoteRepo.GetQuoteOptions(ctx, quote.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote options: %w", err)
+	}
+
+	return BuildQuoteVersion(quote, lines, options, serviceMap), nil
+}
+
+// BuildQuoteVersion snapshots a quote with its line items and options. The version
+// number and revision are assigned when the version is saved.
+func BuildQuoteVersion(quote *domain.Quote, lines []*domain.QuoteService, options []*domain.QuoteOption, services map[uuid.UUID]*domain.Service) *domain.QuoteVersion {
+	version := &domain.QuoteVersion{
+		ID:                 uuid.New(),
+		TenantID:           quote.TenantID,
+		QuoteID:            quote.ID,
+		Title:              quote.Title,
+		Description:        quote.Description,
+		Subtotal:           quote.Subtotal,
+		TaxRate:            quote.TaxRate,
+		TaxAmount:          quote.TaxAmount,
+		TotalAmount:        quote.TotalAmount,
+		ValidUntil:         quote.ValidUntil,
+		TermsAndConditions: quote.TermsAndConditions,
+		Notes:              quote.Notes,
+		LineItems:          make([]domain.QuoteVersionLine, 0, len(lines)),
+		Options:            make([]domain.QuoteOption, 0, len(options)),
+		CreatedAt:          time.Now(),
+	}
+
+	for _, option := range options {
+		version.Options = append(version.Options, *option)
+	}
+
+	for _, line := range lines {
+		name := ""
+		if service, ok := services[line.ServiceID]; ok {
+			name = service.Name
+		}
+
+		// Copy the option ID so the snapshot doesn't alias the working copy
+		var optionID *uuid.UUID
+		if line.OptionID != nil {
+			id := *line.OptionID
+			optionID = &id
+		}
+
+		version.LineItems = append(version.LineItems, domain.QuoteVersionLine{
+			LineID:      line.ID,
+			ServiceID:   line.ServiceID,
+			ServiceName: name,
+			OptionID:    optionID,
+			IsOptional:  line.IsOptional,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			TotalPrice:  line.TotalPrice,
+			Description: line.Description,
+		})
+	}
+
+	return version
+}
+
+// DiffQuoteVersions compares two quote revisions. Line items are matched by option
+// and service, in order, so repeated services pair up with their counterparts.
+// Options are matched by group and name since they are recreated on every edit.
+func DiffQuoteVersions(from, to *domain.QuoteVersion) *QuoteVersionDiff {
+	diff := &QuoteVersionDiff{
+		QuoteID:       to.QuoteID,
+		FromVersion:   from.VersionNumber,
+		FromRevision:  from.Revision,
+		ToVersion:     to.VersionNumber,
+		ToRevision:    to.Revision,
+		FieldChanges:  []QuoteFieldChange{},
+		LineChanges:   []QuoteLineChange{},
+		OptionChanges: []QuoteOptionChange{},
+		SubtotalDelta: roundCents(to.Subtotal - from.Subtotal),
+		TotalDelta:    roundCents(to.TotalAmount - from.TotalAmount),
+	}
+
+	addField := func(field string, oldValue, newValue interface{}) {
+		if oldValue != newValue {
+			diff.FieldChanges = append(diff.FieldChanges, QuoteFieldChange{Field: field, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+
+	addField("title", from.Title, to.Title)
+	addField("description", stringValue(from.Description), stringValue(to.Description))
+	addField("valid_until", quoteDateValue(from.ValidUntil), quoteDateValue(to.ValidUntil))
+	addField("terms_and_conditions", stringValue(from.TermsAndConditions), stringValue(to.TermsAndConditions))
+	addField("notes", stringValue(from.Notes), stringValue(to.Notes))
+	addField("tax_rate", roundRate(from.TaxRate), roundRate(to.TaxRate))
+	addField("subtotal", roundCents(from.Subtotal), roundCents(to.Subtotal))
+	addField("tax_amount", roundCents(from.TaxAmount), roundCents(to.TaxAmount))
+	addField("total_amount", roundCents(from.TotalAmount), roundCents(to.TotalAmount))
+
+	diffQuoteOptions(diff, from, to)
+
+	type lineKey struct {
+		option    string
+		serviceID uuid.UUID
+	}
+	fromLabels := quoteOptionLabels(from)
+	toLabels := quoteOptionLabels(to)
+	keyOf := func(line domain.QuoteVersionLine, labels map[uuid.UUID]string) lineKey {
+		key := lineKey{serviceID: line.ServiceID}
+		if line.OptionID != nil {
+			key.option = labels[*line.OptionID]
+		}
+		return key
+	}
+
+	// Queue the old lines per option and service so repeated services match in order
+	remaining := make(map[lineKey][]int)
+	for i, line := range from.LineItems {
+		key := keyOf(line, fromLabels)
+		remaining[key] = append(remaining[key], i)
+	}
+	matched := make(map[int]bool)
+
+	for i := range to.LineItems {
+		newLine := to.LineItems[i]
+		key := keyOf(newLine, toLabels)
+
+		queue := remaining[key]
+		if len(queue) == 0 {
+			diff.LineChanges = append(diff.LineChanges, QuoteLineChange{
+				Change:      domain.QuoteLineChangeAdded,
+				ServiceID:   newLine.ServiceID,
+				ServiceName: newLine.ServiceName,
+				Option:      key.option,
+				New:         &newLine,
+			})
+			continue
+		}
+
+		oldIndex := queue[0]
+		remaining[key] = queue[1:]
+		matched[oldIndex] = true
+
+		oldLine := from.LineItems[oldIndex]
+		if changed := changedQuoteLineFields(oldLine, newLine); len(changed) > 0 {
+			diff.LineChanges = append(diff.LineChanges, QuoteLineChange{
+				Change:        domain.QuoteLineChangeModified,
+				ServiceID:     newLine.ServiceID,
+				ServiceName:   newLine.ServiceName,
+				Option:        key.option,
+				Old:           &oldLine,
+				New:           &newLine,
+				ChangedFields: changed,
+			})
+		}
+	}
+
+	for i := range from.LineItems {
+		if matched[i] {
+			continue
+		}
+		oldLine := from.LineItems[i]
+		diff.LineChanges = append(diff.LineChanges, QuoteLineChange{
+			Change:      domain.QuoteLineChangeRemoved,
+			ServiceID:   oldLine.ServiceID,
+			ServiceName: oldLine.ServiceName,
+			Option:      keyOf(oldLine, fromLabels).option,
+			Old:         &oldLine,
+		})
+	}
+
+	return diff
+}
+
+// diffQuoteOptions records options added, removed or modified between two revisions
+func diffQuoteOptions(diff *QuoteVersionDiff, from, to *domain.QuoteVersion) {
+	type optionKey struct{ group, name string }
+
+	oldOptions := make(map[optionKey]domain.QuoteOption, len(from.Options))
+	for _, option := range from.Options {
+		oldOptions[optionKey{option.GroupName, option.Name}] = option
+	}
+
+	seen := make(map[optionKey]bool)
+	for i := range to.Options {
+		newOption := to.Options[i]
+		key := optionKey{newOption.GroupName, newOption.Name}
+		seen[key] = true
+
+		oldOption, ok := oldOptions[key]
+		if !ok {
+			diff.OptionChanges = append(diff.OptionChanges, QuoteOptionChange{
+				Change:    domain.QuoteLineChangeAdded,
+				GroupName: newOption.GroupName,
+				Name:      newOption.Name,
+				New:       &newOption,
+			})
+			continue
+		}
+
+		var changed []string
+		if stringValue(oldOption.Description) != stringValue(newOption.Description) {
+			changed = append(changed, "description")
+		}
+		if oldOption.IsDefault != newOption.IsDefault {
+			changed = append(changed, "is_default")
+		}
+		if len(changed) > 0 {
+			diff.OptionChanges = append(diff.OptionChanges, QuoteOptionChange{
+				Change:        domain.QuoteLineChangeModified,
+				GroupName:     newOption.GroupName,
+				Name:          newOption.Name,
+				Old:           &oldOption,
+				New:           &newOption,
+				ChangedFields: changed,
+			})
+		}
+	}
+
+	for i := range from.Options {
+		oldOption := from.Options[i]
+		if seen[optionKey{oldOption.GroupName, oldOption.Name}] {
+			continue
+		}
+		diff.OptionChanges = append(diff.OptionChanges, QuoteOptionChange{
+			Change:    domain.QuoteLineChangeRemoved,
+			GroupName: oldOption.GroupName,
+			Name:      oldOption.Name,
+			Old:       &oldOption,
+		})
+	}
+}
+
+// quoteOptionLabels maps a version's option IDs to "Group: Option" labels
+func quoteOptionLabels(version *domain.QuoteVersion) map[uuid.UUID]string {
+	labels := make(map[uuid.UUID]string, len(version.Options))
+	for _, option := range version.Options {
+		labels[option.ID] = option.GroupName + ": " + option.Name
+	}
+	return labels
+}
+
+// SummarizeQuoteDiff describes a diff in one line, e.g.
+// "Changed valid_until; added 1 line item; total +$120.00"
+func SummarizeQuoteDiff(diff *QuoteVersionDiff) string {
+	var parts []string
+
+	var fields []string
+	for _, change := range diff.FieldChanges {
+		if !quoteDerivedFields[change.Field] {
+			fields = append(fields, change.Field)
+		}
+	}
+	if len(fields) > 0 {
+		parts = append(parts, "changed "+strings.Join(fields, ", "))
+	}
+
+	countChanges := func(counts map[string]int, singular, plural string) {
+		for _, change := range []string{domain.QuoteLineChangeAdded, domain.QuoteLineChangeRemoved, domain.QuoteLineChangeModified} {
+			if n := counts[change]; n > 0 {
+				noun := plural
+				if n == 1 {
+					noun = singular
+				}
+				parts = append(parts, fmt.Sprintf("%s %d %s", change, n, noun))
+			}
+		}
+	}
+
+	optionCounts := make(map[string]int)
+	for _, change := range diff.OptionChanges {
+		optionCounts[change.Change]++
+	}
+	countChanges(optionCounts, "option", "options")
+
+	lineCounts := make(map[string]int)
+	for _, change := range diff.LineChanges {
+		lineCounts[change.Change]++
+	}
+	countChanges(lineCounts, "line item", "line items")
+
+	if diff.TotalDelta > 0 {
+		parts = append(parts, fmt.Sprintf("total +$%.2f", diff.TotalDelta))
+	} else if diff.TotalDelta < 0 {
+		parts = append(parts, fmt.Sprintf("total -$%.2f", -diff.TotalDelta))
+	}
+
+	if len(parts) == 0 {
+		return "No changes"
+	}
+
+	summary := strings.Join(parts, "; ")
+	return strings.ToUpper(summary[:1]) + summary[1:]
+}
+
+// QuoteRevisionLabel converts a version number to its revision letter: 1 is A,
+// 26 is Z, 27 is AA
+func QuoteRevisionLabel(version int) string {
+	if version <= 0 {
+		return ""
+	}
+
+	label := ""
+	for version > 0 {
+		version--
+		label = string(rune('A'+version%26)) + label
+		version /= 26
+	}
+	return label
+}
+
+// FormatQuoteRevision formats a quote number with its revision, e.g. "Q-1001 rev B"
+func FormatQuoteRevision(quoteNumber string, version int) string {
+	if version <= 0 {
+		return quoteNumber
+	}
+	return fmt.Sprintf("%s rev %s", quoteNumber, QuoteRevisionLabel(version))
+}
+
+func changedQuoteLineFields(oldLine, newLine domain.QuoteVersionLine) []string {
+	var changed []string
+	if roundCents(oldLine.Quantity) != roundCents(newLine.Quantity) {
+		changed = append(changed, "quantity")
+	}
+	if roundCents(oldLine.UnitPrice) != roundCents(newLine.UnitPrice) {
+		changed = append(changed, "unit_price")
+	}
+	if roundCents(oldLine.TotalPrice) != roundCents(newLine.TotalPrice) {
+		changed = append(changed, "total_price")
+	}
+	if stringValue(oldLine.Description) != stringValue(newLine.Description) {
+		changed = append(changed, "description")
+	}
+	if oldLine.IsOptional != newLine.IsOptional {
+		changed = append(changed, "is_optional")
+	}
+	return changed
+}
+
+func quoteDateValue(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func roundRate(rate float64) float64 {
+	return math.Round(rate*10000) / 10000
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"math"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,13 +16,16 @@ import (
 
 // SchedulingServiceImpl implements the ScheduleService interface
 type SchedulingServiceImpl struct {
-	jobRepo         JobRepositoryComplete
-	userRepo        UserRepository
-	crewRepo        CrewRepository
-	equipmentRepo   EquipmentRepository
-	propertyRepo    PropertyRepositoryExtended
-	auditService    AuditService
-	logger          *log.Logger
+	jobRepo          JobRepositoryComplete
+	userRepo         UserRepository
+	crewRepo         CrewRepository
+	equipmentRepo    EquipmentRepository
+	reservationRepo  EquipmentReservationRepository
+	workOrderRepo    MaintenanceWorkOrderRepository
+	availabilityRepo AvailabilityRepository
+	propertyRepo     PropertyRepositoryExtended
+	auditService     AuditService
+	logger           *log.Logger
 }
 
 // NewSchedulingService creates a new scheduling service instance
@@ -30,18 +34,24 @@ func NewSchedulingService(
 	userRepo UserRepository,
 	crewRepo CrewRepository,
 	equipmentRepo EquipmentRepository,
+	reservationRepo EquipmentReservationRepository,
+	workOrderRepo MaintenanceWorkOrderRepository,
+	availabilityRepo AvailabilityRepository,
 	propertyRepo PropertyRepositoryExtended,
 	auditService AuditService,
 	logger *log.Logger,
 ) ScheduleService {
 	return &SchedulingServiceImpl{
-		jobRepo:       jobRepo,
-		userRepo:      userRepo,
-		crewRepo:      crewRepo,
-		equipmentRepo: equipmentRepo,
-		propertyRepo:  propertyRepo,
-		auditService:  auditService,
-		logger:        logger,
+		jobRepo:          jobRepo,
+		userRepo:         userRepo,
+		crewRepo:         crewRepo,
+		equipmentRepo:    equipmentRepo,
+		reservationRepo:  reservationRepo,
+		workOrderRepo:    workOrderRepo,
+		availabilityRepo: availabilityRepo,
+		propertyRepo:     propertyRepo,
+		auditService:     auditService,
+		logger:           logger,
 	}
 }
 
@@ -70,7 +80,7 @@ func (s *SchedulingServiceImpl) OptimizeSchedule(ctx context.Context, req *Sched
 	}
 
 	// Get all available resources for the time range
-	resources, err := s.getAvailableResources(ctx, tenantID, req.TimeRange)
+	resources, err := s.getAvailableResources(ctx, tenantID, req.TimeRange, jobs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get available resources: %w", err)
 	}
@@ -202,6 +212,19 @@ func (s *SchedulingServiceImpl) CheckAvailability(ctx context.Context, req *Avai
 		}
 	}
 
+	// Check equipment availability
+	if len(req.EquipmentIDs) > 0 {
+		for _, equipmentID := range req.EquipmentIDs {
+			slots, equipmentConflicts, err := s.checkEquipmentAvailability(ctx, tenantID, equipmentID, req.TimeRange)
+			if err != nil {
+				s.logger.Printf("Failed to check availability of equipment %s: %v", equipmentID, err)
+				continue
+			}
+			availableSlots = append(availableSlots, slots...)
+			conflicts = append(conflicts, equipmentConflicts...)
+		}
+	}
+
 	// Sort available slots by start time
 	sort.Slice(availableSlots, func(i, j int) bool {
 		return availableSlots[i].StartTime.Before(availableSlots[j].StartTime)
@@ -305,7 +328,7 @@ func (s *SchedulingServiceImpl) GetCalendarEvents(ctx context.Context, userID uu
 
 // Private helper methods
 
-func (s *SchedulingServiceImpl) getAvailableResources(ctx context.Context, tenantID uuid.UUID, timeRange TimeRange) (*ScheduleResources, error) {
+func (s *SchedulingServiceImpl) getAvailableResources(ctx context.Context, tenantID uuid.UUID, timeRange TimeRange, jobs []*domain.EnhancedJob) (*ScheduleResources, error) {
 	// This would get all available users, crews, and equipment for the time range
 	// For now, return a simplified structure
 	
@@ -321,10 +344,17 @@ func (s *SchedulingServiceImpl) getAvailableResources(ctx context.Context, tenan
 		return nil, fmt.Errorf("failed to get existing jobs: %w", err)
 	}
 
+	// The jobs being optimized are moving, so their current times and their
+	// own equipment reservations do not block anything
+	optimizing := make(map[uuid.UUID]bool, len(jobs))
+	for _, job := range jobs {
+		optimizing[job.ID] = true
+	}
+
 	// Build conflict map
 	conflictMap := make(map[uuid.UUID][]TimeRange)
 	for _, job := range existingJobs {
-		if job.ScheduledDate != nil && job.AssignedUserID != nil {
+		if job.ScheduledDate != nil && job.AssignedUserID != nil && !optimizing[job.ID] {
 			endTime := *job.ScheduledDate
 			if job.EstimatedDuration != nil {
 				endTime = endTime.Add(time.Duration(*job.EstimatedDuration) * time.Minute)
@@ -341,6 +371,93 @@ func (s *SchedulingServiceImpl) getAvailableResources(ctx context.Context, tenan
 		}
 	}
 
+	// Assigned users are busy outside their shifts, on approved time off and
+	// on holidays, as well as during their other jobs
+	userIDs := make([]uuid.UUID, 0)
+	seenUsers := make(map[uuid.UUID]bool)
+	for _, job := range jobs {
+		if job.AssignedUserID != nil && !seenUsers[*job.AssignedUserID] {
+			seenUsers[*job.AssignedUserID] = true
+			userIDs = append(userIDs, *job.AssignedUserID)
+		}
+	}
+
+	windows, _, err := loadWorkingWindows(ctx, s.availabilityRepo, tenantID, userIDs, timeRange)
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range userIDs {
+		offShift := SubtractTimeRanges([]TimeRange{timeRange}, windows[userID])
+		resources.Users = append(resources.Users, ResourceAvailability{
+			ResourceID:   userID,
+			ResourceType: "user",
+			Available:    len(windows[userID]) > 0,
+			Conflicts:    append(offShift, conflictMap[userID]...),
+		})
+	}
+
+	// Nobody works on a holiday, whether or not a job is assigned yet
+	if s.availabilityRepo != nil {
+		holidays, err := s.availabilityRepo.ListHolidays(ctx, tenantID, startOfDay(timeRange.Start), timeRange.End)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get holidays: %w", err)
+		}
+		for _, holiday := range holidays {
+			day := time.Date(holiday.Date.Year(), holiday.Date.Month(), holiday.Date.Day(), 0, 0, 0, 0, timeRange.Start.Location())
+			resources.Holidays = append(resources.Holidays, TimeRange{Start: day, End: day.AddDate(0, 0, 1)})
+		}
+	}
+
+	// Equipment is booked through reservations
+
+	reservations, err := s.reservationRepo.ListReservations(ctx, tenantID, nil, timeRange.Start, timeRange.End)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get equipment reservations: %w", err)
+	}
+
+	// Equipment out of service for maintenance is blocked until it is expected back
+	downtime, err := s.workOrderRepo.ListDowntime(ctx, tenantID, nil, timeRange.Start, timeRange.End)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get equipment downtime: %w", err)
+	}
+
+	equipmentIndex := make(map[uuid.UUID]int)
+	addEquipmentConflict := func(equipmentID uuid.UUID, conflict TimeRange) {
+		index, ok := equipmentIndex[equipmentID]
+		if !ok {
+			index = len(resources.Equipment)
+			equipmentIndex[equipmentID] = index
+			resources.Equipment = append(resources.Equipment, ResourceAvailability{
+				ResourceID:   equipmentID,
+				ResourceType: "equipment",
+				Available:    true,
+			})
+		}
+
+		resources.Equipment[index].Conflicts = append(resources.Equipment[index].Conflicts, conflict)
+		// Equipment blocked for the whole range is not available at all
+		if !conflict.Start.After(timeRange.Start) && !conflict.End.Before(timeRange.End) {
+			resources.Equipment[index].Available = false
+		}
+	}
+
+	for _, reservation := range reservations {
+		if reservation.JobID != nil && optimizing[*reservation.JobID] {
+			continue
+		}
+		addEquipmentConflict(reservation.EquipmentID, TimeRange{
+			Start: reservation.StartsAt,
+			End:   reservation.EndsAt,
+		})
+	}
+
+	now := time.Now()
+	for _, workOrder := range downtime {
+		if window, ok := DowntimeWindow(workOrder, now, timeRange.End); ok {
+			addEquipmentConflict(workOrder.EquipmentID, window)
+		}
+	}
+
 	return resources, nil
 }
 
@@ -378,6 +495,20 @@ func (s *SchedulingServiceImpl) optimizeJobSchedule(ctx context.Context, jobs []
 		return iDuration < jDuration
 	})
 
+	// Equipment is a constrained resource: a job cannot start while anything it
+	// requires is reserved elsewhere or taken by a job scheduled earlier in this run
+	equipmentBusy := make(map[uuid.UUID][]TimeRange, len(resources.Equipment))
+	for _, equipment := range resources.Equipment {
+		equipmentBusy[equipment.ResourceID] = append(equipmentBusy[equipment.ResourceID], equipment.Conflicts...)
+	}
+
+	// Assigned users likewise cannot work outside their shifts or while on
+	// another job, including one scheduled earlier in this run
+	userBusy := make(map[uuid.UUID][]TimeRange, len(resources.Users))
+	for _, user := range resources.Users {
+		userBusy[user.ResourceID] = append(userBusy[user.ResourceID], user.Conflicts...)
+	}
+
 	// Schedule each job
 	currentTime := req.TimeRange.Start
 	for _, job := range sortedJobs {
@@ -386,14 +517,29 @@ func (s *SchedulingServiceImpl) optimizeJobSchedule(ctx context.Context, jobs []
 			duration = time.Duration(*job.EstimatedDuration) * time.Minute
 		}
 
+		busy := append([]TimeRange{}, resources.Holidays...)
+		for _, equipmentID := range job.RequiresEquipment {
+			busy = append(busy, equipmentBusy[equipmentID]...)
+		}
+		if job.AssignedUserID != nil {
+			busy = append(busy, userBusy[*job.AssignedUserID]...)
+		}
+
 		// Find next available slot
-		startTime := s.findNextAvailableSlot(currentTime, duration, req.TimeRange.End)
+		startTime := s.findNextAvailableSlot(currentTime, duration, req.TimeRange.End, busy)
 		if startTime == nil {
 			s.logger.Printf("Could not find available slot for job", "job_id", job.ID)
 			continue
 		}
 
 		endTime := startTime.Add(duration)
+
+		for _, equipmentID := range job.RequiresEquipment {
+			equipmentBusy[equipmentID] = append(equipmentBusy[equipmentID], TimeRange{Start: *startTime, End: endTime})
+		}
+		if job.AssignedUserID != nil {
+			userBusy[*job.AssignedUserID] = append(userBusy[*job.AssignedUserID], TimeRange{Start: *startTime, End: endTime})
+		}
 		
 		// Create schedule slot
 		slot := ScheduleSlot{
@@ -414,14 +560,8 @@ func (s *SchedulingServiceImpl) optimizeJobSchedule(ctx context.Context, jobs []
 	return schedule, nil
 }
 
-func (s *SchedulingServiceImpl) findNextAvailableSlot(startTime time.Time, duration time.Duration, maxTime time.Time) *time.Time {
-	// Simple implementation - in practice, this would check for conflicts
-	if startTime.Add(duration).After(maxTime) {
-		return nil
-	}
-	
-	// For now, just return the start time
-	return &startTime
+func (s *SchedulingServiceImpl) findNextAvailableSlot(startTime time.Time, duration time.Duration, maxTime time.Time, busy []TimeRange) *time.Time {
+	return NextFreeSlot(startTime, duration, maxTime, busy)
 }
 
 func (s *SchedulingServiceImpl) calculateScheduleMetrics(schedule []ScheduleSlot, jobs []*domain.EnhancedJob) ScheduleMetrics {
@@ -503,6 +643,14 @@ func (s *SchedulingServiceImpl) generateImprovements(schedule []ScheduleSlot, jo
 		}
 	}
 
+	// Check for assignees who are not working at all in the window
+	for _, user := range resources.Users {
+		if !user.Available {
+			improvements = append(improvements, "Some assigned users are off or on time off for the whole window - consider reassigning their jobs")
+			break
+		}
+	}
+
 	// Check for unscheduled high-priority jobs
 	scheduledJobIDs := make(map[uuid.UUID]bool)
 	for _, slot := range schedule {
@@ -697,88 +845,162 @@ func (s *SchedulingServiceImpl) checkUserAvailability(ctx context.Context, tenan
 		conflicts = append(conflicts, conflict)
 	}
 
-	// Create available slots between conflicts
-	businessStart := time.Date(timeRange.Start.Year(), timeRange.Start.Month(), timeRange.Start.Day(), 8, 0, 0, 0, timeRange.Start.Location())
-	businessEnd := time.Date(timeRange.Start.Year(), timeRange.Start.Month(), timeRange.Start.Day(), 17, 0, 0, 0, timeRange.Start.Location())
-	
-	// Start from the later of start time or business start
-	currentTime := timeRange.Start
-	if currentTime.Before(businessStart) {
-		currentTime = businessStart
+	// Available slots are the gaps between jobs within the user's shifts, after
+	// holidays and approved time off
+	windows, offConflicts, err := loadWorkingWindows(ctx, s.availabilityRepo, tenantID, []uuid.UUID{userID}, timeRange)
+	if err != nil {
+		return nil, nil, err
 	}
+	conflicts = append(conflicts, offConflicts[userID]...)
 
-	for _, job := range userJobs {
-		if job.ScheduledDate == nil {
-			continue
-		}
-
-		if currentTime.Before(*job.ScheduledDate) && currentTime.Before(businessEnd) {
-			slotEnd := *job.ScheduledDate
-			if slotEnd.After(businessEnd) {
-				slotEnd = businessEnd
-			}
-
-			if slotEnd.Sub(currentTime) >= time.Hour { // Minimum 1 hour slot
-				slot := AvailabilitySlot{
-					UserID:    &userID,
-					StartTime: currentTime,
-					EndTime:   slotEnd,
-					Capacity:  1,
-				}
-				slots = append(slots, slot)
-			}
-		}
-
-		// Move current time to end of job
-		if job.EstimatedDuration != nil {
-			currentTime = job.ScheduledDate.Add(time.Duration(*job.EstimatedDuration) * time.Minute)
-		} else {
-			currentTime = job.ScheduledDate.Add(2 * time.Hour)
-		}
+	busy := make([]TimeRange, len(conflicts))
+	for i, conflict := range conflicts {
+		busy[i] = conflict.ConflictTime
 	}
 
-	// Add final slot if there's time remaining
-	if currentTime.Before(businessEnd) && currentTime.Before(timeRange.End) {
-		slotEnd := businessEnd
-		if timeRange.End.Before(businessEnd) {
-			slotEnd = timeRange.End
-		}
-
-		if slotEnd.Sub(currentTime) >= time.Hour {
+	for _, free := range SubtractTimeRanges(windows[userID], busy) {
+		if free.End.Sub(free.Start) >= time.Hour { // Minimum 1 hour slot
 			slot := AvailabilitySlot{
 				UserID:    &userID,
-				StartTime: currentTime,
-				EndTime:   slotEnd,
+				StartTime: free.Start,
+				EndTime:   free.End,
 				Capacity:  1,
 			}
 			slots = append(slots, slot)
 		}
 	}
 
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].ConflictTime.Start.Before(conflicts[j].ConflictTime.Start)
+	})
+
 	return slots, conflicts, nil
 }
 
 func (s *SchedulingServiceImpl) checkCrewAvailability(ctx context.Context, tenantID, crewID uuid.UUID, timeRange TimeRange) ([]AvailabilitySlot, []AvailabilityConflict, error) {
-	// Similar to user availability but for crews
-	// This would check crew member availability and equipment assignments
-	
+	// A crew works while any of its members is on shift. Holidays close the
+	// crew with everyone else; a crew without members keeps the default shift.
+	memberIDs, err := s.crewRepo.GetActiveMemberIDs(ctx, tenantID, crewID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get crew members: %w", err)
+	}
+
 	slots := make([]AvailabilitySlot, 0)
 	conflicts := make([]AvailabilityConflict, 0)
 
-	// For now, return a simple available slot
-	businessStart := time.Date(timeRange.Start.Year(), timeRange.Start.Month(), timeRange.Start.Day(), 8, 0, 0, 0, timeRange.Start.Location())
-	businessEnd := time.Date(timeRange.Start.Year(), timeRange.Start.Month(), timeRange.Start.Day(), 17, 0, 0, 0, timeRange.Start.Location())
+	// With no members the crew stands in for one, getting the default shift
+	// less holidays
+	rosterIDs := memberIDs
+	if len(rosterIDs) == 0 {
+		rosterIDs = []uuid.UUID{crewID}
+	}
+	windows, offConflicts, err := loadWorkingWindows(ctx, s.availabilityRepo, tenantID, rosterIDs, timeRange)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if businessEnd.After(timeRange.Start) && businessStart.Before(timeRange.End) {
+	var crewWindows []TimeRange
+	for _, id := range rosterIDs {
+		crewWindows = append(crewWindows, windows[id]...)
+	}
+	for _, window := range MergeTimeRanges(crewWindows) {
 		slot := AvailabilitySlot{
 			CrewID:    &crewID,
-			StartTime: businessStart,
-			EndTime:   businessEnd,
+			StartTime: window.Start,
+			EndTime:   window.End,
 			Capacity:  1,
 		}
 		slots = append(slots, slot)
 	}
 
+	// Report the holidays once for the crew rather than once per member
+	for _, conflict := range offConflicts[rosterIDs[0]] {
+		if strings.HasPrefix(conflict.Reason, "Holiday") {
+			conflict.ResourceID = crewID
+			conflict.ResourceType = "crew"
+			conflicts = append(conflicts, conflict)
+		}
+	}
+
+	return slots, conflicts, nil
+}
+
+func (s *SchedulingServiceImpl) checkEquipmentAvailability(ctx context.Context, tenantID, equipmentID uuid.UUID, timeRange TimeRange) ([]AvailabilitySlot, []AvailabilityConflict, error) {
+	reservations, err := s.reservationRepo.ListReservations(ctx, tenantID, []uuid.UUID{equipmentID}, timeRange.Start, timeRange.End)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get equipment reservations: %w", err)
+	}
+
+	downtime, err := s.workOrderRepo.ListDowntime(ctx, tenantID, []uuid.UUID{equipmentID}, timeRange.Start, timeRange.End)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get equipment downtime: %w", err)
+	}
+
+	slots := make([]AvailabilitySlot, 0)
+	conflicts := make([]AvailabilityConflict, 0, len(reservations)+len(downtime))
+
+	for _, reservation := range reservations {
+		reason := "Reserved"
+		if reservation.JobID != nil {
+			reason = fmt.Sprintf("Reserved for job: %s", reservation.JobID)
+		} else if reservation.Notes != nil {
+			reason = fmt.Sprintf("Reserved: %s", *reservation.Notes)
+		}
+
+		conflicts = append(conflicts, AvailabilityConflict{
+			ResourceID:   equipmentID,
+			ResourceType: "equipment",
+			ConflictTime: TimeRange{
+				Start: reservation.StartsAt,
+				End:   reservation.EndsAt,
+			},
+			Reason: reason,
+		})
+	}
+
+	now := time.Now()
+	for _, workOrder := range downtime {
+		window, ok := DowntimeWindow(workOrder, now, timeRange.End)
+		if !ok {
+			continue
+		}
+		conflicts = append(conflicts, AvailabilityConflict{
+			ResourceID:   equipmentID,
+			ResourceType: "equipment",
+			ConflictTime: window,
+			Reason:       fmt.Sprintf("Out of service: %s", workOrder.Title),
+		})
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].ConflictTime.Start.Before(conflicts[j].ConflictTime.Start)
+	})
+
+	// The gaps between reservations and downtime are free slots
+	free := timeRange.Start
+	for _, conflict := range conflicts {
+		if conflict.ConflictTime.Start.After(free) {
+			slots = append(slots, AvailabilitySlot{
+				EquipmentID: &equipmentID,
+				StartTime:   free,
+				EndTime:     conflict.ConflictTime.Start,
+				Capacity:    1,
+			})
+		}
+		if conflict.ConflictTime.End.After(free) {
+			free = conflict.ConflictTime.End
+		}
+	}
+
+	if timeRange.End.After(free) {
+		slots = append(slots, AvailabilitySlot{
+			EquipmentID: &equipmentID,
+			StartTime:   free,
+			EndTime:     timeRange.End,
+			Capacity:    1,
+		})
+	}
+
 	return slots, conflicts, nil
 }
 
@@ -787,6 +1009,7 @@ type ScheduleResources struct {
 	Users     []ResourceAvailability
 	Crews     []ResourceAvailability
 	Equipment []ResourceAvailability
+	Holidays  []TimeRange
 }
 
 type ResourceAvailability struct {
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// SearchService runs the tenant-wide search behind the web UI's command palette. It
+// matches customers, properties, jobs, quotes and invoices by word prefix (Postgres
+// full text) and by trigram similarity, so partial words and small typos still find
+// the record, and ranks everything in one list.
+type SearchService interface {
+	Search(ctx context.Context, req *GlobalSearchRequest) (*GlobalSearchResponse, error)
+}
+
+// SearchRepository defines data access for global search
+type SearchRepository interface {
+	// Search returns up to query.Limit matches for each of query.Types, with their
+	// full-text rank and trigram similarity set
+	Search(ctx context.Context, tenantID uuid.UUID, query *SearchQuery) ([]*SearchResult, error)
+}
+
+// Searchable record types
+const (
+	SearchTypeCustomer = "customer"
+	SearchTypeProperty = "property"
+	SearchTypeJob      = "job"
+	SearchTypeQuote    = "quote"
+	SearchTypeInvoice  = "invoice"
+)
+
+// SearchTypes lists the searchable record types. Results with equal scores are
+// listed in this order.
+var SearchTypes = []string{
+	SearchTypeCustomer, SearchTypeProperty, SearchTypeJob, SearchTypeQuote, SearchTypeInvoice,
+}
+
+// Search query limits
+const (
+	SearchMinQueryLength = 2
+	SearchMaxQueryLength = 100
+	SearchDefaultLimit   = 20
+	SearchMaxLimit       = 50
+)
+
+// GlobalSearchRequest is a search across record types. Types defaults to all of them.
+type GlobalSearchRequest struct {
+	Query string   `json:"query"`
+	Types []string `json:"types,omitempty"`
+	Limit int      `json:"limit,omitempty"`
+}
+
+// GlobalSearchResponse holds the ranked results of a search
+type GlobalSearchResponse struct {
+	Query   string          `json:"query"`
+	Results []*SearchResult `json:"results"`
+}
+
+// SearchQuery is a normalized search handed to the repository
+type SearchQuery struct {
+	Text    string   // normalized query, for trigram matching
+	TSQuery string   // prefix tsquery built by BuildPrefixTSQuery
+	Types   []string // record types to search
+	Limit   int      // maximum matches per type
+}
+
+// SearchResult is one matching record
+type SearchResult struct {
+	Type       string     `json:"type"`
+	ID         uuid.UUID  `json:"id"`
+	Title      string     `json:"title"`
+	Subtitle   string     `json:"subtitle,omitempty"`
+	Number     string     `json:"number,omitempty"` // job, quote or invoice number
+	Status     string     `json:"status,omitempty"`
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"`
+	Score      float64    `json:"score"`
+	TextRank   float64    `json:"-"` // ts_rank of the prefix query
+	Similarity float64    `json:"-"` // trigram word similarity of the query
+}
+
+// searchServiceImpl implements SearchService
+type searchServiceImpl struct {
+	searchRepo SearchRepository
+	logger     *log.Logger
+}
+
+// NewSearchService creates a new global search service
+func NewSearchService(searchRepo SearchRepository, logger *log.Logger) SearchService {
+	return &searchServiceImpl{
+		searchRepo: searchRepo,
+		logger:     logger,
+	}
+}
+
+// Search finds records of the requested types matching the query, best first
+func (s *searchServiceImpl) Search(ctx context.Context, req *GlobalSearchRequest) (*GlobalSearchResponse, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+	if req == nil {
+		req = &GlobalSearchRequest{}
+	}
+
+	text := NormalizeSearchQuery(req.Query)
+	if len([]rune(text)) < SearchMinQueryLength {
+		return nil, fmt.Errorf("validation failed: search query must be at least %d characters", SearchMinQueryLength)
+	}
+	tsQuery := BuildPrefixTSQuery(text)
+	if tsQuery == "" {
+		return nil, fmt.Errorf("validation failed: search query must contain a letter or digit")
+	}
+
+	types, err := validateSearchTypes(req.Types)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// Set defaults
+	limit := req.Limit
+	if limit <= 0 {
+		limit = SearchDefaultLimit
+	}
+	if limit > SearchMaxLimit {
+		limit = SearchMaxLimit
+	}
+
+	results, err := s.searchRepo.Search(ctx, tenantID, &SearchQuery{
+		Text:    text,
+		TSQuery: tsQuery,
+		Types:   types,
+		Limit:   limit,
+	})
+	if err != nil {
+		s.logger.Printf("Failed to search: %v", err)
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+
+	return &GlobalSearchResponse{
+		Query:   text,
+		Results: RankSearchResults(text, results, limit),
+	}, nil
+}
+
+// NormalizeSearchQuery lowercases a query, collapses whitespace and trims it to
+// SearchMaxQueryLength characters
+func NormalizeSearchQuery(query string) string {
+	query = strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	if runes := []rune(query); len(runes) > SearchMaxQueryLength {
+		query = strings.TrimSpace(string(runes[:SearchMaxQueryLength]))
+	}
+	return query
+}
+
+// SearchTerms splits a query into its words, dropping punctuation: "INV-1042" gives
+// "inv" and "1042"
+func SearchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// BuildPrefixTSQuery builds a to_tsquery expression matching every word of the query
+// as a prefix, so "oak dr" matches "Oakwood Drive". Only letters and digits reach the
+// expression, so user input can't inject tsquery operators. An empty result means
+// the query has no searchable words.
+func BuildPrefixTSQuery(query string) string {
+	terms := SearchTerms(query)
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
+
+// ParseSearchTypes parses a comma-separated list of record types. An empty list
+// means every type.
+func ParseSearchTypes(raw string) ([]string, error) {
+	var types []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(strings.ToLower(part)); part != "" {
+			types = append(types, part)
+		}
+	}
+	return validateSearchTypes(types)
+}
+
+// ScoreSearchResult combines a result's trigram similarity and full-text rank into
+// one score, boosting records whose number or title is the query exactly or starts
+// with it
+func ScoreSearchResult(query string, result *SearchResult) float64 {
+	score := result.Similarity + math.Min(result.TextRank, 1)*0.5
+
+	query = NormalizeSearchQuery(query)
+	for _, value := range []string{result.Number, result.Title} {
+		value = NormalizeSearchQuery(value)
+		if value == "" {
+			continue
+		}
+		if value == query {
+			return score + 1
+		}
+		if strings.HasPrefix(value, query) {
+			score += 0.5
+			break
+		}
+	}
+	return score
+}
+
+// RankSearchResults scores the results, sorts them best first and keeps the first
+// limit. Ties are broken by record type, then title.
+func RankSearchResults(query string, results []*SearchResult, limit int) []*SearchResult {
+	typeOrder := make(map[string]int, len(SearchTypes))
+	for i, searchType := range SearchTypes {
+		typeOrder[searchType] = i
+	}
+
+	ranked := make([]*SearchResult, 0, len(results))
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		result.Score = math.Round(ScoreSearchResult(query, result)*1000) / 1000
+		ranked = append(ranked, result)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		if typeOrder[ranked[i].Type] != typeOrder[ranked[j].Type] {
+			return typeOrder[ranked[i].Type] < typeOrder[ranked[j].Type]
+		}
+		return strings.ToLower(ranked[i].Title) < strings.ToLower(ranked[j].Title)
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// Helper functions
+
+func validateSearchTypes(types []string) ([]string, error) {
+	if len(types) == 0 {
+		return append([]string(nil), SearchTypes...), nil
+	}
+
+	var valid []string
+	for _, searchType := range types {
+		if !containsString(SearchTypes, searchType) {
+			return nil, fmt.Errorf("unknown search type %q", searchType)
+		}
+		if !containsString(valid, searchType) {
+			valid = append(valid, searchType)
+		}
+	}
+	return valid, nil
+}
@@ -184,6 +184,12 @@ type JobService interface {
 	
 	// Route optimization
 	OptimizeJobRoute(ctx context.Context, jobIDs []uuid.UUID, date time.Time) (*RouteOptimization, error)
+
+	// Job costing
+	GetJobCosting(ctx context.Context, jobID uuid.UUID) (*JobCosting, error)
+
+	// Qualifications
+	CheckJobQualifications(ctx context.Context, jobID uuid.UUID, userID, crewID *uuid.UUID) (*QualificationCheck, error)
 }
 
 // QuoteService handles quote management
@@ -197,9 +203,19 @@ type QuoteService interface {
 	
 	// Quote lifecycle
 	ApproveQuote(ctx context.Context, quoteID uuid.UUID) error
+	ApproveQuoteWithSelection(ctx context.Context, quoteID uuid.UUID, selection *QuoteSelectionRequest) error
+	ApproveQuoteWithSignature(ctx context.Context, quoteID uuid.UUID, selection *QuoteSelectionRequest, signature *domain.QuoteSignature) error
 	RejectQuote(ctx context.Context, quoteID uuid.UUID, reason string) error
 	ConvertQuoteToJob(ctx context.Context, quoteID uuid.UUID) (*domain.EnhancedJob, error)
 	
+	// Quote revisions
+	ListQuoteVersions(ctx context.Context, quoteID uuid.UUID) ([]*domain.QuoteVersion, error)
+	GetQuoteVersion(ctx context.Context, quoteID uuid.UUID, versionNumber int) (*domain.QuoteVersion, error)
+	CompareQuoteVersions(ctx context.Context, quoteID uuid.UUID, fromVersion, toVersion int) (*QuoteVersionDiff, error)
+
+	// Quote options
+	GetQuotePricing(ctx context.Context, quoteID uuid.UUID) (*QuotePricing, error)
+	
 	// Quote generation
 	GenerateQuotePDF(ctx context.Context, quoteID uuid.UUID) ([]byte, error)
 	SendQuote(ctx context.Context, quoteID uuid.UUID, sendOptions *QuoteSendOptions) error
@@ -268,6 +284,59 @@ type EquipmentService interface {
 	ScheduleMaintenance(ctx context.Context, equipmentID uuid.UUID, req *MaintenanceScheduleRequest) error
 	GetMaintenanceHistory(ctx context.Context, equipmentID uuid.UUID) ([]*MaintenanceRecord, error)
 	GetUpcomingMaintenance(ctx context.Context) ([]*MaintenanceSchedule, error)
+	PerformMaintenance(ctx context.Context, equipmentID uuid.UUID, maintenanceType string, cost *float64, notes *string) error
+	CheckMaintenanceDue(ctx context.Context) ([]*domain.Equipment, error)
+	
+	// Usage meters and usage-based maintenance
+	RecordMeterReading(ctx context.Context, equipmentID uuid.UUID, req *MeterReadingRequest) (*domain.EquipmentMeterReading, error)
+	GetMeterReadings(ctx context.Context, equipmentID uuid.UUID, meter string, limit int) ([]*domain.EquipmentMeterReading, error)
+	GetUsageTriggers(ctx context.Context, equipmentID uuid.UUID) ([]*domain.MaintenanceUsageTrigger, error)
+	SetUsageTriggers(ctx context.Context, equipmentID uuid.UUID, reqs []*UsageTriggerRequest) ([]*domain.MaintenanceUsageTrigger, error)
+	GetMaintenanceForecast(ctx context.Context, horizonDays int) ([]*MaintenanceForecast, error)
+	
+	// Reservations
+	ReserveEquipment(ctx context.Context, equipmentID uuid.UUID, req *EquipmentReservationRequest) (*domain.EquipmentReservation, error)
+	GetEquipmentReservations(ctx context.Context, equipmentID uuid.UUID, start, end time.Time) ([]*domain.EquipmentReservation, error)
+	CancelEquipmentReservation(ctx context.Context, reservationID uuid.UUID) error
+	
+	// Maintenance work orders and parts inventory
+	OpenWorkOrder(ctx context.Context, equipmentID uuid.UUID, req *WorkOrderRequest) (*domain.MaintenanceWorkOrder, error)
+	GetWorkOrder(ctx context.Context, workOrderID uuid.UUID) (*domain.MaintenanceWorkOrder, error)
+	ListWorkOrders(ctx context.Context, filter *WorkOrderFilter) ([]*domain.MaintenanceWorkOrder, error)
+	StartWorkOrder(ctx context.Context, workOrderID uuid.UUID) (*domain.MaintenanceWorkOrder, error)
+	CloseWorkOrder(ctx context.Context, workOrderID uuid.UUID, req *WorkOrderCloseRequest) (*domain.MaintenanceWorkOrder, error)
+	AddWorkOrderPart(ctx context.Context, workOrderID uuid.UUID, req *WorkOrderPartRequest) (*domain.MaintenanceWorkOrder, error)
+	AddWorkOrderLabor(ctx context.Context, workOrderID uuid.UUID, req *WorkOrderLaborRequest) (*domain.MaintenanceWorkOrder, error)
+	AddVendorRepair(ctx context.Context, workOrderID uuid.UUID, req *VendorRepairRequest) (*domain.MaintenanceWorkOrder, error)
+	CreatePart(ctx context.Context, req *PartRequest) (*domain.Part, error)
+	UpdatePart(ctx context.Context, partID uuid.UUID, req *PartUpdateRequest) (*domain.Part, error)
+	ListParts(ctx context.Context, lowStockOnly bool) ([]*domain.Part, error)
+
+	// Fixed asset depreciation, cost of ownership and replacement
+	GetAssetProfile(ctx context.Context, equipmentID uuid.UUID) (*domain.EquipmentAssetProfile, error)
+	SetAssetProfile(ctx context.Context, equipmentID uuid.UUID, req *EquipmentAssetProfileRequest) (*domain.EquipmentAssetProfile, error)
+	DisposeEquipment(ctx context.Context, equipmentID uuid.UUID, req *EquipmentDisposalRequest) (*domain.EquipmentAssetProfile, error)
+	GetDepreciationSchedule(ctx context.Context, equipmentID uuid.UUID) (*DepreciationSchedule, error)
+	GetTotalCostOfOwnership(ctx context.Context, equipmentID uuid.UUID, startDate, endDate time.Time) (*TotalCostOfOwnership, error)
+	AnalyzeReplaceOrRepair(ctx context.Context, equipmentID uuid.UUID, req *ReplaceOrRepairRequest) (*ReplaceOrRepairAnalysis, error)
+	GetFixedAssetRegister(ctx context.Context, year int) (*FixedAssetRegister, error)
+	ExportFixedAssetRegister(ctx context.Context, year int) (*FixedAssetRegisterExport, error)
+
+	// Fuel logging and efficiency
+	RecordFuelEntry(ctx context.Context, equipmentID uuid.UUID, req *FuelEntryRequest) (*domain.FuelEntry, error)
+	ListFuelEntries(ctx context.Context, equipmentID uuid.UUID, startDate, endDate *time.Time) ([]*domain.FuelEntry, error)
+	ListFuelAnomalies(ctx context.Context, startDate, endDate *time.Time) ([]*domain.FuelEntry, error)
+	GetFuelEfficiencyReport(ctx context.Context, equipmentID uuid.UUID, startDate, endDate time.Time) (*FuelEfficiencyReport, error)
+
+	// Asset tags, checkout and check-in
+	GetAssetTag(ctx context.Context, equipmentID uuid.UUID) (*domain.EquipmentAssetTag, error)
+	GenerateAssetLabels(ctx context.Context, equipmentIDs []uuid.UUID) (*AssetLabelSheet, error)
+	ScanAssetTag(ctx context.Context, code string) (*EquipmentWhereabouts, error)
+	CheckOutEquipment(ctx context.Context, req *EquipmentCheckoutRequest) (*domain.EquipmentCheckout, error)
+	CheckInEquipment(ctx context.Context, req *EquipmentCheckinRequest) (*domain.EquipmentCheckout, error)
+	GetEquipmentWhereabouts(ctx context.Context, filter *EquipmentWhereaboutsFilter) ([]*EquipmentWhereabouts, error)
+	GetCheckoutHistory(ctx context.Context, equipmentID uuid.UUID, limit int) ([]*domain.EquipmentCheckout, error)
+	ProcessOverdueCheckouts(ctx context.Context, now time.Time) error
 }
 
 // CrewService handles crew management
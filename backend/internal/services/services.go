@@ -33,6 +33,25 @@ type Services struct {
 	LLM          LLMService
 	Communication CommunicationService
 	Schedule     ScheduleService
+	Statement    StatementService
+	Collections  CollectionsService
+	Accounting   AccountingService
+	AccountingSyncAdapters []AccountingSyncAdapter
+	Pricing      PricingService
+	Portal       PortalService
+	Lead         LeadService
+	Pipeline     PipelineService
+	Booking      BookingService
+	Import       ImportService
+	CustomerMerge CustomerMergeService
+	Search       SearchService
+	Conversation ConversationService
+	MessagingProvider MessagingProvider
+	SiteMap      SiteMapService
+	Certification CertificationService
+	Availability  AvailabilityService
+	Dispatch      DispatchService
+	Payroll       PayrollService
 	// File and Email services not yet defined
 }
 
@@ -41,7 +60,9 @@ type Services struct {
 func NewServices(config *config.Config) *Services { // Simplified version without repositories
 	// TODO: Initialize external service clients when integrations are available
 	// For now, set to nil to prevent compilation errors
-	
+	accountingSyncAdapters := NewAccountingSyncAdapters(config)
+	messagingProvider := NewMessagingProvider(config)
+
 	return &Services{
 		// Auth:      NewAuthService(repos, config), // Temporarily commented - requires repos
 		// User:      NewUserService(repos), // Temporarily commented - requires repos
@@ -52,9 +73,57 @@ func NewServices(config *config.Config) *Services { // Simplified version withou
 		// Invoice:   NewInvoiceService(repos), // Temporarily commented - requires repos
 		// Payment:   NewPaymentService(repos, config), // Temporarily commented - requires repos
 		// Equipment: NewEquipmentService(repos), // Temporarily commented - requires repos
+		// Statement: NewStatementService(repos), // Temporarily commented - requires repos
+		// Collections: NewCollectionsService(repos), // Temporarily commented - requires repos
+		// Accounting: NewAccountingService(repos, accountingSyncAdapters...), // Temporarily commented - requires repos
+		AccountingSyncAdapters: accountingSyncAdapters,
+		// Pricing:   NewPricingService(repos), // Temporarily commented - requires repos
+		// Portal:    NewPortalService(repos), // Temporarily commented - requires repos
+		// Lead:      NewLeadService(repos), // Temporarily commented - requires repos
+		// Pipeline:  NewPipelineService(repos), // Temporarily commented - requires repos
+		// Report:    NewPipelineReportService(nil, pipeline), // Temporarily commented - requires repos
+		// Booking:   NewBookingService(repos), // Temporarily commented - requires repos
+		// Import:    NewImportService(repos), // Temporarily commented - requires repos
+		// CustomerMerge: NewCustomerMergeService(repos), // Temporarily commented - requires repos
+		// Search:    NewSearchService(repos), // Temporarily commented - requires repos
+		// Conversation: NewConversationService(repos, messagingProvider), // Temporarily commented - requires repos
+		MessagingProvider: messagingProvider,
+		// SiteMap:   NewSiteMapService(repos), // Temporarily commented - requires repos
+		// Certification: NewCertificationService(repos), // Temporarily commented - requires repos
+		// Availability: NewAvailabilityService(repos), // Temporarily commented - requires repos
+		// Dispatch:  NewDispatchService(repos), // Temporarily commented - requires repos
+		// Payroll:   NewPayrollService(repos), // Temporarily commented - requires repos
+		// Crew:      NewAvailabilityCrewService(NewCrewService(repos), repos.Availability), // Temporarily commented - requires repos
 		// File:      NewFileService(repos, storageService), // Temporarily commented - requires repos
 		// Email:     nil, // TODO: Implement when email service is available
 		Storage:   nil, // TODO: Implement when storage service is available
 		LLM:       nil, // TODO: Implement when LLM service is available
 	}
 }
+
+// NewMessagingProvider creates the comms provider selected by MESSAGING_PROVIDER. The
+// local provider only records messages and is for development and tests.
+func NewMessagingProvider(config *config.Config) MessagingProvider {
+	if config.MessagingProvider == "twilio" {
+		return NewTwilioMessagingProvider(TwilioMessagingConfig{
+			APIURL:               config.TwilioAPIURL,
+			AccountSID:           config.TwilioAccountSID,
+			AuthToken:            config.TwilioAuthToken,
+			SMSFrom:              config.SMSFromNumber,
+			SendGridAPIURL:       config.SendGridAPIURL,
+			SendGridAPIKey:       config.SendGridAPIKey,
+			EmailFrom:            config.SMTPFromEmail,
+			EmailFromName:        config.SMTPFromName,
+			InboundParsePassword: config.SendGridInboundParsePassword,
+			WebhookURL:           config.CommsWebhookURL,
+		}, nil)
+	}
+	return NewLocalMessagingProvider(config.CommsWebhookSecret, config.SMSFromNumber, config.SMTPFromEmail)
+}
+
+// NewAccountingSyncAdapters creates the accounting providers tenants can connect and sync to
+func NewAccountingSyncAdapters(config *config.Config) []AccountingSyncAdapter {
+	return []AccountingSyncAdapter{
+		NewQuickBooksSyncAdapter(config.QuickBooksAPIURL, QuickBooksTokenURL, config.QuickBooksClientID, config.QuickBooksClientSecret, nil),
+	}
+}
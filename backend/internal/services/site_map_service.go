@@ -0,0 +1,604 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// SiteMapService manages a property's site map: the turf zones, beds, trees,
+// irrigation zones, hardscape and obstacles measured on it. Maps are edited as
+// GeoJSON, and the measured areas feed square-foot pricing.
+type SiteMapService interface {
+	GetSiteMap(ctx context.Context, propertyID uuid.UUID) (*SiteMap, error)
+	ExportSiteMap(ctx context.Context, propertyID uuid.UUID) (*GeoJSONFeatureCollection, error)
+	ImportSiteMap(ctx context.Context, propertyID uuid.UUID, collection *GeoJSONFeatureCollection) (*SiteMap, error)
+
+	AddFeature(ctx context.Context, propertyID uuid.UUID, req *PropertyFeatureRequest) (*domain.PropertyFeature, error)
+	UpdateFeature(ctx context.Context, featureID uuid.UUID, req *PropertyFeatureRequest) (*domain.PropertyFeature, error)
+	DeleteFeature(ctx context.Context, featureID uuid.UUID) error
+}
+
+// SiteMapRepository defines data access for property features
+type SiteMapRepository interface {
+	CreateFeature(ctx context.Context, feature *domain.PropertyFeature) error
+	GetFeature(ctx context.Context, tenantID, featureID uuid.UUID) (*domain.PropertyFeature, error)
+	UpdateFeature(ctx context.Context, feature *domain.PropertyFeature) error
+	DeleteFeature(ctx context.Context, tenantID, featureID uuid.UUID) error
+	ListFeatures(ctx context.Context, tenantID, propertyID uuid.UUID) ([]*domain.PropertyFeature, error)
+
+	// ReplaceFeatures swaps a property's features for a new set in one transaction
+	ReplaceFeatures(ctx context.Context, tenantID, propertyID uuid.UUID, features []*domain.PropertyFeature) error
+
+	// GetMeasuredAreas totals a property's feature areas in square feet by feature type
+	GetMeasuredAreas(ctx context.Context, tenantID, propertyID uuid.UUID) (map[string]float64, error)
+}
+
+// Site map limits
+const (
+	maxSiteMapFeatures = 500
+	maxFeatureVertices = 5000
+)
+
+// earthRadiusFeet matches the mean radius haversineDistance uses
+const earthRadiusFeet = 3959 * 5280
+
+// PropertyFeatureRequest creates or updates a feature. Geometry is a GeoJSON
+// Polygon or MultiPolygon in longitude/latitude.
+type PropertyFeatureRequest struct {
+	FeatureType string          `json:"feature_type" validate:"required"`
+	Name        *string         `json:"name,omitempty"`
+	Geometry    json.RawMessage `json:"geometry" validate:"required"`
+	Notes       *string         `json:"notes,omitempty"`
+}
+
+// SiteMap is a property's features with their measured totals
+type SiteMap struct {
+	PropertyID uuid.UUID                 `json:"property_id"`
+	Features   []*domain.PropertyFeature `json:"features"`
+	Totals     []SiteMapAreaTotal        `json:"totals"`
+}
+
+// SiteMapAreaTotal is the measured total for one feature type
+type SiteMapAreaTotal struct {
+	FeatureType  string  `json:"feature_type"`
+	FeatureCount int     `json:"feature_count"`
+	AreaSqFt     float64 `json:"area_sq_ft"`
+	PerimeterFt  float64 `json:"perimeter_ft"`
+}
+
+// GeoJSONFeatureCollection is a site map in GeoJSON. Each feature's properties
+// carry feature_type, and optionally name and notes; exports add the feature's id,
+// area_sq_ft and perimeter_ft.
+type GeoJSONFeatureCollection struct {
+	Type     string            `json:"type"`
+	Features []*GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature is one feature of a GeoJSON feature collection
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   json.RawMessage        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// geoJSONGeometry is the part of a GeoJSON geometry site maps read
+type geoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// SiteMapServiceImpl implements SiteMapService
+type SiteMapServiceImpl struct {
+	siteMapRepo  SiteMapRepository
+	propertyRepo PropertyRepositoryExtended
+	auditService AuditService
+	logger       *log.Logger
+}
+
+// NewSiteMapService creates a new site map service
+func NewSiteMapService(
+	siteMapRepo SiteMapRepository,
+	propertyRepo PropertyRepositoryExtended,
+	auditService AuditService,
+	logger *log.Logger,
+) SiteMapService {
+	return &SiteMapServiceImpl{
+		siteMapRepo:  siteMapRepo,
+		propertyRepo: propertyRepo,
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// GetSiteMap returns a property's features and their totals by type
+func (s *SiteMapServiceImpl) GetSiteMap(ctx context.Context, propertyID uuid.UUID) (*SiteMap, error) {
+	tenantID, err := s.checkProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	features, err := s.siteMapRepo.ListFeatures(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list property features: %w", err)
+	}
+
+	return &SiteMap{
+		PropertyID: propertyID,
+		Features:   features,
+		Totals:     SummarizeSiteMap(features),
+	}, nil
+}
+
+// ExportSiteMap returns a property's site map as a GeoJSON feature collection
+func (s *SiteMapServiceImpl) ExportSiteMap(ctx context.Context, propertyID uuid.UUID) (*GeoJSONFeatureCollection, error) {
+	siteMap, err := s.GetSiteMap(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	return SiteMapToGeoJSON(siteMap.Features), nil
+}
+
+// ImportSiteMap replaces a property's site map with the features of a GeoJSON
+// feature collection. Nothing is saved unless every feature is valid.
+func (s *SiteMapServiceImpl) ImportSiteMap(ctx context.Context, propertyID uuid.UUID, collection *GeoJSONFeatureCollection) (*SiteMap, error) {
+	tenantID, err := s.checkProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	reqs, err := ParseSiteMapGeoJSON(collection)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	now := time.Now()
+	features := make([]*domain.PropertyFeature, 0, len(reqs))
+	for i, req := range reqs {
+		feature := &domain.PropertyFeature{
+			ID:         uuid.New(),
+			TenantID:   tenantID,
+			PropertyID: propertyID,
+			CreatedBy:  GetUserIDFromContext(ctx),
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if err := applyPropertyFeatureRequest(feature, req); err != nil {
+			return nil, fmt.Errorf("validation failed: feature %d: %w", i+1, err)
+		}
+		features = append(features, feature)
+	}
+
+	previous, err := s.siteMapRepo.ListFeatures(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list property features: %w", err)
+	}
+
+	if err := s.siteMapRepo.ReplaceFeatures(ctx, tenantID, propertyID, features); err != nil {
+		return nil, fmt.Errorf("failed to import site map: %w", err)
+	}
+
+	s.logSiteMapAction(ctx, "site_map.import", "property", propertyID,
+		siteMapAuditValues(previous), siteMapAuditValues(features))
+
+	return &SiteMap{
+		PropertyID: propertyID,
+		Features:   features,
+		Totals:     SummarizeSiteMap(features),
+	}, nil
+}
+
+// AddFeature adds a feature to a property's site map
+func (s *SiteMapServiceImpl) AddFeature(ctx context.Context, propertyID uuid.UUID, req *PropertyFeatureRequest) (*domain.PropertyFeature, error) {
+	tenantID, err := s.checkProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	feature := &domain.PropertyFeature{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		PropertyID: propertyID,
+		CreatedBy:  GetUserIDFromContext(ctx),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := applyPropertyFeatureRequest(feature, req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if err := s.siteMapRepo.CreateFeature(ctx, feature); err != nil {
+		return nil, fmt.Errorf("failed to create property feature: %w", err)
+	}
+
+	s.logSiteMapAction(ctx, "property_feature.create", "property_feature", feature.ID, nil, propertyFeatureAuditValues(feature))
+
+	return feature, nil
+}
+
+// UpdateFeature replaces a feature's type, name, notes and geometry and remeasures it
+func (s *SiteMapServiceImpl) UpdateFeature(ctx context.Context, featureID uuid.UUID, req *PropertyFeatureRequest) (*domain.PropertyFeature, error) {
+	feature, err := s.getFeature(ctx, featureID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldValues := propertyFeatureAuditValues(feature)
+	if err := applyPropertyFeatureRequest(feature, req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	feature.UpdatedAt = time.Now()
+
+	if err := s.siteMapRepo.UpdateFeature(ctx, feature); err != nil {
+		return nil, fmt.Errorf("failed to update property feature: %w", err)
+	}
+
+	s.logSiteMapAction(ctx, "property_feature.update", "property_feature", feature.ID, oldValues, propertyFeatureAuditValues(feature))
+
+	return feature, nil
+}
+
+// DeleteFeature removes a feature from its site map
+func (s *SiteMapServiceImpl) DeleteFeature(ctx context.Context, featureID uuid.UUID) error {
+	feature, err := s.getFeature(ctx, featureID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.siteMapRepo.DeleteFeature(ctx, feature.TenantID, featureID); err != nil {
+		return fmt.Errorf("failed to delete property feature: %w", err)
+	}
+
+	s.logSiteMapAction(ctx, "property_feature.delete", "property_feature", feature.ID, propertyFeatureAuditValues(feature), nil)
+
+	return nil
+}
+
+// checkProperty returns the tenant ID once the property is known to exist
+func (s *SiteMapServiceImpl) checkProperty(ctx context.Context, propertyID uuid.UUID) (uuid.UUID, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return uuid.Nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	property, err := s.propertyRepo.GetByID(ctx, tenantID, propertyID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get property: %w", err)
+	}
+	if property == nil {
+		return uuid.Nil, fmt.Errorf("property not found")
+	}
+
+	return tenantID, nil
+}
+
+func (s *SiteMapServiceImpl) getFeature(ctx context.Context, featureID uuid.UUID) (*domain.PropertyFeature, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	feature, err := s.siteMapRepo.GetFeature(ctx, tenantID, featureID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get property feature: %w", err)
+	}
+	if feature == nil {
+		return nil, fmt.Errorf("property feature not found")
+	}
+
+	return feature, nil
+}
+
+func (s *SiteMapServiceImpl) logSiteMapAction(ctx context.Context, action, resourceType string, resourceID uuid.UUID, oldValues, newValues map[string]interface{}) {
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   &resourceID,
+		OldValues:    oldValues,
+		NewValues:    newValues,
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+}
+
+// MeasureGeometry validates a GeoJSON Polygon or MultiPolygon and returns its area in
+// square feet and perimeter in feet, measured on a spherical earth. Holes are
+// subtracted from the area and their edges count toward the perimeter. The returned
+// geometry is the input reduced to its type and coordinates.
+func MeasureGeometry(geometry json.RawMessage) (json.RawMessage, float64, float64, error) {
+	var parsed geoJSONGeometry
+	if len(geometry) == 0 || string(geometry) == "null" {
+		return nil, 0, 0, fmt.Errorf("geometry is required")
+	}
+	if err := json.Unmarshal(geometry, &parsed); err != nil {
+		return nil, 0, 0, fmt.Errorf("invalid geometry: %w", err)
+	}
+
+	var polygons [][][][]float64
+	switch parsed.Type {
+	case "Polygon":
+		var polygon [][][]float64
+		if err := json.Unmarshal(parsed.Coordinates, &polygon); err != nil {
+			return nil, 0, 0, fmt.Errorf("invalid polygon coordinates: %w", err)
+		}
+		polygons = [][][][]float64{polygon}
+	case "MultiPolygon":
+		if err := json.Unmarshal(parsed.Coordinates, &polygons); err != nil {
+			return nil, 0, 0, fmt.Errorf("invalid multipolygon coordinates: %w", err)
+		}
+		if len(polygons) == 0 {
+			return nil, 0, 0, fmt.Errorf("multipolygon has no polygons")
+		}
+	default:
+		return nil, 0, 0, fmt.Errorf("geometry must be a Polygon or MultiPolygon, got %q", parsed.Type)
+	}
+
+	area, perimeter, vertices := 0.0, 0.0, 0
+	for _, polygon := range polygons {
+		if len(polygon) == 0 {
+			return nil, 0, 0, fmt.Errorf("polygon has no rings")
+		}
+		for i, ring := range polygon {
+			if err := validateRing(ring); err != nil {
+				return nil, 0, 0, err
+			}
+			vertices += len(ring)
+
+			ringSize := ringArea(ring)
+			if i == 0 {
+				area += ringSize
+			} else {
+				area -= ringSize
+			}
+			perimeter += ringLength(ring)
+		}
+	}
+	if vertices > maxFeatureVertices {
+		return nil, 0, 0, fmt.Errorf("geometry has more than %d vertices", maxFeatureVertices)
+	}
+	if area <= 0 {
+		return nil, 0, 0, fmt.Errorf("geometry has no area")
+	}
+
+	normalized, err := json.Marshal(&geoJSONGeometry{Type: parsed.Type, Coordinates: parsed.Coordinates})
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to encode geometry: %w", err)
+	}
+
+	return normalized, roundMeasurement(area), roundMeasurement(perimeter), nil
+}
+
+// ValidatePropertyFeatureRequest checks a feature's type and geometry
+func ValidatePropertyFeatureRequest(req *PropertyFeatureRequest) error {
+	if err := validatePropertyFeatureFields(req); err != nil {
+		return err
+	}
+	_, _, _, err := MeasureGeometry(req.Geometry)
+	return err
+}
+
+// ParseSiteMapGeoJSON reads the features of a GeoJSON feature collection. Feature
+// type comes from each feature's feature_type property.
+func ParseSiteMapGeoJSON(collection *GeoJSONFeatureCollection) ([]*PropertyFeatureRequest, error) {
+	if collection == nil || collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("site map must be a GeoJSON FeatureCollection")
+	}
+	if len(collection.Features) > maxSiteMapFeatures {
+		return nil, fmt.Errorf("site map cannot have more than %d features", maxSiteMapFeatures)
+	}
+
+	reqs := make([]*PropertyFeatureRequest, 0, len(collection.Features))
+	for i, feature := range collection.Features {
+		if feature == nil || feature.Type != "Feature" {
+			return nil, fmt.Errorf("feature %d is not a GeoJSON Feature", i+1)
+		}
+
+		req := &PropertyFeatureRequest{
+			FeatureType: strings.ToLower(strings.TrimSpace(geoJSONString(feature.Properties, "feature_type"))),
+			Name:        optionalString(geoJSONString(feature.Properties, "name")),
+			Notes:       optionalString(geoJSONString(feature.Properties, "notes")),
+			Geometry:    feature.Geometry,
+		}
+		if err := ValidatePropertyFeatureRequest(req); err != nil {
+			return nil, fmt.Errorf("feature %d: %w", i+1, err)
+		}
+		reqs = append(reqs, req)
+	}
+
+	return reqs, nil
+}
+
+// SiteMapToGeoJSON converts features to a GeoJSON feature collection
+func SiteMapToGeoJSON(features []*domain.PropertyFeature) *GeoJSONFeatureCollection {
+	collection := &GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]*GeoJSONFeature, 0, len(features)),
+	}
+
+	for _, feature := range features {
+		properties := map[string]interface{}{
+			"id":           feature.ID.String(),
+			"feature_type": feature.FeatureType,
+			"area_sq_ft":   feature.AreaSqFt,
+			"perimeter_ft": feature.PerimeterFt,
+		}
+		if feature.Name != nil {
+			properties["name"] = *feature.Name
+		}
+		if feature.Notes != nil {
+			properties["notes"] = *feature.Notes
+		}
+
+		collection.Features = append(collection.Features, &GeoJSONFeature{
+			Type:       "Feature",
+			Geometry:   feature.Geometry,
+			Properties: properties,
+		})
+	}
+
+	return collection
+}
+
+// SummarizeSiteMap totals features by type, in PropertyFeatureTypes order. Types
+// without features are left out.
+func SummarizeSiteMap(features []*domain.PropertyFeature) []SiteMapAreaTotal {
+	byType := make(map[string]*SiteMapAreaTotal)
+	for _, feature := range features {
+		total, ok := byType[feature.FeatureType]
+		if !ok {
+			total = &SiteMapAreaTotal{FeatureType: feature.FeatureType}
+			byType[feature.FeatureType] = total
+		}
+		total.FeatureCount++
+		total.AreaSqFt += feature.AreaSqFt
+		total.PerimeterFt += feature.PerimeterFt
+	}
+
+	totals := []SiteMapAreaTotal{}
+	for _, featureType := range domain.PropertyFeatureTypes {
+		if total, ok := byType[featureType]; ok {
+			total.AreaSqFt = roundMeasurement(total.AreaSqFt)
+			total.PerimeterFt = roundMeasurement(total.PerimeterFt)
+			totals = append(totals, *total)
+		}
+	}
+	return totals
+}
+
+// MeasuredAreas returns the total area in square feet of each feature type on a
+// site map, as the pricing engine takes it
+func MeasuredAreas(features []*domain.PropertyFeature) map[string]float64 {
+	areas := make(map[string]float64)
+	for _, total := range SummarizeSiteMap(features) {
+		areas[total.FeatureType] = total.AreaSqFt
+	}
+	return areas
+}
+
+// Helper functions
+
+func applyPropertyFeatureRequest(feature *domain.PropertyFeature, req *PropertyFeatureRequest) error {
+	if err := validatePropertyFeatureFields(req); err != nil {
+		return err
+	}
+
+	geometry, area, perimeter, err := MeasureGeometry(req.Geometry)
+	if err != nil {
+		return err
+	}
+
+	feature.FeatureType = req.FeatureType
+	feature.Name = optionalString(stringValue(req.Name))
+	feature.Notes = optionalString(stringValue(req.Notes))
+	feature.Geometry = geometry
+	feature.AreaSqFt = area
+	feature.PerimeterFt = perimeter
+	return nil
+}
+
+func validatePropertyFeatureFields(req *PropertyFeatureRequest) error {
+	if !isPropertyFeatureType(req.FeatureType) {
+		return fmt.Errorf("feature type must be one of %s", strings.Join(domain.PropertyFeatureTypes, ", "))
+	}
+	if req.Name != nil && len(*req.Name) > 255 {
+		return fmt.Errorf("name cannot be longer than 255 characters")
+	}
+	return nil
+}
+
+// validateRing checks a linear ring is closed, has at least three distinct
+// positions and stays within longitude/latitude bounds
+func validateRing(ring [][]float64) error {
+	if len(ring) < 4 {
+		return fmt.Errorf("polygon ring needs at least four positions")
+	}
+	for _, position := range ring {
+		if len(position) < 2 {
+			return fmt.Errorf("position needs a longitude and latitude")
+		}
+		if position[0] < -180 || position[0] > 180 || position[1] < -90 || position[1] > 90 {
+			return fmt.Errorf("position [%g, %g] is not a valid longitude and latitude", position[0], position[1])
+		}
+	}
+	first, last := ring[0], ring[len(ring)-1]
+	if first[0] != last[0] || first[1] != last[1] {
+		return fmt.Errorf("polygon ring must end at its first position")
+	}
+	return nil
+}
+
+// ringArea is the area in square feet enclosed by a closed ring on a sphere
+// (Chamberlain and Duquette, "Some Algorithms for Polygons on a Sphere")
+func ringArea(ring [][]float64) float64 {
+	total := 0.0
+	for i := 0; i < len(ring)-1; i++ {
+		p1, p2 := ring[i], ring[i+1]
+		total += degreesToRadians(p2[0]-p1[0]) *
+			(2 + math.Sin(degreesToRadians(p1[1])) + math.Sin(degreesToRadians(p2[1])))
+	}
+	return math.Abs(total * earthRadiusFeet * earthRadiusFeet / 2)
+}
+
+// ringLength is the length in feet of a ring's edges
+func ringLength(ring [][]float64) float64 {
+	miles := 0.0
+	for i := 0; i < len(ring)-1; i++ {
+		miles += haversineDistance(ring[i][1], ring[i][0], ring[i+1][1], ring[i+1][0])
+	}
+	return miles * 5280
+}
+
+func degreesToRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func roundMeasurement(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func isPropertyFeatureType(featureType string) bool {
+	for _, t := range domain.PropertyFeatureTypes {
+		if t == featureType {
+			return true
+		}
+	}
+	return false
+}
+
+func geoJSONString(properties map[string]interface{}, key string) string {
+	if value, ok := properties[key].(string); ok {
+		return value
+	}
+	return ""
+}
+
+func propertyFeatureAuditValues(feature *domain.PropertyFeature) map[string]interface{} {
+	return map[string]interface{}{
+		"property_id":  feature.PropertyID,
+		"feature_type": feature.FeatureType,
+		"name":         feature.Name,
+		"area_sq_ft":   feature.AreaSqFt,
+		"perimeter_ft": feature.PerimeterFt,
+	}
+}
+
+func siteMapAuditValues(features []*domain.PropertyFeature) map[string]interface{} {
+	areas := make(map[string]interface{})
+	for featureType, area := range MeasuredAreas(features) {
+		areas[featureType] = area
+	}
+	return map[string]interface{}{
+		"feature_count": len(features),
+		"areas":         areas,
+	}
+}
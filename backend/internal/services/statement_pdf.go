@@ -0,0 +1,221 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// Statement and invoice layout in PDF points on US Letter
+const (
+	documentMargin      = 54.0
+	documentLineGap     = 4.0
+	documentSectionGap  = 14.0
+	documentTextLength  = 95 // characters of body text that fit across the page
+	documentTitleSize   = 18.0
+	documentHeadingSize = 13.0
+	documentBodySize    = 10.0
+	documentTableSize   = 9.0
+)
+
+// documentCell is text placed at an offset from the left margin
+type documentCell struct {
+	x    float64
+	text string
+}
+
+// documentPDF lays lines of text out top down, starting a new page when one fills
+type documentPDF struct {
+	pages   []string
+	content strings.Builder
+	y       float64
+}
+
+func newDocumentPDF() *documentPDF {
+	return &documentPDF{y: labelPageHeight - documentMargin}
+}
+
+// row writes one line with each cell at its offset
+func (d *documentPDF) row(font string, size float64, cells ...documentCell) {
+	if d.y-size < documentMargin {
+		d.pages = append(d.pages, d.content.String())
+		d.content.Reset()
+		d.y = labelPageHeight - documentMargin
+	}
+
+	d.y -= size
+	for _, cell := range cells {
+		if cell.text == "" {
+			continue
+		}
+		fmt.Fprintf(&d.content, "BT /%s %.0f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, documentMargin+cell.x, d.y, pdfString(cell.text))
+	}
+	d.y -= documentLineGap
+}
+
+func (d *documentPDF) title(text string) {
+	d.row("F2", documentTitleSize, documentCell{text: text})
+	d.y -= documentLineGap
+}
+
+func (d *documentPDF) heading(text string) {
+	d.y -= documentSectionGap
+	d.row("F2", documentHeadingSize, documentCell{text: text})
+}
+
+// text writes a paragraph, wrapped to the page width
+func (d *documentPDF) text(font, text string) {
+	for _, line := range wrapDocumentText(text, documentTextLength) {
+		d.row(font, documentBodySize, documentCell{text: line})
+	}
+}
+
+// table writes a bold header row and then each row, with columns at the offsets
+func (d *documentPDF) table(offsets []float64, header []string, rows [][]string) {
+	d.row("F2", documentTableSize, documentCells(offsets, header)...)
+	for _, row := range rows {
+		d.row("F1", documentTableSize, documentCells(offsets, row)...)
+	}
+}
+
+func (d *documentPDF) bytes() []byte {
+	return buildLetterPDF(append(d.pages, d.content.String()))
+}
+
+func documentCells(offsets []float64, texts []string) []documentCell {
+	cells := make([]documentCell, len(texts))
+	for i, text := range texts {
+		cells[i] = documentCell{x: offsets[i], text: text}
+	}
+	return cells
+}
+
+func wrapDocumentText(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && len(line)+1+len(word) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// truncateDocumentText shortens text to fit a table column
+func truncateDocumentText(text string, length int) string {
+	if len(text) <= length {
+		return text
+	}
+	return text[:length-3] + "..."
+}
+
+func documentMoney(amount float64) string {
+	return fmt.Sprintf("$%.2f", amount)
+}
+
+// BuildAgingStatementPDF renders a customer's aging statement as a PDF
+func BuildAgingStatementPDF(statement *AgingStatement) []byte {
+	doc := newDocumentPDF()
+	doc.title("Account Statement")
+	doc.text("F1", statement.CustomerName)
+	doc.text("F1", "As of: "+statement.AsOf.Format("January 2, 2006"))
+
+	doc.heading("Aging Summary")
+	buckets := statement.Buckets
+	doc.table([]float64{0, 85, 170, 255, 340, 425},
+		[]string{"Current", "1-30 Days", "31-60 Days", "61-90 Days", "Over 90 Days", "Total Due"},
+		[][]string{{
+			documentMoney(buckets.Current), documentMoney(buckets.Days1To30), documentMoney(buckets.Days31To60),
+			documentMoney(buckets.Days61To90), documentMoney(buckets.Over90), documentMoney(buckets.Total),
+		}})
+
+	doc.heading("Open Invoices")
+	rows := make([][]string, 0, len(statement.Invoices))
+	for _, line := range statement.Invoices {
+		issued, due := "", ""
+		if line.IssuedDate != nil {
+			issued = line.IssuedDate.Format("01/02/2006")
+		}
+		if line.DueDate != nil {
+			due = line.DueDate.Format("01/02/2006")
+		}
+		rows = append(rows, []string{
+			truncateDocumentText(line.InvoiceNumber, 16), issued, due,
+			documentMoney(line.TotalAmount), documentMoney(line.AmountPaid), documentMoney(line.Balance),
+			fmt.Sprintf("%d", line.DaysPastDue),
+		})
+	}
+	doc.table([]float64{0, 90, 160, 230, 300, 370, 440},
+		[]string{"Invoice", "Issued", "Due", "Amount", "Paid", "Balance", "Days Past Due"}, rows)
+
+	return doc.bytes()
+}
+
+// BuildConsolidatedInvoicePDF renders a consolidated invoice as a PDF
+func BuildConsolidatedInvoicePDF(consolidated *ConsolidatedInvoice, customer *domain.EnhancedCustomer) []byte {
+	invoice := consolidated.Invoice
+
+	doc := newDocumentPDF()
+	doc.title("Invoice " + invoice.InvoiceNumber)
+	if invoice.IssuedDate != nil {
+		doc.text("F1", "Issued: "+invoice.IssuedDate.Format("January 2, 2006"))
+	}
+	if invoice.DueDate != nil {
+		doc.text("F1", "Due: "+invoice.DueDate.Format("January 2, 2006"))
+	}
+
+	doc.heading("Bill To")
+	doc.text("F1", customerDisplayName(customer))
+	if customer.Email != nil {
+		doc.text("F1", "Email: "+*customer.Email)
+	}
+
+	if len(consolidated.PropertySubtotals) > 0 {
+		doc.heading("Properties")
+		rows := make([][]string, 0, len(consolidated.PropertySubtotals))
+		for _, subtotal := range consolidated.PropertySubtotals {
+			rows = append(rows, []string{
+				truncateDocumentText(subtotal.PropertyName, 26), truncateDocumentText(subtotal.PropertyAddress, 44),
+				fmt.Sprintf("%d", subtotal.JobCount), documentMoney(subtotal.Subtotal),
+			})
+		}
+		doc.table([]float64{0, 140, 380, 430}, []string{"Property", "Address", "Jobs", "Subtotal"}, rows)
+	}
+
+	if len(consolidated.LineItems) > 0 {
+		doc.heading("Services")
+		rows := make([][]string, 0, len(consolidated.LineItems))
+		for _, item := range consolidated.LineItems {
+			description := "Service " + item.ServiceID.String()
+			if item.Description != nil {
+				description = *item.Description
+			}
+			rows = append(rows, []string{
+				truncateDocumentText(description, 52), fmt.Sprintf("%.2f", item.Quantity),
+				documentMoney(item.UnitPrice), documentMoney(item.TotalPrice),
+			})
+		}
+		doc.table([]float64{0, 280, 350, 430}, []string{"Description", "Quantity", "Unit Price", "Total"}, rows)
+	}
+
+	doc.heading("Totals")
+	doc.text("F1", "Subtotal: "+documentMoney(invoice.Subtotal))
+	doc.text("F1", "Tax: "+documentMoney(invoice.TaxAmount))
+	doc.text("F2", "Total: "+documentMoney(invoice.TotalAmount))
+
+	if invoice.Notes != nil {
+		doc.heading("Notes")
+		doc.text("F1", *invoice.Notes)
+	}
+
+	return doc.bytes()
+}
@@ -0,0 +1,1075 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+)
+
+// StatementService handles monthly customer statements, consolidated invoicing and aging statements
+type StatementService interface {
+	// Statement runs
+	PreviewStatementRun(ctx context.Context, req *StatementRunRequest) (*StatementRunPreview, error)
+	RunStatements(ctx context.Context, req *StatementRunRequest) (*domain.StatementRun, error)
+	GetStatementRun(ctx context.Context, runID uuid.UUID) (*domain.StatementRun, error)
+	ListStatementRuns(ctx context.Context, filter *StatementRunFilter) (*domain.PaginatedResponse, error)
+
+	// Consolidated invoices
+	GetConsolidatedInvoice(ctx context.Context, invoiceID uuid.UUID) (*ConsolidatedInvoice, error)
+	GenerateConsolidatedInvoicePDF(ctx context.Context, invoiceID uuid.UUID) ([]byte, error)
+
+	// Aging statements
+	GetAgingStatement(ctx context.Context, customerID uuid.UUID, asOf time.Time) (*AgingStatement, error)
+	GenerateAgingStatementPDF(ctx context.Context, customerID uuid.UUID, asOf time.Time) ([]byte, error)
+	SendAgingStatement(ctx context.Context, customerID uuid.UUID) error
+
+	// Scheduling
+	GetStatementSchedule(ctx context.Context) (*domain.StatementSchedule, error)
+	UpdateStatementSchedule(ctx context.Context, req *StatementScheduleRequest) (*domain.StatementSchedule, error)
+	ProcessScheduledStatements(ctx context.Context, asOf time.Time) error
+}
+
+// StatementRepository defines data access for statement runs and consolidated invoices
+type StatementRepository interface {
+	// Statement runs
+	CreateRun(ctx context.Context, run *domain.StatementRun) error
+	UpdateRun(ctx context.Context, run *domain.StatementRun) error
+	GetRun(ctx context.Context, tenantID, runID uuid.UUID) (*domain.StatementRun, error)
+	ListRuns(ctx context.Context, tenantID uuid.UUID, filter *StatementRunFilter) ([]*domain.StatementRun, int64, error)
+
+	// Billable work
+	GetUninvoicedJobs(ctx context.Context, tenantID uuid.UUID, periodStart, periodEnd time.Time, customerIDs []uuid.UUID) ([]*UninvoicedJob, error)
+
+	// Consolidated invoice links
+	// CreateConsolidatedInvoice stores the invoice, its job links and line items
+	// and attaches it to the run in one transaction
+	CreateConsolidatedInvoice(ctx context.Context, invoice *domain.Invoice, invoiceJobs []*domain.InvoiceJob, lineItems []*InvoiceLineItem, runID uuid.UUID) error
+	GetInvoiceJobs(ctx context.Context, tenantID, invoiceID uuid.UUID) ([]*domain.InvoiceJob, error)
+	GetInvoicePropertySubtotals(ctx context.Context, tenantID, invoiceID uuid.UUID) ([]*PropertySubtotal, error)
+
+	// Receivables
+	GetOpenInvoices(ctx context.Context, tenantID uuid.UUID, customerID *uuid.UUID) ([]*OpenInvoice, error)
+	GetCustomersWithOpenBalances(ctx context.Context, tenantID uuid.UUID) ([]uuid.UUID, error)
+
+	// Schedules
+	GetSchedule(ctx context.Context, tenantID uuid.UUID) (*domain.StatementSchedule, error)
+	UpsertSchedule(ctx context.Context, schedule *domain.StatementSchedule) error
+	GetDueSchedules(ctx context.Context, asOf time.Time) ([]*domain.StatementSchedule, error)
+}
+
+// StatementRunRequest describes a statement run for a billing period
+type StatementRunRequest struct {
+	PeriodStart         time.Time   `json:"period_start" validate:"required"`
+	PeriodEnd           time.Time   `json:"period_end" validate:"required"`
+	GroupBy             string      `json:"group_by,omitempty"`
+	CustomerIDs         []uuid.UUID `json:"customer_ids,omitempty"`
+	SendInvoices        bool        `json:"send_invoices"`
+	SendAgingStatements bool        `json:"send_aging_statements"`
+	PaymentTermsDays    *int        `json:"payment_terms_days,omitempty"`
+	TaxRate             *float64    `json:"tax_rate,omitempty"`
+	Notes               *string     `json:"notes,omitempty"`
+}
+
+// StatementRunFilter filters statement run listings
+type StatementRunFilter struct {
+	BaseFilter
+	Status string `json:"status,omitempty"`
+}
+
+// StatementRunPreview shows what a statement run would invoice without persisting anything
+type StatementRunPreview struct {
+	PeriodStart time.Time         `json:"period_start"`
+	PeriodEnd   time.Time         `json:"period_end"`
+	GroupBy     string            `json:"group_by"`
+	Groups      []*StatementGroup `json:"groups"`
+	TotalJobs   int               `json:"total_jobs"`
+	TotalAmount float64           `json:"total_amount"`
+}
+
+// StatementGroup is the set of jobs that will be billed on a single consolidated invoice
+type StatementGroup struct {
+	CustomerID        uuid.UUID           `json:"customer_id"`
+	PropertyID        *uuid.UUID          `json:"property_id,omitempty"`
+	PONumber          *string             `json:"po_number,omitempty"`
+	Jobs              []*UninvoicedJob    `json:"jobs"`
+	PropertySubtotals []*PropertySubtotal `json:"property_subtotals"`
+	Subtotal          float64             `json:"subtotal"`
+}
+
+// UninvoicedJob is a completed job that has not been billed yet
+type UninvoicedJob struct {
+	JobID           uuid.UUID  `json:"job_id" db:"job_id"`
+	CustomerID      uuid.UUID  `json:"customer_id" db:"customer_id"`
+	PropertyID      uuid.UUID  `json:"property_id" db:"property_id"`
+	JobNumber       *string    `json:"job_number" db:"job_number"`
+	Title           string     `json:"title" db:"title"`
+	PONumber        *string    `json:"po_number" db:"po_number"`
+	PropertyName    string     `json:"property_name" db:"property_name"`
+	PropertyAddress string     `json:"property_address" db:"property_address"`
+	CompletedAt     *time.Time `json:"completed_at" db:"completed_at"`
+	Amount          float64    `json:"amount" db:"amount"`
+}
+
+// PropertySubtotal summarizes the billed amount for one property on a consolidated invoice
+type PropertySubtotal struct {
+	PropertyID      uuid.UUID `json:"property_id" db:"property_id"`
+	PropertyName    string    `json:"property_name" db:"property_name"`
+	PropertyAddress string    `json:"property_address" db:"property_address"`
+	JobCount        int       `json:"job_count" db:"job_count"`
+	Subtotal        float64   `json:"subtotal" db:"subtotal"`
+}
+
+// ConsolidatedInvoice is an invoice together with the jobs and property subtotals it covers
+type ConsolidatedInvoice struct {
+	Invoice           *domain.Invoice      `json:"invoice"`
+	Jobs              []*domain.InvoiceJob `json:"jobs"`
+	LineItems         []*InvoiceLineItem   `json:"line_items"`
+	PropertySubtotals []*PropertySubtotal  `json:"property_subtotals"`
+}
+
+// OpenInvoice is an issued, unpaid invoice with the amount collected so far
+type OpenInvoice struct {
+	Invoice    *domain.Invoice `json:"invoice"`
+	AmountPaid float64         `json:"amount_paid"`
+}
+
+// Balance returns the amount still owed on the invoice
+func (o *OpenInvoice) Balance() float64 {
+	return o.Invoice.TotalAmount - o.AmountPaid
+}
+
+// Aging buckets
+const (
+	AgingBucketCurrent = "current"
+	AgingBucket1To30   = "1_30"
+	AgingBucket31To60  = "31_60"
+	AgingBucket61To90  = "61_90"
+	AgingBucketOver90  = "over_90"
+)
+
+// AgingBuckets holds outstanding balances split by days past due
+type AgingBuckets struct {
+	Current    float64 `json:"current"`
+	Days1To30  float64 `json:"days_1_30"`
+	Days31To60 float64 `json:"days_31_60"`
+	Days61To90 float64 `json:"days_61_90"`
+	Over90     float64 `json:"over_90"`
+	Total      float64 `json:"total"`
+}
+
+// Add adds an amount to the named bucket and the running total
+func (b *AgingBuckets) Add(bucket string, amount float64) {
+	switch bucket {
+	case AgingBucketCurrent:
+		b.Current += amount
+	case AgingBucket1To30:
+		b.Days1To30 += amount
+	case AgingBucket31To60:
+		b.Days31To60 += amount
+	case AgingBucket61To90:
+		b.Days61To90 += amount
+	default:
+		b.Over90 += amount
+	}
+	b.Total += amount
+}
+
+// AgingBucketFor returns the aging bucket and days past due for an invoice due date
+func AgingBucketFor(dueDate *time.Time, asOf time.Time) (string, int) {
+	if dueDate == nil {
+		return AgingBucketCurrent, 0
+	}
+
+	due := time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 0, 0, 0, 0, time.UTC)
+	ref := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	daysPastDue := int(ref.Sub(due).Hours() / 24)
+
+	switch {
+	case daysPastDue <= 0:
+		return AgingBucketCurrent, 0
+	case daysPastDue <= 30:
+		return AgingBucket1To30, daysPastDue
+	case daysPastDue <= 60:
+		return AgingBucket31To60, daysPastDue
+	case daysPastDue <= 90:
+		return AgingBucket61To90, daysPastDue
+	default:
+		return AgingBucketOver90, daysPastDue
+	}
+}
+
+// AgingStatement is a customer's outstanding balance broken down by age
+type AgingStatement struct {
+	CustomerID   uuid.UUID           `json:"customer_id"`
+	CustomerName string              `json:"customer_name"`
+	AsOf         time.Time           `json:"as_of"`
+	Buckets      AgingBuckets        `json:"buckets"`
+	Invoices     []*AgingInvoiceLine `json:"invoices"`
+}
+
+// AgingInvoiceLine is one open invoice on an aging statement
+type AgingInvoiceLine struct {
+	InvoiceID     uuid.UUID  `json:"invoice_id"`
+	InvoiceNumber string     `json:"invoice_number"`
+	IssuedDate    *time.Time `json:"issued_date"`
+	DueDate       *time.Time `json:"due_date"`
+	TotalAmount   float64    `json:"total_amount"`
+	AmountPaid    float64    `json:"amount_paid"`
+	Balance       float64    `json:"balance"`
+	DaysPastDue   int        `json:"days_past_due"`
+	Bucket        string     `json:"bucket"`
+}
+
+// StatementScheduleRequest updates a tenant's statement schedule
+type StatementScheduleRequest struct {
+	Enabled             *bool   `json:"enabled,omitempty"`
+	DayOfMonth          *int    `json:"day_of_month,omitempty"`
+	GroupBy             *string `json:"group_by,omitempty"`
+	AutoSendInvoices    *bool   `json:"auto_send_invoices,omitempty"`
+	SendAgingStatements *bool   `json:"send_aging_statements,omitempty"`
+	PaymentTermsDays    *int    `json:"payment_terms_days,omitempty"`
+}
+
+// statementServiceImpl implements StatementService
+type statementServiceImpl struct {
+	statementRepo        StatementRepository
+	invoiceRepo          InvoiceRepositoryFull
+	customerRepo         CustomerRepository
+	jobRepo              JobRepositoryComplete
+	auditService         AuditService
+	communicationService CommunicationService
+	logger               *log.Logger
+}
+
+// NewStatementService creates a new statement service instance
+func NewStatementService(
+	statementRepo StatementRepository,
+	invoiceRepo InvoiceRepositoryFull,
+	customerRepo CustomerRepository,
+	jobRepo JobRepositoryComplete,
+	auditService AuditService,
+	communicationService CommunicationService,
+	logger *log.Logger,
+) StatementService {
+	return &statementServiceImpl{
+		statementRepo:        statementRepo,
+		invoiceRepo:          invoiceRepo,
+		customerRepo:         customerRepo,
+		jobRepo:              jobRepo,
+		auditService:         auditService,
+		communicationService: communicationService,
+		logger:               logger,
+	}
+}
+
+// PreviewStatementRun groups un-invoiced jobs for the period without creating invoices
+func (s *statementServiceImpl) PreviewStatementRun(ctx context.Context, req *StatementRunRequest) (*StatementRunPreview, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if err := s.validateRunRequest(req); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	jobs, err := s.statementRepo.GetUninvoicedJobs(ctx, tenantID, req.PeriodStart, req.PeriodEnd, req.CustomerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get uninvoiced jobs: %w", err)
+	}
+
+	groups := GroupUninvoicedJobs(jobs, req.GroupBy)
+
+	preview := &StatementRunPreview{
+		PeriodStart: req.PeriodStart,
+		PeriodEnd:   req.PeriodEnd,
+		GroupBy:     req.GroupBy,
+		Groups:      groups,
+		TotalJobs:   len(jobs),
+	}
+	for _, group := range groups {
+		preview.TotalAmount += group.Subtotal
+	}
+
+	return preview, nil
+}
+
+// RunStatements creates one consolidated invoice per group of un-invoiced jobs in the period
+func (s *statementServiceImpl) RunStatements(ctx context.Context, req *StatementRunRequest) (*domain.StatementRun, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	preview, err := s.PreviewStatementRun(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &domain.StatementRun{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		PeriodStart: req.PeriodStart,
+		PeriodEnd:   req.PeriodEnd,
+		GroupBy:     req.GroupBy,
+		Status:      domain.StatementRunStatusRunning,
+		TriggeredBy: GetUserIDFromContext(ctx),
+		StartedAt:   time.Now(),
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.statementRepo.CreateRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to create statement run: %w", err)
+	}
+
+	customers := make(map[uuid.UUID]*domain.EnhancedCustomer)
+	var failures []string
+
+	for _, group := range preview.Groups {
+		customer, ok := customers[group.CustomerID]
+		if !ok {
+			customer, err = s.customerRepo.GetByID(ctx, tenantID, group.CustomerID)
+			if err != nil || customer == nil {
+				failures = append(failures, fmt.Sprintf("customer %s: not found", group.CustomerID))
+				continue
+			}
+			customers[group.CustomerID] = customer
+		}
+
+		invoice, err := s.createConsolidatedInvoice(ctx, run, req, customer, group)
+		if err != nil {
+			s.logger.Printf("Failed to create consolidated invoice for customer %s: %v", group.CustomerID, err)
+			failures = append(failures, fmt.Sprintf("customer %s: %v", group.CustomerID, err))
+			continue
+		}
+
+		run.InvoicesCreated++
+		run.TotalAmount += invoice.TotalAmount
+
+		if req.SendInvoices {
+			if err := s.sendConsolidatedInvoice(ctx, invoice, customer); err != nil {
+				s.logger.Printf("Failed to send consolidated invoice %s: %v", invoice.InvoiceNumber, err)
+				failures = append(failures, fmt.Sprintf("invoice %s: %v", invoice.InvoiceNumber, err))
+			}
+		}
+	}
+	run.CustomersProcessed = len(customers)
+
+	if req.SendAgingStatements {
+		customerIDs, err := s.statementRepo.GetCustomersWithOpenBalances(ctx, tenantID)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("aging statements: %v", err))
+		}
+		for _, customerID := range customerIDs {
+			if !containsUUID(req.CustomerIDs, customerID) && len(req.CustomerIDs) > 0 {
+				continue
+			}
+			if err := s.SendAgingStatement(ctx, customerID); err != nil {
+				s.logger.Printf("Failed to send aging statement to customer %s: %v", customerID, err)
+				continue
+			}
+			run.StatementsSent++
+		}
+	}
+
+	now := time.Now()
+	run.CompletedAt = &now
+	run.Status = domain.StatementRunStatusCompleted
+	if len(failures) > 0 {
+		message := strings.Join(failures, "; ")
+		run.ErrorMessage = &message
+		if run.InvoicesCreated == 0 && len(preview.Groups) > 0 {
+			run.Status = domain.StatementRunStatusFailed
+		}
+	}
+
+	if err := s.statementRepo.UpdateRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to update statement run: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       run.TriggeredBy,
+		Action:       "statement_run.complete",
+		ResourceType: "statement_run",
+		ResourceID:   &run.ID,
+		NewValues: map[string]interface{}{
+			"period_start":     run.PeriodStart,
+			"period_end":       run.PeriodEnd,
+			"group_by":         run.GroupBy,
+			"invoices_created": run.InvoicesCreated,
+			"statements_sent":  run.StatementsSent,
+			"total_amount":     run.TotalAmount,
+			"status":           run.Status,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return run, nil
+}
+
+// GetStatementRun retrieves a statement run by ID
+func (s *statementServiceImpl) GetStatementRun(ctx context.Context, runID uuid.UUID) (*domain.StatementRun, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	run, err := s.statementRepo.GetRun(ctx, tenantID, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statement run: %w", err)
+	}
+	if run == nil {
+		return nil, fmt.Errorf("statement run not found")
+	}
+
+	return run, nil
+}
+
+// ListStatementRuns lists statement runs with pagination
+func (s *statementServiceImpl) ListStatementRuns(ctx context.Context, filter *StatementRunFilter) (*domain.PaginatedResponse, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	if filter == nil {
+		filter = &StatementRunFilter{}
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PerPage <= 0 {
+		filter.PerPage = 50
+	}
+	if filter.PerPage > 100 {
+		filter.PerPage = 100
+	}
+
+	runs, total, err := s.statementRepo.ListRuns(ctx, tenantID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statement runs: %w", err)
+	}
+
+	totalPages := int((total + int64(filter.PerPage) - 1) / int64(filter.PerPage))
+
+	return &domain.PaginatedResponse{
+		Data:       runs,
+		Total:      total,
+		Page:       filter.Page,
+		PerPage:    filter.PerPage,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetConsolidatedInvoice retrieves an invoice with its billed jobs and per-property subtotals
+func (s *statementServiceImpl) GetConsolidatedInvoice(ctx context.Context, invoiceID uuid.UUID) (*ConsolidatedInvoice, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(ctx, tenantID, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	if invoice == nil {
+		return nil, fmt.Errorf("invoice not found")
+	}
+
+	jobs, err := s.statementRepo.GetInvoiceJobs(ctx, tenantID, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice jobs: %w", err)
+	}
+
+	lineItems, err := s.invoiceRepo.GetInvoiceServices(ctx, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice services: %w", err)
+	}
+
+	subtotals, err := s.statementRepo.GetInvoicePropertySubtotals(ctx, tenantID, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get property subtotals: %w", err)
+	}
+
+	return &ConsolidatedInvoice{
+		Invoice:           invoice,
+		Jobs:              jobs,
+		LineItems:         lineItems,
+		PropertySubtotals: subtotals,
+	}, nil
+}
+
+// GenerateConsolidatedInvoicePDF renders a consolidated invoice grouped by property
+func (s *statementServiceImpl) GenerateConsolidatedInvoicePDF(ctx context.Context, invoiceID uuid.UUID) ([]byte, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	consolidated, err := s.GetConsolidatedInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.customerRepo.GetByID(ctx, tenantID, consolidated.Invoice.CustomerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	if customer == nil {
+		return nil, fmt.Errorf("customer not found")
+	}
+
+	return BuildConsolidatedInvoicePDF(consolidated, customer), nil
+}
+
+// GetAgingStatement builds a customer's aging statement as of the given date
+func (s *statementServiceImpl) GetAgingStatement(ctx context.Context, customerID uuid.UUID, asOf time.Time) (*AgingStatement, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	customer, err := s.customerRepo.GetByID(ctx, tenantID, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	if customer == nil {
+		return nil, fmt.Errorf("customer not found")
+	}
+
+	openInvoices, err := s.statementRepo.GetOpenInvoices(ctx, tenantID, &customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open invoices: %w", err)
+	}
+
+	return BuildAgingStatement(customer, openInvoices, asOf), nil
+}
+
+// BuildAgingStatement buckets a customer's open invoices by days past due
+func BuildAgingStatement(customer *domain.EnhancedCustomer, openInvoices []*OpenInvoice, asOf time.Time) *AgingStatement {
+	statement := &AgingStatement{
+		CustomerID:   customer.ID,
+		CustomerName: customerDisplayName(customer),
+		AsOf:         asOf,
+		Invoices:     make([]*AgingInvoiceLine, 0, len(openInvoices)),
+	}
+
+	for _, open := range openInvoices {
+		balance := open.Balance()
+		if balance <= 0 {
+			continue
+		}
+
+		bucket, daysPastDue := AgingBucketFor(open.Invoice.DueDate, asOf)
+		statement.Buckets.Add(bucket, balance)
+		statement.Invoices = append(statement.Invoices, &AgingInvoiceLine{
+			InvoiceID:     open.Invoice.ID,
+			InvoiceNumber: open.Invoice.InvoiceNumber,
+			IssuedDate:    open.Invoice.IssuedDate,
+			DueDate:       open.Invoice.DueDate,
+			TotalAmount:   open.Invoice.TotalAmount,
+			AmountPaid:    open.AmountPaid,
+			Balance:       balance,
+			DaysPastDue:   daysPastDue,
+			Bucket:        bucket,
+		})
+	}
+
+	// Oldest invoices first
+	sort.Slice(statement.Invoices, func(i, j int) bool {
+		return statement.Invoices[i].DaysPastDue > statement.Invoices[j].DaysPastDue
+	})
+
+	return statement
+}
+
+// GenerateAgingStatementPDF renders an aging statement for a customer
+func (s *statementServiceImpl) GenerateAgingStatementPDF(ctx context.Context, customerID uuid.UUID, asOf time.Time) ([]byte, error) {
+	statement, err := s.GetAgingStatement(ctx, customerID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildAgingStatementPDF(statement), nil
+}
+
+// SendAgingStatement emails the current aging statement to a customer
+func (s *statementServiceImpl) SendAgingStatement(ctx context.Context, customerID uuid.UUID) error {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("tenant ID not found in context")
+	}
+
+	customer, err := s.customerRepo.GetByID(ctx, tenantID, customerID)
+	if err != nil {
+		return fmt.Errorf("failed to get customer: %w", err)
+	}
+	if customer == nil {
+		return fmt.Errorf("customer not found")
+	}
+	if customer.Email == nil || *customer.Email == "" {
+		return fmt.Errorf("no email address available for customer")
+	}
+
+	asOf := time.Now()
+	statement, err := s.GetAgingStatement(ctx, customerID, asOf)
+	if err != nil {
+		return err
+	}
+	if statement.Buckets.Total <= 0 {
+		return nil
+	}
+
+	emailReq := &EmailRequest{
+		To:      []string{*customer.Email},
+		Subject: fmt.Sprintf("Account statement as of %s", asOf.Format("January 2, 2006")),
+		Body: fmt.Sprintf("Dear %s,\n\nPlease find attached your account statement. Your outstanding balance is $%.2f.\n\nThank you for your business!",
+			statement.CustomerName, statement.Buckets.Total),
+		IsHTML: false,
+		Attachments: []Attachment{
+			{
+				Name:        fmt.Sprintf("statement_%s.pdf", asOf.Format("2006-01-02")),
+				ContentType: "application/pdf",
+				Data:        BuildAgingStatementPDF(statement),
+			},
+		},
+	}
+
+	if err := s.communicationService.SendEmail(ctx, emailReq); err != nil {
+		return fmt.Errorf("failed to send statement email: %w", err)
+	}
+
+	return nil
+}
+
+// GetStatementSchedule returns the tenant's statement schedule, or defaults if none is saved
+func (s *statementServiceImpl) GetStatementSchedule(ctx context.Context) (*domain.StatementSchedule, error) {
+	tenantID, ok := GetTenantIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	schedule, err := s.statementRepo.GetSchedule(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statement schedule: %w", err)
+	}
+	if schedule == nil {
+		schedule = &domain.StatementSchedule{
+			ID:                  uuid.New(),
+			TenantID:            tenantID,
+			Enabled:             false,
+			DayOfMonth:          1,
+			GroupBy:             domain.StatementGroupByCustomer,
+			AutoSendInvoices:    true,
+			SendAgingStatements: true,
+			PaymentTermsDays:    30,
+			CreatedAt:           time.Now(),
+			UpdatedAt:           time.Now(),
+		}
+	}
+
+	return schedule, nil
+}
+
+// UpdateStatementSchedule updates the tenant's statement schedule
+func (s *statementServiceImpl) UpdateStatementSchedule(ctx context.Context, req *StatementScheduleRequest) (*domain.StatementSchedule, error) {
+	schedule, err := s.GetStatementSchedule(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	oldValues := map[string]interface{}{
+		"enabled":      schedule.Enabled,
+		"day_of_month": schedule.DayOfMonth,
+		"group_by":     schedule.GroupBy,
+	}
+
+	if req.Enabled != nil {
+		schedule.Enabled = *req.Enabled
+	}
+	if req.DayOfMonth != nil {
+		if *req.DayOfMonth < 1 || *req.DayOfMonth > 28 {
+			return nil, fmt.Errorf("day of month must be between 1 and 28")
+		}
+		schedule.DayOfMonth = *req.DayOfMonth
+	}
+	if req.GroupBy != nil {
+		if !isValidStatementGroupBy(*req.GroupBy) {
+			return nil, fmt.Errorf("invalid group by: %s", *req.GroupBy)
+		}
+		schedule.GroupBy = *req.GroupBy
+	}
+	if req.AutoSendInvoices != nil {
+		schedule.AutoSendInvoices = *req.AutoSendInvoices
+	}
+	if req.SendAgingStatements != nil {
+		schedule.SendAgingStatements = *req.SendAgingStatements
+	}
+	if req.PaymentTermsDays != nil {
+		if *req.PaymentTermsDays < 0 {
+			return nil, fmt.Errorf("payment terms cannot be negative")
+		}
+		schedule.PaymentTermsDays = *req.PaymentTermsDays
+	}
+
+	if schedule.Enabled {
+		nextRun := NextStatementRunDate(time.Now(), schedule.DayOfMonth)
+		schedule.NextRunAt = &nextRun
+	} else {
+		schedule.NextRunAt = nil
+	}
+	schedule.UpdatedAt = time.Now()
+
+	if err := s.statementRepo.UpsertSchedule(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to save statement schedule: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       GetUserIDFromContext(ctx),
+		Action:       "statement_schedule.update",
+		ResourceType: "statement_schedule",
+		ResourceID:   &schedule.ID,
+		OldValues:    oldValues,
+		NewValues: map[string]interface{}{
+			"enabled":      schedule.Enabled,
+			"day_of_month": schedule.DayOfMonth,
+			"group_by":     schedule.GroupBy,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return schedule, nil
+}
+
+// ProcessScheduledStatements runs statements for every tenant whose schedule is due.
+// It is called periodically by the worker and is not tenant-scoped.
+func (s *statementServiceImpl) ProcessScheduledStatements(ctx context.Context, asOf time.Time) error {
+	schedules, err := s.statementRepo.GetDueSchedules(ctx, asOf)
+	if err != nil {
+		return fmt.Errorf("failed to get due statement schedules: %w", err)
+	}
+
+	for _, schedule := range schedules {
+		tenantCtx := context.WithValue(ctx, "tenant_id", schedule.TenantID)
+
+		// Bill the previous calendar month
+		periodEnd := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, asOf.Location()).Add(-time.Nanosecond)
+		periodStart := time.Date(periodEnd.Year(), periodEnd.Month(), 1, 0, 0, 0, 0, asOf.Location())
+		terms := schedule.PaymentTermsDays
+
+		run, err := s.RunStatements(tenantCtx, &StatementRunRequest{
+			PeriodStart:         periodStart,
+			PeriodEnd:           periodEnd,
+			GroupBy:             schedule.GroupBy,
+			SendInvoices:        schedule.AutoSendInvoices,
+			SendAgingStatements: schedule.SendAgingStatements,
+			PaymentTermsDays:    &terms,
+		})
+		if err != nil {
+			s.logger.Printf("Scheduled statement run failed for tenant %s: %v", schedule.TenantID, err)
+		} else {
+			s.logger.Printf("Scheduled statement run %s completed for tenant %s: %d invoices, %d statements",
+				run.ID, schedule.TenantID, run.InvoicesCreated, run.StatementsSent)
+		}
+
+		lastRun := asOf
+		nextRun := NextStatementRunDate(asOf, schedule.DayOfMonth)
+		schedule.LastRunAt = &lastRun
+		schedule.NextRunAt = &nextRun
+		schedule.UpdatedAt = time.Now()
+		if err := s.statementRepo.UpsertSchedule(tenantCtx, schedule); err != nil {
+			s.logger.Printf("Failed to advance statement schedule for tenant %s: %v", schedule.TenantID, err)
+		}
+	}
+
+	return nil
+}
+
+// NextStatementRunDate returns the next occurrence of dayOfMonth strictly after from
+func NextStatementRunDate(from time.Time, dayOfMonth int) time.Time {
+	candidate := time.Date(from.Year(), from.Month(), dayOfMonth, 6, 0, 0, 0, from.Location())
+	if !candidate.After(from) {
+		candidate = candidate.AddDate(0, 1, 0)
+	}
+	return candidate
+}
+
+// GroupUninvoicedJobs groups jobs into consolidated invoices by customer, property or PO number
+func GroupUninvoicedJobs(jobs []*UninvoicedJob, groupBy string) []*StatementGroup {
+	groups := make(map[string]*StatementGroup)
+	var order []string
+
+	for _, job := range jobs {
+		key := job.CustomerID.String()
+		switch groupBy {
+		case domain.StatementGroupByProperty:
+			key += "|" + job.PropertyID.String()
+		case domain.StatementGroupByPONumber:
+			if job.PONumber != nil && *job.PONumber != "" {
+				key += "|po:" + *job.PONumber
+			}
+		}
+
+		group, exists := groups[key]
+		if !exists {
+			group = &StatementGroup{CustomerID: job.CustomerID}
+			switch groupBy {
+			case domain.StatementGroupByProperty:
+				propertyID := job.PropertyID
+				group.PropertyID = &propertyID
+			case domain.StatementGroupByPONumber:
+				if job.PONumber != nil && *job.PONumber != "" {
+					group.PONumber = job.PONumber
+				}
+			}
+			groups[key] = group
+			order = append(order, key)
+		}
+
+		group.Jobs = append(group.Jobs, job)
+		group.Subtotal += job.Amount
+	}
+
+	result := make([]*StatementGroup, 0, len(order))
+	for _, key := range order {
+		group := groups[key]
+		group.PropertySubtotals = summarizeByProperty(group.Jobs)
+		result = append(result, group)
+	}
+
+	return result
+}
+
+// Helper methods
+
+func (s *statementServiceImpl) validateRunRequest(req *StatementRunRequest) error {
+	if req == nil {
+		return fmt.Errorf("request is required")
+	}
+	if req.PeriodStart.IsZero() || req.PeriodEnd.IsZero() {
+		return fmt.Errorf("period start and end are required")
+	}
+	if req.PeriodEnd.Before(req.PeriodStart) {
+		return fmt.Errorf("period end must be after period start")
+	}
+	if req.GroupBy == "" {
+		req.GroupBy = domain.StatementGroupByCustomer
+	}
+	if !isValidStatementGroupBy(req.GroupBy) {
+		return fmt.Errorf("invalid group by: %s", req.GroupBy)
+	}
+	return nil
+}
+
+func (s *statementServiceImpl) createConsolidatedInvoice(ctx context.Context, run *domain.StatementRun, req *StatementRunRequest, customer *domain.EnhancedCustomer, group *StatementGroup) (*domain.Invoice, error) {
+	invoiceNumber, err := s.invoiceRepo.GetNextInvoiceNumber(ctx, run.TenantID)
+	if err != nil {
+		s.logger.Printf("Failed to generate invoice number: %v", err)
+		invoiceNumber = fmt.Sprintf("INV-%d", time.Now().UnixNano())
+	}
+
+	// Payment terms: request override, then customer terms, then 30 days
+	terms := 30
+	if customer.PaymentTerms > 0 {
+		terms = customer.PaymentTerms
+	}
+	if req.PaymentTermsDays != nil {
+		terms = *req.PaymentTermsDays
+	}
+
+	taxRate := 0.08 // Default tax rate
+	if req.TaxRate != nil {
+		taxRate = *req.TaxRate
+	}
+
+	now := time.Now()
+	dueDate := now.AddDate(0, 0, terms)
+
+	notes := fmt.Sprintf("Statement for %s - %s", run.PeriodStart.Format("Jan 2, 2006"), run.PeriodEnd.Format("Jan 2, 2006"))
+	if group.PONumber != nil {
+		notes += fmt.Sprintf(" (PO %s)", *group.PONumber)
+	}
+	if req.Notes != nil {
+		notes += "\n" + *req.Notes
+	}
+
+	invoice := &domain.Invoice{
+		ID:            uuid.New(),
+		TenantID:      run.TenantID,
+		CustomerID:    group.CustomerID,
+		InvoiceNumber: invoiceNumber,
+		Status:        "draft",
+		Subtotal:      group.Subtotal,
+		TaxRate:       taxRate,
+		TaxAmount:     group.Subtotal * taxRate,
+		TotalAmount:   group.Subtotal * (1 + taxRate),
+		DueDate:       &dueDate,
+		Notes:         &notes,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	// A single-job group keeps the direct job reference for compatibility with per-job invoicing
+	if len(group.Jobs) == 1 {
+		invoice.JobID = &group.Jobs[0].JobID
+	}
+
+	invoiceJobs := make([]*domain.InvoiceJob, 0, len(group.Jobs))
+	var lineItems []*InvoiceLineItem
+	for _, job := range group.Jobs {
+		invoiceJobs = append(invoiceJobs, &domain.InvoiceJob{
+			ID:         uuid.New(),
+			TenantID:   run.TenantID,
+			InvoiceID:  invoice.ID,
+			JobID:      job.JobID,
+			PropertyID: job.PropertyID,
+			PONumber:   job.PONumber,
+			Amount:     job.Amount,
+			CreatedAt:  now,
+		})
+
+		jobServices, err := s.jobRepo.GetJobServices(ctx, job.JobID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get services for job %s: %w", job.JobID, err)
+		}
+
+		for _, jobService := range jobServices {
+			description := fmt.Sprintf("%s - %s", job.Title, job.PropertyName)
+			if job.JobNumber != nil {
+				description = fmt.Sprintf("%s: %s", *job.JobNumber, description)
+			}
+			lineItems = append(lineItems, &InvoiceLineItem{
+				ID:          uuid.New(),
+				InvoiceID:   invoice.ID,
+				ServiceID:   jobService.ServiceID,
+				Quantity:    jobService.Quantity,
+				UnitPrice:   jobService.UnitPrice,
+				TotalPrice:  jobService.TotalPrice,
+				Description: &description,
+				CreatedAt:   now,
+			})
+		}
+	}
+
+	// The invoice, its job links and its lines are saved together so a failure
+	// leaves every job in the group unbilled for the next run
+	if err := s.statementRepo.CreateConsolidatedInvoice(ctx, invoice, invoiceJobs, lineItems, run.ID); err != nil {
+		return nil, fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, &AuditLogRequest{
+		UserID:       run.TriggeredBy,
+		Action:       "invoice.create_consolidated",
+		ResourceType: "invoice",
+		ResourceID:   &invoice.ID,
+		NewValues: map[string]interface{}{
+			"invoice_number":   invoice.InvoiceNumber,
+			"customer_id":      invoice.CustomerID,
+			"statement_run_id": run.ID,
+			"job_count":        len(group.Jobs),
+			"total_amount":     invoice.TotalAmount,
+		},
+	}); err != nil {
+		s.logger.Printf("Failed to log audit event: %v", err)
+	}
+
+	return invoice, nil
+}
+
+func (s *statementServiceImpl) sendConsolidatedInvoice(ctx context.Context, invoice *domain.Invoice, customer *domain.EnhancedCustomer) error {
+	if customer.Email == nil || *customer.Email == "" {
+		return fmt.Errorf("no email address available for customer")
+	}
+
+	pdfData, err := s.GenerateConsolidatedInvoicePDF(ctx, invoice.ID)
+	if err != nil {
+		return fmt.Errorf("failed to generate invoice: %w", err)
+	}
+
+	emailReq := &EmailRequest{
+		To:      []string{*customer.Email},
+		Subject: fmt.Sprintf("Invoice %s", invoice.InvoiceNumber),
+		Body:    fmt.Sprintf("Please find attached your invoice %s for $%.2f", invoice.InvoiceNumber, invoice.TotalAmount),
+		IsHTML:  false,
+		Attachments: []Attachment{
+			{
+				Name:        fmt.Sprintf("invoice_%s.pdf", invoice.InvoiceNumber),
+				ContentType: "application/pdf",
+				Data:        pdfData,
+			},
+		},
+	}
+
+	if err := s.communicationService.SendEmail(ctx, emailReq); err != nil {
+		return fmt.Errorf("failed to send invoice email: %w", err)
+	}
+
+	now := time.Now()
+	invoice.Status = "sent"
+	invoice.IssuedDate = &now
+	invoice.UpdatedAt = now
+	if err := s.invoiceRepo.Update(ctx, invoice); err != nil {
+		return fmt.Errorf("failed to update invoice status: %w", err)
+	}
+
+	return nil
+}
+
+func summarizeByProperty(jobs []*UninvoicedJob) []*PropertySubtotal {
+	byProperty := make(map[uuid.UUID]*PropertySubtotal)
+	var order []uuid.UUID
+
+	for _, job := range jobs {
+		subtotal, exists := byProperty[job.PropertyID]
+		if !exists {
+			subtotal = &PropertySubtotal{
+				PropertyID:      job.PropertyID,
+				PropertyName:    job.PropertyName,
+				PropertyAddress: job.PropertyAddress,
+			}
+			byProperty[job.PropertyID] = subtotal
+			order = append(order, job.PropertyID)
+		}
+		subtotal.JobCount++
+		subtotal.Subtotal += job.Amount
+	}
+
+	result := make([]*PropertySubtotal, 0, len(order))
+	for _, propertyID := range order {
+		result = append(result, byProperty[propertyID])
+	}
+	return result
+}
+
+func customerDisplayName(customer *domain.EnhancedCustomer) string {
+	if customer.CompanyName != nil && *customer.CompanyName != "" {
+		return *customer.CompanyName
+	}
+	return strings.TrimSpace(customer.FirstName + " " + customer.LastName)
+}
+
+func isValidStatementGroupBy(groupBy string) bool {
+	switch groupBy {
+	case domain.StatementGroupByCustomer, domain.StatementGroupByProperty, domain.StatementGroupByPONumber:
+		return true
+	}
+	return false
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
@@ -1285,6 +1285,8 @@ type tenantAnalyticsServiceImpl struct {
 	alertingService    AlertingService
 	cacheService       CacheService
 	auditService       AuditService
+	fuelRepo           FuelRepository
+	meterRepo          EquipmentMeterRepository
 	logger             *log.Logger
 }
 
@@ -1298,6 +1300,8 @@ func NewTenantAnalyticsService(
 	alertingService AlertingService,
 	cacheService CacheService,
 	auditService AuditService,
+	fuelRepo FuelRepository,
+	meterRepo EquipmentMeterRepository,
 	logger *log.Logger,
 ) TenantAnalyticsService {
 	return &tenantAnalyticsServiceImpl{
@@ -1309,6 +1313,8 @@ func NewTenantAnalyticsService(
 		alertingService:   alertingService,
 		cacheService:      cacheService,
 		auditService:      auditService,
+		fuelRepo:          fuelRepo,
+		meterRepo:         meterRepo,
 		logger:            logger,
 	}
 }
@@ -1669,8 +1675,27 @@ func (s *tenantAnalyticsServiceImpl) GetUserEngagementMetrics(ctx context.Contex
 	return &UserEngagementMetrics{}, nil
 }
 
+// GetEfficiencyMetrics retrieves operational efficiency metrics for a tenant,
+// including fleet fuel use and the fuel cost charged to jobs
 func (s *tenantAnalyticsServiceImpl) GetEfficiencyMetrics(ctx context.Context, tenantID uuid.UUID, period *TimePeriod) (*EfficiencyMetrics, error) {
-	return &EfficiencyMetrics{}, nil
+	entries, err := s.fuelRepo.ListFuelEntries(ctx, tenantID, &FuelEntryFilter{
+		StartDate: &period.StartDate,
+		EndDate:   &period.EndDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fuel entries: %w", err)
+	}
+
+	allocations, err := allocateJobFuel(ctx, s.fuelRepo, s.meterRepo, tenantID, nil, &period.StartDate, &period.EndDate, period.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EfficiencyMetrics{
+		ByService: make(map[string]float64),
+		Trends:    []EfficiencyTrend{},
+		Fuel:      SummarizeFuelEfficiency(entries, allocations),
+	}, nil
 }
 
 func (s *tenantAnalyticsServiceImpl) GetGrowthMetrics(ctx context.Context, tenantID uuid.UUID, period *TimePeriod) (*GrowthMetrics, error) {
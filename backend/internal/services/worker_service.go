@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pageza/landscaping-app/backend/internal/config"
+)
+
+// WorkerService runs periodic background tasks such as scheduled statements and collections
+type WorkerService interface {
+	RegisterTask(task *WorkerTask)
+	Start(ctx context.Context) error
+}
+
+// WorkerTask is a named job executed on a fixed interval
+type WorkerTask struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context, now time.Time) error
+}
+
+// workerServiceImpl implements WorkerService
+type workerServiceImpl struct {
+	tasks       []*WorkerTask
+	concurrency int
+	logger      *log.Logger
+}
+
+// NewWorkerService creates a worker with the default tasks for the configured services
+func NewWorkerService(svc *Services, cfg *config.Config) WorkerService {
+	concurrency := 1
+	if cfg != nil && cfg.WorkerConcurrency > 0 {
+		concurrency = cfg.WorkerConcurrency
+	}
+
+	worker := &workerServiceImpl{
+		concurrency: concurrency,
+		logger:      log.New(os.Stdout, "[worker] ", log.LstdFlags),
+	}
+
+	if svc != nil && svc.Statement != nil {
+		worker.RegisterTask(&WorkerTask{
+			Name:     "scheduled_statements",
+			Interval: time.Hour,
+			Run:      svc.Statement.ProcessScheduledStatements,
+		})
+	}
+
+	if svc != nil && svc.Collections != nil {
+		worker.RegisterTask(&WorkerTask{
+			Name:     "collections",
+			Interval: time.Hour,
+			Run:      svc.Collections.ProcessCollections,
+		})
+	}
+
+	if svc != nil && svc.Accounting != nil {
+		worker.RegisterTask(&WorkerTask{
+			Name:     "accounting_sync",
+			Interval: 15 * time.Minute,
+			Run:      svc.Accounting.ProcessAccountingSync,
+		})
+	}
+
+	if svc != nil && svc.Pipeline != nil {
+		worker.RegisterTask(&WorkerTask{
+			Name:     "pipeline_task_reminders",
+			Interval: 15 * time.Minute,
+			Run:      svc.Pipeline.ProcessTaskReminders,
+		})
+	}
+
+	if svc != nil && svc.Booking != nil {
+		worker.RegisterTask(&WorkerTask{
+			Name:     "booking_hold_expiry",
+			Interval: time.Minute,
+			Run:      svc.Booking.ProcessExpiredHolds,
+		})
+	}
+
+	if svc != nil && svc.Import != nil {
+		worker.RegisterTask(&WorkerTask{
+			Name:     "import_processing",
+			Interval: time.Minute,
+			Run:      svc.Import.ProcessImports,
+		})
+	}
+
+	if svc != nil && svc.Certification != nil {
+		worker.RegisterTask(&WorkerTask{
+			Name:     "certification_expiry_reminders",
+			Interval: time.Hour,
+			Run:      svc.Certification.ProcessExpiryReminders,
+		})
+	}
+
+	if svc != nil && svc.Equipment != nil {
+		worker.RegisterTask(&WorkerTask{
+			Name:     "equipment_overdue_returns",
+			Interval: 15 * time.Minute,
+			Run:      svc.Equipment.ProcessOverdueCheckouts,
+		})
+	}
+
+	return worker
+}
+
+// RegisterTask adds a periodic task; tasks must be registered before Start
+func (w *workerServiceImpl) RegisterTask(task *WorkerTask) {
+	w.tasks = append(w.tasks, task)
+}
+
+// Start runs all registered tasks until the context is cancelled
+func (w *workerServiceImpl) Start(ctx context.Context) error {
+	slots := make(chan struct{}, w.concurrency)
+	var wg sync.WaitGroup
+
+	for _, task := range w.tasks {
+		wg.Add(1)
+		go func(task *WorkerTask) {
+			defer wg.Done()
+			w.runTask(ctx, task, slots)
+		}(task)
+	}
+
+	wg.Wait()
+	return nil
+}
+
+func (w *workerServiceImpl) runTask(ctx context.Context, task *WorkerTask, slots chan struct{}) {
+	ticker := time.NewTicker(task.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			slots <- struct{}{}
+			if err := task.Run(ctx, now); err != nil {
+				w.logger.Printf("Task %s failed: %v", task.Name, err)
+			}
+			<-slots
+		}
+	}
+}
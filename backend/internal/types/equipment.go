@@ -14,6 +14,7 @@ type EquipmentFilter struct {
 	Status           *string  `json:"status,omitempty"`
 	Location         *string  `json:"location,omitempty"`
 	AssignedUserID   *uuid.UUID `json:"assigned_user_id,omitempty"`
+	AssignedCrewID   *uuid.UUID `json:"assigned_crew_id,omitempty"` // checked out to the crew
 	MinPurchaseDate  *time.Time `json:"min_purchase_date,omitempty"`
 	MaxPurchaseDate  *time.Time `json:"max_purchase_date,omitempty"`
 	MinWarrantyEnd   *time.Time `json:"min_warranty_end,omitempty"`
@@ -0,0 +1,21 @@
+-- Rollback Customer Statements and Consolidated Invoicing
+
+DROP TRIGGER IF EXISTS update_statement_schedules_updated_at ON statement_schedules;
+
+DROP POLICY IF EXISTS statement_schedule_tenant_isolation ON statement_schedules;
+DROP POLICY IF EXISTS invoice_job_tenant_isolation ON invoice_jobs;
+DROP POLICY IF EXISTS statement_run_tenant_isolation ON statement_runs;
+
+DROP TABLE IF EXISTS statement_schedules;
+DROP TABLE IF EXISTS invoice_jobs;
+
+ALTER TABLE invoices DROP CONSTRAINT IF EXISTS fk_invoices_statement_run;
+DROP INDEX IF EXISTS idx_invoices_statement_run_id;
+ALTER TABLE invoices DROP COLUMN IF EXISTS statement_run_id;
+
+DROP TABLE IF EXISTS statement_runs;
+
+DROP INDEX IF EXISTS idx_jobs_tenant_po_number;
+ALTER TABLE jobs DROP COLUMN IF EXISTS po_number;
+
+DROP TABLE IF EXISTS invoice_services;
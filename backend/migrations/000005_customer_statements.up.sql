@@ -0,0 +1,111 @@
+-- Customer Statements and Consolidated Invoicing
+-- Adds statement runs, consolidated invoice links and scheduled aging statement delivery
+
+-- Invoice line items (referenced by the invoice repository)
+CREATE TABLE IF NOT EXISTS invoice_services (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    service_id UUID NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    quantity DECIMAL(10,2) NOT NULL DEFAULT 1,
+    unit_price DECIMAL(10,2) NOT NULL,
+    total_price DECIMAL(10,2) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Purchase order numbers on jobs for commercial billing
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS po_number VARCHAR(100);
+
+-- Statement run tracking on invoices
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS statement_run_id UUID;
+
+-- Statement runs (one per billing period execution)
+CREATE TABLE IF NOT EXISTS statement_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    group_by VARCHAR(20) NOT NULL DEFAULT 'customer',
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    customers_processed INTEGER DEFAULT 0,
+    invoices_created INTEGER DEFAULT 0,
+    statements_sent INTEGER DEFAULT 0,
+    total_amount DECIMAL(12,2) DEFAULT 0,
+    error_message TEXT,
+    triggered_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    started_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+ALTER TABLE invoices ADD CONSTRAINT fk_invoices_statement_run
+    FOREIGN KEY (statement_run_id) REFERENCES statement_runs(id) ON DELETE SET NULL;
+
+-- Jobs billed on a consolidated invoice
+CREATE TABLE IF NOT EXISTS invoice_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    property_id UUID NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    po_number VARCHAR(100),
+    amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(invoice_id, job_id)
+);
+
+-- Per-tenant statement schedule
+CREATE TABLE IF NOT EXISTS statement_schedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    enabled BOOLEAN DEFAULT FALSE,
+    day_of_month INTEGER NOT NULL DEFAULT 1 CHECK (day_of_month BETWEEN 1 AND 28),
+    group_by VARCHAR(20) NOT NULL DEFAULT 'customer',
+    auto_send_invoices BOOLEAN DEFAULT TRUE,
+    send_aging_statements BOOLEAN DEFAULT TRUE,
+    payment_terms_days INTEGER DEFAULT 30,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(tenant_id)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_invoice_services_invoice_id ON invoice_services(invoice_id);
+CREATE INDEX IF NOT EXISTS idx_jobs_tenant_po_number ON jobs(tenant_id, po_number) WHERE po_number IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_invoices_statement_run_id ON invoices(statement_run_id);
+CREATE INDEX IF NOT EXISTS idx_statement_runs_tenant_created ON statement_runs(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_invoice_jobs_invoice_id ON invoice_jobs(invoice_id);
+CREATE INDEX IF NOT EXISTS idx_invoice_jobs_job_id ON invoice_jobs(job_id);
+CREATE INDEX IF NOT EXISTS idx_invoice_jobs_tenant_property ON invoice_jobs(tenant_id, property_id);
+CREATE INDEX IF NOT EXISTS idx_statement_schedules_next_run ON statement_schedules(next_run_at) WHERE enabled = TRUE;
+
+-- Row Level Security
+ALTER TABLE statement_runs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE invoice_jobs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE statement_schedules ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY statement_run_tenant_isolation ON statement_runs
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY invoice_job_tenant_isolation ON invoice_jobs
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY statement_schedule_tenant_isolation ON statement_schedules
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_statement_schedules_updated_at BEFORE UPDATE ON statement_schedules FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,23 @@
+-- Rollback Accounts Receivable Collections
+
+DROP TRIGGER IF EXISTS update_payment_promises_updated_at ON payment_promises;
+DROP TRIGGER IF EXISTS update_collection_policies_updated_at ON collection_policies;
+
+DROP POLICY IF EXISTS payment_promise_tenant_isolation ON payment_promises;
+DROP POLICY IF EXISTS collection_note_tenant_isolation ON collection_notes;
+DROP POLICY IF EXISTS invoice_late_fee_tenant_isolation ON invoice_late_fees;
+DROP POLICY IF EXISTS invoice_reminder_tenant_isolation ON invoice_reminders;
+DROP POLICY IF EXISTS collection_policy_tenant_isolation ON collection_policies;
+
+DROP TABLE IF EXISTS payment_promises;
+DROP TABLE IF EXISTS collection_notes;
+DROP TABLE IF EXISTS invoice_late_fees;
+DROP TABLE IF EXISTS invoice_reminders;
+DROP TABLE IF EXISTS collection_policies;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS late_fee_total;
+
+DROP INDEX IF EXISTS idx_customers_scheduling_suspended;
+ALTER TABLE customers DROP COLUMN IF EXISTS scheduling_suspended_reason;
+ALTER TABLE customers DROP COLUMN IF EXISTS scheduling_suspended_at;
+ALTER TABLE customers DROP COLUMN IF EXISTS scheduling_suspended;
@@ -0,0 +1,140 @@
+-- Accounts Receivable Collections
+-- Adds reminder cadences, late fee policies, collections notes, promise-to-pay tracking
+-- and scheduling suspension for delinquent accounts
+
+-- Scheduling suspension for delinquent customers
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS scheduling_suspended BOOLEAN DEFAULT FALSE;
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS scheduling_suspended_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS scheduling_suspended_reason TEXT;
+
+-- Late fees accrued on invoices
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS late_fee_total DECIMAL(10,2) DEFAULT 0;
+
+-- Per-tenant collections policy
+CREATE TABLE IF NOT EXISTS collection_policies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    reminders_enabled BOOLEAN DEFAULT TRUE,
+    reminder_steps JSONB NOT NULL DEFAULT '[{"days_overdue": 3, "template": "friendly"}, {"days_overdue": 10, "template": "firm"}, {"days_overdue": 30, "template": "final"}]',
+    late_fee_enabled BOOLEAN DEFAULT FALSE,
+    late_fee_type VARCHAR(20) DEFAULT 'flat',
+    late_fee_amount DECIMAL(10,2) DEFAULT 0,
+    late_fee_grace_days INTEGER DEFAULT 0,
+    late_fee_repeat_days INTEGER DEFAULT 0,
+    late_fee_max_applications INTEGER DEFAULT 1,
+    suspend_scheduling_enabled BOOLEAN DEFAULT FALSE,
+    suspend_after_days INTEGER DEFAULT 60,
+    suspend_min_balance DECIMAL(10,2) DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(tenant_id)
+);
+
+-- Reminders sent per invoice and cadence step
+CREATE TABLE IF NOT EXISTS invoice_reminders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    days_overdue INTEGER NOT NULL,
+    template VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL DEFAULT 'email',
+    recipient VARCHAR(255),
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(invoice_id, days_overdue)
+);
+
+-- Late fees applied to invoices
+CREATE TABLE IF NOT EXISTS invoice_late_fees (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    amount DECIMAL(10,2) NOT NULL,
+    days_overdue INTEGER NOT NULL,
+    reason TEXT,
+    waived BOOLEAN DEFAULT FALSE,
+    waived_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    waived_at TIMESTAMP WITH TIME ZONE,
+    applied_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Collections notes against customers and invoices
+CREATE TABLE IF NOT EXISTS collection_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    invoice_id UUID REFERENCES invoices(id) ON DELETE SET NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    note_type VARCHAR(30) NOT NULL DEFAULT 'note',
+    content TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Promises to pay
+CREATE TABLE IF NOT EXISTS payment_promises (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    invoice_id UUID REFERENCES invoices(id) ON DELETE SET NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    promised_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    notes TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_customers_scheduling_suspended ON customers(tenant_id) WHERE scheduling_suspended = TRUE;
+CREATE INDEX IF NOT EXISTS idx_invoice_reminders_invoice_id ON invoice_reminders(invoice_id);
+CREATE INDEX IF NOT EXISTS idx_invoice_late_fees_invoice_id ON invoice_late_fees(invoice_id);
+CREATE INDEX IF NOT EXISTS idx_collection_notes_customer ON collection_notes(tenant_id, customer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_payment_promises_customer ON payment_promises(tenant_id, customer_id);
+CREATE INDEX IF NOT EXISTS idx_payment_promises_pending ON payment_promises(tenant_id, promised_date) WHERE status = 'pending';
+
+-- Row Level Security
+ALTER TABLE collection_policies ENABLE ROW LEVEL SECURITY;
+ALTER TABLE invoice_reminders ENABLE ROW LEVEL SECURITY;
+ALTER TABLE invoice_late_fees ENABLE ROW LEVEL SECURITY;
+ALTER TABLE collection_notes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE payment_promises ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY collection_policy_tenant_isolation ON collection_policies
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY invoice_reminder_tenant_isolation ON invoice_reminders
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY invoice_late_fee_tenant_isolation ON invoice_late_fees
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY collection_note_tenant_isolation ON collection_notes
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY payment_promise_tenant_isolation ON payment_promises
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_collection_policies_updated_at BEFORE UPDATE ON collection_policies FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_payment_promises_updated_at BEFORE UPDATE ON payment_promises FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,13 @@
+-- Rollback Accounting Integration
+
+DROP TRIGGER IF EXISTS update_accounting_sync_records_updated_at ON accounting_sync_records;
+DROP TRIGGER IF EXISTS update_accounting_connections_updated_at ON accounting_connections;
+DROP TRIGGER IF EXISTS update_accounting_account_mappings_updated_at ON accounting_account_mappings;
+
+DROP POLICY IF EXISTS accounting_sync_record_tenant_isolation ON accounting_sync_records;
+DROP POLICY IF EXISTS accounting_connection_tenant_isolation ON accounting_connections;
+DROP POLICY IF EXISTS accounting_account_mapping_tenant_isolation ON accounting_account_mappings;
+
+DROP TABLE IF EXISTS accounting_sync_records;
+DROP TABLE IF EXISTS accounting_connections;
+DROP TABLE IF EXISTS accounting_account_mappings;
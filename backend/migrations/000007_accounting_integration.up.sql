@@ -0,0 +1,88 @@
+-- Accounting Integration
+-- Adds chart-of-accounts mappings, accounting provider connections and per-record sync tracking
+
+-- Maps customers, services, taxes and payment methods to chart-of-accounts entries
+CREATE TABLE IF NOT EXISTS accounting_account_mappings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    mapping_type VARCHAR(30) NOT NULL, -- receivable, income, income_category, tax, deposit, late_fee
+    mapping_key VARCHAR(255) NOT NULL DEFAULT '', -- empty key is the default for the type
+    account_code VARCHAR(50),
+    account_name VARCHAR(255) NOT NULL,
+    account_type VARCHAR(30) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(tenant_id, mapping_type, mapping_key)
+);
+
+-- API connections to accounting providers
+CREATE TABLE IF NOT EXISTS accounting_connections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    provider VARCHAR(30) NOT NULL, -- quickbooks, xero
+    external_tenant_id VARCHAR(255),
+    access_token TEXT,
+    refresh_token TEXT,
+    token_expires_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    auto_sync BOOLEAN DEFAULT TRUE,
+    last_sync_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(tenant_id, provider)
+);
+
+-- One row per record pushed to a provider; source_updated_at drives change tracking
+CREATE TABLE IF NOT EXISTS accounting_sync_records (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    provider VARCHAR(30) NOT NULL,
+    entity_type VARCHAR(30) NOT NULL, -- customer, service, invoice, payment
+    entity_id UUID NOT NULL,
+    external_id VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'synced',
+    source_updated_at TIMESTAMP WITH TIME ZONE,
+    attempts INTEGER DEFAULT 0,
+    last_error TEXT,
+    synced_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(tenant_id, provider, entity_type, entity_id)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_accounting_account_mappings_tenant ON accounting_account_mappings(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_accounting_connections_status ON accounting_connections(status);
+CREATE INDEX IF NOT EXISTS idx_accounting_sync_records_status ON accounting_sync_records(tenant_id, provider, status);
+
+-- Row Level Security
+ALTER TABLE accounting_account_mappings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE accounting_connections ENABLE ROW LEVEL SECURITY;
+ALTER TABLE accounting_sync_records ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY accounting_account_mapping_tenant_isolation ON accounting_account_mappings
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY accounting_connection_tenant_isolation ON accounting_connections
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY accounting_sync_record_tenant_isolation ON accounting_sync_records
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_accounting_account_mappings_updated_at BEFORE UPDATE ON accounting_account_mappings FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_accounting_connections_updated_at BEFORE UPDATE ON accounting_connections FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_accounting_sync_records_updated_at BEFORE UPDATE ON accounting_sync_records FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,16 @@
+-- Rollback Quote Versioning
+
+DROP TRIGGER IF EXISTS prevent_quote_version_changes ON quote_versions;
+DROP FUNCTION IF EXISTS prevent_quote_version_changes();
+
+DROP POLICY IF EXISTS quote_version_tenant_isolation ON quote_versions;
+
+DROP INDEX IF EXISTS idx_jobs_quote_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS quote_version_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS quote_id;
+
+ALTER TABLE quotes DROP COLUMN IF EXISTS approved_version_id;
+
+DROP TABLE IF EXISTS quote_versions;
+
+ALTER TABLE quotes DROP COLUMN IF EXISTS current_version;
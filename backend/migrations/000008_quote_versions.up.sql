@@ -0,0 +1,65 @@
+-- Quote Versioning
+-- Immutable revision snapshots of a quote's line items and terms, taken each time
+-- a quote is sent or approved, and job references to the approved revision
+
+-- Revision tracking on quotes
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS current_version INTEGER NOT NULL DEFAULT 0;
+
+-- Quote revision snapshots
+CREATE TABLE IF NOT EXISTS quote_versions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    quote_id UUID NOT NULL REFERENCES quotes(id) ON DELETE CASCADE,
+    version_number INTEGER NOT NULL,
+    revision VARCHAR(10) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    subtotal DECIMAL(10,2) NOT NULL,
+    tax_rate DECIMAL(5,4) NOT NULL,
+    tax_amount DECIMAL(10,2) NOT NULL,
+    total_amount DECIMAL(10,2) NOT NULL,
+    valid_until DATE,
+    terms_and_conditions TEXT,
+    notes TEXT,
+    line_items JSONB NOT NULL DEFAULT '[]',
+    change_summary TEXT,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    sent_to VARCHAR(255),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(quote_id, version_number)
+);
+
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS approved_version_id UUID REFERENCES quote_versions(id) ON DELETE SET NULL;
+
+-- Jobs converted from a quote reference the approved revision
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS quote_id UUID REFERENCES quotes(id) ON DELETE SET NULL;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS quote_version_id UUID REFERENCES quote_versions(id) ON DELETE SET NULL;
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_quote_versions_quote ON quote_versions(quote_id, version_number DESC);
+CREATE INDEX IF NOT EXISTS idx_quote_versions_tenant ON quote_versions(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_jobs_quote_id ON jobs(quote_id) WHERE quote_id IS NOT NULL;
+
+-- Row Level Security
+ALTER TABLE quote_versions ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY quote_version_tenant_isolation ON quote_versions
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+-- Revisions are immutable once written; only delivery details may be recorded afterwards
+CREATE OR REPLACE FUNCTION prevent_quote_version_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (to_jsonb(NEW) - 'sent_at' - 'sent_to') IS DISTINCT FROM (to_jsonb(OLD) - 'sent_at' - 'sent_to') THEN
+        RAISE EXCEPTION 'quote versions are immutable';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER prevent_quote_version_changes BEFORE UPDATE ON quote_versions FOR EACH ROW EXECUTE FUNCTION prevent_quote_version_changes();
@@ -0,0 +1,14 @@
+-- Rollback Quote Options
+
+DROP TRIGGER IF EXISTS update_quote_options_updated_at ON quote_options;
+
+DROP POLICY IF EXISTS quote_option_tenant_isolation ON quote_options;
+
+DROP INDEX IF EXISTS idx_quote_services_option;
+
+ALTER TABLE quotes DROP COLUMN IF EXISTS approved_selection;
+ALTER TABLE quote_versions DROP COLUMN IF EXISTS options;
+ALTER TABLE quote_services DROP COLUMN IF EXISTS is_optional;
+ALTER TABLE quote_services DROP COLUMN IF EXISTS option_id;
+
+DROP TABLE IF EXISTS quote_options;
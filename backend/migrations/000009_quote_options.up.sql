@@ -0,0 +1,44 @@
+-- Quote Options
+-- Good/better/best option groups within a quote, optional add-on line items,
+-- and the customer's selection captured on approval
+
+-- Options the customer chooses between; one option is selected per group
+CREATE TABLE IF NOT EXISTS quote_options (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    quote_id UUID NOT NULL REFERENCES quotes(id) ON DELETE CASCADE,
+    group_name VARCHAR(100) NOT NULL DEFAULT 'Options',
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Line items belong to the base quote or to an option, and may be optional add-ons
+ALTER TABLE quote_services ADD COLUMN IF NOT EXISTS option_id UUID REFERENCES quote_options(id) ON DELETE CASCADE;
+ALTER TABLE quote_services ADD COLUMN IF NOT EXISTS is_optional BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Options captured with each revision
+ALTER TABLE quote_versions ADD COLUMN IF NOT EXISTS options JSONB NOT NULL DEFAULT '[]';
+
+-- Selected options, add-ons and resulting totals recorded on approval
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS approved_selection JSONB;
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_quote_options_quote ON quote_options(quote_id, group_name, sort_order);
+CREATE INDEX IF NOT EXISTS idx_quote_services_option ON quote_services(option_id) WHERE option_id IS NOT NULL;
+
+-- Row Level Security
+ALTER TABLE quote_options ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY quote_option_tenant_isolation ON quote_options
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_quote_options_updated_at BEFORE UPDATE ON quote_options FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,10 @@
+-- Rollback Pricing Engine
+
+DROP TRIGGER IF EXISTS update_pricing_rules_updated_at ON pricing_rules;
+DROP TRIGGER IF EXISTS update_price_books_updated_at ON price_books;
+
+DROP POLICY IF EXISTS pricing_rule_tenant_isolation ON pricing_rules;
+DROP POLICY IF EXISTS price_book_tenant_isolation ON price_books;
+
+DROP TABLE IF EXISTS pricing_rules;
+DROP TABLE IF EXISTS price_books;
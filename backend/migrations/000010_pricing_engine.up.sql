@@ -0,0 +1,70 @@
+-- Pricing Engine
+-- Tenant-configurable price books and pricing rules shared by the public booking
+-- calculator, quotes and service pricing
+
+-- Price books group rules by effective date; seasonal books override the standing
+-- book while they are in effect
+CREATE TABLE IF NOT EXISTS price_books (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    effective_from DATE NOT NULL,
+    effective_to DATE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (effective_to IS NULL OR effective_to >= effective_from)
+);
+
+-- Rules apply to a specific service, a public catalog key, a category, or every
+-- service when no scope is set
+CREATE TABLE IF NOT EXISTS pricing_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    price_book_id UUID NOT NULL REFERENCES price_books(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    rule_type VARCHAR(50) NOT NULL CHECK (rule_type IN (
+        'unit_rate', 'size_tier', 'minimum_charge', 'frequency_discount',
+        'zone_surcharge', 'bundle_discount', 'same_day_discount', 'distance_surcharge'
+    )),
+    service_id UUID REFERENCES services(id) ON DELETE CASCADE,
+    service_key VARCHAR(100),
+    category VARCHAR(100),
+    unit VARCHAR(20),
+    rate DECIMAL(12,4) NOT NULL DEFAULT 0,
+    min_value DECIMAL(12,2),
+    max_value DECIMAL(12,2),
+    frequency VARCHAR(20),
+    zip_codes JSONB NOT NULL DEFAULT '[]',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_price_books_tenant_effective ON price_books(tenant_id, effective_from, effective_to) WHERE is_active = TRUE;
+CREATE INDEX IF NOT EXISTS idx_pricing_rules_book ON pricing_rules(price_book_id, rule_type);
+CREATE INDEX IF NOT EXISTS idx_pricing_rules_service ON pricing_rules(service_id) WHERE service_id IS NOT NULL;
+
+-- Row Level Security
+ALTER TABLE price_books ENABLE ROW LEVEL SECURITY;
+ALTER TABLE pricing_rules ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY price_book_tenant_isolation ON price_books
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY pricing_rule_tenant_isolation ON pricing_rules
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_price_books_updated_at BEFORE UPDATE ON price_books FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_pricing_rules_updated_at BEFORE UPDATE ON pricing_rules FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
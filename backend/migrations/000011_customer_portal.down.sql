@@ -0,0 +1,17 @@
+-- Rollback Customer Self-Service Portal
+
+DROP TRIGGER IF EXISTS update_portal_service_requests_updated_at ON portal_service_requests;
+DROP TRIGGER IF EXISTS update_portal_schedule_changes_updated_at ON portal_schedule_changes;
+DROP TRIGGER IF EXISTS update_portal_settings_updated_at ON portal_settings;
+
+DROP POLICY IF EXISTS portal_service_request_tenant_isolation ON portal_service_requests;
+DROP POLICY IF EXISTS portal_schedule_change_tenant_isolation ON portal_schedule_changes;
+DROP POLICY IF EXISTS quote_signature_tenant_isolation ON quote_signatures;
+DROP POLICY IF EXISTS portal_settings_tenant_isolation ON portal_settings;
+
+DROP TABLE IF EXISTS portal_service_requests;
+DROP TABLE IF EXISTS portal_schedule_changes;
+DROP TABLE IF EXISTS quote_signatures;
+DROP TABLE IF EXISTS portal_sessions;
+DROP TABLE IF EXISTS portal_login_tokens;
+DROP TABLE IF EXISTS portal_settings;
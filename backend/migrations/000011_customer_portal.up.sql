@@ -0,0 +1,155 @@
+-- Customer Self-Service Portal
+-- Adds passwordless portal access for customers, e-signed quote acceptance,
+-- skip/reschedule requests and customer service requests
+
+-- Per-tenant portal rules
+CREATE TABLE IF NOT EXISTS portal_settings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    enabled BOOLEAN DEFAULT TRUE,
+    portal_url VARCHAR(500),
+    magic_link_ttl_minutes INTEGER DEFAULT 15,
+    session_ttl_hours INTEGER DEFAULT 720,
+    allow_online_payment BOOLEAN DEFAULT TRUE,
+    allow_quote_acceptance BOOLEAN DEFAULT TRUE,
+    allow_skip BOOLEAN DEFAULT TRUE,
+    allow_reschedule BOOLEAN DEFAULT TRUE,
+    min_notice_hours INTEGER DEFAULT 48,
+    max_reschedule_days INTEGER DEFAULT 14,
+    max_skips_per_year INTEGER DEFAULT 0,
+    auto_approve_changes BOOLEAN DEFAULT FALSE,
+    signature_consent_text TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(tenant_id)
+);
+
+-- Single-use magic link tokens; only the token hash is stored
+CREATE TABLE IF NOT EXISTS portal_login_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    ip_address INET,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Portal sessions scoped to a single customer
+CREATE TABLE IF NOT EXISTS portal_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    ip_address INET,
+    user_agent TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- E-signatures captured when a customer accepts a quote
+CREATE TABLE IF NOT EXISTS quote_signatures (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    quote_id UUID NOT NULL REFERENCES quotes(id) ON DELETE CASCADE,
+    quote_version_id UUID REFERENCES quote_versions(id) ON DELETE SET NULL,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    signer_name VARCHAR(255) NOT NULL,
+    signer_email VARCHAR(255),
+    signature_type VARCHAR(20) NOT NULL CHECK (signature_type IN ('typed', 'drawn')),
+    signature_data TEXT NOT NULL,
+    consent_text TEXT NOT NULL,
+    total_amount DECIMAL(12,2) NOT NULL,
+    ip_address INET,
+    user_agent TEXT,
+    signed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Skip and reschedule requests for upcoming visits
+CREATE TABLE IF NOT EXISTS portal_schedule_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    change_type VARCHAR(20) NOT NULL CHECK (change_type IN ('skip', 'reschedule')),
+    original_date TIMESTAMP WITH TIME ZONE,
+    requested_date TIMESTAMP WITH TIME ZONE,
+    reason TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'declined', 'cancelled')),
+    auto_approved BOOLEAN DEFAULT FALSE,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    response_note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Service requests submitted by customers, worked from the admin queue
+CREATE TABLE IF NOT EXISTS portal_service_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    property_id UUID REFERENCES properties(id) ON DELETE SET NULL,
+    service_id UUID REFERENCES services(id) ON DELETE SET NULL,
+    description TEXT NOT NULL,
+    preferred_date DATE,
+    status VARCHAR(20) NOT NULL DEFAULT 'new' CHECK (status IN ('new', 'in_review', 'quoted', 'scheduled', 'closed')),
+    admin_notes TEXT,
+    assigned_to UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_portal_login_tokens_customer ON portal_login_tokens(customer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_portal_sessions_customer ON portal_sessions(customer_id) WHERE revoked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_quote_signatures_quote ON quote_signatures(quote_id);
+CREATE INDEX IF NOT EXISTS idx_portal_schedule_changes_queue ON portal_schedule_changes(tenant_id, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_portal_schedule_changes_customer ON portal_schedule_changes(customer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_portal_service_requests_queue ON portal_service_requests(tenant_id, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_portal_service_requests_customer ON portal_service_requests(customer_id, created_at DESC);
+
+-- Row Level Security
+-- Login tokens and sessions are looked up by token hash before the tenant is known,
+-- so like user_sessions they are not tenant-isolated
+ALTER TABLE portal_settings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE quote_signatures ENABLE ROW LEVEL SECURITY;
+ALTER TABLE portal_schedule_changes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE portal_service_requests ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY portal_settings_tenant_isolation ON portal_settings
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY quote_signature_tenant_isolation ON quote_signatures
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY portal_schedule_change_tenant_isolation ON portal_schedule_changes
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY portal_service_request_tenant_isolation ON portal_service_requests
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_portal_settings_updated_at BEFORE UPDATE ON portal_settings FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_portal_schedule_changes_updated_at BEFORE UPDATE ON portal_schedule_changes FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_portal_service_requests_updated_at BEFORE UPDATE ON portal_service_requests FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,7 @@
+-- Rollback Leads
+
+DROP TRIGGER IF EXISTS update_leads_updated_at ON leads;
+
+DROP POLICY IF EXISTS lead_tenant_isolation ON leads;
+
+DROP TABLE IF EXISTS leads;
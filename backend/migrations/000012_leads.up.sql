@@ -0,0 +1,57 @@
+-- Leads
+-- Persists booking and consultation requests from the public site so they can be
+-- screened, reviewed and converted into customers, properties and quotes
+
+CREATE TABLE IF NOT EXISTS leads (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    source VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'denied', 'scheduled', 'spam', 'duplicate')),
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL DEFAULT '',
+    email VARCHAR(255),
+    phone VARCHAR(50),
+    service_key VARCHAR(100),
+    service_id UUID REFERENCES services(id) ON DELETE SET NULL,
+    message TEXT,
+    address_line1 VARCHAR(255),
+    city VARCHAR(100),
+    state VARCHAR(50),
+    zip_code VARCHAR(20),
+    property_size DECIMAL(12,2),
+    estimated_price DECIMAL(12,2),
+    spam_score INTEGER DEFAULT 0,
+    spam_reasons JSONB DEFAULT '[]',
+    duplicate_of_id UUID REFERENCES leads(id) ON DELETE SET NULL,
+    matched_customer_id UUID REFERENCES customers(id) ON DELETE SET NULL,
+    customer_id UUID REFERENCES customers(id) ON DELETE SET NULL,
+    property_id UUID REFERENCES properties(id) ON DELETE SET NULL,
+    quote_id UUID REFERENCES quotes(id) ON DELETE SET NULL,
+    ip_address INET,
+    user_agent TEXT,
+    metadata JSONB DEFAULT '{}',
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    denial_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_leads_queue ON leads(tenant_id, status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_leads_email ON leads(tenant_id, LOWER(email)) WHERE email IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_leads_phone ON leads(tenant_id, regexp_replace(phone, '\D', '', 'g')) WHERE phone IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_leads_ip ON leads(tenant_id, ip_address, created_at) WHERE ip_address IS NOT NULL;
+
+-- Row Level Security
+ALTER TABLE leads ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY lead_tenant_isolation ON leads
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_leads_updated_at BEFORE UPDATE ON leads FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,15 @@
+-- Rollback Sales Pipeline
+
+DROP TRIGGER IF EXISTS update_follow_up_tasks_updated_at ON follow_up_tasks;
+DROP TRIGGER IF EXISTS update_opportunities_updated_at ON opportunities;
+DROP TRIGGER IF EXISTS update_pipeline_stages_updated_at ON pipeline_stages;
+
+DROP POLICY IF EXISTS follow_up_task_tenant_isolation ON follow_up_tasks;
+DROP POLICY IF EXISTS opportunity_stage_change_tenant_isolation ON opportunity_stage_changes;
+DROP POLICY IF EXISTS opportunity_tenant_isolation ON opportunities;
+DROP POLICY IF EXISTS pipeline_stage_tenant_isolation ON pipeline_stages;
+
+DROP TABLE IF EXISTS follow_up_tasks;
+DROP TABLE IF EXISTS opportunity_stage_changes;
+DROP TABLE IF EXISTS opportunities;
+DROP TABLE IF EXISTS pipeline_stages;
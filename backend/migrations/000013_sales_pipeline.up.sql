@@ -0,0 +1,129 @@
+-- Sales Pipeline
+-- Adds configurable pipeline stages, opportunities with estimated value and
+-- win/loss reasons, stage history for conversion metrics, and follow-up tasks
+
+-- Per-tenant pipeline stages; defaults are created on first use
+CREATE TABLE IF NOT EXISTS pipeline_stages (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    key VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    stage_type VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (stage_type IN ('open', 'won', 'lost')),
+    position INTEGER NOT NULL DEFAULT 0,
+    probability INTEGER NOT NULL DEFAULT 0 CHECK (probability BETWEEN 0 AND 100),
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(tenant_id, key)
+);
+
+-- Opportunities
+CREATE TABLE IF NOT EXISTS opportunities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    stage_id UUID NOT NULL REFERENCES pipeline_stages(id),
+    status VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'won', 'lost')),
+    lead_id UUID REFERENCES leads(id) ON DELETE SET NULL,
+    customer_id UUID REFERENCES customers(id) ON DELETE SET NULL,
+    property_id UUID REFERENCES properties(id) ON DELETE SET NULL,
+    quote_id UUID REFERENCES quotes(id) ON DELETE SET NULL,
+    contact_name VARCHAR(200),
+    contact_email VARCHAR(255),
+    contact_phone VARCHAR(50),
+    source VARCHAR(50),
+    estimated_value DECIMAL(12,2) NOT NULL DEFAULT 0,
+    probability INTEGER CHECK (probability BETWEEN 0 AND 100),
+    expected_close_date DATE,
+    assigned_to UUID REFERENCES users(id) ON DELETE SET NULL,
+    close_reason VARCHAR(100),
+    close_notes TEXT,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    stage_changed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    notes TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Stage history, used to measure how many opportunities reach each stage
+CREATE TABLE IF NOT EXISTS opportunity_stage_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    opportunity_id UUID NOT NULL REFERENCES opportunities(id) ON DELETE CASCADE,
+    from_stage_id UUID REFERENCES pipeline_stages(id) ON DELETE SET NULL,
+    to_stage_id UUID NOT NULL REFERENCES pipeline_stages(id) ON DELETE CASCADE,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Follow-up tasks
+CREATE TABLE IF NOT EXISTS follow_up_tasks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    opportunity_id UUID REFERENCES opportunities(id) ON DELETE CASCADE,
+    customer_id UUID REFERENCES customers(id) ON DELETE CASCADE,
+    assigned_to UUID REFERENCES users(id) ON DELETE SET NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    task_type VARCHAR(20) NOT NULL DEFAULT 'call' CHECK (task_type IN ('call', 'email', 'site_visit', 'other')),
+    due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    remind_at TIMESTAMP WITH TIME ZONE,
+    reminder_sent_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'completed', 'cancelled')),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    completed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    outcome TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_pipeline_stages_tenant ON pipeline_stages(tenant_id, position);
+CREATE INDEX IF NOT EXISTS idx_opportunities_stage ON opportunities(tenant_id, stage_id);
+CREATE INDEX IF NOT EXISTS idx_opportunities_status ON opportunities(tenant_id, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_opportunities_assigned ON opportunities(tenant_id, assigned_to) WHERE assigned_to IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_opportunities_customer ON opportunities(customer_id) WHERE customer_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_opportunity_stage_changes ON opportunity_stage_changes(opportunity_id, changed_at);
+CREATE INDEX IF NOT EXISTS idx_follow_up_tasks_assigned ON follow_up_tasks(tenant_id, assigned_to, status, due_at);
+CREATE INDEX IF NOT EXISTS idx_follow_up_tasks_reminders ON follow_up_tasks(COALESCE(remind_at, due_at)) WHERE status = 'open' AND reminder_sent_at IS NULL;
+
+-- Row Level Security
+ALTER TABLE pipeline_stages ENABLE ROW LEVEL SECURITY;
+ALTER TABLE opportunities ENABLE ROW LEVEL SECURITY;
+ALTER TABLE opportunity_stage_changes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE follow_up_tasks ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY pipeline_stage_tenant_isolation ON pipeline_stages
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY opportunity_tenant_isolation ON opportunities
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY opportunity_stage_change_tenant_isolation ON opportunity_stage_changes
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY follow_up_task_tenant_isolation ON follow_up_tasks
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_pipeline_stages_updated_at BEFORE UPDATE ON pipeline_stages FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_opportunities_updated_at BEFORE UPDATE ON opportunities FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_follow_up_tasks_updated_at BEFORE UPDATE ON follow_up_tasks FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,12 @@
+-- Rollback Online Booking
+
+DROP TRIGGER IF EXISTS update_booking_holds_updated_at ON booking_holds;
+DROP TRIGGER IF EXISTS update_booking_settings_updated_at ON booking_settings;
+
+DROP POLICY IF EXISTS booking_hold_tenant_isolation ON booking_holds;
+DROP POLICY IF EXISTS booking_blackout_tenant_isolation ON booking_blackouts;
+DROP POLICY IF EXISTS booking_settings_tenant_isolation ON booking_settings;
+
+DROP TABLE IF EXISTS booking_holds;
+DROP TABLE IF EXISTS booking_blackouts;
+DROP TABLE IF EXISTS booking_settings;
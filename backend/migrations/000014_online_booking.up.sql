@@ -0,0 +1,94 @@
+-- Online Booking
+-- Adds self-scheduling settings, blackout days and short-lived slot holds for the
+-- public booking page and embeddable booking API
+
+-- Per-tenant booking settings; defaults are used until a row exists
+CREATE TABLE IF NOT EXISTS booking_settings (
+    tenant_id UUID PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    enabled BOOLEAN DEFAULT FALSE,
+    timezone VARCHAR(50) NOT NULL DEFAULT 'UTC',
+    workday_start VARCHAR(5) NOT NULL DEFAULT '08:00',
+    workday_end VARCHAR(5) NOT NULL DEFAULT '17:00',
+    working_days INTEGER[] NOT NULL DEFAULT '{1,2,3,4,5}',
+    arrival_window_minutes INTEGER NOT NULL DEFAULT 120 CHECK (arrival_window_minutes > 0),
+    default_duration_minutes INTEGER NOT NULL DEFAULT 60 CHECK (default_duration_minutes > 0),
+    lead_time_hours INTEGER NOT NULL DEFAULT 24 CHECK (lead_time_hours >= 0),
+    max_days_ahead INTEGER NOT NULL DEFAULT 30 CHECK (max_days_ahead > 0),
+    hold_minutes INTEGER NOT NULL DEFAULT 10 CHECK (hold_minutes > 0),
+    max_jobs_per_crew_day INTEGER NOT NULL DEFAULT 6 CHECK (max_jobs_per_crew_day > 0),
+    travel_speed_mph DECIMAL(5,1) NOT NULL DEFAULT 25 CHECK (travel_speed_mph > 0),
+    default_travel_minutes INTEGER NOT NULL DEFAULT 20 CHECK (default_travel_minutes >= 0),
+    allowed_origins TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Days closed to online booking, for all crews or one crew
+CREATE TABLE IF NOT EXISTS booking_blackouts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    crew_id UUID REFERENCES crews(id) ON DELETE CASCADE,
+    reason VARCHAR(255),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Arrival windows held while a visitor completes a booking
+CREATE TABLE IF NOT EXISTS booking_holds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    crew_id UUID NOT NULL REFERENCES crews(id) ON DELETE CASCADE,
+    window_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    window_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    job_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    job_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    service_id UUID REFERENCES services(id) ON DELETE SET NULL,
+    service_key VARCHAR(50),
+    duration_minutes INTEGER NOT NULL,
+    latitude DECIMAL(10,8),
+    longitude DECIMAL(11,8),
+    token VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'held' CHECK (status IN ('held', 'confirmed', 'released', 'expired')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    lead_id UUID REFERENCES leads(id) ON DELETE SET NULL,
+    job_id UUID REFERENCES jobs(id) ON DELETE SET NULL,
+    ip_address INET,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_booking_blackouts_date ON booking_blackouts(tenant_id, date);
+CREATE INDEX IF NOT EXISTS idx_booking_holds_window ON booking_holds(tenant_id, job_start) WHERE status = 'held';
+CREATE INDEX IF NOT EXISTS idx_booking_holds_expiry ON booking_holds(expires_at) WHERE status = 'held';
+
+-- Row Level Security
+ALTER TABLE booking_settings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE booking_blackouts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE booking_holds ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY booking_settings_tenant_isolation ON booking_settings
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY booking_blackout_tenant_isolation ON booking_blackouts
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY booking_hold_tenant_isolation ON booking_holds
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_booking_settings_updated_at BEFORE UPDATE ON booking_settings FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_booking_holds_updated_at BEFORE UPDATE ON booking_holds FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
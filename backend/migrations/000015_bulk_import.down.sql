@@ -0,0 +1,10 @@
+-- Rollback Bulk Import
+
+DROP TRIGGER IF EXISTS update_import_rows_updated_at ON import_rows;
+DROP TRIGGER IF EXISTS update_import_jobs_updated_at ON import_jobs;
+
+DROP POLICY IF EXISTS import_row_tenant_isolation ON import_rows;
+DROP POLICY IF EXISTS import_job_tenant_isolation ON import_jobs;
+
+DROP TABLE IF EXISTS import_rows;
+DROP TABLE IF EXISTS import_jobs;
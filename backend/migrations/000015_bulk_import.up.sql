@@ -0,0 +1,82 @@
+-- Bulk Import
+-- Adds spreadsheet imports of customers and properties with column mapping, dry-run
+-- validation, duplicate detection, resumable processing and batch rollback
+
+-- Import jobs
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    file_format VARCHAR(10) NOT NULL CHECK (file_format IN ('csv', 'xlsx')),
+    headers TEXT[] NOT NULL DEFAULT '{}',
+    mapping JSONB NOT NULL DEFAULT '{}',
+    duplicate_strategy VARCHAR(20) NOT NULL DEFAULT 'skip' CHECK (duplicate_strategy IN ('skip', 'merge', 'create')),
+    status VARCHAR(20) NOT NULL DEFAULT 'uploaded' CHECK (status IN ('uploaded', 'validated', 'processing', 'geocoding', 'completed', 'failed', 'rolled_back')),
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    valid_rows INTEGER NOT NULL DEFAULT 0,
+    invalid_rows INTEGER NOT NULL DEFAULT 0,
+    duplicate_rows INTEGER NOT NULL DEFAULT 0,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    created_customers INTEGER NOT NULL DEFAULT 0,
+    updated_customers INTEGER NOT NULL DEFAULT 0,
+    created_properties INTEGER NOT NULL DEFAULT 0,
+    failed_rows INTEGER NOT NULL DEFAULT 0,
+    geocoded_at TIMESTAMP WITH TIME ZONE,
+    validated_at TIMESTAMP WITH TIME ZONE,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    rolled_back_at TIMESTAMP WITH TIME ZONE,
+    error TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Spreadsheet rows and their outcome
+CREATE TABLE IF NOT EXISTS import_rows (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    import_id UUID NOT NULL REFERENCES import_jobs(id) ON DELETE CASCADE,
+    row_number INTEGER NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'valid', 'invalid', 'duplicate', 'imported', 'skipped', 'failed', 'rolled_back')),
+    errors TEXT[] NOT NULL DEFAULT '{}',
+    warnings TEXT[] NOT NULL DEFAULT '{}',
+    duplicate_of_id UUID REFERENCES customers(id) ON DELETE SET NULL,
+    duplicate_score DECIMAL(4,3),
+    customer_id UUID REFERENCES customers(id) ON DELETE SET NULL,
+    property_id UUID REFERENCES properties(id) ON DELETE SET NULL,
+    created_customer BOOLEAN DEFAULT FALSE,
+    created_property BOOLEAN DEFAULT FALSE,
+    filled_fields TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(import_id, row_number)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_import_jobs_tenant ON import_jobs(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_import_jobs_active ON import_jobs(status) WHERE status IN ('processing', 'geocoding');
+CREATE INDEX IF NOT EXISTS idx_import_rows_status ON import_rows(import_id, status);
+
+-- Row Level Security
+ALTER TABLE import_jobs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE import_rows ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY import_job_tenant_isolation ON import_jobs
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY import_row_tenant_isolation ON import_rows
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_import_jobs_updated_at BEFORE UPDATE ON import_jobs FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_import_rows_updated_at BEFORE UPDATE ON import_rows FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,9 @@
+-- Rollback Customer Merge
+
+DROP TRIGGER IF EXISTS update_customer_merges_updated_at ON customer_merges;
+
+DROP POLICY IF EXISTS customer_duplicate_dismissal_tenant_isolation ON customer_duplicate_dismissals;
+DROP POLICY IF EXISTS customer_merge_tenant_isolation ON customer_merges;
+
+DROP TABLE IF EXISTS customer_duplicate_dismissals;
+DROP TABLE IF EXISTS customer_merges;
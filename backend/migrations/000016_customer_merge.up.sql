@@ -0,0 +1,61 @@
+-- Customer Merge
+-- Adds duplicate customer merges with re-parenting of related records and an undo
+-- window, and dismissals for suggested pairs that aren't duplicates
+
+-- Merges
+CREATE TABLE IF NOT EXISTS customer_merges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    survivor_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    merged_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    score DECIMAL(4,3),
+    field_changes JSONB NOT NULL DEFAULT '{}',
+    moved_records JSONB NOT NULL DEFAULT '{}',
+    merged_status VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'merged' CHECK (status IN ('merged', 'undone')),
+    undo_expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    merged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    undone_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    undone_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (survivor_id <> merged_id)
+);
+
+-- Suggested pairs reviewed as not duplicates
+CREATE TABLE IF NOT EXISTS customer_duplicate_dismissals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    customer_a_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    customer_b_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    dismissed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(tenant_id, customer_a_id, customer_b_id),
+    CHECK (customer_a_id < customer_b_id)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_customer_merges_tenant ON customer_merges(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_customer_merges_survivor ON customer_merges(survivor_id);
+CREATE INDEX IF NOT EXISTS idx_customer_merges_merged ON customer_merges(merged_id);
+
+-- Row Level Security
+ALTER TABLE customer_merges ENABLE ROW LEVEL SECURITY;
+ALTER TABLE customer_duplicate_dismissals ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY customer_merge_tenant_isolation ON customer_merges
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY customer_duplicate_dismissal_tenant_isolation ON customer_duplicate_dismissals
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_customer_merges_updated_at BEFORE UPDATE ON customer_merges FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,24 @@
+-- Rollback Global Search
+-- The pg_trgm extension is left installed
+
+DROP INDEX IF EXISTS idx_invoices_search_trgm;
+DROP INDEX IF EXISTS idx_invoices_search_vector;
+DROP INDEX IF EXISTS idx_quotes_search_trgm;
+DROP INDEX IF EXISTS idx_quotes_search_vector;
+DROP INDEX IF EXISTS idx_jobs_search_trgm;
+DROP INDEX IF EXISTS idx_jobs_search_vector;
+DROP INDEX IF EXISTS idx_properties_search_trgm;
+DROP INDEX IF EXISTS idx_properties_search_vector;
+DROP INDEX IF EXISTS idx_customers_search_trgm;
+DROP INDEX IF EXISTS idx_customers_search_vector;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE invoices DROP COLUMN IF EXISTS search_text;
+ALTER TABLE quotes DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE quotes DROP COLUMN IF EXISTS search_text;
+ALTER TABLE jobs DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE jobs DROP COLUMN IF EXISTS search_text;
+ALTER TABLE properties DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE properties DROP COLUMN IF EXISTS search_text;
+ALTER TABLE customers DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE customers DROP COLUMN IF EXISTS search_text;
@@ -0,0 +1,70 @@
+-- Global Search
+-- Adds generated full-text and trigram search columns to customers, properties, jobs,
+-- quotes and invoices for the global search endpoint. search_vector answers word and
+-- prefix matches; search_text backs typo-tolerant trigram matching.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Customers: name, company, email and phone (digits only, so any formatting matches)
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS search_text TEXT GENERATED ALWAYS AS (
+    lower(
+        coalesce(first_name, '') || ' ' || coalesce(last_name, '') || ' ' ||
+        coalesce(company_name, '') || ' ' || coalesce(email, '') || ' ' ||
+        regexp_replace(coalesce(phone, ''), '[^0-9]', '', 'g')
+    )
+) STORED;
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(first_name, '') || ' ' || coalesce(last_name, '') || ' ' || coalesce(company_name, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(email, '') || ' ' || regexp_replace(coalesce(phone, ''), '[^0-9]', '', 'g')), 'B')
+) STORED;
+
+-- Properties: name and address
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS search_text TEXT GENERATED ALWAYS AS (
+    lower(
+        coalesce(name, '') || ' ' || coalesce(address_line1, '') || ' ' ||
+        coalesce(address_line2, '') || ' ' || coalesce(city, '') || ' ' ||
+        coalesce(state, '') || ' ' || coalesce(zip_code, '')
+    )
+) STORED;
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(address_line1, '') || ' ' || coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(address_line2, '') || ' ' || coalesce(city, '') || ' ' || coalesce(state, '') || ' ' || coalesce(zip_code, '')), 'B')
+) STORED;
+
+-- Jobs: number and title
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS search_text TEXT GENERATED ALWAYS AS (
+    lower(coalesce(job_number, '') || ' ' || coalesce(title, ''))
+) STORED;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(job_number, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(title, '')), 'B')
+) STORED;
+
+-- Quotes: number and title
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS search_text TEXT GENERATED ALWAYS AS (
+    lower(coalesce(quote_number, '') || ' ' || coalesce(title, ''))
+) STORED;
+ALTER TABLE quotes ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(quote_number, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(title, '')), 'B')
+) STORED;
+
+-- Invoices: number
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS search_text TEXT GENERATED ALWAYS AS (
+    lower(coalesce(invoice_number, ''))
+) STORED;
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(invoice_number, '')), 'A')
+) STORED;
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_customers_search_vector ON customers USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_customers_search_trgm ON customers USING GIN(search_text gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_properties_search_vector ON properties USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_properties_search_trgm ON properties USING GIN(search_text gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_jobs_search_vector ON jobs USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_jobs_search_trgm ON jobs USING GIN(search_text gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_quotes_search_vector ON quotes USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_quotes_search_trgm ON quotes USING GIN(search_text gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_invoices_search_vector ON invoices USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS idx_invoices_search_trgm ON invoices USING GIN(search_text gin_trgm_ops);
@@ -0,0 +1,13 @@
+-- Rollback Customer Communications
+
+DROP TRIGGER IF EXISTS update_customer_notes_updated_at ON customer_notes;
+DROP TRIGGER IF EXISTS update_communication_messages_updated_at ON communication_messages;
+DROP TRIGGER IF EXISTS update_communication_threads_updated_at ON communication_threads;
+
+DROP POLICY IF EXISTS customer_note_tenant_isolation ON customer_notes;
+DROP POLICY IF EXISTS communication_message_tenant_isolation ON communication_messages;
+DROP POLICY IF EXISTS communication_thread_tenant_isolation ON communication_threads;
+
+DROP TABLE IF EXISTS customer_notes;
+DROP TABLE IF EXISTS communication_messages;
+DROP TABLE IF EXISTS communication_threads;
@@ -0,0 +1,93 @@
+-- Customer Communications
+-- Adds two-way SMS and email threads fed by the comms provider's inbound webhooks,
+-- and staff notes, for the customer communication timeline
+
+-- Conversations with one phone number or email address
+CREATE TABLE IF NOT EXISTS communication_threads (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    customer_id UUID REFERENCES customers(id) ON DELETE SET NULL,
+    channel VARCHAR(10) NOT NULL CHECK (channel IN ('sms', 'email')),
+    address VARCHAR(255) NOT NULL,
+    subject VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    unread_count INTEGER NOT NULL DEFAULT 0,
+    last_message_at TIMESTAMP WITH TIME ZONE,
+    last_message_preview VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Messages sent and received
+CREATE TABLE IF NOT EXISTS communication_messages (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    thread_id UUID NOT NULL REFERENCES communication_threads(id) ON DELETE CASCADE,
+    customer_id UUID REFERENCES customers(id) ON DELETE SET NULL,
+    channel VARCHAR(10) NOT NULL CHECK (channel IN ('sms', 'email')),
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('inbound', 'outbound')),
+    from_address VARCHAR(255) NOT NULL,
+    to_address VARCHAR(255) NOT NULL,
+    subject VARCHAR(255),
+    body TEXT NOT NULL,
+    provider_message_id VARCHAR(255),
+    in_reply_to VARCHAR(255),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('queued', 'sent', 'delivered', 'failed', 'received')),
+    error TEXT,
+    sent_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Staff notes on customers
+CREATE TABLE IF NOT EXISTS customer_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_communication_threads_tenant ON communication_threads(tenant_id, status, last_message_at DESC);
+CREATE INDEX IF NOT EXISTS idx_communication_threads_address ON communication_threads(tenant_id, channel, address);
+CREATE INDEX IF NOT EXISTS idx_communication_threads_customer ON communication_threads(customer_id);
+CREATE INDEX IF NOT EXISTS idx_communication_messages_thread ON communication_messages(thread_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_communication_messages_customer ON communication_messages(customer_id, created_at DESC);
+-- Provider webhooks are retried; a provider message is stored once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_communication_messages_provider ON communication_messages(tenant_id, provider_message_id) WHERE provider_message_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_customer_notes_customer ON customer_notes(customer_id, created_at DESC);
+
+-- Row Level Security
+ALTER TABLE communication_threads ENABLE ROW LEVEL SECURITY;
+ALTER TABLE communication_messages ENABLE ROW LEVEL SECURITY;
+ALTER TABLE customer_notes ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY communication_thread_tenant_isolation ON communication_threads
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY communication_message_tenant_isolation ON communication_messages
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY customer_note_tenant_isolation ON customer_notes
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_communication_threads_updated_at BEFORE UPDATE ON communication_threads FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_communication_messages_updated_at BEFORE UPDATE ON communication_messages FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_customer_notes_updated_at BEFORE UPDATE ON customer_notes FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
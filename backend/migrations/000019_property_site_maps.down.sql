@@ -0,0 +1,9 @@
+-- Rollback Property Site Maps
+
+DROP TRIGGER IF EXISTS update_property_features_updated_at ON property_features;
+
+DROP POLICY IF EXISTS property_feature_tenant_isolation ON property_features;
+
+ALTER TABLE pricing_rules DROP COLUMN IF EXISTS measured_area;
+
+DROP TABLE IF EXISTS property_features;
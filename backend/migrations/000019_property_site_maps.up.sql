@@ -0,0 +1,42 @@
+-- Property Site Maps
+-- Adds measured property features drawn as GeoJSON polygons, and lets square-foot
+-- pricing rules price a measured area such as turf or beds instead of the lot size
+
+-- Turf zones, beds, trees, irrigation zones, hardscape and obstacles. Area and
+-- perimeter are computed from the geometry when it is saved.
+CREATE TABLE IF NOT EXISTS property_features (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    property_id UUID NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    feature_type VARCHAR(30) NOT NULL CHECK (feature_type IN (
+        'turf', 'bed', 'tree', 'irrigation_zone', 'hardscape', 'obstacle'
+    )),
+    name VARCHAR(255),
+    geometry JSONB NOT NULL,
+    area_sq_ft DECIMAL(14,2) NOT NULL DEFAULT 0,
+    perimeter_ft DECIMAL(12,2) NOT NULL DEFAULT 0,
+    notes TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+ALTER TABLE pricing_rules ADD COLUMN IF NOT EXISTS measured_area VARCHAR(30) CHECK (measured_area IN (
+    'turf', 'bed', 'tree', 'irrigation_zone', 'hardscape', 'obstacle'
+));
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_property_features_property ON property_features(tenant_id, property_id, feature_type);
+
+-- Row Level Security
+ALTER TABLE property_features ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY property_feature_tenant_isolation ON property_features
+    FOR ALL
+    USING (
+        is_super_admin() OR 
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_property_features_updated_at BEFORE UPDATE ON property_features FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,9 @@
+-- Rollback Equipment Meters
+
+DROP TRIGGER IF EXISTS update_maintenance_usage_triggers_updated_at ON maintenance_usage_triggers;
+
+DROP POLICY IF EXISTS maintenance_usage_trigger_tenant_isolation ON maintenance_usage_triggers;
+DROP POLICY IF EXISTS equipment_meter_reading_tenant_isolation ON equipment_meter_readings;
+
+DROP TABLE IF EXISTS maintenance_usage_triggers;
+DROP TABLE IF EXISTS equipment_meter_readings;
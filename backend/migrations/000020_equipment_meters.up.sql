@@ -0,0 +1,62 @@
+-- Equipment Meters
+-- Adds engine-hour, mileage and acreage meter readings per equipment, recorded
+-- manually or from completed jobs, and usage-based maintenance triggers that come
+-- due alongside the calendar schedule
+
+-- Cumulative meter readings. usage is how far the meter moved since the
+-- previous reading on the same meter.
+CREATE TABLE IF NOT EXISTS equipment_meter_readings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    equipment_id UUID NOT NULL REFERENCES equipment(id) ON DELETE CASCADE,
+    meter VARCHAR(20) NOT NULL CHECK (meter IN ('hours', 'miles', 'acres')),
+    reading DECIMAL(12,2) NOT NULL CHECK (reading >= 0),
+    usage DECIMAL(12,2) NOT NULL DEFAULT 0 CHECK (usage >= 0),
+    source VARCHAR(20) NOT NULL DEFAULT 'manual' CHECK (source IN ('manual', 'job')),
+    job_id UUID REFERENCES jobs(id) ON DELETE SET NULL,
+    recorded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Service every service_interval units on a meter, whichever of this or the calendar
+-- schedule comes first
+CREATE TABLE IF NOT EXISTS maintenance_usage_triggers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    equipment_id UUID NOT NULL REFERENCES equipment(id) ON DELETE CASCADE,
+    meter VARCHAR(20) NOT NULL CHECK (meter IN ('hours', 'miles', 'acres')),
+    service_interval DECIMAL(12,2) NOT NULL CHECK (service_interval > 0),
+    last_service_reading DECIMAL(12,2) NOT NULL DEFAULT 0,
+    next_due_reading DECIMAL(12,2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(equipment_id, meter)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_equipment_meter_readings_equipment ON equipment_meter_readings(tenant_id, equipment_id, meter, recorded_at DESC);
+CREATE INDEX IF NOT EXISTS idx_equipment_meter_readings_job ON equipment_meter_readings(job_id) WHERE job_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_maintenance_usage_triggers_tenant ON maintenance_usage_triggers(tenant_id);
+
+-- Row Level Security
+ALTER TABLE equipment_meter_readings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE maintenance_usage_triggers ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY equipment_meter_reading_tenant_isolation ON equipment_meter_readings
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY maintenance_usage_trigger_tenant_isolation ON maintenance_usage_triggers
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_maintenance_usage_triggers_updated_at BEFORE UPDATE ON maintenance_usage_triggers FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,11 @@
+-- Rollback Equipment Reservations
+
+DROP TRIGGER IF EXISTS update_equipment_reservations_updated_at ON equipment_reservations;
+
+DROP POLICY IF EXISTS equipment_reservation_tenant_isolation ON equipment_reservations;
+
+DROP TABLE IF EXISTS equipment_reservations;
+
+ALTER TABLE equipment DROP COLUMN IF EXISTS towing_capacity_lbs;
+ALTER TABLE equipment DROP COLUMN IF EXISTS payload_capacity_lbs;
+ALTER TABLE equipment DROP COLUMN IF EXISTS weight_lbs;
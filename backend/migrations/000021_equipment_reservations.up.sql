@@ -0,0 +1,50 @@
+-- Equipment Reservations
+-- Books equipment for jobs when they are scheduled or assigned so two crews
+-- cannot take the same machine at once, and records equipment weights and truck
+-- and trailer capacities for transport checks
+
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE equipment ADD COLUMN IF NOT EXISTS weight_lbs DECIMAL(10,2) CHECK (weight_lbs >= 0);
+ALTER TABLE equipment ADD COLUMN IF NOT EXISTS payload_capacity_lbs DECIMAL(10,2) CHECK (payload_capacity_lbs >= 0);
+ALTER TABLE equipment ADD COLUMN IF NOT EXISTS towing_capacity_lbs DECIMAL(10,2) CHECK (towing_capacity_lbs >= 0);
+
+-- A job's reservations are replaced whenever it is rescheduled or reassigned and
+-- removed when it is completed, cancelled or deleted. Reservations without a job
+-- block equipment out by hand.
+CREATE TABLE IF NOT EXISTS equipment_reservations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    equipment_id UUID NOT NULL REFERENCES equipment(id) ON DELETE CASCADE,
+    job_id UUID REFERENCES jobs(id) ON DELETE CASCADE,
+    crew_id UUID REFERENCES crews(id) ON DELETE SET NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    notes TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (ends_at > starts_at),
+    CONSTRAINT equipment_reservations_no_overlap EXCLUDE USING gist (
+        equipment_id WITH =,
+        tstzrange(starts_at, ends_at) WITH &&
+    )
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_equipment_reservations_tenant_time ON equipment_reservations(tenant_id, starts_at, ends_at);
+CREATE INDEX IF NOT EXISTS idx_equipment_reservations_job ON equipment_reservations(job_id) WHERE job_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_equipment_reservations_crew ON equipment_reservations(crew_id, starts_at) WHERE crew_id IS NOT NULL;
+
+-- Row Level Security
+ALTER TABLE equipment_reservations ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY equipment_reservation_tenant_isolation ON equipment_reservations
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_equipment_reservations_updated_at BEFORE UPDATE ON equipment_reservations FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,16 @@
+-- Rollback Maintenance Work Orders
+
+DROP TRIGGER IF EXISTS update_maintenance_work_orders_updated_at ON maintenance_work_orders;
+DROP TRIGGER IF EXISTS update_parts_updated_at ON parts;
+
+DROP POLICY IF EXISTS work_order_vendor_repair_tenant_isolation ON work_order_vendor_repairs;
+DROP POLICY IF EXISTS work_order_labor_tenant_isolation ON work_order_labor;
+DROP POLICY IF EXISTS work_order_part_tenant_isolation ON work_order_parts;
+DROP POLICY IF EXISTS maintenance_work_order_tenant_isolation ON maintenance_work_orders;
+DROP POLICY IF EXISTS part_tenant_isolation ON parts;
+
+DROP TABLE IF EXISTS work_order_vendor_repairs;
+DROP TABLE IF EXISTS work_order_labor;
+DROP TABLE IF EXISTS work_order_parts;
+DROP TABLE IF EXISTS maintenance_work_orders;
+DROP TABLE IF EXISTS parts;
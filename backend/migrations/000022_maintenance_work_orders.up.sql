@@ -0,0 +1,145 @@
+-- Maintenance Work Orders
+-- Tracks equipment maintenance from opening to close with parts drawn from a
+-- parts inventory, labor by mechanic, outside vendor repairs with their invoices,
+-- and the time equipment spends out of service
+
+-- Parts inventory. Work orders draw stock down as parts are used.
+CREATE TABLE IF NOT EXISTS parts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    sku VARCHAR(100) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    unit VARCHAR(20) NOT NULL DEFAULT 'each',
+    unit_cost DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (unit_cost >= 0),
+    quantity_on_hand DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (quantity_on_hand >= 0),
+    reorder_point DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (reorder_point >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(tenant_id, sku)
+);
+
+-- The equipment is out of service from downtime_started_at until
+-- downtime_ended_at. Costs are totalled from the line items below.
+CREATE TABLE IF NOT EXISTS maintenance_work_orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    equipment_id UUID NOT NULL REFERENCES equipment(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('preventive', 'repair', 'inspection')),
+    priority VARCHAR(20) NOT NULL DEFAULT 'medium' CHECK (priority IN ('low', 'medium', 'high', 'urgent')),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'in_progress', 'closed')),
+    assigned_to UUID REFERENCES users(id) ON DELETE SET NULL,
+    scheduled_date TIMESTAMP WITH TIME ZONE,
+    opened_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    downtime_started_at TIMESTAMP WITH TIME ZONE,
+    expected_return_at TIMESTAMP WITH TIME ZONE,
+    downtime_ended_at TIMESTAMP WITH TIME ZONE,
+    parts_cost DECIMAL(10,2) NOT NULL DEFAULT 0,
+    labor_cost DECIMAL(10,2) NOT NULL DEFAULT 0,
+    vendor_cost DECIMAL(10,2) NOT NULL DEFAULT 0,
+    total_cost DECIMAL(10,2) NOT NULL DEFAULT 0,
+    resolution_notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (downtime_ended_at IS NULL OR downtime_ended_at >= downtime_started_at)
+);
+
+CREATE TABLE IF NOT EXISTS work_order_parts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    work_order_id UUID NOT NULL REFERENCES maintenance_work_orders(id) ON DELETE CASCADE,
+    part_id UUID NOT NULL REFERENCES parts(id) ON DELETE RESTRICT,
+    part_name VARCHAR(255) NOT NULL,
+    quantity DECIMAL(10,2) NOT NULL CHECK (quantity > 0),
+    unit_cost DECIMAL(10,2) NOT NULL CHECK (unit_cost >= 0),
+    total_cost DECIMAL(10,2) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS work_order_labor (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    work_order_id UUID NOT NULL REFERENCES maintenance_work_orders(id) ON DELETE CASCADE,
+    mechanic_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    work_date DATE NOT NULL,
+    hours DECIMAL(6,2) NOT NULL CHECK (hours > 0),
+    hourly_rate DECIMAL(10,2) NOT NULL CHECK (hourly_rate >= 0),
+    total_cost DECIMAL(10,2) NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Invoices are stored in file_attachments with entity_type 'work_order_vendor_repair'
+CREATE TABLE IF NOT EXISTS work_order_vendor_repairs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    work_order_id UUID NOT NULL REFERENCES maintenance_work_orders(id) ON DELETE CASCADE,
+    vendor_name VARCHAR(255) NOT NULL,
+    invoice_number VARCHAR(100),
+    description TEXT,
+    amount DECIMAL(10,2) NOT NULL CHECK (amount >= 0),
+    invoice_attachment_id UUID REFERENCES file_attachments(id) ON DELETE SET NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_parts_tenant_name ON parts(tenant_id, name);
+CREATE INDEX IF NOT EXISTS idx_maintenance_work_orders_equipment ON maintenance_work_orders(tenant_id, equipment_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_maintenance_work_orders_status ON maintenance_work_orders(tenant_id, status);
+CREATE INDEX IF NOT EXISTS idx_maintenance_work_orders_downtime ON maintenance_work_orders(tenant_id, downtime_started_at) WHERE downtime_started_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_work_order_parts_work_order ON work_order_parts(work_order_id);
+CREATE INDEX IF NOT EXISTS idx_work_order_labor_work_order ON work_order_labor(work_order_id);
+CREATE INDEX IF NOT EXISTS idx_work_order_labor_mechanic ON work_order_labor(tenant_id, mechanic_id, work_date);
+CREATE INDEX IF NOT EXISTS idx_work_order_vendor_repairs_work_order ON work_order_vendor_repairs(work_order_id);
+
+-- Row Level Security
+ALTER TABLE parts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE maintenance_work_orders ENABLE ROW LEVEL SECURITY;
+ALTER TABLE work_order_parts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE work_order_labor ENABLE ROW LEVEL SECURITY;
+ALTER TABLE work_order_vendor_repairs ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY part_tenant_isolation ON parts
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY maintenance_work_order_tenant_isolation ON maintenance_work_orders
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY work_order_part_tenant_isolation ON work_order_parts
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY work_order_labor_tenant_isolation ON work_order_labor
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY work_order_vendor_repair_tenant_isolation ON work_order_vendor_repairs
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_parts_updated_at BEFORE UPDATE ON parts FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_maintenance_work_orders_updated_at BEFORE UPDATE ON maintenance_work_orders FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,7 @@
+-- Rollback Equipment Asset Profiles
+
+DROP TRIGGER IF EXISTS update_equipment_asset_profiles_updated_at ON equipment_asset_profiles;
+
+DROP POLICY IF EXISTS equipment_asset_profile_tenant_isolation ON equipment_asset_profiles;
+
+DROP TABLE IF EXISTS equipment_asset_profiles;
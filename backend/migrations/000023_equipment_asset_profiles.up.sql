@@ -0,0 +1,37 @@
+-- Equipment Asset Profiles
+-- Carries equipment as fixed assets: depreciation method and life, replacement,
+-- fuel and downtime costs for ownership and replace-or-repair analysis, and
+-- disposals for the fixed asset register
+
+CREATE TABLE IF NOT EXISTS equipment_asset_profiles (
+    equipment_id UUID PRIMARY KEY REFERENCES equipment(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    depreciation_method VARCHAR(30) NOT NULL CHECK (depreciation_method IN ('straight_line', 'declining_balance', 'macrs')),
+    useful_life_years INTEGER NOT NULL CHECK (useful_life_years > 0),
+    salvage_value DECIMAL(12,2) NOT NULL DEFAULT 0 CHECK (salvage_value >= 0),
+    declining_balance_rate DECIMAL(4,2) NOT NULL DEFAULT 2 CHECK (declining_balance_rate > 0),
+    in_service_date DATE,
+    replacement_cost DECIMAL(12,2) CHECK (replacement_cost >= 0),
+    fuel_cost_per_hour DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (fuel_cost_per_hour >= 0),
+    downtime_cost_per_hour DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (downtime_cost_per_hour >= 0),
+    disposed_at DATE,
+    disposal_proceeds DECIMAL(12,2) CHECK (disposal_proceeds >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_equipment_asset_profiles_tenant ON equipment_asset_profiles(tenant_id);
+
+-- Row Level Security
+ALTER TABLE equipment_asset_profiles ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY equipment_asset_profile_tenant_isolation ON equipment_asset_profiles
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_equipment_asset_profiles_updated_at BEFORE UPDATE ON equipment_asset_profiles FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
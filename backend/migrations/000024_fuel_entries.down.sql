@@ -0,0 +1,10 @@
+-- Rollback Fuel Entries
+
+DROP POLICY IF EXISTS fuel_entry_tenant_isolation ON fuel_entries;
+
+DELETE FROM equipment_meter_readings WHERE source = 'fuel';
+ALTER TABLE equipment_meter_readings DROP CONSTRAINT IF EXISTS equipment_meter_readings_source_check;
+ALTER TABLE equipment_meter_readings ADD CONSTRAINT equipment_meter_readings_source_check
+    CHECK (source IN ('manual', 'job'));
+
+DROP TABLE IF EXISTS fuel_entries;
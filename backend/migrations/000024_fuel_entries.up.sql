@@ -0,0 +1,45 @@
+-- Fuel Entries
+-- Logs fuel purchases per vehicle or piece of equipment with the odometer or
+-- hour meter at the pump, so fuel efficiency can be trended, suspicious fills
+-- flagged and fuel cost allocated to jobs
+
+CREATE TABLE IF NOT EXISTS fuel_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    equipment_id UUID NOT NULL REFERENCES equipment(id) ON DELETE CASCADE,
+    fueled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    fuel_type VARCHAR(20) NOT NULL CHECK (fuel_type IN ('gasoline', 'diesel')),
+    gallons DECIMAL(10,3) NOT NULL CHECK (gallons > 0),
+    price_per_gallon DECIMAL(10,3) NOT NULL CHECK (price_per_gallon >= 0),
+    total_cost DECIMAL(10,2) NOT NULL CHECK (total_cost >= 0),
+    odometer DECIMAL(12,1) CHECK (odometer >= 0),
+    engine_hours DECIMAL(12,1) CHECK (engine_hours >= 0),
+    meter VARCHAR(20) CHECK (meter IN ('hours', 'miles')),
+    usage DECIMAL(12,1),
+    efficiency DECIMAL(10,3),
+    anomaly_flags TEXT[] NOT NULL DEFAULT '{}',
+    vendor VARCHAR(255),
+    recorded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Fuel entries also record odometer and hour meter readings
+ALTER TABLE equipment_meter_readings DROP CONSTRAINT IF EXISTS equipment_meter_readings_source_check;
+ALTER TABLE equipment_meter_readings ADD CONSTRAINT equipment_meter_readings_source_check
+    CHECK (source IN ('manual', 'job', 'fuel'));
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_fuel_entries_tenant ON fuel_entries(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_fuel_entries_equipment ON fuel_entries(equipment_id, fueled_at);
+CREATE INDEX IF NOT EXISTS idx_fuel_entries_anomalies ON fuel_entries(tenant_id, fueled_at) WHERE cardinality(anomaly_flags) > 0;
+
+-- Row Level Security
+ALTER TABLE fuel_entries ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY fuel_entry_tenant_isolation ON fuel_entries
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
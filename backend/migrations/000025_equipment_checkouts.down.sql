@@ -0,0 +1,9 @@
+-- Rollback Equipment Checkouts
+
+DROP TRIGGER IF EXISTS update_equipment_checkouts_updated_at ON equipment_checkouts;
+
+DROP POLICY IF EXISTS equipment_checkout_tenant_isolation ON equipment_checkouts;
+DROP POLICY IF EXISTS equipment_asset_tag_tenant_isolation ON equipment_asset_tags;
+
+DROP TABLE IF EXISTS equipment_checkouts;
+DROP TABLE IF EXISTS equipment_asset_tags;
@@ -0,0 +1,64 @@
+-- Equipment Checkouts
+-- QR asset tags for equipment, and checkouts recording who took equipment from
+-- the shop, when it is due back and the condition it came back in
+
+CREATE TABLE IF NOT EXISTS equipment_asset_tags (
+    equipment_id UUID PRIMARY KEY REFERENCES equipment(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    code VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(tenant_id, code)
+);
+
+-- Equipment is checked out to a user or to a crew, and stays out until it is
+-- checked back in
+CREATE TABLE IF NOT EXISTS equipment_checkouts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    equipment_id UUID NOT NULL REFERENCES equipment(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    crew_id UUID REFERENCES crews(id) ON DELETE SET NULL,
+    checked_out_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    checked_out_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    due_back_at TIMESTAMP WITH TIME ZONE,
+    checkout_notes TEXT,
+    checked_in_at TIMESTAMP WITH TIME ZONE,
+    checked_in_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    return_condition VARCHAR(20) CHECK (return_condition IN ('good', 'needs_service', 'damaged')),
+    return_notes TEXT,
+    overdue_notified_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (checked_in_at IS NOT NULL OR user_id IS NOT NULL OR crew_id IS NOT NULL),
+    CHECK (due_back_at IS NULL OR due_back_at > checked_out_at)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_equipment_asset_tags_tenant ON equipment_asset_tags(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_equipment_checkouts_tenant ON equipment_checkouts(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_equipment_checkouts_equipment ON equipment_checkouts(equipment_id, checked_out_at);
+CREATE INDEX IF NOT EXISTS idx_equipment_checkouts_user ON equipment_checkouts(user_id) WHERE checked_in_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_equipment_checkouts_crew ON equipment_checkouts(crew_id) WHERE checked_in_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_equipment_checkouts_due ON equipment_checkouts(due_back_at) WHERE checked_in_at IS NULL AND overdue_notified_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_equipment_checkouts_open ON equipment_checkouts(equipment_id) WHERE checked_in_at IS NULL;
+
+-- Row Level Security
+ALTER TABLE equipment_asset_tags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE equipment_checkouts ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY equipment_asset_tag_tenant_isolation ON equipment_asset_tags
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY equipment_checkout_tenant_isolation ON equipment_checkouts
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_equipment_checkouts_updated_at BEFORE UPDATE ON equipment_checkouts FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,9 @@
+-- Rollback User Certifications
+
+DROP TRIGGER IF EXISTS update_user_certifications_updated_at ON user_certifications;
+
+DROP POLICY IF EXISTS service_certification_requirement_tenant_isolation ON service_certification_requirements;
+DROP POLICY IF EXISTS user_certification_tenant_isolation ON user_certifications;
+
+DROP TABLE IF EXISTS service_certification_requirements;
+DROP TABLE IF EXISTS user_certifications;
@@ -0,0 +1,61 @@
+-- User Certifications
+-- Licences and certifications held by users, with expiry dates and scanned
+-- documents, and the certifications services require of assigned workers
+
+CREATE TABLE IF NOT EXISTS user_certifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    certification_type VARCHAR(30) NOT NULL CHECK (certification_type IN (
+        'pesticide_applicator', 'cdl', 'irrigation', 'osha'
+    )),
+    name VARCHAR(255),
+    license_number VARCHAR(100),
+    issuing_authority VARCHAR(255),
+    issued_on DATE,
+    expires_on DATE,
+    document_attachment_id UUID REFERENCES file_attachments(id) ON DELETE SET NULL,
+    -- The reminder threshold, in days before expiry, last sent; reset on renewal
+    reminder_days_sent INTEGER,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (expires_on IS NULL OR issued_on IS NULL OR expires_on >= issued_on)
+);
+
+CREATE TABLE IF NOT EXISTS service_certification_requirements (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    service_id UUID NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    certification_type VARCHAR(30) NOT NULL CHECK (certification_type IN (
+        'pesticide_applicator', 'cdl', 'irrigation', 'osha'
+    )),
+    enforcement VARCHAR(10) NOT NULL DEFAULT 'block' CHECK (enforcement IN ('block', 'warn')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (service_id, certification_type)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_user_certifications_user ON user_certifications(tenant_id, user_id, certification_type);
+CREATE INDEX IF NOT EXISTS idx_user_certifications_expiry ON user_certifications(expires_on) WHERE expires_on IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_service_certification_requirements_tenant ON service_certification_requirements(tenant_id);
+
+-- Row Level Security
+ALTER TABLE user_certifications ENABLE ROW LEVEL SECURITY;
+ALTER TABLE service_certification_requirements ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY user_certification_tenant_isolation ON user_certifications
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY service_certification_requirement_tenant_isolation ON service_certification_requirements
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_user_certifications_updated_at BEFORE UPDATE ON user_certifications FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
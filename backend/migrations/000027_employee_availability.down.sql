@@ -0,0 +1,12 @@
+-- Rollback Employee Availability
+
+DROP TRIGGER IF EXISTS update_time_off_requests_updated_at ON time_off_requests;
+DROP TRIGGER IF EXISTS update_user_working_hours_updated_at ON user_working_hours;
+
+DROP POLICY IF EXISTS tenant_holiday_tenant_isolation ON tenant_holidays;
+DROP POLICY IF EXISTS time_off_request_tenant_isolation ON time_off_requests;
+DROP POLICY IF EXISTS user_working_hours_tenant_isolation ON user_working_hours;
+
+DROP TABLE IF EXISTS tenant_holidays;
+DROP TABLE IF EXISTS time_off_requests;
+DROP TABLE IF EXISTS user_working_hours;
@@ -0,0 +1,9 @@
+-- Rollback Daily Crew Rosters
+
+DROP TRIGGER IF EXISTS update_crew_rosters_updated_at ON crew_rosters;
+
+DROP POLICY IF EXISTS crew_roster_member_tenant_isolation ON crew_roster_members;
+DROP POLICY IF EXISTS crew_roster_tenant_isolation ON crew_rosters;
+
+DROP TABLE IF EXISTS crew_roster_members;
+DROP TABLE IF EXISTS crew_rosters;
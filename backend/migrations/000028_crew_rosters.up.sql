@@ -0,0 +1,50 @@
+-- Daily Crew Rosters
+-- Who rides with each crew on a given day and the equipment they take,
+-- overriding the crew's standing membership for that date
+
+CREATE TABLE IF NOT EXISTS crew_rosters (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    crew_id UUID NOT NULL REFERENCES crews(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    equipment_ids UUID[] NOT NULL DEFAULT '{}',
+    notes TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (crew_id, date)
+);
+
+CREATE TABLE IF NOT EXISTS crew_roster_members (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    roster_id UUID NOT NULL REFERENCES crew_rosters(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL DEFAULT 'member',
+    UNIQUE (roster_id, user_id)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_crew_rosters_date ON crew_rosters(tenant_id, date);
+CREATE INDEX IF NOT EXISTS idx_crew_roster_members_user ON crew_roster_members(tenant_id, user_id);
+
+-- Row Level Security
+ALTER TABLE crew_rosters ENABLE ROW LEVEL SECURITY;
+ALTER TABLE crew_roster_members ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY crew_roster_tenant_isolation ON crew_rosters
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY crew_roster_member_tenant_isolation ON crew_roster_members
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_crew_rosters_updated_at BEFORE UPDATE ON crew_rosters FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,19 @@
+-- Rollback Payroll
+
+DROP TRIGGER IF EXISTS update_pay_periods_updated_at ON pay_periods;
+DROP TRIGGER IF EXISTS update_time_entries_updated_at ON time_entries;
+DROP TRIGGER IF EXISTS update_user_pay_plans_updated_at ON user_pay_plans;
+
+DROP POLICY IF EXISTS payroll_line_tenant_isolation ON payroll_lines;
+DROP POLICY IF EXISTS payroll_register_entry_tenant_isolation ON payroll_register_entries;
+DROP POLICY IF EXISTS pay_period_tenant_isolation ON pay_periods;
+DROP POLICY IF EXISTS time_entry_tenant_isolation ON time_entries;
+DROP POLICY IF EXISTS pay_rule_tenant_isolation ON pay_rules;
+DROP POLICY IF EXISTS user_pay_plan_tenant_isolation ON user_pay_plans;
+
+DROP TABLE IF EXISTS payroll_lines;
+DROP TABLE IF EXISTS payroll_register_entries;
+DROP TABLE IF EXISTS pay_periods;
+DROP TABLE IF EXISTS time_entries;
+DROP TABLE IF EXISTS pay_rules;
+DROP TABLE IF EXISTS user_pay_plans;
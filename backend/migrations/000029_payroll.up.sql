@@ -0,0 +1,152 @@
+-- Payroll
+-- Pay plans with hourly, piece-rate, revenue percentage and crew-lead bonus
+-- rules, time entries, and pay periods whose register is locked on approval
+
+CREATE TABLE IF NOT EXISTS user_pay_plans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- The user's number in the payroll provider
+    employee_number VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (tenant_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS pay_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('hourly', 'piece_rate', 'revenue_percent', 'crew_lead_bonus')),
+    rate DECIMAL(12,4) NOT NULL CHECK (rate >= 0),
+    service_id UUID REFERENCES services(id) ON DELETE CASCADE,
+    effective_from DATE,
+    effective_to DATE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (effective_to IS NULL OR effective_from IS NULL OR effective_to >= effective_from)
+);
+
+CREATE TABLE IF NOT EXISTS time_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    job_id UUID REFERENCES jobs(id) ON DELETE SET NULL,
+    clock_in TIMESTAMP WITH TIME ZONE NOT NULL,
+    clock_out TIMESTAMP WITH TIME ZONE,
+    break_minutes INTEGER NOT NULL DEFAULT 0 CHECK (break_minutes >= 0),
+    notes TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (clock_out IS NULL OR clock_out > clock_in)
+);
+
+CREATE TABLE IF NOT EXISTS pay_periods (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'approved')),
+    total_gross DECIMAL(12,2) NOT NULL DEFAULT 0,
+    calculated_at TIMESTAMP WITH TIME ZONE,
+    approved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    approved_at TIMESTAMP WITH TIME ZONE,
+    exported_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (end_date >= start_date)
+);
+
+CREATE TABLE IF NOT EXISTS payroll_register_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    pay_period_id UUID NOT NULL REFERENCES pay_periods(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    employee_number VARCHAR(50),
+    hours_worked DECIMAL(10,2) NOT NULL DEFAULT 0,
+    regular_hours DECIMAL(10,2) NOT NULL DEFAULT 0,
+    overtime_hours DECIMAL(10,2) NOT NULL DEFAULT 0,
+    regular_pay DECIMAL(12,2) NOT NULL DEFAULT 0,
+    overtime_pay DECIMAL(12,2) NOT NULL DEFAULT 0,
+    piece_rate_pay DECIMAL(12,2) NOT NULL DEFAULT 0,
+    commission_pay DECIMAL(12,2) NOT NULL DEFAULT 0,
+    bonus_pay DECIMAL(12,2) NOT NULL DEFAULT 0,
+    gross_pay DECIMAL(12,2) NOT NULL DEFAULT 0,
+    UNIQUE (pay_period_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS payroll_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    entry_id UUID NOT NULL REFERENCES payroll_register_entries(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('regular', 'overtime', 'piece_rate', 'commission', 'bonus')),
+    description TEXT NOT NULL,
+    job_id UUID REFERENCES jobs(id) ON DELETE SET NULL,
+    service_id UUID REFERENCES services(id) ON DELETE SET NULL,
+    quantity DECIMAL(12,4) NOT NULL DEFAULT 0,
+    rate DECIMAL(12,4) NOT NULL DEFAULT 0,
+    amount DECIMAL(12,2) NOT NULL DEFAULT 0
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_pay_rules_user ON pay_rules(tenant_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_time_entries_user ON time_entries(tenant_id, user_id, clock_in);
+CREATE INDEX IF NOT EXISTS idx_time_entries_job ON time_entries(job_id) WHERE job_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_pay_periods_dates ON pay_periods(tenant_id, start_date, end_date);
+CREATE INDEX IF NOT EXISTS idx_payroll_lines_entry ON payroll_lines(entry_id);
+
+-- Row Level Security
+ALTER TABLE user_pay_plans ENABLE ROW LEVEL SECURITY;
+ALTER TABLE pay_rules ENABLE ROW LEVEL SECURITY;
+ALTER TABLE time_entries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE pay_periods ENABLE ROW LEVEL SECURITY;
+ALTER TABLE payroll_register_entries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE payroll_lines ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY user_pay_plan_tenant_isolation ON user_pay_plans
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY pay_rule_tenant_isolation ON pay_rules
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY time_entry_tenant_isolation ON time_entries
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY pay_period_tenant_isolation ON pay_periods
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY payroll_register_entry_tenant_isolation ON payroll_register_entries
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+CREATE POLICY payroll_line_tenant_isolation ON payroll_lines
+    FOR ALL
+    USING (
+        is_super_admin() OR
+        tenant_id = current_tenant_id()
+    );
+
+-- Triggers
+CREATE TRIGGER update_user_pay_plans_updated_at BEFORE UPDATE ON user_pay_plans FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_time_entries_updated_at BEFORE UPDATE ON time_entries FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_pay_periods_updated_at BEFORE UPDATE ON pay_periods FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
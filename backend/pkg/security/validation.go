@@ -31,7 +31,7 @@ func NewInputValidator() *InputValidator {
 
 	// XSS patterns
 	xssPatterns := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)<script\b[^<]*(?:(?!<\/script>)<[^<]*)*<\/script>`),
+		regexp.MustCompile(`(?is)<script\b.*?</script>`),
 		regexp.MustCompile(`(?i)javascript:`),
 		regexp.MustCompile(`(?i)vbscript:`),
 		regexp.MustCompile(`(?i)on\w+\s*=`),
@@ -0,0 +1,285 @@
+package accounting_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// fakeAccountingRepo keeps connections and sync records in memory and selects pending
+// customers the way the repository's change-tracking query does
+type fakeAccountingRepo struct {
+	services.AccountingRepository
+	connection *domain.AccountingConnection
+	customers  map[uuid.UUID]*domain.EnhancedCustomer
+	records    map[uuid.UUID]*domain.AccountingSyncRecord
+}
+
+func (r *fakeAccountingRepo) ListMappings(ctx context.Context, tenantID uuid.UUID) ([]*domain.AccountMapping, error) {
+	return nil, nil
+}
+
+func (r *fakeAccountingRepo) GetConnection(ctx context.Context, tenantID uuid.UUID, provider string) (*domain.AccountingConnection, error) {
+	if r.connection == nil || r.connection.Provider != provider {
+		return nil, nil
+	}
+	return r.connection, nil
+}
+
+func (r *fakeAccountingRepo) UpsertConnection(ctx context.Context, connection *domain.AccountingConnection) error {
+	r.connection = connection
+	return nil
+}
+
+func (r *fakeAccountingRepo) GetPendingChanges(ctx context.Context, tenantID uuid.UUID, provider, entityType string, maxAttempts, limit int) ([]uuid.UUID, error) {
+	if entityType != domain.AccountingEntityCustomer {
+		return nil, nil
+	}
+	var ids []uuid.UUID
+	for id, customer := range r.customers {
+		record, ok := r.records[id]
+		switch {
+		case !ok,
+			record.SourceUpdatedAt == nil || customer.UpdatedAt.After(*record.SourceUpdatedAt),
+			record.Status == domain.AccountingSyncStatusFailed && record.Attempts < maxAttempts:
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeAccountingRepo) GetSyncRecord(ctx context.Context, tenantID uuid.UUID, provider, entityType string, entityID uuid.UUID) (*domain.AccountingSyncRecord, error) {
+	record, ok := r.records[entityID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *record
+	return &copied, nil
+}
+
+func (r *fakeAccountingRepo) UpsertSyncRecord(ctx context.Context, record *domain.AccountingSyncRecord) error {
+	copied := *record
+	r.records[record.EntityID] = &copied
+	return nil
+}
+
+type fakeCustomerRepo struct {
+	services.CustomerRepository
+	customers map[uuid.UUID]*domain.EnhancedCustomer
+}
+
+func (r *fakeCustomerRepo) GetByID(ctx context.Context, tenantID, customerID uuid.UUID) (*domain.EnhancedCustomer, error) {
+	customer, ok := r.customers[customerID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *customer
+	return &copied, nil
+}
+
+type fakeAuditService struct {
+	services.AuditService
+}
+
+func (fakeAuditService) LogAction(ctx context.Context, req *services.AuditLogRequest) error {
+	return nil
+}
+
+// quickBooksServer records the requests made to a fake QuickBooks Online API
+type quickBooksServer struct {
+	mu       sync.Mutex
+	requests []string
+	bodies   []map[string]interface{}
+}
+
+func (q *quickBooksServer) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		assert.Equal(t, "Bearer access-token", r.Header.Get("Authorization"))
+		q.requests = append(q.requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(data, &body))
+			q.bodies = append(q.bodies, body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Customer":{"Id":"58","SyncToken":"3"}}`))
+	}
+}
+
+func (q *quickBooksServer) reset() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.requests = nil
+	q.bodies = nil
+}
+
+func TestQuickBooksSyncPushesOnceAndResyncsChanges(t *testing.T) {
+	tenantID := uuid.New()
+	ctx := context.WithValue(context.Background(), "tenant_id", tenantID)
+
+	qb := &quickBooksServer{}
+	server := httptest.NewServer(qb.handler(t))
+	defer server.Close()
+
+	customer := &domain.EnhancedCustomer{}
+	customer.ID = uuid.New()
+	customer.TenantID = tenantID
+	customer.FirstName = "Ada"
+	customer.LastName = "Green"
+	customer.UpdatedAt = time.Now().Add(-time.Hour)
+	customers := map[uuid.UUID]*domain.EnhancedCustomer{customer.ID: customer}
+
+	realmID := "9130"
+	accessToken := "access-token"
+	repo := &fakeAccountingRepo{
+		customers: customers,
+		records:   map[uuid.UUID]*domain.AccountingSyncRecord{},
+		connection: &domain.AccountingConnection{
+			ID:               uuid.New(),
+			TenantID:         tenantID,
+			Provider:         domain.AccountingProviderQuickBooks,
+			ExternalTenantID: &realmID,
+			AccessToken:      &accessToken,
+			Status:           domain.AccountingConnectionStatusActive,
+		},
+	}
+
+	adapter := services.NewQuickBooksSyncAdapter(server.URL, "", "", "", server.Client())
+	svc := services.NewAccountingService(repo, nil, nil, &fakeCustomerRepo{customers: customers}, nil, fakeAuditService{}, log.New(io.Discard, "", 0), adapter)
+
+	// First sync creates the customer
+	result, err := svc.SyncProvider(ctx, domain.AccountingProviderQuickBooks)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Zero(t, result.Updated)
+	assert.Zero(t, result.Failed)
+	assert.Equal(t, []string{"POST /v3/company/9130/customer"}, qb.requests)
+	assert.Equal(t, "Ada Green", qb.bodies[0]["DisplayName"])
+	assert.NotContains(t, qb.bodies[0], "Id")
+
+	record := repo.records[customer.ID]
+	require.NotNil(t, record)
+	require.NotNil(t, record.ExternalID)
+	assert.Equal(t, "58", *record.ExternalID)
+	assert.Equal(t, domain.AccountingSyncStatusSynced, record.Status)
+	require.NotNil(t, record.SourceUpdatedAt)
+	assert.True(t, record.SourceUpdatedAt.Equal(customer.UpdatedAt))
+
+	// Unchanged records are not pushed again
+	qb.reset()
+	result, err = svc.SyncProvider(ctx, domain.AccountingProviderQuickBooks)
+	require.NoError(t, err)
+	assert.Zero(t, result.Created)
+	assert.Zero(t, result.Updated)
+	assert.Empty(t, qb.requests)
+
+	// Editing the customer moves updated_at past source_updated_at and triggers an update
+	qb.reset()
+	customer.LastName = "Greenwood"
+	customer.UpdatedAt = time.Now()
+	result, err = svc.SyncProvider(ctx, domain.AccountingProviderQuickBooks)
+	require.NoError(t, err)
+	assert.Zero(t, result.Created)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, []string{
+		"GET /v3/company/9130/customer/58",
+		"POST /v3/company/9130/customer",
+	}, qb.requests)
+	require.Len(t, qb.bodies, 1)
+	assert.Equal(t, "58", qb.bodies[0]["Id"])
+	assert.Equal(t, "3", qb.bodies[0]["SyncToken"])
+	assert.Equal(t, true, qb.bodies[0]["sparse"])
+	assert.Equal(t, "Greenwood", qb.bodies[0]["FamilyName"])
+	assert.True(t, repo.records[customer.ID].SourceUpdatedAt.Equal(customer.UpdatedAt))
+}
+
+func TestQuickBooksSyncRecordsProviderErrors(t *testing.T) {
+	tenantID := uuid.New()
+	ctx := context.WithValue(context.Background(), "tenant_id", tenantID)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"Fault":{"Error":[{"Message":"Duplicate Name Exists Error","Detail":"The name supplied already exists."}]}}`))
+	}))
+	defer server.Close()
+
+	customer := &domain.EnhancedCustomer{}
+	customer.ID = uuid.New()
+	customer.FirstName = "Ada"
+	customer.UpdatedAt = time.Now()
+	customers := map[uuid.UUID]*domain.EnhancedCustomer{customer.ID: customer}
+
+	realmID := "9130"
+	accessToken := "access-token"
+	repo := &fakeAccountingRepo{
+		customers: customers,
+		records:   map[uuid.UUID]*domain.AccountingSyncRecord{},
+		connection: &domain.AccountingConnection{
+			ID:               uuid.New(),
+			TenantID:         tenantID,
+			Provider:         domain.AccountingProviderQuickBooks,
+			ExternalTenantID: &realmID,
+			AccessToken:      &accessToken,
+			Status:           domain.AccountingConnectionStatusActive,
+		},
+	}
+
+	adapter := services.NewQuickBooksSyncAdapter(server.URL, "", "", "", server.Client())
+	svc := services.NewAccountingService(repo, nil, nil, &fakeCustomerRepo{customers: customers}, nil, fakeAuditService{}, log.New(io.Discard, "", 0), adapter)
+
+	result, err := svc.SyncProvider(ctx, domain.AccountingProviderQuickBooks)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Error, "Duplicate Name Exists Error")
+
+	record := repo.records[customer.ID]
+	require.NotNil(t, record)
+	assert.Equal(t, domain.AccountingSyncStatusFailed, record.Status)
+	assert.Nil(t, record.ExternalID)
+	assert.Equal(t, domain.AccountingConnectionStatusError, repo.connection.Status)
+}
+
+func TestConnectProviderAcceptsQuickBooks(t *testing.T) {
+	tenantID := uuid.New()
+	ctx := context.WithValue(context.Background(), "tenant_id", tenantID)
+
+	repo := &fakeAccountingRepo{records: map[uuid.UUID]*domain.AccountingSyncRecord{}}
+	svc := services.NewAccountingService(repo, nil, nil, nil, nil, fakeAuditService{}, log.New(io.Discard, "", 0),
+		services.NewQuickBooksSyncAdapter("", "", "", "", nil))
+
+	realmID := "9130"
+	connection, err := svc.ConnectProvider(ctx, &services.AccountingConnectionRequest{
+		Provider:         domain.AccountingProviderQuickBooks,
+		ExternalTenantID: &realmID,
+		AccessToken:      "access-token",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.AccountingConnectionStatusActive, connection.Status)
+	assert.Equal(t, tenantID, connection.TenantID)
+
+	_, err = svc.ConnectProvider(ctx, &services.AccountingConnectionRequest{
+		Provider:    domain.AccountingProviderXero,
+		AccessToken: "access-token",
+	})
+	assert.Error(t, err)
+}
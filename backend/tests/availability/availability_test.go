@@ -0,0 +1,137 @@
+package availability_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, time.June, day, hour, minute, 0, 0, time.UTC)
+}
+
+func date(year int, month time.Month, day int) *time.Time {
+	d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &d
+}
+
+func shift(userID uuid.UUID, weekday time.Weekday, start, end string) *domain.WorkingHours {
+	return &domain.WorkingHours{UserID: userID, Weekday: int(weekday), StartTime: start, EndTime: end}
+}
+
+func TestValidateWorkingHours(t *testing.T) {
+	assert.NoError(t, services.ValidateWorkingHours(nil))
+	assert.NoError(t, services.ValidateWorkingHours([]services.WorkingHoursRequest{
+		{Weekday: 1, StartTime: "07:00", EndTime: "11:00"},
+		{Weekday: 1, StartTime: "12:00", EndTime: "16:00"},
+		{Weekday: 6, StartTime: "08:00", EndTime: "12:00"},
+	}))
+
+	assert.Error(t, services.ValidateWorkingHours([]services.WorkingHoursRequest{{Weekday: 7, StartTime: "08:00", EndTime: "12:00"}}))
+	assert.Error(t, services.ValidateWorkingHours([]services.WorkingHoursRequest{{Weekday: 2, StartTime: "8am", EndTime: "12:00"}}))
+	assert.Error(t, services.ValidateWorkingHours([]services.WorkingHoursRequest{{Weekday: 2, StartTime: "12:00", EndTime: "08:00"}}))
+	assert.Error(t, services.ValidateWorkingHours([]services.WorkingHoursRequest{
+		{Weekday: 3, StartTime: "08:00", EndTime: "12:00"},
+		{Weekday: 3, StartTime: "11:00", EndTime: "15:00"},
+	}), "overlapping shifts")
+
+	// The same day in different seasons does not overlap
+	assert.NoError(t, services.ValidateWorkingHours([]services.WorkingHoursRequest{
+		{Weekday: 3, StartTime: "06:00", EndTime: "16:00", EffectiveFrom: date(2026, time.April, 1), EffectiveTo: date(2026, time.October, 31)},
+		{Weekday: 3, StartTime: "08:00", EndTime: "12:00", EffectiveFrom: date(2026, time.November, 1)},
+	}))
+}
+
+func TestValidateTimeOffRequest(t *testing.T) {
+	assert.NoError(t, services.ValidateTimeOffRequest(&services.TimeOffCreateRequest{
+		Type: domain.TimeOffVacation, StartsAt: at(8, 0, 0), EndsAt: at(13, 0, 0),
+	}))
+	assert.Error(t, services.ValidateTimeOffRequest(&services.TimeOffCreateRequest{
+		Type: "sabbatical", StartsAt: at(8, 0, 0), EndsAt: at(13, 0, 0),
+	}))
+	assert.Error(t, services.ValidateTimeOffRequest(&services.TimeOffCreateRequest{
+		Type: domain.TimeOffSick, StartsAt: at(8, 0, 0), EndsAt: at(8, 0, 0),
+	}))
+}
+
+func TestShiftsOn(t *testing.T) {
+	userID := uuid.New()
+	monday := at(1, 0, 0)
+	require.Equal(t, time.Monday, monday.Weekday())
+
+	shifts := services.ShiftsOn(nil, monday)
+	require.Len(t, shifts, 1, "no template works the default shift")
+	assert.Equal(t, at(1, 8, 0), shifts[0].Start)
+	assert.Equal(t, at(1, 17, 0), shifts[0].End)
+
+	partTime := []*domain.WorkingHours{
+		shift(userID, time.Monday, "13:00", "17:30"),
+		shift(userID, time.Monday, "07:30", "11:00"),
+		shift(userID, time.Wednesday, "07:30", "11:00"),
+	}
+	shifts = services.ShiftsOn(partTime, monday)
+	require.Len(t, shifts, 2)
+	assert.Equal(t, at(1, 7, 30), shifts[0].Start)
+	assert.Equal(t, at(1, 17, 30), shifts[1].End)
+	assert.Empty(t, services.ShiftsOn(partTime, at(2, 0, 0)), "not scheduled on Tuesdays")
+
+	seasonal := shift(userID, time.Monday, "06:00", "16:00")
+	seasonal.EffectiveFrom = date(2026, time.April, 1)
+	seasonal.EffectiveTo = date(2026, time.September, 30)
+	assert.Len(t, services.ShiftsOn([]*domain.WorkingHours{seasonal}, monday), 1)
+	assert.Empty(t, services.ShiftsOn([]*domain.WorkingHours{seasonal}, time.Date(2026, time.November, 2, 0, 0, 0, 0, time.UTC)), "out of season")
+}
+
+func TestWorkingWindows(t *testing.T) {
+	userID, otherID := uuid.New(), uuid.New()
+	week := services.TimeRange{Start: at(1, 0, 0), End: at(4, 0, 0)} // Monday through Wednesday
+
+	holidays := []*domain.TenantHoliday{{Date: *date(2026, time.June, 2), Name: "Founders Day"}}
+	timeOff := []*domain.TimeOffRequest{
+		{UserID: userID, Type: domain.TimeOffPersonal, Status: domain.TimeOffApproved, StartsAt: at(3, 12, 0), EndsAt: at(3, 15, 0)},
+		{UserID: userID, Type: domain.TimeOffVacation, Status: domain.TimeOffPending, StartsAt: at(1, 0, 0), EndsAt: at(2, 0, 0)},
+		{UserID: otherID, Type: domain.TimeOffSick, Status: domain.TimeOffApproved, StartsAt: at(1, 0, 0), EndsAt: at(4, 0, 0)},
+	}
+
+	windows, conflicts := services.WorkingWindows(userID, nil, timeOff, holidays, week)
+	assert.Equal(t, []services.TimeRange{
+		{Start: at(1, 8, 0), End: at(1, 17, 0)},
+		{Start: at(3, 8, 0), End: at(3, 12, 0)},
+		{Start: at(3, 15, 0), End: at(3, 17, 0)},
+	}, windows, "pending time off does not count")
+	require.Len(t, conflicts, 2)
+	assert.Equal(t, "Holiday: Founders Day", conflicts[0].Reason)
+	assert.Equal(t, "Time off: personal", conflicts[1].Reason)
+
+	windows, _ = services.WorkingWindows(otherID, nil, timeOff, holidays, week)
+	assert.Empty(t, windows, "off sick all week")
+
+	// Another user's template does not apply
+	windows, _ = services.WorkingWindows(userID, []*domain.WorkingHours{shift(otherID, time.Monday, "06:00", "10:00")}, nil, nil,
+		services.TimeRange{Start: at(1, 0, 0), End: at(2, 0, 0)})
+	assert.Equal(t, []services.TimeRange{{Start: at(1, 8, 0), End: at(1, 17, 0)}}, windows)
+}
+
+func TestSubtractAndMergeTimeRanges(t *testing.T) {
+	windows := []services.TimeRange{{Start: at(1, 8, 0), End: at(1, 17, 0)}}
+	busy := []services.TimeRange{
+		{Start: at(1, 13, 0), End: at(1, 14, 0)},
+		{Start: at(1, 7, 0), End: at(1, 9, 0)},
+		{Start: at(1, 16, 0), End: at(1, 18, 0)},
+	}
+	assert.Equal(t, []services.TimeRange{
+		{Start: at(1, 9, 0), End: at(1, 13, 0)},
+		{Start: at(1, 14, 0), End: at(1, 16, 0)},
+	}, services.SubtractTimeRanges(windows, busy))
+
+	assert.Equal(t, []services.TimeRange{
+		{Start: at(1, 7, 0), End: at(1, 9, 0)},
+		{Start: at(1, 13, 0), End: at(1, 18, 0)},
+	}, services.MergeTimeRanges(append(busy, services.TimeRange{Start: at(1, 14, 0), End: at(1, 16, 0)})))
+}
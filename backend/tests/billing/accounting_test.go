@@ -0,0 +1,188 @@
+package billing_test
+
+import (
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func strPtr(s string) *string { return &s }
+
+func accountingFixture(t *testing.T) (*services.ChartOfAccounts, *services.AccountingInvoice, *services.AccountingPayment) {
+	t.Helper()
+
+	mowing := &domain.Service{ID: uuid.New(), Name: "Lawn Mowing", Category: "maintenance"}
+	mulch := &domain.Service{ID: uuid.New(), Name: "Mulch Install", Category: "installation"}
+
+	chart := services.NewChartOfAccounts([]*domain.AccountMapping{
+		{MappingType: domain.AccountMappingTypeIncome, MappingKey: mowing.ID.String(), AccountCode: strPtr("4010"), AccountName: "Mowing Income", AccountType: domain.AccountTypeIncome},
+		{MappingType: domain.AccountMappingTypeIncomeCategory, MappingKey: "installation", AccountCode: strPtr("4020"), AccountName: "Installation Income", AccountType: domain.AccountTypeIncome},
+		{MappingType: domain.AccountMappingTypeDeposit, MappingKey: "card", AccountCode: strPtr("1010"), AccountName: "Stripe Clearing", AccountType: domain.AccountTypeBank},
+	})
+
+	issued := time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)
+	customer := &domain.EnhancedCustomer{
+		Customer: domain.Customer{
+			ID: uuid.New(), FirstName: "Jane", LastName: "Doe",
+			Email: strPtr("jane@example.com"), City: strPtr("Austin"), State: strPtr("TX"), ZipCode: strPtr("78701"),
+		},
+		CustomerType: "residential",
+	}
+	invoice := &domain.Invoice{
+		ID:            uuid.New(),
+		CustomerID:    customer.ID,
+		InvoiceNumber: "INV-1001",
+		Subtotal:      300,
+		TaxAmount:     24,
+		TotalAmount:   349, // includes a $25 late fee
+		IssuedDate:    &issued,
+	}
+	lines := []*services.InvoiceLineItem{
+		{ServiceID: mowing.ID, Quantity: 4, UnitPrice: 50, TotalPrice: 200},
+		{ServiceID: mulch.ID, Quantity: 2, UnitPrice: 50, TotalPrice: 100, Description: strPtr("Mulch, front beds")},
+	}
+
+	built := services.BuildAccountingInvoice(invoice, customer, lines,
+		map[uuid.UUID]*domain.Service{mowing.ID: mowing, mulch.ID: mulch}, chart)
+
+	processed := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+	payment := &services.AccountingPayment{
+		Payment:       &domain.Payment{ID: uuid.New(), InvoiceID: invoice.ID, Amount: 349, PaymentMethod: "card", ProcessedAt: &processed},
+		InvoiceNumber: invoice.InvoiceNumber,
+		CustomerName:  "Jane Doe",
+		Receivable:    chart.Receivable(customer),
+		Deposit:       chart.Deposit("card"),
+	}
+
+	return chart, built, payment
+}
+
+func TestChartOfAccounts(t *testing.T) {
+	chart, _, _ := accountingFixture(t)
+
+	assert.Equal(t, "Accounts Receivable", chart.Receivable(nil).Name, "defaults apply without a mapping")
+	assert.Equal(t, "Landscaping Services", chart.Income(&domain.Service{ID: uuid.New(), Category: "other"}).Name)
+	assert.Equal(t, "Installation Income", chart.Income(&domain.Service{ID: uuid.New(), Category: "installation"}).Name)
+	assert.Equal(t, "Stripe Clearing", chart.Deposit("card").Name)
+	assert.Equal(t, "Undeposited Funds", chart.Deposit("check").Name)
+}
+
+func TestBuildAccountingInvoice(t *testing.T) {
+	_, invoice, _ := accountingFixture(t)
+
+	assert.Equal(t, "Jane Doe", invoice.CustomerName)
+	require.Len(t, invoice.Lines, 3)
+
+	assert.Equal(t, "Mowing Income", invoice.Lines[0].Account.Name)
+	assert.Equal(t, "Lawn Mowing", invoice.Lines[0].Description)
+	assert.Equal(t, "Installation Income", invoice.Lines[1].Account.Name)
+	assert.Equal(t, "Mulch, front beds", invoice.Lines[1].Description)
+
+	assert.Equal(t, "Late fees", invoice.Lines[2].Description)
+	assert.Equal(t, "Late Fee Income", invoice.Lines[2].Account.Name)
+	assert.InDelta(t, 25, invoice.Lines[2].Amount, 0.001)
+}
+
+func TestBuildIIFExport(t *testing.T) {
+	chart, invoice, payment := accountingFixture(t)
+
+	data := string(services.BuildIIFExport([]*services.AccountingInvoice{invoice}, []*services.AccountingPayment{payment}, chart))
+
+	assert.Contains(t, data, "ACCNT\tMowing Income\tINC\t4010\r\n")
+	assert.Contains(t, data, "CUST\tJane Doe\t\t\tAustin, TX 78701\tjane@example.com\t\r\n")
+	assert.Contains(t, data, "TRNS\t\tINVOICE\t05/03/2024\tAccounts Receivable\tJane Doe\t349.00\tINV-1001\t\r\n")
+	assert.Contains(t, data, "TRNS\t\tPAYMENT\t05/20/2024\tStripe Clearing\tJane Doe\t349.00\tINV-1001\t")
+
+	// Every transaction must balance: TRNS plus its SPL lines sum to zero
+	sum := 0.0
+	for _, line := range strings.Split(data, "\r\n") {
+		fields := strings.Split(line, "\t")
+		switch fields[0] {
+		case "TRNS", "SPL":
+			amount, err := strconv.ParseFloat(fields[6], 64)
+			require.NoError(t, err)
+			sum += amount
+		case "ENDTRNS":
+			assert.InDelta(t, 0, sum, 0.001)
+			sum = 0
+		}
+	}
+}
+
+func TestBuildOFXExport(t *testing.T) {
+	_, _, payment := accountingFixture(t)
+
+	options := services.OFXExportOptions{
+		Start:       time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		End:         time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+		GeneratedAt: time.Date(2024, 6, 1, 9, 30, 0, 0, time.UTC),
+		AccountID:   "1200",
+	}
+
+	ofx := string(services.BuildOFXExport([]*services.AccountingPayment{payment}, options))
+	assert.True(t, strings.HasPrefix(ofx, "OFXHEADER:100"))
+	assert.NotContains(t, ofx, "<INTU.BID>")
+	assert.Contains(t, ofx, "<DTPOSTED>20240520\r\n<TRNAMT>349.00")
+	assert.Contains(t, ofx, "<FITID>"+payment.Payment.ID.String())
+	assert.Contains(t, ofx, "<BALAMT>349.00")
+
+	options.IntuitBankID = "3000"
+	qbo := string(services.BuildOFXExport([]*services.AccountingPayment{payment}, options))
+	assert.Contains(t, qbo, "<INTU.BID>3000")
+}
+
+func TestBuildXeroInvoicesCSV(t *testing.T) {
+	_, invoice, _ := accountingFixture(t)
+
+	data, err := services.BuildXeroInvoicesCSV([]*services.AccountingInvoice{invoice})
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4, "header plus one row per line")
+
+	header := records[0]
+	column := func(name string) int {
+		for i, h := range header {
+			if h == name {
+				return i
+			}
+		}
+		t.Fatalf("missing column %s", name)
+		return -1
+	}
+
+	assert.Equal(t, "Jane Doe", records[1][column("*ContactName")])
+	assert.Equal(t, "4010", records[1][column("*AccountCode")])
+	assert.Equal(t, "Tax on Sales", records[1][column("*TaxType")])
+
+	// Tax is allocated across lines and sums back to the invoice tax
+	taxTotal := 0.0
+	for _, record := range records[1:] {
+		tax, err := strconv.ParseFloat(record[column("TaxAmount")], 64)
+		require.NoError(t, err)
+		taxTotal += tax
+	}
+	assert.InDelta(t, 24, taxTotal, 0.001)
+}
+
+func TestBuildXeroPaymentsCSV(t *testing.T) {
+	_, _, payment := accountingFixture(t)
+
+	data, err := services.BuildXeroPaymentsCSV([]*services.AccountingPayment{payment})
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, []string{"05/20/2024", "349.00", "Jane Doe", "Payment by card", "INV-1001", ""}, records[1])
+}
@@ -0,0 +1,237 @@
+package billing_test
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func overdueInvoice(customerID uuid.UUID, number string, total, paid float64, daysLate int, asOf time.Time) *services.OpenInvoice {
+	dueDate := asOf.AddDate(0, 0, -daysLate)
+	return &services.OpenInvoice{
+		Invoice: &domain.Invoice{
+			ID:            uuid.New(),
+			CustomerID:    customerID,
+			InvoiceNumber: number,
+			TotalAmount:   total,
+			DueDate:       &dueDate,
+		},
+		AmountPaid: paid,
+	}
+}
+
+func TestDueReminderStep(t *testing.T) {
+	steps := services.DefaultReminderSteps()
+
+	t.Run("nothing due before first step", func(t *testing.T) {
+		assert.Nil(t, services.DueReminderStep(steps, 2, nil))
+	})
+
+	t.Run("first step", func(t *testing.T) {
+		step := services.DueReminderStep(steps, 3, nil)
+		require.NotNil(t, step)
+		assert.Equal(t, domain.ReminderTemplateFriendly, step.Template)
+	})
+
+	t.Run("already sent", func(t *testing.T) {
+		assert.Nil(t, services.DueReminderStep(steps, 5, map[int]bool{3: true}))
+	})
+
+	t.Run("escalates", func(t *testing.T) {
+		step := services.DueReminderStep(steps, 12, map[int]bool{3: true})
+		require.NotNil(t, step)
+		assert.Equal(t, domain.ReminderTemplateFirm, step.Template)
+	})
+
+	t.Run("skips to most escalated step reached", func(t *testing.T) {
+		step := services.DueReminderStep(steps, 45, nil)
+		require.NotNil(t, step)
+		assert.Equal(t, domain.ReminderTemplateFinal, step.Template)
+	})
+}
+
+func TestCalculateLateFee(t *testing.T) {
+	flat := &domain.CollectionPolicy{
+		LateFeeEnabled:         true,
+		LateFeeType:            domain.LateFeeTypeFlat,
+		LateFeeAmount:          25,
+		LateFeeGraceDays:       5,
+		LateFeeRepeatDays:      30,
+		LateFeeMaxApplications: 2,
+	}
+
+	t.Run("within grace period", func(t *testing.T) {
+		_, due := services.CalculateLateFee(flat, 500, 5, 0, 0)
+		assert.False(t, due)
+	})
+
+	t.Run("first application", func(t *testing.T) {
+		amount, due := services.CalculateLateFee(flat, 500, 6, 0, 0)
+		assert.True(t, due)
+		assert.InDelta(t, 25, amount, 0.001)
+	})
+
+	t.Run("waits for repeat interval", func(t *testing.T) {
+		_, due := services.CalculateLateFee(flat, 500, 30, 1, 6)
+		assert.False(t, due)
+
+		_, due = services.CalculateLateFee(flat, 500, 36, 1, 6)
+		assert.True(t, due)
+	})
+
+	t.Run("respects maximum applications", func(t *testing.T) {
+		_, due := services.CalculateLateFee(flat, 500, 90, 2, 36)
+		assert.False(t, due)
+	})
+
+	t.Run("percentage of balance", func(t *testing.T) {
+		percentage := *flat
+		percentage.LateFeeType = domain.LateFeeTypePercentage
+		percentage.LateFeeAmount = 1.5
+
+		amount, due := services.CalculateLateFee(&percentage, 333.33, 10, 0, 0)
+		assert.True(t, due)
+		assert.InDelta(t, 5.00, amount, 0.001)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := *flat
+		disabled.LateFeeEnabled = false
+		_, due := services.CalculateLateFee(&disabled, 500, 60, 0, 0)
+		assert.False(t, due)
+	})
+}
+
+func TestBuildARAgingReport(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	customerA, customerB := uuid.New(), uuid.New()
+
+	report := services.BuildARAgingReport([]*services.OpenInvoice{
+		overdueInvoice(customerA, "INV-1", 100, 0, -5, asOf),
+		overdueInvoice(customerA, "INV-2", 200, 0, 40, asOf),
+		overdueInvoice(customerB, "INV-3", 1000, 0, 100, asOf),
+		overdueInvoice(customerB, "INV-4", 50, 50, 100, asOf), // paid in full
+	}, asOf)
+
+	assert.InDelta(t, 100, report.Totals.Current, 0.001)
+	assert.InDelta(t, 200, report.Totals.Days31To60, 0.001)
+	assert.InDelta(t, 1000, report.Totals.Over90, 0.001)
+	assert.InDelta(t, 1300, report.Totals.Total, 0.001)
+
+	require.Len(t, report.Customers, 2)
+	assert.Equal(t, customerB, report.Customers[0].CustomerID, "largest balance first")
+	assert.Equal(t, 1, report.Customers[0].InvoiceCount)
+	assert.Equal(t, 100, report.Customers[0].OldestDaysPastDue)
+	assert.Equal(t, 2, report.Customers[1].InvoiceCount)
+	assert.Equal(t, 40, report.Customers[1].OldestDaysPastDue)
+}
+
+func TestDelinquentCustomers(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	late, small, recent := uuid.New(), uuid.New(), uuid.New()
+
+	delinquent := services.DelinquentCustomers([]*services.OpenInvoice{
+		overdueInvoice(late, "INV-1", 300, 0, 65, asOf),
+		overdueInvoice(small, "INV-2", 20, 0, 90, asOf),
+		overdueInvoice(recent, "INV-3", 5000, 0, 20, asOf),
+	}, asOf, 60, 50)
+
+	assert.True(t, delinquent[late])
+	assert.False(t, delinquent[small], "balance under the minimum")
+	assert.False(t, delinquent[recent], "not overdue long enough")
+}
+
+func TestRenderReminder(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	customer := &domain.EnhancedCustomer{
+		Customer: domain.Customer{ID: uuid.New(), FirstName: "Jane", LastName: "Doe"},
+	}
+	open := overdueInvoice(customer.ID, "INV-9", 150, 25, 10, asOf)
+
+	subject, body := services.RenderReminder(&domain.ReminderStep{DaysOverdue: 10, Template: domain.ReminderTemplateFirm}, customer, open, 10)
+	assert.Equal(t, "Second notice: invoice INV-9 is 10 days overdue", subject)
+	assert.Contains(t, body, "Dear Jane Doe")
+	assert.Contains(t, body, "$125.00")
+
+	custom := "Pay {{invoice_number}} now"
+	subject, _ = services.RenderReminder(&domain.ReminderStep{DaysOverdue: 10, Template: "custom", Subject: &custom}, customer, open, 10)
+	assert.Equal(t, "Pay INV-9 now", subject)
+}
+
+type policyRepo struct {
+	services.CollectionsRepository
+	saved *domain.CollectionPolicy
+}
+
+func (r *policyRepo) GetPolicy(ctx context.Context, tenantID uuid.UUID) (*domain.CollectionPolicy, error) {
+	return nil, nil
+}
+
+func (r *policyRepo) UpsertPolicy(ctx context.Context, policy *domain.CollectionPolicy) error {
+	r.saved = policy
+	return nil
+}
+
+type policyAudit struct {
+	services.AuditService
+}
+
+func (policyAudit) LogAction(ctx context.Context, req *services.AuditLogRequest) error {
+	return nil
+}
+
+func TestUpdateCollectionPolicyValidation(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+	strPtr := func(v string) *string { return &v }
+
+	tests := []struct {
+		name    string
+		req     services.CollectionPolicyRequest
+		wantErr string
+	}{
+		{name: "valid", req: services.CollectionPolicyRequest{
+			LateFeeType: strPtr(domain.LateFeeTypePercentage), LateFeeAmount: floatPtr(1.5),
+			LateFeeGraceDays: intPtr(0), LateFeeRepeatDays: intPtr(30), LateFeeMaxApplications: intPtr(0),
+			SuspendMinBalance: floatPtr(0),
+		}},
+		{name: "negative grace days", req: services.CollectionPolicyRequest{LateFeeGraceDays: intPtr(-1)}, wantErr: "grace days cannot be negative"},
+		{name: "zero repeat days", req: services.CollectionPolicyRequest{LateFeeRepeatDays: intPtr(0)}, wantErr: "repeat days must be at least 1"},
+		{name: "negative repeat days", req: services.CollectionPolicyRequest{LateFeeRepeatDays: intPtr(-7)}, wantErr: "repeat days must be at least 1"},
+		{name: "negative max applications", req: services.CollectionPolicyRequest{LateFeeMaxApplications: intPtr(-1)}, wantErr: "max applications cannot be negative"},
+		{name: "negative min balance", req: services.CollectionPolicyRequest{SuspendMinBalance: floatPtr(-10)}, wantErr: "min balance cannot be negative"},
+		{name: "percentage over 100", req: services.CollectionPolicyRequest{
+			LateFeeType: strPtr(domain.LateFeeTypePercentage), LateFeeAmount: floatPtr(150),
+		}, wantErr: "cannot exceed 100"},
+		{name: "flat fee over 100", req: services.CollectionPolicyRequest{
+			LateFeeType: strPtr(domain.LateFeeTypeFlat), LateFeeAmount: floatPtr(150),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &policyRepo{}
+			svc := services.NewCollectionsService(repo, nil, nil, nil, policyAudit{}, nil, log.New(io.Discard, "", 0))
+			ctx := context.WithValue(context.Background(), "tenant_id", uuid.New())
+
+			_, err := svc.UpdateCollectionPolicy(ctx, &tt.req)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				assert.NotNil(t, repo.saved)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Nil(t, repo.saved, "an invalid policy is not saved")
+		})
+	}
+}
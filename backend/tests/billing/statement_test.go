@@ -0,0 +1,186 @@
+package billing_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func TestAgingBucketFor(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 15, 0, 0, 0, time.UTC)
+	due := func(daysAgo int) *time.Time {
+		d := asOf.AddDate(0, 0, -daysAgo)
+		return &d
+	}
+
+	tests := []struct {
+		name        string
+		dueDate     *time.Time
+		wantBucket  string
+		wantDaysDue int
+	}{
+		{"no due date", nil, services.AgingBucketCurrent, 0},
+		{"not yet due", due(-5), services.AgingBucketCurrent, 0},
+		{"due today", due(0), services.AgingBucketCurrent, 0},
+		{"1 day late", due(1), services.AgingBucket1To30, 1},
+		{"30 days late", due(30), services.AgingBucket1To30, 30},
+		{"31 days late", due(31), services.AgingBucket31To60, 31},
+		{"60 days late", due(60), services.AgingBucket31To60, 60},
+		{"90 days late", due(90), services.AgingBucket61To90, 90},
+		{"91 days late", due(91), services.AgingBucketOver90, 91},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket, days := services.AgingBucketFor(tt.dueDate, asOf)
+			assert.Equal(t, tt.wantBucket, bucket)
+			assert.Equal(t, tt.wantDaysDue, days)
+		})
+	}
+}
+
+func TestBuildAgingStatement(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	customer := &domain.EnhancedCustomer{
+		Customer: domain.Customer{ID: uuid.New(), FirstName: "Jane", LastName: "Doe"},
+	}
+
+	openInvoice := func(number string, total, paid float64, daysLate int) *services.OpenInvoice {
+		dueDate := asOf.AddDate(0, 0, -daysLate)
+		return &services.OpenInvoice{
+			Invoice: &domain.Invoice{
+				ID:            uuid.New(),
+				InvoiceNumber: number,
+				TotalAmount:   total,
+				DueDate:       &dueDate,
+			},
+			AmountPaid: paid,
+		}
+	}
+
+	statement := services.BuildAgingStatement(customer, []*services.OpenInvoice{
+		openInvoice("INV-1", 100, 0, -10),
+		openInvoice("INV-2", 200, 50, 15),
+		openInvoice("INV-3", 300, 0, 45),
+		openInvoice("INV-4", 400, 0, 75),
+		openInvoice("INV-5", 500, 0, 120),
+		openInvoice("INV-6", 80, 80, 120), // fully paid, excluded
+	}, asOf)
+
+	assert.Equal(t, "Jane Doe", statement.CustomerName)
+	assert.InDelta(t, 100, statement.Buckets.Current, 0.001)
+	assert.InDelta(t, 150, statement.Buckets.Days1To30, 0.001)
+	assert.InDelta(t, 300, statement.Buckets.Days31To60, 0.001)
+	assert.InDelta(t, 400, statement.Buckets.Days61To90, 0.001)
+	assert.InDelta(t, 500, statement.Buckets.Over90, 0.001)
+	assert.InDelta(t, 1450, statement.Buckets.Total, 0.001)
+
+	require.Len(t, statement.Invoices, 5)
+	assert.Equal(t, "INV-5", statement.Invoices[0].InvoiceNumber, "oldest invoice should be listed first")
+}
+
+func TestBuildAgingStatementPDF(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	customer := &domain.EnhancedCustomer{
+		Customer: domain.Customer{ID: uuid.New(), FirstName: "Jane", LastName: "O'Brien (Home)"},
+	}
+
+	var open []*services.OpenInvoice
+	for i := 0; i < 80; i++ {
+		dueDate := asOf.AddDate(0, 0, -i)
+		open = append(open, &services.OpenInvoice{
+			Invoice: &domain.Invoice{ID: uuid.New(), InvoiceNumber: fmt.Sprintf("INV-%03d", i), TotalAmount: 125, DueDate: &dueDate},
+		})
+	}
+
+	pdf := services.BuildAgingStatementPDF(services.BuildAgingStatement(customer, open, asOf))
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.NotContains(t, string(pdf), "<html>")
+	assert.Contains(t, string(pdf), `(Jane O'Brien \(Home\)) Tj`)
+	assert.Contains(t, string(pdf), "(INV-079) Tj")
+	assert.Contains(t, string(pdf), "($10000.00) Tj", "total due")
+	assert.Contains(t, string(pdf), "/Count 2", "long statements continue on a second page")
+}
+
+func TestBuildConsolidatedInvoicePDF(t *testing.T) {
+	customer := &domain.EnhancedCustomer{
+		Customer: domain.Customer{ID: uuid.New(), FirstName: "Jane", LastName: "Doe"},
+	}
+	description := "Weekly mowing"
+	notes := "Thank you for your business."
+	pdf := services.BuildConsolidatedInvoicePDF(&services.ConsolidatedInvoice{
+		Invoice: &domain.Invoice{InvoiceNumber: "INV-2024-001", Subtotal: 200, TaxAmount: 16, TotalAmount: 216, Notes: &notes},
+		LineItems: []*services.InvoiceLineItem{
+			{ServiceID: uuid.New(), Description: &description, Quantity: 4, UnitPrice: 50, TotalPrice: 200},
+		},
+		PropertySubtotals: []*services.PropertySubtotal{
+			{PropertyID: uuid.New(), PropertyName: "Main St", PropertyAddress: "1 Main St", JobCount: 4, Subtotal: 200},
+		},
+	}, customer)
+
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+	assert.Contains(t, string(pdf), "(Invoice INV-2024-001) Tj")
+	assert.Contains(t, string(pdf), "(Weekly mowing) Tj")
+	assert.Contains(t, string(pdf), "(Total: $216.00) Tj")
+	assert.Contains(t, string(pdf), "/Count 1")
+}
+
+func TestGroupUninvoicedJobs(t *testing.T) {
+	customerA, customerB := uuid.New(), uuid.New()
+	property1, property2 := uuid.New(), uuid.New()
+	po := "PO-77"
+
+	jobs := []*services.UninvoicedJob{
+		{JobID: uuid.New(), CustomerID: customerA, PropertyID: property1, PropertyName: "HQ", Amount: 100, PONumber: &po},
+		{JobID: uuid.New(), CustomerID: customerA, PropertyID: property2, PropertyName: "Warehouse", Amount: 50},
+		{JobID: uuid.New(), CustomerID: customerA, PropertyID: property1, PropertyName: "HQ", Amount: 25, PONumber: &po},
+		{JobID: uuid.New(), CustomerID: customerB, PropertyID: uuid.New(), PropertyName: "Home", Amount: 80},
+	}
+
+	t.Run("by customer", func(t *testing.T) {
+		groups := services.GroupUninvoicedJobs(jobs, domain.StatementGroupByCustomer)
+		require.Len(t, groups, 2)
+
+		assert.Equal(t, customerA, groups[0].CustomerID)
+		assert.Len(t, groups[0].Jobs, 3)
+		assert.InDelta(t, 175, groups[0].Subtotal, 0.001)
+
+		require.Len(t, groups[0].PropertySubtotals, 2)
+		assert.Equal(t, "HQ", groups[0].PropertySubtotals[0].PropertyName)
+		assert.Equal(t, 2, groups[0].PropertySubtotals[0].JobCount)
+		assert.InDelta(t, 125, groups[0].PropertySubtotals[0].Subtotal, 0.001)
+	})
+
+	t.Run("by property", func(t *testing.T) {
+		groups := services.GroupUninvoicedJobs(jobs, domain.StatementGroupByProperty)
+		require.Len(t, groups, 3)
+		require.NotNil(t, groups[0].PropertyID)
+		assert.Equal(t, property1, *groups[0].PropertyID)
+		assert.InDelta(t, 125, groups[0].Subtotal, 0.001)
+	})
+
+	t.Run("by PO number", func(t *testing.T) {
+		groups := services.GroupUninvoicedJobs(jobs, domain.StatementGroupByPONumber)
+		require.Len(t, groups, 3)
+		require.NotNil(t, groups[0].PONumber)
+		assert.Equal(t, po, *groups[0].PONumber)
+		assert.Len(t, groups[0].Jobs, 2)
+		assert.Nil(t, groups[1].PONumber, "jobs without a PO are billed together")
+	})
+}
+
+func TestNextStatementRunDate(t *testing.T) {
+	from := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 2, 1, 6, 0, 0, 0, time.UTC), services.NextStatementRunDate(from, 1))
+	assert.Equal(t, time.Date(2024, 1, 20, 6, 0, 0, 0, time.UTC), services.NextStatementRunDate(from, 20))
+}
@@ -0,0 +1,183 @@
+package booking_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// Monday 2 March 2026; the booking day is Wednesday 4 March
+var (
+	now = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	day = time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+)
+
+func at(hour, minute int) time.Time {
+	return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func settings() *domain.BookingSettings {
+	s := services.DefaultBookingSettings(uuid.New())
+	s.Enabled = true
+	return s
+}
+
+func starts(slots []services.BookingSlot) []string {
+	result := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		result = append(result, slot.Start.Format("Mon 15:04"))
+	}
+	return result
+}
+
+func TestGenerateBookingSlotsOpenDay(t *testing.T) {
+	crewID := uuid.New()
+	slots := services.GenerateBookingSlots(&services.BookingSlotInput{
+		Settings: settings(),
+		CrewDays: []services.BookingCrewDay{{CrewID: crewID, Date: day}},
+		Duration: time.Hour,
+		Now:      now,
+	})
+
+	assert.Equal(t, []string{"Wed 08:00", "Wed 10:00", "Wed 12:00", "Wed 14:00", "Wed 16:00"}, starts(slots),
+		"two-hour windows while the job still fits before 17:00")
+	require.NotEmpty(t, slots)
+	assert.Equal(t, at(10, 0), slots[0].End)
+	assert.Equal(t, 1, slots[0].Available)
+	assert.Equal(t, []uuid.UUID{crewID}, slots[0].CrewIDs)
+	assert.Equal(t, at(17, 0), slots[4].End, "the last window ends with the workday")
+}
+
+func TestGenerateBookingSlotsSkipsClosedDays(t *testing.T) {
+	s := settings()
+	crewID := uuid.New()
+	saturday := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+	farOut := day.AddDate(0, 0, s.MaxDaysAhead+1)
+
+	slots := services.GenerateBookingSlots(&services.BookingSlotInput{
+		Settings: s,
+		CrewDays: []services.BookingCrewDay{
+			{CrewID: crewID, Date: day},
+			{CrewID: crewID, Date: saturday},
+			{CrewID: crewID, Date: farOut},
+			{CrewID: crewID, Date: now},
+		},
+		Blackouts: []*domain.BookingBlackout{{Date: day}},
+		Duration:  time.Hour,
+		Now:       now,
+	})
+
+	assert.Empty(t, slots, "blackout, weekend, beyond the booking horizon and inside the lead time")
+}
+
+func TestGenerateBookingSlotsCrewBlackoutAndMerge(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	input := &services.BookingSlotInput{
+		Settings: settings(),
+		CrewDays: []services.BookingCrewDay{{CrewID: first, Date: day}, {CrewID: second, Date: day}},
+		Duration: time.Hour,
+		Now:      now,
+	}
+
+	slots := services.GenerateBookingSlots(input)
+	require.Len(t, slots, 5)
+	assert.Equal(t, 2, slots[0].Available)
+
+	input.Blackouts = []*domain.BookingBlackout{{Date: day, CrewID: &second}}
+	slots = services.GenerateBookingSlots(input)
+	require.Len(t, slots, 5)
+	assert.Equal(t, 1, slots[0].Available)
+	assert.Equal(t, []uuid.UUID{first}, slots[0].CrewIDs)
+}
+
+func TestGenerateBookingSlotsCapacity(t *testing.T) {
+	s := settings()
+	s.MaxJobsPerCrewDay = 2
+	crewID := uuid.New()
+	stops := []services.BookingStop{
+		{Start: at(8, 0), End: at(9, 0)},
+		{Start: at(15, 0), End: at(16, 0)},
+	}
+
+	slots := services.GenerateBookingSlots(&services.BookingSlotInput{
+		Settings: s,
+		CrewDays: []services.BookingCrewDay{{CrewID: crewID, Date: day, Stops: stops}},
+		Duration: time.Hour,
+		Now:      now,
+	})
+	assert.Empty(t, slots, "the settings default applies when the crew has no capacity")
+
+	slots = services.GenerateBookingSlots(&services.BookingSlotInput{
+		Settings: s,
+		CrewDays: []services.BookingCrewDay{{CrewID: crewID, Date: day, Capacity: 3, Stops: stops}},
+		Duration: time.Hour,
+		Now:      now,
+	})
+	assert.Equal(t, []string{"Wed 10:00", "Wed 12:00"}, starts(slots))
+}
+
+func TestGenerateBookingSlotsTravelTime(t *testing.T) {
+	s := settings()
+	s.ArrivalWindowMinutes = 60
+	crewID := uuid.New()
+	nearby := &services.Location{Latitude: 40.0, Longitude: -75.0}
+	target := &services.Location{Latitude: 40.0, Longitude: -75.0}
+	farAway := &services.Location{Latitude: 40.3, Longitude: -75.0} // about 21 miles
+
+	input := &services.BookingSlotInput{
+		Settings: s,
+		CrewDays: []services.BookingCrewDay{{CrewID: crewID, Date: day, Stops: []services.BookingStop{
+			{Start: at(8, 0), End: at(9, 0), Location: nearby},
+			{Start: at(12, 0), End: at(13, 0), Location: farAway},
+		}}},
+		Duration: time.Hour,
+		Target:   target,
+		Now:      now,
+	}
+
+	slots := services.GenerateBookingSlots(input)
+	assert.Equal(t, []string{"Wed 09:00", "Wed 10:00", "Wed 14:00", "Wed 15:00", "Wed 16:00"}, starts(slots),
+		"no travel from the neighbour; about 50 minutes to and from the far stop")
+	require.Len(t, slots, 5)
+	assert.Equal(t, 0, slots[0].TravelMinutes)
+	assert.Equal(t, 50, slots[2].TravelMinutes)
+
+	input.Target = nil
+	slots = services.GenerateBookingSlots(input)
+	assert.Equal(t, []string{"Wed 10:00", "Wed 14:00", "Wed 15:00", "Wed 16:00"}, starts(slots),
+		"the default travel time applies when the location is unknown")
+}
+
+func TestGenerateBookingSlotsHoldsAndFreeTime(t *testing.T) {
+	crewID := uuid.New()
+	input := &services.BookingSlotInput{
+		Settings: settings(),
+		CrewDays: []services.BookingCrewDay{{CrewID: crewID, Date: day, Free: []services.TimeRange{{Start: at(8, 0), End: at(12, 30)}}}},
+		Holds: []*domain.BookingHold{
+			{CrewID: crewID, JobStart: at(8, 0), JobEnd: at(9, 0), Status: domain.BookingHoldStatusHeld, ExpiresAt: now.Add(5 * time.Minute)},
+			{CrewID: crewID, JobStart: at(10, 0), JobEnd: at(11, 0), Status: domain.BookingHoldStatusHeld, ExpiresAt: now.Add(-time.Minute)},
+		},
+		Duration: time.Hour,
+		Now:      now,
+	}
+
+	slots := services.GenerateBookingSlots(input)
+	assert.Equal(t, []string{"Wed 10:00"}, starts(slots),
+		"active holds block their window, expired ones don't, and jobs must fit in free time")
+}
+
+func TestBookingOriginAllowed(t *testing.T) {
+	s := settings()
+	s.AllowedOrigins = []string{"https://greenacres.example"}
+
+	assert.True(t, services.BookingOriginAllowed(s, "https://greenacres.example"))
+	assert.True(t, services.BookingOriginAllowed(s, "https://GreenAcres.example/"))
+	assert.False(t, services.BookingOriginAllowed(s, "https://evil.example"))
+	assert.False(t, services.BookingOriginAllowed(s, ""))
+}
@@ -0,0 +1,157 @@
+package certifications_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func date(year int, month time.Month, day int) *time.Time {
+	d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &d
+}
+
+func certification(userID uuid.UUID, certificationType string, expiresOn *time.Time) *domain.UserCertification {
+	return &domain.UserCertification{ID: uuid.New(), UserID: userID, CertificationType: certificationType, ExpiresOn: expiresOn}
+}
+
+func requirement(serviceID uuid.UUID, certificationType, enforcement string) *domain.ServiceCertificationRequirement {
+	return &domain.ServiceCertificationRequirement{ServiceID: serviceID, CertificationType: certificationType, Enforcement: enforcement}
+}
+
+func TestValidateCertificationRequest(t *testing.T) {
+	assert.NoError(t, services.ValidateCertificationRequest(&services.CertificationRequest{
+		CertificationType: domain.CertificationCDL,
+		IssuedOn:          date(2024, time.March, 1),
+		ExpiresOn:         date(2028, time.March, 1),
+	}))
+	assert.Error(t, services.ValidateCertificationRequest(&services.CertificationRequest{CertificationType: "forklift"}))
+	assert.Error(t, services.ValidateCertificationRequest(&services.CertificationRequest{
+		CertificationType: domain.CertificationOSHA,
+		IssuedOn:          date(2024, time.March, 1),
+		ExpiresOn:         date(2023, time.March, 1),
+	}))
+}
+
+func TestIsValidOn(t *testing.T) {
+	cert := certification(uuid.New(), domain.CertificationCDL, date(2026, time.June, 30))
+	assert.True(t, cert.IsValidOn(time.Date(2026, time.June, 30, 16, 0, 0, 0, time.UTC)), "good through the expiry date")
+	assert.False(t, cert.IsValidOn(time.Date(2026, time.July, 1, 7, 0, 0, 0, time.UTC)))
+	assert.True(t, certification(uuid.New(), domain.CertificationOSHA, nil).IsValidOn(time.Now()), "no expiry date never lapses")
+}
+
+func TestCertificationReminderDue(t *testing.T) {
+	now := time.Date(2026, time.May, 1, 9, 0, 0, 0, time.UTC)
+	cert := certification(uuid.New(), domain.CertificationPesticideApplicator, nil)
+
+	_, due := services.CertificationReminderDue(cert, now)
+	assert.False(t, due, "no expiry date")
+
+	cert.ExpiresOn = date(2026, time.August, 1)
+	_, due = services.CertificationReminderDue(cert, now)
+	assert.False(t, due, "more than 60 days out")
+
+	cert.ExpiresOn = date(2026, time.June, 15)
+	days, due := services.CertificationReminderDue(cert, now)
+	assert.True(t, due)
+	assert.Equal(t, 60, days)
+
+	sent := 60
+	cert.ReminderDaysSent = &sent
+	_, due = services.CertificationReminderDue(cert, now)
+	assert.False(t, due, "the 60 day reminder went out already")
+
+	days, due = services.CertificationReminderDue(cert, now.AddDate(0, 0, 20))
+	assert.True(t, due)
+	assert.Equal(t, 30, days)
+
+	days, due = services.CertificationReminderDue(cert, time.Date(2026, time.June, 15, 18, 0, 0, 0, time.UTC))
+	assert.True(t, due)
+	assert.Equal(t, 7, days, "expiring today")
+
+	days, due = services.CertificationReminderDue(cert, time.Date(2026, time.June, 16, 8, 0, 0, 0, time.UTC))
+	assert.True(t, due)
+	assert.Equal(t, 0, days, "expired")
+
+	expired := 0
+	cert.ReminderDaysSent = &expired
+	_, due = services.CertificationReminderDue(cert, time.Date(2026, time.July, 20, 8, 0, 0, 0, time.UTC))
+	assert.False(t, due, "the expired reminder goes out once")
+}
+
+func TestCheckQualificationsForUser(t *testing.T) {
+	workDate := time.Date(2026, time.May, 12, 8, 0, 0, 0, time.UTC)
+	sprayID, mowID := uuid.New(), uuid.New()
+	requirements := []*domain.ServiceCertificationRequirement{
+		requirement(sprayID, domain.CertificationPesticideApplicator, domain.CertificationEnforcementBlock),
+		requirement(sprayID, domain.CertificationOSHA, domain.CertificationEnforcementWarn),
+		requirement(mowID, domain.CertificationOSHA, domain.CertificationEnforcementWarn),
+	}
+
+	userID := uuid.New()
+	gaps, warnings := services.CheckQualifications(requirements, []uuid.UUID{userID}, []*domain.UserCertification{
+		certification(userID, domain.CertificationPesticideApplicator, date(2027, time.January, 31)),
+		certification(userID, domain.CertificationOSHA, nil),
+	}, workDate, false)
+	assert.Empty(t, gaps)
+	assert.Empty(t, warnings)
+
+	gaps, warnings = services.CheckQualifications(requirements, []uuid.UUID{userID}, []*domain.UserCertification{
+		certification(userID, domain.CertificationPesticideApplicator, date(2026, time.April, 30)),
+	}, workDate, false)
+	require.Len(t, gaps, 1)
+	assert.Equal(t, domain.CertificationPesticideApplicator, gaps[0].CertificationType)
+	assert.Equal(t, services.QualificationExpired, gaps[0].Reason)
+	assert.Equal(t, []uuid.UUID{userID}, gaps[0].UserIDs)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, domain.CertificationOSHA, warnings[0].CertificationType)
+	assert.Equal(t, services.QualificationMissing, warnings[0].Reason)
+	assert.ElementsMatch(t, []uuid.UUID{sprayID, mowID}, warnings[0].ServiceIDs)
+	assert.Equal(t, "pesticide applicator expired", services.DescribeQualificationGaps(gaps))
+
+	renewed := []*domain.UserCertification{
+		certification(userID, domain.CertificationPesticideApplicator, date(2026, time.April, 30)),
+		certification(userID, domain.CertificationPesticideApplicator, date(2028, time.April, 30)),
+	}
+	gaps, _ = services.CheckQualifications(requirements, []uuid.UUID{userID}, renewed, workDate, false)
+	assert.Empty(t, gaps, "a renewed licence counts over the lapsed one")
+
+	strictest := append(requirements, requirement(mowID, domain.CertificationOSHA, domain.CertificationEnforcementBlock))
+	gaps, warnings = services.CheckQualifications(strictest, []uuid.UUID{userID}, renewed, workDate, false)
+	require.Len(t, gaps, 1)
+	assert.Equal(t, domain.CertificationOSHA, gaps[0].CertificationType, "blocking wins where services disagree")
+	assert.Empty(t, warnings)
+}
+
+func TestCheckQualificationsForCrew(t *testing.T) {
+	workDate := time.Date(2026, time.May, 12, 8, 0, 0, 0, time.UTC)
+	serviceID := uuid.New()
+	requirements := []*domain.ServiceCertificationRequirement{
+		requirement(serviceID, domain.CertificationPesticideApplicator, domain.CertificationEnforcementBlock),
+		requirement(serviceID, domain.CertificationCDL, domain.CertificationEnforcementBlock),
+	}
+
+	lead, driver, laborer := uuid.New(), uuid.New(), uuid.New()
+	crew := []uuid.UUID{lead, driver, laborer}
+	gaps, _ := services.CheckQualifications(requirements, crew, []*domain.UserCertification{
+		certification(lead, domain.CertificationPesticideApplicator, date(2027, time.January, 31)),
+		certification(driver, domain.CertificationCDL, date(2029, time.October, 1)),
+	}, workDate, true)
+	assert.Empty(t, gaps, "one licensed member covers the crew")
+
+	gaps, _ = services.CheckQualifications(requirements, crew, []*domain.UserCertification{
+		certification(lead, domain.CertificationPesticideApplicator, date(2027, time.January, 31)),
+	}, workDate, true)
+	require.Len(t, gaps, 1)
+	assert.Equal(t, domain.CertificationCDL, gaps[0].CertificationType)
+	assert.Nil(t, gaps[0].UserIDs)
+
+	gaps, _ = services.CheckQualifications(requirements, nil, nil, workDate, true)
+	assert.Len(t, gaps, 2, "a crew with no members is qualified for nothing")
+}
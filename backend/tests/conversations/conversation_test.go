@@ -0,0 +1,159 @@
+package conversations_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestNormalizeMessageAddress(t *testing.T) {
+	assert.Equal(t, "5552345678", services.NormalizeMessageAddress(domain.CommunicationChannelSMS, "+1 (555) 234-5678"))
+	assert.Equal(t, "5552345678", services.NormalizeMessageAddress(domain.CommunicationChannelSMS, "555.234.5678"))
+	assert.Equal(t, "jane@example.com", services.NormalizeMessageAddress(domain.CommunicationChannelEmail, " Jane Doe <Jane@Example.com> "))
+	assert.Equal(t, "jane@example.com", services.NormalizeMessageAddress(domain.CommunicationChannelEmail, "JANE@example.com"))
+}
+
+func TestNormalizeThreadSubject(t *testing.T) {
+	assert.Equal(t, "Spring cleanup", services.NormalizeThreadSubject("RE: Fwd: [External] Spring cleanup"))
+	assert.Equal(t, "Spring cleanup", services.NormalizeThreadSubject("Re[2]:  Spring   cleanup "))
+	assert.Equal(t, "Quote Q-1001", services.NormalizeThreadSubject("Quote Q-1001"))
+
+	assert.Equal(t, "Re: Spring cleanup", services.ReplySubject("Spring cleanup"))
+	assert.Equal(t, "RE: Spring cleanup", services.ReplySubject("RE: Spring cleanup"))
+	assert.Equal(t, "", services.ReplySubject(""))
+}
+
+func TestMatchInboundThread(t *testing.T) {
+	now := time.Now()
+	older := now.Add(-48 * time.Hour)
+	thread := func(channel, subject string, last time.Time) *domain.CommunicationThread {
+		thread := &domain.CommunicationThread{ID: uuid.New(), Channel: channel, LastMessageAt: &last}
+		if subject != "" {
+			thread.Subject = strPtr(subject)
+		}
+		return thread
+	}
+
+	// Texts continue the most recent thread with the number
+	oldSMS := thread(domain.CommunicationChannelSMS, "", older)
+	newSMS := thread(domain.CommunicationChannelSMS, "", now)
+	match := services.MatchInboundThread([]*domain.CommunicationThread{oldSMS, newSMS}, domain.CommunicationChannelSMS, "", nil)
+	require.NotNil(t, match)
+	assert.Equal(t, newSMS.ID, match.ID)
+
+	// Emails continue the thread with the same subject
+	cleanup := thread(domain.CommunicationChannelEmail, "Spring cleanup", older)
+	invoice := thread(domain.CommunicationChannelEmail, "Invoice INV-1042", now)
+	emails := []*domain.CommunicationThread{invoice, cleanup}
+	match = services.MatchInboundThread(emails, domain.CommunicationChannelEmail, "Re: spring cleanup", nil)
+	require.NotNil(t, match)
+	assert.Equal(t, cleanup.ID, match.ID)
+
+	// A reply to a known message wins over the subject
+	match = services.MatchInboundThread(emails, domain.CommunicationChannelEmail, "Something else", &invoice.ID)
+	require.NotNil(t, match)
+	assert.Equal(t, invoice.ID, match.ID)
+
+	// A new subject starts a new thread
+	assert.Nil(t, services.MatchInboundThread(emails, domain.CommunicationChannelEmail, "Fall aeration", nil))
+	assert.Nil(t, services.MatchInboundThread(nil, domain.CommunicationChannelSMS, "", nil))
+}
+
+func TestStripQuotedReply(t *testing.T) {
+	gmail := "Tuesday works for us.\n\nThanks,\nJane\n\nOn Mon, Apr 6, 2026 at 9:14 AM Green Acres <office@greenacres.com> wrote:\n> Can we come by Tuesday?\n"
+	assert.Equal(t, "Tuesday works for us.\n\nThanks,\nJane", services.StripQuotedReply(gmail))
+
+	outlook := "Yes please.\r\n\r\nFrom: Green Acres <office@greenacres.com>\r\nSent: Monday, April 6, 2026 9:14 AM\r\nSubject: Spring cleanup\r\n\r\nCan we come by Tuesday?"
+	assert.Equal(t, "Yes please.", services.StripQuotedReply(outlook))
+
+	inline := "> Can we come by Tuesday?\nYes, any time after 10."
+	assert.Equal(t, "Yes, any time after 10.", services.StripQuotedReply(inline))
+
+	assert.Equal(t, "from: the back gate is open", services.StripQuotedReply("from: the back gate is open"))
+}
+
+func TestMessagePreview(t *testing.T) {
+	assert.Equal(t, "Tuesday works for us. Thanks, Jane", services.MessagePreview("Tuesday works for us.\n\nThanks,\n  Jane"))
+
+	preview := services.MessagePreview(strings.Repeat("word ", 60))
+	assert.LessOrEqual(t, len([]rune(preview)), services.MessagePreviewLength)
+	assert.True(t, strings.HasSuffix(preview, "…"))
+}
+
+func TestTimelineEventTitle(t *testing.T) {
+	assert.Equal(t, "Quote viewed", services.TimelineEventTitle("portal.quote_view"))
+	assert.Equal(t, "Text received", services.TimelineEventTitle("sms.inbound"))
+	assert.Equal(t, "Payment received", services.TimelineEventTitle("payment.completed"))
+	assert.Equal(t, "Collections promise to pay", services.TimelineEventTitle("collections.promise_to_pay"))
+	assert.Equal(t, "Job update services", services.TimelineEventTitle("job.update_services"))
+	assert.Equal(t, "", services.TimelineEventTitle(""))
+}
+
+func TestLocalMessagingProviderWebhooks(t *testing.T) {
+	provider := services.NewLocalMessagingProvider("test-secret", "+15550001111", "office@greenacres.com")
+
+	payload, signature, err := provider.Webhook(&services.MessagingEvent{
+		Type:              services.MessagingEventMessage,
+		Channel:           domain.CommunicationChannelSMS,
+		ProviderMessageID: "in-1",
+		From:              "+15552345678",
+		To:                "+15550001111",
+		Body:              "Running late?",
+	})
+	require.NoError(t, err)
+	require.NoError(t, provider.VerifyWebhook(localWebhook(payload, signature)))
+	assert.Error(t, provider.VerifyWebhook(localWebhook(payload, services.SignWebhookPayload("other-secret", payload))))
+	assert.Error(t, provider.VerifyWebhook(localWebhook(append(payload, ' '), signature)))
+	assert.Error(t, provider.VerifyWebhook(localWebhook(payload, "not-hex")))
+
+	events, err := provider.ParseWebhook(localWebhook(payload, signature))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Running late?", events[0].Body)
+
+	_, err = provider.ParseWebhook(localWebhook([]byte(`{"events":[{"type":"message","channel":"fax","from":"x"}]}`), ""))
+	assert.Error(t, err)
+	_, err = provider.ParseWebhook(localWebhook([]byte(`{"events":[{"type":"status","channel":"sms"}]}`), ""))
+	assert.Error(t, err)
+
+	unsigned := services.NewLocalMessagingProvider("", "", "")
+	assert.Error(t, unsigned.VerifyWebhook(localWebhook(payload, services.SignWebhookPayload("", payload))))
+}
+
+func localWebhook(payload []byte, signature string) *services.MessagingWebhook {
+	header := http.Header{}
+	header.Set(services.MessagingSignatureHeader, signature)
+	return &services.MessagingWebhook{TenantID: uuid.New(), Header: header, Payload: payload}
+}
+
+func TestLocalMessagingProviderRecordsSentMessages(t *testing.T) {
+	provider := services.NewLocalMessagingProvider("test-secret", "+15550001111", "office@greenacres.com")
+	assert.Equal(t, "+15550001111", provider.SenderAddress(domain.CommunicationChannelSMS))
+	assert.Equal(t, "office@greenacres.com", provider.SenderAddress(domain.CommunicationChannelEmail))
+
+	id, err := provider.Send(context.Background(), &services.OutboundMessage{
+		Channel: domain.CommunicationChannelSMS,
+		To:      "5552345678",
+		Body:    "On our way",
+	})
+	require.NoError(t, err)
+
+	_, err = provider.Send(context.Background(), &services.OutboundMessage{Channel: domain.CommunicationChannelSMS, Body: "no recipient"})
+	assert.Error(t, err)
+
+	sent := provider.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, id, sent[0].ProviderMessageID)
+	assert.Equal(t, "On our way", sent[0].Body)
+}
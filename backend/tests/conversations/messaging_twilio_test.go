@@ -0,0 +1,218 @@
+package conversations_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+const twilioWebhookURL = "https://api.greenacres.com/api/v1/public/communications/{tenant_id}/webhook"
+
+func newTwilioProvider(serverURL string, client *http.Client) *services.TwilioMessagingProvider {
+	return services.NewTwilioMessagingProvider(services.TwilioMessagingConfig{
+		APIURL:               serverURL,
+		AccountSID:           "AC123",
+		AuthToken:            "auth-token",
+		SMSFrom:              "+15550001111",
+		SendGridAPIURL:       serverURL,
+		SendGridAPIKey:       "sg-key",
+		EmailFrom:            "office@greenacres.com",
+		EmailFromName:        "Green Acres",
+		InboundParsePassword: "parse-secret",
+		WebhookURL:           twilioWebhookURL,
+	}, client)
+}
+
+func TestTwilioProviderSendsSMS(t *testing.T) {
+	tenantID := uuid.New()
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", r.URL.Path)
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "AC123", user)
+		assert.Equal(t, "auth-token", password)
+		require.NoError(t, r.ParseForm())
+		form = r.PostForm
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid":"SM42","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	provider := newTwilioProvider(server.URL, server.Client())
+	ctx := context.WithValue(context.Background(), "tenant_id", tenantID)
+	id, err := provider.Send(ctx, &services.OutboundMessage{
+		Channel: domain.CommunicationChannelSMS,
+		From:    provider.SenderAddress(domain.CommunicationChannelSMS),
+		To:      "5552345678",
+		Body:    "On our way",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SM42", id)
+	assert.Equal(t, "+15552345678", form.Get("To"))
+	assert.Equal(t, "+15550001111", form.Get("From"))
+	assert.Equal(t, "On our way", form.Get("Body"))
+	assert.Equal(t, strings.ReplaceAll(twilioWebhookURL, "{tenant_id}", tenantID.String()), form.Get("StatusCallback"))
+}
+
+func TestTwilioProviderReportsSMSErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":21211,"message":"The 'To' number is not a valid phone number."}`))
+	}))
+	defer server.Close()
+
+	_, err := newTwilioProvider(server.URL, server.Client()).Send(context.Background(), &services.OutboundMessage{
+		Channel: domain.CommunicationChannelSMS,
+		To:      "555",
+		Body:    "On our way",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a valid phone number")
+	assert.Contains(t, err.Error(), "21211")
+}
+
+func TestTwilioProviderSendsEmailReplies(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/mail/send", r.URL.Path)
+		assert.Equal(t, "Bearer sg-key", r.Header.Get("Authorization"))
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	id, err := newTwilioProvider(server.URL, server.Client()).Send(context.Background(), &services.OutboundMessage{
+		Channel:   domain.CommunicationChannelEmail,
+		To:        "jane@example.com",
+		Subject:   "Re: Spring cleanup",
+		Body:      "We can come Tuesday.",
+		InReplyTo: "abc@mail.example.com",
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(id, "@greenacres.com"))
+
+	headers := body["headers"].(map[string]interface{})
+	assert.Equal(t, "<"+id+">", headers["Message-ID"])
+	assert.Equal(t, "<abc@mail.example.com>", headers["In-Reply-To"])
+	assert.Equal(t, "Re: Spring cleanup", body["subject"])
+	assert.Equal(t, "office@greenacres.com", body["from"].(map[string]interface{})["email"])
+}
+
+// twilioWebhook builds a form post signed the way Twilio signs it
+func twilioWebhook(tenantID uuid.UUID, params url.Values, authToken string) *services.MessagingWebhook {
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	signedURL := strings.ReplaceAll(twilioWebhookURL, "{tenant_id}", tenantID.String())
+	header.Set(services.TwilioSignatureHeader, services.TwilioSignature(authToken, signedURL, params))
+	return &services.MessagingWebhook{TenantID: tenantID, Header: header, Payload: []byte(params.Encode())}
+}
+
+func TestTwilioProviderInboundSMS(t *testing.T) {
+	provider := newTwilioProvider("", nil)
+	tenantID := uuid.New()
+	params := url.Values{
+		"MessageSid": {"SM99"},
+		"SmsStatus":  {"received"},
+		"From":       {"+15552345678"},
+		"To":         {"+15550001111"},
+		"Body":       {"Running late?"},
+	}
+
+	webhook := twilioWebhook(tenantID, params, "auth-token")
+	require.NoError(t, provider.VerifyWebhook(webhook))
+
+	events, err := provider.ParseWebhook(webhook)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, services.MessagingEventMessage, events[0].Type)
+	assert.Equal(t, domain.CommunicationChannelSMS, events[0].Channel)
+	assert.Equal(t, "SM99", events[0].ProviderMessageID)
+	assert.Equal(t, "+15552345678", events[0].From)
+	assert.Equal(t, "Running late?", events[0].Body)
+
+	// Signed with another token, for another tenant or tampered with
+	assert.Error(t, provider.VerifyWebhook(twilioWebhook(tenantID, params, "other-token")))
+	otherTenant := twilioWebhook(tenantID, params, "auth-token")
+	otherTenant.TenantID = uuid.New()
+	assert.Error(t, provider.VerifyWebhook(otherTenant))
+	tampered := twilioWebhook(tenantID, params, "auth-token")
+	tampered.Payload = []byte(strings.Replace(string(tampered.Payload), "Running", "Arriving", 1))
+	assert.Error(t, provider.VerifyWebhook(tampered))
+}
+
+func TestTwilioProviderStatusCallbacks(t *testing.T) {
+	provider := newTwilioProvider("", nil)
+	tenantID := uuid.New()
+
+	events, err := provider.ParseWebhook(twilioWebhook(tenantID, url.Values{"MessageSid": {"SM42"}, "MessageStatus": {"delivered"}}, "auth-token"))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, services.MessagingEventStatus, events[0].Type)
+	assert.Equal(t, domain.MessageStatusDelivered, events[0].Status)
+
+	events, err = provider.ParseWebhook(twilioWebhook(tenantID, url.Values{"MessageSid": {"SM42"}, "MessageStatus": {"undelivered"}, "ErrorCode": {"30003"}}, "auth-token"))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, domain.MessageStatusFailed, events[0].Status)
+	assert.Contains(t, events[0].Error, "30003")
+
+	events, err = provider.ParseWebhook(twilioWebhook(tenantID, url.Values{"MessageSid": {"SM42"}, "MessageStatus": {"sent"}}, "auth-token"))
+	require.NoError(t, err)
+	assert.Empty(t, events, "intermediate statuses are ignored")
+}
+
+func TestTwilioProviderInboundEmail(t *testing.T) {
+	provider := newTwilioProvider("", nil)
+
+	var payload bytes.Buffer
+	writer := multipart.NewWriter(&payload)
+	fields := map[string]string{
+		"from":    "Jane Doe <jane@example.com>",
+		"to":      "office@greenacres.com",
+		"subject": "Re: Spring cleanup",
+		"text":    "Tuesday works.",
+		"headers": "Message-ID: <reply-1@mail.example.com>\nIn-Reply-To: <abc@greenacres.com>\nSubject: Re: Spring cleanup",
+	}
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	require.NoError(t, writer.Close())
+
+	header := http.Header{}
+	header.Set("Content-Type", writer.FormDataContentType())
+	webhook := &services.MessagingWebhook{TenantID: uuid.New(), Header: header, Payload: payload.Bytes()}
+
+	assert.Error(t, provider.VerifyWebhook(webhook), "Inbound Parse posts need the configured credentials")
+	(&http.Request{Header: header}).SetBasicAuth("parse", "wrong")
+	assert.Error(t, provider.VerifyWebhook(webhook))
+	(&http.Request{Header: header}).SetBasicAuth("parse", "parse-secret")
+	require.NoError(t, provider.VerifyWebhook(webhook))
+
+	events, err := provider.ParseWebhook(webhook)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, domain.CommunicationChannelEmail, events[0].Channel)
+	assert.Equal(t, "reply-1@mail.example.com", events[0].ProviderMessageID)
+	assert.Equal(t, "abc@greenacres.com", events[0].InReplyTo)
+	assert.Equal(t, "Jane Doe <jane@example.com>", events[0].From)
+	assert.Equal(t, "Tuesday works.", events[0].Body)
+}
@@ -0,0 +1,151 @@
+package customermerge_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func strPtr(s string) *string { return &s }
+
+type contact struct {
+	first, last, email, phone, address, zip, notes string
+}
+
+func customer(c contact) *domain.EnhancedCustomer {
+	optional := func(s string) *string {
+		if s == "" {
+			return nil
+		}
+		return strPtr(s)
+	}
+	return &domain.EnhancedCustomer{Customer: domain.Customer{
+		ID:           uuid.New(),
+		FirstName:    c.first,
+		LastName:     c.last,
+		Email:        optional(c.email),
+		Phone:        optional(c.phone),
+		AddressLine1: optional(c.address),
+		City:         optional(map[bool]string{true: "Springfield"}[c.address != ""]),
+		ZipCode:      optional(c.zip),
+		Notes:        optional(c.notes),
+		Status:       "active",
+		CreatedAt:    time.Now(),
+	}}
+}
+
+func TestScoreCustomerDuplicateNicknameAtSameAddress(t *testing.T) {
+	bob := customer(contact{first: "Bob", last: "Smith", address: "12 Oak Street", zip: "02134"})
+	robert := customer(contact{first: "Robert", last: "Smith", address: "12 Oak St.", zip: "02134"})
+
+	score, reasons := services.ScoreCustomerDuplicate(bob, robert)
+	assert.Equal(t, 1.0, score)
+	assert.Equal(t, []string{"same name", "same address"}, reasons)
+}
+
+func TestScoreCustomerDuplicateHouseholdIsNotDuplicate(t *testing.T) {
+	mary := customer(contact{first: "Mary", last: "Smith", address: "12 Oak Street", zip: "02134"})
+	robert := customer(contact{first: "Robert", last: "Smith", address: "12 Oak Street", zip: "02134"})
+
+	score, _ := services.ScoreCustomerDuplicate(mary, robert)
+	assert.Less(t, score, services.CustomerDuplicateThreshold, "people sharing a household are separate customers")
+}
+
+func TestScoreCustomerDuplicateContactDetails(t *testing.T) {
+	a := customer(contact{first: "Jon", last: "Smith", email: "JSMITH@example.com", phone: "(555) 234-5678"})
+	b := customer(contact{first: "Jonathan", last: "Smith", email: "jsmith@example.com", phone: "+1 555 234 5678"})
+
+	score, reasons := services.ScoreCustomerDuplicate(a, b)
+	assert.Equal(t, 1.0, score)
+	assert.Equal(t, []string{"same name", "same email", "same phone"}, reasons)
+
+	b.Email = strPtr("other@example.com")
+	score, _ = services.ScoreCustomerDuplicate(a, b)
+	assert.Less(t, score, 1.0, "a different email counts against the pair")
+	assert.GreaterOrEqual(t, score, services.CustomerDuplicateThreshold)
+
+	namesakes := []*domain.EnhancedCustomer{
+		customer(contact{first: "John", last: "Smith"}),
+		customer(contact{first: "John", last: "Smith"}),
+	}
+	score, _ = services.ScoreCustomerDuplicate(namesakes[0], namesakes[1])
+	assert.Equal(t, 0.6, score, "a name alone is weak evidence")
+}
+
+func TestFindCustomerDuplicates(t *testing.T) {
+	bob := customer(contact{first: "Bob", last: "Smith", address: "12 Oak Street", zip: "02134"})
+	robert := customer(contact{first: "Robert", last: "Smith", address: "12 Oak Street", zip: "02134"})
+	robert.CreatedAt = bob.CreatedAt.Add(-time.Hour)
+	jane := customer(contact{first: "Jane", last: "Doe", email: "jane@example.com"})
+	janet := customer(contact{first: "Janet", last: "Doe", email: "jane@example.com"})
+	other := customer(contact{first: "Alice", last: "Jones", zip: "02134"})
+
+	customers := []*domain.EnhancedCustomer{bob, robert, jane, janet, other}
+	duplicates := services.FindCustomerDuplicates(customers, services.CustomerDuplicateThreshold, nil)
+	require.Len(t, duplicates, 2)
+	assert.Equal(t, robert, duplicates[0].CustomerA, "the older record is the suggested survivor")
+	assert.Equal(t, bob, duplicates[0].CustomerB)
+	assert.ElementsMatch(t, []*domain.EnhancedCustomer{jane, janet}, []*domain.EnhancedCustomer{duplicates[1].CustomerA, duplicates[1].CustomerB})
+
+	duplicates = services.FindCustomerDuplicates(customers, services.CustomerDuplicateThreshold, func(a, b uuid.UUID) bool {
+		return a == jane.ID || b == jane.ID
+	})
+	require.Len(t, duplicates, 1, "skipped pairs are left out")
+	assert.Equal(t, robert, duplicates[0].CustomerA)
+}
+
+func TestMergeCustomerFields(t *testing.T) {
+	survivor := customer(contact{first: "Robert", last: "Smith", email: "bob@example.com", notes: "Gate code 1234"})
+	merged := customer(contact{first: "Bob", last: "Smith", email: "robert@work.example", phone: "555-234-5678",
+		address: "12 Oak Street", zip: "02134", notes: "Prefers mornings"})
+
+	result, changes := services.MergeCustomerFields(survivor, merged, nil)
+
+	assert.Equal(t, "Robert", result.FirstName, "the survivor keeps its own values")
+	assert.Equal(t, "bob@example.com", *result.Email)
+	assert.Equal(t, "555-234-5678", *result.Phone, "blank fields are filled in")
+	assert.Equal(t, "12 Oak Street", *result.AddressLine1)
+	assert.Equal(t, "Springfield", *result.City, "the address moves as a whole")
+	assert.Equal(t, "Gate code 1234\n\nPrefers mornings\n\nMerged from Bob Smith: email robert@work.example", *result.Notes)
+	assert.Nil(t, survivor.Phone, "the survivor passed in is not modified")
+
+	assert.Equal(t, domain.CustomerFieldChange{Old: "", New: "555-234-5678"}, changes["phone"])
+	assert.NotContains(t, changes, "first_name")
+	assert.NotContains(t, changes, "email")
+
+	result, changes = services.MergeCustomerFields(survivor, merged, map[string]string{"email": "merged"})
+	assert.Equal(t, "robert@work.example", *result.Email)
+	assert.Equal(t, domain.CustomerFieldChange{Old: "bob@example.com", New: "robert@work.example"}, changes["email"])
+	assert.Contains(t, *result.Notes, "Merged from Bob Smith: email bob@example.com")
+}
+
+func TestRevertCustomerMergeFields(t *testing.T) {
+	survivor := customer(contact{first: "Robert", last: "Smith", email: "bob@example.com"})
+	merged := customer(contact{first: "Bob", last: "Smith", phone: "555-234-5678", address: "12 Oak Street", zip: "02134"})
+
+	result, changes := services.MergeCustomerFields(survivor, merged, nil)
+	result.ZipCode = strPtr("02135") // corrected by staff after the merge
+
+	kept := services.RevertCustomerMergeFields(result, changes)
+	assert.Equal(t, []string{"zip_code"}, kept)
+	assert.Nil(t, result.Phone)
+	assert.Nil(t, result.AddressLine1)
+	assert.Equal(t, "02135", *result.ZipCode, "edits made since the merge are kept")
+}
+
+func TestCustomerMergeCanUndo(t *testing.T) {
+	now := time.Now()
+	merge := &domain.CustomerMerge{Status: domain.CustomerMergeStatusMerged, UndoExpiresAt: now.Add(services.CustomerMergeUndoWindow)}
+
+	assert.True(t, merge.CanUndo(now))
+	assert.False(t, merge.CanUndo(now.Add(services.CustomerMergeUndoWindow+time.Minute)))
+
+	merge.Status = domain.CustomerMergeStatusUndone
+	assert.False(t, merge.CanUndo(now))
+}
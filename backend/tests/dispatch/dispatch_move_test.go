@@ -0,0 +1,191 @@
+package dispatch_test
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+type moveDispatchRepo struct {
+	services.DispatchRepository
+}
+
+func (moveDispatchRepo) ListActiveCrews(ctx context.Context, tenantID uuid.UUID) ([]*domain.Crew, error) {
+	return nil, nil
+}
+
+func (moveDispatchRepo) ListStandingMembers(ctx context.Context, tenantID uuid.UUID) ([]*domain.CrewMember, error) {
+	return nil, nil
+}
+
+func (moveDispatchRepo) ListRosters(ctx context.Context, tenantID uuid.UUID, date time.Time) ([]*domain.CrewRoster, error) {
+	return nil, nil
+}
+
+func (moveDispatchRepo) GetUserNames(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	return map[uuid.UUID]string{}, nil
+}
+
+// moveJobRepo holds one job and records whether it was saved
+type moveJobRepo struct {
+	services.JobRepositoryComplete
+	job       *domain.EnhancedJob
+	serviceID uuid.UUID
+	updated   *domain.EnhancedJob
+}
+
+func (r *moveJobRepo) GetByID(ctx context.Context, tenantID, jobID uuid.UUID) (*domain.EnhancedJob, error) {
+	copied := *r.job
+	return &copied, nil
+}
+
+func (r *moveJobRepo) GetByDateRange(ctx context.Context, tenantID uuid.UUID, start, end time.Time) ([]*domain.EnhancedJob, error) {
+	if r.updated != nil {
+		return []*domain.EnhancedJob{r.updated}, nil
+	}
+	return []*domain.EnhancedJob{r.job}, nil
+}
+
+func (r *moveJobRepo) GetJobServices(ctx context.Context, jobID uuid.UUID) ([]*domain.JobService, error) {
+	return []*domain.JobService{{ID: uuid.New(), JobID: jobID, ServiceID: r.serviceID}}, nil
+}
+
+func (r *moveJobRepo) Update(ctx context.Context, job *domain.EnhancedJob) error {
+	r.updated = job
+	return nil
+}
+
+type movePropertyRepo struct {
+	services.PropertyRepositoryExtended
+}
+
+func (movePropertyRepo) GetByID(ctx context.Context, tenantID, propertyID uuid.UUID) (*domain.EnhancedProperty, error) {
+	return nil, nil
+}
+
+type moveCustomerRepo struct {
+	services.CustomerRepository
+	suspended bool
+}
+
+func (r moveCustomerRepo) GetByID(ctx context.Context, tenantID, customerID uuid.UUID) (*domain.EnhancedCustomer, error) {
+	customer := &domain.EnhancedCustomer{SchedulingSuspended: r.suspended}
+	customer.ID = customerID
+	return customer, nil
+}
+
+type moveCertificationRepo struct {
+	services.CertificationRepository
+	requirements   []*domain.ServiceCertificationRequirement
+	certifications []*domain.UserCertification
+}
+
+func (r *moveCertificationRepo) ListServiceRequirements(ctx context.Context, tenantID uuid.UUID, serviceIDs []uuid.UUID) ([]*domain.ServiceCertificationRequirement, error) {
+	return r.requirements, nil
+}
+
+func (r *moveCertificationRepo) ListCertifications(ctx context.Context, tenantID uuid.UUID, userIDs []uuid.UUID) ([]*domain.UserCertification, error) {
+	return r.certifications, nil
+}
+
+type moveReservationRepo struct {
+	services.EquipmentReservationRepository
+}
+
+func (moveReservationRepo) DeleteJobReservations(ctx context.Context, tenantID, jobID uuid.UUID) error {
+	return nil
+}
+
+type moveAudit struct {
+	services.AuditService
+}
+
+func (moveAudit) LogAction(ctx context.Context, req *services.AuditLogRequest) error {
+	return nil
+}
+
+type moveFixture struct {
+	ctx            context.Context
+	jobs           *moveJobRepo
+	certifications *moveCertificationRepo
+	customers      *moveCustomerRepo
+	svc            services.DispatchService
+}
+
+// newMoveFixture has an unassigned 9 AM job whose service requires a pesticide license
+func newMoveFixture(enforcement string) *moveFixture {
+	tenantID := uuid.New()
+	unassigned := job("Weed control", nil, at(9, 0), 60)
+	unassigned.TenantID = tenantID
+	unassigned.CustomerID = uuid.New()
+
+	f := &moveFixture{
+		ctx:  context.WithValue(context.Background(), "tenant_id", tenantID),
+		jobs: &moveJobRepo{job: unassigned, serviceID: uuid.New()},
+		certifications: &moveCertificationRepo{requirements: []*domain.ServiceCertificationRequirement{
+			{TenantID: tenantID, CertificationType: "pesticide_license", Enforcement: enforcement},
+		}},
+		customers: &moveCustomerRepo{},
+	}
+	f.certifications.requirements[0].ServiceID = f.jobs.serviceID
+	f.svc = services.NewDispatchService(moveDispatchRepo{}, f.jobs, movePropertyRepo{}, nil, moveReservationRepo{}, nil, nil,
+		f.customers, f.certifications, moveAudit{}, log.New(io.Discard, "", 0))
+	return f
+}
+
+func TestMoveJobRefusesUnqualifiedAssignee(t *testing.T) {
+	f := newMoveFixture(domain.CertificationEnforcementBlock)
+	userID := uuid.New()
+
+	_, err := f.svc.MoveJob(f.ctx, &services.DispatchJobMove{JobID: f.jobs.job.ID, UserID: &userID})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "job cannot be moved")
+	assert.Contains(t, err.Error(), "is not qualified: pesticide license missing")
+	assert.Nil(t, f.jobs.updated, "the job is not reassigned")
+
+	// Once licensed the move goes through
+	f.certifications.certifications = []*domain.UserCertification{
+		{ID: uuid.New(), TenantID: f.jobs.job.TenantID, UserID: userID, CertificationType: "pesticide_license"},
+	}
+	change, err := f.svc.MoveJob(f.ctx, &services.DispatchJobMove{JobID: f.jobs.job.ID, UserID: &userID})
+	require.NoError(t, err)
+	require.NotNil(t, f.jobs.updated)
+	assert.Equal(t, &userID, f.jobs.updated.AssignedUserID)
+	assert.Empty(t, change.Warnings)
+}
+
+func TestMoveJobWarnsOnQualificationWarnings(t *testing.T) {
+	f := newMoveFixture(domain.CertificationEnforcementWarn)
+	userID := uuid.New()
+
+	change, err := f.svc.MoveJob(f.ctx, &services.DispatchJobMove{JobID: f.jobs.job.ID, UserID: &userID})
+	require.NoError(t, err)
+	require.NotNil(t, f.jobs.updated)
+	require.Len(t, change.Warnings, 1)
+	assert.Contains(t, change.Warnings[0], "Weed control assigned without: pesticide license")
+}
+
+func TestMoveJobRefusesRescheduleForSuspendedCustomer(t *testing.T) {
+	f := newMoveFixture(domain.CertificationEnforcementWarn)
+	f.customers.suspended = true
+	userID := uuid.New()
+
+	_, err := f.svc.MoveJob(f.ctx, &services.DispatchJobMove{JobID: f.jobs.job.ID, UserID: &userID, ScheduledDate: at(13, 0)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "scheduling is suspended")
+	assert.Nil(t, f.jobs.updated)
+
+	// Reassigning without moving the date is still allowed
+	_, err = f.svc.MoveJob(f.ctx, &services.DispatchJobMove{JobID: f.jobs.job.ID, UserID: &userID, ScheduledDate: at(9, 0)})
+	require.NoError(t, err)
+	assert.NotNil(t, f.jobs.updated)
+}
@@ -0,0 +1,244 @@
+package dispatch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+var day = time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+func at(hour, minute int) *time.Time {
+	t := day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	return &t
+}
+
+func minutes(n int) *int {
+	return &n
+}
+
+func job(title string, assignee *uuid.UUID, start *time.Time, duration int) *domain.EnhancedJob {
+	j := &domain.EnhancedJob{}
+	j.ID = uuid.New()
+	j.PropertyID = uuid.New()
+	j.Title = title
+	j.Status = domain.JobStatusScheduled
+	j.AssignedUserID = assignee
+	j.ScheduledDate = start
+	if duration > 0 {
+		j.EstimatedDuration = minutes(duration)
+	}
+	return j
+}
+
+func workday() []services.TimeRange {
+	return []services.TimeRange{{Start: *at(8, 0), End: *at(17, 0)}}
+}
+
+func TestValidateCrewRoster(t *testing.T) {
+	userID := uuid.New()
+	assert.NoError(t, services.ValidateCrewRoster(&services.CrewRosterRequest{
+		Members: []services.CrewRosterMemberRequest{{UserID: userID, Role: "lead"}, {UserID: uuid.New()}},
+	}))
+	assert.Error(t, services.ValidateCrewRoster(&services.CrewRosterRequest{
+		Members: []services.CrewRosterMemberRequest{{UserID: userID}, {UserID: userID}},
+	}), "the same person twice")
+	assert.Error(t, services.ValidateCrewRoster(&services.CrewRosterRequest{
+		Members: []services.CrewRosterMemberRequest{{UserID: userID, Role: "lead"}, {UserID: uuid.New(), Role: "foreman"}},
+	}), "two leads")
+
+	mower := uuid.New()
+	assert.Error(t, services.ValidateCrewRoster(&services.CrewRosterRequest{EquipmentIDs: []uuid.UUID{mower, mower}}))
+}
+
+func TestEffectiveRosters(t *testing.T) {
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	mower, trailer := uuid.New(), uuid.New()
+	north := &domain.Crew{ID: uuid.New(), Name: "North", EquipmentIDs: []uuid.UUID{mower, trailer}}
+	south := &domain.Crew{ID: uuid.New(), Name: "South"}
+	standing := []*domain.CrewMember{
+		{CrewID: north.ID, UserID: alice, Role: "lead"},
+		{CrewID: north.ID, UserID: bob, Role: "member"},
+		{CrewID: north.ID, UserID: carol, Role: "member", LeftAt: at(0, 0)},
+	}
+
+	rosters := services.EffectiveRosters(day, []*domain.Crew{north, south}, standing, nil)
+	require.Len(t, rosters, 2)
+	assert.Equal(t, uuid.Nil, rosters[north.ID].ID, "built from standing membership")
+	assert.True(t, rosters[north.ID].HasMember(alice))
+	assert.True(t, rosters[north.ID].HasMember(bob))
+	assert.False(t, rosters[north.ID].HasMember(carol), "left the crew")
+	assert.Equal(t, []uuid.UUID{mower, trailer}, rosters[north.ID].EquipmentIDs)
+	assert.Empty(t, rosters[south.ID].Members)
+
+	// Bob and the mower ride with South today
+	saved := &domain.CrewRoster{
+		ID:           uuid.New(),
+		CrewID:       south.ID,
+		Date:         day,
+		EquipmentIDs: []uuid.UUID{mower},
+		Members:      []*domain.CrewRosterMember{{UserID: bob, Role: "lead"}},
+	}
+	rosters = services.EffectiveRosters(day, []*domain.Crew{north, south}, standing, []*domain.CrewRoster{saved})
+	assert.Same(t, saved, rosters[south.ID])
+	assert.False(t, rosters[north.ID].HasMember(bob), "a saved roster overrides standing membership")
+	assert.Equal(t, []uuid.UUID{trailer}, rosters[north.ID].EquipmentIDs)
+
+	assert.Equal(t, south.ID, *services.RosterCrewOf(rosters, bob))
+	assert.Equal(t, north.ID, *services.RosterCrewOf(rosters, alice))
+	assert.Nil(t, services.RosterCrewOf(rosters, carol))
+	assert.Equal(t, south.ID, *services.RosterCrewHolding(rosters, mower))
+}
+
+func TestRosterClashes(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	mower := uuid.New()
+	north, south := uuid.New(), uuid.New()
+	rosters := map[uuid.UUID]*domain.CrewRoster{
+		north: {ID: uuid.New(), CrewID: north, EquipmentIDs: []uuid.UUID{mower}, Members: []*domain.CrewRosterMember{{UserID: alice}}},
+		south: {CrewID: south, Members: []*domain.CrewRosterMember{{UserID: bob}}},
+	}
+
+	candidate := &domain.CrewRoster{CrewID: south, Members: []*domain.CrewRosterMember{{UserID: bob}}}
+	assert.Empty(t, services.RosterClashes(candidate, rosters))
+
+	candidate.Members = append(candidate.Members, &domain.CrewRosterMember{UserID: alice})
+	candidate.EquipmentIDs = []uuid.UUID{mower}
+	clashes := services.RosterClashes(candidate, rosters)
+	require.Len(t, clashes, 2)
+	assert.Equal(t, services.DispatchConflictMember, clashes[0].Type)
+	assert.Equal(t, alice, clashes[0].ResourceID)
+	assert.Equal(t, services.DispatchConflictEquipment, clashes[1].Type)
+
+	// Only saved rosters clash; built ones give people up
+	other := &domain.CrewRoster{CrewID: north, Members: []*domain.CrewRosterMember{{UserID: bob}}}
+	assert.Empty(t, services.RosterClashes(other, map[uuid.UUID]*domain.CrewRoster{south: rosters[south]}))
+}
+
+func TestRosterLead(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	assert.Nil(t, services.RosterLead(&domain.CrewRoster{}))
+	assert.Equal(t, alice, *services.RosterLead(&domain.CrewRoster{Members: []*domain.CrewRosterMember{{UserID: alice}, {UserID: bob}}}))
+	assert.Equal(t, bob, *services.RosterLead(&domain.CrewRoster{Members: []*domain.CrewRosterMember{{UserID: alice}, {UserID: bob, Role: "Foreman"}}}))
+}
+
+func TestJobOverlaps(t *testing.T) {
+	userID := uuid.New()
+	mowing := job("Mowing", &userID, at(9, 0), 60)
+	hedges := job("Hedges", &userID, at(9, 30), 0) // two hours without an estimate
+	cleanup := job("Cleanup", &userID, at(12, 0), 30)
+
+	overlaps := services.JobOverlaps(mowing, []*domain.EnhancedJob{mowing, hedges, cleanup})
+	require.Len(t, overlaps, 1)
+	assert.Equal(t, hedges.ID, *overlaps[0].JobID)
+	assert.Empty(t, services.JobOverlaps(cleanup, []*domain.EnhancedJob{mowing, hedges}), "hedges end at 11:30")
+}
+
+func TestDispatchRoute(t *testing.T) {
+	userID := uuid.New()
+	second := job("Second", &userID, at(11, 0), 45)
+	first := job("First", &userID, at(8, 30), 0)
+	cancelled := job("Cancelled", &userID, at(10, 0), 30)
+	cancelled.Status = domain.JobStatusCancelled
+
+	locations := map[uuid.UUID]*services.Location{
+		first.PropertyID:  {Latitude: 40.0, Longitude: -75.0, Address: "1 First St"},
+		second.PropertyID: {Latitude: 40.1, Longitude: -75.0, Address: "2 Second St"},
+	}
+
+	route := services.DispatchRoute([]*domain.EnhancedJob{second, cancelled, first}, locations)
+	require.Len(t, route, 2)
+	assert.Equal(t, first.ID, route[0].JobID)
+	assert.Equal(t, 1, route[0].Sequence)
+	assert.Equal(t, 120, route[0].Duration)
+	assert.Zero(t, route[0].Distance)
+	assert.Equal(t, "2 Second St", route[1].Address)
+	assert.Equal(t, *at(11, 0), route[1].ArrivalTime)
+	assert.InDelta(t, 6.9, route[1].Distance, 0.1, "a tenth of a degree of latitude is about 6.9 miles")
+}
+
+func TestBuildDispatchBoard(t *testing.T) {
+	alice, bob, dave := uuid.New(), uuid.New(), uuid.New()
+	mower := uuid.New()
+	north := &domain.Crew{ID: uuid.New(), Name: "North", EquipmentIDs: []uuid.UUID{mower}}
+	south := &domain.Crew{ID: uuid.New(), Name: "South"}
+	standing := []*domain.CrewMember{
+		{CrewID: north.ID, UserID: alice, Role: "lead"},
+		{CrewID: north.ID, UserID: bob, Role: "member"},
+		{CrewID: south.ID, UserID: dave, Role: "lead"},
+	}
+	// Dave is off South today
+	saved := &domain.CrewRoster{ID: uuid.New(), CrewID: south.ID, Date: day, Members: []*domain.CrewRosterMember{}}
+
+	mowing := job("Mowing", &alice, at(9, 0), 60)
+	mulch := job("Mulch", &bob, at(9, 30), 60)
+	late := job("Late", &alice, at(16, 30), 60)
+	unassigned := job("Unassigned", nil, at(10, 0), 60)
+	solo := job("Solo", &dave, at(13, 0), 60)
+	mowing.RequiresEquipment = []uuid.UUID{mower}
+
+	otherJob := uuid.New()
+	board := services.BuildDispatchBoard(&services.DispatchBoardInput{
+		Date:     day,
+		Crews:    []*domain.Crew{north, south},
+		Standing: standing,
+		Rosters:  services.EffectiveRosters(day, []*domain.Crew{north, south}, standing, []*domain.CrewRoster{saved}),
+		Jobs:     []*domain.EnhancedJob{late, mowing, mulch, unassigned, solo},
+		Windows: map[uuid.UUID][]services.TimeRange{
+			alice: workday(),
+			bob:   {},
+			dave:  workday(),
+		},
+		Unavailable: map[uuid.UUID][]services.AvailabilityConflict{
+			bob: {{ResourceID: bob, ResourceType: "user", Reason: "Time off: sick"}},
+		},
+		Reservations: []*domain.EquipmentReservation{
+			{EquipmentID: mower, JobID: &otherJob, StartsAt: *at(9, 30), EndsAt: *at(10, 30)},
+		},
+		Downtime:  map[uuid.UUID][]services.TimeRange{},
+		Equipment: map[uuid.UUID]*domain.Equipment{mower: {ID: mower, Name: "Zero-turn"}},
+		Names:     map[uuid.UUID]string{alice: "Alice Smith", bob: "Bob Jones"},
+		Locations: map[uuid.UUID]*services.Location{},
+	})
+
+	require.Len(t, board.Crews, 2)
+	northDay := board.Crews[0]
+	assert.False(t, northDay.Rostered)
+	require.Len(t, northDay.Members, 2)
+	assert.Equal(t, "Alice Smith", northDay.Members[0].Name)
+	require.Len(t, northDay.Equipment, 1)
+	assert.Equal(t, "Zero-turn", northDay.Equipment[0].Name)
+
+	require.Len(t, northDay.Jobs, 3)
+	assert.Equal(t, mowing.ID, northDay.Jobs[0].ID, "in route order")
+	assert.Equal(t, late.ID, northDay.Jobs[2].ID)
+	require.Len(t, northDay.Route, 3)
+
+	reasons := make([]string, 0, len(northDay.Conflicts))
+	for _, conflict := range northDay.Conflicts {
+		reasons = append(reasons, conflict.Reason)
+	}
+	assert.Contains(t, reasons, "Bob Jones is not working: Time off: sick")
+	assert.Contains(t, reasons, "Mowing overlaps Mulch from 9:30 AM to 10:30 AM")
+	assert.Contains(t, reasons, "Late is outside Alice Smith's working hours")
+	assert.Contains(t, reasons, "Zero-turn is already reserved from 9:30 AM to 10:30 AM")
+
+	southDay := board.Crews[1]
+	assert.True(t, southDay.Rostered)
+	assert.Empty(t, southDay.Members)
+	assert.Empty(t, southDay.Jobs)
+
+	require.Len(t, board.Unassigned, 2)
+	assert.Equal(t, unassigned.ID, board.Unassigned[0].ID)
+	assert.Equal(t, solo.ID, board.Unassigned[1].ID, "Dave is on no crew today")
+
+	require.Len(t, board.Bench, 1)
+	assert.Equal(t, dave, board.Bench[0].UserID)
+	assert.Equal(t, domain.CrewRoleLead, board.Bench[0].Role)
+}
@@ -0,0 +1,183 @@
+package equipmentassets_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func depreciationAmounts(periods []services.DepreciationPeriod) []float64 {
+	amounts := make([]float64, len(periods))
+	for i, period := range periods {
+		amounts[i] = period.Depreciation
+	}
+	return amounts
+}
+
+func TestValidateAssetProfileRequest(t *testing.T) {
+	req := &services.EquipmentAssetProfileRequest{DepreciationMethod: domain.DepreciationStraightLine, UsefulLifeYears: 7, SalvageValue: 1500}
+	assert.NoError(t, services.ValidateAssetProfileRequest(req))
+
+	assert.Error(t, services.ValidateAssetProfileRequest(&services.EquipmentAssetProfileRequest{DepreciationMethod: "sum_of_years", UsefulLifeYears: 7}))
+	assert.Error(t, services.ValidateAssetProfileRequest(&services.EquipmentAssetProfileRequest{DepreciationMethod: domain.DepreciationStraightLine}))
+	assert.Error(t, services.ValidateAssetProfileRequest(&services.EquipmentAssetProfileRequest{DepreciationMethod: domain.DepreciationMACRS, UsefulLifeYears: 6}), "MACRS only has tables for the standard recovery periods")
+	assert.Error(t, services.ValidateAssetProfileRequest(&services.EquipmentAssetProfileRequest{DepreciationMethod: domain.DepreciationStraightLine, UsefulLifeYears: 7, SalvageValue: -1}))
+}
+
+func TestBuildDepreciationScheduleStraightLine(t *testing.T) {
+	profile := &domain.EquipmentAssetProfile{DepreciationMethod: domain.DepreciationStraightLine, UsefulLifeYears: 5, SalvageValue: 2000}
+
+	periods, err := services.BuildDepreciationSchedule(12000, date(2024, time.July, 15), profile)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1000, 2000, 2000, 2000, 2000, 1000}, depreciationAmounts(periods), "the first and last years are half years")
+	assert.Equal(t, date(2024, time.July, 1), periods[0].PeriodStart)
+	assert.Equal(t, 2000.0, periods[5].ClosingBookValue)
+	assert.Equal(t, 10000.0, periods[5].AccumulatedDepreciation)
+
+	assert.Equal(t, 12000.0, services.BookValueAt(12000, periods, date(2024, time.March, 1)))
+	assert.Equal(t, 8500.0, services.BookValueAt(12000, periods, date(2026, time.April, 10)))
+	assert.Equal(t, 2000.0, services.BookValueAt(12000, periods, date(2035, time.January, 1)))
+}
+
+func TestBuildDepreciationScheduleDecliningBalance(t *testing.T) {
+	profile := &domain.EquipmentAssetProfile{DepreciationMethod: domain.DepreciationDecliningBalance, UsefulLifeYears: 5, DecliningBalanceRate: 2}
+
+	periods, err := services.BuildDepreciationSchedule(10000, date(2024, time.January, 10), profile)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{4000, 2400, 1440, 1080, 1080}, depreciationAmounts(periods), "switches to straight-line in the fourth year")
+	assert.Equal(t, 0.0, periods[4].ClosingBookValue)
+
+	profile.SalvageValue = 1000
+	periods, err = services.BuildDepreciationSchedule(10000, date(2024, time.January, 10), profile)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{4000, 2400, 1440, 864, 296}, depreciationAmounts(periods), "never depreciates below salvage value")
+}
+
+func TestBuildDepreciationScheduleMACRS(t *testing.T) {
+	profile := &domain.EquipmentAssetProfile{DepreciationMethod: domain.DepreciationMACRS, UsefulLifeYears: 5, SalvageValue: 500}
+
+	periods, err := services.BuildDepreciationSchedule(10000, date(2024, time.March, 4), profile)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{2000, 3200, 1920, 1152, 1152, 576}, depreciationAmounts(periods), "salvage value is ignored")
+
+	disposedAt := date(2026, time.June, 30)
+	profile.DisposedAt = &disposedAt
+	periods, err = services.BuildDepreciationSchedule(10000, date(2024, time.March, 4), profile)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{2000, 3200, 960}, depreciationAmounts(periods), "the disposal year takes half a year")
+	assert.Equal(t, disposedAt, periods[2].PeriodEnd)
+
+	_, err = services.BuildDepreciationSchedule(10000, date(2024, time.March, 4), &domain.EquipmentAssetProfile{DepreciationMethod: domain.DepreciationMACRS, UsefulLifeYears: 4})
+	assert.Error(t, err)
+}
+
+func TestRecommendReplaceOrRepair(t *testing.T) {
+	base := func() *services.ReplaceOrRepairAnalysis {
+		return &services.ReplaceOrRepairAnalysis{
+			AgeYears:                2,
+			UsefulLifeYears:         7,
+			BookValue:               20000,
+			ReplacementCost:         35000,
+			AnnualReplacementCost:   5000,
+			TrailingMaintenanceCost: 800,
+			TrailingDowntimeCost:    200,
+		}
+	}
+
+	analysis := base()
+	services.RecommendReplaceOrRepair(analysis)
+	assert.Equal(t, services.ReplaceOrRepairRepair, analysis.Recommendation)
+
+	analysis = base()
+	analysis.AgeYears = 5.5
+	services.RecommendReplaceOrRepair(analysis)
+	assert.Equal(t, services.ReplaceOrRepairPlanReplacement, analysis.Recommendation, "near the end of its useful life")
+
+	analysis = base()
+	analysis.TrailingMaintenanceCost = 9000
+	analysis.RepairEstimate = 9000
+	services.RecommendReplaceOrRepair(analysis)
+	assert.Equal(t, services.ReplaceOrRepairReplace, analysis.Recommendation, "repairs reach half the replacement cost")
+
+	analysis = base()
+	analysis.AgeYears = 8
+	analysis.TrailingMaintenanceCost = 5800
+	services.RecommendReplaceOrRepair(analysis)
+	assert.Equal(t, services.ReplaceOrRepairReplace, analysis.Recommendation, "past its life and dearer to run than to replace")
+
+	analysis = base()
+	analysis.AgeYears = 6
+	analysis.BookValue = 3000
+	analysis.ReplacementCost = 0
+	analysis.RepairEstimate = 3500
+	services.RecommendReplaceOrRepair(analysis)
+	assert.Equal(t, services.ReplaceOrRepairReplace, analysis.Recommendation, "the repair costs more than the equipment is worth")
+	require.Len(t, analysis.Reasons, 1)
+	assert.Contains(t, analysis.Reasons[0], "book value")
+}
+
+func TestFixedAssetRegister(t *testing.T) {
+	price := 10000.0
+	serial := "ZT-4471"
+	equipment := &domain.Equipment{ID: uuid.New(), Name: "Zero-turn mower", Type: "mower", SerialNumber: &serial, PurchasePrice: &price}
+	disposedAt := date(2026, time.June, 30)
+	proceeds := 4000.0
+	inService := date(2024, time.March, 4)
+	profile := &domain.EquipmentAssetProfile{
+		DepreciationMethod: domain.DepreciationMACRS,
+		UsefulLifeYears:    5,
+		InServiceDate:      &inService,
+		DisposedAt:         &disposedAt,
+		DisposalProceeds:   &proceeds,
+	}
+
+	periods, err := services.BuildDepreciationSchedule(price, inService, profile)
+	require.NoError(t, err)
+	schedule := &services.DepreciationSchedule{
+		EquipmentID:     equipment.ID,
+		Method:          profile.DepreciationMethod,
+		Cost:            price,
+		UsefulLifeYears: profile.UsefulLifeYears,
+		InServiceDate:   inService,
+		Periods:         periods,
+	}
+
+	_, ok := services.BuildFixedAssetRegisterEntry(equipment, profile, schedule, 2023)
+	assert.False(t, ok, "not yet in service")
+	_, ok = services.BuildFixedAssetRegisterEntry(equipment, profile, schedule, 2027)
+	assert.False(t, ok, "already disposed of")
+
+	entry, ok := services.BuildFixedAssetRegisterEntry(equipment, profile, schedule, 2026)
+	require.True(t, ok)
+	assert.Equal(t, 5200.0, entry.OpeningAccumulatedDepreciation)
+	assert.Equal(t, 960.0, entry.Depreciation)
+	assert.Equal(t, 6160.0, entry.ClosingAccumulatedDepreciation)
+	assert.Equal(t, 3840.0, entry.NetBookValue)
+	require.NotNil(t, entry.GainOnDisposal)
+	assert.Equal(t, 160.0, *entry.GainOnDisposal)
+
+	data, err := services.BuildFixedAssetRegisterCSV(&services.FixedAssetRegister{
+		Year:              2026,
+		Entries:           []*services.FixedAssetRegisterEntry{entry},
+		TotalCost:         entry.Cost,
+		TotalDepreciation: entry.Depreciation,
+	})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "Depreciation 2026")
+	assert.Contains(t, lines[1], "Zero-turn mower,mower,ZT-4471,03/04/2024,macrs,5,10000.00,0.00,5200.00,960.00,6160.00,3840.00,06/30/2026,4000.00,160.00")
+	assert.Contains(t, lines[2], "Total")
+}
@@ -0,0 +1,94 @@
+package equipmentcheckouts_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func TestValidateEquipmentCheckoutRequest(t *testing.T) {
+	now := time.Now()
+	userID, crewID := uuid.New(), uuid.New()
+	dueBack := now.Add(8 * time.Hour)
+
+	req := &services.EquipmentCheckoutRequest{UserID: &userID, DueBackAt: &dueBack}
+	req.Code = "EQ-AB12CD34"
+	assert.NoError(t, services.ValidateEquipmentCheckoutRequest(req, now))
+
+	unidentified := &services.EquipmentCheckoutRequest{UserID: &userID}
+	assert.Error(t, services.ValidateEquipmentCheckoutRequest(unidentified, now))
+
+	both := &services.EquipmentCheckoutRequest{UserID: &userID, CrewID: &crewID}
+	both.Code = "EQ-AB12CD34"
+	assert.Error(t, services.ValidateEquipmentCheckoutRequest(both, now), "a user or a crew, not both")
+
+	nobody := &services.EquipmentCheckoutRequest{}
+	nobody.Code = "EQ-AB12CD34"
+	assert.Error(t, services.ValidateEquipmentCheckoutRequest(nobody, now))
+
+	past := now.Add(-time.Hour)
+	late := &services.EquipmentCheckoutRequest{CrewID: &crewID, DueBackAt: &past}
+	late.Code = "EQ-AB12CD34"
+	assert.Error(t, services.ValidateEquipmentCheckoutRequest(late, now))
+}
+
+func TestNormalizeAssetTagCode(t *testing.T) {
+	assert.Equal(t, "EQ-AB12CD34", services.NormalizeAssetTagCode("EQ-AB12CD34"))
+	assert.Equal(t, "EQ-AB12CD34", services.NormalizeAssetTagCode(" eq-ab12 cd34\n"))
+	assert.Equal(t, "EQ-AB12CD34", services.NormalizeAssetTagCode("ab12cd34"))
+	assert.Equal(t, "", services.NormalizeAssetTagCode("  "))
+}
+
+func TestBuildEquipmentWhereabouts(t *testing.T) {
+	now := time.Date(2026, time.May, 4, 15, 0, 0, 0, time.UTC)
+	equipment := &domain.Equipment{}
+	equipment.ID = uuid.New()
+	tag := &domain.EquipmentAssetTag{EquipmentID: equipment.ID, Code: "EQ-AB12CD34"}
+
+	inShop := services.BuildEquipmentWhereabouts(equipment, tag, nil, now)
+	assert.Equal(t, services.EquipmentLocationInShop, inShop.Location)
+	assert.Equal(t, "EQ-AB12CD34", inShop.AssetTag)
+	assert.Nil(t, inShop.Checkout)
+
+	userID := uuid.New()
+	dueBack := now.Add(2 * time.Hour)
+	checkout := &domain.EquipmentCheckout{EquipmentID: equipment.ID, UserID: &userID, CheckedOutAt: now.Add(-6 * time.Hour), DueBackAt: &dueBack}
+	out := services.BuildEquipmentWhereabouts(equipment, tag, checkout, now)
+	assert.Equal(t, services.EquipmentLocationCheckedOut, out.Location)
+	assert.Equal(t, 6.0, out.HoursOut)
+	assert.Zero(t, out.HoursOverdue)
+
+	overdue := services.BuildEquipmentWhereabouts(equipment, tag, checkout, now.Add(3*time.Hour+30*time.Minute))
+	assert.Equal(t, services.EquipmentLocationOverdue, overdue.Location)
+	assert.Equal(t, 1.5, overdue.HoursOverdue)
+
+	returned := now
+	checkout.CheckedInAt = &returned
+	assert.Equal(t, services.EquipmentLocationInShop, services.BuildEquipmentWhereabouts(equipment, tag, checkout, now.Add(4*time.Hour)).Location)
+}
+
+func TestBuildAssetLabelSheetPDF(t *testing.T) {
+	_, err := services.BuildAssetLabelSheetPDF(nil)
+	assert.Error(t, err)
+
+	labels := make([]services.AssetLabel, 31)
+	for i := range labels {
+		labels[i] = services.AssetLabel{Code: fmt.Sprintf("EQ-TEST%04d", i), Name: "Backpack Blower (Shop)", SerialNumber: "BB-1234"}
+	}
+
+	pdf, err := services.BuildAssetLabelSheetPDF(labels)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), "/Count 2", "thirty labels to a sheet")
+	assert.Contains(t, string(pdf), "(EQ-TEST0030) Tj")
+	assert.Contains(t, string(pdf), `(Backpack Blower \(Shop\)) Tj`)
+}
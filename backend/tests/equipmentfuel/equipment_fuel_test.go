@@ -0,0 +1,175 @@
+package equipmentfuel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func fill(fueledAt time.Time, gallons, price float64, odometer *float64) *services.FuelEntryRequest {
+	return &services.FuelEntryRequest{
+		FueledAt:       &fueledAt,
+		FuelType:       domain.FuelTypeGasoline,
+		Gallons:        gallons,
+		PricePerGallon: price,
+		Odometer:       odometer,
+	}
+}
+
+// truckFills records a truck's fills through NewFuelEntry and
+// ResolveFuelEfficiency, returning the entries oldest first
+func truckFills(t *testing.T, equipmentID uuid.UUID, odometers []float64, gallons []float64) []*domain.FuelEntry {
+	var entries []*domain.FuelEntry
+	start := time.Date(2026, time.March, 2, 8, 0, 0, 0, time.UTC)
+	for i := range odometers {
+		entry := services.NewFuelEntry(uuid.New(), equipmentID, fill(start.AddDate(0, 0, 7*i), gallons[i], 4, float(odometers[i])), start)
+		var previous *domain.FuelEntry
+		if len(entries) > 0 {
+			previous = entries[len(entries)-1]
+		}
+		require.NoError(t, services.ResolveFuelEfficiency(entry, previous))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestValidateFuelEntryRequest(t *testing.T) {
+	now := time.Now()
+	assert.NoError(t, services.ValidateFuelEntryRequest(fill(now, 20, 3.89, float(1200))))
+
+	req := fill(now, 20, 3.89, nil)
+	req.FuelType = "kerosene"
+	assert.Error(t, services.ValidateFuelEntryRequest(req))
+	assert.Error(t, services.ValidateFuelEntryRequest(fill(now, 0, 3.89, nil)))
+	assert.Error(t, services.ValidateFuelEntryRequest(fill(now, 20, -1, nil)))
+	assert.Error(t, services.ValidateFuelEntryRequest(fill(now, 20, 3.89, float(-5))))
+}
+
+func TestResolveFuelEfficiency(t *testing.T) {
+	equipmentID := uuid.New()
+	entries := truckFills(t, equipmentID, []float64{10000, 10300}, []float64{18, 20})
+
+	assert.Nil(t, entries[0].Usage, "the first fill has nothing to measure against")
+	require.NotNil(t, entries[1].Efficiency)
+	assert.Equal(t, 300.0, *entries[1].Usage)
+	assert.Equal(t, 15.0, *entries[1].Efficiency, "miles per gallon")
+	assert.Equal(t, 80.0, entries[1].TotalCost)
+
+	mower := services.NewFuelEntry(uuid.New(), equipmentID, &services.FuelEntryRequest{FuelType: domain.FuelTypeDiesel, Gallons: 6, PricePerGallon: 4, EngineHours: float(412)}, time.Now())
+	previous := services.NewFuelEntry(uuid.New(), equipmentID, &services.FuelEntryRequest{FuelType: domain.FuelTypeDiesel, Gallons: 6, PricePerGallon: 4, EngineHours: float(408)}, time.Now())
+	require.NoError(t, services.ResolveFuelEfficiency(mower, previous))
+	assert.Equal(t, domain.EquipmentMeterHours, *mower.Meter)
+	assert.Equal(t, 1.5, *mower.Efficiency, "gallons per hour")
+
+	backwards := services.NewFuelEntry(uuid.New(), equipmentID, fill(time.Now(), 10, 4, float(9000)), time.Now())
+	assert.Error(t, services.ResolveFuelEfficiency(backwards, entries[1]))
+}
+
+func TestDetectFuelAnomalies(t *testing.T) {
+	equipmentID := uuid.New()
+	history := truckFills(t, equipmentID, []float64{10000, 10300, 10600, 10900, 11200}, []float64{20, 20, 20, 20, 20})
+	baseline := services.BuildFuelBaseline(history, history[0].Meter)
+	assert.Equal(t, 15.0, baseline.Efficiency)
+	assert.Equal(t, 20.0, baseline.Gallons)
+
+	normal := truckFills(t, equipmentID, []float64{11200, 11490}, []float64{20, 20})[1]
+	assert.Empty(t, services.DetectFuelAnomalies(normal, baseline))
+
+	thirsty := truckFills(t, equipmentID, []float64{11200, 11400}, []float64{20, 20})[1]
+	assert.Equal(t, []string{domain.FuelAnomalyLowEfficiency}, services.DetectFuelAnomalies(thirsty, baseline), "10 mpg against a usual 15")
+
+	jerrycans := truckFills(t, equipmentID, []float64{11200, 11650}, []float64{20, 35})[1]
+	assert.Equal(t, []string{domain.FuelAnomalyUnusualVolume}, services.DetectFuelAnomalies(jerrycans, baseline))
+
+	parked := truckFills(t, equipmentID, []float64{11200, 11200}, []float64{20, 12})[1]
+	assert.Equal(t, []string{domain.FuelAnomalyNoUsage}, services.DetectFuelAnomalies(parked, baseline))
+
+	short := services.BuildFuelBaseline(history[:2], history[0].Meter)
+	assert.Empty(t, services.DetectFuelAnomalies(thirsty, short), "too little history to compare against")
+}
+
+func TestBuildFuelEfficiencyReport(t *testing.T) {
+	equipmentID := uuid.New()
+	entries := truckFills(t, equipmentID,
+		[]float64{10000, 10300, 10600, 10900, 11100},
+		[]float64{20, 20, 20, 20, 20})
+	// The last fill lands in April
+	entries[4].FueledAt = time.Date(2026, time.April, 3, 8, 0, 0, 0, time.UTC)
+	entries[4].AnomalyFlags = []string{domain.FuelAnomalyLowEfficiency}
+
+	report := services.BuildFuelEfficiencyReport(entries)
+	assert.Equal(t, domain.EquipmentMeterMiles, report.Meter)
+	assert.Equal(t, services.FuelEfficiencyMPG, report.Unit)
+	assert.Equal(t, 100.0, report.Gallons)
+	assert.Equal(t, 400.0, report.Cost)
+	assert.Equal(t, 1100.0, report.Usage)
+	require.NotNil(t, report.Efficiency)
+	assert.Equal(t, 13.75, *report.Efficiency, "the first fill is left out")
+	require.NotNil(t, report.CostPerUnit)
+	assert.Equal(t, 0.29, *report.CostPerUnit)
+
+	require.Len(t, report.Trend, 2)
+	assert.Equal(t, 15.0, *report.Trend[0].Efficiency)
+	assert.Equal(t, 10.0, *report.Trend[1].Efficiency)
+	require.NotNil(t, report.ChangePercent)
+	assert.Equal(t, -27.3, *report.ChangePercent)
+	assert.True(t, report.Deteriorating)
+	assert.Len(t, report.Anomalies, 1)
+}
+
+func TestAllocateJobFuel(t *testing.T) {
+	truckID := uuid.New()
+	mowerID := uuid.New()
+	entries := truckFills(t, truckID, []float64{10000, 10300, 10600}, []float64{20, 20, 20})
+	mowerUsage := 8.0
+	mowerMeter := domain.EquipmentMeterHours
+	entries = append(entries, &domain.FuelEntry{EquipmentID: mowerID, Gallons: 12, TotalCost: 48, Meter: &mowerMeter, Usage: &mowerUsage})
+
+	rates := services.CalculateFuelRates(entries)
+	require.Len(t, rates, 2)
+	assert.Equal(t, services.FuelRate{EquipmentID: truckID, Meter: domain.EquipmentMeterMiles, CostPerUnit: 0.2667}, rates[0])
+	assert.Equal(t, services.FuelRate{EquipmentID: mowerID, Meter: domain.EquipmentMeterHours, CostPerUnit: 6}, rates[1])
+
+	jobA, jobB := uuid.New(), uuid.New()
+	readings := []*domain.EquipmentMeterReading{
+		{EquipmentID: truckID, Meter: domain.EquipmentMeterMiles, Usage: 30, JobID: &jobA},
+		{EquipmentID: mowerID, Meter: domain.EquipmentMeterHours, Usage: 2.5, JobID: &jobA},
+		{EquipmentID: truckID, Meter: domain.EquipmentMeterHours, Usage: 1, JobID: &jobA}, // truck is priced by the mile
+		{EquipmentID: truckID, Meter: domain.EquipmentMeterMiles, Usage: 12, JobID: &jobB},
+		{EquipmentID: uuid.New(), Meter: domain.EquipmentMeterHours, Usage: 3, JobID: &jobB}, // no fuel logged
+	}
+
+	allocations := services.AllocateJobFuel(rates, readings)
+	require.Len(t, allocations, 3)
+	assert.Equal(t, 8.0, allocations[0].Cost)
+	assert.Equal(t, 15.0, allocations[1].Cost)
+	assert.Equal(t, 3.2, allocations[2].Cost)
+
+	job := &domain.EnhancedJob{}
+	job.ID = jobA
+	job.TotalAmount = float(250)
+	costing := services.BuildJobCosting(job, allocations)
+	assert.Equal(t, 23.0, costing.FuelCost)
+	assert.Len(t, costing.Fuel, 2)
+	assert.Equal(t, 227.0, costing.GrossProfit)
+	require.NotNil(t, costing.MarginPercent)
+	assert.Equal(t, 90.8, *costing.MarginPercent)
+
+	metrics := services.SummarizeFuelEfficiency(entries, allocations)
+	assert.Equal(t, 72.0, metrics.Gallons)
+	assert.Equal(t, 288.0, metrics.Cost)
+	assert.Len(t, metrics.ByEquipment, 2)
+	assert.Equal(t, 26.2, metrics.JobFuelCost)
+	assert.Equal(t, 2, metrics.JobsCosted)
+	assert.Equal(t, 3.2, metrics.ByJob[jobB])
+}
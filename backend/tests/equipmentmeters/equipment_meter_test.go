@@ -0,0 +1,134 @@
+package equipmentmeters_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+func intPtr(i int) *int           { return &i }
+
+var now = time.Date(2026, 5, 4, 8, 0, 0, 0, time.UTC)
+
+func day(n int) time.Time { return now.AddDate(0, 0, n) }
+
+func TestResolveMeterReading(t *testing.T) {
+	previous := &domain.EquipmentMeterReading{Meter: domain.EquipmentMeterHours, Reading: 120.5}
+
+	reading, usage, err := services.ResolveMeterReading(previous, &services.MeterReadingRequest{Meter: "hours", Reading: floatPtr(124)})
+	require.NoError(t, err)
+	assert.Equal(t, 124.0, reading)
+	assert.Equal(t, 3.5, usage)
+
+	reading, usage, err = services.ResolveMeterReading(previous, &services.MeterReadingRequest{Meter: "hours", Usage: floatPtr(2.25)})
+	require.NoError(t, err)
+	assert.Equal(t, 122.75, reading)
+	assert.Equal(t, 2.25, usage)
+
+	_, _, err = services.ResolveMeterReading(previous, &services.MeterReadingRequest{Meter: "hours", Reading: floatPtr(100)})
+	assert.Error(t, err, "meters only count up")
+
+	reading, usage, err = services.ResolveMeterReading(nil, &services.MeterReadingRequest{Meter: "miles", Reading: floatPtr(48210)})
+	require.NoError(t, err)
+	assert.Equal(t, 48210.0, reading)
+	assert.Zero(t, usage, "a first reading has no usage to measure")
+}
+
+func TestValidateMeterRequests(t *testing.T) {
+	assert.NoError(t, services.ValidateMeterReadingRequest(&services.MeterReadingRequest{Meter: "acres", Usage: floatPtr(1.5)}))
+	assert.Error(t, services.ValidateMeterReadingRequest(&services.MeterReadingRequest{Meter: "gallons", Usage: floatPtr(1)}))
+	assert.Error(t, services.ValidateMeterReadingRequest(&services.MeterReadingRequest{Meter: "hours"}))
+	assert.Error(t, services.ValidateMeterReadingRequest(&services.MeterReadingRequest{Meter: "hours", Reading: floatPtr(10), Usage: floatPtr(1)}))
+	assert.Error(t, services.ValidateMeterReadingRequest(&services.MeterReadingRequest{Meter: "hours", Usage: floatPtr(-1)}))
+
+	assert.NoError(t, services.ValidateUsageTriggerRequests([]*services.UsageTriggerRequest{
+		{Meter: "hours", Interval: 50}, {Meter: "miles", Interval: 5000},
+	}))
+	assert.Error(t, services.ValidateUsageTriggerRequests([]*services.UsageTriggerRequest{
+		{Meter: "hours", Interval: 50}, {Meter: "hours", Interval: 100},
+	}), "one trigger per meter")
+	assert.Error(t, services.ValidateUsageTriggerRequests([]*services.UsageTriggerRequest{{Meter: "hours", Interval: 0}}))
+}
+
+func TestEstimateJobUsage(t *testing.T) {
+	job := &domain.EnhancedJob{}
+	job.EstimatedDuration = intPtr(90)
+
+	assert.Equal(t, 1.5, services.EstimateJobUsage(domain.EquipmentMeterHours, job, 4), "hours come from the job's estimate")
+	assert.Equal(t, 12.0, services.EstimateJobUsage(domain.EquipmentMeterMiles, job, 12), "other meters use the average job usage")
+
+	job.EstimatedDuration = nil
+	assert.Equal(t, 4.0, services.EstimateJobUsage(domain.EquipmentMeterHours, job, 4))
+
+	start, end := now, now.Add(2*time.Hour+15*time.Minute)
+	job.ActualStartTime, job.ActualEndTime = &start, &end
+	assert.Equal(t, 2.25, services.JobUsageHours(job))
+	job.ActualEndTime = nil
+	assert.Zero(t, services.JobUsageHours(job))
+}
+
+func TestProjectUsageTrigger(t *testing.T) {
+	trigger := &domain.MaintenanceUsageTrigger{Meter: "hours", Interval: 50, LastServiceReading: 100, NextDueReading: 150}
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	jobs := []services.ProjectedJobUsage{
+		{JobID: third, Date: day(5), Usage: 4},
+		{JobID: first, Date: day(1), Usage: 4},
+		{JobID: second, Date: day(3), Usage: 4},
+	}
+
+	projection := services.ProjectUsageTrigger(trigger, 143, jobs, now)
+	assert.False(t, projection.Overdue)
+	assert.Equal(t, 7.0, projection.RemainingUsage)
+	assert.Equal(t, 155.0, projection.ProjectedReading)
+	require.NotNil(t, projection.ProjectedDueDate)
+	assert.Equal(t, day(3), *projection.ProjectedDueDate, "jobs are projected in date order")
+	assert.Equal(t, second, *projection.ReachedOnJobID)
+
+	projection = services.ProjectUsageTrigger(trigger, 120, jobs, now)
+	assert.Nil(t, projection.ProjectedDueDate, "not due within the scheduled jobs")
+	assert.Equal(t, 132.0, projection.ProjectedReading)
+
+	projection = services.ProjectUsageTrigger(trigger, 151, jobs, now)
+	assert.True(t, projection.Overdue)
+	assert.Equal(t, now, *projection.ProjectedDueDate)
+}
+
+func TestForecastMaintenanceWhicheverComesFirst(t *testing.T) {
+	calendar := day(10)
+	equipment := &domain.Equipment{ID: uuid.New(), Name: "Zero-turn mower", NextMaintenance: &calendar}
+	usageDue := day(3)
+	horizonEnd := day(14)
+
+	forecast := services.ForecastMaintenance(equipment, []services.UsageProjection{
+		{Meter: "hours", ProjectedDueDate: &usageDue},
+	}, now, horizonEnd)
+	assert.Equal(t, services.MaintenanceDueByUsage, forecast.DueBy)
+	assert.Equal(t, usageDue, *forecast.DueDate)
+	assert.True(t, forecast.DueWithinHorizon)
+	assert.False(t, forecast.Overdue)
+
+	forecast = services.ForecastMaintenance(equipment, []services.UsageProjection{{Meter: "hours"}}, now, horizonEnd)
+	assert.Equal(t, services.MaintenanceDueByDate, forecast.DueBy, "the calendar date applies when usage will not reach the interval")
+	assert.Equal(t, calendar, *forecast.DueDate)
+
+	forecast = services.ForecastMaintenance(equipment, []services.UsageProjection{{Meter: "hours", Overdue: true, ProjectedDueDate: &now}}, now, horizonEnd)
+	assert.True(t, forecast.Overdue)
+
+	equipment.NextMaintenance = nil
+	forecast = services.ForecastMaintenance(equipment, nil, now, horizonEnd)
+	assert.Nil(t, forecast.DueDate)
+	assert.False(t, forecast.DueWithinHorizon)
+
+	past := day(-2)
+	equipment.NextMaintenance = &past
+	forecast = services.ForecastMaintenance(equipment, nil, now, horizonEnd)
+	assert.True(t, forecast.Overdue)
+	assert.Equal(t, services.MaintenanceDueByDate, forecast.DueBy)
+}
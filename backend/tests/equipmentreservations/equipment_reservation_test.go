@@ -0,0 +1,138 @@
+package equipmentreservations_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+func intPtr(i int) *int           { return &i }
+
+var now = time.Date(2026, 5, 4, 8, 0, 0, 0, time.UTC)
+
+func at(hour, minute int) time.Time {
+	return time.Date(2026, 5, 4, hour, minute, 0, 0, time.UTC)
+}
+
+func TestJobReservationWindow(t *testing.T) {
+	scheduled := at(9, 0)
+
+	start, end, ok := services.JobReservationWindow(&domain.EnhancedJob{Job: domain.Job{ScheduledDate: &scheduled, EstimatedDuration: intPtr(150)}})
+	require.True(t, ok)
+	assert.Equal(t, at(9, 0), start)
+	assert.Equal(t, at(11, 30), end)
+
+	_, end, ok = services.JobReservationWindow(&domain.EnhancedJob{Job: domain.Job{ScheduledDate: &scheduled}})
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 5, 5, 0, 0, 0, 0, time.UTC), end, "jobs without a duration hold equipment for the rest of the day")
+
+	_, _, ok = services.JobReservationWindow(&domain.EnhancedJob{})
+	assert.False(t, ok, "unscheduled jobs hold no equipment")
+}
+
+func TestBuildJobReservations(t *testing.T) {
+	scheduled := at(9, 0)
+	mower, trailer := uuid.New(), uuid.New()
+	crewID := uuid.New()
+	job := &domain.EnhancedJob{
+		Job: domain.Job{
+			ID:                uuid.New(),
+			TenantID:          uuid.New(),
+			Status:            domain.JobStatusScheduled,
+			ScheduledDate:     &scheduled,
+			EstimatedDuration: intPtr(120),
+		},
+		RequiresEquipment: []uuid.UUID{mower, trailer, mower},
+	}
+
+	reservations := services.BuildJobReservations(job, &crewID, nil, now)
+	require.Len(t, reservations, 2, "duplicate equipment is reserved once")
+	assert.Equal(t, mower, reservations[0].EquipmentID)
+	assert.Equal(t, trailer, reservations[1].EquipmentID)
+	for _, reservation := range reservations {
+		assert.Equal(t, job.ID, *reservation.JobID)
+		assert.Equal(t, crewID, *reservation.CrewID)
+		assert.Equal(t, job.TenantID, reservation.TenantID)
+		assert.Equal(t, at(9, 0), reservation.StartsAt)
+		assert.Equal(t, at(11, 0), reservation.EndsAt)
+	}
+
+	job.Status = domain.JobStatusCompleted
+	assert.Empty(t, services.BuildJobReservations(job, &crewID, nil, now), "completed jobs release their equipment")
+
+	job.Status = domain.JobStatusScheduled
+	job.ScheduledDate = nil
+	assert.Empty(t, services.BuildJobReservations(job, &crewID, nil, now))
+}
+
+func TestReservationConflicts(t *testing.T) {
+	jobID, otherJobID := uuid.New(), uuid.New()
+	own := &domain.EquipmentReservation{JobID: &jobID, StartsAt: at(9, 0), EndsAt: at(11, 0)}
+	other := &domain.EquipmentReservation{JobID: &otherJobID, StartsAt: at(10, 0), EndsAt: at(12, 0)}
+	blockOut := &domain.EquipmentReservation{StartsAt: at(13, 0), EndsAt: at(15, 0)}
+	adjacent := &domain.EquipmentReservation{JobID: &otherJobID, StartsAt: at(11, 0), EndsAt: at(12, 0)}
+	reservations := []*domain.EquipmentReservation{own, other, blockOut, adjacent}
+
+	conflicts := services.ReservationConflicts(reservations, jobID, at(9, 0), at(11, 0))
+	require.Len(t, conflicts, 1, "the job's own reservations and ones starting as it ends do not conflict")
+	assert.Same(t, other, conflicts[0])
+
+	conflicts = services.ReservationConflicts(reservations, jobID, at(14, 0), at(16, 0))
+	require.Len(t, conflicts, 1)
+	assert.Same(t, blockOut, conflicts[0])
+}
+
+func TestCheckTransportCapacity(t *testing.T) {
+	truck := &domain.Equipment{ID: uuid.New(), Name: "F-250", PayloadCapacityLbs: floatPtr(2000), TowingCapacityLbs: floatPtr(12000)}
+	trailer := &domain.Equipment{ID: uuid.New(), Name: "16ft trailer", WeightLbs: floatPtr(2200), PayloadCapacityLbs: floatPtr(7000)}
+	zeroTurn := &domain.Equipment{ID: uuid.New(), Name: "Zero turn", WeightLbs: floatPtr(1500)}
+	skidSteer := &domain.Equipment{ID: uuid.New(), Name: "Skid steer", WeightLbs: floatPtr(8500)}
+
+	assert.Empty(t, services.CheckTransportCapacity([]*domain.Equipment{truck, trailer, zeroTurn}))
+	assert.Empty(t, services.CheckTransportCapacity([]*domain.Equipment{truck, zeroTurn, zeroTurn}), "equipment is counted once")
+
+	warnings := services.CheckTransportCapacity([]*domain.Equipment{zeroTurn})
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "no truck or trailer")
+
+	warnings = services.CheckTransportCapacity([]*domain.Equipment{truck, trailer, zeroTurn, skidSteer})
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "equipment weighs 10000 lbs but the trucks and trailers carry 9000 lbs")
+
+	warnings = services.CheckTransportCapacity([]*domain.Equipment{trailer, zeroTurn})
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "1 trailers but only 0 trucks")
+
+	lightTruck := &domain.Equipment{ID: uuid.New(), Name: "Ranger", PayloadCapacityLbs: floatPtr(1500), TowingCapacityLbs: floatPtr(3500)}
+	warnings = services.CheckTransportCapacity([]*domain.Equipment{lightTruck, trailer, skidSteer})
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "loaded trailers weigh 9200 lbs but the trucks tow 3500 lbs")
+}
+
+func TestNextFreeSlot(t *testing.T) {
+	busy := []services.TimeRange{
+		{Start: at(12, 0), End: at(13, 0)},
+		{Start: at(9, 0), End: at(10, 30)},
+	}
+
+	slot := services.NextFreeSlot(at(8, 0), time.Hour, at(17, 0), busy)
+	require.NotNil(t, slot)
+	assert.Equal(t, at(8, 0), *slot, "a job that ends as the equipment is needed fits")
+
+	slot = services.NextFreeSlot(at(8, 30), time.Hour, at(17, 0), busy)
+	require.NotNil(t, slot)
+	assert.Equal(t, at(10, 30), *slot)
+
+	slot = services.NextFreeSlot(at(10, 30), 2*time.Hour, at(17, 0), busy)
+	require.NotNil(t, slot)
+	assert.Equal(t, at(13, 0), *slot, "a job too long for the gap waits for the next one")
+
+	assert.Nil(t, services.NextFreeSlot(at(15, 0), 3*time.Hour, at(17, 0), busy))
+}
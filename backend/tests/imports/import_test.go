@@ -0,0 +1,179 @@
+package imports_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+	"github.com/pageza/landscaping-app/backend/pkg/security"
+)
+
+func strPtr(s string) *string { return &s }
+
+func customer(first, last, email, phone, address, zip string) *domain.EnhancedCustomer {
+	c := &domain.EnhancedCustomer{Customer: domain.Customer{ID: uuid.New(), FirstName: first, LastName: last}}
+	if email != "" {
+		c.Email = strPtr(email)
+	}
+	if phone != "" {
+		c.Phone = strPtr(phone)
+	}
+	if address != "" {
+		c.AddressLine1 = strPtr(address)
+	}
+	if zip != "" {
+		c.ZipCode = strPtr(zip)
+	}
+	return c
+}
+
+func TestParseImportFileCSV(t *testing.T) {
+	content := "\xef\xbb\xbfName,Email,,Name\n\nJane Doe,jane@example.com\n,,,\n\"Smith, John\",john@example.com,x,y,extra\n"
+
+	sheet, err := services.ParseImportFile("customers.csv", []byte(content))
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.ImportFormatCSV, sheet.Format)
+	assert.Equal(t, []string{"Name", "Email", "Column 3", "Name (2)"}, sheet.Headers)
+	require.Len(t, sheet.Rows, 2, "blank rows are dropped")
+	assert.Equal(t, []string{"Jane Doe", "jane@example.com", "", ""}, sheet.Rows[0], "short rows are padded")
+	assert.Equal(t, []string{"Smith, John", "john@example.com", "x", "y"}, sheet.Rows[1], "long rows are truncated")
+}
+
+func TestParseImportFileSemicolonCSV(t *testing.T) {
+	sheet, err := services.ParseImportFile("export.CSV", []byte("Name;City\nJane Doe;Springfield\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Name", "City"}, sheet.Headers)
+	assert.Equal(t, [][]string{{"Jane Doe", "Springfield"}}, sheet.Rows)
+
+	_, err = services.ParseImportFile("customers.pdf", []byte("x"))
+	assert.Error(t, err)
+}
+
+func TestParseImportFileXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	parts := map[string]string{
+		"xl/sharedStrings.xml": `<sst><si><t>Name</t></si><si><t>Zip</t></si><si><r><t>Jane </t></r><r><t>Doe</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>
+			<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2" t="inlineStr"><is><t>inline</t></is></c><c r="C2"><v>2134</v></c></row>
+		</sheetData></worksheet>`,
+	}
+	for name, body := range parts {
+		w, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(body))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+
+	sheet, err := services.ParseImportFile("customers.xlsx", buf.Bytes())
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.ImportFormatXLSX, sheet.Format)
+	assert.Equal(t, []string{"Name", "Column 2", "Zip"}, sheet.Headers)
+	assert.Equal(t, [][]string{{"Jane Doe", "inline", "2134"}}, sheet.Rows)
+}
+
+func TestSuggestImportMapping(t *testing.T) {
+	mapping := services.SuggestImportMapping([]string{"Customer Name", "E-mail", "Mobile", "Service Address", "Town", "Postal Code", "Favourite Colour"})
+
+	assert.Equal(t, map[string]string{
+		"full_name":     "Customer Name",
+		"email":         "E-mail",
+		"phone":         "Mobile",
+		"address_line1": "Service Address",
+		"city":          "Town",
+		"zip_code":      "Postal Code",
+	}, mapping)
+
+	mapping = services.SuggestImportMapping([]string{"First Name", "Last Name", "Name"})
+	assert.Equal(t, "First Name", mapping["first_name"])
+	assert.Equal(t, "Last Name", mapping["last_name"])
+	assert.NotContains(t, mapping, "full_name", "a full name is redundant with first and last name")
+}
+
+func TestMapImportRow(t *testing.T) {
+	mapping := map[string]string{"full_name": "Name", "email": "Email"}
+
+	record := services.MapImportRow(mapping, map[string]string{"Name": "  Mary  Ann   Jones ", "Email": "MARY@example.com"})
+	assert.Equal(t, "Mary Ann", record.FirstName)
+	assert.Equal(t, "Jones", record.LastName)
+	assert.Equal(t, "MARY@example.com", record.Email)
+
+	record = services.MapImportRow(mapping, map[string]string{"Name": "Smith, John"})
+	assert.Equal(t, "John", record.FirstName)
+	assert.Equal(t, "Smith", record.LastName)
+}
+
+func TestValidateImportRecord(t *testing.T) {
+	validator := security.NewInputValidator()
+
+	record := &services.ImportRecord{
+		CompanyName:  "Acme Corp",
+		Email:        "Billing@Acme.example",
+		Phone:        "(555) 234-4567",
+		AddressLine1: "1 Main St",
+		City:         "Boston",
+		State:        "ma",
+		ZipCode:      "2134",
+	}
+	errs, warnings := services.ValidateImportRecord(validator, record)
+	assert.Empty(t, errs)
+	assert.Contains(t, warnings, "last_name: missing; using the company name")
+	assert.Contains(t, warnings, "zip_code: restored a leading zero")
+	assert.Equal(t, "Acme Corp", record.LastName)
+	assert.Equal(t, "billing@acme.example", record.Email)
+	assert.Equal(t, "MA", record.State)
+	assert.Equal(t, "02134", record.ZipCode)
+	assert.Equal(t, "commercial", record.CustomerType)
+
+	record = &services.ImportRecord{Email: "not-an-email", City: "Boston", LotSize: "big"}
+	errs, _ = services.ValidateImportRecord(validator, record)
+	fields := make([]string, 0, len(errs))
+	for _, message := range errs {
+		fields = append(fields, message[:bytes.IndexByte([]byte(message), ':')])
+	}
+	assert.ElementsMatch(t, []string{"last_name", "email", "address", "lot_size"}, fields)
+}
+
+func TestImportSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, services.ImportSimilarity("John Smith", "Smith, John"))
+	assert.Equal(t, 1.0, services.ImportSimilarity("12 Oak Street", "12 oak st."))
+	assert.Greater(t, services.ImportSimilarity("Jon Smith", "John Smith"), 0.85)
+	assert.Less(t, services.ImportSimilarity("Jane Doe", "Robert Brown"), 0.5)
+	assert.Equal(t, 0.0, services.ImportSimilarity("", "John"))
+}
+
+func TestFindDuplicateCustomer(t *testing.T) {
+	byEmail := customer("Janet", "Doe", "jane@example.com", "", "", "")
+	byPhone := customer("J", "Doe", "", "+15551234567", "", "")
+	byName := customer("Jon", "Smith", "", "", "12 Oak Street", "02134")
+	sameName := customer("John", "Smith", "", "", "99 Elm Road", "90210")
+
+	found, score := services.FindDuplicateCustomer(&services.ImportRecord{FirstName: "Jane", LastName: "Doe", Email: "JANE@example.com"}, []*domain.EnhancedCustomer{byPhone, byEmail})
+	assert.Equal(t, byEmail, found)
+	assert.Equal(t, 1.0, score)
+
+	found, score = services.FindDuplicateCustomer(&services.ImportRecord{LastName: "Roe", Phone: "555-123-4568"}, []*domain.EnhancedCustomer{byPhone})
+	assert.Nil(t, found, "phone digits must match")
+
+	found, score = services.FindDuplicateCustomer(&services.ImportRecord{LastName: "Doe", Phone: "(555) 123-4567"}, []*domain.EnhancedCustomer{byPhone})
+	assert.Equal(t, byPhone, found, "the US country code is ignored")
+	assert.Equal(t, 0.95, score)
+
+	record := &services.ImportRecord{FirstName: "John", LastName: "Smith", AddressLine1: "12 Oak St", ZipCode: "02134"}
+	found, score = services.FindDuplicateCustomer(record, []*domain.EnhancedCustomer{sameName, byName})
+	assert.Equal(t, byName, found, "a similar name at the same address")
+	assert.GreaterOrEqual(t, score, services.ImportDuplicateThreshold)
+
+	found, _ = services.FindDuplicateCustomer(&services.ImportRecord{FirstName: "John", LastName: "Smith", ZipCode: "10001"}, []*domain.EnhancedCustomer{sameName})
+	assert.Nil(t, found, "the same name elsewhere is a different customer")
+}
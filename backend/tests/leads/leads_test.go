@@ -0,0 +1,90 @@
+package leads_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+var now = time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+func stringPtr(s string) *string { return &s }
+
+func submission() *services.LeadSubmission {
+	renderedAt := now.Add(-time.Minute)
+	return &services.LeadSubmission{
+		FirstName:      "Dana",
+		LastName:       "Reyes",
+		Email:          "dana@example.com",
+		Phone:          "(555) 123-4567",
+		ServiceKey:     "lawn_care",
+		Message:        "Weekly mowing for a quarter acre lot",
+		FormRenderedAt: &renderedAt,
+	}
+}
+
+func TestScreenLeadAcceptsGenuineSubmission(t *testing.T) {
+	screening := services.ScreenLead(submission(), 0, now)
+	assert.False(t, screening.IsSpam)
+	assert.Zero(t, screening.Score)
+	assert.Empty(t, screening.Reasons)
+
+	oneLink := submission()
+	oneLink.Message = "Photos of the yard: https://photos.example.com/yard"
+	assert.False(t, services.ScreenLead(oneLink, 0, now).IsSpam, "a single link is not enough to flag a lead")
+}
+
+func TestScreenLeadFlagsSpam(t *testing.T) {
+	cases := map[string]func(*services.LeadSubmission){
+		"hidden form field": func(s *services.LeadSubmission) { s.Honeypot = "http://spam.example" },
+		"too quickly": func(s *services.LeadSubmission) {
+			renderedAt := now.Add(-time.Second)
+			s.FormRenderedAt = &renderedAt
+		},
+		"name contains a link": func(s *services.LeadSubmission) { s.FirstName = "www.cheap-seo.example" },
+		"no email or phone": func(s *services.LeadSubmission) {
+			s.Email, s.Phone = "", ""
+			s.Message = "Visit https://a.example and https://b.example"
+		},
+	}
+	for reason, mutate := range cases {
+		sub := submission()
+		mutate(sub)
+		screening := services.ScreenLead(sub, 0, now)
+		assert.True(t, screening.IsSpam, reason)
+		assert.GreaterOrEqual(t, screening.Score, services.LeadSpamThreshold, reason)
+		assert.Contains(t, strings.Join(screening.Reasons, "; "), reason)
+	}
+}
+
+func TestScreenLeadCombinesWeakSignals(t *testing.T) {
+	sub := submission()
+	sub.Email = "dana@mailinator.com"
+	screening := services.ScreenLead(sub, 0, now)
+	assert.False(t, screening.IsSpam, "a disposable address alone is allowed")
+
+	screening = services.ScreenLead(sub, 3, now)
+	assert.True(t, screening.IsSpam, "disposable address plus repeated submissions")
+	assert.Len(t, screening.Reasons, 2)
+}
+
+func TestFindDuplicateLead(t *testing.T) {
+	older := &domain.Lead{ID: uuid.New(), Status: domain.LeadStatusDenied, Email: stringPtr("Dana@Example.com"), CreatedAt: now.Add(-48 * time.Hour)}
+	newer := &domain.Lead{ID: uuid.New(), Status: domain.LeadStatusPending, Phone: stringPtr("+1 555-123-4567"), CreatedAt: now.Add(-time.Hour)}
+	spam := &domain.Lead{ID: uuid.New(), Status: domain.LeadStatusSpam, Email: stringPtr("dana@example.com"), CreatedAt: now}
+	other := &domain.Lead{ID: uuid.New(), Status: domain.LeadStatusPending, Email: stringPtr("someone@example.com"), CreatedAt: now}
+	candidates := []*domain.Lead{older, newer, spam, other}
+
+	assert.Equal(t, newer, services.FindDuplicateLead(candidates, "dana@example.com", "5551234567"),
+		"newest match wins and phone matches ignore formatting and country code")
+	assert.Equal(t, older, services.FindDuplicateLead(candidates, "DANA@example.com", ""),
+		"email matches ignore case and spam leads are skipped")
+	assert.Nil(t, services.FindDuplicateLead(candidates, "new@example.com", "5559999999"))
+	assert.Nil(t, services.FindDuplicateLead(candidates, "", ""))
+}
@@ -0,0 +1,309 @@
+package payroll_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// Monday
+var monday = time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+func period(days int) *domain.PayPeriod {
+	return &domain.PayPeriod{
+		ID:        uuid.New(),
+		StartDate: monday,
+		EndDate:   monday.AddDate(0, 0, days-1),
+		Status:    domain.PayPeriodDraft,
+	}
+}
+
+func shift(userID uuid.UUID, jobID *uuid.UUID, day, startHour, hours int) *domain.TimeEntry {
+	clockIn := monday.AddDate(0, 0, day).Add(time.Duration(startHour) * time.Hour)
+	clockOut := clockIn.Add(time.Duration(hours) * time.Hour)
+	return &domain.TimeEntry{ID: uuid.New(), UserID: userID, JobID: jobID, ClockIn: clockIn, ClockOut: &clockOut}
+}
+
+func plan(userID uuid.UUID, rules ...*domain.PayRule) *domain.PayPlan {
+	for _, rule := range rules {
+		rule.UserID = userID
+	}
+	return &domain.PayPlan{UserID: userID, Rules: rules}
+}
+
+func completedJob(title string, assignee *uuid.UUID, day int) *domain.EnhancedJob {
+	j := &domain.EnhancedJob{}
+	j.ID = uuid.New()
+	j.Title = title
+	j.Status = domain.JobStatusCompleted
+	j.AssignedUserID = assignee
+	ended := monday.AddDate(0, 0, day).Add(15 * time.Hour)
+	j.ActualEndTime = &ended
+	return j
+}
+
+func entryFor(t *testing.T, entries []*domain.PayrollRegisterEntry, userID uuid.UUID) *domain.PayrollRegisterEntry {
+	for _, entry := range entries {
+		if entry.UserID == userID {
+			return entry
+		}
+	}
+	require.Failf(t, "no register entry", "user %s", userID)
+	return nil
+}
+
+func TestTimeEntryHours(t *testing.T) {
+	entry := shift(uuid.New(), nil, 0, 8, 9)
+	entry.BreakMinutes = 30
+	assert.InDelta(t, 8.5, entry.Hours(), 0.001)
+
+	entry.ClockOut = nil
+	assert.Zero(t, entry.Hours(), "running entries are not paid")
+}
+
+func TestValidatePayPlan(t *testing.T) {
+	from := monday
+	to := monday.AddDate(0, 0, 30)
+	serviceID := uuid.New()
+
+	assert.NoError(t, services.ValidatePayPlan(&services.PayPlanRequest{Rules: []services.PayRuleRequest{
+		{Type: domain.PayRuleHourly, Rate: 18, EffectiveTo: &to},
+		{Type: domain.PayRuleHourly, Rate: 20, EffectiveFrom: ptr(to.AddDate(0, 0, 1))},
+		{Type: domain.PayRulePieceRate, Rate: 35, ServiceID: &serviceID},
+		{Type: domain.PayRuleRevenuePercent, Rate: 10},
+	}}))
+
+	assert.Error(t, services.ValidatePayPlan(&services.PayPlanRequest{Rules: []services.PayRuleRequest{{Type: "salary", Rate: 1}}}))
+	assert.Error(t, services.ValidatePayPlan(&services.PayPlanRequest{Rules: []services.PayRuleRequest{{Type: domain.PayRuleHourly, Rate: -1}}}))
+	assert.Error(t, services.ValidatePayPlan(&services.PayPlanRequest{Rules: []services.PayRuleRequest{{Type: domain.PayRuleRevenuePercent, Rate: 120}}}))
+	assert.Error(t, services.ValidatePayPlan(&services.PayPlanRequest{Rules: []services.PayRuleRequest{
+		{Type: domain.PayRuleHourly, Rate: 18, ServiceID: &serviceID},
+	}}), "only piece rates are for a service")
+	assert.Error(t, services.ValidatePayPlan(&services.PayPlanRequest{Rules: []services.PayRuleRequest{
+		{Type: domain.PayRuleHourly, Rate: 18, EffectiveFrom: &from},
+		{Type: domain.PayRuleHourly, Rate: 20, EffectiveFrom: &to},
+	}}), "two hourly rates at once")
+}
+
+func TestValidateTimeEntry(t *testing.T) {
+	clockIn := monday.Add(8 * time.Hour)
+	assert.NoError(t, services.ValidateTimeEntry(&services.TimeEntryRequest{ClockIn: clockIn}), "clocking in")
+	assert.NoError(t, services.ValidateTimeEntry(&services.TimeEntryRequest{ClockIn: clockIn, ClockOut: ptr(clockIn.Add(4 * time.Hour)), BreakMinutes: 30}))
+
+	assert.Error(t, services.ValidateTimeEntry(&services.TimeEntryRequest{}))
+	assert.Error(t, services.ValidateTimeEntry(&services.TimeEntryRequest{ClockIn: clockIn, ClockOut: &clockIn}))
+	assert.Error(t, services.ValidateTimeEntry(&services.TimeEntryRequest{ClockIn: clockIn, ClockOut: ptr(clockIn.Add(time.Hour)), BreakMinutes: 60}))
+}
+
+func TestCalculatePayrollHourlyOvertime(t *testing.T) {
+	userID := uuid.New()
+	var entries []*domain.TimeEntry
+	// 45 hours in the first week, 20 in the second
+	for day := 0; day < 5; day++ {
+		entries = append(entries, shift(userID, nil, day, 7, 9))
+	}
+	entries = append(entries, shift(userID, nil, 7, 7, 10), shift(userID, nil, 8, 7, 10))
+
+	register := services.CalculatePayroll(&services.PayrollInput{
+		Period:      period(14),
+		Plans:       []*domain.PayPlan{plan(userID, &domain.PayRule{Type: domain.PayRuleHourly, Rate: 20})},
+		TimeEntries: entries,
+	})
+	require.Len(t, register, 1)
+
+	entry := register[0]
+	assert.Equal(t, 65.0, entry.HoursWorked)
+	assert.Equal(t, 60.0, entry.RegularHours)
+	assert.Equal(t, 5.0, entry.OvertimeHours, "overtime resets each week")
+	assert.Equal(t, 1200.0, entry.RegularPay)
+	assert.Equal(t, 150.0, entry.OvertimePay)
+	assert.Equal(t, 1350.0, entry.GrossPay)
+}
+
+func TestCalculatePayrollSkipsTimeOutsideThePeriod(t *testing.T) {
+	userID := uuid.New()
+	running := shift(userID, nil, 1, 7, 0)
+	running.ClockOut = nil
+
+	register := services.CalculatePayroll(&services.PayrollInput{
+		Period:      period(7),
+		Plans:       []*domain.PayPlan{plan(userID, &domain.PayRule{Type: domain.PayRuleHourly, Rate: 20})},
+		TimeEntries: []*domain.TimeEntry{shift(userID, nil, 0, 7, 8), shift(userID, nil, 7, 7, 8), running},
+	})
+
+	assert.Equal(t, 8.0, entryFor(t, register, userID).HoursWorked)
+	assert.Equal(t, 160.0, entryFor(t, register, userID).GrossPay)
+}
+
+func TestCalculatePayrollJobCredit(t *testing.T) {
+	lead, member := uuid.New(), uuid.New()
+	mowing, mulch := uuid.New(), uuid.New()
+
+	mowJob := completedJob("Smith mow", &lead, 2)
+	lines := []*domain.JobService{
+		{ID: uuid.New(), JobID: mowJob.ID, ServiceID: mowing, Quantity: 3, UnitPrice: 60, TotalPrice: 180},
+		{ID: uuid.New(), JobID: mowJob.ID, ServiceID: mulch, Quantity: 4, UnitPrice: 55, TotalPrice: 220},
+	}
+	// The lead works a third of the job
+	entries := []*domain.TimeEntry{
+		shift(lead, &mowJob.ID, 2, 8, 2),
+		shift(member, &mowJob.ID, 2, 8, 4),
+	}
+
+	register := services.CalculatePayroll(&services.PayrollInput{
+		Period: period(7),
+		Plans: []*domain.PayPlan{
+			plan(lead,
+				&domain.PayRule{Type: domain.PayRuleRevenuePercent, Rate: 10},
+				&domain.PayRule{Type: domain.PayRuleCrewLeadBonus, Rate: 25},
+			),
+			plan(member, &domain.PayRule{Type: domain.PayRulePieceRate, Rate: 15, ServiceID: &mowing}),
+		},
+		TimeEntries: entries,
+		Jobs:        []*services.PayrollJob{{Job: mowJob, Services: lines}},
+		Services:    map[uuid.UUID]*domain.Service{mowing: {Name: "Mowing", Unit: ptr("acre")}},
+	})
+
+	leadEntry := entryFor(t, register, lead)
+	assert.InDelta(t, 13.33, leadEntry.CommissionPay, 0.001, "10% of a third of 400")
+	assert.Equal(t, 25.0, leadEntry.BonusPay)
+	assert.Zero(t, leadEntry.PieceRatePay)
+
+	memberEntry := entryFor(t, register, member)
+	assert.Equal(t, 30.0, memberEntry.PieceRatePay, "two thirds of 3 acres at 15")
+	assert.Zero(t, memberEntry.BonusPay)
+	require.Len(t, memberEntry.Lines, 1)
+	assert.Equal(t, "Mowing per acre: Smith mow", memberEntry.Lines[0].Description)
+	assert.Equal(t, mowJob.ID, *memberEntry.Lines[0].JobID)
+}
+
+func TestCalculatePayrollSoloJob(t *testing.T) {
+	userID := uuid.New()
+	soloJob := completedJob("Spring cleanup", &userID, 1)
+	soloJob.TotalAmount = ptr(500.0)
+	notDone := completedJob("Hedge trim", &userID, 1)
+	notDone.Status = domain.JobStatusCancelled
+	nextPeriod := completedJob("Aeration", &userID, 9)
+
+	register := services.CalculatePayroll(&services.PayrollInput{
+		Period: period(7),
+		Plans: []*domain.PayPlan{plan(userID,
+			&domain.PayRule{Type: domain.PayRulePieceRate, Rate: 40},
+			&domain.PayRule{Type: domain.PayRuleRevenuePercent, Rate: 5},
+			&domain.PayRule{Type: domain.PayRuleCrewLeadBonus, Rate: 25},
+		)},
+		Jobs: []*services.PayrollJob{{Job: soloJob}, {Job: notDone}, {Job: nextPeriod}},
+	})
+
+	entry := entryFor(t, register, userID)
+	assert.Equal(t, 40.0, entry.PieceRatePay, "the whole job goes to the assigned user")
+	assert.Equal(t, 25.0, entry.CommissionPay, "5% of the job total")
+	assert.Zero(t, entry.BonusPay, "nobody to lead")
+	assert.Equal(t, 65.0, entry.GrossPay)
+}
+
+func TestCalculatePayrollRuleEffectiveDates(t *testing.T) {
+	userID := uuid.New()
+	raise := monday.AddDate(0, 0, 3)
+
+	register := services.CalculatePayroll(&services.PayrollInput{
+		Period: period(7),
+		Plans: []*domain.PayPlan{plan(userID,
+			&domain.PayRule{Type: domain.PayRuleHourly, Rate: 18, EffectiveTo: ptr(raise.AddDate(0, 0, -1))},
+			&domain.PayRule{Type: domain.PayRuleHourly, Rate: 20, EffectiveFrom: &raise},
+		)},
+		TimeEntries: []*domain.TimeEntry{shift(userID, nil, 0, 8, 8), shift(userID, nil, 4, 8, 8)},
+	})
+
+	entry := entryFor(t, register, userID)
+	assert.Equal(t, 16.0, entry.RegularHours)
+	assert.Equal(t, 304.0, entry.RegularPay)
+	assert.Len(t, entry.Lines, 2, "a line per rate")
+}
+
+func TestJobCreditShares(t *testing.T) {
+	lead, member := uuid.New(), uuid.New()
+	j := completedJob("Install", &lead, 0)
+
+	shares := services.JobCreditShares(j, nil)
+	assert.Equal(t, map[uuid.UUID]float64{lead: 1}, shares)
+
+	shares = services.JobCreditShares(j, []*domain.TimeEntry{shift(member, &j.ID, 0, 8, 3), shift(member, nil, 0, 12, 3)})
+	assert.Equal(t, 1.0, shares[member])
+	assert.Contains(t, shares, lead, "the lead is credited for leading")
+	assert.Zero(t, shares[lead])
+}
+
+func registerFixture() []*domain.PayrollRegisterEntry {
+	jobID := uuid.New()
+	entry := &domain.PayrollRegisterEntry{
+		UserID:         uuid.New(),
+		FirstName:      "Ana",
+		LastName:       "Lopez",
+		EmployeeNumber: ptr("1042"),
+		Lines: []*domain.PayrollLine{
+			{Type: domain.EarningRegular, Description: "Regular hours", Quantity: 40, Rate: 20, Amount: 800},
+			{Type: domain.EarningOvertime, Description: "Overtime hours", Quantity: 2.5, Rate: 30, Amount: 75},
+			{Type: domain.EarningPieceRate, Description: "Mowing per acre: Smith mow", JobID: &jobID, Quantity: 3, Rate: 15, Amount: 45},
+			{Type: domain.EarningBonus, Description: "Crew lead: Smith mow", JobID: &jobID, Quantity: 1, Rate: 25, Amount: 25},
+		},
+	}
+	services.SummarizePayrollEntry(entry)
+	return []*domain.PayrollRegisterEntry{entry}
+}
+
+func TestBuildPayrollRegisterCSV(t *testing.T) {
+	data, err := services.BuildPayrollRegisterCSV(registerFixture())
+	require.NoError(t, err)
+
+	rows := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, rows, 5)
+	assert.Equal(t, "Employee Number,Last Name,First Name,Earning,Description,Job ID,Quantity,Rate,Amount", rows[0])
+	assert.Equal(t, "1042,Lopez,Ana,overtime,Overtime hours,,2.5,30,75.00", rows[2])
+}
+
+func TestBuildADPPaydataCSV(t *testing.T) {
+	entries := registerFixture()
+	assert.Equal(t, 945.0, entries[0].GrossPay)
+
+	data, err := services.BuildADPPaydataCSV(entries, "XYZ", "PAY0601")
+	require.NoError(t, err)
+
+	rows := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, rows, 3)
+	assert.Equal(t, "Co Code,Batch ID,File #,Reg Hours,O/T Hours,Earnings 3 Code,Earnings 3 Amount", rows[0])
+	assert.Equal(t, "XYZ,PAY0601,1042,40.00,2.50,P,45.00", rows[1])
+	assert.Equal(t, "XYZ,PAY0601,1042,,,B,25.00", rows[2])
+
+	entries[0].EmployeeNumber = nil
+	_, err = services.BuildADPPaydataCSV(entries, "XYZ", "")
+	assert.Error(t, err, "ADP matches employees by file number")
+}
+
+func TestBuildGustoHoursCSV(t *testing.T) {
+	data, err := services.BuildGustoHoursCSV(registerFixture())
+	require.NoError(t, err)
+
+	rows := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, rows, 2)
+	assert.Equal(t, "last_name,first_name,employee_id,regular_hours,overtime_hours,bonus,commission", rows[0])
+	assert.Equal(t, "Lopez,Ana,1042,40.00,2.50,25.00,45.00", rows[1])
+}
+
+func TestPayPeriodContains(t *testing.T) {
+	p := period(14)
+	assert.True(t, p.Contains(monday))
+	assert.True(t, p.Contains(monday.AddDate(0, 0, 13).Add(23*time.Hour)), "the end date is included")
+	assert.False(t, p.Contains(monday.AddDate(0, 0, 14)))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
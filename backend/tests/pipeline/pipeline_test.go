@@ -0,0 +1,143 @@
+package pipeline_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+var created = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+func stringPtr(s string) *string { return &s }
+func intPtr(i int) *int          { return &i }
+
+func stagesByKey(stages []*domain.PipelineStage) map[string]*domain.PipelineStage {
+	byKey := make(map[string]*domain.PipelineStage, len(stages))
+	for _, stage := range stages {
+		byKey[stage.Key] = stage
+	}
+	return byKey
+}
+
+// path records an opportunity moving through the given stages and leaves it in the last one
+func path(opportunity *domain.Opportunity, stages ...*domain.PipelineStage) []*domain.OpportunityStageChange {
+	changes := []*domain.OpportunityStageChange{{OpportunityID: opportunity.ID, ToStageID: stages[0].ID}}
+	for i := 1; i < len(stages); i++ {
+		from := stages[i-1].ID
+		changes = append(changes, &domain.OpportunityStageChange{OpportunityID: opportunity.ID, FromStageID: &from, ToStageID: stages[i].ID})
+	}
+	opportunity.StageID = stages[len(stages)-1].ID
+	return changes
+}
+
+func TestDefaultPipelineStages(t *testing.T) {
+	tenantID := uuid.New()
+	stages := services.DefaultPipelineStages(tenantID)
+
+	keys := make([]string, 0, len(stages))
+	for i, stage := range stages {
+		keys = append(keys, stage.Key)
+		assert.Equal(t, tenantID, stage.TenantID)
+		assert.Equal(t, i+1, stage.Position)
+		assert.True(t, stage.IsActive)
+	}
+	assert.Equal(t, []string{"new", "contacted", "site_visit", "quoted", "won", "lost"}, keys)
+
+	byKey := stagesByKey(stages)
+	assert.False(t, byKey["quoted"].IsClosed())
+	assert.True(t, byKey["won"].IsClosed())
+	assert.Equal(t, domain.PipelineStageLost, byKey["lost"].StageType)
+	assert.Equal(t, 100, byKey["won"].Probability)
+}
+
+func TestCalculatePipelineMetrics(t *testing.T) {
+	stages := services.DefaultPipelineStages(uuid.New())
+	s := stagesByKey(stages)
+	closedAt := created.AddDate(0, 0, 10)
+	salesperson := uuid.New()
+
+	won := &domain.Opportunity{ID: uuid.New(), Status: domain.OpportunityStatusWon, EstimatedValue: 1000,
+		Source: stringPtr("website"), AssignedTo: &salesperson, CloseReason: stringPtr("referral"), ClosedAt: &closedAt, CreatedAt: created}
+	lost := &domain.Opportunity{ID: uuid.New(), Status: domain.OpportunityStatusLost, EstimatedValue: 500,
+		Source: stringPtr("website"), AssignedTo: &salesperson, CloseReason: stringPtr("competitor"), ClosedAt: &closedAt, CreatedAt: created}
+	visiting := &domain.Opportunity{ID: uuid.New(), Status: domain.OpportunityStatusOpen, EstimatedValue: 2000,
+		Source: stringPtr("phone"), CreatedAt: created}
+	fresh := &domain.Opportunity{ID: uuid.New(), Status: domain.OpportunityStatusOpen, EstimatedValue: 400,
+		Probability: intPtr(20), CreatedAt: created}
+
+	var changes []*domain.OpportunityStageChange
+	changes = append(changes, path(won, s["new"], s["contacted"], s["site_visit"], s["quoted"], s["won"])...)
+	changes = append(changes, path(lost, s["new"], s["contacted"], s["lost"])...)
+	changes = append(changes, path(visiting, s["new"], s["contacted"], s["site_visit"])...)
+	changes = append(changes, path(fresh, s["new"])...)
+
+	metrics := services.CalculatePipelineMetrics(stages, []*domain.Opportunity{won, lost, visiting, fresh}, changes)
+
+	assert.Equal(t, 4, metrics.TotalOpportunities)
+	assert.Equal(t, 1, metrics.WonCount)
+	assert.Equal(t, 1, metrics.LostCount)
+	assert.Equal(t, 2, metrics.OpenCount)
+	assert.InDelta(t, 0.5, metrics.WinRate, 0.0001)
+	assert.InDelta(t, 0.25, metrics.ConversionRate, 0.0001)
+	assert.Equal(t, 2400.0, metrics.OpenValue)
+	assert.Equal(t, 1080.0, metrics.WeightedValue, "stage probability unless the opportunity overrides it")
+	assert.Equal(t, 1000.0, metrics.AverageDealSize)
+	assert.Equal(t, 10.0, metrics.AverageDaysToClose)
+
+	require.Len(t, metrics.Stages, len(stages))
+	reached := map[string]int{}
+	conversion := map[string]float64{}
+	for _, stage := range metrics.Stages {
+		reached[stage.Key] = stage.Reached
+		conversion[stage.Key] = stage.ConversionRate
+	}
+	assert.Equal(t, map[string]int{"new": 4, "contacted": 3, "site_visit": 2, "quoted": 1, "won": 0, "lost": 0}, reached)
+	assert.InDelta(t, 0.75, conversion["new"], 0.0001)
+	assert.InDelta(t, 2.0/3.0, conversion["contacted"], 0.0001)
+	assert.InDelta(t, 0.5, conversion["site_visit"], 0.0001)
+	assert.InDelta(t, 1.0, conversion["quoted"], 0.0001, "last open stage converts to won")
+
+	assert.Equal(t, []services.PipelineReasonCount{{Reason: "competitor", Count: 1, Value: 500}}, metrics.LossReasons)
+	assert.Equal(t, []services.PipelineReasonCount{{Reason: "referral", Count: 1, Value: 1000}}, metrics.WinReasons)
+
+	require.NotEmpty(t, metrics.Sources)
+	assert.Equal(t, "website", metrics.Sources[0].Source)
+	assert.Equal(t, 2, metrics.Sources[0].Count)
+	assert.InDelta(t, 0.5, metrics.Sources[0].ConversionRate, 0.0001)
+
+	require.Len(t, metrics.Salespeople, 2)
+	assert.Equal(t, &salesperson, metrics.Salespeople[0].UserID)
+	assert.InDelta(t, 0.5, metrics.Salespeople[0].WinRate, 0.0001)
+	assert.Nil(t, metrics.Salespeople[1].UserID, "unassigned opportunities are reported last")
+	assert.Equal(t, 2, metrics.Salespeople[1].OpenCount)
+}
+
+func TestCalculatePipelineMetricsEmpty(t *testing.T) {
+	metrics := services.CalculatePipelineMetrics(services.DefaultPipelineStages(uuid.New()), nil, nil)
+	assert.Zero(t, metrics.TotalOpportunities)
+	assert.Zero(t, metrics.WinRate)
+	assert.Zero(t, metrics.ConversionRate)
+	assert.NotNil(t, metrics.LossReasons)
+}
+
+func TestRenderTaskReminder(t *testing.T) {
+	due := time.Date(2026, 3, 5, 14, 0, 0, 0, time.UTC)
+	task := &domain.FollowUpTask{Title: "Call about spring cleanup", DueAt: due, Description: stringPtr("Ask about mulch")}
+	opportunity := &domain.Opportunity{Title: "Spring cleanup", EstimatedValue: 850, ContactName: stringPtr("Dana Reyes"), ContactPhone: stringPtr("555-123-4567")}
+
+	subject, body := services.RenderTaskReminder(task, opportunity, due.Add(-time.Hour))
+	assert.Equal(t, "Follow-up reminder: Call about spring cleanup", subject)
+	assert.Contains(t, body, "is due Thu Mar 5 at 2:00 PM")
+	assert.Contains(t, body, "Spring cleanup ($850.00)")
+	assert.Contains(t, body, "Dana Reyes, 555-123-4567")
+	assert.Contains(t, body, "Ask about mulch")
+
+	_, body = services.RenderTaskReminder(task, nil, due.Add(time.Hour))
+	assert.Contains(t, body, "was due")
+}
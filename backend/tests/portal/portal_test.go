@@ -0,0 +1,106 @@
+package portal_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+var now = time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+func timePtr(t time.Time) *time.Time { return &t }
+
+func visitIn(d time.Duration) *domain.EnhancedJob {
+	job := &domain.EnhancedJob{}
+	job.ID = uuid.New()
+	job.Status = domain.JobStatusScheduled
+	job.ScheduledDate = timePtr(now.Add(d))
+	return job
+}
+
+func skip() *services.PortalScheduleChangeRequest {
+	return &services.PortalScheduleChangeRequest{ChangeType: domain.ScheduleChangeSkip}
+}
+
+func reschedule(to time.Time) *services.PortalScheduleChangeRequest {
+	return &services.PortalScheduleChangeRequest{ChangeType: domain.ScheduleChangeReschedule, RequestedDate: &to}
+}
+
+func TestValidateScheduleChangeNotice(t *testing.T) {
+	settings := services.DefaultPortalSettings(uuid.New())
+
+	err := services.ValidateScheduleChange(settings, visitIn(24*time.Hour), skip(), now, 0, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at least 48 hours notice")
+
+	assert.NoError(t, services.ValidateScheduleChange(settings, visitIn(48*time.Hour), skip(), now, 0, false))
+
+	started := visitIn(72 * time.Hour)
+	started.Status = domain.JobStatusInProgress
+	err = services.ValidateScheduleChange(settings, started, skip(), now, 0, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "can no longer be changed")
+
+	unscheduled := visitIn(0)
+	unscheduled.ScheduledDate = nil
+	assert.Error(t, services.ValidateScheduleChange(settings, unscheduled, skip(), now, 0, false))
+}
+
+func TestValidateScheduleChangeSkipLimit(t *testing.T) {
+	settings := services.DefaultPortalSettings(uuid.New())
+	settings.MaxSkipsPerYear = 2
+	job := visitIn(7 * 24 * time.Hour)
+
+	assert.NoError(t, services.ValidateScheduleChange(settings, job, skip(), now, 1, false))
+
+	err := services.ValidateScheduleChange(settings, job, skip(), now, 2, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "skip limit of 2")
+
+	settings.AllowSkip = false
+	err = services.ValidateScheduleChange(settings, job, skip(), now, 0, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not allowed")
+}
+
+func TestValidateScheduleChangeReschedule(t *testing.T) {
+	settings := services.DefaultPortalSettings(uuid.New())
+	job := visitIn(7 * 24 * time.Hour)
+	original := *job.ScheduledDate
+
+	assert.NoError(t, services.ValidateScheduleChange(settings, job, reschedule(original.AddDate(0, 0, 14)), now, 0, false),
+		"moving by exactly the limit is allowed")
+
+	cases := map[string]*services.PortalScheduleChangeRequest{
+		"requested date is required":   {ChangeType: domain.ScheduleChangeReschedule},
+		"only move up to 14 days":      reschedule(original.AddDate(0, 0, 15)),
+		"new date must be at least 48": reschedule(now.Add(12 * time.Hour)),
+		"change type must be skip or":  {ChangeType: "cancel"},
+	}
+	for message, req := range cases {
+		err := services.ValidateScheduleChange(settings, job, req, now, 0, false)
+		require.Error(t, err, message)
+		assert.Contains(t, err.Error(), message)
+	}
+
+	err := services.ValidateScheduleChange(settings, job, reschedule(original.AddDate(0, 0, 1)), now, 0, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "scheduling is on hold")
+
+	settings.MaxRescheduleDays = 0
+	assert.NoError(t, services.ValidateScheduleChange(settings, job, reschedule(original.AddDate(0, 2, 0)), now, 0, false),
+		"zero means no limit")
+}
+
+func TestHashPortalToken(t *testing.T) {
+	hash := services.HashPortalToken("token")
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, services.HashPortalToken("token"))
+	assert.NotEqual(t, hash, services.HashPortalToken("token2"))
+}
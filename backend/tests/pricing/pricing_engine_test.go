@@ -0,0 +1,196 @@
+package pricing_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func strPtr(s string) *string        { return &s }
+func floatPtr(f float64) *float64    { return &f }
+func date(y, m, d int) time.Time     { return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC) }
+func datePtr(y, m, d int) *time.Time { t := date(y, m, d); return &t }
+
+func rule(name, ruleType string, rate float64) domain.PricingRule {
+	return domain.PricingRule{ID: uuid.New(), Name: name, RuleType: ruleType, Rate: rate, IsActive: true}
+}
+
+func hourlyRule(key string, rate float64) domain.PricingRule {
+	r := rule("Hourly", domain.PricingRuleUnitRate, rate)
+	r.ServiceKey, r.Unit = strPtr(key), strPtr(domain.PricingUnitHour)
+	return r
+}
+
+func standingBook(rules ...domain.PricingRule) *domain.PriceBook {
+	return &domain.PriceBook{ID: uuid.New(), Name: "Standard", EffectiveFrom: date(2020, 1, 1), IsActive: true, Rules: rules}
+}
+
+func TestApplyPricingRulesScopePrecedence(t *testing.T) {
+	serviceID := uuid.New()
+
+	global := rule("Default", domain.PricingRuleUnitRate, 40)
+	global.Unit = strPtr(domain.PricingUnitHour)
+	category := rule("Maintenance", domain.PricingRuleUnitRate, 50)
+	category.Unit, category.Category = strPtr(domain.PricingUnitHour), strPtr("maintenance")
+	byKey := hourlyRule("lawn_care", 60)
+	byService := rule("Mowing", domain.PricingRuleUnitRate, 70)
+	byService.Unit, byService.ServiceID = strPtr(domain.PricingUnitHour), &serviceID
+
+	books := []*domain.PriceBook{standingBook(global, category, byKey, byService)}
+	base := services.PriceRequest{Quantity: 2, Date: date(2026, 5, 1)}
+
+	cases := []struct {
+		name string
+		req  func(*services.PriceRequest)
+		rate float64
+	}{
+		{"no scope matches", func(*services.PriceRequest) {}, 40},
+		{"category", func(r *services.PriceRequest) { r.Category = "Maintenance" }, 50},
+		{"catalog key over category", func(r *services.PriceRequest) { r.Category, r.ServiceKey = "maintenance", "lawn_care" }, 60},
+		{"service over everything", func(r *services.PriceRequest) {
+			r.Category, r.ServiceKey, r.ServiceID = "maintenance", "lawn_care", &serviceID
+		}, 70},
+	}
+
+	for _, tc := range cases {
+		req := base
+		tc.req(&req)
+		breakdown := services.ApplyPricingRules(books, &req)
+		assert.Equal(t, tc.rate, breakdown.UnitRate, tc.name)
+		assert.Equal(t, tc.rate*2, breakdown.Total, tc.name)
+	}
+}
+
+func TestApplyPricingRulesSeasonalOverride(t *testing.T) {
+	standing := standingBook(hourlyRule("lawn_care", 50), rule("Minimum", domain.PricingRuleMinimumCharge, 75))
+	spring := &domain.PriceBook{
+		ID: uuid.New(), Name: "Spring rush", IsActive: true,
+		EffectiveFrom: date(2026, 3, 1), EffectiveTo: datePtr(2026, 5, 31),
+		Rules: []domain.PricingRule{hourlyRule("lawn_care", 65)},
+	}
+	books := []*domain.PriceBook{standing, spring}
+
+	req := &services.PriceRequest{ServiceKey: "lawn_care", Quantity: 1, Date: date(2026, 4, 15)}
+	breakdown := services.ApplyPricingRules(books, req)
+	assert.Equal(t, 65.0, breakdown.UnitRate, "seasonal rate while in effect")
+	require.Len(t, breakdown.Adjustments, 1, "minimum still comes from the standing book")
+	assert.Equal(t, 10.0, breakdown.Adjustments[0].Amount)
+	assert.Equal(t, 75.0, breakdown.Total)
+
+	req.Date = date(2026, 6, 1)
+	assert.Equal(t, 50.0, services.ApplyPricingRules(books, req).UnitRate, "standing rate after the season")
+
+	spring.IsActive = false
+	req.Date = date(2026, 4, 15)
+	assert.Equal(t, 50.0, services.ApplyPricingRules(books, req).UnitRate, "inactive books are ignored")
+}
+
+func TestApplyPricingRulesSizeTiers(t *testing.T) {
+	tier := func(min, max *float64, rate float64) domain.PricingRule {
+		r := rule("Tier", domain.PricingRuleSizeTier, rate)
+		r.Unit, r.MinValue, r.MaxValue = strPtr(domain.PricingUnitSqFt), min, max
+		return r
+	}
+	fallback := rule("Flat", domain.PricingRuleUnitRate, 0.02)
+	fallback.Unit = strPtr(domain.PricingUnitSqFt)
+
+	books := []*domain.PriceBook{standingBook(
+		tier(nil, floatPtr(5000), 0.010),
+		tier(floatPtr(5000), floatPtr(20000), 0.008),
+		fallback,
+	)}
+
+	breakdown := services.ApplyPricingRules(books, &services.PriceRequest{PropertySize: 4000})
+	assert.Equal(t, 4000.0, breakdown.Quantity, "square-foot rates price the property size")
+	assert.Equal(t, 40.0, breakdown.Total)
+
+	breakdown = services.ApplyPricingRules(books, &services.PriceRequest{PropertySize: 5000})
+	assert.Equal(t, 0.008, breakdown.UnitRate, "tier floors are inclusive")
+	assert.Equal(t, 40.0, breakdown.Total)
+
+	breakdown = services.ApplyPricingRules(books, &services.PriceRequest{PropertySize: 25000})
+	assert.Equal(t, 0.02, breakdown.UnitRate, "unit rate when no tier matches")
+	assert.Equal(t, 500.0, breakdown.Total)
+}
+
+func TestApplyPricingRulesCatalogPriceFallback(t *testing.T) {
+	breakdown := services.ApplyPricingRules(nil, &services.PriceRequest{
+		ServiceName: "Aeration", BasePrice: floatPtr(85), Unit: domain.PricingUnitVisit, Quantity: 2,
+	})
+	assert.Equal(t, 85.0, breakdown.UnitRate)
+	assert.Equal(t, 170.0, breakdown.Total)
+	assert.Empty(t, breakdown.Adjustments)
+}
+
+func TestApplyPricingRulesAdjustments(t *testing.T) {
+	weekly := rule("Weekly", domain.PricingRuleFrequencyDiscount, 0.10)
+	weekly.Frequency = strPtr("weekly")
+	biweekly := rule("Bi-weekly", domain.PricingRuleFrequencyDiscount, 0.05)
+	biweekly.Frequency = strPtr("bi-weekly")
+	zone := rule("Zone B", domain.PricingRuleZoneSurcharge, 0.20)
+	zone.ZipCodes = []string{"12400"}
+	near := rule("Route", domain.PricingRuleDistanceSurcharge, 0.10)
+	near.MinValue = floatPtr(15)
+	far := rule("Long route", domain.PricingRuleDistanceSurcharge, 0.30)
+	far.MinValue = floatPtr(30)
+	bundle := rule("Bundle", domain.PricingRuleBundleDiscount, 0.10)
+	bundle.MinValue = floatPtr(3)
+
+	books := []*domain.PriceBook{standingBook(
+		hourlyRule("lawn_care", 50),
+		weekly, biweekly, zone, near, far, bundle,
+		rule("Same day", domain.PricingRuleSameDayDiscount, 0.25),
+	)}
+
+	breakdown := services.ApplyPricingRules(books, &services.PriceRequest{
+		ServiceKey: "lawn_care", Quantity: 2, Frequency: "weekly", ZipCode: "12400",
+		SameDay: true, Distance: 20, ServiceCount: 3,
+	})
+
+	require.Len(t, breakdown.Adjustments, 5)
+	amounts := make(map[string]float64)
+	for _, adjustment := range breakdown.Adjustments {
+		amounts[adjustment.Description] = adjustment.Amount
+	}
+	assert.Equal(t, -10.0, amounts["Weekly"])
+	assert.Equal(t, 18.0, amounts["Zone B"])
+	assert.Equal(t, -27.0, amounts["Same day"])
+	assert.Equal(t, 8.1, amounts["Route"], "highest distance threshold reached")
+	assert.Equal(t, -8.91, amounts["Bundle"])
+	assert.Equal(t, 80.19, breakdown.Total)
+
+	breakdown = services.ApplyPricingRules(books, &services.PriceRequest{
+		ServiceKey: "lawn_care", Quantity: 2, Frequency: "biweekly", Distance: 15, ServiceCount: 2,
+	})
+	require.Len(t, breakdown.Adjustments, 1, "distance threshold is exclusive, bundle needs three services")
+	assert.Equal(t, "Bi-weekly", breakdown.Adjustments[0].Description)
+	assert.Equal(t, 95.0, breakdown.Total)
+}
+
+func TestValidatePricingRuleRequest(t *testing.T) {
+	cases := map[string]*services.PricingRuleRequest{
+		"unsupported rule type":            {Name: "x", RuleType: "mystery"},
+		"unit must be one of":              {Name: "x", RuleType: domain.PricingRuleUnitRate, Rate: 50},
+		"minimum must be less than":        {Name: "x", RuleType: domain.PricingRuleSizeTier, Unit: strPtr("sq_ft"), MinValue: floatPtr(10), MaxValue: floatPtr(5)},
+		"frequency is required":            {Name: "x", RuleType: domain.PricingRuleFrequencyDiscount, Rate: 0.1},
+		"zip codes are required":           {Name: "x", RuleType: domain.PricingRuleZoneSurcharge, Rate: 0.1},
+		"discount rate must be a fraction": {Name: "x", RuleType: domain.PricingRuleBundleDiscount, Rate: 10},
+		"rate cannot be negative":          {Name: "x", RuleType: domain.PricingRuleMinimumCharge, Rate: -1},
+	}
+
+	for message, req := range cases {
+		err := services.ValidatePricingRuleRequest(req)
+		require.Error(t, err, message)
+		assert.Contains(t, err.Error(), message)
+	}
+
+	assert.NoError(t, services.ValidatePricingRuleRequest(&services.PricingRuleRequest{
+		Name: "Zone B", RuleType: domain.PricingRuleZoneSurcharge, Rate: 0.25, ZipCodes: []string{"12400"},
+	}))
+}
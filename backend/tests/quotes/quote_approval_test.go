@@ -0,0 +1,162 @@
+package quotes_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+// approvalQuoteRepo serves one quote and records how its approval was saved
+type approvalQuoteRepo struct {
+	services.QuoteRepositoryFull
+	quote        *domain.Quote
+	lines        []*domain.QuoteService
+	latest       *domain.QuoteVersion
+	signatureErr error
+
+	updated   *domain.Quote
+	approved  *domain.Quote
+	signature *domain.QuoteSignature
+}
+
+func (r *approvalQuoteRepo) GetByID(ctx context.Context, tenantID, quoteID uuid.UUID) (*domain.Quote, error) {
+	copied := *r.quote
+	return &copied, nil
+}
+
+func (r *approvalQuoteRepo) GetQuoteServices(ctx context.Context, quoteID uuid.UUID) ([]*domain.QuoteService, error) {
+	return r.lines, nil
+}
+
+func (r *approvalQuoteRepo) GetQuoteOptions(ctx context.Context, quoteID uuid.UUID) ([]*domain.QuoteOption, error) {
+	return nil, nil
+}
+
+func (r *approvalQuoteRepo) GetLatestQuoteVersion(ctx context.Context, tenantID, quoteID uuid.UUID) (*domain.QuoteVersion, error) {
+	return r.latest, nil
+}
+
+func (r *approvalQuoteRepo) Update(ctx context.Context, quote *domain.Quote) error {
+	r.updated = quote
+	return nil
+}
+
+func (r *approvalQuoteRepo) ApproveWithSignature(ctx context.Context, quote *domain.Quote, signature *domain.QuoteSignature) error {
+	if r.signatureErr != nil {
+		return r.signatureErr
+	}
+	r.approved = quote
+	r.signature = signature
+	return nil
+}
+
+type approvalServiceRepo struct {
+	services.ServiceRepository
+	services map[uuid.UUID]*domain.Service
+}
+
+func (r *approvalServiceRepo) GetByIDs(ctx context.Context, tenantID uuid.UUID, serviceIDs []uuid.UUID) ([]*domain.Service, error) {
+	var result []*domain.Service
+	for _, id := range serviceIDs {
+		result = append(result, r.services[id])
+	}
+	return result, nil
+}
+
+type approvalCustomerRepo struct {
+	services.CustomerRepository
+}
+
+func (approvalCustomerRepo) GetByID(ctx context.Context, tenantID, customerID uuid.UUID) (*domain.EnhancedCustomer, error) {
+	customer := &domain.EnhancedCustomer{}
+	customer.ID = customerID
+	customer.Email = strPtr("customer@example.com")
+	return customer, nil
+}
+
+type approvalMailer struct {
+	services.CommunicationService
+	sent []*services.EmailRequest
+}
+
+func (m *approvalMailer) SendEmail(ctx context.Context, req *services.EmailRequest) error {
+	m.sent = append(m.sent, req)
+	return nil
+}
+
+type approvalAudit struct {
+	services.AuditService
+}
+
+func (approvalAudit) LogAction(ctx context.Context, req *services.AuditLogRequest) error {
+	return nil
+}
+
+func newApprovalFixture() (*approvalQuoteRepo, *approvalMailer, services.QuoteService) {
+	quote, lines, serviceMap := quoteVersionFixture()
+	quote.Status = "pending"
+	quote.CustomerID = uuid.New()
+
+	latest := services.BuildQuoteVersion(quote, lines, nil, serviceMap)
+	latest.VersionNumber, latest.Revision = 1, "A"
+
+	repo := &approvalQuoteRepo{quote: quote, lines: lines, latest: latest}
+	mailer := &approvalMailer{}
+	svc := services.NewQuoteService(repo, approvalCustomerRepo{}, nil, &approvalServiceRepo{services: serviceMap}, nil, nil,
+		approvalAudit{}, mailer, nil, nil, log.New(io.Discard, "", 0))
+
+	return repo, mailer, svc
+}
+
+func signature(quote *domain.Quote) *domain.QuoteSignature {
+	return &domain.QuoteSignature{
+		ID:            uuid.New(),
+		TenantID:      quote.TenantID,
+		QuoteID:       quote.ID,
+		CustomerID:    quote.CustomerID,
+		SignerName:    "Pat Customer",
+		SignatureType: "typed",
+		SignatureData: "Pat Customer",
+		SignedAt:      time.Now(),
+	}
+}
+
+func TestApproveQuoteWithSignature(t *testing.T) {
+	repo, mailer, svc := newApprovalFixture()
+	ctx := context.WithValue(context.Background(), "tenant_id", repo.quote.TenantID)
+
+	sig := signature(repo.quote)
+	require.NoError(t, svc.ApproveQuoteWithSignature(ctx, repo.quote.ID, nil, sig))
+
+	require.NotNil(t, repo.approved, "approval and signature are saved together")
+	assert.Nil(t, repo.updated)
+	assert.Equal(t, "approved", repo.approved.Status)
+	assert.Equal(t, &repo.latest.ID, repo.approved.ApprovedVersionID)
+	assert.Same(t, sig, repo.signature)
+	assert.Equal(t, &repo.latest.ID, sig.QuoteVersionID)
+	assert.Equal(t, 270.0, sig.TotalAmount)
+	assert.Len(t, mailer.sent, 1)
+}
+
+func TestApproveQuoteWithSignatureFailure(t *testing.T) {
+	repo, mailer, svc := newApprovalFixture()
+	repo.signatureErr = errors.New("insert failed")
+	ctx := context.WithValue(context.Background(), "tenant_id", repo.quote.TenantID)
+
+	err := svc.ApproveQuoteWithSignature(ctx, repo.quote.ID, nil, signature(repo.quote))
+	require.Error(t, err)
+
+	assert.Nil(t, repo.approved)
+	assert.Nil(t, repo.updated, "the quote is not approved without its signature")
+	assert.Empty(t, mailer.sent, "the customer is not told the quote was approved")
+}
@@ -0,0 +1,168 @@
+package quotes_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+type quoteOptionFixture struct {
+	quote    *domain.Quote
+	lines    []*domain.QuoteService
+	options  []*domain.QuoteOption
+	services map[uuid.UUID]*domain.Service
+
+	basic, premium, noIrrigation, tuneUp *domain.QuoteOption
+	cleanup, aeration, overseed          *domain.QuoteService
+}
+
+func newQuoteOptionFixture() *quoteOptionFixture {
+	f := &quoteOptionFixture{services: make(map[uuid.UUID]*domain.Service)}
+	service := func(name string) uuid.UUID {
+		s := &domain.Service{ID: uuid.New(), Name: name}
+		f.services[s.ID] = s
+		return s.ID
+	}
+	cleanupID, mowingID, fertID, irrigationID := service("Spring Cleanup"), service("Lawn Mowing"), service("Fertilization"), service("Irrigation Tune-up")
+	aerationID, overseedID := service("Aeration"), service("Overseeding")
+
+	f.quote = &domain.Quote{ID: uuid.New(), TenantID: uuid.New(), QuoteNumber: "Q-2001", Title: "Season plan", TaxRate: 0.1}
+
+	option := func(group, name string, sortOrder int, isDefault bool) *domain.QuoteOption {
+		o := &domain.QuoteOption{ID: uuid.New(), QuoteID: f.quote.ID, GroupName: group, Name: name, SortOrder: sortOrder, IsDefault: isDefault}
+		f.options = append(f.options, o)
+		return o
+	}
+	f.basic = option("Maintenance plan", "Basic", 0, false)
+	f.premium = option("Maintenance plan", "Premium", 1, true)
+	f.noIrrigation = option("Irrigation", "No irrigation", 2, false)
+	f.tuneUp = option("Irrigation", "Tune-up", 3, false)
+
+	line := func(serviceID uuid.UUID, optionID *uuid.UUID, optional bool, quantity, unitPrice float64) *domain.QuoteService {
+		l := &domain.QuoteService{ID: uuid.New(), QuoteID: f.quote.ID, ServiceID: serviceID, OptionID: optionID, IsOptional: optional,
+			Quantity: quantity, UnitPrice: unitPrice, TotalPrice: quantity * unitPrice}
+		f.lines = append(f.lines, l)
+		return l
+	}
+	f.cleanup = line(cleanupID, nil, false, 1, 100)
+	line(mowingID, &f.basic.ID, false, 4, 40)
+	line(mowingID, &f.premium.ID, false, 4, 40)
+	line(fertID, &f.premium.ID, false, 1, 90)
+	line(irrigationID, &f.tuneUp.ID, false, 1, 120)
+	f.aeration = line(aerationID, nil, true, 1, 75)
+	f.overseed = line(overseedID, &f.premium.ID, true, 1, 50)
+
+	return f
+}
+
+func (f *quoteOptionFixture) version() *domain.QuoteVersion {
+	return services.BuildQuoteVersion(f.quote, f.lines, f.options, f.services)
+}
+
+func TestDefaultQuoteSelection(t *testing.T) {
+	f := newQuoteOptionFixture()
+	version := f.version()
+
+	defaults := services.DefaultQuoteSelection(version)
+	assert.Equal(t, []uuid.UUID{f.premium.ID, f.noIrrigation.ID}, defaults.OptionIDs, "marked default, else first in group")
+	assert.Empty(t, defaults.AddOnLineIDs)
+
+	selection, lines, err := services.ResolveQuoteSelection(version, defaults)
+	require.NoError(t, err)
+	assert.Equal(t, version.ID, selection.VersionID)
+	assert.Equal(t, 350.0, selection.Subtotal)
+	assert.Equal(t, 35.0, selection.TaxAmount)
+	assert.Equal(t, 385.0, selection.TotalAmount)
+	assert.Len(t, lines, 3, "cleanup plus the two premium lines")
+}
+
+func TestResolveQuoteSelection(t *testing.T) {
+	f := newQuoteOptionFixture()
+	version := f.version()
+
+	selection, lines, err := services.ResolveQuoteSelection(version, &services.QuoteSelectionRequest{
+		OptionIDs:    []uuid.UUID{f.basic.ID, f.tuneUp.ID},
+		AddOnLineIDs: []uuid.UUID{f.aeration.ID},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 455.0, selection.Subtotal)
+	assert.Equal(t, 500.5, selection.TotalAmount)
+	require.Len(t, lines, 4)
+	assert.Equal(t, "Aeration", lines[3].ServiceName)
+
+	assert.Equal(t, "Basic + Tune-up + Aeration", services.DescribeQuoteSelection(version, selection))
+}
+
+func TestResolveQuoteSelectionErrors(t *testing.T) {
+	f := newQuoteOptionFixture()
+	version := f.version()
+
+	cases := map[string]*services.QuoteSelectionRequest{
+		"more than one option selected for Maintenance plan": {OptionIDs: []uuid.UUID{f.basic.ID, f.premium.ID, f.tuneUp.ID}},
+		"no option selected for Irrigation":                  {OptionIDs: []uuid.UUID{f.basic.ID}},
+		"unknown quote option":                               {OptionIDs: []uuid.UUID{uuid.New()}},
+		"is not an optional add-on":                          {OptionIDs: []uuid.UUID{f.basic.ID, f.tuneUp.ID}, AddOnLineIDs: []uuid.UUID{f.cleanup.ID}},
+		"requires option Premium":                            {OptionIDs: []uuid.UUID{f.basic.ID, f.tuneUp.ID}, AddOnLineIDs: []uuid.UUID{f.overseed.ID}},
+	}
+
+	for message, req := range cases {
+		_, _, err := services.ResolveQuoteSelection(version, req)
+		require.Error(t, err, message)
+		assert.Contains(t, err.Error(), message)
+	}
+}
+
+func TestBuildQuotePricing(t *testing.T) {
+	f := newQuoteOptionFixture()
+
+	pricing := services.BuildQuotePricing(f.version())
+
+	assert.Equal(t, 100.0, pricing.BaseSubtotal)
+	assert.Equal(t, 385.0, pricing.Default.TotalAmount)
+	require.Len(t, pricing.AddOns, 2)
+	assert.Equal(t, &f.premium.ID, pricing.AddOns[1].OptionID)
+
+	require.Len(t, pricing.Groups, 2)
+	plan := pricing.Groups[0]
+	assert.Equal(t, "Maintenance plan", plan.GroupName)
+	require.Len(t, plan.Options, 2)
+	assert.Equal(t, 160.0, plan.Options[0].Subtotal)
+	assert.Equal(t, 286.0, plan.Options[0].QuoteTotal, "basic with no irrigation")
+	assert.Equal(t, 385.0, plan.Options[1].QuoteTotal)
+
+	irrigation := pricing.Groups[1]
+	assert.Empty(t, irrigation.Options[0].Lines)
+	assert.Equal(t, 517.0, irrigation.Options[1].QuoteTotal, "tune-up with the premium plan")
+}
+
+func TestDiffQuoteVersionsOptions(t *testing.T) {
+	f := newQuoteOptionFixture()
+	before := f.version()
+
+	// Options are recreated on edit, so matching is by group and name rather than ID
+	for _, option := range f.options {
+		newID := uuid.New()
+		for _, line := range f.lines {
+			if line.OptionID != nil && *line.OptionID == option.ID {
+				line.OptionID = &newID
+			}
+		}
+		option.ID = newID
+	}
+	unchanged := f.version()
+	assert.False(t, services.DiffQuoteVersions(before, unchanged).HasChanges())
+
+	f.basic.IsDefault, f.premium.IsDefault = true, false
+	f.options = append(f.options, &domain.QuoteOption{ID: uuid.New(), GroupName: "Maintenance plan", Name: "Deluxe", SortOrder: 4})
+	after := f.version()
+
+	diff := services.DiffQuoteVersions(before, after)
+	require.Len(t, diff.OptionChanges, 3)
+	assert.Empty(t, diff.LineChanges)
+	assert.Equal(t, "Added 1 option; modified 2 options", services.SummarizeQuoteDiff(diff))
+}
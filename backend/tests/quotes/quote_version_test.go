@@ -0,0 +1,146 @@
+package quotes_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestQuoteRevisionLabel(t *testing.T) {
+	cases := map[int]string{0: "", 1: "A", 2: "B", 26: "Z", 27: "AA", 28: "AB", 52: "AZ", 53: "BA", 702: "ZZ", 703: "AAA"}
+	for version, label := range cases {
+		assert.Equal(t, label, services.QuoteRevisionLabel(version), "version %d", version)
+	}
+
+	assert.Equal(t, "Q-1001 rev B", services.FormatQuoteRevision("Q-1001", 2))
+	assert.Equal(t, "Q-1001", services.FormatQuoteRevision("Q-1001", 0))
+}
+
+func quoteVersionFixture() (*domain.Quote, []*domain.QuoteService, map[uuid.UUID]*domain.Service) {
+	mowing := &domain.Service{ID: uuid.New(), Name: "Lawn Mowing"}
+	edging := &domain.Service{ID: uuid.New(), Name: "Edging"}
+	validUntil := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	quote := &domain.Quote{
+		ID:                 uuid.New(),
+		TenantID:           uuid.New(),
+		QuoteNumber:        "Q-1001",
+		Title:              "Spring cleanup",
+		Subtotal:           250,
+		TaxRate:            0.08,
+		TaxAmount:          20,
+		TotalAmount:        270,
+		ValidUntil:         &validUntil,
+		TermsAndConditions: strPtr("Net 30"),
+	}
+	lines := []*domain.QuoteService{
+		{ID: uuid.New(), QuoteID: quote.ID, ServiceID: mowing.ID, Quantity: 4, UnitPrice: 50, TotalPrice: 200},
+		{ID: uuid.New(), QuoteID: quote.ID, ServiceID: edging.ID, Quantity: 1, UnitPrice: 50, TotalPrice: 50},
+	}
+
+	return quote, lines, map[uuid.UUID]*domain.Service{mowing.ID: mowing, edging.ID: edging}
+}
+
+func TestBuildQuoteVersion(t *testing.T) {
+	quote, lines, serviceMap := quoteVersionFixture()
+
+	version := services.BuildQuoteVersion(quote, lines, nil, serviceMap)
+
+	assert.Equal(t, quote.ID, version.QuoteID)
+	assert.Equal(t, quote.TenantID, version.TenantID)
+	assert.Equal(t, 270.0, version.TotalAmount)
+	require.Len(t, version.LineItems, 2)
+	assert.Equal(t, "Lawn Mowing", version.LineItems[0].ServiceName)
+	assert.Equal(t, 200.0, version.LineItems[0].TotalPrice)
+}
+
+func TestDiffQuoteVersionsUnchanged(t *testing.T) {
+	quote, lines, serviceMap := quoteVersionFixture()
+
+	sent := services.BuildQuoteVersion(quote, lines, nil, serviceMap)
+	sent.VersionNumber, sent.Revision = 1, "A"
+	working := services.BuildQuoteVersion(quote, lines, nil, serviceMap)
+
+	diff := services.DiffQuoteVersions(sent, working)
+	assert.False(t, diff.HasChanges())
+	assert.Equal(t, "No changes", services.SummarizeQuoteDiff(diff))
+}
+
+func TestDiffQuoteVersions(t *testing.T) {
+	quote, lines, serviceMap := quoteVersionFixture()
+
+	revA := services.BuildQuoteVersion(quote, lines, nil, serviceMap)
+	revA.VersionNumber, revA.Revision = 1, "A"
+
+	// Customer asks for weekly mowing, drops edging and adds mulch
+	mulch := &domain.Service{ID: uuid.New(), Name: "Mulch"}
+	serviceMap[mulch.ID] = mulch
+
+	newValidUntil := time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC)
+	quote.ValidUntil = &newValidUntil
+	quote.Subtotal, quote.TaxAmount, quote.TotalAmount = 400, 32, 432
+	revisedLines := []*domain.QuoteService{
+		{ServiceID: lines[0].ServiceID, Quantity: 8, UnitPrice: 50, TotalPrice: 400},
+		{ServiceID: mulch.ID, Quantity: 2, UnitPrice: 0, TotalPrice: 0},
+	}
+
+	revB := services.BuildQuoteVersion(quote, revisedLines, nil, serviceMap)
+	revB.VersionNumber, revB.Revision = 2, "B"
+
+	diff := services.DiffQuoteVersions(revA, revB)
+	require.True(t, diff.HasChanges())
+	assert.Equal(t, 1, diff.FromVersion)
+	assert.Equal(t, "B", diff.ToRevision)
+	assert.Equal(t, 162.0, diff.TotalDelta)
+	assert.Equal(t, 150.0, diff.SubtotalDelta)
+
+	fields := make(map[string]services.QuoteFieldChange)
+	for _, change := range diff.FieldChanges {
+		fields[change.Field] = change
+	}
+	assert.Equal(t, "2024-06-30", fields["valid_until"].OldValue)
+	assert.Equal(t, "2024-07-15", fields["valid_until"].NewValue)
+	assert.Contains(t, fields, "total_amount")
+	assert.NotContains(t, fields, "title")
+
+	require.Len(t, diff.LineChanges, 3)
+	assert.Equal(t, domain.QuoteLineChangeModified, diff.LineChanges[0].Change)
+	assert.Equal(t, "Lawn Mowing", diff.LineChanges[0].ServiceName)
+	assert.Equal(t, []string{"quantity", "total_price"}, diff.LineChanges[0].ChangedFields)
+	assert.Equal(t, domain.QuoteLineChangeAdded, diff.LineChanges[1].Change)
+	assert.Equal(t, "Mulch", diff.LineChanges[1].ServiceName)
+	assert.Equal(t, domain.QuoteLineChangeRemoved, diff.LineChanges[2].Change)
+	assert.Equal(t, "Edging", diff.LineChanges[2].ServiceName)
+
+	assert.Equal(t,
+		"Changed valid_until; added 1 line item; removed 1 line item; modified 1 line item; total +$162.00",
+		services.SummarizeQuoteDiff(diff))
+}
+
+func TestDiffQuoteVersionsRepeatedService(t *testing.T) {
+	quote, lines, serviceMap := quoteVersionFixture()
+	mowingID := lines[0].ServiceID
+
+	// The same service quoted twice, e.g. front and back yards
+	lines = []*domain.QuoteService{
+		{ServiceID: mowingID, Quantity: 4, UnitPrice: 50, TotalPrice: 200, Description: strPtr("Front yard")},
+		{ServiceID: mowingID, Quantity: 4, UnitPrice: 30, TotalPrice: 120, Description: strPtr("Back yard")},
+	}
+	before := services.BuildQuoteVersion(quote, lines, nil, serviceMap)
+
+	lines = lines[:1]
+	after := services.BuildQuoteVersion(quote, lines, nil, serviceMap)
+
+	diff := services.DiffQuoteVersions(before, after)
+	require.Len(t, diff.LineChanges, 1)
+	assert.Equal(t, domain.QuoteLineChangeRemoved, diff.LineChanges[0].Change)
+	assert.Equal(t, "Back yard", *diff.LineChanges[0].Old.Description)
+}
@@ -0,0 +1,77 @@
+package search_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func TestNormalizeSearchQuery(t *testing.T) {
+	assert.Equal(t, "oak drive", services.NormalizeSearchQuery("  Oak \t DRIVE \n"))
+	assert.Equal(t, "", services.NormalizeSearchQuery("   "))
+
+	long := services.NormalizeSearchQuery(strings.Repeat("ab ", 60))
+	assert.LessOrEqual(t, len([]rune(long)), services.SearchMaxQueryLength)
+	assert.NotEqual(t, ' ', rune(long[len(long)-1]))
+}
+
+func TestBuildPrefixTSQuery(t *testing.T) {
+	assert.Equal(t, "oak:* & dr:*", services.BuildPrefixTSQuery("oak dr"))
+	assert.Equal(t, "inv:* & 1042:*", services.BuildPrefixTSQuery("INV-1042"))
+	assert.Equal(t, "jane:* & example:* & com:*", services.BuildPrefixTSQuery("jane@example.com"))
+
+	// tsquery operators in user input never reach the expression
+	assert.Equal(t, "smith:* & jones:*", services.BuildPrefixTSQuery("smith | !jones & (:*"))
+	assert.Equal(t, "", services.BuildPrefixTSQuery("&|!:*()"))
+}
+
+func TestParseSearchTypes(t *testing.T) {
+	all, err := services.ParseSearchTypes("")
+	require.NoError(t, err)
+	assert.Equal(t, services.SearchTypes, all)
+
+	types, err := services.ParseSearchTypes(" Job, invoice,job ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{services.SearchTypeJob, services.SearchTypeInvoice}, types)
+
+	_, err = services.ParseSearchTypes("customer,equipment")
+	assert.Error(t, err)
+}
+
+func TestScoreSearchResultBoostsExactAndPrefixMatches(t *testing.T) {
+	exact := &services.SearchResult{Title: "Spring cleanup", Number: "INV-1042", Similarity: 0.5}
+	prefix := &services.SearchResult{Title: "INV-10420", Similarity: 0.5}
+	fuzzy := &services.SearchResult{Title: "Invoice reminder", Similarity: 0.5}
+
+	assert.InDelta(t, 1.5, services.ScoreSearchResult("inv-1042", exact), 0.0001)
+	assert.InDelta(t, 1.0, services.ScoreSearchResult("inv-1042", prefix), 0.0001)
+	assert.InDelta(t, 0.5, services.ScoreSearchResult("inv-1042", fuzzy), 0.0001)
+
+	// Full-text rank counts for half, capped at 1
+	ranked := &services.SearchResult{Title: "Trimming hedges", TextRank: 3, Similarity: 0.2}
+	assert.InDelta(t, 0.7, services.ScoreSearchResult("hedge", ranked), 0.0001)
+}
+
+func TestRankSearchResults(t *testing.T) {
+	customer := &services.SearchResult{Type: services.SearchTypeCustomer, ID: uuid.New(), Title: "Jane Oakley", Similarity: 0.6}
+	property := &services.SearchResult{Type: services.SearchTypeProperty, ID: uuid.New(), Title: "12 Oak Drive", Similarity: 0.6}
+	job := &services.SearchResult{Type: services.SearchTypeJob, ID: uuid.New(), Title: "Oak removal", Similarity: 0.9}
+	typo := &services.SearchResult{Type: services.SearchTypeQuote, ID: uuid.New(), Title: "Oka pruning", Similarity: 0.3}
+
+	ranked := services.RankSearchResults("oak", []*services.SearchResult{typo, property, nil, customer, job}, 0)
+	require.Len(t, ranked, 4)
+	assert.Equal(t, job.ID, ranked[0].ID)
+	assert.InDelta(t, 1.4, ranked[0].Score, 0.0001)
+	// Equal scores are listed by record type
+	assert.Equal(t, customer.ID, ranked[1].ID)
+	assert.Equal(t, property.ID, ranked[2].ID)
+	assert.Equal(t, typo.ID, ranked[3].ID)
+
+	limited := services.RankSearchResults("oak", []*services.SearchResult{typo, property, customer, job}, 2)
+	assert.Len(t, limited, 2)
+}
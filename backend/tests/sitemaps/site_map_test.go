@@ -0,0 +1,189 @@
+package sitemaps_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func strPtr(s string) *string { return &s }
+
+// ring returns a closed square ring of side degrees with its southwest corner at lng, lat
+func ring(lng, lat, side float64) string {
+	return fmt.Sprintf("[[%g,%g],[%g,%g],[%g,%g],[%g,%g],[%g,%g]]",
+		lng, lat, lng+side, lat, lng+side, lat+side, lng, lat+side, lng, lat)
+}
+
+func polygon(rings ...string) json.RawMessage {
+	coordinates := "["
+	for i, r := range rings {
+		if i > 0 {
+			coordinates += ","
+		}
+		coordinates += r
+	}
+	return json.RawMessage(`{"type":"Polygon","coordinates":` + coordinates + `]}`)
+}
+
+// A thousandth of a degree is about 364.8 ft on the earth's mean radius
+const side = 0.001
+const sideFeet = 364.8
+
+func TestMeasureGeometrySquare(t *testing.T) {
+	geometry, area, perimeter, err := services.MeasureGeometry(polygon(ring(0, 0, side)))
+	require.NoError(t, err)
+
+	assert.InEpsilon(t, sideFeet*sideFeet, area, 0.005)
+	assert.InEpsilon(t, 4*sideFeet, perimeter, 0.005)
+	assert.JSONEq(t, string(polygon(ring(0, 0, side))), string(geometry))
+
+	// Longitude degrees shrink away from the equator
+	_, northern, _, err := services.MeasureGeometry(polygon(ring(-84.39, 60, side)))
+	require.NoError(t, err)
+	assert.InEpsilon(t, area/2, northern, 0.01, "a degree of longitude at 60°N is half as long")
+}
+
+func TestMeasureGeometryHolesAndMultiPolygons(t *testing.T) {
+	_, outer, outerPerimeter, err := services.MeasureGeometry(polygon(ring(0, 0, side)))
+	require.NoError(t, err)
+
+	_, withHole, holePerimeter, err := services.MeasureGeometry(polygon(ring(0, 0, side), ring(0.00025, 0.00025, side/2)))
+	require.NoError(t, err)
+	assert.InEpsilon(t, outer*0.75, withHole, 0.005, "holes are cut out of the area")
+	assert.InEpsilon(t, outerPerimeter*1.5, holePerimeter, 0.005, "hole edges count toward the perimeter")
+
+	multi := json.RawMessage(`{"type":"MultiPolygon","coordinates":[[` + ring(0, 0, side) + `],[` + ring(0.01, 0, side) + `]]}`)
+	_, both, _, err := services.MeasureGeometry(multi)
+	require.NoError(t, err)
+	assert.InEpsilon(t, outer*2, both, 0.005)
+}
+
+func TestMeasureGeometryRejectsInvalidShapes(t *testing.T) {
+	cases := map[string]json.RawMessage{
+		"missing":      nil,
+		"point":        json.RawMessage(`{"type":"Point","coordinates":[0,0]}`),
+		"open ring":    json.RawMessage(`{"type":"Polygon","coordinates":[[[0,0],[0.001,0],[0.001,0.001],[0,0.001]]]}`),
+		"too few":      json.RawMessage(`{"type":"Polygon","coordinates":[[[0,0],[0.001,0],[0,0]]]}`),
+		"out of range": json.RawMessage(`{"type":"Polygon","coordinates":[[[0,0],[200,0],[200,1],[0,0]]]}`),
+		"no area":      json.RawMessage(`{"type":"Polygon","coordinates":[[[0,0],[0.001,0],[0.002,0],[0,0]]]}`),
+		"no rings":     json.RawMessage(`{"type":"Polygon","coordinates":[]}`),
+	}
+	for name, geometry := range cases {
+		_, _, _, err := services.MeasureGeometry(geometry)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestParseSiteMapGeoJSON(t *testing.T) {
+	var collection services.GeoJSONFeatureCollection
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "FeatureCollection",
+		"features": [
+			{"type": "Feature", "properties": {"feature_type": "Turf", "name": "Front lawn"}, "geometry": `+string(polygon(ring(0, 0, side)))+`},
+			{"type": "Feature", "properties": {"feature_type": "bed", "notes": "Hydrangeas"}, "geometry": `+string(polygon(ring(0.002, 0, side)))+`}
+		]
+	}`), &collection))
+
+	reqs, err := services.ParseSiteMapGeoJSON(&collection)
+	require.NoError(t, err)
+	require.Len(t, reqs, 2)
+	assert.Equal(t, domain.PropertyFeatureTurf, reqs[0].FeatureType, "feature types are case-insensitive")
+	assert.Equal(t, "Front lawn", *reqs[0].Name)
+	assert.Nil(t, reqs[1].Name)
+	assert.Equal(t, "Hydrangeas", *reqs[1].Notes)
+
+	collection.Features[1].Properties["feature_type"] = "pond"
+	_, err = services.ParseSiteMapGeoJSON(&collection)
+	assert.ErrorContains(t, err, "feature 2")
+
+	_, err = services.ParseSiteMapGeoJSON(&services.GeoJSONFeatureCollection{Type: "Feature"})
+	assert.Error(t, err, "only feature collections are imported")
+}
+
+func TestSiteMapSummaryAndExport(t *testing.T) {
+	feature := func(featureType string, area, perimeter float64) *domain.PropertyFeature {
+		return &domain.PropertyFeature{
+			ID: uuid.New(), FeatureType: featureType, Geometry: polygon(ring(0, 0, side)),
+			AreaSqFt: area, PerimeterFt: perimeter,
+		}
+	}
+	features := []*domain.PropertyFeature{
+		feature(domain.PropertyFeatureBed, 120.5, 60),
+		feature(domain.PropertyFeatureTurf, 5000, 300),
+		feature(domain.PropertyFeatureBed, 79.5, 40),
+	}
+	features[1].Name = strPtr("Back lawn")
+
+	totals := services.SummarizeSiteMap(features)
+	require.Len(t, totals, 2)
+	assert.Equal(t, services.SiteMapAreaTotal{FeatureType: "turf", FeatureCount: 1, AreaSqFt: 5000, PerimeterFt: 300}, totals[0])
+	assert.Equal(t, services.SiteMapAreaTotal{FeatureType: "bed", FeatureCount: 2, AreaSqFt: 200, PerimeterFt: 100}, totals[1])
+	assert.Equal(t, map[string]float64{"turf": 5000, "bed": 200}, services.MeasuredAreas(features))
+
+	collection := services.SiteMapToGeoJSON(features)
+	assert.Equal(t, "FeatureCollection", collection.Type)
+	require.Len(t, collection.Features, 3)
+	assert.Equal(t, "Back lawn", collection.Features[1].Properties["name"])
+	assert.Equal(t, features[1].ID.String(), collection.Features[1].Properties["id"])
+
+	// An export imports back unchanged
+	reqs, err := services.ParseSiteMapGeoJSON(collection)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PropertyFeatureTurf, reqs[1].FeatureType)
+}
+
+func TestApplyPricingRulesMeasuredAreas(t *testing.T) {
+	mowing := domain.PricingRule{
+		ID: uuid.New(), Name: "Mowing", RuleType: domain.PricingRuleUnitRate, Rate: 0.01, IsActive: true,
+		Unit: strPtr(domain.PricingUnitSqFt), MeasuredArea: strPtr(domain.PropertyFeatureTurf),
+	}
+	books := []*domain.PriceBook{{ID: uuid.New(), Name: "Standard", IsActive: true, Rules: []domain.PricingRule{mowing}}}
+
+	breakdown := services.ApplyPricingRules(books, &services.PriceRequest{
+		PropertySize:  20000,
+		MeasuredAreas: map[string]float64{"turf": 6000, "bed": 800},
+	})
+	assert.Equal(t, 6000.0, breakdown.Quantity, "measured turf, not the lot, is priced")
+	assert.Equal(t, 60.0, breakdown.Total)
+
+	breakdown = services.ApplyPricingRules(books, &services.PriceRequest{PropertySize: 20000})
+	assert.Equal(t, 20000.0, breakdown.Quantity, "unmeasured properties price the property size")
+
+	// Size tiers can be chosen by a measured area and charge per visit
+	tier := func(min, max float64, rate float64) domain.PricingRule {
+		return domain.PricingRule{
+			ID: uuid.New(), Name: "Bed tier", RuleType: domain.PricingRuleSizeTier, Rate: rate, IsActive: true,
+			Unit: strPtr(domain.PricingUnitVisit), MeasuredArea: strPtr(domain.PropertyFeatureBed),
+			MinValue: &min, MaxValue: &max,
+		}
+	}
+	books = []*domain.PriceBook{{ID: uuid.New(), Name: "Mulch", IsActive: true, Rules: []domain.PricingRule{
+		tier(0, 500, 150), tier(500, 2000, 300),
+	}}}
+	breakdown = services.ApplyPricingRules(books, &services.PriceRequest{
+		PropertySize:  20000,
+		MeasuredAreas: map[string]float64{"bed": 800},
+	})
+	assert.Equal(t, 300.0, breakdown.Total)
+}
+
+func TestValidatePricingRuleMeasuredArea(t *testing.T) {
+	req := &services.PricingRuleRequest{
+		Name: "Mowing", RuleType: domain.PricingRuleUnitRate, Rate: 0.01,
+		Unit: strPtr(domain.PricingUnitSqFt), MeasuredArea: strPtr(domain.PropertyFeatureTurf),
+	}
+	assert.NoError(t, services.ValidatePricingRuleRequest(req))
+
+	req.MeasuredArea = strPtr("pond")
+	assert.Error(t, services.ValidatePricingRuleRequest(req))
+
+	req.MeasuredArea, req.Unit = strPtr(domain.PropertyFeatureTurf), strPtr(domain.PricingUnitHour)
+	assert.Error(t, services.ValidatePricingRuleRequest(req), "hourly rates cannot price an area")
+}
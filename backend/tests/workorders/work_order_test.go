@@ -0,0 +1,107 @@
+package workorders_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pageza/landscaping-app/backend/internal/domain"
+	"github.com/pageza/landscaping-app/backend/internal/services"
+)
+
+func at(day, hour int) time.Time {
+	return time.Date(2026, 6, day, hour, 0, 0, 0, time.UTC)
+}
+
+func timePtr(t time.Time) *time.Time { return &t }
+
+func TestValidateWorkOrderRequest(t *testing.T) {
+	req := &services.WorkOrderRequest{Title: "Replace blades", Type: domain.WorkOrderTypeRepair, Priority: "high"}
+	assert.NoError(t, services.ValidateWorkOrderRequest(req))
+
+	assert.Error(t, services.ValidateWorkOrderRequest(&services.WorkOrderRequest{Title: " ", Type: domain.WorkOrderTypeRepair}))
+	assert.Error(t, services.ValidateWorkOrderRequest(&services.WorkOrderRequest{Title: "Oil change", Type: "overhaul"}))
+	assert.Error(t, services.ValidateWorkOrderRequest(&services.WorkOrderRequest{Title: "Oil change", Type: domain.WorkOrderTypePreventive, Priority: "whenever"}))
+
+	req.ExpectedReturnAt = timePtr(at(3, 17))
+	assert.Error(t, services.ValidateWorkOrderRequest(req), "an expected return needs the equipment taken out of service")
+	req.TakeOutOfService = true
+	assert.NoError(t, services.ValidateWorkOrderRequest(req))
+}
+
+func TestValidateWorkOrderTransition(t *testing.T) {
+	assert.NoError(t, services.ValidateWorkOrderTransition(domain.WorkOrderStatusOpen, domain.WorkOrderStatusInProgress))
+	assert.NoError(t, services.ValidateWorkOrderTransition(domain.WorkOrderStatusInProgress, domain.WorkOrderStatusClosed))
+	assert.NoError(t, services.ValidateWorkOrderTransition(domain.WorkOrderStatusOpen, domain.WorkOrderStatusClosed))
+
+	err := services.ValidateWorkOrderTransition(domain.WorkOrderStatusClosed, domain.WorkOrderStatusInProgress)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "work order is closed")
+
+	err = services.ValidateWorkOrderTransition(domain.WorkOrderStatusInProgress, domain.WorkOrderStatusInProgress)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot move from in_progress to in_progress")
+}
+
+func TestCalculateWorkOrderCosts(t *testing.T) {
+	workOrder := &domain.MaintenanceWorkOrder{
+		Parts: []*domain.WorkOrderPart{
+			{Quantity: 2, UnitCost: 12.495, TotalCost: 24.99},
+			{Quantity: 1, UnitCost: 8.5, TotalCost: 8.5},
+		},
+		Labor: []*domain.WorkOrderLabor{
+			{Hours: 1.5, HourlyRate: 45, TotalCost: 67.5},
+		},
+		VendorRepairs: []*domain.WorkOrderVendorRepair{
+			{VendorName: "Small Engine Shop", Amount: 210.1},
+		},
+	}
+
+	services.CalculateWorkOrderCosts(workOrder)
+	assert.Equal(t, 33.49, workOrder.PartsCost)
+	assert.Equal(t, 67.5, workOrder.LaborCost)
+	assert.Equal(t, 210.1, workOrder.VendorCost)
+	assert.Equal(t, 311.09, workOrder.TotalCost)
+}
+
+func TestDowntimeWindow(t *testing.T) {
+	now := at(5, 12)
+	horizon := at(12, 0)
+
+	_, ok := services.DowntimeWindow(&domain.MaintenanceWorkOrder{}, now, horizon)
+	assert.False(t, ok, "work orders that leave the equipment in service have no downtime")
+
+	window, ok := services.DowntimeWindow(&domain.MaintenanceWorkOrder{DowntimeStartedAt: timePtr(at(2, 8)), DowntimeEndedAt: timePtr(at(3, 16))}, now, horizon)
+	require.True(t, ok)
+	assert.Equal(t, at(2, 8), window.Start)
+	assert.Equal(t, at(3, 16), window.End)
+
+	window, ok = services.DowntimeWindow(&domain.MaintenanceWorkOrder{DowntimeStartedAt: timePtr(at(4, 8)), ExpectedReturnAt: timePtr(at(7, 8))}, now, horizon)
+	require.True(t, ok)
+	assert.Equal(t, at(7, 8), window.End, "open downtime runs to the expected return")
+
+	window, ok = services.DowntimeWindow(&domain.MaintenanceWorkOrder{DowntimeStartedAt: timePtr(at(1, 8)), ExpectedReturnAt: timePtr(at(3, 8))}, now, horizon)
+	require.True(t, ok)
+	assert.Equal(t, horizon, window.End, "an overdue return keeps the equipment down until the horizon")
+}
+
+func TestSummarizeWorkOrders(t *testing.T) {
+	start, end, now := at(1, 0), at(11, 0), at(10, 10)
+	workOrders := []*domain.MaintenanceWorkOrder{
+		// Closed in the period, down 1st 08:00 to 2nd 08:00
+		{TotalCost: 120, ClosedAt: timePtr(at(2, 9)), DowntimeStartedAt: timePtr(at(1, 8)), DowntimeEndedAt: timePtr(at(2, 8))},
+		// Overlaps the first, down 1st 20:00 to 2nd 20:00
+		{TotalCost: 80.255, ClosedAt: timePtr(at(3, 9)), DowntimeStartedAt: timePtr(at(1, 20)), DowntimeEndedAt: timePtr(at(2, 20))},
+		// Closed before the period, downtime clipped to the period start
+		{TotalCost: 500, ClosedAt: timePtr(at(1, 0).Add(-time.Hour)), DowntimeStartedAt: timePtr(at(1, 0).Add(-48 * time.Hour)), DowntimeEndedAt: timePtr(at(1, 2))},
+		// Still open and down since the 9th
+		{TotalCost: 40, DowntimeStartedAt: timePtr(at(9, 10))},
+	}
+
+	summary := services.SummarizeWorkOrders(workOrders, start, end, now)
+	assert.Equal(t, 3, summary.WorkOrders)
+	assert.Equal(t, 240.26, summary.Cost)
+	assert.Equal(t, 2.0+36.0+24.0, summary.DowntimeHours)
+}
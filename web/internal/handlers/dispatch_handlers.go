@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pageza/landscaping-app/web/internal/services"
+)
+
+// Dispatch board requests are passed through to the backend API as they are.
+// Every successful edit is pushed to the rest of the tenant so open dispatch
+// boards update live.
+
+func (h *Handlers) proxyDispatch(w http.ResponseWriter, r *http.Request) {
+	user, err := h.getCurrentUser(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	token := h.extractToken(r)
+
+	path := r.URL.Path
+	if r.URL.RawQuery != "" {
+		path += "?" + r.URL.RawQuery
+	}
+
+	var resp *http.Response
+	switch r.Method {
+	case http.MethodGet:
+		resp, err = h.services.API.AuthenticatedGet(path, token)
+	case http.MethodPost:
+		resp, err = h.services.API.AuthenticatedPost(path, token, r.Body)
+	case http.MethodPut:
+		resp, err = h.services.API.AuthenticatedPut(path, token, r.Body)
+	case http.MethodDelete:
+		resp, err = h.services.API.AuthenticatedDelete(path, token)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Dispatch service unavailable", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, "Dispatch service unavailable", http.StatusBadGateway)
+		return
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.WriteHeader(resp.StatusCode)
+	w.Write(body)
+
+	if r.Method != http.MethodGet && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		h.broadcastDispatchUpdate(user, body)
+	}
+}
+
+// Send real-time dispatch board updates
+func (h *Handlers) broadcastDispatchUpdate(user *services.User, change []byte) {
+	if !json.Valid(change) {
+		return
+	}
+
+	msg := services.Message{
+		Type:    "dispatch_update",
+		Data:    json.RawMessage(change),
+		UserID:  user.ID,
+		Channel: "dispatch",
+	}
+
+	h.services.WebSocket.BroadcastToTenant(user.TenantID, msg)
+}
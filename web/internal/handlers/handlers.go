@@ -170,6 +170,9 @@ func (h *Handlers) setupAPIProxyRoutes(r *mux.Router) {
 	// Real-time updates
 	r.HandleFunc("/v1/notifications", h.getNotifications).Methods("GET")
 	r.HandleFunc("/v1/dashboard/stats", h.getDashboardStats).Methods("GET")
+
+	// Dispatch board, relayed to the backend API with live updates
+	r.PathPrefix("/v1/dispatch/").HandlerFunc(h.proxyDispatch).Methods("GET", "POST", "PUT", "DELETE")
 }
 
 // setupWebSocketRoutes configures WebSocket routes